@@ -30,10 +30,13 @@ func Command(id clio.Identification) *cobra.Command {
 }
 
 func create(id clio.Identification) (clio.Application, *cobra.Command) {
+	logOpts := &logOptions{}
+
 	clioCfg := clio.NewSetupConfig(id).
 		WithGlobalConfigFlag().   // add persistent -c <path> for reading an application config from
 		WithGlobalLoggingFlags(). // add persistent -v and -q flags tied to the logging config
 		WithConfigInRootHelp().   // --help on the root command renders the full application config in the help text
+		WithLoggerConstructor(logOpts.newLogger()).
 		WithUIConstructor(
 			// select a UI based on the logging configuration and state of stdin (if stdin is a tty)
 			func(cfg clio.Config) ([]clio.UI, error) {
@@ -75,14 +78,25 @@ func create(id clio.Identification) (clio.Application, *cobra.Command) {
 	app := clio.New(*clioCfg)
 
 	rootCmd := commands.Root(app)
+	logOpts.addFlags(rootCmd)
+
+	configCmd := clio.ConfigCommand(app, nil)
+	configCmd.AddCommand(commands.ConfigMatchers(app))
 
 	// add sub-commands
 	rootCmd.AddCommand(
 		commands.DB(app),
+		commands.Benchmark(app),
 		commands.Completion(app),
 		commands.Explain(app),
+		commands.Simulate(app),
+		commands.Monitor(app),
+		commands.History(app),
+		commands.ReportFP(app),
+		commands.Report(app),
+		commands.VerifyResults(app),
 		clio.VersionCommand(id, syftVersion, dbVersion),
-		clio.ConfigCommand(app, nil),
+		configCmd,
 	)
 
 	return app, rootCmd
@@ -0,0 +1,303 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/linux"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type benchmarkOptions struct {
+	Output     string `yaml:"output" json:"output" mapstructure:"output"`
+	Scale      int    `yaml:"scale" json:"scale" mapstructure:"scale"`
+	Iterations int    `yaml:"iterations" json:"iterations" mapstructure:"iterations"`
+	DBOptions  `yaml:",inline" mapstructure:",squash"`
+}
+
+var _ clio.FlagAdder = (*benchmarkOptions)(nil)
+
+func (o *benchmarkOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", "format to display results (available=[table, json])")
+	flags.IntVarP(&o.Scale, "scale", "", "number of copies of the synthetic package corpus to match against")
+	flags.IntVarP(&o.Iterations, "iterations", "", "number of times to run the matching phase (timings are averaged)")
+}
+
+func Benchmark(app clio.Application) *cobra.Command {
+	opts := &benchmarkOptions{
+		Output:     "table",
+		Scale:      10,
+		Iterations: 3,
+		DBOptions:  *dbOptionsDefault(app.ID()),
+	}
+	// benchmarks should be run against the installed DB as-is
+	opts.DB.AutoUpdate = false
+	opts.DB.RequireUpdateCheck = false
+
+	return app.SetupCommand(&cobra.Command{
+		Use:     "benchmark",
+		Short:   "run a standardized synthetic SBOM through the matchers and report timings",
+		Args:    cobra.ExactArgs(0),
+		PreRunE: disableUI(app),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runBenchmark(app.ID(), opts, os.Stdout)
+		},
+	}, opts)
+}
+
+type benchmarkReport struct {
+	GrypeVersion string                   `json:"grypeVersion"`
+	GOOS         string                   `json:"goos"`
+	GOARCH       string                   `json:"goarch"`
+	NumCPU       int                      `json:"numCPU"`
+	DBBuilt      time.Time                `json:"dbBuilt"`
+	DBSchema     int                      `json:"dbSchemaVersion"`
+	DBChecksum   string                   `json:"dbChecksum"`
+	Packages     int                      `json:"packages"`
+	Iterations   int                      `json:"iterations"`
+	Matches      int                      `json:"matches"`
+	Phases       []benchmarkPhaseTiming   `json:"phases"`
+	Matchers     []benchmarkMatcherTiming `json:"matchers"`
+}
+
+type benchmarkPhaseTiming struct {
+	Phase    string        `json:"phase"`
+	Duration time.Duration `json:"duration"`
+}
+
+type benchmarkMatcherTiming struct {
+	Matcher  match.MatcherType `json:"matcher"`
+	Calls    int               `json:"calls"`
+	Matches  int               `json:"matches"`
+	Duration time.Duration     `json:"duration"`
+}
+
+func runBenchmark(id clio.Identification, opts *benchmarkOptions, out io.Writer) error {
+	if opts.Scale < 1 || opts.Iterations < 1 {
+		return fmt.Errorf("scale and iterations must be positive values")
+	}
+
+	report := benchmarkReport{
+		GrypeVersion: id.Version,
+		GOOS:         runtime.GOOS,
+		GOARCH:       runtime.GOARCH,
+		NumCPU:       runtime.NumCPU(),
+		Iterations:   opts.Iterations,
+	}
+
+	start := time.Now()
+	str, status, dbCloser, err := grype.LoadVulnerabilityDB(opts.DB.ToCuratorConfig(), false)
+	if err = validateDBLoad(err, status); err != nil {
+		return err
+	}
+	if dbCloser != nil {
+		defer dbCloser.Close()
+	}
+	report.Phases = append(report.Phases, benchmarkPhaseTiming{Phase: "load db", Duration: time.Since(start)})
+	report.DBBuilt = status.Built
+	report.DBSchema = status.SchemaVersion
+	report.DBChecksum = status.Checksum
+
+	start = time.Now()
+	packages, pkgContext := benchmarkPackages(opts.Scale)
+	report.Packages = len(packages)
+	report.Phases = append(report.Phases, benchmarkPhaseTiming{Phase: "synthesize packages", Duration: time.Since(start)})
+
+	timed := newTimedMatchers(getMatchers(options.DefaultGrype(id)))
+	vulnMatcher := grype.VulnerabilityMatcher{
+		Store:    *str,
+		Matchers: timed.matchers(),
+	}
+
+	var matchDuration time.Duration
+	for i := 0; i < opts.Iterations; i++ {
+		start = time.Now()
+		remaining, _, err := vulnMatcher.FindMatches(packages, pkgContext)
+		if err != nil {
+			return fmt.Errorf("failed to match benchmark packages: %w", err)
+		}
+		matchDuration += time.Since(start)
+		report.Matches = remaining.Count()
+	}
+	report.Phases = append(report.Phases, benchmarkPhaseTiming{Phase: "match (avg)", Duration: matchDuration / time.Duration(opts.Iterations)})
+	report.Matchers = timed.report(opts.Iterations)
+
+	log.WithFields("packages", report.Packages, "matches", report.Matches).Debug("benchmark complete")
+
+	return presentBenchmark(opts.Output, report, out)
+}
+
+func presentBenchmark(outputFormat string, report benchmarkReport, out io.Writer) error {
+	switch outputFormat {
+	case "table":
+		fmt.Fprintf(out, "Grype: %s (%s/%s, %d CPUs)\n", report.GrypeVersion, report.GOOS, report.GOARCH, report.NumCPU)
+		fmt.Fprintf(out, "DB:    schema v%d built %s (%s)\n", report.DBSchema, report.DBBuilt.Format(time.RFC3339), report.DBChecksum)
+		fmt.Fprintf(out, "Input: %d packages, %d iterations, %d matches\n\n", report.Packages, report.Iterations, report.Matches)
+
+		phases := newBenchmarkTable(out, []string{"Phase", "Duration"})
+		for _, p := range report.Phases {
+			phases.Append([]string{p.Phase, p.Duration.String()})
+		}
+		phases.Render()
+
+		fmt.Fprintln(out)
+
+		matchers := newBenchmarkTable(out, []string{"Matcher", "Calls", "Matches", "Duration (avg)"})
+		for _, m := range report.Matchers {
+			matchers.Append([]string{string(m.Matcher), fmt.Sprint(m.Calls), fmt.Sprint(m.Matches), m.Duration.String()})
+		}
+		matchers.Render()
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", " ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode benchmark report: %+v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+	return nil
+}
+
+func newBenchmarkTable(out io.Writer, columns []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(columns)
+	table.SetAutoWrapText(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+	return table
+}
+
+// benchmarkCorpus is the standardized set of packages used for benchmarking. This should only be changed with care
+// since doing so invalidates comparisons with results from previous grype versions.
+var benchmarkCorpus = []syftPkg.Package{
+	{Name: "openssl", Version: "1.1.1n-0+deb11u3", Type: syftPkg.DebPkg},
+	{Name: "libc6", Version: "2.31-13+deb11u3", Type: syftPkg.DebPkg},
+	{Name: "zlib1g", Version: "1:1.2.11.dfsg-2+deb11u1", Type: syftPkg.DebPkg},
+	{Name: "curl", Version: "7.74.0-1.3+deb11u1", Type: syftPkg.DebPkg},
+	{Name: "lodash", Version: "4.17.15", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript, PURL: "pkg:npm/lodash@4.17.15"},
+	{Name: "minimist", Version: "1.2.0", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript, PURL: "pkg:npm/minimist@1.2.0"},
+	{Name: "django", Version: "2.2.0", Type: syftPkg.PythonPkg, Language: syftPkg.Python, PURL: "pkg:pypi/django@2.2.0"},
+	{Name: "requests", Version: "2.19.0", Type: syftPkg.PythonPkg, Language: syftPkg.Python, PURL: "pkg:pypi/requests@2.19.0"},
+	{Name: "log4j-core", Version: "2.14.1", Type: syftPkg.JavaPkg, Language: syftPkg.Java, PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+	{Name: "jackson-databind", Version: "2.9.8", Type: syftPkg.JavaPkg, Language: syftPkg.Java, PURL: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.9.8"},
+	{Name: "rails", Version: "5.2.0", Type: syftPkg.GemPkg, Language: syftPkg.Ruby, PURL: "pkg:gem/rails@5.2.0"},
+	{Name: "golang.org/x/net", Version: "v0.0.0-20200202094626-16171245cfb2", Type: syftPkg.GoModulePkg, Language: syftPkg.Go, PURL: "pkg:golang/golang.org/x/net@v0.0.0-20200202094626-16171245cfb2"},
+	{Name: "github.com/gin-gonic/gin", Version: "v1.6.0", Type: syftPkg.GoModulePkg, Language: syftPkg.Go, PURL: "pkg:golang/github.com/gin-gonic/gin@v1.6.0"},
+	{Name: "tokio", Version: "1.8.0", Type: syftPkg.RustPkg, Language: syftPkg.Rust, PURL: "pkg:cargo/tokio@1.8.0"},
+	{Name: "Newtonsoft.Json", Version: "12.0.1", Type: syftPkg.DotnetPkg, Language: syftPkg.Dotnet, PURL: "pkg:nuget/Newtonsoft.Json@12.0.1"},
+}
+
+// benchmarkPackages returns the benchmark corpus repeated the given number of times, where each copy is a distinct
+// package (by location and ID), along with the context (distro) used for matching OS packages.
+func benchmarkPackages(scale int) ([]pkg.Package, pkg.Context) {
+	var syftPkgs []syftPkg.Package
+	for i := 0; i < scale; i++ {
+		for _, p := range benchmarkCorpus {
+			p.Locations = file.NewLocationSet(file.NewLocation(fmt.Sprintf("/benchmark/%d/%s", i, p.Name)))
+			p.SetID()
+			syftPkgs = append(syftPkgs, p)
+		}
+	}
+
+	return pkg.FromPackages(syftPkgs, pkg.SynthesisConfig{GenerateMissingCPEs: true}), pkg.Context{
+		Distro: &linux.Release{
+			ID:        "debian",
+			Name:      "debian",
+			VersionID: "11",
+			Version:   "11",
+		},
+	}
+}
+
+// timedMatchers decorates matchers to track the time spent within each.
+type timedMatchers struct {
+	lock    sync.Mutex
+	wrapped []*timedMatcher
+}
+
+type timedMatcher struct {
+	matcher.Matcher
+	parent   *timedMatchers
+	calls    int
+	matches  int
+	duration time.Duration
+}
+
+func newTimedMatchers(matchers []matcher.Matcher) *timedMatchers {
+	t := &timedMatchers{}
+	for _, m := range matchers {
+		t.wrapped = append(t.wrapped, &timedMatcher{Matcher: m, parent: t})
+	}
+	return t
+}
+
+func (t *timedMatchers) matchers() []matcher.Matcher {
+	var out []matcher.Matcher
+	for _, m := range t.wrapped {
+		out = append(out, m)
+	}
+	return out
+}
+
+func (t *timedMatchers) report(iterations int) []benchmarkMatcherTiming {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var out []benchmarkMatcherTiming
+	for _, m := range t.wrapped {
+		out = append(out, benchmarkMatcherTiming{
+			Matcher:  m.Type(),
+			Calls:    m.calls / iterations,
+			Matches:  m.matches / iterations,
+			Duration: m.duration / time.Duration(iterations),
+		})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Duration > out[j].Duration
+	})
+	return out
+}
+
+func (m *timedMatcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	start := time.Now()
+	matches, err := m.Matcher.Match(store, d, p)
+	elapsed := time.Since(start)
+
+	m.parent.lock.Lock()
+	defer m.parent.lock.Unlock()
+	m.calls++
+	m.matches += len(matches)
+	m.duration += elapsed
+
+	return matches, err
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func Test_benchmarkPackages(t *testing.T) {
+	packages, ctx := benchmarkPackages(3)
+	require.Len(t, packages, 3*len(benchmarkCorpus))
+	require.NotNil(t, ctx.Distro)
+
+	ids := map[pkg.ID]struct{}{}
+	for _, p := range packages {
+		assert.NotEmpty(t, p.ID)
+		ids[p.ID] = struct{}{}
+	}
+	assert.Len(t, ids, len(packages), "each synthetic package must have a unique ID")
+}
+
+type stubMatcher struct {
+	matches int
+}
+
+func (s stubMatcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{syftPkg.NpmPkg}
+}
+
+func (s stubMatcher) Type() match.MatcherType {
+	return match.JavascriptMatcher
+}
+
+func (s stubMatcher) Match(_ vulnerability.Provider, _ *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	var out []match.Match
+	for i := 0; i < s.matches; i++ {
+		out = append(out, match.Match{Package: p})
+	}
+	return out, nil
+}
+
+func Test_timedMatchers(t *testing.T) {
+	timed := newTimedMatchers([]matcher.Matcher{stubMatcher{matches: 2}})
+	ms := timed.matchers()
+	require.Len(t, ms, 1)
+	assert.Equal(t, match.JavascriptMatcher, ms[0].Type())
+
+	for i := 0; i < 4; i++ {
+		_, err := ms[0].Match(nil, nil, pkg.Package{})
+		require.NoError(t, err)
+	}
+
+	report := timed.report(2)
+	require.Len(t, report, 1)
+	assert.Equal(t, 2, report[0].Calls)
+	assert.Equal(t, 4, report[0].Matches)
+}
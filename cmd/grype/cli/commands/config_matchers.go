@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/matcher"
+)
+
+type configMatchersOptions struct {
+	Output string              `yaml:"output" json:"output" mapstructure:"output"`
+	Match  options.MatchConfig `yaml:"match" json:"match" mapstructure:"match"`
+}
+
+var _ clio.FlagAdder = (*configMatchersOptions)(nil)
+
+func (o *configMatchersOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", "format to display results (available=[table, json])")
+}
+
+// configMatcherEntry is the effective configuration of a single matcher along with the package types it handles.
+type configMatcherEntry struct {
+	options.MatcherSettings
+	PackageTypes []string `json:"package-types"`
+}
+
+// ConfigMatchers shows the effective per-matcher configuration, after config files, environment variables
+// and defaults have been applied.
+func ConfigMatchers(app clio.Application) *cobra.Command {
+	opts := &configMatchersOptions{
+		Output: "table",
+		Match:  options.DefaultMatchConfig(),
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:     "matchers",
+		Short:   "show the effective configuration of each vulnerability matcher",
+		PreRunE: disableUI(app),
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return presentConfigMatchers(opts.Output, configMatcherEntries(opts.Match), cmd.OutOrStdout())
+		},
+	}, opts)
+}
+
+func configMatcherEntries(cfg options.MatchConfig) []configMatcherEntry {
+	pkgTypes := make(map[string][]string)
+	for _, m := range matcher.NewDefaultMatchers(matcher.Config{}) {
+		var types []string
+		for _, t := range m.PackageTypes() {
+			types = append(types, string(t))
+		}
+		sort.Strings(types)
+		pkgTypes[string(m.Type())] = types
+	}
+
+	var entries []configMatcherEntry
+	for _, s := range cfg.Matchers() {
+		entries = append(entries, configMatcherEntry{
+			MatcherSettings: s,
+			PackageTypes:    pkgTypes[string(s.Matcher)],
+		})
+	}
+	return entries
+}
+
+func presentConfigMatchers(outputFormat string, entries []configMatcherEntry, output io.Writer) error {
+	switch outputFormat {
+	case "table":
+		rows := [][]string{}
+		for _, e := range entries {
+			normalization := e.NameNormalization
+			if normalization == "" {
+				normalization = "(default)"
+			}
+			prerelease := e.PrereleaseComparison
+			if prerelease == "" {
+				prerelease = "(default)"
+			}
+			m := string(e.Matcher)
+			types := strings.Join(e.PackageTypes, ", ")
+			switch {
+			case m == "":
+				m = "(none)"
+			case types == "":
+				// the stock matcher handles any package type without a dedicated matcher
+				types = "(any)"
+			}
+			rows = append(rows, []string{e.Section, m, strconv.FormatBool(e.UseCPEs), strconv.FormatBool(e.AllowPrereleaseConstraints), prerelease, strconv.FormatBool(e.IgnoreBuildMetadata), normalization, types})
+		}
+
+		table := tablewriter.NewWriter(output)
+		columns := []string{"Section", "Matcher", "Using CPEs", "Allow Prerelease Constraints", "Prerelease Comparison", "Ignore Build Metadata", "Name Normalization", "Package Types"}
+
+		table.SetHeader(columns)
+		table.SetAutoWrapText(false)
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+		table.SetHeaderLine(false)
+		table.SetBorder(false)
+		table.SetAutoFormatHeaders(true)
+		table.SetCenterSeparator("")
+		table.SetColumnSeparator("")
+		table.SetRowSeparator("")
+		table.SetTablePadding("  ")
+		table.SetNoWhiteSpace(true)
+
+		table.AppendBulk(rows)
+		table.Render()
+	case "json":
+		enc := json.NewEncoder(output)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", " ")
+		if err := enc.Encode(entries); err != nil {
+			return fmt.Errorf("failed to encode matcher configuration: %+v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+	return nil
+}
@@ -8,7 +8,8 @@ import (
 )
 
 type DBOptions struct {
-	DB options.Database `yaml:"db" json:"db" mapstructure:"db"`
+	DB   options.Database `yaml:"db" json:"db" mapstructure:"db"`
+	FIPS options.FIPSMode `yaml:"fips" json:"fips" mapstructure:"fips"`
 }
 
 func dbOptionsDefault(id clio.Identification) *DBOptions {
@@ -27,14 +28,19 @@ func DB(app clio.Application) *cobra.Command {
 	}
 
 	db.AddCommand(
+		DBBuild(app),
 		DBCheck(app),
 		DBDelete(app),
 		DBDiff(app),
+		DBExportOSV(app),
+		DBFixture(app),
 		DBImport(app),
 		DBList(app),
+		DBMerge(app),
 		DBStatus(app),
 		DBUpdate(app),
 		DBSearch(app),
+		DBWarm(app),
 	)
 
 	return db
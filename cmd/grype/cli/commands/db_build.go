@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/db/build"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/internal/bus"
+)
+
+type dbBuildOptions struct {
+	Output    string `yaml:"output" json:"output" mapstructure:"output"`
+	BaseURL   string `yaml:"base-url" json:"base-url" mapstructure:"base-url"`
+	Import    bool   `yaml:"import" json:"import" mapstructure:"import"`
+	DBOptions `yaml:",inline" mapstructure:",squash"`
+}
+
+var _ clio.FlagAdder = (*dbBuildOptions)(nil)
+
+func (o *dbBuildOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", "the directory to write the vulnerability database and its archive to")
+	flags.StringVarP(&o.BaseURL, "base-url", "", "the URL the archive will be served from, to write a listing of the archive for the db.update-url of clients")
+	flags.BoolVarP(&o.Import, "import", "", "import the built archive as the local vulnerability database")
+}
+
+func DBBuild(app clio.Application) *cobra.Command {
+	opts := &dbBuildOptions{
+		DBOptions: *dbOptionsDefault(app.ID()),
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "build [OSV OR NVD JSON FILE OR DIR...] --output [DIR]",
+		Short: "build a vulnerability database from OSV records and NVD CVE records",
+		Example: `  grype db build ./advisories --output ./db
+  grype db build ./osv ./nvd/cves.json --output ./db --base-url https://db.example.com/grype`,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: disableUI(app),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runDBBuild(opts, args)
+		},
+	}, opts)
+}
+
+func runDBBuild(opts *dbBuildOptions, inputs []string) error {
+	if opts.Output == "" {
+		return fmt.Errorf("an output directory is required (--output)")
+	}
+
+	result, err := build.Build(build.Config{
+		Inputs:  inputs,
+		Dir:     opts.Output,
+		BaseURL: opts.BaseURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.Import {
+		dbCurator, err := distribution.NewCurator(opts.DB.ToCuratorConfig())
+		if err != nil {
+			return err
+		}
+		if err := dbCurator.ImportFrom(result.ArchivePath); err != nil {
+			return fmt.Errorf("unable to import vulnerability database: %w", err)
+		}
+	}
+
+	report := fmt.Sprintf("built a vulnerability database of %d records to %s", result.Vulnerabilities, result.ArchivePath)
+	if result.ListingPath != "" {
+		report += fmt.Sprintf(" (listed in %s)", result.ListingPath)
+	}
+	bus.Report(report)
+	return nil
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/osv"
+	"github.com/anchore/grype/internal/bus"
+	"github.com/anchore/grype/internal/log"
+)
+
+type dbExportOSVOptions struct {
+	Ecosystems []string `yaml:"ecosystems" json:"ecosystems" mapstructure:"ecosystems"`
+	Output     string   `yaml:"output" json:"output" mapstructure:"output"`
+	DBOptions  `yaml:",inline" mapstructure:",squash"`
+}
+
+var _ clio.FlagAdder = (*dbExportOSVOptions)(nil)
+
+func (o *dbExportOSVOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringArrayVarP(&o.Ecosystems, "ecosystem", "e", `the OSV ecosystems to export (e.g. "npm", "PyPI", "Debian:12" or "Debian" for all releases), default is all ecosystems`)
+	flags.StringVarP(&o.Output, "output", "o", "the directory to write one OSV JSON file per advisory to")
+}
+
+func DBExportOSV(app clio.Application) *cobra.Command {
+	opts := &dbExportOSVOptions{
+		DBOptions: *dbOptionsDefault(app.ID()),
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "export-osv --output [DIR]",
+		Short: "export the vulnerability database content as OSV JSON records",
+		Example: `  grype db export-osv --ecosystem npm --output ./osv
+  grype db export-osv --ecosystem Debian:12 --ecosystem Alpine --output ./osv`,
+		Args:    cobra.ExactArgs(0),
+		PreRunE: disableUI(app),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runDBExportOSV(opts)
+		},
+	}, opts)
+}
+
+func runDBExportOSV(opts *dbExportOSVOptions) error {
+	if opts.Output == "" {
+		return fmt.Errorf("an output directory is required (--output)")
+	}
+
+	dbCurator, err := distribution.NewCurator(opts.DB.ToCuratorConfig())
+	if err != nil {
+		return err
+	}
+
+	if opts.DB.AutoUpdate {
+		if _, err := dbCurator.Update(); err != nil {
+			return err
+		}
+	}
+
+	reader, closer, err := dbCurator.GetStore()
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	log.Debug("converting DB records to OSV")
+	records, err := osv.FromDB(reader, opts.Ecosystems...)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no vulnerability records found for the selected ecosystems")
+	}
+
+	if err := osv.WriteDir(opts.Output, records); err != nil {
+		return err
+	}
+
+	bus.Report(fmt.Sprintf("exported %d OSV records to %s", len(records), opts.Output))
+	return nil
+}
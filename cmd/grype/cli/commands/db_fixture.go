@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/db/build"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/internal/bus"
+)
+
+type dbFixtureOptions struct {
+	Output    string `yaml:"output" json:"output" mapstructure:"output"`
+	BaseURL   string `yaml:"base-url" json:"base-url" mapstructure:"base-url"`
+	Import    bool   `yaml:"import" json:"import" mapstructure:"import"`
+	DBOptions `yaml:",inline" mapstructure:",squash"`
+}
+
+var _ clio.FlagAdder = (*dbFixtureOptions)(nil)
+
+func (o *dbFixtureOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", "the directory to write the fixture database and its archive to")
+	flags.StringVarP(&o.BaseURL, "base-url", "", "the URL the archive will be served from, to write a listing of the archive for the db.update-url of clients")
+	flags.BoolVarP(&o.Import, "import", "", "import the built archive as the local vulnerability database, replacing the database installed (even if built later)")
+}
+
+func DBFixture(app clio.Application) *cobra.Command {
+	opts := &dbFixtureOptions{
+		DBOptions: *dbOptionsDefault(app.ID()),
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "fixture [SPEC FILE] --output [DIR]",
+		Short: "build a tiny (schema v5) vulnerability database of synthetic vulnerabilities from a YAML spec, for hermetic tests",
+		Example: `  grype db fixture ./spec.yaml --output ./db
+  grype db fixture ./spec.yaml --output ./db --import`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: disableUI(app),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runDBFixture(opts, args[0])
+		},
+	}, opts)
+}
+
+func runDBFixture(opts *dbFixtureOptions, specPath string) error {
+	if opts.Output == "" {
+		return fmt.Errorf("an output directory is required (--output)")
+	}
+
+	spec, err := build.ReadFixtureSpec(specPath)
+	if err != nil {
+		return err
+	}
+	result, err := build.BuildFixture(*spec, opts.Output, opts.BaseURL)
+	if err != nil {
+		return err
+	}
+
+	if opts.Import {
+		// the fixed build time of a fixture is usually before the db installed: importing it is a deliberate downgrade
+		opts.DB.AllowRollback = true
+		dbCurator, err := distribution.NewCurator(opts.DB.ToCuratorConfig())
+		if err != nil {
+			return err
+		}
+		if err := dbCurator.ImportFrom(result.ArchivePath); err != nil {
+			return fmt.Errorf("unable to import vulnerability database: %w", err)
+		}
+	}
+
+	bus.Report(fmt.Sprintf("built a fixture vulnerability database of %d vulnerabilities to %s", result.Vulnerabilities, result.ArchivePath))
+	return nil
+}
@@ -2,17 +2,33 @@ package commands
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/anchore/clio"
 	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/db/build"
 	"github.com/anchore/grype/grype/db/legacy/distribution"
 	"github.com/anchore/grype/internal"
 )
 
+type dbImportOptions struct {
+	Overlay   string `yaml:"overlay" json:"overlay" mapstructure:"overlay"`
+	DBOptions `yaml:",inline" mapstructure:",squash"`
+}
+
+var _ clio.FlagAdder = (*dbImportOptions)(nil)
+
+func (o *dbImportOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.Overlay, "overlay", "", "a vulnerability database (file, directory or archive) to layer over the imported database (see 'grype db merge')")
+	o.DB.AddRollbackFlags(flags)
+}
+
 func DBImport(app clio.Application) *cobra.Command {
-	opts := dbOptionsDefault(app.ID())
+	opts := &dbImportOptions{
+		DBOptions: *dbOptionsDefault(app.ID()),
+	}
 
 	return app.SetupCommand(&cobra.Command{
 		Use:     "import FILE",
@@ -21,17 +37,31 @@ func DBImport(app clio.Application) *cobra.Command {
 		Args:    cobra.ExactArgs(1),
 		PreRunE: disableUI(app),
 		RunE: func(_ *cobra.Command, args []string) error {
-			return runDBImport(opts.DB, args[0])
+			return runDBImport(opts.DB, args[0], opts.Overlay)
 		},
 	}, opts)
 }
 
-func runDBImport(opts options.Database, dbArchivePath string) error {
+func runDBImport(opts options.Database, dbArchivePath, overlay string) error {
 	dbCurator, err := distribution.NewCurator(opts.ToCuratorConfig())
 	if err != nil {
 		return err
 	}
 
+	if overlay != "" {
+		mergeDir, err := os.MkdirTemp("", "grype-import-overlay")
+		if err != nil {
+			return fmt.Errorf("unable to create temp dir for the overlay: %w", err)
+		}
+		defer os.RemoveAll(mergeDir)
+
+		result, err := build.Merge(build.MergeConfig{Base: dbArchivePath, Overlay: overlay, Dir: mergeDir})
+		if err != nil {
+			return fmt.Errorf("unable to layer the overlay over the vulnerability database: %w", err)
+		}
+		dbArchivePath = result.ArchivePath
+	}
+
 	if err := dbCurator.ImportFrom(dbArchivePath); err != nil {
 		return fmt.Errorf("unable to import vulnerability database: %+v", err)
 	}
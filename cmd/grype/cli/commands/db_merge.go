@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/db/build"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/internal/bus"
+)
+
+type dbMergeOptions struct {
+	Output    string `yaml:"output" json:"output" mapstructure:"output"`
+	BaseURL   string `yaml:"base-url" json:"base-url" mapstructure:"base-url"`
+	Import    bool   `yaml:"import" json:"import" mapstructure:"import"`
+	DBOptions `yaml:",inline" mapstructure:",squash"`
+}
+
+var _ clio.FlagAdder = (*dbMergeOptions)(nil)
+
+func (o *dbMergeOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", "the directory to write the merged vulnerability database and its archive to")
+	flags.StringVarP(&o.BaseURL, "base-url", "", "the URL the archive will be served from, to write a listing of the archive for the db.update-url of clients")
+	flags.BoolVarP(&o.Import, "import", "", "import the merged archive as the local vulnerability database")
+}
+
+func DBMerge(app clio.Application) *cobra.Command {
+	opts := &dbMergeOptions{
+		DBOptions: *dbOptionsDefault(app.ID()),
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "merge BASE OVERLAY --output [DIR]",
+		Short: "layer an overlay vulnerability database over a base vulnerability database",
+		Long: `layer an overlay vulnerability database (extra advisories, severity overrides and match exclusions) over a
+base vulnerability database, such as the official one. Each database is a database file, a directory holding
+a database file, or a database archive.
+
+The records of an advisory and namespace of the overlay replace those of the base, the non-empty fields of a
+metadata record of the overlay override those of the base, and the match exclusions of the overlay are added.`,
+		Example: `  grype db merge vulnerability-db_v5_2024-03-01T00:00:00Z.tar.gz ./overlay/vulnerability.db --output ./db
+  grype db merge ~/.cache/grype/db/5 ./overlay --output ./db --import`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: disableUI(app),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runDBMerge(opts, args[0], args[1])
+		},
+	}, opts)
+}
+
+func runDBMerge(opts *dbMergeOptions, base, overlay string) error {
+	if opts.Output == "" {
+		return fmt.Errorf("an output directory is required (--output)")
+	}
+
+	result, err := build.Merge(build.MergeConfig{
+		Base:    base,
+		Overlay: overlay,
+		Dir:     opts.Output,
+		BaseURL: opts.BaseURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.Import {
+		dbCurator, err := distribution.NewCurator(opts.DB.ToCuratorConfig())
+		if err != nil {
+			return err
+		}
+		if err := dbCurator.ImportFrom(result.ArchivePath); err != nil {
+			return fmt.Errorf("unable to import vulnerability database: %w", err)
+		}
+	}
+
+	stats := result.Stats
+	report := fmt.Sprintf("merged %d vulnerability records (replacing %d), %d metadata records (overriding %d) and %d match exclusions into %s",
+		stats.Vulnerabilities, stats.ReplacedVulnerabilities, stats.Metadata, stats.OverriddenMetadata, stats.Exclusions, result.ArchivePath)
+	if result.ListingPath != "" {
+		report += fmt.Sprintf(" (listed in %s)", result.ListingPath)
+	}
+	bus.Report(report)
+	return nil
+}
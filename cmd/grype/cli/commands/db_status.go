@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -41,6 +42,13 @@ func runDBStatus(opts options.Database) error {
 	fmt.Println("Built:    ", status.Built.String())
 	fmt.Println("Schema:   ", status.SchemaVersion)
 	fmt.Println("Checksum: ", status.Checksum)
+	if status.Provenance != nil {
+		fmt.Println("Builder:  ", status.Provenance.Builder)
+		fmt.Println("Sources:  ", len(status.Provenance.Sources))
+	}
+	if len(status.Ecosystems) > 0 {
+		fmt.Println("Ecosystems:", strings.Join(status.Ecosystems, ", "))
+	}
 	fmt.Println("Status:   ", statusStr)
 
 	return status.Err
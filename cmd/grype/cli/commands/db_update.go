@@ -12,8 +12,20 @@ import (
 	"github.com/anchore/grype/internal/log"
 )
 
+type dbUpdateOptions struct {
+	DBOptions `yaml:",inline" mapstructure:",squash"`
+}
+
+var _ clio.FlagAdder = (*dbUpdateOptions)(nil)
+
+func (o *dbUpdateOptions) AddFlags(flags clio.FlagSet) {
+	o.DB.AddRollbackFlags(flags)
+}
+
 func DBUpdate(app clio.Application) *cobra.Command {
-	opts := dbOptionsDefault(app.ID())
+	opts := &dbUpdateOptions{
+		DBOptions: *dbOptionsDefault(app.ID()),
+	}
 
 	return app.SetupCommand(&cobra.Command{
 		Use:   "update",
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+)
+
+func DBWarm(app clio.Application) *cobra.Command {
+	opts := dbOptionsDefault(app.ID())
+
+	return app.SetupCommand(&cobra.Command{
+		Use:     "warm",
+		Short:   "prepare the installed vulnerability database so the next scan starts quickly",
+		Args:    cobra.ExactArgs(0),
+		PreRunE: disableUI(app),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runDBWarm(opts.DB)
+		},
+	}, opts)
+}
+
+func runDBWarm(opts options.Database) error {
+	dbCurator, err := distribution.NewCurator(opts.ToCuratorConfig())
+	if err != nil {
+		return err
+	}
+
+	result, err := dbCurator.Warm()
+	if err != nil {
+		return fmt.Errorf("unable to warm vulnerability database: %+v", err)
+	}
+
+	return stderrPrintLnf("Vulnerability database warmed (%d files, %s) in %s", result.Files, humanize.Bytes(uint64(result.Bytes)), result.Duration.Round(time.Millisecond))
+}
@@ -0,0 +1,365 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/pkg/cataloger/macos"
+	"github.com/anchore/grype/internal/bus"
+	"github.com/anchore/grype/internal/format"
+	"github.com/anchore/grype/internal/resources"
+)
+
+// scanPlan is what a scan would do with the effective configuration, as sections of "label: value" lines.
+type scanPlan struct {
+	sections []planSection
+}
+
+type planSection struct {
+	title string
+	lines [][2]string
+}
+
+func (p *scanPlan) section(title string) *planSection {
+	p.sections = append(p.sections, planSection{title: title})
+	return &p.sections[len(p.sections)-1]
+}
+
+func (s *planSection) add(label, value string) {
+	s.lines = append(s.lines, [2]string{label, value})
+}
+
+func (p scanPlan) String() string {
+	var sb strings.Builder
+	for i, s := range p.sections {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(s.title + ":\n")
+		width := 0
+		for _, l := range s.lines {
+			width = max(width, len(l[0]))
+		}
+		for _, l := range s.lines {
+			if l[0] == "" {
+				fmt.Fprintf(&sb, "  - %s\n", l[1])
+				continue
+			}
+			fmt.Fprintf(&sb, "  %-*s  %s\n", width+1, l[0]+":", l[1])
+		}
+	}
+	return sb.String()
+}
+
+// runDryRun reports what the scan of the given input would do, without cataloging the input, loading the
+// vulnerability database or writing any report.
+func runDryRun(opts *options.Grype, userInput string) error {
+	plan, err := makeScanPlan(opts, userInput)
+	if err != nil {
+		return err
+	}
+	bus.Report(plan.String())
+	return nil
+}
+
+func makeScanPlan(opts *options.Grype, userInput string) (*scanPlan, error) {
+	outputs, err := format.ParseOutputs(opts.Outputs, opts.File)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveIgnoreRules(opts); err != nil {
+		return nil, err
+	}
+	if err := applyVexRules(opts); err != nil {
+		return nil, fmt.Errorf("applying vex rules: %w", err)
+	}
+
+	plan := &scanPlan{}
+	input := pkg.ResolveInput(userInput)
+	planTarget(plan.section("Target"), input, opts)
+	planCatalogers(plan.section("Catalogers"), input, opts)
+	planMatchers(plan.section("Matchers"), opts)
+	planVulnerabilityData(plan.section("Vulnerability data"), opts)
+	planIgnoreRules(plan.section(fmt.Sprintf("Ignore rules (%d)", len(opts.Ignore))), opts)
+	planOutputs(plan.section("Outputs"), outputs, opts)
+	if limits := opts.Limits.ToLimits(); limits.CPUs > 0 || limits.Memory > 0 || opts.MaxScanDuration() > 0 {
+		planLimits(plan.section("Limits"), limits, opts.MaxScanDuration())
+	}
+	return plan, nil
+}
+
+func planTarget(s *planSection, input pkg.Input, opts *options.Grype) {
+	reference := input.Reference
+	if input.Kind == pkg.SBOMInput && reference == "" {
+		reference = "(stdin)"
+	}
+	s.add("input", reference)
+	s.add("kind", string(input.Kind))
+
+	if input.Kind == pkg.ImageInput {
+		source := input.Source
+		switch {
+		case source != "":
+			source += " (from the scheme of the input)"
+		case opts.DefaultImagePullSource != "":
+			source = opts.DefaultImagePullSource + " (default image pull source)"
+		default:
+			source = "detected when scanning (a local archive or layout, a daemon, or else a registry)"
+		}
+		s.add("source", source)
+		if opts.Platform != "" {
+			s.add("platform", opts.Platform)
+		}
+		if len(opts.Platforms) > 0 {
+			s.add("platforms", strings.Join(opts.Platforms, ", "))
+		}
+		if opts.UseReferrers {
+			referrers := "an attached SBOM is used for OCI layouts"
+			if opts.TrustReferrerVEX {
+				referrers = "an attached SBOM and VEX documents are used for OCI layouts"
+			}
+			s.add("referrers", referrers)
+		}
+	}
+	if opts.Distro != "" {
+		s.add("distro", opts.Distro+" (overriding the detected distro)")
+	}
+}
+
+func planCatalogers(s *planSection, input pkg.Input, opts *options.Grype) {
+	switch input.Kind {
+	case pkg.SBOMInput:
+		s.add("packages", "read from the SBOM ("+strings.Join(sbomFormats(opts), ", ")+")")
+	case pkg.PURLInput:
+		s.add("packages", "read from the purls")
+	default:
+		tag := "directory"
+		switch input.Kind {
+		case pkg.ImageInput:
+			tag = "image"
+		case pkg.RuntimeInput:
+			tag = "installed"
+		}
+		s.add("selection", fmt.Sprintf("the syft catalogers tagged %q", tag))
+		if input.Kind == pkg.RuntimeInput {
+			s.add("packages", "the libraries of the language of the "+input.Source+" environment, and its interpreter")
+		}
+		var added []string
+		for _, ref := range macos.CatalogerReferences() {
+			if slices.Contains(ref.Tags, tag) {
+				added = append(added, ref.Cataloger.Name())
+			}
+		}
+		if len(added) > 0 {
+			s.add("added", strings.Join(added, ", "))
+		}
+		if opts.Search.Scope != "" {
+			s.add("scope", opts.Search.Scope)
+		}
+		if input.Kind == pkg.ImageInput && opts.LayerCache.Enabled {
+			s.add("layer cache", opts.LayerCache.Dir+" (only the layers not cached are cataloged)")
+		}
+	}
+	if len(opts.Lockfiles) > 0 {
+		s.add("lockfiles", strings.Join(opts.Lockfiles, ", "))
+	}
+	if len(opts.Exclusions) > 0 {
+		s.add("exclusions", strings.Join(opts.Exclusions, ", "))
+	}
+	if len(opts.IncludePaths) > 0 {
+		s.add("include paths", strings.Join(opts.IncludePaths, ", "))
+	}
+	if len(opts.ExcludePaths) > 0 {
+		s.add("exclude paths", strings.Join(opts.ExcludePaths, ", "))
+	}
+	if opts.Layers != "" {
+		s.add("layers", opts.Layers)
+	}
+}
+
+func sbomFormats(opts *options.Grype) []string {
+	formats := []string{"syft, SPDX or CycloneDX"}
+	for _, d := range opts.SBOMDecoders {
+		formats = append(formats, d.Name+" with "+d.Command)
+	}
+	return formats
+}
+
+func planMatchers(s *planSection, opts *options.Grype) {
+	for _, m := range getMatchers(opts) {
+		var types []string
+		for _, t := range m.PackageTypes() {
+			types = append(types, string(t))
+		}
+		sort.Strings(types)
+		if len(types) == 0 {
+			// the stock matcher handles any package type without a dedicated matcher
+			types = []string{"(any other)"}
+		}
+		s.add(string(m.Type()), strings.Join(types, ", "))
+	}
+}
+
+func planVulnerabilityData(s *planSection, opts *options.Grype) {
+	updates := "disabled"
+	if opts.DB.AutoUpdate {
+		updates = "checked at " + opts.DB.UpdateURL
+	}
+
+	curator, err := distribution.NewCurator(opts.DB.ToCuratorConfig())
+	if err != nil {
+		s.add("database", fmt.Sprintf("unavailable: %v", err))
+		s.add("updates", updates)
+		return
+	}
+	status := curator.Status()
+	if status.Err != nil {
+		s.add("database", fmt.Sprintf("%s (invalid: %v)", opts.DB.Dir, status.Err))
+	} else {
+		s.add("database", fmt.Sprintf("%s (schema v%d, built %s)", status.Location, status.SchemaVersion, status.Built.UTC().Format("2006-01-02 15:04:05 UTC")))
+	}
+	s.add("updates", updates)
+	if status.Err == nil {
+		if providers := dbProviders(status); len(providers) > 0 {
+			s.add("providers", strings.Join(providers, ", "))
+		}
+	}
+
+	if cfg := opts.ExternalSources.ToJavaMatcherConfig(); cfg.SearchMavenUpstream {
+		s.add("maven", "searched at "+cfg.MavenBaseURL)
+	}
+	if cfg, ok := opts.ExternalSources.ToOSVProviderConfig(opts.DB.Dir); ok {
+		ecosystems := "all ecosystems"
+		if len(cfg.Ecosystems) > 0 {
+			ecosystems = strings.Join(cfg.Ecosystems, ", ")
+		}
+		s.add("osv", fmt.Sprintf("queried at %s for %s", cfg.BaseURL, ecosystems))
+	}
+	if opts.PendingAnalysis.Enabled {
+		s.add("pending analysis", "enabled")
+	}
+	if opts.Malware.Enabled {
+		s.add("malware", "enabled")
+	}
+	if opts.Typosquatting.Enabled {
+		s.add("typosquatting", "enabled")
+	}
+	if opts.PackageStatus.Enabled {
+		s.add("package status", "enabled")
+	}
+	if opts.BaseImageAdvice.Enabled {
+		s.add("base image advice", "enabled")
+	}
+	if opts.Aliases.Enabled {
+		s.add("aliases", "enabled")
+	}
+	if opts.Localization.Lang != "" {
+		s.add("language", opts.Localization.Lang)
+	}
+}
+
+// dbProviders returns the providers of the database (e.g. "nvd" or "alpine"), or else its ecosystems.
+func dbProviders(status distribution.Status) []string {
+	if status.Provenance == nil || len(status.Provenance.Sources) == 0 {
+		return status.Ecosystems
+	}
+	var providers []string
+	for _, source := range status.Provenance.Sources {
+		providers = append(providers, source.Provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+func planIgnoreRules(s *planSection, opts *options.Grype) {
+	for _, rule := range opts.Ignore {
+		s.add("", describeIgnoreRule(rule))
+	}
+	for _, doc := range opts.VexDocuments {
+		s.add("", "VEX document "+doc)
+	}
+}
+
+// describeIgnoreRule returns the criteria of the rule, as the "key=value" pairs of its configuration.
+func describeIgnoreRule(rule match.IgnoreRule) string {
+	var criteria []string
+	add := func(key, value string) {
+		if value != "" {
+			criteria = append(criteria, key+"="+value)
+		}
+	}
+	add("vulnerability", rule.Vulnerability)
+	add("namespace", rule.Namespace)
+	add("fix-state", rule.FixState)
+	add("package.name", rule.Package.Name)
+	add("package.version", rule.Package.Version)
+	add("package.language", rule.Package.Language)
+	add("package.type", rule.Package.Type)
+	add("package.location", rule.Package.Location)
+	add("package.upstream-name", rule.Package.UpstreamName)
+	add("vex-status", rule.VexStatus)
+	add("vex-justification", rule.VexJustification)
+	add("match-type", string(rule.MatchType))
+	add("published-within", rule.PublishedWithin)
+	add("published-before", rule.PublishedBefore)
+	add("modified-within", rule.ModifiedWithin)
+	add("modified-before", rule.ModifiedBefore)
+	add("environment", rule.Environment)
+	description := strings.Join(criteria, " ")
+	if rule.Reason != "" {
+		description += fmt.Sprintf(" (%s)", rule.Reason)
+	}
+	return description
+}
+
+func planOutputs(s *planSection, outputs []format.Output, opts *options.Grype) {
+	for _, o := range outputs {
+		destination := "stdout"
+		if o.Path != "" {
+			destination = o.Path
+		}
+		name := string(o.Format)
+		if o.SarifProfile != "" {
+			name += " (" + string(o.SarifProfile) + " profile)"
+		}
+		s.add(name, destination)
+	}
+	if opts.OutputTemplateFile != "" {
+		s.add("template", opts.OutputTemplateFile)
+	}
+	if opts.FailOn != "" {
+		s.add("fail on", opts.FailOnSeverity().String()+" severity or higher")
+	}
+	if opts.Strict {
+		s.add("strict", "data-quality problems fail the scan")
+	}
+	if policy := opts.LicensePolicy.ToPolicy(); !policy.IsEmpty() {
+		description := fmt.Sprintf("%d denied, %d allowed, %d exceptions", len(policy.Deny), len(policy.Allow), len(policy.Exceptions))
+		if opts.LicensePolicy.FailOnViolation {
+			description += " (violations fail the scan)"
+		}
+		s.add("license policy", description)
+	}
+}
+
+func planLimits(s *planSection, limits resources.Limits, maxScanTime time.Duration) {
+	if limits.CPUs > 0 {
+		s.add("cpus", strconv.Itoa(limits.CPUs))
+	}
+	if limits.Memory > 0 {
+		s.add("memory", humanize.IBytes(limits.Memory))
+	}
+	if maxScanTime > 0 {
+		s.add("max scan time", maxScanTime.String()+" (then the results are partial)")
+	}
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/match"
+)
+
+func Test_makeScanPlan(t *testing.T) {
+	opts := options.DefaultGrype(clio.Identification{Name: "grype"})
+	opts.DB.Dir = t.TempDir()
+	opts.DB.AutoUpdate = false
+	opts.OnlyFixed = true
+	opts.MatchUpstreamKernelHeaders = true
+	opts.Ignore = []match.IgnoreRule{{Vulnerability: "CVE-2024-1234", Reason: "not exploitable"}}
+	opts.Outputs = []string{"table", "json=report.json"}
+	opts.FailOn = "high"
+	opts.MaxScanTime = "2m"
+	opts.UseReferrers = true
+
+	plan, err := makeScanPlan(opts, "registry:alpine:3.20")
+	require.NoError(t, err)
+	got := plan.String()
+
+	for _, expected := range []string{
+		"Target:\n  input:      alpine:3.20\n  kind:       image\n  source:     registry (from the scheme of the input)\n",
+		"  referrers:  an attached SBOM is used for OCI layouts\n",
+		`  selection:  the syft catalogers tagged "image"`,
+		"  apk-matcher:         apk\n",
+		"  stock-matcher:       (any other)\n",
+		"  updates:   disabled\n",
+		"Ignore rules (4):\n  - vulnerability=CVE-2024-1234 (not exploitable)\n  - fix-state=not-fixed\n",
+		"Outputs:\n  table:    stdout\n  json:     report.json\n  fail on:  high severity or higher\n",
+		"Limits:\n  max scan time:  2m0s (then the results are partial)\n",
+	} {
+		assert.Contains(t, got, expected)
+	}
+
+	// the options are validated as for a scan
+	opts.Outputs = []string{"unknown"}
+	_, err = makeScanPlan(opts, "alpine:3.20")
+	assert.ErrorContains(t, err, `unsupported output format "unknown"`)
+}
+
+func Test_describeIgnoreRule(t *testing.T) {
+	rule := match.IgnoreRule{
+		Vulnerability: "CVE-2024-1234",
+		FixState:      "wont-fix",
+		Package:       match.IgnoreRulePackage{Name: "openssl", Type: "apk"},
+		Reason:        "accepted risk",
+	}
+	assert.Equal(t, "vulnerability=CVE-2024-1234 fix-state=wont-fix package.name=openssl package.type=apk (accepted risk)", describeIgnoreRule(rule))
+}
@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/history"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+type historyOptions struct {
+	Output        string              `yaml:"output" json:"output" mapstructure:"output"`
+	Vulnerability string              `yaml:"vulnerability" json:"vulnerability" mapstructure:"vulnerability"`
+	Scan          int                 `yaml:"scan" json:"scan" mapstructure:"scan"`
+	History       options.ScanHistory `yaml:"history" json:"history" mapstructure:"history"`
+}
+
+var _ interface {
+	clio.FlagAdder
+	clio.PostLoader
+} = (*historyOptions)(nil)
+
+func (o *historyOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", "format to display results (available=[table, json])")
+	flags.StringVarP(&o.Vulnerability, "vulnerability", "", "show when the vulnerability was first and last reported for each target and package")
+	flags.IntVarP(&o.Scan, "scan", "", "show the findings of the scan with the given ID")
+}
+
+func (o *historyOptions) PostLoad() error {
+	switch o.Output {
+	case "table", "json":
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.Output)
+	}
+	if o.Scan < 0 {
+		return fmt.Errorf("invalid scan ID: %d", o.Scan)
+	}
+	if o.Vulnerability != "" && o.Scan != 0 {
+		return fmt.Errorf("only one of --vulnerability and --scan can be given")
+	}
+	return nil
+}
+
+func History(app clio.Application) *cobra.Command {
+	opts := &historyOptions{
+		Output:  "table",
+		History: options.DefaultScanHistory(app.ID()),
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "history [TARGET]",
+		Short: "query the history of recorded scans",
+		Long: `Query the scans recorded in the scan history database (see the "history" configuration).
+
+Lists the recorded scans (of the given target only, when given) by default, the findings of a single
+scan with --scan, or when a vulnerability was first and last reported with --vulnerability.`,
+		Example: `  grype history alpine:3.20
+  grype history alpine:3.20 --vulnerability CVE-2024-6119
+  grype history --scan 12 -o json`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: disableUI(app),
+		RunE: func(_ *cobra.Command, args []string) error {
+			target := ""
+			if len(args) > 0 {
+				target = args[0]
+			}
+			return runHistory(opts, target, os.Stdout)
+		},
+	}, opts)
+}
+
+func runHistory(opts *historyOptions, target string, output io.Writer) error {
+	if _, err := os.Stat(opts.History.Path); err != nil {
+		return fmt.Errorf("no scan history at %q (enable it with the history.enabled configuration): %w", opts.History.Path, err)
+	}
+
+	s, err := history.Open(opts.History.Path)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	switch {
+	case opts.Vulnerability != "":
+		occurrences, err := s.Occurrences(opts.Vulnerability, target)
+		if err != nil {
+			return err
+		}
+		return presentHistory(opts.Output, occurrences, output, []string{"Target", "Name", "Installed", "Type", "First Seen", "Last Seen", "Scans"}, func(o history.Occurrence) []string {
+			return []string{o.Target, o.Package, o.Version, o.Type, historyTime(o.FirstSeen), historyTime(o.LastSeen), strconv.Itoa(o.Scans)}
+		})
+	case opts.Scan != 0:
+		scan, err := s.Scan(uint(opts.Scan))
+		if err != nil {
+			return err
+		}
+		if scan == nil {
+			return fmt.Errorf("no recorded scan with ID %d", opts.Scan)
+		}
+		if opts.Output == "json" {
+			return encodeHistory(output, scan)
+		}
+		return presentHistory(opts.Output, scan.Findings, output, []string{"Name", "Installed", "Type", "Vulnerability", "Severity"}, func(f history.Finding) []string {
+			return []string{f.Package, f.Version, f.Type, f.Vulnerability, f.Severity}
+		})
+	default:
+		scans, err := s.Scans(target)
+		if err != nil {
+			return err
+		}
+		return presentHistory(opts.Output, scans, output, []string{"ID", "Target", "Digest", "Scanned", "DB Built"}, func(s history.Scan) []string {
+			return []string{strconv.FormatUint(uint64(s.ID), 10), s.Target, s.TargetDigest, historyTime(s.Timestamp), historyTime(s.DBBuilt)}
+		})
+	}
+}
+
+func presentHistory[T any](outputFormat string, items []T, output io.Writer, columns []string, row func(T) []string) error {
+	if outputFormat == "json" {
+		if items == nil {
+			items = []T{}
+		}
+		return encodeHistory(output, items)
+	}
+
+	rows := [][]string{}
+	for _, item := range items {
+		rows = append(rows, row(item))
+	}
+
+	table := tablewriter.NewWriter(output)
+
+	table.SetHeader(columns)
+	table.SetAutoWrapText(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+
+	table.AppendBulk(rows)
+	table.Render()
+	return nil
+}
+
+func encodeHistory(output io.Writer, value any) error {
+	enc := json.NewEncoder(output)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	if err := enc.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode scan history: %+v", err)
+	}
+	return nil
+}
+
+func historyTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// recordScanHistory records the scan of the target in the scan history database.
+func recordScanHistory(cfg options.ScanHistory, target string, pkgContext pkg.Context, status *distribution.Status, matches match.Matches, metadataProvider vulnerability.MetadataProvider) error {
+	if target == "" && pkgContext.Source != nil {
+		target = pkgContext.Source.Name
+	}
+
+	scan := history.Scan{
+		Target:       target,
+		TargetDigest: history.TargetDigest(pkgContext.Source),
+		Timestamp:    time.Now(),
+		Findings:     history.NewFindings(matches, metadataProvider),
+	}
+	if status != nil {
+		scan.DBBuilt = status.Built
+		scan.DBSchema = status.SchemaVersion
+		scan.DBChecksum = status.Checksum
+	}
+
+	s, err := history.Open(cfg.Path)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	_, err = s.Record(scan)
+	return err
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/history"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+func Test_recordScanHistory(t *testing.T) {
+	cfg := options.ScanHistory{Enabled: true, Path: filepath.Join(t.TempDir(), "history.db")}
+	built := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	status := &distribution.Status{Built: built, SchemaVersion: 5, Checksum: "sha256:db"}
+	pkgContext := pkg.Context{Source: &source.Description{Name: "alpine", Metadata: source.ImageMetadata{ManifestDigest: "sha256:image"}}}
+	openssl := pkg.Package{ID: "1", Name: "openssl", Version: "3.1.4", Type: syftPkg.ApkPkg}
+	matches := match.NewMatches(match.Match{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0001"}, Package: openssl})
+
+	require.NoError(t, recordScanHistory(cfg, "alpine:3.19", pkgContext, status, matches, nil))
+	// without a user input the target is named after the source
+	require.NoError(t, recordScanHistory(cfg, "", pkgContext, status, match.NewMatches(), nil))
+
+	opts := &historyOptions{Output: "json", History: cfg}
+	var out bytes.Buffer
+	require.NoError(t, runHistory(opts, "", &out))
+	var scans []history.Scan
+	require.NoError(t, json.Unmarshal(out.Bytes(), &scans))
+	require.Len(t, scans, 2)
+	assert.Equal(t, "alpine", scans[0].Target)
+	assert.Equal(t, "alpine:3.19", scans[1].Target)
+	assert.Equal(t, "sha256:image", scans[1].TargetDigest)
+	assert.Equal(t, built, scans[1].DBBuilt)
+	assert.Equal(t, "sha256:db", scans[1].DBChecksum)
+
+	opts.Vulnerability = "CVE-2024-0001"
+	out.Reset()
+	require.NoError(t, runHistory(opts, "alpine:3.19", &out))
+	var occurrences []history.Occurrence
+	require.NoError(t, json.Unmarshal(out.Bytes(), &occurrences))
+	require.Len(t, occurrences, 1)
+	assert.Equal(t, "openssl", occurrences[0].Package)
+	assert.Equal(t, "sha256:image", occurrences[0].FirstSeenDigest)
+
+	opts.Vulnerability = ""
+	opts.Scan = 100
+	assert.ErrorContains(t, runHistory(opts, "", &out), "no recorded scan")
+
+	opts.History.Path = filepath.Join(t.TempDir(), "missing.db")
+	opts.Scan = 0
+	assert.ErrorContains(t, runHistory(opts, "", &out), "no scan history")
+}
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/malware"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/internal/log"
+)
+
+// findMalware returns the packages found within the malicious package dataset (if any), warning of each.
+func findMalware(dataset *malware.Dataset, packages []pkg.Package) []malware.Finding {
+	findings := dataset.Find(packages)
+	for _, f := range findings {
+		log.WithFields("package", f.Package.Name, "version", f.Package.Version, "id", f.ID, "matchedBy", f.MatchedBy).
+			Warn("found a known-malicious package")
+	}
+	return findings
+}
+
+// malwareThresholdError fails the scan when known-malicious packages were found and a severity threshold is set,
+// whatever the threshold, unless the scan already failed the threshold.
+func malwareThresholdError(findings []malware.Finding, failOn string, errs error) error {
+	if len(findings) == 0 || failOn == "" || errors.Is(errs, grypeerr.ErrAboveSeverityThreshold) {
+		return nil
+	}
+	return grypeerr.ErrAboveSeverityThreshold
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/malware"
+)
+
+func TestMalwareThresholdError(t *testing.T) {
+	findings := []malware.Finding{{ID: "MAL-2022-1001", MatchedBy: malware.VersionMatch}}
+
+	assert.NoError(t, malwareThresholdError(nil, "low", nil))
+	assert.NoError(t, malwareThresholdError(findings, "", nil), "without a threshold malware is only reported")
+
+	err := malwareThresholdError(findings, "critical", nil)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, grypeerr.ErrAboveSeverityThreshold))
+
+	existing := appendErrors(errors.New("unable to write result"), grypeerr.ErrAboveSeverityThreshold)
+	assert.NoError(t, malwareThresholdError(findings, "critical", existing), "the threshold already failed")
+}
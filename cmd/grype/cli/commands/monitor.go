@@ -0,0 +1,349 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/monitor"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/vex"
+	"github.com/anchore/grype/internal/log"
+)
+
+const monitorStateFileName = ".grype-monitor.json"
+
+type monitorOptions struct {
+	Output         string              `yaml:"output" json:"output" mapstructure:"output"`
+	Interval       string              `yaml:"interval" json:"interval" mapstructure:"interval"`
+	StateFile      string              `yaml:"state-file" json:"state-file" mapstructure:"state-file"`
+	Once           bool                `yaml:"once" json:"once" mapstructure:"once"`
+	ReportBaseline bool                `yaml:"report-baseline" json:"report-baseline" mapstructure:"report-baseline"`
+	Match          options.MatchConfig `yaml:"match" json:"match" mapstructure:"match"`
+	Ignore         []match.IgnoreRule  `yaml:"ignore" json:"ignore" mapstructure:"ignore"`
+	DBOptions      `yaml:",inline" mapstructure:",squash"`
+
+	interval time.Duration
+}
+
+var _ interface {
+	clio.FlagAdder
+	clio.PostLoader
+} = (*monitorOptions)(nil)
+
+func (o *monitorOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", "format to display new findings (available=[table, json])")
+	flags.StringVarP(&o.Interval, "interval", "", "how often to check for DB updates and changed targets (e.g. 30m, 6h)")
+	flags.StringVarP(&o.StateFile, "state-file", "", "where to record previously reported findings (default is alongside the targets)")
+	flags.BoolVarP(&o.Once, "once", "", "run a single monitoring cycle and exit")
+	flags.BoolVarP(&o.ReportBaseline, "report-baseline", "", "report all findings on the first cycle instead of only recording them")
+}
+
+func (o *monitorOptions) PostLoad() error {
+	interval, err := time.ParseDuration(o.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid monitor interval %q: %w", o.Interval, err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("monitor interval must be positive, got %q", o.Interval)
+	}
+	o.interval = interval
+
+	switch o.Output {
+	case "table", "json":
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.Output)
+	}
+	return nil
+}
+
+func Monitor(app clio.Application) *cobra.Command {
+	opts := &monitorOptions{
+		Output:    "table",
+		Interval:  "1h",
+		Match:     options.DefaultMatchConfig(),
+		DBOptions: *dbOptionsDefault(app.ID()),
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "monitor [DIR | MANIFEST]",
+		Short: "watch stored SBOMs (or a manifest of targets) and report only new findings as the DB is updated",
+		Long: `Continuously re-evaluate a set of targets and report only the findings that have not been reported before.
+
+The target is either a directory of SBOMs, or a manifest file listing one grype input per line
+(e.g. "sbom:path/to/sbom.json" or "registry:alpine:3.20"). A YAML manifest (".yaml" or ".yml") lists
+the targets with their own distro, ignore rules and VEX documents:
+
+  targets:
+    - input: registry:example.com/legacy-app:1.4
+      distro: rhel:8
+      ignore:
+        - vulnerability: CVE-2023-1234
+      vex-documents:
+        - vex/legacy-app.openvex.json
+
+On every cycle the DB is updated (when auto-update is enabled) and targets are rescanned whenever
+the DB, the target or its overrides have changed.`,
+		Example: `  grype monitor ./sboms
+  grype monitor targets.txt --interval 6h -o json
+  grype monitor fleet.yaml --once`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: disableUI(app),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			return runMonitor(ctx, app.ID(), opts, args[0], os.Stdout)
+		},
+	}, opts)
+}
+
+func runMonitor(ctx context.Context, id clio.Identification, opts *monitorOptions, path string, output io.Writer) error {
+	stateFile := opts.StateFile
+	if stateFile == "" {
+		stateFile = defaultMonitorStateFile(path)
+	}
+
+	grypeOpts := options.DefaultGrype(id)
+	grypeOpts.Match = opts.Match
+
+	for {
+		err := runMonitorCycle(grypeOpts, opts, path, stateFile, output)
+		var failures *monitorFailures
+		switch {
+		case errors.As(err, &failures) && !opts.Once:
+			// the failed targets are scanned again in the next cycle
+			log.Warnf("monitor cycle incomplete: %v", err)
+		case err != nil:
+			return err
+		}
+		if opts.Once {
+			return nil
+		}
+
+		log.Debugf("next monitor cycle in %s", opts.interval)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.interval):
+		}
+	}
+}
+
+func runMonitorCycle(grypeOpts *options.Grype, opts *monitorOptions, path, stateFile string, output io.Writer) error {
+	targets, err := monitor.Targets(path)
+	if err != nil {
+		return err
+	}
+
+	state, err := monitor.LoadState(stateFile)
+	if err != nil {
+		return err
+	}
+	baseline := state.DBChecksum == ""
+
+	log.Debug("loading DB")
+	str, status, dbCloser, err := grype.LoadVulnerabilityDB(opts.DB.ToCuratorConfig(), opts.DB.AutoUpdate)
+	err = validateDBLoad(err, status)
+	if err != nil {
+		return err
+	}
+	if dbCloser != nil {
+		defer dbCloser.Close()
+	}
+
+	if state.DBChecksum != "" && state.DBChecksum != status.Checksum {
+		log.Infof("vulnerability DB changed (built %s), rescanning %d targets", status.Built.Format(time.RFC3339), len(targets))
+	}
+
+	providerConfig := getProviderConfig(grypeOpts)
+	provide := func(input string) ([]pkg.Package, pkg.Context, error) {
+		packages, pkgContext, _, err := pkg.Provide(input, providerConfig)
+		return packages, pkgContext, err
+	}
+
+	// the findings of the targets that were scanned are reported (and recorded) even when other targets failed
+	findings, scanErr := monitorTargets(*str, getMatchers(grypeOpts), opts.Ignore, state, targets, status.Checksum, provide)
+
+	if baseline && !opts.ReportBaseline {
+		log.Infof("recorded %d existing findings across %d targets as the monitoring baseline", len(findings), len(targets))
+		findings = nil
+	}
+
+	if err := presentMonitorFindings(opts.Output, findings, output); err != nil {
+		return err
+	}
+
+	if err := state.Save(stateFile); err != nil {
+		return err
+	}
+	return scanErr
+}
+
+// monitorTargets scans every target that changed (or every target, when the DB changed) and returns the findings
+// that were not previously recorded in the state. Each target is matched with its own distro, ignore rules and VEX
+// documents when the manifest overrides them. A target that cannot be scanned does not stop the others: it is marked
+// to be scanned again in the next cycle and its failure is returned alongside the findings of the other targets.
+func monitorTargets(str store.Store, matchers []matcher.Matcher, ignoreRules []match.IgnoreRule, state *monitor.State, targets []monitor.Target, dbChecksum string, provide func(string) ([]pkg.Package, pkg.Context, error)) ([]monitor.Finding, error) {
+	var newFindings []monitor.Finding
+	var errs error
+	for _, t := range targets {
+		if !state.NeedsScan(t, dbChecksum) {
+			continue
+		}
+
+		findings, err := scanMonitorTarget(str, matchers, ignoreRules, t, provide)
+		if err != nil {
+			log.Warnf("unable to scan monitored target %q: %v", t.Name, err)
+			state.Failed(t)
+			errs = errors.Join(errs, err)
+			continue
+		}
+		newFindings = append(newFindings, state.Record(t, findings)...)
+	}
+
+	state.Prune(targets)
+	state.DBChecksum = dbChecksum
+	if errs != nil {
+		return newFindings, &monitorFailures{err: errs}
+	}
+	return newFindings, nil
+}
+
+// monitorFailures are the failures of the targets that could not be scanned in a monitor cycle.
+type monitorFailures struct {
+	err error
+}
+
+func (f *monitorFailures) Error() string {
+	return fmt.Sprintf("unable to scan some monitored targets: %v", f.err)
+}
+
+func (f *monitorFailures) Unwrap() error {
+	return f.err
+}
+
+// scanMonitorTarget returns all the findings of the target.
+func scanMonitorTarget(str store.Store, matchers []matcher.Matcher, ignoreRules []match.IgnoreRule, t monitor.Target, provide func(string) ([]pkg.Package, pkg.Context, error)) ([]monitor.Finding, error) {
+	packages, pkgContext, err := provide(t.Input)
+	if err != nil {
+		return nil, fmt.Errorf("unable to catalog %q: %w", t.Name, err)
+	}
+	if t.Distro != "" {
+		applyDistroHint(packages, &pkgContext, &options.Grype{Distro: t.Distro})
+	}
+
+	matches, _, err := targetVulnerabilityMatcher(str, matchers, ignoreRules, t).FindMatches(packages, pkgContext)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find matches for %q: %w", t.Name, err)
+	}
+
+	var findings []monitor.Finding
+	for _, m := range matches.Sorted() {
+		severity := "Unknown"
+		if metadata, err := str.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace); err == nil && metadata != nil && metadata.Severity != "" {
+			severity = metadata.Severity
+		}
+		findings = append(findings, monitor.Finding{
+			Target:        t.Name,
+			Package:       m.Package.Name,
+			Version:       m.Package.Version,
+			Type:          string(m.Package.Type),
+			Vulnerability: m.Vulnerability.ID,
+			Severity:      severity,
+			FixVersions:   m.Vulnerability.Fix.Versions,
+		})
+	}
+	return findings, nil
+}
+
+// targetVulnerabilityMatcher returns the matcher of the target, applying its ignore rules and VEX documents on top of
+// the ignore rules of the monitor.
+func targetVulnerabilityMatcher(str store.Store, matchers []matcher.Matcher, ignoreRules []match.IgnoreRule, t monitor.Target) *grype.VulnerabilityMatcher {
+	rules := append(append([]match.IgnoreRule(nil), ignoreRules...), t.Ignore...)
+	// as with a single scan, VEX documents without explicit rules suppress the fixed and not affected findings
+	if len(rules) == 0 && len(t.VexDocuments) > 0 {
+		rules = ignoreVEXFixedNotAffected
+	}
+
+	vulnMatcher := &grype.VulnerabilityMatcher{
+		Store:       str,
+		Matchers:    matchers,
+		IgnoreRules: rules,
+	}
+	if len(t.VexDocuments) > 0 {
+		vulnMatcher.VexProcessor = vex.NewProcessor(vex.ProcessorOptions{
+			Documents:   t.VexDocuments,
+			IgnoreRules: rules,
+		})
+	}
+	return vulnMatcher
+}
+
+func defaultMonitorStateFile(path string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return filepath.Join(path, monitorStateFileName)
+	}
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".grype-monitor.json")
+}
+
+func presentMonitorFindings(outputFormat string, findings []monitor.Finding, output io.Writer) error {
+	if len(findings) == 0 {
+		return nil
+	}
+
+	switch outputFormat {
+	case "table":
+		rows := [][]string{}
+		for _, f := range findings {
+			rows = append(rows, []string{f.Target, f.Package, f.Version, f.Type, f.Vulnerability, f.Severity})
+		}
+
+		table := tablewriter.NewWriter(output)
+		columns := []string{"Target", "Name", "Installed", "Type", "Vulnerability", "Severity"}
+
+		table.SetHeader(columns)
+		table.SetAutoWrapText(false)
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+		table.SetHeaderLine(false)
+		table.SetBorder(false)
+		table.SetAutoFormatHeaders(true)
+		table.SetCenterSeparator("")
+		table.SetColumnSeparator("")
+		table.SetRowSeparator("")
+		table.SetTablePadding("  ")
+		table.SetNoWhiteSpace(true)
+
+		table.AppendBulk(rows)
+		table.Render()
+	case "json":
+		// one finding per line so that the stream can be consumed as findings arrive
+		enc := json.NewEncoder(output)
+		enc.SetEscapeHTML(false)
+		for _, f := range findings {
+			if err := enc.Encode(f); err != nil {
+				return fmt.Errorf("failed to encode monitor finding: %+v", err)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/monitor"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+func Test_monitorTargets(t *testing.T) {
+	newVuln := func(id string) vulnerability.Vulnerability {
+		return vulnerability.Vulnerability{
+			ID:          id,
+			Namespace:   "github:language:javascript",
+			PackageName: "lodash",
+			Constraint:  version.MustGetConstraint("< 4.17.21", version.UnknownFormat),
+		}
+	}
+	p := simulateProvider{
+		byName: map[string][]vulnerability.Vulnerability{
+			"lodash": {newVuln("GHSA-1")},
+		},
+	}
+	str := store.Store{Provider: p, MetadataProvider: p, ExclusionProvider: p}
+	matchers := matcher.NewDefaultMatchers(matcher.Config{})
+
+	lodash, err := pkg.NewFromPURL("pkg:npm/lodash@4.17.20")
+	require.NoError(t, err)
+
+	scanned := 0
+	provide := func(string) ([]pkg.Package, pkg.Context, error) {
+		scanned++
+		return []pkg.Package{lodash}, pkg.Context{}, nil
+	}
+
+	state := monitor.NewState()
+	targets := []monitor.Target{{Name: "app.json", Input: "sbom:app.json", Digest: "sha256:1"}}
+
+	findings, err := monitorTargets(str, matchers, nil, state, targets, "db-1", provide)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, monitor.Finding{
+		Target:        "app.json",
+		Package:       "lodash",
+		Version:       "4.17.20",
+		Type:          "npm",
+		Vulnerability: "GHSA-1",
+		Severity:      "High",
+	}, findings[0])
+
+	// unchanged DB and target: nothing is rescanned
+	findings, err = monitorTargets(str, matchers, nil, state, targets, "db-1", provide)
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+	assert.Equal(t, 1, scanned)
+
+	// a DB update with a newly published vulnerability reports only that vulnerability
+	p.byName["lodash"] = append(p.byName["lodash"], newVuln("GHSA-2"))
+	findings, err = monitorTargets(str, matchers, nil, state, targets, "db-2", provide)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "GHSA-2", findings[0].Vulnerability)
+	assert.Equal(t, 2, scanned)
+}
+
+func Test_monitorTargets_failedTarget(t *testing.T) {
+	p := simulateProvider{byName: map[string][]vulnerability.Vulnerability{
+		"lodash": {{
+			ID:          "GHSA-1",
+			Namespace:   "github:language:javascript",
+			PackageName: "lodash",
+			Constraint:  version.MustGetConstraint("< 4.17.21", version.UnknownFormat),
+		}},
+	}}
+	str := store.Store{Provider: p, MetadataProvider: p, ExclusionProvider: p}
+	matchers := matcher.NewDefaultMatchers(matcher.Config{})
+
+	lodash, err := pkg.NewFromPURL("pkg:npm/lodash@4.17.20")
+	require.NoError(t, err)
+
+	broken := true
+	scans := map[string]int{}
+	provide := func(input string) ([]pkg.Package, pkg.Context, error) {
+		scans[input]++
+		if input == "sbom:broken.json" && broken {
+			return nil, pkg.Context{}, errors.New("registry unavailable")
+		}
+		return []pkg.Package{lodash}, pkg.Context{}, nil
+	}
+
+	state := monitor.NewState()
+	targets := []monitor.Target{
+		{Name: "app.json", Input: "sbom:app.json"},
+		{Name: "broken.json", Input: "sbom:broken.json"},
+	}
+
+	// the failure is returned along with the findings of the other targets
+	findings, err := monitorTargets(str, matchers, nil, state, targets, "db-1", provide)
+	var failures *monitorFailures
+	require.ErrorAs(t, err, &failures)
+	require.ErrorContains(t, err, `unable to catalog "broken.json": registry unavailable`)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "app.json", findings[0].Target)
+
+	// the failed target is scanned again with the same DB, the other one is not
+	broken = false
+	findings, err = monitorTargets(str, matchers, nil, state, targets, "db-1", provide)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "broken.json", findings[0].Target)
+	assert.Equal(t, map[string]int{"sbom:app.json": 1, "sbom:broken.json": 2}, scans)
+
+	// a target that failed after a successful scan keeps its findings, which are not reported again
+	broken = true
+	_, err = monitorTargets(str, matchers, nil, state, targets, "db-2", provide)
+	require.Error(t, err)
+	broken = false
+	findings, err = monitorTargets(str, matchers, nil, state, targets, "db-2", provide)
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+	assert.Equal(t, map[string]int{"sbom:app.json": 2, "sbom:broken.json": 4}, scans)
+}
+
+// distroRecordingProvider records the distros the vulnerabilities are looked up for.
+type distroRecordingProvider struct {
+	simulateProvider
+	distros *[]string
+}
+
+func (p distroRecordingProvider) GetByDistro(d *distro.Distro, _ pkg.Package) ([]vulnerability.Vulnerability, error) {
+	*p.distros = append(*p.distros, d.String())
+	return nil, nil
+}
+
+func Test_monitorTargets_overrides(t *testing.T) {
+	var distros []string
+	p := distroRecordingProvider{
+		simulateProvider: simulateProvider{
+			byName: map[string][]vulnerability.Vulnerability{
+				"lodash": {
+					{
+						ID:          "GHSA-1",
+						Namespace:   "github:language:javascript",
+						PackageName: "lodash",
+						Constraint:  version.MustGetConstraint("< 4.17.21", version.UnknownFormat),
+					},
+				},
+			},
+		},
+		distros: &distros,
+	}
+	str := store.Store{Provider: p, MetadataProvider: p, ExclusionProvider: p}
+	matchers := matcher.NewDefaultMatchers(matcher.Config{})
+
+	lodash, err := pkg.NewFromPURL("pkg:npm/lodash@4.17.20")
+	require.NoError(t, err)
+	openssl, err := pkg.NewFromPURL("pkg:deb/debian/openssl@3.0.11-1")
+	require.NoError(t, err)
+	provide := func(string) ([]pkg.Package, pkg.Context, error) {
+		return []pkg.Package{lodash, openssl}, pkg.Context{}, nil
+	}
+
+	targets := []monitor.Target{
+		{Name: "app", Input: "registry:app:1.0"},
+		{
+			Name:   "legacy",
+			Input:  "registry:legacy:1.0",
+			Distro: "debian:11",
+			Ignore: []match.IgnoreRule{{Vulnerability: "GHSA-1"}},
+		},
+	}
+
+	findings, err := monitorTargets(str, matchers, nil, monitor.NewState(), targets, "db-1", provide)
+	require.NoError(t, err)
+
+	// the ignore rules of a target do not apply to the other targets
+	require.Len(t, findings, 1)
+	assert.Equal(t, "app", findings[0].Target)
+	assert.Equal(t, "GHSA-1", findings[0].Vulnerability)
+
+	// only the target with a distro hint is matched against the distro
+	assert.Equal(t, []string{"debian 11"}, distros)
+}
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// platformScan is the cataloged packages of a single platform of an image index.
+type platformScan struct {
+	platform string
+	packages []pkg.Package
+	context  pkg.Context
+	sbom     *sbom.SBOM
+}
+
+// providePlatforms catalogs each selected platform of the image index that the user input refers to.
+func providePlatforms(ctx context.Context, userInput string, opts *options.Grype) ([]platformScan, error) {
+	cfg := getProviderConfig(opts)
+	cfg.Context = ctx
+	index, err := pkg.ResolveImageIndex(userInput, cfg.RegistryOptions)
+	if err != nil {
+		return nil, err
+	}
+	platforms, err := index.Select(opts.Platforms)
+	if err != nil {
+		return nil, err
+	}
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("the image index %q has no platforms to scan", userInput)
+	}
+
+	var scans []platformScan
+	for _, platform := range platforms {
+		log.WithFields("platform", platform).Debug("gathering packages")
+		platformConfig := cfg
+		platformConfig.Platform = platform
+		packages, pkgContext, s, err := pkg.Provide(index.Reference, platformConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to catalog platform %s: %w", platform, err)
+		}
+		scans = append(scans, platformScan{platform: platform, packages: packages, context: pkgContext, sbom: s})
+	}
+	return scans, nil
+}
+
+// combinePlatforms returns the packages of all platforms, along with the context and SBOM of the first platform,
+// which stand for the image index as a whole.
+func combinePlatforms(scans []platformScan) ([]pkg.Package, pkg.Context, *sbom.SBOM) {
+	var packages []pkg.Package
+	seen := make(map[pkg.ID]bool)
+	for _, s := range scans {
+		for _, p := range s.packages {
+			if !seen[p.ID] {
+				seen[p.ID] = true
+				packages = append(packages, p)
+			}
+		}
+	}
+	if len(scans) == 0 {
+		return packages, pkg.Context{}, nil
+	}
+	return packages, scans[0].context, scans[0].sbom
+}
+
+// findPlatformMatches matches the packages of each platform separately, returning the matches of all platforms
+// combined along with the results of each platform. An error for exceeding the severity threshold is reported once,
+// after all platforms are matched.
+func findPlatformMatches(vulnMatcher grype.VulnerabilityMatcher, scans []platformScan) (*match.Matches, []match.IgnoredMatch, []models.PlatformResult, error) {
+	all := match.NewMatches()
+	var ignored []match.IgnoredMatch
+	var results []models.PlatformResult
+	var thresholdErr error
+	var problems []grypeerr.DataQualityProblem
+	for _, s := range scans {
+		remaining, ignoredMatches, err := vulnMatcher.FindMatches(s.packages, s.context)
+		if err != nil {
+			if !grypeerr.IsScanFailure(err) {
+				return nil, nil, nil, fmt.Errorf("unable to find matches for platform %s: %w", s.platform, err)
+			}
+			if errors.Is(err, grypeerr.ErrAboveSeverityThreshold) {
+				thresholdErr = grypeerr.ErrAboveSeverityThreshold
+			}
+			var dataQualityErr *grypeerr.DataQualityError
+			if errors.As(err, &dataQualityErr) {
+				for _, p := range dataQualityErr.Problems {
+					p.Subject = fmt.Sprintf("%s (platform %s)", p.Subject, s.platform)
+					problems = append(problems, p)
+				}
+			}
+		}
+
+		all.Merge(*remaining)
+		ignored = append(ignored, ignoredMatches...)
+		results = append(results, models.PlatformResult{
+			Platform:       s.platform,
+			Matches:        *remaining,
+			IgnoredMatches: ignoredMatches,
+			Packages:       s.packages,
+			Context:        s.context,
+		})
+	}
+	if len(problems) > 0 {
+		return &all, ignored, results, errors.Join(thresholdErr, &grypeerr.DataQualityError{Problems: problems})
+	}
+	return &all, ignored, results, thresholdErr
+}
+
+// findUnsupportedPackages returns the packages that cannot be matched against any vulnerability data, of each
+// platform when scanning several platforms of an image index.
+func findUnsupportedPackages(vulnMatcher grype.VulnerabilityMatcher, packages []pkg.Package, context pkg.Context, scans []platformScan) ([]pkg.UnsupportedPackage, error) {
+	if len(scans) == 0 {
+		return vulnMatcher.UnsupportedPackages(packages, context)
+	}
+
+	var unsupported []pkg.UnsupportedPackage
+	for _, s := range scans {
+		u, err := vulnMatcher.UnsupportedPackages(s.packages, s.context)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find unsupported packages for platform %s: %w", s.platform, err)
+		}
+		unsupported = append(unsupported, u...)
+	}
+	return unsupported, nil
+}
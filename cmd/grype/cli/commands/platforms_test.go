@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+func Test_findPlatformMatches(t *testing.T) {
+	p := simulateProvider{
+		byName: map[string][]vulnerability.Vulnerability{
+			"lodash": {{
+				ID:          "GHSA-1",
+				Namespace:   "github:language:javascript",
+				PackageName: "lodash",
+				Constraint:  version.MustGetConstraint("< 4.17.21", version.UnknownFormat),
+			}},
+		},
+	}
+	str := store.Store{Provider: p, MetadataProvider: p, ExclusionProvider: p}
+
+	vulnerable, err := pkg.NewFromPURL("pkg:npm/lodash@4.17.20")
+	require.NoError(t, err)
+	fixed, err := pkg.NewFromPURL("pkg:npm/lodash@4.17.21")
+	require.NoError(t, err)
+
+	scans := []platformScan{
+		{platform: "linux/amd64", packages: []pkg.Package{vulnerable}},
+		{platform: "linux/arm64", packages: []pkg.Package{fixed}},
+	}
+
+	packages, _, _ := combinePlatforms(append(scans, platformScan{platform: "linux/arm/v7", packages: []pkg.Package{vulnerable}}))
+	assert.Len(t, packages, 2, "the same package in several platforms is reported once")
+
+	severity := vulnerability.HighSeverity
+	vulnMatcher := grype.VulnerabilityMatcher{
+		Store:        str,
+		Matchers:     matcher.NewDefaultMatchers(matcher.Config{}),
+		FailSeverity: &severity,
+	}
+	all, _, results, err := findPlatformMatches(vulnMatcher, scans)
+	assert.ErrorIs(t, err, grypeerr.ErrAboveSeverityThreshold)
+	require.NotNil(t, all)
+	assert.Equal(t, 1, all.Count())
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "linux/amd64", results[0].Platform)
+	assert.Equal(t, 1, results[0].Matches.Count())
+	assert.Equal(t, "linux/arm64", results[1].Platform)
+	assert.Equal(t, 0, results[1].Matches.Count())
+}
+
+func Test_findPlatformMatches_strict(t *testing.T) {
+	p := simulateProvider{}
+	str := store.Store{Provider: p, MetadataProvider: p, ExclusionProvider: p}
+
+	complete, err := pkg.NewFromPURL("pkg:npm/lodash@4.17.21")
+	require.NoError(t, err)
+	unversioned, err := pkg.NewFromPURL("pkg:npm/lodash")
+	require.NoError(t, err)
+
+	vulnMatcher := grype.VulnerabilityMatcher{
+		Store:    str,
+		Matchers: matcher.NewDefaultMatchers(matcher.Config{}),
+		Strict:   true,
+	}
+	_, _, results, err := findPlatformMatches(vulnMatcher, []platformScan{
+		{platform: "linux/amd64", packages: []pkg.Package{complete}},
+		{platform: "linux/arm64", packages: []pkg.Package{unversioned}},
+	})
+	require.Len(t, results, 2)
+
+	var dataQualityErr *grypeerr.DataQualityError
+	require.ErrorAs(t, err, &dataQualityErr)
+	require.Len(t, dataQualityErr.Problems, 1)
+	assert.Equal(t, string(pkg.MissingVersion), dataQualityErr.Problems[0].Kind)
+	assert.Equal(t, "pkg:npm/lodash (platform linux/arm64)", dataQualityErr.Problems[0].Subject)
+	assert.NotErrorIs(t, err, grypeerr.ErrAboveSeverityThreshold)
+}
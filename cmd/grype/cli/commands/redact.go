@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/internal/redact"
+)
+
+// localInputSchemes are the schemes of user inputs that refer to a path on the local filesystem.
+var localInputSchemes = []string{"dir", "file", "sbom", "purl", "docker-archive", "oci-archive", "oci-dir", "singularity"}
+
+var userinfoPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://([^/@\s]+)@`)
+
+// applyRedactions adds the values of the requested categories (--redact) to the redaction store, from which they are
+// redacted in the reports, the logs and the error messages.
+func applyRedactions(userInput string, opts *options.Grype) {
+	if len(opts.Redact) == 0 {
+		return
+	}
+	redact.Add(redactionValues(userInput, opts)...)
+}
+
+func redactionValues(userInput string, opts *options.Grype) []string {
+	var values []string
+	for _, category := range opts.Redact {
+		switch category {
+		case options.RedactPaths:
+			values = append(values, localPaths(userInput, opts)...)
+		case options.RedactEnv:
+			values = append(values, environmentDetails()...)
+		case options.RedactCredentials:
+			for _, s := range []string{userInput, opts.DB.UpdateURL, opts.ExternalSources.Maven.BaseURL, opts.ExternalSources.OSV.BaseURL} {
+				values = append(values, urlCredentials(s)...)
+			}
+		}
+	}
+
+	var nonTrivial []string
+	for _, v := range values {
+		// the root directory is within every path
+		if v != "" && v != string(filepath.Separator) {
+			nonTrivial = append(nonTrivial, v)
+		}
+	}
+	return nonTrivial
+}
+
+func localPaths(userInput string, opts *options.Grype) []string {
+	var paths []string
+	if wd, err := os.Getwd(); err == nil {
+		paths = append(paths, wd)
+	}
+	if home, err := homedir.Dir(); err == nil {
+		paths = append(paths, home)
+	}
+	if dir, err := homedir.Expand(opts.DB.Dir); err == nil && dir != "" {
+		paths = append(paths, dir)
+	}
+	if input := localInputPath(userInput); input != "" {
+		if abs, err := filepath.Abs(input); err == nil {
+			paths = append(paths, abs)
+		}
+		paths = append(paths, input)
+	}
+	return paths
+}
+
+// localInputPath returns the path of a user input referring to a path the local filesystem, if it does.
+func localInputPath(userInput string) string {
+	if scheme, path, found := strings.Cut(userInput, ":"); found {
+		for _, s := range localInputSchemes {
+			if scheme == s {
+				return path
+			}
+		}
+	}
+	if _, err := os.Stat(userInput); err == nil {
+		return userInput
+	}
+	return ""
+}
+
+func environmentDetails() []string {
+	var details []string
+	if u, err := user.Current(); err == nil {
+		details = append(details, u.Username)
+	}
+	details = append(details, os.Getenv("USER"), os.Getenv("USERNAME"))
+	if hostname, err := os.Hostname(); err == nil {
+		details = append(details, hostname)
+	}
+	return details
+}
+
+// urlCredentials returns the credentials within the URLs of the value (the whole userinfo, and the password alone).
+func urlCredentials(value string) []string {
+	var credentials []string
+	for _, m := range userinfoPattern.FindAllStringSubmatch(value, -1) {
+		credentials = append(credentials, m[1])
+		if userinfo, err := url.PathUnescape(m[1]); err == nil {
+			if _, password, found := strings.Cut(userinfo, ":"); found {
+				credentials = append(credentials, password)
+			}
+		}
+	}
+	return credentials
+}
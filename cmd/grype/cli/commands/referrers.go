@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/internal/bus"
+	"github.com/anchore/grype/internal/log"
+)
+
+// useReferrers looks for the SBOM and VEX documents attached to the image of an OCI image layout: the first attached
+// SBOM is scanned in place of cataloging the image, and the attached VEX documents are considered along with any
+// given VEX documents when they are trusted (as they are not signature-verified, anyone able to attach a document
+// could suppress findings otherwise). It returns the input to scan, along with a function removing the documents
+// written for the scan.
+func useReferrers(userInput string, opts *options.Grype) (string, func(), error) {
+	noop := func() {}
+
+	docs, err := pkg.FindReferrers(userInput)
+	if err != nil {
+		if opts.Strict {
+			return "", noop, fmt.Errorf("unable to look for the SBOM and VEX documents attached to the image: %w", err)
+		}
+		// the attached documents are a convenience, the image can still be cataloged
+		log.WithFields("input", userInput, "error", err).Warn("unable to look for the SBOM and VEX documents attached to the image")
+		return userInput, noop, nil
+	}
+	if len(docs) == 0 {
+		return userInput, noop, nil
+	}
+
+	dir, err := os.MkdirTemp("", "grype-referrers-")
+	if err != nil {
+		return "", noop, fmt.Errorf("unable to create a directory for the attached documents: %w", err)
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.WithFields("dir", dir, "error", err).Debug("unable to remove the attached documents")
+		}
+	}
+
+	decoder := pkg.NewSBOMDecoder(getProviderConfig(opts).SBOMDecoders...)
+	input := userInput
+	for i, doc := range docs {
+		name := filepath.Join(dir, fmt.Sprintf("%d-%s.json", i, strings.TrimPrefix(doc.Referrer, "sha256:")))
+		switch doc.Kind {
+		case pkg.SBOMReferrer:
+			if input != userInput {
+				log.WithFields("referrer", doc.Referrer).Debug("skipping additional SBOM attached to the image")
+				continue
+			}
+			if id, _ := decoder.Identify(bytes.NewReader(doc.Contents)); id == "" {
+				log.WithFields("referrer", doc.Referrer, "mediaType", doc.MediaType).Warn("skipping SBOM attached to the image in an unsupported format")
+				continue
+			}
+			if err := os.WriteFile(name, doc.Contents, 0o600); err != nil {
+				cleanup()
+				return "", noop, fmt.Errorf("unable to write the SBOM attached to the image: %w", err)
+			}
+			log.WithFields("referrer", doc.Referrer, "subject", doc.Subject).Info("using the SBOM attached to the image instead of cataloging it")
+			bus.Notify(fmt.Sprintf("Scanned the SBOM attached to the image (%s) instead of cataloging the image", doc.Referrer))
+			input = "sbom:" + name
+		case pkg.VEXReferrer:
+			if !opts.TrustReferrerVEX {
+				log.WithFields("referrer", doc.Referrer, "subject", doc.Subject).Warn("not applying the unverified VEX document attached to the image (use --trust-referrer-vex to apply it)")
+				continue
+			}
+			if err := os.WriteFile(name, doc.Contents, 0o600); err != nil {
+				cleanup()
+				return "", noop, fmt.Errorf("unable to write the VEX document attached to the image: %w", err)
+			}
+			log.WithFields("referrer", doc.Referrer, "subject", doc.Subject).Info("using the VEX document attached to the image")
+			opts.VexDocuments = append(opts.VexDocuments, name)
+		}
+	}
+	return input, cleanup, nil
+}
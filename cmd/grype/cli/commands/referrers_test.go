@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/cmd/grype/cli/options"
+)
+
+func Test_useReferrers(t *testing.T) {
+	dir := t.TempDir()
+	l, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, l.AppendImage(img))
+
+	sbom, err := os.ReadFile("../../../../grype/pkg/test-fixtures/syft-spring.json")
+	require.NoError(t, err)
+	vex, err := os.ReadFile("../../../../grype/vex/testdata/vex-docs/openvex-debian.json")
+	require.NoError(t, err)
+	attach(t, l, img, "application/vnd.syft+json", sbom)
+	attach(t, l, img, "application/vnd.openvex+json", vex)
+
+	// the attached VEX documents are not applied unless trusted
+	opts := &options.Grype{VexDocuments: []string{"given.json"}}
+	input, cleanup, err := useReferrers("oci-dir:"+dir, opts)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(input, "sbom:"), input)
+	assert.Equal(t, []string{"given.json"}, opts.VexDocuments)
+	cleanup()
+
+	opts.TrustReferrerVEX = true
+	input, cleanup, err = useReferrers("oci-dir:"+dir, opts)
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(input, "sbom:"), input)
+	contents, err := os.ReadFile(strings.TrimPrefix(input, "sbom:"))
+	require.NoError(t, err)
+	assert.Equal(t, sbom, contents)
+
+	require.Len(t, opts.VexDocuments, 2)
+	assert.Equal(t, "given.json", opts.VexDocuments[0])
+	contents, err = os.ReadFile(opts.VexDocuments[1])
+	require.NoError(t, err)
+	assert.Equal(t, vex, contents)
+
+	cleanup()
+	assert.NoFileExists(t, opts.VexDocuments[1])
+
+	// other inputs are cataloged as usual
+	input, cleanup, err = useReferrers("dir:"+dir, opts)
+	require.NoError(t, err)
+	defer cleanup()
+	assert.Equal(t, "dir:"+dir, input)
+	assert.Len(t, opts.VexDocuments, 2)
+}
+
+func attach(t *testing.T, l layout.Path, subject v1.Image, mediaType string, contents []byte) {
+	t.Helper()
+	desc, err := partial.Descriptor(subject)
+	require.NoError(t, err)
+	artifact := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	artifact = mutate.ConfigMediaType(artifact, types.MediaType(mediaType))
+	artifact, err = mutate.Append(artifact, mutate.Addendum{Layer: static.NewLayer(contents, types.MediaType(mediaType))})
+	require.NoError(t, err)
+	require.NoError(t, l.AppendImage(mutate.Subject(artifact, *desc).(v1.Image)))
+}
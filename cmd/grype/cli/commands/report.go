@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/history"
+)
+
+func Report(app clio.Application) *cobra.Command {
+	report := &cobra.Command{
+		Use:   "report",
+		Short: "reports across past scans",
+	}
+
+	report.AddCommand(
+		ReportTrend(app),
+	)
+
+	return report
+}
+
+type reportTrendOptions struct {
+	Output     string              `yaml:"output" json:"output" mapstructure:"output"`
+	File       string              `yaml:"file" json:"file" mapstructure:"file"`
+	ResultsDir string              `yaml:"results-dir" json:"results-dir" mapstructure:"results-dir"`
+	Target     string              `yaml:"target" json:"target" mapstructure:"target"`
+	History    options.ScanHistory `yaml:"history" json:"history" mapstructure:"history"`
+}
+
+var _ interface {
+	clio.FlagAdder
+	clio.PostLoader
+} = (*reportTrendOptions)(nil)
+
+func (o *reportTrendOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", "format of the report (available=[json, html])")
+	flags.StringVarP(&o.File, "file", "", "file to write the report to (default is STDOUT)")
+	flags.StringVarP(&o.ResultsDir, "results-dir", "", "read the scans from a directory of grype JSON reports instead of the scan history")
+	flags.StringVarP(&o.Target, "target", "", "only report on the scans of the given target")
+}
+
+func (o *reportTrendOptions) PostLoad() error {
+	switch o.Output {
+	case "json", "html":
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.Output)
+	}
+	return nil
+}
+
+func ReportTrend(app clio.Application) *cobra.Command {
+	opts := &reportTrendOptions{
+		Output:  "json",
+		History: options.DefaultScanHistory(app.ID()),
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "trend",
+		Short: "report the new and fixed findings per week, and the mean time to remediate them",
+		Long: `Report the findings that appeared (new) and disappeared (fixed) each week across the targets, by severity,
+along with the mean and median time to remediate the fixed findings.
+
+The scans are read from the scan history (see the "history" configuration), or from a directory of
+grype JSON reports with --results-dir. A finding is fixed by the first later scan of the same target
+that no longer reports it.`,
+		Example: `  grype report trend -o html --file trend.html
+  grype report trend --results-dir ./reports --target registry.example.com/app:latest`,
+		Args:    cobra.NoArgs,
+		PreRunE: disableUI(app),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			output := io.Writer(os.Stdout)
+			if opts.File != "" {
+				f, err := os.Create(opts.File)
+				if err != nil {
+					return fmt.Errorf("unable to create report file: %w", err)
+				}
+				defer f.Close()
+				output = f
+			}
+			return runReportTrend(opts, output)
+		},
+	}, opts)
+}
+
+func runReportTrend(opts *reportTrendOptions, output io.Writer) error {
+	scans, err := trendScans(opts)
+	if err != nil {
+		return err
+	}
+
+	report := history.Trend(scans)
+	if opts.Output == "html" {
+		return report.WriteHTML(output)
+	}
+
+	enc := json.NewEncoder(output)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode trend report: %+v", err)
+	}
+	return nil
+}
+
+func trendScans(opts *reportTrendOptions) ([]history.Scan, error) {
+	if opts.ResultsDir != "" {
+		scans, err := history.FromReports(opts.ResultsDir)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Target == "" {
+			return scans, nil
+		}
+		var selected []history.Scan
+		for _, s := range scans {
+			if s.Target == opts.Target {
+				selected = append(selected, s)
+			}
+		}
+		return selected, nil
+	}
+
+	if _, err := os.Stat(opts.History.Path); err != nil {
+		return nil, fmt.Errorf("no scan history at %q (enable it with the history.enabled configuration, or use --results-dir): %w", opts.History.Path, err)
+	}
+	s, err := history.Open(opts.History.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	return s.ScansWithFindings(opts.Target)
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/feedback"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/internal"
+	"github.com/anchore/grype/internal/bus"
+	"github.com/anchore/grype/internal/log"
+)
+
+type reportFPOptions struct {
+	ID         string   `yaml:"id" json:"id" mapstructure:"id"`
+	Package    string   `yaml:"package" json:"package" mapstructure:"package"`
+	File       string   `yaml:"file" json:"file" mapstructure:"file"`
+	Note       string   `yaml:"note" json:"note" mapstructure:"note"`
+	KeepSource bool     `yaml:"keep-source" json:"keep-source" mapstructure:"keep-source"`
+	Redact     []string `yaml:"redact" json:"redact" mapstructure:"redact"`
+	DBOptions  `yaml:",inline" mapstructure:",squash"`
+}
+
+var _ clio.FlagAdder = (*reportFPOptions)(nil)
+
+func (o *reportFPOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.ID, "id", "", "the match ID (the \"id\" of the match in the JSON report) or the vulnerability ID of the match to report")
+	flags.StringVarP(&o.Package, "package", "p", "the name or purl of the matched package, when a vulnerability ID matches several packages")
+	flags.StringVarP(&o.File, "file", "f", "file to write the bundle to (default is STDOUT)")
+	flags.StringVarP(&o.Note, "note", "", "why the match is believed to be wrong")
+	flags.BoolVarP(&o.KeepSource, "keep-source", "", "keep the name and digests of the scanned source in the bundle, and in the locations and metadata of the package (redacted by default)")
+	flags.StringArrayVarP(&o.Redact, "redact", "", "a value to redact wherever it appears in the bundle (e.g. an internal hostname)")
+}
+
+func ReportFP(app clio.Application) *cobra.Command {
+	opts := &reportFPOptions{
+		DBOptions: *dbOptionsDefault(app.ID()),
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "report-fp [REPORT] --id [MATCH ID | VULNERABILITY ID]",
+		Short: "capture a match from a JSON report, with the evidence behind it, into a redacted bundle for reporting a false positive",
+		Example: `  grype alpine:3.18 -o json > report.json
+  grype report-fp report.json --id CVE-2023-1234 --package openssl --note "fixed by the distro backport" -f fp.json
+  grype alpine:3.18 -o json | grype report-fp --id 0f5c2b44-1e54-5f0e-9c3a-2b5f8d4e6a71`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: disableUI(app),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runReportFP(opts, args)
+		},
+	}, opts)
+}
+
+func runReportFP(opts *reportFPOptions, args []string) error {
+	if opts.ID == "" {
+		return fmt.Errorf("a match ID or vulnerability ID is required (--id)")
+	}
+
+	doc, err := readReport(args)
+	if err != nil {
+		return err
+	}
+
+	var provider feedback.RecordProvider
+	log.Debug("loading DB")
+	str, status, dbCloser, err := grype.LoadVulnerabilityDB(opts.DB.ToCuratorConfig(), opts.DB.AutoUpdate)
+	if err = validateDBLoad(err, status); err != nil {
+		// the match and its details are still worth reporting without the records
+		log.Warnf("unable to include vulnerability records in the bundle: %v", err)
+	} else {
+		provider = str
+	}
+	if dbCloser != nil {
+		defer dbCloser.Close()
+	}
+
+	bundle, err := feedback.New(*doc, feedback.Selector{ID: opts.ID, Package: opts.Package}, provider, feedback.Options{
+		Note:       opts.Note,
+		KeepSource: opts.KeepSource,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.File != "" {
+		f, err := os.Create(opts.File)
+		if err != nil {
+			return fmt.Errorf("unable to create bundle file: %w", err)
+		}
+		defer f.Close()
+		return bundle.Encode(f, opts.Redact...)
+	}
+
+	sb := &strings.Builder{}
+	err = bundle.Encode(sb, opts.Redact...)
+	bus.Report(sb.String())
+	return err
+}
+
+// readReport reads the grype JSON report from the given file or from stdin.
+func readReport(args []string) (*models.Document, error) {
+	var reader io.Reader
+	switch {
+	case len(args) == 1 && args[0] != "-":
+		f, err := os.Open(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("unable to open report: %w", err)
+		}
+		defer f.Close()
+		reader = f
+	default:
+		isStdinPipeOrRedirect, err := internal.IsStdinPipeOrRedirect()
+		if err != nil || !isStdinPipeOrRedirect {
+			return nil, fmt.Errorf("requires a grype JSON report as an argument or on stdin, please run 'grype -o json ... | grype report-fp ...'")
+		}
+		reader = os.Stdin
+	}
+
+	var doc models.Document
+	if err := json.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unable to parse grype JSON report: %w", err)
+	}
+	return &doc, nil
+}
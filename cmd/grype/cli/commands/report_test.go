@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/history"
+)
+
+func Test_runReportTrend(t *testing.T) {
+	cfg := options.ScanHistory{Enabled: true, Path: filepath.Join(t.TempDir(), "history.db")}
+	s, err := history.Open(cfg.Path)
+	require.NoError(t, err)
+	finding := history.Finding{Fingerprint: "1", Vulnerability: "CVE-2024-0001", Package: "openssl", Version: "3.1.4", Type: "apk", Severity: "High"}
+	for _, scan := range []history.Scan{
+		{Target: "alpine:3.19", Timestamp: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Findings: []history.Finding{finding}},
+		{Target: "alpine:3.19", Timestamp: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{Target: "alpine:3.20", Timestamp: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), Findings: []history.Finding{finding}},
+	} {
+		_, err := s.Record(scan)
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.Close())
+
+	opts := &reportTrendOptions{Output: "json", History: cfg}
+	var out bytes.Buffer
+	require.NoError(t, runReportTrend(opts, &out))
+	var report history.TrendReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, 2, report.Targets)
+	require.Len(t, report.Weeks, 1)
+	assert.Equal(t, map[string]int{"High": 2}, report.Weeks[0].New)
+	assert.Equal(t, map[string]int{"High": 1}, report.Weeks[0].Fixed)
+	assert.Equal(t, history.MTTR{Fixed: 1, MeanDays: 4, MedianDays: 4}, report.MTTR)
+
+	opts.Target = "alpine:3.20"
+	out.Reset()
+	require.NoError(t, runReportTrend(opts, &out))
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, 1, report.Targets)
+
+	opts.Output, opts.Target = "html", ""
+	out.Reset()
+	require.NoError(t, runReportTrend(opts, &out))
+	assert.Contains(t, out.String(), "<td>2024-W10 (2024-03-04)</td>")
+	assert.Contains(t, out.String(), "<th>New High</th>")
+}
+
+func Test_runReportTrend_resultsDir(t *testing.T) {
+	dir := t.TempDir()
+	writeReport := func(name, target, timestamp, matches string) {
+		doc := `{"matches": [` + matches + `], "source": {"type": "directory", "target": "` + target + `"}, "descriptor": {"name": "grype", "timestamp": "` + timestamp + `"}}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(doc), 0o600))
+	}
+	writeReport("1.json", "./app", "2024-03-04T10:00:00Z", `{"vulnerability": {"id": "CVE-2024-0001", "severity": "High"}, "artifact": {"name": "lodash", "version": "4.17.20", "type": "npm"}}`)
+	writeReport("2.json", "./other", "2024-03-05T10:00:00Z", "")
+
+	opts := &reportTrendOptions{Output: "json", ResultsDir: dir, Target: "./app"}
+	var out bytes.Buffer
+	require.NoError(t, runReportTrend(opts, &out))
+	var report history.TrendReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, 1, report.Scans)
+	require.Len(t, report.Weeks, 1)
+	assert.Equal(t, map[string]int{"High": 1}, report.Weeks[0].New)
+}
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/bundle"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/internal/format"
+	"github.com/anchore/grype/internal/redact"
+	"github.com/anchore/syft/syft/format/syftjson"
+)
+
+// writeResultsArchive bundles the reports of the scan (the JSON report, and a report per output format), the scanned
+// SBOM, the effective configuration and the metadata of the vulnerability database into the results archive.
+func writeResultsArchive(id clio.Identification, opts *options.Grype, userInput string, status *distribution.Status, result models.PresenterConfig) error {
+	manifest := bundle.Manifest{
+		Created: time.Now().UTC(),
+		Tool:    bundle.Tool{Name: id.Name, Version: id.Version},
+		Target:  userInput,
+	}
+	if result.Context.Source != nil && manifest.Target == "" {
+		manifest.Target = result.Context.Source.Name
+	}
+	if status != nil {
+		manifest.DB = &bundle.DB{Built: status.Built, SchemaVersion: status.SchemaVersion, Checksum: status.Checksum}
+	}
+
+	entries, err := resultsArchiveReports(opts, result)
+	if err != nil {
+		return err
+	}
+	if sbomEntry, ok, err := resultsArchiveSBOM(userInput, result); err != nil {
+		return err
+	} else if ok {
+		entries = append(entries, sbomEntry)
+	}
+
+	config, err := yaml.Marshal(opts)
+	if err != nil {
+		return fmt.Errorf("unable to encode the configuration: %w", err)
+	}
+	entries = append(entries, bundle.Entry{Name: "config.yaml", Contents: []byte(redact.Apply(string(config)))})
+
+	if status != nil && status.Location != "" {
+		metadata, err := os.ReadFile(filepath.Join(status.Location, distribution.MetadataFileName))
+		if err != nil {
+			return fmt.Errorf("unable to read the vulnerability database metadata: %w", err)
+		}
+		entries = append(entries, bundle.Entry{Name: "db/" + distribution.MetadataFileName, Contents: metadata})
+	}
+
+	return bundle.Write(opts.ArchiveResults, manifest, entries...)
+}
+
+// resultsArchiveReports renders the JSON report, along with a report of each other output format of the scan.
+func resultsArchiveReports(opts *options.Grype, result models.PresenterConfig) ([]bundle.Entry, error) {
+	outputs, err := format.ParseOutputs(opts.Outputs, opts.File)
+	if err != nil {
+		return nil, err
+	}
+	cfg := format.PresentationConfig{
+		TemplateFilePath: opts.OutputTemplateFile,
+		ShowSuppressed:   opts.ShowSuppressed,
+		Theme:            opts.SeverityTheme(),
+	}
+	formats := []format.Format{format.JSONFormat}
+	for _, o := range outputs {
+		if o.Format != format.JSONFormat {
+			formats = append(formats, o.Format)
+		}
+		if o.Format == format.SarifFormat && cfg.SarifProfile == "" {
+			// a single SARIF report is archived, of the first profile requested
+			cfg.SarifProfile = o.SarifProfile
+		}
+	}
+
+	var entries []bundle.Entry
+	seen := make(map[format.Format]bool)
+	for _, f := range formats {
+		if seen[f] {
+			continue
+		}
+		seen[f] = true
+
+		p := format.GetPresenter(f, cfg, result)
+		if p == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := p.Present(&buf); err != nil {
+			return nil, fmt.Errorf("unable to render the %s report: %w", f, err)
+		}
+		contents := buf.String()
+		if len(opts.Redact) > 0 {
+			contents = redact.Apply(contents)
+		}
+		entries = append(entries, bundle.Entry{Name: "reports/report." + string(f), Contents: []byte(contents)})
+	}
+	return entries, nil
+}
+
+// resultsArchiveSBOM returns the SBOM that was scanned: the SBOM file given as input, or else the SBOM of the packages
+// cataloged from the input (as a syft JSON SBOM).
+func resultsArchiveSBOM(userInput string, result models.PresenterConfig) (bundle.Entry, bool, error) {
+	if input := pkg.ResolveInput(userInput); input.Kind == pkg.SBOMInput && input.Reference != "" {
+		if contents, err := os.ReadFile(input.Reference); err == nil {
+			return bundle.Entry{Name: "sbom/" + filepath.Base(input.Reference), Contents: contents}, true, nil
+		}
+	}
+	if result.SBOM == nil {
+		return bundle.Entry{}, false, nil
+	}
+	var buf bytes.Buffer
+	if err := syftjson.NewFormatEncoder().Encode(&buf, *result.SBOM); err != nil {
+		return bundle.Entry{}, false, fmt.Errorf("unable to encode the SBOM: %w", err)
+	}
+	return bundle.Entry{Name: "sbom/sbom.syft.json", Contents: buf.Bytes()}, true, nil
+}
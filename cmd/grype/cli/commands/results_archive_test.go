@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mholt/archiver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/clio"
+	loggerRedact "github.com/anchore/go-logger/adapter/redact"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/bundle"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/internal/redact"
+)
+
+func TestWriteResultsArchive(t *testing.T) {
+	if redact.Get() == nil {
+		redact.Set(loggerRedact.NewStore())
+	}
+	redact.Add("registry-password")
+
+	id := clio.Identification{Name: "grype", Version: "0.80.0"}
+	opts := options.DefaultGrype(id)
+	opts.Outputs = []string{"table", "json"}
+	opts.ArchiveResults = filepath.Join(t.TempDir(), "results.tgz")
+	opts.Registry.Auth = []options.RegistryCredentials{{Authority: "registry.example.com", Password: "registry-password"}}
+
+	dbDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dbDir, distribution.MetadataFileName), []byte(`{"built": "2024-03-01T00:00:00Z", "version": 5}`), 0o600))
+	status := &distribution.Status{
+		Built:         time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		SchemaVersion: 5,
+		Location:      dbDir,
+		Checksum:      "sha256:abc",
+	}
+
+	sbomPath := filepath.Join(t.TempDir(), "app.spdx.json")
+	require.NoError(t, os.WriteFile(sbomPath, []byte(`{"spdxVersion": "SPDX-2.3"}`), 0o600))
+
+	err := writeResultsArchive(id, opts, "sbom:"+sbomPath, status, models.PresenterConfig{
+		ID:        id,
+		Matches:   match.NewMatches(),
+		Context:   pkg.Context{},
+		AppConfig: opts,
+		DBStatus:  status,
+	})
+	require.NoError(t, err)
+
+	manifest, err := bundle.Verify(opts.ArchiveResults)
+	require.NoError(t, err)
+	assert.Equal(t, "sbom:"+sbomPath, manifest.Target)
+	assert.Equal(t, &bundle.DB{Built: status.Built, SchemaVersion: 5, Checksum: "sha256:abc"}, manifest.DB)
+
+	var names []string
+	for _, f := range manifest.Files {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{
+		"reports/report.json",
+		"reports/report.table",
+		"sbom/app.spdx.json",
+		"config.yaml",
+		"db/metadata.json",
+	}, names)
+
+	files := extractResultsArchive(t, opts.ArchiveResults)
+	assert.NotContains(t, files["config.yaml"], "registry-password")
+	assert.Contains(t, files["config.yaml"], "registry.example.com")
+}
+
+func extractResultsArchive(t *testing.T, path string) map[string]string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, archiver.Unarchive(path, dir))
+	files := make(map[string]string)
+	for _, name := range []string{"config.yaml", "reports/report.json"} {
+		contents, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		files[name] = string(contents)
+	}
+	return files
+}
@@ -1,39 +1,60 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/wagoodman/go-partybus"
 
 	"github.com/anchore/clio"
 	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/cmd/grype/cli/ui"
 	"github.com/anchore/grype/grype"
 	"github.com/anchore/grype/grype/db"
 	"github.com/anchore/grype/grype/db/legacy/distribution"
 	grypeDb "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/dbtrace"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/environment"
 	"github.com/anchore/grype/grype/event"
 	"github.com/anchore/grype/grype/event/parsers"
 	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/ignore"
+	"github.com/anchore/grype/grype/localization"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/matcher/alpm"
 	"github.com/anchore/grype/grype/matcher/dotnet"
 	"github.com/anchore/grype/grype/matcher/golang"
 	"github.com/anchore/grype/grype/matcher/java"
 	"github.com/anchore/grype/grype/matcher/javascript"
 	"github.com/anchore/grype/grype/matcher/python"
 	"github.com/anchore/grype/grype/matcher/ruby"
+	"github.com/anchore/grype/grype/matcher/rust"
 	"github.com/anchore/grype/grype/matcher/stock"
+	"github.com/anchore/grype/grype/osv"
+	"github.com/anchore/grype/grype/pending"
 	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/pkg/cataloger/macos"
+	"github.com/anchore/grype/grype/pkg/qualifier/configuration"
+	"github.com/anchore/grype/grype/postprocess"
 	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/severity"
 	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/supplychain"
+	"github.com/anchore/grype/grype/trust"
 	"github.com/anchore/grype/grype/vex"
 	"github.com/anchore/grype/internal"
 	"github.com/anchore/grype/internal/bus"
 	"github.com/anchore/grype/internal/format"
 	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/grype/internal/redact"
+	"github.com/anchore/grype/internal/resources"
 	"github.com/anchore/grype/internal/stringutil"
 	"github.com/anchore/syft/syft"
 	"github.com/anchore/syft/syft/cataloging"
@@ -66,6 +87,9 @@ You can also explicitly specify the scheme to use:
     {{.appName}} sbom:path/to/syft.json                 read Syft JSON from path on disk
     {{.appName}} registry:yourrepo/yourimage:tag        pull image directly from a registry (no container runtime required)
     {{.appName}} purl:path/to/purl/file                 read a newline separated file of purls from a path on disk
+    {{.appName}} python-env:path/to/site-packages       read the libraries of a Python environment along with its interpreter
+    {{.appName}} node-env:path/to/node_modules          read the libraries of a Node.js environment along with its interpreter
+    {{.appName}} ruby-env:path/to/lib/ruby              read the gems of a Ruby environment along with its interpreter
 
 You can also pipe in Syft JSON directly:
 	syft yourimage:tag -o json | {{.appName}}
@@ -81,6 +105,10 @@ You can also pipe in Syft JSON directly:
 			if len(args) > 0 {
 				userInput = args[0]
 			}
+			if len(opts.Redact) > 0 {
+				applyRedactions(userInput, opts)
+				return redact.Error(runGrype(app, opts, userInput))
+			}
 			return runGrype(app, opts, userInput)
 		},
 		ValidArgsFunction: dockerImageValidArgsFunction,
@@ -110,9 +138,46 @@ var ignoreLinuxKernelHeaders = []match.IgnoreRule{
 
 //nolint:funlen
 func runGrype(app clio.Application, opts *options.Grype, userInput string) (errs error) {
+	if opts.DryRun {
+		return runDryRun(opts, userInput)
+	}
+
+	if opts.Theme != "" || len(opts.ThemePalette) > 0 {
+		// the UI shows plain severities unless a theme is chosen (it was created before the config was loaded)
+		ui.SetTheme(opts.SeverityTheme())
+	}
+
+	budget := grype.NewScanBudget(opts.MaxScanDuration())
+	var rejections *match.Rejections
+	if opts.IncludeRejections {
+		rejections = match.NewRejections()
+	}
+	// the heuristic comparisons are always reported, whether they matched or not
+	fuzzyRejections := match.NewRejections()
+
+	memory, restoreLimits := resources.Apply(opts.Limits.ToLimits())
+	defer restoreLimits()
+	// the cataloging and the matching are aborted as soon as the memory budget is exceeded
+	scanCtx, cancelScan := memory.Context(context.Background())
+	defer cancelScan()
+	// the cataloging is bounded by the maximum scan time too (the matching leaves the packages it has no time for
+	// unscanned instead)
+	catalogCtx, cancelCatalog := budget.Context(scanCtx)
+	defer cancelCatalog()
+
+	scanLog := startScanLog()
+	defer func() { scanLog.end(errs) }()
+	scanLog.startPhase(prepareScanPhase)
+
 	writer, err := format.MakeScanResultWriter(opts.Outputs, opts.File, format.PresentationConfig{
 		TemplateFilePath: opts.OutputTemplateFile,
 		ShowSuppressed:   opts.ShowSuppressed,
+		Redact:           len(opts.Redact) > 0,
+		Theme:            opts.SeverityTheme(),
+		Top:              opts.Ranking.Top,
+		RankBy:           opts.Ranking.Criterion(),
+		Split:            opts.Split(),
+		SplitFilename:    opts.OutputSplitFilename,
 	})
 	if err != nil {
 		return err
@@ -124,28 +189,23 @@ func runGrype(app clio.Application, opts *options.Grype, userInput string) (errs
 	var packages []pkg.Package
 	var s *sbom.SBOM
 	var pkgContext pkg.Context
+	var platformScans []platformScan
 
-	if opts.OnlyFixed {
-		opts.Ignore = append(opts.Ignore, ignoreNonFixedMatches...)
-	}
-
-	if opts.OnlyNotFixed {
-		opts.Ignore = append(opts.Ignore, ignoreFixedMatches...)
-	}
-
-	if !opts.MatchUpstreamKernelHeaders {
-		opts.Ignore = append(opts.Ignore, ignoreLinuxKernelHeaders...)
+	if err = resolveIgnoreRules(opts); err != nil {
+		return err
 	}
 
-	for _, ignoreState := range stringutil.SplitCommaSeparatedString(opts.IgnoreStates) {
-		switch grypeDb.FixState(ignoreState) {
-		case grypeDb.UnknownFixState, grypeDb.FixedState, grypeDb.NotFixedState, grypeDb.WontFixState:
-			opts.Ignore = append(opts.Ignore, match.IgnoreRule{FixState: ignoreState})
-		default:
-			return fmt.Errorf("unknown fix state %s was supplied for --ignore-states", ignoreState)
+	catalogInput := userInput
+	if opts.UseReferrers {
+		var cleanup func()
+		catalogInput, cleanup, err = useReferrers(userInput, opts)
+		if err != nil {
+			return err
 		}
+		defer cleanup()
 	}
 
+	scanLog.startPhase(loadScanPhase)
 	err = parallel(
 		func() error {
 			checkForAppUpdate(app.ID(), opts)
@@ -158,11 +218,21 @@ func runGrype(app clio.Application, opts *options.Grype, userInput string) (errs
 		},
 		func() (err error) {
 			log.Debugf("gathering packages")
+			if len(opts.Platforms) > 0 {
+				platformScans, err = providePlatforms(catalogCtx, userInput, opts)
+				if err != nil {
+					return fmt.Errorf("failed to catalog: %w", err)
+				}
+				packages, pkgContext, s = combinePlatforms(platformScans)
+				return nil
+			}
 			// packages are grype.Package, not syft.Package
 			// the SBOM is returned for downstream formatting concerns
 			// grype uses the SBOM in combination with syft formatters to produce cycloneDX
 			// with vulnerability information appended
-			packages, pkgContext, s, err = pkg.Provide(userInput, getProviderConfig(opts))
+			providerConfig := getProviderConfig(opts)
+			providerConfig.Context = catalogCtx
+			packages, pkgContext, s, err = pkg.Provide(catalogInput, providerConfig)
 			if err != nil {
 				return fmt.Errorf("failed to catalog: %w", err)
 			}
@@ -178,46 +248,220 @@ func runGrype(app clio.Application, opts *options.Grype, userInput string) (errs
 		defer dbCloser.Close()
 	}
 
+	if err = memory.Err(); err != nil {
+		return err
+	}
+
+	if opts.DBTrace != "" {
+		trace, err := os.Create(opts.DBTrace)
+		if err != nil {
+			return fmt.Errorf("unable to create the DB query trace: %w", err)
+		}
+		defer trace.Close()
+		str = dbtrace.Apply(str, trace)
+	}
+
 	if err = applyVexRules(opts); err != nil {
 		return fmt.Errorf("applying vex rules: %w", err)
 	}
 
 	applyDistroHint(packages, &pkgContext, opts)
+	for i := range platformScans {
+		applyDistroHint(platformScans[i].packages, &platformScans[i].context, opts)
+	}
+
+	aliasResolver, err := opts.Aliases.ToResolver(status.Location)
+	if err != nil {
+		return err
+	}
+
+	pendingFeed, err := opts.PendingAnalysis.ToFeed(status.Location)
+	if err != nil {
+		return err
+	}
+	str = pending.Apply(str, pendingFeed)
+
+	malwareDataset, err := opts.Malware.ToDataset(status.Location)
+	if err != nil {
+		return err
+	}
+
+	popularity, err := opts.Typosquatting.ToPopularity(status.Location)
+	if err != nil {
+		return err
+	}
+
+	packageStatuses, err := opts.PackageStatus.ToStatuses(status.Location)
+	if err != nil {
+		return err
+	}
+
+	baseImages, err := opts.BaseImageAdvice.ToDataset(status.Location)
+	if err != nil {
+		return err
+	}
+
+	enrichment, err := opts.Ranking.ToEnrichment(status.Location)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := opts.Localization.ToBundle(status.Location)
+	if err != nil {
+		return err
+	}
+	str = localization.Apply(str, bundle)
+
+	if osvConfig, ok := opts.ExternalSources.ToOSVProviderConfig(opts.DB.Dir); ok {
+		str = osv.Apply(str, osvConfig, packages)
+	}
+
+	severityOverrides, err := severity.FromFiles(opts.SeverityOverrides...)
+	if err != nil {
+		return err
+	}
+	// the metrics were validated when the configuration was loaded
+	cvssMetrics, _ := severity.ParseMetrics(opts.CVSSMetrics)
+	// the context was validated when the configuration was loaded
+	envContext, _ := environment.Parse(opts.Context...)
+	severityOverrides.SetMetrics(envContext.AdjustMetrics(cvssMetrics))
+	// the weights were validated when the configuration was loaded
+	trustWeights, _ := trust.New(opts.TrustWeights...)
+	// the disabled features were validated when the configuration was loaded
+	disabledFeatures, _ := configuration.ParseAllDisabled(opts.DisabledFeatures...)
+	// the disabled distros were validated when the configuration was loaded
+	disabledDistros, _ := distro.ParseTypes(opts.DisabledDistros...)
 
 	vulnMatcher := grype.VulnerabilityMatcher{
 		Store:          *str,
 		IgnoreRules:    opts.Ignore,
 		NormalizeByCVE: opts.ByCVE,
-		FailSeverity:   opts.FailOnSeverity(),
 		Matchers:       getMatchers(opts),
 		VexProcessor: vex.NewProcessor(vex.ProcessorOptions{
 			Documents:   opts.VexDocuments,
 			IgnoreRules: opts.Ignore,
+			Environment: envContext,
 		}),
+		Aliases:           aliasResolver,
+		SeverityOverrides: severityOverrides,
+		TrustWeights:      trustWeights,
+		DisabledFeatures:  disabledFeatures,
+		DisabledDistros:   disabledDistros,
+		Strict:            opts.Strict,
+		Environment:       envContext,
+		Budget:            budget,
+		Rejections:        rejections,
+		FuzzyRejections:   fuzzyRejections,
+		Context:           scanCtx,
 	}
 
-	remainingMatches, ignoredMatches, err := vulnMatcher.FindMatches(packages, pkgContext)
+	scanLog.startPhase(matchScanPhase)
+	var remainingMatches *match.Matches
+	var ignoredMatches []match.IgnoredMatch
+	var platformResults []models.PlatformResult
+	if len(platformScans) > 0 {
+		remainingMatches, ignoredMatches, platformResults, err = findPlatformMatches(vulnMatcher, platformScans)
+	} else {
+		remainingMatches, ignoredMatches, err = vulnMatcher.FindMatches(packages, pkgContext)
+	}
 	if err != nil {
-		if !errors.Is(err, grypeerr.ErrAboveSeverityThreshold) {
+		if !grypeerr.IsScanFailure(err) {
 			return err
 		}
 		errs = appendErrors(errs, err)
 	}
 
-	if err = writer.Write(models.PresenterConfig{
-		ID:               app.ID(),
-		Matches:          *remainingMatches,
-		IgnoredMatches:   ignoredMatches,
-		Packages:         packages,
-		Context:          pkgContext,
-		MetadataProvider: str,
-		SBOM:             s,
-		AppConfig:        opts,
-		DBStatus:         status,
-	}); err != nil {
+	if err = memory.Err(); err != nil {
+		return err
+	}
+
+	unsupported, err := findUnsupportedPackages(vulnMatcher, packages, pkgContext, platformScans)
+	if err != nil {
+		return err
+	}
+
+	malwareFindings := findMalware(malwareDataset, packages)
+	if err := malwareThresholdError(malwareFindings, opts.FailOn, errs); err != nil {
+		errs = appendErrors(errs, err)
+	}
+
+	supplyChainWarnings := findSupplyChainWarnings(popularity, packages)
+	if opts.PackageStatus.FlagAbandoned {
+		supplyChainWarnings = append(supplyChainWarnings, findAbandonedPackages(packageStatuses, packages, *remainingMatches)...)
+		supplychain.SortWarnings(supplyChainWarnings)
+	}
+
+	licenseViolations := opts.LicensePolicy.ToPolicy().Evaluate(packages)
+	if len(licenseViolations) > 0 {
+		log.WithFields("violations", len(licenseViolations)).Warn("found package licenses violating the license policy")
+		if opts.LicensePolicy.FailOnViolation {
+			errs = appendErrors(errs, grypeerr.ErrLicensePolicyViolation)
+		}
+	}
+
+	baseImageAdvice := baseImages.Advise(pkgContext, *remainingMatches)
+	if recommended := baseImageAdvice.Recommended(); recommended != nil {
+		log.WithFields("base", baseImageAdvice.Base.String(), "upgrade", recommended.Base.Tag, "remediates", recommended.Remediates).
+			Info("a newer tag of the base image remediates findings")
+	}
+
+	if opts.History.Enabled {
+		// the scan history is a convenience, failing to record the scan does not fail the scan
+		if err := recordScanHistory(opts.History, userInput, pkgContext, status, *remainingMatches, str); err != nil {
+			log.WithFields("path", opts.History.Path, "error", err).Warn("unable to record the scan in the scan history")
+		}
+	}
+
+	scanLog.startPhase(reportScanPhase)
+	manifest, err := newScanManifest(app.ID(), opts, userInput, pkgContext, status, scanLog)
+	if err != nil {
+		return err
+	}
+	result := models.PresenterConfig{
+		ID:                  app.ID(),
+		Matches:             *remainingMatches,
+		IgnoredMatches:      ignoredMatches,
+		Packages:            packages,
+		Context:             pkgContext,
+		MetadataProvider:    str,
+		SBOM:                s,
+		AppConfig:           opts,
+		DBStatus:            status,
+		Platforms:           platformResults,
+		UnsupportedPackages: unsupported,
+		Malware:             malwareFindings,
+		SupplyChain:         supplyChainWarnings,
+		PackageStatuses:     packageStatuses.Annotate(packages),
+		LicenseViolations:   licenseViolations,
+		BaseImage:           baseImageAdvice,
+		UnscannedPackages:   budget.Unscanned(),
+		Rejections:          rejections.Sorted(),
+		FuzzyRejections:     fuzzyRejections.Sorted(),
+		MatchEvidence:       opts.MatchEvidence,
+		MaxResults:          opts.MaxResults,
+		ResultsOffset:       opts.ResultsOffset,
+		Environment:         envContext,
+		Enrichment:          enrichment,
+		Manifest:            manifest,
+	}
+	if result, err = postprocess.Apply(result, opts.PostProcessors.ToProcessors()...); err != nil {
+		return appendErrors(errs, fmt.Errorf("unable to post-process the results: %w", err))
+	}
+	// the severity threshold applies to the matches as presented, which the post-processors may have removed or
+	// changed the severity of
+	if failOn := opts.FailOnSeverity(); failOn != nil && !errors.Is(errs, grypeerr.ErrAboveSeverityThreshold) && grype.HasSeverityAtOrAbove(result.MetadataProvider, *failOn, result.Matches) {
+		errs = appendErrors(errs, grypeerr.ErrAboveSeverityThreshold)
+	}
+	if err = writer.Write(result); err != nil {
 		errs = appendErrors(errs, err)
 	}
 
+	if opts.ArchiveResults != "" {
+		if err := writeResultsArchive(app.ID(), opts, userInput, status, result); err != nil {
+			errs = appendErrors(errs, fmt.Errorf("unable to archive the results: %w", err))
+		}
+	}
+
 	return errs
 }
 
@@ -241,6 +485,7 @@ func applyDistroHint(pkgs []pkg.Package, context *pkg.Context, opts *options.Gry
 			Version:   v,
 			VersionID: v,
 		}
+		context.DistroInferredFrom = ""
 	}
 
 	hasOSPackage := false
@@ -282,24 +527,30 @@ func checkForAppUpdate(id clio.Identification, opts *options.Grype) {
 }
 
 func getMatchers(opts *options.Grype) []matcher.Matcher {
-	return matcher.NewDefaultMatchers(
-		matcher.Config{
-			Java: java.MatcherConfig{
-				ExternalSearchConfig: opts.ExternalSources.ToJavaMatcherConfig(),
-				UseCPEs:              opts.Match.Java.UseCPEs,
-			},
-			Ruby:       ruby.MatcherConfig(opts.Match.Ruby),
-			Python:     python.MatcherConfig(opts.Match.Python),
-			Dotnet:     dotnet.MatcherConfig(opts.Match.Dotnet),
-			Javascript: javascript.MatcherConfig(opts.Match.Javascript),
-			Golang: golang.MatcherConfig{
-				UseCPEs:                                opts.Match.Golang.UseCPEs,
-				AlwaysUseCPEForStdlib:                  opts.Match.Golang.AlwaysUseCPEForStdlib,
-				AllowMainModulePseudoVersionComparison: opts.Match.Golang.AllowMainModulePseudoVersionComparison,
-			},
-			Stock: stock.MatcherConfig(opts.Match.Stock),
+	return matcher.NewDefaultMatchers(getMatcherConfig(opts))
+}
+
+func getMatcherConfig(opts *options.Grype) matcher.Config {
+	return matcher.Config{
+		Java: java.MatcherConfig{
+			ExternalSearchConfig: opts.ExternalSources.ToJavaMatcherConfig(),
+			UseCPEs:              opts.Match.Java.UseCPEs,
 		},
-	)
+		Ruby:       ruby.MatcherConfig{UseCPEs: opts.Match.Ruby.UseCPEs},
+		Python:     python.MatcherConfig{UseCPEs: opts.Match.Python.UseCPEs},
+		Dotnet:     dotnet.MatcherConfig{UseCPEs: opts.Match.Dotnet.UseCPEs},
+		Javascript: javascript.MatcherConfig{UseCPEs: opts.Match.Javascript.UseCPEs},
+		Golang: golang.MatcherConfig{
+			UseCPEs:                                opts.Match.Golang.UseCPEs,
+			AlwaysUseCPEForStdlib:                  opts.Match.Golang.AlwaysUseCPEForStdlib,
+			AllowMainModulePseudoVersionComparison: opts.Match.Golang.AllowMainModulePseudoVersionComparison,
+		},
+		Rust:      rust.MatcherConfig{UseCPEs: opts.Match.Rust.UseCPEs},
+		Stock:     stock.MatcherConfig{UseCPEs: opts.Match.Stock.UseCPEs},
+		Alpm:      alpm.MatcherConfig{UseCPEs: opts.Match.Alpm.UseCPEs},
+		Policies:  opts.Match.ToPolicies(),
+		Targeting: opts.Match.ToTargeting(),
+	}
 }
 
 func getProviderConfig(opts *options.Grype) pkg.ProviderConfig {
@@ -312,6 +563,16 @@ func getProviderConfig(opts *options.Grype) pkg.ProviderConfig {
 	// save us the effort of ever attempting to match with these packages as early as possible.
 	cfg.Compliance.MissingVersion = cataloging.ComplianceActionDrop
 
+	// syft does not catalog macOS package managers, however these are relevant for scanning developer machines and CI runners
+	cfg.WithCatalogers(macos.CatalogerReferences()...)
+
+	// the value was validated when the configuration was loaded
+	lastLayers, _ := pkg.ParseLayers(opts.Layers)
+
+	precedence := pkg.OverlapPrecedence(opts.OverlapPrecedence)
+	// syft drops binary packages owned by other packages on its own, which must not happen when they should be kept
+	cfg.Relationships.ExcludeBinaryPackagesWithFileOwnershipOverlap = precedence.DropsOwnedPackages()
+
 	return pkg.ProviderConfig{
 		SyftProviderConfig: pkg.SyftProviderConfig{
 			RegistryOptions:        opts.Registry.ToOptions(),
@@ -320,11 +581,42 @@ func getProviderConfig(opts *options.Grype) pkg.ProviderConfig {
 			Platform:               opts.Platform,
 			Name:                   opts.Name,
 			DefaultImagePullSource: opts.DefaultImagePullSource,
+			LayerCache:             opts.LayerCache.ToLayerCache(),
 		},
 		SynthesisConfig: pkg.SynthesisConfig{
 			GenerateMissingCPEs: opts.GenerateMissingCPEs,
 		},
+		OverlapPrecedence: precedence,
+		Scope: pkg.Scope{
+			IncludePaths: opts.IncludePaths,
+			ExcludePaths: opts.ExcludePaths,
+			LastLayers:   lastLayers,
+		},
+		Lockfiles:          opts.Lockfiles,
+		LockfileMode:       pkg.LockfileMode(opts.LockfileMode),
+		IgnoreArchitecture: opts.IgnoreArchitecture,
+		SBOMDecoders:       opts.SBOMDecoders.ToDecoders(),
+	}
+}
+
+// newScanManifest records the conditions of the scan, as of now, to embed in the reports.
+func newScanManifest(id clio.Identification, opts *options.Grype, userInput string, pkgContext pkg.Context, status *distribution.Status, scanLog *scanLog) (*models.ScanManifest, error) {
+	var db models.ScanManifestDB
+	if status != nil {
+		db = models.ScanManifestDB{
+			Checksum:      status.Checksum,
+			SchemaVersion: status.SchemaVersion,
+		}
+		if !status.Built.IsZero() {
+			db.Built = status.Built.UTC().Format(time.RFC3339)
+		}
 	}
+	manifest, err := models.NewScanManifest(id, db, opts, userInput, pkgContext.Source, scanLog.begun, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	manifest.ScanID = scanLog.id
+	return manifest, nil
 }
 
 func validateDBLoad(loadErr error, status *distribution.Status) error {
@@ -366,6 +658,45 @@ func validateRootArgs(cmd *cobra.Command, args []string) error {
 	return cobra.MaximumNArgs(1)(cmd, args)
 }
 
+// resolveIgnoreRules adds the ignore rules of the options (e.g. --only-fixed) and of the ignore files to the configured rules.
+func resolveIgnoreRules(opts *options.Grype) error {
+	if opts.OnlyFixed {
+		opts.Ignore = append(opts.Ignore, ignoreNonFixedMatches...)
+	}
+
+	if opts.OnlyNotFixed {
+		opts.Ignore = append(opts.Ignore, ignoreFixedMatches...)
+	}
+
+	if !opts.MatchUpstreamKernelHeaders {
+		opts.Ignore = append(opts.Ignore, ignoreLinuxKernelHeaders...)
+	}
+
+	for _, ignoreState := range stringutil.SplitCommaSeparatedString(opts.IgnoreStates) {
+		switch grypeDb.FixState(ignoreState) {
+		case grypeDb.UnknownFixState, grypeDb.FixedState, grypeDb.NotFixedState, grypeDb.WontFixState:
+			opts.Ignore = append(opts.Ignore, match.IgnoreRule{FixState: ignoreState})
+		default:
+			return fmt.Errorf("unknown fix state %s was supplied for --ignore-states", ignoreState)
+		}
+	}
+
+	for _, ignoreFile := range opts.IgnoreFiles {
+		rules, err := ignore.Import(ignoreFile)
+		if err != nil {
+			return fmt.Errorf("unable to import ignore rules from %q: %w", ignoreFile, err)
+		}
+		log.WithFields("file", ignoreFile, "rules", len(rules)).Debug("imported ignore rules")
+		opts.Ignore = append(opts.Ignore, rules...)
+	}
+
+	// the rules restricted to another environment do not apply (the context was validated when the configuration was loaded)
+	envContext, _ := environment.Parse(opts.Context...)
+	opts.Ignore = envContext.ApplicableRules(opts.Ignore)
+
+	return nil
+}
+
 func applyVexRules(opts *options.Grype) error {
 	if len(opts.Ignore) == 0 && len(opts.VexDocuments) > 0 {
 		opts.Ignore = append(opts.Ignore, ignoreVEXFixedNotAffected...)
@@ -9,6 +9,8 @@ import (
 
 	"github.com/anchore/clio"
 	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype/lib"
+	"github.com/anchore/grype/grype/matcher/java"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/stereoscope/pkg/image"
 	"github.com/anchore/syft/syft"
@@ -16,6 +18,23 @@ import (
 	"github.com/anchore/syft/syft/pkg/cataloger/binary"
 )
 
+func Test_getMatcherConfig_libDefaults(t *testing.T) {
+	opts := options.DefaultGrype(clio.Identification{Name: "grype"})
+	want := getMatcherConfig(opts)
+	got := lib.DefaultScanOptions().Matchers
+
+	// the external sources are configured apart from the matchers (and disabled by default)
+	assert.False(t, want.Java.SearchMavenUpstream)
+	want.Java.ExternalSearchConfig = java.ExternalSearchConfig{}
+	// the default matcher policies are those of matchers without a policy
+	assert.Empty(t, want.Policies)
+	assert.Empty(t, got.Policies)
+	want.Policies = nil
+	got.Policies = nil
+
+	assert.Equal(t, want, got)
+}
+
 func Test_applyDistroHint(t *testing.T) {
 	ctx := pkg.Context{}
 	cfg := options.Grype{}
@@ -71,6 +90,8 @@ func Test_getProviderConfig(t *testing.T) {
 						Credentials: []image.RegistryCredentials{},
 					},
 				},
+				OverlapPrecedence: pkg.OverlapPrecedenceAuto,
+				LockfileMode:      pkg.IncludeLockfiles,
 			},
 		},
 	}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/anchore/go-logger"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/stereoscope"
+	"github.com/anchore/syft/syft"
+)
+
+const (
+	prepareScanPhase = "prepare"
+	loadScanPhase    = "load"
+	matchScanPhase   = "match"
+	reportScanPhase  = "report"
+)
+
+// scanLog correlates the log entries of a scan: while the scan runs, every entry is logged with the ID of the scan and
+// the phase of the scan logging it (the phases run one after the other), and the start and the end of every phase are
+// logged along with how long the phase took. This holds for the entries logged by syft and stereoscope as well.
+type scanLog struct {
+	id      string
+	base    logger.Logger
+	phase   string
+	started time.Time
+	// begun is when the scan started, for the scan manifest
+	begun time.Time
+}
+
+func startScanLog() *scanLog {
+	return &scanLog{
+		id:    uuid.NewString(),
+		base:  log.Get(),
+		begun: time.Now(),
+	}
+}
+
+// startPhase ends the current phase (if any) and starts the given one.
+func (s *scanLog) startPhase(phase string) {
+	s.endPhase(nil)
+	s.phase = phase
+	s.started = time.Now()
+	setLogger(s.base.Nested("scan", s.id, "phase", phase))
+	log.Debug("scan phase started")
+}
+
+// end ends the current phase with the outcome of the scan, and restores the logger of the application.
+func (s *scanLog) end(err error) {
+	s.endPhase(err)
+	setLogger(s.base)
+}
+
+func setLogger(l logger.Logger) {
+	log.Set(l)
+	syft.SetLogger(l)
+	stereoscope.SetLogger(l)
+}
+
+func (s *scanLog) endPhase(err error) {
+	if s.phase == "" {
+		return
+	}
+	fields := []any{"duration", time.Since(s.started).String()}
+	if err != nil {
+		fields = append(fields, "error", err)
+	}
+	log.WithFields(fields...).Debug("scan phase completed")
+	s.phase = ""
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/logrus"
+	"github.com/anchore/grype/internal/log"
+)
+
+func Test_scanLog(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "scan.log")
+	base, err := logrus.New(logrus.Config{FileLocation: file, Level: logger.DebugLevel, Formatter: logrus.DefaultJSONFormatter()})
+	require.NoError(t, err)
+	previous := log.Get()
+	log.Set(base)
+	defer log.Set(previous)
+
+	s := startScanLog()
+	s.startPhase(loadScanPhase)
+	log.Info("loading")
+	s.startPhase(matchScanPhase)
+	s.end(errors.New("failed"))
+	log.Info("after the scan")
+
+	contents, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(contents)), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 6)
+
+	phases := []string{loadScanPhase, loadScanPhase, loadScanPhase, matchScanPhase, matchScanPhase}
+	messages := []string{"scan phase started", "loading", "scan phase completed", "scan phase started", "scan phase completed"}
+	for i, entry := range entries[:5] {
+		assert.Equal(t, s.id, entry["scan"])
+		assert.Equal(t, phases[i], entry["phase"])
+		assert.Equal(t, messages[i], entry["msg"])
+	}
+	assert.NotEmpty(t, entries[2]["duration"])
+	assert.Nil(t, entries[2]["error"])
+	assert.Equal(t, "failed", entries[4]["error"])
+
+	// the logger of the application is restored after the scan
+	assert.Equal(t, "after the scan", entries[5]["msg"])
+	assert.Nil(t, entries[5]["scan"])
+}
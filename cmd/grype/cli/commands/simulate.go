@@ -0,0 +1,327 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/internal/bus"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/packageurl-go"
+)
+
+type simulateOptions struct {
+	Output    string              `yaml:"output" json:"output" mapstructure:"output"`
+	Packages  []string            `yaml:"packages" json:"packages" mapstructure:"packages"`
+	UpgradeTo []string            `yaml:"upgrade-to" json:"upgrade-to" mapstructure:"upgrade-to"`
+	File      string              `yaml:"file" json:"file" mapstructure:"file"`
+	Match     options.MatchConfig `yaml:"match" json:"match" mapstructure:"match"`
+	Ignore    []match.IgnoreRule  `yaml:"ignore" json:"ignore" mapstructure:"ignore"`
+	DBOptions `yaml:",inline" mapstructure:",squash"`
+}
+
+var _ clio.FlagAdder = (*simulateOptions)(nil)
+
+func (o *simulateOptions) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", "format to display results (available=[table, json])")
+	flags.StringArrayVarP(&o.Packages, "package", "p", "the purl of an installed package, including the current version (e.g. pkg:npm/lodash@4.17.21)")
+	flags.StringArrayVarP(&o.UpgradeTo, "upgrade-to", "u", "the version to upgrade to, given once per --package in the same order")
+	flags.StringVarP(&o.File, "file", "f", `a file of upgrades to simulate, one "<purl> <upgrade-version>" pair per line`)
+}
+
+// simulateRequest is a single package upgrade to evaluate.
+type simulateRequest struct {
+	PURL      string
+	UpgradeTo string
+}
+
+// simulateFinding is a vulnerability found for one side of a simulated upgrade.
+type simulateFinding struct {
+	ID          string   `json:"id"`
+	Severity    string   `json:"severity"`
+	FixVersions []string `json:"fixVersions"`
+}
+
+// simulateResult describes how the set of matches for a package would change after an upgrade.
+type simulateResult struct {
+	Package    string            `json:"package"`
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Fixed      []simulateFinding `json:"fixed"`
+	Remaining  []simulateFinding `json:"remaining"`
+	Introduced []simulateFinding `json:"introduced"`
+}
+
+func Simulate(app clio.Application) *cobra.Command {
+	opts := &simulateOptions{
+		Output:    "table",
+		Match:     options.DefaultMatchConfig(),
+		DBOptions: *dbOptionsDefault(app.ID()),
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "simulate --package [PURL] --upgrade-to [VERSION]",
+		Short: "show which vulnerabilities would be fixed (or introduced) by upgrading packages, without running a scan",
+		Example: `  grype simulate --package pkg:npm/lodash@4.17.20 --upgrade-to 4.17.21
+  grype simulate --file upgrades.txt -o json`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runSimulate(app.ID(), opts)
+		},
+	}, opts)
+}
+
+func runSimulate(id clio.Identification, opts *simulateOptions) error {
+	requests, err := simulateRequests(opts)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("loading DB")
+	str, status, dbCloser, err := grype.LoadVulnerabilityDB(opts.DB.ToCuratorConfig(), opts.DB.AutoUpdate)
+	err = validateDBLoad(err, status)
+	if err != nil {
+		return err
+	}
+	if dbCloser != nil {
+		defer dbCloser.Close()
+	}
+
+	grypeOpts := options.DefaultGrype(id)
+	grypeOpts.Match = opts.Match
+
+	results, err := simulateUpgrades(*str, getMatchers(grypeOpts), opts.Ignore, requests)
+	if err != nil {
+		return err
+	}
+
+	sb := &strings.Builder{}
+	err = presentSimulation(opts.Output, results, sb)
+	bus.Report(sb.String())
+
+	return err
+}
+
+// simulateRequests collects the upgrades given via flags and via the upgrades file.
+func simulateRequests(opts *simulateOptions) ([]simulateRequest, error) {
+	if len(opts.Packages) != len(opts.UpgradeTo) {
+		return nil, fmt.Errorf("each --package requires a matching --upgrade-to (got %d packages and %d versions)", len(opts.Packages), len(opts.UpgradeTo))
+	}
+
+	var requests []simulateRequest
+	for i, p := range opts.Packages {
+		requests = append(requests, simulateRequest{PURL: p, UpgradeTo: opts.UpgradeTo[i]})
+	}
+
+	if opts.File != "" {
+		f, err := os.Open(opts.File)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open upgrades file: %w", err)
+		}
+		defer f.Close()
+
+		fromFile, err := readSimulateRequests(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read upgrades file %q: %w", opts.File, err)
+		}
+		requests = append(requests, fromFile...)
+	}
+
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("no upgrades to simulate: provide --package and --upgrade-to or --file")
+	}
+	return requests, nil
+}
+
+func readSimulateRequests(reader io.Reader) ([]simulateRequest, error) {
+	var requests []simulateRequest
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected \"<purl> <upgrade-version>\", got %q", line, text)
+		}
+		requests = append(requests, simulateRequest{PURL: fields[0], UpgradeTo: fields[1]})
+	}
+	return requests, scanner.Err()
+}
+
+func simulateUpgrades(str store.Store, matchers []matcher.Matcher, ignoreRules []match.IgnoreRule, requests []simulateRequest) ([]simulateResult, error) {
+	vulnMatcher := grype.VulnerabilityMatcher{
+		Store:       str,
+		Matchers:    matchers,
+		IgnoreRules: ignoreRules,
+	}
+
+	var results []simulateResult
+	for _, r := range requests {
+		before, err := pkg.NewFromPURL(r.PURL)
+		if err != nil {
+			return nil, err
+		}
+		if before.Version == "" {
+			return nil, fmt.Errorf("purl %q must include the currently installed version", r.PURL)
+		}
+
+		afterPURL, err := withVersion(r.PURL, r.UpgradeTo)
+		if err != nil {
+			return nil, err
+		}
+		after, err := pkg.NewFromPURL(afterPURL)
+		if err != nil {
+			return nil, err
+		}
+		basePURL, err := withVersion(r.PURL, "")
+		if err != nil {
+			return nil, err
+		}
+
+		context := pkg.Context{Distro: pkg.ReleaseFromPURL(r.PURL)}
+
+		beforeFindings, err := simulateFindings(str, vulnMatcher, before, context)
+		if err != nil {
+			return nil, err
+		}
+		afterFindings, err := simulateFindings(str, vulnMatcher, after, context)
+		if err != nil {
+			return nil, err
+		}
+
+		result := simulateResult{
+			Package:    basePURL,
+			From:       before.Version,
+			To:         after.Version,
+			Fixed:      []simulateFinding{},
+			Remaining:  []simulateFinding{},
+			Introduced: []simulateFinding{},
+		}
+		for _, id := range sortedFindingIDs(beforeFindings) {
+			if _, ok := afterFindings[id]; ok {
+				result.Remaining = append(result.Remaining, afterFindings[id])
+				continue
+			}
+			result.Fixed = append(result.Fixed, beforeFindings[id])
+		}
+		for _, id := range sortedFindingIDs(afterFindings) {
+			if _, ok := beforeFindings[id]; !ok {
+				result.Introduced = append(result.Introduced, afterFindings[id])
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// withVersion returns the purl with its version replaced (or removed, when the version is empty).
+func withVersion(raw, ver string) (string, error) {
+	purl, err := packageurl.FromString(raw)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode purl %s: %w", raw, err)
+	}
+	purl.Version = ver
+	return purl.String(), nil
+}
+
+func simulateFindings(str store.Store, vulnMatcher grype.VulnerabilityMatcher, p pkg.Package, context pkg.Context) (map[string]simulateFinding, error) {
+	matches, _, err := vulnMatcher.FindMatches([]pkg.Package{p}, context)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find matches for %s@%s: %w", p.Name, p.Version, err)
+	}
+
+	findings := make(map[string]simulateFinding)
+	for _, m := range matches.Sorted() {
+		if _, ok := findings[m.Vulnerability.ID]; ok {
+			continue
+		}
+		severity := "Unknown"
+		if metadata, err := str.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace); err == nil && metadata != nil && metadata.Severity != "" {
+			severity = metadata.Severity
+		}
+		findings[m.Vulnerability.ID] = simulateFinding{
+			ID:          m.Vulnerability.ID,
+			Severity:    severity,
+			FixVersions: m.Vulnerability.Fix.Versions,
+		}
+	}
+	return findings, nil
+}
+
+func sortedFindingIDs(findings map[string]simulateFinding) []string {
+	ids := make([]string, 0, len(findings))
+	for id := range findings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func presentSimulation(outputFormat string, results []simulateResult, output io.Writer) error {
+	switch outputFormat {
+	case "table":
+		rows := [][]string{}
+		for _, r := range results {
+			for _, f := range r.Fixed {
+				rows = append(rows, []string{r.Package, r.From, r.To, f.ID, f.Severity, "fixed"})
+			}
+			for _, f := range r.Remaining {
+				rows = append(rows, []string{r.Package, r.From, r.To, f.ID, f.Severity, "remaining"})
+			}
+			for _, f := range r.Introduced {
+				rows = append(rows, []string{r.Package, r.From, r.To, f.ID, f.Severity, "introduced"})
+			}
+			if len(r.Fixed)+len(r.Remaining)+len(r.Introduced) == 0 {
+				rows = append(rows, []string{r.Package, r.From, r.To, "", "", "no vulnerabilities"})
+			}
+		}
+
+		table := tablewriter.NewWriter(output)
+		columns := []string{"Package", "From", "To", "Vulnerability", "Severity", "Outcome"}
+
+		table.SetHeader(columns)
+		table.SetAutoWrapText(false)
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+		table.SetHeaderLine(false)
+		table.SetBorder(false)
+		table.SetAutoFormatHeaders(true)
+		table.SetCenterSeparator("")
+		table.SetColumnSeparator("")
+		table.SetRowSeparator("")
+		table.SetTablePadding("  ")
+		table.SetNoWhiteSpace(true)
+
+		table.AppendBulk(rows)
+		table.Render()
+	case "json":
+		enc := json.NewEncoder(output)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", " ")
+		if err := enc.Encode(results); err != nil {
+			return fmt.Errorf("failed to encode simulation results: %+v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type simulateProvider struct {
+	byName map[string][]vulnerability.Vulnerability
+}
+
+func (s simulateProvider) Get(_, _ string) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (s simulateProvider) GetByDistro(_ *distro.Distro, _ pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (s simulateProvider) GetByLanguage(_ syftPkg.Language, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return s.byName[p.Name], nil
+}
+
+func (s simulateProvider) GetByCPE(_ cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (s simulateProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	return &vulnerability.Metadata{ID: id, Namespace: namespace, Severity: "High"}, nil
+}
+
+func (s simulateProvider) GetRules(_ string) ([]match.IgnoreRule, error) {
+	return nil, nil
+}
+
+func Test_simulateUpgrades(t *testing.T) {
+	newVuln := func(id, constraint string) vulnerability.Vulnerability {
+		return vulnerability.Vulnerability{
+			ID:          id,
+			Namespace:   "github:language:javascript",
+			PackageName: "lodash",
+			Constraint:  version.MustGetConstraint(constraint, version.UnknownFormat),
+		}
+	}
+	p := simulateProvider{
+		byName: map[string][]vulnerability.Vulnerability{
+			"lodash": {
+				newVuln("GHSA-fixed", "< 4.17.21"),
+				newVuln("GHSA-remaining", "< 5.0.0"),
+				newVuln("GHSA-introduced", ">= 4.17.23, < 4.17.24"),
+			},
+		},
+	}
+	str := store.Store{Provider: p, MetadataProvider: p, ExclusionProvider: p}
+
+	results, err := simulateUpgrades(str, matcher.NewDefaultMatchers(matcher.Config{}), nil, []simulateRequest{
+		{PURL: "pkg:npm/lodash@4.17.20", UpgradeTo: "4.17.23"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	r := results[0]
+	assert.Equal(t, "pkg:npm/lodash", r.Package)
+	assert.Equal(t, "4.17.20", r.From)
+	assert.Equal(t, "4.17.23", r.To)
+	assert.Equal(t, []simulateFinding{{ID: "GHSA-fixed", Severity: "High"}}, r.Fixed)
+	assert.Equal(t, []simulateFinding{{ID: "GHSA-remaining", Severity: "High"}}, r.Remaining)
+	assert.Equal(t, []simulateFinding{{ID: "GHSA-introduced", Severity: "High"}}, r.Introduced)
+
+	_, err = simulateUpgrades(str, nil, nil, []simulateRequest{{PURL: "pkg:npm/lodash", UpgradeTo: "4.17.23"}})
+	require.Error(t, err, "the installed version is required")
+}
+
+func Test_simulateRequests(t *testing.T) {
+	_, err := simulateRequests(&simulateOptions{Packages: []string{"pkg:npm/lodash@1.0.0"}})
+	require.Error(t, err, "each package needs a version to upgrade to")
+
+	_, err = simulateRequests(&simulateOptions{})
+	require.Error(t, err, "there must be something to simulate")
+
+	requests, err := readSimulateRequests(strings.NewReader("# upgrades\npkg:npm/lodash@4.17.20 4.17.21\n\n  pkg:pypi/django@4.2.0   4.2.11  \n"))
+	require.NoError(t, err)
+	assert.Equal(t, []simulateRequest{
+		{PURL: "pkg:npm/lodash@4.17.20", UpgradeTo: "4.17.21"},
+		{PURL: "pkg:pypi/django@4.2.0", UpgradeTo: "4.2.11"},
+	}, requests)
+
+	_, err = readSimulateRequests(strings.NewReader("pkg:npm/lodash@4.17.20"))
+	require.Error(t, err)
+}
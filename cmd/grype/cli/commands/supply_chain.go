@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/supplychain"
+	"github.com/anchore/grype/internal/log"
+)
+
+// findSupplyChainWarnings returns the signs of supply-chain risk about the packages, warning of each. These do not
+// fail the scan, being only likely problems to review.
+func findSupplyChainWarnings(popularity *supplychain.Popularity, packages []pkg.Package) []supplychain.Warning {
+	warnings := popularity.Typosquats(packages)
+	for _, w := range warnings {
+		log.WithFields("package", w.Package.Name, "version", w.Package.Version, "kind", w.Kind).Warn(w.Message)
+	}
+	return warnings
+}
+
+// findAbandonedPackages returns the warnings about the deprecated, yanked or archived packages without any match,
+// warning of each.
+func findAbandonedPackages(statuses *supplychain.Statuses, packages []pkg.Package, matches match.Matches) []supplychain.Warning {
+	if statuses.Len() == 0 {
+		return nil
+	}
+	vulnerable := make(map[pkg.ID]bool)
+	for m := range matches.Enumerate() {
+		vulnerable[m.Package.ID] = true
+	}
+
+	warnings := statuses.Abandoned(packages, vulnerable)
+	for _, w := range warnings {
+		log.WithFields("package", w.Package.Name, "version", w.Package.Version, "kind", w.Kind).Warn(w.Message)
+	}
+	return warnings
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/supplychain"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestFindAbandonedPackages(t *testing.T) {
+	statuses, err := supplychain.ParseStatuses([]byte(`[
+		{"ecosystem": "npm", "name": "request", "status": "deprecated"},
+		{"ecosystem": "npm", "name": "moment", "status": "deprecated"}
+	]`))
+	require.NoError(t, err)
+
+	request := pkg.Package{ID: "request", Name: "request", Version: "2.88.2", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript}
+	moment := pkg.Package{ID: "moment", Name: "moment", Version: "2.29.1", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript}
+	matches := match.NewMatches(match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: "CVE-2022-24785", Namespace: "github:language:javascript"},
+		Package:       moment,
+	})
+
+	// moment is vulnerable, and so its status annotates its matches instead
+	warnings := findAbandonedPackages(statuses, []pkg.Package{request, moment}, matches)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "request", warnings[0].Package.Name)
+	assert.Equal(t, supplychain.Deprecated, warnings[0].Kind)
+
+	assert.Nil(t, findAbandonedPackages(nil, []pkg.Package{request}, matches))
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/bundle"
+	"github.com/anchore/grype/internal/bus"
+)
+
+func VerifyResults(app clio.Application) *cobra.Command {
+	return app.SetupCommand(&cobra.Command{
+		Use:   "verify-results ARCHIVE",
+		Short: "validate the integrity of a results archive (see --archive-results)",
+		Long: `validate the integrity of a results archive written with --archive-results: every file of the archive must
+match its SHA-256 checksum and the manifest of the archive, and the archive must hold no other file`,
+		Example: "  grype verify-results results.tgz",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: disableUI(app),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runVerifyResults(args[0])
+		},
+	})
+}
+
+func runVerifyResults(path string) error {
+	manifest, err := bundle.Verify(path)
+	if err != nil {
+		return err
+	}
+	bus.Report(describeResultsBundle(path, *manifest))
+	return nil
+}
+
+func describeResultsBundle(path string, manifest bundle.Manifest) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s is intact (%d files)\n", path, len(manifest.Files))
+	fmt.Fprintf(&sb, "  created: %s by %s %s\n", manifest.Created.UTC().Format("2006-01-02 15:04:05 UTC"), manifest.Tool.Name, manifest.Tool.Version)
+	if manifest.Target != "" {
+		fmt.Fprintf(&sb, "  target:  %s\n", manifest.Target)
+	}
+	if manifest.DB != nil {
+		fmt.Fprintf(&sb, "  db:      schema v%d, built %s", manifest.DB.SchemaVersion, manifest.DB.Built.UTC().Format("2006-01-02 15:04:05 UTC"))
+		if manifest.DB.Checksum != "" {
+			fmt.Fprintf(&sb, " (%s)", manifest.DB.Checksum)
+		}
+		sb.WriteString("\n")
+	}
+	for _, f := range manifest.Files {
+		fmt.Fprintf(&sb, "  - %s\n", f.Name)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/logrus"
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+const (
+	textLogFormat = "text"
+	jsonLogFormat = "json"
+
+	logFormatEnv = "GRYPE_LOG_FORMAT"
+)
+
+// logOptions are the logging options that clio does not provide: the format of the log entries, and a flag for the
+// log file (which clio only reads from the application config).
+type logOptions struct {
+	Format string
+	File   string
+}
+
+func (o *logOptions) addFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&o.Format, "log-format", "", fmt.Sprintf("format of the log entries (%s, %s); same as %s env var", textLogFormat, jsonLogFormat, logFormatEnv))
+	flags.StringVar(&o.File, "log-file", "", "file to write the log entries to (overrides the log.file config)")
+}
+
+func (o *logOptions) format() (string, error) {
+	format := o.Format
+	if format == "" {
+		format = os.Getenv(logFormatEnv)
+	}
+	switch f := strings.ToLower(format); f {
+	case "", textLogFormat:
+		return textLogFormat, nil
+	case jsonLogFormat:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported log format %q (expected %s or %s)", format, textLogFormat, jsonLogFormat)
+	}
+}
+
+// newLogger returns a constructor of the application logger honoring the logging options, which writes one JSON
+// object per entry (with the fields of the entry as keys) for the json format.
+func (o *logOptions) newLogger() clio.LoggerConstructor {
+	return func(cfg clio.Config, store redact.Store) (logger.Logger, error) {
+		format, err := o.format()
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Log != nil && o.File != "" {
+			logCfg := *cfg.Log
+			logCfg.FileLocation = o.File
+			cfg.Log = &logCfg
+		}
+		if format == textLogFormat || cfg.Log == nil {
+			return clio.DefaultLogger(cfg, store)
+		}
+
+		l, err := logrus.New(logrus.Config{
+			EnableConsole: cfg.Log.Verbosity > 0 && !cfg.Log.Quiet,
+			FileLocation:  cfg.Log.FileLocation,
+			Level:         cfg.Log.Level,
+			Formatter:     logrus.DefaultJSONFormatter(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if store != nil {
+			l = redact.New(l, store)
+		}
+		return l, nil
+	}
+}
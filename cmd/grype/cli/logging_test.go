@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/go-logger"
+)
+
+func Test_logOptions_format(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		env     string
+		want    string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{name: "default", want: textLogFormat},
+		{name: "text", format: "text", want: textLogFormat},
+		{name: "json", format: "JSON", want: jsonLogFormat},
+		{name: "from the environment", env: "json", want: jsonLogFormat},
+		{name: "flag over the environment", format: "text", env: "json", want: textLogFormat},
+		{name: "unsupported", format: "xml", wantErr: require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(logFormatEnv, tt.env)
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			o := &logOptions{Format: tt.format}
+			got, err := o.format()
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_logOptions_newLogger_json(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "scan.log")
+	o := &logOptions{Format: jsonLogFormat, File: file}
+
+	l, err := o.newLogger()(clio.Config{Log: &clio.LoggingConfig{Level: logger.InfoLevel}}, nil)
+	require.NoError(t, err)
+	l.WithFields("scan", "1234", "phase", "match").Info("scan phase started")
+	l.Debug("not logged")
+
+	contents, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	require.Len(t, lines, 1)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "scan phase started", entry["msg"])
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "1234", entry["scan"])
+	assert.Equal(t, "match", entry["phase"])
+	assert.NotEmpty(t, entry["time"])
+}
@@ -0,0 +1,60 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/alias"
+)
+
+// aliases configures searching for vulnerabilities filed under alternate package names.
+type aliases struct {
+	Enabled bool     `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	Fuzzy   bool     `yaml:"fuzzy" json:"fuzzy" mapstructure:"fuzzy"`
+	Files   []string `yaml:"files" json:"files" mapstructure:"files"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*aliases)(nil)
+
+func defaultAliases() aliases {
+	return aliases{
+		Enabled: false,
+		Fuzzy:   false,
+	}
+}
+
+func (cfg *aliases) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Enabled, `search for vulnerabilities filed under known alternate package names (e.g. pillow -> PIL)
+uses the dictionary built into grype, any dictionary shipped with the DB, and any user-provided files`)
+	descriptions.Add(&cfg.Fuzzy, `additionally search under normalized variants of package names (case, separators, common prefixes/suffixes)
+matches found this way are recorded with a lower confidence`)
+	descriptions.Add(&cfg.Files, `paths to JSON alias dictionaries to use in addition to the built-in dictionary, in the form:
+{"python": {"pillow": ["pil"]}, "*": {"nodejs": ["node"]}}`)
+}
+
+// ToResolver builds an alias resolver from the configured dictionaries, or nil when aliases are disabled.
+func (cfg aliases) ToResolver(dbDir string) (*alias.Resolver, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	d := alias.Default()
+
+	fromDB, err := alias.FromDBDir(dbDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read alias dictionary from DB: %w", err)
+	}
+	d.Merge(fromDB)
+
+	for _, f := range cfg.Files {
+		user, err := alias.FromFile(f)
+		if err != nil {
+			return nil, err
+		}
+		d.Merge(user)
+	}
+
+	return alias.NewResolver(d, cfg.Fuzzy), nil
+}
@@ -0,0 +1,56 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/baseimage"
+)
+
+// baseImageAdvice configures the advice for upgrading the base image of a scanned image.
+type baseImageAdvice struct {
+	Enabled bool     `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	Files   []string `yaml:"files" json:"files" mapstructure:"files"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*baseImageAdvice)(nil)
+
+func defaultBaseImageAdvice() baseImageAdvice {
+	return baseImageAdvice{
+		Enabled: false,
+	}
+}
+
+func (cfg *baseImageAdvice) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Enabled, `detect the base image of a scanned image (from its annotations, or from its layers against the dataset of known
+base images shipped with the DB) and report the newer tags of the base image with the findings each would remediate`)
+	descriptions.Add(&cfg.Files, `paths to datasets of known base images (JSON arrays of tags with their layers and packages) to use in addition to
+the dataset shipped with the DB`)
+}
+
+// ToDataset loads the known base images from the DB directory and any configured files, or nil when disabled.
+func (cfg baseImageAdvice) ToDataset(dbDir string) (*baseimage.Dataset, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	dataset := baseimage.NewDataset()
+
+	fromDB, err := baseimage.FromDBDir(dbDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read base image dataset from DB: %w", err)
+	}
+	dataset.Merge(fromDB)
+
+	for _, f := range cfg.Files {
+		user, err := baseimage.FromPath(f)
+		if err != nil {
+			return nil, err
+		}
+		dataset.Merge(user)
+	}
+
+	return dataset, nil
+}
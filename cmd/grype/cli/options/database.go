@@ -1,7 +1,8 @@
 package options
 
 import (
-	"path"
+	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/adrg/xdg"
@@ -16,6 +17,8 @@ type Database struct {
 	Dir                     string              `yaml:"cache-dir" json:"cache-dir" mapstructure:"cache-dir"`
 	UpdateURL               string              `yaml:"update-url" json:"update-url" mapstructure:"update-url"`
 	CACert                  string              `yaml:"ca-cert" json:"ca-cert" mapstructure:"ca-cert"`
+	PinnedCertificates      []certificatePin    `yaml:"pinned-certificates" json:"pinned-certificates" mapstructure:"pinned-certificates"`
+	Mirrors                 []databaseMirror    `yaml:"mirrors" json:"mirrors" mapstructure:"mirrors"`
 	AutoUpdate              bool                `yaml:"auto-update" json:"auto-update" mapstructure:"auto-update"`
 	ValidateByHashOnStart   bool                `yaml:"validate-by-hash-on-start" json:"validate-by-hash-on-start" mapstructure:"validate-by-hash-on-start"`
 	ValidateAge             bool                `yaml:"validate-age" json:"validate-age" mapstructure:"validate-age"`
@@ -24,12 +27,133 @@ type Database struct {
 	UpdateAvailableTimeout  time.Duration       `yaml:"update-available-timeout" json:"update-available-timeout" mapstructure:"update-available-timeout"`
 	UpdateDownloadTimeout   time.Duration       `yaml:"update-download-timeout" json:"update-download-timeout" mapstructure:"update-download-timeout"`
 	MaxUpdateCheckFrequency time.Duration       `yaml:"max-update-check-frequency" json:"max-update-check-frequency" mapstructure:"max-update-check-frequency"`
+	Retry                   databaseRetry       `yaml:"retry" json:"retry" mapstructure:"retry"`
+	Provenance              databaseProvenance  `yaml:"provenance" json:"provenance" mapstructure:"provenance"`
+	TUF                     databaseTUF         `yaml:"tuf" json:"tuf" mapstructure:"tuf"`
+	Rollback                string              `yaml:"rollback" json:"rollback" mapstructure:"rollback"`
+	AllowRollback           bool                `yaml:"allow-rollback" json:"allow-rollback" mapstructure:"allow-rollback"`
+	Ecosystems              []string            `yaml:"ecosystems" json:"ecosystems" mapstructure:"ecosystems"`
+	Shard                   bool                `yaml:"shard" json:"shard" mapstructure:"shard"`
+	WarmOnUpdate            bool                `yaml:"warm-on-update" json:"warm-on-update" mapstructure:"warm-on-update"`
+	MetricsFile             string              `yaml:"metrics-file" json:"metrics-file" mapstructure:"metrics-file"`
+}
+
+type databaseProvenance struct {
+	Require                bool     `yaml:"require" json:"require" mapstructure:"require"`
+	RequireOfficialBuilder bool     `yaml:"require-official-builder" json:"require-official-builder" mapstructure:"require-official-builder"`
+	AllowedBuilders        []string `yaml:"allowed-builders" json:"allowed-builders" mapstructure:"allowed-builders"`
+	TrustedKeys            []string `yaml:"trusted-keys" json:"trusted-keys" mapstructure:"trusted-keys"`
+}
+
+func (cfg databaseProvenance) toPolicy() distribution.ProvenancePolicy {
+	builders := cfg.AllowedBuilders
+	if cfg.RequireOfficialBuilder {
+		builders = append([]string{distribution.OfficialBuilderIdentity}, builders...)
+	}
+	return distribution.ProvenancePolicy{
+		Required:        cfg.Require,
+		AllowedBuilders: builders,
+		TrustedKeys:     cfg.TrustedKeys,
+	}
+}
+
+type databaseTUF struct {
+	Root        string `yaml:"root" json:"root" mapstructure:"root"`
+	RootDNS     string `yaml:"root-dns" json:"root-dns" mapstructure:"root-dns"`
+	MetadataURL string `yaml:"metadata-url" json:"metadata-url" mapstructure:"metadata-url"`
+}
+
+func (cfg databaseTUF) toConfig() distribution.TUFConfig {
+	return distribution.TUFConfig{
+		Root:        cfg.Root,
+		RootDNS:     cfg.RootDNS,
+		MetadataURL: cfg.MetadataURL,
+	}
+}
+
+type certificatePin struct {
+	Host       string   `yaml:"host" json:"host" mapstructure:"host"`
+	SPKISHA256 []string `yaml:"spki-sha256" json:"spki-sha256" mapstructure:"spki-sha256"`
+}
+
+func (cfg Database) certificatePins() []distribution.CertificatePin {
+	var pins []distribution.CertificatePin
+	for _, p := range cfg.PinnedCertificates {
+		pins = append(pins, distribution.CertificatePin{Host: p.Host, SPKISHA256: p.SPKISHA256})
+	}
+	return pins
+}
+
+type databaseMirror struct {
+	ListingURL string               `yaml:"listing-url" json:"listing-url" mapstructure:"listing-url"`
+	Username   secret               `yaml:"username" json:"username" mapstructure:"username"`
+	Password   secret               `yaml:"password" json:"password" mapstructure:"password"`
+	Token      secret               `yaml:"token" json:"token" mapstructure:"token"`
+	OAuth      *databaseMirrorOAuth `yaml:"oauth" json:"oauth" mapstructure:"oauth"`
+}
+
+type databaseMirrorOAuth struct {
+	Issuer           string   `yaml:"issuer" json:"issuer" mapstructure:"issuer"`
+	TokenURL         string   `yaml:"token-url" json:"token-url" mapstructure:"token-url"`
+	ClientID         string   `yaml:"client-id" json:"client-id" mapstructure:"client-id"`
+	ClientSecret     secret   `yaml:"client-secret" json:"client-secret" mapstructure:"client-secret"`
+	Scopes           []string `yaml:"scopes" json:"scopes" mapstructure:"scopes"`
+	Audience         string   `yaml:"audience" json:"audience" mapstructure:"audience"`
+	SubjectTokenFile string   `yaml:"subject-token-file" json:"subject-token-file" mapstructure:"subject-token-file"`
+	SubjectTokenType string   `yaml:"subject-token-type" json:"subject-token-type" mapstructure:"subject-token-type"`
+}
+
+func (cfg *databaseMirrorOAuth) toOAuth() *distribution.MirrorOAuth {
+	if cfg == nil {
+		return nil
+	}
+	return &distribution.MirrorOAuth{
+		Issuer:           cfg.Issuer,
+		TokenURL:         cfg.TokenURL,
+		ClientID:         cfg.ClientID,
+		ClientSecret:     cfg.ClientSecret.String(),
+		Scopes:           cfg.Scopes,
+		Audience:         cfg.Audience,
+		SubjectTokenFile: cfg.SubjectTokenFile,
+		SubjectTokenType: cfg.SubjectTokenType,
+	}
+}
+
+func (cfg Database) mirrors() []distribution.Mirror {
+	var mirrors []distribution.Mirror
+	for _, m := range cfg.Mirrors {
+		mirrors = append(mirrors, distribution.Mirror{
+			ListingURL: m.ListingURL,
+			Username:   m.Username.String(),
+			Password:   m.Password.String(),
+			Token:      m.Token.String(),
+			OAuth:      m.OAuth.toOAuth(),
+		})
+	}
+	return mirrors
+}
+
+type databaseRetry struct {
+	MaxAttempts      int           `yaml:"max-attempts" json:"max-attempts" mapstructure:"max-attempts"`
+	InitialBackoff   time.Duration `yaml:"initial-backoff" json:"initial-backoff" mapstructure:"initial-backoff"`
+	MaxBackoff       time.Duration `yaml:"max-backoff" json:"max-backoff" mapstructure:"max-backoff"`
+	Budget           int           `yaml:"budget" json:"budget" mapstructure:"budget"`
+	BreakerThreshold int           `yaml:"breaker-threshold" json:"breaker-threshold" mapstructure:"breaker-threshold"`
+	BreakerCooldown  time.Duration `yaml:"breaker-cooldown" json:"breaker-cooldown" mapstructure:"breaker-cooldown"`
 }
 
 var _ interface {
 	clio.FieldDescriber
+	clio.PostLoader
 } = (*Database)(nil)
 
+const (
+	// rollbackDeny refuses to activate a database built before the newest database activated so far, rollbackWarn
+	// activates it with a warning.
+	rollbackDeny = "deny"
+	rollbackWarn = "warn"
+)
+
 const (
 	defaultMaxDBAge                time.Duration = time.Hour * 24 * 5
 	defaultUpdateAvailableTimeout                = time.Second * 30
@@ -40,7 +164,7 @@ const (
 func DefaultDatabase(id clio.Identification) Database {
 	return Database{
 		ID:          id,
-		Dir:         path.Join(xdg.CacheHome, id.Name, "db"),
+		Dir:         filepath.Join(xdg.CacheHome, id.Name, "db"),
 		UpdateURL:   internal.DBUpdateURL,
 		AutoUpdate:  true,
 		ValidateAge: true,
@@ -50,6 +174,21 @@ func DefaultDatabase(id clio.Identification) Database {
 		UpdateAvailableTimeout:  defaultUpdateAvailableTimeout,
 		UpdateDownloadTimeout:   defaultUpdateDownloadTimeout,
 		MaxUpdateCheckFrequency: defaultMaxUpdateCheckFrequency,
+		Retry:                   defaultDatabaseRetry(),
+		Rollback:                rollbackDeny,
+		WarmOnUpdate:            false,
+	}
+}
+
+func defaultDatabaseRetry() databaseRetry {
+	r := distribution.DefaultRetryConfig()
+	return databaseRetry{
+		MaxAttempts:      r.MaxAttempts,
+		InitialBackoff:   r.InitialBackoff,
+		MaxBackoff:       r.MaxBackoff,
+		Budget:           r.Budget,
+		BreakerThreshold: r.BreakerThreshold,
+		BreakerCooldown:  r.BreakerCooldown,
 	}
 }
 
@@ -59,6 +198,8 @@ func (cfg Database) ToCuratorConfig() distribution.Config {
 		DBRootDir:               cfg.Dir,
 		ListingURL:              cfg.UpdateURL,
 		CACert:                  cfg.CACert,
+		CertificatePins:         cfg.certificatePins(),
+		Mirrors:                 cfg.mirrors(),
 		ValidateByHashOnGet:     cfg.ValidateByHashOnStart,
 		ValidateAge:             cfg.ValidateAge,
 		MaxAllowedBuiltAge:      cfg.MaxAllowedBuiltAge,
@@ -66,13 +207,78 @@ func (cfg Database) ToCuratorConfig() distribution.Config {
 		ListingFileTimeout:      cfg.UpdateAvailableTimeout,
 		UpdateTimeout:           cfg.UpdateDownloadTimeout,
 		UpdateCheckMaxFrequency: cfg.MaxUpdateCheckFrequency,
+		Retry: distribution.RetryConfig{
+			MaxAttempts:      cfg.Retry.MaxAttempts,
+			InitialBackoff:   cfg.Retry.InitialBackoff,
+			MaxBackoff:       cfg.Retry.MaxBackoff,
+			Budget:           cfg.Retry.Budget,
+			BreakerThreshold: cfg.Retry.BreakerThreshold,
+			BreakerCooldown:  cfg.Retry.BreakerCooldown,
+		},
+		Provenance: cfg.Provenance.toPolicy(),
+		TUF:        cfg.TUF.toConfig(),
+		Rollback: distribution.RollbackPolicy{
+			Warn:  cfg.Rollback == rollbackWarn,
+			Allow: cfg.AllowRollback,
+		},
+		Ecosystems:     cfg.Ecosystems,
+		Shard:          cfg.Shard,
+		WarmOnActivate: cfg.WarmOnUpdate,
+		MetricsFile:    cfg.MetricsFile,
+	}
+}
+
+func (cfg *Database) PostLoad() error {
+	ecosystems, err := distribution.NormalizeEcosystems(cfg.Ecosystems)
+	if err != nil {
+		return fmt.Errorf("invalid db.ecosystems: %w", err)
 	}
+	cfg.Ecosystems = ecosystems
+	if err := distribution.ValidateCertificatePins(cfg.certificatePins()); err != nil {
+		return fmt.Errorf("invalid db.pinned-certificates: %w", err)
+	}
+	if err := distribution.ValidateMirrors(cfg.mirrors()); err != nil {
+		return fmt.Errorf("invalid db.mirrors: %w", err)
+	}
+	switch cfg.Rollback {
+	case "":
+		cfg.Rollback = rollbackDeny
+	case rollbackDeny, rollbackWarn:
+	default:
+		return fmt.Errorf("invalid db.rollback: %q (options: %s, %s)", cfg.Rollback, rollbackDeny, rollbackWarn)
+	}
+	return nil
+}
+
+// AddRollbackFlags adds the flags of the commands that activate a database (db update, db import and scan).
+func (cfg *Database) AddRollbackFlags(flags clio.FlagSet) {
+	flags.BoolVarP(&cfg.AllowRollback,
+		"allow-db-rollback", "",
+		"allow activating a vulnerability database built before the newest database activated so far (a downgrade)",
+	)
 }
 
 func (cfg *Database) DescribeFields(descriptions clio.FieldDescriptionSet) {
 	descriptions.Add(&cfg.Dir, `location to write the vulnerability database cache`)
 	descriptions.Add(&cfg.UpdateURL, `URL of the vulnerability database`)
 	descriptions.Add(&cfg.CACert, `certificate to trust download the database and listing file`)
+	descriptions.Add(&cfg.PinnedCertificates, `pin the certificates of the hosts of the listing file and the database, in addition to the verification against the
+system trust store (or ca-cert): each host must present a chain including a certificate of one of its pinned public keys,
+as base64-encoded SHA-256 digests of the SubjectPublicKeyInfo, in the form:
+  - host: toolbox-data.anchore.io   # or *.anchore.io for the subdomains
+    spki-sha256: ["<digest>"]`)
+	descriptions.Add(&cfg.Mirrors, `mirrors of the listing file (and the databases it lists), tried in order after update-url when it fails, the mirrors
+that failed recently being tried last; the credentials (basic, bearer or OAuth) are only sent to the host of the listing URL,
+over https:
+  - listing-url: https://mirror.example.com/grype/databases/listing.json
+    token: ""       # or username and password
+  - listing-url: https://db-proxy.example.com/grype/databases/listing.json
+    oauth:          # OAuth 2.0 access tokens, cached and refreshed for the run
+      issuer: https://idp.example.com   # or token-url
+      client-id: ""                     # the client credentials grant, or subject-token-file to exchange a token (e.g. a CI OIDC token)
+      client-secret: ""
+      scopes: []
+      audience: ""`)
 	descriptions.Add(&cfg.AutoUpdate, `check for database updates on execution`)
 	descriptions.Add(&cfg.ValidateAge, `ensure db build is no older than the max-allowed-built-age`)
 	descriptions.Add(&cfg.ValidateByHashOnStart, `validate the database matches the known hash each execution`)
@@ -85,4 +291,33 @@ This file is ~156KiB as of 2024-04-17 so the download should be quick; adjust as
 	descriptions.Add(&cfg.UpdateDownloadTimeout, `Timeout for downloading actual vulnerability DB
 The DB is ~156MB as of 2024-04-17 so slower connections may exceed the default timeout; adjust as needed`)
 	descriptions.Add(&cfg.MaxUpdateCheckFrequency, `Maximum frequency to check for vulnerability database updates`)
+	descriptions.Add(&cfg.Retry.MaxAttempts, `maximum attempts for each listing or database download request that fails with a 5xx/429 response or a connection error (1 disables retries)`)
+	descriptions.Add(&cfg.Retry.InitialBackoff, `wait before the first retry, doubled (with jitter) on each further retry`)
+	descriptions.Add(&cfg.Retry.MaxBackoff, `maximum wait between attempts, including waits requested by the server via Retry-After`)
+	descriptions.Add(&cfg.Retry.Budget, `total number of retries allowed across all requests within a single run`)
+	descriptions.Add(&cfg.Retry.BreakerThreshold, `consecutive failures to a host before further requests to it fail immediately (0 disables the circuit breaker)`)
+	descriptions.Add(&cfg.Retry.BreakerCooldown, `how long requests to a failing host fail immediately before it is tried again`)
+	descriptions.Add(&cfg.Provenance.Require, `reject databases that do not include provenance metadata (builder identity, source feed snapshots, build time)`)
+	descriptions.Add(&cfg.Provenance.RequireOfficialBuilder, `only accept databases built by the official grype-db publishing workflow (implies require)`)
+	descriptions.Add(&cfg.Provenance.AllowedBuilders, `additional builder identities to trust, a trailing "*" matches any identity with that prefix (implies require)`)
+	descriptions.Add(&cfg.Provenance.TrustedKeys, `paths of the PEM-encoded public keys (ed25519 or ECDSA P-256) of the builders, one of which must have signed the provenance (implies require); without trusted keys the provenance is not signed and only checked for consistency`)
+	descriptions.Add(&cfg.TUF.Root, `verify the listing file with the TUF metadata (root, timestamp, snapshot and targets) published along with it,
+trusting this initial root metadata file (e.g. 1.root.json) obtained out of band; the root keys can then be rotated
+by publishing new root versions, and the trusted metadata is kept under the db directory to detect rollbacks`)
+	descriptions.Add(&cfg.TUF.RootDNS, `verify the listing file with TUF metadata, trusting the 1.root.json published along with it when its SHA-256 digest
+is pinned by a "tuf-root-sha256=<hex digest>" TXT record of this DNS name (instead of a root file); the record is not
+DNSSEC-validated, so this trusts the DNS resolution of the host (prefer a root file)`)
+	descriptions.Add(&cfg.TUF.MetadataURL, `base URL of the TUF metadata, the directory of the listing URL (of each mirror) when empty`)
+	descriptions.Add(&cfg.Rollback, `how to handle a database built before the newest database activated so far (e.g. an old listing served by a
+compromised mirror, or an old archive imported), the build time of the newest database being recorded under cache-dir:
+"deny" refuses to activate it, "warn" activates it with a warning`)
+	descriptions.Add(&cfg.AllowRollback, `allow activating a database built before the newest database activated so far, which then becomes the newest
+(a deliberate downgrade), same as --allow-db-rollback`)
+	descriptions.Add(&cfg.Ecosystems, `only install the records for these ecosystems to reduce the size of the database (e.g. "os", "cpe", "java", "npm")
+all ecosystems are installed when empty`)
+	descriptions.Add(&cfg.Shard, `split the database into one file per provider/ecosystem, only opening the files needed by each scan
+(uses additional disk space, reduces memory use and startup time when scanning few ecosystems)`)
+	descriptions.Add(&cfg.MetricsFile, `file to write the freshness of the database to after each update check, in the Prometheus text format of the
+node_exporter textfile collector (e.g. /var/lib/node_exporter/textfile_collector/grype.prom)`)
+	descriptions.Add(&cfg.WarmOnUpdate, `prepare a newly downloaded or imported database (same as "grype db warm") so the first scan is as fast as later ones`)
 }
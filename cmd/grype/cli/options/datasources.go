@@ -1,8 +1,12 @@
 package options
 
 import (
+	"path/filepath"
+	"time"
+
 	"github.com/anchore/clio"
 	"github.com/anchore/grype/grype/matcher/java"
+	"github.com/anchore/grype/grype/osv"
 )
 
 const (
@@ -10,8 +14,9 @@ const (
 )
 
 type externalSources struct {
-	Enable bool  `yaml:"enable" json:"enable" mapstructure:"enable"`
-	Maven  maven `yaml:"maven" json:"maven" mapstructure:"maven"`
+	Enable bool   `yaml:"enable" json:"enable" mapstructure:"enable"`
+	Maven  maven  `yaml:"maven" json:"maven" mapstructure:"maven"`
+	OSV    osvAPI `yaml:"osv" json:"osv" mapstructure:"osv"`
 }
 
 var _ interface {
@@ -23,12 +28,25 @@ type maven struct {
 	BaseURL              string `yaml:"base-url" json:"baseUrl" mapstructure:"base-url"`
 }
 
+type osvAPI struct {
+	Query      bool          `yaml:"query" json:"query" mapstructure:"query"`
+	BaseURL    string        `yaml:"base-url" json:"baseUrl" mapstructure:"base-url"`
+	Ecosystems []string      `yaml:"ecosystems" json:"ecosystems" mapstructure:"ecosystems"`
+	CacheTTL   time.Duration `yaml:"cache-ttl" json:"cacheTtl" mapstructure:"cache-ttl"`
+	Timeout    time.Duration `yaml:"timeout" json:"timeout" mapstructure:"timeout"`
+}
+
 func defaultExternalSources() externalSources {
 	return externalSources{
 		Maven: maven{
 			SearchUpstreamBySha1: true,
 			BaseURL:              defaultMavenBaseURL,
 		},
+		OSV: osvAPI{
+			BaseURL:  osv.DefaultAPIURL,
+			CacheTTL: 24 * time.Hour,
+			Timeout:  30 * time.Second,
+		},
 	}
 }
 
@@ -44,8 +62,34 @@ func (cfg externalSources) ToJavaMatcherConfig() java.ExternalSearchConfig {
 	}
 }
 
+// ToOSVProviderConfig returns the configuration of the OSV.dev API queries, with responses cached within the given DB
+// cache directory, or false when the API should not be queried.
+func (cfg externalSources) ToOSVProviderConfig(dbDir string) (osv.ProviderConfig, bool) {
+	if !cfg.Enable || !cfg.OSV.Query {
+		return osv.ProviderConfig{}, false
+	}
+	var cacheDir string
+	if dbDir != "" {
+		cacheDir = filepath.Join(dbDir, "osv")
+	}
+	return osv.ProviderConfig{
+		BaseURL:    cfg.OSV.BaseURL,
+		Ecosystems: cfg.OSV.Ecosystems,
+		CacheDir:   cacheDir,
+		CacheTTL:   cfg.OSV.CacheTTL,
+		Timeout:    cfg.OSV.Timeout,
+	}, true
+}
+
 func (cfg *externalSources) DescribeFields(descriptions clio.FieldDescriptionSet) {
 	descriptions.Add(&cfg.Enable, `enable Grype searching network source for additional information`)
 	descriptions.Add(&cfg.Maven.SearchUpstreamBySha1, `search for Maven artifacts by SHA1`)
 	descriptions.Add(&cfg.Maven.BaseURL, `base URL of the Maven repository to search`)
+	descriptions.Add(&cfg.OSV.Query, `query the OSV.dev API for vulnerabilities of language packages at scan time, in addition to the vulnerability DB
+(the names and versions of the packages are sent to the API)`)
+	descriptions.Add(&cfg.OSV.BaseURL, `base URL of the OSV.dev API`)
+	descriptions.Add(&cfg.OSV.Ecosystems, `the OSV ecosystems to query for (e.g. "PyPI", "npm"), all language ecosystems when empty`)
+	descriptions.Add(&cfg.OSV.CacheTTL, `how long the OSV.dev results of a package are reused before querying again (cached results are also used
+when the API cannot be reached)`)
+	descriptions.Add(&cfg.OSV.Timeout, `timeout of each request to the OSV.dev API`)
 }
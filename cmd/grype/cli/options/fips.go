@@ -0,0 +1,26 @@
+package options
+
+import (
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/internal/fips"
+	"github.com/anchore/grype/internal/log"
+)
+
+// FIPSMode turns FIPS mode on when set: the digests and signatures are only verified with FIPS-approved algorithms, and
+// the TLS connections only use FIPS-approved cipher suites.
+type FIPSMode bool
+
+var _ interface {
+	clio.PostLoader
+} = (*FIPSMode)(nil)
+
+// PostLoad needs to use a pointer receiver, even if it's not modifying the value
+func (f *FIPSMode) PostLoad() error {
+	if *f {
+		fips.SetEnabled(true)
+	}
+	if fips.Enabled() {
+		log.Debug("FIPS mode is on, only FIPS-approved algorithms are used")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package options
+
+import (
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+
+	"github.com/anchore/clio"
+)
+
+// ScanHistory configures the local database of past scans queried by "grype history".
+type ScanHistory struct {
+	Enabled bool   `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	Path    string `yaml:"path" json:"path" mapstructure:"path"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*ScanHistory)(nil)
+
+func DefaultScanHistory(id clio.Identification) ScanHistory {
+	return ScanHistory{
+		Enabled: false,
+		Path:    filepath.Join(xdg.DataHome, id.Name, "history.db"),
+	}
+}
+
+func (cfg *ScanHistory) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Enabled, `record every scan (the target and its digest, the DB build, and the findings) in the scan history database,
+which can be queried with "grype history"`)
+	descriptions.Add(&cfg.Path, `path of the scan history database (a SQLite file)`)
+}
@@ -0,0 +1,66 @@
+package options
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+	"github.com/dustin/go-humanize"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/pkg"
+)
+
+// LayerCache configures the cache of the packages cataloged from each layer of the scanned images.
+type LayerCache struct {
+	Enabled bool   `yaml:"enabled" json:"enabled" mapstructure:"enabled"` // --layer-cache, catalog only the layers of an image that are not cached
+	Dir     string `yaml:"dir" json:"dir" mapstructure:"dir"`
+	MaxSize string `yaml:"max-size" json:"max-size" mapstructure:"max-size"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*LayerCache)(nil)
+
+func DefaultLayerCache(id clio.Identification) LayerCache {
+	return LayerCache{
+		Enabled: false,
+		Dir:     filepath.Join(xdg.CacheHome, id.Name, "layers"),
+		MaxSize: "1GiB",
+	}
+}
+
+func (cfg *LayerCache) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Enabled, `cache the packages cataloged from each layer of the scanned images by the chain of the layer, so that scanning an
+image sharing layers with a previously scanned image (e.g. a new tag) only catalogs the layers not yet cached
+same as --layer-cache`)
+	descriptions.Add(&cfg.Dir, `directory of the layer cache (remove it to clear the cache)`)
+	descriptions.Add(&cfg.MaxSize, `the size the layer cache is kept within (e.g. '1GiB' or '500MB') by evicting the least recently used layers after
+each scan, empty for no limit`)
+}
+
+func (cfg LayerCache) validate() error {
+	_, err := cfg.maxSize()
+	return err
+}
+
+func (cfg LayerCache) maxSize() (uint64, error) {
+	if cfg.MaxSize == "" {
+		return 0, nil
+	}
+	size, err := humanize.ParseBytes(cfg.MaxSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maximum size %q: %w", cfg.MaxSize, err)
+	}
+	return size, nil
+}
+
+// ToLayerCache returns the layer cache to catalog images with, nil when disabled.
+func (cfg LayerCache) ToLayerCache() *pkg.LayerCache {
+	if !cfg.Enabled {
+		return nil
+	}
+	// the maximum size was validated when the configuration was loaded
+	maxSize, _ := cfg.maxSize()
+	return &pkg.LayerCache{Dir: cfg.Dir, MaxSize: maxSize}
+}
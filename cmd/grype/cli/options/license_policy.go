@@ -0,0 +1,54 @@
+package options
+
+import (
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/license"
+)
+
+// licensePolicy configures the evaluation of the package licenses against a policy of denied and allowed licenses.
+type licensePolicy struct {
+	Deny            []string           `yaml:"deny" json:"deny" mapstructure:"deny"`
+	Allow           []string           `yaml:"allow" json:"allow" mapstructure:"allow"`
+	Exceptions      []licenseException `yaml:"exceptions" json:"exceptions" mapstructure:"exceptions"`
+	FailOnViolation bool               `yaml:"fail-on-violation" json:"fail-on-violation" mapstructure:"fail-on-violation"`
+}
+
+type licenseException struct {
+	Package string `yaml:"package" json:"package" mapstructure:"package"`
+	Version string `yaml:"version" json:"version" mapstructure:"version"`
+	License string `yaml:"license" json:"license" mapstructure:"license"`
+	Reason  string `yaml:"reason" json:"reason" mapstructure:"reason"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*licensePolicy)(nil)
+
+func (cfg *licensePolicy) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Deny, `SPDX license IDs (or globs, e.g. 'GPL-*') that packages must not be licensed under`)
+	descriptions.Add(&cfg.Allow, `SPDX license IDs (or globs) that packages must be licensed under, empty to allow any license that is not denied`)
+	descriptions.Add(&cfg.Exceptions, `packages exempted from the policy, each with the package name (or glob), and optionally the version, the
+license exempted and the reason`)
+	descriptions.Add(&cfg.FailOnViolation, `fail the scan when a package license violates the policy`)
+}
+
+func (cfg licensePolicy) validate() error {
+	return cfg.ToPolicy().Validate()
+}
+
+// ToPolicy returns the license policy, which is empty (and so never violated) without a deny list or an allow list.
+func (cfg licensePolicy) ToPolicy() *license.Policy {
+	p := &license.Policy{
+		Deny:  cfg.Deny,
+		Allow: cfg.Allow,
+	}
+	for _, e := range cfg.Exceptions {
+		p.Exceptions = append(p.Exceptions, license.Exception{
+			Package: e.Package,
+			Version: e.Version,
+			License: e.License,
+			Reason:  e.Reason,
+		})
+	}
+	return p
+}
@@ -0,0 +1,26 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/license"
+)
+
+func Test_licensePolicy(t *testing.T) {
+	cfg := licensePolicy{
+		Deny:       []string{"GPL-*"},
+		Exceptions: []licenseException{{Package: "readline", Reason: "dynamically linked"}},
+	}
+	require.NoError(t, cfg.validate())
+	assert.Equal(t, &license.Policy{
+		Deny:       []string{"GPL-*"},
+		Exceptions: []license.Exception{{Package: "readline", Reason: "dynamically linked"}},
+	}, cfg.ToPolicy())
+
+	assert.True(t, licensePolicy{}.ToPolicy().IsEmpty())
+	assert.Error(t, licensePolicy{Allow: []string{"MIT["}}.validate())
+	assert.Error(t, licensePolicy{Exceptions: []licenseException{{License: "MIT"}}}.validate())
+}
@@ -0,0 +1,55 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/internal/resources"
+)
+
+// limits configures soft limits of the resources used by a scan, for scanning on machines shared with other jobs.
+type limits struct {
+	CPUs   int    `yaml:"cpus" json:"cpus" mapstructure:"cpus"`
+	Memory string `yaml:"memory" json:"memory" mapstructure:"memory"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*limits)(nil)
+
+func (cfg *limits) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.CPUs, `the maximum number of CPUs used at once (as GOMAXPROCS), 0 for all the CPUs of the machine`)
+	descriptions.Add(&cfg.Memory, `the budget of heap memory of the scan (e.g. '2GiB' or '512MB'), empty for no budget; near the budget the memory is
+reclaimed more eagerly, and the scan is aborted when the memory in use still exceeds the budget`)
+}
+
+func (cfg limits) validate() error {
+	if cfg.CPUs < 0 {
+		return fmt.Errorf("the number of CPUs cannot be negative")
+	}
+	_, err := cfg.memory()
+	return err
+}
+
+func (cfg limits) memory() (uint64, error) {
+	if cfg.Memory == "" {
+		return 0, nil
+	}
+	memory, err := humanize.ParseBytes(cfg.Memory)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory budget %q: %w", cfg.Memory, err)
+	}
+	return memory, nil
+}
+
+// ToLimits returns the limits to apply to the scan.
+func (cfg limits) ToLimits() resources.Limits {
+	// the memory budget was validated when the configuration was loaded
+	memory, _ := cfg.memory()
+	return resources.Limits{
+		CPUs:   cfg.CPUs,
+		Memory: memory,
+	}
+}
@@ -0,0 +1,57 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/internal/resources"
+)
+
+func Test_limits(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   limits
+		want    resources.Limits
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "no limits",
+			want: resources.Limits{},
+		},
+		{
+			name:  "binary units",
+			input: limits{CPUs: 2, Memory: "2GiB"},
+			want:  resources.Limits{CPUs: 2, Memory: 2 << 30},
+		},
+		{
+			name:  "decimal units",
+			input: limits{Memory: "512MB"},
+			want:  resources.Limits{Memory: 512_000_000},
+		},
+		{
+			name:    "negative CPUs",
+			input:   limits{CPUs: -1},
+			wantErr: require.Error,
+		},
+		{
+			name:    "invalid memory",
+			input:   limits{Memory: "lots"},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			err := tt.input.validate()
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, tt.input.ToLimits())
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/anchore/clio"
+	grypeLocalization "github.com/anchore/grype/grype/localization"
+	"github.com/anchore/grype/internal/log"
+)
+
+// localization configures the language of the vulnerability summaries of the reports.
+type localization struct {
+	Lang    string   `yaml:"lang" json:"lang" mapstructure:"lang"`
+	Bundles []string `yaml:"bundles" json:"bundles" mapstructure:"bundles"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*localization)(nil)
+
+func (cfg *localization) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Lang, `the language of the vulnerability summaries (e.g. 'ja' or 'pt-BR'), taken from the translation bundles shipped
+with the DB and any configured bundles; vulnerabilities without a translated summary keep their original description
+same as --lang`)
+	descriptions.Add(&cfg.Bundles, `paths to translation bundles to use in addition to any bundle shipped with the DB, each a JSON document in the form
+{"language": "ja", "summaries": {"CVE-2021-44228": "..."}}; only the bundles of the language are used`)
+}
+
+func (cfg localization) validate() error {
+	if cfg.Lang == "" {
+		return nil
+	}
+	return grypeLocalization.ValidateLanguage(cfg.Lang)
+}
+
+// ToBundle loads the translated summaries of the language from the DB directory and any configured bundles, or nil
+// when no language is selected.
+func (cfg localization) ToBundle(dbDir string) (*grypeLocalization.Bundle, error) {
+	if cfg.Lang == "" {
+		return nil, nil
+	}
+
+	bundle := grypeLocalization.NewBundle(cfg.Lang)
+
+	fromDB, err := grypeLocalization.FromDBDir(dbDir, cfg.Lang)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read translation bundle from DB: %w", err)
+	}
+	bundle.Merge(fromDB)
+
+	for _, path := range cfg.Bundles {
+		user, err := grypeLocalization.FromPath(path)
+		if err != nil {
+			return nil, err
+		}
+		if !user.Matches(cfg.Lang) {
+			log.WithFields("path", path, "language", user.Language).Debug("skipping translation bundle of another language")
+			continue
+		}
+		bundle.Merge(user)
+	}
+
+	if bundle.Len() == 0 {
+		log.WithFields("language", cfg.Lang).Warn("no translated vulnerability summaries available, using the original descriptions")
+	}
+	return bundle, nil
+}
@@ -0,0 +1,56 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/malware"
+)
+
+// malwareDetection configures the checking of packages against a dataset of known-malicious packages.
+type malwareDetection struct {
+	Enabled bool     `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	Files   []string `yaml:"files" json:"files" mapstructure:"files"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*malwareDetection)(nil)
+
+func defaultMalwareDetection() malwareDetection {
+	return malwareDetection{
+		Enabled: false,
+	}
+}
+
+func (cfg *malwareDetection) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Enabled, `report packages that are known to be malicious (by the digests of their artifacts, or else by their names and
+versions) as malware findings, separately from vulnerabilities`)
+	descriptions.Add(&cfg.Files, `paths to OSV records of malicious packages (MAL- records, e.g. a checkout of github.com/ossf/malicious-packages)
+to use in addition to any dataset shipped with the DB`)
+}
+
+// ToDataset loads the malicious package dataset from the DB directory and any configured files, or nil when disabled.
+func (cfg malwareDetection) ToDataset(dbDir string) (*malware.Dataset, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	dataset := malware.NewDataset()
+
+	fromDB, err := malware.FromDBDir(dbDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read malware dataset from DB: %w", err)
+	}
+	dataset.Merge(fromDB)
+
+	for _, f := range cfg.Files {
+		user, err := malware.FromPath(f)
+		if err != nil {
+			return nil, err
+		}
+		dataset.Merge(user)
+	}
+
+	return dataset, nil
+}
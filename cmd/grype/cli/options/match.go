@@ -1,9 +1,17 @@
 package options
 
-import "github.com/anchore/clio"
+import (
+	"errors"
+	"fmt"
+	"strings"
 
-// matchConfig contains all matching-related configuration options available to the user via the application config.
-type matchConfig struct {
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+)
+
+// MatchConfig contains all matching-related configuration options available to the user via the application config.
+type MatchConfig struct {
 	Java       matcherConfig `yaml:"java" json:"java" mapstructure:"java"`                   // settings for the java matcher
 	JVM        matcherConfig `yaml:"jvm" json:"jvm" mapstructure:"jvm"`                      // settings for the jvm matcher
 	Dotnet     matcherConfig `yaml:"dotnet" json:"dotnet" mapstructure:"dotnet"`             // settings for the dotnet matcher
@@ -13,14 +21,22 @@ type matchConfig struct {
 	Ruby       matcherConfig `yaml:"ruby" json:"ruby" mapstructure:"ruby"`                   // settings for the ruby matcher
 	Rust       matcherConfig `yaml:"rust" json:"rust" mapstructure:"rust"`                   // settings for the rust matcher
 	Stock      matcherConfig `yaml:"stock" json:"stock" mapstructure:"stock"`                // settings for the default/stock matcher
+	Alpm       matcherConfig `yaml:"alpm" json:"alpm" mapstructure:"alpm"`                   // settings for the arch linux (alpm) matcher
+
+	AdvisoryTargeting advisoryTargetingConfig `yaml:"advisory-targeting" json:"advisory-targeting" mapstructure:"advisory-targeting"` // which package names distro advisories are matched against
 }
 
 var _ interface {
 	clio.FieldDescriber
-} = (*matchConfig)(nil)
+	clio.PostLoader
+} = (*MatchConfig)(nil)
 
 type matcherConfig struct {
-	UseCPEs bool `yaml:"using-cpes" json:"using-cpes" mapstructure:"using-cpes"` // if CPEs should be used during matching
+	UseCPEs                    bool   `yaml:"using-cpes" json:"using-cpes" mapstructure:"using-cpes"`                                                       // if CPEs should be used during matching
+	AllowPrereleaseConstraints bool   `yaml:"allow-prerelease-constraints" json:"allow-prerelease-constraints" mapstructure:"allow-prerelease-constraints"` // if vulnerabilities bounded by pre-release versions should be reported
+	NameNormalization          string `yaml:"name-normalization" json:"name-normalization" mapstructure:"name-normalization"`                               // how package names are rewritten before searching
+	PrereleaseComparison       string `yaml:"prerelease-comparison" json:"prerelease-comparison" mapstructure:"prerelease-comparison"`                      // how pre-release versions compare against release-only constraints
+	IgnoreBuildMetadata        bool   `yaml:"ignore-build-metadata" json:"ignore-build-metadata" mapstructure:"ignore-build-metadata"`                      // if build metadata is removed from versions before comparing them
 }
 
 type golangConfig struct {
@@ -29,20 +45,41 @@ type golangConfig struct {
 	AllowMainModulePseudoVersionComparison bool `yaml:"allow-main-module-pseudo-version-comparison" json:"allow-main-module-pseudo-version-comparison" mapstructure:"allow-main-module-pseudo-version-comparison"` // if pseudo versions should be compared
 }
 
+type advisoryTargetingConfig struct {
+	Default string            `yaml:"default" json:"default" mapstructure:"default"` // the targeting for all distros
+	Distros map[string]string `yaml:"distros" json:"distros" mapstructure:"distros"` // the targeting by distro type
+}
+
+// MatcherSettings is the effective configuration for a single matcher.
+type MatcherSettings struct {
+	Section                    string            `json:"section"`
+	Matcher                    match.MatcherType `json:"matcher"`
+	UseCPEs                    bool              `json:"using-cpes"`
+	AllowPrereleaseConstraints bool              `json:"allow-prerelease-constraints"`
+	NameNormalization          string            `json:"name-normalization"`
+	PrereleaseComparison       string            `json:"prerelease-comparison"`
+	IgnoreBuildMetadata        bool              `json:"ignore-build-metadata"`
+}
+
+func newMatcherConfig(useCPEs bool) matcherConfig {
+	return matcherConfig{
+		UseCPEs:                    useCPEs,
+		AllowPrereleaseConstraints: true,
+	}
+}
+
 func defaultGolangConfig() golangConfig {
 	return golangConfig{
-		matcherConfig: matcherConfig{
-			UseCPEs: false,
-		},
+		matcherConfig:                          newMatcherConfig(false),
 		AlwaysUseCPEForStdlib:                  true,
 		AllowMainModulePseudoVersionComparison: false,
 	}
 }
 
-func defaultMatchConfig() matchConfig {
-	useCpe := matcherConfig{UseCPEs: true}
-	dontUseCpe := matcherConfig{UseCPEs: false}
-	return matchConfig{
+func DefaultMatchConfig() MatchConfig {
+	useCpe := newMatcherConfig(true)
+	dontUseCpe := newMatcherConfig(false)
+	return MatchConfig{
 		Java:       dontUseCpe,
 		JVM:        useCpe,
 		Dotnet:     dontUseCpe,
@@ -52,10 +89,11 @@ func defaultMatchConfig() matchConfig {
 		Ruby:       dontUseCpe,
 		Rust:       dontUseCpe,
 		Stock:      useCpe,
+		Alpm:       useCpe,
 	}
 }
 
-func (cfg *matchConfig) DescribeFields(descriptions clio.FieldDescriptionSet) {
+func (cfg *MatchConfig) DescribeFields(descriptions clio.FieldDescriptionSet) {
 	usingCpeDescription := `use CPE matching to find vulnerabilities`
 	descriptions.Add(&cfg.Java.UseCPEs, usingCpeDescription)
 	descriptions.Add(&cfg.Dotnet.UseCPEs, usingCpeDescription)
@@ -67,4 +105,114 @@ func (cfg *matchConfig) DescribeFields(descriptions clio.FieldDescriptionSet) {
 	descriptions.Add(&cfg.Ruby.UseCPEs, usingCpeDescription)
 	descriptions.Add(&cfg.Rust.UseCPEs, usingCpeDescription)
 	descriptions.Add(&cfg.Stock.UseCPEs, usingCpeDescription)
+	descriptions.Add(&cfg.Alpm.UseCPEs, usingCpeDescription+" for arch linux packages not covered by the Arch Security Tracker")
+
+	descriptions.Add(&cfg.AdvisoryTargeting.Default, `match distro advisories against the names of "source" packages, "binary" packages, or "both" (default is the setting of the vulnerability database for the distro, otherwise "both")`)
+	descriptions.Add(&cfg.AdvisoryTargeting.Distros, `the advisory targeting by distro type, taking precedence over the default (e.g. "debian: source")`)
+
+	for _, e := range cfg.sections() {
+		descriptions.Add(&e.config.AllowPrereleaseConstraints, `report vulnerabilities whose version constraints are bounded by pre-release versions (e.g. "< 2.0.0-rc.1")`)
+		descriptions.Add(&e.config.NameNormalization, `rewrite package names before searching: "" (as-is), "lowercase", or "pep503" (lowercase, runs of "-_." become "-")`)
+		descriptions.Add(&e.config.PrereleaseComparison, `how pre-release versions (e.g. "2.0.0-rc.1") compare against constraints bounded by release versions only:
+"" (before the release they precede), "as-release" (as the release they precede), "either" (report if either matches),
+or "exclude" (never report)`)
+		descriptions.Add(&e.config.IgnoreBuildMetadata, `remove build metadata (e.g. "+build.5" or the "+ubuntu1" local version of PEP 440) from versions before comparing them
+(only for version formats with build metadata: the "+dfsg" of a debian version is kept)`)
+	}
+}
+
+func (cfg *MatchConfig) PostLoad() error {
+	var errs error
+	for _, e := range cfg.sections() {
+		if _, err := matcher.ParseNameNormalization(e.config.NameNormalization); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("match.%s.name-normalization: %w", e.section, err))
+		}
+		if _, err := matcher.ParsePrereleaseComparison(e.config.PrereleaseComparison); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("match.%s.prerelease-comparison: %w", e.section, err))
+		}
+	}
+	if _, err := match.ParseTargeting(cfg.AdvisoryTargeting.Default); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("match.advisory-targeting.default: %w", err))
+	}
+	for distroType, targeting := range cfg.AdvisoryTargeting.Distros {
+		if _, err := match.ParseTargeting(targeting); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("match.advisory-targeting.distros.%s: %w", distroType, err))
+		}
+	}
+	return errs
+}
+
+// ToTargeting returns the advisory targeting configuration of the matchers.
+func (cfg MatchConfig) ToTargeting() matcher.TargetingConfig {
+	// values have already been validated on load
+	config := matcher.TargetingConfig{}
+	config.Default, _ = match.ParseTargeting(cfg.AdvisoryTargeting.Default)
+	for distroType, targeting := range cfg.AdvisoryTargeting.Distros {
+		if config.ByDistro == nil {
+			config.ByDistro = make(map[string]match.Targeting)
+		}
+		config.ByDistro[strings.ToLower(distroType)], _ = match.ParseTargeting(targeting)
+	}
+	return config
+}
+
+// Matchers returns the effective settings of every configurable matcher.
+func (cfg MatchConfig) Matchers() []MatcherSettings {
+	var settings []MatcherSettings
+	for _, e := range cfg.sections() {
+		settings = append(settings, MatcherSettings{
+			Section:                    e.section,
+			Matcher:                    e.matcher,
+			UseCPEs:                    e.config.UseCPEs,
+			AllowPrereleaseConstraints: e.config.AllowPrereleaseConstraints,
+			NameNormalization:          e.config.NameNormalization,
+			PrereleaseComparison:       e.config.PrereleaseComparison,
+			IgnoreBuildMetadata:        e.config.IgnoreBuildMetadata,
+		})
+	}
+	return settings
+}
+
+// ToPolicies returns the matcher policies that differ from the default behavior, keyed by matcher.
+func (cfg MatchConfig) ToPolicies() map[match.MatcherType]matcher.Policy {
+	policies := make(map[match.MatcherType]matcher.Policy)
+	for _, e := range cfg.sections() {
+		// values have already been validated on load
+		normalization, _ := matcher.ParseNameNormalization(e.config.NameNormalization)
+		prerelease, _ := matcher.ParsePrereleaseComparison(e.config.PrereleaseComparison)
+		policy := matcher.Policy{
+			AllowPrereleaseConstraints: e.config.AllowPrereleaseConstraints,
+			NameNormalization:          normalization,
+			PrereleaseComparison:       prerelease,
+			IgnoreBuildMetadata:        e.config.IgnoreBuildMetadata,
+		}
+		if e.matcher == "" || policy == matcher.DefaultPolicy() {
+			continue
+		}
+		policies[e.matcher] = policy
+	}
+	return policies
+}
+
+type matcherSection struct {
+	section string
+	matcher match.MatcherType
+	config  *matcherConfig
+}
+
+// sections ties each config section to the matcher it configures (the jvm section is not tied to a matcher, so it
+// gives no matcher policy).
+func (cfg *MatchConfig) sections() []matcherSection {
+	return []matcherSection{
+		{section: "java", matcher: match.JavaMatcher, config: &cfg.Java},
+		{section: "jvm", config: &cfg.JVM},
+		{section: "dotnet", matcher: match.DotnetMatcher, config: &cfg.Dotnet},
+		{section: "golang", matcher: match.GoModuleMatcher, config: &cfg.Golang.matcherConfig},
+		{section: "javascript", matcher: match.JavascriptMatcher, config: &cfg.Javascript},
+		{section: "python", matcher: match.PythonMatcher, config: &cfg.Python},
+		{section: "ruby", matcher: match.RubyGemMatcher, config: &cfg.Ruby},
+		{section: "rust", matcher: match.RustMatcher, config: &cfg.Rust},
+		{section: "stock", matcher: match.StockMatcher, config: &cfg.Stock},
+		{section: "alpm", matcher: match.AlpmMatcher, config: &cfg.Alpm},
+	}
 }
@@ -0,0 +1,110 @@
+package options
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+)
+
+func TestMatchConfig_PostLoad(t *testing.T) {
+	cfg := DefaultMatchConfig()
+	require.NoError(t, cfg.PostLoad())
+
+	cfg.Python.NameNormalization = "pep503"
+	require.NoError(t, cfg.PostLoad())
+
+	cfg.Ruby.NameNormalization = "bogus"
+	cfg.Rust.NameNormalization = "also-bogus"
+	err := cfg.PostLoad()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "match.ruby.name-normalization")
+	assert.Contains(t, err.Error(), "match.rust.name-normalization")
+
+	cfg = DefaultMatchConfig()
+	cfg.Java.PrereleaseComparison = "as-release"
+	require.NoError(t, cfg.PostLoad())
+
+	cfg.Python.PrereleaseComparison = "newest"
+	err = cfg.PostLoad()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "match.python.prerelease-comparison")
+}
+
+func TestMatchConfig_ToPolicies(t *testing.T) {
+	cfg := DefaultMatchConfig()
+	assert.Empty(t, cfg.ToPolicies(), "defaults should not alter matcher behavior")
+
+	cfg.Python.NameNormalization = "pep503"
+	cfg.Golang.AllowPrereleaseConstraints = false
+	cfg.Java.PrereleaseComparison = "either"
+	cfg.Java.IgnoreBuildMetadata = true
+
+	assert.Equal(t, map[match.MatcherType]matcher.Policy{
+		match.JavaMatcher: {
+			AllowPrereleaseConstraints: true,
+			PrereleaseComparison:       matcher.EitherPrereleaseComparison,
+			IgnoreBuildMetadata:        true,
+		},
+		match.PythonMatcher: {
+			AllowPrereleaseConstraints: true,
+			NameNormalization:          matcher.PEP503NameNormalization,
+		},
+		match.GoModuleMatcher: {
+			AllowPrereleaseConstraints: false,
+		},
+	}, cfg.ToPolicies())
+}
+
+func TestMatchConfig_Matchers(t *testing.T) {
+	cfg := DefaultMatchConfig()
+	cfg.Javascript.UseCPEs = true
+
+	var found bool
+	for _, s := range cfg.Matchers() {
+		if s.Section != "javascript" {
+			continue
+		}
+		found = true
+		assert.Equal(t, match.JavascriptMatcher, s.Matcher)
+		assert.True(t, s.UseCPEs)
+		assert.True(t, s.AllowPrereleaseConstraints)
+	}
+	assert.True(t, found)
+}
+
+func TestMatchConfig_sections(t *testing.T) {
+	cfg := DefaultMatchConfig()
+	sections := make(map[string]*matcherConfig)
+	for _, e := range cfg.sections() {
+		sections[e.section] = e.config
+	}
+
+	// every matcher config section of the match config has its settings validated, described and shown
+	configType := reflect.TypeOf(matcherConfig{})
+	v := reflect.ValueOf(&cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		value := v.Field(i)
+		if field.Type != configType {
+			embedded, ok := field.Type.FieldByName(configType.Name())
+			if !ok || !embedded.Anonymous {
+				continue
+			}
+			value = value.FieldByIndex(embedded.Index)
+		}
+
+		section, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		config, ok := sections[section]
+		if assert.True(t, ok, "match.%s has no section", section) {
+			assert.Equal(t, value.Addr().Pointer(), reflect.ValueOf(config).Pointer(), "match.%s is not the config of its section", section)
+		}
+		delete(sections, section)
+	}
+	assert.Empty(t, sections, "sections without a config field")
+}
@@ -0,0 +1,58 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/supplychain"
+)
+
+// packageStatus configures the annotation of packages that are deprecated, yanked or archived.
+type packageStatus struct {
+	Enabled       bool     `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	Files         []string `yaml:"files" json:"files" mapstructure:"files"`
+	FlagAbandoned bool     `yaml:"flag-abandoned" json:"flag-abandoned" mapstructure:"flag-abandoned"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*packageStatus)(nil)
+
+func defaultPackageStatus() packageStatus {
+	return packageStatus{
+		Enabled: false,
+	}
+}
+
+func (cfg *packageStatus) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Enabled, `annotate matches with the maintenance status of their package from the ecosystem metadata shipped with the DB
+(deprecated npm packages, yanked PyPI versions, archived source repositories)`)
+	descriptions.Add(&cfg.Files, `paths to package status records (JSON arrays of {"ecosystem", "name", "status", "versions", "reason"}) to use
+in addition to the records shipped with the DB`)
+	descriptions.Add(&cfg.FlagAbandoned, `also warn of the packages with a status that have no vulnerabilities`)
+}
+
+// ToStatuses loads the package status records from the DB directory and any configured files, or nil when disabled.
+func (cfg packageStatus) ToStatuses(dbDir string) (*supplychain.Statuses, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	statuses := supplychain.NewStatuses()
+
+	fromDB, err := supplychain.StatusesFromDBDir(dbDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read package statuses from DB: %w", err)
+	}
+	statuses.Merge(fromDB)
+
+	for _, f := range cfg.Files {
+		user, err := supplychain.StatusesFromPath(f)
+		if err != nil {
+			return nil, err
+		}
+		statuses.Merge(user)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,56 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/pending"
+)
+
+// pendingAnalysis configures the supplementary feed of CVEs that have not yet been analyzed by NVD.
+type pendingAnalysis struct {
+	Enabled bool     `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	Files   []string `yaml:"files" json:"files" mapstructure:"files"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*pendingAnalysis)(nil)
+
+func defaultPendingAnalysis() pendingAnalysis {
+	return pendingAnalysis{
+		Enabled: false,
+	}
+}
+
+func (cfg *pendingAnalysis) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Enabled, `match CVEs that are awaiting NVD analysis using the affected vendor/product/version data provided by the CNA
+or ADPs (e.g. CISA vulnrichment), only applies to matchers that search by CPE`)
+	descriptions.Add(&cfg.Files, `paths to CVE JSON 5 records (a single record, an array of records, or a directory of records) to use in
+addition to any feed shipped with the DB`)
+}
+
+// ToFeed loads the pending analysis feed from the DB directory and any configured files, or nil when disabled.
+func (cfg pendingAnalysis) ToFeed(dbDir string) (*pending.Feed, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	feed := pending.NewFeed()
+
+	fromDB, err := pending.FromDBDir(dbDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read pending analysis feed from DB: %w", err)
+	}
+	feed.Merge(fromDB)
+
+	for _, f := range cfg.Files {
+		user, err := pending.FromPath(f)
+		if err != nil {
+			return nil, err
+		}
+		feed.Merge(user)
+	}
+
+	return feed, nil
+}
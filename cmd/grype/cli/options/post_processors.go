@@ -0,0 +1,36 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/postprocess"
+)
+
+// postProcessor configures an external program (a post-processing plugin) receiving the match document of a scan
+// before it is presented, to remove, re-rate or annotate its matches.
+type postProcessor struct {
+	Name    string   `yaml:"name" json:"name" mapstructure:"name"`
+	Command string   `yaml:"command" json:"command" mapstructure:"command"`
+	Args    []string `yaml:"args" json:"args" mapstructure:"args"`
+}
+
+func (cfg postProcessor) validate() error {
+	if cfg.Name == "" {
+		return fmt.Errorf("a post-processor requires a name")
+	}
+	if cfg.Command == "" {
+		return fmt.Errorf("the post-processor %q requires a command", cfg.Name)
+	}
+	return nil
+}
+
+type postProcessors []postProcessor
+
+// ToProcessors returns the processors of the configured external programs, in order.
+func (cfgs postProcessors) ToProcessors() []postprocess.Processor {
+	var processors []postprocess.Processor
+	for _, cfg := range cfgs {
+		processors = append(processors, postprocess.NewExternalProcessor(cfg.Name, cfg.Command, cfg.Args...))
+	}
+	return processors
+}
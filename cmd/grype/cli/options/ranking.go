@@ -0,0 +1,92 @@
+package options
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/priority"
+	"github.com/anchore/grype/internal/log"
+)
+
+// ranking configures the prioritized shortlist of the table output: the top matches ranked by CVSS, EPSS or KEV.
+type ranking struct {
+	Top      int    `yaml:"top" json:"top" mapstructure:"top"`
+	RankBy   string `yaml:"rank-by" json:"rank-by" mapstructure:"rank-by"`
+	EPSSFile string `yaml:"epss-file" json:"epss-file" mapstructure:"epss-file"`
+	KEVFile  string `yaml:"kev-file" json:"kev-file" mapstructure:"kev-file"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*ranking)(nil)
+
+func defaultRanking() ranking {
+	return ranking{
+		RankBy: string(priority.ByCVSS),
+	}
+}
+
+func (cfg *ranking) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Top, `show only the top matches in the table output, ranked by rank-by, 0 for every match
+same as --top`)
+	descriptions.Add(&cfg.RankBy, `what the top matches are ranked by (options: cvss, epss, kev), the other criteria breaking the ties
+same as --rank-by`)
+	descriptions.Add(&cfg.EPSSFile, `the EPSS scores (the CSV file published by FIRST.org, optionally gzip-compressed) to rank the matches with, in
+place of any scores placed in the enrichment directory of the DB directory (required to rank by epss otherwise)`)
+	descriptions.Add(&cfg.KEVFile, `the catalog of Known Exploited Vulnerabilities (the JSON file published by CISA) to rank the matches with, in
+place of any catalog placed in the enrichment directory of the DB directory (required to rank by kev otherwise)`)
+}
+
+func (cfg ranking) validate() error {
+	if cfg.Top < 0 {
+		return fmt.Errorf("the number of top matches cannot be negative")
+	}
+	_, err := priority.ParseCriterion(cfg.RankBy)
+	return err
+}
+
+// Criterion returns what the matches are ranked by.
+func (cfg ranking) Criterion() priority.Criterion {
+	// the criterion was validated when the configuration was loaded
+	c, _ := priority.ParseCriterion(cfg.RankBy)
+	return c
+}
+
+// ToEnrichment loads the EPSS and KEV data to rank the matches with from the DB directory and any configured files,
+// or nil when the matches are not ranked. The DB distribution does not provide the data, so ranking by EPSS or KEV
+// fails without the data of the criterion, rather than silently ranking by the other criteria.
+func (cfg ranking) ToEnrichment(dbDir string) (*priority.Enrichment, error) {
+	if cfg.Top == 0 {
+		return nil, nil
+	}
+
+	enrichment, err := priority.FromDBDir(dbDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read enrichment data from DB: %w", err)
+	}
+	if cfg.EPSSFile != "" {
+		epss, err := priority.EPSSFromPath(cfg.EPSSFile)
+		if err != nil {
+			return nil, err
+		}
+		enrichment.Merge(epss)
+	}
+	if cfg.KEVFile != "" {
+		kev, err := priority.KEVFromPath(cfg.KEVFile)
+		if err != nil {
+			return nil, err
+		}
+		enrichment.Merge(kev)
+	}
+
+	switch c := cfg.Criterion(); {
+	case c == priority.ByEPSS && !enrichment.HasEPSS():
+		return nil, fmt.Errorf("unable to rank the matches by epss without EPSS scores: set ranking.epss-file to the scores published by FIRST.org, or place them in %s", filepath.Join(dbDir, priority.DBDirName, priority.EPSSFileName))
+	case c == priority.ByKEV && !enrichment.HasKEV():
+		return nil, fmt.Errorf("unable to rank the matches by kev without the KEV catalog: set ranking.kev-file to the catalog published by CISA, or place it in %s", filepath.Join(dbDir, priority.DBDirName, priority.KEVFileName))
+	case !enrichment.HasEPSS() || !enrichment.HasKEV():
+		log.Debug("no EPSS scores or KEV catalog available (see ranking.epss-file and ranking.kev-file), the EPSS and KEV columns may be empty")
+	}
+	return enrichment, nil
+}
@@ -0,0 +1,69 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ranking_ToEnrichment(t *testing.T) {
+	const (
+		shippedDB = "../../../../grype/priority/test-fixtures/db"
+		epssFile  = "../../../../grype/priority/test-fixtures/epss.csv"
+	)
+	tests := []struct {
+		name    string
+		input   ranking
+		dbDir   string
+		wantNil bool
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:    "not ranked",
+			input:   ranking{RankBy: "epss"},
+			dbDir:   t.TempDir(),
+			wantNil: true,
+		},
+		{
+			name:  "by cvss without data",
+			input: ranking{Top: 5, RankBy: "cvss"},
+			dbDir: t.TempDir(),
+		},
+		{
+			name:    "by epss without scores",
+			input:   ranking{Top: 5, RankBy: "epss"},
+			dbDir:   t.TempDir(),
+			wantErr: require.Error,
+		},
+		{
+			name:    "by kev without catalog",
+			input:   ranking{Top: 5, RankBy: "kev", EPSSFile: epssFile},
+			dbDir:   t.TempDir(),
+			wantErr: require.Error,
+		},
+		{
+			name:  "by epss with configured scores",
+			input: ranking{Top: 5, RankBy: "epss", EPSSFile: epssFile},
+			dbDir: t.TempDir(),
+		},
+		{
+			name:  "by kev with data placed in the DB directory",
+			input: ranking{Top: 5, RankBy: "kev"},
+			dbDir: shippedDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := tt.input.ToEnrichment(tt.dbDir)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantNil, got == nil)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// sbomDecoder configures an external program (a decoder plugin) converting an SBOM format that syft does not support
+// into Syft JSON.
+type sbomDecoder struct {
+	Name    string   `yaml:"name" json:"name" mapstructure:"name"`
+	Command string   `yaml:"command" json:"command" mapstructure:"command"`
+	Args    []string `yaml:"args" json:"args" mapstructure:"args"`
+}
+
+func (cfg sbomDecoder) validate() error {
+	if cfg.Name == "" {
+		return fmt.Errorf("an SBOM decoder requires a name")
+	}
+	if cfg.Command == "" {
+		return fmt.Errorf("the SBOM decoder %q requires a command", cfg.Name)
+	}
+	return nil
+}
+
+type sbomDecoders []sbomDecoder
+
+// ToDecoders returns the decoders of the configured external programs, in order.
+func (cfgs sbomDecoders) ToDecoders() []sbom.FormatDecoder {
+	var decoders []sbom.FormatDecoder
+	for _, cfg := range cfgs {
+		decoders = append(decoders, pkg.NewExternalSBOMDecoder(cfg.Name, cfg.Command, cfg.Args...))
+	}
+	return decoders
+}
@@ -0,0 +1,60 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/supplychain"
+	"github.com/anchore/grype/internal/log"
+)
+
+// typosquatting configures the warnings about language packages named like popular packages.
+type typosquatting struct {
+	Enabled bool     `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	Files   []string `yaml:"files" json:"files" mapstructure:"files"`
+}
+
+var _ interface {
+	clio.FieldDescriber
+} = (*typosquatting)(nil)
+
+func defaultTyposquatting() typosquatting {
+	return typosquatting{
+		Enabled: false,
+	}
+}
+
+func (cfg *typosquatting) DescribeFields(descriptions clio.FieldDescriptionSet) {
+	descriptions.Add(&cfg.Enabled, `warn of language packages whose names are small edit-distance variants of popular packages of the same
+ecosystem, using the popularity list shipped with the DB`)
+	descriptions.Add(&cfg.Files, `paths to popularity lists (JSON objects of the most popular names of each OSV ecosystem, e.g.
+{"npm": ["react", "lodash"]}) to use in addition to the list shipped with the DB`)
+}
+
+// ToPopularity loads the popularity list from the DB directory and any configured files, or nil when disabled.
+func (cfg typosquatting) ToPopularity(dbDir string) (*supplychain.Popularity, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	popularity := supplychain.NewPopularity()
+
+	fromDB, err := supplychain.PopularityFromDBDir(dbDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read popularity list from DB: %w", err)
+	}
+	popularity.Merge(fromDB)
+
+	for _, f := range cfg.Files {
+		user, err := supplychain.PopularityFromPath(f)
+		if err != nil {
+			return nil, err
+		}
+		popularity.Merge(user)
+	}
+
+	if popularity.Len() == 0 {
+		log.Warn("typosquatting detection is enabled, but neither the DB nor the configured files provide a popularity list")
+	}
+	return popularity, nil
+}
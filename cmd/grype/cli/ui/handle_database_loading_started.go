@@ -0,0 +1,30 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wagoodman/go-partybus"
+
+	"github.com/anchore/bubbly/bubbles/taskprogress"
+	"github.com/anchore/grype/grype/event/parsers"
+	"github.com/anchore/grype/internal/log"
+)
+
+func (m *Handler) handleDatabaseLoadingStarted(e partybus.Event) ([]tea.Model, tea.Cmd) {
+	prog, err := parsers.ParseDatabaseLoadingStarted(e)
+	if err != nil {
+		log.WithFields("error", err).Warn("unable to parse event")
+		return nil, nil
+	}
+
+	tsk := m.newTaskProgress(
+		taskprogress.Title{
+			Default: "Load Vulnerability DB",
+			Running: "Loading Vulnerability DB",
+			Success: "Loaded Vulnerability DB",
+		},
+		taskprogress.WithStagedProgressable(prog), // ignore the static stage provided by the event
+		taskprogress.WithStager(bytesProgressStager{prog: prog, stage: "validating"}),
+	)
+
+	return []tea.Model{tsk}, nil
+}
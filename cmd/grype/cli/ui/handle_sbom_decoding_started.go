@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+	"github.com/wagoodman/go-partybus"
+	"github.com/wagoodman/go-progress"
+
+	"github.com/anchore/bubbly/bubbles/taskprogress"
+	"github.com/anchore/grype/grype/event/parsers"
+	"github.com/anchore/grype/internal/log"
+)
+
+// bytesProgressStager shows the bytes processed so far while in the given stage (e.g. the bytes of an SBOM read while
+// decoding it), and the stage of the progress otherwise.
+type bytesProgressStager struct {
+	prog  progress.StagedProgressable
+	stage string
+}
+
+func (s bytesProgressStager) Stage() string {
+	stage := s.prog.Stage()
+	if stage == s.stage && s.prog.Size() > 0 {
+		return fmt.Sprintf("%s / %s",
+			humanize.Bytes(safeConvertInt64ToUint64(s.prog.Current())),
+			humanize.Bytes(safeConvertInt64ToUint64(s.prog.Size())),
+		)
+	}
+	return stage
+}
+
+func (m *Handler) handleSBOMDecodingStarted(e partybus.Event) ([]tea.Model, tea.Cmd) {
+	_, prog, err := parsers.ParseSBOMDecodingStarted(e)
+	if err != nil {
+		log.WithFields("error", err).Warn("unable to parse event")
+		return nil, nil
+	}
+
+	tsk := m.newTaskProgress(
+		taskprogress.Title{
+			Default: "Decode SBOM",
+			Running: "Decoding SBOM",
+			Success: "Decoded SBOM",
+		},
+		taskprogress.WithStagedProgressable(prog), // ignore the static stage provided by the event
+		taskprogress.WithStager(bytesProgressStager{prog: prog, stage: "decoding"}),
+	)
+
+	tsk.HideStageOnSuccess = false
+
+	return []tea.Model{tsk}, nil
+}
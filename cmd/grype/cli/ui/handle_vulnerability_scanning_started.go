@@ -14,6 +14,7 @@ import (
 	"github.com/anchore/bubbly/bubbles/taskprogress"
 	"github.com/anchore/grype/grype/event/monitor"
 	"github.com/anchore/grype/grype/event/parsers"
+	"github.com/anchore/grype/grype/presenter/theme"
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/grype/internal/log"
 )
@@ -42,9 +43,11 @@ type vulnerabilityProgressTree struct {
 
 	updateDuration time.Duration
 	textStyle      lipgloss.Style
+	// theme shows the severities, which are plain when nil
+	theme *theme.Theme
 }
 
-func newVulnerabilityProgressTree(monitor *monitor.Matching, textStyle lipgloss.Style) vulnerabilityProgressTree {
+func newVulnerabilityProgressTree(monitor *monitor.Matching, textStyle lipgloss.Style, t *theme.Theme) vulnerabilityProgressTree {
 	allSeverities := vulnerability.AllSeverities()
 	sort.Sort(sort.Reverse(vulnerability.Severities(allSeverities)))
 
@@ -53,6 +56,7 @@ func newVulnerabilityProgressTree(monitor *monitor.Matching, textStyle lipgloss.
 		countBySeverity: make(map[vulnerability.Severity]int64),
 		severities:      allSeverities,
 		textStyle:       textStyle,
+		theme:           t,
 	}
 }
 
@@ -105,7 +109,7 @@ func (m *Handler) handleVulnerabilityScanningStarted(e partybus.Event) ([]tea.Mo
 
 	return []tea.Model{
 		tsk,
-		newVulnerabilityProgressTree(mon, textStyle),
+		newVulnerabilityProgressTree(mon, textStyle, m.theme()),
 	}, nil
 }
 
@@ -155,24 +159,7 @@ func (l vulnerabilityProgressTree) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (l vulnerabilityProgressTree) View() string {
 	sb := strings.Builder{}
 
-	for idx, sev := range l.severities {
-		count := l.countBySeverity[sev]
-		sb.WriteString(fmt.Sprintf("%d %s", count, sev))
-		if idx < len(l.severities)-1 {
-			sb.WriteString(", ")
-		}
-	}
-	if l.unknownCount > 0 {
-		unknownStr := fmt.Sprintf(" (%d unknown)", l.unknownCount)
-		sb.WriteString(unknownStr)
-	}
-
-	status := sb.String()
-	sb.Reset()
-
-	sevStr := l.textStyle.Render(fmt.Sprintf("   %s by severity: %s", branch, status))
-
-	sb.WriteString(sevStr)
+	sb.WriteString(l.severityView())
 
 	dropped := ""
 	if l.droppedCount > 0 {
@@ -189,6 +176,44 @@ func (l vulnerabilityProgressTree) View() string {
 	return sb.String()
 }
 
+// severityView renders the line of the counts by severity, with the severities shown by the theme, if any.
+func (l vulnerabilityProgressTree) severityView() string {
+	if l.theme == nil {
+		sb := strings.Builder{}
+		for idx, sev := range l.severities {
+			count := l.countBySeverity[sev]
+			sb.WriteString(fmt.Sprintf("%d %s", count, sev))
+			if idx < len(l.severities)-1 {
+				sb.WriteString(", ")
+			}
+		}
+		if l.unknownCount > 0 {
+			unknownStr := fmt.Sprintf(" (%d unknown)", l.unknownCount)
+			sb.WriteString(unknownStr)
+		}
+		return l.textStyle.Render(fmt.Sprintf("   %s by severity: %s", branch, sb.String()))
+	}
+
+	// the styled counts are rendered apart from the rest of the line, which has the style of the other lines
+	sb := strings.Builder{}
+	sb.WriteString(l.textStyle.Render(fmt.Sprintf("   %s by severity: ", branch)))
+	for idx, sev := range l.severities {
+		label := fmt.Sprintf("%d %s", l.countBySeverity[sev], l.theme.Label(sev.String()))
+		if style := l.theme.Style(sev.String()); !style.IsEmpty() {
+			sb.WriteString(style.Lipgloss().Render(label))
+		} else {
+			sb.WriteString(l.textStyle.Render(label))
+		}
+		if idx < len(l.severities)-1 {
+			sb.WriteString(l.textStyle.Render(", "))
+		}
+	}
+	if l.unknownCount > 0 {
+		sb.WriteString(l.textStyle.Render(fmt.Sprintf(" (%d unknown)", l.unknownCount)))
+	}
+	return sb.String()
+}
+
 func (l vulnerabilityProgressTree) queueNextTick() tea.Cmd {
 	return tea.Tick(l.updateDuration, func(t time.Time) tea.Msg {
 		return vulnerabilityProgressTreeTickMsg{
@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/wagoodman/go-partybus"
 	"github.com/wagoodman/go-progress"
@@ -14,6 +15,7 @@ import (
 	"github.com/anchore/bubbly/bubbles/taskprogress"
 	"github.com/anchore/grype/grype/event"
 	"github.com/anchore/grype/grype/event/monitor"
+	"github.com/anchore/grype/grype/presenter/theme"
 	"github.com/anchore/grype/grype/vulnerability"
 )
 
@@ -83,6 +85,37 @@ func TestHandler_handleVulnerabilityScanningStarted(t *testing.T) {
 	}
 }
 
+func TestHandler_handleVulnerabilityScanningStarted_theme(t *testing.T) {
+	th, err := theme.New(theme.EmojiName, nil)
+	require.NoError(t, err)
+
+	cfg := DefaultHandlerConfig()
+	cfg.Theme = &th
+	handler := New(cfg)
+	handler.WindowSize = tea.WindowSizeMsg{
+		Width:  100,
+		Height: 80,
+	}
+
+	models, _ := handler.Handle(partybus.Event{
+		Type:  event.VulnerabilityScanningStarted,
+		Value: getVulnerabilityMonitor(true),
+	})
+	require.Len(t, models, 2)
+
+	tree, ok := models[1].(vulnerabilityProgressTree)
+	require.True(t, ok)
+	got := runModel(t, tree, 0, vulnerabilityProgressTreeTickMsg{
+		Time:     time.Now(),
+		Sequence: tree.sequence,
+		ID:       tree.id,
+	})
+	assert.Contains(t, got, "by severity: ")
+	assert.Contains(t, got, "1 🔴 critical, 2 🟠 high")
+	assert.Contains(t, got, "5 ⚪ negligible (6 unknown)")
+	assert.Contains(t, got, "by status:")
+}
+
 func getVulnerabilityMonitor(completed bool) monitor.Matching {
 	pkgs := &progress.Manual{}
 	pkgs.SetTotal(-1)
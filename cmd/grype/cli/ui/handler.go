@@ -2,6 +2,7 @@ package ui
 
 import (
 	"sync"
+	"sync/atomic"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/wagoodman/go-partybus"
@@ -9,6 +10,7 @@ import (
 	"github.com/anchore/bubbly"
 	"github.com/anchore/bubbly/bubbles/taskprogress"
 	"github.com/anchore/grype/grype/event"
+	"github.com/anchore/grype/grype/presenter/theme"
 )
 
 var _ interface {
@@ -20,6 +22,17 @@ var _ interface {
 type HandlerConfig struct {
 	TitleWidth        int
 	AdjustDefaultTask func(taskprogress.Model) taskprogress.Model
+	// Theme shows the severities of the matches found (the theme set with SetTheme when nil).
+	Theme *theme.Theme
+}
+
+// the theme set once the application config is loaded, the UI being created before
+var selectedTheme atomic.Pointer[theme.Theme]
+
+// SetTheme sets the theme showing the severities of the matches found, for the handlers without a theme in their
+// config.
+func SetTheme(t theme.Theme) {
+	selectedTheme.Store(&t)
 }
 
 type Handler struct {
@@ -50,11 +63,20 @@ func New(cfg HandlerConfig) *Handler {
 		event.UpdateVulnerabilityDatabase:  h.handleUpdateVulnerabilityDatabase,
 		event.VulnerabilityScanningStarted: h.handleVulnerabilityScanningStarted,
 		event.DatabaseDiffingStarted:       h.handleDatabaseDiffStarted,
+		event.SBOMDecodingStarted:          h.handleSBOMDecodingStarted,
+		event.DatabaseLoadingStarted:       h.handleDatabaseLoadingStarted,
 	})
 
 	return h
 }
 
+func (m *Handler) theme() *theme.Theme {
+	if m.Config.Theme != nil {
+		return m.Config.Theme
+	}
+	return selectedTheme.Load()
+}
+
 func (m *Handler) OnMessage(msg tea.Msg) {
 	if msg, ok := msg.(tea.WindowSizeMsg); ok {
 		m.WindowSize = msg
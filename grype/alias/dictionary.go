@@ -0,0 +1,124 @@
+package alias
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/internal/dataset"
+	"github.com/anchore/grype/internal/stringutil"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// DBFileName is the name of the optional alias dictionary that may be shipped alongside the vulnerability DB.
+const DBFileName = "package-aliases.json"
+
+// anyType is the dictionary key for aliases that apply regardless of package type.
+const anyType = "*"
+
+//go:embed aliases.json
+var defaultAliases []byte
+
+// Dictionary maps package names (per package type) to alternate names that advisories may be filed under. Aliases
+// are symmetric: if "pillow" is an alias of "pil" then "pil" is also an alias of "pillow".
+type Dictionary struct {
+	entries map[string]map[string][]string
+}
+
+// rawDictionary is the on-disk form of a dictionary: package type -> package name -> aliases.
+type rawDictionary map[string]map[string][]string
+
+func NewDictionary() *Dictionary {
+	return &Dictionary{
+		entries: make(map[string]map[string][]string),
+	}
+}
+
+// Default returns the dictionary of well-known renames that is built into grype.
+func Default() *Dictionary {
+	d, err := Parse(defaultAliases)
+	if err != nil {
+		// this is a programming error: the embedded dictionary must always be valid
+		panic(fmt.Errorf("invalid built-in alias dictionary: %w", err))
+	}
+	return d
+}
+
+// Parse reads a JSON alias dictionary.
+func Parse(data []byte) (*Dictionary, error) {
+	var raw rawDictionary
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unable to parse alias dictionary: %w", err)
+	}
+
+	d := NewDictionary()
+	for ty, names := range raw {
+		for name, aliases := range names {
+			d.Add(ty, name, aliases...)
+		}
+	}
+	return d, nil
+}
+
+// sidecar is the alias dictionary shipped within the DB directory.
+var sidecar = dataset.Sidecar[*Dictionary]{
+	Kind:  "alias dictionary",
+	Path:  DBFileName,
+	Parse: Parse,
+}
+
+// FromFile reads a JSON alias dictionary from the given path.
+func FromFile(path string) (*Dictionary, error) {
+	return sidecar.FromFile(path)
+}
+
+// FromDBDir reads the alias dictionary shipped within the given DB directory, if there is one. A nil dictionary
+// (and no error) is returned when the DB does not ship aliases.
+func FromDBDir(dir string) (*Dictionary, error) {
+	return sidecar.FromDBDir(dir)
+}
+
+// Add registers aliases for the given package name. Use "*" as the package type for aliases that apply to all types.
+func (d *Dictionary) Add(ty, name string, aliases ...string) {
+	ty = strings.ToLower(ty)
+	name = strings.ToLower(name)
+	if _, ok := d.entries[ty]; !ok {
+		d.entries[ty] = make(map[string][]string)
+	}
+	for _, a := range aliases {
+		a = strings.ToLower(a)
+		if a == "" || a == name {
+			continue
+		}
+		d.entries[ty][name] = stringutil.AppendUnique(d.entries[ty][name], a)
+		d.entries[ty][a] = stringutil.AppendUnique(d.entries[ty][a], name)
+	}
+}
+
+// Merge adds all entries from the other dictionary into this one.
+func (d *Dictionary) Merge(other *Dictionary) {
+	if other == nil {
+		return
+	}
+	for ty, names := range other.entries {
+		for name, aliases := range names {
+			d.Add(ty, name, aliases...)
+		}
+	}
+}
+
+// Lookup returns all known aliases for the given package name and type (sorted, lowercase).
+func (d *Dictionary) Lookup(ty syftPkg.Type, name string) []string {
+	name = strings.ToLower(name)
+	var out []string
+	for _, a := range d.entries[strings.ToLower(string(ty))][name] {
+		out = stringutil.AppendUnique(out, a)
+	}
+	for _, a := range d.entries[anyType][name] {
+		out = stringutil.AppendUnique(out, a)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,118 @@
+package alias
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/anchore/grype/grype/pkg"
+)
+
+const (
+	// DictionaryConfidence is the confidence recorded for matches found via an alias dictionary entry.
+	DictionaryConfidence = 0.9
+	// FuzzyConfidence is the confidence recorded for matches found via fuzzy name normalization.
+	FuzzyConfidence = 0.6
+)
+
+// Source describes how an alternate package name was derived.
+type Source string
+
+const (
+	DictionarySource Source = "dictionary"
+	FuzzySource      Source = "fuzzy"
+)
+
+// Candidate is an alternate name to search for a package under.
+type Candidate struct {
+	Name       string
+	Source     Source
+	Confidence float64
+}
+
+var (
+	separatorPattern = regexp.MustCompile(`[-_.]+`)
+	fuzzyPrefixes    = []string{"python3-", "python-", "py-", "node-", "golang-", "ruby-", "rust-"}
+	fuzzySuffixes    = []string{"-python", "-py", "-js", "-node", "-ruby", "-rs"}
+)
+
+// Resolver produces alternate names to search for a package under.
+type Resolver struct {
+	dictionary *Dictionary
+	fuzzy      bool
+}
+
+// NewResolver creates a Resolver using the given dictionary. When fuzzy is true, normalized variants of the package
+// name (differing in case, separators, and common ecosystem prefixes/suffixes) are also produced.
+func NewResolver(d *Dictionary, fuzzy bool) *Resolver {
+	if d == nil {
+		d = NewDictionary()
+	}
+	return &Resolver{
+		dictionary: d,
+		fuzzy:      fuzzy,
+	}
+}
+
+// Candidates returns the alternate names for the given package, excluding the package's own name. Dictionary
+// entries take precedence over fuzzy variants of the same name.
+func (r *Resolver) Candidates(p pkg.Package) []Candidate {
+	if r == nil || p.Name == "" {
+		return nil
+	}
+
+	seen := map[string]struct{}{
+		p.Name:                  {},
+		strings.ToLower(p.Name): {},
+	}
+
+	var out []Candidate
+	for _, a := range r.dictionary.Lookup(p.Type, p.Name) {
+		if _, ok := seen[a]; ok {
+			continue
+		}
+		seen[a] = struct{}{}
+		out = append(out, Candidate{Name: a, Source: DictionarySource, Confidence: DictionaryConfidence})
+	}
+
+	if !r.fuzzy {
+		return out
+	}
+
+	for _, v := range fuzzyVariants(p.Name) {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, Candidate{Name: v, Source: FuzzySource, Confidence: FuzzyConfidence})
+	}
+
+	return out
+}
+
+func fuzzyVariants(name string) []string {
+	normalized := separatorPattern.ReplaceAllString(strings.ToLower(name), "-")
+
+	variants := []string{
+		normalized,
+		strings.ReplaceAll(normalized, "-", "_"),
+	}
+
+	stripped := normalized
+	for _, prefix := range fuzzyPrefixes {
+		if s, ok := strings.CutPrefix(stripped, prefix); ok && s != "" {
+			stripped = s
+			break
+		}
+	}
+	for _, suffix := range fuzzySuffixes {
+		if s, ok := strings.CutSuffix(stripped, suffix); ok && s != "" {
+			stripped = s
+			break
+		}
+	}
+	if stripped != normalized {
+		variants = append(variants, stripped)
+	}
+
+	return variants
+}
@@ -0,0 +1,75 @@
+package alias
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestDefault(t *testing.T) {
+	d := Default()
+	assert.Equal(t, []string{"pil"}, d.Lookup(syftPkg.PythonPkg, "Pillow"))
+	assert.Equal(t, []string{"pillow"}, d.Lookup(syftPkg.PythonPkg, "PIL"), "aliases should be symmetric")
+	assert.Equal(t, []string{"node"}, d.Lookup(syftPkg.BinaryPkg, "nodejs"), "wildcard entries apply to all types")
+	assert.Empty(t, d.Lookup(syftPkg.NpmPkg, "pillow"))
+}
+
+func TestDictionary_Merge(t *testing.T) {
+	user, err := Parse([]byte(`{"npm": {"left-pad": ["leftpad"]}}`))
+	require.NoError(t, err)
+
+	d := Default()
+	d.Merge(user)
+
+	assert.Equal(t, []string{"leftpad"}, d.Lookup(syftPkg.NpmPkg, "left-pad"))
+	assert.Equal(t, []string{"pil"}, d.Lookup(syftPkg.PythonPkg, "pillow"))
+}
+
+func TestResolver_Candidates(t *testing.T) {
+	tests := []struct {
+		name  string
+		fuzzy bool
+		pkg   pkg.Package
+		want  []Candidate
+	}{
+		{
+			name: "dictionary only",
+			pkg:  pkg.Package{Name: "pillow", Type: syftPkg.PythonPkg},
+			want: []Candidate{
+				{Name: "pil", Source: DictionarySource, Confidence: DictionaryConfidence},
+			},
+		},
+		{
+			name: "no candidates",
+			pkg:  pkg.Package{Name: "requests", Type: syftPkg.PythonPkg},
+		},
+		{
+			name:  "fuzzy variants",
+			fuzzy: true,
+			pkg:   pkg.Package{Name: "Python_Dateutil", Type: syftPkg.PythonPkg},
+			want: []Candidate{
+				{Name: "python-dateutil", Source: FuzzySource, Confidence: FuzzyConfidence},
+				{Name: "dateutil", Source: FuzzySource, Confidence: FuzzyConfidence},
+			},
+		},
+		{
+			name:  "dictionary takes precedence over fuzzy",
+			fuzzy: true,
+			pkg:   pkg.Package{Name: "pil", Type: syftPkg.PythonPkg},
+			want: []Candidate{
+				{Name: "pillow", Source: DictionarySource, Confidence: DictionaryConfidence},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewResolver(Default(), tt.fuzzy)
+			assert.Equal(t, tt.want, r.Candidates(tt.pkg))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package baseimage
+
+import (
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/file"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// Advice is the base image of a scanned image, with the findings within the packages of the base image and the newer
+// tags of the base image that would remediate them.
+type Advice struct {
+	Base       Base
+	DetectedBy DetectedBy
+	// Findings is the number of matches of the packages of the base image.
+	Findings int
+	// Upgrades are the newer tags of the base image, oldest first.
+	Upgrades []Upgrade
+}
+
+// Upgrade is a newer tag of a base image, with the number of findings of the current base image it would remediate.
+type Upgrade struct {
+	Base       Base
+	Remediates int
+}
+
+// Advise returns the advice for the base image of the scanned image, or nil when the base image is not known.
+//
+// A finding of a package of the base image is remediated by a newer tag when the newer tag no longer has the package,
+// or has a version of the package outside the vulnerable range of the finding (according to the installed DB).
+func (d *Dataset) Advise(ctx pkg.Context, matches match.Matches) *Advice {
+	base, by := d.Detect(ctx)
+	if base == nil {
+		return nil
+	}
+
+	var findings []match.Match
+	for _, m := range matches.Sorted() {
+		if inBase(*base, m.Package) {
+			findings = append(findings, m)
+		}
+	}
+
+	advice := &Advice{
+		Base:       *base,
+		DetectedBy: by,
+		Findings:   len(findings),
+	}
+	for _, newer := range d.newer(*base) {
+		remediates := 0
+		for _, m := range findings {
+			if remediated(newer, m) {
+				remediates++
+			}
+		}
+		advice.Upgrades = append(advice.Upgrades, Upgrade{Base: newer, Remediates: remediates})
+	}
+	return advice
+}
+
+// Recommended returns the oldest upgrade remediating the most findings, if any upgrade remediates findings.
+func (a *Advice) Recommended() *Upgrade {
+	if a == nil {
+		return nil
+	}
+	var best *Upgrade
+	for i := range a.Upgrades {
+		if a.Upgrades[i].Remediates > 0 && (best == nil || a.Upgrades[i].Remediates > best.Remediates) {
+			best = &a.Upgrades[i]
+		}
+	}
+	return best
+}
+
+// inBase tells whether the package was installed by the base image: when the layers of the base are known, the
+// evidence of the package (see evidenceLocations) is within these layers, otherwise the base has a package of the same
+// name and type. The other locations of the package do not tell who installed it: the files of a package installed by
+// the base image are often modified by later layers (e.g. configuration files).
+func inBase(base Base, p pkg.Package) bool {
+	if len(base.Layers) == 0 {
+		_, ok := base.find(p)
+		return ok
+	}
+
+	layers := make(map[string]bool)
+	for _, l := range base.Layers {
+		layers[l] = true
+	}
+	locations := evidenceLocations(p)
+	if len(locations) == 0 {
+		return false
+	}
+	for _, l := range locations {
+		if !layers[l.FileSystemID] {
+			return false
+		}
+	}
+	return true
+}
+
+// evidenceLocations are the locations of the primary evidence of the package (e.g. the package DB entry of an OS
+// package), or all of its locations when none is annotated as primary.
+func evidenceLocations(p pkg.Package) []file.Location {
+	var primary []file.Location
+	locations := p.Locations.ToSlice()
+	for _, l := range locations {
+		if l.Annotations[syftPkg.EvidenceAnnotationKey] == syftPkg.PrimaryEvidenceAnnotation {
+			primary = append(primary, l)
+		}
+	}
+	if len(primary) == 0 {
+		return locations
+	}
+	return primary
+}
+
+func (b Base) find(p pkg.Package) (Package, bool) {
+	for _, bp := range b.Packages {
+		if bp.Name == p.Name && bp.Type == string(p.Type) {
+			return bp, true
+		}
+	}
+	return Package{}, false
+}
+
+func remediated(newer Base, m match.Match) bool {
+	if len(newer.Packages) == 0 {
+		// the packages of the tag are unknown, and so is what it remediates
+		return false
+	}
+	bp, ok := newer.find(m.Package)
+	if !ok {
+		// the package was removed from the base image
+		return true
+	}
+	if m.Vulnerability.Constraint == nil {
+		return false
+	}
+
+	v, err := version.NewVersion(bp.Version, version.FormatFromPkg(m.Package))
+	if err != nil {
+		log.WithFields("package", bp.Name, "version", bp.Version, "base", newer.String(), "error", err).Debug("unable to parse the version of a base image package")
+		return false
+	}
+	vulnerable, err := m.Vulnerability.Constraint.Satisfied(v)
+	if err != nil {
+		return false
+	}
+	return !vulnerable
+}
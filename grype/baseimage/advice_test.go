@@ -0,0 +1,91 @@
+package baseimage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/file"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestDataset_Advise(t *testing.T) {
+	d, err := FromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+
+	apk := func(name, ver, layer string) pkg.Package {
+		return pkg.Package{
+			ID:        pkg.ID(name),
+			Name:      name,
+			Version:   ver,
+			Type:      syftPkg.ApkPkg,
+			Locations: file.NewLocationSet(file.NewLocationFromCoordinates(file.NewCoordinates("/lib/apk/db/installed", layer))),
+		}
+	}
+	finding := func(id string, p pkg.Package, constraint string) match.Match {
+		return match.Match{
+			Vulnerability: vulnerability.Vulnerability{
+				ID:         id,
+				Namespace:  "alpine:distro:alpine:3.18",
+				Constraint: version.MustGetConstraint(constraint, version.ApkFormat),
+			},
+			Package: p,
+		}
+	}
+
+	libcrypto := apk("libcrypto3", "3.1.3-r0", alpine3184Layer)
+	busybox := apk("busybox", "1.36.1-r2", alpine3184Layer)
+	// installed by the image itself, and so not remediated by the base image
+	curl := apk("curl", "8.4.0-r0", appLayer)
+	// installed by the base image, with a file modified by the image (only the package DB entry is the evidence)
+	busybox.Locations.Add(file.NewLocationFromCoordinates(file.NewCoordinates("/etc/securetty", appLayer)))
+	busybox.Locations.Add(file.NewLocationFromCoordinates(file.NewCoordinates("/lib/apk/db/installed", alpine3184Layer)).WithAnnotation(syftPkg.EvidenceAnnotationKey, syftPkg.PrimaryEvidenceAnnotation))
+
+	matches := match.NewMatches(
+		finding("CVE-2023-5363", libcrypto, "< 3.1.4-r0"),
+		finding("CVE-2023-5678", libcrypto, "< 3.1.4-r3"),
+		finding("CVE-2023-42363", busybox, "< 1.36.1-r6"),
+		finding("CVE-2023-46218", curl, "< 8.5.0-r0"),
+	)
+	ctx := imageContext(source.ImageMetadata{Layers: []source.LayerMetadata{{Digest: alpine3184Layer}, {Digest: appLayer}}})
+
+	advice := d.Advise(ctx, matches)
+	require.NotNil(t, advice)
+	assert.Equal(t, "3.18.4", advice.Base.Tag)
+	assert.Equal(t, ByLayers, advice.DetectedBy)
+	assert.Equal(t, 3, advice.Findings)
+
+	var upgrades []string
+	for _, u := range advice.Upgrades {
+		upgrades = append(upgrades, u.Base.Tag)
+	}
+	assert.Equal(t, []string{"3.18.5", "3.18.6"}, upgrades)
+	assert.Equal(t, 1, advice.Upgrades[0].Remediates, "3.18.5 has libcrypto3 3.1.4-r1")
+	assert.Equal(t, 2, advice.Upgrades[1].Remediates, "3.18.6 has libcrypto3 3.1.4-r5")
+
+	recommended := advice.Recommended()
+	require.NotNil(t, recommended)
+	assert.Equal(t, "3.18.6", recommended.Base.Tag)
+
+	assert.Nil(t, d.Advise(imageContext(source.ImageMetadata{}), matches), "unknown base image")
+	assert.Nil(t, (*Advice)(nil).Recommended())
+}
+
+func TestRemediated(t *testing.T) {
+	p := pkg.Package{Name: "musl", Version: "1.2.4-r1", Type: syftPkg.ApkPkg}
+	m := match.Match{
+		Vulnerability: vulnerability.Vulnerability{Constraint: version.MustGetConstraint("< 1.2.4-r2", version.ApkFormat)},
+		Package:       p,
+	}
+
+	assert.True(t, remediated(Base{Packages: []Package{{Name: "musl", Version: "1.2.4-r2", Type: "apk"}}}, m))
+	assert.False(t, remediated(Base{Packages: []Package{{Name: "musl", Version: "1.2.4-r1", Type: "apk"}}}, m))
+	assert.True(t, remediated(Base{Packages: []Package{{Name: "busybox", Version: "1.36.1-r5", Type: "apk"}}}, m), "the package was removed")
+	assert.False(t, remediated(Base{}, m), "the packages of the tag are unknown")
+}
@@ -0,0 +1,151 @@
+/*
+Package baseimage detects the base image of a scanned container image, from its annotations or from its layers against
+a dataset of known base images, and advises which newer tags of the base image would remediate its findings.
+*/
+package baseimage
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/internal/dataset"
+)
+
+// DBDirName is the directory of the optional base image dataset that may be shipped alongside the vulnerability DB.
+const DBDirName = "base-images"
+
+// Base is a known tag of a base image.
+type Base struct {
+	// Image is the repository of the image, e.g. "docker.io/library/alpine".
+	Image string `json:"image"`
+	Tag   string `json:"tag"`
+	// Digest is the digest of the manifest of the tag.
+	Digest string `json:"digest,omitempty"`
+	// Track groups the tags that upgrade each other (e.g. "3.18" for the 3.18.x tags of alpine), or is empty when any
+	// newer tag of the image is an upgrade.
+	Track    string    `json:"track,omitempty"`
+	Released time.Time `json:"released"`
+	// Layers are the diff IDs of the layers of the tag, from the bottom layer up.
+	Layers   []string  `json:"layers,omitempty"`
+	Packages []Package `json:"packages,omitempty"`
+}
+
+// Package is a package installed in a base image.
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+}
+
+func (b Base) String() string {
+	return b.Image + ":" + b.Tag
+}
+
+// Dataset is a collection of known base image tags.
+type Dataset struct {
+	bases []Base
+}
+
+func NewDataset() *Dataset {
+	return &Dataset{}
+}
+
+// Len returns the number of tags in the dataset.
+func (d *Dataset) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.bases)
+}
+
+// Add inserts the tags, replacing any tag of the same image already within the dataset.
+func (d *Dataset) Add(bases ...Base) {
+	for _, b := range bases {
+		if b.Image == "" || b.Tag == "" {
+			continue
+		}
+		b.Image = normalizeImage(b.Image)
+		replaced := false
+		for i := range d.bases {
+			if d.bases[i].Image == b.Image && d.bases[i].Tag == b.Tag {
+				d.bases[i] = b
+				replaced = true
+			}
+		}
+		if !replaced {
+			d.bases = append(d.bases, b)
+		}
+	}
+}
+
+// Merge adds all tags of the other dataset into this dataset. Tags of the other dataset take precedence.
+func (d *Dataset) Merge(other *Dataset) {
+	if other == nil {
+		return
+	}
+	d.Add(other.bases...)
+}
+
+// newer returns the tags upgrading the base (of the same image and track, and released later), oldest first.
+func (d *Dataset) newer(base Base) []Base {
+	var out []Base
+	for _, b := range d.bases {
+		if b.Image == base.Image && b.Track == base.Track && b.Released.After(base.Released) {
+			out = append(out, b)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Released.Before(out[j].Released)
+	})
+	return out
+}
+
+// Parse reads base image tags, as an array of tags.
+func Parse(data []byte) (*Dataset, error) {
+	var bases []Base
+	if err := json.Unmarshal(data, &bases); err != nil {
+		return nil, fmt.Errorf("unable to parse base image dataset: %w", err)
+	}
+	d := NewDataset()
+	d.Add(bases...)
+	return d, nil
+}
+
+// sidecar is the base image dataset shipped within the DB directory.
+var sidecar = dataset.Sidecar[*Dataset]{
+	Kind:  "base image dataset",
+	Path:  DBDirName,
+	Parse: Parse,
+	New:   NewDataset,
+}
+
+// FromPath reads a dataset from a single JSON file or from every JSON file within a directory.
+func FromPath(path string) (*Dataset, error) {
+	return sidecar.FromPath(path)
+}
+
+// FromDBDir reads the base image dataset shipped within the given DB directory, if there is one. A nil dataset (and
+// no error) is returned when the DB does not ship a dataset.
+func FromDBDir(dir string) (*Dataset, error) {
+	return sidecar.FromDBDir(dir)
+}
+
+// normalizeImage returns the image repository with the default registry and namespace of Docker Hub, so that
+// "alpine" and "docker.io/library/alpine" are the same image.
+func normalizeImage(image string) string {
+	image = strings.ToLower(strings.TrimSpace(image))
+	first, _, found := strings.Cut(image, "/")
+	if !found {
+		return "docker.io/library/" + image
+	}
+	if !strings.ContainsAny(first, ".:") && first != "localhost" {
+		return "docker.io/" + image
+	}
+	if first == "index.docker.io" {
+		return "docker.io/" + strings.TrimPrefix(image, "index.docker.io/")
+	}
+	return image
+}
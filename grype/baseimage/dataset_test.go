@@ -0,0 +1,53 @@
+package baseimage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromDBDir(t *testing.T) {
+	d, err := FromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+	assert.Equal(t, 4, d.Len())
+	assert.Equal(t, "docker.io/library/alpine:3.18.4", d.bases[0].String(), "the image is normalized")
+
+	var newer []string
+	for _, b := range d.newer(d.bases[0]) {
+		newer = append(newer, b.Tag)
+	}
+	assert.Equal(t, []string{"3.18.5", "3.18.6"}, newer, "newer tags of the same track, oldest first")
+}
+
+func TestFromPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{}`), 0o600))
+	_, err := FromPath(path)
+	assert.ErrorContains(t, err, "unable to parse base image dataset")
+
+	_, err = FromPath("test-fixtures/missing.json")
+	assert.ErrorContains(t, err, "unable to read base image dataset")
+}
+
+func TestDataset_Merge(t *testing.T) {
+	d, err := FromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+
+	other := NewDataset()
+	other.Add(Base{Image: "alpine", Tag: "3.18.4", Track: "3.18", Digest: "sha256:replaced"}, Base{Image: "alpine"})
+	d.Merge(other)
+
+	assert.Equal(t, 4, d.Len(), "tags without a tag are ignored")
+	assert.Equal(t, "sha256:replaced", d.bases[0].Digest)
+}
+
+func TestNormalizeImage(t *testing.T) {
+	assert.Equal(t, "docker.io/library/alpine", normalizeImage("alpine"))
+	assert.Equal(t, "docker.io/library/alpine", normalizeImage("index.docker.io/library/alpine"))
+	assert.Equal(t, "docker.io/bitnami/redis", normalizeImage("bitnami/redis"))
+	assert.Equal(t, "gcr.io/distroless/static", normalizeImage("gcr.io/distroless/static"))
+	assert.Equal(t, "localhost:5000/base", normalizeImage("localhost:5000/base"))
+}
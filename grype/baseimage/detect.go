@@ -0,0 +1,124 @@
+package baseimage
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+// DetectedBy is how the base image of an image was detected.
+type DetectedBy string
+
+const (
+	// ByAnnotation is a base image named by the OCI base image annotations (or labels) of the image.
+	ByAnnotation DetectedBy = "annotation"
+	// ByLayers is a base image whose layers are the bottom layers of the image.
+	ByLayers DetectedBy = "layers"
+)
+
+const (
+	baseNameAnnotation   = "org.opencontainers.image.base.name"
+	baseDigestAnnotation = "org.opencontainers.image.base.digest"
+)
+
+// Detect returns the known base image of the scanned image: the base named by the annotations of the manifest (or
+// the labels of the config) of the image when known to the dataset, or else the known base with the most layers
+// that are the bottom layers of the image.
+func (d *Dataset) Detect(ctx pkg.Context) (*Base, DetectedBy) {
+	if d.Len() == 0 || ctx.Source == nil {
+		return nil, ""
+	}
+	metadata, ok := ctx.Source.Metadata.(source.ImageMetadata)
+	if !ok {
+		return nil, ""
+	}
+
+	if b := d.byAnnotations(annotations(metadata)); b != nil {
+		return b, ByAnnotation
+	}
+	if b := d.byLayers(metadata.Layers); b != nil {
+		return b, ByLayers
+	}
+	return nil, ""
+}
+
+// annotations returns the annotations of the manifest of the image, along with the labels of its config (since
+// several build tools set the base image as labels), the annotations taking precedence.
+func annotations(metadata source.ImageMetadata) map[string]string {
+	values := make(map[string]string)
+	for k, v := range metadata.Labels {
+		values[k] = v
+	}
+	var manifest struct {
+		Annotations map[string]string `json:"annotations"`
+	}
+	if len(metadata.RawManifest) > 0 && json.Unmarshal(metadata.RawManifest, &manifest) == nil {
+		for k, v := range manifest.Annotations {
+			values[k] = v
+		}
+	}
+	return values
+}
+
+func (d *Dataset) byAnnotations(values map[string]string) *Base {
+	if digest := values[baseDigestAnnotation]; digest != "" {
+		for i := range d.bases {
+			if strings.EqualFold(d.bases[i].Digest, digest) {
+				return &d.bases[i]
+			}
+		}
+	}
+
+	name := values[baseNameAnnotation]
+	if name == "" {
+		return nil
+	}
+	image, tag, digest := parseReference(name)
+	for i := range d.bases {
+		b := &d.bases[i]
+		if b.Image != image {
+			continue
+		}
+		if (digest != "" && strings.EqualFold(b.Digest, digest)) || (tag != "" && b.Tag == tag) {
+			return b
+		}
+	}
+	return nil
+}
+
+func (d *Dataset) byLayers(layers []source.LayerMetadata) *Base {
+	var best *Base
+	for i := range d.bases {
+		b := &d.bases[i]
+		if len(b.Layers) == 0 || len(b.Layers) > len(layers) {
+			continue
+		}
+		if best != nil && len(b.Layers) <= len(best.Layers) {
+			continue
+		}
+		prefix := true
+		for j, l := range b.Layers {
+			if !strings.EqualFold(l, layers[j].Digest) {
+				prefix = false
+				break
+			}
+		}
+		if prefix {
+			best = b
+		}
+	}
+	return best
+}
+
+// parseReference splits an image reference into its (normalized) repository, tag and digest, e.g.
+// "alpine:3.18@sha256:..." into "docker.io/library/alpine", "3.18" and "sha256:...".
+func parseReference(ref string) (string, string, string) {
+	ref, digest, _ := strings.Cut(strings.TrimSpace(ref), "@")
+	image, tag := ref, ""
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		image, tag = ref[:i], ref[i+1:]
+	}
+	return normalizeImage(image), tag, digest
+}
@@ -0,0 +1,98 @@
+package baseimage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+const (
+	alpine3184Layer = "sha256:cc2447e1835a40530975ab80bb1f872fbab0f2a0faecf2ab16fbbb89b3589438"
+	appLayer        = "sha256:1f9bd6a8b2b5ea6b4e1d69e4fa5ea5b3e7bb9ff8e5d2aa7e82b1f0a0f0e10c4c"
+)
+
+func imageContext(metadata source.ImageMetadata) pkg.Context {
+	return pkg.Context{Source: &source.Description{Metadata: metadata}}
+}
+
+func TestDataset_Detect(t *testing.T) {
+	d, err := FromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		metadata source.ImageMetadata
+		wantTag  string
+		wantBy   DetectedBy
+	}{
+		{
+			name:     "by layers",
+			metadata: source.ImageMetadata{Layers: []source.LayerMetadata{{Digest: alpine3184Layer}, {Digest: appLayer}}},
+			wantTag:  "3.18.4",
+			wantBy:   ByLayers,
+		},
+		{
+			name: "by the base name annotation of the manifest",
+			metadata: source.ImageMetadata{
+				RawManifest: []byte(`{"annotations": {"org.opencontainers.image.base.name": "docker.io/library/alpine:3.18.5"}}`),
+				Layers:      []source.LayerMetadata{{Digest: alpine3184Layer}},
+			},
+			wantTag: "3.18.5",
+			wantBy:  ByAnnotation,
+		},
+		{
+			name: "by the base digest label",
+			metadata: source.ImageMetadata{
+				Labels: map[string]string{
+					"org.opencontainers.image.base.digest": "sha256:eece025e432126ce23f223450a0326fbebde39cdf496a85d8c016293fc851978",
+				},
+			},
+			wantTag: "3.18.4",
+			wantBy:  ByAnnotation,
+		},
+		{
+			name: "an unknown base falls back to the layers",
+			metadata: source.ImageMetadata{
+				Labels: map[string]string{"org.opencontainers.image.base.name": "alpine:3.17.0"},
+				Layers: []source.LayerMetadata{{Digest: alpine3184Layer}},
+			},
+			wantTag: "3.18.4",
+			wantBy:  ByLayers,
+		},
+		{
+			name:     "unknown",
+			metadata: source.ImageMetadata{Layers: []source.LayerMetadata{{Digest: appLayer}, {Digest: alpine3184Layer}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, by := d.Detect(imageContext(tt.metadata))
+			if tt.wantTag == "" {
+				assert.Nil(t, base)
+				return
+			}
+			require.NotNil(t, base)
+			assert.Equal(t, tt.wantTag, base.Tag)
+			assert.Equal(t, tt.wantBy, by)
+		})
+	}
+
+	base, _ := d.Detect(pkg.Context{Source: &source.Description{Metadata: source.DirectoryMetadata{Path: "."}}})
+	assert.Nil(t, base, "only images have a base image")
+}
+
+func TestParseReference(t *testing.T) {
+	image, tag, digest := parseReference("alpine:3.18@sha256:abc")
+	assert.Equal(t, "docker.io/library/alpine", image)
+	assert.Equal(t, "3.18", tag)
+	assert.Equal(t, "sha256:abc", digest)
+
+	image, tag, digest = parseReference("localhost:5000/base")
+	assert.Equal(t, "localhost:5000/base", image)
+	assert.Empty(t, tag)
+	assert.Empty(t, digest)
+}
@@ -0,0 +1,277 @@
+/*
+Package bundle writes and verifies results bundles: a gzipped tar of the reports of a scan along with the SBOM that was
+scanned, the effective configuration and the metadata of the vulnerability database, for evidence retention. The
+bundle holds a manifest describing the scan and its files, and the SHA-256 checksums of every file (in the format of
+sha256sum, so a bundle may also be verified with standard tools once extracted).
+*/
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ManifestFileName is the name of the manifest within a bundle.
+	ManifestFileName = "manifest.json"
+	// ChecksumsFileName is the name of the checksums of the files of a bundle (the manifest included).
+	ChecksumsFileName = "checksums.txt"
+
+	// ManifestVersion is the version of the manifest written.
+	ManifestVersion = 1
+)
+
+// Manifest describes a scan and the files of its bundle.
+type Manifest struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	Tool    Tool      `json:"tool"`
+	Target  string    `json:"target,omitempty"`
+	DB      *DB       `json:"db,omitempty"`
+	Files   []File    `json:"files"`
+}
+
+// Tool is the application that scanned the target.
+type Tool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// DB identifies the vulnerability database used for the scan.
+type DB struct {
+	Built         time.Time `json:"built"`
+	SchemaVersion int       `json:"schemaVersion"`
+	Checksum      string    `json:"checksum,omitempty"`
+}
+
+// File is a file of a bundle, with its SHA-256 checksum ("sha256:<hex>").
+type File struct {
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// Entry is a file to write to a bundle.
+type Entry struct {
+	// Name is the path of the file within the bundle (e.g. "reports/report.json").
+	Name     string
+	Contents []byte
+}
+
+// Write writes the bundle of the entries to the given path, listing them in the manifest (whose Files are set by
+// Write) and in the checksums of the bundle.
+func Write(bundlePath string, manifest Manifest, entries ...Entry) error {
+	if manifest.Version == 0 {
+		manifest.Version = ManifestVersion
+	}
+	manifest.Files = nil
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		name, err := entryName(e.Name)
+		if err != nil {
+			return err
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate results bundle file %q", name)
+		}
+		seen[name] = true
+		manifest.Files = append(manifest.Files, File{Name: name, Size: int64(len(e.Contents)), Checksum: "sha256:" + digest(e.Contents)})
+	}
+
+	contents, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode results bundle manifest: %w", err)
+	}
+	entries = append(entries, Entry{Name: ManifestFileName, Contents: append(contents, '\n')})
+
+	var checksums strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&checksums, "%s  %s\n", digest(e.Contents), path.Clean(e.Name))
+	}
+	entries = append(entries, Entry{Name: ChecksumsFileName, Contents: []byte(checksums.String())})
+
+	out, err := os.Create(bundlePath)
+	if err != nil {
+		return fmt.Errorf("unable to create results bundle: %w", err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		header := &tar.Header{
+			Name:    path.Clean(e.Name),
+			Size:    int64(len(e.Contents)),
+			Mode:    0o644,
+			ModTime: manifest.Created,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("unable to write results bundle: %w", err)
+		}
+		if _, err := tw.Write(e.Contents); err != nil {
+			return fmt.Errorf("unable to write results bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("unable to write results bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("unable to write results bundle: %w", err)
+	}
+	return out.Close()
+}
+
+// Verify checks the integrity of the bundle at the given path, returning its manifest: every file listed in the
+// checksums must be in the bundle with the listed checksum, the manifest must list every other file with the same
+// checksum and size, and the bundle must hold no other file. Every problem found is reported.
+func Verify(bundlePath string) (*Manifest, error) {
+	files, err := readBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	checksumsFile, ok := files[ChecksumsFileName]
+	if !ok {
+		return nil, fmt.Errorf("invalid results bundle: no %s", ChecksumsFileName)
+	}
+	checksums, err := parseChecksums(checksumsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []error
+	for name, checksum := range checksums {
+		contents, ok := files[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Errorf("%s is missing", name))
+		case digest(contents) != checksum:
+			problems = append(problems, fmt.Errorf("%s does not match its checksum", name))
+		}
+	}
+	for name := range files {
+		if _, ok := checksums[name]; !ok && name != ChecksumsFileName {
+			problems = append(problems, fmt.Errorf("%s is not listed in the checksums", name))
+		}
+	}
+
+	manifestFile, ok := files[ManifestFileName]
+	if !ok {
+		return nil, errors.Join(append(problems, fmt.Errorf("invalid results bundle: no %s", ManifestFileName))...)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestFile, &manifest); err != nil {
+		return nil, errors.Join(append(problems, fmt.Errorf("unable to parse results bundle manifest: %w", err))...)
+	}
+	listed := make(map[string]bool)
+	for _, f := range manifest.Files {
+		listed[f.Name] = true
+		contents, ok := files[f.Name]
+		switch {
+		case !ok:
+			// reported as missing from the checksums, or else as not listed in the checksums
+			if _, inChecksums := checksums[f.Name]; !inChecksums {
+				problems = append(problems, fmt.Errorf("%s is missing", f.Name))
+			}
+		case "sha256:"+digest(contents) != f.Checksum || int64(len(contents)) != f.Size:
+			problems = append(problems, fmt.Errorf("%s does not match the manifest", f.Name))
+		}
+	}
+	for name := range files {
+		if !listed[name] && name != ManifestFileName && name != ChecksumsFileName {
+			problems = append(problems, fmt.Errorf("%s is not listed in the manifest", name))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Slice(problems, func(i, j int) bool { return problems[i].Error() < problems[j].Error() })
+		return &manifest, fmt.Errorf("results bundle failed verification: %w", errors.Join(problems...))
+	}
+	return &manifest, nil
+}
+
+func readBundle(bundlePath string) (map[string][]byte, error) {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open results bundle: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read results bundle: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read results bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		if _, ok := files[name]; ok {
+			return nil, fmt.Errorf("invalid results bundle: duplicate file %q", name)
+		}
+		contents, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read results bundle: %w", err)
+		}
+		files[name] = contents
+	}
+	return files, nil
+}
+
+// parseChecksums reads checksums in the format of sha256sum ("<hex>  <name>" lines).
+func parseChecksums(contents []byte) (map[string]string, error) {
+	checksums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		checksum, name, ok := strings.Cut(line, "  ")
+		if !ok {
+			return nil, fmt.Errorf("invalid results bundle checksum line %q", line)
+		}
+		checksums[path.Clean(name)] = checksum
+	}
+	return checksums, scanner.Err()
+}
+
+func entryName(name string) (string, error) {
+	clean := path.Clean(name)
+	if clean == "." || path.IsAbs(clean) || strings.HasPrefix(clean, "../") || clean == ".." {
+		return "", fmt.Errorf("invalid results bundle file name %q", name)
+	}
+	if clean == ManifestFileName || clean == ChecksumsFileName {
+		return "", fmt.Errorf("results bundle file name %q is reserved", name)
+	}
+	return clean, nil
+}
+
+func digest(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,122 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testManifest = Manifest{
+	Created: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	Tool:    Tool{Name: "grype", Version: "0.80.0"},
+	Target:  "alpine:3.19",
+	DB:      &DB{Built: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), SchemaVersion: 5, Checksum: "sha256:abc"},
+}
+
+var testEntries = []Entry{
+	{Name: "reports/report.json", Contents: []byte(`{"matches": []}`)},
+	{Name: "config.yaml", Contents: []byte("output: json\n")},
+}
+
+func TestWriteVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.tgz")
+	require.NoError(t, Write(path, testManifest, testEntries...))
+
+	manifest, err := Verify(path)
+	require.NoError(t, err)
+
+	expected := testManifest
+	expected.Version = ManifestVersion
+	expected.Files = []File{
+		{Name: "reports/report.json", Size: 15, Checksum: "sha256:" + digest(testEntries[0].Contents)},
+		{Name: "config.yaml", Size: 13, Checksum: "sha256:" + digest(testEntries[1].Contents)},
+	}
+	assert.Equal(t, &expected, manifest)
+
+	files, err := readBundle(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(files[ChecksumsFileName]), digest(testEntries[1].Contents)+"  config.yaml\n")
+	assert.Contains(t, string(files[ChecksumsFileName]), digest(files[ManifestFileName])+"  manifest.json\n")
+}
+
+func TestWrite_invalidNames(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"../escape", "/abs", "manifest.json", ""} {
+		err := Write(filepath.Join(dir, "results.tgz"), testManifest, Entry{Name: name})
+		assert.Error(t, err, name)
+	}
+	err := Write(filepath.Join(dir, "results.tgz"), testManifest, Entry{Name: "a"}, Entry{Name: "./a"})
+	assert.ErrorContains(t, err, "duplicate")
+}
+
+func TestVerify_tampered(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  map[string][]byte
+		drop    string
+		wantErr []string
+	}{
+		{
+			name:    "modified file",
+			tamper:  map[string][]byte{"reports/report.json": []byte(`{"matches": null}`)},
+			wantErr: []string{"reports/report.json does not match its checksum", "reports/report.json does not match the manifest"},
+		},
+		{
+			name:    "added file",
+			tamper:  map[string][]byte{"reports/extra.json": []byte(`{}`)},
+			wantErr: []string{"reports/extra.json is not listed in the checksums", "reports/extra.json is not listed in the manifest"},
+		},
+		{
+			name:    "removed file",
+			drop:    "config.yaml",
+			wantErr: []string{"config.yaml is missing"},
+		},
+		{
+			name:    "removed checksums",
+			drop:    ChecksumsFileName,
+			wantErr: []string{"no checksums.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "results.tgz")
+			require.NoError(t, Write(path, testManifest, testEntries...))
+			files, err := readBundle(path)
+			require.NoError(t, err)
+			for name, contents := range tt.tamper {
+				files[name] = contents
+			}
+			delete(files, tt.drop)
+			rewrite(t, path, files)
+
+			_, err = Verify(path)
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func rewrite(t *testing.T, path string, files map[string][]byte) {
+	t.Helper()
+	out, err := os.Create(path)
+	require.NoError(t, err)
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(contents)), Mode: 0o644}))
+		_, err := tw.Write(contents)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+}
@@ -0,0 +1,155 @@
+package build
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/anchore/grype/grype/alias"
+	"github.com/anchore/grype/grype/baseimage"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/localization"
+	"github.com/anchore/grype/grype/malware"
+	"github.com/anchore/grype/grype/pending"
+	"github.com/anchore/grype/grype/priority"
+	"github.com/anchore/grype/grype/supplychain"
+	"github.com/anchore/grype/internal/file"
+)
+
+const listingFileName = distribution.ListingFileName
+
+// sidecarDatasets are the files and directories of the datasets that may be shipped within the database directory
+// along with the database file.
+var sidecarDatasets = []string{
+	alias.DBFileName,
+	baseimage.DBDirName,
+	localization.DBDirName,
+	malware.DBDirName,
+	pending.DBFileName,
+	priority.DBDirName,
+	supplychain.DBDirName,
+}
+
+// sidecars returns the sidecar datasets found within the given database directory.
+func sidecars(dir string) []string {
+	var found []string
+	for _, name := range sidecarDatasets {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+// ArchiveName returns the file name of the archive of a database built at the given time (e.g.
+// "vulnerability-db_v5_2024-03-01T00:00:00Z.tar.gz").
+func ArchiveName(built time.Time) string {
+	return fmt.Sprintf("vulnerability-db_v%d_%s.tar.gz", v5.SchemaVersion, built.UTC().Format(time.RFC3339))
+}
+
+// WriteArchive writes the archive of the database file built at the given time, as served to curators: a gzipped tar
+// of the metadata of the database, of the database file, and of the sidecar datasets within the directory of the
+// database file.
+func WriteArchive(dbPath string, built time.Time, archivePath string) error {
+	fs := afero.NewOsFs()
+	checksum, err := file.HashFile(fs, dbPath, sha256.New())
+	if err != nil {
+		return fmt.Errorf("unable to find vulnerability database checksum: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "grype-db-build")
+	if err != nil {
+		return fmt.Errorf("unable to create temp dir for the vulnerability database metadata: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	metadataPath := filepath.Join(dir, distribution.MetadataFileName)
+	metadata := distribution.Metadata{
+		Built:    built,
+		Version:  v5.SchemaVersion,
+		Checksum: "sha256:" + checksum,
+	}
+	if err := metadata.Write(metadataPath); err != nil {
+		return err
+	}
+
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("unable to create vulnerability database archive: %w", err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for _, f := range []struct{ name, path string }{
+		{name: distribution.MetadataFileName, path: metadataPath},
+		{name: v5.VulnerabilityStoreFileName, path: dbPath},
+	} {
+		if err := addToArchive(tw, f.name, f.path); err != nil {
+			return fmt.Errorf("unable to write vulnerability database archive: %w", err)
+		}
+	}
+	for _, name := range sidecars(filepath.Dir(dbPath)) {
+		if err := addTreeToArchive(tw, name, filepath.Join(filepath.Dir(dbPath), name)); err != nil {
+			return fmt.Errorf("unable to write vulnerability database archive: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("unable to write vulnerability database archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("unable to write vulnerability database archive: %w", err)
+	}
+	return out.Close()
+}
+
+func addToArchive(tw *tar.Writer, name, path string) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(contents)), Mode: 0o600}); err != nil {
+		return err
+	}
+	_, err = tw.Write(contents)
+	return err
+}
+
+// addTreeToArchive adds the file, or every file within the directory, under the given name.
+func addTreeToArchive(tw *tar.Writer, name, path string) error {
+	return filepath.WalkDir(path, func(p string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+		return addToArchive(tw, filepath.ToSlash(filepath.Join(name, rel)), p)
+	})
+}
+
+// WriteListing writes the listing of the archive of a database built at the given time, the archive being served
+// from the base URL.
+func WriteListing(archivePath string, built time.Time, baseURL, listingPath string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("unable to parse the base URL of the vulnerability database archive: %w", err)
+	}
+	entry, err := distribution.NewListingEntryFromArchive(afero.NewOsFs(), distribution.Metadata{
+		Built:   built,
+		Version: v5.SchemaVersion,
+	}, archivePath, u)
+	if err != nil {
+		return err
+	}
+	return distribution.NewListing(entry).Write(listingPath)
+}
@@ -0,0 +1,248 @@
+/*
+Package build builds vulnerability databases from OSV records and NVD CVE records, so that a database holding only
+private advisories (or private advisories along with public ones) can be built, archived and served to curators
+without the upstream database build. It also merges an overlay database (of extra advisories, severity overrides and
+match exclusions) over a base database such as the official one.
+
+The databases are of the schema of the vulnerability databases used for matching (v5), since the v6 schema does not
+yet hold vulnerability records.
+*/
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store"
+	"github.com/anchore/grype/grype/osv"
+	"github.com/anchore/grype/internal/log"
+)
+
+// Config is the configuration of a database build.
+type Config struct {
+	// Inputs are the JSON files, or directories of JSON files (recursively), of the records to build the database
+	// from: OSV records (a single record or an array of records per file), and NVD CVE API (2.0) responses or single
+	// CVE records.
+	Inputs []string
+	// Dir is the directory to write the database, its archive and the listing of the archive to.
+	Dir string
+	// Built is the build time of the database, now when zero.
+	Built time.Time
+	// BaseURL is the URL the archive is served from, for the listing of the archive. No listing is written when empty.
+	BaseURL string
+}
+
+// Result describes a database build.
+type Result struct {
+	// DBPath is the path of the database file.
+	DBPath string
+	// ArchivePath is the path of the archive of the database, as served to curators.
+	ArchivePath string
+	// ListingPath is the path of the listing of the archive, empty when no listing was written.
+	ListingPath string
+	// Built is the build time of the database.
+	Built time.Time
+	// Vulnerabilities and Metadata are the number of vulnerability records and of metadata records of the database.
+	Vulnerabilities int
+	Metadata        int
+}
+
+// records are the records read from the inputs.
+type records struct {
+	osv []osv.Vulnerability
+	nvd []nvdCVE
+}
+
+// Build builds a database from the records of the inputs (OSV records are converted with osv.ToDB, NVD CVE records
+// into "nvd:cpe" records), then writes the database file, its archive, and the listing of the archive when a base URL
+// is given. Records are written in the order of the inputs, the first metadata record of a vulnerability and
+// namespace winning over the others.
+func Build(cfg Config) (*Result, error) {
+	if len(cfg.Inputs) == 0 {
+		return nil, fmt.Errorf("no inputs to build the vulnerability database from")
+	}
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("no output directory for the vulnerability database")
+	}
+	built := cfg.Built
+	if built.IsZero() {
+		built = time.Now()
+	}
+	built = built.UTC().Truncate(time.Second)
+
+	var in records
+	for _, input := range cfg.Inputs {
+		if err := in.readPath(input); err != nil {
+			return nil, err
+		}
+	}
+
+	vulns, metadata := osv.ToDB(in.osv)
+	nvdVulns, nvdMetadata, err := fromNVD(in.nvd)
+	if err != nil {
+		return nil, err
+	}
+	vulns = append(vulns, nvdVulns...)
+	metadata = uniqueMetadata(append(metadata, nvdMetadata...))
+	if len(vulns) == 0 {
+		return nil, fmt.Errorf("no vulnerability records found within the inputs")
+	}
+	log.WithFields("vulnerabilities", len(vulns), "metadata", len(metadata)).Debug("building vulnerability database")
+
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create vulnerability database output directory: %w", err)
+	}
+	result := &Result{
+		DBPath:          filepath.Join(cfg.Dir, v5.VulnerabilityStoreFileName),
+		Built:           built,
+		Vulnerabilities: len(vulns),
+		Metadata:        len(metadata),
+	}
+	if err := writeDB(result.DBPath, built, vulns, metadata); err != nil {
+		return nil, err
+	}
+
+	if result.ArchivePath, result.ListingPath, err = publish(result.DBPath, built, cfg.BaseURL); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// publish writes the archive of the database file next to the file, and the listing of the archive when a base URL is
+// given.
+func publish(dbPath string, built time.Time, baseURL string) (string, string, error) {
+	dir := filepath.Dir(dbPath)
+	archivePath := filepath.Join(dir, ArchiveName(built))
+	if err := WriteArchive(dbPath, built, archivePath); err != nil {
+		return "", "", err
+	}
+	if baseURL == "" {
+		return archivePath, "", nil
+	}
+	listingPath := filepath.Join(dir, listingFileName)
+	if err := WriteListing(archivePath, built, baseURL, listingPath); err != nil {
+		return "", "", err
+	}
+	return archivePath, listingPath, nil
+}
+
+func writeDB(path string, built time.Time, vulns []v5.Vulnerability, metadata []v5.VulnerabilityMetadata) error {
+	s, err := store.New(path, true)
+	if err != nil {
+		return fmt.Errorf("unable to create vulnerability database: %w", err)
+	}
+	defer s.Close()
+
+	if err := s.SetID(v5.NewID(built)); err != nil {
+		return fmt.Errorf("unable to write vulnerability database ID: %w", err)
+	}
+	if err := s.AddVulnerability(vulns...); err != nil {
+		return fmt.Errorf("unable to write vulnerabilities: %w", err)
+	}
+	if err := s.AddVulnerabilityMetadata(metadata...); err != nil {
+		return fmt.Errorf("unable to write vulnerability metadata: %w", err)
+	}
+	return nil
+}
+
+func uniqueMetadata(metadata []v5.VulnerabilityMetadata) []v5.VulnerabilityMetadata {
+	seen := make(map[string]bool)
+	var unique []v5.VulnerabilityMetadata
+	for _, m := range metadata {
+		key := m.ID + "/" + m.Namespace
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, m)
+	}
+	return unique
+}
+
+// readPath reads the records of a single JSON file or of every JSON file within a directory (recursively).
+func (r *records) readPath(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("unable to read vulnerability records: %w", err)
+	}
+	if !info.IsDir() {
+		return r.readFile(path)
+	}
+	return filepath.WalkDir(path, func(p string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(p), ".json") {
+			return nil
+		}
+		return r.readFile(p)
+	})
+}
+
+func (r *records) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read vulnerability records: %w", err)
+	}
+	if err := r.parse(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// parse reads the records of a file, telling NVD responses and CVE records (by their "vulnerabilities" or
+// "configurations" fields) apart from OSV records.
+func (r *records) parse(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var records []osv.Vulnerability
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return fmt.Errorf("unable to parse OSV records: %w", err)
+		}
+		r.osv = append(r.osv, records...)
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return fmt.Errorf("unable to parse vulnerability records: %w", err)
+	}
+	switch {
+	case fields["vulnerabilities"] != nil:
+		var response nvdResponse
+		if err := json.Unmarshal(trimmed, &response); err != nil {
+			return fmt.Errorf("unable to parse NVD CVE records: %w", err)
+		}
+		for _, item := range response.Vulnerabilities {
+			r.nvd = append(r.nvd, item.CVE)
+		}
+	case fields["cve"] != nil:
+		var item nvdItem
+		if err := json.Unmarshal(trimmed, &item); err != nil {
+			return fmt.Errorf("unable to parse NVD CVE record: %w", err)
+		}
+		r.nvd = append(r.nvd, item.CVE)
+	case fields["configurations"] != nil:
+		var cve nvdCVE
+		if err := json.Unmarshal(trimmed, &cve); err != nil {
+			return fmt.Errorf("unable to parse NVD CVE record: %w", err)
+		}
+		r.nvd = append(r.nvd, cve)
+	default:
+		var record osv.Vulnerability
+		if err := json.Unmarshal(trimmed, &record); err != nil {
+			return fmt.Errorf("unable to parse OSV record: %w", err)
+		}
+		if record.ID == "" {
+			return fmt.Errorf("neither an OSV record nor an NVD CVE record")
+		}
+		r.osv = append(r.osv, record)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package build
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store"
+)
+
+var testBuilt = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	result, err := Build(Config{
+		Inputs:  []string{"test-fixtures/advisories"},
+		Dir:     dir,
+		Built:   testBuilt,
+		BaseURL: "https://db.example.com/grype",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &Result{
+		DBPath:          filepath.Join(dir, "vulnerability.db"),
+		ArchivePath:     filepath.Join(dir, "vulnerability-db_v5_2024-03-01T12:00:00Z.tar.gz"),
+		ListingPath:     filepath.Join(dir, "listing.json"),
+		Built:           testBuilt,
+		Vulnerabilities: 2,
+		Metadata:        2,
+	}, result)
+
+	s, err := store.New(result.DBPath, false)
+	require.NoError(t, err)
+	defer s.Close()
+
+	id, err := s.GetID()
+	require.NoError(t, err)
+	assert.Equal(t, testBuilt, id.BuildTimestamp.UTC())
+
+	python, err := s.SearchForVulnerabilities("osv:language:python", "internal-lib")
+	require.NoError(t, err)
+	require.Len(t, python, 1)
+	assert.Equal(t, ">= 1.0, < 1.4.2", python[0].VersionConstraint)
+
+	cpes, err := s.SearchForVulnerabilities("nvd:cpe", "internal-lib")
+	require.NoError(t, err)
+	require.Len(t, cpes, 1)
+	assert.Equal(t, ">= 1.0, < 1.4.2 || = 0.9", cpes[0].VersionConstraint)
+	assert.Equal(t, []string{"cpe:2.3:a:example:internal-lib:*:*:*:*:*:python:*:*"}, cpes[0].CPEs)
+
+	metadata, err := s.GetVulnerabilityMetadata("CVE-2024-0001", "nvd:cpe")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "Critical", metadata.Severity)
+
+	rejected, err := s.GetVulnerability("nvd:cpe", "CVE-2024-0002")
+	require.NoError(t, err)
+	assert.Empty(t, rejected)
+
+	contents, err := os.ReadFile(result.ListingPath)
+	require.NoError(t, err)
+	var listing distribution.Listing
+	require.NoError(t, json.Unmarshal(contents, &listing))
+	entry := listing.BestUpdate(v5.SchemaVersion)
+	require.NotNil(t, entry)
+	assert.Equal(t, "https://db.example.com/grype/vulnerability-db_v5_2024-03-01T12:00:00Z.tar.gz", entry.URL.String())
+	assert.Equal(t, testBuilt, entry.Built)
+}
+
+func TestBuild_archiveImports(t *testing.T) {
+	result, err := Build(Config{
+		Inputs: []string{"test-fixtures/advisories/osv/INTERNAL-2024-0001.json"},
+		Dir:    t.TempDir(),
+		Built:  testBuilt,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.ListingPath)
+
+	curator, err := distribution.NewCurator(distribution.Config{DBRootDir: t.TempDir()})
+	require.NoError(t, err)
+	require.NoError(t, curator.ImportFrom(result.ArchivePath))
+
+	status := curator.Status()
+	require.NoError(t, status.Err)
+	assert.Equal(t, testBuilt, status.Built.UTC())
+	assert.Equal(t, v5.SchemaVersion, status.SchemaVersion)
+}
+
+func TestBuild_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name:    "no inputs",
+			cfg:     Config{Dir: t.TempDir()},
+			wantErr: "no inputs",
+		},
+		{
+			name:    "no output directory",
+			cfg:     Config{Inputs: []string{"test-fixtures/advisories"}},
+			wantErr: "no output directory",
+		},
+		{
+			name:    "missing input",
+			cfg:     Config{Inputs: []string{"test-fixtures/missing"}, Dir: t.TempDir()},
+			wantErr: "unable to read vulnerability records",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Build(tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRecords_parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		osv     int
+		nvd     int
+		wantErr bool
+	}{
+		{name: "OSV record", data: `{"id": "INTERNAL-1", "affected": []}`, osv: 1},
+		{name: "OSV records", data: `[{"id": "INTERNAL-1"}, {"id": "INTERNAL-2"}]`, osv: 2},
+		{name: "NVD response", data: `{"vulnerabilities": [{"cve": {"id": "CVE-1"}}, {"cve": {"id": "CVE-2"}}]}`, nvd: 2},
+		{name: "NVD item", data: `{"cve": {"id": "CVE-1"}}`, nvd: 1},
+		{name: "NVD CVE", data: `{"id": "CVE-1", "configurations": []}`, nvd: 1},
+		{name: "unknown", data: `{"name": "something"}`, wantErr: true},
+		{name: "invalid", data: `{`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r records
+			err := r.parse([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, r.osv, tt.osv)
+			assert.Len(t, r.nvd, tt.nvd)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/namespace"
+	"github.com/anchore/grype/grype/osv"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/internal/log"
+)
+
+// FixtureSpec is the YAML spec of a fixture database: a tiny database of synthetic vulnerabilities, so that tests
+// scanning with a vulnerability database run hermetically (without the network) and fast.
+type FixtureSpec struct {
+	// Built is the build time of the database, now when zero (a fixed time makes the database reproducible, the
+	// scans then needing db.validate-age to be off).
+	Built           time.Time              `yaml:"built"`
+	Vulnerabilities []FixtureVulnerability `yaml:"vulnerabilities"`
+}
+
+// FixtureVulnerability is a synthetic vulnerability of a package in a namespace.
+type FixtureVulnerability struct {
+	ID string `yaml:"id"`
+	// Namespace is the DB namespace of the package (e.g. "github:language:python", "debian:distro:debian:12" or
+	// "nvd:cpe").
+	Namespace string `yaml:"namespace"`
+	Package   string `yaml:"package"`
+	// Constraint is the version range of the vulnerable versions (e.g. "< 2.31.0"), any version when empty.
+	Constraint string `yaml:"constraint"`
+	// VersionFormat is the format of the versions of the constraint, inferred from the namespace when empty.
+	VersionFormat string   `yaml:"version-format"`
+	FixedIn       []string `yaml:"fixed-in"`
+	// FixState is the fix state, "fixed" when fixed-in is given and "not-fixed" otherwise when empty.
+	FixState string   `yaml:"fix-state"`
+	CPEs     []string `yaml:"cpes"`
+	// Related are the related vulnerabilities, as "namespace:id" (e.g. "nvd:cpe:CVE-2023-32681").
+	Related     []string      `yaml:"related"`
+	Severity    string        `yaml:"severity"`
+	Description string        `yaml:"description"`
+	URLs        []string      `yaml:"urls"`
+	CVSS        []FixtureCVSS `yaml:"cvss"`
+	Published   *time.Time    `yaml:"published"`
+	Modified    *time.Time    `yaml:"modified"`
+}
+
+// FixtureCVSS is a CVSS score of a synthetic vulnerability.
+type FixtureCVSS struct {
+	Version string  `yaml:"version"`
+	Vector  string  `yaml:"vector"`
+	Score   float64 `yaml:"score"`
+}
+
+// ReadFixtureSpec reads the YAML spec of a fixture database, rejecting unknown fields (e.g. a misspelled field).
+func ReadFixtureSpec(path string) (*FixtureSpec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the fixture database spec: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	var spec FixtureSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("unable to parse the fixture database spec %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// BuildFixture builds the fixture database of the spec into the directory, as Build does: the database file, its
+// archive, and the listing of the archive when a base URL is given.
+func BuildFixture(spec FixtureSpec, dir, baseURL string) (*Result, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("no output directory for the vulnerability database")
+	}
+	vulns, metadata, err := spec.records()
+	if err != nil {
+		return nil, err
+	}
+	built := spec.Built
+	if built.IsZero() {
+		built = time.Now()
+	}
+	built = built.UTC().Truncate(time.Second)
+	log.WithFields("vulnerabilities", len(vulns), "metadata", len(metadata)).Debug("building fixture vulnerability database")
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create vulnerability database output directory: %w", err)
+	}
+	result := &Result{
+		DBPath:          filepath.Join(dir, v5.VulnerabilityStoreFileName),
+		Built:           built,
+		Vulnerabilities: len(vulns),
+		Metadata:        len(metadata),
+	}
+	if err := writeDB(result.DBPath, built, vulns, metadata); err != nil {
+		return nil, err
+	}
+	if result.ArchivePath, result.ListingPath, err = publish(result.DBPath, built, baseURL); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// records returns the vulnerability and metadata records of the spec, a metadata record per vulnerability ID and
+// namespace.
+func (s FixtureSpec) records() ([]v5.Vulnerability, []v5.VulnerabilityMetadata, error) {
+	if len(s.Vulnerabilities) == 0 {
+		return nil, nil, fmt.Errorf("the fixture database spec has no vulnerabilities")
+	}
+	var vulns []v5.Vulnerability
+	var metadata []v5.VulnerabilityMetadata
+	for i, fv := range s.Vulnerabilities {
+		v, m, err := fv.records()
+		if err != nil {
+			name := fv.ID
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, nil, fmt.Errorf("vulnerability %s: %w", name, err)
+		}
+		vulns = append(vulns, v)
+		metadata = append(metadata, m)
+	}
+	return vulns, uniqueMetadata(metadata), nil
+}
+
+func (fv FixtureVulnerability) records() (v5.Vulnerability, v5.VulnerabilityMetadata, error) {
+	switch {
+	case fv.ID == "":
+		return v5.Vulnerability{}, v5.VulnerabilityMetadata{}, fmt.Errorf("no id given")
+	case fv.Package == "":
+		return v5.Vulnerability{}, v5.VulnerabilityMetadata{}, fmt.Errorf("no package given")
+	}
+	if _, err := namespace.FromString(fv.Namespace); err != nil {
+		return v5.Vulnerability{}, v5.VulnerabilityMetadata{}, fmt.Errorf("invalid namespace %q: %w", fv.Namespace, err)
+	}
+
+	format := osv.NamespaceFormat(fv.Namespace)
+	if fv.VersionFormat != "" {
+		format = version.ParseFormat(fv.VersionFormat)
+		if format == version.UnknownFormat && !strings.EqualFold(fv.VersionFormat, version.UnknownFormat.String()) {
+			return v5.Vulnerability{}, v5.VulnerabilityMetadata{}, fmt.Errorf("unknown version format %q", fv.VersionFormat)
+		}
+	}
+	if _, err := version.GetConstraint(fv.Constraint, format); err != nil {
+		return v5.Vulnerability{}, v5.VulnerabilityMetadata{}, fmt.Errorf("invalid constraint %q for the %s version format: %w", fv.Constraint, format, err)
+	}
+
+	fix := v5.Fix{Versions: fv.FixedIn, State: v5.NotFixedState}
+	if len(fv.FixedIn) > 0 {
+		fix.State = v5.FixedState
+	}
+	if fv.FixState != "" {
+		fix.State = v5.FixState(strings.ToLower(fv.FixState))
+		switch fix.State {
+		case v5.FixedState, v5.NotFixedState, v5.WontFixState, v5.UnknownFixState:
+		default:
+			return v5.Vulnerability{}, v5.VulnerabilityMetadata{}, fmt.Errorf("unknown fix state %q", fv.FixState)
+		}
+	}
+
+	var related []v5.VulnerabilityReference
+	for _, r := range fv.Related {
+		i := strings.LastIndex(r, ":")
+		if i <= 0 || i == len(r)-1 {
+			return v5.Vulnerability{}, v5.VulnerabilityMetadata{}, fmt.Errorf("invalid related vulnerability %q (expected namespace:id)", r)
+		}
+		related = append(related, v5.VulnerabilityReference{Namespace: r[:i], ID: r[i+1:]})
+	}
+
+	severity := fv.Severity
+	if severity == "" {
+		severity = "Unknown"
+	}
+	m := v5.VulnerabilityMetadata{
+		ID:           fv.ID,
+		Namespace:    fv.Namespace,
+		DataSource:   "fixture",
+		RecordSource: "fixture",
+		Severity:     severity,
+		URLs:         fv.URLs,
+		Description:  fv.Description,
+		Published:    fv.Published,
+		Modified:     fv.Modified,
+	}
+	for _, c := range fv.CVSS {
+		m.Cvss = append(m.Cvss, v5.Cvss{
+			Metrics: v5.CvssMetrics{BaseScore: c.Score},
+			Vector:  c.Vector,
+			Version: c.Version,
+			Type:    "Primary",
+		})
+	}
+
+	return v5.Vulnerability{
+		ID:                     fv.ID,
+		PackageName:            fv.Package,
+		Namespace:              fv.Namespace,
+		VersionConstraint:      fv.Constraint,
+		VersionFormat:          strings.ToLower(format.String()),
+		CPEs:                   fv.CPEs,
+		RelatedVulnerabilities: related,
+		Fix:                    fix,
+	}, m, nil
+}
@@ -0,0 +1,133 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store"
+)
+
+func TestBuildFixture(t *testing.T) {
+	spec, err := ReadFixtureSpec("test-fixtures/fixture/spec.yaml")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	result, err := BuildFixture(*spec, dir, "https://db.example.com/grype")
+	require.NoError(t, err)
+	assert.Equal(t, &Result{
+		DBPath:          filepath.Join(dir, "vulnerability.db"),
+		ArchivePath:     filepath.Join(dir, "vulnerability-db_v5_2024-03-01T12:00:00Z.tar.gz"),
+		ListingPath:     filepath.Join(dir, "listing.json"),
+		Built:           testBuilt,
+		Vulnerabilities: 3,
+		Metadata:        3,
+	}, result)
+
+	s, err := store.New(result.DBPath, false)
+	require.NoError(t, err)
+
+	python, err := s.SearchForVulnerabilities("github:language:python", "requests")
+	require.NoError(t, err)
+	require.Len(t, python, 1)
+	assert.Equal(t, "< 2.31.0", python[0].VersionConstraint)
+	assert.Equal(t, "python", python[0].VersionFormat)
+	assert.Equal(t, v5.Fix{Versions: []string{"2.31.0"}, State: v5.FixedState}, python[0].Fix)
+	assert.Equal(t, []v5.VulnerabilityReference{{ID: "CVE-2023-32681", Namespace: "nvd:cpe"}}, python[0].RelatedVulnerabilities)
+
+	debian, err := s.SearchForVulnerabilities("debian:distro:debian:12", "openssl")
+	require.NoError(t, err)
+	require.Len(t, debian, 1)
+	assert.Equal(t, "deb", debian[0].VersionFormat)
+	assert.Equal(t, v5.WontFixState, debian[0].Fix.State)
+
+	metadata, err := s.GetVulnerabilityMetadata("GHSA-fixture-0001", "github:language:python")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "Medium", metadata.Severity)
+	require.Len(t, metadata.Cvss, 1)
+	assert.Equal(t, 6.1, metadata.Cvss[0].Metrics.BaseScore)
+
+	unknown, err := s.GetVulnerabilityMetadata("CVE-2024-0001", "debian:distro:debian:12")
+	require.NoError(t, err)
+	require.NotNil(t, unknown)
+	assert.Equal(t, "Unknown", unknown.Severity)
+
+	// building again in the same directory replaces the database
+	s.Close()
+	_, err = BuildFixture(*spec, dir, "")
+	require.NoError(t, err)
+	s2, err := store.New(result.DBPath, false)
+	require.NoError(t, err)
+	defer s2.Close()
+	python, err = s2.SearchForVulnerabilities("github:language:python", "requests")
+	require.NoError(t, err)
+	assert.Len(t, python, 1)
+}
+
+func TestBuildFixture_invalid(t *testing.T) {
+	valid := FixtureVulnerability{ID: "GHSA-1", Namespace: "github:language:go", Package: "example.com/mod", Constraint: "< 1.2.0"}
+	tests := []struct {
+		name    string
+		mutate  func(*FixtureVulnerability)
+		wantErr string
+	}{
+		{
+			name:    "no id",
+			mutate:  func(v *FixtureVulnerability) { v.ID = "" },
+			wantErr: "vulnerability #1: no id given",
+		},
+		{
+			name:    "no package",
+			mutate:  func(v *FixtureVulnerability) { v.Package = "" },
+			wantErr: "vulnerability GHSA-1: no package given",
+		},
+		{
+			name:    "invalid namespace",
+			mutate:  func(v *FixtureVulnerability) { v.Namespace = "github" },
+			wantErr: `invalid namespace "github"`,
+		},
+		{
+			name:    "unknown version format",
+			mutate:  func(v *FixtureVulnerability) { v.VersionFormat = "calver" },
+			wantErr: `unknown version format "calver"`,
+		},
+		{
+			name:    "invalid constraint",
+			mutate:  func(v *FixtureVulnerability) { v.Constraint = "<< 1.2.0" },
+			wantErr: `invalid constraint "<< 1.2.0"`,
+		},
+		{
+			name:    "unknown fix state",
+			mutate:  func(v *FixtureVulnerability) { v.FixState = "maybe" },
+			wantErr: `unknown fix state "maybe"`,
+		},
+		{
+			name:    "invalid related vulnerability",
+			mutate:  func(v *FixtureVulnerability) { v.Related = []string{"CVE-2024-1"} },
+			wantErr: `invalid related vulnerability "CVE-2024-1"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := valid
+			tt.mutate(&v)
+			_, err := BuildFixture(FixtureSpec{Vulnerabilities: []FixtureVulnerability{v}}, t.TempDir(), "")
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	_, err := BuildFixture(FixtureSpec{}, t.TempDir(), "")
+	require.ErrorContains(t, err, "the fixture database spec has no vulnerabilities")
+}
+
+func TestReadFixtureSpec_unknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("vulnerabilities:\n  - id: GHSA-1\n    fixed_in: [1.0.0]\n"), 0o600))
+	_, err := ReadFixtureSpec(path)
+	require.ErrorContains(t, err, "field fixed_in not found")
+}
@@ -0,0 +1,217 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mholt/archiver/v3"
+	"github.com/spf13/afero"
+
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store"
+	"github.com/anchore/grype/internal/file"
+	"github.com/anchore/grype/internal/log"
+)
+
+// MergeConfig is the configuration of the merge of an overlay database over a base database.
+type MergeConfig struct {
+	// Base and Overlay are the databases to merge, each a database file, a directory holding a database file (such as
+	// the database directory of a curator), or a database archive.
+	Base    string
+	Overlay string
+	// Dir is the directory to write the merged database, its archive and the listing of the archive to.
+	Dir string
+	// Built is the build time of the merged database, that of the base database when zero (so that a newer base
+	// database still supersedes the merged database when updating).
+	Built time.Time
+	// BaseURL is the URL the archive is served from, for the listing of the archive. No listing is written when empty.
+	BaseURL string
+}
+
+// MergeResult describes the merge of an overlay database over a base database.
+type MergeResult struct {
+	// DBPath is the path of the merged database file.
+	DBPath string
+	// ArchivePath is the path of the archive of the merged database, as served to curators.
+	ArchivePath string
+	// ListingPath is the path of the listing of the archive, empty when no listing was written.
+	ListingPath string
+	// Built is the build time of the merged database.
+	Built time.Time
+	// Stats counts the records of the overlay merged into the base database.
+	Stats store.MergeStats
+}
+
+// Merge layers the records of the overlay database over the records of the base database (see store.Merge), writing
+// the merged database, its archive, and the listing of the archive when a base URL is given. Both databases must be of
+// the schema of this version of grype, and the merged database is validated before being archived. The sidecar
+// datasets shipped with the base database (such as the malware dataset) are shipped with the merged database; those
+// of the overlay are not merged.
+func Merge(cfg MergeConfig) (*MergeResult, error) {
+	if cfg.Base == "" || cfg.Overlay == "" {
+		return nil, fmt.Errorf("a base and an overlay vulnerability database are required")
+	}
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("no output directory for the vulnerability database")
+	}
+	tempDir, err := os.MkdirTemp("", "grype-db-merge")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temp dir for the vulnerability database merge: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	basePath, baseID, err := dbFile(cfg.Base, filepath.Join(tempDir, "base"))
+	if err != nil {
+		return nil, fmt.Errorf("base vulnerability database: %w", err)
+	}
+	overlayPath, _, err := dbFile(cfg.Overlay, filepath.Join(tempDir, "overlay"))
+	if err != nil {
+		return nil, fmt.Errorf("overlay vulnerability database: %w", err)
+	}
+	built := cfg.Built
+	if built.IsZero() {
+		built = baseID.BuildTimestamp
+	}
+	built = built.UTC().Truncate(time.Second)
+
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create vulnerability database output directory: %w", err)
+	}
+	result := &MergeResult{
+		DBPath: filepath.Join(cfg.Dir, v5.VulnerabilityStoreFileName),
+		Built:  built,
+	}
+	if samePath(basePath, result.DBPath) {
+		return nil, fmt.Errorf("the merged vulnerability database would replace the base vulnerability database")
+	}
+	if err := copyFile(basePath, result.DBPath); err != nil {
+		return nil, fmt.Errorf("unable to copy base vulnerability database: %w", err)
+	}
+	if err := copySidecars(filepath.Dir(basePath), cfg.Dir); err != nil {
+		return nil, fmt.Errorf("unable to copy the datasets of the base vulnerability database: %w", err)
+	}
+	if names := sidecars(filepath.Dir(overlayPath)); len(names) > 0 {
+		log.WithFields("datasets", names).Warn("the datasets shipped with the overlay vulnerability database are not merged")
+	}
+
+	stats, err := store.Merge(result.DBPath, overlayPath, v5.NewID(built))
+	if err != nil {
+		return nil, err
+	}
+	result.Stats = *stats
+	if _, err := validateDB(result.DBPath); err != nil {
+		return nil, fmt.Errorf("merged vulnerability database: %w", err)
+	}
+
+	if result.ArchivePath, result.ListingPath, err = publish(result.DBPath, built, cfg.BaseURL); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// dbFile returns the path of the database file of the given database file, directory or archive (unarchived to the
+// given directory) along with its ID, once validated.
+func dbFile(path, unarchiveDir string) (string, *v5.ID, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, err
+	}
+	switch {
+	case info.IsDir():
+		path = filepath.Join(path, v5.VulnerabilityStoreFileName)
+	case !strings.EqualFold(filepath.Ext(path), ".db"):
+		if err := archiver.Unarchive(path, unarchiveDir); err != nil {
+			return "", nil, fmt.Errorf("unable to unarchive %s: %w", path, err)
+		}
+		path = filepath.Join(unarchiveDir, v5.VulnerabilityStoreFileName)
+	}
+	id, err := validateDB(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, id, nil
+}
+
+// validateDB checks that the database file can be read and is of the schema of this version of grype, returning its
+// ID.
+func validateDB(path string) (*v5.ID, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	s, err := store.New(path, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s: %w", path, err)
+	}
+	defer s.Close()
+
+	id, err := s.GetID()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the ID of %s: %w", path, err)
+	}
+	if id == nil {
+		return nil, fmt.Errorf("%s has no ID", path)
+	}
+	if id.SchemaVersion != v5.SchemaVersion {
+		return nil, fmt.Errorf("%s is of schema v%d, not of the supported schema v%d", path, id.SchemaVersion, v5.SchemaVersion)
+	}
+	if _, err := s.GetVulnerabilityNamespaces(); err != nil {
+		return nil, fmt.Errorf("unable to read the vulnerabilities of %s: %w", path, err)
+	}
+	return id, nil
+}
+
+// copySidecars replaces the sidecar datasets of the destination database directory with those of the source database
+// directory.
+func copySidecars(from, to string) error {
+	fs := afero.NewOsFs()
+	for _, name := range sidecarDatasets {
+		if err := os.RemoveAll(filepath.Join(to, name)); err != nil {
+			return err
+		}
+	}
+	for _, name := range sidecars(from) {
+		src, dst := filepath.Join(from, name), filepath.Join(to, name)
+		info, err := os.Stat(src)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			err = file.CopyDir(fs, src, dst)
+		} else {
+			err = file.CopyFile(fs, src, dst)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+func copyFile(from, to string) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,149 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/baseimage"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store"
+	"github.com/anchore/grype/grype/malware"
+	"github.com/anchore/grype/grype/pending"
+)
+
+func TestMerge(t *testing.T) {
+	base, err := Build(Config{
+		Inputs: []string{"test-fixtures/advisories"},
+		Dir:    t.TempDir(),
+		Built:  testBuilt,
+	})
+	require.NoError(t, err)
+
+	overlayPath := filepath.Join(t.TempDir(), "overlay.db")
+	o, err := store.New(overlayPath, true)
+	require.NoError(t, err)
+	require.NoError(t, o.SetID(v5.NewID(testBuilt)))
+	require.NoError(t, o.AddVulnerabilityMetadata(v5.VulnerabilityMetadata{ID: "CVE-2024-0001", Namespace: "nvd:cpe", Severity: "Medium"}))
+	require.NoError(t, o.AddVulnerabilityMatchExclusion(v5.VulnerabilityMatchExclusion{ID: "CVE-2024-0001", Justification: "not exploitable in our builds"}))
+	o.Close()
+
+	dir := t.TempDir()
+	result, err := Merge(MergeConfig{
+		// the base database is given as an archive, the overlay as a database file
+		Base:    base.ArchivePath,
+		Overlay: overlayPath,
+		Dir:     dir,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testBuilt, result.Built)
+	assert.Equal(t, filepath.Join(dir, "vulnerability.db"), result.DBPath)
+	assert.Equal(t, store.MergeStats{Metadata: 1, OverriddenMetadata: 1, Exclusions: 1}, result.Stats)
+	assert.Empty(t, result.ListingPath)
+
+	curator, err := distribution.NewCurator(distribution.Config{DBRootDir: t.TempDir()})
+	require.NoError(t, err)
+	require.NoError(t, curator.ImportFrom(result.ArchivePath))
+
+	reader, closer, err := curator.GetStore()
+	require.NoError(t, err)
+	defer closer.Close()
+
+	metadata, err := reader.GetVulnerabilityMetadata("CVE-2024-0001", "nvd:cpe")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "Medium", metadata.Severity)
+	assert.Equal(t, "internal-lib before 1.4.2 allows remote code execution.", metadata.Description)
+
+	exclusions, err := reader.GetVulnerabilityMatchExclusion("CVE-2024-0001")
+	require.NoError(t, err)
+	assert.Len(t, exclusions, 1)
+}
+
+func TestMerge_errors(t *testing.T) {
+	base, err := Build(Config{
+		Inputs: []string{"test-fixtures/advisories"},
+		Dir:    t.TempDir(),
+		Built:  testBuilt,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		cfg     MergeConfig
+		wantErr string
+	}{
+		{
+			name:    "no overlay",
+			cfg:     MergeConfig{Base: base.DBPath, Dir: t.TempDir()},
+			wantErr: "a base and an overlay vulnerability database are required",
+		},
+		{
+			name:    "missing overlay",
+			cfg:     MergeConfig{Base: base.DBPath, Overlay: "test-fixtures/missing.db", Dir: t.TempDir()},
+			wantErr: "overlay vulnerability database",
+		},
+		{
+			name:    "replacing the base",
+			cfg:     MergeConfig{Base: filepath.Dir(base.DBPath), Overlay: base.DBPath, Dir: filepath.Dir(base.DBPath)},
+			wantErr: "would replace the base vulnerability database",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Merge(tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMerge_overItself(t *testing.T) {
+	// the records of the database vary in which of their nullable fields are set (CPEs, related vulnerabilities)
+	db, err := Build(Config{
+		Inputs: []string{"test-fixtures/advisories"},
+		Dir:    t.TempDir(),
+		Built:  testBuilt,
+	})
+	require.NoError(t, err)
+
+	result, err := Merge(MergeConfig{Base: db.DBPath, Overlay: db.DBPath, Dir: t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, db.Vulnerabilities, result.Stats.Vulnerabilities)
+	assert.Equal(t, db.Vulnerabilities, result.Stats.ReplacedVulnerabilities)
+	assert.Equal(t, db.Metadata, result.Stats.OverriddenMetadata)
+}
+
+func TestMerge_sidecars(t *testing.T) {
+	baseDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, pending.DBFileName), []byte(`[]`), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, malware.DBDirName, "npm"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, malware.DBDirName, "npm", "MAL-2024-1.json"), []byte(`{"id": "MAL-2024-1"}`), 0o600))
+	base, err := Build(Config{
+		Inputs: []string{"test-fixtures/advisories"},
+		Dir:    baseDir,
+		Built:  testBuilt,
+	})
+	require.NoError(t, err)
+
+	// the merged directory holds a dataset that the base database does not ship anymore
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, baseimage.DBDirName), 0o755))
+
+	result, err := Merge(MergeConfig{Base: base.ArchivePath, Overlay: base.DBPath, Dir: dir})
+	require.NoError(t, err)
+
+	curator, err := distribution.NewCurator(distribution.Config{DBRootDir: t.TempDir()})
+	require.NoError(t, err)
+	require.NoError(t, curator.ImportFrom(result.ArchivePath))
+
+	location := curator.Status().Location
+	assert.FileExists(t, filepath.Join(location, pending.DBFileName))
+	contents, err := os.ReadFile(filepath.Join(location, malware.DBDirName, "npm", "MAL-2024-1.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "MAL-2024-1"}`, string(contents))
+	assert.NoDirExists(t, filepath.Join(location, baseimage.DBDirName))
+}
@@ -0,0 +1,267 @@
+package build
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/internal/stringutil"
+	"github.com/anchore/syft/syft/cpe"
+)
+
+const nvdNamespace = "nvd:cpe"
+
+// nvdResponse is a response of the NVD CVE API (2.0), as saved from the API or found in the NVD JSON feeds.
+type nvdResponse struct {
+	Vulnerabilities []nvdItem `json:"vulnerabilities"`
+}
+
+type nvdItem struct {
+	CVE nvdCVE `json:"cve"`
+}
+
+// nvdCVE is a CVE record of the NVD CVE API (2.0), limited to the fields used for matching and for metadata.
+type nvdCVE struct {
+	ID             string             `json:"id"`
+	VulnStatus     string             `json:"vulnStatus,omitempty"`
+	Published      string             `json:"published,omitempty"`
+	LastModified   string             `json:"lastModified,omitempty"`
+	Descriptions   []nvdDescription   `json:"descriptions,omitempty"`
+	Metrics        nvdMetrics         `json:"metrics,omitempty"`
+	Configurations []nvdConfiguration `json:"configurations,omitempty"`
+	References     []nvdReference     `json:"references,omitempty"`
+}
+
+type nvdDescription struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+type nvdMetrics struct {
+	CvssMetricV31 []nvdCvssMetric `json:"cvssMetricV31,omitempty"`
+	CvssMetricV30 []nvdCvssMetric `json:"cvssMetricV30,omitempty"`
+	CvssMetricV2  []nvdCvssMetric `json:"cvssMetricV2,omitempty"`
+}
+
+type nvdCvssMetric struct {
+	Source              string      `json:"source"`
+	Type                string      `json:"type"`
+	CvssData            nvdCvssData `json:"cvssData"`
+	BaseSeverity        string      `json:"baseSeverity,omitempty"`
+	ExploitabilityScore *float64    `json:"exploitabilityScore,omitempty"`
+	ImpactScore         *float64    `json:"impactScore,omitempty"`
+}
+
+type nvdCvssData struct {
+	Version      string  `json:"version"`
+	VectorString string  `json:"vectorString"`
+	BaseScore    float64 `json:"baseScore"`
+	BaseSeverity string  `json:"baseSeverity,omitempty"`
+}
+
+type nvdConfiguration struct {
+	Nodes []nvdNode `json:"nodes"`
+}
+
+type nvdNode struct {
+	Operator string        `json:"operator"`
+	Negate   bool          `json:"negate,omitempty"`
+	CPEMatch []nvdCPEMatch `json:"cpeMatch"`
+}
+
+type nvdCPEMatch struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	Criteria              string `json:"criteria"`
+	VersionStartIncluding string `json:"versionStartIncluding,omitempty"`
+	VersionStartExcluding string `json:"versionStartExcluding,omitempty"`
+	VersionEndIncluding   string `json:"versionEndIncluding,omitempty"`
+	VersionEndExcluding   string `json:"versionEndExcluding,omitempty"`
+}
+
+type nvdReference struct {
+	URL string `json:"url"`
+}
+
+// nvdProduct is what is known of the vulnerable versions of a single product of a CVE.
+type nvdProduct struct {
+	name        string
+	cpes        []string
+	constraints []string
+}
+
+// fromNVD converts the CVE records into DB records: a vulnerability record per vulnerable product (keyed by the CPE
+// product name, with the CPEs of the product and the version ranges of the product) and a metadata record per CVE.
+// Rejected CVEs and CVEs without vulnerable CPEs are skipped.
+func fromNVD(records []nvdCVE) ([]v5.Vulnerability, []v5.VulnerabilityMetadata, error) {
+	var vulns []v5.Vulnerability
+	var metadata []v5.VulnerabilityMetadata
+	for _, r := range records {
+		if strings.EqualFold(r.VulnStatus, "Rejected") {
+			continue
+		}
+
+		products, err := nvdProducts(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", r.ID, err)
+		}
+		if len(products) == 0 {
+			continue
+		}
+		for _, p := range products {
+			vulns = append(vulns, v5.Vulnerability{
+				ID:                r.ID,
+				PackageName:       p.name,
+				Namespace:         nvdNamespace,
+				VersionConstraint: strings.Join(p.constraints, " || "),
+				VersionFormat:     strings.ToLower(version.UnknownFormat.String()),
+				CPEs:              p.cpes,
+				Fix:               v5.Fix{State: v5.UnknownFixState},
+			})
+		}
+		metadata = append(metadata, nvdMetadata(r))
+	}
+	return vulns, metadata, nil
+}
+
+// nvdProducts returns the vulnerable products of the CVE, in the order of their names. Every version of a product is
+// vulnerable when a criteria of the product carries no version range nor version.
+func nvdProducts(r nvdCVE) ([]nvdProduct, error) {
+	products := make(map[string]*nvdProduct)
+	for _, c := range r.Configurations {
+		for _, n := range c.Nodes {
+			if n.Negate {
+				continue
+			}
+			for _, m := range n.CPEMatch {
+				if !m.Vulnerable {
+					continue
+				}
+				attrs, err := cpe.NewAttributes(m.Criteria)
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse CPE %q: %w", m.Criteria, err)
+				}
+				constraint := nvdConstraint(m, attrs.Version)
+				attrs.Version = cpe.Any
+				attrs.Update = cpe.Any
+
+				p, ok := products[attrs.Product]
+				if !ok {
+					p = &nvdProduct{name: attrs.Product}
+					products[attrs.Product] = p
+				}
+				p.cpes = stringutil.AppendUnique(p.cpes, attrs.BindToFmtString())
+				p.constraints = stringutil.AppendUnique(p.constraints, constraint)
+			}
+		}
+	}
+
+	var results []nvdProduct
+	for _, p := range products {
+		for _, constraint := range p.constraints {
+			if constraint == "" {
+				p.constraints = nil
+				break
+			}
+		}
+		results = append(results, *p)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].name < results[j].name
+	})
+	return results, nil
+}
+
+// nvdConstraint returns the version range of the CPE match (e.g. ">= 1.0, < 1.2"), the single version of the criteria
+// when there is no range, or an empty constraint when every version is vulnerable.
+func nvdConstraint(m nvdCPEMatch, criteriaVersion string) string {
+	var clauses []string
+	switch {
+	case m.VersionStartIncluding != "":
+		clauses = append(clauses, ">= "+m.VersionStartIncluding)
+	case m.VersionStartExcluding != "":
+		clauses = append(clauses, "> "+m.VersionStartExcluding)
+	}
+	switch {
+	case m.VersionEndIncluding != "":
+		clauses = append(clauses, "<= "+m.VersionEndIncluding)
+	case m.VersionEndExcluding != "":
+		clauses = append(clauses, "< "+m.VersionEndExcluding)
+	}
+	if len(clauses) > 0 {
+		return strings.Join(clauses, ", ")
+	}
+	if criteriaVersion != cpe.Any && criteriaVersion != "" && criteriaVersion != "-" {
+		return "= " + criteriaVersion
+	}
+	return ""
+}
+
+// nvdMetadata describes the CVE with its English description, its references and its CVSS metrics, rated by the
+// most recent CVSS version available.
+func nvdMetadata(r nvdCVE) v5.VulnerabilityMetadata {
+	m := v5.VulnerabilityMetadata{
+		ID:         r.ID,
+		Namespace:  nvdNamespace,
+		DataSource: "https://nvd.nist.gov/vuln/detail/" + r.ID,
+		Severity:   "Unknown",
+		Published:  nvdTime(r.Published),
+		Modified:   nvdTime(r.LastModified),
+	}
+	for _, d := range r.Descriptions {
+		if d.Lang == "en" {
+			m.Description = d.Value
+			break
+		}
+	}
+	for _, ref := range r.References {
+		m.URLs = stringutil.AppendUnique(m.URLs, ref.URL)
+	}
+
+	rated := false
+	for _, metrics := range [][]nvdCvssMetric{r.Metrics.CvssMetricV31, r.Metrics.CvssMetricV30, r.Metrics.CvssMetricV2} {
+		for _, c := range metrics {
+			m.Cvss = append(m.Cvss, v5.Cvss{
+				Source:  c.Source,
+				Type:    c.Type,
+				Version: c.CvssData.Version,
+				Vector:  c.CvssData.VectorString,
+				Metrics: v5.CvssMetrics{
+					BaseScore:           c.CvssData.BaseScore,
+					ExploitabilityScore: c.ExploitabilityScore,
+					ImpactScore:         c.ImpactScore,
+				},
+			})
+			if rated || c.Type != "Primary" {
+				continue
+			}
+			severity := c.CvssData.BaseSeverity
+			if severity == "" {
+				// CVSS v2 metrics carry the severity outside of the CVSS data
+				severity = c.BaseSeverity
+			}
+			if severity != "" {
+				m.Severity = strings.ToUpper(severity[:1]) + strings.ToLower(severity[1:])
+				rated = true
+			}
+		}
+	}
+	return m
+}
+
+// nvdTime parses a timestamp of the NVD CVE API, which is in UTC without a time zone (e.g. "2024-03-01T10:15:09.143"),
+// returning nil when it is missing or malformed.
+func nvdTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, s); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package build
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNVDConstraint(t *testing.T) {
+	tests := []struct {
+		name     string
+		match    nvdCPEMatch
+		version  string
+		expected string
+	}{
+		{
+			name:     "range",
+			match:    nvdCPEMatch{VersionStartIncluding: "1.0", VersionEndExcluding: "1.2"},
+			expected: ">= 1.0, < 1.2",
+		},
+		{
+			name:     "exclusive start and inclusive end",
+			match:    nvdCPEMatch{VersionStartExcluding: "1.0", VersionEndIncluding: "1.2"},
+			expected: "> 1.0, <= 1.2",
+		},
+		{
+			name:     "single version",
+			version:  "2.4.1",
+			expected: "= 2.4.1",
+		},
+		{
+			name: "every version",
+		},
+		{
+			name:    "not applicable version",
+			version: "-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nvdConstraint(tt.match, tt.version))
+		})
+	}
+}
+
+func TestNVDProducts_everyVersion(t *testing.T) {
+	products, err := nvdProducts(nvdCVE{
+		ID: "CVE-2024-0003",
+		Configurations: []nvdConfiguration{{Nodes: []nvdNode{{CPEMatch: []nvdCPEMatch{
+			{Vulnerable: true, Criteria: "cpe:2.3:a:example:tool:*:*:*:*:*:*:*:*", VersionEndExcluding: "3.0"},
+			{Vulnerable: true, Criteria: "cpe:2.3:a:example:tool:*:*:*:*:*:*:*:*"},
+		}}}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	// an unbounded criteria makes every version of the product vulnerable
+	assert.Empty(t, products[0].constraints)
+}
+
+func TestNVDMetadata_v2Severity(t *testing.T) {
+	m := nvdMetadata(nvdCVE{
+		ID:           "CVE-2014-0001",
+		Published:    "2014-01-02T10:15:09.143",
+		LastModified: "2014-02-03T00:00:00Z",
+		Metrics: nvdMetrics{CvssMetricV2: []nvdCvssMetric{{
+			Source:       "nvd@nist.gov",
+			Type:         "Primary",
+			CvssData:     nvdCvssData{Version: "2.0", VectorString: "AV:N/AC:L/Au:N/C:P/I:P/A:P", BaseScore: 7.5},
+			BaseSeverity: "HIGH",
+		}}},
+	})
+	assert.Equal(t, "High", m.Severity)
+	require.Len(t, m.Cvss, 1)
+	assert.Equal(t, "2.0", m.Cvss[0].Version)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2014-0001", m.DataSource)
+	require.NotNil(t, m.Published)
+	assert.Equal(t, time.Date(2014, 1, 2, 10, 15, 9, 143000000, time.UTC), *m.Published)
+	require.NotNil(t, m.Modified)
+	assert.Equal(t, time.Date(2014, 2, 3, 0, 0, 0, 0, time.UTC), *m.Modified)
+}
@@ -0,0 +1,211 @@
+/*
+Package dbtest provides in-memory vulnerability databases built programmatically, for testing matchers and
+integrations without the fixtures of complete databases, along with a fake distribution server serving them to a
+curator.
+
+The databases are of the schema of the vulnerability databases used for matching (v5), since the v6 schema does not
+yet hold vulnerability records.
+*/
+package dbtest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anchore/grype/grype/db"
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store"
+	grypeStore "github.com/anchore/grype/grype/store"
+)
+
+var (
+	_ grypeDB.StoreReader                 = (*DB)(nil)
+	_ grypeDB.PackageTargetingStoreReader = (*DB)(nil)
+)
+
+// DB is an in-memory vulnerability database. It is not safe for concurrent writes, but may be read concurrently once
+// built.
+type DB struct {
+	built           time.Time
+	vulnerabilities map[string]map[string][]grypeDB.Vulnerability
+	metadata        map[string]map[string]grypeDB.VulnerabilityMetadata
+	exclusions      map[string][]grypeDB.VulnerabilityMatchExclusion
+	targeting       []grypeDB.PackageTargeting
+}
+
+// New returns an empty database, built at the given time.
+func New(built time.Time) *DB {
+	return &DB{
+		built:           built.UTC(),
+		vulnerabilities: make(map[string]map[string][]grypeDB.Vulnerability),
+		metadata:        make(map[string]map[string]grypeDB.VulnerabilityMetadata),
+		exclusions:      make(map[string][]grypeDB.VulnerabilityMatchExclusion),
+	}
+}
+
+// Built returns the build time of the database.
+func (d *DB) Built() time.Time {
+	return d.built
+}
+
+// AddVulnerability adds the given vulnerability records, keyed by their namespace and package name. A record without
+// a namespace is filed under the namespace of NVD CPE records.
+func (d *DB) AddVulnerability(vulnerabilities ...grypeDB.Vulnerability) *DB {
+	for _, v := range vulnerabilities {
+		if v.Namespace == "" {
+			v.Namespace = "nvd:cpe"
+		}
+		if _, ok := d.vulnerabilities[v.Namespace]; !ok {
+			d.vulnerabilities[v.Namespace] = make(map[string][]grypeDB.Vulnerability)
+		}
+		d.vulnerabilities[v.Namespace][v.PackageName] = append(d.vulnerabilities[v.Namespace][v.PackageName], v)
+	}
+	return d
+}
+
+// AddVulnerabilityMetadata adds the given metadata records, replacing any existing record of the same ID and
+// namespace.
+func (d *DB) AddVulnerabilityMetadata(metadata ...grypeDB.VulnerabilityMetadata) *DB {
+	for _, m := range metadata {
+		if _, ok := d.metadata[m.ID]; !ok {
+			d.metadata[m.ID] = make(map[string]grypeDB.VulnerabilityMetadata)
+		}
+		d.metadata[m.ID][m.Namespace] = m
+	}
+	return d
+}
+
+// AddVulnerabilityMatchExclusion adds the given match exclusions.
+func (d *DB) AddVulnerabilityMatchExclusion(exclusions ...grypeDB.VulnerabilityMatchExclusion) *DB {
+	for _, e := range exclusions {
+		d.exclusions[e.ID] = append(d.exclusions[e.ID], e)
+	}
+	return d
+}
+
+// AddPackageTargeting adds the given per-distro package targeting defaults.
+func (d *DB) AddPackageTargeting(targeting ...grypeDB.PackageTargeting) *DB {
+	d.targeting = append(d.targeting, targeting...)
+	return d
+}
+
+// Store returns the providers of the database, as used by the vulnerability matcher.
+func (d *DB) Store() (*grypeStore.Store, error) {
+	p, err := db.NewVulnerabilityProvider(d)
+	if err != nil {
+		return nil, err
+	}
+	return &grypeStore.Store{
+		Provider:          p,
+		MetadataProvider:  db.NewVulnerabilityMetadataProvider(d),
+		ExclusionProvider: db.NewMatchExclusionProvider(d),
+		Targeting:         db.NewPackageTargetingProvider(d),
+	}, nil
+}
+
+// Write writes the database as a SQLite vulnerability database file to the given path, replacing any existing file.
+func (d *DB) Write(path string) error {
+	s, err := store.New(path, true)
+	if err != nil {
+		return fmt.Errorf("unable to create vulnerability database: %w", err)
+	}
+	defer s.Close()
+
+	if err := s.SetID(grypeDB.NewID(d.built)); err != nil {
+		return fmt.Errorf("unable to write vulnerability database ID: %w", err)
+	}
+	vulns, _ := d.GetAllVulnerabilities()
+	if err := s.AddVulnerability(*vulns...); err != nil {
+		return fmt.Errorf("unable to write vulnerabilities: %w", err)
+	}
+	metadata, _ := d.GetAllVulnerabilityMetadata()
+	if err := s.AddVulnerabilityMetadata(*metadata...); err != nil {
+		return fmt.Errorf("unable to write vulnerability metadata: %w", err)
+	}
+	for _, id := range sortedKeys(d.exclusions) {
+		if err := s.AddVulnerabilityMatchExclusion(d.exclusions[id]...); err != nil {
+			return fmt.Errorf("unable to write vulnerability match exclusions: %w", err)
+		}
+	}
+	if writer, ok := s.(grypeDB.PackageTargetingStoreWriter); ok && len(d.targeting) > 0 {
+		if err := writer.AddPackageTargeting(d.targeting...); err != nil {
+			return fmt.Errorf("unable to write package targeting: %w", err)
+		}
+	}
+	return nil
+}
+
+func (d *DB) GetID() (*grypeDB.ID, error) {
+	id := grypeDB.NewID(d.built)
+	return &id, nil
+}
+
+func (d *DB) GetVulnerabilityNamespaces() ([]string, error) {
+	return sortedKeys(d.vulnerabilities), nil
+}
+
+func (d *DB) GetVulnerability(namespace, id string) ([]grypeDB.Vulnerability, error) {
+	var results []grypeDB.Vulnerability
+	for _, name := range sortedKeys(d.vulnerabilities[namespace]) {
+		for _, v := range d.vulnerabilities[namespace][name] {
+			if v.ID == id {
+				results = append(results, v)
+			}
+		}
+	}
+	return results, nil
+}
+
+func (d *DB) SearchForVulnerabilities(namespace, packageName string) ([]grypeDB.Vulnerability, error) {
+	return d.vulnerabilities[namespace][packageName], nil
+}
+
+func (d *DB) GetAllVulnerabilities() (*[]grypeDB.Vulnerability, error) {
+	var all []grypeDB.Vulnerability
+	for _, namespace := range sortedKeys(d.vulnerabilities) {
+		for _, name := range sortedKeys(d.vulnerabilities[namespace]) {
+			all = append(all, d.vulnerabilities[namespace][name]...)
+		}
+	}
+	return &all, nil
+}
+
+func (d *DB) GetVulnerabilityMetadata(id, namespace string) (*grypeDB.VulnerabilityMetadata, error) {
+	m, ok := d.metadata[id][namespace]
+	if !ok {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+func (d *DB) GetAllVulnerabilityMetadata() (*[]grypeDB.VulnerabilityMetadata, error) {
+	var all []grypeDB.VulnerabilityMetadata
+	for _, id := range sortedKeys(d.metadata) {
+		for _, namespace := range sortedKeys(d.metadata[id]) {
+			all = append(all, d.metadata[id][namespace])
+		}
+	}
+	return &all, nil
+}
+
+func (d *DB) GetVulnerabilityMatchExclusion(id string) ([]grypeDB.VulnerabilityMatchExclusion, error) {
+	return d.exclusions[id], nil
+}
+
+func (d *DB) GetPackageTargeting() ([]grypeDB.PackageTargeting, error) {
+	return d.targeting, nil
+}
+
+func (d *DB) DiffStore(grypeDB.StoreReader) (*[]grypeDB.Diff, error) {
+	return nil, fmt.Errorf("diffing in-memory vulnerability databases is not supported")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+package dbtest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype"
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/linux"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+var built = time.Date(2024, 6, 13, 17, 13, 13, 0, time.UTC)
+
+func testDB() *DB {
+	return New(built).
+		AddVulnerability(
+			grypeDB.Vulnerability{
+				ID:                "CVE-2014-fake-1",
+				PackageName:       "neutron",
+				Namespace:         "debian:distro:debian:8",
+				VersionConstraint: "< 2014.1.5-6",
+				VersionFormat:     "deb",
+			},
+			grypeDB.Vulnerability{
+				ID:                "GHSA-2014-fake-3",
+				PackageName:       "activerecord",
+				Namespace:         "github:language:ruby",
+				VersionConstraint: "< 3.7.6",
+				VersionFormat:     "unknown",
+			},
+		).
+		AddVulnerabilityMetadata(grypeDB.VulnerabilityMetadata{
+			ID:        "CVE-2014-fake-1",
+			Namespace: "debian:distro:debian:8",
+			Severity:  "medium",
+		})
+}
+
+var (
+	neutron = pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "neutron",
+		Version: "2014.1.3-5",
+		Type:    syftPkg.DebPkg,
+	}
+	activerecord = pkg.Package{
+		ID:       pkg.ID(uuid.NewString()),
+		Name:     "activerecord",
+		Version:  "3.7.5",
+		Type:     syftPkg.GemPkg,
+		Language: syftPkg.Ruby,
+	}
+	debian = pkg.Context{Distro: &linux.Release{ID: "debian", VersionID: "8"}}
+)
+
+func TestDB_Store(t *testing.T) {
+	s, err := testDB().Store()
+	require.NoError(t, err)
+
+	matches, _, err := grype.DefaultVulnerabilityMatcher(*s).FindMatches([]pkg.Package{neutron, activerecord}, debian)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, m := range matches.Sorted() {
+		ids = append(ids, m.Vulnerability.ID)
+	}
+	assert.ElementsMatch(t, []string{"CVE-2014-fake-1", "GHSA-2014-fake-3"}, ids)
+
+	metadata, err := s.GetMetadata("CVE-2014-fake-1", "debian:distro:debian:8")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "medium", metadata.Severity)
+}
+
+func TestDB_AddVulnerability(t *testing.T) {
+	d := New(built).AddVulnerability(grypeDB.Vulnerability{ID: "CVE-2020-0001", PackageName: "curl"})
+
+	namespaces, err := d.GetVulnerabilityNamespaces()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nvd:cpe"}, namespaces, "records without a namespace are NVD CPE records")
+
+	vulns, err := d.GetVulnerability("nvd:cpe", "CVE-2020-0001")
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	assert.Equal(t, "curl", vulns[0].PackageName)
+}
+
+func TestDB_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), grypeDB.VulnerabilityStoreFileName)
+	require.NoError(t, testDB().Write(path))
+
+	s, err := store.New(path, false)
+	require.NoError(t, err)
+	defer s.Close()
+
+	id, err := s.GetID()
+	require.NoError(t, err)
+	assert.True(t, built.Equal(id.BuildTimestamp))
+
+	vulns, err := s.GetAllVulnerabilities()
+	require.NoError(t, err)
+	assert.Len(t, *vulns, 2)
+
+	metadata, err := s.GetVulnerabilityMetadata("CVE-2014-fake-1", "debian:distro:debian:8")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "medium", metadata.Severity)
+}
@@ -0,0 +1,123 @@
+package dbtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anchore/grype/grype/db/build"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+)
+
+const (
+	listingPath = "/" + distribution.ListingFileName
+	archivePath = "/vulnerability-db.tar.gz"
+)
+
+// Server is a fake distribution server, serving a listing of a single database and the archive of the database, as
+// the listing and archives of the vulnerability databases are served to curators.
+type Server struct {
+	*httptest.Server
+	listing []byte
+	archive []byte
+
+	lock     sync.Mutex
+	requests []string
+}
+
+// NewServer starts serving the given database, which is closed when the test completes.
+func NewServer(t testing.TB, d *DB) *Server {
+	t.Helper()
+
+	archive, err := d.archive(t.TempDir())
+	if err != nil {
+		t.Fatalf("unable to archive the vulnerability database: %v", err)
+	}
+
+	s := &Server{archive: archive}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	archiveURL, err := url.Parse(s.URL + archivePath)
+	if err != nil {
+		t.Fatalf("unable to parse the URL of the vulnerability database archive: %v", err)
+	}
+	listing := distribution.NewListing(distribution.ListingEntry{
+		Built:    d.built,
+		Version:  grypeDB.SchemaVersion,
+		URL:      archiveURL,
+		Checksum: "sha256:" + digest(archive),
+	})
+	if s.listing, err = json.Marshal(listing); err != nil {
+		t.Fatalf("unable to encode the listing of the vulnerability database: %v", err)
+	}
+	return s
+}
+
+// ListingURL returns the URL of the listing of the database.
+func (s *Server) ListingURL() string {
+	return s.URL + listingPath
+}
+
+// CuratorConfig returns the configuration of a curator of the given DB directory, updating from the server.
+func (s *Server) CuratorConfig(dbRootDir string) distribution.Config {
+	return distribution.Config{
+		DBRootDir:           dbRootDir,
+		ListingURL:          s.ListingURL(),
+		ValidateByHashOnGet: true,
+		ListingFileTimeout:  10 * time.Second,
+		UpdateTimeout:       10 * time.Second,
+	}
+}
+
+// Requests returns the requests made to the server (as "METHOD /path"), in order.
+func (s *Server) Requests() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	s.lock.Unlock()
+
+	switch r.URL.Path {
+	case listingPath:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(s.listing)
+	case archivePath:
+		w.Header().Set("Content-Type", "application/gzip")
+		_, _ = w.Write(s.archive)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// archive returns the database as a vulnerability database archive (the database file along with its metadata),
+// using the given directory for the database file and the archive.
+func (d *DB) archive(dir string) ([]byte, error) {
+	dbPath := filepath.Join(dir, grypeDB.VulnerabilityStoreFileName)
+	if err := d.Write(dbPath); err != nil {
+		return nil, err
+	}
+	archivePath := filepath.Join(dir, build.ArchiveName(d.built))
+	if err := build.WriteArchive(dbPath, d.built, archivePath); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(archivePath)
+}
+
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
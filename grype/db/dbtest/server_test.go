@@ -0,0 +1,63 @@
+package dbtest
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/pkg"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer(t, testDB())
+
+	cfg := server.CuratorConfig(t.TempDir())
+	cfg.ValidateAge = true
+	cfg.MaxAllowedBuiltAge = time.Hour
+	cfg.Now = func() time.Time { return built.Add(time.Minute) }
+
+	s, status, closer, err := grype.LoadVulnerabilityDB(cfg, true)
+	require.NoError(t, err)
+	defer closer.Close()
+
+	assert.NoError(t, status.Err)
+	assert.True(t, built.Equal(status.Built))
+	assert.Equal(t, []string{
+		"HEAD " + listingPath, "GET " + listingPath,
+		"HEAD " + archivePath, "GET " + archivePath,
+	}, server.Requests())
+
+	matches, _, err := grype.DefaultVulnerabilityMatcher(*s).FindMatches([]pkg.Package{neutron}, debian)
+	require.NoError(t, err)
+	require.Equal(t, 1, matches.Count())
+	assert.Equal(t, "CVE-2014-fake-1", matches.Sorted()[0].Vulnerability.ID)
+}
+
+func TestServer_mirrors(t *testing.T) {
+	server := NewServer(t, testDB())
+
+	// a mirror listing the same database, which it fails to serve
+	broken := &Server{}
+	broken.Server = httptest.NewServer(http.HandlerFunc(broken.serve))
+	t.Cleanup(broken.Close)
+	broken.listing = bytes.ReplaceAll(server.listing, []byte(server.URL), []byte(broken.URL))
+
+	cfg := server.CuratorConfig(t.TempDir())
+	cfg.ListingURL = broken.ListingURL()
+	cfg.Mirrors = []distribution.Mirror{{ListingURL: server.ListingURL()}}
+
+	_, status, closer, err := grype.LoadVulnerabilityDB(cfg, true)
+	require.NoError(t, err)
+	defer closer.Close()
+
+	assert.NoError(t, status.Err)
+	assert.Contains(t, broken.Requests(), "GET "+archivePath)
+	assert.Contains(t, server.Requests(), "GET "+archivePath)
+}
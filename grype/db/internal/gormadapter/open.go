@@ -24,9 +24,10 @@ var readOptions = []string{
 }
 
 type config struct {
-	path   string
-	write  bool
-	memory bool
+	path     string
+	write    bool
+	preserve bool
+	memory   bool
 }
 
 type Option func(*config)
@@ -37,6 +38,14 @@ func WithTruncate(truncate bool) Option {
 	}
 }
 
+// WithWritable opens an existing DB for modification without removing its contents.
+func WithWritable() Option {
+	return func(c *config) {
+		c.write = true
+		c.preserve = true
+	}
+}
+
 func newConfig(path string, opts []Option) config {
 	c := config{}
 	c.apply(path, opts)
@@ -52,7 +61,7 @@ func (c *config) apply(path string, opts []Option) {
 }
 
 func (c config) shouldTruncate() bool {
-	return c.write && !c.memory
+	return c.write && !c.preserve && !c.memory
 }
 
 func (c config) connectionString() string {
@@ -51,6 +51,7 @@ func TestConfigShouldTruncate(t *testing.T) {
 	tests := []struct {
 		name             string
 		write            bool
+		preserve         bool
 		memory           bool
 		expectedTruncate bool
 	}{
@@ -66,6 +67,13 @@ func TestConfigShouldTruncate(t *testing.T) {
 			memory:           false,
 			expectedTruncate: false,
 		},
+		{
+			name:             "should not truncate when preserving an existing DB",
+			write:            true,
+			preserve:         true,
+			memory:           false,
+			expectedTruncate: false,
+		},
 		{
 			name:             "should not truncate when using in-memory DB",
 			write:            true,
@@ -77,8 +85,9 @@ func TestConfigShouldTruncate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := config{
-				write:  tt.write,
-				memory: tt.memory,
+				write:    tt.write,
+				preserve: tt.preserve,
+				memory:   tt.memory,
 			}
 			require.Equal(t, tt.expectedTruncate, c.shouldTruncate())
 		})
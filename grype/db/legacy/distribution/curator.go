@@ -3,10 +3,11 @@ package distribution
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
-	"path"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -24,12 +25,21 @@ import (
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/grype/internal/bus"
 	"github.com/anchore/grype/internal/file"
+	"github.com/anchore/grype/internal/fips"
 	"github.com/anchore/grype/internal/log"
 )
 
 const (
 	FileName                = grypeDB.VulnerabilityStoreFileName
 	lastUpdateCheckFileName = "last_update_check"
+
+	// stagingDirSuffix and previousDirSuffix are the suffixes of the directories, next to the db directory, of the db
+	// being activated and of the db being replaced.
+	stagingDirSuffix  = ".staging"
+	previousDirSuffix = ".previous"
+
+	// lockFileSuffix is the suffix of the lock file of a directory, next to the directory.
+	lockFileSuffix = ".lock"
 )
 
 type Config struct {
@@ -37,6 +47,9 @@ type Config struct {
 	DBRootDir               string
 	ListingURL              string
 	CACert                  string
+	CertificatePins         []CertificatePin
+	Mirrors                 []Mirror
+	MetricsFile             string
 	ValidateByHashOnGet     bool
 	ValidateAge             bool
 	MaxAllowedBuiltAge      time.Duration
@@ -44,6 +57,18 @@ type Config struct {
 	ListingFileTimeout      time.Duration
 	UpdateTimeout           time.Duration
 	UpdateCheckMaxFrequency time.Duration
+	Retry                   RetryConfig
+	Provenance              ProvenancePolicy
+	Rollback                RollbackPolicy
+	Ecosystems              []string
+	Shard                   bool
+	WarmOnActivate          bool
+	TUF                     TUFConfig
+	// Now is the clock of the curator, for the age of the DB, the frequency of update checks and the retries of
+	// requests, time.Now when nil.
+	Now func() time.Time
+	// Jitter returns the random fraction (in [0, 1)) of the jitter of the retry backoffs, math/rand when nil.
+	Jitter func() float64
 }
 
 type Curator struct {
@@ -53,59 +78,220 @@ type Curator struct {
 	targetSchema            int
 	dbDir                   string
 	dbPath                  string
-	listingURL              string
+	tempRoot                string
+	mirrors                 []Mirror
+	metricsFile             string
 	validateByHashOnGet     bool
 	validateAge             bool
 	maxAllowedBuiltAge      time.Duration
 	requireUpdateCheck      bool
 	updateCheckMaxFrequency time.Duration
+	provenancePolicy        ProvenancePolicy
+	rollbackPolicy          RollbackPolicy
+	highestBuiltPath        string
+	ecosystems              []string
+	shard                   bool
+	warmOnActivate          bool
+	tuf                     *tufClient
+	now                     func() time.Time
 }
 
 func NewCurator(cfg Config) (Curator, error) {
-	dbDir := path.Join(cfg.DBRootDir, strconv.Itoa(vulnerability.SchemaVersion))
+	dbDir := filepath.Join(cfg.DBRootDir, strconv.Itoa(vulnerability.SchemaVersion))
+
+	ecosystems, err := NormalizeEcosystems(cfg.Ecosystems)
+	if err != nil {
+		return Curator{}, err
+	}
 
 	fs := afero.NewOsFs()
-	listingClient, err := defaultHTTPClient(fs, cfg.CACert)
+	listingClient, err := defaultHTTPClient(fs, cfg.CACert, cfg.CertificatePins)
 	if err != nil {
 		return Curator{}, err
 	}
 	listingClient.Timeout = cfg.ListingFileTimeout
 
-	dbClient, err := defaultHTTPClient(fs, cfg.CACert)
+	dbClient, err := defaultHTTPClient(fs, cfg.CACert, cfg.CertificatePins)
 	if err != nil {
 		return Curator{}, err
 	}
 	dbClient.Timeout = cfg.UpdateTimeout
 
+	// the listing and DB downloads share a single retry budget and view of host health
+	retries := newRetryState(cfg.Retry)
+	listingClient.Transport = newRetryTransport(newEncodingTransport(listingClient.Transport), cfg.Retry, retries).withClock(cfg.Now, cfg.Jitter)
+	dbClient.Transport = newRetryTransport(newEncodingTransport(dbClient.Transport), cfg.Retry, retries).withClock(cfg.Now, cfg.Jitter)
+
+	// the requests to the mirrors are authenticated with their credentials
+	mirrors := newMirrors(cfg.ListingURL, cfg.Mirrors)
+	tokens := newOAuthTokenSources(mirrors, &http.Client{Transport: listingClient.Transport, Timeout: listingClient.Timeout}, cfg.Now)
+	listingClient.Transport = newAuthTransport(listingClient.Transport, mirrors, tokens)
+	dbClient.Transport = newAuthTransport(dbClient.Transport, mirrors, tokens)
+
+	var tuf *tufClient
+	if cfg.TUF.enabled() {
+		tuf = newTUFClient(fs, filepath.Join(cfg.DBRootDir, tufDirName), cfg.TUF, listingClient, cfg.Now)
+	}
+
 	return Curator{
 		fs:                      fs,
 		targetSchema:            vulnerability.SchemaVersion,
 		listingDownloader:       file.NewGetter(cfg.ID, listingClient),
 		updateDownloader:        file.NewGetter(cfg.ID, dbClient),
 		dbDir:                   dbDir,
-		dbPath:                  path.Join(dbDir, FileName),
-		listingURL:              cfg.ListingURL,
+		dbPath:                  filepath.Join(dbDir, FileName),
+		tempRoot:                tempRoot(cfg.DBRootDir),
+		mirrors:                 mirrors,
+		metricsFile:             cfg.MetricsFile,
 		validateByHashOnGet:     cfg.ValidateByHashOnGet,
 		validateAge:             cfg.ValidateAge,
 		maxAllowedBuiltAge:      cfg.MaxAllowedBuiltAge,
 		requireUpdateCheck:      cfg.RequireUpdateCheck,
 		updateCheckMaxFrequency: cfg.UpdateCheckMaxFrequency,
+		provenancePolicy:        cfg.Provenance,
+		rollbackPolicy:          cfg.Rollback,
+		highestBuiltPath:        filepath.Join(cfg.DBRootDir, highestBuiltFileName),
+		ecosystems:              ecosystems,
+		shard:                   cfg.Shard,
+		warmOnActivate:          cfg.WarmOnActivate,
+		tuf:                     tuf,
+		now:                     cfg.Now,
 	}, nil
 }
 
+// currentTime returns the time according to the clock of the curator.
+func (c Curator) currentTime() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
 func (c Curator) SupportedSchema() int {
 	return c.targetSchema
 }
 
 func (c *Curator) GetStore() (grypeDB.StoreReader, grypeDB.DBCloser, error) {
+	// let consumers know of a monitorable event (the validation and opening of the db)
+	stage := progress.NewAtomicStage("validating")
+	validated := progress.NewManual(-1)
+	bus.Publish(partybus.Event{
+		Type: event.DatabaseLoadingStarted,
+		Value: progress.StagedProgressable(&struct {
+			progress.Stager
+			progress.Progressable
+		}{
+			Stager:       progress.Stager(stage),
+			Progressable: progress.Progressable(validated),
+		}),
+	})
+	defer validated.SetCompleted()
+
 	// ensure the DB is ok
-	_, err := c.validateIntegrity(c.dbDir)
+	metadata, err := c.validateIntegrity(c.dbDir, validated)
 	if err != nil {
+		validated.SetError(err)
 		return nil, nil, fmt.Errorf("vulnerability database is invalid (run db update to correct): %+v", err)
 	}
 
+	if c.shard {
+		stage.Set("sharding")
+		if err := c.ensureShards(c.dbDir, metadata); err != nil {
+			validated.SetError(err)
+			return nil, nil, err
+		}
+		stage.Set("opening")
+		s, err := store.NewSharded(filepath.Join(c.dbDir, store.ShardDirName))
+		if err != nil {
+			validated.SetError(err)
+			return nil, nil, fmt.Errorf("unable to open vulnerability database shards: %w", err)
+		}
+		stage.Set("loaded")
+		return s, s, nil
+	}
+
+	stage.Set("opening")
 	s, err := store.New(c.dbPath, false)
-	return s, s, err
+	if err != nil {
+		validated.SetError(err)
+		return s, s, err
+	}
+	stage.Set("loaded")
+	return s, s, nil
+}
+
+func (c *Curator) prepareShards(dbDirPath string) error {
+	metadata, err := NewMetadataFromDir(c.fs, dbDirPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse database metadata (%s): %w", dbDirPath, err)
+	}
+	if metadata == nil {
+		return fmt.Errorf("database metadata not found: %s", dbDirPath)
+	}
+	return c.ensureShards(dbDirPath, *metadata)
+}
+
+// ensureShards splits the DB within the given directory into per-provider shards, unless shards for this exact DB
+// were already created. The shards are built in a temporary directory and then renamed into place, under a lock
+// shared by every process (e.g. concurrent scans that find the same stale shards), so that the shards are built once
+// and a reader never sees a partially written set of shards.
+func (c *Curator) ensureShards(dbDirPath string, metadata Metadata) error {
+	shardDir := filepath.Join(dbDirPath, store.ShardDirName)
+	if shardsOf(shardDir, metadata) {
+		return nil
+	}
+
+	unlock, err := file.Lock(shardDir + lockFileSuffix)
+	if err != nil {
+		return fmt.Errorf("unable to lock vulnerability database shards: %w", err)
+	}
+	defer unlock()
+	// the shards may have been built while waiting for the lock
+	if shardsOf(shardDir, metadata) {
+		return nil
+	}
+
+	log.WithFields("dir", shardDir).Debug("sharding vulnerability database")
+	tempDir, err := os.MkdirTemp(dbDirPath, store.ShardDirName+"-")
+	if err != nil {
+		return fmt.Errorf("unable to create shard temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+	if _, err := store.Shard(filepath.Join(dbDirPath, FileName), tempDir, metadata.Checksum); err != nil {
+		return fmt.Errorf("unable to shard vulnerability database: %w", err)
+	}
+
+	// a directory cannot be renamed over another, so the previous shards are moved aside first
+	previous := shardDir + previousDirSuffix
+	if err := os.RemoveAll(previous); err != nil {
+		return fmt.Errorf("unable to remove previous shards: %w", err)
+	}
+	if err := os.Rename(shardDir, previous); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to move previous shards aside: %w", err)
+	}
+	if err := os.Rename(tempDir, shardDir); err != nil {
+		return fmt.Errorf("unable to move shards into place: %w", err)
+	}
+	if err := os.RemoveAll(previous); err != nil {
+		log.WithFields("dir", previous, "error", err).Warn("unable to remove previous shards")
+	}
+	return nil
+}
+
+// lock takes the lock of the directory, a lock file next to the directory shared by every process using it (see
+// file.Lock). A curator of an in-memory filesystem (as in tests) is the only user of its directories, so it takes no
+// lock.
+func (c *Curator) lock(dir string) (func(), error) {
+	if _, ok := c.fs.(*afero.OsFs); !ok {
+		return func() {}, nil
+	}
+	return file.Lock(dir + lockFileSuffix)
+}
+
+// shardsOf tells whether the shard directory holds the shards of the DB.
+func shardsOf(shardDir string, metadata Metadata) bool {
+	index, err := store.ReadShardIndex(shardDir)
+	return err == nil && index.Source == metadata.Checksum
 }
 
 func (c *Curator) Status() Status {
@@ -126,6 +312,8 @@ func (c *Curator) Status() Status {
 		SchemaVersion: metadata.Version,
 		Location:      c.dbDir,
 		Checksum:      metadata.Checksum,
+		Provenance:    metadata.Provenance,
+		Ecosystems:    metadata.Ecosystems,
 		Err:           nil,
 	}
 }
@@ -136,7 +324,7 @@ func (c *Curator) Delete() error {
 }
 
 // Update the existing DB, returning an indication if any action was taken.
-func (c *Curator) Update() (bool, error) { // nolint: funlen
+func (c *Curator) Update() (updated bool, err error) { // nolint: funlen
 	if !c.isUpdateCheckAllowed() {
 		// we should not notify the user of an update check if the current configuration and state
 		// indicates we're should be in a low-pass filter mode (and the check frequency is too high).
@@ -164,7 +352,16 @@ func (c *Curator) Update() (bool, error) { // nolint: funlen
 	defer downloadProgress.SetCompleted()
 	defer importProgress.SetCompleted()
 
-	updateAvailable, metadata, updateEntry, checkErr := c.IsUpdateAvailable()
+	updateAvailable, metadata, updateEntry, checkErr := c.isUpdateAvailable()
+	// the metrics are written once the update (if any) is done, with the outcome of the check and of the update: a
+	// failed update (e.g. of a db that fails validation) is a failure even though the check succeeded
+	defer func() {
+		updateErr := err
+		if updateErr == nil {
+			updateErr = checkErr
+		}
+		c.writeMetrics(updateErr)
+	}()
 	if checkErr != nil {
 		if c.requireUpdateCheck {
 			return false, fmt.Errorf("check for vulnerability database update failed: %w", checkErr)
@@ -234,7 +431,7 @@ func (c Curator) isUpdateCheckAllowed() bool {
 func (c Curator) durationSinceUpdateCheck() (*time.Duration, error) {
 	// open `$dbDir/last_update_check` file and read the timestamp and do now() - timestamp
 
-	filePath := path.Join(c.dbDir, lastUpdateCheckFileName)
+	filePath := filepath.Join(c.dbDir, lastUpdateCheckFileName)
 
 	if _, err := c.fs.Stat(filePath); os.IsNotExist(err) {
 		log.Trace("first-run of DB update")
@@ -264,7 +461,7 @@ func (c Curator) durationSinceUpdateCheck() (*time.Duration, error) {
 		return nil, fmt.Errorf("empty update check timestamp")
 	}
 
-	elapsed := time.Since(lastCheck)
+	elapsed := c.currentTime().Sub(lastCheck)
 	return &elapsed, nil
 }
 
@@ -272,7 +469,7 @@ func (c Curator) setLastSuccessfulUpdateCheck() {
 	// note: we should always assume the DB dir actually exists, otherwise let this operation fail (since having a DB
 	// is a prerequisite for a successful update).
 
-	filePath := path.Join(c.dbDir, lastUpdateCheckFileName)
+	filePath := filepath.Join(c.dbDir, lastUpdateCheckFileName)
 	fh, err := c.fs.OpenFile(filePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
 	if err != nil {
 		log.WithFields("error", err).Trace("unable to write last update check timestamp")
@@ -281,12 +478,18 @@ func (c Curator) setLastSuccessfulUpdateCheck() {
 
 	defer fh.Close()
 
-	_, _ = fmt.Fprintf(fh, "%s", time.Now().UTC().Format(time.RFC3339))
+	_, _ = fmt.Fprintf(fh, "%s", c.currentTime().UTC().Format(time.RFC3339))
 }
 
 // IsUpdateAvailable indicates if there is a new update available as a boolean, and returns the latest listing information
 // available for this schema.
 func (c *Curator) IsUpdateAvailable() (bool, *Metadata, *ListingEntry, error) {
+	updateAvailable, current, updateEntry, err := c.isUpdateAvailable()
+	c.writeMetrics(err)
+	return updateAvailable, current, updateEntry, err
+}
+
+func (c *Curator) isUpdateAvailable() (bool, *Metadata, *ListingEntry, error) {
 	log.Debugf("checking for available database updates")
 
 	listing, err := c.ListingFromURL()
@@ -310,6 +513,11 @@ func (c *Curator) IsUpdateAvailable() (bool, *Metadata, *ListingEntry, error) {
 		log.Debugf("database update available: %s", updateEntry)
 		return true, current, updateEntry, nil
 	}
+
+	if !sameEcosystems(current.Ecosystems, c.ecosystems) {
+		log.Debugf("installed database ecosystems (%v) differ from the configured ecosystems (%v)", current.Ecosystems, c.ecosystems)
+		return true, current, updateEntry, nil
+	}
 	log.Debugf("no database update available")
 
 	return false, nil, nil, nil
@@ -319,7 +527,7 @@ func (c *Curator) IsUpdateAvailable() (bool, *Metadata, *ListingEntry, error) {
 func (c *Curator) UpdateTo(listing *ListingEntry, downloadProgress, importProgress *progress.Manual, stage *progress.AtomicStage) error {
 	stage.Set("downloading")
 	// note: the temp directory is persisted upon download/validation/activation failure to allow for investigation
-	tempDir, err := c.download(listing, downloadProgress)
+	tempDir, err := c.downloadFromMirrors(listing, downloadProgress)
 	if err != nil {
 		return err
 	}
@@ -330,11 +538,28 @@ func (c *Curator) UpdateTo(listing *ListingEntry, downloadProgress, importProgre
 		return err
 	}
 
+	stage.Set("selecting ecosystems")
+	err = c.applySubset(tempDir)
+	if err != nil {
+		return err
+	}
+
+	if c.shard {
+		stage.Set("sharding")
+		if err := c.prepareShards(tempDir); err != nil {
+			return err
+		}
+	}
+
 	stage.Set("importing")
 	err = c.activate(tempDir)
 	if err != nil {
 		return err
 	}
+
+	stage.Set("warming")
+	c.warmAfterActivation()
+
 	stage.Set("updated")
 	importProgress.Set(importProgress.Size())
 	importProgress.SetCompleted()
@@ -355,7 +580,7 @@ func (c *Curator) Validate() error {
 // ImportFrom takes a DB archive file and imports it into the final DB location.
 func (c *Curator) ImportFrom(dbArchivePath string) error {
 	// note: the temp directory is persisted upon download/validation/activation failure to allow for investigation
-	tempDir, err := os.MkdirTemp("", "grype-import")
+	tempDir, err := c.mkdirTemp("grype-import")
 	if err != nil {
 		return fmt.Errorf("unable to create db temp dir: %w", err)
 	}
@@ -370,16 +595,29 @@ func (c *Curator) ImportFrom(dbArchivePath string) error {
 		return err
 	}
 
+	err = c.applySubset(tempDir)
+	if err != nil {
+		return err
+	}
+
+	if c.shard {
+		if err := c.prepareShards(tempDir); err != nil {
+			return err
+		}
+	}
+
 	err = c.activate(tempDir)
 	if err != nil {
 		return err
 	}
 
+	c.warmAfterActivation()
+
 	return c.fs.RemoveAll(tempDir)
 }
 
 func (c *Curator) download(listing *ListingEntry, downloadProgress *progress.Manual) (string, error) {
-	tempDir, err := os.MkdirTemp("", "grype-scratch")
+	tempDir, err := c.mkdirTemp("grype-scratch")
 	if err != nil {
 		return "", fmt.Errorf("unable to create db temp dir: %w", err)
 	}
@@ -411,7 +649,7 @@ func (c *Curator) validateStaleness(m Metadata) error {
 
 	// built time is defined in UTC,
 	// we should compare it against UTC
-	now := time.Now().UTC()
+	now := c.currentTime().UTC()
 
 	age := now.Sub(m.Built)
 	if age > c.maxAllowedBuiltAge {
@@ -421,7 +659,9 @@ func (c *Curator) validateStaleness(m Metadata) error {
 	return nil
 }
 
-func (c *Curator) validateIntegrity(dbDirPath string) (Metadata, error) {
+// validateIntegrity validates the db of the directory against its metadata. The optional monitor is the progress of
+// the bytes of the db file hashed, when validating by hash.
+func (c *Curator) validateIntegrity(dbDirPath string, monitors ...*progress.Manual) (Metadata, error) {
 	// check that the disk checksum still matches the db payload
 	metadata, err := NewMetadataFromDir(c.fs, dbDirPath)
 	if err != nil {
@@ -432,8 +672,8 @@ func (c *Curator) validateIntegrity(dbDirPath string) (Metadata, error) {
 	}
 
 	if c.validateByHashOnGet {
-		dbPath := path.Join(dbDirPath, FileName)
-		valid, actualHash, err := file.ValidateByHash(c.fs, dbPath, metadata.Checksum)
+		dbPath := filepath.Join(dbDirPath, FileName)
+		valid, actualHash, err := file.ValidateByHash(c.fs, dbPath, metadata.Checksum, monitors...)
 		if err != nil {
 			return Metadata{}, err
 		}
@@ -446,34 +686,100 @@ func (c *Curator) validateIntegrity(dbDirPath string) (Metadata, error) {
 		return Metadata{}, fmt.Errorf("unsupported database version: have=%d want=%d", metadata.Version, c.targetSchema)
 	}
 
+	if err := c.provenancePolicy.VerifyProvenance(*metadata); err != nil {
+		return Metadata{}, err
+	}
+
 	// TODO: add version checks here to ensure this version of the application can use this database version (relative to what the DB says, not JUST the metadata!)
 
 	return *metadata, nil
 }
 
-// activate swaps over the downloaded db to the application directory
+// activate swaps over the downloaded db to the application directory: the db is staged next to the application
+// directory (on the same volume), then swapped in by renames, so that a failed activation leaves the existing db in
+// place and an activation never leaves a partially copied db behind. A db built before the newest db activated so far
+// is only activated as allowed by the rollback policy. Activations (and the removal of their leftovers) take the lock of
+// the application directory, so that concurrent updates (e.g. of several processes) activate one db after the other.
 func (c *Curator) activate(dbDirPath string) error {
-	_, err := c.fs.Stat(c.dbDir)
-	if !os.IsNotExist(err) {
-		// remove any previous databases
-		err = c.Delete()
-		if err != nil {
+	unlock, err := c.lock(c.dbDir)
+	if err != nil {
+		return fmt.Errorf("unable to lock the database directory: %w", err)
+	}
+	defer unlock()
+
+	metadata, err := NewMetadataFromDir(c.fs, dbDirPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse database metadata (%s): %w", dbDirPath, err)
+	}
+	if metadata == nil {
+		// without a build time the db cannot be checked against the newest db activated so far
+		return fmt.Errorf("refusing to activate the database: no metadata found (%s)", dbDirPath)
+	}
+	highest, err := c.checkRollback(*metadata)
+	if err != nil {
+		return err
+	}
+
+	staging := c.dbDir + stagingDirSuffix
+	previous := c.dbDir + previousDirSuffix
+
+	// remove the leftovers of earlier activations (e.g. a previous db that was still open when it was replaced)
+	for _, dir := range []string{staging, previous} {
+		if err := c.fs.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove the leftovers of a previous activation (%s): %w", dir, err)
+		}
+	}
+
+	if err := file.CopyDir(c.fs, dbDirPath, staging); err != nil {
+		return fmt.Errorf("failed to stage the database: %w", err)
+	}
+
+	_, err = c.fs.Stat(c.dbDir)
+	hasPrevious := !os.IsNotExist(err)
+	if hasPrevious {
+		if err := file.Rename(c.fs, c.dbDir, previous); err != nil {
 			return fmt.Errorf("failed to purge existing database: %w", err)
 		}
 	}
 
-	// ensure there is an application db directory
-	err = c.fs.MkdirAll(c.dbDir, 0755)
-	if err != nil {
-		return fmt.Errorf("failed to create db directory: %w", err)
+	if err := file.Rename(c.fs, staging, c.dbDir); err != nil {
+		if hasPrevious {
+			if restoreErr := file.Rename(c.fs, previous, c.dbDir); restoreErr != nil {
+				log.WithFields("path", previous, "error", restoreErr).Warn("unable to restore the previous database")
+			}
+		}
+		return fmt.Errorf("failed to activate the database: %w", err)
 	}
+	c.recordBuilt(metadata.Built, highest)
 
-	// activate the new db cache
-	return file.CopyDir(c.fs, dbDirPath, c.dbDir)
+	if hasPrevious {
+		if err := c.fs.RemoveAll(previous); err != nil {
+			// the files of a db still open by another process cannot be removed on Windows: they are removed by the
+			// next activation
+			log.WithFields("path", previous, "error", err).Debug("unable to remove the previous database")
+		}
+	}
+	return nil
+}
+
+// mkdirTemp creates a scratch directory for a download or an import, within the temp root of the curator.
+func (c *Curator) mkdirTemp(pattern string) (string, error) {
+	if c.tempRoot != "" {
+		if err := c.fs.MkdirAll(c.tempRoot, 0755); err != nil {
+			return "", fmt.Errorf("unable to create db temp root: %w", err)
+		}
+	}
+	return os.MkdirTemp(c.tempRoot, pattern)
 }
 
-// ListingFromURL loads a Listing from a URL.
+// ListingFromURL loads a Listing from the listing URL, or else from the first mirror serving it.
 func (c Curator) ListingFromURL() (Listing, error) {
+	listing, _, err := c.listingFromMirrors()
+	return listing, err
+}
+
+// listingFrom loads a Listing from a URL.
+func (c Curator) listingFrom(listingURL string) (Listing, error) {
 	tempFile, err := afero.TempFile(c.fs, "", "grype-db-listing")
 	if err != nil {
 		return Listing{}, fmt.Errorf("unable to create listing temp file: %w", err)
@@ -486,11 +792,18 @@ func (c Curator) ListingFromURL() (Listing, error) {
 	}()
 
 	// download the listing file
-	err = c.listingDownloader.GetFile(tempFile.Name(), c.listingURL)
+	err = c.listingDownloader.GetFile(tempFile.Name(), listingURL)
 	if err != nil {
 		return Listing{}, fmt.Errorf("unable to download listing: %w", err)
 	}
 
+	// verify the listing file against the TUF metadata published along with it
+	if c.tuf != nil {
+		if err := c.tuf.verifyListing(listingURL, tempFile.Name()); err != nil {
+			return Listing{}, fmt.Errorf("unable to verify listing: %w", err)
+		}
+	}
+
 	// parse the listing file
 	listing, err := NewListingFromFile(c.fs, tempFile.Name())
 	if err != nil {
@@ -499,7 +812,7 @@ func (c Curator) ListingFromURL() (Listing, error) {
 	return listing, nil
 }
 
-func defaultHTTPClient(fs afero.Fs, caCertPath string) (*http.Client, error) {
+func defaultHTTPClient(fs afero.Fs, caCertPath string, pins []CertificatePin) (*http.Client, error) {
 	httpClient := cleanhttp.DefaultClient()
 	httpClient.Timeout = 30 * time.Second
 	if caCertPath != "" {
@@ -516,5 +829,17 @@ func defaultHTTPClient(fs afero.Fs, caCertPath string) (*http.Client, error) {
 			RootCAs:    rootCAs,
 		}
 	}
-	return httpClient, nil
+	if len(pins) > 0 {
+		certificatePins, err := newCertificatePins(pins)
+		if err != nil {
+			return nil, fmt.Errorf("unable to configure pinned certificates for curator: %w", err)
+		}
+		transport := httpClient.Transport.(*http.Transport)
+		if transport.TLSClientConfig == nil {
+			// the chains are verified against the system trust store
+			transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		transport.TLSClientConfig.VerifyConnection = certificatePins.verifyConnection
+	}
+	return fips.HTTPClient(httpClient), nil
 }
@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"testing"
 	"time"
@@ -31,6 +32,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/wagoodman/go-progress"
 
+	v5 "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store"
 	"github.com/anchore/grype/internal/file"
 	"github.com/anchore/grype/internal/stringutil"
 )
@@ -107,7 +110,7 @@ func Test_defaultHTTPClientHasCert(t *testing.T) {
 				certPath = generateCertFixture(t)
 			}
 
-			httpClient, err := defaultHTTPClient(afero.NewOsFs(), certPath)
+			httpClient, err := defaultHTTPClient(afero.NewOsFs(), certPath, nil)
 			require.NoError(t, err)
 
 			if test.hasCert {
@@ -121,7 +124,7 @@ func Test_defaultHTTPClientHasCert(t *testing.T) {
 }
 
 func Test_defaultHTTPClientTimeout(t *testing.T) {
-	c, err := defaultHTTPClient(afero.NewMemMapFs(), "")
+	c, err := defaultHTTPClient(afero.NewMemMapFs(), "", nil)
 	require.NoError(t, err)
 	assert.Equal(t, 30*time.Second, c.Timeout)
 }
@@ -452,9 +455,16 @@ func Test_requireUpdateCheck(t *testing.T) {
 		Checksum: "sha256:" + checksum(newDB),
 	}}}})
 
+	missingListing := toJson(Listing{Available: map[int][]ListingEntry{5: {ListingEntry{
+		Built:    newTime,
+		URL:      mustUrl(url.Parse(srv.URL + "/missing.tar.gz")),
+		Checksum: "sha256:" + checksum(newDB),
+	}}}})
+
 	newListingURI := "/listing.json"
 	oldListingURI := "/oldlisting.json"
 	badListingURI := "/badlisting.json"
+	missingListingURI := "/missinglisting.json"
 
 	handlerFunc = func(response http.ResponseWriter, request *http.Request) {
 		switch request.RequestURI {
@@ -464,6 +474,9 @@ func Test_requireUpdateCheck(t *testing.T) {
 		case oldListingURI:
 			response.WriteHeader(http.StatusOK)
 			_, _ = response.Write(oldListing)
+		case missingListingURI:
+			response.WriteHeader(http.StatusOK)
+			_, _ = response.Write(missingListing)
 		case newDbURI:
 			response.WriteHeader(http.StatusOK)
 			_, _ = response.Write(newDB)
@@ -478,6 +491,8 @@ func Test_requireUpdateCheck(t *testing.T) {
 		dbDir      map[string][]byte
 		wantResult bool
 		wantErr    require.ErrorAssertionFunc
+		// wantFailed is whether the metrics report a failed update
+		wantFailed bool
 	}{
 		{
 			name: "listing with update",
@@ -511,6 +526,7 @@ func Test_requireUpdateCheck(t *testing.T) {
 			},
 			wantResult: false,
 			wantErr:    require.Error,
+			wantFailed: true,
 		},
 		{
 			name: "update error continue",
@@ -520,6 +536,20 @@ func Test_requireUpdateCheck(t *testing.T) {
 			},
 			wantResult: false,
 			wantErr:    require.NoError,
+			wantFailed: true,
+		},
+		{
+			name: "download error after a successful check",
+			config: Config{
+				ListingURL:         srv.URL + missingListingURI,
+				RequireUpdateCheck: true,
+			},
+			dbDir: map[string][]byte{
+				"5/metadata.json": midMetadata,
+			},
+			wantResult: false,
+			wantErr:    require.Error,
+			wantFailed: true,
 		},
 	}
 
@@ -529,6 +559,7 @@ func Test_requireUpdateCheck(t *testing.T) {
 			tt.config.DBRootDir = dbTmpDir
 			tt.config.ListingFileTimeout = 1 * time.Minute
 			tt.config.UpdateTimeout = 1 * time.Minute
+			tt.config.MetricsFile = filepath.Join(dbTmpDir, "grype.prom")
 			for filePath, contents := range tt.dbDir {
 				fullPath := filepath.Join(dbTmpDir, filepath.FromSlash(filePath))
 				err := os.MkdirAll(filepath.Dir(fullPath), 0700|os.ModeDir)
@@ -542,6 +573,14 @@ func Test_requireUpdateCheck(t *testing.T) {
 			result, err := c.Update()
 			require.Equal(t, tt.wantResult, result)
 			tt.wantErr(t, err)
+
+			metrics, err := os.ReadFile(tt.config.MetricsFile)
+			require.NoError(t, err)
+			success := "grype_db_last_update_check_success 1\n"
+			if tt.wantFailed {
+				success = "grype_db_last_update_check_success 0\n"
+			}
+			assert.Contains(t, string(metrics), success)
 		})
 	}
 }
@@ -725,6 +764,37 @@ func TestCurator_SetLastSuccessfulUpdateCheck(t *testing.T) {
 	})
 }
 
+func TestCurator_clock(t *testing.T) {
+	built := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := built.Add(90 * time.Minute)
+
+	c, err := NewCurator(Config{
+		DBRootDir:               t.TempDir(),
+		ValidateAge:             true,
+		MaxAllowedBuiltAge:      2 * time.Hour,
+		UpdateCheckMaxFrequency: time.Hour,
+		Now:                     func() time.Time { return now },
+	})
+	require.NoError(t, err)
+	c.fs = afero.NewMemMapFs()
+	require.NoError(t, c.fs.MkdirAll(c.dbDir, 0755))
+
+	// the age of the DB is according to the clock of the curator, not the current time
+	assert.NoError(t, c.validateStaleness(Metadata{Built: built}))
+	now = now.Add(time.Hour)
+	assert.ErrorContains(t, c.validateStaleness(Metadata{Built: built}), "the vulnerability database was built 2 hours ago (max allowed age is 2 hours)")
+
+	// as is the frequency of update checks
+	c.setLastSuccessfulUpdateCheck()
+	elapsed, err := c.durationSinceUpdateCheck()
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), *elapsed)
+	assert.False(t, c.isUpdateCheckAllowed())
+
+	now = now.Add(61 * time.Minute)
+	assert.True(t, c.isUpdateCheckAllowed())
+}
+
 // Mock for the file.Getter interface
 type MockGetter struct {
 	mock.Mock
@@ -766,3 +836,212 @@ func TestCurator_Update_setLastSuccessfulUpdateCheck_notCalled(t *testing.T) {
 	})
 
 }
+
+func TestCuratorValidate_provenancePolicy(t *testing.T) {
+	fs := afero.NewOsFs()
+	cur := newTestCurator(t, fs, newTestGetter(fs, nil, nil), "/tmp/dbdir", "http://metadata.io", true)
+	cur.targetSchema = 1
+
+	_, err := cur.validateIntegrity("test-fixtures/curator-validate/good-checksum")
+	require.NoError(t, err)
+
+	cur.provenancePolicy = ProvenancePolicy{Required: true}
+	_, err = cur.validateIntegrity("test-fixtures/curator-validate/good-checksum")
+	require.ErrorContains(t, err, "no provenance metadata")
+}
+
+func TestCurator_GetStore_sharded(t *testing.T) {
+	fs := afero.NewOsFs()
+	root := t.TempDir()
+	dbDir := path.Join(root, "5")
+	require.NoError(t, fs.MkdirAll(dbDir, 0755))
+	dbPath := path.Join(dbDir, FileName)
+
+	s, err := store.New(dbPath, true)
+	require.NoError(t, err)
+	require.NoError(t, s.SetID(v5.NewID(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))))
+	require.NoError(t, s.AddVulnerability(
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:12", VersionConstraint: "< 1.0", VersionFormat: "dpkg"},
+		v5.Vulnerability{ID: "GHSA-1", PackageName: "left-pad", Namespace: "github:language:javascript", VersionConstraint: "< 2.0", VersionFormat: "unknown"},
+	))
+	s.Close()
+
+	checksum, err := file.HashFile(fs, dbPath, sha256.New())
+	require.NoError(t, err)
+	metadata := Metadata{
+		Built:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Version:  5,
+		Checksum: "sha256:" + checksum,
+	}
+	require.NoError(t, metadata.Write(metadataPath(dbDir)))
+
+	c := Curator{fs: fs, dbDir: dbDir, dbPath: dbPath, targetSchema: 5, shard: true}
+
+	reader, closer, err := c.GetStore()
+	require.NoError(t, err)
+	sharded, ok := reader.(*store.ShardedStore)
+	require.True(t, ok, "expected a sharded store")
+
+	vulns, err := reader.SearchForVulnerabilities("github:language:javascript", "left-pad")
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	assert.Equal(t, []string{"github-language-javascript"}, sharded.Opened())
+	closer.Close()
+
+	index, err := store.ReadShardIndex(path.Join(dbDir, store.ShardDirName))
+	require.NoError(t, err)
+	assert.Equal(t, metadata.Checksum, index.Source)
+
+	// shards for a different DB are rebuilt
+	require.NoError(t, c.ensureShards(dbDir, Metadata{Checksum: "sha256:other"}))
+	index, err = store.ReadShardIndex(path.Join(dbDir, store.ShardDirName))
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:other", index.Source)
+
+	// concurrent scans finding stale shards build them once, in place of the previous shards
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = c.ensureShards(dbDir, metadata)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	index, err = store.ReadShardIndex(path.Join(dbDir, store.ShardDirName))
+	require.NoError(t, err)
+	assert.Equal(t, metadata.Checksum, index.Source)
+
+	entries, err := os.ReadDir(dbDir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	// no temporary or previous shards are left behind
+	assert.ElementsMatch(t, []string{FileName, MetadataFileName, store.ShardDirName, store.ShardDirName + lockFileSuffix}, names)
+}
+
+func TestCurator_Warm(t *testing.T) {
+	fs := afero.NewOsFs()
+	dbDir := path.Join(t.TempDir(), "5")
+	require.NoError(t, fs.MkdirAll(dbDir, 0755))
+	dbPath := path.Join(dbDir, FileName)
+
+	s, err := store.New(dbPath, true)
+	require.NoError(t, err)
+	require.NoError(t, s.SetID(v5.NewID(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))))
+	require.NoError(t, s.AddVulnerability(
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:12", VersionConstraint: "< 1.0", VersionFormat: "dpkg"},
+	))
+	s.Close()
+
+	checksum, err := file.HashFile(fs, dbPath, sha256.New())
+	require.NoError(t, err)
+	metadata := Metadata{
+		Built:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Version:  5,
+		Checksum: "sha256:" + checksum,
+	}
+	require.NoError(t, metadata.Write(metadataPath(dbDir)))
+
+	info, err := fs.Stat(dbPath)
+	require.NoError(t, err)
+
+	c := Curator{fs: fs, dbDir: dbDir, dbPath: dbPath, targetSchema: 5}
+	result, err := c.Warm()
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Files)
+	assert.Equal(t, info.Size(), result.Bytes)
+	assert.Empty(t, result.MissingIndexes)
+
+	// with sharding enabled the shards are built and warmed instead of the monolithic DB
+	c.shard = true
+	result, err = c.Warm()
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Files) // base + debian-distro
+	_, err = store.ReadShardIndex(path.Join(dbDir, store.ShardDirName))
+	require.NoError(t, err)
+
+	// an invalid DB cannot be warmed
+	c.targetSchema = 6
+	_, err = c.Warm()
+	require.Error(t, err)
+}
+
+func TestCurator_activate(t *testing.T) {
+	fs := afero.NewOsFs()
+	root := t.TempDir()
+	dbDir := filepath.Join(root, "5")
+	require.NoError(t, fs.MkdirAll(dbDir, 0755))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dbDir, FileName), []byte("old"), 0600))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dbDir, "stale"), []byte("old"), 0600))
+	// the leftover of an activation whose previous db could not be removed
+	require.NoError(t, fs.MkdirAll(dbDir+previousDirSuffix, 0755))
+
+	downloaded := filepath.Join(root, "download")
+	require.NoError(t, fs.MkdirAll(downloaded, 0755))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(downloaded, FileName), []byte("new"), 0600))
+	require.NoError(t, Metadata{Built: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Version: 5, Checksum: "sha256:0000"}.Write(metadataPath(downloaded)))
+
+	c := Curator{fs: fs, dbDir: dbDir, highestBuiltPath: filepath.Join(root, highestBuiltFileName)}
+	require.NoError(t, c.activate(downloaded))
+
+	contents, err := afero.ReadFile(fs, filepath.Join(dbDir, FileName))
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(contents))
+	for _, p := range []string{filepath.Join(dbDir, "stale"), dbDir + stagingDirSuffix, dbDir + previousDirSuffix} {
+		exists, err := afero.Exists(fs, p)
+		require.NoError(t, err)
+		assert.False(t, exists, p)
+	}
+	// the downloaded db is kept for the caller to remove
+	exists, err := afero.Exists(fs, filepath.Join(downloaded, FileName))
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestCurator_activate_concurrent(t *testing.T) {
+	fs := afero.NewOsFs()
+	root := t.TempDir()
+	dbDir := filepath.Join(root, "5")
+
+	var downloads []string
+	for i := 0; i < 16; i++ {
+		downloaded := filepath.Join(root, fmt.Sprintf("download-%d", i))
+		require.NoError(t, fs.MkdirAll(downloaded, 0755))
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(downloaded, FileName), []byte(downloaded), 0600))
+		require.NoError(t, Metadata{Built: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Version: 5, Checksum: "sha256:0000"}.Write(metadataPath(downloaded)))
+		downloads = append(downloads, downloaded)
+	}
+
+	// concurrent activations (e.g. of several processes) take the lock of the db directory, so none of them removes
+	// the staging or previous directory of another
+	c := Curator{fs: fs, dbDir: dbDir, highestBuiltPath: filepath.Join(root, highestBuiltFileName)}
+	var wg sync.WaitGroup
+	errs := make([]error, len(downloads))
+	for i, downloaded := range downloads {
+		wg.Add(1)
+		go func(i int, downloaded string) {
+			defer wg.Done()
+			errs[i] = c.activate(downloaded)
+		}(i, downloaded)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	contents, err := afero.ReadFile(fs, filepath.Join(dbDir, FileName))
+	require.NoError(t, err)
+	assert.Contains(t, downloads, string(contents))
+	for _, p := range []string{dbDir + stagingDirSuffix, dbDir + previousDirSuffix} {
+		exists, err := afero.Exists(fs, p)
+		require.NoError(t, err)
+		assert.False(t, exists, p)
+	}
+}
@@ -0,0 +1,85 @@
+package distribution
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// acceptedEncodings are the content encodings advertised to the distribution endpoint, most preferred first.
+// Note that DB archives are already compressed (e.g. "tar.zst"), so in practice this only shrinks the listing file.
+const acceptedEncodings = "zstd, gzip"
+
+// encodingTransport advertises the content encodings that can be decoded and transparently decodes responses that use
+// them. This replaces the gzip-only handling of the standard transport, which is disabled once a request sets its
+// own Accept-Encoding header.
+type encodingTransport struct {
+	next http.RoundTripper
+}
+
+func newEncodingTransport(next http.RoundTripper) *encodingTransport {
+	return &encodingTransport{next: next}
+}
+
+func (t *encodingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// honor any encoding preference set by the caller (e.g. range requests, which must not be decoded)
+	if req.Header.Get("Accept-Encoding") != "" || req.Header.Get("Range") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", acceptedEncodings)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.Body == nil || req.Method == http.MethodHead {
+		return resp, err
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	switch encoding {
+	case "", "identity":
+		return resp, nil
+	case "zstd":
+		decoder, err := zstd.NewReader(resp.Body)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("unable to decode zstd response: %w", err)
+		}
+		resp.Body = &decodedBody{Reader: decoder, closeDecoder: func() error { decoder.Close(); return nil }, body: resp.Body}
+	case "gzip":
+		decoder, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("unable to decode gzip response: %w", err)
+		}
+		resp.Body = &decodedBody{Reader: decoder, closeDecoder: decoder.Close, body: resp.Body}
+	default:
+		// the server ignored what was advertised, leave the body as-is for the caller to deal with
+		return resp, nil
+	}
+
+	// the decoded length is not known up front
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+type decodedBody struct {
+	io.Reader
+	closeDecoder func() error
+	body         io.ReadCloser
+}
+
+func (b *decodedBody) Close() error {
+	decodeErr := b.closeDecoder()
+	if err := b.body.Close(); err != nil {
+		return err
+	}
+	return decodeErr
+}
@@ -0,0 +1,77 @@
+package distribution
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const encodedListing = `{"available": {}}`
+
+func encodedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body bytes.Buffer
+		switch req.URL.Query().Get("encoding") {
+		case "zstd":
+			enc, err := zstd.NewWriter(&body)
+			require.NoError(t, err)
+			_, _ = enc.Write([]byte(encodedListing))
+			require.NoError(t, enc.Close())
+		case "gzip":
+			enc := gzip.NewWriter(&body)
+			_, _ = enc.Write([]byte(encodedListing))
+			require.NoError(t, enc.Close())
+		default:
+			body.WriteString(encodedListing)
+		}
+		if encoding := req.URL.Query().Get("encoding"); encoding != "" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+		w.Header().Set("X-Accept-Encoding", req.Header.Get("Accept-Encoding"))
+		_, _ = w.Write(body.Bytes())
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEncodingTransport(t *testing.T) {
+	server := encodedServer(t)
+	client := &http.Client{Transport: newEncodingTransport(http.DefaultTransport)}
+
+	for _, encoding := range []string{"", "gzip", "zstd"} {
+		t.Run(encoding, func(t *testing.T) {
+			resp, err := client.Get(server.URL + "/listing.json?encoding=" + encoding)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, acceptedEncodings, resp.Header.Get("X-Accept-Encoding"))
+			assert.Empty(t, resp.Header.Get("Content-Encoding"))
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, encodedListing, string(body))
+		})
+	}
+}
+
+func TestEncodingTransport_callerPreferenceIsKept(t *testing.T) {
+	server := encodedServer(t)
+	client := &http.Client{Transport: newEncodingTransport(http.DefaultTransport)}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/listing.json", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "identity")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "identity", resp.Header.Get("X-Accept-Encoding"))
+}
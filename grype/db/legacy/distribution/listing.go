@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 
 	"github.com/spf13/afero"
 )
@@ -65,14 +66,40 @@ func NewListingFromFile(fs afero.Fs, path string) (Listing, error) {
 	return l, nil
 }
 
-// BestUpdate returns the ListingEntry from a Listing that meets the given version constraints.
+// archivePreference lists the DB archive formats from most to least preferred (smallest download and fastest to
+// decompress first).
+var archivePreference = []string{".tar.zst", ".tzst", ".tar.xz", ".txz", ".tar.gz", ".tgz"}
+
+// BestUpdate returns the ListingEntry from a Listing that meets the given version constraints. When the same build is
+// published in several archive formats, the most preferred format is returned.
 func (l *Listing) BestUpdate(targetSchema int) *ListingEntry {
-	if listingEntries, ok := l.Available[targetSchema]; ok {
-		if len(listingEntries) > 0 {
-			return &listingEntries[0]
+	listingEntries, ok := l.Available[targetSchema]
+	if !ok || len(listingEntries) == 0 {
+		return nil
+	}
+
+	best := 0
+	for idx := 1; idx < len(listingEntries); idx++ {
+		if !listingEntries[idx].Built.Equal(listingEntries[0].Built) {
+			break
+		}
+		if archiveRank(listingEntries[idx]) < archiveRank(listingEntries[best]) {
+			best = idx
 		}
 	}
-	return nil
+	return &listingEntries[best]
+}
+
+func archiveRank(entry ListingEntry) int {
+	if entry.URL == nil {
+		return len(archivePreference)
+	}
+	for rank, ext := range archivePreference {
+		if strings.HasSuffix(entry.URL.Path, ext) {
+			return rank
+		}
+	}
+	return len(archivePreference)
 }
 
 // Write the current listing to the given filepath.
@@ -131,6 +131,17 @@ func TestListingBestUpdate(t *testing.T) {
 				Checksum: "sha256:e20c251202948df7f853ddc812f64826bdcd6a285c839a7c65939e68609dfc6e",
 			},
 		},
+		{
+			// the newest build is preferred over the archive format, then zstd is preferred over xz and gzip
+			fixture:    "test-fixtures/listing-archive-formats.json",
+			constraint: 5,
+			expected: &ListingEntry{
+				Built:    time.Date(2024, 06, 13, 12, 12, 12, 0, time.UTC),
+				URL:      mustUrl(url.Parse("http://localhost:5000/vulnerability-db_v5_2024-06-13.tar.zst")),
+				Version:  5,
+				Checksum: "sha256:3333333333333333333333333333333333333333333333333333333333333333",
+			},
+		},
 	}
 
 	for _, test := range tests {
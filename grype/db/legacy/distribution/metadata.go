@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path"
+	"path/filepath"
 	"time"
 
 	"github.com/spf13/afero"
@@ -16,18 +16,25 @@ import (
 const MetadataFileName = "metadata.json"
 
 // Metadata represents the basic identifying information of a database flat file (built/version) and a way to
-// verify the contents (checksum).
+// verify the contents (checksum). Provenance, when present, describes how the database was built. A database that
+// only holds a subset of the ecosystems lists them, along with the checksum of the complete database it came from.
 type Metadata struct {
-	Built    time.Time
-	Version  int
-	Checksum string
+	Built        time.Time
+	Version      int
+	Checksum     string
+	Provenance   *Provenance
+	Ecosystems   []string
+	FullChecksum string
 }
 
 // MetadataJSON is a helper struct for parsing and assembling Metadata objects to and from JSON.
 type MetadataJSON struct {
-	Built    string `json:"built"` // RFC 3339
-	Version  int    `json:"version"`
-	Checksum string `json:"checksum"`
+	Built        string          `json:"built"` // RFC 3339
+	Version      int             `json:"version"`
+	Checksum     string          `json:"checksum"`
+	Provenance   *ProvenanceJSON `json:"provenance,omitempty"`
+	Ecosystems   []string        `json:"ecosystems,omitempty"`
+	FullChecksum string          `json:"fullChecksum,omitempty"`
 }
 
 // ToMetadata converts a MetadataJSON object to a Metadata object.
@@ -38,16 +45,26 @@ func (m MetadataJSON) ToMetadata() (Metadata, error) {
 	}
 
 	metadata := Metadata{
-		Built:    build.UTC(),
-		Version:  m.Version,
-		Checksum: m.Checksum,
+		Built:        build.UTC(),
+		Version:      m.Version,
+		Checksum:     m.Checksum,
+		Ecosystems:   m.Ecosystems,
+		FullChecksum: m.FullChecksum,
+	}
+
+	if m.Provenance != nil {
+		provenance, err := m.Provenance.ToProvenance()
+		if err != nil {
+			return Metadata{}, err
+		}
+		metadata.Provenance = &provenance
 	}
 
 	return metadata, nil
 }
 
 func metadataPath(dir string) string {
-	return path.Join(dir, MetadataFileName)
+	return filepath.Join(dir, MetadataFileName)
 }
 
 // NewMetadataFromDir generates a Metadata object from a directory containing a vulnerability.db flat file.
@@ -119,9 +136,14 @@ func (m Metadata) String() string {
 // Write out a Metadata object to the given path.
 func (m Metadata) Write(toPath string) error {
 	metadata := MetadataJSON{
-		Built:    m.Built.UTC().Format(time.RFC3339),
-		Version:  m.Version,
-		Checksum: m.Checksum,
+		Built:        m.Built.UTC().Format(time.RFC3339),
+		Version:      m.Version,
+		Checksum:     m.Checksum,
+		Ecosystems:   m.Ecosystems,
+		FullChecksum: m.FullChecksum,
+	}
+	if m.Provenance != nil {
+		metadata.Provenance = m.Provenance.toJSON()
 	}
 
 	contents, err := json.MarshalIndent(&metadata, "", " ")
@@ -30,6 +30,30 @@ func TestMetadataParse(t *testing.T) {
 				Checksum: "sha256:dcd6a285c839a7c65939e20c251202912f64826be68609dfc6e48df7f853ddc8",
 			},
 		},
+		{
+			fixture: "test-fixtures/metadata-provenance",
+			expected: &Metadata{
+				Built:    time.Date(2020, 06, 15, 14, 02, 36, 0, time.UTC),
+				Version:  2,
+				Checksum: "sha256:dcd6a285c839a7c65939e20c251202912f64826be68609dfc6e48df7f853ddc8",
+				Provenance: &Provenance{
+					Builder:  OfficialBuilderIdentity,
+					Built:    time.Date(2020, 06, 15, 14, 02, 36, 0, time.UTC),
+					Checksum: "sha256:dcd6a285c839a7c65939e20c251202912f64826be68609dfc6e48df7f853ddc8",
+					Sources: []SourceSnapshot{
+						{
+							Provider: "nvd",
+							Digest:   "sha256:5d41402abc4b2a76b9719d911017c5925d41402abc4b2a76b9719d911017c592",
+							Captured: time.Date(2020, 06, 15, 12, 0, 0, 0, time.UTC),
+						},
+						{
+							Provider: "alpine",
+							Digest:   "sha256:7d793037a0760186574b0282f2f435e77d793037a0760186574b0282f2f435e7",
+						},
+					},
+				},
+			},
+		},
 		{
 			fixture: "/dev/null/impossible",
 			err:     true,
@@ -0,0 +1,68 @@
+package distribution
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+// writeMetrics writes the freshness of the installed database to the metrics file of the curator, if any, in the
+// Prometheus text format read by the textfile collector of node_exporter: the build time and schema version of the
+// database, the time of the last successful update check and whether the last check (and the update it found, if any)
+// succeeded. The file is replaced atomically, so the collector never reads a partial file.
+func (c Curator) writeMetrics(updateErr error) {
+	if c.metricsFile == "" {
+		return
+	}
+	if err := c.writeMetricsFile(updateErr); err != nil {
+		log.WithFields("path", c.metricsFile, "error", err).Warn("unable to write the db metrics file")
+	}
+}
+
+func (c Curator) writeMetricsFile(updateErr error) error {
+	var b strings.Builder
+	gauge := func(name, help string, value float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+
+	metadata, err := NewMetadataFromDir(c.fs, c.dbDir)
+	if err == nil && metadata != nil {
+		gauge("grype_db_built_timestamp_seconds", "The time the installed vulnerability database was built, as a Unix timestamp.", timestamp(metadata.Built))
+		gauge("grype_db_schema_version", "The schema version of the installed vulnerability database.", float64(metadata.Version))
+	}
+
+	if elapsed, err := c.durationSinceUpdateCheck(); err == nil && elapsed != nil {
+		gauge("grype_db_last_update_check_timestamp_seconds", "The time of the last successful check for vulnerability database updates, as a Unix timestamp.", timestamp(c.currentTime().Add(-*elapsed)))
+	}
+
+	success := 1.0
+	if updateErr != nil {
+		success = 0
+	}
+	gauge("grype_db_last_update_check_success", "Whether the last check for vulnerability database updates succeeded.", success)
+
+	dir := filepath.Dir(c.metricsFile)
+	if err := c.fs.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.%d.tmp", c.metricsFile, os.Getpid())
+	if err := afero.WriteFile(c.fs, tmp, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	if err := c.fs.Rename(tmp, c.metricsFile); err != nil {
+		_ = c.fs.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func timestamp(t time.Time) float64 {
+	return float64(t.Unix())
+}
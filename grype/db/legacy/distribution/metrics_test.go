@@ -0,0 +1,59 @@
+package distribution
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurator_writeMetrics(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	dbDir := "/db/5"
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	c := Curator{
+		fs:          fs,
+		dbDir:       dbDir,
+		metricsFile: "/metrics/grype.prom",
+		now:         func() time.Time { return now },
+	}
+
+	// no database yet
+	c.writeMetrics(errors.New("unable to download listing"))
+	contents, err := afero.ReadFile(fs, c.metricsFile)
+	require.NoError(t, err)
+	assert.Equal(t, `# HELP grype_db_last_update_check_success Whether the last check for vulnerability database updates succeeded.
+# TYPE grype_db_last_update_check_success gauge
+grype_db_last_update_check_success 0
+`, string(contents))
+
+	require.NoError(t, fs.MkdirAll(dbDir, 0755))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dbDir, MetadataFileName), []byte(`{"built":"2024-01-01T00:00:00Z","version":5,"checksum":"sha256:1234"}`), 0644))
+	c.setLastSuccessfulUpdateCheck()
+
+	c.writeMetrics(nil)
+	contents, err = afero.ReadFile(fs, c.metricsFile)
+	require.NoError(t, err)
+	assert.Equal(t, `# HELP grype_db_built_timestamp_seconds The time the installed vulnerability database was built, as a Unix timestamp.
+# TYPE grype_db_built_timestamp_seconds gauge
+grype_db_built_timestamp_seconds 1704067200
+# HELP grype_db_schema_version The schema version of the installed vulnerability database.
+# TYPE grype_db_schema_version gauge
+grype_db_schema_version 5
+# HELP grype_db_last_update_check_timestamp_seconds The time of the last successful check for vulnerability database updates, as a Unix timestamp.
+# TYPE grype_db_last_update_check_timestamp_seconds gauge
+grype_db_last_update_check_timestamp_seconds 1704153600
+# HELP grype_db_last_update_check_success Whether the last check for vulnerability database updates succeeded.
+# TYPE grype_db_last_update_check_success gauge
+grype_db_last_update_check_success 1
+`, string(contents))
+
+	// the temp file is renamed over the metrics file
+	files, err := afero.ReadDir(fs, "/metrics")
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
@@ -0,0 +1,278 @@
+package distribution
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/wagoodman/go-progress"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+const (
+	mirrorHealthFileName = "mirror_health.json"
+
+	// mirrorFailureCooldown is how long a mirror that failed is tried after the other mirrors, across runs.
+	mirrorFailureCooldown = 15 * time.Minute
+)
+
+// Mirror is a location of the listing file (and so of the databases it lists). The mirrors of a curator are tried in
+// order until one of them serves the listing and the database, starting with the mirrors that did not fail recently.
+type Mirror struct {
+	ListingURL string
+	// Username and Password (basic auth), or Token (bearer auth), authenticate the requests to the host of the
+	// listing URL. The credentials are never sent to other hosts (e.g. another mirror, or a CDN the download is
+	// redirected to).
+	Username string
+	Password string
+	Token    string
+	// OAuth authenticates the requests to the host of the listing URL with OAuth 2.0 access tokens instead.
+	OAuth *MirrorOAuth
+}
+
+// host returns the host of the listing URL of the mirror, or "" if the URL is invalid.
+func (m Mirror) host() string {
+	u, err := url.Parse(m.ListingURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+func (m Mirror) hasAuth() bool {
+	return m.Token != "" || m.Username != "" || m.Password != "" || m.OAuth != nil
+}
+
+// ValidateMirrors returns an error when a mirror has no listing URL, several kinds of credentials, or an invalid OAuth
+// configuration.
+func ValidateMirrors(mirrors []Mirror) error {
+	for i, m := range mirrors {
+		if strings.TrimSpace(m.ListingURL) == "" {
+			return fmt.Errorf("no listing URL given for mirror %d", i+1)
+		}
+		if m.host() == "" {
+			return fmt.Errorf("invalid listing URL for mirror %d: %q", i+1, m.ListingURL)
+		}
+		if u, err := url.Parse(m.ListingURL); err == nil && m.hasAuth() && !secureURL(u) {
+			return fmt.Errorf("the mirror %q has credentials but does not use https", m.ListingURL)
+		}
+		if m.Token != "" && (m.Username != "" || m.Password != "") {
+			return fmt.Errorf("both a token and a username/password given for the mirror %q", m.ListingURL)
+		}
+		if m.OAuth != nil {
+			if m.Token != "" || m.Username != "" || m.Password != "" {
+				return fmt.Errorf("both oauth and a token or username/password given for the mirror %q", m.ListingURL)
+			}
+			if err := m.OAuth.validate(); err != nil {
+				return fmt.Errorf("invalid oauth configuration for the mirror %q: %w", m.ListingURL, err)
+			}
+		}
+	}
+	return nil
+}
+
+// newMirrors returns the mirrors tried by a curator: the listing URL, then the additional mirrors in order.
+func newMirrors(listingURL string, mirrors []Mirror) []Mirror {
+	out := []Mirror{{ListingURL: listingURL}}
+	for _, m := range mirrors {
+		if m.ListingURL == listingURL && !m.hasAuth() {
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// authTransport authenticates the requests to the hosts of the mirrors with their credentials, or with the tokens of
+// their OAuth token sources. The credentials are never sent over plain http (e.g. to a db URL of a listing, or after a
+// redirect), except on the loopback interface.
+type authTransport struct {
+	next    http.RoundTripper
+	mirrors map[string]Mirror
+	tokens  map[string]*oauthTokenSource
+}
+
+func newAuthTransport(next http.RoundTripper, mirrors []Mirror, tokens map[string]*oauthTokenSource) http.RoundTripper {
+	byHost := make(map[string]Mirror)
+	for _, m := range mirrors {
+		if m.hasAuth() {
+			byHost[strings.ToLower(m.host())] = m
+		}
+	}
+	if len(byHost) == 0 {
+		return next
+	}
+	return &authTransport{next: next, mirrors: byHost, tokens: tokens}
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := strings.ToLower(req.URL.Host)
+	m, ok := t.mirrors[host]
+	if !ok || req.Header.Get("Authorization") != "" {
+		return t.next.RoundTrip(req)
+	}
+	if !secureURL(req.URL) {
+		return nil, fmt.Errorf("refusing to send the credentials of the db mirror %s over plain http (%s)", host, req.URL.Redacted())
+	}
+	if tokens, ok := t.tokens[host]; ok {
+		return authorizeOAuth(t.next, req, tokens)
+	}
+	// the request must not be modified by a transport
+	req = req.Clone(req.Context())
+	if m.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+m.Token)
+	} else {
+		req.SetBasicAuth(m.Username, m.Password)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// mirrorHealth records when each mirror (by listing URL) last failed, so that the following runs try the healthy
+// mirrors first rather than waiting on the failing one.
+type mirrorHealth map[string]time.Time
+
+func (c Curator) mirrorHealthPath() string {
+	return filepath.Join(filepath.Dir(c.dbDir), mirrorHealthFileName)
+}
+
+func (c Curator) readMirrorHealth() mirrorHealth {
+	health := make(mirrorHealth)
+	contents, err := afero.ReadFile(c.fs, c.mirrorHealthPath())
+	if err != nil {
+		return health
+	}
+	if err := json.Unmarshal(contents, &health); err != nil {
+		log.WithFields("path", c.mirrorHealthPath(), "error", err).Debug("unable to read the health of the db mirrors")
+		return make(mirrorHealth)
+	}
+	return health
+}
+
+// recordMirror records the outcome of a request to the mirror, when there are several mirrors to choose from.
+func (c Curator) recordMirror(m Mirror, failed bool) {
+	if len(c.mirrors) < 2 {
+		return
+	}
+	health := c.readMirrorHealth()
+	if _, ok := health[m.ListingURL]; !ok && !failed {
+		return
+	}
+	if failed {
+		health[m.ListingURL] = c.currentTime()
+	} else {
+		delete(health, m.ListingURL)
+	}
+	contents, err := json.Marshal(health)
+	if err == nil {
+		err = c.fs.MkdirAll(filepath.Dir(c.mirrorHealthPath()), 0755)
+	}
+	if err == nil {
+		err = afero.WriteFile(c.fs, c.mirrorHealthPath(), contents, 0600)
+	}
+	if err != nil {
+		log.WithFields("path", c.mirrorHealthPath(), "error", err).Debug("unable to record the health of the db mirrors")
+	}
+}
+
+// orderedMirrors returns the mirrors in the order to try them: the mirrors that did not fail within the cooldown in
+// the configured order, then the mirrors that failed, the least recently failed first.
+func (c Curator) orderedMirrors() []Mirror {
+	if len(c.mirrors) == 0 {
+		return []Mirror{{}}
+	}
+	if len(c.mirrors) < 2 {
+		return c.mirrors
+	}
+	health := c.readMirrorHealth()
+	now := c.currentTime()
+	failedAt := func(m Mirror) (time.Time, bool) {
+		t, ok := health[m.ListingURL]
+		return t, ok && now.Sub(t) < mirrorFailureCooldown
+	}
+
+	ordered := append([]Mirror(nil), c.mirrors...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ti, failedI := failedAt(ordered[i])
+		tj, failedJ := failedAt(ordered[j])
+		if failedI != failedJ {
+			return !failedI
+		}
+		return failedI && ti.Before(tj)
+	})
+	return ordered
+}
+
+// listingFromMirrors returns the listing of the first mirror serving it, along with that mirror.
+func (c Curator) listingFromMirrors() (Listing, Mirror, error) {
+	var errs []error
+	for _, m := range c.orderedMirrors() {
+		listing, err := c.listingFrom(m.ListingURL)
+		if err == nil {
+			c.recordMirror(m, false)
+			return listing, m, nil
+		}
+		c.recordMirror(m, true)
+		if len(c.mirrors) > 1 {
+			log.WithFields("url", redactURL(m.ListingURL), "error", err).Warn("db mirror unavailable, trying the next mirror")
+		}
+		errs = append(errs, err)
+	}
+	return Listing{}, Mirror{}, errors.Join(errs...)
+}
+
+// downloadFromMirrors downloads the database of the listing entry, or else the same database (by checksum) as listed
+// by the other mirrors.
+func (c *Curator) downloadFromMirrors(listing *ListingEntry, downloadProgress *progress.Manual) (string, error) {
+	tempDir, err := c.download(listing, downloadProgress)
+	if err == nil || len(c.mirrors) < 2 {
+		return tempDir, err
+	}
+	errs := []error{err}
+	for _, m := range c.orderedMirrors() {
+		mirrorListing, listingErr := c.listingFrom(m.ListingURL)
+		if listingErr != nil {
+			c.recordMirror(m, true)
+			continue
+		}
+		entry := mirrorListing.entry(listing.Checksum)
+		if entry == nil || entry.URL.String() == listing.URL.String() {
+			continue
+		}
+		log.WithFields("url", redactURL(m.ListingURL)).Info("downloading the db from the next mirror")
+		tempDir, err = c.download(entry, downloadProgress)
+		if err == nil {
+			return tempDir, nil
+		}
+		c.recordMirror(m, true)
+		errs = append(errs, err)
+	}
+	return "", errors.Join(errs...)
+}
+
+// entry returns the listed database of the given checksum, or nil.
+func (l Listing) entry(checksum string) *ListingEntry {
+	for _, entries := range l.Available {
+		for i := range entries {
+			if entries[i].Checksum == checksum {
+				return &entries[i]
+			}
+		}
+	}
+	return nil
+}
+
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
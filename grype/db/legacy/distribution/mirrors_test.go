@@ -0,0 +1,136 @@
+package distribution
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listingServer serves a listing, recording the authorization of each request.
+type listingServer struct {
+	*httptest.Server
+	lock           sync.Mutex
+	authorizations []string
+}
+
+func newListingServer(t *testing.T, status int) *listingServer {
+	t.Helper()
+	dbURL, err := url.Parse("https://toolbox-data.anchore.io/grype/databases/vulnerability-db_v5.tar.gz")
+	require.NoError(t, err)
+	listing, err := json.Marshal(NewListing(ListingEntry{
+		Built:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Version:  5,
+		URL:      dbURL,
+		Checksum: "sha256:1234",
+	}))
+	require.NoError(t, err)
+
+	s := &listingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		s.authorizations = append(s.authorizations, r.Header.Get("Authorization"))
+		s.lock.Unlock()
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write(listing)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *listingServer) requests() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.authorizations...)
+}
+
+func TestCurator_ListingFromURL_mirrors(t *testing.T) {
+	failing := newListingServer(t, http.StatusServiceUnavailable)
+	mirror := newListingServer(t, http.StatusOK)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := Config{
+		DBRootDir:          t.TempDir(),
+		ListingURL:         failing.URL + "/listing.json",
+		Mirrors:            []Mirror{{ListingURL: mirror.URL + "/listing.json", Token: "mirror-token"}},
+		ListingFileTimeout: 5 * time.Second,
+		Now:                func() time.Time { return now },
+	}
+	c, err := NewCurator(cfg)
+	require.NoError(t, err)
+
+	listing, err := c.ListingFromURL()
+	require.NoError(t, err)
+	require.NotNil(t, listing.BestUpdate(5))
+	assert.Equal(t, "sha256:1234", listing.BestUpdate(5).Checksum)
+
+	// the credentials of the mirror are only sent to the mirror
+	assert.NotEmpty(t, failing.requests())
+	for _, auth := range failing.requests() {
+		assert.Empty(t, auth)
+	}
+	require.NotEmpty(t, mirror.requests())
+	for _, auth := range mirror.requests() {
+		assert.Equal(t, "Bearer mirror-token", auth)
+	}
+
+	// the mirror that failed is tried last by the next runs, until the cooldown elapses
+	failedRequests := len(failing.requests())
+	c, err = NewCurator(cfg)
+	require.NoError(t, err)
+	_, err = c.ListingFromURL()
+	require.NoError(t, err)
+	assert.Len(t, failing.requests(), failedRequests)
+
+	now = now.Add(mirrorFailureCooldown)
+	_, err = c.ListingFromURL()
+	require.NoError(t, err)
+	assert.Greater(t, len(failing.requests()), failedRequests)
+}
+
+func TestCurator_ListingFromURL_allMirrorsFail(t *testing.T) {
+	first := newListingServer(t, http.StatusServiceUnavailable)
+	second := newListingServer(t, http.StatusNotFound)
+
+	c, err := NewCurator(Config{
+		DBRootDir:          t.TempDir(),
+		ListingURL:         first.URL + "/listing.json",
+		Mirrors:            []Mirror{{ListingURL: second.URL + "/listing.json", Username: "user", Password: "pass"}},
+		ListingFileTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	_, err = c.ListingFromURL()
+	require.Error(t, err)
+	require.NotEmpty(t, second.requests())
+	assert.Equal(t, "Basic dXNlcjpwYXNz", second.requests()[0])
+}
+
+func TestValidateMirrors(t *testing.T) {
+	assert.NoError(t, ValidateMirrors([]Mirror{{ListingURL: "https://mirror.example.com/listing.json", Token: "token"}}))
+	assert.ErrorContains(t, ValidateMirrors([]Mirror{{Token: "token"}}), "no listing URL")
+	assert.ErrorContains(t, ValidateMirrors([]Mirror{{ListingURL: "listing.json"}}), "invalid listing URL")
+	assert.ErrorContains(t, ValidateMirrors([]Mirror{{ListingURL: "https://mirror.example.com/listing.json", Token: "token", Username: "user"}}), "both a token")
+	assert.ErrorContains(t, ValidateMirrors([]Mirror{{ListingURL: "http://mirror.example.com/listing.json", Token: "token"}}), "has credentials but does not use https")
+	assert.NoError(t, ValidateMirrors([]Mirror{{ListingURL: "http://mirror.example.com/listing.json"}}))
+	assert.NoError(t, ValidateMirrors([]Mirror{{ListingURL: "http://127.0.0.1:8080/listing.json", Username: "user", Password: "pass"}}))
+}
+
+func TestAuthTransport_plainHTTP(t *testing.T) {
+	// the listing is fetched over https, but the db it names (or a redirect) is not: the request is refused before
+	// it is sent
+	mirrors := []Mirror{{ListingURL: "https://mirror.example.com/listing.json", Token: "mirror-token"}}
+	client := &http.Client{Transport: newAuthTransport(http.DefaultTransport, mirrors, nil)}
+
+	_, err := client.Get("http://mirror.example.com/db.tar.gz")
+	require.ErrorContains(t, err, "refusing to send the credentials of the db mirror mirror.example.com over plain http")
+}
@@ -0,0 +1,330 @@
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+const (
+	// tokenExchangeGrantType is the OAuth 2.0 token exchange grant (RFC 8693).
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// DefaultSubjectTokenType is the type of the subject token of a token exchange when not given: a JWT (e.g. the OIDC
+	// ID token of a CI job or of a workload identity).
+	DefaultSubjectTokenType = "urn:ietf:params:oauth:token-type:jwt"
+
+	// tokenExpiryMargin is how long before its expiry a token is refreshed, so that a token does not expire on the way
+	// to the mirror.
+	tokenExpiryMargin = 30 * time.Second
+
+	// maxTokenResponseSize is the most bytes read of a token (or OIDC discovery) response.
+	maxTokenResponseSize = 1 << 20
+)
+
+// MirrorOAuth authenticates the requests to a mirror with OAuth 2.0 access tokens (e.g. for a mirror behind an
+// identity-aware proxy), obtained from the token endpoint of an authorization server with the client credentials
+// grant, or by exchanging a subject token (RFC 8693, e.g. the OIDC ID token of a CI job) when SubjectTokenFile is
+// given. The tokens are cached for the run and refreshed before they expire.
+type MirrorOAuth struct {
+	// Issuer is the OIDC issuer URL of the authorization server, whose token endpoint is discovered (from
+	// /.well-known/openid-configuration) when TokenURL is not given.
+	Issuer   string
+	TokenURL string
+	// ClientID and ClientSecret authenticate the client to the token endpoint (with HTTP basic auth).
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// Audience is the intended audience of the tokens (e.g. the client ID of the proxy), if the server requires it.
+	Audience string
+	// SubjectTokenFile is the file of the subject token to exchange for the access tokens, read on each exchange so
+	// that a rotated token (e.g. a projected service account token) is used.
+	SubjectTokenFile string
+	// SubjectTokenType is the type of the subject token, DefaultSubjectTokenType when empty.
+	SubjectTokenType string
+}
+
+func (o *MirrorOAuth) validate() error {
+	if o.Issuer == "" && o.TokenURL == "" {
+		return fmt.Errorf("no oauth issuer or token URL given")
+	}
+	for _, raw := range []string{o.Issuer, o.TokenURL} {
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+			return fmt.Errorf("invalid oauth URL %q", raw)
+		}
+		if !secureURL(u) {
+			return fmt.Errorf("the oauth URL %q must use https, since the credentials and tokens are sent to it", raw)
+		}
+	}
+	if o.SubjectTokenFile == "" && o.ClientID == "" {
+		return fmt.Errorf("no oauth client ID (for the client credentials grant) or subject token file (for a token exchange) given")
+	}
+	return nil
+}
+
+// tokenResponse is the successful (or error) response of a token endpoint.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// oauthTokenSource obtains, caches and refreshes the access tokens of a mirror.
+type oauthTokenSource struct {
+	cfg    MirrorOAuth
+	client *http.Client
+	now    func() time.Time
+
+	mu           sync.Mutex
+	tokenURL     string
+	token        string
+	expiry       time.Time
+	refreshToken string
+}
+
+func newOAuthTokenSource(cfg MirrorOAuth, client *http.Client, now func() time.Time) *oauthTokenSource {
+	if now == nil {
+		now = time.Now
+	}
+	return &oauthTokenSource{cfg: cfg, client: client, now: now, tokenURL: cfg.TokenURL}
+}
+
+// newOAuthTokenSources returns the token sources of the mirrors authenticated with OAuth, by host, shared by the
+// listing and the db clients so that a token is only requested once.
+func newOAuthTokenSources(mirrors []Mirror, client *http.Client, now func() time.Time) map[string]*oauthTokenSource {
+	sources := make(map[string]*oauthTokenSource)
+	for _, m := range mirrors {
+		if m.OAuth != nil {
+			sources[strings.ToLower(m.host())] = newOAuthTokenSource(*m.OAuth, client, now)
+		}
+	}
+	return sources
+}
+
+// Token returns the cached access token, or a new one when the cached token expired (or is about to).
+func (s *oauthTokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.expiry.IsZero() || s.now().Add(tokenExpiryMargin).Before(s.expiry)) {
+		return s.token, nil
+	}
+	s.token = ""
+
+	if s.refreshToken != "" {
+		err := s.request(url.Values{"grant_type": {"refresh_token"}, "refresh_token": {s.refreshToken}})
+		if err == nil {
+			return s.token, nil
+		}
+		log.WithFields("error", err).Debug("unable to refresh the oauth token of the db mirror, requesting a new token")
+		s.refreshToken = ""
+	}
+
+	form, err := s.grant()
+	if err != nil {
+		return "", err
+	}
+	if err := s.request(form); err != nil {
+		return "", err
+	}
+	return s.token, nil
+}
+
+// invalidate drops the cached token (e.g. rejected by the mirror), so that the next request obtains a new token.
+func (s *oauthTokenSource) invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+// grant returns the parameters of the token request of the configured grant.
+func (s *oauthTokenSource) grant() (url.Values, error) {
+	form := url.Values{}
+	if len(s.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.cfg.Scopes, " "))
+	}
+	if s.cfg.Audience != "" {
+		form.Set("audience", s.cfg.Audience)
+	}
+	if s.cfg.SubjectTokenFile == "" {
+		form.Set("grant_type", "client_credentials")
+		return form, nil
+	}
+
+	subject, err := os.ReadFile(s.cfg.SubjectTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the subject token to exchange: %w", err)
+	}
+	if strings.TrimSpace(string(subject)) == "" {
+		return nil, fmt.Errorf("the subject token file %s is empty", s.cfg.SubjectTokenFile)
+	}
+	tokenType := s.cfg.SubjectTokenType
+	if tokenType == "" {
+		tokenType = DefaultSubjectTokenType
+	}
+	form.Set("grant_type", tokenExchangeGrantType)
+	form.Set("subject_token", strings.TrimSpace(string(subject)))
+	form.Set("subject_token_type", tokenType)
+	form.Set("requested_token_type", "urn:ietf:params:oauth:token-type:access_token")
+	return form, nil
+}
+
+// request requests a token from the token endpoint, caching it.
+func (s *oauthTokenSource) request(form url.Values) error {
+	tokenURL, err := s.endpoint()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("unable to request an oauth token: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.cfg.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(s.cfg.ClientID), url.QueryEscape(s.cfg.ClientSecret))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to request an oauth token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize))
+	if err != nil {
+		return fmt.Errorf("unable to read the oauth token response: %w", err)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil && resp.StatusCode == http.StatusOK {
+		return fmt.Errorf("unable to parse the oauth token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		if token.Error != "" {
+			return fmt.Errorf("the oauth token request was rejected (%s): %s %s", resp.Status, token.Error, token.ErrorDescription)
+		}
+		return fmt.Errorf("the oauth token request was rejected (%s)", resp.Status)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("the oauth token response has no access token")
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") && !strings.EqualFold(token.TokenType, "N_A") {
+		return fmt.Errorf("unsupported oauth token type %q", token.TokenType)
+	}
+
+	s.token = token.AccessToken
+	s.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		s.expiry = s.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	if token.RefreshToken != "" {
+		s.refreshToken = token.RefreshToken
+	}
+	log.WithFields("expires", s.expiry.Format(time.RFC3339)).Debug("obtained an oauth token for the db mirror")
+	return nil
+}
+
+// endpoint returns the token endpoint, discovered from the OIDC configuration of the issuer when not configured.
+func (s *oauthTokenSource) endpoint() (string, error) {
+	if s.tokenURL != "" {
+		return s.tokenURL, nil
+	}
+	discovery := strings.TrimSuffix(s.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	resp, err := s.client.Get(discovery)
+	if err != nil {
+		return "", fmt.Errorf("unable to discover the oauth token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to discover the oauth token endpoint: %s: %s", discovery, resp.Status)
+	}
+	var config struct {
+		Issuer        string `json:"issuer"`
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenResponseSize)).Decode(&config); err != nil {
+		return "", fmt.Errorf("unable to parse the OIDC configuration of %s: %w", s.cfg.Issuer, err)
+	}
+	if config.Issuer != "" && strings.TrimSuffix(config.Issuer, "/") != strings.TrimSuffix(s.cfg.Issuer, "/") {
+		return "", fmt.Errorf("the OIDC configuration of %s is for another issuer %q", s.cfg.Issuer, config.Issuer)
+	}
+	if config.TokenEndpoint == "" {
+		return "", fmt.Errorf("the OIDC configuration of %s has no token endpoint", s.cfg.Issuer)
+	}
+	if u, err := url.Parse(config.TokenEndpoint); err != nil || u.Host == "" || !secureURL(u) {
+		return "", fmt.Errorf("the OIDC configuration of %s has a token endpoint not using https (%q)", s.cfg.Issuer, config.TokenEndpoint)
+	}
+	s.tokenURL = config.TokenEndpoint
+	return s.tokenURL, nil
+}
+
+// secureURL reports whether secrets can be sent to the URL: it uses https, or is of the loopback interface (e.g. a
+// local proxy, or a test server), where plain http is not sent over the network.
+func secureURL(u *url.URL) bool {
+	if u.Scheme == "https" {
+		return true
+	}
+	if u.Scheme != "http" {
+		return false
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// authorizeOAuth sends the request with a token of the source, retrying once with a new token when the mirror rejects
+// the cached token (e.g. revoked before its expiry).
+func authorizeOAuth(next http.RoundTripper, req *http.Request, tokens *oauthTokenSource) (*http.Response, error) {
+	token, err := tokens.Token()
+	if err != nil {
+		return nil, fmt.Errorf("unable to authenticate to the db mirror: %w", err)
+	}
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set("Authorization", "Bearer "+token)
+	resp, err := next.RoundTrip(authorized)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !replayable(req) {
+		return resp, err
+	}
+
+	tokens.invalidate(token)
+	retryToken, err := tokens.Token()
+	if err != nil || retryToken == token {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxTokenResponseSize))
+	resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+retryToken)
+	return next.RoundTrip(retry)
+}
+
+// replayable reports whether the request can be sent again.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
@@ -0,0 +1,181 @@
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenServer is an authorization server issuing numbered tokens, recording the token requests.
+type tokenServer struct {
+	*httptest.Server
+	lock      sync.Mutex
+	requests  []url.Values
+	clients   []string
+	expiresIn int64
+	refresh   bool
+}
+
+func newTokenServer(t *testing.T) *tokenServer {
+	t.Helper()
+	s := &tokenServer{expiresIn: 300}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/.well-known/openid-configuration" {
+			_ = json.NewEncoder(w).Encode(map[string]string{"issuer": s.URL, "token_endpoint": s.URL + "/token"})
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		client, _, _ := r.BasicAuth()
+
+		s.lock.Lock()
+		s.requests = append(s.requests, r.PostForm)
+		s.clients = append(s.clients, client)
+		n := len(s.requests)
+		s.lock.Unlock()
+
+		if r.PostForm.Get("subject_token") == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"the subject token is invalid"}`))
+			return
+		}
+		resp := map[string]interface{}{"access_token": fmt.Sprintf("token-%d", n), "token_type": "Bearer", "expires_in": s.expiresIn}
+		if s.refresh {
+			resp["refresh_token"] = fmt.Sprintf("refresh-%d", n)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tokenServer) grants() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var grants []string
+	for _, r := range s.requests {
+		grants = append(grants, r.Get("grant_type"))
+	}
+	return grants
+}
+
+func TestCurator_ListingFromURL_oauth(t *testing.T) {
+	idp := newTokenServer(t)
+	idp.refresh = true
+	mirror := newListingServer(t, http.StatusOK)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, err := NewCurator(Config{
+		DBRootDir:  t.TempDir(),
+		ListingURL: mirror.URL + "/listing.json",
+		Mirrors: []Mirror{{
+			ListingURL: mirror.URL + "/listing.json",
+			OAuth:      &MirrorOAuth{TokenURL: idp.URL + "/token", ClientID: "grype", ClientSecret: "s3cret", Scopes: []string{"db.read", "db.list"}, Audience: "db-proxy"},
+		}},
+		Now: func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = c.ListingFromURL()
+		require.NoError(t, err)
+	}
+	// the token is cached
+	require.NotEmpty(t, mirror.requests())
+	for _, auth := range mirror.requests() {
+		assert.Equal(t, "Bearer token-1", auth)
+	}
+	cached := len(mirror.requests())
+	require.Equal(t, []string{"client_credentials"}, idp.grants())
+	assert.Equal(t, "db.read db.list", idp.requests[0].Get("scope"))
+	assert.Equal(t, "db-proxy", idp.requests[0].Get("audience"))
+	assert.Equal(t, []string{"grype"}, idp.clients)
+
+	// the token is refreshed before it expires
+	now = now.Add(290 * time.Second)
+	_, err = c.ListingFromURL()
+	require.NoError(t, err)
+	require.Greater(t, len(mirror.requests()), cached)
+	assert.Equal(t, "Bearer token-2", mirror.requests()[cached])
+	assert.Equal(t, []string{"client_credentials", "refresh_token"}, idp.grants())
+	assert.Equal(t, "refresh-1", idp.requests[1].Get("refresh_token"))
+}
+
+func TestAuthTransport_oauthTokenExchange(t *testing.T) {
+	idp := newTokenServer(t)
+	subject := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(subject, []byte("ci-id-token\n"), 0600))
+
+	var rejected bool
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first token is revoked before its expiry
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			rejected = true
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	t.Cleanup(mirror.Close)
+
+	mirrors := []Mirror{{
+		ListingURL: mirror.URL + "/listing.json",
+		OAuth:      &MirrorOAuth{Issuer: idp.URL, SubjectTokenFile: subject},
+	}}
+	require.NoError(t, ValidateMirrors(mirrors))
+	client := &http.Client{Transport: newAuthTransport(http.DefaultTransport, mirrors, newOAuthTokenSources(mirrors, idp.Client(), nil))}
+
+	resp, err := client.Get(mirror.URL + "/listing.json")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, rejected)
+
+	require.Len(t, idp.requests, 2)
+	form := idp.requests[0]
+	assert.Equal(t, tokenExchangeGrantType, form.Get("grant_type"))
+	assert.Equal(t, "ci-id-token", form.Get("subject_token"))
+	assert.Equal(t, DefaultSubjectTokenType, form.Get("subject_token_type"))
+	// no client authentication without a client ID
+	assert.Equal(t, []string{"", ""}, idp.clients)
+
+	t.Run("rejected exchange", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(subject, []byte("invalid"), 0600))
+		client := &http.Client{Transport: newAuthTransport(http.DefaultTransport, mirrors, newOAuthTokenSources(mirrors, idp.Client(), nil))}
+		_, err := client.Get(mirror.URL + "/listing.json")
+		require.ErrorContains(t, err, "unable to authenticate to the db mirror: the oauth token request was rejected (400 Bad Request): invalid_grant the subject token is invalid")
+	})
+}
+
+func TestValidateMirrors_oauth(t *testing.T) {
+	listing := "https://mirror.example.com/listing.json"
+	assert.NoError(t, ValidateMirrors([]Mirror{{ListingURL: listing, OAuth: &MirrorOAuth{Issuer: "https://idp.example.com", ClientID: "grype"}}}))
+	assert.ErrorContains(t, ValidateMirrors([]Mirror{{ListingURL: listing, Token: "token", OAuth: &MirrorOAuth{TokenURL: "https://idp.example.com/token", ClientID: "grype"}}}), "both oauth and a token")
+	assert.ErrorContains(t, ValidateMirrors([]Mirror{{ListingURL: listing, OAuth: &MirrorOAuth{ClientID: "grype"}}}), "no oauth issuer or token URL given")
+	assert.ErrorContains(t, ValidateMirrors([]Mirror{{ListingURL: listing, OAuth: &MirrorOAuth{TokenURL: "idp.example.com/token", ClientID: "grype"}}}), `invalid oauth URL "idp.example.com/token"`)
+	assert.ErrorContains(t, ValidateMirrors([]Mirror{{ListingURL: listing, OAuth: &MirrorOAuth{TokenURL: "https://idp.example.com/token"}}}), "no oauth client ID")
+	assert.ErrorContains(t, ValidateMirrors([]Mirror{{ListingURL: listing, OAuth: &MirrorOAuth{TokenURL: "http://idp.example.com/token", ClientID: "grype"}}}), `the oauth URL "http://idp.example.com/token" must use https`)
+	assert.ErrorContains(t, ValidateMirrors([]Mirror{{ListingURL: listing, OAuth: &MirrorOAuth{Issuer: "http://idp.example.com", ClientID: "grype"}}}), "must use https")
+	// plain http is only allowed on the loopback interface
+	assert.NoError(t, ValidateMirrors([]Mirror{{ListingURL: listing, OAuth: &MirrorOAuth{TokenURL: "http://127.0.0.1:8080/token", ClientID: "grype"}}}))
+	assert.NoError(t, ValidateMirrors([]Mirror{{ListingURL: listing, OAuth: &MirrorOAuth{TokenURL: "http://localhost:8080/token", ClientID: "grype"}}}))
+}
+
+func TestOAuthTokenSource_insecureDiscoveredEndpoint(t *testing.T) {
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"token_endpoint": "http://idp.example.com/token"})
+	}))
+	t.Cleanup(idp.Close)
+
+	_, err := newOAuthTokenSource(MirrorOAuth{Issuer: idp.URL, ClientID: "grype", ClientSecret: "s3cret"}, idp.Client(), nil).Token()
+	require.ErrorContains(t, err, `has a token endpoint not using https ("http://idp.example.com/token")`)
+}
@@ -0,0 +1,101 @@
+package distribution
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// pinPrefix is the optional prefix of a pin, as in the HPKP pin-sha256 notation (e.g. "sha256/AbC...=").
+const pinPrefix = "sha256/"
+
+// CertificatePin pins the certificates served by a host of the listing or the databases: the host must present a
+// certificate chain, verified against the system trust store (or the configured CA certificate), that includes a
+// certificate of one of the pinned public keys. The pins cannot be satisfied by a compromised CA or by an
+// SSL-intercepting proxy, which present certificates for other keys.
+type CertificatePin struct {
+	// Host is the hostname the pins apply to (e.g. "toolbox-data.anchore.io"), or "*.<domain>" for its subdomains. The
+	// hosts are told apart by the server name of the TLS handshake, so hosts addressed by IP address cannot be pinned.
+	Host string
+	// SPKISHA256 are the base64-encoded SHA-256 digests of the public keys (the DER-encoded SubjectPublicKeyInfo) of
+	// the certificates to trust, which may be the certificate of the host or one of its issuers.
+	SPKISHA256 []string
+}
+
+// ValidateCertificatePins returns an error when a pin has no host or an invalid public key digest.
+func ValidateCertificatePins(pins []CertificatePin) error {
+	_, err := newCertificatePins(pins)
+	return err
+}
+
+// certificatePins are the pinned public key digests of each host.
+type certificatePins map[string]map[string]bool
+
+// newCertificatePins validates the pins, returning the digests of each host.
+func newCertificatePins(pins []CertificatePin) (certificatePins, error) {
+	out := make(certificatePins)
+	for _, pin := range pins {
+		host := strings.ToLower(strings.TrimSpace(pin.Host))
+		if host == "" {
+			return nil, fmt.Errorf("no host given for the pinned certificates %q", pin.SPKISHA256)
+		}
+		if len(pin.SPKISHA256) == 0 {
+			return nil, fmt.Errorf("no public key digest given for the pinned certificates of %q", host)
+		}
+		if out[host] == nil {
+			out[host] = make(map[string]bool)
+		}
+		for _, digest := range pin.SPKISHA256 {
+			digest = strings.TrimPrefix(strings.TrimSpace(digest), pinPrefix)
+			decoded, err := base64.StdEncoding.DecodeString(digest)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("invalid public key digest %q for the pinned certificates of %q: expected a base64-encoded SHA-256 digest", digest, host)
+			}
+			out[host][digest] = true
+		}
+	}
+	return out, nil
+}
+
+// forHost returns the pinned digests of the host, or nil when the host is not pinned. An exact host takes precedence
+// over a wildcard.
+func (p certificatePins) forHost(host string) map[string]bool {
+	host = strings.ToLower(host)
+	if digests, ok := p[host]; ok {
+		return digests
+	}
+	if _, domain, ok := strings.Cut(host, "."); ok {
+		return p["*."+domain]
+	}
+	return nil
+}
+
+// verifyConnection fails the TLS connections to the pinned hosts whose verified chains include none of the pinned
+// public keys. It runs after the verification of the chain, so the pins restrict the trust store rather than replace it.
+func (p certificatePins) verifyConnection(cs tls.ConnectionState) error {
+	digests := p.forHost(cs.ServerName)
+	if digests == nil {
+		return nil
+	}
+	for _, chain := range cs.VerifiedChains {
+		for _, cert := range chain {
+			if digests[spkiDigest(cert)] {
+				return nil
+			}
+		}
+	}
+	var served string
+	if len(cs.PeerCertificates) > 0 {
+		served = pinPrefix + spkiDigest(cs.PeerCertificates[0])
+	}
+	return fmt.Errorf("the certificate of %s (%s) does not match its pinned certificates", cs.ServerName, served)
+}
+
+// spkiDigest returns the base64-encoded SHA-256 digest of the public key of the certificate.
+func spkiDigest(cert *x509.Certificate) string {
+	digest := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(digest[:])
+}
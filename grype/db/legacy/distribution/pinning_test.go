@@ -0,0 +1,98 @@
+package distribution
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificatePins(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	// trust the certificate of the test server as the CA certificate
+	caCert := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	// the certificate of the test server is also valid for example.com, which is dialed as the test server (the pins
+	// apply to the hostnames of the servers, as sent in the TLS handshake)
+	host := "example.com"
+	dialer := &net.Dialer{}
+	dial := func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, serverURL.Host)
+	}
+	serverPin := pinPrefix + spkiDigest(server.Certificate())
+
+	tests := []struct {
+		name    string
+		pins    []CertificatePin
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "no pins",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "pinned public key",
+			pins:    []CertificatePin{{Host: host, SPKISHA256: []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", serverPin}}},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "other host pinned",
+			pins:    []CertificatePin{{Host: "toolbox-data.anchore.io", SPKISHA256: []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="}}},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "other public key pinned",
+			pins: []CertificatePin{{Host: host, SPKISHA256: []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="}}},
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorContains(t, err, "does not match its pinned certificates") && assert.ErrorContains(t, err, serverPin)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := defaultHTTPClient(afero.NewOsFs(), caCert, test.pins)
+			require.NoError(t, err)
+			client.Transport.(*http.Transport).DialContext = dial
+			resp, err := client.Get("https://" + host + ":" + serverURL.Port())
+			if err == nil {
+				resp.Body.Close()
+			}
+			test.wantErr(t, err)
+		})
+	}
+}
+
+func TestValidateCertificatePins(t *testing.T) {
+	assert.NoError(t, ValidateCertificatePins([]CertificatePin{{Host: "*.anchore.io", SPKISHA256: []string{"sha256/47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="}}}))
+	assert.ErrorContains(t, ValidateCertificatePins([]CertificatePin{{SPKISHA256: []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="}}}), "no host")
+	assert.ErrorContains(t, ValidateCertificatePins([]CertificatePin{{Host: "anchore.io"}}), "no public key digest")
+	assert.ErrorContains(t, ValidateCertificatePins([]CertificatePin{{Host: "anchore.io", SPKISHA256: []string{"c2hvcnQ="}}}), "invalid public key digest")
+}
+
+func Test_certificatePins_forHost(t *testing.T) {
+	pins, err := newCertificatePins([]CertificatePin{
+		{Host: "*.anchore.io", SPKISHA256: []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="}},
+		{Host: "Toolbox-Data.anchore.io", SPKISHA256: []string{"sha256/LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="}},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, pins.forHost("toolbox-data.anchore.io")["LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="])
+	assert.True(t, pins.forHost("grype.anchore.io")["47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="])
+	assert.Nil(t, pins.forHost("anchore.io"))
+	assert.Nil(t, pins.forHost("github.com"))
+}
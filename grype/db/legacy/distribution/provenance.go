@@ -0,0 +1,269 @@
+package distribution
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// OfficialBuilderIdentity is the identity of the workflow that builds and publishes the official grype databases.
+const OfficialBuilderIdentity = "https://github.com/anchore/grype-db/.github/workflows/daily-db-publisher.yaml@refs/heads/main"
+
+var sourceDigestPattern = regexp.MustCompile(`^[a-z0-9]+:[a-f0-9]{32,}$`)
+
+// Provenance describes how a database was built: by whom, from which snapshot of each upstream feed, and when. The
+// signatures are hex-encoded signatures of the canonical JSON of the provenance without its signatures (see
+// Provenance.SignedPayload), by the key of the builder.
+type Provenance struct {
+	Builder    string
+	Built      time.Time
+	Checksum   string
+	Sources    []SourceSnapshot
+	Signatures []string
+}
+
+// SourceSnapshot identifies the snapshot of a single upstream feed that went into a database build.
+type SourceSnapshot struct {
+	Provider string
+	Digest   string
+	Captured time.Time
+}
+
+// ProvenanceJSON is a helper struct for parsing and assembling Provenance objects to and from JSON.
+type ProvenanceJSON struct {
+	Builder    string               `json:"builder"`
+	Built      string               `json:"built"` // RFC 3339
+	Checksum   string               `json:"checksum"`
+	Sources    []SourceSnapshotJSON `json:"sources"`
+	Signatures []string             `json:"signatures,omitempty"`
+}
+
+type SourceSnapshotJSON struct {
+	Provider string `json:"provider"`
+	Digest   string `json:"digest"`
+	Captured string `json:"captured,omitempty"` // RFC 3339
+}
+
+// ToProvenance converts a ProvenanceJSON object to a Provenance object.
+func (p ProvenanceJSON) ToProvenance() (Provenance, error) {
+	built, err := time.Parse(time.RFC3339, p.Built)
+	if err != nil {
+		return Provenance{}, fmt.Errorf("cannot convert provenance built time (%s): %+v", p.Built, err)
+	}
+
+	provenance := Provenance{
+		Builder:    p.Builder,
+		Built:      built.UTC(),
+		Checksum:   p.Checksum,
+		Signatures: p.Signatures,
+	}
+
+	for _, s := range p.Sources {
+		snapshot := SourceSnapshot{
+			Provider: s.Provider,
+			Digest:   s.Digest,
+		}
+		if s.Captured != "" {
+			captured, err := time.Parse(time.RFC3339, s.Captured)
+			if err != nil {
+				return Provenance{}, fmt.Errorf("cannot convert capture time for source %q (%s): %+v", s.Provider, s.Captured, err)
+			}
+			snapshot.Captured = captured.UTC()
+		}
+		provenance.Sources = append(provenance.Sources, snapshot)
+	}
+
+	return provenance, nil
+}
+
+func (p Provenance) toJSON() *ProvenanceJSON {
+	j := &ProvenanceJSON{
+		Builder:  p.Builder,
+		Built:    p.Built.UTC().Format(time.RFC3339),
+		Checksum: p.Checksum,
+		Sources:  []SourceSnapshotJSON{},
+	}
+	for _, s := range p.Sources {
+		snapshot := SourceSnapshotJSON{
+			Provider: s.Provider,
+			Digest:   s.Digest,
+		}
+		if !s.Captured.IsZero() {
+			snapshot.Captured = s.Captured.UTC().Format(time.RFC3339)
+		}
+		j.Sources = append(j.Sources, snapshot)
+	}
+	j.Signatures = p.Signatures
+	return j
+}
+
+// SignedPayload returns the content signed by the signatures of the provenance: the canonical JSON (as signed by TUF)
+// of the provenance without its signatures, with the times in RFC 3339 (UTC).
+func (p Provenance) SignedPayload() ([]byte, error) {
+	unsigned := p
+	unsigned.Signatures = nil
+	data, err := json.Marshal(unsigned.toJSON())
+	if err != nil {
+		return nil, err
+	}
+	return canonicalJSON(data)
+}
+
+// ProvenancePolicy describes the provenance a database must have to be used. The zero value accepts any database,
+// and only checks the consistency of provenance that is present.
+type ProvenancePolicy struct {
+	// Required rejects databases without provenance metadata.
+	Required bool
+	// AllowedBuilders lists the builder identities that are trusted. A trailing "*" matches any identity with the
+	// given prefix. When empty, any builder is allowed.
+	AllowedBuilders []string
+	// TrustedKeys are the paths of the PEM-encoded public keys (ed25519 or ECDSA P-256) of the builders: the provenance
+	// must be signed by one of them. Without trusted keys the provenance is unsigned metadata, which anyone able to
+	// write the database metadata can forge.
+	TrustedKeys []string
+}
+
+// VerifyProvenance checks the provenance within the database metadata against the policy. Verification only uses
+// the local metadata (and the trusted keys), so no network access is needed. The signature of the provenance is only
+// verified with TrustedKeys; otherwise this only checks that the provenance is consistent with the database and
+// claims an allowed builder, which does not prove who built the database.
+func (p ProvenancePolicy) VerifyProvenance(m Metadata) error {
+	if m.Provenance == nil {
+		if p.Required || len(p.AllowedBuilders) > 0 || len(p.TrustedKeys) > 0 {
+			return fmt.Errorf("database has no provenance metadata")
+		}
+		return nil
+	}
+
+	prov := m.Provenance
+	var errs error
+
+	if len(p.TrustedKeys) > 0 {
+		if err := p.verifySignature(*prov); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	if prov.Builder == "" {
+		errs = errors.Join(errs, fmt.Errorf("provenance has no builder identity"))
+	} else if len(p.AllowedBuilders) > 0 && !p.allowsBuilder(prov.Builder) {
+		errs = errors.Join(errs, fmt.Errorf("database was built by an untrusted builder %q", prov.Builder))
+	}
+
+	// the provenance must describe this database, not some other build
+	if !prov.Built.Equal(m.Built) {
+		errs = errors.Join(errs, fmt.Errorf("provenance build time %s does not match the database build time %s", prov.Built.Format(time.RFC3339), m.Built.Format(time.RFC3339)))
+	}
+	// a database subset is described by the provenance of the complete database it was derived from
+	checksum := m.Checksum
+	if m.FullChecksum != "" {
+		checksum = m.FullChecksum
+	}
+	if prov.Checksum != checksum {
+		errs = errors.Join(errs, fmt.Errorf("provenance checksum %q does not match the database checksum %q", prov.Checksum, checksum))
+	}
+
+	if len(prov.Sources) == 0 {
+		errs = errors.Join(errs, fmt.Errorf("provenance does not list any source feed snapshots"))
+	}
+	for _, s := range prov.Sources {
+		if s.Provider == "" {
+			errs = errors.Join(errs, fmt.Errorf("provenance lists a source snapshot without a provider"))
+			continue
+		}
+		if !sourceDigestPattern.MatchString(s.Digest) {
+			errs = errors.Join(errs, fmt.Errorf("source %q has an invalid snapshot digest %q", s.Provider, s.Digest))
+		}
+		if s.Captured.After(prov.Built) {
+			errs = errors.Join(errs, fmt.Errorf("source %q was captured after the database was built", s.Provider))
+		}
+	}
+
+	if errs != nil {
+		return fmt.Errorf("database provenance verification failed: %w", errs)
+	}
+	return nil
+}
+
+// verifySignature checks that the provenance is signed by one of the trusted keys.
+func (p ProvenancePolicy) verifySignature(prov Provenance) error {
+	if len(prov.Signatures) == 0 {
+		return fmt.Errorf("provenance is not signed")
+	}
+	payload, err := prov.SignedPayload()
+	if err != nil {
+		return fmt.Errorf("unable to encode the signed provenance: %w", err)
+	}
+	for _, path := range p.TrustedKeys {
+		key, err := readPublicKey(path)
+		if err != nil {
+			return err
+		}
+		for _, signature := range prov.Signatures {
+			if verifyProvenanceSignature(key, payload, signature) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("provenance is not signed by a trusted key")
+}
+
+// readPublicKey reads a PEM-encoded (PKIX) public key.
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read trusted provenance key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("trusted provenance key %q is not PEM-encoded", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse trusted provenance key %q: %w", path, err)
+	}
+	return key, nil
+}
+
+// verifyProvenanceSignature reports whether the hex-encoded signature of the payload is valid for the key, an ed25519
+// key or an ECDSA P-256 key (signing the SHA-256 digest of the payload).
+func verifyProvenanceSignature(key crypto.PublicKey, payload []byte, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, sig)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		return ecdsa.VerifyASN1(k, digest[:], sig)
+	}
+	return false
+}
+
+func (p ProvenancePolicy) allowsBuilder(builder string) bool {
+	for _, allowed := range p.AllowedBuilders {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(builder, prefix) {
+				return true
+			}
+			continue
+		}
+		if builder == allowed {
+			return true
+		}
+	}
+	return false
+}
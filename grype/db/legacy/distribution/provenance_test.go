@@ -0,0 +1,205 @@
+package distribution
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func provenanceMetadata(t *testing.T) Metadata {
+	t.Helper()
+	m, err := NewMetadataFromDir(afero.NewOsFs(), "test-fixtures/metadata-provenance")
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	return *m
+}
+
+func TestProvenancePolicy_VerifyProvenance(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  ProvenancePolicy
+		mutate  func(*Metadata)
+		wantErr string
+	}{
+		{
+			name:   "no policy accepts valid provenance",
+			policy: ProvenancePolicy{},
+		},
+		{
+			name:   "no policy accepts missing provenance",
+			policy: ProvenancePolicy{},
+			mutate: func(m *Metadata) { m.Provenance = nil },
+		},
+		{
+			name:    "required provenance is missing",
+			policy:  ProvenancePolicy{Required: true},
+			mutate:  func(m *Metadata) { m.Provenance = nil },
+			wantErr: "no provenance metadata",
+		},
+		{
+			name:    "allowed builders imply required provenance",
+			policy:  ProvenancePolicy{AllowedBuilders: []string{OfficialBuilderIdentity}},
+			mutate:  func(m *Metadata) { m.Provenance = nil },
+			wantErr: "no provenance metadata",
+		},
+		{
+			name:   "official builder",
+			policy: ProvenancePolicy{Required: true, AllowedBuilders: []string{OfficialBuilderIdentity}},
+		},
+		{
+			name:   "builder prefix",
+			policy: ProvenancePolicy{AllowedBuilders: []string{"https://github.com/anchore/grype-db/*"}},
+		},
+		{
+			name:    "untrusted builder",
+			policy:  ProvenancePolicy{AllowedBuilders: []string{OfficialBuilderIdentity}},
+			mutate:  func(m *Metadata) { m.Provenance.Builder = "https://example.com/builder" },
+			wantErr: `untrusted builder "https://example.com/builder"`,
+		},
+		{
+			name:    "provenance for a different database",
+			mutate:  func(m *Metadata) { m.Checksum = "sha256:0000" },
+			wantErr: "does not match the database checksum",
+		},
+		{
+			name:    "provenance built at a different time",
+			mutate:  func(m *Metadata) { m.Built = m.Built.Add(time.Hour) },
+			wantErr: "does not match the database build time",
+		},
+		{
+			name:    "invalid source digest",
+			mutate:  func(m *Metadata) { m.Provenance.Sources[0].Digest = "latest" },
+			wantErr: `source "nvd" has an invalid snapshot digest`,
+		},
+		{
+			name:    "source captured after the build",
+			mutate:  func(m *Metadata) { m.Provenance.Sources[0].Captured = m.Built.Add(time.Minute) },
+			wantErr: `source "nvd" was captured after the database was built`,
+		},
+		{
+			name:    "no sources",
+			mutate:  func(m *Metadata) { m.Provenance.Sources = nil },
+			wantErr: "does not list any source feed snapshots",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := provenanceMetadata(t)
+			if tt.mutate != nil {
+				tt.mutate(&m)
+			}
+
+			err := tt.policy.VerifyProvenance(m)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMetadata_Write_provenance(t *testing.T) {
+	m := provenanceMetadata(t)
+
+	dir := t.TempDir()
+	require.NoError(t, m.Write(filepath.Join(dir, MetadataFileName)))
+
+	got, err := NewMetadataFromDir(afero.NewOsFs(), dir)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, m, *got)
+}
+
+func TestProvenancePolicy_VerifyProvenance_signature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	otherPublic, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeKey := func(name string, key ed25519.PublicKey) string {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		require.NoError(t, err)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+		return path
+	}
+	trusted := writeKey("builder.pub", public)
+	other := writeKey("other.pub", otherPublic)
+
+	sign := func(m *Metadata) {
+		payload, err := m.Provenance.SignedPayload()
+		require.NoError(t, err)
+		m.Provenance.Signatures = []string{hex.EncodeToString(ed25519.Sign(private, payload))}
+	}
+
+	tests := []struct {
+		name    string
+		keys    []string
+		mutate  func(*Metadata)
+		wantErr string
+	}{
+		{
+			name:   "signed by a trusted key",
+			keys:   []string{other, trusted},
+			mutate: sign,
+		},
+		{
+			name:    "unsigned",
+			keys:    []string{trusted},
+			wantErr: "provenance is not signed",
+		},
+		{
+			name:    "signed by an untrusted key",
+			keys:    []string{other},
+			mutate:  sign,
+			wantErr: "not signed by a trusted key",
+		},
+		{
+			name: "tampered after signing",
+			keys: []string{trusted},
+			mutate: func(m *Metadata) {
+				sign(m)
+				m.Provenance.Builder = "https://example.com/builder"
+			},
+			wantErr: "not signed by a trusted key",
+		},
+		{
+			name:    "trusted keys imply required provenance",
+			keys:    []string{trusted},
+			mutate:  func(m *Metadata) { m.Provenance = nil },
+			wantErr: "no provenance metadata",
+		},
+		{
+			name:    "missing key",
+			keys:    []string{filepath.Join(dir, "missing.pub")},
+			mutate:  sign,
+			wantErr: "unable to read trusted provenance key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := provenanceMetadata(t)
+			if tt.mutate != nil {
+				tt.mutate(&m)
+			}
+
+			err := ProvenancePolicy{TrustedKeys: tt.keys}.VerifyProvenance(m)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
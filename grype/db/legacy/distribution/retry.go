@@ -0,0 +1,264 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+// ErrCircuitOpen is returned when requests to a host are not attempted because recent requests have consistently failed.
+var ErrCircuitOpen = errors.New("too many failed requests, not contacting host until the cooldown elapses")
+
+// RetryConfig describes how requests for the listing file and DB archives are retried when a server responds with a
+// transient error (5xx/429) or the connection fails. The zero value disables retries and the circuit breaker.
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of attempts per request (including the first attempt).
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled (with jitter) on every following retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts, including any wait requested by a Retry-After header.
+	MaxBackoff time.Duration
+	// Budget is the total number of retries allowed across all requests made by a curator, so that an outage does
+	// not multiply the time spent on every request.
+	Budget int
+	// BreakerThreshold is the number of consecutive failures to a host before requests to the host fail immediately.
+	BreakerThreshold int
+	// BreakerCooldown is how long requests to a host fail immediately once the breaker has tripped.
+	BreakerCooldown time.Duration
+}
+
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:      3,
+		InitialBackoff:   500 * time.Millisecond,
+		MaxBackoff:       10 * time.Second,
+		Budget:           6,
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Minute,
+	}
+}
+
+// retryState is shared by all the transports of a curator, tracking the remaining retry budget and the health of
+// each host.
+type retryState struct {
+	lock      sync.Mutex
+	remaining int
+	breakers  map[string]*breaker
+}
+
+type breaker struct {
+	failures  int
+	openUntil time.Time
+}
+
+func newRetryState(cfg RetryConfig) *retryState {
+	return &retryState{
+		remaining: cfg.Budget,
+		breakers:  make(map[string]*breaker),
+	}
+}
+
+type retryTransport struct {
+	next  http.RoundTripper
+	cfg   RetryConfig
+	state *retryState
+	now   func() time.Time
+	sleep func(*http.Request, time.Duration) error
+	rand  func() float64
+}
+
+func newRetryTransport(next http.RoundTripper, cfg RetryConfig, state *retryState) *retryTransport {
+	return &retryTransport{
+		next:  next,
+		cfg:   cfg,
+		state: state,
+		now:   time.Now,
+		sleep: sleepWithContext,
+		rand:  rand.Float64, //nolint:gosec // jitter does not need a secure source
+	}
+}
+
+// withClock replaces the clock and the source of jitter of the transport with the given ones, when given.
+func (t *retryTransport) withClock(now func() time.Time, jitter func() float64) *retryTransport {
+	if now != nil {
+		t.now = now
+	}
+	if jitter != nil {
+		t.rand = jitter
+	}
+	return t
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// only requests that can be safely replayed are retried (the listing and DB downloads are always GETs)
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || req.Body != nil && req.Body != http.NoBody {
+		return t.next.RoundTrip(req)
+	}
+
+	host := req.URL.Host
+	if !t.allow(host) {
+		return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if !isRetryable(resp, err) {
+			if err == nil {
+				t.succeeded(host)
+			}
+			return resp, err
+		}
+
+		tripped := t.failed(host)
+		if tripped || attempt >= t.cfg.MaxAttempts || !t.consumeRetry() {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if err != nil {
+			log.WithFields("url", req.URL.Redacted(), "attempt", attempt, "error", err).Debugf("request failed, retrying in %s", wait)
+		} else {
+			log.WithFields("url", req.URL.Redacted(), "attempt", attempt, "status", resp.StatusCode).Debugf("request failed, retrying in %s", wait)
+			// the body must be consumed and closed for the connection to be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(req, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isRetryable indicates if the request failed in a way that may succeed if attempted again.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		// cancellation is a decision by the caller, not a transient failure
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// backoff is the wait before the given (1-based) attempt is retried: exponential with jitter, and at least as long as
+// any Retry-After requested by the server, capped at the max backoff.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	d := t.cfg.InitialBackoff << (attempt - 1)
+	if d <= 0 || d > t.cfg.MaxBackoff {
+		d = t.cfg.MaxBackoff
+	}
+	// "equal jitter": wait at least half the backoff so that retries are still spread out over time
+	wait := d/2 + time.Duration(t.rand()*float64(d/2))
+
+	if resp != nil {
+		if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), t.now()); ok && after > wait {
+			wait = after
+		}
+	}
+	if wait > t.cfg.MaxBackoff {
+		wait = t.cfg.MaxBackoff
+	}
+	return wait
+}
+
+// parseRetryAfter reads a Retry-After header value given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func (t *retryTransport) consumeRetry() bool {
+	t.state.lock.Lock()
+	defer t.state.lock.Unlock()
+
+	if t.state.remaining <= 0 {
+		log.Debug("retry budget exhausted, not retrying request")
+		return false
+	}
+	t.state.remaining--
+	return true
+}
+
+// allow indicates if a request to the host may be attempted. Once the cooldown of a tripped breaker elapses a
+// single request is allowed through; its outcome closes or re-trips the breaker.
+func (t *retryTransport) allow(host string) bool {
+	if t.cfg.BreakerThreshold <= 0 {
+		return true
+	}
+
+	t.state.lock.Lock()
+	defer t.state.lock.Unlock()
+
+	b, ok := t.state.breakers[host]
+	if !ok || b.openUntil.IsZero() {
+		return true
+	}
+	if t.now().Before(b.openUntil) {
+		return false
+	}
+	// half-open: one more failure trips the breaker again
+	b.openUntil = time.Time{}
+	b.failures = t.cfg.BreakerThreshold - 1
+	return true
+}
+
+func (t *retryTransport) succeeded(host string) {
+	t.state.lock.Lock()
+	defer t.state.lock.Unlock()
+
+	delete(t.state.breakers, host)
+}
+
+// failed records a failed attempt to the host, returning true if this trips the breaker.
+func (t *retryTransport) failed(host string) bool {
+	if t.cfg.BreakerThreshold <= 0 {
+		return false
+	}
+
+	t.state.lock.Lock()
+	defer t.state.lock.Unlock()
+
+	b, ok := t.state.breakers[host]
+	if !ok {
+		b = &breaker{}
+		t.state.breakers[host] = b
+	}
+	b.failures++
+	if b.failures < t.cfg.BreakerThreshold {
+		return false
+	}
+
+	b.openUntil = t.now().Add(t.cfg.BreakerCooldown)
+	log.WithFields("host", host, "failures", b.failures).Debugf("not contacting host for %s", t.cfg.BreakerCooldown)
+	return true
+}
+
+func sleepWithContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
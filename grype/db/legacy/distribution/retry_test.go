@@ -0,0 +1,185 @@
+package distribution
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusServer responds with the given status codes in order, then with 200 for every following request.
+func statusServer(t *testing.T, headers http.Header, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		i := int(requests.Add(1)) - 1
+		for k, v := range headers {
+			w.Header()[k] = v
+		}
+		if i < len(statuses) {
+			w.WriteHeader(statuses[i])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newTestRetryClient(cfg RetryConfig, now *time.Time) (*http.Client, *[]time.Duration) {
+	var waits []time.Duration
+	var clock func() time.Time
+	if now != nil {
+		clock = func() time.Time { return *now }
+	}
+	transport := newRetryTransport(http.DefaultTransport, cfg, newRetryState(cfg)).withClock(clock, func() float64 { return 1 })
+	transport.sleep = func(_ *http.Request, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return &http.Client{Transport: transport}, &waits
+}
+
+func TestRetryTransport_retriesTransientFailures(t *testing.T) {
+	server, requests := statusServer(t, nil, http.StatusServiceUnavailable, http.StatusBadGateway)
+
+	client, waits := newTestRetryClient(DefaultRetryConfig(), nil)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, time.Second}, *waits)
+}
+
+func TestRetryTransport_doesNotRetryClientErrors(t *testing.T) {
+	server, requests := statusServer(t, nil, http.StatusNotFound)
+
+	client, waits := newTestRetryClient(DefaultRetryConfig(), nil)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Empty(t, *waits)
+}
+
+func TestRetryTransport_honorsRetryAfter(t *testing.T) {
+	server, _ := statusServer(t, http.Header{"Retry-After": []string{"4"}}, http.StatusTooManyRequests)
+
+	client, waits := newTestRetryClient(DefaultRetryConfig(), nil)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []time.Duration{4 * time.Second}, *waits)
+}
+
+func TestRetryTransport_givesUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      func(*RetryConfig)
+		requests int32
+	}{
+		{
+			name:     "max attempts per request",
+			cfg:      func(*RetryConfig) {},
+			requests: 3,
+		},
+		{
+			name:     "retry budget",
+			cfg:      func(c *RetryConfig) { c.Budget = 1 },
+			requests: 2,
+		},
+		{
+			name:     "zero value disables retries",
+			cfg:      func(c *RetryConfig) { *c = RetryConfig{} },
+			requests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := statusServer(t, nil, 500, 500, 500, 500, 500)
+
+			cfg := DefaultRetryConfig()
+			tt.cfg(&cfg)
+			client, _ := newTestRetryClient(cfg, nil)
+
+			resp, err := client.Get(server.URL)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			assert.Equal(t, tt.requests, requests.Load())
+		})
+	}
+}
+
+func TestRetryTransport_circuitBreaker(t *testing.T) {
+	server, requests := statusServer(t, nil, 500, 500, 500)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := DefaultRetryConfig()
+	cfg.BreakerThreshold = 2
+	client, _ := newTestRetryClient(cfg, &now)
+
+	// the second consecutive failure trips the breaker, ending the retries early
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(2), requests.Load())
+
+	// while open, the host is not contacted
+	_, err = client.Get(server.URL)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// after the cooldown a single attempt is allowed, which fails and re-trips the breaker
+	now = now.Add(cfg.BreakerCooldown)
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, int32(3), requests.Load())
+
+	_, err = client.Get(server.URL)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	// a successful attempt closes the breaker
+	now = now.Add(cfg.BreakerCooldown)
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{value: "", ok: false},
+		{value: "120", want: 2 * time.Minute, ok: true},
+		{value: "Mon, 01 Jan 2024 00:00:30 GMT", want: 30 * time.Second, ok: true},
+		{value: "Sun, 31 Dec 2023 00:00:00 GMT", want: 0, ok: true},
+		{value: "soon", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package distribution
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+// highestBuiltFileName is the file (in the db root directory, so that it outlives the db directory) recording the
+// build time of the newest db ever activated.
+const highestBuiltFileName = "highest_built"
+
+// RollbackPolicy is how the activation of a db built before the newest db activated so far (a rollback, whether by a
+// compromised mirror serving an old listing or by importing an old archive) is handled. By default it is refused.
+type RollbackPolicy struct {
+	// Warn activates the older db with a warning instead of refusing it.
+	Warn bool
+	// Allow activates the older db, which then becomes the newest db activated so far (a deliberate downgrade).
+	Allow bool
+}
+
+// checkRollback checks the build time of the db about to be activated against the newest db activated so far,
+// returning the build time of the newest db.
+func (c *Curator) checkRollback(m Metadata) (time.Time, error) {
+	highest, err := c.highestBuilt()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if highest.IsZero() || !m.Built.Before(highest) {
+		return highest, nil
+	}
+
+	msg := fmt.Sprintf("the database was built at %s, before the newest database activated so far (built at %s)", m.Built.UTC().Format(time.RFC3339), highest.UTC().Format(time.RFC3339))
+	switch {
+	case c.rollbackPolicy.Allow:
+		log.Infof("rolling back: %s", msg)
+		return highest, nil
+	case c.rollbackPolicy.Warn:
+		log.Warnf("possible rollback: %s", msg)
+		return highest, nil
+	}
+	return time.Time{}, fmt.Errorf("refusing to roll back: %s (use --allow-db-rollback to allow it)", msg)
+}
+
+// highestBuilt returns the build time of the newest db activated so far: the recorded time, or the build time of the
+// installed db when newer (e.g. a db installed before the time was recorded). The time is zero when there is neither.
+func (c *Curator) highestBuilt() (time.Time, error) {
+	var highest time.Time
+	contents, err := afero.ReadFile(c.fs, c.highestBuiltPath)
+	switch {
+	case err == nil:
+		highest, err = time.Parse(time.RFC3339, strings.TrimSpace(string(contents)))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unable to parse the build time of the newest database activated so far (%s): %w", c.highestBuiltPath, err)
+		}
+	case !os.IsNotExist(err):
+		return time.Time{}, fmt.Errorf("unable to read the build time of the newest database activated so far: %w", err)
+	}
+
+	current, err := NewMetadataFromDir(c.fs, c.dbDir)
+	if err != nil {
+		log.WithFields("error", err).Debug("unable to read the metadata of the installed database")
+		return highest, nil
+	}
+	if current != nil && current.Built.After(highest) {
+		highest = current.Built
+	}
+	return highest, nil
+}
+
+// recordBuilt records the build time of the activated db when newer than the newest db activated so far, or in any
+// case for an allowed rollback.
+func (c *Curator) recordBuilt(built, highest time.Time) {
+	if built.Before(highest) && !c.rollbackPolicy.Allow {
+		return
+	}
+	if err := afero.WriteFile(c.fs, c.highestBuiltPath, []byte(built.UTC().Format(time.RFC3339)), 0644); err != nil {
+		log.WithFields("error", err).Warn("unable to record the build time of the activated database")
+	}
+}
@@ -0,0 +1,119 @@
+package distribution
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBuiltDB(t *testing.T, dir string, built time.Time) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte(built.String()), 0600))
+	require.NoError(t, Metadata{Built: built, Version: 5, Checksum: "sha256:0000"}.Write(metadataPath(dir)))
+}
+
+func TestCurator_activate_rollback(t *testing.T) {
+	older := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		policy     RollbackPolicy
+		wantErr    string
+		wantBuilt  time.Time
+		wantActive time.Time
+	}{
+		{
+			name:      "refused by default",
+			wantErr:   "refusing to roll back: the database was built at 2024-05-01T00:00:00Z, before the newest database activated so far (built at 2024-05-02T00:00:00Z)",
+			wantBuilt: newer,
+		},
+		{
+			name:       "warn",
+			policy:     RollbackPolicy{Warn: true},
+			wantBuilt:  newer,
+			wantActive: older,
+		},
+		{
+			name:       "allowed",
+			policy:     RollbackPolicy{Allow: true},
+			wantBuilt:  older,
+			wantActive: older,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewOsFs()
+			root := t.TempDir()
+			c := Curator{fs: fs, dbDir: filepath.Join(root, "5"), highestBuiltPath: filepath.Join(root, highestBuiltFileName), rollbackPolicy: tt.policy}
+
+			writeBuiltDB(t, filepath.Join(root, "newer"), newer)
+			writeBuiltDB(t, filepath.Join(root, "older"), older)
+			require.NoError(t, c.activate(filepath.Join(root, "newer")))
+
+			// the newest db is recorded, so that deleting the installed db does not allow a rollback either
+			require.NoError(t, c.Delete())
+
+			err := c.activate(filepath.Join(root, "older"))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				require.ErrorContains(t, err, "use --allow-db-rollback to allow it")
+			} else {
+				require.NoError(t, err)
+				m, err := NewMetadataFromDir(fs, c.dbDir)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantActive, m.Built)
+			}
+
+			highest, err := c.highestBuilt()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBuilt, highest)
+		})
+	}
+}
+
+func TestCurator_activate_noMetadata(t *testing.T) {
+	fs := afero.NewOsFs()
+	root := t.TempDir()
+	c := Curator{fs: fs, dbDir: filepath.Join(root, "5"), highestBuiltPath: filepath.Join(root, highestBuiltFileName), rollbackPolicy: RollbackPolicy{Allow: true}}
+
+	dir := filepath.Join(root, "unknown")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte("db"), 0600))
+
+	require.ErrorContains(t, c.activate(dir), "refusing to activate the database: no metadata found")
+	_, err := os.Stat(c.dbDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCurator_highestBuilt(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	c := Curator{fs: fs, dbDir: "/db/5", highestBuiltPath: "/db/" + highestBuiltFileName}
+
+	highest, err := c.highestBuilt()
+	require.NoError(t, err)
+	assert.True(t, highest.IsZero())
+
+	// a db installed before the build time was recorded
+	installed := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, fs.MkdirAll(c.dbDir, 0755))
+	require.NoError(t, afero.WriteFile(fs, metadataPath(c.dbDir), []byte(`{"built":"2024-05-01T00:00:00Z","version":5,"checksum":"sha256:0000"}`), 0600))
+	highest, err = c.highestBuilt()
+	require.NoError(t, err)
+	assert.Equal(t, installed, highest)
+
+	require.NoError(t, afero.WriteFile(fs, c.highestBuiltPath, []byte("2024-06-01T00:00:00Z\n"), 0644))
+	highest, err = c.highestBuilt()
+	require.NoError(t, err)
+	assert.Equal(t, installed.AddDate(0, 1, 0), highest)
+
+	require.NoError(t, afero.WriteFile(fs, c.highestBuiltPath, []byte("yesterday"), 0644))
+	_, err = c.highestBuilt()
+	require.ErrorContains(t, err, "unable to parse the build time of the newest database activated so far")
+}
@@ -3,9 +3,11 @@ package distribution
 import "time"
 
 type Status struct {
-	Built         time.Time `json:"built"`
-	SchemaVersion int       `json:"schemaVersion"`
-	Location      string    `json:"location"`
-	Checksum      string    `json:"checksum"`
-	Err           error     `json:"error"`
+	Built         time.Time   `json:"built"`
+	SchemaVersion int         `json:"schemaVersion"`
+	Location      string      `json:"location"`
+	Checksum      string      `json:"checksum"`
+	Provenance    *Provenance `json:"provenance,omitempty"`
+	Ecosystems    []string    `json:"ecosystems,omitempty"`
+	Err           error       `json:"error"`
 }
@@ -0,0 +1,141 @@
+package distribution
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/db/v5/store"
+	"github.com/anchore/grype/internal/file"
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+const (
+	// EcosystemOS selects the records for all OS distributions.
+	EcosystemOS = "os"
+	// EcosystemCPE selects the CPE based records (e.g. NVD), used for packages without a more specific source.
+	EcosystemCPE = "cpe"
+)
+
+var ecosystemAliases = map[string]string{
+	"cargo":  string(syftPkg.Rust),
+	"gem":    string(syftPkg.Ruby),
+	"golang": string(syftPkg.Go),
+	"maven":  string(syftPkg.Java),
+	"npm":    string(syftPkg.JavaScript),
+	"nuget":  string(syftPkg.Dotnet),
+	"pypi":   string(syftPkg.Python),
+}
+
+// Ecosystems returns the ecosystems that may be used to select a subset of the DB.
+func Ecosystems() []string {
+	values := []string{EcosystemOS, EcosystemCPE}
+	for _, l := range syftPkg.AllLanguages {
+		values = append(values, string(l))
+	}
+	return values
+}
+
+// NormalizeEcosystems validates the given ecosystem selection, resolving common aliases (e.g. "npm" selects
+// "javascript"). The result is sorted and without duplicates. An empty selection selects the complete DB.
+func NormalizeEcosystems(values []string) ([]string, error) {
+	known := make(map[string]struct{})
+	for _, e := range Ecosystems() {
+		known[e] = struct{}{}
+	}
+
+	selected := make(map[string]struct{})
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "" {
+			continue
+		}
+		if alias, ok := ecosystemAliases[v]; ok {
+			v = alias
+		}
+		if _, ok := known[v]; !ok {
+			return nil, fmt.Errorf("unknown ecosystem %q (available: %s)", v, strings.Join(Ecosystems(), ", "))
+		}
+		selected[v] = struct{}{}
+	}
+
+	var result []string
+	for v := range selected {
+		result = append(result, v)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// namespaceEcosystem returns the ecosystem that a DB namespace belongs to (e.g. "github:language:java" is "java").
+func namespaceEcosystem(namespace string) string {
+	components := strings.Split(namespace, ":")
+	if len(components) < 2 {
+		return ""
+	}
+	switch components[1] {
+	case "distro":
+		return EcosystemOS
+	case "cpe":
+		return EcosystemCPE
+	case "language":
+		if len(components) > 2 {
+			return components[2]
+		}
+	}
+	return ""
+}
+
+func sameEcosystems(a, b []string) bool {
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
+
+// applySubset removes every record outside of the configured ecosystems from the DB within the given directory,
+// updating the metadata to describe the subset.
+func (c *Curator) applySubset(dbDirPath string) error {
+	if len(c.ecosystems) == 0 {
+		return nil
+	}
+
+	metadata, err := NewMetadataFromDir(c.fs, dbDirPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse database metadata (%s): %w", dbDirPath, err)
+	}
+	if metadata == nil {
+		return fmt.Errorf("database metadata not found: %s", dbDirPath)
+	}
+	if sameEcosystems(metadata.Ecosystems, c.ecosystems) {
+		return nil
+	}
+	if len(metadata.Ecosystems) > 0 {
+		return fmt.Errorf("database is already a subset (%s) and cannot be filtered again", strings.Join(metadata.Ecosystems, ", "))
+	}
+
+	selected := make(map[string]struct{})
+	for _, e := range c.ecosystems {
+		selected[e] = struct{}{}
+	}
+
+	dbPath := filepath.Join(dbDirPath, FileName)
+	removed, err := store.Prune(dbPath, func(namespace string) bool {
+		_, ok := selected[namespaceEcosystem(namespace)]
+		return ok
+	})
+	if err != nil {
+		return fmt.Errorf("unable to select database ecosystems: %w", err)
+	}
+	log.WithFields("ecosystems", strings.Join(c.ecosystems, ","), "removed-namespaces", len(removed)).Debug("selected database subset")
+
+	checksum, err := file.HashFile(c.fs, dbPath, sha256.New())
+	if err != nil {
+		return fmt.Errorf("unable to hash database subset: %w", err)
+	}
+
+	metadata.FullChecksum = metadata.Checksum
+	metadata.Checksum = "sha256:" + checksum
+	metadata.Ecosystems = c.ecosystems
+	return metadata.Write(metadataPath(dbDirPath))
+}
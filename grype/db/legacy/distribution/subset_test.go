@@ -0,0 +1,120 @@
+package distribution
+
+import (
+	"crypto/sha256"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v5 "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store"
+	"github.com/anchore/grype/internal/file"
+)
+
+func TestNormalizeEcosystems(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:   "empty selects everything",
+			values: nil,
+			want:   nil,
+		},
+		{
+			name:   "aliases, case and duplicates",
+			values: []string{"npm", "OS", " java ", "javascript", ""},
+			want:   []string{"java", "javascript", "os"},
+		},
+		{
+			name:    "unknown ecosystem",
+			values:  []string{"os", "cobol"},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := NormalizeEcosystems(tt.values)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_namespaceEcosystem(t *testing.T) {
+	tests := map[string]string{
+		"debian:distro:debian:12":      EcosystemOS,
+		"nvd:cpe":                      EcosystemCPE,
+		"github:language:java":         "java",
+		"github:language:javascript":   "javascript",
+		"msrc:distro:windows:10816":    EcosystemOS,
+		"not-a-namespace":              "",
+		"github:language":              "",
+		"something:else:entirely:here": "",
+	}
+	for namespace, want := range tests {
+		t.Run(namespace, func(t *testing.T) {
+			assert.Equal(t, want, namespaceEcosystem(namespace))
+		})
+	}
+}
+
+func TestCurator_applySubset(t *testing.T) {
+	fs := afero.NewOsFs()
+	dir := t.TempDir()
+	dbPath := path.Join(dir, FileName)
+
+	s, err := store.New(dbPath, true)
+	require.NoError(t, err)
+	require.NoError(t, s.AddVulnerability(
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:12", VersionConstraint: "< 1.0", VersionFormat: "dpkg"},
+		v5.Vulnerability{ID: "GHSA-1", PackageName: "left-pad", Namespace: "github:language:javascript", VersionConstraint: "< 2.0", VersionFormat: "unknown"},
+	))
+	s.Close()
+
+	fullChecksum, err := file.HashFile(fs, dbPath, sha256.New())
+	require.NoError(t, err)
+	metadata := Metadata{
+		Built:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Version:  5,
+		Checksum: "sha256:" + fullChecksum,
+	}
+	require.NoError(t, metadata.Write(metadataPath(dir)))
+
+	c := &Curator{fs: fs, ecosystems: []string{EcosystemOS}}
+	require.NoError(t, c.applySubset(dir))
+
+	subset, err := NewMetadataFromDir(fs, dir)
+	require.NoError(t, err)
+	require.NotNil(t, subset)
+	assert.Equal(t, []string{EcosystemOS}, subset.Ecosystems)
+	assert.Equal(t, metadata.Checksum, subset.FullChecksum)
+
+	subsetChecksum, err := file.HashFile(fs, dbPath, sha256.New())
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:"+subsetChecksum, subset.Checksum)
+
+	s, err = store.New(dbPath, false)
+	require.NoError(t, err)
+	vulns, err := s.GetAllVulnerabilities()
+	require.NoError(t, err)
+	s.Close()
+	require.Len(t, *vulns, 1)
+	assert.Equal(t, "CVE-2023-1", (*vulns)[0].ID)
+
+	// applying the same selection again is a no-op
+	require.NoError(t, c.applySubset(dir))
+
+	// a subset cannot be filtered down to a different selection
+	c.ecosystems = []string{EcosystemOS, "java"}
+	require.Error(t, c.applySubset(dir))
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package distribution
+
+// tempRoot returns the directory of the scratch directories of the downloads and imports of the db, which is the
+// system temp directory.
+func tempRoot(string) string {
+	return ""
+}
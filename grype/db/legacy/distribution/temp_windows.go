@@ -0,0 +1,10 @@
+package distribution
+
+import "path/filepath"
+
+// tempRoot returns the directory of the scratch directories of the downloads and imports of the db. On Windows it is a
+// directory of the db root: the system temp directory is often on another volume than the local app data (so the db
+// would be copied rather than renamed), and it is cleaned up by the system (e.g. Storage Sense) while in use.
+func tempRoot(dbRootDir string) string {
+	return filepath.Join(dbRootDir, "tmp")
+}
@@ -0,0 +1,735 @@
+package distribution
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/afero"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+const (
+	tufDirName = "tuf"
+
+	// tufMaxMetadataSize is the most bytes read of a metadata file, so that a mirror cannot exhaust the memory
+	// (an endless data attack).
+	tufMaxMetadataSize = 1 << 20
+
+	// tufMaxRootRotations is the most new root versions applied in a single update.
+	tufMaxRootRotations = 1024
+
+	// tufRootDigestPrefix prefixes the SHA-256 digests of the trusted root metadata in the DNS TXT records.
+	tufRootDigestPrefix = "tuf-root-sha256="
+)
+
+var errTUFNotFound = errors.New("not found")
+
+// TUFConfig configures the verification of the listing file with TUF (The Update Framework) metadata published along
+// with it: a root of trusted keys (which can be rotated) signs the targets metadata with the digest of the listing
+// file, the snapshot of the versions of the metadata, and the timestamp of the latest snapshot, so that a mirror can
+// neither serve a tampered listing, nor an older one (a rollback attack), nor stop the updates (a freeze attack) for
+// longer than the metadata takes to expire. The verification is enabled by giving the initial trusted root, as a file
+// or as its digest in DNS.
+type TUFConfig struct {
+	// Root is the path of the initial trusted root metadata (e.g. "1.root.json"), obtained out of band.
+	Root string
+	// RootDNS is a DNS name whose TXT records pin the initial trusted root, as "tuf-root-sha256=<hex digest>" records: the
+	// first root version served along with the listing (1.root.json) is trusted when its digest is pinned. The records
+	// are not DNSSEC-validated, so this trusts the DNS resolution of the client (Root is the stronger anchor).
+	RootDNS string
+	// MetadataURL is the base URL of the metadata, the directory of the listing URL of each mirror by default.
+	MetadataURL string
+}
+
+func (cfg TUFConfig) enabled() bool {
+	return cfg.Root != "" || cfg.RootDNS != ""
+}
+
+// tufEnvelope is a signed metadata file.
+type tufEnvelope struct {
+	Signed     json.RawMessage `json:"signed"`
+	Signatures []tufSignature  `json:"signatures"`
+}
+
+type tufSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+type tufKey struct {
+	KeyType string `json:"keytype"`
+	Scheme  string `json:"scheme"`
+	KeyVal  struct {
+		Public string `json:"public"`
+	} `json:"keyval"`
+}
+
+type tufRole struct {
+	KeyIDs    []string `json:"keyids"`
+	Threshold int      `json:"threshold"`
+}
+
+type tufCommon struct {
+	Type    string    `json:"_type"`
+	Version int64     `json:"version"`
+	Expires time.Time `json:"expires"`
+}
+
+type tufRoot struct {
+	tufCommon
+	Keys               map[string]tufKey  `json:"keys"`
+	Roles              map[string]tufRole `json:"roles"`
+	ConsistentSnapshot bool               `json:"consistent_snapshot"`
+}
+
+type tufMetaFile struct {
+	Version int64             `json:"version"`
+	Length  int64             `json:"length,omitempty"`
+	Hashes  map[string]string `json:"hashes,omitempty"`
+}
+
+// tufMeta is the timestamp or the snapshot metadata: the versions (and optionally digests) of other metadata files.
+type tufMeta struct {
+	tufCommon
+	Meta map[string]tufMetaFile `json:"meta"`
+}
+
+type tufTargetFile struct {
+	Length int64             `json:"length"`
+	Hashes map[string]string `json:"hashes"`
+}
+
+type tufTargets struct {
+	tufCommon
+	Targets map[string]tufTargetFile `json:"targets"`
+}
+
+// tufClient verifies the listing files with the TUF metadata published along with them, keeping the trusted metadata
+// in a directory.
+type tufClient struct {
+	fs        afero.Fs
+	dir       string
+	cfg       TUFConfig
+	client    *http.Client
+	lookupTXT func(name string) ([]string, error)
+	now       func() time.Time
+}
+
+func newTUFClient(fs afero.Fs, dir string, cfg TUFConfig, client *http.Client, now func() time.Time) *tufClient {
+	return &tufClient{
+		fs:        fs,
+		dir:       dir,
+		cfg:       cfg,
+		client:    client,
+		lookupTXT: net.LookupTXT,
+		now:       now,
+	}
+}
+
+func (c *tufClient) currentTime() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// verifyListing verifies the listing file at the path, downloaded from the listing URL, against the latest trusted
+// targets metadata, updating the trusted metadata first.
+func (c *tufClient) verifyListing(listingURL, listingPath string) error {
+	base, name, err := c.metadataURL(listingURL)
+	if err != nil {
+		return err
+	}
+
+	root, err := c.updateRoot(base)
+	if err != nil {
+		return err
+	}
+	timestamp, err := c.updateTimestamp(base, root)
+	if err != nil {
+		return err
+	}
+	snapshot, err := c.updateSnapshot(base, root, timestamp)
+	if err != nil {
+		return err
+	}
+	targets, err := c.updateTargets(base, root, snapshot)
+	if err != nil {
+		return err
+	}
+
+	target, ok := targets.Targets[name]
+	if !ok {
+		return fmt.Errorf("the targets metadata does not list %q", name)
+	}
+	contents, err := afero.ReadFile(c.fs, listingPath)
+	if err != nil {
+		return err
+	}
+	if int64(len(contents)) != target.Length {
+		return fmt.Errorf("%s is %d bytes, the targets metadata lists %d bytes", name, len(contents), target.Length)
+	}
+	if err := verifyTUFHashes(contents, target.Hashes, true); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	log.WithFields("targets", targets.Version, "snapshot", snapshot.Version, "timestamp", timestamp.Version).Debug("verified the listing with TUF metadata")
+	return nil
+}
+
+// metadataURL returns the base URL of the metadata of the listing URL, and the name of the listing file as a target.
+func (c *tufClient) metadataURL(listingURL string) (string, string, error) {
+	u, err := url.Parse(listingURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid listing URL %q: %w", listingURL, err)
+	}
+	name := path.Base(u.Path)
+	if c.cfg.MetadataURL != "" {
+		return strings.TrimSuffix(c.cfg.MetadataURL, "/"), name, nil
+	}
+	u.Path = path.Dir(u.Path)
+	u.RawQuery = ""
+	return strings.TrimSuffix(u.String(), "/"), name, nil
+}
+
+// updateRoot returns the latest trusted root, applying the new root versions signed by the trusted root in turn.
+func (c *tufClient) updateRoot(base string) (*tufRoot, error) {
+	root, err := c.trustedRoot(base)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := root
+	for i := 0; i < tufMaxRootRotations; i++ {
+		next := root.Version + 1
+		data, err := c.fetch(base, rootFileName(next))
+		if errors.Is(err, errTUFNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		newRoot, err := verifyRoot(data, root)
+		if err != nil {
+			return nil, fmt.Errorf("unable to rotate the root to version %d: %w", next, err)
+		}
+		if newRoot.Version != next {
+			return nil, fmt.Errorf("unable to rotate the root to version %d: the metadata is version %d", next, newRoot.Version)
+		}
+		if err := c.store(rootFileName(next), data); err != nil {
+			return nil, err
+		}
+		root = newRoot
+	}
+
+	if root.Version != previous.Version {
+		log.WithFields("from", previous.Version, "to", root.Version).Info("rotated the TUF root of the db listing")
+		// after a rotation of the keys of a role, its metadata trusted so far may have been signed by compromised keys
+		for _, role := range []string{"timestamp", "snapshot", "targets"} {
+			if !sameKeys(previous.Roles[role], root.Roles[role]) {
+				c.remove(role + ".json")
+			}
+		}
+	}
+	if c.currentTime().After(root.Expires) {
+		return nil, fmt.Errorf("the TUF root metadata (version %d) expired at %s, the db listing may be frozen", root.Version, root.Expires.Format(time.RFC3339))
+	}
+	return root, nil
+}
+
+// trustedRoot returns the root trusted so far: the initial root of the configuration, rotated through the root
+// versions stored by previous updates. Each stored version is verified again against the version before it, starting
+// from the initial root, so that replacing the stored files cannot replace the trust anchor.
+func (c *tufClient) trustedRoot(base string) (*tufRoot, error) {
+	root, err := c.initialRoot(base)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < tufMaxRootRotations; i++ {
+		next := root.Version + 1
+		data, err := afero.ReadFile(c.fs, filepath.Join(c.dir, rootFileName(next)))
+		if err != nil {
+			break
+		}
+		newRoot, err := verifyRoot(data, root)
+		if err != nil || newRoot.Version != next {
+			log.WithFields("version", next, "error", err).Warn("ignoring the stored TUF root that does not chain to the trusted root")
+			c.remove(rootFileName(next))
+			break
+		}
+		root = newRoot
+	}
+	return root, nil
+}
+
+// initialRoot returns the initial trusted root: the root file of the configuration, or else the first root version
+// served along with the listing when its digest is pinned in DNS (checked on every update).
+func (c *tufClient) initialRoot(base string) (*tufRoot, error) {
+	var data []byte
+	var err error
+	switch {
+	case c.cfg.Root != "":
+		data, err = afero.ReadFile(c.fs, c.cfg.Root)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the initial TUF root: %w", err)
+		}
+	default:
+		if data, err = c.fetch(base, rootFileName(1)); err != nil {
+			return nil, err
+		}
+		if err := c.verifyPinnedRoot(data); err != nil {
+			return nil, err
+		}
+	}
+	root, err := verifyRoot(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("the initial TUF root is invalid: %w", err)
+	}
+	return root, nil
+}
+
+// verifyPinnedRoot checks that the digest of the root metadata is pinned by the TXT records of the DNS name. The
+// records are resolved without DNSSEC validation, so the pin is only as trustworthy as the DNS resolution of the
+// client: it protects against a compromised mirror, not against a spoofed DNS answer (unlike a root file).
+func (c *tufClient) verifyPinnedRoot(data []byte) error {
+	records, err := c.lookupTXT(c.cfg.RootDNS)
+	if err != nil {
+		return fmt.Errorf("unable to look up the pinned TUF root at %s: %w", c.cfg.RootDNS, err)
+	}
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	var pinned int
+	for _, r := range records {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(r), tufRootDigestPrefix); ok {
+			pinned++
+			if strings.EqualFold(value, digest) {
+				return nil
+			}
+		}
+	}
+	if pinned == 0 {
+		return fmt.Errorf("no TUF root is pinned at %s (expected %s<digest> TXT records)", c.cfg.RootDNS, tufRootDigestPrefix)
+	}
+	return fmt.Errorf("the initial TUF root (sha256:%s) is not pinned at %s", digest, c.cfg.RootDNS)
+}
+
+// updateTimestamp returns the latest timestamp, which must not be older than the trusted timestamp.
+func (c *tufClient) updateTimestamp(base string, root *tufRoot) (*tufMeta, error) {
+	data, err := c.fetch(base, "timestamp.json")
+	if err != nil {
+		return nil, err
+	}
+	var timestamp tufMeta
+	if err := verifyRole(root, "timestamp", data, &timestamp); err != nil {
+		return nil, err
+	}
+	if _, ok := timestamp.Meta["snapshot.json"]; !ok {
+		return nil, fmt.Errorf("the timestamp metadata does not list the snapshot")
+	}
+
+	var trusted tufMeta
+	if c.trusted(root, "timestamp", &trusted) {
+		if timestamp.Version < trusted.Version {
+			return nil, fmt.Errorf("the timestamp metadata (version %d) is older than the trusted version %d, the db listing may be rolled back", timestamp.Version, trusted.Version)
+		}
+		if timestamp.Meta["snapshot.json"].Version < trusted.Meta["snapshot.json"].Version {
+			return nil, fmt.Errorf("the timestamp metadata lists an older snapshot (version %d) than the trusted version %d, the db listing may be rolled back", timestamp.Meta["snapshot.json"].Version, trusted.Meta["snapshot.json"].Version)
+		}
+	}
+	if err := c.checkExpiry(&timestamp.tufCommon); err != nil {
+		return nil, err
+	}
+	return &timestamp, c.store("timestamp.json", data)
+}
+
+// updateSnapshot returns the snapshot listed by the timestamp, which must not list older targets than the trusted
+// snapshot.
+func (c *tufClient) updateSnapshot(base string, root *tufRoot, timestamp *tufMeta) (*tufMeta, error) {
+	meta := timestamp.Meta["snapshot.json"]
+	data, err := c.fetch(base, "snapshot.json")
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyTUFMetaFile(data, meta); err != nil {
+		return nil, fmt.Errorf("snapshot.json: %w", err)
+	}
+	var snapshot tufMeta
+	if err := verifyRole(root, "snapshot", data, &snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Version != meta.Version {
+		return nil, fmt.Errorf("the snapshot metadata is version %d, the timestamp lists version %d", snapshot.Version, meta.Version)
+	}
+	if _, ok := snapshot.Meta["targets.json"]; !ok {
+		return nil, fmt.Errorf("the snapshot metadata does not list the targets")
+	}
+
+	var trusted tufMeta
+	if c.trusted(root, "snapshot", &trusted) {
+		for name, m := range trusted.Meta {
+			current, ok := snapshot.Meta[name]
+			if !ok {
+				return nil, fmt.Errorf("the snapshot metadata no longer lists %s", name)
+			}
+			if current.Version < m.Version {
+				return nil, fmt.Errorf("the snapshot metadata lists an older %s (version %d) than the trusted version %d, the db listing may be rolled back", name, current.Version, m.Version)
+			}
+		}
+	}
+	if err := c.checkExpiry(&snapshot.tufCommon); err != nil {
+		return nil, err
+	}
+	return &snapshot, c.store("snapshot.json", data)
+}
+
+// updateTargets returns the targets listed by the snapshot.
+func (c *tufClient) updateTargets(base string, root *tufRoot, snapshot *tufMeta) (*tufTargets, error) {
+	meta := snapshot.Meta["targets.json"]
+	data, err := c.fetch(base, "targets.json")
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyTUFMetaFile(data, meta); err != nil {
+		return nil, fmt.Errorf("targets.json: %w", err)
+	}
+	var targets tufTargets
+	if err := verifyRole(root, "targets", data, &targets); err != nil {
+		return nil, err
+	}
+	if targets.Version != meta.Version {
+		return nil, fmt.Errorf("the targets metadata is version %d, the snapshot lists version %d", targets.Version, meta.Version)
+	}
+	if err := c.checkExpiry(&targets.tufCommon); err != nil {
+		return nil, err
+	}
+	return &targets, c.store("targets.json", data)
+}
+
+func (c *tufClient) checkExpiry(m *tufCommon) error {
+	if c.currentTime().After(m.Expires) {
+		return fmt.Errorf("the TUF %s metadata (version %d) expired at %s, the db listing may be frozen", m.Type, m.Version, m.Expires.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// trusted reads the stored metadata of the role into v, reporting whether there is stored metadata still signed by
+// the keys of the role.
+func (c *tufClient) trusted(root *tufRoot, role string, v interface{}) bool {
+	data, err := afero.ReadFile(c.fs, filepath.Join(c.dir, role+".json"))
+	if err != nil {
+		return false
+	}
+	if err := verifyRole(root, role, data, v); err != nil {
+		log.WithFields("role", role, "error", err).Debug("ignoring the trusted TUF metadata")
+		return false
+	}
+	return true
+}
+
+// fetch downloads the metadata file from the base URL, errTUFNotFound when there is no such file.
+func (c *tufClient) fetch(base, name string) ([]byte, error) {
+	u := base + "/" + name
+	resp, err := c.client.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download the TUF metadata %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		// any other failure (e.g. a denied request) must not read as "no newer root"
+		return nil, fmt.Errorf("the TUF metadata %s: %w", name, errTUFNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unable to download the TUF metadata %s: %s", name, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, tufMaxMetadataSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to download the TUF metadata %s: %w", name, err)
+	}
+	if len(data) > tufMaxMetadataSize {
+		return nil, fmt.Errorf("the TUF metadata %s is larger than %d bytes", name, tufMaxMetadataSize)
+	}
+	return data, nil
+}
+
+// rootFileName is the name of the root metadata of the version.
+func rootFileName(version int64) string {
+	return fmt.Sprintf("%d.root.json", version)
+}
+
+func (c *tufClient) store(name string, data []byte) error {
+	if err := c.fs.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("unable to store the trusted TUF metadata: %w", err)
+	}
+	if err := afero.WriteFile(c.fs, filepath.Join(c.dir, name), data, 0644); err != nil {
+		return fmt.Errorf("unable to store the trusted TUF metadata: %w", err)
+	}
+	return nil
+}
+
+func (c *tufClient) remove(name string) {
+	if err := c.fs.Remove(filepath.Join(c.dir, name)); err != nil && !errors.Is(err, afero.ErrFileNotFound) {
+		log.WithFields("file", name, "error", err).Warn("unable to remove the trusted TUF metadata")
+	}
+}
+
+// verifyRoot parses the root metadata, which must be signed by the threshold of its own root keys, and by the
+// threshold of the root keys of the trusted root when given.
+func verifyRoot(data []byte, trusted *tufRoot) (*tufRoot, error) {
+	var root tufRoot
+	if trusted != nil {
+		if err := verifyRole(trusted, "root", data, &root); err != nil {
+			return nil, fmt.Errorf("not signed by the trusted root: %w", err)
+		}
+	}
+	if err := verifyRole(nil, "root", data, &root); err != nil {
+		return nil, err
+	}
+	for _, role := range []string{"root", "timestamp", "snapshot", "targets"} {
+		r, ok := root.Roles[role]
+		if !ok || r.Threshold < 1 {
+			return nil, fmt.Errorf("the root metadata has no valid %s role", role)
+		}
+	}
+	if root.ConsistentSnapshot {
+		return nil, fmt.Errorf("consistent snapshots are not supported")
+	}
+	return &root, nil
+}
+
+// verifyRole parses the metadata of the role into v, which must be signed by the threshold of the keys of the role
+// in the root (or in the metadata itself, for a root metadata verified without a trusted root).
+func verifyRole(root *tufRoot, role string, data []byte, v interface{}) error {
+	var envelope tufEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("unable to parse the TUF %s metadata: %w", role, err)
+	}
+	var common tufCommon
+	if err := json.Unmarshal(envelope.Signed, &common); err != nil {
+		return fmt.Errorf("unable to parse the TUF %s metadata: %w", role, err)
+	}
+	if common.Type != role {
+		return fmt.Errorf("expected TUF %s metadata, got %q", role, common.Type)
+	}
+	if err := json.Unmarshal(envelope.Signed, v); err != nil {
+		return fmt.Errorf("unable to parse the TUF %s metadata: %w", role, err)
+	}
+	if root == nil {
+		self, ok := v.(*tufRoot)
+		if !ok {
+			return fmt.Errorf("the TUF %s metadata cannot be verified without a root", role)
+		}
+		root = self
+	}
+
+	r, ok := root.Roles[role]
+	if !ok || r.Threshold < 1 {
+		return fmt.Errorf("the root metadata has no valid %s role", role)
+	}
+	canonical, err := canonicalJSON(envelope.Signed)
+	if err != nil {
+		return fmt.Errorf("unable to encode the TUF %s metadata: %w", role, err)
+	}
+
+	allowed := make(map[string]bool)
+	for _, id := range r.KeyIDs {
+		allowed[id] = true
+	}
+	valid := make(map[string]bool)
+	for _, s := range envelope.Signatures {
+		key, ok := root.Keys[s.KeyID]
+		if !allowed[s.KeyID] || !ok || valid[s.KeyID] {
+			continue
+		}
+		if verifyTUFSignature(key, canonical, s.Sig) {
+			valid[s.KeyID] = true
+		}
+	}
+	if len(valid) < r.Threshold {
+		return fmt.Errorf("the TUF %s metadata has %d valid signatures, %d required", role, len(valid), r.Threshold)
+	}
+	return nil
+}
+
+// verifyTUFSignature reports whether the hex-encoded signature of the message is valid for the key, an ed25519 key or
+// an ECDSA P-256 key (as PEM).
+func verifyTUFSignature(key tufKey, message []byte, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	switch {
+	case key.KeyType == "ed25519" && key.Scheme == "ed25519":
+		public, err := hex.DecodeString(key.KeyVal.Public)
+		if err != nil || len(public) != ed25519.PublicKeySize {
+			return false
+		}
+		return ed25519.Verify(public, message, sig)
+	case strings.HasPrefix(key.KeyType, "ecdsa") && key.Scheme == "ecdsa-sha2-nistp256":
+		block, _ := pem.Decode([]byte(key.KeyVal.Public))
+		if block == nil {
+			return false
+		}
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return false
+		}
+		ecKey, ok := public.(*ecdsa.PublicKey)
+		if !ok {
+			return false
+		}
+		digest := sha256.Sum256(message)
+		return ecdsa.VerifyASN1(ecKey, digest[:], sig)
+	}
+	return false
+}
+
+// verifyTUFMetaFile checks the length and digests of a metadata file, when listed.
+func verifyTUFMetaFile(data []byte, meta tufMetaFile) error {
+	if meta.Length > 0 && int64(len(data)) != meta.Length {
+		return fmt.Errorf("the metadata is %d bytes, %d expected", len(data), meta.Length)
+	}
+	if len(meta.Hashes) == 0 {
+		return nil
+	}
+	return verifyTUFHashes(data, meta.Hashes, false)
+}
+
+// verifyTUFHashes checks the data against every listed digest of a supported algorithm, at least one being required
+// when required is set.
+func verifyTUFHashes(data []byte, hashes map[string]string, required bool) error {
+	var verified int
+	for algorithm, want := range hashes {
+		var h hash.Hash
+		switch strings.ToLower(algorithm) {
+		case "sha256":
+			h = sha256.New()
+		case "sha512":
+			h = sha512.New()
+		default:
+			continue
+		}
+		h.Write(data)
+		if !strings.EqualFold(hex.EncodeToString(h.Sum(nil)), want) {
+			return fmt.Errorf("the %s digest does not match the TUF metadata", algorithm)
+		}
+		verified++
+	}
+	if required && verified == 0 {
+		return fmt.Errorf("the TUF metadata lists no sha256 or sha512 digest")
+	}
+	return nil
+}
+
+func sameKeys(a, b tufRole) bool {
+	if a.Threshold != b.Threshold || len(a.KeyIDs) != len(b.KeyIDs) {
+		return false
+	}
+	x := append([]string(nil), a.KeyIDs...)
+	y := append([]string(nil), b.KeyIDs...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalJSON encodes the JSON document in the canonical form signed by TUF (OLPC canonical JSON): the object keys
+// sorted, no insignificant whitespace, integers only, and only the quote and the backslash escaped in strings.
+func canonicalJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v interface{}) error {
+	switch value := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(value))
+	case json.Number:
+		i, err := value.Int64()
+		if err != nil {
+			return fmt.Errorf("non-integer number %s", value)
+		}
+		buf.WriteString(strconv.FormatInt(i, 10))
+	case string:
+		if !utf8.ValidString(value) {
+			return fmt.Errorf("invalid UTF-8 string")
+		}
+		buf.WriteByte('"')
+		for _, r := range value {
+			if r == '"' || r == '\\' {
+				buf.WriteByte('\\')
+			}
+			buf.WriteRune(r)
+		}
+		buf.WriteByte('"')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, e := range value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, value[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported value %T", v)
+	}
+	return nil
+}
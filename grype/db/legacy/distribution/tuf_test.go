@@ -0,0 +1,326 @@
+package distribution
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tufTestNow = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+type tufTestKey struct {
+	id      string
+	public  ed25519.PublicKey
+	private ed25519.PrivateKey
+}
+
+func newTUFTestKey(t *testing.T) tufTestKey {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sum := sha256.Sum256(public)
+	return tufTestKey{id: hex.EncodeToString(sum[:]), public: public, private: private}
+}
+
+// tufTestRepo publishes the TUF metadata of a listing file, signed with the keys of its roles.
+type tufTestRepo struct {
+	t       *testing.T
+	mu      sync.Mutex
+	files   map[string][]byte
+	keys    map[string][]tufTestKey
+	root    int64
+	version int64
+	expires time.Time
+}
+
+func newTUFTestRepo(t *testing.T) *tufTestRepo {
+	r := &tufTestRepo{
+		t:       t,
+		files:   map[string][]byte{},
+		keys:    map[string][]tufTestKey{},
+		expires: tufTestNow.Add(24 * time.Hour),
+	}
+	for _, role := range []string{"root", "timestamp", "snapshot", "targets"} {
+		r.keys[role] = []tufTestKey{newTUFTestKey(t)}
+	}
+	r.publishRoot(r.keys["root"])
+	return r
+}
+
+func (r *tufTestRepo) sign(signed interface{}, keys ...tufTestKey) []byte {
+	data, err := json.Marshal(signed)
+	require.NoError(r.t, err)
+	canonical, err := canonicalJSON(data)
+	require.NoError(r.t, err)
+	envelope := tufEnvelope{Signed: data, Signatures: []tufSignature{}}
+	for _, k := range keys {
+		envelope.Signatures = append(envelope.Signatures, tufSignature{KeyID: k.id, Sig: hex.EncodeToString(ed25519.Sign(k.private, canonical))})
+	}
+	out, err := json.Marshal(envelope)
+	require.NoError(r.t, err)
+	return out
+}
+
+// publishRoot publishes the next root version with the current keys, signed by the given keys.
+func (r *tufTestRepo) publishRoot(signers []tufTestKey) {
+	r.root++
+	root := tufRoot{
+		tufCommon: tufCommon{Type: "root", Version: r.root, Expires: r.expires},
+		Keys:      map[string]tufKey{},
+		Roles:     map[string]tufRole{},
+	}
+	for role, keys := range r.keys {
+		var ids []string
+		for _, k := range keys {
+			key := tufKey{KeyType: "ed25519", Scheme: "ed25519"}
+			key.KeyVal.Public = hex.EncodeToString(k.public)
+			root.Keys[k.id] = key
+			ids = append(ids, k.id)
+		}
+		root.Roles[role] = tufRole{KeyIDs: ids, Threshold: 1}
+	}
+	r.set(fmt.Sprintf("%d.root.json", r.root), r.sign(root, signers...))
+}
+
+// publish publishes a new version of the targets, the snapshot and the timestamp for the listing.
+func (r *tufTestRepo) publish(listing []byte) {
+	r.version++
+	sum := sha256.Sum256(listing)
+	targets := r.sign(tufTargets{
+		tufCommon: tufCommon{Type: "targets", Version: r.version, Expires: r.expires},
+		Targets:   map[string]tufTargetFile{"listing.json": {Length: int64(len(listing)), Hashes: map[string]string{"sha256": hex.EncodeToString(sum[:])}}},
+	}, r.keys["targets"]...)
+	snapshot := r.sign(tufMeta{
+		tufCommon: tufCommon{Type: "snapshot", Version: r.version, Expires: r.expires},
+		Meta:      map[string]tufMetaFile{"targets.json": {Version: r.version}},
+	}, r.keys["snapshot"]...)
+	snapshotSum := sha256.Sum256(snapshot)
+	timestamp := r.sign(tufMeta{
+		tufCommon: tufCommon{Type: "timestamp", Version: r.version, Expires: r.expires},
+		Meta:      map[string]tufMetaFile{"snapshot.json": {Version: r.version, Length: int64(len(snapshot)), Hashes: map[string]string{"sha256": hex.EncodeToString(snapshotSum[:])}}},
+	}, r.keys["timestamp"]...)
+
+	r.set("targets.json", targets)
+	r.set("snapshot.json", snapshot)
+	r.set("timestamp.json", timestamp)
+	r.set("listing.json", listing)
+}
+
+func (r *tufTestRepo) set(name string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files[name] = data
+}
+
+func (r *tufTestRepo) get(name string) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.files[name]
+}
+
+func (r *tufTestRepo) serve() *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data := r.get(filepath.Base(req.URL.Path))
+		if data == nil {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	r.t.Cleanup(srv.Close)
+	return srv
+}
+
+// verify verifies the listing currently published with a client trusting the first root.
+func (r *tufTestRepo) verify(fs afero.Fs, srv *httptest.Server, now time.Time) error {
+	require.NoError(r.t, afero.WriteFile(fs, "/1.root.json", r.get("1.root.json"), 0644))
+	require.NoError(r.t, afero.WriteFile(fs, "/listing.json", r.get("listing.json"), 0644))
+	c := newTUFClient(fs, "/db/tuf", TUFConfig{Root: "/1.root.json"}, srv.Client(), func() time.Time { return now })
+	return c.verifyListing(srv.URL+"/databases/listing.json", "/listing.json")
+}
+
+func Test_tufClient_verifyListing(t *testing.T) {
+	repo := newTUFTestRepo(t)
+	repo.publish([]byte(`{"available":{}}`))
+	srv := repo.serve()
+	fs := afero.NewMemMapFs()
+
+	require.NoError(t, repo.verify(fs, srv, tufTestNow))
+	for _, name := range []string{"timestamp.json", "snapshot.json", "targets.json"} {
+		exists, err := afero.Exists(fs, filepath.Join("/db/tuf", name))
+		require.NoError(t, err)
+		assert.True(t, exists, name)
+	}
+
+	t.Run("tampered listing", func(t *testing.T) {
+		require.NoError(t, afero.WriteFile(fs, "/tampered.json", []byte(`{"available":{"5":[]}}`), 0644))
+		c := newTUFClient(fs, "/db/tuf", TUFConfig{Root: "/1.root.json"}, srv.Client(), func() time.Time { return tufTestNow })
+		err := c.verifyListing(srv.URL+"/databases/listing.json", "/tampered.json")
+		require.ErrorContains(t, err, "listing.json is 22 bytes, the targets metadata lists 16 bytes")
+	})
+
+	t.Run("expired metadata", func(t *testing.T) {
+		err := repo.verify(afero.NewMemMapFs(), srv, tufTestNow.Add(48*time.Hour))
+		require.ErrorContains(t, err, "expired")
+		require.ErrorContains(t, err, "may be frozen")
+	})
+}
+
+func Test_tufClient_rollback(t *testing.T) {
+	repo := newTUFTestRepo(t)
+	repo.publish([]byte(`{"available":{}}`))
+	old := map[string][]byte{}
+	for _, name := range []string{"timestamp.json", "snapshot.json", "targets.json", "listing.json"} {
+		old[name] = repo.get(name)
+	}
+	repo.publish([]byte(`{"available":{"5":[]}}`))
+	srv := repo.serve()
+	fs := afero.NewMemMapFs()
+	require.NoError(t, repo.verify(fs, srv, tufTestNow))
+
+	// the mirror serves the previous (validly signed) metadata and listing
+	for name, data := range old {
+		repo.set(name, data)
+	}
+	err := repo.verify(fs, srv, tufTestNow)
+	require.ErrorContains(t, err, "the timestamp metadata (version 1) is older than the trusted version 2")
+
+	// a client without trusted metadata yet cannot tell
+	require.NoError(t, repo.verify(afero.NewMemMapFs(), srv, tufTestNow))
+}
+
+func Test_tufClient_rootRotation(t *testing.T) {
+	repo := newTUFTestRepo(t)
+	repo.publish([]byte(`{"available":{}}`))
+	srv := repo.serve()
+	fs := afero.NewMemMapFs()
+	require.NoError(t, repo.verify(fs, srv, tufTestNow))
+
+	// rotate the root and timestamp keys: the new root is signed by both the old and the new root keys
+	oldRoot, oldTimestamp := repo.keys["root"], repo.keys["timestamp"]
+	repo.keys["root"] = []tufTestKey{newTUFTestKey(t)}
+	repo.keys["timestamp"] = []tufTestKey{newTUFTestKey(t)}
+	repo.publishRoot(append(append([]tufTestKey{}, oldRoot...), repo.keys["root"]...))
+	repo.publish([]byte(`{"available":{"5":[]}}`))
+
+	require.NoError(t, repo.verify(fs, srv, tufTestNow))
+	data, err := afero.ReadFile(fs, "/db/tuf/2.root.json")
+	require.NoError(t, err)
+	root, err := verifyRoot(data, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), root.Version)
+
+	t.Run("stored root not chained to the initial root", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, repo.verify(fs, srv, tufTestNow))
+
+		// a root stored in the cache directory is only trusted when signed by the trusted root before it
+		evil := newTUFTestRepo(t)
+		evil.publishRoot(evil.keys["root"])
+		require.NoError(t, afero.WriteFile(fs, "/db/tuf/3.root.json", evil.get("2.root.json"), 0644))
+		c := newTUFClient(fs, "/db/tuf", TUFConfig{Root: "/1.root.json"}, srv.Client(), func() time.Time { return tufTestNow })
+		root, err := c.trustedRoot(srv.URL)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), root.Version)
+		exists, err := afero.Exists(fs, "/db/tuf/3.root.json")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	// the revoked timestamp key is no longer trusted
+	repo.keys["timestamp"] = oldTimestamp
+	repo.publish([]byte(`{"available":{"5":[]}}`))
+	require.ErrorContains(t, repo.verify(fs, srv, tufTestNow), "the TUF timestamp metadata has 0 valid signatures, 1 required")
+
+	t.Run("root not signed by the trusted root", func(t *testing.T) {
+		repo := newTUFTestRepo(t)
+		repo.publish([]byte(`{"available":{}}`))
+		repo.keys["root"] = []tufTestKey{newTUFTestKey(t)}
+		repo.publishRoot(repo.keys["root"])
+		err := repo.verify(afero.NewMemMapFs(), repo.serve(), tufTestNow)
+		require.ErrorContains(t, err, "unable to rotate the root to version 2: not signed by the trusted root")
+	})
+}
+
+func Test_tufClient_rootRotationDenied(t *testing.T) {
+	repo := newTUFTestRepo(t)
+	repo.publish([]byte(`{"available":{}}`))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		name := filepath.Base(req.URL.Path)
+		if name == "2.root.json" {
+			// a denied request is not the absence of a newer root
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write(repo.get(name))
+	}))
+	t.Cleanup(srv.Close)
+
+	require.ErrorContains(t, repo.verify(afero.NewMemMapFs(), srv, tufTestNow), "403")
+}
+
+func Test_tufClient_pinnedRoot(t *testing.T) {
+	repo := newTUFTestRepo(t)
+	repo.publish([]byte(`{"available":{}}`))
+	srv := repo.serve()
+	sum := sha256.Sum256(repo.get("1.root.json"))
+
+	tests := []struct {
+		name    string
+		records []string
+		wantErr string
+	}{
+		{
+			name:    "pinned",
+			records: []string{"v=spf1 -all", tufRootDigestPrefix + hex.EncodeToString(sum[:])},
+		},
+		{
+			name:    "not pinned",
+			records: []string{tufRootDigestPrefix + "00"},
+			wantErr: "is not pinned at db.example.com",
+		},
+		{
+			name:    "no pins",
+			records: []string{"v=spf1 -all"},
+			wantErr: "no TUF root is pinned at db.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "/listing.json", repo.get("listing.json"), 0644))
+			c := newTUFClient(fs, "/db/tuf", TUFConfig{RootDNS: "db.example.com"}, srv.Client(), func() time.Time { return tufTestNow })
+			c.lookupTXT = func(name string) ([]string, error) {
+				assert.Equal(t, "db.example.com", name)
+				return tt.records, nil
+			}
+			err := c.verifyListing(srv.URL+"/listing.json", "/listing.json")
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_canonicalJSON(t *testing.T) {
+	got, err := canonicalJSON([]byte(`{ "b": [1, true, null], "a": "q\"\\<\n" }`))
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a\":\"q\\\"\\\\<\n\",\"b\":[1,true,null]}", string(got))
+
+	_, err = canonicalJSON([]byte(`{"a": 1.5}`))
+	require.ErrorContains(t, err, "non-integer number 1.5")
+}
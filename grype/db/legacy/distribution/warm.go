@@ -0,0 +1,112 @@
+package distribution
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/anchore/grype/grype/db/v5/store"
+	"github.com/anchore/grype/internal/log"
+)
+
+// WarmResult describes the work done to warm the active DB.
+type WarmResult struct {
+	// Files is the number of DB files that were read.
+	Files int
+	// Bytes is the total size of the DB files that were read.
+	Bytes int64
+	// MissingIndexes are the search indexes missing from the DB files (see store.Warm).
+	MissingIndexes []string
+	// Duration is how long warming took.
+	Duration time.Duration
+}
+
+// Warm validates the active DB and pre-builds the structures derived from it (the shards with their query planner
+// statistics, when enabled), then reads the DB files and their search indexes through once, so the first scan after
+// activation does not pay these costs.
+func (c *Curator) Warm() (WarmResult, error) {
+	start := time.Now()
+
+	metadata, err := c.validateIntegrity(c.dbDir)
+	if err != nil {
+		return WarmResult{}, fmt.Errorf("vulnerability database is invalid (run db update to correct): %+v", err)
+	}
+	return c.warm(metadata, start)
+}
+
+// warm warms the active DB, which has already been validated (so that the DB is not hashed again).
+func (c *Curator) warm(metadata Metadata, start time.Time) (WarmResult, error) {
+	files := []string{c.dbPath}
+	if c.shard {
+		if err := c.ensureShards(c.dbDir, metadata); err != nil {
+			return WarmResult{}, err
+		}
+		shardDir := filepath.Join(c.dbDir, store.ShardDirName)
+		index, err := store.ReadShardIndex(shardDir)
+		if err != nil {
+			return WarmResult{}, fmt.Errorf("unable to read shard index: %w", err)
+		}
+		// only the shards are read during scans, so they are all that needs to be warm
+		files = files[:0]
+		for _, entry := range index.Shards {
+			files = append(files, filepath.Join(shardDir, entry.File))
+		}
+	}
+
+	result := WarmResult{}
+	for _, f := range files {
+		n, err := c.readThrough(f)
+		if err != nil {
+			return WarmResult{}, err
+		}
+		result.Files++
+		result.Bytes += n
+
+		// opening each file proves it is usable now rather than during the first scan
+		missing, err := store.Warm(f)
+		if err != nil {
+			return WarmResult{}, fmt.Errorf("unable to warm DB file (%s): %w", f, err)
+		}
+		result.MissingIndexes = append(result.MissingIndexes, missing...)
+	}
+
+	result.Duration = time.Since(start)
+	log.WithFields("files", result.Files, "bytes", result.Bytes, "time", result.Duration).Debug("warmed vulnerability database")
+	return result, nil
+}
+
+// readThrough reads the entire file, leaving it in the OS page cache for the next reader.
+func (c *Curator) readThrough(filePath string) (int64, error) {
+	fh, err := c.fs.Open(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open DB file: %w", err)
+	}
+	defer fh.Close()
+
+	n, err := io.Copy(io.Discard, fh)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read DB file (%s): %w", filePath, err)
+	}
+	return n, nil
+}
+
+// warmAfterActivation warms the newly activated DB, when configured to. The DB was validated before its activation, so
+// it is not hashed again. Failure to warm does not fail the activation, since scans work the same (only slower)
+// without it.
+func (c *Curator) warmAfterActivation() {
+	if !c.warmOnActivate {
+		return
+	}
+	start := time.Now()
+	metadata, err := NewMetadataFromDir(c.fs, c.dbDir)
+	if err == nil && metadata == nil {
+		err = fmt.Errorf("database metadata not found: %s", c.dbDir)
+	}
+	if err == nil {
+		_, err = c.warm(*metadata, start)
+	}
+	if err != nil {
+		log.Warnf("unable to warm vulnerability database: %+v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/internal/log"
+)
+
+var _ match.TargetingProvider = (*PackageTargetingProvider)(nil)
+
+type PackageTargetingProvider struct {
+	reader  grypeDB.PackageTargetingStoreReader
+	once    sync.Once
+	entries []grypeDB.PackageTargeting
+	err     error
+}
+
+func NewPackageTargetingProvider(reader grypeDB.PackageTargetingStoreReader) *PackageTargetingProvider {
+	return &PackageTargetingProvider{
+		reader: reader,
+	}
+}
+
+// GetTargeting returns the default targeting of the given distro, where an entry for the exact distro version takes
+// precedence over one for the major version, which takes precedence over one for all versions of the distro.
+func (pr *PackageTargetingProvider) GetTargeting(d *distro.Distro) (match.Targeting, error) {
+	if d == nil {
+		return match.DefaultTargeting, nil
+	}
+
+	pr.once.Do(func() {
+		pr.entries, pr.err = pr.reader.GetPackageTargeting()
+	})
+	if pr.err != nil {
+		return match.DefaultTargeting, fmt.Errorf("package targeting provider failed to fetch records: %w", pr.err)
+	}
+
+	best, bestRank := match.DefaultTargeting, 0
+	for _, e := range pr.entries {
+		if !strings.EqualFold(e.DistroType, string(d.Type)) {
+			continue
+		}
+
+		var rank int
+		switch e.DistroVersion {
+		case "":
+			rank = 1
+		case d.MajorVersion():
+			rank = 2
+		case d.FullVersion():
+			rank = 3
+		default:
+			continue
+		}
+		if rank <= bestRank {
+			continue
+		}
+
+		targeting, err := match.ParseTargeting(e.Targeting)
+		if err != nil {
+			log.WithFields("distro", d, "targeting", e.Targeting).Debug("skipping unsupported package targeting")
+			continue
+		}
+		best, bestRank = targeting, rank
+	}
+	return best, nil
+}
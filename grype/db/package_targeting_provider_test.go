@@ -0,0 +1,56 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+)
+
+type mockPackageTargetingReader []grypeDB.PackageTargeting
+
+func (r mockPackageTargetingReader) GetPackageTargeting() ([]grypeDB.PackageTargeting, error) {
+	return r, nil
+}
+
+func TestPackageTargetingProvider_GetTargeting(t *testing.T) {
+	provider := NewPackageTargetingProvider(mockPackageTargetingReader{
+		{DistroType: "debian", Targeting: "source"},
+		{DistroType: "redhat", Targeting: "binary"},
+		{DistroType: "redhat", DistroVersion: "8", Targeting: "both"},
+		{DistroType: "redhat", DistroVersion: "8.6", Targeting: "source"},
+		{DistroType: "ubuntu", Targeting: "upstream"},
+	})
+
+	tests := []struct {
+		distroType distro.Type
+		version    string
+		want       match.Targeting
+	}{
+		{distroType: distro.Debian, version: "12", want: match.SourceTargeting},
+		{distroType: distro.RedHat, version: "9", want: match.BinaryTargeting},
+		{distroType: distro.RedHat, version: "8.4", want: match.BothTargeting},
+		{distroType: distro.RedHat, version: "8.6", want: match.SourceTargeting},
+		// unsupported values are ignored
+		{distroType: distro.Ubuntu, version: "22.04", want: match.DefaultTargeting},
+		{distroType: distro.Alpine, version: "3.20", want: match.DefaultTargeting},
+	}
+	for _, test := range tests {
+		t.Run(string(test.distroType)+":"+test.version, func(t *testing.T) {
+			d, err := distro.New(test.distroType, test.version, "")
+			require.NoError(t, err)
+
+			got, err := provider.GetTargeting(d)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	got, err := provider.GetTargeting(nil)
+	require.NoError(t, err)
+	assert.Equal(t, match.DefaultTargeting, got)
+}
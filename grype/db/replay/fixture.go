@@ -0,0 +1,160 @@
+/*
+Package replay records the DB reads made during a scan into a fixture file and replays them without a real DB,
+allowing fast and deterministic tests of grype-based pipelines. Wrap a DB reader with NewRecorder, run the scan against
+NewStore(recorder), then write recorder.Fixture(); later, build the store from OpenReplayer(path) instead.
+*/
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/pkg/qualifier"
+)
+
+// FixtureSchemaVersion is the version of the fixture file format (not the DB schema).
+const FixtureSchemaVersion = "1.0.0"
+
+// Fixture is the serialized form of every DB lookup made during one or more scans.
+type Fixture struct {
+	Schema     string           `json:"schema"`
+	Namespaces []string         `json:"namespaces"`
+	Searches   []SearchEntry    `json:"searches,omitempty"`
+	Lookups    []LookupEntry    `json:"lookups,omitempty"`
+	Metadata   []MetadataEntry  `json:"metadata,omitempty"`
+	Exclusions []ExclusionEntry `json:"exclusions,omitempty"`
+}
+
+// SearchEntry is the recorded result of searching for vulnerabilities by namespace and package name.
+type SearchEntry struct {
+	Namespace       string                `json:"namespace"`
+	PackageName     string                `json:"packageName"`
+	Vulnerabilities []vulnerabilityRecord `json:"vulnerabilities"`
+}
+
+// LookupEntry is the recorded result of fetching vulnerabilities by namespace and ID.
+type LookupEntry struct {
+	Namespace       string                `json:"namespace"`
+	ID              string                `json:"id"`
+	Vulnerabilities []vulnerabilityRecord `json:"vulnerabilities"`
+}
+
+// MetadataEntry is the recorded result of fetching vulnerability metadata by ID and namespace.
+type MetadataEntry struct {
+	ID        string                         `json:"id"`
+	Namespace string                         `json:"namespace"`
+	Metadata  *grypeDB.VulnerabilityMetadata `json:"metadata"`
+}
+
+// ExclusionEntry is the recorded result of fetching match exclusions by vulnerability ID.
+type ExclusionEntry struct {
+	ID         string                                `json:"id"`
+	Exclusions []grypeDB.VulnerabilityMatchExclusion `json:"exclusions"`
+}
+
+// vulnerabilityRecord wraps a DB vulnerability so that package qualifiers (which are interfaces) survive a JSON
+// round trip in the same way they are read from the sqlite store.
+type vulnerabilityRecord struct {
+	grypeDB.Vulnerability
+	PackageQualifiers json.RawMessage `json:"package_qualifiers,omitempty"`
+}
+
+func newVulnerabilityRecords(vulns []grypeDB.Vulnerability) ([]vulnerabilityRecord, error) {
+	records := make([]vulnerabilityRecord, 0, len(vulns))
+	for _, v := range vulns {
+		r := vulnerabilityRecord{Vulnerability: v}
+		if len(v.PackageQualifiers) > 0 {
+			raw, err := json.Marshal(v.PackageQualifiers)
+			if err != nil {
+				return nil, fmt.Errorf("unable to encode package qualifiers for %q: %w", v.ID, err)
+			}
+			r.PackageQualifiers = raw
+		}
+		r.Vulnerability.PackageQualifiers = nil
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func toVulnerabilities(records []vulnerabilityRecord) ([]grypeDB.Vulnerability, error) {
+	vulns := make([]grypeDB.Vulnerability, 0, len(records))
+	for _, r := range records {
+		v := r.Vulnerability
+		if len(r.PackageQualifiers) > 0 {
+			qualifiers, err := qualifier.FromJSON(r.PackageQualifiers)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode package qualifiers for %q: %w", v.ID, err)
+			}
+			v.PackageQualifiers = qualifiers
+		}
+		vulns = append(vulns, v)
+	}
+	return vulns, nil
+}
+
+// sort orders all entries so that fixtures written from the same set of lookups are byte-for-byte identical.
+func (f *Fixture) sort() {
+	sort.Strings(f.Namespaces)
+	sort.Slice(f.Searches, func(i, j int) bool {
+		a, b := f.Searches[i], f.Searches[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.PackageName < b.PackageName
+	})
+	sort.Slice(f.Lookups, func(i, j int) bool {
+		a, b := f.Lookups[i], f.Lookups[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.ID < b.ID
+	})
+	sort.Slice(f.Metadata, func(i, j int) bool {
+		a, b := f.Metadata[i], f.Metadata[j]
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Namespace < b.Namespace
+	})
+	sort.Slice(f.Exclusions, func(i, j int) bool {
+		return f.Exclusions[i].ID < f.Exclusions[j].ID
+	})
+}
+
+// Write serializes the fixture to the given path.
+func (f Fixture) Write(path string) error {
+	fh, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create fixture file: %w", err)
+	}
+	defer fh.Close()
+
+	f.sort()
+	enc := json.NewEncoder(fh)
+	enc.SetIndent("", " ")
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("unable to write fixture file: %w", err)
+	}
+	return nil
+}
+
+// ReadFixture reads a fixture previously written with Fixture.Write.
+func ReadFixture(path string) (*Fixture, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open fixture file: %w", err)
+	}
+	defer fh.Close()
+
+	var f Fixture
+	if err := json.NewDecoder(fh).Decode(&f); err != nil {
+		return nil, fmt.Errorf("unable to decode fixture file: %w", err)
+	}
+	if f.Schema != FixtureSchemaVersion {
+		return nil, fmt.Errorf("unsupported fixture schema %q (expected %q)", f.Schema, FixtureSchemaVersion)
+	}
+	return &f, nil
+}
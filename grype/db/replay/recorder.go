@@ -0,0 +1,144 @@
+package replay
+
+import (
+	"sync"
+
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+)
+
+// Reader is the subset of DB store reads that are made during matching.
+type Reader interface {
+	grypeDB.VulnerabilityStoreReader
+	grypeDB.VulnerabilityMetadataStoreReader
+	grypeDB.VulnerabilityMatchExclusionStoreReader
+}
+
+var _ Reader = (*Recorder)(nil)
+
+type searchKey struct {
+	namespace, name string
+}
+
+// Recorder passes reads through to a real DB reader while capturing every result for later replay.
+type Recorder struct {
+	reader     Reader
+	lock       sync.Mutex
+	namespaces []string
+	searches   map[searchKey][]grypeDB.Vulnerability
+	lookups    map[searchKey][]grypeDB.Vulnerability
+	metadata   map[searchKey]*grypeDB.VulnerabilityMetadata
+	exclusions map[string][]grypeDB.VulnerabilityMatchExclusion
+}
+
+func NewRecorder(reader Reader) *Recorder {
+	return &Recorder{
+		reader:     reader,
+		searches:   make(map[searchKey][]grypeDB.Vulnerability),
+		lookups:    make(map[searchKey][]grypeDB.Vulnerability),
+		metadata:   make(map[searchKey]*grypeDB.VulnerabilityMetadata),
+		exclusions: make(map[string][]grypeDB.VulnerabilityMatchExclusion),
+	}
+}
+
+func (r *Recorder) GetVulnerabilityNamespaces() ([]string, error) {
+	namespaces, err := r.reader.GetVulnerabilityNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.namespaces = namespaces
+	return namespaces, nil
+}
+
+func (r *Recorder) GetVulnerability(namespace, id string) ([]grypeDB.Vulnerability, error) {
+	vulns, err := r.reader.GetVulnerability(namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.lookups[searchKey{namespace: namespace, name: id}] = vulns
+	return vulns, nil
+}
+
+func (r *Recorder) SearchForVulnerabilities(namespace, packageName string) ([]grypeDB.Vulnerability, error) {
+	vulns, err := r.reader.SearchForVulnerabilities(namespace, packageName)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.searches[searchKey{namespace: namespace, name: packageName}] = vulns
+	return vulns, nil
+}
+
+// GetAllVulnerabilities is passed through but not recorded (full table scans are not part of matching).
+func (r *Recorder) GetAllVulnerabilities() (*[]grypeDB.Vulnerability, error) {
+	return r.reader.GetAllVulnerabilities()
+}
+
+func (r *Recorder) GetVulnerabilityMetadata(id, namespace string) (*grypeDB.VulnerabilityMetadata, error) {
+	metadata, err := r.reader.GetVulnerabilityMetadata(id, namespace)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.metadata[searchKey{namespace: namespace, name: id}] = metadata
+	return metadata, nil
+}
+
+// GetAllVulnerabilityMetadata is passed through but not recorded (full table scans are not part of matching).
+func (r *Recorder) GetAllVulnerabilityMetadata() (*[]grypeDB.VulnerabilityMetadata, error) {
+	return r.reader.GetAllVulnerabilityMetadata()
+}
+
+func (r *Recorder) GetVulnerabilityMatchExclusion(id string) ([]grypeDB.VulnerabilityMatchExclusion, error) {
+	exclusions, err := r.reader.GetVulnerabilityMatchExclusion(id)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.exclusions[id] = exclusions
+	return exclusions, nil
+}
+
+// Fixture returns everything recorded so far.
+func (r *Recorder) Fixture() (*Fixture, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	f := &Fixture{
+		Schema:     FixtureSchemaVersion,
+		Namespaces: append([]string{}, r.namespaces...),
+	}
+
+	for k, vulns := range r.searches {
+		records, err := newVulnerabilityRecords(vulns)
+		if err != nil {
+			return nil, err
+		}
+		f.Searches = append(f.Searches, SearchEntry{Namespace: k.namespace, PackageName: k.name, Vulnerabilities: records})
+	}
+
+	for k, vulns := range r.lookups {
+		records, err := newVulnerabilityRecords(vulns)
+		if err != nil {
+			return nil, err
+		}
+		f.Lookups = append(f.Lookups, LookupEntry{Namespace: k.namespace, ID: k.name, Vulnerabilities: records})
+	}
+
+	for k, m := range r.metadata {
+		f.Metadata = append(f.Metadata, MetadataEntry{ID: k.name, Namespace: k.namespace, Metadata: m})
+	}
+
+	for id, e := range r.exclusions {
+		f.Exclusions = append(f.Exclusions, ExclusionEntry{ID: id, Exclusions: e})
+	}
+
+	f.sort()
+	return f, nil
+}
@@ -0,0 +1,125 @@
+package replay
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/pkg/qualifier"
+	"github.com/anchore/grype/grype/db/v5/pkg/qualifier/rpmmodularity"
+)
+
+type mockReader struct {
+	vulns map[string]map[string][]grypeDB.Vulnerability
+}
+
+func (m mockReader) GetVulnerabilityNamespaces() ([]string, error) {
+	var namespaces []string
+	for ns := range m.vulns {
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces, nil
+}
+
+func (m mockReader) GetVulnerability(namespace, id string) ([]grypeDB.Vulnerability, error) {
+	var results []grypeDB.Vulnerability
+	for _, vulns := range m.vulns[namespace] {
+		for _, v := range vulns {
+			if v.ID == id {
+				results = append(results, v)
+			}
+		}
+	}
+	return results, nil
+}
+
+func (m mockReader) SearchForVulnerabilities(namespace, packageName string) ([]grypeDB.Vulnerability, error) {
+	return m.vulns[namespace][packageName], nil
+}
+
+func (m mockReader) GetAllVulnerabilities() (*[]grypeDB.Vulnerability, error) {
+	return nil, nil
+}
+
+func (m mockReader) GetVulnerabilityMetadata(id, namespace string) (*grypeDB.VulnerabilityMetadata, error) {
+	return &grypeDB.VulnerabilityMetadata{ID: id, Namespace: namespace, Severity: "High"}, nil
+}
+
+func (m mockReader) GetAllVulnerabilityMetadata() (*[]grypeDB.VulnerabilityMetadata, error) {
+	return nil, nil
+}
+
+func (m mockReader) GetVulnerabilityMatchExclusion(_ string) ([]grypeDB.VulnerabilityMatchExclusion, error) {
+	return nil, nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ns := "redhat:distro:redhat:8"
+	reader := mockReader{
+		vulns: map[string]map[string][]grypeDB.Vulnerability{
+			ns: {
+				"nodejs": {
+					{
+						ID:                "CVE-2021-1234",
+						PackageName:       "nodejs",
+						Namespace:         ns,
+						VersionConstraint: "< 12.0.0",
+						VersionFormat:     "rpm",
+						PackageQualifiers: []qualifier.Qualifier{
+							rpmmodularity.Qualifier{Kind: "rpm-modularity", Module: "nodejs:12"},
+						},
+						Fix: grypeDB.Fix{Versions: []string{"12.0.0"}, State: grypeDB.FixedState},
+					},
+				},
+			},
+		},
+	}
+
+	recorder := NewRecorder(reader)
+
+	_, err := recorder.GetVulnerabilityNamespaces()
+	require.NoError(t, err)
+	expected, err := recorder.SearchForVulnerabilities(ns, "nodejs")
+	require.NoError(t, err)
+	_, err = recorder.SearchForVulnerabilities(ns, "not-vulnerable")
+	require.NoError(t, err)
+	expectedMetadata, err := recorder.GetVulnerabilityMetadata("CVE-2021-1234", ns)
+	require.NoError(t, err)
+
+	fixture, err := recorder.Fixture()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, fixture.Write(path))
+
+	replayer, err := OpenReplayer(path)
+	require.NoError(t, err)
+
+	namespaces, err := replayer.GetVulnerabilityNamespaces()
+	require.NoError(t, err)
+	assert.Equal(t, []string{ns}, namespaces)
+
+	actual, err := replayer.SearchForVulnerabilities(ns, "nodejs")
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.True(t, expected[0].Equal(actual[0]))
+	assert.Equal(t, expected[0].PackageQualifiers, actual[0].PackageQualifiers)
+
+	empty, err := replayer.SearchForVulnerabilities(ns, "not-vulnerable")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	actualMetadata, err := replayer.GetVulnerabilityMetadata("CVE-2021-1234", ns)
+	require.NoError(t, err)
+	assert.Equal(t, expectedMetadata, actualMetadata)
+
+	_, err = replayer.SearchForVulnerabilities(ns, "never-searched")
+	assert.ErrorIs(t, err, ErrNotRecorded)
+
+	s, err := NewStore(replayer)
+	require.NoError(t, err)
+	assert.NotNil(t, s.Provider)
+}
@@ -0,0 +1,112 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+)
+
+// ErrNotRecorded is returned when a lookup is made during replay that was not captured in the fixture. This
+// typically means the scan under test has diverged from the scan that was recorded.
+var ErrNotRecorded = errors.New("lookup was not recorded in the replay fixture")
+
+var _ Reader = (*Replayer)(nil)
+
+// Replayer serves DB reads exclusively from a fixture, without access to a real DB.
+type Replayer struct {
+	namespaces []string
+	searches   map[searchKey][]grypeDB.Vulnerability
+	lookups    map[searchKey][]grypeDB.Vulnerability
+	metadata   map[searchKey]*grypeDB.VulnerabilityMetadata
+	exclusions map[string][]grypeDB.VulnerabilityMatchExclusion
+}
+
+func NewReplayer(f Fixture) (*Replayer, error) {
+	r := &Replayer{
+		namespaces: f.Namespaces,
+		searches:   make(map[searchKey][]grypeDB.Vulnerability),
+		lookups:    make(map[searchKey][]grypeDB.Vulnerability),
+		metadata:   make(map[searchKey]*grypeDB.VulnerabilityMetadata),
+		exclusions: make(map[string][]grypeDB.VulnerabilityMatchExclusion),
+	}
+
+	for _, s := range f.Searches {
+		vulns, err := toVulnerabilities(s.Vulnerabilities)
+		if err != nil {
+			return nil, err
+		}
+		r.searches[searchKey{namespace: s.Namespace, name: s.PackageName}] = vulns
+	}
+
+	for _, l := range f.Lookups {
+		vulns, err := toVulnerabilities(l.Vulnerabilities)
+		if err != nil {
+			return nil, err
+		}
+		r.lookups[searchKey{namespace: l.Namespace, name: l.ID}] = vulns
+	}
+
+	for _, m := range f.Metadata {
+		r.metadata[searchKey{namespace: m.Namespace, name: m.ID}] = m.Metadata
+	}
+
+	for _, e := range f.Exclusions {
+		r.exclusions[e.ID] = e.Exclusions
+	}
+
+	return r, nil
+}
+
+// OpenReplayer reads the fixture at the given path and returns a Replayer for it.
+func OpenReplayer(path string) (*Replayer, error) {
+	f, err := ReadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(*f)
+}
+
+func (r *Replayer) GetVulnerabilityNamespaces() ([]string, error) {
+	return r.namespaces, nil
+}
+
+func (r *Replayer) GetVulnerability(namespace, id string) ([]grypeDB.Vulnerability, error) {
+	vulns, ok := r.lookups[searchKey{namespace: namespace, name: id}]
+	if !ok {
+		return nil, fmt.Errorf("vulnerability namespace=%q id=%q: %w", namespace, id, ErrNotRecorded)
+	}
+	return vulns, nil
+}
+
+func (r *Replayer) SearchForVulnerabilities(namespace, packageName string) ([]grypeDB.Vulnerability, error) {
+	vulns, ok := r.searches[searchKey{namespace: namespace, name: packageName}]
+	if !ok {
+		return nil, fmt.Errorf("search namespace=%q package=%q: %w", namespace, packageName, ErrNotRecorded)
+	}
+	return vulns, nil
+}
+
+func (r *Replayer) GetAllVulnerabilities() (*[]grypeDB.Vulnerability, error) {
+	return nil, fmt.Errorf("all vulnerabilities: %w", ErrNotRecorded)
+}
+
+func (r *Replayer) GetVulnerabilityMetadata(id, namespace string) (*grypeDB.VulnerabilityMetadata, error) {
+	metadata, ok := r.metadata[searchKey{namespace: namespace, name: id}]
+	if !ok {
+		return nil, fmt.Errorf("metadata id=%q namespace=%q: %w", id, namespace, ErrNotRecorded)
+	}
+	return metadata, nil
+}
+
+func (r *Replayer) GetAllVulnerabilityMetadata() (*[]grypeDB.VulnerabilityMetadata, error) {
+	return nil, fmt.Errorf("all vulnerability metadata: %w", ErrNotRecorded)
+}
+
+func (r *Replayer) GetVulnerabilityMatchExclusion(id string) ([]grypeDB.VulnerabilityMatchExclusion, error) {
+	exclusions, ok := r.exclusions[id]
+	if !ok {
+		return nil, fmt.Errorf("match exclusion id=%q: %w", id, ErrNotRecorded)
+	}
+	return exclusions, nil
+}
@@ -0,0 +1,26 @@
+package replay
+
+import (
+	"github.com/anchore/grype/grype/db"
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/store"
+)
+
+// NewStore builds a vulnerability store on top of the given reader (typically a Recorder or Replayer), wired the
+// same way as a store loaded from the on-disk DB.
+func NewStore(reader Reader) (*store.Store, error) {
+	p, err := db.NewVulnerabilityProvider(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &store.Store{
+		Provider:          p,
+		MetadataProvider:  db.NewVulnerabilityMetadataProvider(reader),
+		ExclusionProvider: db.NewMatchExclusionProvider(reader),
+	}
+	if targeting, ok := reader.(grypeDB.PackageTargetingStoreReader); ok {
+		s.Targeting = db.NewPackageTargetingProvider(targeting)
+	}
+	return s, nil
+}
@@ -202,6 +202,8 @@ func Test_NamespaceForDistro(t *testing.T) {
 	// v3 and older schemas don't include these newer distros:
 	allDistros.Remove(distro.Mariner.String())
 	allDistros.Remove(distro.Azure.String())
+	allDistros.Remove(distro.OpenWrt.String())
+	allDistros.Remove(distro.Yocto.String())
 
 	for _, test := range tests {
 		name := fmt.Sprintf("%s:%s", test.dist, test.version)
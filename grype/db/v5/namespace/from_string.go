@@ -17,7 +17,7 @@ func FromString(namespaceStr string) (Namespace, error) {
 
 	components := strings.Split(namespaceStr, ":")
 
-	if len(components) < 1 {
+	if len(components) < 2 {
 		return nil, fmt.Errorf("unable to create namespace from %s: incorrect number of components", namespaceStr)
 	}
 
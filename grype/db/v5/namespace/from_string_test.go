@@ -48,3 +48,10 @@ func TestFromString(t *testing.T) {
 		assert.Equal(t, result, test.result)
 	}
 }
+
+func TestFromString_invalid(t *testing.T) {
+	for _, s := range []string{"", "github", "github:unknown"} {
+		_, err := FromString(s)
+		assert.Error(t, err, s)
+	}
+}
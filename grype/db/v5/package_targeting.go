@@ -0,0 +1,20 @@
+package v5
+
+// PackageTargeting is the default strategy for matching the advisories of a distro: against the names of source
+// packages ("source"), binary packages ("binary"), or both ("both"). Entries without a distro version apply to all
+// versions of the distro.
+type PackageTargeting struct {
+	DistroType    string `json:"distro_type"`
+	DistroVersion string `json:"distro_version,omitempty"`
+	Targeting     string `json:"targeting"`
+}
+
+// PackageTargetingStoreReader is implemented by stores that hold the package targeting defaults. These are optional,
+// so DBs built without them remain readable.
+type PackageTargetingStoreReader interface {
+	GetPackageTargeting() ([]PackageTargeting, error)
+}
+
+type PackageTargetingStoreWriter interface {
+	AddPackageTargeting(targeting ...PackageTargeting) error
+}
@@ -0,0 +1,21 @@
+package arch
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/pkg/qualifier"
+	"github.com/anchore/grype/grype/pkg/qualifier/arch"
+)
+
+type Qualifier struct {
+	Kind          string   `json:"kind" mapstructure:"kind"`                                       // Kind of qualifier
+	Architectures []string `json:"architectures,omitempty" mapstructure:"architectures,omitempty"` // Architectures the vulnerability affects
+}
+
+func (q Qualifier) Parse() qualifier.Qualifier {
+	return arch.New(q.Architectures)
+}
+
+func (q Qualifier) String() string {
+	return fmt.Sprintf("kind: %s, architectures: %q", q.Kind, q.Architectures)
+}
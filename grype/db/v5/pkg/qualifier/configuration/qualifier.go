@@ -0,0 +1,21 @@
+package configuration
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/pkg/qualifier"
+	"github.com/anchore/grype/grype/pkg/qualifier/configuration"
+)
+
+type Qualifier struct {
+	Kind     string   `json:"kind" mapstructure:"kind"`                             // Kind of qualifier
+	Features []string `json:"features,omitempty" mapstructure:"features,omitempty"` // Features of which one must be enabled for the package to be affected
+}
+
+func (q Qualifier) Parse() qualifier.Qualifier {
+	return configuration.New(q.Features)
+}
+
+func (q Qualifier) String() string {
+	return fmt.Sprintf("kind: %s, features: %q", q.Kind, q.Features)
+}
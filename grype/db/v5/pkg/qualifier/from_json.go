@@ -5,6 +5,8 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 
+	"github.com/anchore/grype/grype/db/v5/pkg/qualifier/arch"
+	"github.com/anchore/grype/grype/db/v5/pkg/qualifier/configuration"
 	"github.com/anchore/grype/grype/db/v5/pkg/qualifier/platformcpe"
 	"github.com/anchore/grype/grype/db/v5/pkg/qualifier/rpmmodularity"
 	"github.com/anchore/grype/internal/log"
@@ -42,6 +44,20 @@ func FromJSON(data []byte) ([]Qualifier, error) {
 				continue
 			}
 			qualifiers = append(qualifiers, q)
+		case "arch":
+			var q arch.Qualifier
+			if err := mapstructure.Decode(r, &q); err != nil {
+				log.Warn("Error decoding arch package qualifier:  (%v)", err)
+				continue
+			}
+			qualifiers = append(qualifiers, q)
+		case "configuration":
+			var q configuration.Qualifier
+			if err := mapstructure.Decode(r, &q); err != nil {
+				log.Warn("Error decoding configuration package qualifier:  (%v)", err)
+				continue
+			}
+			qualifiers = append(qualifiers, q)
 		default:
 			log.Debug("Skipping unsupported package qualifier: %s", k)
 			continue
@@ -0,0 +1,222 @@
+package store
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/anchore/grype/grype/db/internal/gormadapter"
+	v5 "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store/model"
+)
+
+// MergeStats counts the records of an overlay DB merged into a DB.
+type MergeStats struct {
+	// Vulnerabilities is the number of vulnerability records of the overlay, and ReplacedVulnerabilities the number of
+	// records of the DB they replaced.
+	Vulnerabilities         int
+	ReplacedVulnerabilities int
+	// Metadata is the number of metadata records of the overlay, and OverriddenMetadata the number of them that
+	// overrode an existing metadata record.
+	Metadata           int
+	OverriddenMetadata int
+	Exclusions         int
+	Targeting          int
+}
+
+// Merge layers the records of the overlay DB file over the records of an existing DB file, in place:
+//   - the vulnerability records of a vulnerability ID and namespace of the overlay replace every record of the same ID
+//     and namespace of the DB (so an overlay may narrow or widen the affected versions of an advisory);
+//   - the fields of a metadata record of the overlay override the fields of the metadata record of the same ID and
+//     namespace of the DB, fields left empty by the overlay being kept (so an overlay may only override a severity);
+//   - the match exclusions of the overlay are added to those of the DB;
+//   - the package targeting defaults of a distro of the overlay replace those of the same distro of the DB.
+//
+// The DB is given the ID of the merged DB. The DB is left untouched when the merge fails.
+func Merge(dbFilePath, overlayFilePath string, id v5.ID) (*MergeStats, error) {
+	overlay, err := gormadapter.Open(overlayFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB(overlay)
+
+	db, err := gormadapter.Open(dbFilePath, gormadapter.WithWritable())
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB(db)
+
+	// the records are merged in a single transaction, so that a failing merge leaves the DB as it was
+	stats := &MergeStats{}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return merge(tx, overlay, id, stats)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result := db.Exec("VACUUM;"); result.Error != nil {
+		return nil, fmt.Errorf("unable to compact DB: %w", result.Error)
+	}
+	return stats, nil
+}
+
+func merge(db, overlay *gorm.DB, id v5.ID, stats *MergeStats) error {
+	if result := db.Where("1 = 1").Delete(&model.IDModel{}); result.Error != nil {
+		return fmt.Errorf("unable to replace DB ID: %w", result.Error)
+	}
+	idModel := model.NewIDModel(id)
+	if result := db.Create(&idModel); result.Error != nil {
+		return fmt.Errorf("unable to replace DB ID: %w", result.Error)
+	}
+
+	if err := mergeVulnerabilities(db, overlay, stats); err != nil {
+		return fmt.Errorf("unable to merge vulnerabilities: %w", err)
+	}
+	if err := mergeMetadata(db, overlay, stats); err != nil {
+		return fmt.Errorf("unable to merge vulnerability metadata: %w", err)
+	}
+	if err := mergeExclusions(db, overlay, stats); err != nil {
+		return fmt.Errorf("unable to merge vulnerability match exclusions: %w", err)
+	}
+	if err := mergeTargeting(db, overlay, stats); err != nil {
+		return fmt.Errorf("unable to merge package targeting: %w", err)
+	}
+	return nil
+}
+
+func mergeVulnerabilities(db, overlay *gorm.DB, stats *MergeStats) error {
+	// the replaced records are removed before any record of the overlay is added, since the records of a
+	// vulnerability may span batches
+	var replaced []struct {
+		Namespace string
+		ID        string
+	}
+	if err := overlay.Model(&model.VulnerabilityModel{}).Distinct("namespace", "id").Find(&replaced).Error; err != nil {
+		return err
+	}
+	for _, r := range replaced {
+		result := db.Where("namespace = ? AND id = ?", r.Namespace, r.ID).Delete(&model.VulnerabilityModel{})
+		if result.Error != nil {
+			return result.Error
+		}
+		stats.ReplacedVulnerabilities += int(result.RowsAffected)
+	}
+
+	var vulns []model.VulnerabilityModel
+	return overlay.FindInBatches(&vulns, shardBatchSize, func(*gorm.DB, int) error {
+		stats.Vulnerabilities += len(vulns)
+		return createEach(db, vulns, func(v *model.VulnerabilityModel) { v.PK = 0 })
+	}).Error
+}
+
+func mergeMetadata(db, overlay *gorm.DB, stats *MergeStats) error {
+	// DBs built before the publication dates were introduced have no columns for them
+	if err := db.AutoMigrate(&model.VulnerabilityMetadataModel{}); err != nil {
+		return err
+	}
+	var metadata []model.VulnerabilityMetadataModel
+	return overlay.FindInBatches(&metadata, shardBatchSize, func(*gorm.DB, int) error {
+		for _, m := range metadata {
+			incoming, err := m.Inflate()
+			if err != nil {
+				return err
+			}
+
+			var existing []model.VulnerabilityMetadataModel
+			if err := db.Where("id = ? AND namespace = ?", m.ID, m.Namespace).Find(&existing).Error; err != nil {
+				return err
+			}
+			if len(existing) > 0 {
+				merged, err := existing[0].Inflate()
+				if err != nil {
+					return err
+				}
+				incoming = overrideMetadata(merged, incoming)
+				stats.OverriddenMetadata++
+			}
+
+			newModel := model.NewVulnerabilityMetadataModel(incoming)
+			if err := db.Save(&newModel).Error; err != nil {
+				return err
+			}
+			stats.Metadata++
+		}
+		return nil
+	}).Error
+}
+
+func overrideMetadata(base, overlay v5.VulnerabilityMetadata) v5.VulnerabilityMetadata {
+	if overlay.DataSource != "" {
+		base.DataSource = overlay.DataSource
+	}
+	if overlay.RecordSource != "" {
+		base.RecordSource = overlay.RecordSource
+	}
+	if overlay.Severity != "" {
+		base.Severity = overlay.Severity
+	}
+	if len(overlay.URLs) > 0 {
+		base.URLs = overlay.URLs
+	}
+	if overlay.Description != "" {
+		base.Description = overlay.Description
+	}
+	if len(overlay.Cvss) > 0 {
+		base.Cvss = overlay.Cvss
+	}
+	if overlay.Published != nil {
+		base.Published = overlay.Published
+	}
+	if overlay.Modified != nil {
+		base.Modified = overlay.Modified
+	}
+	return base
+}
+
+func mergeExclusions(db, overlay *gorm.DB, stats *MergeStats) error {
+	var exclusions []model.VulnerabilityMatchExclusionModel
+	return overlay.FindInBatches(&exclusions, shardBatchSize, func(*gorm.DB, int) error {
+		stats.Exclusions += len(exclusions)
+		return createEach(db, exclusions, func(e *model.VulnerabilityMatchExclusionModel) { e.PK = 0 })
+	}).Error
+}
+
+func mergeTargeting(db, overlay *gorm.DB, stats *MergeStats) error {
+	if !overlay.Migrator().HasTable(&model.PackageTargetingModel{}) {
+		return nil
+	}
+	var targeting []model.PackageTargetingModel
+	if err := overlay.Find(&targeting).Error; err != nil {
+		return err
+	}
+	if len(targeting) == 0 {
+		return nil
+	}
+	// DBs built before package targeting was introduced have no table for it
+	if err := db.AutoMigrate(&model.PackageTargetingModel{}); err != nil {
+		return err
+	}
+	for i := range targeting {
+		result := db.Where("distro_type = ? AND distro_version = ?", targeting[i].DistroType, targeting[i].DistroVersion).Delete(&model.PackageTargetingModel{})
+		if result.Error != nil {
+			return result.Error
+		}
+		targeting[i].PK = 0
+	}
+	stats.Targeting = len(targeting)
+	return db.Create(&targeting).Error
+}
+
+// createEach creates a copy of each record of a batch without its primary key, for the DB to assign its own key (the
+// keys of the batch itself are how the next batch is found). Records are created one at a time, since a batch insert
+// of records whose nullable fields are only set on some of the records is not supported by sqlite.
+func createEach[T any](db *gorm.DB, records []T, clearPK func(*T)) error {
+	for _, r := range records {
+		clearPK(&r)
+		if err := db.Create(&r).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/db/internal/gormadapter"
+	v5 "github.com/anchore/grype/grype/db/v5"
+)
+
+func TestMerge(t *testing.T) {
+	dir := t.TempDir()
+	built := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	dbFilePath := filepath.Join(dir, "vulnerability.db")
+	s, err := New(dbFilePath, true)
+	require.NoError(t, err)
+	require.NoError(t, s.SetID(v5.NewID(built)))
+	require.NoError(t, s.AddVulnerability(
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:12", VersionConstraint: "< 1.0", VersionFormat: "dpkg"},
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "libssl3", Namespace: "debian:distro:debian:12", VersionConstraint: "< 1.0", VersionFormat: "dpkg"},
+		v5.Vulnerability{ID: "GHSA-1", PackageName: "left-pad", Namespace: "github:language:javascript", VersionConstraint: "< 2.0", VersionFormat: "unknown"},
+	))
+	require.NoError(t, s.AddVulnerabilityMetadata(
+		v5.VulnerabilityMetadata{ID: "CVE-2023-1", Namespace: "debian:distro:debian:12", Severity: "High", Description: "a flaw in openssl", URLs: []string{"https://example.com/CVE-2023-1"}},
+		v5.VulnerabilityMetadata{ID: "GHSA-1", Namespace: "github:language:javascript", Severity: "Low"},
+	))
+	require.NoError(t, s.AddVulnerabilityMatchExclusion(v5.VulnerabilityMatchExclusion{ID: "CVE-2023-2", Justification: "upstream"}))
+	require.NoError(t, s.(v5.PackageTargetingStoreWriter).AddPackageTargeting(
+		v5.PackageTargeting{DistroType: "debian", DistroVersion: "12", Targeting: "source"},
+	))
+	s.Close()
+
+	overlayFilePath := filepath.Join(dir, "overlay.db")
+	o, err := New(overlayFilePath, true)
+	require.NoError(t, err)
+	require.NoError(t, o.SetID(v5.NewID(built)))
+	require.NoError(t, o.AddVulnerability(
+		// narrows the affected packages of the advisory
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:12", VersionConstraint: "< 0.9", VersionFormat: "dpkg"},
+		v5.Vulnerability{ID: "INTERNAL-1", PackageName: "internal-lib", Namespace: "osv:language:python", VersionConstraint: "< 1.4.2", VersionFormat: "python"},
+	))
+	require.NoError(t, o.AddVulnerabilityMetadata(
+		// only overrides the severity
+		v5.VulnerabilityMetadata{ID: "CVE-2023-1", Namespace: "debian:distro:debian:12", Severity: "Low"},
+		v5.VulnerabilityMetadata{ID: "INTERNAL-1", Namespace: "osv:language:python", Severity: "Critical"},
+	))
+	require.NoError(t, o.AddVulnerabilityMatchExclusion(v5.VulnerabilityMatchExclusion{ID: "GHSA-1", Justification: "false positive"}))
+	require.NoError(t, o.(v5.PackageTargetingStoreWriter).AddPackageTargeting(
+		v5.PackageTargeting{DistroType: "debian", DistroVersion: "12", Targeting: "binary"},
+	))
+	o.Close()
+
+	merged := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	stats, err := Merge(dbFilePath, overlayFilePath, v5.NewID(merged))
+	require.NoError(t, err)
+	assert.Equal(t, &MergeStats{
+		Vulnerabilities:         2,
+		ReplacedVulnerabilities: 2,
+		Metadata:                2,
+		OverriddenMetadata:      1,
+		Exclusions:              1,
+		Targeting:               1,
+	}, stats)
+
+	s, err = New(dbFilePath, false)
+	require.NoError(t, err)
+	defer s.Close()
+
+	id, err := s.GetID()
+	require.NoError(t, err)
+	assert.Equal(t, merged, id.BuildTimestamp.UTC())
+
+	debian, err := s.GetVulnerability("debian:distro:debian:12", "CVE-2023-1")
+	require.NoError(t, err)
+	require.Len(t, debian, 1)
+	assert.Equal(t, "openssl", debian[0].PackageName)
+	assert.Equal(t, "< 0.9", debian[0].VersionConstraint)
+
+	untouched, err := s.GetVulnerability("github:language:javascript", "GHSA-1")
+	require.NoError(t, err)
+	assert.Len(t, untouched, 1)
+
+	added, err := s.GetVulnerability("osv:language:python", "INTERNAL-1")
+	require.NoError(t, err)
+	assert.Len(t, added, 1)
+
+	metadata, err := s.GetVulnerabilityMetadata("CVE-2023-1", "debian:distro:debian:12")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "Low", metadata.Severity)
+	assert.Equal(t, "a flaw in openssl", metadata.Description)
+	assert.Equal(t, []string{"https://example.com/CVE-2023-1"}, metadata.URLs)
+
+	exclusions, err := s.GetVulnerabilityMatchExclusion("GHSA-1")
+	require.NoError(t, err)
+	assert.Len(t, exclusions, 1)
+	exclusions, err = s.GetVulnerabilityMatchExclusion("CVE-2023-2")
+	require.NoError(t, err)
+	assert.Len(t, exclusions, 1)
+
+	targeting, err := s.(v5.PackageTargetingStoreReader).GetPackageTargeting()
+	require.NoError(t, err)
+	require.Len(t, targeting, 1)
+	assert.Equal(t, "binary", targeting[0].Targeting)
+}
+
+func TestMerge_failure(t *testing.T) {
+	dir := t.TempDir()
+	built := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	dbFilePath := filepath.Join(dir, "vulnerability.db")
+	s, err := New(dbFilePath, true)
+	require.NoError(t, err)
+	require.NoError(t, s.SetID(v5.NewID(built)))
+	require.NoError(t, s.AddVulnerability(
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:12", VersionConstraint: "< 1.0", VersionFormat: "dpkg"},
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "libssl3", Namespace: "debian:distro:debian:12", VersionConstraint: "< 1.0", VersionFormat: "dpkg"},
+	))
+	s.Close()
+
+	overlayFilePath := filepath.Join(dir, "overlay.db")
+	o, err := New(overlayFilePath, true)
+	require.NoError(t, err)
+	require.NoError(t, o.SetID(v5.NewID(built)))
+	require.NoError(t, o.AddVulnerability(
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:12", VersionConstraint: "< 0.9", VersionFormat: "dpkg"},
+	))
+	require.NoError(t, o.AddVulnerabilityMetadata(
+		v5.VulnerabilityMetadata{ID: "CVE-2023-1", Namespace: "debian:distro:debian:12", Severity: "Low"},
+	))
+	o.Close()
+
+	// the metadata is merged after the vulnerabilities, so the merge fails once the vulnerabilities are replaced
+	overlay, err := gormadapter.Open(overlayFilePath, gormadapter.WithWritable())
+	require.NoError(t, err)
+	require.NoError(t, overlay.Exec("UPDATE vulnerability_metadata SET cvss = '{'").Error)
+	closeDB(overlay)
+
+	_, err = Merge(dbFilePath, overlayFilePath, v5.NewID(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)))
+	require.ErrorContains(t, err, "unable to merge vulnerability metadata")
+
+	s, err = New(dbFilePath, false)
+	require.NoError(t, err)
+	defer s.Close()
+
+	id, err := s.GetID()
+	require.NoError(t, err)
+	assert.Equal(t, built, id.BuildTimestamp.UTC(), "the ID of the DB is kept")
+
+	debian, err := s.GetVulnerability("debian:distro:debian:12", "CVE-2023-1")
+	require.NoError(t, err)
+	assert.Len(t, debian, 2, "the replaced vulnerabilities are kept")
+	for _, v := range debian {
+		assert.Equal(t, "< 1.0", v.VersionConstraint)
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	v5 "github.com/anchore/grype/grype/db/v5"
+)
+
+const (
+	PackageTargetingTableName = "package_targeting"
+)
+
+// PackageTargetingModel is a struct used to serialize db.PackageTargeting information into a sqlite3 DB.
+type PackageTargetingModel struct {
+	PK            uint64 `gorm:"primary_key;auto_increment;"`
+	DistroType    string `gorm:"column:distro_type"`
+	DistroVersion string `gorm:"column:distro_version"`
+	Targeting     string `gorm:"column:targeting"`
+}
+
+// NewPackageTargetingModel generates a new model from a db.PackageTargeting struct.
+func NewPackageTargetingModel(t v5.PackageTargeting) PackageTargetingModel {
+	return PackageTargetingModel{
+		DistroType:    t.DistroType,
+		DistroVersion: t.DistroVersion,
+		Targeting:     t.Targeting,
+	}
+}
+
+// TableName returns the table which all db.PackageTargeting model instances are stored into.
+func (PackageTargetingModel) TableName() string {
+	return PackageTargetingTableName
+}
+
+// Inflate generates a db.PackageTargeting object from the serialized model instance.
+func (m PackageTargetingModel) Inflate() v5.PackageTargeting {
+	return v5.PackageTargeting{
+		DistroType:    m.DistroType,
+		DistroVersion: m.DistroVersion,
+		Targeting:     m.Targeting,
+	}
+}
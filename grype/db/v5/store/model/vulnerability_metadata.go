@@ -3,6 +3,7 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	sqlite "github.com/anchore/grype/grype/db/internal/sqlite"
 	v5 "github.com/anchore/grype/grype/db/v5"
@@ -22,6 +23,9 @@ type VulnerabilityMetadataModel struct {
 	URLs         sqlite.NullString `gorm:"column:urls; default:null"`
 	Description  string            `gorm:"column:description"`
 	Cvss         sqlite.NullString `gorm:"column:cvss; default:null"`
+	// DBs built before the dates were introduced have no columns for them (see store.Merge)
+	Published *time.Time `gorm:"column:published; default:null"`
+	Modified  *time.Time `gorm:"column:modified; default:null"`
 }
 
 // NewVulnerabilityMetadataModel generates a new model from a db.VulnerabilityMetadata struct.
@@ -39,6 +43,8 @@ func NewVulnerabilityMetadataModel(metadata v5.VulnerabilityMetadata) Vulnerabil
 		URLs:         sqlite.ToNullString(metadata.URLs),
 		Description:  metadata.Description,
 		Cvss:         sqlite.ToNullString(metadata.Cvss),
+		Published:    metadata.Published,
+		Modified:     metadata.Modified,
 	}
 }
 
@@ -70,5 +76,7 @@ func (m *VulnerabilityMetadataModel) Inflate() (v5.VulnerabilityMetadata, error)
 		URLs:         links,
 		Description:  m.Description,
 		Cvss:         cvss,
+		Published:    m.Published,
+		Modified:     m.Modified,
 	}, nil
 }
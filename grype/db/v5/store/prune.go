@@ -0,0 +1,104 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/anchore/grype/grype/db/internal/gormadapter"
+	v5 "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store/model"
+)
+
+// Prune removes the vulnerability and metadata records of every namespace that is not kept from an existing DB
+// file, returning the namespaces that were removed. The metadata records that kept vulnerabilities refer to as related
+// vulnerabilities (e.g. the nvd:cpe metadata of a CVE with a distro record) are kept, as they give the severity and
+// CVSS scores of the kept records. Match exclusions are not namespaced and are always kept.
+func Prune(dbFilePath string, keep func(namespace string) bool) ([]string, error) {
+	db, err := gormadapter.Open(dbFilePath, gormadapter.WithWritable())
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB(db)
+
+	namespaces, err := distinctNamespaces(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, ns := range namespaces {
+		if !keep(ns) {
+			removed = append(removed, ns)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	if result := db.Where("namespace IN ?", removed).Delete(&model.VulnerabilityModel{}); result.Error != nil {
+		return nil, fmt.Errorf("unable to remove vulnerabilities: %w", result.Error)
+	}
+	if err := pruneMetadata(db, removed); err != nil {
+		return nil, err
+	}
+
+	// reclaim the space from the removed rows, which is the point of pruning
+	if result := db.Exec("VACUUM;"); result.Error != nil {
+		return nil, fmt.Errorf("unable to compact DB: %w", result.Error)
+	}
+
+	return removed, nil
+}
+
+// pruneMetadata removes the metadata records of the removed namespaces, except those related to the vulnerability
+// records left in the DB.
+func pruneMetadata(db *gorm.DB, removed []string) error {
+	related, err := relatedReferences(db)
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range removed {
+		var ids []string
+		if result := db.Model(&model.VulnerabilityMetadataModel{}).Where("namespace = ?", ns).Pluck("id", &ids); result.Error != nil {
+			return fmt.Errorf("unable to read vulnerability metadata: %w", result.Error)
+		}
+		var unrelated []string
+		for _, id := range ids {
+			if _, ok := related[v5.VulnerabilityReference{ID: id, Namespace: ns}]; !ok {
+				unrelated = append(unrelated, id)
+			}
+		}
+		for start := 0; start < len(unrelated); start += shardBatchSize {
+			batch := unrelated[start:min(start+shardBatchSize, len(unrelated))]
+			if result := db.Where("namespace = ? AND id IN ?", ns, batch).Delete(&model.VulnerabilityMetadataModel{}); result.Error != nil {
+				return fmt.Errorf("unable to remove vulnerability metadata: %w", result.Error)
+			}
+		}
+	}
+	return nil
+}
+
+// relatedReferences returns the related vulnerabilities of the vulnerability records of the DB.
+func relatedReferences(db *gorm.DB) (map[v5.VulnerabilityReference]struct{}, error) {
+	related := make(map[v5.VulnerabilityReference]struct{})
+	var vulns []model.VulnerabilityModel
+	err := db.Select("pk", "related_vulnerabilities").Where("related_vulnerabilities IS NOT NULL").FindInBatches(&vulns, shardBatchSize, func(*gorm.DB, int) error {
+		for _, v := range vulns {
+			var refs []v5.VulnerabilityReference
+			if err := json.Unmarshal(v.RelatedVulnerabilities.ToByteSlice(), &refs); err != nil {
+				return fmt.Errorf("unable to unmarshal related vulnerabilities (%+v): %w", v.RelatedVulnerabilities, err)
+			}
+			for _, ref := range refs {
+				related[ref] = struct{}{}
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("unable to read related vulnerabilities: %w", err)
+	}
+	return related, nil
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v5 "github.com/anchore/grype/grype/db/v5"
+)
+
+func TestPrune(t *testing.T) {
+	dbFilePath := filepath.Join(t.TempDir(), "vulnerability.db")
+	s, err := New(dbFilePath, true)
+	require.NoError(t, err)
+
+	require.NoError(t, s.AddVulnerability(
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:12", VersionConstraint: "< 1.0", VersionFormat: "dpkg",
+			RelatedVulnerabilities: []v5.VulnerabilityReference{{ID: "CVE-2023-1", Namespace: "nvd:cpe"}}},
+		v5.Vulnerability{ID: "CVE-2023-2", PackageName: "zlib", Namespace: "nvd:cpe", VersionConstraint: "< 1.0", VersionFormat: "unknown"},
+		v5.Vulnerability{ID: "GHSA-1", PackageName: "left-pad", Namespace: "github:language:javascript", VersionConstraint: "< 2.0", VersionFormat: "unknown"},
+		v5.Vulnerability{ID: "GHSA-2", PackageName: "requests", Namespace: "github:language:python", VersionConstraint: "< 3.0", VersionFormat: "python"},
+	))
+	require.NoError(t, s.AddVulnerabilityMetadata(
+		v5.VulnerabilityMetadata{ID: "CVE-2023-1", Namespace: "debian:distro:debian:12", Severity: "High"},
+		v5.VulnerabilityMetadata{ID: "GHSA-2", Namespace: "github:language:python", Severity: "Low"},
+		v5.VulnerabilityMetadata{ID: "CVE-2023-1", Namespace: "nvd:cpe", Severity: "High"},
+		v5.VulnerabilityMetadata{ID: "CVE-2023-2", Namespace: "nvd:cpe", Severity: "Medium"},
+	))
+	s.Close()
+
+	removed, err := Prune(dbFilePath, func(namespace string) bool {
+		return strings.Contains(namespace, ":distro:") || namespace == "github:language:javascript"
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"github:language:python", "nvd:cpe"}, removed)
+
+	s, err = New(dbFilePath, false)
+	require.NoError(t, err)
+	defer s.Close()
+
+	vulns, err := s.GetAllVulnerabilities()
+	require.NoError(t, err)
+	var ids []string
+	for _, v := range *vulns {
+		ids = append(ids, v.ID)
+	}
+	assert.ElementsMatch(t, []string{"CVE-2023-1", "GHSA-1"}, ids)
+
+	// the nvd:cpe metadata related to the kept debian record is kept, the metadata of the removed record is not
+	metadata, err := s.GetAllVulnerabilityMetadata()
+	require.NoError(t, err)
+	var kept []string
+	for _, m := range *metadata {
+		kept = append(kept, m.ID+"@"+m.Namespace)
+	}
+	assert.ElementsMatch(t, []string{"CVE-2023-1@debian:distro:debian:12", "CVE-2023-1@nvd:cpe"}, kept)
+
+	// nothing left to remove
+	removed, err = Prune(dbFilePath, func(string) bool { return true })
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+}
@@ -0,0 +1,435 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/anchore/grype/grype/db/internal/gormadapter"
+	v5 "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store/model"
+	"github.com/anchore/grype/internal/log"
+)
+
+const (
+	// ShardDirName is the directory (relative to the DB directory) that holds the DB shards.
+	ShardDirName = "shards"
+	// ShardIndexFileName is the name of the file within the shard directory that describes the shards.
+	ShardIndexFileName = "index.json"
+
+	// baseShard holds the records that are not namespaced (match exclusions and package targeting), which are needed
+	// for every scan.
+	baseShard = "base"
+
+	shardBatchSize = 1000
+)
+
+// ShardIndex describes how a DB was split into shards, so that a shard only needs to be opened when one of its
+// namespaces is searched.
+type ShardIndex struct {
+	// Source identifies the DB the shards were created from (typically its checksum), so stale shards can be detected.
+	Source string       `json:"source"`
+	ID     v5.ID        `json:"id"`
+	Shards []ShardEntry `json:"shards"`
+}
+
+// ShardEntry is a single DB file holding all records for a set of namespaces.
+type ShardEntry struct {
+	Name       string   `json:"name"`
+	File       string   `json:"file"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// ShardName returns the shard that the records for the given namespace are placed in: one shard per provider for
+// OS distributions and CPEs, and one shard per provider and language for language ecosystems
+// (e.g. "github:language:java" is in the "github-language-java" shard).
+func ShardName(namespace string) string {
+	components := strings.Split(namespace, ":")
+	if len(components) > 2 && components[1] == "language" {
+		components = components[:3]
+	} else if len(components) > 2 {
+		components = components[:2]
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, strings.Join(components, "-"))
+}
+
+// ReadShardIndex reads the shard index from the given shard directory.
+func ReadShardIndex(shardDir string) (*ShardIndex, error) {
+	contents, err := os.ReadFile(filepath.Join(shardDir, ShardIndexFileName))
+	if err != nil {
+		return nil, err
+	}
+	var index ShardIndex
+	if err := json.Unmarshal(contents, &index); err != nil {
+		return nil, fmt.Errorf("unable to parse shard index: %w", err)
+	}
+	return &index, nil
+}
+
+// Shard splits an existing DB file into one DB file per shard (see ShardName) within the given directory, replacing
+// any shards already there. The source DB is left as-is.
+func Shard(dbFilePath, shardDir, source string) (*ShardIndex, error) {
+	src, err := gormadapter.Open(dbFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB(src)
+
+	var ids []model.IDModel
+	if result := src.Find(&ids); result.Error != nil {
+		return nil, fmt.Errorf("unable to read DB ID: %w", result.Error)
+	}
+	if len(ids) != 1 {
+		return nil, fmt.Errorf("unable to shard DB with %d IDs", len(ids))
+	}
+	id, err := ids[0].Inflate()
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, err := distinctNamespaces(src)
+	if err != nil {
+		return nil, err
+	}
+
+	byShard := make(map[string][]string)
+	for _, ns := range namespaces {
+		name := ShardName(ns)
+		byShard[name] = append(byShard[name], ns)
+	}
+
+	if err := os.RemoveAll(shardDir); err != nil {
+		return nil, fmt.Errorf("unable to remove existing shards: %w", err)
+	}
+	if err := os.MkdirAll(shardDir, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create shard directory: %w", err)
+	}
+
+	index := &ShardIndex{Source: source, ID: id}
+
+	base := ShardEntry{Name: baseShard, File: baseShard + ".db"}
+	err = writeShard(filepath.Join(shardDir, base.File), id, func(dst *gorm.DB) error {
+		var exclusions []model.VulnerabilityMatchExclusionModel
+		err := src.FindInBatches(&exclusions, shardBatchSize, func(*gorm.DB, int) error {
+			return dst.Create(&exclusions).Error
+		}).Error
+		if err != nil || !src.Migrator().HasTable(&model.PackageTargetingModel{}) {
+			return err
+		}
+		var targeting []model.PackageTargetingModel
+		return src.FindInBatches(&targeting, shardBatchSize, func(*gorm.DB, int) error {
+			return dst.Create(&targeting).Error
+		}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to write %q shard: %w", base.Name, err)
+	}
+	index.Shards = append(index.Shards, base)
+
+	var names []string
+	for name := range byShard {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		entry := ShardEntry{Name: name, File: name + ".db", Namespaces: byShard[name]}
+		err := writeShard(filepath.Join(shardDir, entry.File), id, func(dst *gorm.DB) error {
+			var vulns []model.VulnerabilityModel
+			err := src.Where("namespace IN ?", entry.Namespaces).FindInBatches(&vulns, shardBatchSize, func(*gorm.DB, int) error {
+				return dst.Create(&vulns).Error
+			}).Error
+			if err != nil {
+				return err
+			}
+			var metadata []model.VulnerabilityMetadataModel
+			return src.Where("namespace IN ?", entry.Namespaces).FindInBatches(&metadata, shardBatchSize, func(*gorm.DB, int) error {
+				return dst.Create(&metadata).Error
+			}).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to write %q shard: %w", name, err)
+		}
+		index.Shards = append(index.Shards, entry)
+	}
+
+	contents, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	// the index is written last so that an interrupted run never leaves a usable (but incomplete) set of shards
+	if err := os.WriteFile(filepath.Join(shardDir, ShardIndexFileName), contents, 0644); err != nil { //nolint:gosec
+		return nil, fmt.Errorf("unable to write shard index: %w", err)
+	}
+
+	return index, nil
+}
+
+func writeShard(path string, id v5.ID, copyRecords func(dst *gorm.DB) error) error {
+	s, err := New(path, true)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if err := s.SetID(id); err != nil {
+		return err
+	}
+	db := s.(*store).db
+	if err := copyRecords(db); err != nil {
+		return err
+	}
+	// the shards are derived from the DB (and not verified by a published checksum), so they can hold the statistics
+	// of the query planner
+	return analyze(db)
+}
+
+func distinctNamespaces(db *gorm.DB) ([]string, error) {
+	var namespaces []string
+	if result := db.Model(&model.VulnerabilityModel{}).Distinct().Pluck("namespace", &namespaces); result.Error != nil {
+		return nil, fmt.Errorf("unable to read namespaces: %w", result.Error)
+	}
+	var metadataNamespaces []string
+	if result := db.Model(&model.VulnerabilityMetadataModel{}).Distinct().Pluck("namespace", &metadataNamespaces); result.Error != nil {
+		return nil, fmt.Errorf("unable to read metadata namespaces: %w", result.Error)
+	}
+
+	seen := make(map[string]struct{})
+	var all []string
+	for _, ns := range append(namespaces, metadataNamespaces...) {
+		if _, ok := seen[ns]; ok {
+			continue
+		}
+		seen[ns] = struct{}{}
+		all = append(all, ns)
+	}
+	sort.Strings(all)
+	return all, nil
+}
+
+func closeDB(db *gorm.DB) {
+	if sqlDB, _ := db.DB(); sqlDB != nil {
+		_ = sqlDB.Close()
+	}
+}
+
+var _ v5.StoreReader = (*ShardedStore)(nil)
+
+// ShardedStore reads from a set of DB shards (see Shard), only opening a shard the first time one of its namespaces
+// is searched. Scanning a single ecosystem therefore never touches the shards for the other ecosystems.
+type ShardedStore struct {
+	dir       string
+	index     ShardIndex
+	byName    map[string]ShardEntry
+	namespace map[string]string
+	lock      sync.Mutex
+	open      map[string]*store
+}
+
+// NewSharded opens the shards described by the index within the given directory. No shard is opened until it is needed.
+func NewSharded(shardDir string) (*ShardedStore, error) {
+	index, err := ReadShardIndex(shardDir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ShardedStore{
+		dir:       shardDir,
+		index:     *index,
+		byName:    make(map[string]ShardEntry),
+		namespace: make(map[string]string),
+		open:      make(map[string]*store),
+	}
+	for _, entry := range index.Shards {
+		s.byName[entry.Name] = entry
+		for _, ns := range entry.Namespaces {
+			s.namespace[ns] = entry.Name
+		}
+	}
+	if _, ok := s.byName[baseShard]; !ok {
+		return nil, fmt.Errorf("shard index has no %q shard", baseShard)
+	}
+	return s, nil
+}
+
+// shard returns the (opened) shard with the given name.
+func (s *ShardedStore) shard(name string) (*store, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if opened, ok := s.open[name]; ok {
+		return opened, nil
+	}
+	entry, ok := s.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown DB shard %q", name)
+	}
+
+	db, err := gormadapter.Open(filepath.Join(s.dir, entry.File))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open DB shard %q: %w", name, err)
+	}
+	log.WithFields("shard", name, "namespaces", len(entry.Namespaces)).Trace("opened DB shard")
+
+	opened := &store{db: db}
+	s.open[name] = opened
+	return opened, nil
+}
+
+// namespaceShard returns the shard holding the given namespace, or nil if no shard holds it.
+func (s *ShardedStore) namespaceShard(namespace string) (*store, error) {
+	name, ok := s.namespace[namespace]
+	if !ok {
+		return nil, nil
+	}
+	return s.shard(name)
+}
+
+// GetID returns the ID of the DB the shards were created from, without opening any shard.
+func (s *ShardedStore) GetID() (*v5.ID, error) {
+	id := s.index.ID
+	return &id, nil
+}
+
+// GetVulnerabilityNamespaces returns the namespaces from the shard index, without opening any shard.
+func (s *ShardedStore) GetVulnerabilityNamespaces() ([]string, error) {
+	var namespaces []string
+	for ns := range s.namespace {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+func (s *ShardedStore) GetVulnerability(namespace, id string) ([]v5.Vulnerability, error) {
+	shard, err := s.namespaceShard(namespace)
+	if err != nil || shard == nil {
+		return nil, err
+	}
+	return shard.GetVulnerability(namespace, id)
+}
+
+func (s *ShardedStore) SearchForVulnerabilities(namespace, packageName string) ([]v5.Vulnerability, error) {
+	shard, err := s.namespaceShard(namespace)
+	if err != nil || shard == nil {
+		return nil, err
+	}
+	return shard.SearchForVulnerabilities(namespace, packageName)
+}
+
+func (s *ShardedStore) GetVulnerabilityMetadata(id, namespace string) (*v5.VulnerabilityMetadata, error) {
+	shard, err := s.namespaceShard(namespace)
+	if err != nil || shard == nil {
+		return nil, err
+	}
+	return shard.GetVulnerabilityMetadata(id, namespace)
+}
+
+func (s *ShardedStore) GetVulnerabilityMatchExclusion(id string) ([]v5.VulnerabilityMatchExclusion, error) {
+	shard, err := s.shard(baseShard)
+	if err != nil {
+		return nil, err
+	}
+	return shard.GetVulnerabilityMatchExclusion(id)
+}
+
+func (s *ShardedStore) GetPackageTargeting() ([]v5.PackageTargeting, error) {
+	shard, err := s.shard(baseShard)
+	if err != nil {
+		return nil, err
+	}
+	return shard.GetPackageTargeting()
+}
+
+// GetAllVulnerabilities reads the vulnerabilities from every shard.
+func (s *ShardedStore) GetAllVulnerabilities() (*[]v5.Vulnerability, error) {
+	var all []v5.Vulnerability
+	err := s.eachShard(func(shard *store) error {
+		vulns, err := shard.GetAllVulnerabilities()
+		if err != nil {
+			return err
+		}
+		all = append(all, *vulns...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &all, nil
+}
+
+// GetAllVulnerabilityMetadata reads the vulnerability metadata from every shard.
+func (s *ShardedStore) GetAllVulnerabilityMetadata() (*[]v5.VulnerabilityMetadata, error) {
+	var all []v5.VulnerabilityMetadata
+	err := s.eachShard(func(shard *store) error {
+		metadata, err := shard.GetAllVulnerabilityMetadata()
+		if err != nil {
+			return err
+		}
+		all = append(all, *metadata...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &all, nil
+}
+
+// DiffStore creates a diff between all shards and the given store.
+func (s *ShardedStore) DiffStore(targetStore v5.StoreReader) (*[]v5.Diff, error) {
+	return diffStores(s, targetStore)
+}
+
+func (s *ShardedStore) eachShard(fn func(shard *store) error) error {
+	for _, entry := range s.index.Shards {
+		if entry.Name == baseShard {
+			continue
+		}
+		shard, err := s.shard(entry.Name)
+		if err != nil {
+			return err
+		}
+		if err := fn(shard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Opened returns the names of the shards that have been opened so far.
+func (s *ShardedStore) Opened() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var names []string
+	for name := range s.open {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close closes every opened shard.
+func (s *ShardedStore) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for name, shard := range s.open {
+		closeDB(shard.db)
+		delete(s.open, name)
+	}
+}
@@ -0,0 +1,163 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/db/internal/gormadapter"
+	v5 "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store/model"
+)
+
+func TestShardName(t *testing.T) {
+	tests := map[string]string{
+		"debian:distro:debian:12":    "debian-distro",
+		"redhat:distro:redhat:9":     "redhat-distro",
+		"nvd:cpe":                    "nvd-cpe",
+		"github:language:java":       "github-language-java",
+		"github:language:javascript": "github-language-javascript",
+		"Odd/Provider:cpe":           "odd-provider-cpe",
+	}
+	for namespace, want := range tests {
+		t.Run(namespace, func(t *testing.T) {
+			assert.Equal(t, want, ShardName(namespace))
+		})
+	}
+}
+
+func TestShard_lazyReads(t *testing.T) {
+	dir := t.TempDir()
+	dbFilePath := filepath.Join(dir, "vulnerability.db")
+	s, err := New(dbFilePath, true)
+	require.NoError(t, err)
+
+	id := v5.NewID(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, s.SetID(id))
+	require.NoError(t, s.AddVulnerability(
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:12", VersionConstraint: "< 1.0", VersionFormat: "dpkg"},
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:11", VersionConstraint: "< 1.1", VersionFormat: "dpkg"},
+		v5.Vulnerability{ID: "GHSA-1", PackageName: "left-pad", Namespace: "github:language:javascript", VersionConstraint: "< 2.0", VersionFormat: "unknown"},
+		v5.Vulnerability{ID: "GHSA-2", PackageName: "requests", Namespace: "github:language:python", VersionConstraint: "< 3.0", VersionFormat: "python"},
+	))
+	require.NoError(t, s.AddVulnerabilityMetadata(
+		v5.VulnerabilityMetadata{ID: "GHSA-2", Namespace: "github:language:python", Severity: "Low"},
+		v5.VulnerabilityMetadata{ID: "CVE-2023-1", Namespace: "nvd:cpe", Severity: "High"},
+	))
+	require.NoError(t, s.AddVulnerabilityMatchExclusion(v5.VulnerabilityMatchExclusion{
+		ID:            "CVE-2023-1",
+		Constraints:   []v5.VulnerabilityMatchExclusionConstraint{{Vulnerability: v5.VulnerabilityExclusionConstraint{Namespace: "nvd:cpe"}}},
+		Justification: "not applicable",
+	}))
+	s.Close()
+
+	shardDir := filepath.Join(dir, ShardDirName)
+	index, err := Shard(dbFilePath, shardDir, "sha256:abc")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:abc", index.Source)
+
+	var names []string
+	for _, e := range index.Shards {
+		names = append(names, e.Name)
+	}
+	assert.Equal(t, []string{"base", "debian-distro", "github-language-javascript", "github-language-python", "nvd-cpe"}, names)
+
+	sharded, err := NewSharded(shardDir)
+	require.NoError(t, err)
+	defer sharded.Close()
+
+	namespaces, err := sharded.GetVulnerabilityNamespaces()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"debian:distro:debian:11", "debian:distro:debian:12", "github:language:javascript", "github:language:python", "nvd:cpe"}, namespaces)
+
+	gotID, err := sharded.GetID()
+	require.NoError(t, err)
+	assert.True(t, id.BuildTimestamp.Equal(gotID.BuildTimestamp))
+	assert.Empty(t, sharded.Opened(), "no shard should be opened for index reads")
+
+	vulns, err := sharded.SearchForVulnerabilities("github:language:javascript", "left-pad")
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	assert.Equal(t, "GHSA-1", vulns[0].ID)
+	assert.Equal(t, []string{"github-language-javascript"}, sharded.Opened())
+
+	vulns, err = sharded.SearchForVulnerabilities("debian:distro:debian:12", "openssl")
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	assert.Equal(t, "< 1.0", vulns[0].VersionConstraint)
+
+	vulns, err = sharded.SearchForVulnerabilities("alpine:distro:alpine:3.18", "openssl")
+	require.NoError(t, err)
+	assert.Empty(t, vulns)
+
+	metadata, err := sharded.GetVulnerabilityMetadata("GHSA-2", "github:language:python")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "Low", metadata.Severity)
+
+	exclusions, err := sharded.GetVulnerabilityMatchExclusion("CVE-2023-1")
+	require.NoError(t, err)
+	require.Len(t, exclusions, 1)
+	assert.Equal(t, []string{"base", "debian-distro", "github-language-javascript", "github-language-python"}, sharded.Opened())
+
+	all, err := sharded.GetAllVulnerabilities()
+	require.NoError(t, err)
+	assert.Len(t, *all, 4)
+
+	allMetadata, err := sharded.GetAllVulnerabilityMetadata()
+	require.NoError(t, err)
+	assert.Len(t, *allMetadata, 2)
+}
+
+func TestShard_analyzed(t *testing.T) {
+	dir := t.TempDir()
+	dbFilePath := filepath.Join(dir, "vulnerability.db")
+	s, err := New(dbFilePath, true)
+	require.NoError(t, err)
+	require.NoError(t, s.SetID(v5.NewID(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))))
+	require.NoError(t, s.AddVulnerability(
+		v5.Vulnerability{ID: "CVE-2023-1", PackageName: "openssl", Namespace: "debian:distro:debian:12", VersionConstraint: "< 1.0", VersionFormat: "dpkg"},
+	))
+	s.Close()
+
+	// the published DB is only read
+	missing, err := Warm(dbFilePath)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	shardDir := filepath.Join(dir, ShardDirName)
+	_, err = Shard(dbFilePath, shardDir, "sha256:abc")
+	require.NoError(t, err)
+
+	shard, err := gormadapter.Open(filepath.Join(shardDir, "debian-distro.db"))
+	require.NoError(t, err)
+	defer closeDB(shard)
+	var stats int64
+	require.NoError(t, shard.Raw("SELECT count(*) FROM sqlite_stat1").Scan(&stats).Error)
+	assert.NotZero(t, stats)
+
+	source, err := gormadapter.Open(dbFilePath)
+	require.NoError(t, err)
+	defer closeDB(source)
+	assert.False(t, source.Migrator().HasTable("sqlite_stat1"))
+}
+
+func TestWarm_missingIndex(t *testing.T) {
+	dbFilePath := filepath.Join(t.TempDir(), "vulnerability.db")
+	s, err := New(dbFilePath, true)
+	require.NoError(t, err)
+	require.NoError(t, s.SetID(v5.NewID(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))))
+	s.Close()
+
+	db, err := gormadapter.Open(dbFilePath, gormadapter.WithWritable())
+	require.NoError(t, err)
+	require.NoError(t, db.Exec("DROP INDEX "+model.GetVulnerabilityIndexName).Error)
+	closeDB(db)
+
+	missing, err := Warm(dbFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.GetVulnerabilityIndexName}, missing)
+}
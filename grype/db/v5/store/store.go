@@ -42,6 +42,9 @@ func New(dbFilePath string, overwrite bool) (v5.Store, error) {
 		if err := db.AutoMigrate(&model.VulnerabilityMatchExclusionModel{}); err != nil {
 			return nil, fmt.Errorf("unable to migrate Vulnerability Match Exclusion model: %w", err)
 		}
+		if err := db.AutoMigrate(&model.PackageTargetingModel{}); err != nil {
+			return nil, fmt.Errorf("unable to migrate Package Targeting model: %w", err)
+		}
 	}
 
 	return &store{
@@ -286,6 +289,41 @@ func (s *store) AddVulnerabilityMatchExclusion(exclusions ...v5.VulnerabilityMat
 	return nil
 }
 
+// GetPackageTargeting retrieves the package targeting defaults of all distros. DBs built before these defaults were
+// introduced have none.
+func (s *store) GetPackageTargeting() ([]v5.PackageTargeting, error) {
+	if !s.db.Migrator().HasTable(&model.PackageTargetingModel{}) {
+		return nil, nil
+	}
+
+	var models []model.PackageTargetingModel
+	result := s.db.Find(&models)
+
+	var targeting []v5.PackageTargeting
+	for _, m := range models {
+		targeting = append(targeting, m.Inflate())
+	}
+	return targeting, result.Error
+}
+
+// AddPackageTargeting saves one or more package targeting defaults into the sqlite3 store.
+func (s *store) AddPackageTargeting(targeting ...v5.PackageTargeting) error {
+	for _, t := range targeting {
+		m := model.NewPackageTargetingModel(t)
+
+		result := s.db.Create(&m)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected != 1 {
+			return fmt.Errorf("unable to add package targeting (%d rows affected)", result.RowsAffected)
+		}
+	}
+
+	return nil
+}
+
 func (s *store) Close() {
 	s.db.Exec("VACUUM;")
 
@@ -331,6 +369,10 @@ func (s *store) GetAllVulnerabilityMetadata() (*[]v5.VulnerabilityMetadata, erro
 
 // DiffStore creates a diff between the current sql database and the given store
 func (s *store) DiffStore(targetStore v5.StoreReader) (*[]v5.Diff, error) {
+	return diffStores(s, targetStore)
+}
+
+func diffStores(s, targetStore v5.StoreReader) (*[]v5.Diff, error) {
 	// 7 stages, one for each step of the diff process (stages)
 	rowsProgress, diffItems, stager := trackDiff(7)
 
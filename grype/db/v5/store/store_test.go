@@ -1210,6 +1210,94 @@ func TestStore_GetVulnerabilityMatchExclusion_SetVulnerabilityMatchExclusion(t *
 	assertVulnerabilityMatchExclusionReader(t, s, expected[0].ID, expected)
 }
 
+func TestStore_GetPackageTargeting_AddPackageTargeting(t *testing.T) {
+	dbTempFile := t.TempDir()
+	s, err := New(dbTempFile, true)
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+
+	expected := []v5.PackageTargeting{
+		{
+			DistroType: "debian",
+			Targeting:  "source",
+		},
+		{
+			DistroType:    "redhat",
+			DistroVersion: "8",
+			Targeting:     "binary",
+		},
+	}
+
+	if err = s.(*store).AddPackageTargeting(expected...); err != nil {
+		t.Fatalf("failed to add package targeting: %+v", err)
+	}
+
+	actual, err := s.(*store).GetPackageTargeting()
+	if err != nil {
+		t.Fatalf("failed to get package targeting: %+v", err)
+	}
+	assert.ElementsMatch(t, expected, actual)
+}
+
+func TestStore_GetPackageTargeting_missingTable(t *testing.T) {
+	dbTempFile := t.TempDir()
+	s, err := New(dbTempFile, true)
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+	// DBs built before package targeting was introduced do not have the table
+	if err = s.(*store).db.Migrator().DropTable(&model.PackageTargetingModel{}); err != nil {
+		t.Fatalf("failed to drop table: %+v", err)
+	}
+
+	actual, err := s.(*store).GetPackageTargeting()
+	assert.NoError(t, err)
+	assert.Empty(t, actual)
+}
+
+func TestStore_VulnerabilityMetadata_dates(t *testing.T) {
+	dbTempFile := t.TempDir()
+	s, err := New(dbTempFile, true)
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+
+	published := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	modified := time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC)
+	expected := v5.VulnerabilityMetadata{
+		ID:        "CVE-2024-0001",
+		Namespace: "nvd:cpe",
+		Severity:  "High",
+		URLs:      []string{},
+		Cvss:      []v5.Cvss{},
+		Published: &published,
+		Modified:  &modified,
+	}
+	if err = s.AddVulnerabilityMetadata(expected); err != nil {
+		t.Fatalf("failed to add metadata: %+v", err)
+	}
+	actual, err := s.GetVulnerabilityMetadata(expected.ID, expected.Namespace)
+	if err != nil {
+		t.Fatalf("failed to get metadata: %+v", err)
+	}
+	assert.True(t, expected.Equal(*actual), "expected %+v, got %+v", expected, *actual)
+
+	// DBs built before the dates were introduced do not have their columns
+	db := s.(*store).db
+	for _, column := range []string{"published", "modified"} {
+		if err = db.Migrator().DropColumn(&model.VulnerabilityMetadataModel{}, column); err != nil {
+			t.Fatalf("failed to drop column: %+v", err)
+		}
+	}
+	actual, err = s.GetVulnerabilityMetadata(expected.ID, expected.Namespace)
+	if err != nil {
+		t.Fatalf("failed to get metadata: %+v", err)
+	}
+	assert.Nil(t, actual.Published)
+	assert.Nil(t, actual.Modified)
+}
+
 func Test_DiffStore(t *testing.T) {
 	//GIVEN
 	dbTempFile := t.TempDir()
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/anchore/grype/grype/db/internal/gormadapter"
+	"github.com/anchore/grype/grype/db/v5/store/model"
+	"github.com/anchore/grype/internal/log"
+)
+
+// searchIndexes are the indexes the searches of a DB use, by the model of their table.
+var searchIndexes = []struct {
+	model interface{}
+	table string
+	name  string
+}{
+	{model: &model.VulnerabilityModel{}, table: model.VulnerabilityTableName, name: model.GetVulnerabilityIndexName},
+	{model: &model.VulnerabilityMatchExclusionModel{}, table: model.VulnerabilityMatchExclusionTableName, name: model.GetVulnerabilityMatchExclusionIndexName},
+}
+
+// Warm checks that the DB file has the indexes its searches use, and reads each of them through once so that their
+// pages are in the OS page cache for the next reader. The DB is only read, since a published DB is verified by its
+// checksum; query planner statistics (ANALYZE) are only kept within the shards (see Shard). The indexes missing from
+// the DB are returned, searches of their table scanning the whole table instead.
+func Warm(dbFilePath string) ([]string, error) {
+	db, err := gormadapter.Open(dbFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB(db)
+
+	var missing []string
+	for _, index := range searchIndexes {
+		if !db.Migrator().HasTable(index.model) {
+			continue
+		}
+		if !db.Migrator().HasIndex(index.model, index.name) {
+			missing = append(missing, index.name)
+			continue
+		}
+		if err := readIndex(db, index.table, index.name); err != nil {
+			return nil, err
+		}
+	}
+	if len(missing) > 0 {
+		log.WithFields("path", dbFilePath, "indexes", missing).Warn("vulnerability database is missing search indexes")
+	}
+	return missing, nil
+}
+
+func readIndex(db *gorm.DB, table, index string) error {
+	var count int64
+	if err := db.Raw(fmt.Sprintf("SELECT count(*) FROM %q INDEXED BY %q", table, index)).Scan(&count).Error; err != nil {
+		return fmt.Errorf("unable to read index %q: %w", index, err)
+	}
+	return nil
+}
+
+// analyze records the statistics the query planner uses to choose the indexes of the searches within the DB.
+func analyze(db *gorm.DB) error {
+	if err := db.Exec("ANALYZE").Error; err != nil {
+		return fmt.Errorf("unable to analyze DB: %w", err)
+	}
+	return nil
+}
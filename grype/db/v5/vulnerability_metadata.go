@@ -1,6 +1,9 @@
 package v5
 
-import "reflect"
+import (
+	"reflect"
+	"time"
+)
 
 // VulnerabilityMetadata represents all vulnerability data that is not necessary to perform package-to-vulnerability matching.
 type VulnerabilityMetadata struct {
@@ -12,6 +15,9 @@ type VulnerabilityMetadata struct {
 	URLs         []string `json:"urls"`          // URLs to get more information about the vulnerability or advisory
 	Description  string   `json:"description"`   // Description of the vulnerability
 	Cvss         []Cvss   `json:"cvss"`          // Common Vulnerability Scoring System values
+	// Published and Modified are when the vulnerability was published and last modified by its source, if known
+	Published *time.Time `json:"published,omitempty"`
+	Modified  *time.Time `json:"modified,omitempty"`
 }
 
 // Cvss contains select Common Vulnerability Scoring System fields for a vulnerability.
@@ -58,7 +64,9 @@ func (v *VulnerabilityMetadata) Equal(vv VulnerabilityMetadata) bool {
 		v.Severity == vv.Severity &&
 		v.Description == vv.Description &&
 		len(v.URLs) == len(vv.URLs) &&
-		len(v.Cvss) == len(vv.Cvss)
+		len(v.Cvss) == len(vv.Cvss) &&
+		sameTime(v.Published, vv.Published) &&
+		sameTime(v.Modified, vv.Modified)
 
 	if !equal {
 		return false
@@ -76,3 +84,10 @@ func (v *VulnerabilityMetadata) Equal(vv VulnerabilityMetadata) bool {
 
 	return true
 }
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,169 @@
+/*
+Package dbtrace traces the queries made to the vulnerability database while matching, writing one JSON object per
+query with the criteria of the query, how long it took and how many records it returned.
+*/
+package dbtrace
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+var (
+	_ vulnerability.Provider         = (*Tracer)(nil)
+	_ vulnerability.MetadataProvider = (*Tracer)(nil)
+	_ match.ExclusionProvider        = (*Tracer)(nil)
+	_ store.DegradationReporter      = (*Tracer)(nil)
+)
+
+// Query is the trace of a single query to the vulnerability database.
+type Query struct {
+	Time     time.Time         `json:"time"`
+	Query    string            `json:"query"`
+	Criteria map[string]string `json:"criteria"`
+	Duration time.Duration     `json:"durationNs"`
+	Rows     int               `json:"rows"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// Tracer decorates the providers of a store, writing the trace of every query made to them. It is safe for
+// concurrent use.
+type Tracer struct {
+	vulnerabilities vulnerability.Provider
+	metadata        vulnerability.MetadataProvider
+	exclusions      match.ExclusionProvider
+
+	lock    sync.Mutex
+	encoder *json.Encoder
+	failed  bool
+}
+
+// Apply returns a copy of the given store with every query to its providers traced to the given writer. The store is
+// returned unchanged without a writer.
+func Apply(s *store.Store, w io.Writer) *store.Store {
+	if s == nil || w == nil {
+		return s
+	}
+
+	t := &Tracer{
+		vulnerabilities: s.Provider,
+		metadata:        s.MetadataProvider,
+		exclusions:      s.ExclusionProvider,
+		encoder:         json.NewEncoder(w),
+	}
+	t.encoder.SetEscapeHTML(false)
+
+	traced := &store.Store{
+		Provider:         t,
+		MetadataProvider: t,
+		Targeting:        s.Targeting,
+	}
+	if s.ExclusionProvider != nil {
+		traced.ExclusionProvider = t
+	}
+	return traced
+}
+
+// Degradations returns the degradations of the traced providers.
+func (t *Tracer) Degradations() []grypeerr.DataQualityProblem {
+	return append(store.DegradationsOf(t.vulnerabilities), store.DegradationsOf(t.metadata)...)
+}
+
+func (t *Tracer) Get(id, namespace string) ([]vulnerability.Vulnerability, error) {
+	start := time.Now()
+	vulns, err := t.vulnerabilities.Get(id, namespace)
+	t.trace(start, "Get", map[string]string{"id": id, "namespace": namespace}, len(vulns), err)
+	return vulns, err
+}
+
+func (t *Tracer) GetByDistro(d *distro.Distro, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	start := time.Now()
+	vulns, err := t.vulnerabilities.GetByDistro(d, p)
+	criteria := packageCriteria(p)
+	if d != nil {
+		criteria["distro"] = d.String()
+	}
+	t.trace(start, "GetByDistro", criteria, len(vulns), err)
+	return vulns, err
+}
+
+func (t *Tracer) GetByLanguage(l syftPkg.Language, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	start := time.Now()
+	vulns, err := t.vulnerabilities.GetByLanguage(l, p)
+	criteria := packageCriteria(p)
+	criteria["language"] = string(l)
+	t.trace(start, "GetByLanguage", criteria, len(vulns), err)
+	return vulns, err
+}
+
+func (t *Tracer) GetByCPE(c cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	start := time.Now()
+	vulns, err := t.vulnerabilities.GetByCPE(c)
+	t.trace(start, "GetByCPE", map[string]string{"cpe": c.Attributes.BindToFmtString()}, len(vulns), err)
+	return vulns, err
+}
+
+func (t *Tracer) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	if t.metadata == nil {
+		return nil, nil
+	}
+	start := time.Now()
+	m, err := t.metadata.GetMetadata(id, namespace)
+	rows := 0
+	if m != nil {
+		rows = 1
+	}
+	t.trace(start, "GetMetadata", map[string]string{"id": id, "namespace": namespace}, rows, err)
+	return m, err
+}
+
+func (t *Tracer) GetRules(vulnerabilityID string) ([]match.IgnoreRule, error) {
+	start := time.Now()
+	rules, err := t.exclusions.GetRules(vulnerabilityID)
+	t.trace(start, "GetRules", map[string]string{"id": vulnerabilityID}, len(rules), err)
+	return rules, err
+}
+
+func packageCriteria(p pkg.Package) map[string]string {
+	return map[string]string{
+		"package": p.Name,
+		"version": p.Version,
+		"type":    string(p.Type),
+	}
+}
+
+func (t *Tracer) trace(start time.Time, query string, criteria map[string]string, rows int, err error) {
+	q := Query{
+		Time:     start,
+		Query:    query,
+		Criteria: criteria,
+		Duration: time.Since(start),
+		Rows:     rows,
+	}
+	if err != nil {
+		q.Error = err.Error()
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.failed {
+		return
+	}
+	if err := t.encoder.Encode(q); err != nil {
+		// tracing is a diagnostic aid, failing to write the trace does not fail the scan
+		log.WithFields("error", err).Warn("unable to write the DB query trace, no more queries are traced")
+		t.failed = true
+	}
+}
@@ -0,0 +1,104 @@
+package dbtrace
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type fakeProvider struct{}
+
+func (fakeProvider) Get(id, _ string) ([]vulnerability.Vulnerability, error) {
+	return []vulnerability.Vulnerability{{ID: id}}, nil
+}
+
+func (fakeProvider) GetByDistro(*distro.Distro, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return []vulnerability.Vulnerability{{ID: "CVE-2024-0001"}, {ID: "CVE-2024-0002"}}, nil
+}
+
+func (fakeProvider) GetByLanguage(syftPkg.Language, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (fakeProvider) GetByCPE(cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	return nil, errors.New("query failed")
+}
+
+func (fakeProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	return &vulnerability.Metadata{ID: id, Namespace: namespace}, nil
+}
+
+func (fakeProvider) GetRules(string) ([]match.IgnoreRule, error) {
+	return nil, nil
+}
+
+func TestApply(t *testing.T) {
+	var trace bytes.Buffer
+	s := Apply(&store.Store{
+		Provider:          fakeProvider{},
+		MetadataProvider:  fakeProvider{},
+		ExclusionProvider: fakeProvider{},
+	}, &trace)
+
+	d, err := distro.New(distro.Alpine, "3.20", "")
+	require.NoError(t, err)
+	p := pkg.Package{Name: "openssl", Version: "3.3.1-r0", Type: syftPkg.ApkPkg}
+
+	vulns, err := s.GetByDistro(d, p)
+	require.NoError(t, err)
+	assert.Len(t, vulns, 2)
+	_, err = s.GetByLanguage(syftPkg.Python, pkg.Package{Name: "requests", Version: "2.31.0", Type: syftPkg.PythonPkg})
+	require.NoError(t, err)
+	_, err = s.GetByCPE(cpe.Must("cpe:2.3:a:openssl:openssl:3.3.1:*:*:*:*:*:*:*", ""))
+	assert.Error(t, err)
+	m, err := s.GetMetadata("CVE-2024-0001", "nvd:cpe")
+	require.NoError(t, err)
+	assert.Equal(t, "CVE-2024-0001", m.ID)
+	_, err = s.GetRules("CVE-2024-0001")
+	require.NoError(t, err)
+
+	var queries []Query
+	for _, line := range strings.Split(strings.TrimSpace(trace.String()), "\n") {
+		var q Query
+		require.NoError(t, json.Unmarshal([]byte(line), &q))
+		assert.False(t, q.Time.IsZero())
+		queries = append(queries, q)
+	}
+	require.Len(t, queries, 5)
+
+	assert.Equal(t, "GetByDistro", queries[0].Query)
+	assert.Equal(t, map[string]string{"distro": "alpine 3.20", "package": "openssl", "version": "3.3.1-r0", "type": "apk"}, queries[0].Criteria)
+	assert.Equal(t, 2, queries[0].Rows)
+
+	assert.Equal(t, "GetByLanguage", queries[1].Query)
+	assert.Equal(t, "python", queries[1].Criteria["language"])
+	assert.Equal(t, 0, queries[1].Rows)
+
+	assert.Equal(t, "GetByCPE", queries[2].Query)
+	assert.Equal(t, "cpe:2.3:a:openssl:openssl:3.3.1:*:*:*:*:*:*:*", queries[2].Criteria["cpe"])
+	assert.Equal(t, "query failed", queries[2].Error)
+
+	assert.Equal(t, "GetMetadata", queries[3].Query)
+	assert.Equal(t, map[string]string{"id": "CVE-2024-0001", "namespace": "nvd:cpe"}, queries[3].Criteria)
+	assert.Equal(t, 1, queries[3].Rows)
+
+	assert.Equal(t, "GetRules", queries[4].Query)
+}
+
+func TestApply_withoutWriter(t *testing.T) {
+	s := &store.Store{Provider: fakeProvider{}}
+	assert.Same(t, s, Apply(s, nil))
+}
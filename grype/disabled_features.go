@@ -0,0 +1,50 @@
+package grype
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg/qualifier/configuration"
+)
+
+const (
+	// disabledFeaturesVexStatus and disabledFeaturesVexJustification are the VEX status and justification of the
+	// matches set aside because the features they require are disabled.
+	disabledFeaturesVexStatus        = "not_affected"
+	disabledFeaturesVexJustification = "vulnerable_code_not_in_execute_path"
+)
+
+// applyDisabledFeatures sets aside the matches of vulnerabilities that require optional features (or configurations)
+// of the package which are all declared as disabled for the package (e.g. a vulnerability of the mod_lua module of
+// Apache when mod_lua is not enabled for apache2). The matches are ignored as not affected, with a VEX justification, so they are still reported
+// alongside the ignored matches (and in the generated VEX documents).
+func (m *VulnerabilityMatcher) applyDisabledFeatures(matches match.Matches) (match.Matches, []match.IgnoredMatch) {
+	if len(m.DisabledFeatures) == 0 {
+		return matches, nil
+	}
+
+	var ignored []match.IgnoredMatch
+	remaining := match.NewMatches()
+	for _, mt := range matches.Sorted() {
+		q, ok := configuration.Of(mt.Vulnerability.PackageQualifiers)
+		if !ok || !q.DisabledBy(m.DisabledFeatures, mt.Package) {
+			remaining.Add(mt)
+			continue
+		}
+		ignored = append(ignored, match.IgnoredMatch{
+			Match: mt,
+			AppliedIgnoreRules: []match.IgnoreRule{{
+				Vulnerability:    mt.Vulnerability.ID,
+				Namespace:        mt.Vulnerability.Namespace,
+				Reason:           fmt.Sprintf("requires %s, which is disabled for %s", strings.Join(q.Features(), " or "), mt.Package.Name),
+				VexStatus:        disabledFeaturesVexStatus,
+				VexJustification: disabledFeaturesVexJustification,
+			}},
+		})
+	}
+	if count := len(ignored); count > 0 {
+		m.log().Infof("ignoring %d matches requiring disabled features", count)
+	}
+	return remaining, ignored
+}
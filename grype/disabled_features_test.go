@@ -0,0 +1,58 @@
+package grype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/pkg/qualifier"
+	"github.com/anchore/grype/grype/pkg/qualifier/configuration"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestVulnerabilityMatcher_applyDisabledFeatures(t *testing.T) {
+	apache := pkg.Package{ID: "apache2", Name: "apache2", Version: "2.4.57-2", Type: syftPkg.DebPkg, PURL: "pkg:deb/debian/apache2@2.4.57-2"}
+	nginx := pkg.Package{ID: "nginx", Name: "nginx", Version: "1.22.1-9", Type: syftPkg.DebPkg}
+	newMatch := func(id string, p pkg.Package, qualifiers ...qualifier.Qualifier) match.Match {
+		return match.Match{
+			Vulnerability: vulnerability.Vulnerability{ID: id, Namespace: "debian:distro:debian:12", PackageQualifiers: qualifiers},
+			Package:       p,
+		}
+	}
+	matches := match.NewMatches(
+		newMatch("CVE-2023-0001", apache, configuration.New([]string{"mod_lua"})),
+		newMatch("CVE-2023-0002", apache, configuration.New([]string{"mod_lua", "mod_proxy"})),
+		newMatch("CVE-2023-0003", apache),
+		// the feature is only disabled for apache2
+		newMatch("CVE-2023-0004", nginx, configuration.New([]string{"mod_lua"})),
+	)
+
+	for _, value := range []string{"apache2:mod_lua", "pkg:deb/debian/apache2:mod_lua"} {
+		t.Run(value, func(t *testing.T) {
+			disabled, err := configuration.ParseAllDisabled(value)
+			require.NoError(t, err)
+			m := &VulnerabilityMatcher{DisabledFeatures: disabled}
+			remaining, ignored := m.applyDisabledFeatures(matches)
+
+			assert.Equal(t, 3, remaining.Count())
+			require.Len(t, ignored, 1)
+			assert.Equal(t, "CVE-2023-0001", ignored[0].Vulnerability.ID)
+			assert.Equal(t, []match.IgnoreRule{{
+				Vulnerability:    "CVE-2023-0001",
+				Namespace:        "debian:distro:debian:12",
+				Reason:           "requires mod_lua, which is disabled for apache2",
+				VexStatus:        "not_affected",
+				VexJustification: "vulnerable_code_not_in_execute_path",
+			}}, ignored[0].AppliedIgnoreRules)
+		})
+	}
+
+	m := &VulnerabilityMatcher{}
+	remaining, ignored := m.applyDisabledFeatures(matches)
+	assert.Equal(t, 4, remaining.Count())
+	assert.Empty(t, ignored)
+}
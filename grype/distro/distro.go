@@ -98,12 +98,13 @@ func (d Distro) IsRolling() bool {
 	return d.Type == Wolfi || d.Type == Chainguard || d.Type == ArchLinux || d.Type == Gentoo
 }
 
-// Unsupported Linux distributions
-func (d Distro) Disabled() bool {
-	switch {
-	case d.Type == ArchLinux:
-		return true
-	default:
-		return false
+// Disabled returns true if the distribution is one of the given distributions whose packages are not matched (e.g.
+// the distributions disabled by the configuration).
+func (d Distro) Disabled(disabled []Type) bool {
+	for _, t := range disabled {
+		if d.Type == t {
+			return true
+		}
 	}
+	return false
 }
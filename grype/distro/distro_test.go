@@ -243,6 +243,16 @@ func Test_NewDistroFromRelease_Coverage(t *testing.T) {
 			fixture: "test-fixtures/os/chainguard",
 			Type:    Chainguard,
 		},
+		{
+			fixture: "test-fixtures/os/openwrt",
+			Type:    OpenWrt,
+			Version: "23.5.3",
+		},
+		{
+			fixture: "test-fixtures/os/yocto",
+			Type:    Yocto,
+			Version: "4.0.12",
+		},
 	}
 
 	observedDistros := stringutil.NewStringSet()
@@ -367,3 +377,20 @@ func TestDistro_MajorVersion(t *testing.T) {
 	}
 
 }
+
+func TestDistro_Disabled(t *testing.T) {
+	d, err := NewFromRelease(linux.Release{ID: "arch"})
+	require.NoError(t, err)
+	assert.False(t, d.Disabled(nil))
+	assert.False(t, d.Disabled([]Type{Debian}))
+	assert.True(t, d.Disabled([]Type{Debian, ArchLinux}))
+}
+
+func TestParseTypes(t *testing.T) {
+	types, err := ParseTypes("archlinux", " Arch ", "rhel")
+	require.NoError(t, err)
+	assert.Equal(t, []Type{ArchLinux, ArchLinux, RedHat}, types)
+
+	_, err = ParseTypes("no-such-distro")
+	require.ErrorContains(t, err, `unknown linux distribution "no-such-distro"`)
+}
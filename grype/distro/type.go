@@ -1,6 +1,9 @@
 package distro
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/anchore/syft/syft/linux"
 )
 
@@ -31,6 +34,8 @@ const (
 	Gentoo       Type = "gentoo"
 	Wolfi        Type = "wolfi"
 	Chainguard   Type = "chainguard"
+	OpenWrt      Type = "openwrt"
+	Yocto        Type = "yocto"
 )
 
 // All contains all Linux distribution options
@@ -56,6 +61,8 @@ var All = []Type{
 	Gentoo,
 	Wolfi,
 	Chainguard,
+	OpenWrt,
+	Yocto,
 }
 
 // IDMapping connects a distro ID like "ubuntu" to a Distro type
@@ -82,6 +89,37 @@ var IDMapping = map[string]Type{
 	"gentoo":        Gentoo,
 	"wolfi":         Wolfi,
 	"chainguard":    Chainguard,
+	"openwrt":       OpenWrt,
+	"yocto":         Yocto,
+	"poky":          Yocto,
+}
+
+// ParseType returns the distribution type of the given type or distro ID (e.g. "archlinux" or "arch"), or an error
+// for an unknown distribution.
+func ParseType(value string) (Type, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, t := range All {
+		if string(t) == value {
+			return t, nil
+		}
+	}
+	if t, ok := IDMapping[value]; ok {
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown linux distribution %q", value)
+}
+
+// ParseTypes returns the distribution types of the given types or distro IDs (see ParseType).
+func ParseTypes(values ...string) ([]Type, error) {
+	var types []Type
+	for _, v := range values {
+		t, err := ParseType(v)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	return types, nil
 }
 
 func TypeFromRelease(release linux.Release) Type {
@@ -0,0 +1,132 @@
+/*
+Package environment describes the environment the scanned artifact is deployed in (e.g. "env=prod,exposure=internet"),
+which is reported along with the results, decides which ignore rules apply, and implies the environmental CVSS metrics
+adjusting the scores of the matches.
+*/
+package environment
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/severity"
+)
+
+const (
+	// ExposureKey is how reachable the deployed artifact is: "internet", "internal" (from the internal network only),
+	// "local" (from the host only) or "physical" (with physical access only).
+	ExposureKey = "exposure"
+	// CriticalityKey is how critical the deployed artifact is to the organization: "high", "medium" or "low".
+	CriticalityKey = "criticality"
+)
+
+// the CVSS v3 metrics implied by the values of the well-known keys
+var impliedMetrics = map[string]map[string]severity.Metrics{
+	ExposureKey: {
+		"internet": {},
+		"internal": {"MAV": "A"},
+		"local":    {"MAV": "L"},
+		"physical": {"MAV": "P"},
+	},
+	CriticalityKey: {
+		"high":   {"CR": "H", "IR": "H", "AR": "H"},
+		"medium": {"CR": "M", "IR": "M", "AR": "M"},
+		"low":    {"CR": "L", "IR": "L", "AR": "L"},
+	},
+}
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Context is the environment of the scanned artifact, as values by key (e.g. "env" is "prod"). The keys and the values
+// of the well-known keys are lowercase.
+type Context map[string]string
+
+// Parse parses environment contexts of comma-separated key=value pairs (e.g. "env=prod,exposure=internet"), the last
+// value of a key given several times being kept.
+func Parse(values ...string) (Context, error) {
+	c := make(Context)
+	for _, value := range values {
+		for _, pair := range strings.Split(value, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			key, v, ok := strings.Cut(pair, "=")
+			key, v = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(v)
+			if !ok || v == "" {
+				return nil, fmt.Errorf("bad environment context %q: expected key=value", pair)
+			}
+			if !keyPattern.MatchString(key) {
+				return nil, fmt.Errorf("bad environment context key %q: expected lowercase letters, digits, '.', '_' and '-'", key)
+			}
+			if known, ok := impliedMetrics[key]; ok {
+				v = strings.ToLower(v)
+				if _, ok := known[v]; !ok {
+					return nil, fmt.Errorf("unknown %s %q (options: %s)", key, v, strings.Join(sortedKeys(known), ", "))
+				}
+			}
+			c[key] = v
+		}
+	}
+	return c, nil
+}
+
+// String returns the context as sorted key=value pairs.
+func (c Context) String() string {
+	pairs := make([]string, 0, len(c))
+	for _, key := range sortedKeys(c) {
+		pairs = append(pairs, key+"="+c[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Satisfies returns true if the context has every value of the other context (the values being compared ignoring
+// case).
+func (c Context) Satisfies(other Context) bool {
+	for key, v := range other {
+		if !strings.EqualFold(c[key], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// AdjustMetrics returns the CVSS metrics with the environmental metrics implied by the exposure and criticality of the
+// context, for the metrics not already given.
+func (c Context) AdjustMetrics(metrics severity.Metrics) severity.Metrics {
+	adjusted := make(severity.Metrics, len(metrics))
+	for name, v := range metrics {
+		adjusted[name] = v
+	}
+	for key, values := range impliedMetrics {
+		for name, v := range values[c[key]] {
+			if _, ok := adjusted[name]; !ok {
+				adjusted[name] = v
+			}
+		}
+	}
+	return adjusted
+}
+
+// ApplicableRules returns the ignore rules applying in the context: the rules without an environment, and the rules
+// whose environment (e.g. "env=dev") the context satisfies. The rules were validated, so their environments parse.
+func (c Context) ApplicableRules(rules []match.IgnoreRule) []match.IgnoreRule {
+	var applicable []match.IgnoreRule
+	for _, rule := range rules {
+		if rule.AppliesInEnvironment(c) {
+			applicable = append(applicable, rule)
+		}
+	}
+	return applicable
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,68 @@
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/severity"
+)
+
+func TestParse(t *testing.T) {
+	c, err := Parse("env=prod,Exposure=Internet", "criticality=high, team=Payments", "env=staging")
+	require.NoError(t, err)
+	assert.Equal(t, Context{
+		"env":         "staging",
+		"exposure":    "internet",
+		"criticality": "high",
+		"team":        "Payments",
+	}, c)
+	assert.Equal(t, "criticality=high,env=staging,exposure=internet,team=Payments", c.String())
+
+	c, err = Parse()
+	require.NoError(t, err)
+	assert.Empty(t, c)
+
+	_, err = Parse("env")
+	assert.ErrorContains(t, err, "expected key=value")
+
+	_, err = Parse("my env=prod")
+	assert.ErrorContains(t, err, `bad environment context key "my env"`)
+
+	_, err = Parse("exposure=public")
+	assert.ErrorContains(t, err, `unknown exposure "public" (options: internal, internet, local, physical)`)
+}
+
+func TestContext_AdjustMetrics(t *testing.T) {
+	c, err := Parse("exposure=internal,criticality=low")
+	require.NoError(t, err)
+
+	assert.Equal(t, severity.Metrics{"MAV": "A", "CR": "L", "IR": "L", "AR": "L"}, c.AdjustMetrics(nil))
+	// the given metrics take precedence
+	assert.Equal(t, severity.Metrics{"MAV": "L", "CR": "L", "IR": "L", "AR": "L", "E": "U"}, c.AdjustMetrics(severity.Metrics{"MAV": "L", "E": "U"}))
+
+	c, err = Parse("exposure=internet,env=prod")
+	require.NoError(t, err)
+	assert.Empty(t, c.AdjustMetrics(nil))
+}
+
+func TestContext_ApplicableRules(t *testing.T) {
+	rules := []match.IgnoreRule{
+		{Vulnerability: "CVE-2024-0001"},
+		{Vulnerability: "CVE-2024-0002", Environment: "env=dev"},
+		{Vulnerability: "CVE-2024-0003", Environment: "env=prod,exposure=internal"},
+	}
+
+	prod, err := Parse("env=prod,exposure=internal,criticality=high")
+	require.NoError(t, err)
+	assert.Equal(t, []match.IgnoreRule{rules[0], rules[2]}, prod.ApplicableRules(rules))
+
+	dev, err := Parse("env=Dev")
+	require.NoError(t, err)
+	assert.Equal(t, []match.IgnoreRule{rules[0], rules[1]}, dev.ApplicableRules(rules))
+
+	var none Context
+	assert.Equal(t, []match.IgnoreRule{rules[0]}, none.ApplicableRules(rules))
+}
@@ -14,6 +14,13 @@ const (
 	VulnerabilityScanningStarted partybus.EventType = typePrefix + "-vulnerability-scanning-started"
 	DatabaseDiffingStarted       partybus.EventType = typePrefix + "-database-diffing-started"
 
+	// SBOMDecodingStarted is a partybus event that occurs when an SBOM given as input starts being decoded
+	SBOMDecodingStarted partybus.EventType = typePrefix + "-sbom-decoding-started"
+
+	// DatabaseLoadingStarted is a partybus event that occurs when the vulnerability database starts being validated
+	// and opened
+	DatabaseLoadingStarted partybus.EventType = typePrefix + "-database-loading-started"
+
 	// Events exclusively for the CLI
 
 	// CLIAppUpdateAvailable is a partybus event that occurs when an application update is available
@@ -0,0 +1,374 @@
+/*
+Package feedback captures a single match with the evidence behind it (the package as cataloged, the matcher details and
+the vulnerability records consulted) into a bundle that can be attached to a bug report about match quality, such as
+a false positive. Bundles are redacted so they can be shared outside of the organization that ran the scan.
+*/
+package feedback
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/file"
+)
+
+// Schema identifies the format of the bundle.
+const Schema = "grype-match-report/1"
+
+// Redacted replaces the values that are removed from a bundle.
+const Redacted = "<redacted>"
+
+// Bundle is a match along with the evidence needed to reproduce how it was made.
+type Bundle struct {
+	Schema  string       `json:"schema"`
+	Note    string       `json:"note,omitempty"`
+	Tool    Tool         `json:"tool"`
+	Source  Source       `json:"source"`
+	Distro  Distro       `json:"distro"`
+	Ignored bool         `json:"ignored,omitempty"`
+	Match   models.Match `json:"match"` // the match as reported, including the package (the SBOM entry matched on) and the matcher details
+	Records []Record     `json:"records"`
+}
+
+// Tool describes the grype version and vulnerability DB that made the match.
+type Tool struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	DB      interface{} `json:"db,omitempty"`
+}
+
+// Source describes the scanned source, where the target is redacted unless kept on request.
+type Source struct {
+	Type   string      `json:"type"`
+	Target interface{} `json:"target"`
+}
+
+// Distro is the distro the match was made for, if any.
+type Distro struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	IDLike  []string `json:"idLike,omitempty"`
+}
+
+// Record is a vulnerability record from the DB that was consulted for the match: the record of the matched
+// vulnerability and those of the related vulnerabilities.
+type Record struct {
+	ID                string                        `json:"id"`
+	Namespace         string                        `json:"namespace"`
+	PackageName       string                        `json:"packageName,omitempty"`
+	VersionConstraint string                        `json:"versionConstraint,omitempty"`
+	CPEs              []string                      `json:"cpes,omitempty"`
+	FixVersions       []string                      `json:"fixVersions,omitempty"`
+	FixState          string                        `json:"fixState,omitempty"`
+	Metadata          *models.VulnerabilityMetadata `json:"metadata,omitempty"`
+}
+
+// RecordProvider looks up the vulnerability records consulted for a match.
+type RecordProvider interface {
+	vulnerability.MetadataProvider
+	Get(id, namespace string) ([]vulnerability.Vulnerability, error)
+}
+
+// Selector identifies the match to capture from a report.
+type Selector struct {
+	// ID is the ID of the match (see the "id" of each match in the JSON report) or the ID of the vulnerability.
+	ID string
+
+	// Package narrows down the matches for a vulnerability by package name or package URL.
+	Package string
+}
+
+// Options configures how a bundle is created.
+type Options struct {
+	// Note describes why the match is believed to be wrong.
+	Note string
+
+	// KeepSource keeps the scanned source target (e.g. the image name and digests) in the bundle, along with the values
+	// of the package that reveal it: the layer IDs of its locations, the source paths within its locations and
+	// metadata, and the location of the DB.
+	KeepSource bool
+}
+
+// New creates a bundle for the match of the report that is identified by the selector, looking up the consulted
+// vulnerability records with the provider (when given).
+func New(doc models.Document, selector Selector, provider RecordProvider, opts Options) (*Bundle, error) {
+	m, ignored, err := find(doc, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		Schema:  Schema,
+		Note:    opts.Note,
+		Tool:    Tool{Name: doc.Descriptor.Name, Version: doc.Descriptor.Version, DB: doc.Descriptor.VulnerabilityDBStatus},
+		Distro:  Distro{Name: doc.Distro.Name, Version: doc.Distro.Version, IDLike: doc.Distro.IDLike},
+		Ignored: ignored,
+		Match:   *m,
+		Records: []Record{},
+	}
+
+	if doc.Source != nil {
+		b.Source = Source{Type: doc.Source.Type, Target: Redacted}
+		if opts.KeepSource {
+			b.Source.Target = doc.Source.Target
+		}
+	}
+	if !opts.KeepSource {
+		var values []string
+		if doc.Source != nil {
+			values = sourceValues(doc.Source.Target)
+		}
+		b.Match.Artifact = redactPackage(b.Match.Artifact, values)
+		b.Tool.DB = redactDBStatus(b.Tool.DB)
+	}
+
+	if provider != nil {
+		if b.Records, err = records(*m, provider); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Encode writes the bundle as JSON, replacing each of the given values wherever it appears with Redacted.
+func (b Bundle) Encode(writer io.Writer, redact ...string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	if err := enc.Encode(b); err != nil {
+		return fmt.Errorf("unable to encode bundle: %w", err)
+	}
+
+	out := buf.String()
+	for _, value := range redact {
+		if value == "" {
+			continue
+		}
+		// the value is replaced the way it appears within JSON strings
+		escaped, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		out = strings.ReplaceAll(out, strings.Trim(string(escaped), `"`), Redacted)
+	}
+
+	_, err := io.WriteString(writer, out)
+	return err
+}
+
+// sourceValues returns the values identifying the scanned source within a source target: the target itself when it is a
+// path, or else the user input, the image ID and the digests, tags and layer digests of an image.
+func sourceValues(target interface{}) []string {
+	var values []string
+	var collect func(key string, v interface{})
+	collect = func(key string, v interface{}) {
+		switch value := v.(type) {
+		case string:
+			switch key {
+			case "", "userInput", "imageID", "manifestDigest", "repoDigests", "tags", "digest", "path":
+				if value != "" && value != "/" {
+					values = append(values, value)
+				}
+			}
+		case []interface{}:
+			for _, e := range value {
+				collect(key, e)
+			}
+		case map[string]interface{}:
+			for k, e := range value {
+				collect(k, e)
+			}
+		}
+	}
+	collect("", generic(target))
+	// the longest values first, so that a value within another is not replaced on its own first
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	return values
+}
+
+// redactPackage returns the package with the layer IDs of its locations redacted, and the source values redacted from
+// the paths of its locations and its metadata.
+func redactPackage(p models.Package, values []string) models.Package {
+	locations := make([]file.Coordinates, 0, len(p.Locations))
+	for _, l := range p.Locations {
+		l.RealPath = redactValues(l.RealPath, values)
+		if l.FileSystemID != "" {
+			l.FileSystemID = Redacted
+		}
+		locations = append(locations, l)
+	}
+	p.Locations = locations
+	if p.Metadata != nil {
+		p.Metadata = redactGeneric(generic(p.Metadata), values)
+	}
+	return p
+}
+
+// redactDBStatus returns the status of the DB without its location, a path of the host that ran the scan.
+func redactDBStatus(status interface{}) interface{} {
+	fields, ok := generic(status).(map[string]interface{})
+	if !ok {
+		return status
+	}
+	if _, ok := fields["location"]; ok {
+		fields["location"] = Redacted
+	}
+	return fields
+}
+
+func redactGeneric(v interface{}, values []string) interface{} {
+	switch value := v.(type) {
+	case string:
+		return redactValues(value, values)
+	case []interface{}:
+		for i := range value {
+			value[i] = redactGeneric(value[i], values)
+		}
+	case map[string]interface{}:
+		for k := range value {
+			value[k] = redactGeneric(value[k], values)
+		}
+	}
+	return v
+}
+
+func redactValues(s string, values []string) string {
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, Redacted)
+	}
+	return s
+}
+
+// generic returns the value as decoded from its JSON form (maps, lists and scalars), as it is in a report read back.
+func generic(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+func find(doc models.Document, selector Selector) (*models.Match, bool, error) {
+	var candidates []models.Match
+	var ignored []bool
+	consider := func(m models.Match, isIgnored bool) {
+		if m.ID == selector.ID {
+			// match IDs are unique, so the selected match has been found
+			candidates, ignored = []models.Match{m}, []bool{isIgnored}
+			return
+		}
+		if !strings.EqualFold(m.Vulnerability.ID, selector.ID) {
+			return
+		}
+		if selector.Package != "" && m.Artifact.Name != selector.Package && m.Artifact.PURL != selector.Package {
+			return
+		}
+		candidates = append(candidates, m)
+		ignored = append(ignored, isIgnored)
+	}
+
+	for _, m := range doc.Matches {
+		consider(m, false)
+	}
+	for _, m := range doc.IgnoredMatches {
+		consider(m.Match, true)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, false, fmt.Errorf("no match for %q found in the report", selector.ID)
+	case 1:
+		return &candidates[0], ignored[0], nil
+	}
+
+	var found []string
+	for _, c := range candidates {
+		found = append(found, fmt.Sprintf("%s (%s %s)", c.ID, c.Artifact.Name, c.Artifact.Version))
+	}
+	return nil, false, fmt.Errorf("several matches for %q found in the report, select one by match ID or with a package: %s", selector.ID, strings.Join(found, ", "))
+}
+
+func records(m models.Match, provider RecordProvider) ([]Record, error) {
+	refs := []vulnerability.Reference{{ID: m.Vulnerability.ID, Namespace: m.Vulnerability.Namespace}}
+	for _, r := range m.RelatedVulnerabilities {
+		refs = append(refs, vulnerability.Reference{ID: r.ID, Namespace: r.Namespace})
+	}
+
+	out := []Record{}
+	for _, ref := range refs {
+		vulns, err := provider.Get(ref.ID, ref.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch records for vuln=%q: %w", ref.ID, err)
+		}
+
+		var metadata *models.VulnerabilityMetadata
+		if md, err := provider.GetMetadata(ref.ID, ref.Namespace); err == nil && md != nil {
+			vm := models.NewVulnerabilityMetadata(ref.ID, ref.Namespace, md)
+			metadata = &vm
+		}
+
+		if len(vulns) == 0 {
+			// related vulnerabilities often only have metadata (e.g. NVD records of a GHSA advisory)
+			out = append(out, Record{ID: ref.ID, Namespace: ref.Namespace, Metadata: metadata})
+			continue
+		}
+
+		for _, v := range forPackage(vulns, m.Artifact) {
+			out = append(out, newRecord(v, metadata))
+		}
+	}
+
+	return out, nil
+}
+
+// forPackage returns the records about the given package, since a vulnerability may affect many packages. All records
+// are returned when none is about the package by name, as with CPE matches (where the record names the product).
+func forPackage(vulns []vulnerability.Vulnerability, p models.Package) []vulnerability.Vulnerability {
+	names := []string{p.Name}
+	for _, u := range p.Upstreams {
+		names = append(names, u.Name)
+	}
+
+	var out []vulnerability.Vulnerability
+	for _, v := range vulns {
+		for _, name := range names {
+			if strings.EqualFold(v.PackageName, name) {
+				out = append(out, v)
+				break
+			}
+		}
+	}
+	if len(out) == 0 {
+		return vulns
+	}
+	return out
+}
+
+func newRecord(v vulnerability.Vulnerability, metadata *models.VulnerabilityMetadata) Record {
+	r := Record{
+		ID:          v.ID,
+		Namespace:   v.Namespace,
+		PackageName: v.PackageName,
+		FixVersions: v.Fix.Versions,
+		FixState:    string(v.Fix.State),
+		Metadata:    metadata,
+	}
+	if v.Constraint != nil {
+		r.VersionConstraint = v.Constraint.String()
+	}
+	for _, c := range v.CPEs {
+		r.CPEs = append(r.CPEs, c.Attributes.BindToFmtString())
+	}
+	return r
+}
@@ -0,0 +1,142 @@
+package feedback
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/file"
+)
+
+type recordProvider map[string][]vulnerability.Vulnerability
+
+func (p recordProvider) Get(id, namespace string) ([]vulnerability.Vulnerability, error) {
+	return p[namespace+"/"+id], nil
+}
+
+func (p recordProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	return &vulnerability.Metadata{ID: id, Namespace: namespace, Severity: "High"}, nil
+}
+
+func readFixture(t *testing.T) models.Document {
+	t.Helper()
+	contents, err := os.ReadFile("test-fixtures/report.json")
+	require.NoError(t, err)
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(contents, &doc))
+	return doc
+}
+
+func TestNew(t *testing.T) {
+	doc := readFixture(t)
+	constraint, err := version.GetConstraint("< 3.1.1-r0", version.ApkFormat)
+	require.NoError(t, err)
+	provider := recordProvider{
+		"alpine:distro:alpine:3.18/CVE-2023-0001": {
+			{ID: "CVE-2023-0001", Namespace: "alpine:distro:alpine:3.18", PackageName: "openssl", Constraint: constraint, Fix: vulnerability.Fix{Versions: []string{"3.1.1-r0"}, State: "fixed"}},
+			{ID: "CVE-2023-0001", Namespace: "alpine:distro:alpine:3.18", PackageName: "openssl1.1-compat", Constraint: constraint},
+		},
+	}
+
+	bundle, err := New(doc, Selector{ID: "CVE-2023-0001", Package: "libcrypto3"}, provider, Options{Note: "fixed by a backport"})
+	require.NoError(t, err)
+
+	assert.Equal(t, Schema, bundle.Schema)
+	assert.Equal(t, "fixed by a backport", bundle.Note)
+	assert.Equal(t, Tool{Name: "grype", Version: "0.80.0", DB: map[string]interface{}{"built": "2024-06-01T00:00:00Z", "schemaVersion": float64(5)}}, bundle.Tool)
+	assert.Equal(t, Source{Type: "image", Target: Redacted}, bundle.Source)
+	assert.Equal(t, Distro{Name: "alpine", Version: "3.18.0", IDLike: []string{}}, bundle.Distro)
+	assert.False(t, bundle.Ignored)
+	assert.Equal(t, "3d8a2c1e-8b1f-5d0a-9f4e-1c2b3d4e5f60", bundle.Match.ID)
+	require.Len(t, bundle.Match.MatchDetails, 1)
+
+	// only the record for the upstream of the package, followed by the related NVD record
+	require.Len(t, bundle.Records, 2)
+	assert.Equal(t, "openssl", bundle.Records[0].PackageName)
+	assert.Equal(t, "< 3.1.1-r0 (apk)", bundle.Records[0].VersionConstraint)
+	assert.Equal(t, []string{"3.1.1-r0"}, bundle.Records[0].FixVersions)
+	assert.Equal(t, "nvd:cpe", bundle.Records[1].Namespace)
+	require.NotNil(t, bundle.Records[1].Metadata)
+}
+
+func TestNew_selection(t *testing.T) {
+	doc := readFixture(t)
+
+	tests := []struct {
+		name        string
+		selector    Selector
+		wantID      string
+		wantIgnored bool
+		wantErr     string
+	}{
+		{name: "by match ID", selector: Selector{ID: "7f1e2d3c-4b5a-5968-8776-a5b4c3d2e1f0"}, wantID: "7f1e2d3c-4b5a-5968-8776-a5b4c3d2e1f0"},
+		{name: "by purl", selector: Selector{ID: "cve-2023-0001", Package: "pkg:apk/alpine/libssl3@3.1.0-r4"}, wantID: "7f1e2d3c-4b5a-5968-8776-a5b4c3d2e1f0"},
+		{name: "ignored match", selector: Selector{ID: "CVE-2023-0002"}, wantID: "0c9b8a7d-6e5f-5a4b-9c3d-2e1f0a9b8c7d", wantIgnored: true},
+		{name: "several matches", selector: Selector{ID: "CVE-2023-0001"}, wantErr: "several matches"},
+		{name: "no match", selector: Selector{ID: "CVE-2023-9999"}, wantErr: "no match"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bundle, err := New(doc, test.selector, nil, Options{KeepSource: true})
+			if test.wantErr != "" {
+				require.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantID, bundle.Match.ID)
+			assert.Equal(t, test.wantIgnored, bundle.Ignored)
+			assert.Empty(t, bundle.Records)
+			assert.NotEqual(t, Redacted, bundle.Source.Target)
+		})
+	}
+}
+
+func TestNew_redaction(t *testing.T) {
+	var doc models.Document
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"source": {"type": "directory", "target": "/home/alice/app"},
+		"matches": [{
+			"id": "m1",
+			"vulnerability": {"id": "GHSA-0001"},
+			"artifact": {
+				"name": "log4j-core",
+				"locations": [{"path": "/home/alice/app/lib/log4j-core.jar", "layerID": "sha256:aaaa"}],
+				"metadata": {"virtualPath": "/home/alice/app/lib/log4j-core.jar", "manifest": {"main": ["Implementation-Title: log4j"]}}
+			}
+		}],
+		"descriptor": {"name": "grype", "version": "0.80.0", "db": {"schemaVersion": 5, "location": "/home/alice/.cache/grype/db/5"}}
+	}`), &doc))
+
+	bundle, err := New(doc, Selector{ID: "m1"}, nil, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []file.Coordinates{{RealPath: Redacted + "/lib/log4j-core.jar", FileSystemID: Redacted}}, bundle.Match.Artifact.Locations)
+	assert.Equal(t, map[string]interface{}{"virtualPath": Redacted + "/lib/log4j-core.jar", "manifest": map[string]interface{}{"main": []interface{}{"Implementation-Title: log4j"}}}, bundle.Match.Artifact.Metadata)
+	assert.Equal(t, map[string]interface{}{"schemaVersion": float64(5), "location": Redacted}, bundle.Tool.DB)
+	assert.Equal(t, "/home/alice/app/lib/log4j-core.jar", doc.Matches[0].Artifact.Locations[0].RealPath, "the report is not changed")
+
+	kept, err := New(doc, Selector{ID: "m1"}, nil, Options{KeepSource: true})
+	require.NoError(t, err)
+	assert.Equal(t, doc.Matches[0].Artifact, kept.Match.Artifact)
+}
+
+func TestBundle_Encode(t *testing.T) {
+	bundle, err := New(readFixture(t), Selector{ID: "CVE-2023-0002"}, nil, Options{})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, bundle.Encode(&buf, "libcrypto3"))
+
+	out := buf.String()
+	assert.NotContains(t, out, "libcrypto3")
+	assert.Contains(t, out, `"name": "<redacted>"`)
+	// the configuration of the scan (which may include registry credentials) is never part of the bundle
+	assert.NotContains(t, out, "registry")
+	assert.NotContains(t, out, "registry.internal.example.com")
+}
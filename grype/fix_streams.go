@@ -0,0 +1,104 @@
+package grype
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	grypeDb "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// releaseStreamPattern captures the major and minor version of an upstream version.
+var releaseStreamPattern = regexp.MustCompile(`^(\d+)(?:\.(\d+))?`)
+
+// releaseStream is the major and minor upstream version of a package.
+type releaseStream struct {
+	major, minor int
+}
+
+// laterMinor reports whether the stream is a later minor version of the same major version as the other stream. The
+// minor versions of major version 0 are streams of their own, since their releases may break compatibility.
+func (s releaseStream) laterMinor(other releaseStream) bool {
+	return s.major != 0 && s.major == other.major && s.minor > other.minor
+}
+
+// selectStreamFixes keeps, for the matches of distro vulnerability data, the fixes that are upgrades within the release
+// stream of the installed version: the fixes of the same major and minor version (e.g. 1.1.1f-1ubuntu2.20 for 1.1.1f,
+// rather than 3.0.2-0ubuntu1.10), or else of a later minor version of the same major version. When none of the fixes
+// is such an upgrade, the fixes are kept and flagged as requiring an upgrade (e.g. of the distro release).
+func selectStreamFixes(matches match.Matches) match.Matches {
+	out := match.NewMatches()
+	for _, m := range matches.Sorted() {
+		out.Add(selectStreamFix(m))
+	}
+	return out
+}
+
+func selectStreamFix(m match.Match) match.Match {
+	fix := m.Vulnerability.Fix
+	if fix.State != grypeDb.FixedState || len(fix.Versions) == 0 || !strings.Contains(m.Vulnerability.Namespace, ":distro:") {
+		return m
+	}
+	installed, ok := releaseStreamOf(m.Package.Version, m.Package.Type)
+	if !ok {
+		return m
+	}
+
+	var sameMinor, laterMinor []string
+	for _, v := range fix.Versions {
+		stream, ok := releaseStreamOf(v, m.Package.Type)
+		if !ok {
+			// the streams of the fixes cannot be told apart
+			return m
+		}
+		switch {
+		case stream == installed:
+			sameMinor = append(sameMinor, v)
+		case stream.laterMinor(installed):
+			laterMinor = append(laterMinor, v)
+		}
+	}
+
+	switch {
+	case len(sameMinor) > 0:
+		fix.Versions = sameMinor
+	case len(laterMinor) > 0:
+		fix.Versions = laterMinor
+	default:
+		fix.UpgradeRequired = true
+	}
+	m.Vulnerability.Fix = fix
+	return m
+}
+
+// releaseStreamOf returns the release stream of the version of a package, from its upstream version, without the
+// epoch nor the revision of the distro (e.g. 1.1 for "1:1.1.1f-1ubuntu2.20").
+func releaseStreamOf(version string, packageType syftPkg.Type) (releaseStream, bool) {
+	upstream := version
+	if i := strings.Index(upstream, ":"); i >= 0 {
+		upstream = upstream[i+1:]
+	}
+	switch packageType {
+	case syftPkg.ApkPkg:
+		if i := strings.LastIndex(upstream, "-r"); i >= 0 {
+			upstream = upstream[:i]
+		}
+	case syftPkg.DebPkg, syftPkg.RpmPkg, pkg.OpkgPkg:
+		if i := strings.LastIndex(upstream, "-"); i >= 0 {
+			upstream = upstream[:i]
+		}
+	}
+	parts := releaseStreamPattern.FindStringSubmatch(upstream)
+	if parts == nil {
+		return releaseStream{}, false
+	}
+	var stream releaseStream
+	stream.major, _ = strconv.Atoi(parts[1])
+	if parts[2] != "" {
+		stream.minor, _ = strconv.Atoi(parts[2])
+	}
+	return stream, true
+}
@@ -0,0 +1,125 @@
+package grype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	grypeDb "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func Test_selectStreamFix(t *testing.T) {
+	tests := []struct {
+		name            string
+		version         string
+		packageType     syftPkg.Type
+		namespace       string
+		fixes           []string
+		expected        []string
+		upgradeRequired bool
+	}{
+		{
+			name:        "fix of the installed stream",
+			version:     "1.1.1f-1ubuntu2.16",
+			packageType: syftPkg.DebPkg,
+			namespace:   "ubuntu:distro:ubuntu:20.04",
+			fixes:       []string{"1.1.1f-1ubuntu2.20", "3.0.2-0ubuntu1.10"},
+			expected:    []string{"1.1.1f-1ubuntu2.20"},
+		},
+		{
+			name:            "only fixes of other streams",
+			version:         "1.1.1f-1ubuntu2.16",
+			packageType:     syftPkg.DebPkg,
+			namespace:       "ubuntu:distro:ubuntu:20.04",
+			fixes:           []string{"3.0.2-0ubuntu1.10", "3.0.13-0ubuntu3.1"},
+			expected:        []string{"3.0.2-0ubuntu1.10", "3.0.13-0ubuntu3.1"},
+			upgradeRequired: true,
+		},
+		{
+			name:        "a fix of a later minor version is a plain upgrade",
+			version:     "2.4.1-1",
+			packageType: syftPkg.DebPkg,
+			namespace:   "debian:distro:debian:12",
+			fixes:       []string{"2.5.0-1", "3.0.1-1"},
+			expected:    []string{"2.5.0-1"},
+		},
+		{
+			name:            "a fix of an earlier minor version",
+			version:         "2.4.1-1",
+			packageType:     syftPkg.DebPkg,
+			namespace:       "debian:distro:debian:12",
+			fixes:           []string{"2.3.9-1"},
+			expected:        []string{"2.3.9-1"},
+			upgradeRequired: true,
+		},
+		{
+			name:            "the minor versions of major version 0 are streams",
+			version:         "0.9.1-1",
+			packageType:     syftPkg.DebPkg,
+			namespace:       "debian:distro:debian:12",
+			fixes:           []string{"0.10.2-1"},
+			expected:        []string{"0.10.2-1"},
+			upgradeRequired: true,
+		},
+		{
+			name:        "epochs and rpm releases",
+			version:     "1:1.1.1k-7.el8_6",
+			packageType: syftPkg.RpmPkg,
+			namespace:   "redhat:distro:redhat:8",
+			fixes:       []string{"1:1.1.1k-9.el8_7", "1:3.0.7-1.el9"},
+			expected:    []string{"1:1.1.1k-9.el8_7"},
+		},
+		{
+			name:        "apk revisions",
+			version:     "3.0.8-r0",
+			packageType: syftPkg.ApkPkg,
+			namespace:   "alpine:distro:alpine:3.17",
+			fixes:       []string{"1.1.1t-r0", "3.0.8-r1"},
+			expected:    []string{"3.0.8-r1"},
+		},
+		{
+			name:        "a single fix of the installed stream",
+			version:     "2.4.57-2",
+			packageType: syftPkg.DebPkg,
+			namespace:   "debian:distro:debian:12",
+			fixes:       []string{"2.4.59-1~deb12u1"},
+			expected:    []string{"2.4.59-1~deb12u1"},
+		},
+		{
+			name:            "a single fix of another stream",
+			version:         "115.0-1",
+			packageType:     syftPkg.DebPkg,
+			namespace:       "debian:distro:debian:12",
+			fixes:           []string{"128.3.0esr-1~deb12u1"},
+			expected:        []string{"128.3.0esr-1~deb12u1"},
+			upgradeRequired: true,
+		},
+		{
+			name:        "language vulnerability data is left as is",
+			version:     "2.14.1",
+			packageType: syftPkg.JavaPkg,
+			namespace:   "github:language:java",
+			fixes:       []string{"2.12.2", "2.15.0"},
+			expected:    []string{"2.12.2", "2.15.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := selectStreamFix(match.Match{
+				Vulnerability: vulnerability.Vulnerability{
+					ID:        "CVE-2023-0001",
+					Namespace: tt.namespace,
+					Fix:       vulnerability.Fix{Versions: tt.fixes, State: grypeDb.FixedState},
+				},
+				Package: pkg.Package{ID: "p", Name: "openssl", Version: tt.version, Type: tt.packageType},
+			})
+			assert.Equal(t, tt.expected, m.Vulnerability.Fix.Versions)
+			assert.Equal(t, tt.upgradeRequired, m.Vulnerability.Fix.UpgradeRequired)
+		})
+	}
+}
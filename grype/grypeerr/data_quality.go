@@ -0,0 +1,44 @@
+package grypeerr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DataQualityProblem is a degradation of the results of a scan that is only logged outside of strict mode, such as a
+// package that could not be matched or a matcher that failed.
+type DataQualityProblem struct {
+	// Kind identifies the problem (e.g. "unparseable-version" or "matcher-failed").
+	Kind string `json:"kind"`
+	// Subject is what the problem is about, typically a package.
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+func (p DataQualityProblem) String() string {
+	return fmt.Sprintf("[%s] %s: %s", p.Kind, p.Subject, p.Message)
+}
+
+// DataQualityError fails a scan in strict mode, listing the problems that could have made the results incomplete.
+type DataQualityError struct {
+	Problems []DataQualityProblem
+}
+
+func (e *DataQualityError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "strict mode: found %d data-quality problem(s)", len(e.Problems))
+	for _, p := range e.Problems {
+		sb.WriteString("\n  - ")
+		sb.WriteString(p.String())
+	}
+	return sb.String()
+}
+
+// IsScanFailure tells whether the error fails a scan because of its results (a match above the severity threshold,
+// a license policy violation, or data-quality problems in strict mode), in which case the results were still found and
+// can be reported.
+func IsScanFailure(err error) bool {
+	var dataQualityErr *DataQualityError
+	return errors.Is(err, ErrAboveSeverityThreshold) || errors.Is(err, ErrLicensePolicyViolation) || errors.As(err, &dataQualityErr)
+}
@@ -3,4 +3,7 @@ package grypeerr
 var (
 	// ErrAboveSeverityThreshold indicates when a vulnerability severity is discovered that is above the given --fail-on severity value
 	ErrAboveSeverityThreshold = NewExpectedErr("discovered vulnerabilities at or above the severity threshold")
+
+	// ErrLicensePolicyViolation indicates when a package license violates the license policy
+	ErrLicensePolicyViolation = NewExpectedErr("discovered packages violating the license policy")
 )
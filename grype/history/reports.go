@@ -0,0 +1,114 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/internal/log"
+)
+
+// FromReports reads the scans from a directory (searched recursively) of grype JSON reports, from the oldest. Files
+// that are not grype JSON reports are skipped.
+func FromReports(dir string) ([]Scan, error) {
+	var scans []Scan
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read report %q: %w", path, err)
+		}
+		scan, ok := fromReport(contents)
+		if !ok {
+			log.WithFields("path", path).Debug("skipping file that is not a grype JSON report")
+			return nil
+		}
+		scans = append(scans, scan)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read reports from %q: %w", dir, err)
+	}
+
+	sort.SliceStable(scans, func(i, j int) bool {
+		return scans[i].Timestamp.Before(scans[j].Timestamp)
+	})
+	return scans, nil
+}
+
+func fromReport(contents []byte) (Scan, bool) {
+	var doc models.Document
+	if err := json.Unmarshal(contents, &doc); err != nil || doc.Descriptor.Timestamp == "" {
+		return Scan{}, false
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, doc.Descriptor.Timestamp)
+	if err != nil {
+		return Scan{}, false
+	}
+
+	scan := Scan{Timestamp: timestamp}
+	if doc.Source != nil {
+		scan.Target, scan.TargetDigest = reportTarget(doc.Source.Target)
+	}
+	if db, ok := doc.Descriptor.VulnerabilityDBStatus.(map[string]any); ok {
+		if built, ok := db["built"].(string); ok {
+			scan.DBBuilt, _ = time.Parse(time.RFC3339Nano, built)
+		}
+		if checksum, ok := db["checksum"].(string); ok {
+			scan.DBChecksum = checksum
+		}
+		if schema, ok := db["schemaVersion"].(float64); ok {
+			scan.DBSchema = int(schema)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range doc.Matches {
+		f := Finding{
+			Vulnerability: m.Vulnerability.ID,
+			Package:       m.Artifact.Name,
+			Version:       m.Artifact.Version,
+			Type:          string(m.Artifact.Type),
+			Severity:      m.Vulnerability.Severity,
+		}
+		if len(m.Artifact.Locations) > 0 {
+			f.Location = m.Artifact.Locations[0].RealPath
+		}
+		f.Fingerprint = fingerprint(f)
+		if seen[f.Fingerprint] {
+			continue
+		}
+		seen[f.Fingerprint] = true
+		scan.Findings = append(scan.Findings, f)
+	}
+	return scan, true
+}
+
+// reportTarget returns the name and digest of the scanned source of a report: the user input and manifest digest
+// of images, and the path of directories and files.
+func reportTarget(target any) (string, string) {
+	switch t := target.(type) {
+	case string:
+		return t, ""
+	case map[string]any:
+		name, _ := t["userInput"].(string)
+		digest, _ := t["manifestDigest"].(string)
+		if digest == "" {
+			digest, _ = t["imageID"].(string)
+		}
+		return name, digest
+	}
+	return "", ""
+}
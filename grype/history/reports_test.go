@@ -0,0 +1,32 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromReports(t *testing.T) {
+	scans, err := FromReports("test-fixtures/reports")
+	require.NoError(t, err)
+	require.Len(t, scans, 2, "files that are not grype reports are skipped")
+
+	f := finding("CVE-2024-0001", "3.1.4")
+	f.Severity = "High"
+	assert.Equal(t, Scan{
+		Target:       "alpine:3.19",
+		TargetDigest: "sha256:manifest",
+		Timestamp:    time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+		DBBuilt:      time.Date(2024, 3, 3, 1, 31, 43, 0, time.UTC),
+		DBSchema:     5,
+		DBChecksum:   "sha256:db",
+		Findings:     []Finding{f},
+	}, scans[0])
+
+	assert.Equal(t, "./app", scans[1].Target)
+	assert.Empty(t, scans[1].TargetDigest)
+	assert.True(t, scans[1].Timestamp.Equal(time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)))
+	assert.Empty(t, scans[1].Findings)
+}
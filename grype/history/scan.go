@@ -0,0 +1,74 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/source"
+)
+
+// NewFindings returns the findings of the matches, with the severities from the metadata provider.
+func NewFindings(matches match.Matches, metadataProvider vulnerability.MetadataProvider) []Finding {
+	var findings []Finding
+	seen := make(map[string]bool)
+	for _, m := range matches.Sorted() {
+		f := Finding{
+			Vulnerability: m.Vulnerability.ID,
+			Package:       m.Package.Name,
+			Version:       m.Package.Version,
+			Type:          string(m.Package.Type),
+			Location:      packageLocation(m.Package),
+			Severity:      "Unknown",
+		}
+		f.Fingerprint = fingerprint(f)
+		if seen[f.Fingerprint] {
+			continue
+		}
+		seen[f.Fingerprint] = true
+
+		var metadata *vulnerability.Metadata
+		if metadataProvider != nil {
+			metadata, _ = metadataProvider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace)
+		}
+		if metadata = m.WithSeverityOverride(metadata); metadata != nil && metadata.Severity != "" {
+			f.Severity = metadata.Severity
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// fingerprint identifies the finding independent of details that may change between DB builds (e.g. the severity
+// or the namespace that reported it) and of the version of the package, so that the same finding can be followed
+// across scans, including when the package is upgraded to a version that is still vulnerable.
+func fingerprint(f Finding) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{f.Vulnerability, f.Package, f.Type, f.Location}, "\x00")))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// packageLocation returns the first path the package was found at, if any.
+func packageLocation(p pkg.Package) string {
+	for _, l := range p.Locations.ToSlice() {
+		return l.RealPath
+	}
+	return ""
+}
+
+// TargetDigest returns the digest that identifies the content of the scanned target: the manifest digest (or else
+// the ID) of images, and nothing for other sources.
+func TargetDigest(src *source.Description) string {
+	if src == nil {
+		return ""
+	}
+	if m, ok := src.Metadata.(source.ImageMetadata); ok {
+		if m.ManifestDigest != "" {
+			return m.ManifestDigest
+		}
+		return m.ID
+	}
+	return ""
+}
@@ -0,0 +1,288 @@
+/*
+Package history keeps a local record of every scan (the target, the vulnerability DB build used, and the findings)
+so that questions such as "when did this CVE first appear for this image" can be answered without external tooling.
+*/
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Scan is a single recorded scan of a target.
+type Scan struct {
+	ID           uint      `json:"id"`
+	Target       string    `json:"target"`
+	TargetDigest string    `json:"targetDigest,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+	DBBuilt      time.Time `json:"dbBuilt"`
+	DBSchema     int       `json:"dbSchemaVersion"`
+	DBChecksum   string    `json:"dbChecksum,omitempty"`
+	Findings     []Finding `json:"findings,omitempty"`
+}
+
+// Finding is a single vulnerability reported by a scan.
+type Finding struct {
+	Fingerprint   string `json:"fingerprint"`
+	Vulnerability string `json:"vulnerability"`
+	Package       string `json:"package"`
+	Version       string `json:"version"`
+	Type          string `json:"type"`
+	// Location is the path the package was found at, which tells apart the packages of the same name
+	Location string `json:"location,omitempty"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// Occurrence summarizes the scans of a target that reported a finding.
+type Occurrence struct {
+	Target          string    `json:"target"`
+	Vulnerability   string    `json:"vulnerability"`
+	Package         string    `json:"package"`
+	Version         string    `json:"version"`
+	Type            string    `json:"type"`
+	FirstSeen       time.Time `json:"firstSeen"`
+	FirstSeenDigest string    `json:"firstSeenDigest,omitempty"`
+	LastSeen        time.Time `json:"lastSeen"`
+	LastSeenDigest  string    `json:"lastSeenDigest,omitempty"`
+	Scans           int       `json:"scans"`
+}
+
+type scanModel struct {
+	ID           uint   `gorm:"primaryKey"`
+	Target       string `gorm:"index"`
+	TargetDigest string
+	Timestamp    time.Time `gorm:"index"`
+	DBBuilt      time.Time
+	DBSchema     int
+	DBChecksum   string
+	Findings     []findingModel `gorm:"foreignKey:ScanID;constraint:OnDelete:CASCADE"`
+}
+
+func (scanModel) TableName() string {
+	return "scans"
+}
+
+type findingModel struct {
+	ID            uint `gorm:"primaryKey"`
+	ScanID        uint `gorm:"index"`
+	Fingerprint   string
+	Vulnerability string `gorm:"index"`
+	Package       string
+	Version       string
+	Type          string
+	Location      string
+	Severity      string
+}
+
+func (findingModel) TableName() string {
+	return "findings"
+}
+
+// Store is the SQLite database of recorded scans.
+type Store struct {
+	db *gorm.DB
+}
+
+// Open opens (creating when needed) the history database at the given path.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create scan history directory: %w", err)
+	}
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?_pragma=foreign_keys(1)", path)), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open scan history %q: %w", path, err)
+	}
+	if err := db.AutoMigrate(&scanModel{}, &findingModel{}); err != nil {
+		return nil, fmt.Errorf("unable to migrate scan history %q: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Record stores the scan and its findings, returning the ID of the recorded scan.
+func (s *Store) Record(scan Scan) (uint, error) {
+	m := scanModel{
+		Target:       scan.Target,
+		TargetDigest: scan.TargetDigest,
+		Timestamp:    scan.Timestamp.UTC(),
+		DBBuilt:      scan.DBBuilt.UTC(),
+		DBSchema:     scan.DBSchema,
+		DBChecksum:   scan.DBChecksum,
+	}
+	for _, f := range scan.Findings {
+		m.Findings = append(m.Findings, findingModel{
+			Fingerprint:   f.Fingerprint,
+			Vulnerability: f.Vulnerability,
+			Package:       f.Package,
+			Version:       f.Version,
+			Type:          f.Type,
+			Location:      f.Location,
+			Severity:      f.Severity,
+		})
+	}
+	if err := s.db.Create(&m).Error; err != nil {
+		return 0, fmt.Errorf("unable to record scan of %q: %w", scan.Target, err)
+	}
+	return m.ID, nil
+}
+
+// Scans returns the recorded scans (without their findings) from the most recent, optionally of the given target
+// only.
+func (s *Store) Scans(target string) ([]Scan, error) {
+	var models []scanModel
+	query := s.db.Order("timestamp desc, id desc")
+	if target != "" {
+		query = query.Where("target = ?", target)
+	}
+	if err := query.Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("unable to read scan history: %w", err)
+	}
+
+	scans := make([]Scan, 0, len(models))
+	for _, m := range models {
+		scans = append(scans, m.toScan())
+	}
+	return scans, nil
+}
+
+// ScansWithFindings returns the recorded scans with their findings from the oldest, optionally of the given target
+// only.
+func (s *Store) ScansWithFindings(target string) ([]Scan, error) {
+	var models []scanModel
+	query := s.db.Preload("Findings").Order("timestamp, id")
+	if target != "" {
+		query = query.Where("target = ?", target)
+	}
+	if err := query.Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("unable to read scan history: %w", err)
+	}
+
+	scans := make([]Scan, 0, len(models))
+	for _, m := range models {
+		scans = append(scans, m.toScanWithFindings())
+	}
+	return scans, nil
+}
+
+// Scan returns the recorded scan with the given ID, with its findings.
+func (s *Store) Scan(id uint) (*Scan, error) {
+	var m scanModel
+	err := s.db.Preload("Findings").First(&m, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read scan %d: %w", id, err)
+	}
+	scan := m.toScanWithFindings()
+	return &scan, nil
+}
+
+// Occurrences returns when the vulnerability was first and last reported for each target (optionally the given
+// target only) and package, ordered by when each was first seen. The version is the version last reported, as the
+// package may have been upgraded without fixing the vulnerability.
+func (s *Store) Occurrences(vulnerability, target string) ([]Occurrence, error) {
+	type row struct {
+		Target        string
+		TargetDigest  string
+		Timestamp     time.Time
+		Vulnerability string
+		Package       string
+		Version       string
+		Type          string
+		Location      string
+	}
+
+	query := s.db.Table("findings").
+		Select("scans.target, scans.target_digest, scans.timestamp, findings.vulnerability, findings.package, findings.version, findings.type, findings.location").
+		Joins("JOIN scans ON scans.id = findings.scan_id").
+		Where("findings.vulnerability = ?", vulnerability).
+		Order("scans.timestamp, scans.id")
+	if target != "" {
+		query = query.Where("scans.target = ?", target)
+	}
+
+	var rows []row
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("unable to read scan history: %w", err)
+	}
+
+	byKey := make(map[string]*Occurrence)
+	var occurrences []*Occurrence
+	for _, r := range rows {
+		key := r.Target + "\x00" + fingerprint(Finding{Vulnerability: r.Vulnerability, Package: r.Package, Type: r.Type, Location: r.Location})
+		o, ok := byKey[key]
+		if !ok {
+			o = &Occurrence{
+				Target:          r.Target,
+				Vulnerability:   r.Vulnerability,
+				Package:         r.Package,
+				Version:         r.Version,
+				Type:            r.Type,
+				FirstSeen:       r.Timestamp,
+				FirstSeenDigest: r.TargetDigest,
+			}
+			byKey[key] = o
+			occurrences = append(occurrences, o)
+		}
+		o.Version = r.Version
+		o.LastSeen = r.Timestamp
+		o.LastSeenDigest = r.TargetDigest
+		o.Scans++
+	}
+
+	result := make([]Occurrence, 0, len(occurrences))
+	for _, o := range occurrences {
+		result = append(result, *o)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].FirstSeen.Before(result[j].FirstSeen)
+	})
+	return result, nil
+}
+
+func (m scanModel) toScan() Scan {
+	return Scan{
+		ID:           m.ID,
+		Target:       m.Target,
+		TargetDigest: m.TargetDigest,
+		Timestamp:    m.Timestamp,
+		DBBuilt:      m.DBBuilt,
+		DBSchema:     m.DBSchema,
+		DBChecksum:   m.DBChecksum,
+	}
+}
+
+func (m scanModel) toScanWithFindings() Scan {
+	scan := m.toScan()
+	for _, f := range m.Findings {
+		finding := Finding{
+			Vulnerability: f.Vulnerability,
+			Package:       f.Package,
+			Version:       f.Version,
+			Type:          f.Type,
+			Location:      f.Location,
+			Severity:      f.Severity,
+		}
+		// the findings recorded by older versions have fingerprints that include the version of the package
+		finding.Fingerprint = fingerprint(finding)
+		scan.Findings = append(scan.Findings, finding)
+	}
+	return scan
+}
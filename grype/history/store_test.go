@@ -0,0 +1,107 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+func finding(vuln, version string) Finding {
+	f := Finding{Vulnerability: vuln, Package: "openssl", Version: version, Type: "apk"}
+	f.Fingerprint = fingerprint(f)
+	return f
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.db")
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC)
+	}
+	scans := []Scan{
+		{Target: "alpine:3.19", TargetDigest: "sha256:1", Timestamp: day(1), DBBuilt: day(1), DBSchema: 5, Findings: []Finding{finding("CVE-2024-0001", "3.1.4")}},
+		{Target: "alpine:3.19", TargetDigest: "sha256:1", Timestamp: day(2), DBBuilt: day(2), DBSchema: 5, Findings: []Finding{finding("CVE-2024-0001", "3.1.4"), finding("CVE-2024-0002", "3.1.4")}},
+		{Target: "alpine:3.19", TargetDigest: "sha256:2", Timestamp: day(3), DBBuilt: day(3), DBSchema: 5, Findings: []Finding{finding("CVE-2024-0002", "3.1.5")}},
+		{Target: "alpine:3.20", TargetDigest: "sha256:3", Timestamp: day(4), DBBuilt: day(3), DBSchema: 5, Findings: []Finding{finding("CVE-2024-0002", "3.1.5")}},
+	}
+	for _, scan := range scans {
+		_, err := s.Record(scan)
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.Close())
+
+	// the history is kept between runs
+	s, err = Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	recorded, err := s.Scans("alpine:3.19")
+	require.NoError(t, err)
+	require.Len(t, recorded, 3)
+	assert.Equal(t, "sha256:2", recorded[0].TargetDigest, "the most recent scan is first")
+	assert.Empty(t, recorded[0].Findings)
+
+	scan, err := s.Scan(recorded[1].ID)
+	require.NoError(t, err)
+	require.NotNil(t, scan)
+	assert.Equal(t, scans[1].Findings, scan.Findings)
+	assert.Equal(t, day(2), scan.DBBuilt)
+
+	missing, err := s.Scan(100)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	occurrences, err := s.Occurrences("CVE-2024-0002", "")
+	require.NoError(t, err)
+	// the package upgraded to a version that is still vulnerable is the same occurrence, with the version last reported
+	assert.Equal(t, []Occurrence{
+		{
+			Target: "alpine:3.19", Vulnerability: "CVE-2024-0002", Package: "openssl", Version: "3.1.5", Type: "apk",
+			FirstSeen: day(2), FirstSeenDigest: "sha256:1", LastSeen: day(3), LastSeenDigest: "sha256:2", Scans: 2,
+		},
+		{
+			Target: "alpine:3.20", Vulnerability: "CVE-2024-0002", Package: "openssl", Version: "3.1.5", Type: "apk",
+			FirstSeen: day(4), FirstSeenDigest: "sha256:3", LastSeen: day(4), LastSeenDigest: "sha256:3", Scans: 1,
+		},
+	}, occurrences)
+
+	occurrences, err = s.Occurrences("CVE-2024-0001", "alpine:3.19")
+	require.NoError(t, err)
+	require.Len(t, occurrences, 1)
+	assert.Equal(t, day(1), occurrences[0].FirstSeen)
+	assert.Equal(t, day(2), occurrences[0].LastSeen)
+	assert.Equal(t, 2, occurrences[0].Scans)
+}
+
+func TestNewFindings(t *testing.T) {
+	p := pkg.Package{ID: "1", Name: "openssl", Version: "3.1.4", Type: syftPkg.ApkPkg}
+	other := pkg.Package{ID: "2", Name: "openssl", Version: "3.1.4", Type: syftPkg.ApkPkg}
+	matches := match.NewMatches(
+		match.Match{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0001", Namespace: "alpine:distro:alpine:3.19"}, Package: p},
+		// the same package found at another location is the same finding
+		match.Match{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0001", Namespace: "alpine:distro:alpine:3.19"}, Package: other},
+		match.Match{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0002", Namespace: "alpine:distro:alpine:3.19"}, Package: p, SeverityOverride: &match.SeverityOverride{Severity: "Low"}},
+	)
+
+	f1, f2 := finding("CVE-2024-0001", "3.1.4"), finding("CVE-2024-0002", "3.1.4")
+	f1.Severity, f2.Severity = "Unknown", "Low"
+	assert.Equal(t, []Finding{f1, f2}, NewFindings(matches, nil))
+}
+
+func TestTargetDigest(t *testing.T) {
+	assert.Equal(t, "sha256:m", TargetDigest(&source.Description{Metadata: source.ImageMetadata{ID: "sha256:i", ManifestDigest: "sha256:m"}}))
+	assert.Equal(t, "sha256:i", TargetDigest(&source.Description{Metadata: source.ImageMetadata{ID: "sha256:i"}}))
+	assert.Empty(t, TargetDigest(&source.Description{Metadata: source.DirectoryMetadata{Path: "."}}))
+	assert.Empty(t, TargetDigest(nil))
+}
@@ -0,0 +1,157 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TrendReport summarizes the findings reported across scans week by week, for security program reporting.
+type TrendReport struct {
+	Targets int         `json:"targets"`
+	Scans   int         `json:"scans"`
+	Weeks   []WeekTrend `json:"weeks"`
+	// MTTR is the time to remediate the findings that were fixed, overall and by severity.
+	MTTR           MTTR            `json:"mttr"`
+	MTTRBySeverity map[string]MTTR `json:"mttrBySeverity"`
+}
+
+// WeekTrend counts the findings that appeared (new) and disappeared (fixed) across the targets within a week, by
+// severity.
+type WeekTrend struct {
+	Week  string         `json:"week"` // the ISO week, e.g. "2024-W09"
+	Start time.Time      `json:"start"`
+	New   map[string]int `json:"new"`
+	Fixed map[string]int `json:"fixed"`
+}
+
+// MTTR is the mean and median time, in days, from the first scan that reported a finding to the first scan of the
+// same target that no longer did.
+type MTTR struct {
+	Fixed      int     `json:"fixed"`
+	MeanDays   float64 `json:"meanDays"`
+	MedianDays float64 `json:"medianDays"`
+}
+
+// Trend compares the consecutive scans of each target: a finding is new in the week of the first scan that reports
+// it, and fixed in the week of the first later scan of the same target that no longer does. A finding that comes back
+// after having been fixed is new again. Findings that are still open are neither fixed nor part of the MTTR.
+func Trend(scans []Scan) TrendReport {
+	ordered := append([]Scan(nil), scans...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Timestamp.Before(ordered[j].Timestamp)
+	})
+
+	type openFinding struct {
+		since    time.Time
+		severity string
+	}
+
+	weeks := make(map[time.Time]*WeekTrend)
+	week := func(t time.Time) *WeekTrend {
+		start := weekStart(t)
+		w, ok := weeks[start]
+		if !ok {
+			year, number := start.ISOWeek()
+			w = &WeekTrend{Week: fmt.Sprintf("%d-W%02d", year, number), Start: start, New: map[string]int{}, Fixed: map[string]int{}}
+			weeks[start] = w
+		}
+		return w
+	}
+
+	var all []time.Duration
+	bySeverity := make(map[string][]time.Duration)
+	open := make(map[string]map[string]openFinding)
+	for _, scan := range ordered {
+		w := week(scan.Timestamp)
+		previous := open[scan.Target]
+		current := make(map[string]openFinding)
+		for _, f := range scan.Findings {
+			severity := severityOf(f)
+			if o, ok := previous[f.Fingerprint]; ok {
+				// the severity may be re-rated while the finding is open
+				current[f.Fingerprint] = openFinding{since: o.since, severity: severity}
+				continue
+			}
+			if _, ok := current[f.Fingerprint]; ok {
+				continue
+			}
+			current[f.Fingerprint] = openFinding{since: scan.Timestamp, severity: severity}
+			w.New[severity]++
+		}
+		for fp, o := range previous {
+			if _, ok := current[fp]; ok {
+				continue
+			}
+			w.Fixed[o.severity]++
+			d := scan.Timestamp.Sub(o.since)
+			all = append(all, d)
+			bySeverity[o.severity] = append(bySeverity[o.severity], d)
+		}
+		open[scan.Target] = current
+	}
+
+	report := TrendReport{
+		Targets:        len(open),
+		Scans:          len(ordered),
+		Weeks:          []WeekTrend{},
+		MTTR:           newMTTR(all),
+		MTTRBySeverity: make(map[string]MTTR),
+	}
+	for severity, durations := range bySeverity {
+		report.MTTRBySeverity[severity] = newMTTR(durations)
+	}
+
+	if len(ordered) > 0 {
+		// every week within the period is reported, including the weeks without any change
+		last := weekStart(ordered[len(ordered)-1].Timestamp)
+		for start := weekStart(ordered[0].Timestamp); !start.After(last); start = start.AddDate(0, 0, 7) {
+			report.Weeks = append(report.Weeks, *week(start))
+		}
+	}
+	return report
+}
+
+func severityOf(f Finding) string {
+	if f.Severity == "" {
+		return "Unknown"
+	}
+	return f.Severity
+}
+
+// weekStart returns the start (Monday, in UTC) of the ISO week of the time.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func newMTTR(durations []time.Duration) MTTR {
+	if len(durations) == 0 {
+		return MTTR{}
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	return MTTR{
+		Fixed:      len(sorted),
+		MeanDays:   days(total / time.Duration(len(sorted))),
+		MedianDays: days(median),
+	}
+}
+
+func days(d time.Duration) float64 {
+	// rounded to the hour
+	return float64(d.Round(time.Hour)/time.Hour) / 24
+}
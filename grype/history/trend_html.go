@@ -0,0 +1,110 @@
+package history
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/anchore/grype/grype/presenter/models"
+)
+
+//go:embed trend.html.tmpl
+var trendTemplate string
+
+// the widest bar of the weekly chart, in pixels
+const maxBarWidth = 200
+
+type trendView struct {
+	Report     TrendReport
+	Severities []string
+	Weeks      []weekView
+	MTTR       []mttrView
+}
+
+type weekView struct {
+	WeekTrend
+	New        []int
+	Fixed      []int
+	NewWidth   int
+	FixedWidth int
+}
+
+type mttrView struct {
+	MTTR
+	Severity string
+}
+
+// WriteHTML writes the report as a standalone HTML page.
+func (r TrendReport) WriteHTML(w io.Writer) error {
+	tmpl, err := template.New("trend").Parse(trendTemplate)
+	if err != nil {
+		return fmt.Errorf("unable to parse trend report template: %w", err)
+	}
+	if err := tmpl.Execute(w, newTrendView(r)); err != nil {
+		return fmt.Errorf("unable to write trend report: %w", err)
+	}
+	return nil
+}
+
+func newTrendView(r TrendReport) trendView {
+	present := make(map[string]bool)
+	for _, w := range r.Weeks {
+		for severity := range w.New {
+			present[severity] = true
+		}
+		for severity := range w.Fixed {
+			present[severity] = true
+		}
+	}
+	var severities []string
+	for severity := range present {
+		severities = append(severities, severity)
+	}
+	sortSeverities(severities)
+
+	view := trendView{Report: r, Severities: severities}
+	busiest := 0
+	for _, w := range r.Weeks {
+		wv := weekView{WeekTrend: w}
+		var added, fixed int
+		for _, severity := range severities {
+			wv.New = append(wv.New, w.New[severity])
+			wv.Fixed = append(wv.Fixed, w.Fixed[severity])
+			added += w.New[severity]
+			fixed += w.Fixed[severity]
+		}
+		wv.NewWidth, wv.FixedWidth = added, fixed
+		busiest = max(busiest, added, fixed)
+		view.Weeks = append(view.Weeks, wv)
+	}
+	for i := range view.Weeks {
+		if busiest > 0 {
+			view.Weeks[i].NewWidth = view.Weeks[i].NewWidth * maxBarWidth / busiest
+			view.Weeks[i].FixedWidth = view.Weeks[i].FixedWidth * maxBarWidth / busiest
+		}
+	}
+
+	view.MTTR = append(view.MTTR, mttrView{MTTR: r.MTTR, Severity: "All"})
+	var rated []string
+	for severity := range r.MTTRBySeverity {
+		rated = append(rated, severity)
+	}
+	sortSeverities(rated)
+	for _, severity := range rated {
+		view.MTTR = append(view.MTTR, mttrView{MTTR: r.MTTRBySeverity[severity], Severity: severity})
+	}
+	return view
+}
+
+// sortSeverities orders the severities from the most severe.
+func sortSeverities(severities []string) {
+	sort.Slice(severities, func(i, j int) bool {
+		si, sj := models.SeverityScore(severities[i]), models.SeverityScore(severities[j])
+		if si != sj {
+			return si > sj
+		}
+		return severities[i] < severities[j]
+	})
+}
@@ -0,0 +1,101 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrend(t *testing.T) {
+	day := func(d int) time.Time {
+		// 2024-03-04 is a Monday
+		return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC)
+	}
+	high, critical := finding("CVE-2024-0001", "3.1.4"), finding("CVE-2024-0002", "3.1.4")
+	high.Severity, critical.Severity = "High", "Critical"
+	other := finding("CVE-2024-0003", "3.1.4")
+
+	report := Trend([]Scan{
+		// out of order scans are ordered by time
+		{Target: "b", Timestamp: day(20), Findings: []Finding{critical}},
+		{Target: "a", Timestamp: day(4), Findings: []Finding{high, critical}},
+		{Target: "a", Timestamp: day(6), Findings: []Finding{critical}},
+		{Target: "b", Timestamp: day(5), Findings: []Finding{critical, other}},
+		{Target: "a", Timestamp: day(19), Findings: nil},
+	})
+
+	assert.Equal(t, 2, report.Targets)
+	assert.Equal(t, 5, report.Scans)
+	assert.Equal(t, []WeekTrend{
+		{
+			Week:  "2024-W10",
+			Start: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+			New:   map[string]int{"High": 1, "Critical": 2, "Unknown": 1},
+			Fixed: map[string]int{"High": 1},
+		},
+		{
+			Week:  "2024-W11",
+			Start: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+			New:   map[string]int{},
+			Fixed: map[string]int{},
+		},
+		{
+			Week:  "2024-W12",
+			Start: time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC),
+			New:   map[string]int{},
+			Fixed: map[string]int{"Critical": 1, "Unknown": 1},
+		},
+	}, report.Weeks)
+
+	// fixed after 2 (high on a), 15 (critical on a) and 15 days (unknown on b)
+	assert.Equal(t, 3, report.MTTR.Fixed)
+	assert.InDelta(t, 32.0/3, report.MTTR.MeanDays, 0.05)
+	assert.Equal(t, 15.0, report.MTTR.MedianDays)
+	assert.Equal(t, map[string]MTTR{
+		"High":     {Fixed: 1, MeanDays: 2, MedianDays: 2},
+		"Critical": {Fixed: 1, MeanDays: 15, MedianDays: 15},
+		"Unknown":  {Fixed: 1, MeanDays: 15, MedianDays: 15},
+	}, report.MTTRBySeverity)
+}
+
+func TestTrend_reappearing(t *testing.T) {
+	f := finding("CVE-2024-0001", "3.1.4")
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+	report := Trend([]Scan{
+		{Target: "a", Timestamp: day(4), Findings: []Finding{f}},
+		{Target: "a", Timestamp: day(5)},
+		{Target: "a", Timestamp: day(6), Findings: []Finding{f}},
+	})
+	assert.Equal(t, map[string]int{"Unknown": 2}, report.Weeks[0].New)
+	assert.Equal(t, map[string]int{"Unknown": 1}, report.Weeks[0].Fixed)
+	assert.Equal(t, MTTR{Fixed: 1, MeanDays: 1, MedianDays: 1}, report.MTTR)
+}
+
+func TestTrend_empty(t *testing.T) {
+	report := Trend(nil)
+	assert.Empty(t, report.Weeks)
+	assert.NotNil(t, report.Weeks)
+	assert.Equal(t, MTTR{}, report.MTTR)
+}
+
+func TestTrend_versionChange(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC)
+	}
+	before, after := finding("CVE-2024-0001", "3.1.4"), finding("CVE-2024-0001", "3.1.5")
+
+	// upgrading the package to a version that is still vulnerable does not fix the finding
+	report := Trend([]Scan{
+		{Target: "a", Timestamp: day(4), Findings: []Finding{before}},
+		{Target: "a", Timestamp: day(6), Findings: []Finding{after}},
+		{Target: "a", Timestamp: day(14), Findings: nil},
+	})
+
+	assert.Equal(t, map[string]int{"Unknown": 1}, report.Weeks[0].New)
+	assert.Empty(t, report.Weeks[0].Fixed)
+	assert.Equal(t, map[string]int{"Unknown": 1}, report.Weeks[1].Fixed)
+	assert.Equal(t, MTTR{Fixed: 1, MeanDays: 10, MedianDays: 10}, report.MTTR)
+}
@@ -0,0 +1,109 @@
+package ignore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// dependabotAlert is the subset of an alert from the GitHub "list Dependabot alerts" API needed to ignore it
+type dependabotAlert struct {
+	Number           int    `json:"number"`
+	State            string `json:"state"`
+	DismissedReason  string `json:"dismissed_reason"`
+	DismissedComment string `json:"dismissed_comment"`
+	Dependency       struct {
+		Package      dependabotPackage `json:"package"`
+		ManifestPath string            `json:"manifest_path"`
+	} `json:"dependency"`
+	SecurityAdvisory struct {
+		GHSAID string `json:"ghsa_id"`
+		CVEID  string `json:"cve_id"`
+	} `json:"security_advisory"`
+}
+
+type dependabotPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// dependabotLanguages maps the Dependabot package ecosystems to package languages
+var dependabotLanguages = map[string]syftPkg.Language{
+	"composer": syftPkg.PHP,
+	"go":       syftPkg.Go,
+	"maven":    syftPkg.Java,
+	"npm":      syftPkg.JavaScript,
+	"nuget":    syftPkg.Dotnet,
+	"pip":      syftPkg.Python,
+	"pub":      syftPkg.Dart,
+	"rubygems": syftPkg.Ruby,
+	"rust":     syftPkg.Rust,
+	"swift":    syftPkg.Swift,
+}
+
+func decodeDependabot(reader io.Reader) ([]match.IgnoreRule, error) {
+	var alerts []dependabotAlert
+	if err := json.NewDecoder(reader).Decode(&alerts); err != nil {
+		return nil, fmt.Errorf("unable to parse dependabot alerts: %w", err)
+	}
+
+	var rules []match.IgnoreRule
+	for _, alert := range alerts {
+		if alert.State != "dismissed" && alert.State != "auto_dismissed" {
+			continue
+		}
+
+		p := alert.Dependency.Package
+		language, ok := dependabotLanguages[strings.ToLower(p.Ecosystem)]
+		if !ok || p.Name == "" {
+			log.WithFields("alert", alert.Number, "ecosystem", p.Ecosystem).Warn("unable to import dependabot alert for unsupported ecosystem")
+			continue
+		}
+
+		name := p.Name
+		if language == syftPkg.Java {
+			// maven packages are named "group:artifact" by dependabot, while only the artifact is the package name
+			if _, artifact, ok := strings.Cut(name, ":"); ok {
+				name = artifact
+			}
+		}
+
+		reason := "dismissed in dependabot"
+		if alert.DismissedReason != "" {
+			reason += " as " + alert.DismissedReason
+		}
+		if comment := singleLine(alert.DismissedComment); comment != "" {
+			reason += ": " + comment
+		}
+
+		// the alert is of the dependency of a single manifest, relative to the root of the repository (the alerts do not
+		// tell the installed version)
+		var location string
+		if alert.Dependency.ManifestPath != "" {
+			location = "/" + strings.TrimPrefix(alert.Dependency.ManifestPath, "/")
+		}
+
+		// the same advisory may be reported by the GHSA ID or the CVE ID depending on the vulnerability data source
+		for _, id := range []string{alert.SecurityAdvisory.GHSAID, alert.SecurityAdvisory.CVEID} {
+			if id == "" {
+				continue
+			}
+			rules = append(rules, match.IgnoreRule{
+				Vulnerability: id,
+				Reason:        reason,
+				Package: match.IgnoreRulePackage{
+					Name:     regexp.QuoteMeta(name),
+					Language: string(language),
+					Location: location,
+				},
+			})
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,303 @@
+package ignore
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// dependencyCheckSuppressions is an OWASP Dependency-Check suppression file (any version of the schema)
+type dependencyCheckSuppressions struct {
+	Suppress []dependencyCheckSuppress `xml:"suppress"`
+}
+
+// dependencyCheckSuppress selects the dependencies with packageUrl, gav, filePath or sha1 and then the
+// vulnerabilities to suppress for them with cve, vulnerabilityName, cpe or cvssBelow
+type dependencyCheckSuppress struct {
+	Until             string                   `xml:"until,attr"`
+	Notes             string                   `xml:"notes"`
+	PackageURL        []dependencyCheckPattern `xml:"packageUrl"`
+	GAV               []dependencyCheckPattern `xml:"gav"`
+	FilePath          []dependencyCheckPattern `xml:"filePath"`
+	SHA1              []string                 `xml:"sha1"`
+	CPE               []dependencyCheckPattern `xml:"cpe"`
+	CVE               []string                 `xml:"cve"`
+	VulnerabilityName []dependencyCheckPattern `xml:"vulnerabilityName"`
+	CVSSBelow         []string                 `xml:"cvssBelow"`
+}
+
+type dependencyCheckPattern struct {
+	Regex bool   `xml:"regex,attr"`
+	Value string `xml:",chardata"`
+}
+
+func decodeDependencyCheck(reader io.Reader) ([]match.IgnoreRule, error) {
+	var doc dependencyCheckSuppressions
+	if err := xml.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unable to parse dependency-check suppressions: %w", err)
+	}
+
+	var rules []match.IgnoreRule
+	for idx, s := range doc.Suppress {
+		if expired(s.Until) {
+			log.WithFields("suppression", idx, "until", s.Until).Debug("skipping expired dependency-check suppression")
+			continue
+		}
+
+		suppressed, err := s.toRules()
+		if err != nil {
+			log.WithFields("suppression", idx).Warnf("unable to import dependency-check suppression: %v", err)
+		}
+		rules = append(rules, suppressed...)
+	}
+	return rules, nil
+}
+
+// toRules returns a rule for each vulnerability and supported package selector of the suppression. The selectors that
+// cannot be expressed as ignore rules are reported in the error, along with the rules of the other selectors.
+func (s dependencyCheckSuppress) toRules() ([]match.IgnoreRule, error) {
+	var ids []string
+	for _, cve := range s.CVE {
+		ids = append(ids, strings.TrimSpace(cve))
+	}
+	for _, name := range s.VulnerabilityName {
+		if name.Regex {
+			return nil, fmt.Errorf("regular expressions for vulnerability names are not supported")
+		}
+		ids = append(ids, strings.TrimSpace(name.Value))
+	}
+	if len(ids) == 0 {
+		// suppressing by CPE or CVSS score would ignore vulnerabilities that were never reviewed in grype
+		return nil, fmt.Errorf("only suppressions by cve or vulnerabilityName are supported")
+	}
+
+	packages, err := s.rulePackages()
+	if len(packages) == 0 {
+		return nil, err
+	}
+
+	reason := "suppressed in dependency-check"
+	if notes := singleLine(s.Notes); notes != "" {
+		reason = notes
+	}
+
+	var rules []match.IgnoreRule
+	for _, p := range packages {
+		for _, id := range ids {
+			rules = append(rules, match.IgnoreRule{
+				Vulnerability: id,
+				Reason:        reason,
+				Package:       p,
+			})
+		}
+	}
+	return rules, err
+}
+
+// rulePackages returns the package criteria for each dependency selector of the suppression (a single empty criteria
+// when the suppression has no selector, since it is optional), and an error for the selectors that are not supported
+func (s dependencyCheckSuppress) rulePackages() ([]match.IgnoreRulePackage, error) {
+	if len(s.PackageURL) == 0 && len(s.GAV) == 0 && len(s.FilePath) == 0 && len(s.SHA1) == 0 {
+		return []match.IgnoreRulePackage{{}}, nil
+	}
+
+	var packages []match.IgnoreRulePackage
+	var errs []error
+	for _, purl := range s.PackageURL {
+		p, err := packageFromPURLSelector(purl)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		packages = append(packages, p)
+	}
+	for _, gav := range s.GAV {
+		p, err := packageFromGAVSelector(gav)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		packages = append(packages, p)
+	}
+	if len(s.FilePath) > 0 || len(s.SHA1) > 0 {
+		// these refer to files on the machine that dependency-check ran on, not to locations within the scan target
+		errs = append(errs, fmt.Errorf("suppressions by filePath or sha1 are not supported"))
+	}
+	return packages, errors.Join(errs...)
+}
+
+// packageFromPURLSelector returns the package criteria for a packageUrl selector. A regular expression is supported
+// when its type and namespace are literal and its version is either literal or matches any version.
+func packageFromPURLSelector(selector dependencyCheckPattern) (match.IgnoreRulePackage, error) {
+	value := strings.TrimSpace(selector.Value)
+	if !selector.Regex {
+		return packageFromPURL(value)
+	}
+
+	unsupported := fmt.Errorf("unsupported regular expression for package URLs %q", value)
+	pattern := anchorless(value)
+	path, version, found := cutTopLevel(pattern, '@')
+	segments := splitTopLevel(path, '/')
+	if len(segments) < 2 {
+		return match.IgnoreRulePackage{}, unsupported
+	}
+	scheme, ok := literal(segments[0])
+	if !ok || !strings.HasPrefix(scheme, "pkg:") {
+		return match.IgnoreRulePackage{}, unsupported
+	}
+	var namespace []string
+	for _, segment := range segments[1 : len(segments)-1] {
+		ns, ok := literal(segment)
+		if !ok {
+			return match.IgnoreRulePackage{}, unsupported
+		}
+		namespace = append(namespace, ns)
+	}
+	name := segments[len(segments)-1]
+	if matchesAnything(name) {
+		// the namespace is not part of the package criteria, so this would ignore every package of the type
+		return match.IgnoreRulePackage{}, unsupported
+	}
+
+	// build the package URL of a package of the same type and namespace to reuse the criteria of literal package URLs
+	purl := scheme + "/" + strings.Join(append(namespace, "placeholder"), "/")
+	p, err := packageFromPURL(purl)
+	if err != nil {
+		return match.IgnoreRulePackage{}, unsupported
+	}
+	p.Name = strings.TrimSuffix(p.Name, "placeholder") + name
+	if found {
+		if p.Version, ok = versionSelector(version); !ok {
+			return match.IgnoreRulePackage{}, unsupported
+		}
+	}
+	return p, nil
+}
+
+// packageFromGAVSelector returns the package criteria for a gav (maven group:artifact:version) selector. A regular
+// expression is supported when its group is literal and its version is either literal or matches any version.
+func packageFromGAVSelector(selector dependencyCheckPattern) (match.IgnoreRulePackage, error) {
+	value := strings.TrimSpace(selector.Value)
+	var parts []string
+	if selector.Regex {
+		parts = splitTopLevel(anchorless(value), ':')
+	} else {
+		for _, part := range strings.Split(value, ":") {
+			parts = append(parts, regexp.QuoteMeta(part))
+		}
+	}
+	if len(parts) < 2 {
+		return match.IgnoreRulePackage{}, fmt.Errorf("invalid maven coordinates %q", value)
+	}
+
+	unsupported := fmt.Errorf("unsupported regular expression for maven coordinates %q", value)
+	if _, ok := literal(parts[0]); !ok || matchesAnything(parts[1]) {
+		return match.IgnoreRulePackage{}, unsupported
+	}
+	p := match.IgnoreRulePackage{
+		Name:     parts[1],
+		Language: string(syftPkg.Java),
+	}
+	if len(parts) > 2 {
+		var ok bool
+		if p.Version, ok = versionSelector(strings.Join(parts[2:], ":")); !ok {
+			return match.IgnoreRulePackage{}, unsupported
+		}
+	}
+	return p, nil
+}
+
+// versionSelector returns the exact version for a version pattern, which is empty when the pattern matches any version
+func versionSelector(pattern string) (string, bool) {
+	if matchesAnything(pattern) {
+		return "", true
+	}
+	return literal(pattern)
+}
+
+// anchorless removes the anchors of a pattern, since dependency-check matches the whole value anyway
+func anchorless(pattern string) string {
+	pattern = strings.TrimPrefix(pattern, "^")
+	if strings.HasSuffix(pattern, "$") && !strings.HasSuffix(pattern, `\$`) {
+		pattern = strings.TrimSuffix(pattern, "$")
+	}
+	return pattern
+}
+
+// literal returns the string matched by a pattern that matches a single string
+func literal(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	switch re = re.Simplify(); re.Op {
+	case syntax.OpEmptyMatch:
+		return "", true
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return "", false
+		}
+		return string(re.Rune), true
+	default:
+		return "", false
+	}
+}
+
+// matchesAnything tells whether a pattern matches any string (or any non-empty string)
+func matchesAnything(pattern string) bool {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	re = re.Simplify()
+	if re.Op != syntax.OpStar && re.Op != syntax.OpPlus {
+		return false
+	}
+	switch re.Sub[0].Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return true
+	default:
+		return false
+	}
+}
+
+// cutTopLevel slices a pattern around the first separator that is not escaped nor within a group or character class
+func cutTopLevel(pattern string, sep byte) (before, after string, found bool) {
+	parts := splitTopLevel(pattern, sep)
+	if len(parts) == 1 {
+		return pattern, "", false
+	}
+	return parts[0], pattern[len(parts[0])+1:], true
+}
+
+// splitTopLevel slices a pattern around each separator that is not escaped nor within a group or character class
+func splitTopLevel(pattern string, sep byte) []string {
+	var parts []string
+	depth, start, inClass := 0, 0, false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, pattern[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, pattern[start:])
+}
@@ -0,0 +1,157 @@
+// Package ignore translates the ignore (or suppression) files of other tools into grype ignore rules, so that the
+// triage done with another scanner carries over to grype.
+package ignore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/packageurl-go"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// Format is a supported ignore file format.
+type Format string
+
+const (
+	// TrivyFormat is a Trivy ".trivyignore" file, either the plain list of IDs or the YAML (".trivyignore.yaml") form.
+	TrivyFormat Format = "trivy"
+
+	// DependabotFormat is a JSON export of Dependabot alerts from the GitHub API (only dismissed alerts are imported).
+	DependabotFormat Format = "dependabot"
+
+	// DependencyCheckFormat is an OWASP Dependency-Check suppression XML file.
+	DependencyCheckFormat Format = "dependency-check"
+)
+
+// AllFormats lists the supported formats.
+var AllFormats = []Format{TrivyFormat, DependabotFormat, DependencyCheckFormat}
+
+// now is used to decide whether entries with an expiry date still apply
+var now = time.Now
+
+// Import reads the ignore file referenced by the given value and returns the equivalent ignore rules. The value is a
+// path to the file, optionally prefixed with the format (e.g. "trivy=.trivyignore"); without a prefix the format is
+// detected from the file name and contents.
+func Import(reference string) ([]match.IgnoreRule, error) {
+	format, path := parseReference(reference)
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read ignore file: %w", err)
+	}
+
+	if format == "" {
+		format = detectFormat(path, contents)
+	}
+
+	return Decode(format, bytes.NewReader(contents))
+}
+
+// Decode returns the ignore rules equivalent to the given ignore file contents. Entries that have expired or that
+// cannot be expressed as grype ignore rules are left out (and logged), since ignoring more than the original entry
+// did would hide real findings.
+func Decode(format Format, reader io.Reader) ([]match.IgnoreRule, error) {
+	switch format {
+	case TrivyFormat:
+		return decodeTrivy(reader)
+	case DependabotFormat:
+		return decodeDependabot(reader)
+	case DependencyCheckFormat:
+		return decodeDependencyCheck(reader)
+	default:
+		return nil, fmt.Errorf("unsupported ignore file format %q (options: %v)", format, AllFormats)
+	}
+}
+
+func parseReference(reference string) (Format, string) {
+	if prefix, path, ok := strings.Cut(reference, "="); ok {
+		for _, f := range AllFormats {
+			if strings.EqualFold(prefix, string(f)) {
+				return f, path
+			}
+		}
+	}
+	return "", reference
+}
+
+func detectFormat(path string, contents []byte) Format {
+	name := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.Contains(name, "trivyignore"):
+		return TrivyFormat
+	case strings.HasSuffix(name, ".xml"):
+		return DependencyCheckFormat
+	case strings.HasSuffix(name, ".json"):
+		return DependabotFormat
+	}
+
+	trimmed := bytes.TrimSpace(contents)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return DependencyCheckFormat
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return DependabotFormat
+	default:
+		return TrivyFormat
+	}
+}
+
+// expired reports whether an entry that applies until the given date (inclusive) no longer applies. Dates that
+// cannot be parsed are treated as not expired, which matches how the original tools treat entries without a date.
+func expired(date string) bool {
+	date = strings.TrimSpace(date)
+	if date == "" {
+		return false
+	}
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		return !now().Before(t)
+	}
+	// dependency-check dates may carry a zone designator without a time (e.g. "2020-01-01Z")
+	if t, err := time.Parse("2006-01-02", strings.TrimSuffix(date, "Z")); err == nil {
+		return !now().Before(t.AddDate(0, 0, 1))
+	}
+	return false
+}
+
+// packageFromPURL returns the ignore rule package criteria matching the package described by the given package URL.
+func packageFromPURL(purl string) (match.IgnoreRulePackage, error) {
+	p, err := packageurl.FromString(purl)
+	if err != nil {
+		return match.IgnoreRulePackage{}, fmt.Errorf("invalid package URL %q: %w", purl, err)
+	}
+
+	name := p.Name
+	if p.Namespace != "" {
+		switch p.Type {
+		case packageurl.TypeNPM, packageurl.TypeGolang, packageurl.TypeComposer, packageurl.TypeSwift:
+			// these ecosystems include the namespace in the package name
+			name = p.Namespace + "/" + p.Name
+		}
+	}
+
+	rulePackage := match.IgnoreRulePackage{
+		Name:    regexp.QuoteMeta(name),
+		Version: p.Version,
+	}
+	// prefer the language over the package type since a single language may be cataloged as several package types
+	// (e.g. java archives and jenkins plugins for maven packages)
+	if language := syftPkg.LanguageFromPURL(purl); language != syftPkg.UnknownLanguage {
+		rulePackage.Language = string(language)
+	} else if t := syftPkg.TypeFromPURL(purl); t != syftPkg.UnknownPkg {
+		rulePackage.Type = string(t)
+	}
+	return rulePackage, nil
+}
+
+// singleLine collapses all whitespace so multi-line notes read well as a rule reason.
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
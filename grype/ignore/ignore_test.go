@@ -0,0 +1,378 @@
+package ignore
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/file"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func withNow(t *testing.T, when string) {
+	t.Helper()
+	original := now
+	parsed, err := time.Parse(time.RFC3339, when)
+	require.NoError(t, err)
+	now = func() time.Time { return parsed }
+	t.Cleanup(func() { now = original })
+}
+
+func vulnerabilityIDs(rules []match.IgnoreRule) []string {
+	var ids []string
+	for _, r := range rules {
+		ids = append(ids, r.Vulnerability)
+	}
+	return ids
+}
+
+func TestImport(t *testing.T) {
+	withNow(t, "2024-06-01T00:00:00Z")
+
+	tests := []struct {
+		reference string
+		wantIDs   []string
+		wantErr   require.ErrorAssertionFunc
+	}{
+		{
+			reference: "test-fixtures/.trivyignore",
+			wantIDs:   []string{"CVE-2022-40897", "GHSA-xxxx-yyyy-zzzz", "CVE-2024-1111"},
+		},
+		{
+			reference: "test-fixtures/.trivyignore.yaml",
+			wantIDs:   []string{"CVE-2022-40897", "CVE-2023-2650", "CVE-2023-4807", "CVE-2023-4807"},
+		},
+		{
+			reference: "test-fixtures/dependabot-alerts.json",
+			wantIDs:   []string{"GHSA-67hx-6x53-jw92", "CVE-2023-45133", "GHSA-3gh6-v5v9-6v9j"},
+		},
+		{
+			reference: "test-fixtures/dependency-check-suppressions.xml",
+			wantIDs:   []string{"CVE-2023-26048", "CVE-2023-26049", "CVE-2022-38752", "CVE-2021-45046", "CVE-2020-8203", "CVE-2020-8203", "CVE-2021-44228"},
+		},
+		{
+			// the format prefix takes precedence over detection
+			reference: "dependency-check=test-fixtures/.trivyignore",
+			wantErr:   require.Error,
+		},
+		{
+			reference: "test-fixtures/does-not-exist",
+			wantErr:   require.Error,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.reference, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			rules, err := Import(test.reference)
+			test.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, test.wantIDs, vulnerabilityIDs(rules))
+		})
+	}
+}
+
+func TestDecode_unsupportedFormat(t *testing.T) {
+	_, err := Decode("snyk", strings.NewReader(""))
+	require.ErrorContains(t, err, "unsupported ignore file format")
+}
+
+func Test_detectFormat(t *testing.T) {
+	tests := []struct {
+		path     string
+		contents string
+		want     Format
+	}{
+		{path: "project/.trivyignore", want: TrivyFormat},
+		{path: "project/.trivyignore.yaml", want: TrivyFormat},
+		{path: "suppressions.xml", want: DependencyCheckFormat},
+		{path: "alerts.json", want: DependabotFormat},
+		{path: "ignored", contents: "  <?xml version=\"1.0\"?><suppressions/>", want: DependencyCheckFormat},
+		{path: "ignored", contents: "[]", want: DependabotFormat},
+		{path: "ignored", contents: "CVE-2020-1234", want: TrivyFormat},
+	}
+	for _, test := range tests {
+		t.Run(test.path+test.contents, func(t *testing.T) {
+			assert.Equal(t, test.want, detectFormat(test.path, []byte(test.contents)))
+		})
+	}
+}
+
+func Test_expired(t *testing.T) {
+	withNow(t, "2024-06-01T12:00:00Z")
+
+	assert.False(t, expired(""))
+	assert.False(t, expired("not a date"))
+	assert.False(t, expired("2024-06-01"), "entries apply through the end of the day")
+	assert.False(t, expired("2024-06-01Z"))
+	assert.True(t, expired("2024-05-31"))
+	assert.True(t, expired("2024-06-01T11:00:00Z"))
+	assert.False(t, expired("2024-06-01T13:00:00Z"))
+}
+
+func Test_packageFromPURL(t *testing.T) {
+	tests := []struct {
+		purl    string
+		want    match.IgnoreRulePackage
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			purl: "pkg:npm/%40babel/traverse@7.22.5",
+			want: match.IgnoreRulePackage{Name: `@babel/traverse`, Version: "7.22.5", Language: "javascript"},
+		},
+		{
+			purl: "pkg:maven/org.eclipse.jetty/jetty-server@9.4.51",
+			want: match.IgnoreRulePackage{Name: `jetty-server`, Version: "9.4.51", Language: "java"},
+		},
+		{
+			purl: "pkg:golang/github.com/gin-gonic/gin@v1.9.0",
+			want: match.IgnoreRulePackage{Name: `github\.com/gin-gonic/gin`, Version: "v1.9.0", Language: "go"},
+		},
+		{
+			purl: "pkg:deb/debian/libssl1.1",
+			want: match.IgnoreRulePackage{Name: `libssl1\.1`, Type: "deb"},
+		},
+		{
+			purl:    "not-a-purl",
+			wantErr: require.Error,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.purl, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			got, err := packageFromPURL(test.purl)
+			test.wantErr(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestImport_rules(t *testing.T) {
+	withNow(t, "2024-06-01T00:00:00Z")
+
+	trivy, err := Import("test-fixtures/.trivyignore.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []match.IgnoreRule{
+		{
+			Vulnerability: "CVE-2022-40897",
+			Reason:        "Accept the risk",
+			Package:       match.IgnoreRulePackage{Location: "/usr/local/lib/python3.9/site-packages/setuptools-58.1.0.dist-info/METADATA"},
+		},
+		{Vulnerability: "CVE-2023-2650", Reason: "ignored in .trivyignore.yaml"},
+		{
+			Vulnerability: "CVE-2023-4807",
+			Reason:        "ignored in .trivyignore.yaml",
+			Package:       match.IgnoreRulePackage{Name: "@babel/traverse", Version: "7.22.5", Language: "javascript"},
+		},
+		{
+			Vulnerability: "CVE-2023-4807",
+			Reason:        "ignored in .trivyignore.yaml",
+			Package:       match.IgnoreRulePackage{Name: "jetty-server", Version: "9.4.51", Language: "java"},
+		},
+	}, trivy)
+
+	dependabot, err := Import("test-fixtures/dependabot-alerts.json")
+	require.NoError(t, err)
+	assert.Equal(t, []match.IgnoreRule{
+		{
+			Vulnerability: "GHSA-67hx-6x53-jw92",
+			Reason:        "dismissed in dependabot as tolerable_risk: only used in tests",
+			Package:       match.IgnoreRulePackage{Name: "@babel/traverse", Language: "javascript", Location: "/package-lock.json"},
+		},
+		{
+			Vulnerability: "CVE-2023-45133",
+			Reason:        "dismissed in dependabot as tolerable_risk: only used in tests",
+			Package:       match.IgnoreRulePackage{Name: "@babel/traverse", Language: "javascript", Location: "/package-lock.json"},
+		},
+		{
+			Vulnerability: "GHSA-3gh6-v5v9-6v9j",
+			Reason:        "dismissed in dependabot",
+			Package:       match.IgnoreRulePackage{Name: "jetty-server", Language: "java", Location: "/pom.xml"},
+		},
+	}, dependabot)
+
+	dependencyCheck, err := Import("test-fixtures/dependency-check-suppressions.xml")
+	require.NoError(t, err)
+	jetty := match.IgnoreRulePackage{Name: "jetty-server", Version: "9.4.51", Language: "java"}
+	assert.Equal(t, []match.IgnoreRule{
+		{Vulnerability: "CVE-2023-26048", Reason: "false positive, the vulnerable connector is not used", Package: jetty},
+		{Vulnerability: "CVE-2023-26049", Reason: "false positive, the vulnerable connector is not used", Package: jetty},
+		{
+			Vulnerability: "CVE-2022-38752",
+			Reason:        "suppressed in dependency-check",
+			Package:       match.IgnoreRulePackage{Name: "snakeyaml", Version: "1.33", Language: "java"},
+		},
+		{
+			Vulnerability: "CVE-2021-45046",
+			Reason:        "suppressed in dependency-check",
+			Package:       match.IgnoreRulePackage{Name: `log4j\-.*`, Language: "java"},
+		},
+		// the package URL regex of a version range is not supported, the other selectors of the entry are
+		{
+			Vulnerability: "CVE-2020-8203",
+			Reason:        "suppressed in dependency-check",
+			Package:       match.IgnoreRulePackage{Name: "lodash", Version: "4.17.20", Language: "javascript"},
+		},
+		{
+			Vulnerability: "CVE-2020-8203",
+			Reason:        "suppressed in dependency-check",
+			Package:       match.IgnoreRulePackage{Name: "guava", Version: "31.1-jre", Language: "java"},
+		},
+		{Vulnerability: "CVE-2021-44228", Reason: "suppressed in dependency-check"},
+	}, dependencyCheck)
+}
+
+func Test_packageFromPURLSelector(t *testing.T) {
+	tests := []struct {
+		selector dependencyCheckPattern
+		want     match.IgnoreRulePackage
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			selector: dependencyCheckPattern{Value: "pkg:maven/org.yaml/snakeyaml@1.33"},
+			want:     match.IgnoreRulePackage{Name: "snakeyaml", Version: "1.33", Language: "java"},
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^pkg:maven/org\.yaml/snakeyaml@1\.33$`},
+			want:     match.IgnoreRulePackage{Name: "snakeyaml", Version: "1.33", Language: "java"},
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^pkg:maven/org\.yaml/snakeyaml@.*$`},
+			want:     match.IgnoreRulePackage{Name: "snakeyaml", Language: "java"},
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `pkg:maven/org\.springframework/spring\-(web|webmvc)@.+`},
+			want:     match.IgnoreRulePackage{Name: `spring\-(web|webmvc)`, Language: "java"},
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^pkg:npm/%40babel/traverse@.*$`},
+			want:     match.IgnoreRulePackage{Name: "@babel/traverse", Language: "javascript"},
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^pkg:golang/github\.com/gin\-gonic/gin.*$`},
+			want:     match.IgnoreRulePackage{Name: `github\.com/gin-gonic/gin.*`, Language: "go"},
+		},
+		{
+			// the group is not part of the package criteria, so this would ignore every java package
+			selector: dependencyCheckPattern{Regex: true, Value: `^pkg:maven/org\.springframework/.*$`},
+			wantErr:  require.Error,
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^pkg:maven/org\.yaml/snakeyaml@1\..*$`},
+			wantErr:  require.Error,
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^pkg:maven/org\.(yaml|json)/snakeyaml@.*$`},
+			wantErr:  require.Error,
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^pkg:.*$`},
+			wantErr:  require.Error,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.selector.Value, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			got, err := packageFromPURLSelector(test.selector)
+			test.wantErr(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_packageFromGAVSelector(t *testing.T) {
+	tests := []struct {
+		selector dependencyCheckPattern
+		want     match.IgnoreRulePackage
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			selector: dependencyCheckPattern{Value: "org.yaml:snakeyaml:1.33"},
+			want:     match.IgnoreRulePackage{Name: "snakeyaml", Version: "1.33", Language: "java"},
+		},
+		{
+			selector: dependencyCheckPattern{Value: "org.yaml:snakeyaml"},
+			want:     match.IgnoreRulePackage{Name: "snakeyaml", Language: "java"},
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^org\.yaml:snakeyaml:.*$`},
+			want:     match.IgnoreRulePackage{Name: "snakeyaml", Language: "java"},
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^org\.apache\.tomcat\.embed:tomcat\-embed\-.*:10\.1\.5$`},
+			want:     match.IgnoreRulePackage{Name: `tomcat\-embed\-.*`, Version: "10.1.5", Language: "java"},
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^com\.example:.*$`},
+			wantErr:  require.Error,
+		},
+		{
+			selector: dependencyCheckPattern{Regex: true, Value: `^com\.example\..*:sdk:.*$`},
+			wantErr:  require.Error,
+		},
+		{
+			selector: dependencyCheckPattern{Value: "org.yaml"},
+			wantErr:  require.Error,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.selector.Value, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			got, err := packageFromGAVSelector(test.selector)
+			test.wantErr(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestDecode_dependabotManifests(t *testing.T) {
+	// the alert of the root manifest is dismissed, the alert of the frontend manifest is not
+	rules, err := Decode(DependabotFormat, strings.NewReader(`[
+  {
+    "number": 1,
+    "state": "dismissed",
+    "dependency": {"package": {"ecosystem": "npm", "name": "lodash"}, "manifest_path": "package-lock.json"},
+    "security_advisory": {"ghsa_id": "GHSA-jf85-cpcp-j695", "cve_id": null}
+  },
+  {
+    "number": 2,
+    "state": "open",
+    "dependency": {"package": {"ecosystem": "npm", "name": "lodash"}, "manifest_path": "frontend/package-lock.json"},
+    "security_advisory": {"ghsa_id": "GHSA-jf85-cpcp-j695", "cve_id": null}
+  }
+]`))
+	require.NoError(t, err)
+
+	lodash := func(manifest string) match.Match {
+		return match.Match{
+			Vulnerability: vulnerability.Vulnerability{ID: "GHSA-jf85-cpcp-j695"},
+			Package: pkg.Package{
+				ID:        pkg.ID(manifest),
+				Name:      "lodash",
+				Version:   "4.17.15",
+				Language:  syftPkg.JavaScript,
+				Locations: file.NewLocationSet(file.NewLocation(manifest)),
+			},
+		}
+	}
+	remaining, ignored := match.ApplyIgnoreRules(match.NewMatches(lodash("/package-lock.json"), lodash("/frontend/package-lock.json")), rules)
+	require.Len(t, ignored, 1)
+	assert.Equal(t, pkg.ID("/package-lock.json"), ignored[0].Package.ID)
+	require.Equal(t, 1, remaining.Count())
+	assert.Equal(t, pkg.ID("/frontend/package-lock.json"), remaining.Sorted()[0].Package.ID)
+}
@@ -0,0 +1,145 @@
+package ignore
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/internal/log"
+)
+
+// trivyYAML is the ".trivyignore.yaml" form, only vulnerability entries are applicable to grype
+type trivyYAML struct {
+	Vulnerabilities []trivyYAMLEntry `yaml:"vulnerabilities"`
+}
+
+type trivyYAMLEntry struct {
+	ID        string   `yaml:"id"`
+	Paths     []string `yaml:"paths"`
+	PURLs     []string `yaml:"purls"`
+	ExpiredAt string   `yaml:"expired_at"`
+	Statement string   `yaml:"statement"`
+}
+
+func decodeTrivy(reader io.Reader) ([]match.IgnoreRule, error) {
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read trivy ignore file: %w", err)
+	}
+
+	if isTrivyYAML(contents) {
+		return decodeTrivyYAML(contents)
+	}
+	return decodeTrivyIgnore(contents)
+}
+
+// isTrivyYAML reports whether the file is the YAML form, which always has a top-level mapping of finding kinds
+func isTrivyYAML(contents []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return strings.HasSuffix(line, ":") && !strings.Contains(line, " ")
+	}
+	return false
+}
+
+// decodeTrivyIgnore reads the plain form: one ID per line with an optional expiry (e.g. "CVE-2020-1234 exp:2024-01-31")
+func decodeTrivyIgnore(contents []byte) ([]match.IgnoreRule, error) {
+	var rules []match.IgnoreRule
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		id := fields[0]
+		var expiry string
+		for _, f := range fields[1:] {
+			if v, ok := strings.CutPrefix(f, "exp:"); ok {
+				expiry = v
+			}
+		}
+		if expired(expiry) {
+			log.WithFields("vulnerability", id, "expired", expiry).Debug("skipping expired trivy ignore entry")
+			continue
+		}
+
+		rules = append(rules, match.IgnoreRule{
+			Vulnerability: id,
+			Reason:        "ignored in .trivyignore",
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read trivy ignore file: %w", err)
+	}
+	return rules, nil
+}
+
+func decodeTrivyYAML(contents []byte) ([]match.IgnoreRule, error) {
+	var doc trivyYAML
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse trivy ignore file: %w", err)
+	}
+
+	var rules []match.IgnoreRule
+	for _, entry := range doc.Vulnerabilities {
+		if entry.ID == "" {
+			continue
+		}
+		if expired(entry.ExpiredAt) {
+			log.WithFields("vulnerability", entry.ID, "expired", entry.ExpiredAt).Debug("skipping expired trivy ignore entry")
+			continue
+		}
+
+		reason := "ignored in .trivyignore.yaml"
+		if entry.Statement != "" {
+			reason = singleLine(entry.Statement)
+		}
+
+		// an entry applies to any of its paths and any of its package URLs, so each combination becomes a rule
+		packages := []match.IgnoreRulePackage{{}}
+		if len(entry.PURLs) > 0 {
+			packages = packages[:0]
+			for _, purl := range entry.PURLs {
+				p, err := packageFromPURL(purl)
+				if err != nil {
+					return nil, fmt.Errorf("unable to import trivy ignore entry %q: %w", entry.ID, err)
+				}
+				packages = append(packages, p)
+			}
+		}
+		locations := []string{""}
+		if len(entry.Paths) > 0 {
+			locations = locations[:0]
+			for _, p := range entry.Paths {
+				// trivy paths are relative to the root of the scan target
+				locations = append(locations, "/"+strings.TrimPrefix(p, "/"))
+			}
+		}
+
+		for _, p := range packages {
+			for _, location := range locations {
+				p.Location = location
+				rules = append(rules, match.IgnoreRule{
+					Vulnerability: entry.ID,
+					Reason:        reason,
+					Package:       p,
+				})
+			}
+		}
+	}
+	return rules, nil
+}
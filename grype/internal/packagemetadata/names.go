@@ -19,6 +19,7 @@ var jsonNameFromType = map[reflect.Type][]string{
 	reflect.TypeOf(pkg.JavaMetadata{}):               nameList("JavaMetadata"),
 	reflect.TypeOf(pkg.RpmMetadata{}):                nameList("RpmMetadata"),
 	reflect.TypeOf(pkg.JavaVMInstallationMetadata{}): nameList("JavaVMInstallationMetadata"),
+	reflect.TypeOf(pkg.NixMetadata{}):                nameList("NixMetadata"),
 }
 
 //nolint:unparam
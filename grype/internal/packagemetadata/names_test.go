@@ -63,6 +63,11 @@ func TestReflectTypeFromJSONName(t *testing.T) {
 			lookup:     "JavaVMInstallationMetadata",
 			wantRecord: reflect.TypeOf(pkg.JavaVMInstallationMetadata{}),
 		},
+		{
+			name:       "NixMetadata lookup",
+			lookup:     "NixMetadata",
+			wantRecord: reflect.TypeOf(pkg.NixMetadata{}),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
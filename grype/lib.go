@@ -8,10 +8,14 @@ import (
 	"github.com/anchore/grype/internal/log"
 )
 
+// SetLogger sets the logger of the library, used unless a VulnerabilityMatcher is given a logger of its own. The
+// components used by the VulnerabilityMatcher (e.g. the matchers and the package providers) always log here, even
+// when the VulnerabilityMatcher has a logger of its own (see VulnerabilityMatcher.Logger).
 func SetLogger(l logger.Logger) {
 	log.Set(l)
 }
 
+// SetBus sets the bus receiving the events of the library, used unless a VulnerabilityMatcher is given a bus of its own.
 func SetBus(b *partybus.Bus) {
 	bus.Set(b)
 }
@@ -0,0 +1,136 @@
+package lib
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/adrg/xdg"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/db/legacy/distribution"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/internal"
+)
+
+// DBOptions describes where the vulnerability database lives and how it should be kept up to date.
+type DBOptions struct {
+	// ApplicationName and ApplicationVersion identify the embedding application (used for the user agent during downloads).
+	ApplicationName    string
+	ApplicationVersion string
+
+	Dir                string        // the root directory for the DB cache (a schema-specific subdirectory is used within)
+	UpdateURL          string        // the URL of the DB listing file
+	CACert             string        // path to a CA certificate to trust when downloading the listing and DB
+	AutoUpdate         bool          // check for (and apply) DB updates when loading
+	ValidateByHash     bool          // validate the DB checksum on each load
+	ValidateAge        bool          // fail loading when the DB was built longer ago than MaxAllowedBuiltAge
+	MaxAllowedBuiltAge time.Duration // the age at which a DB is considered stale
+	RequireUpdateCheck bool          // fail loading when the update check cannot be completed
+	ListingTimeout     time.Duration // timeout for fetching the listing file
+	DownloadTimeout    time.Duration // timeout for downloading the DB archive
+}
+
+// DefaultDBOptions returns the same DB settings the grype CLI uses by default, caching the DB under the
+// given application name.
+func DefaultDBOptions(applicationName string) DBOptions {
+	return DBOptions{
+		ApplicationName:    applicationName,
+		Dir:                filepath.Join(xdg.CacheHome, applicationName, "db"),
+		UpdateURL:          internal.DBUpdateURL,
+		AutoUpdate:         true,
+		ValidateAge:        true,
+		MaxAllowedBuiltAge: time.Hour * 24 * 5,
+		ListingTimeout:     time.Second * 30,
+		DownloadTimeout:    time.Second * 300,
+	}
+}
+
+func (o DBOptions) curatorConfig() distribution.Config {
+	return distribution.Config{
+		ID: clio.Identification{
+			Name:    o.ApplicationName,
+			Version: o.ApplicationVersion,
+		},
+		DBRootDir:           o.Dir,
+		ListingURL:          o.UpdateURL,
+		CACert:              o.CACert,
+		ValidateByHashOnGet: o.ValidateByHash,
+		ValidateAge:         o.ValidateAge,
+		MaxAllowedBuiltAge:  o.MaxAllowedBuiltAge,
+		RequireUpdateCheck:  o.RequireUpdateCheck,
+		ListingFileTimeout:  o.ListingTimeout,
+		UpdateTimeout:       o.DownloadTimeout,
+	}
+}
+
+// DBStatus describes the DB that was loaded.
+type DBStatus struct {
+	Built         time.Time
+	SchemaVersion int
+	Location      string
+	Checksum      string
+}
+
+// DB is a loaded vulnerability database. It is safe to share a single DB across many scanners; Close should be
+// called once no more scans will be run against it.
+type DB struct {
+	store  store.Store
+	status DBStatus
+	closer *db.Closer
+}
+
+// LoadDB loads (and optionally updates) the vulnerability database described by the given options.
+func LoadDB(opts DBOptions) (*DB, error) {
+	s, status, closer, err := grype.LoadVulnerabilityDB(opts.curatorConfig(), opts.AutoUpdate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load vulnerability db: %w", err)
+	}
+	if status == nil {
+		return nil, fmt.Errorf("unable to determine the status of the vulnerability db")
+	}
+	if status.Err != nil {
+		if closer != nil {
+			closer.Close()
+		}
+		return nil, fmt.Errorf("db could not be loaded: %w", status.Err)
+	}
+
+	return &DB{
+		store: *s,
+		status: DBStatus{
+			Built:         status.Built,
+			SchemaVersion: status.SchemaVersion,
+			Location:      status.Location,
+			Checksum:      status.Checksum,
+		},
+		closer: closer,
+	}, nil
+}
+
+// NewDB wraps an already-constructed store (for instance, an in-memory store used in tests) as a DB.
+func NewDB(s store.Store, status DBStatus) *DB {
+	return &DB{
+		store:  s,
+		status: status,
+	}
+}
+
+// Status returns details about the loaded DB.
+func (d *DB) Status() DBStatus {
+	return d.status
+}
+
+// Store returns the underlying vulnerability store.
+func (d *DB) Store() store.Store {
+	return d.store
+}
+
+// Close releases any resources held by the DB.
+func (d *DB) Close() {
+	if d.closer != nil {
+		d.closer.Close()
+	}
+}
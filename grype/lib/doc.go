@@ -0,0 +1,60 @@
+/*
+Package lib is the supported entry point for embedding grype within other Go programs.
+
+Everything exported from this package is covered by the following compatibility guarantees:
+
+  - exported functions, types, and struct fields will not be removed or change meaning within a major version
+  - new fields may be added to options structs; callers should start from the Default* constructors rather than
+    relying on zero values so that new fields receive sensible defaults
+  - behavior changes that alter which matches are reported for the same DB and input are called out in release notes
+
+Types from other grype packages that appear in this API (e.g. match.Matches, pkg.Package) are re-exported as-is.
+Anything reachable only through other packages (including everything under any "internal" directory) carries no
+such guarantees and may change in any minor release.
+
+A typical integration looks like:
+
+	db, err := lib.LoadDB(lib.DefaultDBOptions("my-app"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	scanner, err := lib.NewScanner(db, lib.DefaultScanOptions())
+	if err != nil {
+		return err
+	}
+
+	result, err := scanner.ScanSBOM("path/to/sbom.json")
+
+The matches of a huge result can be handled a page at a time:
+
+	for page := result.Page(0, 100); ; page = result.Page(page.Next, 100) {
+		serve(page.Matches)
+		if page.Next == 0 {
+			break
+		}
+	}
+
+Packages can be amended before they are matched, instead of rewriting the SBOM, with enrichers:
+
+	opts := lib.DefaultScanOptions()
+	opts.Enrichers = []pkg.Enricher{
+		pkg.EnricherFunc(func(p *pkg.Package, _ pkg.Context) error {
+			if p.Name == "libfoo" {
+				// the package was rebuilt from a Debian source package, whatever the distro of the image
+				p.Distro = &linux.Release{ID: "debian", VersionID: "12"}
+			}
+			return nil
+		}),
+	}
+
+Known vulnerable version ranges for a package can also be queried directly from the DB, without a scan:
+
+	ranges, err := db.VulnerableRanges("pkg:npm/lodash")
+	if err != nil {
+		return err
+	}
+	affecting, err := ranges[0].Affecting("4.17.21")
+*/
+package lib
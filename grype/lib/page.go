@@ -0,0 +1,35 @@
+package lib
+
+import (
+	"github.com/anchore/grype/grype/match"
+)
+
+// Page is a page of the matches of a result, so that the matches of a huge scan can be served (or processed) a few at
+// a time.
+type Page struct {
+	// Matches are the matches of the page, in the deterministic order of the matches of the result.
+	Matches []match.Match
+	// Offset is the index of the first match of the page, and Total the number of matches of the result.
+	Offset int
+	Total  int
+	// Next is the offset of the next page, zero when the page is the last.
+	Next int
+}
+
+// Page returns at most limit matches of the result (all the remaining matches when limit is zero or negative) from the
+// match at the offset on. The page is empty when the offset is past the last match.
+func (r Result) Page(offset, limit int) Page {
+	matches := r.Matches.Sorted()
+	total := len(matches)
+	start := min(max(offset, 0), total)
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+
+	page := Page{Matches: matches[start:end], Offset: start, Total: total}
+	if end < total {
+		page.Next = end
+	}
+	return page
+}
@@ -0,0 +1,46 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+func TestResult_Page(t *testing.T) {
+	var matches []match.Match
+	for _, id := range []string{"CVE-2024-0003", "CVE-2024-0001", "CVE-2024-0002"} {
+		matches = append(matches, match.Match{
+			Vulnerability: vulnerability.Vulnerability{ID: id, Namespace: "nvd:cpe"},
+			Package:       pkg.Package{ID: pkg.ID("lodash"), Name: "lodash", Version: "4.17.20"},
+		})
+	}
+	result := Result{Matches: match.NewMatches(matches...)}
+
+	ids := func(p Page) []string {
+		var out []string
+		for _, m := range p.Matches {
+			out = append(out, m.Vulnerability.ID)
+		}
+		return out
+	}
+
+	first := result.Page(0, 2)
+	assert.Equal(t, []string{"CVE-2024-0001", "CVE-2024-0002"}, ids(first))
+	assert.Equal(t, 3, first.Total)
+	assert.Equal(t, 2, first.Next)
+
+	last := result.Page(first.Next, 2)
+	assert.Equal(t, []string{"CVE-2024-0003"}, ids(last))
+	assert.Equal(t, 2, last.Offset)
+	assert.Zero(t, last.Next)
+
+	assert.Len(t, result.Page(0, 0).Matches, 3)
+	assert.Zero(t, result.Page(0, 0).Next)
+	past := result.Page(10, 2)
+	assert.Empty(t, past.Matches)
+	assert.Equal(t, 3, past.Offset)
+}
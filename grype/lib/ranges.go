@@ -0,0 +1,180 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/packageurl-go"
+)
+
+// VulnerableRange is a single vulnerability record describing a range of affected versions of a package.
+type VulnerableRange struct {
+	ID          string   // the vulnerability ID (e.g. GHSA-xxxx or CVE-xxxx)
+	Namespace   string   // the DB namespace the record originates from
+	Severity    string   // the severity from the vulnerability metadata, if known
+	Constraint  string   // the affected version constraint, as recorded in the DB
+	FixState    string   // fixed, not-fixed, wont-fix, or unknown
+	FixVersions []string // the versions that fix the vulnerability, if any
+
+	constraint version.Constraint
+}
+
+// PackageRanges holds every known vulnerable version range for a single package, independent of any version.
+type PackageRanges struct {
+	PURL   string
+	Name   string
+	Ranges []VulnerableRange
+
+	format version.Format
+}
+
+// VulnerableRanges returns all known vulnerable version ranges (and fixed versions) for each given purl. Any
+// version within the purls is ignored, which allows callers such as dependency update tools to evaluate candidate
+// versions with PackageRanges.Affecting without running a scan per version.
+func (d *DB) VulnerableRanges(purls ...string) ([]PackageRanges, error) {
+	var results []PackageRanges
+	for _, raw := range purls {
+		r, err := d.vulnerableRanges(raw)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *r)
+	}
+	return results, nil
+}
+
+func (d *DB) vulnerableRanges(raw string) (*PackageRanges, error) {
+	purl, err := packageurl.FromString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode purl %s: %w", raw, err)
+	}
+	// ranges are version independent, so the version is dropped to keep the package ID stable
+	purl.Version = ""
+
+	p, err := pkg.NewFromPURL(purl.String())
+	if err != nil {
+		return nil, err
+	}
+
+	vulns, err := d.candidates(p)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PackageRanges{
+		PURL:   p.PURL,
+		Name:   p.Name,
+		format: version.FormatFromPkg(p),
+	}
+
+	seen := make(map[string]struct{})
+	for _, v := range vulns {
+		r := d.newVulnerableRange(v)
+		key := strings.Join([]string{r.Namespace, r.ID, r.Constraint, strings.Join(r.FixVersions, ",")}, "|")
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result.Ranges = append(result.Ranges, r)
+	}
+
+	sort.SliceStable(result.Ranges, func(i, j int) bool {
+		if result.Ranges[i].ID == result.Ranges[j].ID {
+			return result.Ranges[i].Namespace < result.Ranges[j].Namespace
+		}
+		return result.Ranges[i].ID < result.Ranges[j].ID
+	})
+
+	return result, nil
+}
+
+// candidates fetches all vulnerability records for the package, searching by distro, language, and any CPEs
+// provided within the purl (in that order).
+func (d *DB) candidates(p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	var vulns []vulnerability.Vulnerability
+
+	if release := pkg.ReleaseFromPURL(p.PURL); release != nil {
+		dist, err := distro.NewFromRelease(*release)
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine distro for purl %s: %w", p.PURL, err)
+		}
+		found, err := d.store.GetByDistro(dist, p)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch vulnerabilities for purl %s by distro: %w", p.PURL, err)
+		}
+		vulns = append(vulns, found...)
+	}
+
+	if p.Language != "" {
+		found, err := d.store.GetByLanguage(p.Language, p)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch vulnerabilities for purl %s by language: %w", p.PURL, err)
+		}
+		vulns = append(vulns, found...)
+	}
+
+	for _, c := range p.CPEs {
+		found, err := d.store.GetByCPE(c)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch vulnerabilities for purl %s by CPE: %w", p.PURL, err)
+		}
+		vulns = append(vulns, found...)
+	}
+
+	return vulns, nil
+}
+
+func (d *DB) newVulnerableRange(v vulnerability.Vulnerability) VulnerableRange {
+	r := VulnerableRange{
+		ID:          v.ID,
+		Namespace:   v.Namespace,
+		FixState:    string(v.Fix.State),
+		FixVersions: v.Fix.Versions,
+		constraint:  v.Constraint,
+	}
+	if v.Constraint != nil {
+		r.Constraint = v.Constraint.String()
+	}
+
+	if d.store.MetadataProvider != nil {
+		m, err := d.store.GetMetadata(v.ID, v.Namespace)
+		if err == nil && m != nil {
+			r.Severity = m.Severity
+		}
+	}
+	return r
+}
+
+// Affecting returns the ranges which include the given version. A version with no affecting ranges has no known
+// vulnerabilities in the DB.
+func (p PackageRanges) Affecting(ver string) ([]VulnerableRange, error) {
+	verObj, err := version.NewVersion(ver, p.format)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse version %q for %s: %w", ver, p.PURL, err)
+	}
+
+	var affecting []VulnerableRange
+	for _, r := range p.Ranges {
+		if r.constraint == nil {
+			continue
+		}
+		satisfied, err := r.constraint.Satisfied(verObj)
+		if err != nil {
+			var nonFatal *version.NonFatalConstraintError
+			if errors.As(err, &nonFatal) || errors.Is(err, version.ErrUnknownCommitRange) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to evaluate %s constraint %q: %w", r.ID, r.Constraint, err)
+		}
+		if satisfied {
+			affecting = append(affecting, r)
+		}
+	}
+	return affecting, nil
+}
@@ -0,0 +1,62 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+func TestDB_VulnerableRanges(t *testing.T) {
+	p := mockProvider{
+		byName: map[string][]vulnerability.Vulnerability{
+			"lodash": {
+				{
+					ID:         "GHSA-bbbb",
+					Namespace:  "github:language:javascript",
+					Constraint: version.MustGetConstraint(">= 4.0.0, < 4.17.21", version.UnknownFormat),
+					Fix:        vulnerability.Fix{State: grypeDB.FixedState, Versions: []string{"4.17.21"}},
+				},
+				{
+					ID:         "GHSA-aaaa",
+					Namespace:  "github:language:javascript",
+					Constraint: version.MustGetConstraint("< 4.17.12", version.UnknownFormat),
+					Fix:        vulnerability.Fix{State: grypeDB.FixedState, Versions: []string{"4.17.12"}},
+				},
+			},
+		},
+	}
+	db := NewDB(store.Store{Provider: p, MetadataProvider: p, ExclusionProvider: p}, DBStatus{})
+
+	results, err := db.VulnerableRanges("pkg:npm/lodash@4.17.0", "pkg:npm/left-pad")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	lodash := results[0]
+	assert.Equal(t, "pkg:npm/lodash", lodash.PURL, "the version should be dropped")
+	assert.Equal(t, "lodash", lodash.Name)
+	require.Len(t, lodash.Ranges, 2)
+	assert.Equal(t, "GHSA-aaaa", lodash.Ranges[0].ID, "ranges should be sorted by ID")
+	assert.Equal(t, []string{"4.17.12"}, lodash.Ranges[0].FixVersions)
+	assert.Equal(t, "fixed", lodash.Ranges[0].FixState)
+	assert.Equal(t, "high", lodash.Ranges[0].Severity)
+
+	affecting, err := lodash.Affecting("4.17.15")
+	require.NoError(t, err)
+	require.Len(t, affecting, 1)
+	assert.Equal(t, "GHSA-bbbb", affecting[0].ID)
+
+	affecting, err = lodash.Affecting("4.17.21")
+	require.NoError(t, err)
+	assert.Empty(t, affecting)
+
+	assert.Empty(t, results[1].Ranges)
+
+	_, err = db.VulnerableRanges("not-a-purl")
+	require.Error(t, err)
+}
@@ -0,0 +1,264 @@
+package lib
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/environment"
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/matcher/alpm"
+	"github.com/anchore/grype/grype/matcher/golang"
+	"github.com/anchore/grype/grype/matcher/stock"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/pkg/cataloger/macos"
+	"github.com/anchore/grype/grype/severity"
+	"github.com/anchore/grype/grype/vex"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/anchore/syft/syft"
+	"github.com/anchore/syft/syft/cataloging"
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// ErrAboveSeverityThreshold is returned (alongside a complete Result) when FailOnSeverity is set and a match at or
+// above that severity was found.
+var ErrAboveSeverityThreshold = grypeerr.ErrAboveSeverityThreshold
+
+// ScanOptions controls how packages are matched against the vulnerability DB.
+type ScanOptions struct {
+	// Matchers configures the per-ecosystem matchers.
+	Matchers matcher.Config
+
+	// IgnoreRules are applied to all matches; ignored matches are reported separately in the result.
+	IgnoreRules []match.IgnoreRule
+
+	// Environment is the environment context of the scans, outside of which the ignore rules restricted to an
+	// environment do not apply.
+	Environment environment.Context
+
+	// FailOnSeverity, when set, causes scans to return ErrAboveSeverityThreshold if any match is at or above this severity.
+	FailOnSeverity string
+
+	// SeverityOverrides re-rate vulnerabilities in place of the severities from the DB, for both FailOnSeverity and
+	// the reported metadata.
+	SeverityOverrides *severity.Overrides
+
+	// NormalizeByCVE reports matches by CVE instead of the original vulnerability ID when possible.
+	NormalizeByCVE bool
+
+	// VexDocuments are paths to VEX documents to apply to scan results.
+	VexDocuments []string
+
+	// Distro overrides the detected distro, in the form <distro>:<version>.
+	Distro string
+
+	// DisabledDistros are the linux distributions whose packages are not matched.
+	DisabledDistros []distro.Type
+
+	// GenerateMissingCPEs synthesizes CPEs for packages that have none (useful for 3rd party SBOMs).
+	GenerateMissingCPEs bool
+
+	// Exclusions are glob expressions of paths to exclude from scanning.
+	Exclusions []string
+
+	// OverlapPrecedence decides which package is matched when an OS package owns the files of another package
+	// (defaults to pkg.OverlapPrecedenceAuto).
+	OverlapPrecedence pkg.OverlapPrecedence
+
+	// Scope restricts matching to the packages found under some paths or in the last layers of an image.
+	Scope pkg.Scope
+
+	// Lockfiles are lockfiles whose resolved dependencies are added to the packages of a scanned input when they
+	// were not found in it (such as dev dependencies that are not installed).
+	Lockfiles []string
+
+	// LockfileMode is how the packages of the Lockfiles are combined with the packages of the input (defaults to
+	// pkg.IncludeLockfiles).
+	LockfileMode pkg.LockfileMode
+
+	// IgnoreArchitecture matches advisories scoped to specific architectures regardless of the architecture of the
+	// scanned packages (the Arch of packages given to ScanPackages is used as is).
+	IgnoreArchitecture bool
+
+	// Enrichers amend the packages before they are matched, for instance to correct wrongly detected versions, add
+	// CPEs, or match some packages against another distro. The packages of the result are the amended packages.
+	Enrichers []pkg.Enricher
+
+	// Strict fails scans with a *grypeerr.DataQualityError (alongside a complete Result) when the results may be
+	// incomplete, such as when packages cannot be matched or a matcher failed.
+	Strict bool
+
+	// Platform selects the platform to use for multi-arch images (e.g. linux/arm64).
+	Platform string
+
+	// RegistryOptions are used when pulling images from a registry.
+	RegistryOptions *image.RegistryOptions
+}
+
+// DefaultScanOptions returns the same matching behavior the grype CLI uses by default: CPEs are searched for the
+// packages of the stock and alpm matchers and for the Go standard library, and vulnerabilities bounded by pre-release
+// versions are reported (the default policy of the matchers).
+func DefaultScanOptions() ScanOptions {
+	return ScanOptions{
+		Matchers: matcher.Config{
+			Golang: golang.MatcherConfig{AlwaysUseCPEForStdlib: true},
+			Stock:  stock.MatcherConfig{UseCPEs: true},
+			Alpm:   alpm.MatcherConfig{UseCPEs: true},
+		},
+	}
+}
+
+// Result is the outcome of a single scan.
+type Result struct {
+	Matches        match.Matches
+	IgnoredMatches []match.IgnoredMatch
+	Packages       []pkg.Package
+	Context        pkg.Context
+	SBOM           *sbom.SBOM
+}
+
+// Scanner matches packages against a loaded DB.
+type Scanner struct {
+	db             *DB
+	opts           ScanOptions
+	failOnSeverity *vulnerability.Severity
+}
+
+// NewScanner creates a Scanner over the given DB.
+func NewScanner(db *DB, opts ScanOptions) (*Scanner, error) {
+	if db == nil {
+		return nil, fmt.Errorf("no vulnerability db provided")
+	}
+
+	var failOnSeverity *vulnerability.Severity
+	if opts.FailOnSeverity != "" {
+		sev := vulnerability.ParseSeverity(opts.FailOnSeverity)
+		if sev == vulnerability.UnknownSeverity {
+			return nil, fmt.Errorf("bad fail-on severity value %q", opts.FailOnSeverity)
+		}
+		failOnSeverity = &sev
+	}
+
+	return &Scanner{
+		db:             db,
+		opts:           opts,
+		failOnSeverity: failOnSeverity,
+	}, nil
+}
+
+// ScanSBOM scans the SBOM at the given path (any format syft can decode).
+func (s *Scanner) ScanSBOM(path string) (*Result, error) {
+	return s.scanInput("sbom:" + path)
+}
+
+// ScanImage catalogs and scans the given container image reference. Any source scheme supported by the grype CLI
+// may be used (e.g. "registry:alpine:latest" or "docker-archive:path/to/image.tar").
+func (s *Scanner) ScanImage(ref string) (*Result, error) {
+	return s.scanInput(ref)
+}
+
+// ScanPackages scans an already-assembled set of packages.
+func (s *Scanner) ScanPackages(packages []pkg.Package, context pkg.Context) (*Result, error) {
+	s.applyDistro(&context)
+
+	packages, err := pkg.Enrich(packages, context, s.opts.Enrichers...)
+	if err != nil {
+		return nil, err
+	}
+
+	vulnMatcher := grype.VulnerabilityMatcher{
+		Store:          s.db.store,
+		Matchers:       matcher.NewDefaultMatchers(s.opts.Matchers),
+		IgnoreRules:    s.opts.IgnoreRules,
+		FailSeverity:   s.failOnSeverity,
+		NormalizeByCVE: s.opts.NormalizeByCVE,
+
+		SeverityOverrides: s.opts.SeverityOverrides,
+		Strict:            s.opts.Strict,
+		Environment:       s.opts.Environment,
+		DisabledDistros:   s.opts.DisabledDistros,
+	}
+
+	if len(s.opts.VexDocuments) > 0 {
+		vulnMatcher.VexProcessor = vex.NewProcessor(vex.ProcessorOptions{
+			Documents:   s.opts.VexDocuments,
+			IgnoreRules: s.opts.IgnoreRules,
+			Environment: s.opts.Environment,
+		})
+	}
+
+	remaining, ignored, err := vulnMatcher.FindMatches(packages, context)
+	if err != nil && !grypeerr.IsScanFailure(err) {
+		return nil, err
+	}
+
+	result := &Result{
+		IgnoredMatches: ignored,
+		Packages:       packages,
+		Context:        context,
+	}
+	if remaining != nil {
+		result.Matches = *remaining
+	}
+
+	return result, err
+}
+
+func (s *Scanner) scanInput(input string) (*Result, error) {
+	packages, context, sb, err := pkg.Provide(input, s.providerConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to catalog: %w", err)
+	}
+
+	result, err := s.ScanPackages(packages, context)
+	if result != nil {
+		result.SBOM = sb
+	}
+	return result, err
+}
+
+func (s *Scanner) providerConfig() pkg.ProviderConfig {
+	cfg := syft.DefaultCreateSBOMConfig()
+	// packages without versions cannot be matched, so there is no reason to catalog them
+	cfg.Compliance.MissingVersion = cataloging.ComplianceActionDrop
+	cfg.WithCatalogers(macos.CatalogerReferences()...)
+	cfg.Relationships.ExcludeBinaryPackagesWithFileOwnershipOverlap = s.opts.OverlapPrecedence.DropsOwnedPackages()
+
+	return pkg.ProviderConfig{
+		SyftProviderConfig: pkg.SyftProviderConfig{
+			RegistryOptions: s.opts.RegistryOptions,
+			Exclusions:      s.opts.Exclusions,
+			SBOMOptions:     cfg,
+			Platform:        s.opts.Platform,
+		},
+		SynthesisConfig: pkg.SynthesisConfig{
+			GenerateMissingCPEs: s.opts.GenerateMissingCPEs,
+		},
+		OverlapPrecedence:  s.opts.OverlapPrecedence,
+		Scope:              s.opts.Scope,
+		Lockfiles:          s.opts.Lockfiles,
+		LockfileMode:       s.opts.LockfileMode,
+		IgnoreArchitecture: s.opts.IgnoreArchitecture,
+	}
+}
+
+func (s *Scanner) applyDistro(context *pkg.Context) {
+	if s.opts.Distro == "" {
+		return
+	}
+
+	name, version, _ := strings.Cut(s.opts.Distro, ":")
+	context.Distro = &linux.Release{
+		PrettyName: name,
+		Name:       name,
+		ID:         name,
+		IDLike:     []string{name},
+		Version:    version,
+		VersionID:  version,
+	}
+}
@@ -0,0 +1,171 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockProvider struct {
+	byName map[string][]vulnerability.Vulnerability
+}
+
+func (m mockProvider) Get(_, _ string) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (m mockProvider) GetByDistro(_ *distro.Distro, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return m.byName[p.Name], nil
+}
+
+func (m mockProvider) GetByLanguage(_ syftPkg.Language, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return m.byName[p.Name], nil
+}
+
+func (m mockProvider) GetByCPE(_ cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (m mockProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	return &vulnerability.Metadata{ID: id, Namespace: namespace, Severity: "high"}, nil
+}
+
+func (m mockProvider) GetRules(_ string) ([]match.IgnoreRule, error) {
+	return nil, nil
+}
+
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	constraint, err := version.GetConstraint("< 2.0.0", version.PythonFormat)
+	require.NoError(t, err)
+
+	p := mockProvider{
+		byName: map[string][]vulnerability.Vulnerability{
+			"requests": {
+				{
+					ID:          "GHSA-1234",
+					Namespace:   "github:language:python",
+					PackageName: "requests",
+					Constraint:  constraint,
+				},
+			},
+		},
+	}
+
+	return NewDB(store.Store{
+		Provider:          p,
+		MetadataProvider:  p,
+		ExclusionProvider: p,
+	}, DBStatus{SchemaVersion: vulnerability.SchemaVersion})
+}
+
+func TestScanner_ScanPackages(t *testing.T) {
+	packages := []pkg.Package{
+		{
+			ID:       pkg.ID("vulnerable"),
+			Name:     "requests",
+			Version:  "1.0.0",
+			Type:     syftPkg.PythonPkg,
+			Language: syftPkg.Python,
+		},
+		{
+			ID:       pkg.ID("fixed"),
+			Name:     "requests",
+			Version:  "2.1.0",
+			Type:     syftPkg.PythonPkg,
+			Language: syftPkg.Python,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		opts        ScanOptions
+		wantMatches int
+		wantIgnored int
+		wantErr     error
+	}{
+		{
+			name:        "default options",
+			opts:        DefaultScanOptions(),
+			wantMatches: 1,
+		},
+		{
+			name: "ignore rules are applied",
+			opts: ScanOptions{
+				IgnoreRules: []match.IgnoreRule{{Vulnerability: "GHSA-1234"}},
+			},
+			wantIgnored: 1,
+		},
+		{
+			name: "enrichers amend the packages before matching",
+			opts: ScanOptions{
+				Enrichers: []pkg.Enricher{
+					// the fixed package was wrongly detected
+					pkg.EnricherFunc(func(p *pkg.Package, _ pkg.Context) error {
+						if p.ID == "fixed" {
+							p.Version = "1.9.0"
+						}
+						return nil
+					}),
+				},
+			},
+			wantMatches: 2,
+		},
+		{
+			name: "fail on severity still returns results",
+			opts: ScanOptions{
+				FailOnSeverity: "medium",
+			},
+			wantMatches: 1,
+			wantErr:     ErrAboveSeverityThreshold,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewScanner(newTestDB(t), tt.opts)
+			require.NoError(t, err)
+
+			result, err := s.ScanPackages(packages, pkg.Context{})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NotNil(t, result)
+
+			assert.Equal(t, tt.wantMatches, result.Matches.Count())
+			assert.Len(t, result.IgnoredMatches, tt.wantIgnored)
+			assert.Len(t, result.Packages, len(packages))
+		})
+	}
+}
+
+func TestNewScanner_BadSeverity(t *testing.T) {
+	_, err := NewScanner(newTestDB(t), ScanOptions{FailOnSeverity: "bogus"})
+	require.Error(t, err)
+
+	_, err = NewScanner(nil, DefaultScanOptions())
+	require.Error(t, err)
+}
+
+func TestScanner_DistroOverride(t *testing.T) {
+	s, err := NewScanner(newTestDB(t), ScanOptions{Distro: "ubuntu:20.04"})
+	require.NoError(t, err)
+
+	result, err := s.ScanPackages(nil, pkg.Context{})
+	require.NoError(t, err)
+	require.NotNil(t, result.Context.Distro)
+	assert.Equal(t, "ubuntu", result.Context.Distro.ID)
+	assert.Equal(t, "20.04", result.Context.Distro.VersionID)
+}
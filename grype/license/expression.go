@@ -0,0 +1,158 @@
+package license
+
+import (
+	"strings"
+)
+
+// expression is a parsed SPDX license expression, e.g. "MIT OR (Apache-2.0 AND BSD-3-Clause)".
+type expression interface {
+	// evaluate returns whether the expression is compliant with the policy, or else the licenses making it
+	// non-compliant.
+	evaluate(p *Policy) (bool, []termViolation)
+}
+
+type termViolation struct {
+	license string
+	reason  Reason
+}
+
+// term is a single license, possibly with an exception (e.g. "GPL-2.0-only WITH Classpath-exception-2.0").
+type term struct {
+	id        string
+	exception string
+}
+
+type conjunction struct {
+	left, right expression
+}
+
+type disjunction struct {
+	left, right expression
+}
+
+func (t term) String() string {
+	if t.exception != "" {
+		return t.id + " WITH " + t.exception
+	}
+	return t.id
+}
+
+func (t term) evaluate(p *Policy) (bool, []termViolation) {
+	if reason, ok := p.check(t); !ok {
+		return false, []termViolation{{license: t.String(), reason: reason}}
+	}
+	return true, nil
+}
+
+// evaluate is compliant when both sides are compliant.
+func (c conjunction) evaluate(p *Policy) (bool, []termViolation) {
+	leftOK, left := c.left.evaluate(p)
+	rightOK, right := c.right.evaluate(p)
+	return leftOK && rightOK, append(left, right...)
+}
+
+// evaluate is compliant when either side is compliant, since the licensee may choose either license.
+func (d disjunction) evaluate(p *Policy) (bool, []termViolation) {
+	leftOK, left := d.left.evaluate(p)
+	if leftOK {
+		return true, nil
+	}
+	rightOK, right := d.right.evaluate(p)
+	if rightOK {
+		return true, nil
+	}
+	return false, append(left, right...)
+}
+
+// parseExpression parses the SPDX license expression, taking the whole value as a single license when it is not a
+// well-formed expression (e.g. a free-form license name).
+func parseExpression(value string) expression {
+	p := &parser{tokens: tokenize(value)}
+	expr, ok := p.parseOr()
+	if !ok || p.pos != len(p.tokens) {
+		return term{id: strings.TrimSpace(value)}
+	}
+	return expr
+}
+
+func tokenize(value string) []string {
+	value = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(value)
+	return strings.Fields(value)
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) parseOr() (expression, bool) {
+	left, ok := p.parseAnd()
+	if !ok {
+		return nil, false
+	}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.pos++
+		right, ok := p.parseAnd()
+		if !ok {
+			return nil, false
+		}
+		left = disjunction{left: left, right: right}
+	}
+	return left, true
+}
+
+func (p *parser) parseAnd() (expression, bool) {
+	left, ok := p.parseTerm()
+	if !ok {
+		return nil, false
+	}
+	for strings.EqualFold(p.peek(), "AND") {
+		p.pos++
+		right, ok := p.parseTerm()
+		if !ok {
+			return nil, false
+		}
+		left = conjunction{left: left, right: right}
+	}
+	return left, true
+}
+
+func (p *parser) parseTerm() (expression, bool) {
+	token := p.peek()
+	switch {
+	case token == "(":
+		p.pos++
+		expr, ok := p.parseOr()
+		if !ok || p.peek() != ")" {
+			return nil, false
+		}
+		p.pos++
+		return expr, true
+	case token == "", token == ")", isOperator(token):
+		return nil, false
+	}
+
+	p.pos++
+	t := term{id: token}
+	if strings.EqualFold(p.peek(), "WITH") {
+		p.pos++
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" || isOperator(exception) {
+			return nil, false
+		}
+		p.pos++
+		t.exception = exception
+	}
+	return t, true
+}
+
+func isOperator(token string) bool {
+	return strings.EqualFold(token, "AND") || strings.EqualFold(token, "OR") || strings.EqualFold(token, "WITH")
+}
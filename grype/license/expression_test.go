@@ -0,0 +1,39 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		value string
+		want  expression
+	}{
+		{value: "MIT", want: term{id: "MIT"}},
+		{value: "MIT OR Apache-2.0", want: disjunction{left: term{id: "MIT"}, right: term{id: "Apache-2.0"}}},
+		{
+			// AND binds tighter than OR
+			value: "MIT or Apache-2.0 AND BSD-3-Clause",
+			want:  disjunction{left: term{id: "MIT"}, right: conjunction{left: term{id: "Apache-2.0"}, right: term{id: "BSD-3-Clause"}}},
+		},
+		{
+			value: "(MIT OR Apache-2.0) AND BSD-3-Clause",
+			want:  conjunction{left: disjunction{left: term{id: "MIT"}, right: term{id: "Apache-2.0"}}, right: term{id: "BSD-3-Clause"}},
+		},
+		{
+			value: "GPL-2.0-only WITH Classpath-exception-2.0",
+			want:  term{id: "GPL-2.0-only", exception: "Classpath-exception-2.0"},
+		},
+		// not well-formed expressions are single licenses
+		{value: "Apache License 2.0", want: term{id: "Apache License 2.0"}},
+		{value: "(MIT OR", want: term{id: "(MIT OR"}},
+		{value: "MIT AND", want: term{id: "MIT AND"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseExpression(tt.value))
+		})
+	}
+}
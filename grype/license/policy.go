@@ -0,0 +1,167 @@
+/*
+Package license evaluates the licenses of the cataloged packages against a license policy of denied and allowed SPDX
+license IDs, so that license compliance is checked in the same run as the vulnerability scan.
+*/
+package license
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/pkg"
+)
+
+// Reason is why a license violates the policy.
+type Reason string
+
+const (
+	// Denied is a license matching the deny list.
+	Denied Reason = "denied"
+	// NotAllowed is a license not matching the allow list.
+	NotAllowed Reason = "not-allowed"
+)
+
+// Exception exempts the licenses of a package from the policy.
+type Exception struct {
+	// Package is the name of the package, which may be a glob (e.g. "@acme/*").
+	Package string
+	// Version is the version of the package, or empty for every version.
+	Version string
+	// License is the license exempted (which may be a glob), or empty for every license of the package.
+	License string
+	Reason  string
+}
+
+// Policy is a deny list and an allow list of SPDX license IDs, which may be globs (e.g. "GPL-*"), compared ignoring
+// case. A license is compliant when it matches no denied license and, with an allow list, matches an allowed license.
+type Policy struct {
+	Deny       []string
+	Allow      []string
+	Exceptions []Exception
+}
+
+// Violation is a license of a package that violates the policy.
+type Violation struct {
+	Package pkg.Package
+	// License is the license of the package as declared (e.g. an SPDX expression such as "MIT OR GPL-3.0-only").
+	License string
+	// Licenses are the licenses within the declared license that are not compliant, along with the reason of each.
+	Licenses []string
+	Reasons  []Reason
+}
+
+// Validate returns an error when a license or package pattern of the policy is not a valid glob.
+func (p Policy) Validate() error {
+	patterns := append(append([]string{}, p.Deny...), p.Allow...)
+	for _, e := range p.Exceptions {
+		if e.Package == "" {
+			return fmt.Errorf("license policy exception has no package")
+		}
+		patterns = append(patterns, e.Package, e.License)
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid license policy pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// IsEmpty tells whether the policy has neither a deny list nor an allow list, in which case every license complies.
+func (p *Policy) IsEmpty() bool {
+	return p == nil || (len(p.Deny) == 0 && len(p.Allow) == 0)
+}
+
+// Evaluate returns the violations of the policy by the licenses of the packages. Each declared license of a package
+// must comply, and an SPDX expression complies when either side of an OR complies and both sides of an AND comply.
+// Packages without licenses are not evaluated.
+func (p *Policy) Evaluate(packages []pkg.Package) []Violation {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	var violations []Violation
+	for _, pk := range packages {
+		for _, declared := range pk.Licenses {
+			if strings.TrimSpace(declared) == "" {
+				continue
+			}
+			ok, terms := parseExpression(declared).evaluate(p)
+			if ok {
+				continue
+			}
+			v := Violation{Package: pk, License: declared}
+			for _, t := range terms {
+				if p.exempted(pk, t.license) {
+					continue
+				}
+				v.Licenses = append(v.Licenses, t.license)
+				v.Reasons = append(v.Reasons, t.reason)
+			}
+			if len(v.Licenses) > 0 {
+				violations = append(violations, v)
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Package.Name != violations[j].Package.Name {
+			return violations[i].Package.Name < violations[j].Package.Name
+		}
+		if violations[i].Package.Version != violations[j].Package.Version {
+			return violations[i].Package.Version < violations[j].Package.Version
+		}
+		return violations[i].License < violations[j].License
+	})
+	return violations
+}
+
+// check returns whether the license complies, or else the reason it does not. A license with an exception is also
+// matched by its license ID alone.
+func (p *Policy) check(t term) (Reason, bool) {
+	candidates := []string{t.String()}
+	if t.exception != "" {
+		candidates = append(candidates, t.id)
+	}
+	if matchesAny(p.Deny, candidates...) {
+		return Denied, false
+	}
+	if len(p.Allow) > 0 && !matchesAny(p.Allow, candidates...) {
+		return NotAllowed, false
+	}
+	return "", true
+}
+
+func (p *Policy) exempted(pk pkg.Package, license string) bool {
+	for _, e := range p.Exceptions {
+		if !matches(e.Package, pk.Name) {
+			continue
+		}
+		if e.Version != "" && e.Version != pk.Version {
+			continue
+		}
+		if e.License != "" && !matches(e.License, license) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func matchesAny(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, v := range values {
+			if matches(pattern, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matches(pattern, value string) bool {
+	ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	return err == nil && ok
+}
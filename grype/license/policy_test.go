@@ -0,0 +1,98 @@
+package license
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestPolicy_Evaluate(t *testing.T) {
+	npm := func(name, version string, licenses ...string) pkg.Package {
+		return pkg.Package{Name: name, Version: version, Type: syftPkg.NpmPkg, Licenses: licenses}
+	}
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		packages []pkg.Package
+		want     []string
+	}{
+		{
+			name:     "denied license",
+			policy:   Policy{Deny: []string{"GPL-*", "AGPL-3.0-only"}},
+			packages: []pkg.Package{npm("a", "1.0.0", "MIT"), npm("b", "1.0.0", "gpl-3.0-only"), npm("c", "1.0.0")},
+			want:     []string{"b@1.0.0 gpl-3.0-only: gpl-3.0-only denied"},
+		},
+		{
+			name:     "either license of a disjunction may be chosen",
+			policy:   Policy{Deny: []string{"GPL-3.0-only", "AGPL-3.0-only"}},
+			packages: []pkg.Package{npm("a", "1.0.0", "MIT OR GPL-3.0-only"), npm("b", "1.0.0", "GPL-3.0-only OR AGPL-3.0-only")},
+			want:     []string{"b@1.0.0 GPL-3.0-only OR AGPL-3.0-only: GPL-3.0-only denied, AGPL-3.0-only denied"},
+		},
+		{
+			name:     "both licenses of a conjunction apply",
+			policy:   Policy{Allow: []string{"MIT", "Apache-2.0"}},
+			packages: []pkg.Package{npm("a", "1.0.0", "MIT AND Apache-2.0"), npm("b", "1.0.0", "MIT AND (BSD-3-Clause OR ISC)")},
+			want:     []string{"b@1.0.0 MIT AND (BSD-3-Clause OR ISC): BSD-3-Clause not-allowed, ISC not-allowed"},
+		},
+		{
+			name:     "every declared license applies",
+			policy:   Policy{Allow: []string{"MIT"}},
+			packages: []pkg.Package{npm("a", "1.0.0", "MIT", "Unlicense")},
+			want:     []string{"a@1.0.0 Unlicense: Unlicense not-allowed"},
+		},
+		{
+			name:     "a license with an exception matches its license ID",
+			policy:   Policy{Deny: []string{"GPL-2.0-only"}, Allow: []string{"GPL-2.0-only WITH Classpath-exception-2.0"}},
+			packages: []pkg.Package{npm("a", "1.0.0", "GPL-2.0-only WITH Classpath-exception-2.0")},
+			want:     []string{"a@1.0.0 GPL-2.0-only WITH Classpath-exception-2.0: GPL-2.0-only WITH Classpath-exception-2.0 denied"},
+		},
+		{
+			name: "exceptions",
+			policy: Policy{
+				Deny: []string{"GPL-*"},
+				Exceptions: []Exception{
+					{Package: "@acme/*", Reason: "our own packages"},
+					{Package: "b", Version: "1.0.0", License: "GPL-2.0-*"},
+				},
+			},
+			packages: []pkg.Package{
+				npm("@acme/tool", "1.0.0", "GPL-3.0-only"),
+				npm("b", "1.0.0", "GPL-2.0-only AND GPL-3.0-only"),
+				npm("b", "2.0.0", "GPL-2.0-only"),
+			},
+			want: []string{
+				"b@1.0.0 GPL-2.0-only AND GPL-3.0-only: GPL-3.0-only denied",
+				"b@2.0.0 GPL-2.0-only: GPL-2.0-only denied",
+			},
+		},
+		{
+			name:     "empty policy",
+			packages: []pkg.Package{npm("a", "1.0.0", "GPL-3.0-only")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range tt.policy.Evaluate(tt.packages) {
+				var licenses []string
+				for i := range v.Licenses {
+					licenses = append(licenses, v.Licenses[i]+" "+string(v.Reasons[i]))
+				}
+				got = append(got, v.Package.Name+"@"+v.Package.Version+" "+v.License+": "+strings.Join(licenses, ", "))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	require.NoError(t, Policy{Deny: []string{"GPL-*"}, Exceptions: []Exception{{Package: "@acme/*"}}}.Validate())
+	assert.ErrorContains(t, Policy{Allow: []string{"MIT["}}.Validate(), `invalid license policy pattern "MIT["`)
+	assert.ErrorContains(t, Policy{Exceptions: []Exception{{License: "MIT"}}}.Validate(), "has no package")
+}
@@ -3,6 +3,7 @@ package grype
 import (
 	"github.com/anchore/grype/grype/db"
 	"github.com/anchore/grype/grype/db/legacy/distribution"
+	grypeDB "github.com/anchore/grype/grype/db/v5"
 	"github.com/anchore/grype/grype/store"
 	"github.com/anchore/grype/internal/log"
 )
@@ -38,6 +39,9 @@ func LoadVulnerabilityDB(cfg distribution.Config, update bool) (*store.Store, *d
 		MetadataProvider:  db.NewVulnerabilityMetadataProvider(storeReader),
 		ExclusionProvider: db.NewMatchExclusionProvider(storeReader),
 	}
+	if reader, ok := storeReader.(grypeDB.PackageTargetingStoreReader); ok {
+		s.Targeting = db.NewPackageTargetingProvider(reader)
+	}
 
 	closer := &db.Closer{DBCloser: dbCloser}
 
@@ -0,0 +1,134 @@
+/*
+Package localization provides translated vulnerability summaries, so that the reports of a scan can be embedded into
+reporting workflows in other languages than English. The summaries come from translation bundles: JSON documents of
+the summaries of vulnerabilities in a language, either shipped beside the vulnerability DB or given by the user.
+*/
+package localization
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/anchore/grype/internal/dataset"
+)
+
+// DBDirName is the name of the optional directory of translation bundles (one <language>.json bundle per language)
+// that may be shipped alongside the vulnerability DB.
+const DBDirName = "translations"
+
+var languagePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// ValidateLanguage checks that the language is a BCP 47 language tag (e.g. "ja" or "pt-BR").
+func ValidateLanguage(lang string) error {
+	if !languagePattern.MatchString(lang) {
+		return fmt.Errorf("invalid language %q: expected a language tag such as 'ja' or 'pt-BR'", lang)
+	}
+	return nil
+}
+
+// Bundle holds the translated summaries of vulnerabilities in a single language, keyed by vulnerability ID.
+type Bundle struct {
+	Language  string            `json:"language"`
+	Summaries map[string]string `json:"summaries"`
+}
+
+func NewBundle(lang string) *Bundle {
+	return &Bundle{
+		Language:  lang,
+		Summaries: make(map[string]string),
+	}
+}
+
+// Len returns the number of translated summaries in the bundle.
+func (b *Bundle) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.Summaries)
+}
+
+// Summary returns the translated summary of the vulnerability, if there is one.
+func (b *Bundle) Summary(id string) (string, bool) {
+	if b == nil {
+		return "", false
+	}
+	s, ok := b.Summaries[id]
+	return s, ok && s != ""
+}
+
+// Merge adds the summaries of the other bundle, which take precedence over the summaries already in the bundle.
+func (b *Bundle) Merge(other *Bundle) {
+	if other == nil {
+		return
+	}
+	for id, s := range other.Summaries {
+		b.Summaries[id] = s
+	}
+}
+
+// Parse reads a bundle document, in the form {"language": "ja", "summaries": {"CVE-2021-44228": "..."}}.
+func Parse(data []byte) (*Bundle, error) {
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("unable to parse translation bundle: %w", err)
+	}
+	if err := ValidateLanguage(b.Language); err != nil {
+		return nil, fmt.Errorf("unable to parse translation bundle: %w", err)
+	}
+	if b.Summaries == nil {
+		b.Summaries = make(map[string]string)
+	}
+	return &b, nil
+}
+
+// sidecar reads translation bundles, which are single files.
+var sidecar = dataset.Sidecar[*Bundle]{
+	Kind:  "translation bundle",
+	Parse: Parse,
+}
+
+// FromPath reads a bundle from a file.
+func FromPath(path string) (*Bundle, error) {
+	return sidecar.FromFile(path)
+}
+
+// FromDBDir reads the bundle of the language shipped within the given DB directory, falling back to the bundle of
+// the base language (e.g. "pt" for "pt-BR"). A nil bundle (and no error) is returned when the DB does not ship one.
+func FromDBDir(dir, lang string) (*Bundle, error) {
+	for _, candidate := range fallbacks(lang) {
+		s := sidecar
+		s.Path = filepath.Join(DBDirName, candidate+".json")
+		b, err := s.FromDBDir(dir)
+		if b != nil || err != nil {
+			return b, err
+		}
+	}
+	return nil, nil
+}
+
+// Matches tells whether the bundle is in the language, or in the base language of the language.
+func (b *Bundle) Matches(lang string) bool {
+	for _, candidate := range fallbacks(lang) {
+		if strings.EqualFold(b.Language, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// fallbacks returns the language followed by its less specific tags (e.g. "zh-Hant-TW", "zh-Hant", "zh").
+func fallbacks(lang string) []string {
+	var tags []string
+	for lang != "" {
+		tags = append(tags, lang)
+		i := strings.LastIndex(lang, "-")
+		if i < 0 {
+			break
+		}
+		lang = lang[:i]
+	}
+	return tags
+}
@@ -0,0 +1,70 @@
+package localization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromDBDir(t *testing.T) {
+	tests := []struct {
+		lang     string
+		wantLang string
+		wantLen  int
+	}{
+		{lang: "ja", wantLang: "ja", wantLen: 1},
+		{lang: "pt-BR", wantLang: "pt", wantLen: 2},
+		{lang: "de"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			b, err := FromDBDir("test-fixtures/db", tt.lang)
+			require.NoError(t, err)
+			if tt.wantLang == "" {
+				assert.Nil(t, b)
+				return
+			}
+			require.NotNil(t, b)
+			assert.Equal(t, tt.wantLang, b.Language)
+			assert.Equal(t, tt.wantLen, b.Len())
+		})
+	}
+}
+
+func TestBundle_Merge(t *testing.T) {
+	b, err := FromDBDir("test-fixtures/db", "pt-BR")
+	require.NoError(t, err)
+	user, err := FromPath("test-fixtures/pt-BR.json")
+	require.NoError(t, err)
+	assert.True(t, user.Matches("pt-BR"))
+	assert.False(t, user.Matches("pt"), "a regional bundle does not apply to the base language")
+
+	b.Merge(user)
+	summary, ok := b.Summary("CVE-2022-22965")
+	require.True(t, ok)
+	assert.Contains(t, summary, "Um aplicativo")
+	summary, ok = b.Summary("CVE-2021-44228")
+	require.True(t, ok)
+	assert.Contains(t, summary, "Os recursos JNDI")
+	_, ok = b.Summary("CVE-2000-0001")
+	assert.False(t, ok)
+}
+
+func TestParse(t *testing.T) {
+	_, err := Parse([]byte(`{"summaries": {"CVE-2021-44228": "..."}}`))
+	assert.ErrorContains(t, err, "invalid language")
+
+	b, err := Parse([]byte(`{"language": "fr"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestValidateLanguage(t *testing.T) {
+	for _, lang := range []string{"ja", "pt-BR", "zh-Hant-TW"} {
+		assert.NoError(t, ValidateLanguage(lang), lang)
+	}
+	for _, lang := range []string{"", "japanese", "pt_BR", "../../etc"} {
+		assert.Error(t, ValidateLanguage(lang), lang)
+	}
+}
@@ -0,0 +1,67 @@
+package localization
+
+import (
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+)
+
+var _ interface {
+	vulnerability.MetadataProvider
+	store.DegradationReporter
+} = (*Provider)(nil)
+
+// Provider decorates an existing metadata provider, replacing the descriptions of vulnerabilities with their
+// translated summaries when the bundle has one (the original descriptions are kept otherwise).
+type Provider struct {
+	metadata vulnerability.MetadataProvider
+	bundle   *Bundle
+}
+
+func NewProvider(metadata vulnerability.MetadataProvider, bundle *Bundle) *Provider {
+	return &Provider{
+		metadata: metadata,
+		bundle:   bundle,
+	}
+}
+
+// Apply returns a copy of the given store with the bundle layered over its metadata provider. The store is returned
+// unchanged when the bundle is empty.
+func Apply(s *store.Store, bundle *Bundle) *store.Store {
+	if s == nil || bundle.Len() == 0 {
+		return s
+	}
+
+	log.WithFields("language", bundle.Language, "summaries", bundle.Len()).Debug("using translated vulnerability summaries")
+
+	return &store.Store{
+		Provider:          s.Provider,
+		MetadataProvider:  NewProvider(s.MetadataProvider, bundle),
+		ExclusionProvider: s.ExclusionProvider,
+		Targeting:         s.Targeting,
+	}
+}
+
+// Degradations returns the degradations of the decorated provider.
+func (p *Provider) Degradations() []grypeerr.DataQualityProblem {
+	return store.DegradationsOf(p.metadata)
+}
+
+func (p *Provider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	if p.metadata == nil {
+		return nil, nil
+	}
+	m, err := p.metadata.GetMetadata(id, namespace)
+	if err != nil || m == nil {
+		return m, err
+	}
+	summary, ok := p.bundle.Summary(id)
+	if !ok {
+		return m, nil
+	}
+	// the metadata may be shared with other callers
+	localized := *m
+	localized.Description = summary
+	return &localized, nil
+}
@@ -0,0 +1,50 @@
+package localization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+type mockMetadataProvider struct {
+	metadata map[string]*vulnerability.Metadata
+}
+
+func (p mockMetadataProvider) GetMetadata(id, _ string) (*vulnerability.Metadata, error) {
+	return p.metadata[id], nil
+}
+
+func TestProvider_GetMetadata(t *testing.T) {
+	original := &vulnerability.Metadata{ID: "CVE-2021-44228", Namespace: "nvd:cpe", Severity: "Critical", Description: "Apache Log4j2 JNDI features..."}
+	inner := mockMetadataProvider{metadata: map[string]*vulnerability.Metadata{
+		"CVE-2021-44228": original,
+		"CVE-2000-0001":  {ID: "CVE-2000-0001", Namespace: "nvd:cpe", Description: "untranslated"},
+	}}
+	b, err := FromDBDir("test-fixtures/db", "ja")
+	require.NoError(t, err)
+	p := NewProvider(inner, b)
+
+	m, err := p.GetMetadata("CVE-2021-44228", "nvd:cpe")
+	require.NoError(t, err)
+	assert.Contains(t, m.Description, "Apache Log4j2 の JNDI 機能")
+	assert.Equal(t, "Critical", m.Severity)
+	assert.Equal(t, "Apache Log4j2 JNDI features...", original.Description, "the metadata of the inner provider is not modified")
+
+	m, err = p.GetMetadata("CVE-2000-0001", "nvd:cpe")
+	require.NoError(t, err)
+	assert.Equal(t, "untranslated", m.Description)
+
+	m, err = p.GetMetadata("CVE-2099-0001", "nvd:cpe")
+	require.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestApply_emptyBundle(t *testing.T) {
+	s := &store.Store{}
+	assert.Same(t, s, Apply(s, nil))
+	assert.Same(t, s, Apply(s, NewBundle("ja")))
+}
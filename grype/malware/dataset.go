@@ -0,0 +1,166 @@
+/*
+Package malware matches packages against a dataset of known-malicious packages in the OSV format, such as the MAL-
+records of the OpenSSF malicious packages project (https://github.com/ossf/malicious-packages). Malicious packages are
+supply-chain compromises rather than vulnerabilities, and so are reported as findings of their own.
+*/
+package malware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/osv"
+	"github.com/anchore/grype/internal/dataset"
+	"github.com/anchore/grype/internal/fips"
+)
+
+const (
+	// DBDirName is the directory of the optional malware dataset that may be shipped alongside the vulnerability DB.
+	DBDirName = "malware"
+
+	// IDPrefix prefixes the IDs of the OSV records of malicious packages.
+	IDPrefix = "MAL-"
+
+	// originsKey is where the OpenSSF malicious packages records keep the reports the record was built from, which
+	// carry the digests of the malicious artifacts.
+	originsKey = "malicious-packages-origins"
+)
+
+var digestAlgorithms = []string{"sha1", "sha256", "sha512"}
+
+// Dataset is a collection of malicious package records, keyed by ID.
+type Dataset struct {
+	records map[string]osv.Vulnerability
+}
+
+func NewDataset() *Dataset {
+	return &Dataset{
+		records: make(map[string]osv.Vulnerability),
+	}
+}
+
+// Len returns the number of records in the dataset.
+func (d *Dataset) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.records)
+}
+
+// Add inserts the given record, replacing any existing record with the same ID. Records that are not of malicious
+// packages, or that were withdrawn, are ignored.
+func (d *Dataset) Add(r osv.Vulnerability) {
+	if !strings.HasPrefix(r.ID, IDPrefix) || r.Withdrawn != nil {
+		return
+	}
+	d.records[r.ID] = r
+}
+
+// Merge adds all records from the other dataset into this dataset. Records in the other dataset take precedence.
+func (d *Dataset) Merge(other *Dataset) {
+	if other == nil {
+		return
+	}
+	for _, r := range other.records {
+		d.Add(r)
+	}
+}
+
+// IDs returns all record IDs within the dataset in sorted order.
+func (d *Dataset) IDs() []string {
+	if d == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(d.records))
+	for id := range d.records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Parse reads OSV records, either as a single record object or as an array of records.
+func Parse(data []byte) (*Dataset, error) {
+	var records []osv.Vulnerability
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("unable to parse malware dataset: %w", err)
+		}
+	} else {
+		var single osv.Vulnerability
+		if err := json.Unmarshal(trimmed, &single); err != nil {
+			return nil, fmt.Errorf("unable to parse malware dataset: %w", err)
+		}
+		records = append(records, single)
+	}
+
+	d := NewDataset()
+	for _, r := range records {
+		d.Add(r)
+	}
+	return d, nil
+}
+
+// sidecar is the malware dataset shipped within the DB directory.
+var sidecar = dataset.Sidecar[*Dataset]{
+	Kind:      "malware dataset",
+	Path:      DBDirName,
+	Parse:     Parse,
+	New:       NewDataset,
+	Recursive: true,
+}
+
+// FromPath reads a dataset from a single JSON file or from every JSON file within a directory (recursively), as
+// found in a checkout of the OpenSSF malicious packages repository.
+func FromPath(path string) (*Dataset, error) {
+	return sidecar.FromPath(path)
+}
+
+// FromDBDir reads the malware dataset shipped within the given DB directory, if there is one. A nil dataset (and no
+// error) is returned when the DB does not ship a dataset.
+func FromDBDir(dir string) (*Dataset, error) {
+	return sidecar.FromDBDir(dir)
+}
+
+// recordDigests returns the digests of the malicious artifacts reported by the origins of the record (for the record
+// as a whole and for each affected package), as "algorithm:value" strings.
+func recordDigests(r osv.Vulnerability) []string {
+	digests := originDigests(r.DatabaseSpecific)
+	for _, a := range r.Affected {
+		digests = append(digests, originDigests(a.DatabaseSpecific)...)
+	}
+	return digests
+}
+
+func originDigests(databaseSpecific map[string]any) []string {
+	origins, ok := databaseSpecific[originsKey].([]any)
+	if !ok {
+		return nil
+	}
+	var digests []string
+	for _, origin := range origins {
+		fields, ok := origin.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, algorithm := range digestAlgorithms {
+			if !fips.Usable(algorithm) {
+				// in FIPS mode the artifacts are only identified by the digests of the approved algorithms
+				continue
+			}
+			if value, ok := fields[algorithm].(string); ok && value != "" {
+				digests = append(digests, digestKey(algorithm, value))
+			}
+		}
+	}
+	return digests
+}
+
+func digestKey(algorithm, value string) string {
+	return strings.ToLower(algorithm) + ":" + strings.ToLower(value)
+}
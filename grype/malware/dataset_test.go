@@ -0,0 +1,66 @@
+package malware
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/internal/fips"
+)
+
+func TestFromPath(t *testing.T) {
+	d, err := FromPath("test-fixtures/malicious-packages")
+	require.NoError(t, err)
+
+	// MAL-2021-0009 is withdrawn and GHSA-abcd-1234-wxyz is not of a malicious package
+	assert.Equal(t, []string{"MAL-2022-1001", "MAL-2023-2002", "MAL-2023-2004", "MAL-2024-3003"}, d.IDs())
+	assert.Equal(t, "Malicious code in reqeusts (PyPI)", d.records["MAL-2023-2002"].Summary)
+}
+
+func TestFromPath_file(t *testing.T) {
+	d, err := FromPath("test-fixtures/records.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MAL-2022-1001"}, d.IDs())
+
+	d, err = FromPath("test-fixtures/malicious-packages/pypi/reqeusts/MAL-2023-2002.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MAL-2023-2002"}, d.IDs())
+}
+
+func TestFromPath_invalid(t *testing.T) {
+	path := t.TempDir() + "/bad.json"
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err := FromPath(path)
+	assert.ErrorContains(t, err, "unable to parse malware dataset")
+
+	_, err = FromPath("test-fixtures/missing")
+	assert.ErrorContains(t, err, "unable to read malware dataset")
+}
+
+func TestDataset_nil(t *testing.T) {
+	var d *Dataset
+	assert.Zero(t, d.Len())
+	assert.Nil(t, d.Find(nil))
+}
+
+func TestRecordDigests(t *testing.T) {
+	d, err := FromPath("test-fixtures/malicious-packages/maven")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}, recordDigests(d.records["MAL-2024-3003"]))
+	assert.Empty(t, recordDigests(d.records["MAL-2022-1001"]))
+}
+
+func TestOriginDigests_fips(t *testing.T) {
+	databaseSpecific := map[string]any{
+		originsKey: []any{map[string]any{"sha1": "A94A8FE5", "sha256": "9F86D081"}},
+	}
+	assert.Equal(t, []string{"sha1:a94a8fe5", "sha256:9f86d081"}, originDigests(databaseSpecific))
+
+	fips.SetEnabled(true)
+	t.Cleanup(func() { fips.SetEnabled(false) })
+	assert.Equal(t, []string{"sha256:9f86d081"}, originDigests(databaseSpecific))
+}
@@ -0,0 +1,115 @@
+package malware
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/osv"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/internal/stringutil"
+)
+
+// MatchedBy is how a package was found to be malicious.
+type MatchedBy string
+
+const (
+	// DigestMatch is a package whose artifact has the digest of a known-malicious artifact.
+	DigestMatch MatchedBy = "digest"
+	// VersionMatch is a package whose name and version are of a known-malicious package.
+	VersionMatch MatchedBy = "version"
+)
+
+// Finding is a package found to be a known-malicious package.
+type Finding struct {
+	Package pkg.Package
+	// ID is the ID of the record of the malicious package (e.g. "MAL-2022-1234").
+	ID      string
+	Summary string
+	// MatchedBy is how the package was found, with the matching digest ("algorithm:value") for digest matches.
+	MatchedBy MatchedBy
+	Digest    string
+}
+
+// Find returns the packages found within the dataset, by the digests of their artifacts or else by their names and
+// versions, with at most one finding per package and record.
+func (d *Dataset) Find(packages []pkg.Package) []Finding {
+	if d.Len() == 0 {
+		return nil
+	}
+
+	byDigest := make(map[string][]string)
+	byPackage := make(map[string][]string)
+	for _, id := range d.IDs() {
+		r := d.records[id]
+		for _, digest := range recordDigests(r) {
+			byDigest[digest] = append(byDigest[digest], id)
+		}
+		for _, a := range r.Affected {
+			key := packageKey(a.Package.Ecosystem, a.Package.Name)
+			byPackage[key] = stringutil.AppendUnique(byPackage[key], id)
+		}
+	}
+
+	var findings []Finding
+	for _, p := range packages {
+		found := make(map[string]bool)
+		for _, digest := range packageDigests(p) {
+			for _, id := range byDigest[digest] {
+				if found[id] {
+					continue
+				}
+				found[id] = true
+				findings = append(findings, d.finding(p, id, DigestMatch, digest))
+			}
+		}
+
+		q, ok := osv.PackageQuery(p)
+		if !ok {
+			continue
+		}
+		for _, id := range byPackage[packageKey(q.Package.Ecosystem, q.Package.Name)] {
+			if found[id] || !d.records[id].Affects(q, p) {
+				continue
+			}
+			found[id] = true
+			findings = append(findings, d.finding(p, id, VersionMatch, ""))
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Package.Name != findings[j].Package.Name {
+			return findings[i].Package.Name < findings[j].Package.Name
+		}
+		if findings[i].Package.Version != findings[j].Package.Version {
+			return findings[i].Package.Version < findings[j].Package.Version
+		}
+		return findings[i].ID < findings[j].ID
+	})
+	return findings
+}
+
+func (d *Dataset) finding(p pkg.Package, id string, by MatchedBy, digest string) Finding {
+	return Finding{
+		Package:   p,
+		ID:        id,
+		Summary:   d.records[id].Summary,
+		MatchedBy: by,
+		Digest:    digest,
+	}
+}
+
+// packageDigests returns the digests of the artifacts of the package known from the catalog (see pkg.Package.Digests),
+// as "algorithm:value" strings.
+func packageDigests(p pkg.Package) []string {
+	var digests []string
+	for _, d := range p.Digests {
+		if d.Algorithm != "" && d.Value != "" {
+			digests = append(digests, digestKey(d.Algorithm, d.Value))
+		}
+	}
+	return digests
+}
+
+func packageKey(ecosystem, name string) string {
+	return strings.ToLower(ecosystem) + "/" + osv.NormalizeName(ecosystem, name)
+}
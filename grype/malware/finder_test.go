@@ -0,0 +1,98 @@
+package malware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestDataset_Find(t *testing.T) {
+	d, err := FromPath("test-fixtures/malicious-packages")
+	require.NoError(t, err)
+
+	evil := pkg.Package{Name: "evil-pkg", Version: "1.0.1", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript}
+	evilSafe := pkg.Package{Name: "evil-pkg", Version: "0.9.0", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript}
+	typo := pkg.Package{Name: "reqeusts", Version: "2.31.0", Type: syftPkg.PythonPkg, Language: syftPkg.Python}
+	leftPad := pkg.Package{Name: "left-pad", Version: "1.3.0", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript}
+	// the digest of the artifact matches, although the version is not one of the reported versions
+	repackaged := pkg.Package{
+		Name:     "bad-lib",
+		Version:  "2.0.0-repackaged",
+		Type:     syftPkg.JavaPkg,
+		Language: syftPkg.Java,
+		Digests: []pkg.Digest{
+			{Algorithm: "sha1", Value: "a94a8fe5ccb19ba61c4c0873d391e987982fbbd3"},
+			{Algorithm: "sha256", Value: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"},
+		},
+		Metadata: pkg.JavaMetadata{PomGroupID: "com.example", PomArtifactID: "bad-lib"},
+	}
+	// the integrity of the lockfile entry is the digest of the malicious tarball, published under another name
+	renamed := pkg.Package{
+		Name:     "evil-pkg-fork",
+		Version:  "3.0.0",
+		Type:     syftPkg.NpmPkg,
+		Language: syftPkg.JavaScript,
+		Digests:  []pkg.Digest{{Algorithm: "sha512", Value: "ee26b0dd4af7e749aa1a8ee3c10ae9923f618980772e473f8819a5d4940e0db27ac185f8a0e1d5f84f88bc887fd67b143732c304cc5fa9ad8e6f57f50028a8ff"}},
+	}
+	// python names are compared after PEP 503 normalization
+	dateutils := pkg.Package{Name: "Python_Dateutils", Version: "2.8.3", Type: syftPkg.PythonPkg, Language: syftPkg.Python}
+
+	findings := d.Find([]pkg.Package{typo, evilSafe, leftPad, evil, repackaged, renamed, dateutils})
+	assert.Equal(t, []Finding{
+		{
+			Package:   dateutils,
+			ID:        "MAL-2023-2004",
+			Summary:   "Malicious code in python-dateutils (PyPI)",
+			MatchedBy: VersionMatch,
+		},
+		{
+			Package:   repackaged,
+			ID:        "MAL-2024-3003",
+			Summary:   "Malicious code in com.example:bad-lib (Maven)",
+			MatchedBy: DigestMatch,
+			Digest:    "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		},
+		{
+			Package:   evil,
+			ID:        "MAL-2022-1001",
+			Summary:   "Malicious code in evil-pkg (npm)",
+			MatchedBy: VersionMatch,
+		},
+		{
+			Package:   renamed,
+			ID:        "MAL-2022-1001",
+			Summary:   "Malicious code in evil-pkg (npm)",
+			MatchedBy: DigestMatch,
+			Digest:    "sha512:ee26b0dd4af7e749aa1a8ee3c10ae9923f618980772e473f8819a5d4940e0db27ac185f8a0e1d5f84f88bc887fd67b143732c304cc5fa9ad8e6f57f50028a8ff",
+		},
+		{
+			Package:   typo,
+			ID:        "MAL-2023-2002",
+			Summary:   "Malicious code in reqeusts (PyPI)",
+			MatchedBy: VersionMatch,
+		},
+	}, findings)
+}
+
+func TestDataset_Find_digestAndVersion(t *testing.T) {
+	d, err := FromPath("test-fixtures/malicious-packages/maven")
+	require.NoError(t, err)
+
+	p := pkg.Package{
+		Name:     "bad-lib",
+		Version:  "2.0.0",
+		Type:     syftPkg.JavaPkg,
+		Language: syftPkg.Java,
+		Digests:  []pkg.Digest{{Algorithm: "SHA256", Value: "9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08"}},
+		Metadata: pkg.JavaMetadata{PomGroupID: "com.example", PomArtifactID: "bad-lib"},
+	}
+
+	// a package found by both its digest and its version is reported once, as a digest match
+	findings := d.Find([]pkg.Package{p})
+	require.Len(t, findings, 1)
+	assert.Equal(t, DigestMatch, findings[0].MatchedBy)
+}
@@ -13,7 +13,15 @@ type Detail struct {
 	SearchedBy interface{} // The specific attributes that were used to search (other than package name and version) --this indicates "how" the match was made.
 	Found      interface{} // The specific attributes on the vulnerability object that were matched with --this indicates "what" was matched on / within.
 	Matcher    MatcherType // The matcher object that discovered the match.
-	Confidence float64     // The certainty of the match as a ratio (currently unused, reserved for future use).
+	Confidence float64     // The certainty of the match as a ratio (scaled by the trust weight of the provider, see the trust package).
+	Alias      *Alias      // The alternate package name the match was found under, for an AliasMatch.
+}
+
+// Alias is an alternate name of a package that a match was found under (see AliasMatch).
+type Alias struct {
+	Name       string  // The alternate name of the package.
+	Source     string  // Where the alternate name comes from (e.g. a dictionary or a name normalization).
+	Confidence float64 // The certainty that the alternate name is the same package, as a ratio.
 }
 
 // String is the string representation of select match fields.
@@ -0,0 +1,233 @@
+package match
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EvidenceNodeKind is the kind of a node of the evidence graph of a match.
+type EvidenceNodeKind string
+
+const (
+	// PackageEvidence is the matched package.
+	PackageEvidence EvidenceNodeKind = "package"
+	// CriterionEvidence is an attribute of the package that was searched by (e.g. the distro, a CPE or the name of the
+	// package the installed package provides).
+	CriterionEvidence EvidenceNodeKind = "criterion"
+	// DataSourceEvidence is the vulnerability data that was searched (the namespace of the vulnerability records).
+	DataSourceEvidence EvidenceNodeKind = "data-source"
+	// VulnerabilityEvidence is the matched vulnerability.
+	VulnerabilityEvidence EvidenceNodeKind = "vulnerability"
+)
+
+// EvidenceRelation is the relation an edge of the evidence graph of a match stands for.
+type EvidenceRelation string
+
+const (
+	// HasCriterion links the package to a criterion: the criterion is an attribute of the package.
+	HasCriterion EvidenceRelation = "has"
+	// SearchedWith links a criterion to a data source: the data source was searched with the criterion.
+	SearchedWith EvidenceRelation = "searched"
+	// MatchedBy links a data source to the vulnerability: the decision of a matcher that the vulnerability record of the
+	// data source applies to the package.
+	MatchedBy EvidenceRelation = "matched"
+)
+
+// Evidence is the structured "why matched" graph of a match: how the package attributes (the criteria) were searched
+// against the vulnerability data (the data sources), and the decisions of the matchers that the vulnerability applies.
+// It carries the same information as the details of the match, in a shape that can be walked (e.g. rendered by a UI)
+// without knowing the ad-hoc SearchedBy and Found values of each matcher.
+type Evidence struct {
+	Nodes []EvidenceNode
+	Edges []EvidenceEdge
+}
+
+// EvidenceNode is a node of the evidence graph of a match.
+type EvidenceNode struct {
+	// ID identifies the node in the graph (e.g. "criterion:distro.type=debian").
+	ID   string
+	Kind EvidenceNodeKind
+	// Name is the name of the package, the attribute path of the criterion (e.g. "package.version" or "cpes"), the
+	// namespace of the data source or the ID of the vulnerability.
+	Name string
+	// Value is the version of the package, the value of the criterion or the namespace of the vulnerability.
+	Value string
+}
+
+// EvidenceEdge is an edge of the evidence graph of a match.
+type EvidenceEdge struct {
+	From     string
+	To       string
+	Relation EvidenceRelation
+	// Detail is the index of the detail of the match the edge comes from, so that the edges of a single search can be
+	// told apart when several matchers (or searches) found the vulnerability.
+	Detail int
+	// Matcher, Type, Confidence and Found describe the decision of a MatchedBy edge.
+	Matcher    MatcherType
+	Type       Type
+	Confidence float64
+	// Found are the attributes of the vulnerability record that were matched on (e.g. the version constraint).
+	Found []EvidenceAttribute
+}
+
+// EvidenceAttribute is an attribute of a SearchedBy or Found value, by its path (e.g. "distro.type").
+type EvidenceAttribute struct {
+	Name  string
+	Value string
+}
+
+// Criteria returns the attributes that were searched by, other than the namespace, sorted by path, followed by the
+// attributes of the alias the match was found under. A list gives an attribute per element (e.g. one per searched CPE).
+func (m Detail) Criteria() []EvidenceAttribute {
+	var criteria []EvidenceAttribute
+	for _, a := range flattenEvidence("", m.SearchedBy) {
+		if a.Name != "namespace" {
+			criteria = append(criteria, a)
+		}
+	}
+	if m.Alias != nil {
+		criteria = append(criteria, flattenEvidence("alias", map[string]interface{}{
+			"name":       m.Alias.Name,
+			"source":     m.Alias.Source,
+			"confidence": m.Alias.Confidence,
+		})...)
+	}
+	return criteria
+}
+
+// Findings returns the attributes of the vulnerability record that were matched on, sorted by path.
+func (m Detail) Findings() []EvidenceAttribute {
+	return flattenEvidence("", m.Found)
+}
+
+// Namespace returns the namespace that was searched, if the SearchedBy value records it.
+func (m Detail) Namespace() string {
+	for _, a := range flattenEvidence("", m.SearchedBy) {
+		if a.Name == "namespace" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// Evidence returns the evidence graph of the match, built from its details.
+func (m Match) Evidence() Evidence {
+	var e Evidence
+	seen := make(map[string]bool)
+	addNode := func(n EvidenceNode) {
+		if !seen[n.ID] {
+			seen[n.ID] = true
+			e.Nodes = append(e.Nodes, n)
+		}
+	}
+
+	pkgNode := EvidenceNode{ID: "package", Kind: PackageEvidence, Name: m.Package.Name, Value: m.Package.Version}
+	vulnNode := EvidenceNode{ID: "vulnerability:" + m.Vulnerability.ID, Kind: VulnerabilityEvidence, Name: m.Vulnerability.ID, Value: m.Vulnerability.Namespace}
+	addNode(pkgNode)
+	addNode(vulnNode)
+
+	for i, d := range m.Details {
+		namespace := d.Namespace()
+		if namespace == "" {
+			namespace = m.Vulnerability.Namespace
+		}
+		source := EvidenceNode{ID: "data-source:" + namespace, Kind: DataSourceEvidence, Name: namespace}
+		addNode(source)
+
+		for _, c := range d.Criteria() {
+			criterion := EvidenceNode{ID: criterionID(c), Kind: CriterionEvidence, Name: c.Name, Value: c.Value}
+			addNode(criterion)
+			e.Edges = append(e.Edges,
+				EvidenceEdge{From: pkgNode.ID, To: criterion.ID, Relation: HasCriterion, Detail: i},
+				EvidenceEdge{From: criterion.ID, To: source.ID, Relation: SearchedWith, Detail: i},
+			)
+		}
+
+		e.Edges = append(e.Edges, EvidenceEdge{
+			From:       source.ID,
+			To:         vulnNode.ID,
+			Relation:   MatchedBy,
+			Detail:     i,
+			Matcher:    d.Matcher,
+			Type:       d.Type,
+			Confidence: d.Confidence,
+			Found:      d.Findings(),
+		})
+	}
+	return e
+}
+
+// criterionIDEscaper escapes the "=" of the attribute paths of criteria (and the "%" of the escapes), so that the first
+// "=" of a criterion ID separates the path from the value and no two criteria have the same ID (e.g. the path "a=b" of
+// the value "c" and the path "a" of the value "b=c").
+var criterionIDEscaper = strings.NewReplacer("%", "%25", "=", "%3D")
+
+// criterionID returns the ID of the node of the criterion (e.g. "criterion:distro.type=debian").
+func criterionID(c EvidenceAttribute) string {
+	return "criterion:" + criterionIDEscaper.Replace(c.Name) + "=" + c.Value
+}
+
+// flattenEvidence returns the scalar attributes of a SearchedBy or Found value by path, sorted by path. Values other
+// than maps, lists and scalars (e.g. the structs of the CPE search) are read through their JSON form.
+func flattenEvidence(prefix string, v interface{}) []EvidenceAttribute {
+	var out []EvidenceAttribute
+	switch value := v.(type) {
+	case nil:
+	case string:
+		if value != "" {
+			out = append(out, EvidenceAttribute{Name: prefix, Value: value})
+		}
+	case bool:
+		out = append(out, EvidenceAttribute{Name: prefix, Value: strconv.FormatBool(value)})
+	case float64:
+		out = append(out, EvidenceAttribute{Name: prefix, Value: strconv.FormatFloat(value, 'g', -1, 64)})
+	case int:
+		out = append(out, EvidenceAttribute{Name: prefix, Value: strconv.Itoa(value)})
+	case map[string]string:
+		for _, k := range sortedKeys(value) {
+			out = append(out, flattenEvidence(joinPath(prefix, k), value[k])...)
+		}
+	case map[string]interface{}:
+		for _, k := range sortedKeys(value) {
+			out = append(out, flattenEvidence(joinPath(prefix, k), value[k])...)
+		}
+	case []string:
+		for _, s := range value {
+			out = append(out, flattenEvidence(prefix, s)...)
+		}
+	case []interface{}:
+		for _, s := range value {
+			out = append(out, flattenEvidence(prefix, s)...)
+		}
+	default:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return []EvidenceAttribute{{Name: prefix, Value: fmt.Sprint(value)}}
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return []EvidenceAttribute{{Name: prefix, Value: string(raw)}}
+		}
+		return flattenEvidence(prefix, decoded)
+	}
+	return out
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,125 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// cpeSearch stands in for the (JSON tagged) structs some matchers record as SearchedBy and Found values.
+type cpeSearch struct {
+	Namespace string   `json:"namespace"`
+	CPEs      []string `json:"cpes"`
+}
+
+func TestMatch_Evidence(t *testing.T) {
+	m := Match{
+		Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0001", Namespace: "debian:distro:debian:12"},
+		Package:       pkg.Package{Name: "libssl3", Version: "3.0.11-1"},
+		Details: Details{
+			{
+				Type:    ExactIndirectMatch,
+				Matcher: DpkgMatcher,
+				SearchedBy: map[string]interface{}{
+					"distro":     map[string]string{"type": "debian", "version": "12"},
+					"package":    map[string]string{"name": "openssl", "version": "3.0.11-1"},
+					"namespace":  "debian:distro:debian:12",
+					"providedBy": map[string]string{"name": "libssl3", "version": ""},
+				},
+				Found: map[string]interface{}{
+					"vulnerabilityID":   "CVE-2024-0001",
+					"versionConstraint": "< 3.0.11-2 (deb)",
+				},
+				Confidence: 1,
+			},
+			{
+				Type:       CPEMatch,
+				Matcher:    StockMatcher,
+				SearchedBy: cpeSearch{Namespace: "nvd:cpe", CPEs: []string{"cpe:2.3:a:openssl:openssl:3.0.11:*:*:*:*:*:*:*"}},
+				Found:      cpeSearch{CPEs: []string{"cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*"}},
+				Confidence: 0.9,
+			},
+		},
+	}
+
+	assert.Equal(t, Evidence{
+		Nodes: []EvidenceNode{
+			{ID: "package", Kind: PackageEvidence, Name: "libssl3", Value: "3.0.11-1"},
+			{ID: "vulnerability:CVE-2024-0001", Kind: VulnerabilityEvidence, Name: "CVE-2024-0001", Value: "debian:distro:debian:12"},
+			{ID: "data-source:debian:distro:debian:12", Kind: DataSourceEvidence, Name: "debian:distro:debian:12"},
+			{ID: "criterion:distro.type=debian", Kind: CriterionEvidence, Name: "distro.type", Value: "debian"},
+			{ID: "criterion:distro.version=12", Kind: CriterionEvidence, Name: "distro.version", Value: "12"},
+			{ID: "criterion:package.name=openssl", Kind: CriterionEvidence, Name: "package.name", Value: "openssl"},
+			{ID: "criterion:package.version=3.0.11-1", Kind: CriterionEvidence, Name: "package.version", Value: "3.0.11-1"},
+			{ID: "criterion:providedBy.name=libssl3", Kind: CriterionEvidence, Name: "providedBy.name", Value: "libssl3"},
+			{ID: "data-source:nvd:cpe", Kind: DataSourceEvidence, Name: "nvd:cpe"},
+			{ID: "criterion:cpes=cpe:2.3:a:openssl:openssl:3.0.11:*:*:*:*:*:*:*", Kind: CriterionEvidence, Name: "cpes", Value: "cpe:2.3:a:openssl:openssl:3.0.11:*:*:*:*:*:*:*"},
+		},
+		Edges: []EvidenceEdge{
+			{From: "package", To: "criterion:distro.type=debian", Relation: HasCriterion},
+			{From: "criterion:distro.type=debian", To: "data-source:debian:distro:debian:12", Relation: SearchedWith},
+			{From: "package", To: "criterion:distro.version=12", Relation: HasCriterion},
+			{From: "criterion:distro.version=12", To: "data-source:debian:distro:debian:12", Relation: SearchedWith},
+			{From: "package", To: "criterion:package.name=openssl", Relation: HasCriterion},
+			{From: "criterion:package.name=openssl", To: "data-source:debian:distro:debian:12", Relation: SearchedWith},
+			{From: "package", To: "criterion:package.version=3.0.11-1", Relation: HasCriterion},
+			{From: "criterion:package.version=3.0.11-1", To: "data-source:debian:distro:debian:12", Relation: SearchedWith},
+			{From: "package", To: "criterion:providedBy.name=libssl3", Relation: HasCriterion},
+			{From: "criterion:providedBy.name=libssl3", To: "data-source:debian:distro:debian:12", Relation: SearchedWith},
+			{
+				From: "data-source:debian:distro:debian:12", To: "vulnerability:CVE-2024-0001", Relation: MatchedBy,
+				Matcher: DpkgMatcher, Type: ExactIndirectMatch, Confidence: 1,
+				Found: []EvidenceAttribute{{Name: "versionConstraint", Value: "< 3.0.11-2 (deb)"}, {Name: "vulnerabilityID", Value: "CVE-2024-0001"}},
+			},
+			{From: "package", To: "criterion:cpes=cpe:2.3:a:openssl:openssl:3.0.11:*:*:*:*:*:*:*", Relation: HasCriterion, Detail: 1},
+			{From: "criterion:cpes=cpe:2.3:a:openssl:openssl:3.0.11:*:*:*:*:*:*:*", To: "data-source:nvd:cpe", Relation: SearchedWith, Detail: 1},
+			{
+				From: "data-source:nvd:cpe", To: "vulnerability:CVE-2024-0001", Relation: MatchedBy, Detail: 1,
+				Matcher: StockMatcher, Type: CPEMatch, Confidence: 0.9,
+				Found: []EvidenceAttribute{{Name: "cpes", Value: "cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*"}},
+			},
+		},
+	}, m.Evidence())
+}
+
+func TestMatch_Evidence_noNamespace(t *testing.T) {
+	// a detail that does not record the namespace it searched (e.g. a VEX statement) is of the vulnerability namespace
+	m := Match{
+		Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0002", Namespace: "github:language:go"},
+		Package:       pkg.Package{Name: "example.com/mod", Version: "v1.0.0"},
+		Details:       Details{{Type: ExactDirectMatch, Matcher: OpenVexMatcher, SearchedBy: map[string]interface{}{"alias": map[string]interface{}{"confidence": 0.5, "trusted": true}}}},
+	}
+	e := m.Evidence()
+	assert.Contains(t, e.Nodes, EvidenceNode{ID: "data-source:github:language:go", Kind: DataSourceEvidence, Name: "github:language:go"})
+	assert.Contains(t, e.Nodes, EvidenceNode{ID: "criterion:alias.confidence=0.5", Kind: CriterionEvidence, Name: "alias.confidence", Value: "0.5"})
+	assert.Contains(t, e.Nodes, EvidenceNode{ID: "criterion:alias.trusted=true", Kind: CriterionEvidence, Name: "alias.trusted", Value: "true"})
+	assert.Equal(t, "", m.Details[0].Namespace())
+	assert.Empty(t, m.Details[0].Findings())
+}
+
+func TestMatch_Evidence_criterionIDs(t *testing.T) {
+	// the path "a=b" of the value "c" and the path "a" of the value "b=c" are distinct criteria
+	m := Match{
+		Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0003", Namespace: "nvd:cpe"},
+		Package:       pkg.Package{Name: "example", Version: "1.0"},
+		Details: Details{{Type: ExactDirectMatch, Matcher: StockMatcher, SearchedBy: map[string]interface{}{
+			"a=b": "c",
+			"a":   "b=c",
+			"d%":  "e",
+		}}},
+	}
+	var criteria []EvidenceNode
+	for _, n := range m.Evidence().Nodes {
+		if n.Kind == CriterionEvidence {
+			criteria = append(criteria, n)
+		}
+	}
+	assert.Equal(t, []EvidenceNode{
+		{ID: "criterion:a=b=c", Kind: CriterionEvidence, Name: "a", Value: "b=c"},
+		{ID: "criterion:a%3Db=c", Kind: CriterionEvidence, Name: "a=b", Value: "c"},
+		{ID: "criterion:d%25=e", Kind: CriterionEvidence, Name: "d%", Value: "e"},
+	}, criteria)
+}
@@ -0,0 +1,39 @@
+package match
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// idNamespace is the UUID namespace that match IDs are generated within.
+var idNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/anchore/grype/match"))
+
+// ID returns an identifier for the match that is the same across scans for as long as the same vulnerability
+// (ID and namespace) affects the same package (package URL, version and locations). Unlike the package ID used by the
+// Fingerprint, this does not depend on the package metadata that happened to be cataloged, so it is suitable for
+// external systems to track the lifecycle of a finding with.
+//
+// The ID is a name-based (version 5) UUID.
+func (m Match) ID() string {
+	identity := m.Package.PURL
+	if identity == "" {
+		identity = string(m.Package.Type) + "/" + m.Package.Name
+	}
+
+	var locations []string
+	for _, l := range m.Package.Locations.ToSlice() {
+		locations = append(locations, l.RealPath)
+	}
+	sort.Strings(locations)
+
+	fields := []string{
+		identity,
+		m.Package.Version,
+		m.Vulnerability.ID,
+		m.Vulnerability.Namespace,
+		strings.Join(locations, ","),
+	}
+	return uuid.NewSHA1(idNamespace, []byte(strings.Join(fields, "\x00"))).String()
+}
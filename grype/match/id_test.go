@@ -0,0 +1,75 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/file"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestMatch_ID(t *testing.T) {
+	base := func() Match {
+		return Match{
+			Vulnerability: vulnerability.Vulnerability{
+				ID:        "CVE-2020-0001",
+				Namespace: "github:language:javascript",
+			},
+			Package: pkg.Package{
+				ID:        pkg.ID(uuid.NewString()),
+				Name:      "lodash",
+				Version:   "4.17.20",
+				Type:      syftPkg.NpmPkg,
+				PURL:      "pkg:npm/lodash@4.17.20",
+				Locations: file.NewLocationSet(file.NewLocation("/app/b/package.json"), file.NewLocation("/app/a/package.json")),
+			},
+		}
+	}
+
+	id := base().ID()
+	_, err := uuid.Parse(id)
+	require.NoError(t, err)
+
+	t.Run("stable across package IDs, metadata and details", func(t *testing.T) {
+		m := base()
+		m.Package.Metadata = pkg.JavaMetadata{VirtualPath: "somewhere"}
+		m.Vulnerability.Fix.Versions = []string{"4.17.21"}
+		m.Details = Details{{Type: ExactDirectMatch, Matcher: JavascriptMatcher}}
+		assert.Equal(t, id, m.ID())
+	})
+
+	t.Run("stable across location order", func(t *testing.T) {
+		m := base()
+		m.Package.Locations = file.NewLocationSet(file.NewLocation("/app/a/package.json"), file.NewLocation("/app/b/package.json"))
+		assert.Equal(t, id, m.ID())
+	})
+
+	changes := map[string]func(m *Match){
+		"vulnerability ID":        func(m *Match) { m.Vulnerability.ID = "CVE-2020-0002" },
+		"vulnerability namespace": func(m *Match) { m.Vulnerability.Namespace = "nvd:cpe" },
+		"package URL":             func(m *Match) { m.Package.PURL = "pkg:npm/lodash-es@4.17.20" },
+		"package version":         func(m *Match) { m.Package.Version = "4.17.21" },
+		"package locations":       func(m *Match) { m.Package.Locations = file.NewLocationSet(file.NewLocation("/app/c/package.json")) },
+	}
+	for name, change := range changes {
+		t.Run("changes with "+name, func(t *testing.T) {
+			m := base()
+			change(&m)
+			assert.NotEqual(t, id, m.ID())
+		})
+	}
+
+	t.Run("falls back to the package type and name without a package URL", func(t *testing.T) {
+		m := base()
+		m.Package.PURL = ""
+		other := base()
+		other.Package.PURL = ""
+		other.Package.Name = "underscore"
+		assert.NotEqual(t, m.ID(), other.ID())
+	})
+}
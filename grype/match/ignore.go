@@ -1,9 +1,17 @@
 package match
 
 import (
+	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/bmatcuk/doublestar/v2"
+
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
 )
 
 // An IgnoredMatch is a vulnerability Match that has been ignored because one or more IgnoreRules applied to the match.
@@ -27,6 +35,18 @@ type IgnoreRule struct {
 	VexStatus        string            `yaml:"vex-status" json:"vex-status" mapstructure:"vex-status"`
 	VexJustification string            `yaml:"vex-justification" json:"vex-justification" mapstructure:"vex-justification"`
 	MatchType        Type              `yaml:"match-type" json:"match-type" mapstructure:"match-type"`
+	// PublishedWithin and ModifiedWithin are ages (e.g. "48h" or "7d"): the rule applies to the vulnerabilities
+	// published (or last modified) within that time before the scan. PublishedBefore and ModifiedBefore are dates
+	// (e.g. "2024-03-01"): the rule applies to the vulnerabilities published (or last modified) before that date. The
+	// dates are those of the vulnerability data, so the rules never apply to vulnerabilities without them.
+	PublishedWithin string `yaml:"published-within" json:"published-within" mapstructure:"published-within"`
+	PublishedBefore string `yaml:"published-before" json:"published-before" mapstructure:"published-before"`
+	ModifiedWithin  string `yaml:"modified-within" json:"modified-within" mapstructure:"modified-within"`
+	ModifiedBefore  string `yaml:"modified-before" json:"modified-before" mapstructure:"modified-before"`
+	// Environment restricts the rule to the scans of an environment context (e.g. "env=dev,exposure=internal"), which
+	// has all of the given values: the rule never applies to the scans without a context (e.g. with ApplyIgnoreRules).
+	// It is not a criterion by itself, so it is given along with other criteria.
+	Environment string `yaml:"environment" json:"environment" mapstructure:"environment"`
 }
 
 // IgnoreRulePackage describes the Package-specific fields that comprise the IgnoreRule.
@@ -46,14 +66,42 @@ type IgnoreRulePackage struct {
 // ApplyIgnoreRules returns two collections: the matches that are not being
 // ignored, and the matches that are being ignored.
 func ApplyIgnoreRules(matches Matches, rules []IgnoreRule) (Matches, []IgnoredMatch) {
+	return ApplyIgnoreRulesInEnvironment(matches, rules, nil, nil)
+}
+
+// ApplyIgnoreRulesWithMetadata applies the ignore rules as ApplyIgnoreRules does, evaluating the criteria of the rules
+// on the publication and modification dates of the vulnerabilities against the metadata of the given provider (without
+// a provider, the rules with such criteria never apply). A warning is logged when the date criteria of a rule could
+// not be evaluated, for lack of a provider or of the dates of a vulnerability, since the rule then did not apply.
+func ApplyIgnoreRulesWithMetadata(matches Matches, rules []IgnoreRule, provider vulnerability.MetadataProvider) (Matches, []IgnoredMatch) {
+	return ApplyIgnoreRulesInEnvironment(matches, rules, provider, nil)
+}
+
+// ApplyIgnoreRulesInEnvironment applies the ignore rules as ApplyIgnoreRulesWithMetadata does, in the environment
+// context of the scan (e.g. "env" is "dev"): the rules restricted to an environment that the context does not have
+// (see IgnoreRule.AppliesInEnvironment) do not apply.
+func ApplyIgnoreRulesInEnvironment(matches Matches, rules []IgnoreRule, provider vulnerability.MetadataProvider, environment map[string]string) (Matches, []IgnoredMatch) {
+	var applicable []IgnoreRule
+	for _, rule := range rules {
+		if rule.AppliesInEnvironment(environment) {
+			applicable = append(applicable, rule)
+		}
+	}
+	rules = applicable
+
 	var ignoredMatches []IgnoredMatch
 	remainingMatches := NewMatches()
+	dates := newVulnerabilityDates(provider, time.Now())
+	if dates == nil && hasDateCriteria(rules) && matches.Count() > 0 {
+		log.Warn("unable to evaluate the date criteria of the ignore rules without the vulnerability metadata, the rules with these criteria do not apply")
+	}
+	defer dates.warnUndated()
 
 	for _, match := range matches.Sorted() {
 		var applicableRules []IgnoreRule
 
 		for _, rule := range rules {
-			if shouldIgnore(match, rule) {
+			if shouldIgnore(match, rule, dates) {
 				applicableRules = append(applicableRules, rule)
 			}
 		}
@@ -73,13 +121,13 @@ func ApplyIgnoreRules(matches Matches, rules []IgnoreRule) (Matches, []IgnoredMa
 	return remainingMatches, ignoredMatches
 }
 
-func shouldIgnore(match Match, rule IgnoreRule) bool {
+func shouldIgnore(match Match, rule IgnoreRule, dates *vulnerabilityDates) bool {
 	// VEX rules are handled by the vex processor
 	if rule.VexStatus != "" {
 		return false
 	}
 
-	ignoreConditions := getIgnoreConditionsForRule(rule)
+	ignoreConditions := getIgnoreConditionsForRule(rule, dates)
 	if len(ignoreConditions) == 0 {
 		// this rule specifies no criteria, so it doesn't apply to the Match
 		return false
@@ -96,17 +144,67 @@ func shouldIgnore(match Match, rule IgnoreRule) bool {
 	return true
 }
 
+func hasDateCriteria(rules []IgnoreRule) bool {
+	for _, r := range rules {
+		if r.VexStatus == "" && (r.PublishedWithin != "" || r.PublishedBefore != "" || r.ModifiedWithin != "" || r.ModifiedBefore != "") {
+			return true
+		}
+	}
+	return false
+}
+
+// AppliesInEnvironment returns true if the rule applies in the environment context (e.g. "env" is "dev"): the rule is
+// not restricted to an environment, or the context has every value of its environment (compared ignoring case). A rule
+// whose environment cannot be parsed applies in no context.
+func (ir IgnoreRule) AppliesInEnvironment(environment map[string]string) bool {
+	if ir.Environment == "" {
+		return true
+	}
+	for _, pair := range strings.Split(ir.Environment, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if !ok || value == "" || !strings.EqualFold(environment[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
 // HasConditions returns true if the ignore rule has conditions
 // that can cause a match to be ignored
 func (ir IgnoreRule) HasConditions() bool {
-	return len(getIgnoreConditionsForRule(ir)) == 0
+	return len(getIgnoreConditionsForRule(ir, nil)) == 0
+}
+
+// Validate checks that the dates and ages of the rule can be parsed.
+func (ir IgnoreRule) Validate() error {
+	for _, age := range []struct{ name, value string }{{"published-within", ir.PublishedWithin}, {"modified-within", ir.ModifiedWithin}} {
+		if age.value == "" {
+			continue
+		}
+		if _, err := parseAge(age.value); err != nil {
+			return fmt.Errorf("bad %s value %q: %w", age.name, age.value, err)
+		}
+	}
+	for _, date := range []struct{ name, value string }{{"published-before", ir.PublishedBefore}, {"modified-before", ir.ModifiedBefore}} {
+		if date.value == "" {
+			continue
+		}
+		if _, err := parseDate(date.value); err != nil {
+			return fmt.Errorf("bad %s value %q: %w", date.name, date.value, err)
+		}
+	}
+	return nil
 }
 
 // An ignoreCondition is a function that returns a boolean indicating whether
 // the given Match should be ignored.
 type ignoreCondition func(match Match) bool
 
-func getIgnoreConditionsForRule(rule IgnoreRule) []ignoreCondition {
+func getIgnoreConditionsForRule(rule IgnoreRule, dates *vulnerabilityDates) []ignoreCondition {
 	var ignoreConditions []ignoreCondition
 
 	if v := rule.Vulnerability; v != "" {
@@ -148,6 +246,23 @@ func getIgnoreConditionsForRule(rule IgnoreRule) []ignoreCondition {
 	if matchType := rule.MatchType; matchType != "" {
 		ignoreConditions = append(ignoreConditions, ifMatchTypeApplies(matchType))
 	}
+
+	// the date conditions come last, since they look up the metadata of the vulnerability
+	if age := rule.PublishedWithin; age != "" {
+		ignoreConditions = append(ignoreConditions, ifPublishedWithin(age, dates))
+	}
+
+	if date := rule.PublishedBefore; date != "" {
+		ignoreConditions = append(ignoreConditions, ifPublishedBefore(date, dates))
+	}
+
+	if age := rule.ModifiedWithin; age != "" {
+		ignoreConditions = append(ignoreConditions, ifModifiedWithin(age, dates))
+	}
+
+	if date := rule.ModifiedBefore; date != "" {
+		ignoreConditions = append(ignoreConditions, ifModifiedBefore(date, dates))
+	}
 	return ignoreConditions
 }
 
@@ -256,3 +371,141 @@ func ruleLocationAppliesToPath(location, path string) bool {
 
 	return doesMatch
 }
+
+func ifPublishedWithin(age string, dates *vulnerabilityDates) ignoreCondition {
+	return ifDateWithin(age, dates, publishedDate)
+}
+
+func ifPublishedBefore(date string, dates *vulnerabilityDates) ignoreCondition {
+	return ifDateBefore(date, dates, publishedDate)
+}
+
+func ifModifiedWithin(age string, dates *vulnerabilityDates) ignoreCondition {
+	return ifDateWithin(age, dates, modifiedDate)
+}
+
+func ifModifiedBefore(date string, dates *vulnerabilityDates) ignoreCondition {
+	return ifDateBefore(date, dates, modifiedDate)
+}
+
+func publishedDate(m *vulnerability.Metadata) *time.Time {
+	return m.Published
+}
+
+func modifiedDate(m *vulnerability.Metadata) *time.Time {
+	return m.Modified
+}
+
+func ifDateWithin(age string, dates *vulnerabilityDates, date func(*vulnerability.Metadata) *time.Time) ignoreCondition {
+	d, err := parseAge(age)
+	if err != nil || dates == nil {
+		return func(Match) bool { return false }
+	}
+	since := dates.now.Add(-d)
+
+	return func(match Match) bool {
+		t := dates.get(match, date)
+		return t != nil && !t.Before(since)
+	}
+}
+
+func ifDateBefore(value string, dates *vulnerabilityDates, date func(*vulnerability.Metadata) *time.Time) ignoreCondition {
+	before, err := parseDate(value)
+	if err != nil || dates == nil {
+		return func(Match) bool { return false }
+	}
+
+	return func(match Match) bool {
+		t := dates.get(match, date)
+		return t != nil && t.Before(before)
+	}
+}
+
+// parseAge parses an age as a duration (e.g. "48h"), or a number of days (e.g. "7d").
+func parseAge(age string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(age, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("expected a duration (e.g. '48h') or a number of days (e.g. '7d')")
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(age); err != nil {
+			return 0, fmt.Errorf("expected a duration (e.g. '48h') or a number of days (e.g. '7d')")
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("expected a positive age")
+	}
+	return d, nil
+}
+
+// parseDate parses a date (e.g. "2024-03-01", at midnight UTC) or a timestamp (e.g. "2024-03-01T12:00:00Z").
+func parseDate(date string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, date); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a date (e.g. '2024-03-01') or an RFC 3339 timestamp")
+	}
+	return t, nil
+}
+
+// vulnerabilityDates looks up the metadata of the vulnerabilities of the matches, once per vulnerability, for the
+// date criteria of the rules, evaluated relative to the time of the scan.
+type vulnerabilityDates struct {
+	provider vulnerability.MetadataProvider
+	now      time.Time
+	metadata map[vulnerability.Reference]*vulnerability.Metadata
+	// undated are the vulnerabilities a date criterion was evaluated on without the date in their metadata
+	undated map[vulnerability.Reference]bool
+}
+
+func newVulnerabilityDates(provider vulnerability.MetadataProvider, now time.Time) *vulnerabilityDates {
+	if provider == nil {
+		return nil
+	}
+	return &vulnerabilityDates{
+		provider: provider,
+		now:      now,
+		metadata: make(map[vulnerability.Reference]*vulnerability.Metadata),
+		undated:  make(map[vulnerability.Reference]bool),
+	}
+}
+
+func (d *vulnerabilityDates) get(match Match, date func(*vulnerability.Metadata) *time.Time) *time.Time {
+	ref := vulnerability.Reference{ID: match.Vulnerability.ID, Namespace: match.Vulnerability.Namespace}
+	m, ok := d.metadata[ref]
+	if !ok {
+		var err error
+		m, err = d.provider.GetMetadata(ref.ID, ref.Namespace)
+		if err != nil {
+			log.WithFields("vulnerability", ref.ID, "namespace", ref.Namespace, "error", err).Debug("unable to get the metadata of the vulnerability for the ignore rules")
+		}
+		d.metadata[ref] = m
+	}
+	var t *time.Time
+	if m != nil {
+		t = date(m)
+	}
+	if t == nil {
+		d.undated[ref] = true
+	}
+	return t
+}
+
+// warnUndated warns of the vulnerabilities that the date criteria of the rules did not apply to for lack of dates.
+func (d *vulnerabilityDates) warnUndated() {
+	if d == nil || len(d.undated) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(d.undated))
+	for ref := range d.undated {
+		ids = append(ids, ref.ID)
+	}
+	sort.Strings(ids)
+	log.WithFields("count", len(ids), "vulnerabilities", strings.Join(ids, ", ")).Warn("the vulnerability data has no publication or modification dates for some vulnerabilities, the date criteria of the ignore rules do not apply to them")
+}
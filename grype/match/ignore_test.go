@@ -1,7 +1,9 @@
 package match
 
 import (
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -676,8 +678,14 @@ func TestApplyIgnoreRules(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			actualRemainingMatches, actualIgnoredMatches := ApplyIgnoreRules(sliceToMatches(testCase.allMatches), testCase.ignoreRules)
 
-			assertMatchOrder(t, testCase.expectedRemainingMatches, actualRemainingMatches.Sorted())
-			assertIgnoredMatchOrder(t, testCase.expectedIgnoredMatches, actualIgnoredMatches)
+			// the expectations are listed by vulnerability, results are in the canonical match order (see ByElements)
+			expectedRemainingMatches := append([]Match{}, testCase.expectedRemainingMatches...)
+			sort.Sort(ByElements(expectedRemainingMatches))
+			expectedIgnoredMatches := append([]IgnoredMatch(nil), testCase.expectedIgnoredMatches...)
+			SortIgnored(expectedIgnoredMatches)
+
+			assertMatchOrder(t, expectedRemainingMatches, actualRemainingMatches.Sorted())
+			assertIgnoredMatchOrder(t, expectedIgnoredMatches, actualIgnoredMatches)
 
 		})
 	}
@@ -707,6 +715,137 @@ var (
 	}
 )
 
+type datesMetadataProvider map[string]*vulnerability.Metadata
+
+func (p datesMetadataProvider) GetMetadata(id, _ string) (*vulnerability.Metadata, error) {
+	return p[id], nil
+}
+
+func TestApplyIgnoreRulesWithMetadata(t *testing.T) {
+	ago := func(d time.Duration) *time.Time {
+		t := time.Now().Add(-d)
+		return &t
+	}
+	provider := datesMetadataProvider{
+		"CVE-NEW":    {ID: "CVE-NEW", Published: ago(2 * time.Hour), Modified: ago(time.Hour)},
+		"CVE-OLD":    {ID: "CVE-OLD", Published: ago(400 * 24 * time.Hour), Modified: ago(2 * time.Hour)},
+		"CVE-NODATE": {ID: "CVE-NODATE"},
+	}
+	var matches []Match
+	for _, id := range []string{"CVE-NEW", "CVE-OLD", "CVE-NODATE"} {
+		matches = append(matches, Match{
+			Vulnerability: vulnerability.Vulnerability{ID: id, Namespace: "nvd:cpe"},
+			Package:       pkg.Package{ID: pkg.ID(id), Name: "dive", Version: "0.5.2"},
+		})
+	}
+	lastAudit := time.Now().Add(-30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name    string
+		rules   []IgnoreRule
+		ignored []string
+	}{
+		{
+			name:    "published within",
+			rules:   []IgnoreRule{{PublishedWithin: "48h"}},
+			ignored: []string{"CVE-NEW"},
+		},
+		{
+			name:    "published within days",
+			rules:   []IgnoreRule{{PublishedWithin: "500d"}},
+			ignored: []string{"CVE-NEW", "CVE-OLD"},
+		},
+		{
+			name:    "published before",
+			rules:   []IgnoreRule{{PublishedBefore: lastAudit}},
+			ignored: []string{"CVE-OLD"},
+		},
+		{
+			name:    "modified within",
+			rules:   []IgnoreRule{{ModifiedWithin: "3h"}},
+			ignored: []string{"CVE-NEW", "CVE-OLD"},
+		},
+		{
+			name:    "modified before",
+			rules:   []IgnoreRule{{ModifiedBefore: "2000-01-01"}},
+			ignored: nil,
+		},
+		{
+			name:    "combined with other criteria",
+			rules:   []IgnoreRule{{Vulnerability: "CVE-OLD", ModifiedWithin: "3h"}},
+			ignored: []string{"CVE-OLD"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ignored := ApplyIgnoreRulesWithMetadata(NewMatches(matches...), tt.rules, provider)
+			var ids []string
+			for _, i := range ignored {
+				ids = append(ids, i.Vulnerability.ID)
+			}
+			sort.Strings(ids)
+			assert.Equal(t, tt.ignored, ids)
+
+			// without metadata, the date criteria never apply
+			_, ignored = ApplyIgnoreRules(NewMatches(matches...), tt.rules)
+			assert.Empty(t, ignored)
+		})
+	}
+}
+
+func TestVulnerabilityDates_undated(t *testing.T) {
+	published := time.Now()
+	provider := datesMetadataProvider{
+		"CVE-DATED":  {ID: "CVE-DATED", Published: &published},
+		"CVE-NODATE": {ID: "CVE-NODATE"},
+	}
+	dates := newVulnerabilityDates(provider, time.Now())
+	for _, id := range []string{"CVE-DATED", "CVE-NODATE", "CVE-UNKNOWN"} {
+		dates.get(Match{Vulnerability: vulnerability.Vulnerability{ID: id}}, publishedDate)
+	}
+
+	// the vulnerabilities without the date, or without metadata at all, are warned of
+	assert.Equal(t, map[vulnerability.Reference]bool{{ID: "CVE-NODATE"}: true, {ID: "CVE-UNKNOWN"}: true}, dates.undated)
+	assert.True(t, hasDateCriteria([]IgnoreRule{{Vulnerability: "CVE-1"}, {ModifiedBefore: "2024-03-01"}}))
+	assert.False(t, hasDateCriteria([]IgnoreRule{{Vulnerability: "CVE-1"}, {PublishedWithin: "48h", VexStatus: "not_affected"}}))
+}
+
+func TestApplyIgnoreRulesInEnvironment(t *testing.T) {
+	rules := []IgnoreRule{
+		{Vulnerability: "CVE-2000-1234", Environment: "env=dev,exposure=internal"},
+	}
+
+	tests := []struct {
+		name        string
+		environment map[string]string
+		ignored     bool
+	}{
+		{name: "satisfied", environment: map[string]string{"env": "DEV", "exposure": "internal", "team": "a"}, ignored: true},
+		{name: "another environment", environment: map[string]string{"env": "prod", "exposure": "internal"}},
+		{name: "partially satisfied", environment: map[string]string{"env": "dev"}},
+		{name: "without a context"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ignored := ApplyIgnoreRulesInEnvironment(NewMatches(exampleMatch), rules, nil, tt.environment)
+			assert.Equal(t, tt.ignored, len(ignored) == 1)
+		})
+	}
+
+	// without a context (e.g. a library scan), the rules restricted to an environment do not apply
+	_, ignored := ApplyIgnoreRules(NewMatches(exampleMatch), rules)
+	assert.Empty(t, ignored)
+	assert.True(t, IgnoreRule{Vulnerability: "CVE-2000-1234"}.AppliesInEnvironment(nil))
+	assert.False(t, IgnoreRule{Environment: "env"}.AppliesInEnvironment(map[string]string{"env": "dev"}))
+}
+
+func TestIgnoreRule_Validate(t *testing.T) {
+	assert.NoError(t, IgnoreRule{PublishedWithin: "48h", PublishedBefore: "2024-03-01", ModifiedWithin: "7d", ModifiedBefore: "2024-03-01T12:00:00Z"}.Validate())
+	assert.ErrorContains(t, IgnoreRule{PublishedWithin: "two days"}.Validate(), "bad published-within value")
+	assert.ErrorContains(t, IgnoreRule{ModifiedWithin: "-1h"}.Validate(), "expected a positive age")
+	assert.ErrorContains(t, IgnoreRule{PublishedBefore: "03/01/2024"}.Validate(), "bad published-before value")
+}
+
 func TestShouldIgnore(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -815,7 +954,7 @@ func TestShouldIgnore(t *testing.T) {
 
 	for _, testCase := range cases {
 		t.Run(testCase.name, func(t *testing.T) {
-			actual := shouldIgnore(testCase.match, testCase.rule)
+			actual := shouldIgnore(testCase.match, testCase.rule, nil)
 			assert.Equal(t, testCase.expected, actual)
 		})
 	}
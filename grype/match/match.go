@@ -19,6 +19,8 @@ type Match struct {
 	Vulnerability vulnerability.Vulnerability // The vulnerability details of the match.
 	Package       pkg.Package                 // The package used to search for a match.
 	Details       Details                     // all ways in which how this particular match was made.
+
+	SeverityOverride *SeverityOverride // the severity rating that replaces the rating from the vulnerability data, if any
 }
 
 // String is the string representation of select match fields.
@@ -16,6 +16,11 @@ const (
 	GoModuleMatcher    MatcherType = "go-module-matcher"
 	OpenVexMatcher     MatcherType = "openvex-matcher"
 	RustMatcher        MatcherType = "rust-matcher"
+	OpkgMatcher        MatcherType = "opkg-matcher"
+	MacOSMatcher       MatcherType = "macos-matcher"
+	AlpmMatcher        MatcherType = "alpm-matcher"
+	NixMatcher         MatcherType = "nix-matcher"
+	RuntimeMatcher     MatcherType = "runtime-matcher"
 )
 
 var AllMatcherTypes = []MatcherType{
@@ -32,6 +37,11 @@ var AllMatcherTypes = []MatcherType{
 	GoModuleMatcher,
 	OpenVexMatcher,
 	RustMatcher,
+	OpkgMatcher,
+	MacOSMatcher,
+	AlpmMatcher,
+	NixMatcher,
+	RuntimeMatcher,
 }
 
 type MatcherType string
@@ -45,11 +45,8 @@ func (r *Matches) AllByPkgID() map[pkg.ID][]Match {
 }
 
 func (r *Matches) Merge(other Matches) {
-	for _, fingerprints := range other.byPackage {
-		for _, fingerprint := range fingerprints {
-			r.Add(other.byFingerprint[fingerprint])
-		}
-	}
+	// matches are added in sorted order so the merged result does not depend on map iteration order
+	r.Add(other.Sorted()...)
 }
 
 func (r *Matches) Diff(other Matches) *Matches {
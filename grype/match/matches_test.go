@@ -134,8 +134,40 @@ func TestMatchesSortMixedDimensions(t *testing.T) {
 	}
 	matches := NewMatches(input...)
 
-	assertMatchOrder(t, []Match{first, second, third, fourth, fifth, sixth, seventh, eighth, ninth}, matches.Sorted())
+	// sorted by package first (name, version, type), then by vulnerability
+	assertMatchOrder(t, []Match{second, third, first, fourth, fifth, sixth, seventh, eighth, ninth}, matches.Sorted())
 
+	// sorting must not reorder the fix versions of the matches themselves
+	assert.Equal(t, []string{"2.0.0", "1.0.0"}, sixth.Vulnerability.Fix.Versions)
+	for _, m := range matches.Sorted() {
+		if m.Package.ID == sixth.Package.ID {
+			assert.Equal(t, []string{"2.0.0", "1.0.0"}, m.Vulnerability.Fix.Versions)
+		}
+	}
+}
+
+func TestMatchesSortIndependentOfInsertionOrder(t *testing.T) {
+	newMatch := func(id, namespace string) Match {
+		return Match{
+			Vulnerability: vulnerability.Vulnerability{ID: "CVE-2020-0010", Namespace: namespace},
+			Package: pkg.Package{
+				ID:        pkg.ID(id),
+				Name:      "package-a",
+				Version:   "1.0.0",
+				Type:      syftPkg.RpmPkg,
+				Locations: file.NewLocationSet(file.NewLocation("/same/path")),
+			},
+		}
+	}
+	// these only differ by namespace and package ID
+	a := newMatch("pkg-1", "ns-a")
+	b := newMatch("pkg-2", "ns-a")
+	c := newMatch("pkg-1", "ns-b")
+
+	for _, input := range [][]Match{{a, b, c}, {c, b, a}, {b, c, a}} {
+		matches := NewMatches(input...)
+		assertMatchOrder(t, []Match{a, b, c}, matches.Sorted())
+	}
 }
 
 func TestMatchesSortByVulnerability(t *testing.T) {
@@ -413,3 +445,19 @@ func TestMatches_Diff(t *testing.T) {
 		})
 	}
 }
+
+func TestSortIgnored(t *testing.T) {
+	newIgnored := func(name, vuln string) IgnoredMatch {
+		return IgnoredMatch{
+			Match: Match{
+				Vulnerability: vulnerability.Vulnerability{ID: vuln},
+				Package:       pkg.Package{ID: pkg.ID(name), Name: name, Version: "1.0.0", Type: syftPkg.RpmPkg},
+			},
+		}
+	}
+	ignored := []IgnoredMatch{newIgnored("b", "CVE-1"), newIgnored("a", "CVE-2"), newIgnored("a", "CVE-1")}
+
+	SortIgnored(ignored)
+
+	assertIgnoredMatchOrder(t, []IgnoredMatch{newIgnored("a", "CVE-1"), newIgnored("a", "CVE-2"), newIgnored("b", "CVE-1")}, ignored)
+}
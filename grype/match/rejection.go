@@ -0,0 +1,127 @@
+package match
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// RejectionFilter names the filter that rejected a candidate vulnerability of a package.
+type RejectionFilter string
+
+const (
+	// VersionConstraintRejection rejects the vulnerabilities whose version constraint the package version is not within.
+	VersionConstraintRejection RejectionFilter = "version-constraint"
+	// VersionComparisonRejection rejects the vulnerabilities whose version constraint the package version could not be
+	// compared with.
+	VersionComparisonRejection RejectionFilter = "version-comparison"
+	// QualifierRejection rejects the vulnerabilities with a package qualifier the package does not satisfy (e.g. an RPM
+	// modularity or a platform CPE).
+	QualifierRejection RejectionFilter = "qualifier"
+	// TargetSoftwareRejection rejects the CPE vulnerabilities whose target software is not the language of the package.
+	TargetSoftwareRejection RejectionFilter = "target-software"
+	// TargetingRejection rejects the distro matches for package names the advisories of the distro are not filed against.
+	TargetingRejection RejectionFilter = "targeting"
+	// DistroRejection rejects the vulnerabilities the security data of the distro declares as false positives.
+	DistroRejection RejectionFilter = "distro"
+	// ExclusionRejection rejects the vulnerabilities excluded for the package by the vulnerability DB (or by the
+	// hard-coded exclusions).
+	ExclusionRejection RejectionFilter = "exclusion"
+	// FixStateRejection rejects the vulnerabilities whose fix state is ignored (e.g. with --only-fixed).
+	FixStateRejection RejectionFilter = "fix-state"
+)
+
+// Rejection is a candidate vulnerability of a package that was considered, but rejected by a filter.
+type Rejection struct {
+	Vulnerability vulnerability.Vulnerability
+	Package       pkg.Package
+	Filter        RejectionFilter
+	// Reason describes why the filter rejected the vulnerability (e.g. the version constraint that is not satisfied).
+	Reason string
+	// Heuristic is the heuristic (e.g. "calver" or "fuzzy") that the comparison of the package version with the version
+	// constraint fell back to, for the rejections of version constraints that no version format could compare.
+	Heuristic string
+}
+
+// RejectionRecorder records the candidate vulnerabilities that are rejected. The vulnerability providers given to the
+// matchers may implement it, in which case the matchers record their rejections to them.
+type RejectionRecorder interface {
+	RecordRejection(Rejection)
+}
+
+// Rejections collects the rejections of a scan, and is safe for concurrent use. A nil *Rejections records nothing.
+type Rejections struct {
+	lock       sync.Mutex
+	rejections map[rejectionKey]Rejection
+}
+
+type rejectionKey struct {
+	packageID       pkg.ID
+	vulnerabilityID string
+	namespace       string
+	filter          RejectionFilter
+}
+
+func NewRejections() *Rejections {
+	return &Rejections{rejections: make(map[rejectionKey]Rejection)}
+}
+
+// RecordRejection records the rejection, once per package, vulnerability and filter (the first reason is kept).
+func (r *Rejections) RecordRejection(rejection Rejection) {
+	if r == nil {
+		return
+	}
+	key := rejectionKey{
+		packageID:       rejection.Package.ID,
+		vulnerabilityID: rejection.Vulnerability.ID,
+		namespace:       rejection.Vulnerability.Namespace,
+		filter:          rejection.Filter,
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.rejections[key]; !ok {
+		r.rejections[key] = rejection
+	}
+}
+
+// Sorted returns the rejections ordered by package, then vulnerability and filter.
+func (r *Rejections) Sorted() []Rejection {
+	if r == nil {
+		return nil
+	}
+	r.lock.Lock()
+	out := make([]Rejection, 0, len(r.rejections))
+	for _, rejection := range r.rejections {
+		out = append(out, rejection)
+	}
+	r.lock.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch {
+		case a.Package.Name != b.Package.Name:
+			return a.Package.Name < b.Package.Name
+		case a.Package.Version != b.Package.Version:
+			return a.Package.Version < b.Package.Version
+		case a.Package.ID != b.Package.ID:
+			return a.Package.ID < b.Package.ID
+		case a.Vulnerability.ID != b.Vulnerability.ID:
+			return a.Vulnerability.ID < b.Vulnerability.ID
+		case a.Vulnerability.Namespace != b.Vulnerability.Namespace:
+			return a.Vulnerability.Namespace < b.Vulnerability.Namespace
+		}
+		return a.Filter < b.Filter
+	})
+	return out
+}
+
+// RejectionRecorderOf returns the recorder of the rejections of the given vulnerability provider, or a recorder
+// discarding them when the provider does not record rejections.
+func RejectionRecorderOf(provider any) RejectionRecorder {
+	if recorder, ok := provider.(RejectionRecorder); ok {
+		return recorder
+	}
+	return (*Rejections)(nil)
+}
@@ -0,0 +1,48 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+func TestRejections(t *testing.T) {
+	zlib := pkg.Package{ID: "zlib-id", Name: "zlib", Version: "1.2.13"}
+	curl := pkg.Package{ID: "curl-id", Name: "curl", Version: "8.4.0"}
+
+	rejections := NewRejections()
+	rejections.RecordRejection(Rejection{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2"}, Package: zlib, Filter: VersionConstraintRejection, Reason: "first"})
+	rejections.RecordRejection(Rejection{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2"}, Package: zlib, Filter: VersionConstraintRejection, Reason: "second"})
+	rejections.RecordRejection(Rejection{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2"}, Package: zlib, Filter: QualifierRejection})
+	rejections.RecordRejection(Rejection{Vulnerability: vulnerability.Vulnerability{ID: "CVE-1"}, Package: zlib, Filter: DistroRejection})
+	rejections.RecordRejection(Rejection{Vulnerability: vulnerability.Vulnerability{ID: "CVE-3"}, Package: curl, Filter: ExclusionRejection})
+
+	var got []string
+	for _, r := range rejections.Sorted() {
+		got = append(got, r.Package.Name+" "+r.Vulnerability.ID+" "+string(r.Filter)+" "+r.Reason)
+	}
+	assert.Equal(t, []string{
+		"curl CVE-3 exclusion ",
+		"zlib CVE-1 distro ",
+		"zlib CVE-2 qualifier ",
+		"zlib CVE-2 version-constraint first",
+	}, got)
+}
+
+func TestRejectionRecorderOf(t *testing.T) {
+	rejections := NewRejections()
+	provider := struct {
+		*Rejections
+	}{rejections}
+	RejectionRecorderOf(provider).RecordRejection(Rejection{Filter: TargetingRejection})
+	assert.Len(t, rejections.Sorted(), 1)
+
+	// providers that do not record rejections discard them
+	RejectionRecorderOf(struct{}{}).RecordRejection(Rejection{Filter: TargetingRejection})
+	var nilRejections *Rejections
+	nilRejections.RecordRejection(Rejection{Filter: TargetingRejection})
+	assert.Nil(t, nilRejections.Sorted())
+}
@@ -0,0 +1,33 @@
+package match
+
+import "github.com/anchore/grype/grype/vulnerability"
+
+// SeverityOverride is a severity rating that replaces the rating from the vulnerability data for a match, typically
+// because a security team re-rated the vulnerability for their environment.
+type SeverityOverride struct {
+	Severity string // the severity to report and gate on instead of the severity from the vulnerability data
+	Reason   string // why the vulnerability was re-rated
+
+	Score *float64 // the adjusted CVSS score the severity was derived from, if any
+}
+
+// WithSeverityOverride returns the metadata of the match vulnerability as it should be reported: when the match has a
+// severity override, a copy of the metadata with the overridden severity (and the original severity retained),
+// otherwise the metadata as given.
+func (m Match) WithSeverityOverride(metadata *vulnerability.Metadata) *vulnerability.Metadata {
+	if m.SeverityOverride == nil {
+		return metadata
+	}
+
+	overridden := vulnerability.Metadata{ID: m.Vulnerability.ID, Namespace: m.Vulnerability.Namespace}
+	if metadata != nil {
+		overridden = *metadata
+	}
+	if overridden.OriginalSeverity == "" {
+		overridden.OriginalSeverity = overridden.Severity
+	}
+	overridden.Severity = m.SeverityOverride.Severity
+	overridden.SeverityOverrideReason = m.SeverityOverride.Reason
+	overridden.AdjustedScore = m.SeverityOverride.Score
+	return &overridden
+}
@@ -3,10 +3,17 @@ package match
 import (
 	"sort"
 	"strings"
+
+	"github.com/anchore/grype/internal/stringutil"
+	"github.com/anchore/syft/syft/file"
 )
 
 var _ sort.Interface = (*ByElements)(nil)
 
+// ByElements sorts matches in the order used by every output format: by package (name, version, type), then by
+// vulnerability ID, with the vulnerability namespace, fix versions, package locations and package ID as tie-breaks.
+// Since these fields identify a match (see Fingerprint), the order is total and does not depend on the order that
+// matches were found in.
 type ByElements []Match
 
 // Len is the number of elements in the collection.
@@ -16,45 +23,58 @@ func (m ByElements) Len() int {
 
 // Less reports whether the element with index i should sort before the element with index j.
 func (m ByElements) Less(i, j int) bool {
-	if m[i].Vulnerability.ID == m[j].Vulnerability.ID {
-		if m[i].Package.Name == m[j].Package.Name {
-			if m[i].Package.Version == m[j].Package.Version {
-				if m[i].Package.Type == m[j].Package.Type {
-					// this is an approximate ordering, but is not accurate in terms of semver and other version formats
-					// but stability is what is important here, not the accuracy of the sort.
-					fixVersions1 := m[i].Vulnerability.Fix.Versions
-					fixVersions2 := m[j].Vulnerability.Fix.Versions
-					sort.Strings(fixVersions1)
-					sort.Strings(fixVersions2)
-					fixStr1 := strings.Join(fixVersions1, ",")
-					fixStr2 := strings.Join(fixVersions2, ",")
-
-					if fixStr1 == fixStr2 {
-						loc1 := m[i].Package.Locations.ToSlice()
-						loc2 := m[j].Package.Locations.ToSlice()
-						var locStr1 string
-						for _, location := range loc1 {
-							locStr1 += location.RealPath
-						}
-						var locStr2 string
-						for _, location := range loc2 {
-							locStr2 += location.RealPath
-						}
-
-						return locStr1 < locStr2
-					}
-					return fixStr1 < fixStr2
-				}
-				return m[i].Package.Type < m[j].Package.Type
-			}
-			return m[i].Package.Version < m[j].Package.Version
-		}
-		return m[i].Package.Name < m[j].Package.Name
-	}
-	return m[i].Vulnerability.ID < m[j].Vulnerability.ID
+	return Compare(m[i], m[j]) < 0
 }
 
 // Swap swaps the elements with indexes i and j.
 func (m ByElements) Swap(i, j int) {
 	m[i], m[j] = m[j], m[i]
 }
+
+// Compare returns -1, 0 or +1 depending on whether match a sorts before, the same as, or after match b (see ByElements).
+func Compare(a, b Match) int {
+	fields := [][2]string{
+		{a.Package.Name, b.Package.Name},
+		{a.Package.Version, b.Package.Version},
+		{string(a.Package.Type), string(b.Package.Type)},
+		{a.Vulnerability.ID, b.Vulnerability.ID},
+		{a.Vulnerability.Namespace, b.Vulnerability.Namespace},
+		// this is an approximate ordering, but is not accurate in terms of semver and other version formats
+		// but stability is what is important here, not the accuracy of the sort.
+		{stringutil.SortedJoin(a.Vulnerability.Fix.Versions, ","), stringutil.SortedJoin(b.Vulnerability.Fix.Versions, ",")},
+		{LocationsKey(coordinates(a.Package.Locations)), LocationsKey(coordinates(b.Package.Locations))},
+		{string(a.Package.ID), string(b.Package.ID)},
+		{strings.Join(a.Vulnerability.Fix.Versions, ","), strings.Join(b.Vulnerability.Fix.Versions, ",")},
+	}
+	for _, f := range fields {
+		if c := strings.Compare(f[0], f[1]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// SortIgnored sorts ignored matches in the same order as matches (see ByElements).
+func SortIgnored(ignored []IgnoredMatch) {
+	sort.SliceStable(ignored, func(i, j int) bool {
+		return Compare(ignored[i].Match, ignored[j].Match) < 0
+	})
+}
+
+// LocationsKey joins the real paths of the locations of a package, which tell apart the matches of packages that are
+// otherwise the same in the order of matches (see ByElements).
+func LocationsKey(locations []file.Coordinates) string {
+	var paths []string
+	for _, location := range locations {
+		paths = append(paths, location.RealPath)
+	}
+	return strings.Join(paths, ",")
+}
+
+func coordinates(locations file.LocationSet) []file.Coordinates {
+	var coordinates []file.Coordinates
+	for _, location := range locations.ToSlice() {
+		coordinates = append(coordinates, location.Coordinates)
+	}
+	return coordinates
+}
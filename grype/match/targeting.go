@@ -0,0 +1,48 @@
+package match
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/grype/distro"
+)
+
+// Targeting describes which package names the advisories of a distro are matched against: the names of the source
+// packages that binary packages are built from, the names of the binary packages themselves, or both. Distros differ
+// in which they file advisories against (e.g. Debian advisories name source packages, while RHEL advisories name
+// binary packages).
+type Targeting string
+
+const (
+	// DefaultTargeting uses the default of the vulnerability DB for the distro, or BothTargeting if there is none.
+	DefaultTargeting Targeting = ""
+	// SourceTargeting matches advisories against the source package names only (binary packages without a source
+	// package are their own source).
+	SourceTargeting Targeting = "source"
+	// BinaryTargeting matches advisories against the binary package names only.
+	BinaryTargeting Targeting = "binary"
+	// BothTargeting matches advisories against both the source and binary package names.
+	BothTargeting Targeting = "both"
+)
+
+// Targetings lists all supported targeting strategies.
+var Targetings = []Targeting{SourceTargeting, BinaryTargeting, BothTargeting}
+
+// ParseTargeting returns the targeting strategy for the given name, or an error if it is not supported.
+func ParseTargeting(s string) (Targeting, error) {
+	t := Targeting(strings.ToLower(strings.TrimSpace(s)))
+	if t == DefaultTargeting {
+		return t, nil
+	}
+	for _, candidate := range Targetings {
+		if t == candidate {
+			return t, nil
+		}
+	}
+	return DefaultTargeting, fmt.Errorf("unsupported advisory targeting %q (supported: %q, %q, %q)", s, SourceTargeting, BinaryTargeting, BothTargeting)
+}
+
+// TargetingProvider supplies the default targeting of a distro, as encoded in the vulnerability DB.
+type TargetingProvider interface {
+	GetTargeting(d *distro.Distro) (Targeting, error)
+}
@@ -8,6 +8,7 @@ const (
 	ExactDirectMatch   Type = "exact-direct-match"
 	ExactIndirectMatch Type = "exact-indirect-match"
 	CPEMatch           Type = "cpe-match"
+	AliasMatch         Type = "alias-match"
 )
 
 type Type string
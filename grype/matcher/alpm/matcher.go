@@ -0,0 +1,119 @@
+package alpm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/stringutil"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// vcsSuffixes are the package name suffixes used by Arch VCS packages, which build from a source repository
+// snapshot of the upstream project (e.g. "neovim-git" tracks "neovim").
+var vcsSuffixes = []string{"-git", "-svn", "-hg", "-bzr", "-darcs", "-cvs", "-fossil"}
+
+type Matcher struct {
+	cfg MatcherConfig
+}
+
+type MatcherConfig struct {
+	// UseCPEs additionally searches NVD for vulnerabilities not covered by the Arch Security Tracker
+	UseCPEs bool
+}
+
+func NewAlpmMatcher(cfg MatcherConfig) *Matcher {
+	return &Matcher{
+		cfg: cfg,
+	}
+}
+
+func (m *Matcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{syftPkg.AlpmPkg}
+}
+
+func (m *Matcher) Type() match.MatcherType {
+	return match.AlpmMatcher
+}
+
+func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	matches, err := search.ByPackageDistro(store, d, p, m.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to match by exact package name: %w", err)
+	}
+
+	vcsMatches, err := m.matchVCSUpstream(store, d, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match by VCS upstream package name: %w", err)
+	}
+	matches = append(matches, vcsMatches...)
+
+	if m.cfg.UseCPEs {
+		cpeMatches, err := search.ByCriteria(store, d, p, m.Type(), search.ByCPE)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, withoutCovered(cpeMatches, matches)...)
+	}
+
+	return matches, nil
+}
+
+// matchVCSUpstream searches for vulnerabilities of the upstream project that a VCS package is built from.
+func (m *Matcher) matchVCSUpstream(store vulnerability.ProviderByDistro, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	base, ok := vcsBaseName(p.Name)
+	if !ok {
+		return nil, nil
+	}
+
+	indirect := p
+	indirect.Name = base
+
+	matches, err := search.ByPackageDistro(store, d, indirect, m.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	// track the match against the package from the SBOM, not the upstream project name
+	match.ConvertToIndirectMatches(matches, p)
+
+	return matches, nil
+}
+
+func vcsBaseName(name string) (string, bool) {
+	for _, suffix := range vcsSuffixes {
+		if base, found := strings.CutSuffix(name, suffix); found && base != "" {
+			return base, true
+		}
+	}
+	return "", false
+}
+
+// withoutCovered drops CPE (NVD) matches for vulnerabilities that were already reported by the distro feed, either
+// directly or through a related vulnerability (e.g. an AVG advisory that references the CVE).
+func withoutCovered(cpeMatches []match.Match, distroMatches []match.Match) []match.Match {
+	if len(distroMatches) == 0 {
+		return cpeMatches
+	}
+
+	covered := stringutil.NewStringSet()
+	for _, dm := range distroMatches {
+		covered.Add(dm.Vulnerability.ID)
+		for _, related := range dm.Vulnerability.RelatedVulnerabilities {
+			covered.Add(related.ID)
+		}
+	}
+
+	var out []match.Match
+	for _, cm := range cpeMatches {
+		if covered.Contains(cm.Vulnerability.ID) {
+			continue
+		}
+		out = append(out, cm)
+	}
+	return out
+}
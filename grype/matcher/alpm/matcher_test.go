@@ -0,0 +1,151 @@
+package alpm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockProvider struct {
+	vulnerability.Provider
+	byDistro map[string][]vulnerability.Vulnerability
+	byCPE    map[string][]vulnerability.Vulnerability
+}
+
+func (pr *mockProvider) GetByDistro(d *distro.Distro, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	if d == nil || d.Type != distro.ArchLinux {
+		return nil, nil
+	}
+	return pr.byDistro[p.Name], nil
+}
+
+func (pr *mockProvider) GetByCPE(c cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	return pr.byCPE[c.Attributes.Product], nil
+}
+
+func newMockProvider() *mockProvider {
+	vulnCPE := cpe.Must("cpe:2.3:a:curl:curl:*:*:*:*:*:*:*:*", "")
+	return &mockProvider{
+		byDistro: map[string][]vulnerability.Vulnerability{
+			"curl": {
+				{
+					ID:         "AVG-2023-fake-1",
+					Namespace:  "archlinux:distro:archlinux:rolling",
+					Constraint: version.MustGetConstraint("< 8.4.0-1", version.AlpmFormat),
+					RelatedVulnerabilities: []vulnerability.Reference{
+						{ID: "CVE-2023-fake-1", Namespace: "nvd:cpe"},
+					},
+				},
+			},
+		},
+		byCPE: map[string][]vulnerability.Vulnerability{
+			"curl": {
+				{
+					ID:         "CVE-2023-fake-1",
+					Namespace:  "nvd:cpe",
+					Constraint: version.MustGetConstraint("< 8.4.0", version.UnknownFormat),
+					CPEs:       []cpe.CPE{vulnCPE},
+				},
+				{
+					ID:         "CVE-2023-fake-2",
+					Namespace:  "nvd:cpe",
+					Constraint: version.MustGetConstraint("< 8.5.0", version.UnknownFormat),
+					CPEs:       []cpe.CPE{vulnCPE},
+				},
+			},
+		},
+	}
+}
+
+func archDistro(t *testing.T) *distro.Distro {
+	d, err := distro.New(distro.ArchLinux, "", "")
+	require.NoError(t, err)
+	return d
+}
+
+func ids(matches []match.Match) []string {
+	var out []string
+	for _, m := range matches {
+		out = append(out, m.Vulnerability.ID)
+	}
+	return out
+}
+
+func TestMatcher_Match(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       MatcherConfig
+		pkgName   string
+		version   string
+		expected  []string
+		matchType match.Type
+	}{
+		{
+			name:      "security tracker only",
+			pkgName:   "curl",
+			version:   "8.3.0-1",
+			expected:  []string{"AVG-2023-fake-1"},
+			matchType: match.ExactDirectMatch,
+		},
+		{
+			name:      "fixed by pkgrel",
+			pkgName:   "curl",
+			version:   "8.4.0-1",
+			matchType: match.ExactDirectMatch,
+		},
+		{
+			name:      "CPE matches already covered by the tracker are dropped",
+			cfg:       MatcherConfig{UseCPEs: true},
+			pkgName:   "curl",
+			version:   "8.3.0-1",
+			expected:  []string{"AVG-2023-fake-1", "CVE-2023-fake-2"},
+			matchType: match.ExactDirectMatch,
+		},
+		{
+			name:      "VCS package matches upstream project",
+			pkgName:   "curl-git",
+			version:   "8.3.0.r120.g1a2b3c4-1",
+			expected:  []string{"AVG-2023-fake-1"},
+			matchType: match.ExactIndirectMatch,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := pkg.Package{
+				ID:      pkg.ID(uuid.NewString()),
+				Name:    test.pkgName,
+				Version: test.version,
+				Type:    syftPkg.AlpmPkg,
+				CPEs: []cpe.CPE{
+					cpe.Must("cpe:2.3:a:curl:curl:"+strings.Split(test.version, "-")[0]+":*:*:*:*:*:*:*", cpe.GeneratedSource),
+				},
+			}
+
+			actual, err := NewAlpmMatcher(test.cfg).Match(newMockProvider(), archDistro(t), p)
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, test.expected, ids(actual))
+			for _, m := range actual {
+				assert.Equal(t, p.Name, m.Package.Name)
+				if m.Vulnerability.Namespace == "nvd:cpe" {
+					continue
+				}
+				require.NotEmpty(t, m.Details)
+				assert.Equal(t, test.matchType, m.Details[0].Type)
+				assert.Equal(t, match.AlpmMatcher, m.Details[0].Matcher)
+			}
+		})
+	}
+}
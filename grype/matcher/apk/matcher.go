@@ -43,6 +43,13 @@ func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Pa
 	}
 	matches = append(matches, indirectMatches...)
 
+	// indirect matches, via the packages the package provides
+	providedMatches, err := search.ByProvidedPackages(store, d, p, m.Type())
+	if err != nil {
+		return nil, err
+	}
+	matches = append(matches, providedMatches...)
+
 	return matches, nil
 }
 
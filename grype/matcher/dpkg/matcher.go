@@ -37,6 +37,12 @@ func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Pa
 	}
 	matches = append(matches, exactMatches...)
 
+	providedMatches, err := search.ByProvidedPackages(store, d, p, m.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to match by provided packages: %w", err)
+	}
+	matches = append(matches, providedMatches...)
+
 	return matches, nil
 }
 
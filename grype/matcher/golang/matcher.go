@@ -57,16 +57,58 @@ func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Pa
 		// when AllowPseudoVersionComparison is false
 		isNotCorrected = strings.HasPrefix(p.Version, "v0.0.0-") || strings.HasPrefix(p.Version, "(devel)")
 	}
-	if p.Name == mainModule && isNotCorrected {
-		return matches, nil
+	if p.Name != mainModule || !isNotCorrected {
+		criteria := search.CommonCriteria
+		if searchByCPE(p.Name, m.cfg) {
+			criteria = append(criteria, search.ByCPE)
+		}
+
+		found, err := search.ByCriteria(store, d, p, m.Type(), criteria...)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, found...)
 	}
 
-	criteria := search.CommonCriteria
-	if searchByCPE(p.Name, m.cfg) {
-		criteria = append(criteria, search.ByCPE)
+	// the commit the module was built from can be matched against advisories of affected commits (OSV GIT ranges),
+	// even when the version of the module is unknown
+	if revision, ok := pkg.VCSRevision(p); ok {
+		found, err := searchByRevision(store, d, p, revision, m.Type())
+		if err != nil {
+			return nil, err
+		}
+		matches = appendNewMatches(matches, found)
 	}
 
-	return search.ByCriteria(store, d, p, m.Type(), criteria...)
+	return matches, nil
+}
+
+func searchByRevision(store vulnerability.Provider, d *distro.Distro, p pkg.Package, revision string, upstreamMatcher match.MatcherType) ([]match.Match, error) {
+	revisionPkg := p
+	revisionPkg.Version = revision
+	matches, err := search.ByPackageLanguage(store, d, revisionPkg, upstreamMatcher)
+	if err != nil {
+		return nil, err
+	}
+	for i := range matches {
+		// report the package as it was found, not as it was searched
+		matches[i].Package = p
+	}
+	return matches, nil
+}
+
+// appendNewMatches adds the matches of vulnerabilities that were not already matched.
+func appendNewMatches(matches, found []match.Match) []match.Match {
+	seen := make(map[string]bool)
+	for _, mt := range matches {
+		seen[mt.Vulnerability.Namespace+":"+mt.Vulnerability.ID] = true
+	}
+	for _, mt := range found {
+		if !seen[mt.Vulnerability.Namespace+":"+mt.Vulnerability.ID] {
+			matches = append(matches, mt)
+		}
+	}
+	return matches
 }
 
 func searchByCPE(name string, cfg MatcherConfig) bool {
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/scylladb/go-set/strset"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/grype/pkg"
@@ -195,6 +196,76 @@ func TestMatcher_SearchForStdlib(t *testing.T) {
 
 }
 
+func TestMatcher_SearchByVCSRevision(t *testing.T) {
+	tests := []struct {
+		name         string
+		subject      pkg.Package
+		expectedCVEs []string
+	}{
+		{
+			name: "main module built from an affected commit",
+			subject: pkg.Package{
+				Name:    "github.com/anchore/example",
+				Version: "(devel)",
+				Metadata: pkg.GolangBinMetadata{
+					MainModule:    "github.com/anchore/example",
+					BuildSettings: syftPkg.KeyValues{{Key: "vcs.revision", Value: "16171245cfb2a1d9b5d5e39f9cb8a5e8c2a0b1c3"}},
+				},
+			},
+			expectedCVEs: []string{"GHSA-fake-commit"},
+		},
+		{
+			name: "main module built from the fixed commit",
+			subject: pkg.Package{
+				Name:    "github.com/anchore/example",
+				Version: "(devel)",
+				Metadata: pkg.GolangBinMetadata{
+					MainModule:    "github.com/anchore/example",
+					BuildSettings: syftPkg.KeyValues{{Key: "vcs.revision", Value: "daa7c04131f5"}},
+				},
+			},
+		},
+		{
+			name: "dependency pinned by the revision of its vcs url",
+			subject: pkg.Package{
+				Name:     "github.com/anchore/example",
+				Version:  "v1.2.0",
+				PURL:     "pkg:golang/github.com/anchore/example@v1.2.0?vcs_url=git%2Bhttps%3A%2F%2Fgithub.com%2Fanchore%2Fexample%4016171245cfb2",
+				Metadata: pkg.GolangBinMetadata{},
+			},
+			expectedCVEs: []string{"GHSA-fake-commit", "GHSA-fake-release"},
+		},
+		{
+			name: "dependency without a revision",
+			subject: pkg.Package{
+				Name:     "github.com/anchore/example",
+				Version:  "v1.2.0",
+				Metadata: pkg.GolangBinMetadata{},
+			},
+			expectedCVEs: []string{"GHSA-fake-release"},
+		},
+	}
+
+	store := newMockProvider()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.subject.ID = pkg.ID(uuid.NewString())
+			test.subject.Type = syftPkg.GoModulePkg
+			test.subject.Language = syftPkg.Go
+
+			actual, err := NewGolangMatcher(MatcherConfig{}).Match(store, nil, test.subject)
+			require.NoError(t, err)
+
+			var actualCVEs []string
+			for _, m := range actual {
+				actualCVEs = append(actualCVEs, m.Vulnerability.ID)
+				assert.Equal(t, test.subject, m.Package)
+			}
+			assert.ElementsMatch(t, test.expectedCVEs, actualCVEs)
+		})
+	}
+}
+
 func newMockProvider() *mockProvider {
 	mp := mockProvider{
 		data: make(map[syftPkg.Language]map[string][]vulnerability.Vulnerability),
@@ -223,6 +294,17 @@ func (mp *mockProvider) populateData() {
 				ID:         "CVE-2013-fake-BAD",
 			},
 		},
+		// for TestMatcher_SearchByVCSRevision
+		"github.com/anchore/example": {
+			{
+				Constraint: version.MustGetConstraint(">= 0, < daa7c04131f5 || = 16171245cfb2", version.GitFormat),
+				ID:         "GHSA-fake-commit",
+			},
+			{
+				Constraint: version.MustGetConstraint("< 1.3.0", version.GolangFormat),
+				ID:         "GHSA-fake-release",
+			},
+		},
 	}
 
 	mp.data["nvd:cpe"] = map[string][]vulnerability.Vulnerability{
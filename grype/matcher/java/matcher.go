@@ -9,6 +9,7 @@ import (
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/search"
 	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/fips"
 	"github.com/anchore/grype/internal/log"
 	syftPkg "github.com/anchore/syft/syft/pkg"
 )
@@ -36,7 +37,7 @@ func NewJavaMatcher(cfg MatcherConfig) *Matcher {
 	return &Matcher{
 		cfg: cfg,
 		MavenSearcher: &mavenSearch{
-			client:  http.DefaultClient,
+			client:  fips.HTTPClient(http.DefaultClient),
 			baseURL: cfg.MavenBaseURL,
 		},
 	}
@@ -0,0 +1,27 @@
+package macos
+
+import (
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// Matcher finds vulnerabilities for packages installed by macOS package managers (Homebrew and MacPorts). There are
+// no ecosystem-specific advisory feeds for these packages, so matching is always done by CPE.
+type Matcher struct {
+}
+
+func (m *Matcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{pkg.HomebrewPkg, pkg.MacPortsPkg}
+}
+
+func (m *Matcher) Type() match.MatcherType {
+	return match.MacOSMatcher
+}
+
+func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	return search.ByCriteria(store, d, p, m.Type(), search.ByCPE)
+}
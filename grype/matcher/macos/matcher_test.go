@@ -0,0 +1,73 @@
+package macos
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockProvider struct {
+	vulnerability.Provider
+	byCPE map[string][]vulnerability.Vulnerability
+}
+
+func (pr *mockProvider) GetByCPE(c cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	return pr.byCPE[c.Attributes.Product], nil
+}
+
+func (pr *mockProvider) GetByLanguage(syftPkg.Language, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func TestMatcher_Match(t *testing.T) {
+	vulnCPE := cpe.Must("cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*", "")
+	store := &mockProvider{
+		byCPE: map[string][]vulnerability.Vulnerability{
+			"openssl": {
+				{
+					ID:         "CVE-2023-fake-1",
+					Namespace:  "nvd:cpe",
+					Constraint: version.MustGetConstraint("< 3.1.5", version.UnknownFormat),
+					CPEs:       []cpe.CPE{vulnCPE},
+				},
+				{
+					ID:         "CVE-2023-fake-2",
+					Namespace:  "nvd:cpe",
+					Constraint: version.MustGetConstraint("< 3.0.0", version.UnknownFormat),
+					CPEs:       []cpe.CPE{vulnCPE},
+				},
+			},
+		},
+	}
+
+	for _, ty := range []syftPkg.Type{pkg.HomebrewPkg, pkg.MacPortsPkg} {
+		t.Run(string(ty), func(t *testing.T) {
+			p := pkg.Package{
+				ID:      pkg.ID(uuid.NewString()),
+				Name:    "openssl@3",
+				Version: "3.1.4",
+				Type:    ty,
+				CPEs:    []cpe.CPE{cpe.Must("cpe:2.3:a:openssl:openssl:3.1.4:*:*:*:*:*:*:*", cpe.GeneratedSource)},
+			}
+
+			m := Matcher{}
+			actual, err := m.Match(store, nil, p)
+			require.NoError(t, err)
+
+			require.Len(t, actual, 1)
+			assert.Equal(t, "CVE-2023-fake-1", actual[0].Vulnerability.ID)
+			require.NotEmpty(t, actual[0].Details)
+			assert.Equal(t, match.CPEMatch, actual[0].Details[0].Type)
+			assert.Equal(t, match.MacOSMatcher, actual[0].Details[0].Matcher)
+		})
+	}
+}
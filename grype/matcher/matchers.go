@@ -1,17 +1,23 @@
 package matcher
 
 import (
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher/alpm"
 	"github.com/anchore/grype/grype/matcher/apk"
 	"github.com/anchore/grype/grype/matcher/dotnet"
 	"github.com/anchore/grype/grype/matcher/dpkg"
 	"github.com/anchore/grype/grype/matcher/golang"
 	"github.com/anchore/grype/grype/matcher/java"
 	"github.com/anchore/grype/grype/matcher/javascript"
+	"github.com/anchore/grype/grype/matcher/macos"
 	"github.com/anchore/grype/grype/matcher/msrc"
+	"github.com/anchore/grype/grype/matcher/nix"
+	"github.com/anchore/grype/grype/matcher/opkg"
 	"github.com/anchore/grype/grype/matcher/portage"
 	"github.com/anchore/grype/grype/matcher/python"
 	"github.com/anchore/grype/grype/matcher/rpm"
 	"github.com/anchore/grype/grype/matcher/ruby"
+	"github.com/anchore/grype/grype/matcher/runtime"
 	"github.com/anchore/grype/grype/matcher/rust"
 	"github.com/anchore/grype/grype/matcher/stock"
 )
@@ -26,22 +32,39 @@ type Config struct {
 	Golang     golang.MatcherConfig
 	Rust       rust.MatcherConfig
 	Stock      stock.MatcherConfig
+	Alpm       alpm.MatcherConfig
+	// Policies holds per-matcher behavior overrides, matchers without an entry use the DefaultPolicy
+	Policies map[match.MatcherType]Policy
+	// Targeting chooses whether distro advisories are matched against source or binary package names (or both)
+	Targeting TargetingConfig
 }
 
 func NewDefaultMatchers(mc Config) []Matcher {
-	return []Matcher{
-		&dpkg.Matcher{},
+	matchers := []Matcher{
+		WithTargeting(&dpkg.Matcher{}, mc.Targeting),
 		ruby.NewRubyMatcher(mc.Ruby),
 		python.NewPythonMatcher(mc.Python),
 		dotnet.NewDotnetMatcher(mc.Dotnet),
-		&rpm.Matcher{},
+		WithTargeting(&rpm.Matcher{}, mc.Targeting),
 		java.NewJavaMatcher(mc.Java),
 		javascript.NewJavascriptMatcher(mc.Javascript),
-		&apk.Matcher{},
+		WithTargeting(&apk.Matcher{}, mc.Targeting),
 		golang.NewGolangMatcher(mc.Golang),
 		&msrc.Matcher{},
 		&portage.Matcher{},
+		&opkg.Matcher{},
+		&macos.Matcher{},
+		alpm.NewAlpmMatcher(mc.Alpm),
+		&nix.Matcher{},
+		&runtime.Matcher{},
 		rust.NewRustMatcher(mc.Rust),
 		stock.NewStockMatcher(mc.Stock),
 	}
+
+	for idx, m := range matchers {
+		if policy, ok := mc.Policies[m.Type()]; ok {
+			matchers[idx] = WithPolicy(m, policy)
+		}
+	}
+	return matchers
 }
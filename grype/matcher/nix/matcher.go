@@ -0,0 +1,76 @@
+package nix
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// nixpkgs prefixes python library package names with the interpreter they were built for (e.g. "python3.11-requests")
+var pythonPackagePattern = regexp.MustCompile(`^python3(\.\d+)?-(?P<name>.+)$`)
+
+// Matcher finds vulnerabilities for packages installed in the nix store. There is no nix-specific advisory feed, so
+// packages are matched against NVD by CPE, and libraries packaged for a language ecosystem are additionally matched
+// against that ecosystem's advisories.
+type Matcher struct {
+}
+
+func (m *Matcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{syftPkg.NixPkg}
+}
+
+func (m *Matcher) Type() match.MatcherType {
+	return match.NixMatcher
+}
+
+func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	if strings.HasPrefix(p.Version, "unstable-") {
+		// snapshot versions (unstable-YYYY-MM-DD) cannot be compared against release version ranges
+		log.WithFields("package", p.Name, "version", p.Version).Trace("skipping nix package with snapshot version")
+		return nil, nil
+	}
+
+	matches, err := search.ByCriteria(store, d, p, m.Type(), search.ByCPE)
+	if err != nil {
+		return nil, err
+	}
+
+	languageMatches, err := m.matchLanguagePackage(store, d, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match by language package: %w", err)
+	}
+
+	return append(matches, languageMatches...), nil
+}
+
+// matchLanguagePackage searches the language ecosystem advisories for libraries that nixpkgs packages for a specific
+// language (e.g. python3.11-requests is the "requests" python package).
+func (m *Matcher) matchLanguagePackage(store vulnerability.ProviderByLanguage, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	groups := pythonPackagePattern.FindStringSubmatch(p.Name)
+	if groups == nil {
+		return nil, nil
+	}
+
+	indirect := p
+	indirect.Name = groups[pythonPackagePattern.SubexpIndex("name")]
+	indirect.Type = syftPkg.PythonPkg
+	indirect.Language = syftPkg.Python
+
+	matches, err := search.ByPackageLanguage(store, d, indirect, m.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	// always report the match against the nix package, keeping the derivation details found in the package metadata
+	match.ConvertToIndirectMatches(matches, p)
+
+	return matches, nil
+}
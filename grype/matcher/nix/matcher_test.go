@@ -0,0 +1,114 @@
+package nix
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockProvider struct {
+	vulnerability.Provider
+	byCPE      map[string][]vulnerability.Vulnerability
+	byLanguage map[string][]vulnerability.Vulnerability
+}
+
+func (pr *mockProvider) GetByCPE(c cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	return pr.byCPE[c.Attributes.Product], nil
+}
+
+func (pr *mockProvider) GetByLanguage(l syftPkg.Language, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return pr.byLanguage[string(l)+":"+p.Name], nil
+}
+
+func newMockProvider() *mockProvider {
+	return &mockProvider{
+		byCPE: map[string][]vulnerability.Vulnerability{
+			"curl": {
+				{
+					ID:         "CVE-2023-fake-1",
+					Namespace:  "nvd:cpe",
+					Constraint: version.MustGetConstraint("< 8.4.0", version.UnknownFormat),
+					CPEs:       []cpe.CPE{cpe.Must("cpe:2.3:a:haxx:curl:*:*:*:*:*:*:*:*", "")},
+				},
+			},
+		},
+		byLanguage: map[string][]vulnerability.Vulnerability{
+			"python:requests": {
+				{
+					ID:         "GHSA-2023-fake-2",
+					Namespace:  "github:language:python",
+					Constraint: version.MustGetConstraint("< 2.31.0", version.PythonFormat),
+				},
+			},
+		},
+	}
+}
+
+func TestMatcher_Match(t *testing.T) {
+	tests := []struct {
+		name      string
+		p         pkg.Package
+		expected  []string
+		matchType match.Type
+	}{
+		{
+			name: "match by CPE",
+			p: pkg.Package{
+				Name:    "curl",
+				Version: "8.3.0",
+				CPEs:    []cpe.CPE{cpe.Must("cpe:2.3:a:haxx:curl:8.3.0:*:*:*:*:*:*:*", cpe.GeneratedSource)},
+			},
+			expected:  []string{"CVE-2023-fake-1"},
+			matchType: match.CPEMatch,
+		},
+		{
+			name: "snapshot versions are skipped",
+			p: pkg.Package{
+				Name:    "curl",
+				Version: "unstable-2023-01-01",
+				CPEs:    []cpe.CPE{cpe.Must("cpe:2.3:a:haxx:curl:unstable-2023-01-01:*:*:*:*:*:*:*", cpe.GeneratedSource)},
+			},
+		},
+		{
+			name: "python library matched against python advisories",
+			p: pkg.Package{
+				Name:    "python3.11-requests",
+				Version: "2.30.0",
+				CPEs:    []cpe.CPE{cpe.Must("cpe:2.3:a:python3.11-requests:python3.11-requests:2.30.0:*:*:*:*:*:*:*", cpe.GeneratedSource)},
+			},
+			expected:  []string{"GHSA-2023-fake-2"},
+			matchType: match.ExactIndirectMatch,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := test.p
+			p.ID = pkg.ID(uuid.NewString())
+			p.Type = syftPkg.NixPkg
+
+			actual, err := (&Matcher{}).Match(newMockProvider(), nil, p)
+			require.NoError(t, err)
+
+			var ids []string
+			for _, m := range actual {
+				ids = append(ids, m.Vulnerability.ID)
+				assert.Equal(t, p.Name, m.Package.Name)
+				assert.Equal(t, syftPkg.NixPkg, m.Package.Type)
+				require.NotEmpty(t, m.Details)
+				assert.Equal(t, test.matchType, m.Details[0].Type)
+				assert.Equal(t, match.NixMatcher, m.Details[0].Matcher)
+			}
+			assert.ElementsMatch(t, test.expected, ids)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package opkg
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// Matcher finds vulnerabilities for opkg packages found on embedded targets (e.g. OpenWrt and Yocto-based images)
+type Matcher struct {
+}
+
+func (m *Matcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{pkg.OpkgPkg}
+}
+
+func (m *Matcher) Type() match.MatcherType {
+	return match.OpkgMatcher
+}
+
+func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	matches, err := search.ByPackageDistro(store, d, p, m.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find vulnerabilities: %w", err)
+	}
+
+	return matches, nil
+}
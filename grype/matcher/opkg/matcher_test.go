@@ -0,0 +1,49 @@
+package opkg
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/pkg"
+)
+
+func TestMatcherOpkg_Match(t *testing.T) {
+	matcher := Matcher{}
+	p := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "dnsmasq",
+		Version: "2.90-1",
+		Type:    pkg.OpkgPkg,
+	}
+
+	d, err := distro.New(distro.OpenWrt, "23.05.3", "")
+	require.NoError(t, err)
+
+	actual, err := matcher.Match(newMockProvider(), d, p)
+	require.NoError(t, err)
+
+	require.Len(t, actual, 1, "unexpected matches count")
+	assert.Equal(t, "CVE-2023-fake-2", actual[0].Vulnerability.ID)
+	require.NotEmpty(t, actual[0].Details)
+	for _, detail := range actual[0].Details {
+		assert.Equal(t, matcher.Type(), detail.Matcher, "failed to capture matcher type")
+	}
+}
+
+func TestMatcherOpkg_NoDistro(t *testing.T) {
+	matcher := Matcher{}
+	p := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "dnsmasq",
+		Version: "2.90-1",
+		Type:    pkg.OpkgPkg,
+	}
+
+	actual, err := matcher.Match(newMockProvider(), nil, p)
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+}
@@ -0,0 +1,243 @@
+package matcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+)
+
+// NameNormalization describes how package names are rewritten before searching for vulnerabilities.
+type NameNormalization string
+
+const (
+	// DefaultNameNormalization leaves the package name as-is (the DB applies its own per-ecosystem normalization).
+	DefaultNameNormalization NameNormalization = ""
+	// LowercaseNameNormalization lowercases package names.
+	LowercaseNameNormalization NameNormalization = "lowercase"
+	// PEP503NameNormalization lowercases package names and collapses runs of "-", "_" and "." into a single "-".
+	PEP503NameNormalization NameNormalization = "pep503"
+)
+
+// NameNormalizations lists all supported name normalization rules.
+var NameNormalizations = []NameNormalization{
+	DefaultNameNormalization,
+	LowercaseNameNormalization,
+	PEP503NameNormalization,
+}
+
+// PrereleaseComparison describes how pre-release versions of packages (e.g. 2.0.0-rc.1) are compared against
+// constraints that are only bounded by release versions (e.g. "< 2.0.0").
+type PrereleaseComparison string
+
+const (
+	// DefaultPrereleaseComparison orders pre-release versions before the release they precede, as each version
+	// format does (2.0.0-rc.1 satisfies "< 2.0.0" but not ">= 2.0.0").
+	DefaultPrereleaseComparison PrereleaseComparison = ""
+	// AsReleasePrereleaseComparison compares pre-release versions as the release they precede (2.0.0-rc.1 satisfies
+	// ">= 2.0.0" but not "< 2.0.0"), assuming that fixes and vulnerabilities of a release are already in its
+	// pre-releases.
+	AsReleasePrereleaseComparison PrereleaseComparison = "as-release"
+	// EitherPrereleaseComparison reports a vulnerability if either the pre-release version or the release it precedes
+	// satisfies the constraint, which has the fewest false negatives.
+	EitherPrereleaseComparison PrereleaseComparison = "either"
+	// ExcludePrereleaseComparison never reports vulnerabilities of pre-release versions for constraints that are
+	// only bounded by release versions, which has the fewest false positives.
+	ExcludePrereleaseComparison PrereleaseComparison = "exclude"
+)
+
+// PrereleaseComparisons lists all supported pre-release comparison policies.
+var PrereleaseComparisons = []PrereleaseComparison{
+	DefaultPrereleaseComparison,
+	AsReleasePrereleaseComparison,
+	EitherPrereleaseComparison,
+	ExcludePrereleaseComparison,
+}
+
+var pep503Separators = regexp.MustCompile(`[-_.]+`)
+
+// ParseNameNormalization returns the normalization rule for the given name, or an error if it is not supported.
+func ParseNameNormalization(s string) (NameNormalization, error) {
+	n := NameNormalization(strings.ToLower(strings.TrimSpace(s)))
+	for _, candidate := range NameNormalizations {
+		if n == candidate {
+			return n, nil
+		}
+	}
+	return DefaultNameNormalization, fmt.Errorf("unsupported name normalization %q (supported: %q, %q)", s, LowercaseNameNormalization, PEP503NameNormalization)
+}
+
+// ParsePrereleaseComparison returns the pre-release comparison policy for the given name, or an error if it is not
+// supported.
+func ParsePrereleaseComparison(s string) (PrereleaseComparison, error) {
+	c := PrereleaseComparison(strings.ToLower(strings.TrimSpace(s)))
+	for _, candidate := range PrereleaseComparisons {
+		if c == candidate {
+			return c, nil
+		}
+	}
+	return DefaultPrereleaseComparison, fmt.Errorf("unsupported pre-release comparison %q (supported: %q, %q, %q)", s, AsReleasePrereleaseComparison, EitherPrereleaseComparison, ExcludePrereleaseComparison)
+}
+
+// Apply rewrites the given package name according to the normalization rule.
+func (n NameNormalization) Apply(name string) string {
+	switch n {
+	case LowercaseNameNormalization:
+		return strings.ToLower(name)
+	case PEP503NameNormalization:
+		return pep503Separators.ReplaceAllString(strings.ToLower(name), "-")
+	default:
+		return name
+	}
+}
+
+// Policy captures matcher-independent behavior that may be tuned per ecosystem.
+type Policy struct {
+	// AllowPrereleaseConstraints indicates if vulnerabilities whose constraints are bounded by pre-release versions
+	// (e.g. "< 2.0.0-rc.1") should be reported.
+	AllowPrereleaseConstraints bool
+	// NameNormalization is applied to package names before searching.
+	NameNormalization NameNormalization
+	// PrereleaseComparison decides how pre-release versions are compared against constraints that are only bounded
+	// by release versions.
+	PrereleaseComparison PrereleaseComparison
+	// IgnoreBuildMetadata indicates if build metadata (e.g. the "+build.5" of semver or the "+ubuntu1" local version
+	// of PEP 440) should be removed from versions before comparing them, instead of following the version format.
+	// Versions of formats without build metadata (e.g. debian, where "+dfsg" is part of the version) are kept as-is.
+	IgnoreBuildMetadata bool
+}
+
+// DefaultPolicy is the policy that preserves the behavior of each matcher.
+func DefaultPolicy() Policy {
+	return Policy{
+		AllowPrereleaseConstraints: true,
+		NameNormalization:          DefaultNameNormalization,
+		PrereleaseComparison:       DefaultPrereleaseComparison,
+		IgnoreBuildMetadata:        false,
+	}
+}
+
+// WithPolicy wraps the given matcher such that the policy is applied to every search. The matcher is returned
+// unchanged when the policy does not alter any behavior.
+func WithPolicy(m Matcher, policy Policy) Matcher {
+	if policy == DefaultPolicy() {
+		return m
+	}
+	return &policyMatcher{
+		Matcher: m,
+		policy:  policy,
+	}
+}
+
+type policyMatcher struct {
+	Matcher
+	policy Policy
+}
+
+func (m *policyMatcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	searchPkg := p
+	searchPkg.Name = m.policy.NameNormalization.Apply(p.Name)
+	if m.policy.IgnoreBuildMetadata {
+		searchPkg.Version = version.WithoutBuildMetadata(p.Version, version.FormatFromPkg(p))
+	}
+
+	matches, err := m.Matcher.Match(store, d, searchPkg)
+	if err != nil {
+		return nil, err
+	}
+
+	format := version.FormatFromPkg(p)
+	matches, err = m.comparePrerelease(store, d, searchPkg, format, matches)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []match.Match
+	for _, mt := range matches {
+		if !m.policy.AllowPrereleaseConstraints && hasPrereleaseBound(mt.Vulnerability.Constraint, format) {
+			log.WithFields("vulnerability", mt.Vulnerability.ID, "package", p.Name).Trace("skipping match with pre-release constraint")
+			continue
+		}
+		// always report the package as it was found, not as it was searched
+		mt.Package = p
+		results = append(results, mt)
+	}
+	return results, nil
+}
+
+// comparePrerelease applies the pre-release comparison policy to the matches of a pre-release version (found by the
+// ordering of the version format), by searching for the release it precedes as well. Matches of constraints bounded
+// by pre-release versions are always kept as found, since these were written with pre-releases in mind.
+func (m *policyMatcher) comparePrerelease(store vulnerability.Provider, d *distro.Distro, p pkg.Package, format version.Format, matches []match.Match) ([]match.Match, error) {
+	if m.policy.PrereleaseComparison == DefaultPrereleaseComparison {
+		return matches, nil
+	}
+	release, ok := version.Prerelease(p.Version, format)
+	if !ok {
+		return matches, nil
+	}
+
+	var results []match.Match
+	found := make(map[string]bool)
+	for _, mt := range matches {
+		if m.policy.PrereleaseComparison == EitherPrereleaseComparison || hasPrereleaseBound(mt.Vulnerability.Constraint, format) {
+			found[mt.Vulnerability.Namespace+":"+mt.Vulnerability.ID] = true
+			results = append(results, mt)
+		}
+	}
+	if m.policy.PrereleaseComparison == ExcludePrereleaseComparison {
+		return results, nil
+	}
+
+	releasePkg := p
+	releasePkg.Version = release
+	releaseMatches, err := m.Matcher.Match(store, d, releasePkg)
+	if err != nil {
+		return nil, err
+	}
+	for _, mt := range releaseMatches {
+		if found[mt.Vulnerability.Namespace+":"+mt.Vulnerability.ID] || hasPrereleaseBound(mt.Vulnerability.Constraint, format) {
+			continue
+		}
+		log.WithFields("vulnerability", mt.Vulnerability.ID, "package", p.Name, "release", release).Trace("matched pre-release version as its release")
+		results = append(results, mt)
+	}
+	return results, nil
+}
+
+// hasPrereleaseBound indicates if any version within the constraint expression is a pre-release version of the given
+// format. The format of the constraint is used instead when the package has no known version format.
+func hasPrereleaseBound(c fmt.Stringer, format version.Format) bool {
+	if c == nil {
+		return false
+	}
+
+	expr := c.String()
+	// constraints are rendered with a trailing format hint, e.g. "< 1.0 (semver)"
+	if idx := strings.LastIndex(expr, " ("); idx >= 0 && strings.HasSuffix(expr, ")") {
+		if format == version.UnknownFormat {
+			format = version.ParseFormat(expr[idx+2 : len(expr)-1])
+		}
+		expr = expr[:idx]
+	}
+
+	if flattened, err := version.FlattenConstraint(expr); err == nil {
+		expr = flattened
+	}
+
+	for _, orPart := range strings.Split(expr, "||") {
+		for _, unit := range strings.Split(orPart, ",") {
+			v := strings.TrimLeft(strings.TrimSpace(unit), "<>=!~^ ")
+			if _, ok := version.Prerelease(v, format); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
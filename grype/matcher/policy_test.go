@@ -0,0 +1,253 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type recordingMatcher struct {
+	searched []string
+	results  []match.Match
+}
+
+func (m *recordingMatcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{syftPkg.PythonPkg}
+}
+
+func (m *recordingMatcher) Type() match.MatcherType {
+	return match.PythonMatcher
+}
+
+func (m *recordingMatcher) Match(_ vulnerability.Provider, _ *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	m.searched = append(m.searched, p.Name)
+	var results []match.Match
+	for _, r := range m.results {
+		r.Package = p
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// constraintMatcher reports the vulnerabilities whose constraints are satisfied by the version of the package.
+type constraintMatcher struct {
+	recordingMatcher
+	searchedVersions []string
+	vulnerabilities  []vulnerability.Vulnerability
+}
+
+func (m *constraintMatcher) Match(_ vulnerability.Provider, _ *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	m.searchedVersions = append(m.searchedVersions, p.Version)
+	v, err := version.NewVersionFromPkg(p)
+	if err != nil {
+		return nil, err
+	}
+	var results []match.Match
+	for _, vuln := range m.vulnerabilities {
+		satisfied, err := vuln.Constraint.Satisfied(v)
+		if err != nil {
+			return nil, err
+		}
+		if satisfied {
+			results = append(results, match.Match{Vulnerability: vuln, Package: p})
+		}
+	}
+	return results, nil
+}
+
+func TestParsePrereleaseComparison(t *testing.T) {
+	for _, c := range PrereleaseComparisons {
+		got, err := ParsePrereleaseComparison(string(c))
+		require.NoError(t, err)
+		assert.Equal(t, c, got)
+	}
+
+	got, err := ParsePrereleaseComparison(" As-Release ")
+	require.NoError(t, err)
+	assert.Equal(t, AsReleasePrereleaseComparison, got)
+
+	_, err = ParsePrereleaseComparison("newest")
+	require.Error(t, err)
+}
+
+func TestWithPolicy_prereleaseComparison(t *testing.T) {
+	vulns := []vulnerability.Vulnerability{
+		// fixed in the release that the package is a pre-release of
+		{ID: "CVE-fixed-in-release", Constraint: version.MustGetConstraint("< 2.0.0", version.PythonFormat)},
+		// introduced in the release that the package is a pre-release of
+		{ID: "CVE-introduced-in-release", Constraint: version.MustGetConstraint(">= 2.0.0, < 2.0.5", version.PythonFormat)},
+		// fixed in a later pre-release
+		{ID: "CVE-fixed-in-prerelease", Constraint: version.MustGetConstraint("< 2.0.0rc2", version.PythonFormat)},
+	}
+
+	tests := []struct {
+		name       string
+		comparison PrereleaseComparison
+		version    string
+		want       []string
+	}{
+		{
+			name:    "ordered",
+			version: "2.0.0rc1",
+			want:    []string{"CVE-fixed-in-release", "CVE-fixed-in-prerelease"},
+		},
+		{
+			name:       "as release",
+			comparison: AsReleasePrereleaseComparison,
+			version:    "2.0.0rc1",
+			want:       []string{"CVE-fixed-in-prerelease", "CVE-introduced-in-release"},
+		},
+		{
+			name:       "either",
+			comparison: EitherPrereleaseComparison,
+			version:    "2.0.0rc1",
+			want:       []string{"CVE-fixed-in-release", "CVE-fixed-in-prerelease", "CVE-introduced-in-release"},
+		},
+		{
+			name:       "exclude",
+			comparison: ExcludePrereleaseComparison,
+			version:    "2.0.0rc1",
+			want:       []string{"CVE-fixed-in-prerelease"},
+		},
+		{
+			name:       "release versions are not affected",
+			comparison: ExcludePrereleaseComparison,
+			version:    "2.0.1",
+			want:       []string{"CVE-introduced-in-release"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &constraintMatcher{vulnerabilities: vulns}
+			m := WithPolicy(inner, Policy{AllowPrereleaseConstraints: true, PrereleaseComparison: tt.comparison})
+
+			p := pkg.Package{Name: "lib", Version: tt.version, Type: syftPkg.PythonPkg}
+			matches, err := m.Match(nil, nil, p)
+			require.NoError(t, err)
+
+			var got []string
+			for _, mt := range matches {
+				got = append(got, mt.Vulnerability.ID)
+				assert.Equal(t, tt.version, mt.Package.Version, "matches should report the package as found")
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithPolicy_ignoreBuildMetadata(t *testing.T) {
+	inner := &constraintMatcher{vulnerabilities: []vulnerability.Vulnerability{
+		{ID: "CVE-fixed", Constraint: version.MustGetConstraint("<= 1.0", version.PythonFormat)},
+	}}
+	p := pkg.Package{Name: "lib", Version: "1.0+ubuntu1", Type: syftPkg.PythonPkg}
+
+	// local versions of PEP 440 are greater than the version they are local to
+	matches, err := inner.Match(nil, nil, p)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	matches, err = WithPolicy(inner, Policy{AllowPrereleaseConstraints: true, IgnoreBuildMetadata: true}).Match(nil, nil, p)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "1.0+ubuntu1", matches[0].Package.Version)
+	assert.Equal(t, "1.0", inner.searchedVersions[len(inner.searchedVersions)-1])
+
+	// the "+" of debian versions is part of the upstream version, not build metadata
+	inner = &constraintMatcher{vulnerabilities: []vulnerability.Vulnerability{
+		{ID: "CVE-fixed", Constraint: version.MustGetConstraint("< 1.2+dfsg-2", version.DebFormat)},
+	}}
+	p = pkg.Package{Name: "lib", Version: "1.2+dfsg-1", Type: syftPkg.DebPkg}
+	matches, err = WithPolicy(inner, Policy{AllowPrereleaseConstraints: true, IgnoreBuildMetadata: true}).Match(nil, nil, p)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "1.2+dfsg-1", inner.searchedVersions[len(inner.searchedVersions)-1])
+}
+
+func TestParseNameNormalization(t *testing.T) {
+	for _, n := range NameNormalizations {
+		got, err := ParseNameNormalization(string(n))
+		require.NoError(t, err)
+		assert.Equal(t, n, got)
+	}
+
+	got, err := ParseNameNormalization(" PEP503 ")
+	require.NoError(t, err)
+	assert.Equal(t, PEP503NameNormalization, got)
+
+	_, err = ParseNameNormalization("soundex")
+	require.Error(t, err)
+}
+
+func TestNameNormalization_Apply(t *testing.T) {
+	assert.Equal(t, "Zope.Interface", DefaultNameNormalization.Apply("Zope.Interface"))
+	assert.Equal(t, "zope.interface", LowercaseNameNormalization.Apply("Zope.Interface"))
+	assert.Equal(t, "zope-interface", PEP503NameNormalization.Apply("Zope.Interface"))
+	assert.Equal(t, "foo-bar", PEP503NameNormalization.Apply("Foo__-.Bar"))
+}
+
+func TestHasPrereleaseBound(t *testing.T) {
+	tests := []struct {
+		constraint string
+		format     version.Format
+		want       bool
+	}{
+		{constraint: "< 2.0.0", format: version.SemanticFormat},
+		{constraint: ">= 1.0.0, < 2.0.0-rc.1", format: version.SemanticFormat, want: true},
+		{constraint: "< 1.2.3 || >= 2.0.0-beta, < 2.0.1", format: version.SemanticFormat, want: true},
+		{constraint: "< 2.0a1", format: version.PythonFormat, want: true},
+		{constraint: "< 2.0.dev0", format: version.PythonFormat, want: true},
+		{constraint: "< 2.0.post1", format: version.PythonFormat},
+		{constraint: "< 3.0-M1", format: version.MavenFormat, want: true},
+		{constraint: "< 3.0.0-SNAPSHOT", format: version.MavenFormat, want: true},
+		{constraint: "< 3.0.1.Final", format: version.MavenFormat},
+		{constraint: "< 1.2.3-alpine", format: version.UnknownFormat},
+		{constraint: "< 1.1.1c-1", format: version.UnknownFormat},
+		{constraint: "< 1.2+dfsg~rc1-1", format: version.DebFormat},
+		{constraint: "(< 1.2.3 || >= 2.0.0-beta), != 1.0.0", format: version.SemanticFormat, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := version.GetConstraint(tt.constraint, tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, hasPrereleaseBound(c, tt.format))
+		})
+	}
+
+	// the format of the constraint is used for packages without a known version format (e.g. npm packages)
+	assert.True(t, hasPrereleaseBound(version.MustGetConstraint("< 2.0.0-alpine.1", version.SemanticFormat), version.UnknownFormat))
+	assert.False(t, hasPrereleaseBound(version.MustGetConstraint("< 2.0.0-alpine.1", version.UnknownFormat), version.UnknownFormat))
+}
+
+func TestWithPolicy(t *testing.T) {
+	inner := &recordingMatcher{
+		results: []match.Match{
+			{Vulnerability: vulnerability.Vulnerability{ID: "CVE-release", Constraint: version.MustGetConstraint("< 2.0.0", version.PythonFormat)}},
+			{Vulnerability: vulnerability.Vulnerability{ID: "CVE-prerelease", Constraint: version.MustGetConstraint("< 2.0.0rc1", version.PythonFormat)}},
+		},
+	}
+
+	assert.Same(t, inner, WithPolicy(inner, DefaultPolicy()).(*recordingMatcher), "default policies should not wrap the matcher")
+
+	m := WithPolicy(inner, Policy{
+		AllowPrereleaseConstraints: false,
+		NameNormalization:          PEP503NameNormalization,
+	})
+	assert.Equal(t, match.PythonMatcher, m.Type())
+
+	p := pkg.Package{Name: "Zope.Interface", Version: "1.0.0", Type: syftPkg.PythonPkg}
+	matches, err := m.Match(nil, nil, p)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"zope-interface"}, inner.searched)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "CVE-release", matches[0].Vulnerability.ID)
+	assert.Equal(t, "Zope.Interface", matches[0].Package.Name, "matches should report the package as found")
+}
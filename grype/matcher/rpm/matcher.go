@@ -101,6 +101,17 @@ func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Pa
 
 	matches = append(matches, exactMatches...)
 
+	// the provided packages (which take the version of the package unless given) are as explicit about the epoch as
+	// the package itself
+	epochPkg := p
+	addEpochIfApplicable(&epochPkg)
+	providedMatches, err := search.ByProvidedPackages(store, d, epochPkg, m.Type())
+	if err != nil {
+		return nil, fmt.Errorf("failed to match by provided packages: %w", err)
+	}
+	match.ConvertToIndirectMatches(providedMatches, p)
+	matches = append(matches, providedMatches...)
+
 	return matches, nil
 }
 
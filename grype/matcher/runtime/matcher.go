@@ -0,0 +1,28 @@
+package runtime
+
+import (
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// Matcher finds vulnerabilities for language runtimes and runtime libraries identified from their binaries (e.g. node,
+// python or openssl). There are no ecosystem-specific advisory feeds for these binaries, so matching is always done by
+// CPE, regardless of whether CPEs are used by the stock matcher.
+type Matcher struct {
+}
+
+func (m *Matcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{pkg.RuntimePkg}
+}
+
+func (m *Matcher) Type() match.MatcherType {
+	return match.RuntimeMatcher
+}
+
+func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	return search.ByCriteria(store, d, p, m.Type(), search.ByCPE)
+}
@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockProvider struct {
+	vulnerability.Provider
+	byCPE map[string][]vulnerability.Vulnerability
+}
+
+func (pr *mockProvider) GetByCPE(c cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	return pr.byCPE[c.Attributes.Product], nil
+}
+
+func (pr *mockProvider) GetByLanguage(syftPkg.Language, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func TestMatcher_Match(t *testing.T) {
+	vulnCPE := cpe.Must("cpe:2.3:a:nodejs:node.js:*:*:*:*:*:*:*:*", "")
+	store := &mockProvider{
+		byCPE: map[string][]vulnerability.Vulnerability{
+			"node.js": {
+				{
+					ID:         "CVE-2024-fake-1",
+					Namespace:  "nvd:cpe",
+					Constraint: version.MustGetConstraint(">= 18.0.0, < 18.20.1", version.UnknownFormat),
+					CPEs:       []cpe.CPE{vulnCPE},
+				},
+				{
+					ID:         "CVE-2024-fake-2",
+					Namespace:  "nvd:cpe",
+					Constraint: version.MustGetConstraint(">= 20.0.0, < 20.12.1", version.UnknownFormat),
+					CPEs:       []cpe.CPE{vulnCPE},
+				},
+			},
+		},
+	}
+
+	// a node binary identified by an SBOM without CPEs
+	p := pkg.New(syftPkg.Package{Name: "node", Version: "18.19.1", Type: syftPkg.BinaryPkg})
+	p.ID = pkg.ID(uuid.NewString())
+	require.Equal(t, syftPkg.BinaryPkg, p.Type)
+	require.Equal(t, "node", p.Runtime)
+
+	m := Matcher{}
+	actual, err := m.Match(store, nil, p)
+	require.NoError(t, err)
+
+	require.Len(t, actual, 1)
+	assert.Equal(t, "CVE-2024-fake-1", actual[0].Vulnerability.ID)
+	require.NotEmpty(t, actual[0].Details)
+	assert.Equal(t, match.CPEMatch, actual[0].Details[0].Type)
+	assert.Equal(t, match.RuntimeMatcher, actual[0].Details[0].Matcher)
+}
@@ -0,0 +1,133 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+)
+
+// TargetingConfig chooses which package names the advisories of a distro are matched against. The targeting for a
+// distro type takes precedence over the default targeting, which takes precedence over the default of the
+// vulnerability DB for the distro (when the store provides one). Without any of these both source and binary package
+// names are matched against.
+type TargetingConfig struct {
+	// Default is the targeting for all distros (unless set for the distro type).
+	Default match.Targeting
+	// ByDistro is the targeting by distro type (e.g. "debian" or "redhat").
+	ByDistro map[string]match.Targeting
+}
+
+// resolve returns the targeting for the given distro.
+func (c TargetingConfig) resolve(store vulnerability.Provider, d *distro.Distro) match.Targeting {
+	if d == nil {
+		return match.BothTargeting
+	}
+	if t := c.ByDistro[strings.ToLower(string(d.Type))]; t != match.DefaultTargeting {
+		return t
+	}
+	if c.Default != match.DefaultTargeting {
+		return c.Default
+	}
+	if provider, ok := store.(match.TargetingProvider); ok {
+		t, err := provider.GetTargeting(d)
+		if err != nil {
+			log.WithFields("distro", d, "error", err).Debug("unable to get the package targeting of the distro")
+		} else if t != match.DefaultTargeting {
+			return t
+		}
+	}
+	return match.BothTargeting
+}
+
+// WithTargeting wraps the given matcher of OS packages such that only the matches for the package names the
+// advisories of the distro are filed against (source and/or binary package names) are reported.
+func WithTargeting(m Matcher, config TargetingConfig) Matcher {
+	return &targetingMatcher{
+		Matcher: m,
+		config:  config,
+	}
+}
+
+type targetingMatcher struct {
+	Matcher
+	config TargetingConfig
+}
+
+func (m *targetingMatcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	matches, err := m.Matcher.Match(store, d, p)
+	if err != nil {
+		return nil, err
+	}
+
+	targeting := m.config.resolve(store, d)
+	if targeting == match.BothTargeting {
+		return matches, nil
+	}
+
+	// a package without a source package is its own source
+	sources := map[string]bool{p.Name: len(p.Upstreams) == 0}
+	for _, u := range p.Upstreams {
+		sources[u.Name] = true
+	}
+
+	recorder := match.RejectionRecorderOf(store)
+	var results []match.Match
+	for _, mt := range matches {
+		if !targeted(mt, p, sources, targeting) {
+			log.WithFields("vulnerability", mt.Vulnerability.ID, "package", p.Name, "targeting", targeting).Trace("skipping match for untargeted package name")
+			recorder.RecordRejection(match.Rejection{
+				Vulnerability: mt.Vulnerability,
+				Package:       p,
+				Filter:        match.TargetingRejection,
+				Reason:        fmt.Sprintf("the advisories of the distro are matched against %s package names only", targeting),
+			})
+			continue
+		}
+		results = append(results, mt)
+	}
+	return results, nil
+}
+
+// targeted indicates if any detail of the match searched by a package name that is targeted. Details that did not
+// search by package name (e.g. CPE searches) are not affected by the targeting.
+func targeted(mt match.Match, p pkg.Package, sources map[string]bool, targeting match.Targeting) bool {
+	for _, d := range mt.Details {
+		name, ok := searchedPackageName(d)
+		if !ok {
+			return true
+		}
+		isSource := sources[name]
+		switch targeting {
+		case match.SourceTargeting:
+			if isSource {
+				return true
+			}
+		case match.BinaryTargeting:
+			// the binary package names include those of the packages it provides
+			if !isSource || name == p.Name {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func searchedPackageName(d match.Detail) (string, bool) {
+	searchedBy, ok := d.SearchedBy.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	searchedPkg, ok := searchedBy["package"].(map[string]string)
+	if !ok {
+		return "", false
+	}
+	name, ok := searchedPkg["name"]
+	return name, ok
+}
@@ -0,0 +1,137 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+type targetingStore struct {
+	vulnerability.Provider
+	targeting match.Targeting
+}
+
+func (s targetingStore) GetTargeting(_ *distro.Distro) (match.Targeting, error) {
+	return s.targeting, nil
+}
+
+func searchedMatch(id, name string) match.Match {
+	return match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: id},
+		Details: []match.Detail{{
+			SearchedBy: map[string]interface{}{
+				"package": map[string]string{"name": name, "version": "1.0"},
+			},
+		}},
+	}
+}
+
+func TestWithTargeting(t *testing.T) {
+	p := pkg.Package{Name: "libssl3", Version: "3.0.11-1", Upstreams: []pkg.UpstreamPackage{{Name: "openssl"}}}
+	inner := &recordingMatcher{results: []match.Match{
+		searchedMatch("CVE-binary", "libssl3"),
+		searchedMatch("CVE-source", "openssl"),
+		searchedMatch("CVE-provided", "libssl"),
+		// matches that were not searched by package name are never filtered
+		{Vulnerability: vulnerability.Vulnerability{ID: "CVE-cpe"}, Details: []match.Detail{{SearchedBy: "cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*"}}},
+	}}
+
+	debian, err := distro.New(distro.Debian, "12", "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		config TargetingConfig
+		store  vulnerability.Provider
+		distro *distro.Distro
+		want   []string
+	}{
+		{
+			name:   "both by default",
+			distro: debian,
+			want:   []string{"CVE-binary", "CVE-source", "CVE-provided", "CVE-cpe"},
+		},
+		{
+			name:   "source",
+			config: TargetingConfig{Default: match.SourceTargeting},
+			distro: debian,
+			want:   []string{"CVE-source", "CVE-cpe"},
+		},
+		{
+			name:   "binary",
+			config: TargetingConfig{Default: match.BinaryTargeting},
+			distro: debian,
+			want:   []string{"CVE-binary", "CVE-provided", "CVE-cpe"},
+		},
+		{
+			name:   "DB default",
+			store:  targetingStore{targeting: match.SourceTargeting},
+			distro: debian,
+			want:   []string{"CVE-source", "CVE-cpe"},
+		},
+		{
+			name:   "configured default over the DB default",
+			config: TargetingConfig{Default: match.BothTargeting},
+			store:  targetingStore{targeting: match.SourceTargeting},
+			distro: debian,
+			want:   []string{"CVE-binary", "CVE-source", "CVE-provided", "CVE-cpe"},
+		},
+		{
+			name:   "distro over the configured default",
+			config: TargetingConfig{Default: match.SourceTargeting, ByDistro: map[string]match.Targeting{"debian": match.BinaryTargeting}},
+			distro: debian,
+			want:   []string{"CVE-binary", "CVE-provided", "CVE-cpe"},
+		},
+		{
+			name:   "no distro",
+			config: TargetingConfig{Default: match.SourceTargeting},
+			want:   []string{"CVE-binary", "CVE-source", "CVE-provided", "CVE-cpe"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches, err := WithTargeting(inner, test.config).Match(test.store, test.distro, p)
+			require.NoError(t, err)
+
+			var got []string
+			for _, m := range matches {
+				got = append(got, m.Vulnerability.ID)
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestWithTargeting_packageWithoutSource(t *testing.T) {
+	// a package without a source package is its own source
+	p := pkg.Package{Name: "openssl", Version: "3.0.11-1"}
+	inner := &recordingMatcher{results: []match.Match{searchedMatch("CVE-binary", "openssl")}}
+
+	d, err := distro.New(distro.Debian, "12", "")
+	require.NoError(t, err)
+
+	matches, err := WithTargeting(inner, TargetingConfig{Default: match.SourceTargeting}).Match(nil, d, p)
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+}
+
+func TestParseTargeting(t *testing.T) {
+	for _, targeting := range append(match.Targetings, match.DefaultTargeting) {
+		got, err := match.ParseTargeting(string(targeting))
+		require.NoError(t, err)
+		assert.Equal(t, targeting, got)
+	}
+
+	got, err := match.ParseTargeting(" Source ")
+	require.NoError(t, err)
+	assert.Equal(t, match.SourceTargeting, got)
+
+	_, err = match.ParseTargeting("upstream")
+	require.Error(t, err)
+}
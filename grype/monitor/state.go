@@ -0,0 +1,174 @@
+/*
+Package monitor tracks the findings reported for a set of long-lived scan targets (typically stored SBOMs) so that
+re-scanning the targets after a vulnerability DB update only surfaces findings that were not reported before.
+*/
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Finding is a single vulnerability match for a monitored target.
+type Finding struct {
+	Target        string   `json:"target"`
+	Package       string   `json:"package"`
+	Version       string   `json:"version"`
+	Type          string   `json:"type"`
+	Vulnerability string   `json:"vulnerability"`
+	Severity      string   `json:"severity"`
+	FixVersions   []string `json:"fixVersions,omitempty"`
+}
+
+// key identifies the finding independent of details that may change between DB builds (e.g. severity).
+func (f Finding) key() string {
+	return strings.Join([]string{f.Package, f.Version, f.Type, f.Vulnerability}, "|")
+}
+
+type targetState struct {
+	Digest   string   `json:"digest,omitempty"`
+	Findings []string `json:"findings"`
+	// Failed is set when the last scan of the target failed, so that it is scanned again in the next cycle even if
+	// neither the DB nor the target changed.
+	Failed bool `json:"failed,omitempty"`
+}
+
+// State is the record of what has already been reported, persisted between monitoring cycles.
+type State struct {
+	DBChecksum string                  `json:"dbChecksum"`
+	Targets    map[string]*targetState `json:"targets"`
+}
+
+func NewState() *State {
+	return &State{
+		Targets: make(map[string]*targetState),
+	}
+}
+
+// LoadState reads the state from the given path. A new, empty state is returned when the file does not exist yet.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewState(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read monitor state: %w", err)
+	}
+
+	s := NewState()
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("unable to parse monitor state %q: %w", path, err)
+	}
+	if s.Targets == nil {
+		s.Targets = make(map[string]*targetState)
+	}
+	return s, nil
+}
+
+// Save writes the state to the given path. The file is replaced atomically so that an interrupted write never
+// leaves a partial state behind (which would cause every finding to be reported again).
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", " ")
+	if err != nil {
+		return fmt.Errorf("unable to encode monitor state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("unable to create monitor state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to write monitor state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write monitor state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write monitor state: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// NeedsScan indicates if the target must be (re)scanned: either the DB has changed since the last cycle, the
+// target has changed, the last scan of the target failed, or the target has never been scanned.
+func (s *State) NeedsScan(t Target, dbChecksum string) bool {
+	if s.DBChecksum != dbChecksum {
+		return true
+	}
+	existing, ok := s.Targets[t.Name]
+	if !ok {
+		return true
+	}
+	return existing.Failed || existing.Digest != t.Digest
+}
+
+// Failed records that the target could not be scanned, keeping its previous findings (so they are not reported again
+// once it is scanned) while making sure it is scanned in the next cycle.
+func (s *State) Failed(t Target) {
+	if existing, ok := s.Targets[t.Name]; ok {
+		existing.Failed = true
+	}
+}
+
+// Record stores the complete set of findings for the target and returns only the findings that have not been
+// recorded for the target before. Findings that are no longer present are forgotten, so they are reported again if
+// they reappear.
+func (s *State) Record(t Target, findings []Finding) []Finding {
+	previous := make(map[string]struct{})
+	if existing, ok := s.Targets[t.Name]; ok {
+		for _, k := range existing.Findings {
+			previous[k] = struct{}{}
+		}
+	}
+
+	current := make(map[string]struct{})
+	var added []Finding
+	for _, f := range findings {
+		k := f.key()
+		if _, ok := current[k]; ok {
+			continue
+		}
+		current[k] = struct{}{}
+		if _, ok := previous[k]; !ok {
+			added = append(added, f)
+		}
+	}
+
+	keys := make([]string, 0, len(current))
+	for k := range current {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	s.Targets[t.Name] = &targetState{
+		Digest:   t.Digest,
+		Findings: keys,
+	}
+
+	sort.SliceStable(added, func(i, j int) bool {
+		return added[i].key() < added[j].key()
+	})
+	return added
+}
+
+// Prune forgets any targets that are no longer being monitored.
+func (s *State) Prune(targets []Target) {
+	keep := make(map[string]struct{})
+	for _, t := range targets {
+		keep[t.Name] = struct{}{}
+	}
+	for name := range s.Targets {
+		if _, ok := keep[name]; !ok {
+			delete(s.Targets, name)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState_Record(t *testing.T) {
+	target := Target{Name: "sbom.json", Digest: "sha256:1"}
+	finding := func(id string) Finding {
+		return Finding{Target: target.Name, Package: "lodash", Version: "4.17.20", Type: "npm", Vulnerability: id}
+	}
+
+	s := NewState()
+
+	added := s.Record(target, []Finding{finding("CVE-2"), finding("CVE-1"), finding("CVE-1")})
+	assert.Equal(t, []Finding{finding("CVE-1"), finding("CVE-2")}, added)
+
+	// nothing new to report
+	added = s.Record(target, []Finding{finding("CVE-1"), finding("CVE-2")})
+	assert.Empty(t, added)
+
+	// only the newly published vulnerability is reported
+	added = s.Record(target, []Finding{finding("CVE-1"), finding("CVE-2"), finding("CVE-3")})
+	assert.Equal(t, []Finding{finding("CVE-3")}, added)
+
+	// a vulnerability that disappears and comes back is reported again
+	s.Record(target, []Finding{finding("CVE-2"), finding("CVE-3")})
+	added = s.Record(target, []Finding{finding("CVE-1"), finding("CVE-2"), finding("CVE-3")})
+	assert.Equal(t, []Finding{finding("CVE-1")}, added)
+
+	// severity changes are not new findings
+	changed := finding("CVE-1")
+	changed.Severity = "Critical"
+	added = s.Record(target, []Finding{changed, finding("CVE-2"), finding("CVE-3")})
+	assert.Empty(t, added)
+}
+
+func TestState_NeedsScan(t *testing.T) {
+	s := NewState()
+	target := Target{Name: "sbom.json", Digest: "sha256:1"}
+
+	assert.True(t, s.NeedsScan(target, "db-1"), "never scanned")
+
+	s.Record(target, nil)
+	s.DBChecksum = "db-1"
+	assert.False(t, s.NeedsScan(target, "db-1"))
+	assert.True(t, s.NeedsScan(target, "db-2"), "db changed")
+	assert.True(t, s.NeedsScan(Target{Name: "sbom.json", Digest: "sha256:2"}, "db-1"), "target changed")
+	assert.True(t, s.NeedsScan(Target{Name: "other.json"}, "db-1"), "new target")
+
+	s.Failed(target)
+	assert.True(t, s.NeedsScan(target, "db-1"), "last scan failed")
+	s.Record(target, nil)
+	assert.False(t, s.NeedsScan(target, "db-1"))
+}
+
+func TestState_Prune(t *testing.T) {
+	s := NewState()
+	s.Record(Target{Name: "a"}, nil)
+	s.Record(Target{Name: "b"}, nil)
+
+	s.Prune([]Target{{Name: "b"}})
+
+	assert.NotContains(t, s.Targets, "a")
+	assert.Contains(t, s.Targets, "b")
+}
+
+func TestState_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+
+	s, err := LoadState(path)
+	require.NoError(t, err)
+	assert.Empty(t, s.Targets)
+
+	target := Target{Name: "sbom.json", Digest: "sha256:1"}
+	s.DBChecksum = "db-1"
+	s.Record(target, []Finding{{Package: "lodash", Version: "4.17.20", Type: "npm", Vulnerability: "CVE-1"}})
+	require.NoError(t, s.Save(path))
+
+	loaded, err := LoadState(path)
+	require.NoError(t, err)
+	assert.Equal(t, s, loaded)
+	assert.False(t, loaded.NeedsScan(target, "db-1"))
+}
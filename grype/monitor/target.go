@@ -0,0 +1,253 @@
+package monitor
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anchore/grype/grype/match"
+)
+
+// Target is a single input to scan on each monitoring cycle.
+type Target struct {
+	// Name identifies the target within the state and the reported findings.
+	Name string
+	// Input is the grype input to scan (e.g. "sbom:path/to/sbom.json" or "registry:alpine:3.20").
+	Input string
+	// Digest changes whenever the content of the target changes. It is empty for targets whose content cannot be
+	// inspected cheaply (such as images), which are only rescanned when the DB changes.
+	Digest string
+
+	// Distro is the distro of the target (as "<distro>:<version>"), overriding the one detected from its content.
+	Distro string
+	// Ignore are the ignore rules of the target, applied in addition to the ignore rules of the monitor.
+	Ignore []match.IgnoreRule
+	// VexDocuments are the VEX documents applied to the findings of the target.
+	VexDocuments []string
+}
+
+// manifest is a YAML manifest of targets, which may override the context of each target.
+type manifest struct {
+	Targets []manifestTarget `yaml:"targets"`
+}
+
+type manifestTarget struct {
+	Name         string             `yaml:"name"`
+	Input        string             `yaml:"input"`
+	Distro       string             `yaml:"distro"`
+	Ignore       []match.IgnoreRule `yaml:"ignore"`
+	VexDocuments []string           `yaml:"vex-documents"`
+}
+
+// Targets discovers the targets to monitor. When the path is a directory every (non-hidden) file within it is
+// treated as an SBOM. A ".yaml" (or ".yml") path is read as a YAML manifest of targets, which may each override their
+// distro, ignore rules and VEX documents. Otherwise the path is read as a manifest with one grype input per line ("#"
+// starts a comment).
+func Targets(path string) ([]Target, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read monitor targets: %w", err)
+	}
+
+	if info.IsDir() {
+		return sbomTargets(path)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return yamlManifestTargets(path)
+	}
+	return manifestTargets(path)
+}
+
+func sbomTargets(dir string) ([]Target, error) {
+	var targets []Target
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && p != dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		digest, err := fileDigest(p)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, Target{
+			Name:   p,
+			Input:  "sbom:" + p,
+			Digest: digest,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read monitor targets: %w", err)
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+	return targets, nil
+}
+
+func manifestTargets(path string) ([]Target, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read monitor manifest: %w", err)
+	}
+	defer f.Close()
+
+	var targets []Target
+	seen := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, ok := seen[line]; ok {
+			continue
+		}
+		seen[line] = struct{}{}
+
+		t := Target{
+			Name:  line,
+			Input: line,
+		}
+		// local SBOMs referenced from the manifest can still be digested to detect changes
+		if p, ok := strings.CutPrefix(line, "sbom:"); ok {
+			if digest, err := fileDigest(p); err == nil {
+				t.Digest = digest
+			}
+		}
+		targets = append(targets, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read monitor manifest: %w", err)
+	}
+	return targets, nil
+}
+
+func yamlManifestTargets(path string) ([]Target, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read monitor manifest: %w", err)
+	}
+	var m manifest
+	if err := yaml.Unmarshal(contents, &m); err != nil {
+		return nil, fmt.Errorf("unable to parse monitor manifest %q: %w", path, err)
+	}
+
+	var targets []Target
+	seen := make(map[string]struct{})
+	for i, mt := range m.Targets {
+		if mt.Input == "" {
+			return nil, fmt.Errorf("target %d of monitor manifest %q has no input", i+1, path)
+		}
+		name := mt.Name
+		if name == "" {
+			name = mt.Input
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("duplicate target %q in monitor manifest %q", name, path)
+		}
+		seen[name] = struct{}{}
+		for _, rule := range mt.Ignore {
+			if err := rule.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid ignore rule of target %q: %w", name, err)
+			}
+		}
+
+		t := Target{
+			Name:         name,
+			Input:        mt.Input,
+			Distro:       mt.Distro,
+			Ignore:       mt.Ignore,
+			VexDocuments: manifestPaths(path, mt.VexDocuments),
+		}
+		if p, ok := strings.CutPrefix(mt.Input, "sbom:"); ok {
+			if digest, err := fileDigest(p); err == nil {
+				t.Digest = digest
+			}
+		}
+		digest, err := overridesDigest(t)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the overrides of target %q: %w", name, err)
+		}
+		t.Digest = digest
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// manifestPaths resolves the paths given in a manifest against the directory of the manifest, so that a manifest
+// refers to the same files wherever the monitor is started from.
+func manifestPaths(manifestPath string, paths []string) []string {
+	if len(paths) == 0 {
+		return paths
+	}
+	resolved := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(filepath.Dir(manifestPath), p)
+		}
+		resolved = append(resolved, p)
+	}
+	return resolved
+}
+
+// overridesDigest folds the overrides of the target (including the content of its VEX documents) into the digest of
+// its content, so that changing them rescans the target.
+func overridesDigest(t Target) (string, error) {
+	if t.Distro == "" && len(t.Ignore) == 0 && len(t.VexDocuments) == 0 {
+		return t.Digest, nil
+	}
+
+	vexDigests := make([]string, 0, len(t.VexDocuments))
+	for _, doc := range t.VexDocuments {
+		digest, err := fileDigest(doc)
+		if err != nil {
+			return "", err
+		}
+		vexDigests = append(vexDigests, digest)
+	}
+
+	overrides, err := json.Marshal(struct {
+		Digest string
+		Distro string
+		Ignore []match.IgnoreRule
+		Vex    []string
+	}{t.Digest, t.Distro, t.Ignore, vexDigests})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(overrides)), nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
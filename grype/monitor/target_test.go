@@ -0,0 +1,152 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+)
+
+func TestTargets(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		inputs []string
+	}{
+		{
+			name:   "directory of sboms skips hidden entries",
+			path:   "test-fixtures/sboms",
+			inputs: []string{"sbom:test-fixtures/sboms/a.json", "sbom:test-fixtures/sboms/b.json"},
+		},
+		{
+			name:   "manifest skips comments, blank lines, and duplicates",
+			path:   "test-fixtures/manifest.txt",
+			inputs: []string{"registry:alpine:3.20", "sbom:test-fixtures/sboms/a.json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := Targets(tt.path)
+			require.NoError(t, err)
+
+			var inputs []string
+			for _, target := range targets {
+				inputs = append(inputs, target.Input)
+			}
+			assert.Equal(t, tt.inputs, inputs)
+		})
+	}
+}
+
+func TestTargets_Digests(t *testing.T) {
+	targets, err := Targets("test-fixtures/manifest.txt")
+	require.NoError(t, err)
+	require.Len(t, targets, 2)
+
+	// images cannot be digested cheaply, local sboms can
+	assert.Empty(t, targets[0].Digest)
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", targets[1].Digest)
+
+	fromDir, err := Targets("test-fixtures/sboms")
+	require.NoError(t, err)
+	assert.Equal(t, targets[1].Digest, fromDir[0].Digest)
+	assert.NotEqual(t, fromDir[0].Digest, fromDir[1].Digest)
+}
+
+func TestTargets_Missing(t *testing.T) {
+	_, err := Targets("test-fixtures/does-not-exist")
+	require.Error(t, err)
+}
+
+func TestTargets_YAMLManifest(t *testing.T) {
+	targets, err := Targets("test-fixtures/manifest.yaml")
+	require.NoError(t, err)
+	require.Len(t, targets, 3)
+
+	assert.Equal(t, "registry:alpine:3.20", targets[0].Name)
+	assert.Equal(t, "alpine:3.20", targets[0].Distro)
+	// the overrides of an image are digested, so changing them rescans it
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", targets[0].Digest)
+
+	assert.Equal(t, "app", targets[1].Name)
+	assert.Equal(t, "sbom:test-fixtures/sboms/a.json", targets[1].Input)
+	assert.Equal(t, []match.IgnoreRule{{Vulnerability: "CVE-2024-1234", Reason: "not reachable in app"}}, targets[1].Ignore)
+	assert.Equal(t, []string{"test-fixtures/vex/a.openvex.json"}, targets[1].VexDocuments)
+
+	// targets without overrides keep the digest of their content
+	fromDir, err := Targets("test-fixtures/sboms")
+	require.NoError(t, err)
+	assert.NotEqual(t, fromDir[0].Digest, targets[1].Digest)
+	assert.Equal(t, fromDir[1].Digest, targets[2].Digest)
+}
+
+func TestTargets_YAMLManifestOverridesDigest(t *testing.T) {
+	dir := t.TempDir()
+	vexPath := filepath.Join(dir, "app.openvex.json")
+	manifestPath := filepath.Join(dir, "targets.yml")
+	write := func(path, contents string) {
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	}
+	digest := func() string {
+		targets, err := Targets(manifestPath)
+		require.NoError(t, err)
+		require.Len(t, targets, 1)
+		return targets[0].Digest
+	}
+
+	write(vexPath, `{"statements": []}`)
+	write(manifestPath, "targets:\n  - input: registry:app:1.0\n    vex-documents: ["+vexPath+"]\n")
+	original := digest()
+	// the document is the same when given relative to the manifest
+	write(manifestPath, "targets:\n  - input: registry:app:1.0\n    vex-documents: [app.openvex.json]\n")
+	assert.Equal(t, original, digest())
+	assert.Equal(t, original, digest())
+
+	write(vexPath, `{"statements": [{}]}`)
+	changedVex := digest()
+	assert.NotEqual(t, original, changedVex)
+
+	write(manifestPath, "targets:\n  - input: registry:app:1.0\n    distro: debian:12\n    vex-documents: ["+vexPath+"]\n")
+	assert.NotEqual(t, changedVex, digest())
+}
+
+func TestTargets_YAMLManifestInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{
+			name:     "target without input",
+			manifest: "targets:\n  - name: app\n",
+			wantErr:  "has no input",
+		},
+		{
+			name:     "duplicate targets",
+			manifest: "targets:\n  - input: registry:app:1.0\n  - input: registry:app:1.0\n",
+			wantErr:  "duplicate target",
+		},
+		{
+			name:     "invalid ignore rule",
+			manifest: "targets:\n  - input: registry:app:1.0\n    ignore:\n      - published-within: soon\n",
+			wantErr:  "invalid ignore rule",
+		},
+		{
+			name:     "missing VEX document",
+			manifest: "targets:\n  - input: registry:app:1.0\n    vex-documents: [does-not-exist.json]\n",
+			wantErr:  "overrides",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "targets.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.manifest), 0o600))
+			_, err := Targets(path)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
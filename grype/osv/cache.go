@@ -0,0 +1,93 @@
+package osv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+// apiCache keeps the query results and records of the OSV.dev API on disk, so that later scans can reuse them (and
+// fall back to them when the API cannot be reached). Nothing is cached when the directory is empty.
+type apiCache struct {
+	dir string
+	ttl time.Duration
+}
+
+type cachedQuery struct {
+	Fetched time.Time            `json:"fetched"`
+	Vulns   []QueryVulnerability `json:"vulns,omitempty"`
+}
+
+// query returns the cached result of the query, and whether the result is younger than the TTL.
+func (c apiCache) query(q Query) (*cachedQuery, bool) {
+	var cached cachedQuery
+	if !c.read(c.queryPath(q), &cached) {
+		return nil, false
+	}
+	return &cached, time.Since(cached.Fetched) < c.ttl
+}
+
+func (c apiCache) putQuery(q Query, vulns []QueryVulnerability) {
+	c.write(c.queryPath(q), cachedQuery{Fetched: time.Now().UTC(), Vulns: vulns})
+}
+
+// record returns the cached record with the given ID, provided it is not older than the given modification time.
+func (c apiCache) record(id string, modified time.Time) (*Vulnerability, bool) {
+	var v Vulnerability
+	if !c.read(c.recordPath(id), &v) {
+		return nil, false
+	}
+	if v.Modified.Before(modified) {
+		return nil, false
+	}
+	return &v, true
+}
+
+func (c apiCache) putRecord(v *Vulnerability) {
+	c.write(c.recordPath(v.ID), v)
+}
+
+func (c apiCache) queryPath(q Query) string {
+	sum := sha256.Sum256([]byte(q.Package.Ecosystem + "\x00" + q.Package.Name + "\x00" + q.Version))
+	return filepath.Join(c.dir, "queries", hex.EncodeToString(sum[:])+".json")
+}
+
+func (c apiCache) recordPath(id string) string {
+	return filepath.Join(c.dir, "vulns", filepath.Base(filepath.Clean("/"+id))+".json")
+}
+
+func (c apiCache) read(path string, value any) bool {
+	if c.dir == "" {
+		return false
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal(contents, value); err != nil {
+		log.WithFields("path", path, "error", err).Debug("ignoring unreadable OSV cache entry")
+		return false
+	}
+	return true
+}
+
+func (c apiCache) write(path string, value any) {
+	if c.dir == "" {
+		return
+	}
+	contents, err := json.Marshal(value)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(path, contents, 0o600)
+	}
+	if err != nil {
+		log.WithFields("path", path, "error", err).Debug("unable to cache OSV response")
+	}
+}
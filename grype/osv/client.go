@@ -0,0 +1,132 @@
+package osv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/internal/fips"
+)
+
+const (
+	// DefaultAPIURL is the base URL of the OSV.dev API.
+	DefaultAPIURL = "https://api.osv.dev"
+
+	// the maximum number of queries within a single batch query of the OSV.dev API
+	maxBatchQueries = 1000
+)
+
+// Query is a single package version query of the OSV.dev API.
+type Query struct {
+	Package Package `json:"package"`
+	Version string  `json:"version,omitempty"`
+}
+
+// QueryResult is the vulnerabilities that affect the package version of a query. The batch API only returns the ID
+// and modification time of each vulnerability, the records are fetched separately by ID.
+type QueryResult struct {
+	Vulns []QueryVulnerability `json:"vulns,omitempty"`
+}
+
+type QueryVulnerability struct {
+	ID       string    `json:"id"`
+	Modified time.Time `json:"modified"`
+}
+
+// Client queries the OSV.dev API.
+type Client struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	return &Client{
+		client:  fips.HTTPClient(&http.Client{Timeout: timeout}),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// QueryBatch returns the vulnerabilities of each query, in the order of the queries.
+func (c *Client) QueryBatch(queries []Query) ([]QueryResult, error) {
+	var results []QueryResult
+	for start := 0; start < len(queries); start += maxBatchQueries {
+		end := start + maxBatchQueries
+		if end > len(queries) {
+			end = len(queries)
+		}
+
+		batch, err := c.queryBatch(queries[start:end])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+func (c *Client) queryBatch(queries []Query) ([]QueryResult, error) {
+	body, err := json.Marshal(struct {
+		Queries []Query `json:"queries"`
+	}{Queries: queries})
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode OSV query: %w", err)
+	}
+
+	var res struct {
+		Results []QueryResult `json:"results"`
+	}
+	if err := c.do(http.MethodPost, "/v1/querybatch", bytes.NewReader(body), &res); err != nil {
+		return nil, err
+	}
+	if len(res.Results) != len(queries) {
+		return nil, fmt.Errorf("OSV returned %d results for %d queries", len(res.Results), len(queries))
+	}
+	return res.Results, nil
+}
+
+// GetVulnerability returns the OSV record with the given ID.
+func (c *Client) GetVulnerability(id string) (*Vulnerability, error) {
+	var v Vulnerability
+	if err := c.do(http.MethodGet, "/v1/vulns/"+url.PathEscape(id), nil, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (c *Client) do(method, path string, body *bytes.Reader, result any) error {
+	var req *http.Request
+	var err error
+	if body != nil {
+		req, err = http.NewRequest(method, c.baseURL+path, body)
+	} else {
+		req, err = http.NewRequest(method, c.baseURL+path, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to initialize HTTP client: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("OSV request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %s from %s", resp.Status, req.URL.String())
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("json decode error: %w", err)
+	}
+	return nil
+}
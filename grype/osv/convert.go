@@ -0,0 +1,243 @@
+package osv
+
+import (
+	"regexp"
+	"strings"
+
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/pkg/qualifier"
+	"github.com/anchore/grype/grype/pkg/qualifier/configuration"
+	"github.com/anchore/grype/grype/severity"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+)
+
+// APINamespacePrefix prefixes the namespaces of the vulnerabilities found by the OSV.dev API, which are followed by
+// the language of the package (e.g. "osv.dev:language:python").
+const APINamespacePrefix = "osv.dev:language:"
+
+const nvdNamespace = "nvd:cpe"
+
+var pep503Separators = regexp.MustCompile(`[-_.]+`)
+
+// NormalizeName returns the name of the package the way the ecosystem compares names: PyPI names are lowercased with
+// runs of separators folded into a dash (as in PEP 503, so that "Foo_Bar" is "foo-bar"), and the names of other
+// ecosystems are lowercased.
+func NormalizeName(ecosystem, name string) string {
+	base, _, _ := strings.Cut(ecosystem, ":")
+	if strings.EqualFold(base, "PyPI") {
+		return pep503Separators.ReplaceAllString(strings.ToLower(name), "-")
+	}
+	return strings.ToLower(name)
+}
+
+// toVulnerabilities converts the affected entries of the record for the queried package into grype vulnerabilities,
+// with constraints of the given version format.
+func toVulnerabilities(r Vulnerability, q Query, namespace string, format version.Format) []vulnerability.Vulnerability {
+	var related []vulnerability.Reference
+	for _, alias := range r.Aliases {
+		if strings.HasPrefix(alias, "CVE-") {
+			related = append(related, vulnerability.Reference{ID: alias, Namespace: nvdNamespace})
+		}
+	}
+
+	var vulns []vulnerability.Vulnerability
+	for _, a := range r.Affected {
+		if NormalizeName(a.Package.Ecosystem, a.Package.Name) != NormalizeName(q.Package.Ecosystem, q.Package.Name) || !matchesEcosystem(a.Package.Ecosystem, []string{q.Package.Ecosystem}) {
+			continue
+		}
+		raw, fixes := affectedConstraint(a)
+		if raw == "" {
+			continue
+		}
+		constraint, err := version.GetConstraint(raw, format)
+		if err != nil {
+			log.WithFields("id", r.ID, "constraint", raw, "error", err).Debug("unable to parse OSV affected range")
+			continue
+		}
+
+		fix := vulnerability.Fix{State: grypeDB.NotFixedState}
+		if len(fixes) > 0 {
+			fix = vulnerability.Fix{Versions: fixes, State: grypeDB.FixedState}
+		}
+		var qualifiers []qualifier.Qualifier
+		if features := requiredFeatures(a); len(features) > 0 {
+			qualifiers = append(qualifiers, configuration.New(features))
+		}
+		vulns = append(vulns, vulnerability.Vulnerability{
+			PackageName:            a.Package.Name,
+			Constraint:             constraint,
+			ID:                     r.ID,
+			Namespace:              namespace,
+			Fix:                    fix,
+			RelatedVulnerabilities: related,
+			PackageQualifiers:      qualifiers,
+		})
+	}
+	return vulns
+}
+
+// Affects tells if the record affects the version of the package, which is of the ecosystem and has the OSV name of
+// the query (see PackageQuery).
+func (r Vulnerability) Affects(q Query, pk pkg.Package) bool {
+	format := version.FormatFromPkg(pk)
+	v, err := version.NewVersion(pk.Version, format)
+	if err != nil {
+		return false
+	}
+	for _, a := range r.Affected {
+		if NormalizeName(a.Package.Ecosystem, a.Package.Name) != NormalizeName(q.Package.Ecosystem, q.Package.Name) || !matchesEcosystem(a.Package.Ecosystem, []string{q.Package.Ecosystem}) {
+			continue
+		}
+		raw, _ := affectedConstraint(a)
+		if raw == "" {
+			continue
+		}
+		constraint, err := version.GetConstraint(raw, format)
+		if err != nil {
+			log.WithFields("id", r.ID, "constraint", raw, "error", err).Debug("unable to parse OSV affected range")
+			continue
+		}
+		if satisfied, err := constraint.Satisfied(v); err == nil && satisfied {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredFeatures returns the optional features (or configurations) of the package of which one must be enabled for
+// the package to be affected, from the "features" or "configurations" list of the ecosystem-specific data of the
+// affected entry (e.g. {"features": ["mod_lua"]}).
+func requiredFeatures(a Affected) []string {
+	var features []string
+	for _, key := range []string{"features", "configurations"} {
+		values, _ := a.EcosystemSpecific[key].([]any)
+		for _, v := range values {
+			if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+				features = append(features, strings.TrimSpace(s))
+			}
+		}
+	}
+	return features
+}
+
+// affectedConstraint returns the grype constraint of the SEMVER and ECOSYSTEM ranges of the affected entry (or of its
+// affected versions when it has no such ranges), along with the fixed versions.
+func affectedConstraint(a Affected) (string, []string) {
+	var clauses, fixes []string
+	for _, r := range a.Ranges {
+		if r.Type != RangeSemver && r.Type != RangeEcosystem {
+			continue
+		}
+		c, f := eventConstraints(r.Events)
+		clauses = append(clauses, c...)
+		fixes = append(fixes, f...)
+	}
+	if len(clauses) == 0 {
+		for _, v := range a.Versions {
+			clauses = append(clauses, "= "+v)
+		}
+	}
+	return strings.Join(clauses, " || "), fixes
+}
+
+// eventConstraints converts the events of a range into one clause per affected interval, e.g. introduced 1.0 and
+// fixed 1.2 is ">= 1.0, < 1.2". An introduced version of "0" is the start of all versions.
+func eventConstraints(events []Event) ([]string, []string) {
+	var clauses, fixes []string
+	var lower string
+	open := false
+	closeRange := func(upper string) {
+		var parts []string
+		if lower != "" && lower != "0" {
+			parts = append(parts, ">= "+lower)
+		}
+		if upper != "" {
+			parts = append(parts, upper)
+		}
+		if len(parts) == 0 {
+			parts = append(parts, ">= 0")
+		}
+		clauses = append(clauses, strings.Join(parts, ", "))
+		lower, open = "", false
+	}
+
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			// overlapping introductions are covered by the earliest
+			if !open {
+				lower, open = e.Introduced, true
+			}
+		case e.Fixed != "":
+			fixes = append(fixes, e.Fixed)
+			closeRange("< " + e.Fixed)
+		case e.LastAffected != "":
+			closeRange("<= " + e.LastAffected)
+		case e.Limit != "":
+			closeRange("< " + e.Limit)
+		}
+	}
+	if open {
+		closeRange("")
+	}
+	return clauses, fixes
+}
+
+// toMetadata describes the record with the CVSS vectors and the (GitHub) severity of the record.
+func toMetadata(r Vulnerability, namespace string) *vulnerability.Metadata {
+	m := &vulnerability.Metadata{
+		ID:          r.ID,
+		DataSource:  "https://osv.dev/vulnerability/" + r.ID,
+		Namespace:   namespace,
+		Severity:    vulnerability.UnknownSeverity.Name(),
+		Description: r.Summary,
+		Published:   r.Published,
+	}
+	if !r.Modified.IsZero() {
+		modified := r.Modified
+		m.Modified = &modified
+	}
+	if m.Description == "" {
+		m.Description = r.Details
+	}
+	for _, ref := range r.References {
+		m.URLs = append(m.URLs, ref.URL)
+	}
+
+	var highest float64
+	for _, s := range r.Severity {
+		if s.Type != "CVSS_V3" {
+			continue
+		}
+		cvss := vulnerability.Cvss{
+			Source: "osv.dev",
+			Type:   "Primary",
+			Vector: s.Score,
+		}
+		if v, _, ok := strings.Cut(strings.TrimPrefix(s.Score, "CVSS:"), "/"); ok {
+			cvss.Version = v
+		}
+		if score, err := severity.AdjustedScore(s.Score, nil); err == nil {
+			cvss.Metrics.BaseScore = score
+			if score > highest {
+				highest = score
+				m.Severity = severity.Rating(score).Name()
+			}
+		}
+		m.Cvss = append(m.Cvss, cvss)
+	}
+
+	if highest == 0 {
+		if s, ok := r.DatabaseSpecific["severity"].(string); ok {
+			// GitHub rates as "moderate" what CVSS rates as "medium"
+			if strings.EqualFold(s, "moderate") {
+				s = vulnerability.MediumSeverity.String()
+			}
+			m.Severity = vulnerability.ParseSeverity(s).Name()
+		}
+	}
+	return m
+}
@@ -0,0 +1,361 @@
+package osv
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/namespace"
+	distroNs "github.com/anchore/grype/grype/db/v5/namespace/distro"
+	languageNs "github.com/anchore/grype/grype/db/v5/namespace/language"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/grype/internal/stringutil"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+var languageEcosystems = map[syftPkg.Language]string{
+	syftPkg.Dart:       "Pub",
+	syftPkg.Dotnet:     "NuGet",
+	syftPkg.Elixir:     "Hex",
+	syftPkg.Erlang:     "Hex",
+	syftPkg.Go:         "Go",
+	syftPkg.Haskell:    "Hackage",
+	syftPkg.Java:       "Maven",
+	syftPkg.JavaScript: "npm",
+	syftPkg.PHP:        "Packagist",
+	syftPkg.Python:     "PyPI",
+	syftPkg.R:          "CRAN",
+	syftPkg.Ruby:       "RubyGems",
+	syftPkg.Rust:       "crates.io",
+	syftPkg.Swift:      "SwiftURL",
+}
+
+var comparisonPattern = regexp.MustCompile(`^(>=|<=|>|<|==|=|!=)?\s*(\S+)$`)
+
+// Ecosystem returns the OSV ecosystem for a DB namespace, if the namespace can be represented in OSV.
+func Ecosystem(ns string) (string, bool) {
+	if !strings.Contains(ns, ":") {
+		return "", false
+	}
+	parsed, err := namespace.FromString(ns)
+	if err != nil {
+		return "", false
+	}
+
+	switch n := parsed.(type) {
+	case *languageNs.Namespace:
+		e, ok := languageEcosystems[n.Language()]
+		return e, ok
+	case *distroNs.Namespace:
+		return distroEcosystem(n.DistroType(), n.Version())
+	}
+	return "", false
+}
+
+func distroEcosystem(t distro.Type, ver string) (string, bool) {
+	switch t {
+	case distro.Debian:
+		return "Debian:" + ver, true
+	case distro.Ubuntu:
+		return "Ubuntu:" + ver, true
+	case distro.Alpine:
+		if ver == "edge" {
+			return "", false
+		}
+		return "Alpine:v" + ver, true
+	case distro.AlmaLinux:
+		return "AlmaLinux:" + ver, true
+	case distro.RockyLinux:
+		return "Rocky Linux:" + ver, true
+	case distro.Photon:
+		return "Photon OS:" + ver, true
+	case distro.Wolfi:
+		return "Wolfi", true
+	case distro.Chainguard:
+		return "Chainguard", true
+	}
+	return "", false
+}
+
+// matchesEcosystem indicates if the ecosystem was selected. Selections are case-insensitive and a selection without
+// a release (e.g. "Debian") selects every release of the ecosystem (e.g. "Debian:12").
+func matchesEcosystem(ecosystem string, selected []string) bool {
+	if len(selected) == 0 {
+		return true
+	}
+	base, _, _ := strings.Cut(ecosystem, ":")
+	for _, s := range selected {
+		if strings.EqualFold(s, ecosystem) || strings.EqualFold(s, base) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromDB converts the records within the DB to one OSV record per vulnerability ID, with an affected entry per
+// package. Only the records within the given ecosystems are converted (all ecosystems when none are given); records
+// in namespaces that cannot be represented in OSV (e.g. CPE based records) are skipped.
+func FromDB(reader v5.StoreReader, ecosystems ...string) ([]Vulnerability, error) {
+	id, err := reader.GetID()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read DB ID: %w", err)
+	}
+	modified := time.Now().UTC()
+	if id != nil {
+		modified = id.BuildTimestamp.UTC()
+	}
+
+	all, err := reader.GetAllVulnerabilities()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read DB vulnerabilities: %w", err)
+	}
+
+	// the ecosystem of each namespace, or empty when the namespace is not selected
+	selected := make(map[string]string)
+	byID := make(map[string]*Vulnerability)
+	for _, v := range *all {
+		ecosystem, ok := selected[v.Namespace]
+		if !ok {
+			if e, ok := Ecosystem(v.Namespace); ok && matchesEcosystem(e, ecosystems) {
+				ecosystem = e
+			}
+			selected[v.Namespace] = ecosystem
+		}
+		if ecosystem == "" {
+			continue
+		}
+
+		record, ok := byID[v.ID]
+		if !ok {
+			record = &Vulnerability{
+				SchemaVersion: SchemaVersion,
+				ID:            v.ID,
+				Modified:      modified,
+			}
+			byID[v.ID] = record
+
+			metadata, err := reader.GetVulnerabilityMetadata(v.ID, v.Namespace)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read metadata for %s: %w", v.ID, err)
+			}
+			applyMetadata(record, metadata)
+		}
+
+		record.Aliases = stringutil.AppendUnique(record.Aliases, aliases(v)...)
+		for _, a := range v.Advisories {
+			record.References = appendReference(record.References, "ADVISORY", a.Link)
+		}
+		record.Affected = append(record.Affected, affected(v, ecosystem))
+	}
+
+	var records []Vulnerability
+	for _, r := range byID {
+		sort.Strings(r.Aliases)
+		sort.SliceStable(r.Affected, func(i, j int) bool {
+			if r.Affected[i].Package.Ecosystem == r.Affected[j].Package.Ecosystem {
+				return r.Affected[i].Package.Name < r.Affected[j].Package.Name
+			}
+			return r.Affected[i].Package.Ecosystem < r.Affected[j].Package.Ecosystem
+		})
+		records = append(records, *r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ID < records[j].ID
+	})
+	return records, nil
+}
+
+func aliases(v v5.Vulnerability) []string {
+	var ids []string
+	for _, r := range v.RelatedVulnerabilities {
+		if r.ID != v.ID {
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids
+}
+
+func applyMetadata(record *Vulnerability, metadata *v5.VulnerabilityMetadata) {
+	if metadata == nil {
+		return
+	}
+
+	record.Details = metadata.Description
+	record.Published = metadata.Published
+	if metadata.Modified != nil {
+		record.Modified = *metadata.Modified
+	}
+	if metadata.Severity != "" {
+		record.DatabaseSpecific = map[string]any{"severity": metadata.Severity}
+	}
+
+	for _, c := range metadata.Cvss {
+		if t := cvssType(c); t != "" && c.Vector != "" {
+			record.Severity = append(record.Severity, Severity{Type: t, Score: c.Vector})
+		}
+	}
+
+	if metadata.DataSource != "" {
+		record.References = appendReference(record.References, "ADVISORY", metadata.DataSource)
+	}
+	for _, u := range metadata.URLs {
+		record.References = appendReference(record.References, "WEB", u)
+	}
+}
+
+func cvssType(c v5.Cvss) string {
+	switch {
+	case strings.HasPrefix(c.Vector, "CVSS:4"), strings.HasPrefix(c.Version, "4"):
+		return "CVSS_V4"
+	case strings.HasPrefix(c.Vector, "CVSS:3"), strings.HasPrefix(c.Version, "3"):
+		return "CVSS_V3"
+	case strings.HasPrefix(c.Version, "2"):
+		return "CVSS_V2"
+	}
+	return ""
+}
+
+func appendReference(refs []Reference, refType, url string) []Reference {
+	if url == "" {
+		return refs
+	}
+	for _, r := range refs {
+		if r.URL == url {
+			return refs
+		}
+	}
+	return append(refs, Reference{Type: refType, URL: url})
+}
+
+// affected describes the package and version range of a single DB record. The original constraint is always kept in
+// the database_specific section since not every grype constraint can be expressed with OSV events (e.g. "> 1.0").
+func affected(v v5.Vulnerability, ecosystem string) Affected {
+	a := Affected{
+		Package: Package{
+			Ecosystem: ecosystem,
+			Name:      v.PackageName,
+		},
+		DatabaseSpecific: map[string]any{
+			"namespace":  v.Namespace,
+			"constraint": v.VersionConstraint,
+			"fix_state":  string(v.Fix.State),
+		},
+	}
+
+	format := version.ParseFormat(v.VersionFormat)
+	rangeType := RangeEcosystem
+	switch format {
+	case version.SemanticFormat, version.GolangFormat:
+		rangeType = RangeSemver
+	case version.GitFormat:
+		rangeType = RangeGit
+	}
+
+	events, versions, ok := constraintEvents(v.VersionConstraint, format)
+	if !ok {
+		log.WithFields("id", v.ID, "namespace", v.Namespace, "constraint", v.VersionConstraint).Debug("constraint cannot be fully represented as OSV events")
+	}
+	if len(events) > 0 {
+		a.Ranges = []Range{{Type: rangeType, Events: events}}
+	}
+	a.Versions = versions
+	return a
+}
+
+// constraintEvents converts a grype constraint (e.g. ">= 1.0, < 1.2 || >= 2.0, < 2.1") into OSV events and exact
+// versions. Clauses that cannot be represented are skipped, which is indicated by the returned bool.
+func constraintEvents(constraint string, format version.Format) ([]Event, []string, bool) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		// an empty constraint means every version is affected
+		return []Event{{Introduced: "0"}}, nil, true
+	}
+
+	// OSV ranges cannot be grouped, so express the constraint as or'd clauses of and'ed comparisons
+	flattened, err := version.FlattenConstraint(constraint)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	var events []Event
+	var versions []string
+	complete := true
+	for _, clause := range strings.Split(flattened, "||") {
+		introduced, upper := "0", Event{}
+		valid := true
+		for _, part := range strings.Split(clause, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			m := comparisonPattern.FindStringSubmatch(part)
+			if m == nil {
+				valid = false
+				break
+			}
+			ver := osvVersion(m[2], format)
+			switch m[1] {
+			case ">=":
+				introduced = ver
+			case "<":
+				upper = Event{Fixed: ver}
+			case "<=":
+				upper = Event{LastAffected: ver}
+			case "", "=", "==":
+				versions = append(versions, ver)
+				valid = false
+			default:
+				// ">" and "!=" have no OSV equivalent
+				valid = false
+				complete = false
+			}
+		}
+		if !valid {
+			continue
+		}
+		events = append(events, Event{Introduced: introduced})
+		if upper != (Event{}) {
+			events = append(events, upper)
+		}
+	}
+	return events, versions, complete
+}
+
+// osvVersion adjusts a version to the form expected by OSV for the range type (SEMVER versions have no "v" prefix).
+func osvVersion(ver string, format version.Format) string {
+	if format == version.GolangFormat || format == version.SemanticFormat {
+		return strings.TrimPrefix(ver, "v")
+	}
+	return ver
+}
+
+// WriteDir writes each record to "<id>.json" within the directory, creating the directory when needed.
+func WriteDir(dir string, records []Vulnerability) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create OSV output directory: %w", err)
+	}
+
+	for _, r := range records {
+		contents, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to encode OSV record %s: %w", r.ID, err)
+		}
+		path := filepath.Join(dir, fileName(r.ID))
+		if err := os.WriteFile(path, append(contents, '\n'), 0o644); err != nil { //nolint:gosec // exported advisories are public data
+			return fmt.Errorf("unable to write OSV record %s: %w", r.ID, err)
+		}
+	}
+	return nil
+}
+
+func fileName(id string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(id) + ".json"
+}
@@ -0,0 +1,253 @@
+package osv
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/store"
+	"github.com/anchore/grype/grype/version"
+)
+
+func newTestStore(t *testing.T) v5.Store {
+	t.Helper()
+	s, err := store.New(filepath.Join(t.TempDir(), "vulnerability.db"), true)
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+
+	require.NoError(t, s.SetID(v5.NewID(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))))
+	require.NoError(t, s.AddVulnerability(
+		v5.Vulnerability{
+			ID:                "GHSA-p6mc-m468-83gw",
+			PackageName:       "lodash",
+			Namespace:         "github:language:javascript",
+			VersionConstraint: ">= 4.0.0, < 4.17.21",
+			VersionFormat:     "semver",
+			RelatedVulnerabilities: []v5.VulnerabilityReference{
+				{ID: "CVE-2020-8203", Namespace: "nvd:cpe"},
+			},
+			Fix: v5.Fix{Versions: []string{"4.17.21"}, State: v5.FixedState},
+		},
+		v5.Vulnerability{
+			ID:                "GHSA-p6mc-m468-83gw",
+			PackageName:       "lodash-es",
+			Namespace:         "github:language:javascript",
+			VersionConstraint: "< 4.17.21",
+			VersionFormat:     "semver",
+			Fix:               v5.Fix{Versions: []string{"4.17.21"}, State: v5.FixedState},
+		},
+		v5.Vulnerability{
+			ID:                "GHSA-xxxx-python",
+			PackageName:       "requests",
+			Namespace:         "github:language:python",
+			VersionConstraint: "<=2.31.0",
+			VersionFormat:     "python",
+			Fix:               v5.Fix{State: v5.NotFixedState},
+		},
+		v5.Vulnerability{
+			ID:                "CVE-2020-8203",
+			PackageName:       "lodash",
+			Namespace:         "nvd:cpe",
+			VersionConstraint: "< 4.17.21",
+			VersionFormat:     "unknown",
+		},
+	))
+	require.NoError(t, s.AddVulnerabilityMetadata(v5.VulnerabilityMetadata{
+		ID:          "GHSA-p6mc-m468-83gw",
+		Namespace:   "github:language:javascript",
+		DataSource:  "https://github.com/advisories/GHSA-p6mc-m468-83gw",
+		Severity:    "High",
+		URLs:        []string{"https://github.com/advisories/GHSA-p6mc-m468-83gw", "https://nvd.nist.gov/vuln/detail/CVE-2020-8203"},
+		Description: "Prototype Pollution in lodash",
+		Cvss: []v5.Cvss{
+			{Version: "3.1", Vector: "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:H/A:H"},
+		},
+	}))
+	return s
+}
+
+func TestFromDB(t *testing.T) {
+	records, err := FromDB(newTestStore(t), "npm")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	expected := Vulnerability{
+		SchemaVersion: SchemaVersion,
+		ID:            "GHSA-p6mc-m468-83gw",
+		Modified:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Aliases:       []string{"CVE-2020-8203"},
+		Details:       "Prototype Pollution in lodash",
+		Severity: []Severity{
+			{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:H/A:H"},
+		},
+		Affected: []Affected{
+			{
+				Package: Package{Ecosystem: "npm", Name: "lodash"},
+				Ranges: []Range{{Type: RangeSemver, Events: []Event{
+					{Introduced: "4.0.0"},
+					{Fixed: "4.17.21"},
+				}}},
+				DatabaseSpecific: map[string]any{
+					"namespace":  "github:language:javascript",
+					"constraint": ">= 4.0.0, < 4.17.21",
+					"fix_state":  "fixed",
+				},
+			},
+			{
+				Package: Package{Ecosystem: "npm", Name: "lodash-es"},
+				Ranges: []Range{{Type: RangeSemver, Events: []Event{
+					{Introduced: "0"},
+					{Fixed: "4.17.21"},
+				}}},
+				DatabaseSpecific: map[string]any{
+					"namespace":  "github:language:javascript",
+					"constraint": "< 4.17.21",
+					"fix_state":  "fixed",
+				},
+			},
+		},
+		References: []Reference{
+			{Type: "ADVISORY", URL: "https://github.com/advisories/GHSA-p6mc-m468-83gw"},
+			{Type: "WEB", URL: "https://nvd.nist.gov/vuln/detail/CVE-2020-8203"},
+		},
+		DatabaseSpecific: map[string]any{"severity": "High"},
+	}
+	assert.Equal(t, expected, records[0])
+}
+
+func TestFromDB_allEcosystems(t *testing.T) {
+	records, err := FromDB(newTestStore(t))
+	require.NoError(t, err)
+
+	var ids []string
+	for _, r := range records {
+		ids = append(ids, r.ID)
+	}
+	// CPE records cannot be represented in OSV
+	assert.Equal(t, []string{"GHSA-p6mc-m468-83gw", "GHSA-xxxx-python"}, ids)
+
+	python := records[1].Affected[0]
+	assert.Equal(t, "PyPI", python.Package.Ecosystem)
+	assert.Equal(t, []Range{{Type: RangeEcosystem, Events: []Event{{Introduced: "0"}, {LastAffected: "2.31.0"}}}}, python.Ranges)
+}
+
+func TestEcosystem(t *testing.T) {
+	tests := []struct {
+		namespace string
+		want      string
+		ok        bool
+	}{
+		{namespace: "github:language:javascript", want: "npm", ok: true},
+		{namespace: "github:language:go", want: "Go", ok: true},
+		{namespace: "debian:distro:debian:12", want: "Debian:12", ok: true},
+		{namespace: "alpine:distro:alpine:3.18", want: "Alpine:v3.18", ok: true},
+		{namespace: "wolfi:distro:wolfi:rolling", want: "Wolfi", ok: true},
+		{namespace: "nvd:cpe", ok: false},
+		{namespace: "msrc:distro:windows:10816", ok: false},
+		{namespace: "garbage", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			got, ok := Ecosystem(tt.namespace)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_matchesEcosystem(t *testing.T) {
+	assert.True(t, matchesEcosystem("Debian:12", nil))
+	assert.True(t, matchesEcosystem("Debian:12", []string{"debian"}))
+	assert.True(t, matchesEcosystem("Debian:12", []string{"Debian:12"}))
+	assert.False(t, matchesEcosystem("Debian:12", []string{"Debian:11"}))
+	assert.True(t, matchesEcosystem("npm", []string{"PyPI", "NPM"}))
+}
+
+func Test_constraintEvents(t *testing.T) {
+	tests := []struct {
+		constraint string
+		format     version.Format
+		events     []Event
+		versions   []string
+		complete   bool
+	}{
+		{
+			constraint: "",
+			events:     []Event{{Introduced: "0"}},
+			complete:   true,
+		},
+		{
+			constraint: ">= 1.0.0, < 1.2.0 || >= 2.0.0, < 2.1.0",
+			format:     version.SemanticFormat,
+			events:     []Event{{Introduced: "1.0.0"}, {Fixed: "1.2.0"}, {Introduced: "2.0.0"}, {Fixed: "2.1.0"}},
+			complete:   true,
+		},
+		{
+			constraint: ">= v0.5.0, < v0.7.1",
+			format:     version.GolangFormat,
+			events:     []Event{{Introduced: "0.5.0"}, {Fixed: "0.7.1"}},
+			complete:   true,
+		},
+		{
+			constraint: ">= 2.0",
+			format:     version.UnknownFormat,
+			events:     []Event{{Introduced: "2.0"}},
+			complete:   true,
+		},
+		{
+			constraint: "= 1.2.3 || = 1.2.4",
+			format:     version.UnknownFormat,
+			versions:   []string{"1.2.3", "1.2.4"},
+			complete:   true,
+		},
+		{
+			constraint: "(>= 1.0.0 || >= 2.0.0-rc1), < 2.1.0",
+			format:     version.SemanticFormat,
+			events:     []Event{{Introduced: "1.0.0"}, {Fixed: "2.1.0"}, {Introduced: "2.0.0-rc1"}, {Fixed: "2.1.0"}},
+			complete:   true,
+		},
+		{
+			constraint: ">= 0, < daa7c04131f5 || >= 16171245cfb2, <= 5d6e7f8a9b0c",
+			format:     version.GitFormat,
+			events:     []Event{{Introduced: "0"}, {Fixed: "daa7c04131f5"}, {Introduced: "16171245cfb2"}, {LastAffected: "5d6e7f8a9b0c"}},
+			complete:   true,
+		},
+		{
+			constraint: "> 1.0, < 2.0 || < 0.5",
+			format:     version.UnknownFormat,
+			events:     []Event{{Introduced: "0"}, {Fixed: "0.5"}},
+			complete:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			events, versions, complete := constraintEvents(tt.constraint, tt.format)
+			assert.Equal(t, tt.events, events)
+			assert.Equal(t, tt.versions, versions)
+			assert.Equal(t, tt.complete, complete)
+		})
+	}
+}
+
+func TestWriteDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	records := []Vulnerability{
+		{SchemaVersion: SchemaVersion, ID: "GHSA-1", Modified: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{SchemaVersion: SchemaVersion, ID: "ALSA-2024:1", Modified: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	require.NoError(t, WriteDir(dir, records))
+
+	contents, err := os.ReadFile(filepath.Join(dir, "ALSA-2024_1.json"))
+	require.NoError(t, err)
+
+	var got Vulnerability
+	require.NoError(t, json.Unmarshal(contents, &got))
+	assert.Equal(t, records[1], got)
+	assert.FileExists(t, filepath.Join(dir, "GHSA-1.json"))
+}
@@ -0,0 +1,233 @@
+package osv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/db/v5/namespace"
+	distroNs "github.com/anchore/grype/grype/db/v5/namespace/distro"
+	languageNs "github.com/anchore/grype/grype/db/v5/namespace/language"
+	dbQualifier "github.com/anchore/grype/grype/db/v5/pkg/qualifier"
+	dbConfiguration "github.com/anchore/grype/grype/db/v5/pkg/qualifier/configuration"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// ImportProvider is the provider of the namespaces of the language records converted from OSV records (e.g.
+// "osv:language:python").
+const ImportProvider = "osv"
+
+var ecosystemLanguages = map[string]syftPkg.Language{
+	"cran":      syftPkg.R,
+	"crates.io": syftPkg.Rust,
+	"go":        syftPkg.Go,
+	"hackage":   syftPkg.Haskell,
+	"hex":       syftPkg.Elixir,
+	"maven":     syftPkg.Java,
+	"npm":       syftPkg.JavaScript,
+	"nuget":     syftPkg.Dotnet,
+	"packagist": syftPkg.PHP,
+	"pub":       syftPkg.Dart,
+	"pypi":      syftPkg.Python,
+	"rubygems":  syftPkg.Ruby,
+	"swifturl":  syftPkg.Swift,
+}
+
+var languageFormats = map[syftPkg.Language]version.Format{
+	syftPkg.Go:     version.GolangFormat,
+	syftPkg.Java:   version.MavenFormat,
+	syftPkg.Python: version.PythonFormat,
+	syftPkg.Ruby:   version.GemFormat,
+}
+
+var distroEcosystems = map[string]distro.Type{
+	"almalinux":   distro.AlmaLinux,
+	"alpine":      distro.Alpine,
+	"chainguard":  distro.Chainguard,
+	"debian":      distro.Debian,
+	"photon os":   distro.Photon,
+	"rocky linux": distro.RockyLinux,
+	"ubuntu":      distro.Ubuntu,
+	"wolfi":       distro.Wolfi,
+}
+
+// ToDB converts OSV records into DB records, the reverse of FromDB: a vulnerability record per affected package (in
+// the namespace of the ecosystem of the package) and a metadata record per vulnerability and namespace. Records
+// exported by FromDB keep their original namespace and constraint. Withdrawn records, and affected packages of
+// ecosystems without a DB namespace or without a version range, are skipped.
+func ToDB(records []Vulnerability) ([]v5.Vulnerability, []v5.VulnerabilityMetadata) {
+	var vulns []v5.Vulnerability
+	var metadata []v5.VulnerabilityMetadata
+	for _, r := range records {
+		if r.Withdrawn != nil {
+			continue
+		}
+
+		var related []v5.VulnerabilityReference
+		for _, alias := range r.Aliases {
+			if strings.HasPrefix(alias, "CVE-") && alias != r.ID {
+				related = append(related, v5.VulnerabilityReference{ID: alias, Namespace: nvdNamespace})
+			}
+		}
+
+		namespaces := make(map[string]bool)
+		for _, a := range r.Affected {
+			v, ok := dbVulnerability(r, a)
+			if !ok {
+				continue
+			}
+			v.RelatedVulnerabilities = related
+			vulns = append(vulns, v)
+			if !namespaces[v.Namespace] {
+				namespaces[v.Namespace] = true
+				metadata = append(metadata, dbMetadata(toMetadata(r, v.Namespace)))
+			}
+		}
+	}
+
+	sort.SliceStable(vulns, func(i, j int) bool {
+		if vulns[i].Namespace != vulns[j].Namespace {
+			return vulns[i].Namespace < vulns[j].Namespace
+		}
+		if vulns[i].PackageName != vulns[j].PackageName {
+			return vulns[i].PackageName < vulns[j].PackageName
+		}
+		return vulns[i].ID < vulns[j].ID
+	})
+	return vulns, metadata
+}
+
+func dbVulnerability(r Vulnerability, a Affected) (v5.Vulnerability, bool) {
+	namespace, _ := a.DatabaseSpecific["namespace"].(string)
+	if namespace == "" {
+		var ok bool
+		if namespace, ok = dbNamespace(a.Package.Ecosystem); !ok {
+			log.WithFields("id", r.ID, "ecosystem", a.Package.Ecosystem).Debug("no DB namespace for the OSV ecosystem, skipping affected package")
+			return v5.Vulnerability{}, false
+		}
+	}
+
+	constraint, hasConstraint := a.DatabaseSpecific["constraint"].(string)
+	raw, fixes := affectedConstraint(a)
+	if !hasConstraint {
+		if raw == "" {
+			log.WithFields("id", r.ID, "package", a.Package.Name).Debug("no version range for the OSV affected package, skipping affected package")
+			return v5.Vulnerability{}, false
+		}
+		constraint = raw
+	}
+
+	format := NamespaceFormat(namespace)
+	if format == version.UnknownFormat && rangeTypes(a)[RangeSemver] {
+		format = version.SemanticFormat
+	}
+
+	fix := v5.Fix{State: v5.NotFixedState}
+	if state, ok := a.DatabaseSpecific["fix_state"].(string); ok && state != "" {
+		fix.State = v5.FixState(state)
+	}
+	if len(fixes) > 0 {
+		fix = v5.Fix{Versions: fixes, State: v5.FixedState}
+	}
+
+	var qualifiers []dbQualifier.Qualifier
+	if features := requiredFeatures(a); len(features) > 0 {
+		qualifiers = append(qualifiers, dbConfiguration.Qualifier{Kind: "configuration", Features: features})
+	}
+
+	return v5.Vulnerability{
+		ID:                r.ID,
+		PackageName:       a.Package.Name,
+		Namespace:         namespace,
+		VersionConstraint: constraint,
+		VersionFormat:     strings.ToLower(format.String()),
+		Fix:               fix,
+		PackageQualifiers: qualifiers,
+	}, true
+}
+
+// dbNamespace returns the DB namespace of the OSV ecosystem (e.g. "PyPI" is "osv:language:python" and "Debian:12" is
+// "debian:distro:debian:12").
+func dbNamespace(ecosystem string) (string, bool) {
+	name, release, _ := strings.Cut(ecosystem, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if l, ok := ecosystemLanguages[name]; ok {
+		return fmt.Sprintf("%s:language:%s", ImportProvider, l), true
+	}
+
+	t, ok := distroEcosystems[name]
+	if !ok {
+		return "", false
+	}
+	// releases may carry a qualifier (e.g. "Ubuntu:22.04:LTS"), and alpine releases are prefixed (e.g. "Alpine:v3.18")
+	release, _, _ = strings.Cut(release, ":")
+	release = strings.TrimPrefix(release, "v")
+	if t == distro.Wolfi || t == distro.Chainguard {
+		release = "rolling"
+	}
+	if release == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%s:distro:%s:%s", t, t, release), true
+}
+
+// NamespaceFormat returns the version format of the packages of the DB namespace, unknown when the format depends on
+// the package rather than on the namespace.
+func NamespaceFormat(ns string) version.Format {
+	parsed, err := namespace.FromString(ns)
+	if err != nil {
+		return version.UnknownFormat
+	}
+	switch n := parsed.(type) {
+	case *languageNs.Namespace:
+		return languageFormats[n.Language()]
+	case *distroNs.Namespace:
+		switch n.DistroType() {
+		case distro.Debian, distro.Ubuntu:
+			return version.DebFormat
+		case distro.Alpine, distro.Wolfi, distro.Chainguard:
+			return version.ApkFormat
+		case distro.AlmaLinux, distro.RockyLinux, distro.Photon, distro.RedHat, distro.CentOS, distro.Fedora,
+			distro.AmazonLinux, distro.OracleLinux, distro.SLES, distro.OpenSuseLeap, distro.Mariner, distro.Azure:
+			return version.RpmFormat
+		}
+	}
+	return version.UnknownFormat
+}
+
+func rangeTypes(a Affected) map[string]bool {
+	types := make(map[string]bool)
+	for _, r := range a.Ranges {
+		types[r.Type] = true
+	}
+	return types
+}
+
+func dbMetadata(m *vulnerability.Metadata) v5.VulnerabilityMetadata {
+	metadata := v5.VulnerabilityMetadata{
+		ID:          m.ID,
+		Namespace:   m.Namespace,
+		DataSource:  m.DataSource,
+		Severity:    m.Severity,
+		URLs:        m.URLs,
+		Description: m.Description,
+		Published:   m.Published,
+		Modified:    m.Modified,
+	}
+	for _, c := range m.Cvss {
+		metadata.Cvss = append(metadata.Cvss, v5.Cvss{
+			Metrics: v5.CvssMetrics{BaseScore: c.Metrics.BaseScore},
+			Vector:  c.Vector,
+			Version: c.Version,
+			Source:  c.Source,
+			Type:    c.Type,
+		})
+	}
+	return metadata
+}
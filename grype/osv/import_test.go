@@ -0,0 +1,161 @@
+package osv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/db/v5"
+	dbQualifier "github.com/anchore/grype/grype/db/v5/pkg/qualifier"
+	dbConfiguration "github.com/anchore/grype/grype/db/v5/pkg/qualifier/configuration"
+)
+
+func TestToDB(t *testing.T) {
+	withdrawn := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	records := []Vulnerability{
+		{
+			ID:       "INTERNAL-2024-0001",
+			Aliases:  []string{"CVE-2024-0001"},
+			Summary:  "Remote code execution in internal-lib",
+			Severity: []Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}},
+			Affected: []Affected{
+				{
+					Package: Package{Ecosystem: "PyPI", Name: "internal-lib"},
+					Ranges:  []Range{{Type: RangeEcosystem, Events: []Event{{Introduced: "1.0"}, {Fixed: "1.4.2"}}}},
+				},
+				{
+					Package: Package{Ecosystem: "Debian:12", Name: "internal-lib"},
+					Ranges:  []Range{{Type: RangeEcosystem, Events: []Event{{Introduced: "0"}, {LastAffected: "1.4.1-1"}}}},
+				},
+				{
+					// an ecosystem without a DB namespace
+					Package: Package{Ecosystem: "Bitnami", Name: "internal-lib"},
+					Ranges:  []Range{{Type: RangeEcosystem, Events: []Event{{Introduced: "0"}}}},
+				},
+				{
+					// no version range
+					Package: Package{Ecosystem: "npm", Name: "internal-lib"},
+				},
+			},
+		},
+		{
+			ID:        "INTERNAL-2024-0002",
+			Withdrawn: &withdrawn,
+			Affected: []Affected{
+				{
+					Package: Package{Ecosystem: "PyPI", Name: "internal-lib"},
+					Ranges:  []Range{{Type: RangeEcosystem, Events: []Event{{Introduced: "0"}}}},
+				},
+			},
+		},
+	}
+
+	vulns, metadata := ToDB(records)
+
+	related := []v5.VulnerabilityReference{{ID: "CVE-2024-0001", Namespace: "nvd:cpe"}}
+	assert.Equal(t, []v5.Vulnerability{
+		{
+			ID:                     "INTERNAL-2024-0001",
+			PackageName:            "internal-lib",
+			Namespace:              "debian:distro:debian:12",
+			VersionConstraint:      "<= 1.4.1-1",
+			VersionFormat:          "deb",
+			RelatedVulnerabilities: related,
+			Fix:                    v5.Fix{State: v5.NotFixedState},
+		},
+		{
+			ID:                     "INTERNAL-2024-0001",
+			PackageName:            "internal-lib",
+			Namespace:              "osv:language:python",
+			VersionConstraint:      ">= 1.0, < 1.4.2",
+			VersionFormat:          "python",
+			RelatedVulnerabilities: related,
+			Fix:                    v5.Fix{Versions: []string{"1.4.2"}, State: v5.FixedState},
+		},
+	}, vulns)
+
+	require.Len(t, metadata, 2)
+	assert.Equal(t, "osv:language:python", metadata[0].Namespace)
+	assert.Equal(t, "debian:distro:debian:12", metadata[1].Namespace)
+	assert.Equal(t, "Critical", metadata[0].Severity)
+	assert.Equal(t, "Remote code execution in internal-lib", metadata[0].Description)
+	require.Len(t, metadata[0].Cvss, 1)
+	assert.Equal(t, 9.8, metadata[0].Cvss[0].Metrics.BaseScore)
+}
+
+func TestToDB_features(t *testing.T) {
+	records := []Vulnerability{
+		{
+			ID: "INTERNAL-2024-0003",
+			Affected: []Affected{
+				{
+					Package:           Package{Ecosystem: "Debian:12", Name: "apache2"},
+					Ranges:            []Range{{Type: RangeEcosystem, Events: []Event{{Introduced: "0"}, {Fixed: "2.4.59-1"}}}},
+					EcosystemSpecific: map[string]any{"features": []any{"mod_lua"}, "configurations": []any{"LuaScope", 3}},
+				},
+			},
+		},
+	}
+
+	vulns, _ := ToDB(records)
+	require.Len(t, vulns, 1)
+	assert.Equal(t, []dbQualifier.Qualifier{
+		dbConfiguration.Qualifier{Kind: "configuration", Features: []string{"mod_lua", "LuaScope"}},
+	}, vulns[0].PackageQualifiers)
+}
+
+func TestToDB_roundTrip(t *testing.T) {
+	s := newTestStore(t)
+	records, err := FromDB(s)
+	require.NoError(t, err)
+
+	vulns, _ := ToDB(records)
+
+	expected, err := s.GetVulnerability("github:language:javascript", "GHSA-p6mc-m468-83gw")
+	require.NoError(t, err)
+	var roundTripped []v5.Vulnerability
+	for _, v := range vulns {
+		if v.Namespace == "github:language:javascript" {
+			roundTripped = append(roundTripped, v)
+		}
+	}
+	require.Len(t, roundTripped, len(expected))
+	for _, e := range expected {
+		var found bool
+		for _, v := range roundTripped {
+			if v.PackageName == e.PackageName {
+				found = true
+				assert.Equal(t, e.VersionConstraint, v.VersionConstraint)
+				assert.Equal(t, e.Fix.State, v.Fix.State)
+				assert.Equal(t, e.Fix.Versions, v.Fix.Versions)
+			}
+		}
+		assert.True(t, found, "missing %s", e.PackageName)
+	}
+}
+
+func TestDBNamespace(t *testing.T) {
+	tests := []struct {
+		ecosystem string
+		expected  string
+	}{
+		{ecosystem: "PyPI", expected: "osv:language:python"},
+		{ecosystem: "crates.io", expected: "osv:language:rust"},
+		{ecosystem: "Go", expected: "osv:language:go"},
+		{ecosystem: "Debian:12", expected: "debian:distro:debian:12"},
+		{ecosystem: "Ubuntu:22.04:LTS", expected: "ubuntu:distro:ubuntu:22.04"},
+		{ecosystem: "Alpine:v3.18", expected: "alpine:distro:alpine:3.18"},
+		{ecosystem: "Wolfi", expected: "wolfi:distro:wolfi:rolling"},
+		{ecosystem: "Debian"},
+		{ecosystem: "Bitnami"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ecosystem, func(t *testing.T) {
+			ns, ok := dbNamespace(tt.ecosystem)
+			assert.Equal(t, tt.expected != "", ok)
+			assert.Equal(t, tt.expected, ns)
+		})
+	}
+}
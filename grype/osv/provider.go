@@ -0,0 +1,471 @@
+package osv
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+var _ interface {
+	vulnerability.Provider
+	vulnerability.MetadataProvider
+	store.DegradationReporter
+} = (*Provider)(nil)
+
+const (
+	// LocalProviderName labels the vulnerabilities from the local vulnerability DB.
+	LocalProviderName = "grype-db"
+	// APIProviderName labels the vulnerabilities reported by the OSV.dev API.
+	APIProviderName = "osv.dev"
+)
+
+const (
+	// ProviderFailedProblem is the data-quality problem of the API not being reachable.
+	ProviderFailedProblem = "provider-failed"
+	// ProviderPartialProblem is the data-quality problem of a vulnerability reported by the API whose record could not
+	// be fetched.
+	ProviderPartialProblem = "provider-partial"
+)
+
+// ProviderConfig configures the queries of the OSV.dev API made at scan time.
+type ProviderConfig struct {
+	// BaseURL is the base URL of the OSV.dev API (DefaultAPIURL when empty).
+	BaseURL string
+	// Ecosystems are the OSV ecosystems to query (e.g. "PyPI"), all language ecosystems when empty.
+	Ecosystems []string
+	// CacheDir is where the responses of the API are kept between scans, nothing is cached when empty.
+	CacheDir string
+	// CacheTTL is how long the cached vulnerabilities of a package version are used before querying the API again.
+	CacheTTL time.Duration
+	// Timeout is the timeout of each request to the API.
+	Timeout time.Duration
+}
+
+// Provider decorates an existing vulnerability provider, adding the vulnerabilities that the OSV.dev API reports for
+// language packages to the vulnerabilities from the local DB. Vulnerabilities are deduplicated by ID and alias: a
+// vulnerability reported by both keeps the record (and affected ranges) of the local DB, and every vulnerability is
+// labeled with the providers that reported it. When the API cannot be reached responses cached by earlier scans are
+// used, and otherwise only the local DB is.
+type Provider struct {
+	vulnerability.Provider
+	metadata   vulnerability.MetadataProvider
+	client     *Client
+	cache      apiCache
+	ecosystems []string
+
+	// the lock guards the results and records, which are fetched without holding it: the queries and the records
+	// being fetched are shared by the packages matched meanwhile
+	lock           sync.Mutex
+	results        map[Query][]QueryVulnerability
+	records        map[string]*Vulnerability
+	offline        bool
+	pendingQueries singleflight.Group
+	pendingRecords singleflight.Group
+
+	// the degradations of the data served: why the API went offline, the number of queries it did not answer, and the
+	// records that could not be fetched (by ID)
+	offlineErr    error
+	unanswered    int
+	missedRecords map[string]string
+}
+
+func NewProvider(provider vulnerability.Provider, metadata vulnerability.MetadataProvider, cfg ProviderConfig) *Provider {
+	return &Provider{
+		Provider:   provider,
+		metadata:   metadata,
+		client:     NewClient(cfg.BaseURL, cfg.Timeout),
+		cache:      apiCache{dir: cfg.CacheDir, ttl: cfg.CacheTTL},
+		ecosystems: cfg.Ecosystems,
+		results:    make(map[Query][]QueryVulnerability),
+		records:    make(map[string]*Vulnerability),
+
+		missedRecords: make(map[string]string),
+	}
+}
+
+// Apply returns a copy of the given store with the OSV.dev API layered over its vulnerability and metadata
+// providers, having queried the API for the given packages in batches.
+func Apply(s *store.Store, cfg ProviderConfig, packages []pkg.Package) *store.Store {
+	if s == nil {
+		return s
+	}
+
+	p := NewProvider(s.Provider, s.MetadataProvider, cfg)
+	p.Prefetch(packages)
+	return &store.Store{
+		Provider:          p,
+		MetadataProvider:  p,
+		ExclusionProvider: s.ExclusionProvider,
+		Targeting:         s.Targeting,
+	}
+}
+
+// Prefetch queries the API for all the given packages at once, rather than for each package as it is matched.
+func (p *Provider) Prefetch(packages []pkg.Package) {
+	p.lock.Lock()
+	var queries []Query
+	seen := make(map[Query]bool)
+	for _, pk := range packages {
+		q, ok := p.queryFor(pk.Language, pk)
+		if !ok || seen[q] {
+			continue
+		}
+		seen[q] = true
+		if _, ok := p.results[q]; ok {
+			continue
+		}
+		if cached, fresh := p.cache.query(q); fresh {
+			p.results[q] = cached.Vulns
+			continue
+		}
+		queries = append(queries, q)
+	}
+	p.lock.Unlock()
+
+	if len(queries) == 0 {
+		return
+	}
+	log.WithFields("packages", len(queries)).Debug("querying OSV.dev for vulnerabilities")
+	p.query(queries)
+}
+
+func (p *Provider) Get(id, namespace string) ([]vulnerability.Vulnerability, error) {
+	if strings.HasPrefix(namespace, APINamespacePrefix) {
+		// the vulnerabilities depend on the package they were queried for
+		return nil, nil
+	}
+	return p.Provider.Get(id, namespace)
+}
+
+func (p *Provider) GetByDistro(d *distro.Distro, pk pkg.Package) ([]vulnerability.Vulnerability, error) {
+	vulns, err := p.Provider.GetByDistro(d, pk)
+	return labeled(vulns, LocalProviderName), err
+}
+
+func (p *Provider) GetByCPE(c cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	vulns, err := p.Provider.GetByCPE(c)
+	return labeled(vulns, LocalProviderName), err
+}
+
+func (p *Provider) GetByLanguage(l syftPkg.Language, pk pkg.Package) ([]vulnerability.Vulnerability, error) {
+	vulns, err := p.Provider.GetByLanguage(l, pk)
+	if err != nil {
+		return nil, err
+	}
+	vulns = labeled(vulns, LocalProviderName)
+
+	q, ok := p.queryFor(l, pk)
+	if !ok {
+		return vulns, nil
+	}
+
+	// the vulnerabilities that affect the package by each of their IDs and aliases: a DB record of the same
+	// vulnerability whose ranges miss the version of the package does not hide the API record (the API only reports
+	// the vulnerabilities of the queried version)
+	known := make(map[string][]int)
+	remember := func(idx int, ids ...string) {
+		for _, id := range ids {
+			known[id] = append(known[id], idx)
+		}
+	}
+	ver, verErr := version.NewVersionFromPkg(pk)
+	for idx, v := range vulns {
+		if !affects(v, ver, verErr) {
+			continue
+		}
+		remember(idx, v.ID)
+		for _, r := range v.RelatedVulnerabilities {
+			remember(idx, r.ID)
+		}
+	}
+
+	namespace := APINamespacePrefix + string(l)
+	format := version.FormatFromPkg(pk)
+	for _, qv := range p.lookup(q) {
+		r := p.record(qv)
+		if r == nil {
+			continue
+		}
+		if indexes := knownAs(*r, known); len(indexes) > 0 {
+			for _, idx := range indexes {
+				vulns[idx].Providers = appendProvider(vulns[idx].Providers, APIProviderName)
+			}
+			continue
+		}
+		for _, v := range toVulnerabilities(*r, q, namespace, format) {
+			v.Providers = []string{APIProviderName}
+			vulns = append(vulns, v)
+			remember(len(vulns)-1, append([]string{r.ID}, r.Aliases...)...)
+		}
+	}
+	return vulns, nil
+}
+
+func (p *Provider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	if strings.HasPrefix(namespace, APINamespacePrefix) {
+		p.lock.Lock()
+		r := p.records[id]
+		p.lock.Unlock()
+		if r == nil {
+			return nil, nil
+		}
+		return toMetadata(*r, namespace), nil
+	}
+	if p.metadata == nil {
+		return nil, nil
+	}
+	return p.metadata.GetMetadata(id, namespace)
+}
+
+// queryFor returns the API query for the package, if the package is within a selected OSV ecosystem.
+func (p *Provider) queryFor(l syftPkg.Language, pk pkg.Package) (Query, bool) {
+	q, ok := packageQuery(l, pk)
+	if !ok || !matchesEcosystem(q.Package.Ecosystem, p.ecosystems) {
+		return Query{}, false
+	}
+	return q, true
+}
+
+// PackageQuery returns the OSV package and version of a language package, if the package is within an OSV ecosystem.
+func PackageQuery(pk pkg.Package) (Query, bool) {
+	return packageQuery(pk.Language, pk)
+}
+
+func packageQuery(l syftPkg.Language, pk pkg.Package) (Query, bool) {
+	ecosystem, ok := languageEcosystems[l]
+	if !ok {
+		return Query{}, false
+	}
+	if pk.Version == "" || strings.HasPrefix(pk.Version, "(devel)") {
+		return Query{}, false
+	}
+
+	name := pk.Name
+	if m, ok := pk.Metadata.(pkg.JavaMetadata); ok {
+		// maven packages are named by group and artifact
+		if m.PomGroupID == "" || m.PomArtifactID == "" {
+			return Query{}, false
+		}
+		name = m.PomGroupID + ":" + m.PomArtifactID
+	} else if l == syftPkg.Java {
+		return Query{}, false
+	}
+
+	return Query{
+		Package: Package{Ecosystem: ecosystem, Name: name},
+		Version: osvVersion(pk.Version, version.FormatFromPkg(pk)),
+	}, true
+}
+
+// lookup returns the vulnerabilities of the query from the results of earlier queries, or else from the API.
+func (p *Provider) lookup(q Query) []QueryVulnerability {
+	p.lock.Lock()
+	vulns, ok := p.results[q]
+	p.lock.Unlock()
+	if ok {
+		return vulns
+	}
+
+	key := q.Package.Ecosystem + "\x00" + q.Package.Name + "\x00" + q.Version
+	result, _, _ := p.pendingQueries.Do(key, func() (any, error) {
+		p.lock.Lock()
+		_, ok := p.results[q]
+		p.lock.Unlock()
+		if !ok {
+			if cached, fresh := p.cache.query(q); fresh {
+				p.lock.Lock()
+				p.results[q] = cached.Vulns
+				p.lock.Unlock()
+			} else {
+				p.query([]Query{q})
+			}
+		}
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		return p.results[q], nil
+	})
+	return result.([]QueryVulnerability)
+}
+
+// query sends the queries to the API and records the results, falling back to stale cached results (or no results)
+// when the API cannot be reached. The lock must not be held, it is only taken to record the results.
+func (p *Provider) query(queries []Query) {
+	p.lock.Lock()
+	offline := p.offline
+	p.lock.Unlock()
+
+	var results []QueryResult
+	var queryErr error
+	if !offline {
+		results, queryErr = p.client.QueryBatch(queries)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if queryErr != nil && !p.offline {
+		p.goOffline(queryErr)
+	}
+	for i, q := range queries {
+		if i < len(results) {
+			p.results[q] = results[i].Vulns
+			p.cache.putQuery(q, results[i].Vulns)
+			continue
+		}
+		p.unanswered++
+		var vulns []QueryVulnerability
+		if cached, _ := p.cache.query(q); cached != nil {
+			vulns = cached.Vulns
+		}
+		p.results[q] = vulns
+	}
+}
+
+// record returns the record of the vulnerability, from memory, the cache, or the API. The record is fetched once,
+// without holding the lock, however many packages are matched against the vulnerability meanwhile.
+func (p *Provider) record(qv QueryVulnerability) *Vulnerability {
+	p.lock.Lock()
+	r, ok := p.records[qv.ID]
+	p.lock.Unlock()
+	if ok {
+		return r
+	}
+
+	result, _, _ := p.pendingRecords.Do(qv.ID, func() (any, error) {
+		p.lock.Lock()
+		r, ok := p.records[qv.ID]
+		offline := p.offline
+		p.lock.Unlock()
+		if ok {
+			// fetched by a call that completed since
+			return r, nil
+		}
+		r, missed := p.fetchRecord(qv, offline)
+
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		if missed != "" {
+			p.missedRecords[qv.ID] = missed
+		}
+		p.records[qv.ID] = r
+		return r, nil
+	})
+	return result.(*Vulnerability)
+}
+
+// fetchRecord returns the record of the vulnerability from the cache or the API, and why the record could not be
+// fetched (when it could not). The lock must not be held.
+func (p *Provider) fetchRecord(qv QueryVulnerability, offline bool) (*Vulnerability, string) {
+	r, ok := p.cache.record(qv.ID, qv.Modified)
+	if ok {
+		return r, ""
+	}
+	var fetchErr error
+	if offline {
+		fetchErr = errors.New("the API cannot be reached")
+	} else {
+		r, fetchErr = p.client.GetVulnerability(qv.ID)
+		if fetchErr == nil {
+			p.cache.putRecord(r)
+			return r, ""
+		}
+		log.WithFields("id", qv.ID, "error", fetchErr).Debug("unable to fetch OSV record")
+	}
+
+	// fall back to an outdated record rather than none at all
+	if r, _ = p.cache.record(qv.ID, time.Time{}); r != nil {
+		return r, fmt.Sprintf("unable to fetch the record (%v), using an outdated cached record", fetchErr)
+	}
+	return nil, fmt.Sprintf("unable to fetch the record (%v), the vulnerability is not reported", fetchErr)
+}
+
+func (p *Provider) goOffline(err error) {
+	log.WithFields("error", err).Warn("unable to query OSV.dev, using the vulnerability DB (and any cached OSV.dev results) only")
+	p.offline = true
+	p.offlineErr = err
+}
+
+// Degradations returns the degradations of the vulnerabilities served so far: the API could not be reached (so that
+// packages were only matched against the vulnerability DB and any cached results), or records of the vulnerabilities
+// it reported could not be fetched. The degradations of the decorated providers are included.
+func (p *Provider) Degradations() []grypeerr.DataQualityProblem {
+	problems := store.DegradationsOf(p.Provider)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.offlineErr != nil {
+		problems = append(problems, grypeerr.DataQualityProblem{
+			Kind:    ProviderFailedProblem,
+			Subject: APIProviderName,
+			Message: fmt.Sprintf("unable to query the API, %d package version(s) were only matched against the vulnerability DB and cached results: %v", p.unanswered, p.offlineErr),
+		})
+	}
+	ids := make([]string, 0, len(p.missedRecords))
+	for id := range p.missedRecords {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		problems = append(problems, grypeerr.DataQualityProblem{Kind: ProviderPartialProblem, Subject: id + " (" + APIProviderName + ")", Message: p.missedRecords[id]})
+	}
+	return problems
+}
+
+// knownAs returns the indexes of the known vulnerabilities that are the record (by its ID or one of its aliases).
+// affects reports whether the version of the package is within the ranges of the DB record. Records whose ranges
+// cannot be compared with the version are taken as not affecting it.
+func affects(v vulnerability.Vulnerability, ver *version.Version, verErr error) bool {
+	if v.Constraint == nil {
+		return true
+	}
+	if verErr != nil {
+		return false
+	}
+	satisfied, err := v.Constraint.Satisfied(ver)
+	return err == nil && satisfied
+}
+
+func knownAs(r Vulnerability, known map[string][]int) []int {
+	var indexes []int
+	seen := make(map[int]bool)
+	for _, id := range append([]string{r.ID}, r.Aliases...) {
+		for _, idx := range known[id] {
+			if !seen[idx] {
+				seen[idx] = true
+				indexes = append(indexes, idx)
+			}
+		}
+	}
+	return indexes
+}
+
+func labeled(vulns []vulnerability.Vulnerability, provider string) []vulnerability.Vulnerability {
+	for i := range vulns {
+		vulns[i].Providers = appendProvider(vulns[i].Providers, provider)
+	}
+	return vulns
+}
+
+func appendProvider(providers []string, provider string) []string {
+	for _, p := range providers {
+		if p == provider {
+			return providers
+		}
+	}
+	return append(append([]string{}, providers...), provider)
+}
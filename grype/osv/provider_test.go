@@ -0,0 +1,341 @@
+package osv
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+var testRecords = map[string]Vulnerability{
+	"PYSEC-2023-74": {
+		ID:       "PYSEC-2023-74",
+		Modified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Aliases:  []string{"CVE-2023-32681", "GHSA-j8r2-6x86-q33q"},
+		Summary:  "Unintended leak of Proxy-Authorization header in requests",
+		Affected: []Affected{{
+			Package: Package{Ecosystem: "PyPI", Name: "requests"},
+			Ranges:  []Range{{Type: RangeEcosystem, Events: []Event{{Introduced: "2.3.0"}, {Fixed: "2.31.0"}}}},
+		}},
+		Severity:   []Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:C/C:H/I:N/A:N"}},
+		References: []Reference{{Type: "WEB", URL: "https://github.com/psf/requests/releases/tag/v2.31.0"}},
+	},
+	"GHSA-j8r2-6x86-q33q": {
+		ID:       "GHSA-j8r2-6x86-q33q",
+		Modified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Aliases:  []string{"CVE-2023-32681", "PYSEC-2023-74"},
+		Affected: []Affected{{
+			Package: Package{Ecosystem: "PyPI", Name: "requests"},
+			Ranges:  []Range{{Type: RangeEcosystem, Events: []Event{{Introduced: "2.3.0"}, {Fixed: "2.31.0"}}}},
+		}},
+	},
+	"GHSA-xxxx-python": {
+		ID:       "GHSA-xxxx-python",
+		Modified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Affected: []Affected{{
+			Package: Package{Ecosystem: "PyPI", Name: "requests"},
+			Ranges:  []Range{{Type: RangeEcosystem, Events: []Event{{Introduced: "0"}, {LastAffected: "2.31.0"}}}},
+		}},
+	},
+}
+
+type testAPI struct {
+	*httptest.Server
+	batches atomic.Int32
+	fetches atomic.Int32
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	api := &testAPI{}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/querybatch":
+			api.batches.Add(1)
+			var req struct {
+				Queries []Query `json:"queries"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+			var res struct {
+				Results []QueryResult `json:"results"`
+			}
+			for _, q := range req.Queries {
+				var result QueryResult
+				if q.Package.Name == "requests" && q.Version == "2.28.0" {
+					for _, id := range []string{"PYSEC-2023-74", "GHSA-j8r2-6x86-q33q", "GHSA-xxxx-python"} {
+						result.Vulns = append(result.Vulns, QueryVulnerability{ID: id, Modified: testRecords[id].Modified})
+					}
+				}
+				res.Results = append(res.Results, result)
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(res))
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/vulns/"):
+			api.fetches.Add(1)
+			record, ok := testRecords[strings.TrimPrefix(r.URL.Path, "/v1/vulns/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(record))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(api.Close)
+	return api
+}
+
+// localProvider is the vulnerability DB, which has one of the vulnerabilities reported by the API (affecting the
+// versions within its constraint, "<=2.31.0" when empty)
+type localProvider struct {
+	constraint string
+}
+
+func (localProvider) Get(string, string) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (localProvider) GetByDistro(*distro.Distro, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (localProvider) GetByCPE(cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (l localProvider) GetByLanguage(_ syftPkg.Language, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	if p.Name != "requests" {
+		return nil, nil
+	}
+	constraint := l.constraint
+	if constraint == "" {
+		constraint = "<=2.31.0"
+	}
+	return []vulnerability.Vulnerability{{
+		ID:         "GHSA-xxxx-python",
+		Namespace:  "github:language:python",
+		Constraint: version.MustGetConstraint(constraint, version.PythonFormat),
+	}}, nil
+}
+
+var requests = pkg.Package{
+	Name:     "requests",
+	Version:  "2.28.0",
+	Type:     syftPkg.PythonPkg,
+	Language: syftPkg.Python,
+}
+
+func TestProvider_GetByLanguage(t *testing.T) {
+	api := newTestAPI(t)
+	p := NewProvider(localProvider{}, nil, ProviderConfig{BaseURL: api.URL, Timeout: time.Second})
+
+	other := pkg.Package{Name: "urllib3", Version: "2.0.0", Type: syftPkg.PythonPkg, Language: syftPkg.Python}
+	p.Prefetch([]pkg.Package{requests, other})
+	assert.Equal(t, int32(1), api.batches.Load(), "all packages are queried in a single batch")
+
+	vulns, err := p.GetByLanguage(syftPkg.Python, requests)
+	require.NoError(t, err)
+	require.Len(t, vulns, 2)
+
+	// the vulnerability from the DB is not reported again by the API
+	assert.Equal(t, "GHSA-xxxx-python", vulns[0].ID)
+	assert.Equal(t, "github:language:python", vulns[0].Namespace)
+	assert.Equal(t, []string{LocalProviderName, APIProviderName}, vulns[0].Providers)
+
+	// nor are the aliases of a vulnerability reported by the API
+	v := vulns[1]
+	assert.Equal(t, "PYSEC-2023-74", v.ID)
+	assert.Equal(t, APINamespacePrefix+"python", v.Namespace)
+	assert.Equal(t, []string{APIProviderName}, v.Providers)
+	assert.Equal(t, []string{"2.31.0"}, v.Fix.Versions)
+	assert.Equal(t, []vulnerability.Reference{{ID: "CVE-2023-32681", Namespace: "nvd:cpe"}}, v.RelatedVulnerabilities)
+	ver, err := version.NewVersionFromPkg(requests)
+	require.NoError(t, err)
+	satisfied, err := v.Constraint.Satisfied(ver)
+	require.NoError(t, err)
+	assert.True(t, satisfied)
+
+	vulns, err = p.GetByLanguage(syftPkg.Python, other)
+	require.NoError(t, err)
+	assert.Empty(t, vulns)
+	assert.Equal(t, int32(1), api.batches.Load(), "prefetched packages are not queried again")
+
+	m, err := p.GetMetadata("PYSEC-2023-74", v.Namespace)
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	assert.Equal(t, "Medium", m.Severity)
+	assert.Equal(t, "Unintended leak of Proxy-Authorization header in requests", m.Description)
+	assert.Equal(t, []string{"https://github.com/psf/requests/releases/tag/v2.31.0"}, m.URLs)
+	require.Len(t, m.Cvss, 1)
+	assert.Equal(t, "3.1", m.Cvss[0].Version)
+}
+
+func TestProvider_GetByLanguage_dbRecordMissesVersion(t *testing.T) {
+	api := newTestAPI(t)
+	// the DB record of the vulnerability has other ranges than OSV.dev, which reports the queried version affected
+	p := NewProvider(localProvider{constraint: "<2.0.0"}, nil, ProviderConfig{BaseURL: api.URL, Timeout: time.Second})
+
+	vulns, err := p.GetByLanguage(syftPkg.Python, requests)
+	require.NoError(t, err)
+	require.Len(t, vulns, 3)
+
+	assert.Equal(t, "GHSA-xxxx-python", vulns[0].ID)
+	assert.Equal(t, []string{LocalProviderName}, vulns[0].Providers, "the DB record did not match the package")
+
+	var fromAPI []string
+	for _, v := range vulns[1:] {
+		assert.Equal(t, APINamespacePrefix+"python", v.Namespace)
+		fromAPI = append(fromAPI, v.ID)
+	}
+	assert.ElementsMatch(t, []string{"PYSEC-2023-74", "GHSA-xxxx-python"}, fromAPI)
+}
+
+func TestProvider_ecosystems(t *testing.T) {
+	api := newTestAPI(t)
+	p := NewProvider(localProvider{}, nil, ProviderConfig{BaseURL: api.URL, Ecosystems: []string{"npm"}, Timeout: time.Second})
+
+	vulns, err := p.GetByLanguage(syftPkg.Python, requests)
+	require.NoError(t, err)
+	assert.Len(t, vulns, 1)
+	assert.Equal(t, int32(0), api.batches.Load())
+}
+
+func TestProvider_offlineFallback(t *testing.T) {
+	api := newTestAPI(t)
+	cfg := ProviderConfig{BaseURL: api.URL, CacheDir: t.TempDir(), CacheTTL: time.Hour, Timeout: time.Second}
+
+	// a first scan caches the results
+	p := NewProvider(localProvider{}, nil, cfg)
+	vulns, err := p.GetByLanguage(syftPkg.Python, requests)
+	require.NoError(t, err)
+	require.Len(t, vulns, 2)
+	assert.Empty(t, p.Degradations())
+
+	// results younger than the TTL are used without querying the API
+	p = NewProvider(localProvider{}, nil, cfg)
+	vulns, err = p.GetByLanguage(syftPkg.Python, requests)
+	require.NoError(t, err)
+	assert.Len(t, vulns, 2)
+	assert.Equal(t, int32(1), api.batches.Load())
+	assert.Equal(t, int32(3), api.fetches.Load())
+
+	// expired results are still used when the API cannot be reached
+	api.Close()
+	cfg.CacheTTL = 0
+	p = NewProvider(localProvider{}, nil, cfg)
+	vulns, err = p.GetByLanguage(syftPkg.Python, requests)
+	require.NoError(t, err)
+	assert.Len(t, vulns, 2)
+	problems := p.Degradations()
+	require.Len(t, problems, 1, "the cached records are those of the cached results")
+	assert.Equal(t, ProviderFailedProblem, problems[0].Kind)
+	assert.Equal(t, APIProviderName, problems[0].Subject)
+	assert.Contains(t, problems[0].Message, "1 package version(s)")
+
+	// without a cache only the vulnerability DB is used
+	cfg.CacheDir = ""
+	p = NewProvider(localProvider{}, nil, cfg)
+	vulns, err = p.GetByLanguage(syftPkg.Python, requests)
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	assert.Equal(t, []string{LocalProviderName}, vulns[0].Providers)
+}
+
+func Test_eventConstraints(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []Event
+		expected []string
+		fixes    []string
+	}{
+		{
+			name:     "introduced and fixed",
+			events:   []Event{{Introduced: "1.0"}, {Fixed: "1.2"}},
+			expected: []string{">= 1.0, < 1.2"},
+			fixes:    []string{"1.2"},
+		},
+		{
+			name:     "from the first version",
+			events:   []Event{{Introduced: "0"}, {LastAffected: "1.2"}},
+			expected: []string{"<= 1.2"},
+		},
+		{
+			name:     "several intervals",
+			events:   []Event{{Introduced: "1.0"}, {Fixed: "1.2"}, {Introduced: "2.0"}, {Limit: "2.5"}},
+			expected: []string{">= 1.0, < 1.2", ">= 2.0, < 2.5"},
+			fixes:    []string{"1.2"},
+		},
+		{
+			name:     "unbounded",
+			events:   []Event{{Introduced: "0"}},
+			expected: []string{">= 0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clauses, fixes := eventConstraints(tt.events)
+			assert.Equal(t, tt.expected, clauses)
+			assert.Equal(t, tt.fixes, fixes)
+		})
+	}
+}
+
+func TestProvider_concurrentLookups(t *testing.T) {
+	api := newTestAPI(t)
+	p := NewProvider(localProvider{}, nil, ProviderConfig{BaseURL: api.URL, Timeout: time.Second})
+
+	// the packages matched at once share the query and the records being fetched
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vulns, err := p.GetByLanguage(syftPkg.Python, requests)
+			assert.NoError(t, err)
+			assert.Len(t, vulns, 2)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), api.batches.Load())
+	assert.Equal(t, int32(3), api.fetches.Load())
+}
+
+func TestProvider_Degradations_missingRecord(t *testing.T) {
+	// the API reports a vulnerability whose record cannot be fetched
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"results": []QueryResult{{Vulns: []QueryVulnerability{{ID: "GHSA-gone"}}}},
+			}))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	p := NewProvider(localProvider{}, nil, ProviderConfig{BaseURL: server.URL, Timeout: time.Second})
+	vulns, err := p.GetByLanguage(syftPkg.Python, requests)
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+
+	problems := p.Degradations()
+	require.Len(t, problems, 1)
+	assert.Equal(t, ProviderPartialProblem, problems[0].Kind)
+	assert.Equal(t, "GHSA-gone (osv.dev)", problems[0].Subject)
+	assert.Contains(t, problems[0].Message, "the vulnerability is not reported")
+}
@@ -0,0 +1,71 @@
+/*
+Package osv provides the Open Source Vulnerability (OSV) schema (https://ossf.github.io/osv-schema/) along with
+conversions between OSV records and grype vulnerability data.
+*/
+package osv
+
+import "time"
+
+const SchemaVersion = "1.6.0"
+
+// Range types
+const (
+	RangeSemver    = "SEMVER"
+	RangeEcosystem = "ECOSYSTEM"
+	RangeGit       = "GIT"
+)
+
+// Vulnerability is a single OSV record.
+type Vulnerability struct {
+	SchemaVersion    string         `json:"schema_version,omitempty"`
+	ID               string         `json:"id"`
+	Modified         time.Time      `json:"modified"`
+	Published        *time.Time     `json:"published,omitempty"`
+	Withdrawn        *time.Time     `json:"withdrawn,omitempty"`
+	Aliases          []string       `json:"aliases,omitempty"`
+	Related          []string       `json:"related,omitempty"`
+	Summary          string         `json:"summary,omitempty"`
+	Details          string         `json:"details,omitempty"`
+	Severity         []Severity     `json:"severity,omitempty"`
+	Affected         []Affected     `json:"affected,omitempty"`
+	References       []Reference    `json:"references,omitempty"`
+	DatabaseSpecific map[string]any `json:"database_specific,omitempty"`
+}
+
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type Affected struct {
+	Package           Package        `json:"package"`
+	Ranges            []Range        `json:"ranges,omitempty"`
+	Versions          []string       `json:"versions,omitempty"`
+	EcosystemSpecific map[string]any `json:"ecosystem_specific,omitempty"`
+	DatabaseSpecific  map[string]any `json:"database_specific,omitempty"`
+}
+
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	PURL      string `json:"purl,omitempty"`
+}
+
+type Range struct {
+	Type   string  `json:"type"`
+	Repo   string  `json:"repo,omitempty"`
+	Events []Event `json:"events"`
+}
+
+// Event is a single point within a range, only one of the fields is set.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
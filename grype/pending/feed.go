@@ -0,0 +1,424 @@
+/*
+Package pending provides a supplementary "pending analysis" vulnerability feed. NVD frequently lags behind CVE
+publication, leaving newly published CVEs without CPE configurations and therefore unmatchable. CVE records in the
+CVE JSON 5 format usually still carry affected vendor/product/version data supplied by the CNA or by an ADP (such
+as CISA vulnrichment); this package turns that data into CPE-searchable vulnerability records.
+*/
+package pending
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	cpeUtil "github.com/anchore/grype/grype/cpe"
+	grypeDB "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/dataset"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/cpe"
+)
+
+const (
+	// Namespace is the namespace assigned to all vulnerabilities and metadata originating from the pending feed.
+	Namespace = "cve:pending-analysis"
+
+	// DBFileName is the name of the optional pending analysis feed that may be shipped alongside the vulnerability DB.
+	DBFileName = "pending-analysis.json"
+
+	nvdNamespace = "nvd:cpe"
+	nvdURLPrefix = "https://nvd.nist.gov/vuln/detail/"
+
+	// anyVersion is the CVE JSON 5 wildcard used to express open-ended version ranges
+	anyVersion = "*"
+)
+
+// Record is a single CVE from the feed that has been reduced to what is needed for CPE matching.
+type Record struct {
+	ID string
+	// Vulnerabilities holds one entry per affected product, each with its own CPEs and version constraint.
+	Vulnerabilities []vulnerability.Vulnerability
+	Metadata        vulnerability.Metadata
+}
+
+// Feed is a collection of pending analysis records, keyed by CVE ID.
+type Feed struct {
+	records map[string]Record
+}
+
+func NewFeed() *Feed {
+	return &Feed{
+		records: make(map[string]Record),
+	}
+}
+
+// Len returns the number of records in the feed.
+func (f *Feed) Len() int {
+	if f == nil {
+		return 0
+	}
+	return len(f.records)
+}
+
+// Add inserts the given record, replacing any existing record with the same ID.
+func (f *Feed) Add(r Record) {
+	f.records[r.ID] = r
+}
+
+// Merge adds all records from the other feed into this feed. Records in the other feed take precedence.
+func (f *Feed) Merge(other *Feed) {
+	if other == nil {
+		return
+	}
+	for _, r := range other.records {
+		f.Add(r)
+	}
+}
+
+// Get returns the record for the given CVE ID, if present.
+func (f *Feed) Get(id string) (Record, bool) {
+	if f == nil {
+		return Record{}, false
+	}
+	r, ok := f.records[id]
+	return r, ok
+}
+
+// IDs returns all CVE IDs within the feed in sorted order.
+func (f *Feed) IDs() []string {
+	if f == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(f.records))
+	for id := range f.records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Parse reads CVE JSON 5 records, either as a single record object or as an array of records.
+func Parse(data []byte) (*Feed, error) {
+	var records []cveRecord
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("unable to parse pending analysis feed: %w", err)
+		}
+	} else {
+		var single cveRecord
+		if err := json.Unmarshal(trimmed, &single); err != nil {
+			return nil, fmt.Errorf("unable to parse pending analysis feed: %w", err)
+		}
+		records = append(records, single)
+	}
+
+	f := NewFeed()
+	for _, raw := range records {
+		r, ok := raw.toRecord()
+		if !ok {
+			continue
+		}
+		f.Add(r)
+	}
+	return f, nil
+}
+
+// sidecar is the pending analysis feed shipped within the DB directory.
+var sidecar = dataset.Sidecar[*Feed]{
+	Kind:      "pending analysis feed",
+	Path:      DBFileName,
+	Parse:     Parse,
+	New:       NewFeed,
+	Recursive: true,
+}
+
+// FromPath reads a feed from a single JSON file or from every JSON file within a directory (recursively).
+func FromPath(path string) (*Feed, error) {
+	return sidecar.FromPath(path)
+}
+
+// FromDBDir reads the pending analysis feed shipped within the given DB directory, if there is one. A nil feed
+// (and no error) is returned when the DB does not ship a feed.
+func FromDBDir(dir string) (*Feed, error) {
+	return sidecar.FromDBDir(dir)
+}
+
+// cveRecord is the subset of the CVE JSON 5 record format needed to build a record.
+type cveRecord struct {
+	Metadata struct {
+		ID    string `json:"cveId"`
+		State string `json:"state"`
+	} `json:"cveMetadata"`
+	Containers struct {
+		CNA cveContainer   `json:"cna"`
+		ADP []cveContainer `json:"adp"`
+	} `json:"containers"`
+}
+
+type cveContainer struct {
+	Provider struct {
+		ShortName string `json:"shortName"`
+	} `json:"providerMetadata"`
+	Affected     []cveAffected    `json:"affected"`
+	Descriptions []cveDescription `json:"descriptions"`
+	Metrics      []cveMetric      `json:"metrics"`
+	References   []struct {
+		URL string `json:"url"`
+	} `json:"references"`
+}
+
+type cveAffected struct {
+	Vendor   string       `json:"vendor"`
+	Product  string       `json:"product"`
+	CPEs     []string     `json:"cpes"`
+	Versions []cveVersion `json:"versions"`
+}
+
+type cveVersion struct {
+	Version         string `json:"version"`
+	Status          string `json:"status"`
+	LessThan        string `json:"lessThan"`
+	LessThanOrEqual string `json:"lessThanOrEqual"`
+}
+
+type cveDescription struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+type cveMetric struct {
+	CvssV31 *cveCvss `json:"cvssV3_1"`
+	CvssV30 *cveCvss `json:"cvssV3_0"`
+}
+
+type cveCvss struct {
+	Version      string  `json:"version"`
+	Vector       string  `json:"vectorString"`
+	BaseScore    float64 `json:"baseScore"`
+	BaseSeverity string  `json:"baseSeverity"`
+}
+
+func (r cveRecord) toRecord() (Record, bool) {
+	id := strings.TrimSpace(r.Metadata.ID)
+	if id == "" || strings.EqualFold(r.Metadata.State, "REJECTED") {
+		return Record{}, false
+	}
+
+	containers := append([]cveContainer{r.Containers.CNA}, r.Containers.ADP...)
+
+	var vulns []vulnerability.Vulnerability
+	for _, c := range containers {
+		for _, a := range c.Affected {
+			v, ok := a.toVulnerability(id)
+			if !ok {
+				continue
+			}
+			vulns = append(vulns, v)
+		}
+	}
+
+	if len(vulns) == 0 {
+		// without a product to search by and a version range to compare against, a match would be pure noise
+		log.WithFields("id", id).Trace("skipping pending analysis record without affected products or versions")
+		return Record{}, false
+	}
+
+	return Record{
+		ID:              id,
+		Vulnerabilities: vulns,
+		Metadata:        r.metadata(id, containers),
+	}, true
+}
+
+// toVulnerability converts a single affected product entry into a CPE-searchable vulnerability.
+func (a cveAffected) toVulnerability(id string) (vulnerability.Vulnerability, bool) {
+	cpes := a.cpes()
+	if len(cpes) == 0 {
+		return vulnerability.Vulnerability{}, false
+	}
+
+	var constraints []string
+	seen := make(map[string]struct{})
+	for _, v := range a.Versions {
+		expr, ok := v.constraint()
+		if !ok {
+			continue
+		}
+		if _, ok := seen[expr]; ok {
+			continue
+		}
+		seen[expr] = struct{}{}
+		constraints = append(constraints, expr)
+	}
+	if len(constraints) == 0 {
+		return vulnerability.Vulnerability{}, false
+	}
+
+	constraint, err := version.GetConstraint(strings.Join(constraints, " || "), version.UnknownFormat)
+	if err != nil {
+		log.WithFields("id", id, "product", a.Product, "error", err).Debug("skipping pending analysis product with unusable version data")
+		return vulnerability.Vulnerability{}, false
+	}
+
+	return vulnerability.Vulnerability{
+		ID:         id,
+		Namespace:  Namespace,
+		Constraint: constraint,
+		CPEs:       cpes,
+		Fix: vulnerability.Fix{
+			State: grypeDB.UnknownFixState,
+		},
+	}, true
+}
+
+func (r cveRecord) metadata(id string, containers []cveContainer) vulnerability.Metadata {
+	m := vulnerability.Metadata{
+		ID:         id,
+		DataSource: nvdURLPrefix + id,
+		Namespace:  Namespace,
+		Severity:   "Unknown",
+	}
+
+	seenURLs := make(map[string]struct{})
+	for _, c := range containers {
+		if m.Description == "" {
+			for _, d := range c.Descriptions {
+				if strings.HasPrefix(strings.ToLower(d.Lang), "en") {
+					m.Description = d.Value
+					break
+				}
+			}
+		}
+		for _, ref := range c.References {
+			if _, ok := seenURLs[ref.URL]; ok || ref.URL == "" {
+				continue
+			}
+			seenURLs[ref.URL] = struct{}{}
+			m.URLs = append(m.URLs, ref.URL)
+		}
+		source := c.Provider.ShortName
+		for _, metric := range c.Metrics {
+			cvss := metric.CvssV31
+			if cvss == nil {
+				cvss = metric.CvssV30
+			}
+			if cvss == nil {
+				continue
+			}
+			m.Cvss = append(m.Cvss, vulnerability.Cvss{
+				Source:  source,
+				Type:    "Secondary",
+				Version: cvss.Version,
+				Vector:  cvss.Vector,
+				Metrics: vulnerability.CvssMetrics{
+					BaseScore: cvss.BaseScore,
+				},
+			})
+			if m.Severity == "Unknown" && cvss.BaseSeverity != "" {
+				m.Severity = titleCase(cvss.BaseSeverity)
+			}
+		}
+	}
+
+	return m
+}
+
+// cpes returns the explicit CPEs for the affected entry, or a CPE synthesized from the vendor and product strings.
+func (a cveAffected) cpes() []cpe.CPE {
+	explicit, _ := cpeUtil.NewSlice(a.CPEs...)
+	if len(explicit) > 0 {
+		return explicit
+	}
+
+	vendor := normalizeCPEField(a.Vendor)
+	product := normalizeCPEField(a.Product)
+	if vendor == "" || product == "" {
+		return nil
+	}
+
+	c, err := cpe.New(fmt.Sprintf("cpe:2.3:a:%s:%s:*:*:*:*:*:*:*:*", vendor, product), "")
+	if err != nil {
+		log.WithFields("vendor", a.Vendor, "product", a.Product, "error", err).Trace("unable to synthesize CPE for pending analysis record")
+		return nil
+	}
+	return []cpe.CPE{c}
+}
+
+// constraint converts a CVE JSON 5 version entry into a fuzzy version constraint expression.
+func (v cveVersion) constraint() (string, bool) {
+	if !strings.EqualFold(v.Status, "affected") {
+		return "", false
+	}
+
+	start := cleanVersion(v.Version)
+	openStart := start == "" || start == "0" || start == anyVersion
+
+	switch {
+	case v.LessThan != "":
+		end := cleanVersion(v.LessThan)
+		switch {
+		case end == "" || end == anyVersion:
+			if openStart {
+				return "", false
+			}
+			return ">= " + start, true
+		case openStart:
+			return "< " + end, true
+		default:
+			return fmt.Sprintf(">= %s, < %s", start, end), true
+		}
+	case v.LessThanOrEqual != "":
+		end := cleanVersion(v.LessThanOrEqual)
+		switch {
+		case end == "" || end == anyVersion:
+			if openStart {
+				return "", false
+			}
+			return ">= " + start, true
+		case openStart:
+			return "<= " + end, true
+		default:
+			return fmt.Sprintf(">= %s, <= %s", start, end), true
+		}
+	case openStart:
+		return "", false
+	default:
+		return "= " + start, true
+	}
+}
+
+// cleanVersion returns the version string when it can be used within a constraint expression, or an empty string
+// for placeholders (e.g. "unspecified", "n/a") and free-form text.
+func cleanVersion(v string) string {
+	v = strings.TrimSpace(v)
+	switch strings.ToLower(v) {
+	case "", "unspecified", "unknown", "n/a", "-":
+		return ""
+	}
+	if strings.ContainsAny(v, " ,|<>=") {
+		return ""
+	}
+	return v
+}
+
+func normalizeCPEField(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "", "n/a", "unknown", "unspecified":
+		return ""
+	}
+	return strings.Join(strings.Fields(s), "_")
+}
+
+func titleCase(s string) string {
+	s = strings.ToLower(s)
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,106 @@
+package pending
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromPath(t *testing.T) {
+	feed, err := FromPath("test-fixtures/feed")
+	require.NoError(t, err)
+
+	// CVE-2024-0002 has no usable product or version data and CVE-2024-0003 is rejected
+	assert.Equal(t, []string{"CVE-2024-0001", "CVE-2024-0004"}, feed.IDs())
+
+	r, ok := feed.Get("CVE-2024-0001")
+	require.True(t, ok)
+	require.Len(t, r.Vulnerabilities, 2, "one vulnerability per affected entry (CNA and ADP)")
+
+	cna := r.Vulnerabilities[0]
+	assert.Equal(t, Namespace, cna.Namespace)
+	assert.Equal(t, "< 2.4.1 || >= 3.0.0, <= 3.0.2 (unknown)", cna.Constraint.String())
+	require.Len(t, cna.CPEs, 1)
+	assert.Equal(t, "cpe:2.3:a:acme_corp:widget_server:*:*:*:*:*:*:*:*", cna.CPEs[0].Attributes.BindToFmtString())
+
+	adp := r.Vulnerabilities[1]
+	assert.Equal(t, "< 2.4.1 (unknown)", adp.Constraint.String())
+	require.Len(t, adp.CPEs, 1)
+	assert.Equal(t, "cpe:2.3:a:acme:widget_server:*:*:*:*:*:*:*:*", adp.CPEs[0].Attributes.BindToFmtString())
+
+	assert.Equal(t, "CVE-2024-0001", r.Metadata.ID)
+	assert.Equal(t, "Critical", r.Metadata.Severity)
+	assert.Equal(t, "A remote code execution flaw in Widget Server.", r.Metadata.Description)
+	assert.Equal(t, []string{"https://acme.example/security/2024-0001"}, r.Metadata.URLs)
+	require.Len(t, r.Metadata.Cvss, 1)
+	assert.Equal(t, "CISA-ADP", r.Metadata.Cvss[0].Source)
+	assert.Equal(t, 9.8, r.Metadata.Cvss[0].Metrics.BaseScore)
+
+	r, ok = feed.Get("CVE-2024-0004")
+	require.True(t, ok)
+	require.Len(t, r.Vulnerabilities, 1)
+	assert.Equal(t, "= 1.0.0 || >= 1.2.0 (unknown)", r.Vulnerabilities[0].Constraint.String())
+}
+
+func TestFeed_nil(t *testing.T) {
+	var feed *Feed
+	assert.Zero(t, feed.Len())
+}
+
+func TestCveVersion_constraint(t *testing.T) {
+	tests := []struct {
+		name    string
+		version cveVersion
+		want    string
+		wantOK  bool
+	}{
+		{
+			name:    "exact version",
+			version: cveVersion{Version: "1.0.0", Status: "affected"},
+			want:    "= 1.0.0",
+			wantOK:  true,
+		},
+		{
+			name:    "open lower bound",
+			version: cveVersion{Version: "*", LessThan: "1.0.0", Status: "affected"},
+			want:    "< 1.0.0",
+			wantOK:  true,
+		},
+		{
+			name:    "bounded inclusive range",
+			version: cveVersion{Version: "1.0.0", LessThanOrEqual: "1.4.0", Status: "affected"},
+			want:    ">= 1.0.0, <= 1.4.0",
+			wantOK:  true,
+		},
+		{
+			name:    "open upper bound",
+			version: cveVersion{Version: "2.0", LessThan: "*", Status: "affected"},
+			want:    ">= 2.0",
+			wantOK:  true,
+		},
+		{
+			name:    "unaffected",
+			version: cveVersion{Version: "1.0.0", Status: "unaffected"},
+		},
+		{
+			name:    "unspecified version",
+			version: cveVersion{Version: "unspecified", Status: "affected"},
+		},
+		{
+			name:    "free-form text",
+			version: cveVersion{Version: "all versions before 1.2", Status: "affected"},
+		},
+		{
+			name:    "everything",
+			version: cveVersion{Version: "0", LessThan: "*", Status: "affected"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.version.constraint()
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package pending
+
+import (
+	"strings"
+	"sync"
+
+	cpeUtil "github.com/anchore/grype/grype/cpe"
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/cpe"
+)
+
+var _ interface {
+	vulnerability.Provider
+	vulnerability.MetadataProvider
+	store.DegradationReporter
+} = (*Provider)(nil)
+
+// Provider decorates an existing vulnerability provider, adding records from a pending analysis feed to CPE
+// searches. Feed records are only surfaced for CVEs that the underlying provider has no NVD analysis for, so once
+// NVD catches up the feed record is superseded without any change in configuration.
+type Provider struct {
+	vulnerability.Provider
+	metadata  vulnerability.MetadataProvider
+	feed      *Feed
+	byProduct map[string][]string
+
+	lock     sync.Mutex
+	analyzed map[string]bool
+}
+
+func NewProvider(provider vulnerability.Provider, metadata vulnerability.MetadataProvider, feed *Feed) *Provider {
+	byProduct := make(map[string][]string)
+	for _, id := range feed.IDs() {
+		r, _ := feed.Get(id)
+		seen := make(map[string]struct{})
+		for _, v := range r.Vulnerabilities {
+			for _, c := range v.CPEs {
+				product := strings.ToLower(c.Attributes.Product)
+				if _, ok := seen[product]; ok {
+					continue
+				}
+				seen[product] = struct{}{}
+				byProduct[product] = append(byProduct[product], id)
+			}
+		}
+	}
+
+	return &Provider{
+		Provider:  provider,
+		metadata:  metadata,
+		feed:      feed,
+		byProduct: byProduct,
+		analyzed:  make(map[string]bool),
+	}
+}
+
+// Apply returns a copy of the given store with the feed layered over its vulnerability and metadata providers. The
+// store is returned unchanged when the feed is empty.
+func Apply(s *store.Store, feed *Feed) *store.Store {
+	if s == nil || feed.Len() == 0 {
+		return s
+	}
+
+	log.WithFields("records", feed.Len()).Debug("using pending analysis feed")
+
+	p := NewProvider(s.Provider, s.MetadataProvider, feed)
+	return &store.Store{
+		Provider:          p,
+		MetadataProvider:  p,
+		ExclusionProvider: s.ExclusionProvider,
+		Targeting:         s.Targeting,
+	}
+}
+
+// Degradations returns the degradations of the decorated providers.
+func (p *Provider) Degradations() []grypeerr.DataQualityProblem {
+	return append(store.DegradationsOf(p.Provider), store.DegradationsOf(p.metadata)...)
+}
+
+func (p *Provider) Get(id, namespace string) ([]vulnerability.Vulnerability, error) {
+	if namespace == Namespace {
+		r, ok := p.feed.Get(id)
+		if !ok {
+			return nil, nil
+		}
+		return r.Vulnerabilities, nil
+	}
+	return p.Provider.Get(id, namespace)
+}
+
+func (p *Provider) GetByCPE(c cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	vulns, err := p.Provider.GetByCPE(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := p.byProduct[strings.ToLower(c.Attributes.Product)]
+	if len(ids) == 0 {
+		return vulns, nil
+	}
+
+	found := make(map[string]struct{})
+	for _, v := range vulns {
+		found[v.ID] = struct{}{}
+	}
+
+	for _, id := range ids {
+		if _, ok := found[id]; ok {
+			continue
+		}
+		if p.hasNVDAnalysis(id) {
+			continue
+		}
+
+		r, _ := p.feed.Get(id)
+		for _, v := range r.Vulnerabilities {
+			candidates := cpeUtil.MatchWithoutVersion(c, v.CPEs)
+			if len(candidates) == 0 {
+				continue
+			}
+			v.CPEs = candidates
+			vulns = append(vulns, v)
+		}
+	}
+
+	return vulns, nil
+}
+
+func (p *Provider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	if namespace == Namespace {
+		r, ok := p.feed.Get(id)
+		if !ok {
+			return nil, nil
+		}
+		m := r.Metadata
+		return &m, nil
+	}
+	if p.metadata == nil {
+		return nil, nil
+	}
+	return p.metadata.GetMetadata(id, namespace)
+}
+
+// hasNVDAnalysis indicates if the underlying provider already has CPE configurations from NVD for the given CVE.
+func (p *Provider) hasNVDAnalysis(id string) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if analyzed, ok := p.analyzed[id]; ok {
+		return analyzed
+	}
+
+	analyzed := false
+	vulns, err := p.Provider.Get(id, nvdNamespace)
+	if err != nil {
+		log.WithFields("id", id, "error", err).Trace("unable to check NVD analysis state")
+	}
+	for _, v := range vulns {
+		if len(v.CPEs) > 0 {
+			analyzed = true
+			break
+		}
+	}
+
+	p.analyzed[id] = analyzed
+	return analyzed
+}
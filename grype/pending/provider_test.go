@@ -0,0 +1,135 @@
+package pending
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
+)
+
+type mockProvider struct {
+	vulnerability.Provider
+	byCPE map[string][]vulnerability.Vulnerability
+	byID  map[string][]vulnerability.Vulnerability
+}
+
+func (pr *mockProvider) Get(id, namespace string) ([]vulnerability.Vulnerability, error) {
+	return pr.byID[namespace+"/"+id], nil
+}
+
+func (pr *mockProvider) GetByCPE(c cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	return pr.byCPE[c.Attributes.Product], nil
+}
+
+type mockMetadataProvider struct{}
+
+func (mockMetadataProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	return &vulnerability.Metadata{ID: id, Namespace: namespace}, nil
+}
+
+func TestProvider_GetByCPE(t *testing.T) {
+	feed, err := FromPath("test-fixtures/feed")
+	require.NoError(t, err)
+
+	widgetCPE := cpe.Must("cpe:2.3:a:acme:widget_server:*:*:*:*:*:*:*:*", "")
+
+	tests := []struct {
+		name    string
+		inner   *mockProvider
+		version string
+		wantIDs []string
+	}{
+		{
+			name:    "pending record matches by vendor and product",
+			inner:   &mockProvider{},
+			version: "2.4.0",
+			wantIDs: []string{"CVE-2024-0001"},
+		},
+		{
+			name:    "version outside of affected ranges",
+			inner:   &mockProvider{},
+			version: "2.4.1",
+		},
+		{
+			name: "record already analyzed by NVD is not duplicated",
+			inner: &mockProvider{
+				byID: map[string][]vulnerability.Vulnerability{
+					"nvd:cpe/CVE-2024-0001": {
+						{ID: "CVE-2024-0001", Namespace: "nvd:cpe", CPEs: []cpe.CPE{widgetCPE}},
+					},
+				},
+			},
+			version: "2.4.0",
+		},
+		{
+			name: "results from the underlying provider are kept",
+			inner: &mockProvider{
+				byCPE: map[string][]vulnerability.Vulnerability{
+					"widget_server": {
+						{
+							ID:         "CVE-2023-9999",
+							Namespace:  "nvd:cpe",
+							Constraint: version.MustGetConstraint("< 3.0.0", version.UnknownFormat),
+							CPEs:       []cpe.CPE{widgetCPE},
+						},
+					},
+				},
+			},
+			version: "2.4.0",
+			wantIDs: []string{"CVE-2023-9999", "CVE-2024-0001"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Apply(&store.Store{Provider: tt.inner, MetadataProvider: mockMetadataProvider{}}, feed)
+
+			p := pkg.Package{
+				ID:      pkg.ID(uuid.NewString()),
+				Name:    "widget-server",
+				Version: tt.version,
+				CPEs:    []cpe.CPE{cpe.Must("cpe:2.3:a:acme:widget_server:"+tt.version+":*:*:*:*:*:*:*", cpe.GeneratedSource)},
+			}
+
+			matches, err := search.ByPackageCPE(s, nil, p, match.StockMatcher)
+			require.NoError(t, err)
+
+			var ids []string
+			for _, m := range matches {
+				ids = append(ids, m.Vulnerability.ID)
+			}
+			assert.ElementsMatch(t, tt.wantIDs, ids)
+		})
+	}
+}
+
+func TestProvider_GetMetadata(t *testing.T) {
+	feed, err := FromPath("test-fixtures/feed")
+	require.NoError(t, err)
+
+	s := Apply(&store.Store{Provider: &mockProvider{}, MetadataProvider: mockMetadataProvider{}}, feed)
+
+	m, err := s.GetMetadata("CVE-2024-0001", Namespace)
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	assert.Equal(t, "Critical", m.Severity)
+
+	m, err = s.GetMetadata("CVE-2023-9999", "nvd:cpe")
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	assert.Equal(t, "nvd:cpe", m.Namespace, "non-feed lookups are delegated")
+}
+
+func TestApply_emptyFeed(t *testing.T) {
+	s := &store.Store{Provider: &mockProvider{}}
+	assert.Same(t, s, Apply(s, nil))
+	assert.Same(t, s, Apply(s, NewFeed()))
+}
@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"strings"
+
+	"github.com/anchore/packageurl-go"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+// the names that distros and package URLs use for the same architecture, keyed by the name used by Grype
+var archAliases = map[string][]string{
+	"amd64":   {"x86_64", "x86-64", "x64"},
+	"386":     {"i386", "i486", "i586", "i686", "x86"},
+	"arm64":   {"aarch64", "arm64v8"},
+	"arm":     {"armhf", "armel", "armv6", "armv6l", "armv6hl", "armv7", "armv7l", "armv7hl", "arm32v6", "arm32v7", "armhfp"},
+	"ppc64le": {"ppc64el"},
+	"s390x":   {},
+	"riscv64": {},
+}
+
+// architecture independent packages, which are the same on every architecture
+var archIndependent = map[string]bool{"noarch": true, "all": true, "any": true}
+
+var canonicalArchs = func() map[string]string {
+	archs := make(map[string]string)
+	for arch, aliases := range archAliases {
+		archs[arch] = arch
+		for _, alias := range aliases {
+			archs[alias] = arch
+		}
+	}
+	return archs
+}()
+
+// NormalizeArch returns the name of the given architecture that does not depend on the ecosystem it was named by
+// (e.g. "x86_64" and "amd64" are both "amd64"). Empty is returned for architecture independent values such as
+// "noarch" or "all", and unknown architectures are returned lowercased.
+func NormalizeArch(arch string) string {
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	if archIndependent[arch] {
+		return ""
+	}
+	if canonical, ok := canonicalArchs[arch]; ok {
+		return canonical
+	}
+	return arch
+}
+
+// archFromPkg returns the architecture the package was built for, from the package metadata or else the "arch"
+// qualifier of the package URL.
+func archFromPkg(p syftPkg.Package) string {
+	var arch string
+	switch m := p.Metadata.(type) {
+	case syftPkg.RpmDBEntry:
+		arch = m.Arch
+	case syftPkg.RpmArchive:
+		arch = m.Arch
+	case syftPkg.DpkgDBEntry:
+		arch = m.Architecture
+	case syftPkg.ApkDBEntry:
+		arch = m.Architecture
+	case syftPkg.AlpmDBEntry:
+		arch = m.Architecture
+	case syftPkg.GolangBinaryBuildinfoEntry:
+		arch = m.Architecture
+	}
+	if arch == "" {
+		arch = archFromPURL(p.PURL)
+	}
+	return NormalizeArch(arch)
+}
+
+func archFromPURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	purl, err := packageurl.FromString(raw)
+	if err != nil {
+		return ""
+	}
+	return NormalizeArch(purl.Qualifiers.Map()["arch"])
+}
+
+// resolveArchitectures attributes the platform of the scanned image to the packages without an architecture of their
+// own (such as architecture independent packages), or removes the architectures of all packages when they should
+// not be considered, so that every advisory matches regardless of the architectures it is scoped to.
+func resolveArchitectures(packages []Package, ctx Context, ignore bool) []Package {
+	if ignore {
+		for i := range packages {
+			packages[i].Arch = ""
+		}
+		return packages
+	}
+
+	platform := platformArch(ctx)
+	if platform == "" {
+		return packages
+	}
+	log.WithFields("arch", platform).Trace("using the image architecture for packages without an architecture")
+	for i := range packages {
+		if packages[i].Arch == "" {
+			packages[i].Arch = platform
+		}
+	}
+	return packages
+}
+
+func platformArch(ctx Context) string {
+	if ctx.Source == nil {
+		return ""
+	}
+	metadata, ok := ctx.Source.Metadata.(source.ImageMetadata)
+	if !ok {
+		return ""
+	}
+	return NormalizeArch(metadata.Architecture)
+}
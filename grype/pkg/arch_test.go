@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestNormalizeArch(t *testing.T) {
+	tests := map[string]string{
+		"x86_64":  "amd64",
+		"amd64":   "amd64",
+		"AARCH64": "arm64",
+		"armhf":   "arm",
+		"i686":    "386",
+		"ppc64el": "ppc64le",
+		"s390x":   "s390x",
+		"noarch":  "",
+		"all":     "",
+		"":        "",
+		"mips64":  "mips64",
+	}
+	for arch, expected := range tests {
+		t.Run(arch, func(t *testing.T) {
+			assert.Equal(t, expected, NormalizeArch(arch))
+		})
+	}
+}
+
+func TestArchFromPkg(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkg      syftPkg.Package
+		expected string
+	}{
+		{
+			name:     "rpm",
+			pkg:      syftPkg.Package{Metadata: syftPkg.RpmDBEntry{Arch: "x86_64"}},
+			expected: "amd64",
+		},
+		{
+			name:     "deb",
+			pkg:      syftPkg.Package{Metadata: syftPkg.DpkgDBEntry{Architecture: "arm64"}},
+			expected: "arm64",
+		},
+		{
+			name:     "apk",
+			pkg:      syftPkg.Package{Metadata: syftPkg.ApkDBEntry{Architecture: "s390x"}},
+			expected: "s390x",
+		},
+		{
+			name:     "architecture independent",
+			pkg:      syftPkg.Package{Metadata: syftPkg.DpkgDBEntry{Architecture: "all"}, PURL: "pkg:deb/debian/tzdata@2024a-0+deb12u1?arch=all"},
+			expected: "",
+		},
+		{
+			name:     "purl qualifier",
+			pkg:      syftPkg.Package{PURL: "pkg:rpm/redhat/openssl@3.0.7-27.el9?arch=aarch64"},
+			expected: "arm64",
+		},
+		{
+			name:     "unknown",
+			pkg:      syftPkg.Package{PURL: "pkg:npm/lodash@4.17.21"},
+			expected: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, archFromPkg(test.pkg))
+		})
+	}
+}
+
+func TestResolveArchitectures(t *testing.T) {
+	ctx := Context{Source: &source.Description{Metadata: source.ImageMetadata{Architecture: "amd64"}}}
+	packages := func() []Package {
+		return []Package{{Name: "openssl", Arch: "arm64"}, {Name: "tzdata"}}
+	}
+
+	t.Run("image platform", func(t *testing.T) {
+		got := resolveArchitectures(packages(), ctx, false)
+		assert.Equal(t, "arm64", got[0].Arch)
+		assert.Equal(t, "amd64", got[1].Arch)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		got := resolveArchitectures(packages(), Context{}, false)
+		assert.Equal(t, "arm64", got[0].Arch)
+		assert.Equal(t, "", got[1].Arch)
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		got := resolveArchitectures(packages(), ctx, true)
+		assert.Equal(t, "", got[0].Arch)
+		assert.Equal(t, "", got[1].Arch)
+	})
+}
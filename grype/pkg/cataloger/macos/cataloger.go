@@ -0,0 +1,46 @@
+/*
+Package macos provides package catalogers for macOS package managers (Homebrew and MacPorts) which syft does not
+catalog itself, allowing grype to find vulnerabilities when scanning macOS directories such as developer laptops
+and CI runners.
+*/
+package macos
+
+import (
+	"github.com/anchore/syft/syft/cataloging/pkgcataloging"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger/generic"
+)
+
+const (
+	HomebrewCatalogerName = "homebrew-cellar-cataloger"
+	MacPortsCatalogerName = "macports-software-cataloger"
+)
+
+// catalogerTags select the catalogers for directory and installed sources only (like the other OS-specific
+// catalogers), since macOS is not shipped as container images.
+var catalogerTags = []string{"directory", "installed", "package", "macos"}
+
+// NewHomebrewCataloger returns a cataloger for formulae installed within a Homebrew Cellar.
+func NewHomebrewCataloger() pkg.Cataloger {
+	return generic.NewCataloger(HomebrewCatalogerName).
+		WithParserByGlobs(parseHomebrewReceipt, "**/Cellar/*/*/INSTALL_RECEIPT.json")
+}
+
+// NewMacPortsCataloger returns a cataloger for ports installed by MacPorts (as recorded in the MacPorts software
+// archive directory).
+func NewMacPortsCataloger() pkg.Cataloger {
+	var globs []string
+	for _, ext := range macPortsArchiveExtensions {
+		globs = append(globs, "**/macports/software/*/*."+ext)
+	}
+	return generic.NewCataloger(MacPortsCatalogerName).
+		WithParserByGlobs(parseMacPortsArchive, globs...)
+}
+
+// CatalogerReferences returns the references needed to add the macOS catalogers to a syft SBOM configuration.
+func CatalogerReferences() []pkgcataloging.CatalogerReference {
+	return []pkgcataloging.CatalogerReference{
+		pkgcataloging.NewCatalogerReference(NewHomebrewCataloger(), append([]string{"homebrew"}, catalogerTags...)),
+		pkgcataloging.NewCatalogerReference(NewMacPortsCataloger(), append([]string{"macports"}, catalogerTags...)),
+	}
+}
@@ -0,0 +1,112 @@
+package macos
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	grypePkg "github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+	"github.com/anchore/syft/syft/source/directorysource"
+)
+
+type expectedPackage struct {
+	name    string
+	version string
+	purl    string
+}
+
+func catalog(t *testing.T, cataloger pkg.Cataloger, fixture string) []pkg.Package {
+	t.Helper()
+
+	src, err := directorysource.NewFromPath(fixture)
+	require.NoError(t, err)
+
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	pkgs, _, err := cataloger.Catalog(context.Background(), resolver)
+	require.NoError(t, err)
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].Name < pkgs[j].Name
+	})
+	return pkgs
+}
+
+func TestHomebrewCataloger(t *testing.T) {
+	pkgs := catalog(t, NewHomebrewCataloger(), "test-fixtures/homebrew")
+
+	expected := []expectedPackage{
+		{name: "jq", version: "1.7.1", purl: "pkg:brew/jq@1.7.1"},
+		{name: "openssl@3", version: "3.1.4", purl: "pkg:brew/openssl%403@3.1.4"},
+	}
+
+	require.Len(t, pkgs, len(expected))
+	for i, e := range expected {
+		assert.Equal(t, e.name, pkgs[i].Name)
+		assert.Equal(t, e.version, pkgs[i].Version)
+		assert.Equal(t, e.purl, pkgs[i].PURL)
+		assert.Equal(t, grypePkg.HomebrewPkg, pkgs[i].Type)
+		assert.NotEmpty(t, pkgs[i].CPEs)
+		assert.NotEmpty(t, pkgs[i].ID())
+	}
+
+	for _, c := range pkgs[1].CPEs {
+		assert.Equal(t, "openssl", c.Attributes.Product, "versioned formula suffix should not be part of the CPE")
+	}
+}
+
+func TestMacPortsCataloger(t *testing.T) {
+	pkgs := catalog(t, NewMacPortsCataloger(), "test-fixtures/macports")
+
+	expected := []expectedPackage{
+		{name: "openssl3", version: "3.1.4", purl: "pkg:macports/openssl3@3.1.4"},
+		{name: "py311-requests", version: "2.31.0", purl: "pkg:macports/py311-requests@2.31.0"},
+	}
+
+	require.Len(t, pkgs, len(expected))
+	for i, e := range expected {
+		assert.Equal(t, e.name, pkgs[i].Name)
+		assert.Equal(t, e.version, pkgs[i].Version)
+		assert.Equal(t, e.purl, pkgs[i].PURL)
+		assert.Equal(t, grypePkg.MacPortsPkg, pkgs[i].Type)
+	}
+}
+
+func Test_parseMacPortsArchiveName(t *testing.T) {
+	tests := []struct {
+		archive string
+		name    string
+		version string
+		ok      bool
+	}{
+		{archive: "openssl3-3.1.4_0+universal.darwin_23.arm64.tbz2", name: "openssl3", version: "3.1.4", ok: true},
+		{archive: "curl-8.4.0_1+http2+ssl.darwin_22.x86_64.tbz2", name: "curl", version: "8.4.0", ok: true},
+		{archive: "py311-requests-2.31.0_0.darwin_23.noarch.tbz2", name: "py311-requests", version: "2.31.0", ok: true},
+		{archive: "not-an-archive.txt", ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.archive, func(t *testing.T) {
+			name, version, ok := parseMacPortsArchiveName(test.archive)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.name, name)
+			assert.Equal(t, test.version, version)
+		})
+	}
+}
+
+func TestCatalogerReferences(t *testing.T) {
+	refs := CatalogerReferences()
+	require.Len(t, refs, 2)
+	for _, ref := range refs {
+		assert.Contains(t, ref.Tags, "directory", ref.Cataloger.Name())
+		assert.Contains(t, ref.Tags, "installed", ref.Cataloger.Name())
+		assert.NotContains(t, ref.Tags, "image", "%s runs on container image scans", ref.Cataloger.Name())
+	}
+}
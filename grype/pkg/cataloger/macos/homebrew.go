@@ -0,0 +1,31 @@
+package macos
+
+import (
+	"context"
+	"path"
+	"regexp"
+
+	grypePkg "github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger/generic"
+)
+
+// homebrew appends a "_N" revision to the keg directory when a formula is rebuilt without an upstream version change
+var homebrewRevisionPattern = regexp.MustCompile(`_\d+$`)
+
+// parseHomebrewReceipt creates a package from the location of an INSTALL_RECEIPT.json within a keg
+// (<prefix>/Cellar/<formula>/<version>[_<revision>]/INSTALL_RECEIPT.json).
+func parseHomebrewReceipt(_ context.Context, _ file.Resolver, _ *generic.Environment, reader file.LocationReadCloser) ([]pkg.Package, []artifact.Relationship, error) {
+	kegDir := path.Dir(reader.Path())
+	name := path.Base(path.Dir(kegDir))
+	ver := homebrewRevisionPattern.ReplaceAllString(path.Base(kegDir), "")
+
+	if name == "" || ver == "" {
+		return nil, nil, nil
+	}
+
+	p := newPackage(name, ver, grypePkg.HomebrewPkg, "brew", reader.Location)
+	return []pkg.Package{p}, nil, nil
+}
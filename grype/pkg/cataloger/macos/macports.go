@@ -0,0 +1,50 @@
+package macos
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+
+	grypePkg "github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger/generic"
+)
+
+var macPortsArchiveExtensions = []string{"tbz2", "tbz", "tgz", "txz", "tlz", "tar", "xar", "zip", "cpgz", "cpio"}
+
+// MacPorts archive names take the form <port>-<version>_<revision>[+variant...].<platform>_<os-major>.<arch>.<ext>,
+// for example "openssl3-3.1.4_0+universal.darwin_23.arm64.tbz2"
+var macPortsArchivePattern = regexp.MustCompile(`^(?P<name>.+)-(?P<version>[^-_]+)_(?P<revision>\d+)(?P<variants>(\+[^.+]+)*)\.[^.]+\.[^.]+\.(?P<ext>[a-z0-9]+)$`)
+
+// parseMacPortsArchive creates a package from an installed port archive within the MacPorts software directory
+// (<prefix>/var/macports/software/<port>/<archive>).
+func parseMacPortsArchive(_ context.Context, _ file.Resolver, _ *generic.Environment, reader file.LocationReadCloser) ([]pkg.Package, []artifact.Relationship, error) {
+	name, ver, ok := parseMacPortsArchiveName(path.Base(reader.Path()))
+	if !ok {
+		return nil, nil, nil
+	}
+
+	// the archive directory is named after the port, which guards against mis-parsing names containing dashes
+	if portDir := path.Base(path.Dir(reader.Path())); !strings.EqualFold(portDir, name) {
+		return nil, nil, nil
+	}
+
+	p := newPackage(name, ver, grypePkg.MacPortsPkg, "macports", reader.Location)
+	return []pkg.Package{p}, nil, nil
+}
+
+func parseMacPortsArchiveName(archive string) (string, string, bool) {
+	match := macPortsArchivePattern.FindStringSubmatch(archive)
+	if match == nil {
+		return "", "", false
+	}
+	name := match[macPortsArchivePattern.SubexpIndex("name")]
+	ver := match[macPortsArchivePattern.SubexpIndex("version")]
+	if name == "" || ver == "" {
+		return "", "", false
+	}
+	return name, ver, true
+}
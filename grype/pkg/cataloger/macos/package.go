@@ -0,0 +1,27 @@
+package macos
+
+import (
+	"strings"
+
+	"github.com/anchore/packageurl-go"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+	cpes "github.com/anchore/syft/syft/pkg/cataloger/common/cpe"
+)
+
+func newPackage(name, version string, ty pkg.Type, purlType string, location file.Location) pkg.Package {
+	p := pkg.Package{
+		Name:      name,
+		Version:   version,
+		Type:      ty,
+		PURL:      packageurl.NewPackageURL(purlType, "", name, version, nil, "").ToString(),
+		Locations: file.NewLocationSet(location.WithAnnotation(pkg.EvidenceAnnotationKey, pkg.PrimaryEvidenceAnnotation)),
+	}
+	// there is no vulnerability feed for these ecosystems, so matching is done against NVD by CPE. Versioned
+	// formulae (e.g. "openssl@3" or "python@3.12") are named after the upstream project without the version suffix.
+	cpeCandidate := p
+	cpeCandidate.Name, _, _ = strings.Cut(name, "@")
+	p.CPEs = cpes.Generate(cpeCandidate)
+	p.SetID()
+	return p
+}
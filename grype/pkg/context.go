@@ -8,4 +8,7 @@ import (
 type Context struct {
 	Source *source.Description
 	Distro *linux.Release
+	// DistroInferredFrom tells the evidence the distro was inferred from when scanning an SBOM that does not record
+	// its distro (see DistroFromOSRelease and DistroFromPURLs), and is empty otherwise.
+	DistroInferredFrom string
 }
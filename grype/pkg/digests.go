@@ -0,0 +1,70 @@
+package pkg
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// digestsFromPkg returns the digests of the artifact of the package (the archive, tarball or wheel it was installed
+// from) that the catalog knows of, with hex encoded values: the digests of java archives, the integrity and hashes
+// recorded by lockfiles (npm, yarn, Pipfile, Cargo, composer, mix and rebar) and the hashes of .NET dependencies.
+func digestsFromPkg(p pkg.Package) []Digest {
+	var digests []Digest
+	switch m := p.Metadata.(type) {
+	case pkg.JavaArchive:
+		for _, d := range m.ArchiveDigests {
+			digests = appendDigest(digests, d.Algorithm, d.Value)
+		}
+	case pkg.NpmPackageLockEntry:
+		digests = subresourceIntegrityDigests(m.Integrity)
+	case pkg.YarnLockEntry:
+		digests = subresourceIntegrityDigests(m.Integrity)
+	case pkg.DotnetDepsEntry:
+		digests = subresourceIntegrityDigests(m.Sha512)
+	case pkg.PythonPipfileLockEntry:
+		for _, h := range m.Hashes {
+			if algorithm, value, ok := strings.Cut(h, ":"); ok {
+				digests = appendDigest(digests, algorithm, value)
+			}
+		}
+	case pkg.RustCargoLockEntry:
+		digests = appendDigest(digests, "sha256", m.Checksum)
+	case pkg.PhpComposerLockEntry:
+		digests = appendDigest(digests, "sha1", m.Dist.Shasum)
+	case pkg.ElixirMixLockEntry:
+		digests = appendDigest(digests, "sha256", m.PkgHash)
+	case pkg.ErlangRebarLockEntry:
+		digests = appendDigest(digests, "sha256", m.PkgHash)
+	}
+	return digests
+}
+
+// subresourceIntegrityDigests returns the digests of a subresource integrity value (e.g. "sha512-<base64>"), which may
+// list several digests separated by spaces.
+func subresourceIntegrityDigests(integrity string) []Digest {
+	var digests []Digest
+	for _, field := range strings.Fields(integrity) {
+		algorithm, value, ok := strings.Cut(field, "-")
+		if !ok {
+			continue
+		}
+		// options may follow the digest (e.g. "sha512-<base64>?foo")
+		value, _, _ = strings.Cut(value, "?")
+		raw, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		digests = appendDigest(digests, algorithm, hex.EncodeToString(raw))
+	}
+	return digests
+}
+
+func appendDigest(digests []Digest, algorithm, value string) []Digest {
+	if algorithm == "" || value == "" {
+		return digests
+	}
+	return append(digests, Digest{Algorithm: strings.ToLower(algorithm), Value: strings.ToLower(value)})
+}
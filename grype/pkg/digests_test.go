@@ -0,0 +1,55 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+func Test_digestsFromPkg(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata any
+		expected []Digest
+	}{
+		{
+			name:     "java archive",
+			metadata: pkg.JavaArchive{ArchiveDigests: []file.Digest{{Algorithm: "SHA1", Value: "A94A8FE5CCB19BA61C4C0873D391E987982FBBD3"}}},
+			expected: []Digest{{Algorithm: "sha1", Value: "a94a8fe5ccb19ba61c4c0873d391e987982fbbd3"}},
+		},
+		{
+			name:     "npm integrity",
+			metadata: pkg.NpmPackageLockEntry{Integrity: "sha1-qUqP5cyxm6YcTAhz05Hph5gvu9M= sha512-7iaw3Ur350mqGo7jwQrpkj9hiYB3Lkc/iBml1JQODbJ6wYX4oOHV+E+IvIh/1nsUNzLDBMxfqa2Ob1f1ACio/w=="},
+			expected: []Digest{
+				{Algorithm: "sha1", Value: "a94a8fe5ccb19ba61c4c0873d391e987982fbbd3"},
+				{Algorithm: "sha512", Value: "ee26b0dd4af7e749aa1a8ee3c10ae9923f618980772e473f8819a5d4940e0db27ac185f8a0e1d5f84f88bc887fd67b143732c304cc5fa9ad8e6f57f50028a8ff"},
+			},
+		},
+		{
+			name:     "invalid integrity",
+			metadata: pkg.YarnLockEntry{Integrity: "sha512-not base64!"},
+		},
+		{
+			name:     "pipfile hashes",
+			metadata: pkg.PythonPipfileLockEntry{Hashes: []string{"sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}},
+			expected: []Digest{{Algorithm: "sha256", Value: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}},
+		},
+		{
+			name:     "cargo checksum",
+			metadata: pkg.RustCargoLockEntry{Checksum: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"},
+			expected: []Digest{{Algorithm: "sha256", Value: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}},
+		},
+		{
+			name:     "no digests",
+			metadata: pkg.NpmPackage{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, digestsFromPkg(pkg.Package{Metadata: tt.metadata}))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package pkg
+
+import "fmt"
+
+// Enricher amends a package before it is matched, for instance to correct a wrongly detected version, add CPEs, or
+// match the package against another distro than the one of the scan target (see Package.Distro). The context is the
+// one of the scan target.
+type Enricher interface {
+	Enrich(p *Package, context Context) error
+}
+
+// EnricherFunc is a function amending packages before they are matched.
+type EnricherFunc func(p *Package, context Context) error
+
+func (f EnricherFunc) Enrich(p *Package, context Context) error {
+	return f(p, context)
+}
+
+// Enrich returns the packages as amended by each enricher in turn, without modifying the given packages.
+func Enrich(packages []Package, context Context, enrichers ...Enricher) ([]Package, error) {
+	if len(enrichers) == 0 {
+		return packages, nil
+	}
+
+	out := make([]Package, 0, len(packages))
+	for _, p := range packages {
+		enriched := p
+		// the slices are shared with the given package, so must be copied before enrichers can change them
+		enriched.CPEs = append(enriched.CPEs[:0:0], p.CPEs...)
+		enriched.Upstreams = append(enriched.Upstreams[:0:0], p.Upstreams...)
+		enriched.Licenses = append(enriched.Licenses[:0:0], p.Licenses...)
+		for _, e := range enrichers {
+			if err := e.Enrich(&enriched, context); err != nil {
+				return nil, fmt.Errorf("unable to enrich package %s@%s: %w", p.Name, p.Version, err)
+			}
+		}
+		out = append(out, enriched)
+	}
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/cpe"
+	"github.com/anchore/syft/syft/linux"
+)
+
+func TestEnrich(t *testing.T) {
+	packages := []Package{
+		{
+			Name:    "openssl",
+			Version: "1.1.1",
+			CPEs:    []cpe.CPE{cpe.Must("cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*", "")},
+		},
+		{
+			Name:    "zlib",
+			Version: "v1.2.13",
+		},
+	}
+	context := Context{Distro: &linux.Release{ID: "alpine", VersionID: "3.20"}}
+
+	var seen []*linux.Release
+	enriched, err := Enrich(packages, context,
+		EnricherFunc(func(p *Package, context Context) error {
+			seen = append(seen, context.Distro)
+			if p.Name == "openssl" {
+				p.CPEs[0] = cpe.Must("cpe:2.3:a:openssl_project:openssl:1.1.1:*:*:*:*:*:*:*", "")
+				p.CPEs = append(p.CPEs, cpe.Must("cpe:2.3:a:example:openssl:1.1.1:*:*:*:*:*:*:*", ""))
+			}
+			return nil
+		}),
+		EnricherFunc(func(p *Package, _ Context) error {
+			if p.Name == "zlib" {
+				p.Version = "1.2.13"
+				p.Distro = &linux.Release{ID: "debian", VersionID: "12"}
+			}
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*linux.Release{context.Distro, context.Distro}, seen)
+	require.Len(t, enriched, 2)
+	assert.Equal(t, []string{"cpe:2.3:a:openssl_project:openssl:1.1.1:*:*:*:*:*:*:*", "cpe:2.3:a:example:openssl:1.1.1:*:*:*:*:*:*:*"}, cpeStrings(enriched[0].CPEs))
+	assert.Equal(t, "1.2.13", enriched[1].Version)
+	assert.Equal(t, "debian", enriched[1].Distro.ID)
+
+	// the given packages are left as they were
+	assert.Equal(t, []string{"cpe:2.3:a:openssl:openssl:1.1.1:*:*:*:*:*:*:*"}, cpeStrings(packages[0].CPEs))
+	assert.Equal(t, "v1.2.13", packages[1].Version)
+	assert.Nil(t, packages[1].Distro)
+
+	_, err = Enrich(packages, context, EnricherFunc(func(*Package, Context) error {
+		return errors.New("unavailable")
+	}))
+	assert.ErrorContains(t, err, "unable to enrich package openssl@1.1.1: unavailable")
+}
+
+func cpeStrings(cpes []cpe.CPE) []string {
+	var out []string
+	for _, c := range cpes {
+		out = append(out, c.Attributes.BindToFmtString())
+	}
+	return out
+}
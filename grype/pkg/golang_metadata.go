@@ -13,4 +13,5 @@ type GolangBinMetadata struct {
 
 type GolangModMetadata struct {
 	H1Digest string `json:"h1Digest,omitempty"`
+	Module   string `json:"module,omitempty"` // the path of the module whose go.mod requires the package
 }
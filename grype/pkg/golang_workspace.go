@@ -0,0 +1,214 @@
+package pkg
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/anchore/packageurl-go"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	cpes "github.com/anchore/syft/syft/pkg/cataloger/common/cpe"
+	"github.com/anchore/syft/syft/source"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+// goWorkspace is a go.work file along with the modules it uses
+type goWorkspace struct {
+	dir     string            // the directory of the go.work file (relative to the source root)
+	modules map[string]string // module directory (relative to the source root) -> module path
+	replace []*modfile.Replace
+}
+
+// resolveGoModules resolves the Go module packages cataloged from the go.mod files of a directory source the same way
+// the go command does: each package is attributed to the module whose go.mod requires it, the replace directives of
+// the go.work file of a workspace are applied to all modules of the workspace, and requirements that resolve to
+// local code (the other modules of the workspace, or a replacement with a local directory) are removed since they
+// are part of the scanned source rather than dependencies on a published version.
+func resolveGoModules(packages []Package, ctx Context) []Package {
+	root, ok := directoryRoot(ctx)
+	if !ok {
+		return packages
+	}
+
+	modulePaths := make(map[string]string)
+	workspaces := make(map[string]*goWorkspace)
+	var out []Package
+	for _, p := range packages {
+		goMod := goModLocation(p)
+		if goMod == "" {
+			out = append(out, p)
+			continue
+		}
+		moduleDir := path.Dir(goMod)
+
+		modulePath, ok := modulePaths[moduleDir]
+		if !ok {
+			modulePath = readModulePath(root, moduleDir)
+			modulePaths[moduleDir] = modulePath
+		}
+
+		if modfile.IsDirectoryPath(p.Name) {
+			// a replace directive of the go.mod with a local directory
+			log.WithFields("module", modulePath, "package", p.Name).Trace("ignoring local Go module replacement")
+			continue
+		}
+
+		if ws := findGoWorkspace(root, moduleDir, workspaces); ws != nil {
+			if _, ok := ws.modules[moduleDir]; ok {
+				var keep bool
+				if p, keep = ws.resolve(p); !keep {
+					continue
+				}
+			}
+		}
+
+		if modulePath != "" {
+			metadata, _ := p.Metadata.(GolangModMetadata)
+			metadata.Module = modulePath
+			p.Metadata = metadata
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// resolve applies the workspace to a requirement of one of its modules, returning false when the requirement
+// resolves to local code.
+func (w *goWorkspace) resolve(p Package) (Package, bool) {
+	for _, modulePath := range w.modules {
+		if p.Name == modulePath {
+			log.WithFields("workspace", path.Join(w.dir, "go.work"), "package", p.Name).Trace("ignoring Go workspace module requirement")
+			return p, false
+		}
+	}
+
+	for _, r := range w.replace {
+		if r.Old.Path != p.Name || (r.Old.Version != "" && r.Old.Version != p.Version) {
+			continue
+		}
+		if modfile.IsDirectoryPath(r.New.Path) {
+			log.WithFields("workspace", path.Join(w.dir, "go.work"), "package", p.Name).Trace("ignoring local Go module replacement")
+			return p, false
+		}
+		return replaceGoModule(p, r.New.Path, r.New.Version), true
+	}
+	return p, true
+}
+
+func replaceGoModule(p Package, name, version string) Package {
+	p.Name, p.Version = name, version
+	p.PURL = goModulePURL(name, version)
+	p.CPEs = cpes.Generate(syftPkg.Package{
+		Name:     name,
+		Version:  version,
+		Type:     syftPkg.GoModulePkg,
+		Language: syftPkg.Go,
+		PURL:     p.PURL,
+	})
+	if metadata, ok := p.Metadata.(GolangModMetadata); ok {
+		// the digest in go.sum was of the replaced module
+		metadata.H1Digest = ""
+		p.Metadata = metadata
+	}
+	return p
+}
+
+// findGoWorkspace returns the workspace that applies to the module in the given directory: the nearest go.work file
+// in the module directory or one of its parents within the source.
+func findGoWorkspace(root, moduleDir string, cache map[string]*goWorkspace) *goWorkspace {
+	var visited []string
+	var found *goWorkspace
+	for dir := moduleDir; ; dir = path.Dir(dir) {
+		if ws, ok := cache[dir]; ok {
+			found = ws
+			break
+		}
+		visited = append(visited, dir)
+		if ws := readGoWorkspace(root, dir); ws != nil {
+			found = ws
+			break
+		}
+		if dir == "/" || dir == "." {
+			break
+		}
+	}
+	for _, dir := range visited {
+		cache[dir] = found
+	}
+	return found
+}
+
+func readGoWorkspace(root, dir string) *goWorkspace {
+	workPath := path.Join(dir, "go.work")
+	contents, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(workPath)))
+	if err != nil {
+		return nil
+	}
+	f, err := modfile.ParseWork(workPath, contents, nil)
+	if err != nil {
+		log.WithFields("path", workPath).Debugf("unable to parse go.work: %v", err)
+		return nil
+	}
+
+	ws := &goWorkspace{dir: dir, modules: make(map[string]string), replace: f.Replace}
+	for _, use := range f.Use {
+		moduleDir := path.Clean(path.Join(dir, filepath.ToSlash(use.Path)))
+		ws.modules[moduleDir] = readModulePath(root, moduleDir)
+	}
+	log.WithFields("path", workPath, "modules", len(ws.modules)).Debug("found Go workspace")
+	return ws
+}
+
+func readModulePath(root, moduleDir string) string {
+	contents, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path.Join(moduleDir, "go.mod"))))
+	if err != nil {
+		return ""
+	}
+	return modfile.ModulePath(contents)
+}
+
+// goModLocation returns the path of the go.mod file the package was cataloged from, if any
+func goModLocation(p Package) string {
+	if p.Type != syftPkg.GoModulePkg {
+		return ""
+	}
+	for _, l := range p.Locations.ToSlice() {
+		if path.Base(l.RealPath) == "go.mod" {
+			return l.RealPath
+		}
+	}
+	return ""
+}
+
+func directoryRoot(ctx Context) (string, bool) {
+	if ctx.Source == nil {
+		return "", false
+	}
+	metadata, ok := ctx.Source.Metadata.(source.DirectoryMetadata)
+	if !ok {
+		return "", false
+	}
+	if metadata.Base != "" {
+		return metadata.Base, true
+	}
+	return metadata.Path, true
+}
+
+func goModulePURL(name, version string) string {
+	fields := strings.Split(name, "/")
+	var namespace, subpath string
+	switch len(fields) {
+	case 1:
+		name = fields[0]
+	case 2:
+		namespace, name = fields[0], fields[1]
+	default:
+		namespace, name = strings.Join(fields[:2], "/"), fields[2]
+		subpath = strings.Join(fields[3:], "/")
+	}
+	return packageurl.NewPackageURL(packageurl.TypeGolang, namespace, name, version, nil, subpath).ToString()
+}
@@ -0,0 +1,55 @@
+package pkg
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft"
+)
+
+func Test_resolveGoModules(t *testing.T) {
+	config := ProviderConfig{SyftProviderConfig: SyftProviderConfig{SBOMOptions: syft.DefaultCreateSBOMConfig()}}
+	packages, _, _, err := Provide("dir:test-fixtures/go-workspace", config)
+	require.NoError(t, err)
+
+	var got []string
+	for _, p := range packages {
+		metadata, ok := p.Metadata.(GolangModMetadata)
+		require.True(t, ok, "unexpected metadata for %s", p.Name)
+		got = append(got, metadata.Module+" -> "+p.Name+"@"+p.Version+" "+p.PURL)
+	}
+	sort.Strings(got)
+
+	assert.Equal(t, []string{
+		// the workspace module requirement (example.com/lib) and the local replacements are resolved to the source
+		"example.com/app -> golang.org/x/net@v0.23.0 pkg:golang/golang.org/x/net@v0.23.0",
+		"example.com/lib -> golang.org/x/net@v0.23.0 pkg:golang/golang.org/x/net@v0.23.0",
+		// modules outside of the workspace are not affected by it
+		"example.com/tools -> golang.org/x/net@v0.17.0 pkg:golang/golang.org/x/net@v0.17.0",
+	}, got)
+}
+
+func Test_resolveGoModules_notDirectory(t *testing.T) {
+	packages := []Package{{Name: "../local", Type: "go-module"}}
+	assert.Equal(t, packages, resolveGoModules(packages, Context{}))
+}
+
+func Test_goModulePURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{name: "golang.org/x/net", version: "v0.23.0", want: "pkg:golang/golang.org/x/net@v0.23.0"},
+		{name: "github.com/aws/aws-sdk-go-v2/service/s3", version: "v1.2.0", want: "pkg:golang/github.com/aws/aws-sdk-go-v2@v1.2.0#service/s3"},
+		{name: "gopkg.in/yaml.v3", version: "", want: "pkg:golang/gopkg.in/yaml.v3"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, goModulePURL(test.name, test.version))
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/stereoscope"
+	"github.com/anchore/stereoscope/pkg/image"
+)
+
+// AllPlatforms selects every platform of an image index.
+const AllPlatforms = "all"
+
+// ImageIndex is an image in a registry that references an image for each of several platforms.
+type ImageIndex struct {
+	// Reference is the grype input that refers to the index in the registry (e.g. "registry:alpine:3.20").
+	Reference string
+	// Platforms are the platforms of the images within the index (e.g. "linux/arm64/v8"), in the order of the index.
+	Platforms []string
+}
+
+// ResolveImageIndex looks up the platforms of the image index that the user input refers to, which must be an image
+// in a registry (either with the "registry" scheme or without a scheme).
+func ResolveImageIndex(userInput string, registryOptions *image.RegistryOptions) (*ImageIndex, error) {
+	scheme, ref := stereoscope.ExtractSchemeSource(userInput, allSourceTags()...)
+	if scheme != "" && scheme != "registry" {
+		return nil, fmt.Errorf("scanning several platforms requires an image in a registry, not a %q source", scheme)
+	}
+	if scheme == "" {
+		ref = userInput
+	}
+
+	if registryOptions == nil {
+		registryOptions = &image.RegistryOptions{}
+	}
+	var nameOptions []name.Option
+	if registryOptions.InsecureUseHTTP {
+		nameOptions = append(nameOptions, name.Insecure)
+	}
+	parsed, err := name.ParseReference(ref, nameOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse image reference %q: %w", ref, err)
+	}
+
+	desc, err := remote.Get(parsed, remoteOptions(parsed, *registryOptions)...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get image %q from the registry: %w", ref, err)
+	}
+	if !desc.MediaType.IsIndex() {
+		return nil, fmt.Errorf("%q is not a multi-platform image index (media type %s)", ref, desc.MediaType)
+	}
+	index, err := desc.ImageIndex()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read image index %q: %w", ref, err)
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read image index %q: %w", ref, err)
+	}
+
+	return &ImageIndex{
+		Reference: "registry:" + ref,
+		Platforms: indexPlatforms(manifest),
+	}, nil
+}
+
+// Select returns the platforms of the index among the selected platforms, or all platforms when the selection is
+// AllPlatforms. Each selected platform must be within the index.
+func (i ImageIndex) Select(selected []string) ([]string, error) {
+	for _, s := range selected {
+		if s == AllPlatforms {
+			return i.Platforms, nil
+		}
+	}
+
+	var platforms []string
+	for _, s := range selected {
+		want, err := image.NewPlatform(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid platform %q: %w", s, err)
+		}
+		found := ""
+		for _, p := range i.Platforms {
+			have, err := image.NewPlatform(p)
+			if err == nil && have.OS == want.OS && have.Architecture == want.Architecture && (want.Variant == "" || have.Variant == want.Variant) {
+				found = p
+				break
+			}
+		}
+		if found == "" {
+			return nil, fmt.Errorf("platform %q is not in the image index (platforms: %s)", s, strings.Join(i.Platforms, ", "))
+		}
+		platforms = append(platforms, found)
+	}
+	return platforms, nil
+}
+
+func indexPlatforms(manifest *v1.IndexManifest) []string {
+	var platforms []string
+	seen := make(map[string]bool)
+	for _, m := range manifest.Manifests {
+		p := m.Platform
+		// attestation manifests are listed with an unknown platform
+		if p == nil || p.OS == "" || p.OS == "unknown" || p.Architecture == "unknown" {
+			continue
+		}
+		platform := p.OS + "/" + p.Architecture
+		if p.Variant != "" {
+			platform += "/" + p.Variant
+		}
+		if !seen[platform] {
+			seen[platform] = true
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms
+}
+
+func remoteOptions(ref name.Reference, registryOptions image.RegistryOptions) []remote.Option {
+	options := []remote.Option{remote.WithContext(context.Background())}
+
+	registryName := ref.Context().RegistryStr()
+	// the same credentials are used as when pulling the images
+	switch authenticator := registryOptions.Authenticator(registryName); {
+	case authenticator != nil:
+		options = append(options, remote.WithAuth(authenticator))
+	case registryOptions.Keychain != nil:
+		options = append(options, remote.WithAuthFromKeychain(registryOptions.Keychain))
+	default:
+		options = append(options, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	}
+
+	tlsConfig, err := registryOptions.TLSConfig(registryName)
+	if err != nil {
+		log.WithFields("error", err).Warn("unable to configure TLS transport")
+	} else if tlsConfig != nil {
+		transport := remote.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		options = append(options, remote.WithTransport(transport))
+	}
+	return options
+}
@@ -0,0 +1,110 @@
+package pkg
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/stereoscope/pkg/image"
+)
+
+func TestResolveImageIndex(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	var index v1.ImageIndex = empty.Index
+	for _, p := range []v1.Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		// attestations are not a platform
+		{OS: "unknown", Architecture: "unknown"},
+	} {
+		img, err := random.Image(64, 1)
+		require.NoError(t, err)
+		platform := p
+		index = mutate.AppendManifests(index, mutate.IndexAddendum{Add: img, Descriptor: v1.Descriptor{Platform: &platform}})
+	}
+	ref := u.Host + "/app:1.0"
+	indexRef, err := name.ParseReference(ref)
+	require.NoError(t, err)
+	require.NoError(t, remote.WriteIndex(indexRef, index))
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	single := u.Host + "/single:1.0"
+	singleRef, err := name.ParseReference(single)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(singleRef, img))
+
+	options := &image.RegistryOptions{InsecureUseHTTP: true}
+
+	resolved, err := ResolveImageIndex("registry:"+ref, options)
+	require.NoError(t, err)
+	assert.Equal(t, &ImageIndex{Reference: "registry:" + ref, Platforms: []string{"linux/amd64", "linux/arm64/v8"}}, resolved)
+
+	// inputs without a scheme are looked up in the registry
+	resolved, err = ResolveImageIndex(ref, options)
+	require.NoError(t, err)
+	assert.Equal(t, "registry:"+ref, resolved.Reference)
+
+	_, err = ResolveImageIndex(single, options)
+	assert.ErrorContains(t, err, "is not a multi-platform image index")
+
+	_, err = ResolveImageIndex("dir:.", options)
+	assert.ErrorContains(t, err, "requires an image in a registry")
+}
+
+func TestImageIndex_Select(t *testing.T) {
+	index := ImageIndex{Platforms: []string{"linux/amd64", "linux/arm64/v8", "linux/arm/v7"}}
+
+	tests := []struct {
+		name     string
+		selected []string
+		expected []string
+		wantErr  string
+	}{
+		{
+			name:     "all",
+			selected: []string{AllPlatforms},
+			expected: index.Platforms,
+		},
+		{
+			name:     "subset",
+			selected: []string{"linux/arm64", "amd64"},
+			expected: []string{"linux/arm64/v8", "linux/amd64"},
+		},
+		{
+			name:     "variant",
+			selected: []string{"linux/arm/v7"},
+			expected: []string{"linux/arm/v7"},
+		},
+		{
+			name:     "missing",
+			selected: []string{"linux/s390x"},
+			wantErr:  `platform "linux/s390x" is not in the image index`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platforms, err := index.Select(tt.selected)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, platforms)
+		})
+	}
+}
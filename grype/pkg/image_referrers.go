@@ -0,0 +1,341 @@
+package pkg
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/anchore/grype/internal/fips"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/stereoscope"
+)
+
+// ReferrerKind is the kind of document attached to an image.
+type ReferrerKind string
+
+const (
+	SBOMReferrer ReferrerKind = "sbom"
+	VEXReferrer  ReferrerKind = "vex"
+)
+
+// maxIndexedBlobSize is the largest blob read while walking the indexes and manifests of a layout tarball. Larger
+// blobs (only ever layers) are read on demand.
+const maxIndexedBlobSize = 4 << 20
+
+// ReferrerDocument is an SBOM or VEX document attached to an image as an OCI referrer, that is a layer of an artifact
+// manifest whose subject is the image.
+type ReferrerDocument struct {
+	Kind ReferrerKind
+	// Referrer is the digest of the artifact manifest.
+	Referrer string
+	// Subject is the digest of the image (or image index) that the document is attached to.
+	Subject      string
+	ArtifactType string
+	MediaType    string
+	Contents     []byte
+}
+
+// FindReferrers returns the SBOM and VEX documents attached to the images within the OCI image layout that the user
+// input refers to, which is either a layout directory or a tarball of a layout (as written by "docker save" since
+// Docker 25). No documents are returned for any other input.
+func FindReferrers(userInput string) ([]ReferrerDocument, error) {
+	layout := openLayout(userInput)
+	if layout == nil {
+		return nil, nil
+	}
+	defer layout.close()
+
+	index, err := layout.read("index.json")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the OCI layout index: %w", err)
+	}
+	w := referrerWalker{layout: layout, seen: make(map[string]bool), subjects: make(map[string]bool)}
+	if err := w.walkIndex(index, 0); err != nil {
+		return nil, err
+	}
+
+	var docs []ReferrerDocument
+	for _, r := range w.referrers {
+		// referrers of referrers (e.g. the signature of an SBOM) are not attached to the image itself
+		if !w.subjects[r.subject] {
+			continue
+		}
+		for _, layer := range r.manifest.Layers {
+			kind, ok := referrerKind(string(layer.MediaType))
+			if !ok {
+				kind, ok = referrerKind(r.artifactType)
+			}
+			if !ok {
+				continue
+			}
+			contents, err := layout.readBlob(layer.Digest)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read referrer %s: %w", r.digest, err)
+			}
+			docs = append(docs, ReferrerDocument{
+				Kind:         kind,
+				Referrer:     r.digest,
+				Subject:      r.subject,
+				ArtifactType: r.artifactType,
+				MediaType:    string(layer.MediaType),
+				Contents:     contents,
+			})
+		}
+	}
+	return docs, nil
+}
+
+// referrerKind classifies a media type or artifact type (e.g. "application/spdx+json", "application/vnd.openvex+json").
+// Of the VEX formats only OpenVEX is supported.
+func referrerKind(mediaType string) (ReferrerKind, bool) {
+	mediaType = strings.ToLower(mediaType)
+	switch {
+	case strings.Contains(mediaType, "spdx"), strings.Contains(mediaType, "cyclonedx"), strings.Contains(mediaType, "syft"):
+		return SBOMReferrer, true
+	case strings.Contains(mediaType, "openvex"):
+		return VEXReferrer, true
+	}
+	return "", false
+}
+
+type referrer struct {
+	digest       string
+	subject      string
+	artifactType string
+	manifest     *v1.Manifest
+}
+
+// referrerWalker walks the indexes and manifests of a layout, collecting the referrers along with the digests of the
+// images and indexes that are not referrers themselves.
+type referrerWalker struct {
+	layout    layoutReader
+	seen      map[string]bool
+	subjects  map[string]bool
+	referrers []referrer
+}
+
+func (w *referrerWalker) walkIndex(contents []byte, depth int) error {
+	// image indexes may nest (e.g. an index of platforms within the layout index), but not endlessly
+	if depth > 4 {
+		return nil
+	}
+	var index v1.IndexManifest
+	if err := json.Unmarshal(contents, &index); err != nil {
+		return fmt.Errorf("unable to parse image index: %w", err)
+	}
+
+	for _, desc := range index.Manifests {
+		digest := desc.Digest.String()
+		if w.seen[digest] {
+			continue
+		}
+		w.seen[digest] = true
+
+		blob, err := w.layout.readBlob(desc.Digest)
+		if err != nil {
+			// layouts may omit the blobs of platforms that were not copied
+			log.WithFields("digest", digest, "error", err).Trace("skipping missing manifest within the OCI layout")
+			continue
+		}
+
+		switch {
+		case desc.MediaType.IsIndex():
+			w.subjects[digest] = true
+			if err := w.walkIndex(blob, depth+1); err != nil {
+				return err
+			}
+		case desc.MediaType.IsImage() || desc.MediaType == types.OCIManifestSchema1:
+			var manifest v1.Manifest
+			if err := json.Unmarshal(blob, &manifest); err != nil {
+				return fmt.Errorf("unable to parse image manifest %s: %w", digest, err)
+			}
+			if manifest.Subject == nil {
+				w.subjects[digest] = true
+				continue
+			}
+			artifactType := desc.ArtifactType
+			if artifactType == "" {
+				artifactType = string(manifest.Config.MediaType)
+			}
+			w.referrers = append(w.referrers, referrer{
+				digest:       digest,
+				subject:      manifest.Subject.Digest.String(),
+				artifactType: artifactType,
+				manifest:     &manifest,
+			})
+		}
+	}
+	return nil
+}
+
+type layoutReader interface {
+	read(name string) ([]byte, error)
+	readBlob(digest v1.Hash) ([]byte, error)
+	close()
+}
+
+// openLayout opens the OCI image layout that the user input refers to, or returns nil if the input is not a layout.
+func openLayout(userInput string) layoutReader {
+	scheme, location := stereoscope.ExtractSchemeSource(userInput, allSourceTags()...)
+	switch scheme {
+	case "oci-dir":
+		return &layoutDir{dir: location}
+	case "oci-archive", "docker-archive":
+		return openLayoutTar(location)
+	case "":
+		if _, err := os.Stat(filepath.Join(userInput, "oci-layout")); err == nil {
+			return &layoutDir{dir: userInput}
+		}
+		if info, err := os.Stat(userInput); err == nil && info.Mode().IsRegular() {
+			return openLayoutTar(userInput)
+		}
+	}
+	return nil
+}
+
+type layoutDir struct {
+	dir string
+}
+
+func (l *layoutDir) read(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(l.dir, filepath.FromSlash(name)))
+}
+
+func (l *layoutDir) readBlob(digest v1.Hash) ([]byte, error) {
+	return readVerified(l, digest)
+}
+
+func (l *layoutDir) close() {}
+
+// layoutTar is an OCI image layout within a tarball. Since tarballs can only be read sequentially, the small blobs
+// are kept from a first pass over the tarball, and any larger blob is read with another pass.
+type layoutTar struct {
+	file  *os.File
+	small map[string][]byte
+	sizes map[string]int64
+}
+
+// openLayoutTar opens the tarball as an OCI image layout, returning nil if the tarball is not a layout (e.g. a
+// "docker save" archive from before Docker 25). Any tarball that cannot be read is left for the image source to report.
+func openLayoutTar(location string) layoutReader {
+	f, err := os.Open(location)
+	if err != nil {
+		log.WithFields("path", location, "error", err).Trace("not looking for referrers")
+		return nil
+	}
+	l := &layoutTar{file: f, small: make(map[string][]byte), sizes: make(map[string]int64)}
+
+	err = l.scan(func(name string, header *tar.Header, r io.Reader) error {
+		l.sizes[name] = header.Size
+		if header.Size > maxIndexedBlobSize {
+			return nil
+		}
+		contents, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		l.small[name] = contents
+		return nil
+	})
+	if err == nil && l.sizes["index.json"] == 0 {
+		err = errors.New("the archive is not an OCI image layout")
+	}
+	if err != nil {
+		l.close()
+		log.WithFields("path", location, "error", err).Trace("not looking for referrers")
+		return nil
+	}
+	return l
+}
+
+func (l *layoutTar) scan(fn func(name string, header *tar.Header, r io.Reader) error) error {
+	if _, err := l.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	reader := tar.NewReader(l.file)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(path.Clean(strings.TrimPrefix(header.Name, "./")), header, reader); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *layoutTar) read(name string) ([]byte, error) {
+	if contents, ok := l.small[name]; ok {
+		return contents, nil
+	}
+	if _, ok := l.sizes[name]; !ok {
+		return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+
+	var contents []byte
+	errFound := errors.New("found")
+	err := l.scan(func(n string, _ *tar.Header, r io.Reader) error {
+		if n != name {
+			return nil
+		}
+		var err error
+		if contents, err = io.ReadAll(r); err != nil {
+			return err
+		}
+		return errFound
+	})
+	if !errors.Is(err, errFound) {
+		if err == nil {
+			err = fmt.Errorf("%s: %w", name, os.ErrNotExist)
+		}
+		return nil, err
+	}
+	return contents, nil
+}
+
+func (l *layoutTar) readBlob(digest v1.Hash) ([]byte, error) {
+	return readVerified(l, digest)
+}
+
+func (l *layoutTar) close() {
+	_ = l.file.Close()
+}
+
+// readVerified reads the blob of the digest from the layout, verifying its contents against sha256 digests.
+func readVerified(l layoutReader, digest v1.Hash) ([]byte, error) {
+	// the digest is part of the path, so must not be able to point outside the blobs directory
+	if digest.Algorithm == "" || strings.ContainsAny(digest.Algorithm+digest.Hex, `/\.`) {
+		return nil, fmt.Errorf("invalid digest %q", digest)
+	}
+	if err := fips.CheckAlgorithm(digest.Algorithm, "verify blob "+digest.String()); err != nil {
+		return nil, err
+	}
+	contents, err := l.read(path.Join("blobs", digest.Algorithm, digest.Hex))
+	if err != nil {
+		return nil, err
+	}
+	if digest.Algorithm == "sha256" {
+		sum := sha256.Sum256(contents)
+		if hex.EncodeToString(sum[:]) != digest.Hex {
+			return nil, fmt.Errorf("the contents of blob %s do not match its digest", digest)
+		}
+	}
+	return contents, nil
+}
@@ -0,0 +1,147 @@
+package pkg
+
+import (
+	"archive/tar"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindReferrers(t *testing.T) {
+	dir := t.TempDir()
+	l, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, l.AppendImage(img))
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+
+	sbom := appendReferrer(t, l, img, "application/spdx+json", []byte(`{"spdxVersion":"SPDX-2.3"}`))
+	// the artifact type is used when the layer media type is not specific
+	vex := appendReferrer(t, l, img, "application/vnd.openvex+json", []byte(`{"@context":"https://openvex.dev/ns"}`))
+	// neither an SBOM nor a VEX document
+	appendReferrer(t, l, img, "application/vnd.dev.cosign.simplesigning.v1+json", []byte(`{}`))
+	// the signature of the SBOM is not attached to the image
+	appendReferrer(t, l, sbom, "application/spdx+json", []byte(`{"spdxVersion":"SPDX-2.2"}`))
+
+	sbomDigest, err := sbom.Digest()
+	require.NoError(t, err)
+	vexDigest, err := vex.Digest()
+	require.NoError(t, err)
+	expected := []ReferrerDocument{
+		{
+			Kind:         SBOMReferrer,
+			Referrer:     sbomDigest.String(),
+			Subject:      imgDigest.String(),
+			ArtifactType: "application/spdx+json",
+			MediaType:    "application/spdx+json",
+			Contents:     []byte(`{"spdxVersion":"SPDX-2.3"}`),
+		},
+		{
+			Kind:         VEXReferrer,
+			Referrer:     vexDigest.String(),
+			Subject:      imgDigest.String(),
+			ArtifactType: "application/vnd.openvex+json",
+			MediaType:    "application/json",
+			Contents:     []byte(`{"@context":"https://openvex.dev/ns"}`),
+		},
+	}
+
+	archive := filepath.Join(t.TempDir(), "image.tar")
+	writeTar(t, dir, archive)
+
+	for _, input := range []string{"oci-dir:" + dir, dir, "oci-archive:" + archive, "docker-archive:" + archive, archive} {
+		t.Run(input, func(t *testing.T) {
+			docs, err := FindReferrers(input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, docs)
+		})
+	}
+
+	t.Run("not a layout", func(t *testing.T) {
+		for _, input := range []string{"dir:" + dir, "registry:alpine:3.20", "test-fixtures/syft-spring.json"} {
+			docs, err := FindReferrers(input)
+			require.NoError(t, err)
+			assert.Empty(t, docs, input)
+		}
+	})
+}
+
+func TestFindReferrers_corruptBlob(t *testing.T) {
+	dir := t.TempDir()
+	l, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, l.AppendImage(img))
+	sbom := appendReferrer(t, l, img, "application/spdx+json", []byte(`{"spdxVersion":"SPDX-2.3"}`))
+
+	manifest, err := sbom.Manifest()
+	require.NoError(t, err)
+	blob := manifest.Layers[0].Digest
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "blobs", blob.Algorithm, blob.Hex), []byte(`{}`), 0o600))
+
+	_, err = FindReferrers("oci-dir:" + dir)
+	assert.ErrorContains(t, err, "do not match its digest")
+}
+
+// appendReferrer adds an artifact to the layout with the contents as its only layer, attached to the subject.
+func appendReferrer(t *testing.T, l layout.Path, subject partial.Describable, mediaType string, contents []byte) v1.Image {
+	t.Helper()
+	desc, err := partial.Descriptor(subject)
+	require.NoError(t, err)
+
+	artifact := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	artifact = mutate.ConfigMediaType(artifact, types.MediaType(mediaType))
+	layerMediaType := types.MediaType(mediaType)
+	if mediaType == "application/vnd.openvex+json" {
+		layerMediaType = "application/json"
+	}
+	artifact, err = mutate.Append(artifact, mutate.Addendum{Layer: static.NewLayer(contents, layerMediaType)})
+	require.NoError(t, err)
+	artifact = mutate.Subject(artifact, *desc).(v1.Image)
+	require.NoError(t, l.AppendImage(artifact))
+	return artifact
+}
+
+func writeTar(t *testing.T, dir, archive string) {
+	t.Helper()
+	f, err := os.Create(archive)
+	require.NoError(t, err)
+	defer f.Close()
+	w := tar.NewWriter(f)
+	defer w.Close()
+
+	require.NoError(t, filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if err := w.WriteHeader(&tar.Header{Name: filepath.ToSlash(rel), Mode: 0o600, Size: int64(len(contents)), Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		_, err = w.Write(contents)
+		return err
+	}))
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"os"
+	"strings"
+
+	"github.com/anchore/stereoscope"
+)
+
+// InputKind is the kind of input packages are provided from.
+type InputKind string
+
+const (
+	// SBOMInput is an SBOM document, read from a file or from stdin.
+	SBOMInput InputKind = "sbom"
+	// PURLInput is a file of purls.
+	PURLInput InputKind = "purl"
+	// ImageInput is a container image cataloged by syft, from a daemon, a registry or an archive.
+	ImageInput InputKind = "image"
+	// DirectoryInput is a directory cataloged by syft.
+	DirectoryInput InputKind = "directory"
+	// FileInput is a single file cataloged by syft.
+	FileInput InputKind = "file"
+	// RuntimeInput is an interpreter environment (e.g. a Python site-packages directory), whose libraries are cataloged
+	// by syft along with its interpreter.
+	RuntimeInput InputKind = "runtime"
+)
+
+// Input describes how the packages of a user input are provided, without reading any packages.
+type Input struct {
+	Kind InputKind
+	// Source is the syft source explicitly selected by the scheme of the input (e.g. "registry" or "oci-dir"), if any,
+	// or the scheme of the runtime environment (e.g. "python-env").
+	Source string
+	// Reference is the input without its scheme, which is empty when reading from stdin.
+	Reference string
+}
+
+// ResolveInput tells how Provide would provide the packages of the given input, following the same order: SBOMs, then
+// purl files, then the sources cataloged by syft. Inputs that are not local files or directories are taken as images,
+// as syft does.
+func ResolveInput(userInput string) Input {
+	switch {
+	case userInput == "":
+		return Input{Kind: SBOMInput}
+	case explicitlySpecifyingSBOM(userInput):
+		return Input{Kind: SBOMInput, Reference: strings.TrimPrefix(userInput, "sbom:")}
+	case isPossibleSBOM(userInput):
+		return Input{Kind: SBOMInput, Reference: userInput}
+	case explicitlySpecifyingPurl(userInput):
+		return Input{Kind: PURLInput, Reference: strings.TrimPrefix(userInput, purlInputPrefix)}
+	}
+	if env, dir, ok := explicitlySpecifyingRuntimeEnvironment(userInput); ok {
+		return Input{Kind: RuntimeInput, Source: env.scheme, Reference: dir}
+	}
+
+	schemeSource, reference := stereoscope.ExtractSchemeSource(userInput, allSourceTags()...)
+	if schemeSource != "" {
+		return Input{Kind: sourceInputKind(schemeSource), Source: schemeSource, Reference: reference}
+	}
+
+	kind := ImageInput
+	if info, err := os.Stat(userInput); err == nil {
+		// image archives and OCI layouts are files and directories as well, which syft tells apart by their contents
+		kind = FileInput
+		if info.IsDir() {
+			kind = DirectoryInput
+		}
+	}
+	return Input{Kind: kind, Reference: userInput}
+}
+
+func sourceInputKind(schemeSource string) InputKind {
+	switch schemeSource {
+	case "dir", "local-directory":
+		return DirectoryInput
+	case "file", "local-file":
+		return FileInput
+	default:
+		return ImageInput
+	}
+}
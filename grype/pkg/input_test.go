@@ -0,0 +1,64 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveInput(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Input
+	}{
+		{
+			input:    "",
+			expected: Input{Kind: SBOMInput},
+		},
+		{
+			input:    "sbom:test-fixtures/syft-spring.json",
+			expected: Input{Kind: SBOMInput, Reference: "test-fixtures/syft-spring.json"},
+		},
+		{
+			input:    "test-fixtures/syft-spring.json",
+			expected: Input{Kind: SBOMInput, Reference: "test-fixtures/syft-spring.json"},
+		},
+		{
+			input:    "purl:test-fixtures/valid-purl.txt",
+			expected: Input{Kind: PURLInput, Reference: "test-fixtures/valid-purl.txt"},
+		},
+		{
+			input:    "test-fixtures/go-workspace",
+			expected: Input{Kind: DirectoryInput, Reference: "test-fixtures/go-workspace"},
+		},
+		{
+			input:    "dir:test-fixtures/go-workspace",
+			expected: Input{Kind: DirectoryInput, Source: "dir", Reference: "test-fixtures/go-workspace"},
+		},
+		{
+			input:    "python-env:test-fixtures/runtime-env/venv/",
+			expected: Input{Kind: RuntimeInput, Source: "python-env", Reference: "test-fixtures/runtime-env/venv"},
+		},
+		{
+			input:    "file:test-fixtures/cosign.pub",
+			expected: Input{Kind: FileInput, Source: "file", Reference: "test-fixtures/cosign.pub"},
+		},
+		{
+			input:    "registry:alpine:3.20",
+			expected: Input{Kind: ImageInput, Source: "registry", Reference: "alpine:3.20"},
+		},
+		{
+			input:    "oci-dir:test-fixtures/go-workspace",
+			expected: Input{Kind: ImageInput, Source: "oci-dir", Reference: "test-fixtures/go-workspace"},
+		},
+		{
+			input:    "alpine:3.20",
+			expected: Input{Kind: ImageInput, Reference: "alpine:3.20"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ResolveInput(tt.input))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package pkg
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/anchore/packageurl-go"
+	"github.com/anchore/syft/syft/pkg"
+	cpes "github.com/anchore/syft/syft/pkg/cataloger/common/cpe"
+)
+
+// protocols of npm, pnpm and yarn that reference code of the project itself rather than a published package
+var localNpmProtocols = []string{"link:", "workspace:", "file:", "portal:"}
+
+// npmWorkspaceMembers returns the packages that npm lockfiles link to a directory of the project (the members of npm
+// workspaces), keyed by lockfile and package name.
+func npmWorkspaceMembers(syftpkgs []pkg.Package) map[string]bool {
+	members := make(map[string]bool)
+	for _, p := range syftpkgs {
+		if m, ok := p.Metadata.(pkg.NpmPackageLockEntry); ok && isLocalNpmReference(m.Resolved) {
+			members[npmLockfileKey(p)] = true
+		}
+	}
+	return members
+}
+
+func npmLockfileKey(p pkg.Package) string {
+	var lockfile string
+	if locations := p.Locations.ToSlice(); len(locations) > 0 {
+		lockfile = locations[0].RealPath
+	}
+	return lockfile + ":" + p.Name
+}
+
+// resolveNpmPackage resolves the name and version of an npm package the way the lockfile it was found in does: aliased
+// packages (e.g. "string-width-cjs@npm:string-width@4.2.3") are the package they alias, and patched packages (with the
+// patch protocol of yarn or the patch hash of pnpm) are the version they patch. False is returned for packages
+// that are part of the project itself, such as the members of a workspace, which have no published versions to
+// match against.
+func resolveNpmPackage(p pkg.Package, workspaceMembers map[string]bool) (pkg.Package, bool) {
+	name, version := p.Name, p.Version
+
+	switch m := p.Metadata.(type) {
+	case pkg.NpmPackageLockEntry:
+		if isLocalNpmReference(m.Resolved) || workspaceMembers[npmLockfileKey(p)] {
+			return p, false
+		}
+	case pkg.YarnLockEntry:
+		resolvedName, resolvedVersion, local, ok := parseYarnResolution(m.Resolved)
+		if local {
+			return p, false
+		}
+		if ok {
+			name, version = resolvedName, resolvedVersion
+		}
+	}
+
+	name, version, local := parseNpmVersion(name, version)
+	if local {
+		return p, false
+	}
+	if name == p.Name && version == p.Version {
+		return p, true
+	}
+
+	p.Name, p.Version = name, version
+	p.PURL = npmPURL(name, version)
+	if len(p.CPEs) > 0 {
+		// the CPEs were generated for the alias
+		p.CPEs = cpes.Generate(p)
+	}
+	return p, true
+}
+
+// parseNpmVersion resolves the version specifications that lockfiles record for aliased and patched packages:
+// "npm:<name>@<version>" (npm), "/<name>@<version>" or "<name>@<version>" (pnpm 6+), "/<name>/<version>" (pnpm 5), and
+// "<version>(patch_hash=...)" (pnpm patches and peer dependencies).
+func parseNpmVersion(name, version string) (string, string, bool) {
+	for _, protocol := range localNpmProtocols {
+		if strings.HasPrefix(version, protocol) {
+			return name, version, true
+		}
+	}
+
+	spec := strings.TrimPrefix(version, "npm:")
+	spec, _, _ = strings.Cut(spec, "(")
+
+	if i := strings.LastIndex(spec, "@"); i > 0 {
+		// a version cannot contain "@", so this is the package an alias refers to
+		return strings.TrimPrefix(spec[:i], "/"), spec[i+1:], false
+	}
+	if strings.HasPrefix(spec, "/") {
+		if i := strings.LastIndex(spec, "/"); i > 0 {
+			return spec[1:i], spec[i+1:], false
+		}
+	}
+	return name, spec, false
+}
+
+// parseYarnResolution parses the resolution of a yarn berry lockfile entry (e.g. "string-width@npm:4.2.3" or
+// "resolve@patch:resolve@npm%3A1.22.8#~builtin<compat/resolve>::version=1.22.8&hash=c3c19d"). Resolutions of classic
+// yarn lockfiles (registry URLs) and of other protocols are not parsed.
+func parseYarnResolution(resolution string) (name, version string, local, ok bool) {
+	if len(resolution) < 2 || strings.Contains(resolution, "://") {
+		return "", "", false, false
+	}
+	i := strings.Index(resolution[1:], "@") + 1
+	if i == 0 {
+		return "", "", false, false
+	}
+	name, ref := resolution[:i], resolution[i+1:]
+
+	for _, protocol := range localNpmProtocols {
+		if strings.HasPrefix(ref, protocol) {
+			return name, "", true, true
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(ref, "npm:"):
+		return name, strings.TrimPrefix(ref, "npm:"), false, true
+	case strings.HasPrefix(ref, "patch:"):
+		// the patched resolution is escaped, followed by the patch
+		patched, _, _ := strings.Cut(strings.TrimPrefix(ref, "patch:"), "#")
+		unescaped, err := url.PathUnescape(patched)
+		if err != nil {
+			return "", "", false, false
+		}
+		return parseYarnResolution(unescaped)
+	}
+	return "", "", false, false
+}
+
+// isLocalNpmReference returns true if the resolved field of a package-lock entry references a directory of the
+// project (e.g. "packages/lib" for a workspace member) instead of a registry, git or tarball URL
+func isLocalNpmReference(resolved string) bool {
+	for _, protocol := range localNpmProtocols {
+		if strings.HasPrefix(resolved, protocol) {
+			return true
+		}
+	}
+	return resolved != "" && !strings.Contains(resolved, ":")
+}
+
+func npmPURL(name, version string) string {
+	var namespace string
+	if fields := strings.SplitN(name, "/", 2); len(fields) > 1 {
+		namespace, name = fields[0], fields[1]
+	}
+	return packageurl.NewPackageURL(packageurl.TypeNPM, namespace, name, version, nil, "").ToString()
+}
@@ -0,0 +1,162 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+func Test_resolveNpmPackage(t *testing.T) {
+	tests := []struct {
+		name        string
+		pkg         pkg.Package
+		wantName    string
+		wantVersion string
+		wantPURL    string
+		wantLocal   bool
+	}{
+		{
+			name:        "regular package",
+			pkg:         pkg.Package{Name: "lodash", Version: "4.17.21", PURL: "pkg:npm/lodash@4.17.21"},
+			wantName:    "lodash",
+			wantVersion: "4.17.21",
+			wantPURL:    "pkg:npm/lodash@4.17.21",
+		},
+		{
+			name:        "npm alias",
+			pkg:         pkg.Package{Name: "string-width-cjs", Version: "npm:string-width@4.2.3"},
+			wantName:    "string-width",
+			wantVersion: "4.2.3",
+			wantPURL:    "pkg:npm/string-width@4.2.3",
+		},
+		{
+			name:        "scoped npm alias",
+			pkg:         pkg.Package{Name: "babel-core", Version: "npm:@babel/core@7.23.0"},
+			wantName:    "@babel/core",
+			wantVersion: "7.23.0",
+			wantPURL:    "pkg:npm/%40babel/core@7.23.0",
+		},
+		{
+			name:        "pnpm 6 alias",
+			pkg:         pkg.Package{Name: "string-width-cjs", Version: "/string-width@4.2.3"},
+			wantName:    "string-width",
+			wantVersion: "4.2.3",
+			wantPURL:    "pkg:npm/string-width@4.2.3",
+		},
+		{
+			name:        "pnpm 9 alias",
+			pkg:         pkg.Package{Name: "string-width-cjs", Version: "string-width@4.2.3"},
+			wantName:    "string-width",
+			wantVersion: "4.2.3",
+			wantPURL:    "pkg:npm/string-width@4.2.3",
+		},
+		{
+			name:        "pnpm 5 alias",
+			pkg:         pkg.Package{Name: "string-width-cjs", Version: "/string-width/4.2.3"},
+			wantName:    "string-width",
+			wantVersion: "4.2.3",
+			wantPURL:    "pkg:npm/string-width@4.2.3",
+		},
+		{
+			name:        "pnpm patch",
+			pkg:         pkg.Package{Name: "lodash", Version: "4.17.21(patch_hash=2vqgqgsrzhkxz6rblu6w2xgweu)"},
+			wantName:    "lodash",
+			wantVersion: "4.17.21",
+			wantPURL:    "pkg:npm/lodash@4.17.21",
+		},
+		{
+			name: "yarn berry alias",
+			pkg: pkg.Package{Name: "string-width-cjs", Version: "4.2.3", Metadata: pkg.YarnLockEntry{
+				Resolved: "string-width@npm:4.2.3",
+			}},
+			wantName:    "string-width",
+			wantVersion: "4.2.3",
+			wantPURL:    "pkg:npm/string-width@4.2.3",
+		},
+		{
+			name: "yarn berry patch",
+			pkg: pkg.Package{Name: "resolve", Version: "1.22.8", PURL: "pkg:npm/resolve@1.22.8", Metadata: pkg.YarnLockEntry{
+				Resolved: "resolve@patch:resolve@npm%3A1.22.8#~builtin<compat/resolve>::version=1.22.8&hash=c3c19d",
+			}},
+			wantName:    "resolve",
+			wantVersion: "1.22.8",
+			wantPURL:    "pkg:npm/resolve@1.22.8",
+		},
+		{
+			name: "yarn classic",
+			pkg: pkg.Package{Name: "async", Version: "3.2.3", PURL: "pkg:npm/async@3.2.3", Metadata: pkg.YarnLockEntry{
+				Resolved: "https://registry.yarnpkg.com/async/-/async-3.2.3.tgz#ac53dafd3f4720ee9e8a160628f18ea91df196c9",
+			}},
+			wantName:    "async",
+			wantVersion: "3.2.3",
+			wantPURL:    "pkg:npm/async@3.2.3",
+		},
+		{
+			name:      "yarn berry workspace",
+			pkg:       pkg.Package{Name: "@my/lib", Version: "0.0.0-use.local", Metadata: pkg.YarnLockEntry{Resolved: "@my/lib@workspace:packages/lib"}},
+			wantLocal: true,
+		},
+		{
+			name:      "pnpm workspace link",
+			pkg:       pkg.Package{Name: "@my/lib", Version: "link:../lib"},
+			wantLocal: true,
+		},
+		{
+			name:      "npm workspace link",
+			pkg:       pkg.Package{Name: "@my/lib", Metadata: pkg.NpmPackageLockEntry{Resolved: "packages/lib"}},
+			wantLocal: true,
+		},
+		{
+			name: "npm git dependency",
+			pkg: pkg.Package{Name: "left-pad", Version: "1.3.0", PURL: "pkg:npm/left-pad@1.3.0", Metadata: pkg.NpmPackageLockEntry{
+				Resolved: "git+ssh://git@github.com/left-pad/left-pad.git#5ddb1a1a",
+			}},
+			wantName:    "left-pad",
+			wantVersion: "1.3.0",
+			wantPURL:    "pkg:npm/left-pad@1.3.0",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, keep := resolveNpmPackage(test.pkg, nil)
+			assert.Equal(t, !test.wantLocal, keep)
+			if test.wantLocal {
+				return
+			}
+			assert.Equal(t, test.wantName, got.Name)
+			assert.Equal(t, test.wantVersion, got.Version)
+			assert.Equal(t, test.wantPURL, got.PURL)
+		})
+	}
+}
+
+func TestFromPackages_npmWorkspace(t *testing.T) {
+	lockfile := file.NewLocationSet(file.NewLocation("/package-lock.json"))
+	other := file.NewLocationSet(file.NewLocation("/other/package-lock.json"))
+	syftpkgs := []pkg.Package{
+		{Name: "app", Version: "1.0.0", Type: pkg.NpmPkg, Locations: lockfile, Metadata: pkg.NpmPackageLockEntry{}},
+		// the link of node_modules/@my/lib to the workspace member, and the member itself
+		{Name: "@my/lib", Type: pkg.NpmPkg, Locations: lockfile, Metadata: pkg.NpmPackageLockEntry{Resolved: "packages/lib"}},
+		{Name: "@my/lib", Version: "1.0.0", Type: pkg.NpmPkg, Locations: lockfile, Metadata: pkg.NpmPackageLockEntry{}},
+		{Name: "string-width-cjs", Version: "4.2.3", Type: pkg.NpmPkg, Locations: lockfile, Metadata: pkg.NpmPackageLockEntry{
+			Resolved: "https://registry.npmjs.org/string-width/-/string-width-4.2.3.tgz",
+		}},
+		// the published package of the same name in another project
+		{Name: "@my/lib", Version: "1.0.0", Type: pkg.NpmPkg, Locations: other, Metadata: pkg.NpmPackageLockEntry{
+			Resolved: "https://registry.npmjs.org/@my/lib/-/lib-1.0.0.tgz",
+		}},
+	}
+
+	var got []string
+	for _, p := range FromPackages(syftpkgs, SynthesisConfig{}) {
+		got = append(got, p.Locations.ToSlice()[0].RealPath+" "+p.Name+"@"+p.Version)
+	}
+	assert.Equal(t, []string{
+		"/package-lock.json app@1.0.0",
+		"/package-lock.json string-width-cjs@4.2.3",
+		"/other/package-lock.json @my/lib@1.0.0",
+	}, got)
+}
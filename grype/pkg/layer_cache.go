@@ -0,0 +1,456 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft"
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/format/syftjson"
+	"github.com/anchore/syft/syft/linux"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
+)
+
+// layerCacheVersion is the version of the entries of the layer cache, to bump when the way layers are cataloged changes.
+const layerCacheVersion = 2
+
+// LayerCache caches the packages cataloged from each layer of the scanned images, keyed by the chain of the layer (the
+// digests of the layer and of the layers below it, as the chain ID of OCI images), so that scanning an image sharing
+// layers with a previously scanned image (e.g. a new tag of the image) only catalogs the layers not yet cached. The
+// same layer on top of other layers is cached again, since what is cataloged from a layer may depend on the layers
+// below it.
+//
+// Each layer is cataloged on its own (each file is cataloged in the layer holding it, as with the all-layers scope),
+// and the packages of an image are those of its layers whose evidence is still visible in the squashed image: a package
+// whose evidence is overwritten or deleted by an upper layer is dropped. Since the layers are cataloged independently,
+// the packages whose evidence spans several layers may differ from those of a scan without the cache.
+type LayerCache struct {
+	// Dir is the directory of the cache, holding an entry per layer chain for each cataloging configuration.
+	Dir string
+	// MaxSize is the size (in bytes) the entries of the cache are kept within, by evicting the least recently used
+	// entries after each scan, or 0 to never evict entries.
+	MaxSize uint64
+}
+
+// createSBOM creates the SBOM of the image of the source from the cached catalogs of its layers, cataloging the layers
+// that are not cached (and caching them). Sources other than images are cataloged as usual.
+func (c LayerCache) createSBOM(ctx context.Context, src source.Source, cfg *syft.CreateSBOMConfig) (*sbom.SBOM, error) {
+	description := src.Describe()
+	metadata, ok := description.Metadata.(source.ImageMetadata)
+	if !ok || len(metadata.Layers) == 0 {
+		return syft.CreateSBOM(ctx, src, cfg)
+	}
+
+	dir, err := c.configDir(cfg)
+	if err != nil {
+		log.WithFields("error", err).Warn("unable to use the layer cache, cataloging the whole image")
+		return syft.CreateSBOM(ctx, src, cfg)
+	}
+
+	defer c.evict()
+
+	chains := layerChains(metadata.Layers)
+	layers := make(map[string]*sbom.SBOM)
+	missing := make(map[string]bool)
+	for _, l := range metadata.Layers {
+		s, err := readCachedLayer(layerCachePath(dir, chains[l.Digest]))
+		switch {
+		case err == nil:
+			layers[l.Digest] = s
+		case errors.Is(err, fs.ErrNotExist):
+			missing[l.Digest] = true
+		default:
+			log.WithFields("layer", l.Digest, "error", err).Warn("unable to read the cached packages of the layer, cataloging it again")
+			missing[l.Digest] = true
+		}
+	}
+	log.WithFields("cached", len(layers), "cataloged", len(missing)).Debug("cataloging the layers of the image")
+
+	var descriptor sbom.Descriptor
+	for _, s := range layers {
+		descriptor = s.Descriptor
+		break
+	}
+	if len(missing) > 0 {
+		s, err := syft.CreateSBOM(ctx, layersSource{Source: src, layers: missing}, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, errors.New("no SBOM provided")
+		}
+		descriptor = s.Descriptor
+		for digest, layer := range splitLayers(s, missing) {
+			layers[digest] = layer
+			if err := writeCachedLayer(layerCachePath(dir, chains[digest]), layer); err != nil {
+				log.WithFields("layer", digest, "error", err).Warn("unable to cache the packages of the layer")
+			}
+		}
+	}
+
+	squashed, err := src.FileResolver(source.SquashedScope)
+	if err != nil {
+		return nil, err
+	}
+	return combineLayers(metadata.Layers, layers, squashed, cfg.Search.Scope != source.AllLayersScope, description, descriptor), nil
+}
+
+// configDir returns the directory of the cache entries of the cataloging configuration: the catalog of a layer
+// depends on the catalogers and their configuration, as well as the version of syft.
+func (c LayerCache) configDir(cfg *syft.CreateSBOMConfig) (string, error) {
+	key, err := json.Marshal(struct {
+		Version            int
+		Syft               string
+		Compliance         any
+		Relationships      any
+		DataGeneration     any
+		Packages           any
+		CatalogerSelection any
+	}{
+		Version:            layerCacheVersion,
+		Syft:               syftModuleVersion(),
+		Compliance:         cfg.Compliance,
+		Relationships:      cfg.Relationships,
+		DataGeneration:     cfg.DataGeneration,
+		Packages:           cfg.Packages,
+		CatalogerSelection: cfg.CatalogerSelection,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to describe the cataloging configuration: %w", err)
+	}
+	sum := sha256.Sum256(key)
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:8])), nil
+}
+
+func syftModuleVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, d := range info.Deps {
+			if d.Path == "github.com/anchore/syft" {
+				return d.Version
+			}
+		}
+	}
+	return ""
+}
+
+// layerChains returns the chain of each layer by its digest: the digest of the bottom layer, and the digest of the
+// chain below and the digest of the layer for the others (as the chain IDs of OCI images). A layer found several times
+// in the image (e.g. an empty layer) has the chain of its lowest occurrence.
+func layerChains(layers []source.LayerMetadata) map[string]string {
+	chains := make(map[string]string)
+	var chain string
+	for _, l := range layers {
+		if chain == "" {
+			chain = l.Digest
+		} else {
+			sum := sha256.Sum256([]byte(chain + " " + l.Digest))
+			chain = "sha256:" + hex.EncodeToString(sum[:])
+		}
+		if _, ok := chains[l.Digest]; !ok {
+			chains[l.Digest] = chain
+		}
+	}
+	return chains
+}
+
+func layerCachePath(dir, chain string) string {
+	return filepath.Join(dir, strings.ReplaceAll(chain, ":", "-")+".json")
+}
+
+// readCachedLayer reads the entry of a layer, marking it as used (by its modification time) so that it is the last
+// to be evicted.
+func readCachedLayer(path string) (*sbom.SBOM, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		log.WithFields("path", path, "error", err).Trace("unable to mark the cached layer as used")
+	}
+	s, _, _, err := syftjson.NewFormatDecoder().Decode(bytes.NewReader(contents))
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// writeCachedLayer writes the entry of a layer through a temporary file, so that concurrent scans never read a
+// partial entry.
+func writeCachedLayer(path string, s *sbom.SBOM) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := syftjson.NewFormatEncoder().Encode(&buf, *s); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".layer-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// evict removes the least recently used entries of the cache (of any cataloging configuration) until the entries
+// are within the maximum size of the cache.
+func (c LayerCache) evict() {
+	if c.MaxSize == 0 {
+		return
+	}
+	type entry struct {
+		path string
+		size uint64
+		used time.Time
+	}
+	var entries []entry
+	var total uint64
+	err := filepath.WalkDir(c.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			// removed by a concurrent scan
+			return nil
+		}
+		entries = append(entries, entry{path: path, size: uint64(info.Size()), used: info.ModTime()})
+		total += uint64(info.Size())
+		return nil
+	})
+	if err != nil {
+		log.WithFields("dir", c.Dir, "error", err).Warn("unable to list the entries of the layer cache to evict")
+		return
+	}
+	if total <= c.MaxSize {
+		return
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].used.Before(entries[j].used)
+	})
+	var evicted int
+	for _, e := range entries {
+		if total <= c.MaxSize {
+			break
+		}
+		if err := os.Remove(e.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.WithFields("path", e.path, "error", err).Debug("unable to evict the cached layer")
+			continue
+		}
+		total -= e.size
+		evicted++
+	}
+	log.WithFields("evicted", evicted, "size", total).Debug("evicted the least recently used entries of the layer cache")
+}
+
+// splitLayers splits the SBOM of the cataloged layers into an SBOM per layer, of the packages whose evidence is in the
+// layer and of the relationships between them. Every layer has an SBOM, even when no package is found in it.
+func splitLayers(s *sbom.SBOM, layers map[string]bool) map[string]*sbom.SBOM {
+	packages := make(map[string][]syftPkg.Package)
+	byID := make(map[artifact.ID]string)
+	for _, p := range s.Artifacts.Packages.Sorted() {
+		layer := packageLayer(p)
+		if !layers[layer] {
+			continue
+		}
+		packages[layer] = append(packages[layer], p)
+		byID[p.ID()] = layer
+	}
+
+	relationships := make(map[string][]artifact.Relationship)
+	for _, r := range s.Relationships {
+		from, fromOK := byID[r.From.ID()]
+		to, toOK := byID[r.To.ID()]
+		if fromOK && toOK && from == to {
+			relationships[from] = append(relationships[from], r)
+		}
+	}
+
+	out := make(map[string]*sbom.SBOM)
+	for layer := range layers {
+		out[layer] = &sbom.SBOM{
+			Artifacts:     sbom.Artifacts{Packages: syftPkg.NewCollection(packages[layer]...)},
+			Relationships: relationships[layer],
+			Source:        s.Source,
+			Descriptor:    s.Descriptor,
+		}
+	}
+	return out
+}
+
+// combineLayers returns the SBOM of the image from the SBOMs of its layers, keeping only the packages whose evidence is
+// visible in the squashed image when squashed.
+func combineLayers(metadata []source.LayerMetadata, layers map[string]*sbom.SBOM, squashed file.Resolver, squash bool, description source.Description, descriptor sbom.Descriptor) *sbom.SBOM {
+	var packages []syftPkg.Package
+	ids := make(map[artifact.ID]bool)
+	for _, l := range metadata {
+		s := layers[l.Digest]
+		if s == nil {
+			continue
+		}
+		for _, p := range s.Artifacts.Packages.Sorted() {
+			if squash && !visibleInLayer(squashed, p, l.Digest) {
+				continue
+			}
+			packages = append(packages, p)
+			ids[p.ID()] = true
+		}
+	}
+
+	var relationships []artifact.Relationship
+	for _, l := range metadata {
+		if s := layers[l.Digest]; s != nil {
+			for _, r := range s.Relationships {
+				if ids[r.From.ID()] && ids[r.To.ID()] {
+					relationships = append(relationships, r)
+				}
+			}
+		}
+	}
+
+	return &sbom.SBOM{
+		Artifacts: sbom.Artifacts{
+			Packages:          syftPkg.NewCollection(packages...),
+			LinuxDistribution: linux.IdentifyRelease(squashed),
+		},
+		Relationships: relationships,
+		Source:        description,
+		Descriptor:    descriptor,
+	}
+}
+
+// evidenceLocations are the locations of the primary evidence of a package, or all of its locations when none is
+// annotated as primary.
+func evidenceLocations(set file.LocationSet) []file.Location {
+	var primary []file.Location
+	locations := set.ToSlice()
+	for _, l := range locations {
+		if l.Annotations[syftPkg.EvidenceAnnotationKey] == syftPkg.PrimaryEvidenceAnnotation {
+			primary = append(primary, l)
+		}
+	}
+	if len(primary) == 0 {
+		return locations
+	}
+	return primary
+}
+
+// packageLayer returns the digest of the layer holding the evidence of the package.
+func packageLayer(p syftPkg.Package) string {
+	for _, l := range evidenceLocations(p.Locations) {
+		if l.FileSystemID != "" {
+			return l.FileSystemID
+		}
+	}
+	return ""
+}
+
+// visibleInLayer tells whether the evidence of the package in the given layer is still the file seen at its path in the
+// squashed image (and so not overwritten or deleted by an upper layer).
+func visibleInLayer(squashed file.Resolver, p syftPkg.Package, layer string) bool {
+	for _, l := range evidenceLocations(p.Locations) {
+		if l.FileSystemID != layer {
+			continue
+		}
+		visible, err := squashed.FilesByPath(l.RealPath)
+		if err != nil {
+			log.WithFields("path", l.RealPath, "error", err).Trace("unable to resolve the evidence of a cached package")
+			continue
+		}
+		for _, v := range visible {
+			if v.FileSystemID == layer {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// layersSource is an image source whose files are those of the given layers only, each file being cataloged in the
+// layer holding it (regardless of the scope requested).
+type layersSource struct {
+	source.Source
+	layers map[string]bool
+}
+
+func (s layersSource) FileResolver(source.Scope) (file.Resolver, error) {
+	resolver, err := s.Source.FileResolver(source.AllLayersScope)
+	if err != nil {
+		return nil, err
+	}
+	return layersResolver{Resolver: resolver, layers: s.layers}, nil
+}
+
+// layersResolver only resolves the files of the given layers.
+type layersResolver struct {
+	file.Resolver
+	layers map[string]bool
+}
+
+func (r layersResolver) filter(locations []file.Location, err error) ([]file.Location, error) {
+	if err != nil {
+		return nil, err
+	}
+	var out []file.Location
+	for _, l := range locations {
+		if r.layers[l.FileSystemID] {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func (r layersResolver) FilesByPath(paths ...string) ([]file.Location, error) {
+	return r.filter(r.Resolver.FilesByPath(paths...))
+}
+
+func (r layersResolver) FilesByGlob(patterns ...string) ([]file.Location, error) {
+	return r.filter(r.Resolver.FilesByGlob(patterns...))
+}
+
+func (r layersResolver) FilesByMIMEType(types ...string) ([]file.Location, error) {
+	return r.filter(r.Resolver.FilesByMIMEType(types...))
+}
+
+func (r layersResolver) AllLocations(ctx context.Context) <-chan file.Location {
+	out := make(chan file.Location)
+	go func() {
+		defer close(out)
+		for l := range r.Resolver.AllLocations(ctx) {
+			if !r.layers[l.FileSystemID] {
+				continue
+			}
+			select {
+			case out <- l:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
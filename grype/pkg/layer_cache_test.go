@@ -0,0 +1,155 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft"
+	"github.com/anchore/syft/syft/source"
+)
+
+const testAPKDB = `P:musl
+V:1.2.4-r2
+A:x86_64
+o:musl
+F:lib
+R:ld-musl-x86_64.so.1
+
+P:busybox
+V:1.36.1-r5
+A:x86_64
+o:busybox
+F:bin
+R:busybox
+
+`
+
+func TestLayerCache(t *testing.T) {
+	base := testLayer(t, map[string][]byte{
+		"etc/os-release":       []byte("ID=alpine\nVERSION_ID=3.19.1\n"),
+		"lib/apk/db/installed": []byte(testAPKDB),
+	})
+	leftPad := testLayer(t, map[string][]byte{
+		"app/node_modules/left-pad/package.json": []byte(`{"name": "left-pad", "version": "1.3.0"}`),
+	})
+	lodash := testLayer(t, map[string][]byte{
+		"app/node_modules/lodash/package.json": []byte(`{"name": "lodash", "version": "4.17.20"}`),
+	})
+	// an upgrade of the OS packages overwrites the package database of the base layer
+	upgrade := testLayer(t, map[string][]byte{
+		"lib/apk/db/installed": []byte("P:musl\nV:1.2.4-r3\nA:x86_64\no:musl\nF:lib\nR:ld-musl-x86_64.so.1\n\n"),
+	})
+
+	cache := &LayerCache{Dir: t.TempDir()}
+	scan := func(layers ...v1.Layer) []string {
+		t.Helper()
+		cfg := ProviderConfig{SyftProviderConfig: SyftProviderConfig{SBOMOptions: syft.DefaultCreateSBOMConfig(), LayerCache: cache}}
+		packages, ctx, _, err := Provide("oci-dir:"+testImage(t, layers...), cfg)
+		require.NoError(t, err)
+		require.NotNil(t, ctx.Distro)
+		assert.Equal(t, "alpine", ctx.Distro.ID)
+		var names []string
+		for _, p := range packages {
+			names = append(names, p.Name+"@"+p.Version)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	assert.Equal(t, []string{"busybox@1.36.1-r5", "left-pad@1.3.0", "musl@1.2.4-r2"}, scan(base, leftPad))
+	entries := cacheEntries(t, cache.Dir)
+	assert.Len(t, entries, 2)
+
+	// the base layer is read from the cache: an entry that was altered shows that it is not cataloged again
+	baseDigest, err := base.DiffID()
+	require.NoError(t, err)
+	baseEntry := entries[baseDigest.String()]
+	require.NotEmpty(t, baseEntry)
+	cached, err := os.ReadFile(baseEntry)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(baseEntry, []byte(strings.ReplaceAll(string(cached), "busybox", "toybox")), 0o600))
+
+	assert.Equal(t, []string{"lodash@4.17.20", "musl@1.2.4-r2", "toybox@1.36.1-r5"}, scan(base, lodash))
+	assert.Len(t, cacheEntries(t, cache.Dir), 3)
+
+	// the packages of the base layer whose evidence is overwritten by an upper layer are dropped
+	assert.Equal(t, []string{"musl@1.2.4-r3"}, scan(base, upgrade))
+
+	// the same layer on top of other layers is another entry
+	assert.Equal(t, []string{"left-pad@1.3.0", "lodash@4.17.20", "musl@1.2.4-r2", "toybox@1.36.1-r5"}, scan(base, lodash, leftPad))
+	assert.Len(t, cacheEntries(t, cache.Dir), 5)
+}
+
+func Test_layerChains(t *testing.T) {
+	chains := layerChains([]source.LayerMetadata{{Digest: "sha256:a"}, {Digest: "sha256:b"}, {Digest: "sha256:a"}})
+	assert.Equal(t, "sha256:a", chains["sha256:a"])
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, chains["sha256:b"])
+	assert.NotEqual(t, chains["sha256:b"], layerChains([]source.LayerMetadata{{Digest: "sha256:c"}, {Digest: "sha256:b"}})["sha256:b"])
+}
+
+func TestLayerCache_evict(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"old", "recent", "newest"} {
+		path := filepath.Join(dir, "config", name+".json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, make([]byte, 100), 0o600))
+		used := now.Add(time.Duration(i-3) * time.Hour)
+		require.NoError(t, os.Chtimes(path, used, used))
+	}
+
+	LayerCache{Dir: dir, MaxSize: 250}.evict()
+	assert.NoFileExists(t, filepath.Join(dir, "config", "old.json"))
+	assert.FileExists(t, filepath.Join(dir, "config", "recent.json"))
+	assert.FileExists(t, filepath.Join(dir, "config", "newest.json"))
+
+	// no limit
+	LayerCache{Dir: dir}.evict()
+	assert.FileExists(t, filepath.Join(dir, "config", "recent.json"))
+}
+
+func testLayer(t *testing.T, files map[string][]byte) v1.Layer {
+	t.Helper()
+	l, err := crane.Layer(files)
+	require.NoError(t, err)
+	return l
+}
+
+func testImage(t *testing.T, layers ...v1.Layer) string {
+	t.Helper()
+	img, err := mutate.ConfigFile(empty.Image, &v1.ConfigFile{Architecture: "amd64", OS: "linux", RootFS: v1.RootFS{Type: "layers"}})
+	require.NoError(t, err)
+	img, err = mutate.AppendLayers(img, layers...)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	l, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+	require.NoError(t, l.AppendImage(img))
+	return dir
+}
+
+// cacheEntries returns the entry of each cached layer by the digest of the layer.
+func cacheEntries(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries := make(map[string]string)
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	require.NoError(t, err)
+	for _, p := range paths {
+		base := filepath.Base(p)
+		entries[strings.ReplaceAll(base[:len(base)-len(".json")], "sha256-", "sha256:")] = p
+	}
+	return entries
+}
@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+const (
+	// InstalledOrigin is the Origin of the packages found in the scan target when lockfiles are supplied alongside it.
+	InstalledOrigin = "installed"
+
+	// LockfileOrigin prefixes the Origin of packages that were only found in a lockfile supplied alongside the scan
+	// target (e.g. "lockfile:package-lock.json"), which are typically resolved but not installed (such as dev
+	// dependencies).
+	LockfileOrigin = "lockfile"
+)
+
+// LockfileMode is how the packages of lockfiles supplied alongside the scan target are combined with its packages.
+type LockfileMode string
+
+const (
+	// IncludeLockfiles adds the packages only found in the lockfiles to the packages of the scan target.
+	IncludeLockfiles LockfileMode = "include"
+
+	// CompareLockfiles adds the packages only found in the lockfiles as well, and also labels the packages of the scan
+	// target that a lockfile resolves (e.g. "installed+lockfile:package-lock.json"), so that the installed packages
+	// that are not locked (at that version) tell the drift between the target and its source apart.
+	CompareLockfiles LockfileMode = "compare"
+)
+
+// AllLockfileModes lists the supported values for LockfileMode.
+var AllLockfileModes = []LockfileMode{IncludeLockfiles, CompareLockfiles}
+
+// ParseLockfileMode returns the LockfileMode for the given value, where an empty value is the same as "include".
+func ParseLockfileMode(value string) (LockfileMode, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return IncludeLockfiles, nil
+	}
+	for _, m := range AllLockfileModes {
+		if string(m) == value {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown lockfile mode %q (options: %v)", value, AllLockfileModes)
+}
+
+// addLockfilePackages catalogs the given lockfiles and adds their packages to the packages of the scan target. The
+// packages already found in the scan target are kept (labeled as installed), so only the dependencies that were
+// resolved but not found in the target are added, labeled with the lockfile they came from. Comparing the lockfiles
+// also labels the installed packages with the first lockfile resolving them.
+func addLockfilePackages(packages []Package, lockfiles []string, mode LockfileMode, config ProviderConfig) ([]Package, error) {
+	installed := len(packages)
+	index := make(map[string]int)
+	for i := range packages {
+		packages[i].Origin = InstalledOrigin
+		index[lockfileKey(packages[i])] = i
+	}
+
+	// the name and exclusions apply to the scan target, not to the lockfiles
+	lockfileConfig := config
+	lockfileConfig.Name = ""
+	lockfileConfig.Exclusions = nil
+
+	locked := make(map[syftPkg.Type]bool)
+	for _, lockfile := range lockfiles {
+		found, _, _, err := syftProvider("file:"+lockfile, lockfileConfig)
+		if err != nil {
+			return nil, fmt.Errorf("unable to catalog lockfile %q: %w", lockfile, err)
+		}
+		if len(found) == 0 {
+			log.WithFields("lockfile", lockfile).Warn("no packages found in lockfile")
+		}
+
+		origin := LockfileOrigin + ":" + lockfile
+		var added int
+		for _, p := range found {
+			locked[p.Type] = true
+			key := lockfileKey(p)
+			if i, ok := index[key]; ok {
+				if mode == CompareLockfiles && packages[i].Origin == InstalledOrigin {
+					packages[i].Origin = InstalledOrigin + "+" + origin
+				}
+				continue
+			}
+			index[key] = len(packages)
+			p.Origin = origin
+			packages = append(packages, p)
+			added++
+		}
+		log.WithFields("lockfile", lockfile, "packages", len(found), "added", added).Debug("added lockfile packages")
+	}
+
+	if mode == CompareLockfiles {
+		var unlocked int
+		for _, p := range packages[:installed] {
+			// only the installed packages of the ecosystems of the lockfiles could have been locked
+			if locked[p.Type] && p.Origin == InstalledOrigin {
+				unlocked++
+			}
+		}
+		if unlocked > 0 {
+			log.WithFields("packages", unlocked).Warn("installed packages are not resolved (at their version) by the lockfiles")
+		}
+	}
+	return packages, nil
+}
+
+// lockfileKey identifies the same dependency whether it was found installed or in a lockfile
+func lockfileKey(p Package) string {
+	return fmt.Sprintf("%s:%s@%s", p.Type, strings.ToLower(p.Name), p.Version)
+}
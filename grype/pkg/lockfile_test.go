@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func Test_addLockfilePackages(t *testing.T) {
+	const lockfile = "test-fixtures/lockfile/package-lock.json"
+	config := ProviderConfig{SyftProviderConfig: SyftProviderConfig{SBOMOptions: syft.DefaultCreateSBOMConfig(), Name: "my-image"}}
+
+	tests := []struct {
+		name string
+		mode LockfileMode
+		want map[string]string
+	}{
+		{
+			name: "include",
+			mode: IncludeLockfiles,
+			want: map[string]string{
+				// already installed in the scan target
+				"lodash@4.17.20":   "installed",
+				"openssl@3.0.8-r3": "installed",
+				"express@4.18.2":   "installed",
+				// only resolved in the lockfile (a dev dependency)
+				"minimist@1.2.5": "lockfile:" + lockfile,
+				// the project itself, as a directory scan would report it
+				"app@1.0.0": "lockfile:" + lockfile,
+			},
+		},
+		{
+			name: "compare",
+			mode: CompareLockfiles,
+			want: map[string]string{
+				// installed and locked
+				"lodash@4.17.20": "installed+lockfile:" + lockfile,
+				// not of the ecosystem of the lockfile
+				"openssl@3.0.8-r3": "installed",
+				// installed but not locked
+				"express@4.18.2": "installed",
+				"minimist@1.2.5": "lockfile:" + lockfile,
+				"app@1.0.0":      "lockfile:" + lockfile,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installed := []Package{
+				{Name: "lodash", Version: "4.17.20", Type: syftPkg.NpmPkg},
+				{Name: "openssl", Version: "3.0.8-r3", Type: syftPkg.ApkPkg},
+				{Name: "express", Version: "4.18.2", Type: syftPkg.NpmPkg},
+			}
+			packages, err := addLockfilePackages(installed, []string{lockfile}, tt.mode, config)
+			require.NoError(t, err)
+
+			origins := make(map[string]string)
+			for _, p := range packages {
+				origins[p.Name+"@"+p.Version] = p.Origin
+			}
+			assert.Equal(t, tt.want, origins)
+		})
+	}
+
+	_, err := addLockfilePackages(nil, []string{"test-fixtures/lockfile/does-not-exist.json"}, IncludeLockfiles, config)
+	require.Error(t, err)
+}
+
+func TestParseLockfileMode(t *testing.T) {
+	for value, want := range map[string]LockfileMode{"": IncludeLockfiles, "include": IncludeLockfiles, " Compare ": CompareLockfiles} {
+		got, err := ParseLockfileMode(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseLockfileMode("merge")
+	require.ErrorContains(t, err, `unknown lockfile mode "merge"`)
+}
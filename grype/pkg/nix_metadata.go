@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// NixMetadata describes the nix store derivation output that a package was found in.
+type NixMetadata struct {
+	StorePath  string `json:"storePath,omitempty"`
+	OutputHash string `json:"outputHash"`
+	Output     string `json:"output,omitempty"`
+}
+
+// NixStorePath is the information encoded in a nix store path (/nix/store/<hash>-<name>-<version>[-<output>]).
+type NixStorePath struct {
+	Path       string
+	OutputHash string
+	Name       string
+	Version    string
+	Output     string
+}
+
+var (
+	nixStoreHashPattern = regexp.MustCompile(`^[0-9a-df-np-sv-z]{32}$`)
+
+	// well-known multiple-output names (see https://nixos.org/manual/nixpkgs/stable/#chap-multiple-output)
+	nixOutputs = map[string]bool{
+		"bin": true, "dev": true, "out": true, "lib": true, "man": true, "doc": true, "devdoc": true,
+		"info": true, "debug": true, "static": true, "python": true,
+	}
+)
+
+// ParseNixStorePath extracts the package name, version and output from a path within the nix store. Following the
+// nixpkgs naming conventions the version starts at the first dash-delimited field that begins with a digit (or with
+// "unstable-" for snapshot versions).
+func ParseNixStorePath(p string) (*NixStorePath, bool) {
+	const indicator = "nix/store/"
+	start := strings.Index(p, indicator)
+	if start == -1 {
+		return nil, false
+	}
+
+	base := p[start+len(indicator):]
+	if idx := strings.Index(base, "/"); idx != -1 {
+		base = base[:idx]
+	}
+	if strings.HasSuffix(base, ".drv") {
+		// derivations describe how to build a package, they are not the package itself
+		return nil, false
+	}
+
+	fields := strings.Split(base, "-")
+	if len(fields) < 3 || !nixStoreHashPattern.MatchString(fields[0]) {
+		return nil, false
+	}
+
+	versionIdx := -1
+	for i := 2; i < len(fields); i++ {
+		if fields[i] == "unstable" || (fields[i] != "" && fields[i][0] >= '0' && fields[i][0] <= '9') {
+			versionIdx = i
+			break
+		}
+	}
+	if versionIdx == -1 {
+		return nil, false
+	}
+
+	versionFields := fields[versionIdx:]
+	var output string
+	if last := versionFields[len(versionFields)-1]; len(versionFields) > 1 && nixOutputs[last] {
+		output = last
+		versionFields = versionFields[:len(versionFields)-1]
+	}
+
+	return &NixStorePath{
+		Path:       path.Join("/", p[:start+len(indicator)], base),
+		OutputHash: fields[0],
+		Name:       strings.Join(fields[1:versionIdx], "-"),
+		Version:    strings.Join(versionFields, "-"),
+		Output:     output,
+	}, true
+}
+
+func nixStorePathFromLocations(locations file.LocationSet) *NixStorePath {
+	for _, l := range locations.ToSlice() {
+		for _, candidate := range []string{l.RealPath, l.AccessPath} {
+			if sp, ok := ParseNixStorePath(candidate); ok {
+				return sp
+			}
+		}
+	}
+	return nil
+}
+
+func nixMetadataFromPkg(p pkg.Package) *NixMetadata {
+	sp := nixStorePathFromLocations(p.Locations)
+
+	m, ok := p.Metadata.(pkg.NixStoreEntry)
+	if !ok {
+		if sp == nil {
+			return nil
+		}
+		return &NixMetadata{
+			StorePath:  sp.Path,
+			OutputHash: sp.OutputHash,
+			Output:     sp.Output,
+		}
+	}
+
+	metadata := NixMetadata{
+		OutputHash: m.OutputHash,
+		Output:     m.Output,
+	}
+	if sp != nil && sp.OutputHash == m.OutputHash {
+		metadata.StorePath = sp.Path
+	}
+	return &metadata
+}
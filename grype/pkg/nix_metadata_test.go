@@ -0,0 +1,108 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/file"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestParseNixStorePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected *NixStorePath
+	}{
+		{
+			path: "/nix/store/h0cnbmfcn93xm5dg2x27ixhag1cwndga-glibc-2.34-210-bin/lib/libc.so.6",
+			expected: &NixStorePath{
+				Path:       "/nix/store/h0cnbmfcn93xm5dg2x27ixhag1cwndga-glibc-2.34-210-bin",
+				OutputHash: "h0cnbmfcn93xm5dg2x27ixhag1cwndga",
+				Name:       "glibc",
+				Version:    "2.34-210",
+				Output:     "bin",
+			},
+		},
+		{
+			path: "/nix/store/0bdfvzfprzqbmjr6hv8skc6ixakj2ahv-python3.11-requests-2.31.0",
+			expected: &NixStorePath{
+				Path:       "/nix/store/0bdfvzfprzqbmjr6hv8skc6ixakj2ahv-python3.11-requests-2.31.0",
+				OutputHash: "0bdfvzfprzqbmjr6hv8skc6ixakj2ahv",
+				Name:       "python3.11-requests",
+				Version:    "2.31.0",
+			},
+		},
+		{
+			path: "nix/store/xzfw2ypaq6s6jzm8di7drs7l6wh1s4hc-libfido2-unstable-2023-01-01/lib",
+			expected: &NixStorePath{
+				Path:       "/nix/store/xzfw2ypaq6s6jzm8di7drs7l6wh1s4hc-libfido2-unstable-2023-01-01",
+				OutputHash: "xzfw2ypaq6s6jzm8di7drs7l6wh1s4hc",
+				Name:       "libfido2",
+				Version:    "unstable-2023-01-01",
+			},
+		},
+		{
+			// derivations are not packages
+			path: "/nix/store/h0cnbmfcn93xm5dg2x27ixhag1cwndga-glibc-2.34-210.drv",
+		},
+		{
+			// no version
+			path: "/nix/store/h0cnbmfcn93xm5dg2x27ixhag1cwndga-source",
+		},
+		{
+			path: "/usr/lib/libc.so.6",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			actual, ok := ParseNixStorePath(test.path)
+			if test.expected == nil {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestNew_NixPackage(t *testing.T) {
+	location := file.NewLocation("/nix/store/h0cnbmfcn93xm5dg2x27ixhag1cwndga-glibc-2.34-210-bin/lib/libc.so.6")
+
+	t.Run("from syft nix cataloger", func(t *testing.T) {
+		p := New(syftPkg.Package{
+			Name:      "glibc",
+			Version:   "2.34-210",
+			Type:      syftPkg.NixPkg,
+			Locations: file.NewLocationSet(location),
+			Metadata: syftPkg.NixStoreEntry{
+				OutputHash: "h0cnbmfcn93xm5dg2x27ixhag1cwndga",
+				Output:     "bin",
+			},
+		})
+
+		assert.Equal(t, NixMetadata{
+			StorePath:  "/nix/store/h0cnbmfcn93xm5dg2x27ixhag1cwndga-glibc-2.34-210-bin",
+			OutputHash: "h0cnbmfcn93xm5dg2x27ixhag1cwndga",
+			Output:     "bin",
+		}, p.Metadata)
+	})
+
+	t.Run("from store path only", func(t *testing.T) {
+		p := New(syftPkg.Package{
+			Type:      syftPkg.NixPkg,
+			Locations: file.NewLocationSet(location),
+		})
+
+		assert.Equal(t, "glibc", p.Name)
+		assert.Equal(t, "2.34-210", p.Version)
+		assert.Equal(t, NixMetadata{
+			StorePath:  "/nix/store/h0cnbmfcn93xm5dg2x27ixhag1cwndga-glibc-2.34-210-bin",
+			OutputHash: "h0cnbmfcn93xm5dg2x27ixhag1cwndga",
+			Output:     "bin",
+		}, p.Metadata)
+	})
+}
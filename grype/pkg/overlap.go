@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OverlapPrecedence decides which package is kept when an SBOM reports that one package owns the files of another
+// (e.g. an OS package that installs a python library or a binary), so that each artifact is matched only once.
+type OverlapPrecedence string
+
+const (
+	// OverlapPrecedenceAuto keeps the OS package and drops the owned package when the distro feed is comprehensive
+	// enough to cover it, and always drops owned binary packages.
+	OverlapPrecedenceAuto OverlapPrecedence = "auto"
+
+	// OverlapPrecedenceOS always keeps the OS package and drops the owned language or binary package.
+	OverlapPrecedenceOS OverlapPrecedence = "os"
+
+	// OverlapPrecedenceLanguage keeps the owned language or binary package and drops the OS package that owns it.
+	OverlapPrecedenceLanguage OverlapPrecedence = "language"
+
+	// OverlapPrecedenceNone keeps all packages, matching each of them (possibly reporting the same vulnerability twice).
+	OverlapPrecedenceNone OverlapPrecedence = "none"
+)
+
+// AllOverlapPrecedences lists the supported values for OverlapPrecedence.
+var AllOverlapPrecedences = []OverlapPrecedence{
+	OverlapPrecedenceAuto,
+	OverlapPrecedenceOS,
+	OverlapPrecedenceLanguage,
+	OverlapPrecedenceNone,
+}
+
+// DropsOwnedPackages returns true if packages owned by an OS package may be dropped in favor of the OS package.
+func (p OverlapPrecedence) DropsOwnedPackages() bool {
+	switch p {
+	case OverlapPrecedenceLanguage, OverlapPrecedenceNone:
+		return false
+	default:
+		return true
+	}
+}
+
+// ParseOverlapPrecedence returns the OverlapPrecedence for the given value, where an empty value is the same as "auto".
+func ParseOverlapPrecedence(value string) (OverlapPrecedence, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return OverlapPrecedenceAuto, nil
+	}
+	for _, p := range AllOverlapPrecedences {
+		if string(p) == value {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown overlap precedence %q (options: %v)", value, AllOverlapPrecedences)
+}
@@ -29,17 +29,24 @@ type ID string
 
 // Package represents an application or library that has been bundled into a distributable format.
 type Package struct {
-	ID        ID
-	Name      string           // the package name
-	Version   string           // the version of the package
-	Locations file.LocationSet // the locations that lead to the discovery of this package (note: this is not necessarily the locations that make up this package)
-	Language  pkg.Language     // the language ecosystem this package belongs to (e.g. JavaScript, Python, etc)
-	Licenses  []string
-	Type      pkg.Type  // the package type (e.g. Npm, Yarn, Python, Rpm, Deb, etc)
-	CPEs      []cpe.CPE // all possible Common Platform Enumerators
-	PURL      string    // the Package URL (see https://github.com/package-url/purl-spec)
-	Upstreams []UpstreamPackage
-	Metadata  interface{} // This is NOT 1-for-1 the syft metadata! Only the select data needed for vulnerability matching
+	ID         ID
+	Name       string           // the package name
+	Version    string           // the version of the package
+	Locations  file.LocationSet // the locations that lead to the discovery of this package (note: this is not necessarily the locations that make up this package)
+	Language   pkg.Language     // the language ecosystem this package belongs to (e.g. JavaScript, Python, etc)
+	Licenses   []string
+	Type       pkg.Type  // the package type (e.g. Npm, Yarn, Python, Rpm, Deb, etc)
+	Runtime    string    // the runtime or runtime library a binary package is (e.g. node or openssl), as named by the syft binary classifiers
+	CPEs       []cpe.CPE // all possible Common Platform Enumerators
+	PURL       string    // the Package URL (see https://github.com/package-url/purl-spec)
+	Digests    []Digest  // the digests of the artifact of the package known from the catalog (e.g. the integrity of a lockfile entry), hex encoded
+	Upstreams  []UpstreamPackage
+	Provides   []ProvidedPackage // the virtual (or former) package names this package provides
+	Arch       string            // the architecture the package was built for (see NormalizeArch), empty if unknown or architecture independent
+	Metadata   interface{}       // This is NOT 1-for-1 the syft metadata! Only the select data needed for vulnerability matching
+	Origin     string            // where the package was found when lockfiles are scanned alongside the scan target (e.g. "installed" or "lockfile:package-lock.json")
+	Distro     *linux.Release    // the distro to match this package against in place of the distro of the scan target (e.g. set by an Enricher)
+	Properties []Property        // the custom properties of the package in the SBOM it was read from (e.g. CycloneDX properties)
 }
 
 func New(p pkg.Package) Package {
@@ -55,17 +62,35 @@ func New(p pkg.Package) Package {
 		licenses = []string{}
 	}
 
+	name, version := p.Name, p.Version
+	if p.Type == pkg.NixPkg && (name == "" || version == "") {
+		// fall back to the name and version encoded in the store path
+		if sp := nixStorePathFromLocations(p.Locations); sp != nil {
+			name, version = sp.Name, sp.Version
+		}
+	}
+
+	runtime := runtimeFromPkg(p)
+	cpes := p.CPEs
+	if runtime != "" {
+		cpes = runtimeCPEsFromPkg(p)
+	}
+
 	return Package{
 		ID:        ID(p.ID()),
-		Name:      p.Name,
-		Version:   p.Version,
+		Name:      name,
+		Version:   version,
 		Locations: p.Locations,
 		Licenses:  licenses,
 		Language:  p.Language,
-		Type:      p.Type,
-		CPEs:      p.CPEs,
+		Type:      typeFromPkg(p),
+		Runtime:   runtime,
+		CPEs:      cpes,
 		PURL:      p.PURL,
+		Digests:   digestsFromPkg(p),
 		Upstreams: upstreams,
+		Provides:  providesFromPkg(p, upstreams),
+		Arch:      archFromPkg(p),
 		Metadata:  metadata,
 	}
 }
@@ -76,7 +101,15 @@ func FromCollection(catalog *pkg.Collection, config SynthesisConfig) []Package {
 
 func FromPackages(syftpkgs []pkg.Package, config SynthesisConfig) []Package {
 	var pkgs []Package
+	npmMembers := npmWorkspaceMembers(syftpkgs)
 	for _, p := range syftpkgs {
+		if p.Type == pkg.NpmPkg {
+			var keep bool
+			if p, keep = resolveNpmPackage(p, npmMembers); !keep {
+				log.WithFields("package", p.Name, "version", p.Version).Trace("ignoring npm package of the project itself")
+				continue
+			}
+		}
 		if len(p.CPEs) == 0 {
 			// For SPDX (or any format, really) we may have no CPEs
 			if config.GenerateMissingCPEs {
@@ -96,23 +129,42 @@ func (p Package) String() string {
 	return fmt.Sprintf("Pkg(type=%s, name=%s, version=%s, upstreams=%d)", p.Type, p.Name, p.Version, len(p.Upstreams))
 }
 
-func removePackagesByOverlap(catalog *pkg.Collection, relationships []artifact.Relationship, distro *linux.Release) *pkg.Collection {
-	byOverlap := map[artifact.ID]artifact.Relationship{}
+func removePackagesByOverlap(catalog *pkg.Collection, relationships []artifact.Relationship, distro *linux.Release, precedence OverlapPrecedence) *pkg.Collection {
+	if precedence == OverlapPrecedenceNone {
+		return catalog
+	}
+
+	excluded := map[artifact.ID]struct{}{}
+	comprehensiveDistroFeed := distroFeedIsComprehensive(distro)
 	for _, r := range relationships {
-		if r.Type == artifact.OwnershipByFileOverlapRelationship {
-			byOverlap[r.To.ID()] = r
+		if r.Type != artifact.OwnershipByFileOverlapRelationship {
+			continue
+		}
+		parent, child := catalog.Package(r.From.ID()), catalog.Package(r.To.ID())
+		if parent == nil || child == nil {
+			continue
+		}
+
+		switch precedence {
+		case OverlapPrecedenceOS:
+			if isSameArtifact(*child, *parent) {
+				excluded[child.ID()] = struct{}{}
+			}
+		case OverlapPrecedenceLanguage:
+			if isSameArtifact(*child, *parent) {
+				excluded[parent.ID()] = struct{}{}
+			}
+		default:
+			if excludePackage(comprehensiveDistroFeed, *child, *parent) {
+				excluded[child.ID()] = struct{}{}
+			}
 		}
 	}
 
 	out := pkg.NewCollection()
-	comprehensiveDistroFeed := distroFeedIsComprehensive(distro)
 	for p := range catalog.Enumerate() {
-		r, ok := byOverlap[p.ID()]
-		if ok {
-			from := catalog.Package(r.From.ID())
-			if from != nil && excludePackage(comprehensiveDistroFeed, p, *from) {
-				continue
-			}
+		if _, ok := excluded[p.ID()]; ok {
+			continue
 		}
 		out.Add(p)
 	}
@@ -120,6 +172,32 @@ func removePackagesByOverlap(catalog *pkg.Collection, relationships []artifact.R
 	return out
 }
 
+// isSameArtifact returns true if the non-OS package p is most likely the same artifact as the OS package that owns its
+// files (that is, the package was packaged by the distro rather than being vendored by the OS package), which is when
+// the version of p is exactly the upstream version of the OS package. Packages without a version are never the same
+// artifact, since nothing tells them apart.
+func isSameArtifact(p pkg.Package, parent pkg.Package) bool {
+	if p.Version == "" || parent.Version == "" {
+		return false
+	}
+	if !isOSPackage(parent) || isOSPackage(p) {
+		return false
+	}
+	return p.Version == parent.Version || p.Version == osUpstreamVersion(parent.Version)
+}
+
+// osUpstreamVersion returns the upstream version of the version of an OS package, without the epoch and the distro
+// revision (e.g. 4.14.3 for 1:4.14.3-26.el8 and 19.2 for 19.2-r1).
+func osUpstreamVersion(version string) string {
+	if epoch, rest, ok := strings.Cut(version, ":"); ok && epoch != "" && strings.Trim(epoch, "0123456789") == "" {
+		version = rest
+	}
+	if i := strings.LastIndex(version, "-"); i > 0 {
+		version = version[:i]
+	}
+	return version
+}
+
 func excludePackage(comprehensiveDistroFeed bool, p pkg.Package, parent pkg.Package) bool {
 	// NOTE: we are not checking the name because we have mismatches like:
 	// python      3.9.2      binary
@@ -186,7 +264,7 @@ var comprehensiveDistros = []string{
 
 func isOSPackage(p pkg.Package) bool {
 	switch p.Type {
-	case pkg.DebPkg, pkg.RpmPkg, pkg.PortagePkg, pkg.AlpmPkg, pkg.ApkPkg:
+	case pkg.DebPkg, pkg.RpmPkg, pkg.PortagePkg, pkg.AlpmPkg, pkg.ApkPkg, OpkgPkg:
 		return true
 	default:
 		return false
@@ -217,6 +295,17 @@ func dataFromPkg(p pkg.Package) (interface{}, []UpstreamPackage) {
 		upstreams = apkDataFromPkg(p)
 	case pkg.JavaVMInstallation:
 		metadata = javaVMDataFromPkg(p)
+	case pkg.NixStoreEntry:
+		if m := nixMetadataFromPkg(p); m != nil {
+			metadata = *m
+		}
+	default:
+		if p.Type == pkg.NixPkg {
+			// SBOMs from other tools may describe nix packages without syft's nix metadata
+			if m := nixMetadataFromPkg(p); m != nil {
+				metadata = *m
+			}
+		}
 	}
 	return metadata, upstreams
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/anchore/syft/syft/artifact"
 	"github.com/anchore/syft/syft/cpe"
@@ -500,6 +501,10 @@ func TestNew(t *testing.T) {
 					},
 				},
 			},
+			metadata: NixMetadata{
+				OutputHash: "a",
+				Output:     "a",
+			},
 		},
 		{
 			name: "linux-kernel-metadata",
@@ -840,6 +845,7 @@ func Test_RemovePackagesByOverlap(t *testing.T) {
 	tests := []struct {
 		name             string
 		sbom             *sbom.SBOM
+		precedence       OverlapPrecedence
 		expectedPackages []string
 	}{
 		{
@@ -912,10 +918,62 @@ func Test_RemovePackagesByOverlap(t *testing.T) {
 				[]string{"rpm:python3-rpm@4.14.3-26.el8 -> python:rpm@4.14.3"}), "amzn"),
 			expectedPackages: []string{"rpm:python3-rpm@4.14.3-26.el8", "python:rpm@4.14.3"},
 		},
+		{
+			name: "os precedence removes owned packages regardless of distro",
+			sbom: withDistro(catalogWithOverlaps(
+				[]string{"rpm:python3-rpm@4.14.3-26.el8", "python:rpm@4.14.3"},
+				[]string{"rpm:python3-rpm@4.14.3-26.el8 -> python:rpm@4.14.3"}), "amzn"),
+			precedence:       OverlapPrecedenceOS,
+			expectedPackages: []string{"rpm:python3-rpm@4.14.3-26.el8"},
+		},
+		{
+			name: "os precedence keeps owned packages with a different version",
+			sbom: withDistro(catalogWithOverlaps(
+				[]string{"rpm:python3-rpm@4.14.3-26.el8", "python:rpm@4.16.1"},
+				[]string{"rpm:python3-rpm@4.14.3-26.el8 -> python:rpm@4.16.1"}), "rhel"),
+			precedence:       OverlapPrecedenceOS,
+			expectedPackages: []string{"rpm:python3-rpm@4.14.3-26.el8", "python:rpm@4.16.1"},
+		},
+		{
+			name: "os precedence keeps owned packages whose version only prefixes the upstream version",
+			sbom: withDistro(catalogWithOverlaps(
+				[]string{"rpm:python3-rpm@4.14.3-26.el8", "python:rpm@4.14"},
+				[]string{"rpm:python3-rpm@4.14.3-26.el8 -> python:rpm@4.14"}), "rhel"),
+			precedence:       OverlapPrecedenceOS,
+			expectedPackages: []string{"rpm:python3-rpm@4.14.3-26.el8", "python:rpm@4.14"},
+		},
+		{
+			name: "language precedence removes the owning OS package",
+			sbom: withDistro(catalogWithOverlaps(
+				[]string{"apk:go@1.18", "apk:node@19.2-r1", "binary:node@19.2"},
+				[]string{"apk:node@19.2-r1 -> binary:node@19.2"}), "alpine"),
+			precedence:       OverlapPrecedenceLanguage,
+			expectedPackages: []string{"apk:go@1.18", "binary:node@19.2"},
+		},
+		{
+			name: "language precedence does not remove non-OS owners",
+			sbom: catalogWithOverlaps(
+				[]string{"python:urllib3@1.2.3", "python:otherlib@1.2.3"},
+				[]string{"python:urllib3@1.2.3 -> python:otherlib@1.2.3"}),
+			precedence:       OverlapPrecedenceLanguage,
+			expectedPackages: []string{"python:otherlib@1.2.3", "python:urllib3@1.2.3"},
+		},
+		{
+			name: "no precedence keeps all packages",
+			sbom: withDistro(catalogWithOverlaps(
+				[]string{"rpm:python3-rpm@4.14.3-26.el8", "python:rpm@4.14.3", "rpm:node@19.2-r1", "binary:node@19.2"},
+				[]string{"rpm:python3-rpm@4.14.3-26.el8 -> python:rpm@4.14.3", "rpm:node@19.2-r1 -> binary:node@19.2"}), "rhel"),
+			precedence:       OverlapPrecedenceNone,
+			expectedPackages: []string{"binary:node@19.2", "rpm:node@19.2-r1", "rpm:python3-rpm@4.14.3-26.el8", "python:rpm@4.14.3"},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			catalog := removePackagesByOverlap(test.sbom.Artifacts.Packages, test.sbom.Relationships, test.sbom.Artifacts.LinuxDistribution)
+			precedence := test.precedence
+			if precedence == "" {
+				precedence = OverlapPrecedenceAuto
+			}
+			catalog := removePackagesByOverlap(test.sbom.Artifacts.Packages, test.sbom.Relationships, test.sbom.Artifacts.LinuxDistribution, precedence)
 			pkgs := FromCollection(catalog, SynthesisConfig{})
 			var pkgNames []string
 			for _, p := range pkgs {
@@ -1006,3 +1064,51 @@ func withDistro(s *sbom.SBOM, id string) *sbom.SBOM {
 func strRef(s string) *string {
 	return &s
 }
+
+func Test_isSameArtifact(t *testing.T) {
+	rpm := syftPkg.Package{Name: "python3-rpm", Version: "4.14.3-26.el8", Type: syftPkg.RpmPkg}
+	tests := []struct {
+		name   string
+		p      syftPkg.Package
+		parent syftPkg.Package
+		want   bool
+	}{
+		{name: "upstream version", p: syftPkg.Package{Version: "4.14.3", Type: syftPkg.PythonPkg}, parent: rpm, want: true},
+		{name: "full version", p: syftPkg.Package{Version: "4.14.3-26.el8", Type: syftPkg.PythonPkg}, parent: rpm, want: true},
+		{name: "upstream version of an epoch", p: syftPkg.Package{Version: "4.14.3", Type: syftPkg.PythonPkg}, parent: syftPkg.Package{Version: "1:4.14.3-26.el8", Type: syftPkg.RpmPkg}, want: true},
+		{name: "prefix of the version", p: syftPkg.Package{Version: "4.1", Type: syftPkg.PythonPkg}, parent: rpm},
+		{name: "no version", p: syftPkg.Package{Type: syftPkg.PythonPkg}, parent: rpm},
+		{name: "no version of the OS package", p: syftPkg.Package{Version: "4.14.3", Type: syftPkg.PythonPkg}, parent: syftPkg.Package{Type: syftPkg.RpmPkg}},
+		{name: "not owned by an OS package", p: syftPkg.Package{Version: "4.14.3", Type: syftPkg.PythonPkg}, parent: syftPkg.Package{Version: "4.14.3", Type: syftPkg.PythonPkg}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, isSameArtifact(test.p, test.parent))
+		})
+	}
+}
+
+func TestParseOverlapPrecedence(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    OverlapPrecedence
+		wantErr require.ErrorAssertionFunc
+	}{
+		{value: "", want: OverlapPrecedenceAuto},
+		{value: "auto", want: OverlapPrecedenceAuto},
+		{value: " OS ", want: OverlapPrecedenceOS},
+		{value: "language", want: OverlapPrecedenceLanguage},
+		{value: "none", want: OverlapPrecedenceNone},
+		{value: "binary", wantErr: require.Error},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			got, err := ParseOverlapPrecedence(test.value)
+			test.wantErr(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
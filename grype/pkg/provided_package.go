@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// ProvidedPackage is a package that another package provides, such as a virtual package (e.g. "mail-transport-agent")
+// or the former name of a renamed package, as listed in the rpm Provides, deb Provides and apk provides fields.
+type ProvidedPackage struct {
+	Name    string // the name of the provided package
+	Version string // the version of the provided package, if given (otherwise that of the providing package)
+}
+
+// provides entries are only considered when they name a package, which excludes capabilities such as
+// "libcrypto.so.3()(64bit)" and "config(openssh)" (rpm), or "so:libcrypto.so.3" and "cmd:openssl" (apk)
+var providedPackageNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.+_~-]*$`)
+
+// ProvidedPackages returns the packages that the given package provides, in place of the package itself, so that
+// vulnerabilities filed against a virtual or renamed package can be searched for.
+func ProvidedPackages(p Package) (pkgs []Package) {
+	for _, provided := range p.Provides {
+		tmp := p
+		tmp.Name = provided.Name
+		if provided.Version != "" {
+			tmp.Version = provided.Version
+		}
+		tmp.Upstreams = nil
+		tmp.Provides = nil
+		// the CPEs describe the providing package, not the provided one
+		tmp.CPEs = nil
+		pkgs = append(pkgs, tmp)
+	}
+	return pkgs
+}
+
+func providesFromPkg(p pkg.Package, upstreams []UpstreamPackage) []ProvidedPackage {
+	var entries []ProvidedPackage
+	switch m := p.Metadata.(type) {
+	case pkg.DpkgDBEntry:
+		for _, entry := range m.Provides {
+			// e.g. "libssl1.1 (= 1.1.1n-0+deb11u5)"
+			name, constraint, _ := strings.Cut(entry, "(")
+			entries = append(entries, ProvidedPackage{Name: strings.TrimSpace(name), Version: exactVersion(strings.TrimSuffix(strings.TrimSpace(constraint), ")"))})
+		}
+	case pkg.RpmDBEntry:
+		for _, entry := range m.Provides {
+			// e.g. "openssl-libs = 1:1.1.1k-7.el8_6"
+			name, constraint, _ := strings.Cut(entry, " ")
+			entries = append(entries, ProvidedPackage{Name: name, Version: exactVersion(constraint)})
+		}
+	case pkg.ApkDBEntry:
+		for _, entry := range m.Provides {
+			// e.g. "openssl1.1-compat=1.1.1w-r1"
+			name, version, _ := strings.Cut(entry, "=")
+			entries = append(entries, ProvidedPackage{Name: name, Version: version})
+		}
+	}
+
+	// the package (and its upstreams) are already searched for by name
+	seen := map[string]bool{p.Name: true}
+	for _, u := range upstreams {
+		seen[u.Name] = true
+	}
+
+	var provides []ProvidedPackage
+	for _, entry := range entries {
+		if seen[entry.Name] || !providedPackageNamePattern.MatchString(entry.Name) {
+			continue
+		}
+		seen[entry.Name] = true
+		provides = append(provides, entry)
+	}
+	return provides
+}
+
+// exactVersion returns the version of an "= <version>" constraint, ignoring any other kind of constraint
+func exactVersion(constraint string) string {
+	constraint = strings.TrimSpace(constraint)
+	if !strings.HasPrefix(constraint, "=") {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(constraint, "="))
+}
@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/syft/syft/pkg"
+)
+
+func Test_providesFromPkg(t *testing.T) {
+	tests := []struct {
+		name      string
+		pkg       pkg.Package
+		upstreams []UpstreamPackage
+		want      []ProvidedPackage
+	}{
+		{
+			name: "deb virtual packages",
+			pkg: pkg.Package{Name: "exim4-daemon-light", Metadata: pkg.DpkgDBEntry{
+				Provides: []string{"mail-transport-agent", "libssl1.1 (= 1.1.1n-0+deb11u5)", "awk (>= 1.0)"},
+			}},
+			want: []ProvidedPackage{
+				{Name: "mail-transport-agent"},
+				{Name: "libssl1.1", Version: "1.1.1n-0+deb11u5"},
+				{Name: "awk"},
+			},
+		},
+		{
+			name: "rpm provides",
+			pkg: pkg.Package{Name: "openssl-libs", Metadata: pkg.RpmDBEntry{
+				Provides: []string{
+					"config(openssl-libs) = 1:1.1.1k-7.el8_6",
+					"libcrypto.so.1.1()(64bit)",
+					"openssl-libs = 1:1.1.1k-7.el8_6",
+					"openssl-libs(x86-64) = 1:1.1.1k-7.el8_6",
+					"compat-openssl11 = 1:1.1.1k-7.el8_6",
+				},
+			}},
+			want: []ProvidedPackage{
+				{Name: "compat-openssl11", Version: "1:1.1.1k-7.el8_6"},
+			},
+		},
+		{
+			name: "apk provides",
+			pkg: pkg.Package{Name: "libcrypto1.1", Metadata: pkg.ApkDBEntry{
+				Provides: []string{"so:libcrypto.so.1.1=1.1", "cmd:openssl=1.1.1w-r1", "openssl1.1-compat=1.1.1w-r1", "openssl=1.1.1w-r1"},
+			}},
+			// the upstream is already searched for
+			upstreams: []UpstreamPackage{{Name: "openssl"}},
+			want: []ProvidedPackage{
+				{Name: "openssl1.1-compat", Version: "1.1.1w-r1"},
+			},
+		},
+		{
+			name: "no provides",
+			pkg:  pkg.Package{Name: "bash", Metadata: pkg.DpkgDBEntry{}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, providesFromPkg(test.pkg, test.upstreams))
+		})
+	}
+}
+
+func TestProvidedPackages(t *testing.T) {
+	p := Package{
+		Name:      "exim4-daemon-light",
+		Version:   "4.94.2-7",
+		Upstreams: []UpstreamPackage{{Name: "exim4"}},
+		Provides:  []ProvidedPackage{{Name: "mail-transport-agent"}, {Name: "exim4-daemon", Version: "4.94.2-6"}},
+	}
+
+	got := ProvidedPackages(p)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "mail-transport-agent", got[0].Name)
+		assert.Equal(t, "4.94.2-7", got[0].Version)
+		assert.Equal(t, "exim4-daemon", got[1].Name)
+		assert.Equal(t, "4.94.2-6", got[1].Version)
+		assert.Nil(t, got[1].Upstreams)
+		assert.Nil(t, got[1].Provides)
+	}
+}
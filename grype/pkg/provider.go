@@ -14,6 +14,29 @@ var errDoesNotProvide = fmt.Errorf("cannot provide packages from the given sourc
 
 // Provide a set of packages and context metadata describing where they were sourced from.
 func Provide(userInput string, config ProviderConfig) ([]Package, Context, *sbom.SBOM, error) {
+	packages, ctx, s, err := provide(userInput, config)
+	if err != nil {
+		return packages, ctx, s, err
+	}
+
+	packages = resolveGoModules(packages, ctx)
+	packages = resolveArchitectures(packages, ctx, config.IgnoreArchitecture)
+
+	packages, err = filterPackageScope(packages, ctx, config.Scope)
+	if err != nil {
+		return nil, ctx, s, err
+	}
+
+	if len(config.Lockfiles) > 0 {
+		packages, err = addLockfilePackages(packages, config.Lockfiles, config.LockfileMode, config)
+		if err != nil {
+			return nil, ctx, s, err
+		}
+	}
+	return packages, ctx, s, nil
+}
+
+func provide(userInput string, config ProviderConfig) ([]Package, Context, *sbom.SBOM, error) {
 	packages, ctx, s, err := syftSBOMProvider(userInput, config)
 	if !errors.Is(err, errDoesNotProvide) {
 		if len(config.Exclusions) > 0 {
@@ -31,6 +54,11 @@ func Provide(userInput string, config ProviderConfig) ([]Package, Context, *sbom
 		return packages, Context{}, s, err
 	}
 
+	packages, ctx, s, err = runtimeEnvironmentProvider(userInput, config)
+	if !errors.Is(err, errDoesNotProvide) {
+		return packages, ctx, s, err
+	}
+
 	return syftProvider(userInput, config)
 }
 
@@ -1,13 +1,23 @@
 package pkg
 
 import (
+	"context"
+
 	"github.com/anchore/stereoscope/pkg/image"
 	"github.com/anchore/syft/syft"
+	"github.com/anchore/syft/syft/sbom"
 )
 
 type ProviderConfig struct {
 	SyftProviderConfig
 	SynthesisConfig
+	OverlapPrecedence  OverlapPrecedence // which package to keep when one package owns the files of another
+	Scope              Scope             // the part of the scan target to match packages from
+	Lockfiles          []string          // lockfiles to add the resolved dependencies of to the packages of the scan target
+	LockfileMode       LockfileMode      // how the packages of the lockfiles are combined with the packages of the scan target
+	IgnoreArchitecture bool              // match advisories regardless of the architectures they are scoped to
+	// SBOMDecoders decode SBOM formats that syft does not support (see NewSBOMDecoder and ExternalSBOMDecoder)
+	SBOMDecoders []sbom.FormatDecoder
 }
 
 type SyftProviderConfig struct {
@@ -17,6 +27,18 @@ type SyftProviderConfig struct {
 	Exclusions             []string
 	Name                   string
 	DefaultImagePullSource string
+	// LayerCache, when set, caches the packages cataloged from each layer of the scanned images.
+	LayerCache *LayerCache
+	// Context, when set, bounds the cataloging: once it is done the cataloging is abandoned, failing with the cause of
+	// the context (e.g. a deadline or an exceeded memory budget).
+	Context context.Context
+}
+
+func (c SyftProviderConfig) context() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+	return c.Context
 }
 
 type SynthesisConfig struct {
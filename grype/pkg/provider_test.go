@@ -1,13 +1,17 @@
 package pkg
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/anchore/stereoscope/pkg/imagetest"
 	"github.com/anchore/syft/syft"
 	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/sbom"
 )
 
 func TestProviderLocationExcludes(t *testing.T) {
@@ -241,3 +245,24 @@ func Test_matchesLocation(t *testing.T) {
 		})
 	}
 }
+
+func Test_catalog(t *testing.T) {
+	// the cataloging completes
+	s, err := catalog(context.Background(), func(context.Context) (*sbom.SBOM, error) {
+		return &sbom.SBOM{}, nil
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, s)
+
+	// the cataloging is abandoned once the context is done, even when the catalogers do not observe it
+	aborted := errors.New("memory budget exceeded")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	blocked := make(chan struct{})
+	defer close(blocked)
+	go cancel(aborted)
+	_, err = catalog(ctx, func(context.Context) (*sbom.SBOM, error) {
+		<-blocked
+		return &sbom.SBOM{}, nil
+	})
+	require.ErrorIs(t, err, aborted)
+}
@@ -9,14 +9,17 @@ import (
 
 	"github.com/mitchellh/go-homedir"
 
+	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/packageurl-go"
 	"github.com/anchore/syft/syft/cpe"
+	"github.com/anchore/syft/syft/linux"
 	"github.com/anchore/syft/syft/pkg"
 )
 
 const (
-	purlInputPrefix  = "purl:"
-	cpesQualifierKey = "cpes"
+	purlInputPrefix    = "purl:"
+	cpesQualifierKey   = "cpes"
+	distroQualifierKey = "distro"
 )
 
 type errEmptyPurlFile struct {
@@ -46,50 +49,130 @@ func decodePurlFile(reader io.Reader) ([]Package, error) {
 	packages := []Package{}
 
 	for scanner.Scan() {
-		rawLine := scanner.Text()
-		purl, err := packageurl.FromString(rawLine)
+		p, err := NewFromPURL(scanner.Text())
 		if err != nil {
-			return nil, fmt.Errorf("unable to decode purl %s: %w", rawLine, err)
+			return nil, err
 		}
+		packages = append(packages, p)
+	}
 
-		cpes := []cpe.CPE{}
-		epoch := "0"
-		for _, qualifier := range purl.Qualifiers {
-			if qualifier.Key == cpesQualifierKey {
-				rawCpes := strings.Split(qualifier.Value, ",")
-				for _, rawCpe := range rawCpes {
-					c, err := cpe.New(rawCpe, "")
-					if err != nil {
-						return nil, fmt.Errorf("unable to decode cpe %s in purl %s: %w", rawCpe, rawLine, err)
-					}
-					cpes = append(cpes, c)
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
+
+// NewFromPURL creates a package from the given package URL. Any CPEs listed in the "cpes" qualifier are attached
+// to the package. The version may be empty, in which case the package can only be used to look up vulnerability
+// records (not to match them).
+func NewFromPURL(raw string) (Package, error) {
+	purl, err := packageurl.FromString(raw)
+	if err != nil {
+		return Package{}, fmt.Errorf("unable to decode purl %s: %w", raw, err)
+	}
+
+	cpes := []cpe.CPE{}
+	epoch := "0"
+	for _, qualifier := range purl.Qualifiers {
+		if qualifier.Key == cpesQualifierKey {
+			rawCpes := strings.Split(qualifier.Value, ",")
+			for _, rawCpe := range rawCpes {
+				c, err := cpe.New(rawCpe, "")
+				if err != nil {
+					return Package{}, fmt.Errorf("unable to decode cpe %s in purl %s: %w", rawCpe, raw, err)
 				}
+				cpes = append(cpes, c)
 			}
+		}
 
-			if qualifier.Key == "epoch" {
-				epoch = qualifier.Value
-			}
+		if qualifier.Key == "epoch" {
+			epoch = qualifier.Value
 		}
+	}
+
+	if purl.Type == packageurl.TypeRPM && purl.Version != "" && !strings.HasPrefix(purl.Version, fmt.Sprintf("%s:", epoch)) {
+		purl.Version = fmt.Sprintf("%s:%s", epoch, purl.Version)
+	}
+
+	name, pkgType, language := purlPackageIdentity(purl)
+
+	return Package{
+		ID:       ID(purl.String()),
+		CPEs:     cpes,
+		Name:     name,
+		Version:  purl.Version,
+		Type:     pkgType,
+		Language: language,
+		PURL:     purl.String(),
+		Arch:     NormalizeArch(purl.Qualifiers.Map()["arch"]),
+	}, nil
+}
 
-		if purl.Type == packageurl.TypeRPM && !strings.HasPrefix(purl.Version, fmt.Sprintf("%s:", epoch)) {
-			purl.Version = fmt.Sprintf("%s:%s", epoch, purl.Version)
+// ReleaseFromPURL describes the distro an OS package purl was installed from, using the distro qualifier that syft
+// records (e.g. "debian-12") or, when absent, the purl namespace (which is sufficient for rolling distros). Nil is
+// returned for purls that do not carry distro information.
+func ReleaseFromPURL(raw string) *linux.Release {
+	purl, err := packageurl.FromString(raw)
+	if err != nil {
+		return nil
+	}
+
+	id := purl.Namespace
+	var ver string
+	if q := purl.Qualifiers.Map()[distroQualifierKey]; q != "" {
+		var ok bool
+		if id, ver, ok = splitDistroQualifier(q); !ok {
+			return nil
 		}
+	}
 
-		packages = append(packages, Package{
-			ID:       ID(purl.String()),
-			CPEs:     cpes,
-			Name:     purl.Name,
-			Version:  purl.Version,
-			Type:     pkg.TypeByName(purl.Type),
-			Language: pkg.LanguageByName(purl.Type),
-			PURL:     purl.String(),
-		})
+	if _, ok := distro.IDMapping[id]; !ok {
+		return nil
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+	return &linux.Release{
+		ID:        id,
+		VersionID: ver,
 	}
-	return packages, nil
+}
+
+// splitDistroQualifier splits a distro qualifier (e.g. "opensuse-leap-15.5") into the distro ID and version, the ID
+// being the longest known distro ID the qualifier starts with, since distro IDs may have dashes too.
+func splitDistroQualifier(q string) (string, string, bool) {
+	if _, ok := distro.IDMapping[q]; ok {
+		return q, "", true
+	}
+	var id, ver string
+	for known := range distro.IDMapping {
+		if len(known) > len(id) && strings.HasPrefix(q, known+"-") {
+			id, ver = known, strings.TrimPrefix(q, known+"-")
+		}
+	}
+	return id, ver, id != ""
+}
+
+// purlPackageIdentity determines the package name, type, and language for a purl. Source-repository purls
+// (e.g. pkg:github/owner/repo@commit) are named after the repository, but are of no ecosystem: a repository is not
+// necessarily a go module (nor a GitHub action), so only its CPEs (and the commit it is pinned to) match it. A go
+// module pinned to a commit is given as pkg:golang/github.com/owner/repo@commit instead.
+func purlPackageIdentity(purl packageurl.PackageURL) (string, pkg.Type, pkg.Language) {
+	switch purl.Type {
+	case packageurl.TypeGithub:
+		name := strings.Join([]string{"github.com", purl.Namespace, purl.Name}, "/")
+		return name, pkg.UnknownPkg, pkg.UnknownLanguage
+	case packageurl.TypeGolang:
+		name := purl.Name
+		if purl.Namespace != "" {
+			name = purl.Namespace + "/" + purl.Name
+		}
+		return name, pkg.GoModulePkg, pkg.Go
+	case packageurl.TypeNPM:
+		// scoped packages are published under their scope (e.g. @babel/core)
+		if purl.Namespace != "" {
+			return purl.Namespace + "/" + purl.Name, pkg.NpmPkg, pkg.JavaScript
+		}
+	}
+	return purl.Name, TypeByName(purl.Type), pkg.LanguageByName(purl.Type)
 }
 
 func getPurlReader(userInput string) (r io.Reader, err error) {
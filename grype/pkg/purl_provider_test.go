@@ -1,9 +1,14 @@
 package pkg
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/linux"
+	syftPkg "github.com/anchore/syft/syft/pkg"
 )
 
 func Test_PurlProvider_Fails(t *testing.T) {
@@ -49,3 +54,57 @@ func Test_CsvProvide(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, packageNames)
 }
+
+func Test_PurlProvider_SourceRepositories(t *testing.T) {
+	packages, err := decodePurlFile(strings.NewReader("pkg:github/anchore/syft@16171245cfb2\npkg:golang/github.com/anchore/stereoscope@v0.0.0-20200202094626-16171245cfb2"))
+	require.NoError(t, err)
+	require.Len(t, packages, 2)
+
+	// a repository is of no ecosystem
+	assert.Equal(t, "github.com/anchore/syft", packages[0].Name)
+	assert.Equal(t, syftPkg.UnknownPkg, packages[0].Type)
+	assert.Equal(t, syftPkg.UnknownLanguage, packages[0].Language)
+	assert.Equal(t, "16171245cfb2", packages[0].Version)
+
+	assert.Equal(t, "github.com/anchore/stereoscope", packages[1].Name)
+	assert.Equal(t, syftPkg.GoModulePkg, packages[1].Type)
+}
+
+func Test_NewFromPURL(t *testing.T) {
+	p, err := NewFromPURL("pkg:npm/%40babel/core")
+	require.NoError(t, err)
+	assert.Equal(t, "@babel/core", p.Name)
+	assert.Equal(t, syftPkg.NpmPkg, p.Type)
+	assert.Equal(t, syftPkg.JavaScript, p.Language)
+	assert.Empty(t, p.Version)
+
+	p, err = NewFromPURL("pkg:rpm/redhat/openssl?epoch=1&distro=rhel-8")
+	require.NoError(t, err)
+	assert.Empty(t, p.Version, "the epoch should not be applied without a version")
+
+	_, err = NewFromPURL("not-a-purl")
+	require.Error(t, err)
+}
+
+func Test_ReleaseFromPURL(t *testing.T) {
+	tests := []struct {
+		purl string
+		want *linux.Release
+	}{
+		{purl: "pkg:deb/debian/openssl?distro=debian-12", want: &linux.Release{ID: "debian", VersionID: "12"}},
+		{purl: "pkg:rpm/redhat/openssl?distro=rhel-8.6", want: &linux.Release{ID: "rhel", VersionID: "8.6"}},
+		{purl: "pkg:apk/wolfi/openssl", want: &linux.Release{ID: "wolfi"}},
+		{purl: "pkg:rpm/opensuse/openssl?distro=opensuse-leap-15.5", want: &linux.Release{ID: "opensuse-leap", VersionID: "15.5"}},
+		{purl: "pkg:rpm/suse/openssl?distro=sles-15.4", want: &linux.Release{ID: "sles", VersionID: "15.4"}},
+		{purl: "pkg:rpm/redhat/openssl?distro=rhel-8", want: &linux.Release{ID: "rhel", VersionID: "8"}},
+		{purl: "pkg:rpm/opensuse/openssl?distro=opensuse-leap", want: &linux.Release{ID: "opensuse-leap"}},
+		{purl: "pkg:deb/debian/openssl?distro=unknown-1"},
+		{purl: "pkg:npm/lodash"},
+		{purl: "not-a-purl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			assert.Equal(t, tt.want, ReleaseFromPURL(tt.purl))
+		})
+	}
+}
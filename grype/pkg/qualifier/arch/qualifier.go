@@ -0,0 +1,43 @@
+package arch
+
+import (
+	"strings"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/pkg/qualifier"
+)
+
+type arch struct {
+	architectures []string
+}
+
+// New returns a qualifier that is satisfied by packages built for one of the given architectures.
+func New(architectures []string) qualifier.Qualifier {
+	var normalized []string
+	for _, a := range architectures {
+		if n := pkg.NormalizeArch(a); n != "" {
+			normalized = append(normalized, n)
+		}
+	}
+	return &arch{architectures: normalized}
+}
+
+func (a arch) String() string {
+	return "arch=" + strings.Join(a.architectures, ",")
+}
+
+func (a arch) Satisfied(_ *distro.Distro, p pkg.Package) (bool, error) {
+	if p.Arch == "" || len(a.architectures) == 0 {
+		// If unable to determine the architecture of the package (or the advisory is not scoped to an
+		// architecture), the constraint should be considered satisfied
+		return true, nil
+	}
+
+	for _, candidate := range a.architectures {
+		if candidate == p.Arch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,57 @@
+package arch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+)
+
+func TestArch_Satisfied(t *testing.T) {
+	tests := []struct {
+		name          string
+		architectures []string
+		pkg           pkg.Package
+		satisfied     bool
+	}{
+		{
+			name:          "package without architecture",
+			architectures: []string{"s390x"},
+			pkg:           pkg.Package{},
+			satisfied:     true,
+		},
+		{
+			name:          "qualifier without architectures",
+			architectures: nil,
+			pkg:           pkg.Package{Arch: "amd64"},
+			satisfied:     true,
+		},
+		{
+			name:          "matching architecture",
+			architectures: []string{"s390x", "ppc64le"},
+			pkg:           pkg.Package{Arch: "s390x"},
+			satisfied:     true,
+		},
+		{
+			name:          "matching architecture of another ecosystem",
+			architectures: []string{"x86_64"},
+			pkg:           pkg.Package{Arch: "amd64"},
+			satisfied:     true,
+		},
+		{
+			name:          "other architecture",
+			architectures: []string{"s390x"},
+			pkg:           pkg.Package{Arch: "amd64"},
+			satisfied:     false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			satisfied, err := New(test.architectures).Satisfied(nil, test.pkg)
+			require.NoError(t, err)
+			assert.Equal(t, test.satisfied, satisfied)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package configuration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/packageurl-go"
+)
+
+// Disabled is an optional feature (or configuration) declared as not enabled for a package, given by name (e.g.
+// "apache2") or by package URL (e.g. "pkg:deb/debian/apache2", of any version unless one is given).
+type Disabled struct {
+	Package string
+	Feature string
+	purl    *packageurl.PackageURL
+}
+
+// ParseDisabled parses a disabled feature of a package, given as "<package>:<feature>" where the package is a name or
+// a package URL (e.g. "apache2:mod_lua" or "pkg:deb/debian/apache2:mod_lua").
+func ParseDisabled(value string) (Disabled, error) {
+	idx := strings.LastIndex(value, ":")
+	if idx < 0 {
+		return Disabled{}, fmt.Errorf("bad disabled feature %q: expected <package>:<feature> (e.g. apache2:mod_lua)", value)
+	}
+	d := Disabled{Package: strings.TrimSpace(value[:idx]), Feature: strings.TrimSpace(value[idx+1:])}
+	if d.Package == "" || d.Feature == "" || strings.Contains(d.Feature, "/") {
+		return Disabled{}, fmt.Errorf("bad disabled feature %q: expected <package>:<feature> (e.g. apache2:mod_lua)", value)
+	}
+	if strings.HasPrefix(d.Package, "pkg:") {
+		p, err := packageurl.FromString(d.Package)
+		if err != nil {
+			return Disabled{}, fmt.Errorf("bad disabled feature %q: invalid package URL: %w", value, err)
+		}
+		d.purl = &p
+	}
+	return d, nil
+}
+
+// ParseAllDisabled parses the disabled features of packages (see ParseDisabled).
+func ParseAllDisabled(values ...string) ([]Disabled, error) {
+	var disabled []Disabled
+	for _, v := range values {
+		d, err := ParseDisabled(v)
+		if err != nil {
+			return nil, err
+		}
+		disabled = append(disabled, d)
+	}
+	return disabled, nil
+}
+
+// Of reports whether the feature is disabled for the package, by its name or its package URL.
+func (d Disabled) Of(p pkg.Package) bool {
+	if d.purl == nil {
+		return d.Package == p.Name
+	}
+	if p.PURL == "" {
+		return false
+	}
+	got, err := packageurl.FromString(p.PURL)
+	if err != nil {
+		return false
+	}
+	if !strings.EqualFold(d.purl.Type, got.Type) || !strings.EqualFold(d.purl.Namespace, got.Namespace) || d.purl.Name != got.Name {
+		return false
+	}
+	return d.purl.Version == "" || d.purl.Version == got.Version
+}
+
+func (d Disabled) String() string {
+	return d.Package + ":" + d.Feature
+}
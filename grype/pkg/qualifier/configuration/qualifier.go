@@ -0,0 +1,80 @@
+package configuration
+
+import (
+	"strings"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/pkg/qualifier"
+)
+
+// Qualifier records the optional features (or configurations) of a package that a vulnerability requires, such as an
+// Apache module or a crate feature: the package is only affected when one of the features is enabled.
+type Qualifier struct {
+	features []string
+}
+
+// New returns a qualifier of a vulnerability that requires one of the given features to be enabled.
+func New(features []string) qualifier.Qualifier {
+	var normalized []string
+	for _, f := range features {
+		if f = strings.TrimSpace(f); f != "" {
+			normalized = append(normalized, f)
+		}
+	}
+	return &Qualifier{features: normalized}
+}
+
+// Features returns the features of which one must be enabled for the package to be affected.
+func (q Qualifier) Features() []string {
+	return q.features
+}
+
+func (q Qualifier) String() string {
+	return "configuration=" + strings.Join(q.features, ",")
+}
+
+// Satisfied is always true: the features enabled for a package are not known from the package itself, so matches of
+// the vulnerability are only set aside when the features are declared as disabled (see DisabledBy).
+func (q Qualifier) Satisfied(_ *distro.Distro, _ pkg.Package) (bool, error) {
+	return true, nil
+}
+
+// DisabledBy returns true when every feature the vulnerability requires is among the features disabled for the
+// package.
+func (q Qualifier) DisabledBy(disabled []Disabled, p pkg.Package) bool {
+	if len(q.features) == 0 {
+		return false
+	}
+	var features []string
+	for _, d := range disabled {
+		if d.Of(p) {
+			features = append(features, d.Feature)
+		}
+	}
+	for _, f := range q.features {
+		if !containsFold(features, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// Of returns the configuration qualifier among the given qualifiers, if any.
+func Of(qualifiers []qualifier.Qualifier) (*Qualifier, bool) {
+	for _, q := range qualifiers {
+		if c, ok := q.(*Qualifier); ok {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/pkg/qualifier"
+	"github.com/anchore/grype/grype/pkg/qualifier/arch"
+)
+
+var apache = pkg.Package{Name: "apache2", Version: "2.4.57-2", PURL: "pkg:deb/debian/apache2@2.4.57-2?arch=amd64"}
+
+func TestConfiguration_DisabledBy(t *testing.T) {
+	tests := []struct {
+		name     string
+		features []string
+		disabled []string
+		expected bool
+	}{
+		{
+			name:     "required feature disabled",
+			features: []string{"mod_lua"},
+			disabled: []string{"apache2:MOD_LUA"},
+			expected: true,
+		},
+		{
+			name:     "one of the required features enabled",
+			features: []string{"mod_lua", "mod_proxy"},
+			disabled: []string{"apache2:mod_lua"},
+			expected: false,
+		},
+		{
+			name:     "every required feature disabled",
+			features: []string{"mod_lua", "mod_proxy"},
+			disabled: []string{"apache2:mod_proxy", "apache2:mod_lua"},
+			expected: true,
+		},
+		{
+			name:     "no required feature",
+			features: []string{" "},
+			disabled: []string{"apache2:mod_lua"},
+			expected: false,
+		},
+		{
+			name:     "no disabled feature",
+			features: []string{"mod_lua"},
+			expected: false,
+		},
+		{
+			name:     "disabled for another package",
+			features: []string{"mod_lua"},
+			disabled: []string{"nginx:mod_lua", "pkg:deb/debian/nginx:mod_lua"},
+			expected: false,
+		},
+		{
+			name:     "disabled by package URL",
+			features: []string{"mod_lua"},
+			disabled: []string{"pkg:deb/debian/apache2:mod_lua"},
+			expected: true,
+		},
+		{
+			name:     "disabled for another version",
+			features: []string{"mod_lua"},
+			disabled: []string{"pkg:deb/debian/apache2@2.4.58-1:mod_lua"},
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, ok := Of([]qualifier.Qualifier{New(test.features)})
+			require.True(t, ok)
+			disabled, err := ParseAllDisabled(test.disabled...)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, q.DisabledBy(disabled, apache))
+
+			satisfied, err := q.Satisfied(nil, pkg.Package{})
+			require.NoError(t, err)
+			assert.True(t, satisfied)
+		})
+	}
+}
+
+func TestOf(t *testing.T) {
+	_, ok := Of([]qualifier.Qualifier{arch.New([]string{"s390x"})})
+	assert.False(t, ok)
+
+	q, ok := Of([]qualifier.Qualifier{arch.New([]string{"s390x"}), New([]string{"mod_lua"})})
+	require.True(t, ok)
+	assert.Equal(t, []string{"mod_lua"}, q.Features())
+	assert.Equal(t, "configuration=mod_lua", q.String())
+}
+
+func TestParseDisabled(t *testing.T) {
+	d, err := ParseDisabled("pkg:deb/debian/apache2:mod_lua")
+	require.NoError(t, err)
+	assert.Equal(t, "pkg:deb/debian/apache2", d.Package)
+	assert.Equal(t, "mod_lua", d.Feature)
+	assert.Equal(t, "pkg:deb/debian/apache2:mod_lua", d.String())
+
+	for _, value := range []string{"mod_lua", "apache2:", ":mod_lua", "pkg:deb/debian/apache2", "pkg:apache2:mod_lua"} {
+		_, err := ParseDisabled(value)
+		assert.Error(t, err, value)
+	}
+}
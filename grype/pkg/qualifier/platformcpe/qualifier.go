@@ -17,6 +17,10 @@ func New(cpe string) qualifier.Qualifier {
 	return &platformCPE{cpe: cpe}
 }
 
+func (p platformCPE) String() string {
+	return "platform-cpe=" + p.cpe
+}
+
 func isWindowsPlatformCPE(c cpe.CPE) bool {
 	return c.Attributes.Vendor == "microsoft" && strings.HasPrefix(c.Attributes.Product, "windows")
 }
@@ -16,6 +16,10 @@ func New(module string) qualifier.Qualifier {
 	return &rpmModularity{module: module}
 }
 
+func (r rpmModularity) String() string {
+	return "rpm-modularity=" + r.module
+}
+
 func (r rpmModularity) Satisfied(d *distro.Distro, p pkg.Package) (bool, error) {
 	if p.Metadata == nil {
 		// If unable to determine package modularity, the constraint should be considered satisfied
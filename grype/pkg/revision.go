@@ -0,0 +1,43 @@
+package pkg
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/anchore/packageurl-go"
+)
+
+// vcsRevisionPattern matches full or abbreviated git commit hashes
+var vcsRevisionPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// VCSRevision returns the commit of the source repository the package was built from, when the SBOM records one:
+// the "vcs.revision" build setting of a go main module, or the revision of the "vcs_url" qualifier of the package
+// URL (e.g. "git+https://github.com/anchore/grype@8a8ff0e").
+func VCSRevision(p Package) (string, bool) {
+	if m, ok := p.Metadata.(GolangBinMetadata); ok && p.Name == m.MainModule {
+		if revision, ok := m.BuildSettings.Get("vcs.revision"); ok && vcsRevisionPattern.MatchString(revision) {
+			return strings.ToLower(revision), true
+		}
+	}
+	return revisionFromPURL(p.PURL)
+}
+
+func revisionFromPURL(raw string) (string, bool) {
+	if raw == "" {
+		return "", false
+	}
+	purl, err := packageurl.FromString(raw)
+	if err != nil {
+		return "", false
+	}
+	vcsURL := purl.Qualifiers.Map()["vcs_url"]
+	idx := strings.LastIndex(vcsURL, "@")
+	if idx < 0 {
+		return "", false
+	}
+	revision := vcsURL[idx+1:]
+	if !vcsRevisionPattern.MatchString(revision) {
+		return "", false
+	}
+	return strings.ToLower(revision), true
+}
@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestVCSRevision(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkg      Package
+		expected string
+	}{
+		{
+			name: "main module build setting",
+			pkg: Package{
+				Name: "github.com/anchore/grype",
+				Metadata: GolangBinMetadata{
+					MainModule:    "github.com/anchore/grype",
+					BuildSettings: syftPkg.KeyValues{{Key: "vcs.revision", Value: "16171245CFB2A1D9B5D5E39F9CB8A5E8C2A0B1C3"}},
+				},
+			},
+			expected: "16171245cfb2a1d9b5d5e39f9cb8a5e8c2a0b1c3",
+		},
+		{
+			name: "build setting of the main module is not the revision of a dependency",
+			pkg: Package{
+				Name: "github.com/anchore/syft",
+				Metadata: GolangBinMetadata{
+					MainModule:    "github.com/anchore/grype",
+					BuildSettings: syftPkg.KeyValues{{Key: "vcs.revision", Value: "16171245cfb2"}},
+				},
+			},
+		},
+		{
+			name: "purl vcs url",
+			pkg: Package{
+				Name: "github.com/anchore/syft",
+				PURL: "pkg:golang/github.com/anchore/syft@v1.0.0?vcs_url=git%2Bhttps%3A%2F%2Fgithub.com%2Fanchore%2Fsyft%40daa7c04131f5",
+			},
+			expected: "daa7c04131f5",
+		},
+		{
+			name: "purl vcs url with a tag",
+			pkg: Package{
+				Name: "github.com/anchore/syft",
+				PURL: "pkg:golang/github.com/anchore/syft@v1.0.0?vcs_url=git%2Bhttps%3A%2F%2Fgithub.com%2Fanchore%2Fsyft%40v1.0.0",
+			},
+		},
+		{
+			name: "no revision",
+			pkg: Package{
+				Name: "github.com/anchore/syft",
+				PURL: "pkg:golang/github.com/anchore/syft@v1.0.0",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			revision, ok := VCSRevision(test.pkg)
+			assert.Equal(t, test.expected != "", ok)
+			assert.Equal(t, test.expected, revision)
+		})
+	}
+}
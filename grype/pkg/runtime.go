@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"github.com/anchore/syft/syft/cpe"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// runtimeCPEs are the CPEs the vulnerabilities of each runtime are published under, by the name the syft binary
+// classifiers give to the binaries of the runtime.
+var runtimeCPEs = map[string][]string{
+	"node": {"cpe:2.3:a:nodejs:node.js:*:*:*:*:*:*:*:*"},
+	"python": {
+		"cpe:2.3:a:python_software_foundation:python:*:*:*:*:*:*:*:*",
+		"cpe:2.3:a:python:python:*:*:*:*:*:*:*:*",
+	},
+	"pypy":         {"cpe:2.3:a:pypy:pypy:*:*:*:*:*:*:*:*"},
+	"ruby":         {"cpe:2.3:a:ruby-lang:ruby:*:*:*:*:*:*:*:*"},
+	"go":           {"cpe:2.3:a:golang:go:*:*:*:*:*:*:*:*"},
+	"java/jre":     {"cpe:2.3:a:oracle:jre:*:*:*:*:*:*:*:*", "cpe:2.3:a:oracle:openjdk:*:*:*:*:*:*:*:*"},
+	"java/jdk":     {"cpe:2.3:a:oracle:jdk:*:*:*:*:*:*:*:*", "cpe:2.3:a:oracle:openjdk:*:*:*:*:*:*:*:*"},
+	"java/graalvm": {"cpe:2.3:a:oracle:graalvm:*:*:*:*:*:*:*:*"},
+	"perl":         {"cpe:2.3:a:perl:perl:*:*:*:*:*:*:*:*"},
+	"php-cli":      {"cpe:2.3:a:php:php:*:*:*:*:*:*:*:*"},
+	"php-fpm":      {"cpe:2.3:a:php:php:*:*:*:*:*:*:*:*"},
+	"libphp":       {"cpe:2.3:a:php:php:*:*:*:*:*:*:*:*"},
+	"openssl":      {"cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*"},
+}
+
+// runtimeFromPkg returns the runtime the binary package is, or "" for packages that are not runtime binaries.
+func runtimeFromPkg(p pkg.Package) string {
+	if p.Type != pkg.BinaryPkg {
+		return ""
+	}
+	if _, ok := runtimeCPEs[p.Name]; !ok {
+		return ""
+	}
+	return p.Name
+}
+
+// runtimeCPEsFromPkg returns the CPEs of the runtime package, which are those of the runtime at the version of the
+// package when the package has none (e.g. when identified by the SBOM of another tool).
+func runtimeCPEsFromPkg(p pkg.Package) []cpe.CPE {
+	if len(p.CPEs) > 0 {
+		return p.CPEs
+	}
+	var cpes []cpe.CPE
+	for _, c := range runtimeCPEs[p.Name] {
+		attributes := cpe.Must(c, cpe.DeclaredSource).Attributes
+		attributes.Version = p.Version
+		cpes = append(cpes, cpe.CPE{Attributes: attributes, Source: cpe.DeclaredSource})
+	}
+	return cpes
+}
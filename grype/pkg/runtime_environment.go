@@ -0,0 +1,301 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/cataloging/pkgcataloging"
+	"github.com/anchore/syft/syft/file"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// runtimeEnvironment is an interpreter environment that can be scanned directly (e.g. "python-env:/usr/lib/python3.11"):
+// the libraries installed in the environment are scanned along with the interpreter itself.
+type runtimeEnvironment struct {
+	// scheme is the scheme of the input scanning the environment.
+	scheme string
+	// language is the language of the libraries of the environment, the other packages found in it being left out.
+	language syftPkg.Language
+	// interpreter is the name of the package of the interpreter, as named by the syft binary classifiers.
+	interpreter string
+	// versionFiles are the files that may declare the version of the interpreter of the environment in the directory.
+	versionFiles func(dir string) []versionFile
+	// binaries are the paths the interpreter of the environment in the directory may be installed at.
+	binaries func(dir string) []string
+}
+
+// versionFile is a file declaring the version of an interpreter, whose pattern captures the parts of the version.
+type versionFile struct {
+	path    string
+	pattern *regexp.Regexp
+}
+
+var runtimeEnvironments = []runtimeEnvironment{
+	{
+		scheme:      "python-env",
+		language:    syftPkg.Python,
+		interpreter: "python",
+		versionFiles: func(dir string) []versionFile {
+			var files []versionFile
+			// virtual environments record the version of their interpreter at their root
+			for _, d := range ancestors(dir) {
+				files = append(files, versionFile{
+					path:    filepath.Join(d, "pyvenv.cfg"),
+					pattern: regexp.MustCompile(`(?m)^\s*version(?:_info)?\s*=\s*(\d+\.\d+\.\d+[a-z0-9]*)`),
+				})
+			}
+			for _, p := range runtimePrefixes(dir, `^python\d+\.\d+$`) {
+				for _, include := range globs(filepath.Join(p.prefix, "include", p.runtimeDir), filepath.Join(p.prefix, "include", "python*")) {
+					files = append(files, versionFile{
+						path:    filepath.Join(include, "patchlevel.h"),
+						pattern: regexp.MustCompile(`#define\s+PY_VERSION\s+"(\d+\.\d+\.\d+[a-z0-9]*)"`),
+					})
+				}
+			}
+			return files
+		},
+		binaries: func(dir string) []string {
+			var binaries []string
+			for _, p := range runtimePrefixes(dir, `^python\d+\.\d+$`) {
+				if p.runtimeDir != "" {
+					binaries = append(binaries, filepath.Join(p.prefix, "bin", p.runtimeDir))
+				}
+				binaries = append(binaries, filepath.Join(p.prefix, "bin", "python3"), filepath.Join(p.prefix, "bin", "python"))
+			}
+			return binaries
+		},
+	},
+	{
+		scheme:      "node-env",
+		language:    syftPkg.JavaScript,
+		interpreter: "node",
+		versionFiles: func(dir string) []versionFile {
+			var files []versionFile
+			for _, p := range runtimePrefixes(dir, `^node_modules$`) {
+				files = append(files, versionFile{
+					path:    filepath.Join(p.prefix, "include", "node", "node_version.h"),
+					pattern: regexp.MustCompile(`#define\s+NODE_MAJOR_VERSION\s+(\d+)\s+#define\s+NODE_MINOR_VERSION\s+(\d+)\s+#define\s+NODE_PATCH_VERSION\s+(\d+)`),
+				})
+			}
+			return files
+		},
+		binaries: func(dir string) []string {
+			var binaries []string
+			for _, p := range runtimePrefixes(dir, `^node_modules$`) {
+				binaries = append(binaries, filepath.Join(p.prefix, "bin", "node"))
+			}
+			return binaries
+		},
+	},
+	{
+		scheme:      "ruby-env",
+		language:    syftPkg.Ruby,
+		interpreter: "ruby",
+		versionFiles: func(dir string) []versionFile {
+			var files []versionFile
+			for _, p := range runtimePrefixes(dir, `^ruby$`) {
+				patterns := []string{filepath.Join(p.prefix, "lib", "ruby", "*", "*", "rbconfig.rb")}
+				if p.prefix == dir {
+					// the directory of the standard library of a version (e.g. /usr/lib/ruby/3.2.0)
+					patterns = append(patterns, filepath.Join(dir, "*", "rbconfig.rb"))
+				}
+				for _, rbconfig := range globs(patterns...) {
+					files = append(files, versionFile{
+						path:    rbconfig,
+						pattern: regexp.MustCompile(`CONFIG\["RUBY_PROGRAM_VERSION"\]\s*=\s*"(\d+\.\d+\.\d+)"`),
+					})
+				}
+			}
+			return files
+		},
+		binaries: func(dir string) []string {
+			var binaries []string
+			for _, p := range runtimePrefixes(dir, `^ruby$`) {
+				binaries = append(binaries, filepath.Join(p.prefix, "bin", "ruby"))
+			}
+			return binaries
+		},
+	},
+}
+
+// runtimeEnvironmentProvider provides the libraries installed in an interpreter environment (e.g.
+// "python-env:venv/lib/python3.11/site-packages") along with the interpreter of the environment, so that the
+// vulnerabilities of the interpreter itself (e.g. of CPython) are matched as well.
+func runtimeEnvironmentProvider(userInput string, config ProviderConfig) ([]Package, Context, *sbom.SBOM, error) {
+	env, dir, ok := explicitlySpecifyingRuntimeEnvironment(userInput)
+	if !ok {
+		return nil, Context{}, nil, errDoesNotProvide
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, Context{}, nil, fmt.Errorf("unable to read %s environment: %w", env.interpreter, err)
+	}
+	if !info.IsDir() {
+		return nil, Context{}, nil, fmt.Errorf("the %s environment %q is not a directory", env.interpreter, dir)
+	}
+
+	found, ctx, s, err := syftProvider("dir:"+dir, installedPackagesConfig(config))
+	if err != nil {
+		return nil, ctx, s, err
+	}
+
+	var packages []Package
+	var interpreter *Package
+	for _, p := range found {
+		switch {
+		case p.Language == env.language:
+			packages = append(packages, p)
+		case p.Runtime == env.interpreter && interpreter == nil:
+			interpreter = &p
+		}
+	}
+
+	if interpreter == nil {
+		// the interpreter is usually installed outside of the environment (e.g. /usr/bin/python3.11 for
+		// /usr/lib/python3.11), so the directories around it are looked at as well
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			absDir = dir
+		}
+		interpreter = env.detectInterpreter(absDir, config)
+	}
+	if interpreter == nil {
+		log.WithFields("environment", dir).Warnf("unable to detect the version of the %s interpreter of the environment, only its libraries are scanned", env.interpreter)
+	} else {
+		log.WithFields("environment", dir, "version", interpreter.Version).Debugf("detected the %s interpreter of the environment", env.interpreter)
+		packages = append(packages, *interpreter)
+	}
+	return packages, ctx, s, nil
+}
+
+// detectInterpreter returns the package of the interpreter of the environment from the files declaring its version,
+// or else from its binary (cataloged by the syft binary classifiers).
+func (env runtimeEnvironment) detectInterpreter(dir string, config ProviderConfig) *Package {
+	for _, f := range env.versionFiles(dir) {
+		contents, err := os.ReadFile(f.path)
+		if err != nil {
+			continue
+		}
+		if m := f.pattern.FindSubmatch(contents); m != nil {
+			var parts []string
+			for _, part := range m[1:] {
+				parts = append(parts, string(part))
+			}
+			p := env.interpreterPackage(strings.Join(parts, "."), f.path)
+			return &p
+		}
+	}
+
+	// the binaries are cataloged on their own, whatever the name and exclusions of the scan
+	binaryConfig := config
+	binaryConfig.Name = ""
+	binaryConfig.Exclusions = nil
+	for _, binary := range env.binaries(dir) {
+		if info, err := os.Stat(binary); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		found, _, _, err := syftProvider("file:"+binary, binaryConfig)
+		if err != nil {
+			log.WithFields("binary", binary, "error", err).Debugf("unable to catalog the %s interpreter", env.interpreter)
+			continue
+		}
+		for _, p := range found {
+			if p.Runtime == env.interpreter {
+				return &p
+			}
+		}
+	}
+	return nil
+}
+
+// interpreterPackage returns the package of the interpreter as the syft binary classifiers would identify it, which is
+// then a runtime binary with the CPEs of the runtime.
+func (env runtimeEnvironment) interpreterPackage(version, evidence string) Package {
+	p := syftPkg.Package{
+		Name:      env.interpreter,
+		Version:   version,
+		Type:      syftPkg.BinaryPkg,
+		PURL:      fmt.Sprintf("pkg:generic/%s@%s", env.interpreter, version),
+		Locations: file.NewLocationSet(file.NewLocation(evidence)),
+	}
+	p.SetID()
+	return New(p)
+}
+
+// installedPackagesConfig selects the catalogers of the installed packages (e.g. of node_modules rather than of the
+// lockfiles of a project) unless the catalogers are explicitly selected.
+func installedPackagesConfig(config ProviderConfig) ProviderConfig {
+	if config.SBOMOptions == nil || len(config.SBOMOptions.CatalogerSelection.DefaultNamesOrTags) > 0 {
+		return config
+	}
+	sbomOptions := *config.SBOMOptions
+	sbomOptions.CatalogerSelection.DefaultNamesOrTags = []string{pkgcataloging.InstalledTag}
+	config.SBOMOptions = &sbomOptions
+	return config
+}
+
+// explicitlySpecifyingRuntimeEnvironment returns the environment and the directory of an input with the scheme of a
+// runtime environment (e.g. "python-env:/usr/lib/python3.11").
+func explicitlySpecifyingRuntimeEnvironment(userInput string) (runtimeEnvironment, string, bool) {
+	for _, env := range runtimeEnvironments {
+		if dir, ok := strings.CutPrefix(userInput, env.scheme+":"); ok {
+			return env, filepath.Clean(dir), true
+		}
+	}
+	return runtimeEnvironment{}, "", false
+}
+
+// runtimePrefix is an installation prefix of a runtime (e.g. "/usr" or the root of a virtual environment), with the
+// directory of the runtime within its "lib" directory (e.g. "python3.11").
+type runtimePrefix struct {
+	prefix     string
+	runtimeDir string
+}
+
+// runtimePrefixes returns the prefixes the directory may be installed within: the parent of the "lib" (or "lib64")
+// directory holding a path element matching the pattern (e.g. "/usr" for "/usr/lib/python3.11/site-packages"), and
+// the directory itself.
+func runtimePrefixes(dir, runtimeDirPattern string) []runtimePrefix {
+	pattern := regexp.MustCompile(runtimeDirPattern)
+	var prefixes []runtimePrefix
+	for _, d := range ancestors(dir) {
+		parent := filepath.Dir(d)
+		if pattern.MatchString(filepath.Base(d)) && (filepath.Base(parent) == "lib" || filepath.Base(parent) == "lib64") {
+			prefixes = append(prefixes, runtimePrefix{prefix: filepath.Dir(parent), runtimeDir: filepath.Base(d)})
+		}
+	}
+	// the directory may be the prefix itself (e.g. the root of a virtual environment)
+	for _, runtimeDir := range globs(filepath.Join(dir, "lib", "*"), filepath.Join(dir, "lib64", "*")) {
+		if pattern.MatchString(filepath.Base(runtimeDir)) {
+			prefixes = append(prefixes, runtimePrefix{prefix: dir, runtimeDir: filepath.Base(runtimeDir)})
+		}
+	}
+	return append(prefixes, runtimePrefix{prefix: dir})
+}
+
+// ancestors returns the directory followed by each of its parents.
+func ancestors(dir string) []string {
+	dirs := []string{dir}
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dirs
+		}
+		dirs = append(dirs, parent)
+		dir = parent
+	}
+}
+
+func globs(patterns ...string) []string {
+	var out []string
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		out = append(out, matches...)
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft"
+)
+
+func TestRuntimeEnvironmentProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		libraries   []string
+		interpreter string
+	}{
+		{
+			name:        "python virtual environment",
+			input:       "python-env:test-fixtures/runtime-env/venv/lib/python3.11/site-packages",
+			libraries:   []string{"requests@2.31.0"},
+			interpreter: "python@3.11.4",
+		},
+		{
+			name:        "python virtual environment root",
+			input:       "python-env:test-fixtures/runtime-env/venv",
+			libraries:   []string{"requests@2.31.0"},
+			interpreter: "python@3.11.4",
+		},
+		{
+			name:        "system python",
+			input:       "python-env:test-fixtures/runtime-env/usr/lib/python3.10",
+			libraries:   []string{"urllib3@1.26.5"},
+			interpreter: "python@3.10.12",
+		},
+		{
+			name:        "global node modules",
+			input:       "node-env:test-fixtures/runtime-env/usr/lib/node_modules",
+			libraries:   []string{"lodash@4.17.20"},
+			interpreter: "node@18.19.1",
+		},
+		{
+			name:        "ruby",
+			input:       "ruby-env:test-fixtures/runtime-env/usr/lib/ruby",
+			libraries:   []string{"rake@13.0.6"},
+			interpreter: "ruby@3.2.2",
+		},
+		{
+			name:      "project without a detectable interpreter",
+			input:     "node-env:test-fixtures/runtime-env/app/node_modules",
+			libraries: []string{"minimist@1.2.5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := ProviderConfig{SyftProviderConfig: SyftProviderConfig{SBOMOptions: syft.DefaultCreateSBOMConfig()}}
+			packages, ctx, _, err := Provide(tt.input, config)
+			require.NoError(t, err)
+			require.NotNil(t, ctx.Source)
+
+			var libraries []string
+			var interpreter *Package
+			for _, p := range packages {
+				if p.Runtime != "" {
+					interpreter = &p
+					continue
+				}
+				libraries = append(libraries, p.Name+"@"+p.Version)
+			}
+			assert.Equal(t, tt.libraries, libraries)
+
+			if tt.interpreter == "" {
+				assert.Nil(t, interpreter)
+				return
+			}
+			require.NotNil(t, interpreter)
+			assert.Equal(t, tt.interpreter, interpreter.Name+"@"+interpreter.Version)
+			assert.Equal(t, "pkg:generic/"+tt.interpreter, interpreter.PURL)
+			require.NotEmpty(t, interpreter.CPEs)
+			assert.Equal(t, interpreter.Version, interpreter.CPEs[0].Attributes.Version)
+		})
+	}
+}
+
+func TestRuntimeEnvironmentProvider_NotADirectory(t *testing.T) {
+	config := ProviderConfig{SyftProviderConfig: SyftProviderConfig{SBOMOptions: syft.DefaultCreateSBOMConfig()}}
+	_, _, _, err := Provide("python-env:test-fixtures/runtime-env/venv/pyvenv.cfg", config)
+	require.ErrorContains(t, err, "not a directory")
+
+	_, _, _, err = Provide("python-env:test-fixtures/runtime-env/does-not-exist", config)
+	require.Error(t, err)
+}
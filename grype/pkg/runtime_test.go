@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/syft/syft/cpe"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestNew_runtime(t *testing.T) {
+	classified := cpe.Must("cpe:2.3:a:openssl:openssl:3.0.13:*:*:*:*:*:*:*", cpe.NVDDictionaryLookupSource)
+
+	tests := []struct {
+		name            string
+		pkg             syftPkg.Package
+		expectedType    syftPkg.Type
+		expectedRuntime string
+		expectedCPEs    []string
+	}{
+		{
+			name:            "runtime binary without CPEs is given those of the runtime",
+			pkg:             syftPkg.Package{Name: "node", Version: "18.19.1", Type: syftPkg.BinaryPkg},
+			expectedType:    syftPkg.BinaryPkg,
+			expectedRuntime: "node",
+			expectedCPEs:    []string{"cpe:2.3:a:nodejs:node.js:18.19.1:*:*:*:*:*:*:*"},
+		},
+		{
+			name:            "runtime binary keeps the CPEs of the classifier",
+			pkg:             syftPkg.Package{Name: "openssl", Version: "3.0.13", Type: syftPkg.BinaryPkg, CPEs: []cpe.CPE{classified}},
+			expectedType:    syftPkg.BinaryPkg,
+			expectedRuntime: "openssl",
+			expectedCPEs:    []string{"cpe:2.3:a:openssl:openssl:3.0.13:*:*:*:*:*:*:*"},
+		},
+		{
+			name:            "JRE binary",
+			pkg:             syftPkg.Package{Name: "java/jre", Version: "17.0.10", Type: syftPkg.BinaryPkg},
+			expectedType:    syftPkg.BinaryPkg,
+			expectedRuntime: "java/jre",
+			expectedCPEs:    []string{"cpe:2.3:a:oracle:jre:17.0.10:*:*:*:*:*:*:*", "cpe:2.3:a:oracle:openjdk:17.0.10:*:*:*:*:*:*:*"},
+		},
+		{
+			name:         "other binaries are not runtimes",
+			pkg:          syftPkg.Package{Name: "curl", Version: "8.5.0", Type: syftPkg.BinaryPkg},
+			expectedType: syftPkg.BinaryPkg,
+		},
+		{
+			name:         "runtime names of other package types are not runtimes",
+			pkg:          syftPkg.Package{Name: "node", Version: "18.19.1", Type: syftPkg.NpmPkg},
+			expectedType: syftPkg.NpmPkg,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.pkg)
+			assert.Equal(t, tt.expectedType, p.Type)
+			assert.Equal(t, tt.expectedRuntime, p.Runtime)
+			var cpes []string
+			for _, c := range p.CPEs {
+				cpes = append(cpes, c.Attributes.BindToFmtString())
+			}
+			assert.Equal(t, tt.expectedCPEs, cpes)
+		})
+	}
+}
+
+func TestIsJvmPackage_runtime(t *testing.T) {
+	assert.True(t, IsJvmPackage(New(syftPkg.Package{Name: "java/jdk", Version: "21.0.2", Type: syftPkg.BinaryPkg})))
+	assert.False(t, IsJvmPackage(New(syftPkg.Package{Name: "node", Version: "18.19.1", Type: syftPkg.BinaryPkg})))
+}
@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/format"
+	"github.com/anchore/syft/syft/format/syftjson"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// externalDecoderVersion is the format version reported for the documents decoded by an external decoder, which
+// does not tell the version of the formats it supports.
+const externalDecoderVersion = "external"
+
+// NewSBOMDecoder returns a decoder of the SBOM formats supported by syft along with the given decoders of other
+// formats (e.g. SWID tags, or the proprietary JSON of a vendor), which are tried in order after the syft formats.
+func NewSBOMDecoder(decoders ...sbom.FormatDecoder) sbom.FormatDecoder {
+	return format.NewDecoderCollection(append(format.Decoders(), decoders...)...)
+}
+
+// ExternalSBOMDecoder decodes an SBOM format with an external program (a decoder plugin). The program reads the
+// document on stdin and writes the packages as a Syft JSON document to stdout, exiting with a non-zero status for
+// documents that are not in its format.
+type ExternalSBOMDecoder struct {
+	Name    string
+	Command string
+	Args    []string
+
+	// documents are identified before being decoded, so the conversion of the last document is kept for decoding it
+	lock         sync.Mutex
+	lastDocument [sha256.Size]byte
+	lastSyftJSON []byte
+}
+
+var _ sbom.FormatDecoder = (*ExternalSBOMDecoder)(nil)
+
+func NewExternalSBOMDecoder(name, command string, args ...string) *ExternalSBOMDecoder {
+	return &ExternalSBOMDecoder{Name: name, Command: command, Args: args}
+}
+
+func (d *ExternalSBOMDecoder) Identify(r io.Reader) (sbom.FormatID, string) {
+	if _, err := d.convert(r); err != nil {
+		log.WithFields("decoder", d.Name, "error", err).Trace("document not identified by the external SBOM decoder")
+		return "", ""
+	}
+	return sbom.FormatID(d.Name), externalDecoderVersion
+}
+
+func (d *ExternalSBOMDecoder) Decode(r io.Reader) (*sbom.SBOM, sbom.FormatID, string, error) {
+	syftJSON, err := d.convert(r)
+	if err != nil {
+		return nil, "", "", err
+	}
+	s, _, _, err := syftjson.NewFormatDecoder().Decode(bytes.NewReader(syftJSON))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("external SBOM decoder %q did not write a valid Syft JSON document: %w", d.Name, err)
+	}
+	return s, sbom.FormatID(d.Name), externalDecoderVersion, nil
+}
+
+// convert runs the program with the document, returning the Syft JSON document it wrote.
+func (d *ExternalSBOMDecoder) convert(r io.Reader) ([]byte, error) {
+	if seeker, ok := r.(io.Seeker); ok {
+		// the readers of a decoder collection are shared between the decoders
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	document, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the document: %w", err)
+	}
+	digest := sha256.Sum256(document)
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.lastSyftJSON != nil && digest == d.lastDocument {
+		return d.lastSyftJSON, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(d.Command, d.Args...)
+	cmd.Stdin = bytes.NewReader(document)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("external SBOM decoder %q failed: %w (%s)", d.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	if id, _ := syftjson.NewFormatDecoder().Identify(bytes.NewReader(stdout.Bytes())); id == "" {
+		return nil, fmt.Errorf("external SBOM decoder %q did not write a Syft JSON document", d.Name)
+	}
+
+	d.lastDocument = digest
+	d.lastSyftJSON = stdout.Bytes()
+	return d.lastSyftJSON, nil
+}
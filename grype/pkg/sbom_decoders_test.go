@@ -0,0 +1,134 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// vendorDecoder decodes the vendor format of test-fixtures/sbom-decoders/vendor.json, as an embedding application
+// would.
+type vendorDecoder struct{}
+
+type vendorDocument struct {
+	Format     string `json:"vendorFormat"`
+	Components []struct {
+		Name    string `json:"name"`
+		Release string `json:"release"`
+	} `json:"components"`
+}
+
+func (vendorDecoder) read(r io.Reader) (*vendorDocument, error) {
+	if seeker, ok := r.(io.Seeker); ok {
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	var doc vendorDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc.Format == "" {
+		return nil, errors.New("not a vendor document")
+	}
+	return &doc, nil
+}
+
+func (d vendorDecoder) Identify(r io.Reader) (sbom.FormatID, string) {
+	doc, err := d.read(r)
+	if err != nil {
+		return "", ""
+	}
+	return "vendor", doc.Format
+}
+
+func (d vendorDecoder) Decode(r io.Reader) (*sbom.SBOM, sbom.FormatID, string, error) {
+	doc, err := d.read(r)
+	if err != nil {
+		return nil, "", "", err
+	}
+	s := &sbom.SBOM{Artifacts: sbom.Artifacts{Packages: syftPkg.NewCollection()}}
+	for _, c := range doc.Components {
+		p := syftPkg.Package{Name: c.Name, Version: c.Release, Type: syftPkg.JavaPkg, Language: syftPkg.Java}
+		p.SetID()
+		s.Artifacts.Packages.Add(p)
+	}
+	return s, "vendor", doc.Format, nil
+}
+
+func TestProvide_SBOMDecoders(t *testing.T) {
+	fixture := "test-fixtures/sbom-decoders/vendor.json"
+
+	tests := []struct {
+		name     string
+		decoders []sbom.FormatDecoder
+		expected []string
+		wantErr  string
+	}{
+		{
+			name:    "unsupported format",
+			wantErr: "unable to decode sbom",
+		},
+		{
+			name:     "decoder of an embedding application",
+			decoders: []sbom.FormatDecoder{vendorDecoder{}},
+			expected: []string{"tomcat-embed-el@9.0.27"},
+		},
+		{
+			name: "external decoder",
+			decoders: []sbom.FormatDecoder{
+				NewExternalSBOMDecoder("other", "false"),
+				NewExternalSBOMDecoder("vendor", "test-fixtures/sbom-decoders/vendor-to-syft.sh"),
+			},
+			// the packages of the Syft JSON document written by the program
+			expected: []string{"charsets@", "tomcat-embed-el@9.0.27"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			packages, _, _, err := Provide("sbom:"+fixture, ProviderConfig{SBOMDecoders: test.decoders})
+			if test.wantErr != "" {
+				assert.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var actual []string
+			for _, p := range packages {
+				actual = append(actual, p.Name+"@"+p.Version)
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestExternalSBOMDecoder(t *testing.T) {
+	d := NewExternalSBOMDecoder("vendor", "test-fixtures/sbom-decoders/vendor-to-syft.sh")
+
+	id, version := d.Identify(strings.NewReader(`{"bomFormat": "CycloneDX"}`))
+	assert.Empty(t, id)
+	assert.Empty(t, version)
+
+	_, _, _, err := d.Decode(strings.NewReader(`{"bomFormat": "CycloneDX"}`))
+	assert.ErrorContains(t, err, "not a vendor document")
+
+	id, version = d.Identify(strings.NewReader(`{"vendorFormat": "1.0"}`))
+	assert.Equal(t, sbom.FormatID("vendor"), id)
+	assert.Equal(t, externalDecoderVersion, version)
+
+	s, id, _, err := d.Decode(strings.NewReader(`{"vendorFormat": "1.0"}`))
+	require.NoError(t, err)
+	assert.Equal(t, sbom.FormatID("vendor"), id)
+	assert.Equal(t, 2, s.Artifacts.Packages.PackageCount())
+
+	// the program must write Syft JSON
+	_, _, _, err = NewExternalSBOMDecoder("echo", "echo", "{}").Decode(strings.NewReader(`{}`))
+	assert.ErrorContains(t, err, "did not write a Syft JSON document")
+}
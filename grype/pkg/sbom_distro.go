@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/base64"
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+const (
+	// DistroFromOSRelease is recorded when the distro of an SBOM was read from an os-release file within the SBOM.
+	DistroFromOSRelease = "os-release"
+	// DistroFromPURLs is recorded when the distro of an SBOM was inferred from the purls of its OS packages.
+	DistroFromPURLs = "purls"
+)
+
+var osReleasePaths = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+// inferDistro reconstructs the distro of an SBOM that does not record it (as SBOMs from other tools often do not),
+// from the contents of an os-release file within the SBOM or else from the distro qualifiers of the purls of the OS
+// packages. It returns nil when there is no such evidence, along with the kind of evidence otherwise.
+func inferDistro(s *sbom.SBOM) (*linux.Release, string) {
+	if release := osReleaseFromFiles(s); release != nil {
+		return release, DistroFromOSRelease
+	}
+	if release := distroFromPURLs(s.Artifacts.Packages); release != nil {
+		return release, DistroFromPURLs
+	}
+	return nil, ""
+}
+
+func osReleaseFromFiles(s *sbom.SBOM) *linux.Release {
+	for _, path := range osReleasePaths {
+		for coordinates, contents := range s.Artifacts.FileContents {
+			if coordinates.RealPath != path {
+				continue
+			}
+			// syft records file contents base64 encoded
+			if decoded, err := base64.StdEncoding.DecodeString(contents); err == nil {
+				contents = string(decoded)
+			}
+			if release := parseOSRelease(contents); release != nil {
+				return release
+			}
+		}
+	}
+	return nil
+}
+
+// parseOSRelease parses the contents of an os-release file (see os-release(5)), returning nil without an ID.
+func parseOSRelease(contents string) *linux.Release {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		values[key] = strings.Trim(value, `"'`)
+	}
+	if values["ID"] == "" {
+		return nil
+	}
+
+	var idLike []string
+	if values["ID_LIKE"] != "" {
+		idLike = strings.Fields(values["ID_LIKE"])
+	}
+	return &linux.Release{
+		PrettyName:      values["PRETTY_NAME"],
+		Name:            values["NAME"],
+		ID:              values["ID"],
+		IDLike:          idLike,
+		Version:         values["VERSION"],
+		VersionID:       values["VERSION_ID"],
+		VersionCodename: values["VERSION_CODENAME"],
+	}
+}
+
+// distroFromPURLs returns the distro qualifying the most OS package purls, preferring the purls that qualify a
+// distro version over those only telling the distro (which is sufficient for rolling distros).
+func distroFromPURLs(collection *pkg.Collection) *linux.Release {
+	if collection == nil {
+		return nil
+	}
+
+	type distroVersion struct {
+		id, version string
+	}
+	counts := make(map[distroVersion]int)
+	versioned := false
+	for p := range collection.Enumerate(pkg.AlpmPkg, pkg.ApkPkg, pkg.DebPkg, pkg.RpmPkg) {
+		release := ReleaseFromPURL(p.PURL)
+		if release == nil {
+			continue
+		}
+		counts[distroVersion{id: release.ID, version: release.VersionID}]++
+		versioned = versioned || release.VersionID != ""
+	}
+
+	candidates := make([]distroVersion, 0, len(counts))
+	for d := range counts {
+		if versioned && d.version == "" {
+			continue
+		}
+		candidates = append(candidates, d)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if counts[candidates[i]] != counts[candidates[j]] {
+			return counts[candidates[i]] > counts[candidates[j]]
+		}
+		if candidates[i].id != candidates[j].id {
+			return candidates[i].id < candidates[j].id
+		}
+		return candidates[i].version < candidates[j].version
+	})
+	return &linux.Release{ID: candidates[0].id, VersionID: candidates[0].version}
+}
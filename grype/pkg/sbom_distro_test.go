@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/linux"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+func TestInferDistro(t *testing.T) {
+	osRelease := `PRETTY_NAME="Ubuntu 22.04.3 LTS"
+NAME="Ubuntu"
+VERSION_ID="22.04"
+VERSION="22.04.3 LTS (Jammy Jellyfish)"
+VERSION_CODENAME=jammy
+ID=ubuntu
+ID_LIKE=debian
+`
+	deb := func(purl string) syftPkg.Package {
+		p := syftPkg.Package{Name: "p", Version: "1", Type: syftPkg.DebPkg, PURL: purl}
+		p.SetID()
+		return p
+	}
+
+	tests := []struct {
+		name             string
+		files            map[file.Coordinates]string
+		packages         []syftPkg.Package
+		expected         *linux.Release
+		expectedEvidence string
+	}{
+		{
+			name: "no evidence",
+			packages: []syftPkg.Package{
+				{Name: "lodash", Version: "4.17.20", Type: syftPkg.NpmPkg, PURL: "pkg:npm/lodash@4.17.20"},
+			},
+		},
+		{
+			name: "os-release contents as recorded by syft",
+			files: map[file.Coordinates]string{
+				{RealPath: "/etc/os-release"}: base64.StdEncoding.EncodeToString([]byte(osRelease)),
+			},
+			packages: []syftPkg.Package{deb("pkg:deb/debian/libc6@2.31?distro=debian-11")},
+			expected: &linux.Release{
+				PrettyName:      "Ubuntu 22.04.3 LTS",
+				Name:            "Ubuntu",
+				ID:              "ubuntu",
+				IDLike:          []string{"debian"},
+				Version:         "22.04.3 LTS (Jammy Jellyfish)",
+				VersionID:       "22.04",
+				VersionCodename: "jammy",
+			},
+			expectedEvidence: DistroFromOSRelease,
+		},
+		{
+			name: "plain os-release contents",
+			files: map[file.Coordinates]string{
+				{RealPath: "/usr/lib/os-release"}: "ID=alpine\nVERSION_ID=3.20.1\n",
+			},
+			expected:         &linux.Release{ID: "alpine", VersionID: "3.20.1"},
+			expectedEvidence: DistroFromOSRelease,
+		},
+		{
+			name: "most common distro of the purls",
+			packages: []syftPkg.Package{
+				deb("pkg:deb/debian/libc6@2.31?distro=debian-11"),
+				deb("pkg:deb/debian/openssl@1.1.1?distro=debian-11"),
+				deb("pkg:deb/debian/zlib@1.2?distro=debian-12"),
+				// purls without a distro version only count when no purl has one
+				deb("pkg:deb/debian/bash@5.1"),
+				deb("pkg:deb/debian/tar@1.34"),
+				deb("pkg:deb/debian/gzip@1.10"),
+			},
+			expected:         &linux.Release{ID: "debian", VersionID: "11"},
+			expectedEvidence: DistroFromPURLs,
+		},
+		{
+			name: "rolling distro",
+			packages: []syftPkg.Package{
+				{Name: "busybox", Version: "1.36", Type: syftPkg.ApkPkg, PURL: "pkg:apk/wolfi/busybox@1.36"},
+			},
+			expected:         &linux.Release{ID: "wolfi"},
+			expectedEvidence: DistroFromPURLs,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &sbom.SBOM{Artifacts: sbom.Artifacts{
+				Packages:     syftPkg.NewCollection(test.packages...),
+				FileContents: test.files,
+			}}
+			release, evidence := inferDistro(s)
+			assert.Equal(t, test.expected, release)
+			assert.Equal(t, test.expectedEvidence, evidence)
+		})
+	}
+}
+
+func TestSyftSBOMProvider_inferredDistro(t *testing.T) {
+	packages, context, _, err := syftSBOMProvider("test-fixtures/sbom-distro/no-distro.cdx.json", ProviderConfig{})
+	require.NoError(t, err)
+	assert.Len(t, packages, 3)
+	assert.Equal(t, &linux.Release{ID: "debian", VersionID: "11"}, context.Distro)
+	assert.Equal(t, DistroFromPURLs, context.DistroInferredFrom)
+
+	// the distro recorded in an SBOM is used as is
+	_, context, _, err = syftSBOMProvider("test-fixtures/syft-multiple-ecosystems.json", ProviderConfig{})
+	require.NoError(t, err)
+	require.NotNil(t, context.Distro)
+	assert.Empty(t, context.DistroInferredFrom)
+}
@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wagoodman/go-partybus"
+	"github.com/wagoodman/go-progress"
+
+	"github.com/anchore/grype/grype/event"
+	"github.com/anchore/grype/internal/bus"
+)
+
+// sbomDecodingProgress is the progress of the decoding of an SBOM, as published with the SBOMDecodingStarted event:
+// the bytes of the document read, then the number of packages as the stage.
+type sbomDecodingProgress struct {
+	stage     *progress.AtomicStage
+	bytesRead *progress.Manual
+}
+
+// publishSBOMDecoding publishes the start of the decoding of the SBOM read by the reader, whose size is found by
+// seeking to its end.
+func publishSBOMDecoding(userInput string, reader io.ReadSeeker) *sbomDecodingProgress {
+	size := int64(-1)
+	if end, err := reader.Seek(0, io.SeekEnd); err == nil {
+		size = end
+	}
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		size = -1
+	}
+
+	p := &sbomDecodingProgress{
+		stage:     progress.NewAtomicStage("decoding"),
+		bytesRead: progress.NewManual(size),
+	}
+
+	source := userInput
+	if source == "" {
+		source = "stdin"
+	}
+	bus.Publish(partybus.Event{
+		Type:   event.SBOMDecodingStarted,
+		Source: source,
+		Value: progress.StagedProgressable(&struct {
+			progress.Stager
+			progress.Progressable
+		}{
+			Stager:       progress.Stager(p.stage),
+			Progressable: progress.Progressable(p.bytesRead),
+		}),
+	})
+	return p
+}
+
+// decoded records that the document was read, and that its packages are being converted.
+func (p *sbomDecodingProgress) decoded() {
+	if p == nil {
+		return
+	}
+	if size := p.bytesRead.Size(); size >= 0 {
+		p.bytesRead.Set(size)
+	}
+	p.stage.Set("converting packages")
+}
+
+// done records the number of packages of the SBOM, once converted.
+func (p *sbomDecodingProgress) done(packages int) {
+	if p == nil {
+		return
+	}
+	p.stage.Set(fmt.Sprintf("%d packages", packages))
+	p.bytesRead.SetCompleted()
+}
+
+func (p *sbomDecodingProgress) failed(err error) {
+	if p == nil {
+		return
+	}
+	p.bytesRead.SetError(err)
+}
+
+// progressReadSeeker reports the furthest offset read in the document, since the decoders seek back to re-read the
+// document (e.g. to identify its format first).
+type progressReadSeeker struct {
+	io.ReadSeeker
+	offset   int64
+	progress *progress.Manual
+}
+
+func (r *progressReadSeeker) Read(b []byte) (int, error) {
+	n, err := r.ReadSeeker.Read(b)
+	r.offset += int64(n)
+	if r.offset > r.progress.Current() {
+		r.progress.Set(r.offset)
+	}
+	return n, err
+}
+
+func (r *progressReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.ReadSeeker.Seek(offset, whence)
+	if err == nil {
+		r.offset = pos
+	}
+	return pos, err
+}
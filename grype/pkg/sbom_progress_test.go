@@ -0,0 +1,43 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wagoodman/go-partybus"
+	"github.com/wagoodman/go-progress"
+
+	"github.com/anchore/grype/grype/event"
+	"github.com/anchore/grype/internal/bus"
+)
+
+type sbomDecodingListener struct {
+	source string
+	prog   progress.StagedProgressable
+}
+
+func (l *sbomDecodingListener) Publish(e partybus.Event) {
+	if e.Type != event.SBOMDecodingStarted {
+		return
+	}
+	l.source, _ = e.Source.(string)
+	l.prog, _ = e.Value.(progress.StagedProgressable)
+}
+
+func TestSyftSBOMProvider_publishesDecodingProgress(t *testing.T) {
+	listener := &sbomDecodingListener{}
+	bus.Set(listener)
+	defer bus.Set(nil)
+
+	packages, _, _, err := syftSBOMProvider("sbom:test-fixtures/syft-multiple-ecosystems.json", ProviderConfig{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "sbom:test-fixtures/syft-multiple-ecosystems.json", listener.source)
+	require.NotNil(t, listener.prog)
+	assert.Positive(t, listener.prog.Size())
+	assert.Equal(t, listener.prog.Size(), listener.prog.Current())
+	assert.True(t, progress.IsErrCompleted(listener.prog.Error()))
+	assert.Equal(t, "3 packages", listener.prog.Stage())
+	assert.Len(t, packages, 3)
+}
@@ -0,0 +1,161 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/CycloneDX/cyclonedx-go"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/format/cyclonedxjson"
+	"github.com/anchore/syft/syft/format/cyclonedxxml"
+	"github.com/anchore/syft/syft/format/spdxjson"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// Property is a custom property of a package passed through from the SBOM it was read from (e.g. a team owner or a
+// service name set as a CycloneDX property or an SPDX annotation), so that it stays attached to the findings.
+type Property struct {
+	Name  string
+	Value string
+}
+
+// syftPropertyPrefix is the prefix of the CycloneDX properties that syft encodes its own package data in, which are
+// decoded into the package rather than passed through.
+const syftPropertyPrefix = "syft:"
+
+// sbomPropertiesKey identifies a package of an SBOM as decoded by syft.
+type sbomPropertiesKey struct {
+	name    string
+	version string
+	purl    string
+}
+
+// sbomProperties are the custom properties of the packages of an SBOM.
+type sbomProperties map[sbomPropertiesKey][]Property
+
+// readSBOMProperties reads the custom properties of the packages of a CycloneDX (JSON or XML) or SPDX JSON document,
+// which syft does not decode. Other formats have none.
+func readSBOMProperties(formatID sbom.FormatID, contents []byte) sbomProperties {
+	var (
+		properties sbomProperties
+		err        error
+	)
+	switch formatID {
+	case cyclonedxjson.ID:
+		properties, err = cycloneDXProperties(contents, cyclonedx.BOMFileFormatJSON)
+	case cyclonedxxml.ID:
+		properties, err = cycloneDXProperties(contents, cyclonedx.BOMFileFormatXML)
+	case spdxjson.ID:
+		properties, err = spdxJSONProperties(contents)
+	}
+	if err != nil {
+		log.WithFields("format", formatID, "error", err).Debug("unable to read the package properties of the SBOM")
+		return nil
+	}
+	return properties
+}
+
+func cycloneDXProperties(contents []byte, fileFormat cyclonedx.BOMFileFormat) (sbomProperties, error) {
+	bom := cyclonedx.NewBOM()
+	if err := cyclonedx.NewBOMDecoder(bytes.NewReader(contents), fileFormat).Decode(bom); err != nil {
+		return nil, err
+	}
+
+	properties := make(sbomProperties)
+	var collect func(c *cyclonedx.Component)
+	collect = func(c *cyclonedx.Component) {
+		if c.Properties != nil {
+			key := sbomPropertiesKey{name: c.Name, version: c.Version, purl: c.PackageURL}
+			for _, p := range *c.Properties {
+				if strings.HasPrefix(p.Name, syftPropertyPrefix) {
+					continue
+				}
+				properties[key] = append(properties[key], Property{Name: p.Name, Value: p.Value})
+			}
+		}
+		if c.Components != nil {
+			for i := range *c.Components {
+				collect(&(*c.Components)[i])
+			}
+		}
+	}
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		collect(bom.Metadata.Component)
+	}
+	if bom.Components != nil {
+		for i := range *bom.Components {
+			collect(&(*bom.Components)[i])
+		}
+	}
+	return properties, nil
+}
+
+// spdxJSONProperties reads the annotations of the packages of an SPDX JSON document: an annotation whose comment is of
+// the form "<name>=<value>" is the property of that name, any other annotation is an "annotation" property.
+func spdxJSONProperties(contents []byte) (sbomProperties, error) {
+	var doc struct {
+		Packages []struct {
+			Name         string `json:"name"`
+			Version      string `json:"versionInfo"`
+			ExternalRefs []struct {
+				Type    string `json:"referenceType"`
+				Locator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+			Annotations []struct {
+				Comment string `json:"comment"`
+			} `json:"annotations"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(contents, &doc); err != nil {
+		return nil, err
+	}
+
+	properties := make(sbomProperties)
+	for _, p := range doc.Packages {
+		key := sbomPropertiesKey{name: p.Name, version: p.Version}
+		for _, r := range p.ExternalRefs {
+			if r.Type == "purl" {
+				key.purl = r.Locator
+				break
+			}
+		}
+		for _, a := range p.Annotations {
+			property := Property{Name: "annotation", Value: a.Comment}
+			if name, value, ok := strings.Cut(a.Comment, "="); ok && strings.TrimSpace(name) != "" && !strings.ContainsAny(name, " \t\n") {
+				property = Property{Name: name, Value: value}
+			}
+			properties[key] = append(properties[key], property)
+		}
+	}
+	return properties, nil
+}
+
+// apply attaches the properties to the packages read from the SBOM, found by name, version and package URL (or by
+// name and version when the package URL was not kept as-is).
+func (s sbomProperties) apply(packages []Package) {
+	if len(s) == 0 {
+		return
+	}
+	byNameVersion := make(map[sbomPropertiesKey][]Property)
+	for key, properties := range s {
+		nameVersion := sbomPropertiesKey{name: key.name, version: key.version}
+		if _, ok := byNameVersion[nameVersion]; ok {
+			// the packages of the same name and version are only told apart by their package URL
+			byNameVersion[nameVersion] = nil
+			continue
+		}
+		byNameVersion[nameVersion] = properties
+	}
+	for i := range packages {
+		p := &packages[i]
+		properties, ok := s[sbomPropertiesKey{name: p.Name, version: p.Version, purl: p.PURL}]
+		if !ok {
+			properties = byNameVersion[sbomPropertiesKey{name: p.Name, version: p.Version}]
+		}
+		if len(properties) > 0 {
+			p.Properties = append([]Property(nil), properties...)
+		}
+	}
+}
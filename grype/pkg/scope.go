@@ -0,0 +1,181 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/source"
+)
+
+const lastLayersPrefix = "last-"
+
+// Scope restricts vulnerability matching to the packages found in part of the scan target, for example only the
+// application layers that were added on top of a vetted base image. Unlike exclusions, the rest of the target is
+// still cataloged, so the distro and package ownership are detected the same way as for a full scan.
+type Scope struct {
+	// IncludePaths are globs of which at least one must match a location of a package for it to be kept (all
+	// packages are kept when empty).
+	IncludePaths []string
+
+	// ExcludePaths are globs that drop a package when all of its locations match one of them.
+	ExcludePaths []string
+
+	// LastLayers keeps only the packages with a location in the last N layers of an image (all layers when zero).
+	// A package whose entry in a package DB is also found in an earlier layer was not introduced by the last layers
+	// and is dropped, which is only known when each layer is cataloged (the all-layers scope). With the squashed scope,
+	// the packages of a package DB rewritten by one of the last layers are all located in that layer.
+	LastLayers int
+}
+
+// IsSet returns true if the scope restricts the packages in any way.
+func (s Scope) IsSet() bool {
+	return len(s.IncludePaths) > 0 || len(s.ExcludePaths) > 0 || s.LastLayers > 0
+}
+
+// ParseLayers returns the number of last image layers selected by the given value ("last-N"), where an empty value
+// or "all" selects all layers (zero).
+func ParseLayers(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "all" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(value, lastLayersPrefix))
+	if !strings.HasPrefix(value, lastLayersPrefix) || err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid layer selection %q (options: all, last-N)", value)
+	}
+	return n, nil
+}
+
+// filterPackageScope returns the packages within the given scope. Packages without any location cannot be placed
+// and are only kept when the scope does not restrict by path or layer inclusion.
+func filterPackageScope(packages []Package, ctx Context, scope Scope) ([]Package, error) {
+	if !scope.IsSet() {
+		return packages, nil
+	}
+
+	var layers map[string]bool
+	if scope.LastLayers > 0 {
+		var err error
+		if layers, err = lastLayers(ctx, scope.LastLayers); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(scope.ExcludePaths) > 0 {
+		var err error
+		if packages, err = filterPackageExclusions(packages, scope.ExcludePaths); err != nil {
+			return nil, err
+		}
+	}
+
+	var earlier map[string]bool
+	if layers != nil {
+		earlier = outsideLayers(packages, layers)
+	}
+
+	var out []Package
+	for _, p := range packages {
+		keep, err := inScope(p, scope.IncludePaths, layers)
+		if err != nil {
+			return nil, err
+		}
+		if keep && earlier[evidenceKey(p)] {
+			// the package was not introduced by the selected layers: an earlier layer has the same entry in the same
+			// package DB, and the selected layer only rewrote the DB (e.g. to add other packages)
+			keep = false
+		}
+		if keep {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// outsideLayers returns the evidence keys (see evidenceKey) of the packages with evidence outside the given layers.
+// Such packages are only found when each layer is cataloged (the all-layers scope): with the squashed scope, a package
+// DB rewritten by an upper layer is only seen in that layer, with all of its packages.
+func outsideLayers(packages []Package, layers map[string]bool) map[string]bool {
+	keys := make(map[string]bool)
+	for _, p := range packages {
+		for _, l := range evidenceLocations(p.Locations) {
+			if !layers[l.FileSystemID] {
+				keys[evidenceKey(p)] = true
+				break
+			}
+		}
+	}
+	return keys
+}
+
+// evidenceKey identifies the entry of a package in the file holding its evidence (e.g. a package DB), the same in
+// every layer having the entry.
+func evidenceKey(p Package) string {
+	var paths []string
+	for _, l := range evidenceLocations(p.Locations) {
+		paths = append(paths, l.RealPath)
+	}
+	sort.Strings(paths)
+	return strings.Join([]string{string(p.Type), p.Name, p.Version, strings.Join(paths, ",")}, "|")
+}
+
+// inScope returns true if any location of the package matches one of the include globs (when given) and is in one
+// of the layers (when given).
+func inScope(p Package, includes []string, layers map[string]bool) (bool, error) {
+	if len(includes) == 0 && layers == nil {
+		return true, nil
+	}
+	for _, location := range p.Locations.ToSlice() {
+		if layers != nil && !layers[location.FileSystemID] {
+			continue
+		}
+		included, err := matchesAny(location, includes)
+		if err != nil {
+			return false, err
+		}
+		if included {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func matchesAny(location file.Location, globs []string) (bool, error) {
+	if len(globs) == 0 {
+		return true, nil
+	}
+	for _, glob := range globs {
+		match, err := locationMatches(location, glob)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lastLayers returns the digests of the last n layers of the scanned image.
+func lastLayers(ctx Context, n int) (map[string]bool, error) {
+	if ctx.Source == nil {
+		return nil, errors.New("layer selection is only supported for container images")
+	}
+	metadata, ok := ctx.Source.Metadata.(source.ImageMetadata)
+	if !ok || len(metadata.Layers) == 0 {
+		return nil, errors.New("layer selection is only supported for container images")
+	}
+
+	start := len(metadata.Layers) - n
+	if start < 0 {
+		start = 0
+	}
+	layers := make(map[string]bool)
+	for _, l := range metadata.Layers[start:] {
+		layers[l.Digest] = true
+	}
+	return layers, nil
+}
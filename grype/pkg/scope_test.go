@@ -0,0 +1,169 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/source"
+)
+
+func scopedPackage(name string, locations ...file.Location) Package {
+	return Package{Name: name, Locations: file.NewLocationSet(locations...)}
+}
+
+func packageNames(packages []Package) []string {
+	var names []string
+	for _, p := range packages {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func Test_filterPackageScope(t *testing.T) {
+	imageContext := Context{
+		Source: &source.Description{
+			Metadata: source.ImageMetadata{
+				Layers: []source.LayerMetadata{{Digest: "sha256:base"}, {Digest: "sha256:runtime"}, {Digest: "sha256:app"}},
+			},
+		},
+	}
+
+	packages := []Package{
+		scopedPackage("openssl", file.NewLocationFromCoordinates(file.NewCoordinates("/lib/apk/db/installed", "sha256:base"))),
+		scopedPackage("python", file.NewLocationFromCoordinates(file.NewCoordinates("/usr/lib/python3.11/METADATA", "sha256:runtime"))),
+		scopedPackage("flask",
+			file.NewLocationFromCoordinates(file.NewCoordinates("/app/requirements.txt", "sha256:app")),
+			file.NewLocationFromCoordinates(file.NewCoordinates("/app/tests/requirements.txt", "sha256:app")),
+		),
+		scopedPackage("pytest", file.NewLocationFromCoordinates(file.NewCoordinates("/app/tests/requirements.txt", "sha256:app"))),
+		scopedPackage("unplaced"),
+	}
+
+	tests := []struct {
+		name    string
+		ctx     Context
+		scope   Scope
+		want    []string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "no scope",
+			ctx:  imageContext,
+			want: []string{"openssl", "python", "flask", "pytest", "unplaced"},
+		},
+		{
+			name:  "include paths",
+			ctx:   imageContext,
+			scope: Scope{IncludePaths: []string{"/app/**"}},
+			want:  []string{"flask", "pytest"},
+		},
+		{
+			name:  "exclude paths drop packages with all locations excluded",
+			ctx:   imageContext,
+			scope: Scope{ExcludePaths: []string{"/app/tests/**"}},
+			want:  []string{"openssl", "python", "flask", "unplaced"},
+		},
+		{
+			name:  "last layer",
+			ctx:   imageContext,
+			scope: Scope{LastLayers: 1},
+			want:  []string{"flask", "pytest"},
+		},
+		{
+			name:  "last layers beyond the image",
+			ctx:   imageContext,
+			scope: Scope{LastLayers: 10},
+			want:  []string{"openssl", "python", "flask", "pytest"},
+		},
+		{
+			name:  "layers and paths",
+			ctx:   imageContext,
+			scope: Scope{LastLayers: 2, IncludePaths: []string{"/usr/**"}},
+			want:  []string{"python"},
+		},
+		{
+			name:    "layers without an image",
+			ctx:     Context{Source: &source.Description{Metadata: source.DirectoryMetadata{Path: "."}}},
+			scope:   Scope{LastLayers: 1},
+			wantErr: require.Error,
+		},
+		{
+			name:    "invalid glob",
+			ctx:     imageContext,
+			scope:   Scope{IncludePaths: []string{"/app/["}},
+			wantErr: require.Error,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			got, err := filterPackageScope(packages, test.ctx, test.scope)
+			test.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, test.want, packageNames(got))
+		})
+	}
+}
+
+func Test_filterPackageScope_rewrittenPackageDB(t *testing.T) {
+	ctx := Context{
+		Source: &source.Description{
+			Metadata: source.ImageMetadata{
+				Layers: []source.LayerMetadata{{Digest: "sha256:base"}, {Digest: "sha256:app"}},
+			},
+		},
+	}
+	installed := func(layer string) file.Location {
+		return file.NewLocationFromCoordinates(file.NewCoordinates("/lib/apk/db/installed", layer))
+	}
+
+	// as cataloged with the all-layers scope: the package DB of each layer, rewritten by "apk add curl" in the app layer
+	packages := []Package{
+		{Name: "openssl", Version: "3.1.4-r0", Locations: file.NewLocationSet(installed("sha256:base"))},
+		{Name: "openssl", Version: "3.1.4-r0", Locations: file.NewLocationSet(installed("sha256:app"))},
+		// the same package in both layers, merged into a single package
+		{Name: "busybox", Version: "1.36.1-r2", Locations: file.NewLocationSet(installed("sha256:base"), installed("sha256:app"))},
+		// upgraded in the app layer
+		{Name: "zlib", Version: "1.3-r0", Locations: file.NewLocationSet(installed("sha256:base"))},
+		{Name: "zlib", Version: "1.3.1-r0", Locations: file.NewLocationSet(installed("sha256:app"))},
+		{Name: "curl", Version: "8.5.0-r0", Locations: file.NewLocationSet(installed("sha256:app"))},
+	}
+
+	got, err := filterPackageScope(packages, ctx, Scope{LastLayers: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zlib", "curl"}, packageNames(got))
+	assert.Equal(t, "1.3.1-r0", got[0].Version)
+}
+
+func TestParseLayers(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr require.ErrorAssertionFunc
+	}{
+		{value: "", want: 0},
+		{value: "all", want: 0},
+		{value: "last-1", want: 1},
+		{value: " Last-3 ", want: 3},
+		{value: "last-0", wantErr: require.Error},
+		{value: "3", wantErr: require.Error},
+		{value: "last-n", wantErr: require.Error},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			got, err := ParseLayers(test.value)
+			test.wantErr(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
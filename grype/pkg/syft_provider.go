@@ -15,7 +15,8 @@ import (
 )
 
 func syftProvider(userInput string, config ProviderConfig) ([]Package, Context, *sbom.SBOM, error) {
-	src, err := getSource(userInput, config)
+	ctx := config.context()
+	src, err := getSource(ctx, userInput, config)
 	if err != nil {
 		return nil, Context{}, nil, err
 	}
@@ -28,7 +29,12 @@ func syftProvider(userInput string, config ProviderConfig) ([]Package, Context,
 		}
 	}()
 
-	s, err := syft.CreateSBOM(context.Background(), src, config.SBOMOptions)
+	s, err := catalog(ctx, func(ctx context.Context) (*sbom.SBOM, error) {
+		if config.LayerCache != nil {
+			return config.LayerCache.createSBOM(ctx, src, config.SBOMOptions)
+		}
+		return syft.CreateSBOM(ctx, src, config.SBOMOptions)
+	})
 	if err != nil {
 		return nil, Context{}, nil, err
 	}
@@ -37,7 +43,7 @@ func syftProvider(userInput string, config ProviderConfig) ([]Package, Context,
 		return nil, Context{}, nil, errors.New("no SBOM provided")
 	}
 
-	pkgCatalog := removePackagesByOverlap(s.Artifacts.Packages, s.Relationships, s.Artifacts.LinuxDistribution)
+	pkgCatalog := removePackagesByOverlap(s.Artifacts.Packages, s.Relationships, s.Artifacts.LinuxDistribution, config.OverlapPrecedence)
 
 	srcDescription := src.Describe()
 
@@ -50,7 +56,31 @@ func syftProvider(userInput string, config ProviderConfig) ([]Package, Context,
 	return packages, pkgCtx, s, nil
 }
 
-func getSource(userInput string, config ProviderConfig) (source.Source, error) {
+// catalog runs the cataloging, returning the cause of the context as soon as the context is done. The catalogers of syft
+// only observe the context between some of their steps, so the cataloging is abandoned (and its result discarded)
+// rather than waited for.
+func catalog(ctx context.Context, create func(context.Context) (*sbom.SBOM, error)) (*sbom.SBOM, error) {
+	type result struct {
+		s   *sbom.SBOM
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		s, err := create(ctx)
+		done <- result{s: s, err: err}
+	}()
+	select {
+	case r := <-done:
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		return r.s, r.err
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
+
+func getSource(ctx context.Context, userInput string, config ProviderConfig) (source.Source, error) {
 	if config.SBOMOptions.Search.Scope == "" {
 		return nil, errDoesNotProvide
 	}
@@ -71,7 +101,7 @@ func getSource(userInput string, config ProviderConfig) (source.Source, error) {
 		userInput = newUserInput
 	}
 
-	return syft.GetSource(context.Background(), userInput, syft.DefaultGetSourceConfig().
+	return syft.GetSource(ctx, userInput, syft.DefaultGetSourceConfig().
 		WithSources(sources...).
 		WithDefaultImagePullSource(config.DefaultImagePullSource).
 		WithAlias(source.Alias{Name: config.Name}).
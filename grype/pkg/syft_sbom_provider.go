@@ -13,7 +13,6 @@ import (
 
 	"github.com/anchore/grype/internal"
 	"github.com/anchore/grype/internal/log"
-	"github.com/anchore/syft/syft/format"
 	"github.com/anchore/syft/syft/sbom"
 )
 
@@ -26,16 +25,29 @@ func (e errEmptySBOM) Error() string {
 }
 
 func syftSBOMProvider(userInput string, config ProviderConfig) ([]Package, Context, *sbom.SBOM, error) {
-	s, err := getSBOM(userInput)
+	s, properties, decoding, err := getSBOM(userInput, NewSBOMDecoder(config.SBOMDecoders...))
 	if err != nil {
 		return nil, Context{}, nil, err
 	}
 
-	catalog := removePackagesByOverlap(s.Artifacts.Packages, s.Relationships, s.Artifacts.LinuxDistribution)
+	release := s.Artifacts.LinuxDistribution
+	var inferredFrom string
+	if release == nil {
+		if release, inferredFrom = inferDistro(s); release != nil {
+			log.WithFields("distro", release.String(), "from", inferredFrom).Info("inferred the distro missing from the SBOM")
+		}
+	}
+
+	catalog := removePackagesByOverlap(s.Artifacts.Packages, s.Relationships, release, config.OverlapPrecedence)
+
+	packages := FromCollection(catalog, config.SynthesisConfig)
+	properties.apply(packages)
+	decoding.done(len(packages))
 
-	return FromCollection(catalog, config.SynthesisConfig), Context{
-		Source: &s.Source,
-		Distro: s.Artifacts.LinuxDistribution,
+	return packages, Context{
+		Source:             &s.Source,
+		Distro:             release,
+		DistroInferredFrom: inferredFrom,
 	}, s, nil
 }
 
@@ -51,22 +63,36 @@ type inputInfo struct {
 	Scheme      string
 }
 
-func getSBOM(userInput string) (*sbom.SBOM, error) {
+// getSBOM decodes the SBOM of the input, along with the custom properties of its packages. The progress of the decoding
+// is published, and is left to the caller to complete once the packages are converted.
+func getSBOM(userInput string, decoder sbom.FormatDecoder) (*sbom.SBOM, sbomProperties, *sbomDecodingProgress, error) {
 	reader, err := getSBOMReader(userInput)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	s, fmtID, _, err := format.Decode(reader)
+	decoding := publishSBOMDecoding(userInput, reader)
+	s, fmtID, _, err := decoder.Decode(&progressReadSeeker{ReadSeeker: reader, progress: decoding.bytesRead})
 	if err != nil {
-		return nil, fmt.Errorf("unable to decode sbom: %w", err)
+		err = fmt.Errorf("unable to decode sbom: %w", err)
+		decoding.failed(err)
+		return nil, nil, nil, err
 	}
 
 	if fmtID == "" || s == nil {
-		return nil, errDoesNotProvide
+		decoding.failed(errDoesNotProvide)
+		return nil, nil, nil, errDoesNotProvide
+	}
+	decoding.decoded()
+
+	var properties sbomProperties
+	if _, err := reader.Seek(0, io.SeekStart); err == nil {
+		if contents, err := io.ReadAll(reader); err == nil {
+			properties = readSBOMProperties(fmtID, contents)
+		}
 	}
 
-	return s, nil
+	return s, properties, decoding, nil
 }
 
 func getSBOMReader(userInput string) (r io.ReadSeeker, err error) {
@@ -42,6 +42,7 @@ func TestParseSyftJSON(t *testing.T) {
 						cpe.Must("cpe:2.3:a:alpine:alpine_baselayout:3.2.0-r6:*:*:*:*:*:*:*", ""),
 					},
 					PURL: "pkg:alpine/alpine-baselayout@3.2.0-r6?arch=x86_64",
+					Arch: "amd64",
 					Upstreams: []UpstreamPackage{
 						{
 							Name: "alpine-baselayout",
@@ -168,6 +169,7 @@ func TestParseSyftJSON(t *testing.T) {
 						cpe.Must("cpe:2.3:a:fake:fake:1.2.0:*:*:*:*:*:*:*", ""),
 					},
 					PURL: "pkg:deb/debian/fake@1.2.0?arch=x86_64",
+					Arch: "amd64",
 					Upstreams: []UpstreamPackage{
 						{
 							Name:    "a-source",
@@ -194,6 +196,7 @@ func TestParseSyftJSON(t *testing.T) {
 						cpe.Must("cpe:2.3:a:gmp:gmp:6.2.0-r0:*:*:*:*:*:*:*", ""),
 					},
 					PURL: "pkg:alpine/gmp@6.2.0-r0?arch=x86_64",
+					Arch: "amd64",
 					Metadata: JavaMetadata{
 						PomArtifactID: "aid",
 						PomGroupID:    "gid",
@@ -360,3 +363,46 @@ func TestGetSBOMReader_EmptySBOM(t *testing.T) {
 	_, err = getSBOMReader(userInput)
 	assert.ErrorAs(t, err, &errEmptySBOM{})
 }
+
+func TestSyftSBOMProvider_properties(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string][]Property
+	}{
+		{
+			name:  "cyclonedx properties",
+			input: "sbom:test-fixtures/sbom-properties/properties.cdx.json",
+			expected: map[string][]Property{
+				"lodash": {
+					{Name: "acme:team", Value: "payments"},
+					{Name: "acme:service", Value: "checkout"},
+				},
+				"minimist": nil,
+			},
+		},
+		{
+			name:  "spdx annotations",
+			input: "sbom:test-fixtures/sbom-properties/properties.spdx.json",
+			expected: map[string][]Property{
+				"lodash": {
+					{Name: "team", Value: "payments"},
+					{Name: "annotation", Value: "reviewed by the security team"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			packages, _, _, err := syftSBOMProvider(test.input, ProviderConfig{})
+			require.NoError(t, err)
+
+			actual := make(map[string][]Property)
+			for _, p := range packages {
+				actual[p.Name] = p.Properties
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
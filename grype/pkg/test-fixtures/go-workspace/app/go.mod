@@ -0,0 +1,9 @@
+module example.com/app
+
+go 1.22
+
+require (
+	example.com/lib v0.0.0-00010101000000-000000000000
+	github.com/example/forked v1.0.0
+	golang.org/x/net v0.17.0
+)
@@ -0,0 +1,10 @@
+module example.com/lib
+
+go 1.22
+
+require (
+	golang.org/x/net v0.17.0
+	golang.org/x/text v0.13.0
+)
+
+replace golang.org/x/text => ../vendored/text
@@ -0,0 +1,5 @@
+module example.com/tools
+
+go 1.22
+
+require golang.org/x/net v0.17.0
@@ -0,0 +1,8 @@
+#ifndef SRC_NODE_VERSION_H_
+#define SRC_NODE_VERSION_H_
+
+#define NODE_MAJOR_VERSION 18
+#define NODE_MINOR_VERSION 19
+#define NODE_PATCH_VERSION 1
+
+#endif  // SRC_NODE_VERSION_H_
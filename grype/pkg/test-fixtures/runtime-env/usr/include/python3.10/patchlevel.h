@@ -0,0 +1,2 @@
+/* Version as a string */
+#define PY_VERSION      	"3.10.12"
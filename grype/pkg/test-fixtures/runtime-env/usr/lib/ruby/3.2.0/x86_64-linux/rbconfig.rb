@@ -0,0 +1,4 @@
+module RbConfig
+  CONFIG = {}
+  CONFIG["RUBY_PROGRAM_VERSION"] = "3.2.2"
+end
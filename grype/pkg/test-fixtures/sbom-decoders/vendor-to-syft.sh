@@ -0,0 +1,7 @@
+#!/bin/sh
+# converts the vendor format into Syft JSON (by always printing the same Syft JSON document)
+input=$(cat)
+case "$input" in
+  *'"vendorFormat"'*) cat "$(dirname "$0")/../syft-spring.json" ;;
+  *) echo "not a vendor document" >&2; exit 1 ;;
+esac
@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"github.com/anchore/packageurl-go"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// package types that grype understands in addition to those defined by syft
+const (
+	// OpkgPkg represents packages installed with opkg (the ipk package manager used by OpenWrt and some Yocto-based
+	// images). Syft does not catalog these packages itself, however they may be provided by SBOMs from other tools.
+	OpkgPkg pkg.Type = "opkg"
+
+	// HomebrewPkg represents formulae installed with Homebrew (typically found on macOS developer machines and CI runners).
+	HomebrewPkg pkg.Type = "homebrew"
+
+	// MacPortsPkg represents ports installed with MacPorts.
+	MacPortsPkg pkg.Type = "macports"
+
+	// RuntimePkg is the package type the matchers of language runtimes and interpreters (e.g. node, python or a JRE)
+	// and the runtime libraries they are commonly linked with (e.g. openssl) are registered for. The packages of these
+	// (see Package.Runtime) keep the binary package type.
+	RuntimePkg pkg.Type = "runtime"
+)
+
+// TypeByName returns the package type for the given purl type, including package types that grype
+// understands but syft does not catalog.
+func TypeByName(name string) pkg.Type {
+	switch name {
+	case "opkg", "ipk":
+		return OpkgPkg
+	case "brew", "homebrew":
+		return HomebrewPkg
+	case "macports":
+		return MacPortsPkg
+	}
+	return pkg.TypeByName(name)
+}
+
+func typeFromPkg(p pkg.Package) pkg.Type {
+	if p.Type != pkg.UnknownPkg && p.Type != "" {
+		return p.Type
+	}
+	// some SBOM formats only convey the package type through the purl
+	purl, err := packageurl.FromString(p.PURL)
+	if err != nil {
+		return p.Type
+	}
+	switch t := TypeByName(purl.Type); t {
+	case OpkgPkg, HomebrewPkg, MacPortsPkg:
+		return t
+	}
+	return p.Type
+}
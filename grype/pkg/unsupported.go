@@ -0,0 +1,36 @@
+package pkg
+
+// UnsupportedReason tells why a package cannot be matched against any vulnerability data.
+type UnsupportedReason string
+
+const (
+	MissingVersion       UnsupportedReason = "missing-version"
+	UnparseableVersion   UnsupportedReason = "unparseable-version"
+	MissingDistro        UnsupportedReason = "missing-distro"
+	UnsupportedDistro    UnsupportedReason = "unsupported-distro"
+	UnsupportedEcosystem UnsupportedReason = "unsupported-ecosystem"
+)
+
+// Description explains the reason to users.
+func (r UnsupportedReason) Description() string {
+	switch r {
+	case MissingVersion:
+		return "the package has no version"
+	case UnparseableVersion:
+		return "the version of the package cannot be parsed"
+	case MissingDistro:
+		return "the distro of the OS package is unknown"
+	case UnsupportedDistro:
+		return "the distro of the OS package is not supported"
+	case UnsupportedEcosystem:
+		return "no vulnerability data covers the ecosystem of the package, and it has no CPEs"
+	}
+	return string(r)
+}
+
+// UnsupportedPackage is a package that was cataloged but cannot be matched against any vulnerability data, so that
+// having no findings for it says nothing about its vulnerabilities.
+type UnsupportedPackage struct {
+	Package Package
+	Reason  UnsupportedReason
+}
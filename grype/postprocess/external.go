@@ -0,0 +1,58 @@
+package postprocess
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/anchore/grype/grype/presenter/models"
+)
+
+// ExternalProcessor post-processes the match document with an external program (a post-processing plugin). The
+// program reads the document as JSON (as written by the JSON output) on stdin and writes the processed document to
+// stdout, exiting with a non-zero status to fail the scan.
+type ExternalProcessor struct {
+	name    string
+	command string
+	args    []string
+}
+
+var _ Processor = (*ExternalProcessor)(nil)
+
+func NewExternalProcessor(name, command string, args ...string) *ExternalProcessor {
+	return &ExternalProcessor{name: name, command: command, args: args}
+}
+
+func (p *ExternalProcessor) Name() string {
+	return p.name
+}
+
+func (p *ExternalProcessor) Process(doc models.Document) (models.Document, error) {
+	var stdin bytes.Buffer
+	enc := json.NewEncoder(&stdin)
+	// prevent > and < from being escaped in the payload
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(&doc); err != nil {
+		return models.Document{}, fmt.Errorf("unable to encode the match document: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(p.command, p.args...)
+	cmd.Stdin = &stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return models.Document{}, fmt.Errorf("%w (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var processed models.Document
+	if err := json.Unmarshal(stdout.Bytes(), &processed); err != nil {
+		return models.Document{}, fmt.Errorf("did not write a valid match document: %w", err)
+	}
+	if processed.Matches == nil {
+		return models.Document{}, fmt.Errorf("did not write a match document (no matches field)")
+	}
+	return processed, nil
+}
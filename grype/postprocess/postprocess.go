@@ -0,0 +1,155 @@
+/*
+Package postprocess runs the post-processors of the results of a scan: programs (or, for applications embedding
+Grype, any Processor) receiving the final match document before it is presented, which may remove matches, change
+their severity and annotate them (e.g. with the criticality of the asset, or an internal risk score), without
+changing the presenters.
+*/
+package postprocess
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/internal/log"
+)
+
+// Processor post-processes the match document of a scan, returning the document to present.
+type Processor interface {
+	// Name identifies the processor in the reasons of the changes it makes.
+	Name() string
+	Process(doc models.Document) (models.Document, error)
+}
+
+// Apply runs the processors in order on the match document of the results, and applies the changes made to the
+// matches of the document to the results (and to the results of each platform):
+//   - the matches removed from the document are ignored, with the processor as the reason
+//   - the matches whose severity was changed get the severity as a severity override
+//   - the annotations set on the matches are kept, to be presented along with the matches
+//
+// Each processor is given the document of the results as the processors before it left them, with their annotations,
+// along with the results of each platform and the EPSS and KEV data of the vulnerabilities. The matches are told apart
+// by their IDs, so the matches added to the document are not presented.
+func Apply(result models.PresenterConfig, processors ...Processor) (models.PresenterConfig, error) {
+	if len(processors) == 0 {
+		return result, nil
+	}
+
+	for _, p := range processors {
+		doc, err := newDocument(result)
+		if err != nil {
+			return result, err
+		}
+		processed, err := p.Process(doc)
+		if err != nil {
+			return result, fmt.Errorf("post-processor %q failed: %w", p.Name(), err)
+		}
+		result = apply(result, p.Name(), doc, processed)
+	}
+	return result, nil
+}
+
+func newDocument(result models.PresenterConfig) (models.Document, error) {
+	doc, err := models.NewDocumentAt(models.ReportTime(result.Now), result.ID, result.Packages, result.Context, result.Matches, result.IgnoredMatches, result.MetadataProvider,
+		result.AppConfig, result.DBStatus)
+	if err != nil {
+		return doc, err
+	}
+	if doc.Platforms, err = models.NewPlatformDocuments(result.Platforms, result.MetadataProvider); err != nil {
+		return doc, err
+	}
+	doc.Environment = result.Environment
+	doc.Manifest = result.Manifest
+	doc.Enrichment = models.NewEnrichment(result.Enrichment, result.Matches)
+	models.AnnotateMatches(doc.Matches, result.Annotations)
+	for i := range doc.Platforms {
+		models.AnnotateMatches(doc.Platforms[i].Matches, result.Annotations)
+	}
+	return doc, nil
+}
+
+func apply(result models.PresenterConfig, name string, doc, processed models.Document) models.PresenterConfig {
+	before := make(map[string]models.Match, len(doc.Matches))
+	for _, m := range doc.Matches {
+		before[m.ID] = m
+	}
+	after := make(map[string]models.Match, len(processed.Matches))
+	for _, m := range processed.Matches {
+		if _, ok := before[m.ID]; !ok {
+			log.WithFields("processor", name, "id", m.ID, "vulnerability", m.Vulnerability.ID).Warn("ignoring a match added by the post-processor")
+			continue
+		}
+		after[m.ID] = m
+	}
+
+	annotations := make(map[string]map[string]string, len(result.Annotations))
+	for id, a := range result.Annotations {
+		annotations[id] = a
+	}
+	for id := range before {
+		if processedMatch, ok := after[id]; ok && len(processedMatch.Annotations) > 0 {
+			annotations[id] = processedMatch.Annotations
+		} else {
+			delete(annotations, id)
+		}
+	}
+
+	c := changes{name: name, before: before, after: after}
+	result.Matches, result.IgnoredMatches = c.apply(result.Matches, result.IgnoredMatches)
+	platforms := make([]models.PlatformResult, len(result.Platforms))
+	for i, r := range result.Platforms {
+		r.Matches, r.IgnoredMatches = c.apply(r.Matches, r.IgnoredMatches)
+		platforms[i] = r
+	}
+	if result.Platforms != nil {
+		result.Platforms = platforms
+	}
+	result.Annotations = annotations
+	return result
+}
+
+// changes are the changes a processor made to the matches of the document, by match ID.
+type changes struct {
+	name   string
+	before map[string]models.Match
+	after  map[string]models.Match
+}
+
+// apply returns the matches with the changes applied, and the ignored matches with the removed matches added. The
+// matches that were not in the document are left as they are.
+func (c changes) apply(matches match.Matches, ignored []match.IgnoredMatch) (match.Matches, []match.IgnoredMatch) {
+	remaining := match.NewMatches()
+	ignored = append([]match.IgnoredMatch(nil), ignored...)
+
+	for _, m := range matches.Sorted() {
+		id := m.ID()
+		original, known := c.before[id]
+		if !known {
+			remaining.Add(m)
+			continue
+		}
+		processedMatch, ok := c.after[id]
+		if !ok {
+			ignored = append(ignored, match.IgnoredMatch{
+				Match: m,
+				AppliedIgnoreRules: []match.IgnoreRule{{
+					Vulnerability: m.Vulnerability.ID,
+					Reason:        fmt.Sprintf("removed by the post-processor %q", c.name),
+				}},
+			})
+			continue
+		}
+
+		severity := processedMatch.Vulnerability.Severity
+		if severity != "" && !strings.EqualFold(severity, original.Vulnerability.Severity) {
+			reason := processedMatch.Vulnerability.SeverityOverrideReason
+			if reason == "" || reason == original.Vulnerability.SeverityOverrideReason {
+				reason = fmt.Sprintf("set by the post-processor %q", c.name)
+			}
+			m.SeverityOverride = &match.SeverityOverride{Severity: severity, Reason: reason}
+		}
+		remaining.Add(m)
+	}
+	return remaining, ignored
+}
@@ -0,0 +1,168 @@
+package postprocess
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/priority"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type processorFunc func(doc models.Document) (models.Document, error)
+
+func (f processorFunc) Name() string {
+	return "test"
+}
+
+func (f processorFunc) Process(doc models.Document) (models.Document, error) {
+	return f(doc)
+}
+
+func newResult() models.PresenterConfig {
+	packages := []pkg.Package{
+		{ID: "openssl", Name: "openssl", Version: "1.1.1", Type: syftPkg.DebPkg},
+		{ID: "lodash", Name: "lodash", Version: "4.17.20", Type: syftPkg.NpmPkg},
+	}
+	return models.PresenterConfig{
+		Matches: match.NewMatches(
+			match.Match{
+				Vulnerability: vulnerability.Vulnerability{ID: "CVE-1999-0001", Namespace: "source-1"},
+				Package:       packages[0],
+			},
+			match.Match{
+				Vulnerability: vulnerability.Vulnerability{ID: "CVE-1999-0002", Namespace: "source-2"},
+				Package:       packages[1],
+			},
+		),
+		Packages:         packages,
+		MetadataProvider: models.NewMetadataMock(),
+	}
+}
+
+func TestApply(t *testing.T) {
+	result := newResult()
+	processor := processorFunc(func(doc models.Document) (models.Document, error) {
+		var matches []models.Match
+		for _, m := range doc.Matches {
+			switch m.Vulnerability.ID {
+			case "CVE-1999-0001":
+				m.Vulnerability.Severity = "Critical"
+				m.Annotations = map[string]string{"asset-criticality": "tier-1"}
+				matches = append(matches, m)
+			case "CVE-1999-0002":
+				// removed
+			}
+		}
+		matches = append(matches, models.Match{ID: "added", Vulnerability: models.Vulnerability{VulnerabilityMetadata: models.VulnerabilityMetadata{ID: "CVE-2000-0001"}}})
+		doc.Matches = matches
+		return doc, nil
+	})
+
+	processed, err := Apply(result, processor)
+	require.NoError(t, err)
+
+	remaining := processed.Matches.Sorted()
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "CVE-1999-0001", remaining[0].Vulnerability.ID)
+	require.NotNil(t, remaining[0].SeverityOverride)
+	assert.Equal(t, "Critical", remaining[0].SeverityOverride.Severity)
+	assert.Equal(t, `set by the post-processor "test"`, remaining[0].SeverityOverride.Reason)
+	assert.Equal(t, map[string]map[string]string{
+		remaining[0].ID(): {"asset-criticality": "tier-1"},
+	}, processed.Annotations)
+
+	require.Len(t, processed.IgnoredMatches, 1)
+	assert.Equal(t, "CVE-1999-0002", processed.IgnoredMatches[0].Vulnerability.ID)
+	assert.Equal(t, `removed by the post-processor "test"`, processed.IgnoredMatches[0].AppliedIgnoreRules[0].Reason)
+
+	// the original results are left as-is
+	assert.Equal(t, 2, result.Matches.Count())
+	assert.Empty(t, result.IgnoredMatches)
+
+	doc, err := models.NewDocument(processed.ID, processed.Packages, processed.Context, processed.Matches, processed.IgnoredMatches, processed.MetadataProvider, nil, nil)
+	require.NoError(t, err)
+	models.AnnotateMatches(doc.Matches, processed.Annotations)
+	assert.Equal(t, "Critical", doc.Matches[0].Vulnerability.Severity)
+	assert.Equal(t, "Low", doc.Matches[0].Vulnerability.OriginalSeverity)
+	assert.Equal(t, "tier-1", doc.Matches[0].Annotations["asset-criticality"])
+}
+
+func TestApply_chained(t *testing.T) {
+	result := newResult()
+	result.Platforms = []models.PlatformResult{{Platform: "linux/amd64", Matches: result.Matches, Packages: result.Packages}}
+	enrichment, err := priority.ParseKEV(strings.NewReader(`{"vulnerabilities":[{"cveID":"CVE-1999-0001","dateAdded":"2024-01-02"}]}`))
+	require.NoError(t, err)
+	result.Enrichment = enrichment
+
+	annotate := processorFunc(func(doc models.Document) (models.Document, error) {
+		for i := range doc.Matches {
+			doc.Matches[i].Annotations = map[string]string{"asset-criticality": "tier-1"}
+		}
+		return doc, nil
+	})
+	var seen models.Document
+	remove := processorFunc(func(doc models.Document) (models.Document, error) {
+		seen = doc
+		var matches []models.Match
+		for _, m := range doc.Matches {
+			if m.Vulnerability.ID == "CVE-1999-0001" {
+				matches = append(matches, m)
+			}
+		}
+		doc.Matches = matches
+		return doc, nil
+	})
+
+	processed, err := Apply(result, annotate, remove)
+	require.NoError(t, err)
+
+	// the second processor is given the annotations of the first, along with the platforms and the enrichment
+	for _, m := range seen.Matches {
+		assert.Equal(t, "tier-1", m.Annotations["asset-criticality"])
+	}
+	require.Len(t, seen.Platforms, 1)
+	assert.Len(t, seen.Platforms[0].Matches, 2)
+	require.Contains(t, seen.Enrichment, "CVE-1999-0001")
+	assert.Equal(t, "2024-01-02", seen.Enrichment["CVE-1999-0001"].KEV.DateAdded)
+
+	remaining := processed.Matches.Sorted()
+	require.Len(t, remaining, 1)
+	assert.Equal(t, map[string]map[string]string{remaining[0].ID(): {"asset-criticality": "tier-1"}}, processed.Annotations)
+
+	// the removal applies to the results of the platform as well
+	require.Len(t, processed.Platforms, 1)
+	assert.Equal(t, 1, processed.Platforms[0].Matches.Count())
+	require.Len(t, processed.Platforms[0].IgnoredMatches, 1)
+	assert.Equal(t, "CVE-1999-0002", processed.Platforms[0].IgnoredMatches[0].Vulnerability.ID)
+	assert.Equal(t, 2, result.Platforms[0].Matches.Count(), "the original results are left as-is")
+}
+
+func TestApply_failed(t *testing.T) {
+	_, err := Apply(newResult(), processorFunc(func(models.Document) (models.Document, error) {
+		return models.Document{}, errors.New("boom")
+	}))
+	assert.ErrorContains(t, err, `post-processor "test" failed: boom`)
+}
+
+func TestExternalProcessor(t *testing.T) {
+	processed, err := Apply(newResult(), NewExternalProcessor("echo", "cat"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, processed.Matches.Count())
+	assert.Empty(t, processed.IgnoredMatches)
+	assert.Empty(t, processed.Annotations)
+
+	_, err = Apply(newResult(), NewExternalProcessor("failing", "sh", "-c", "echo unavailable >&2; exit 1"))
+	assert.ErrorContains(t, err, `post-processor "failing" failed`)
+	assert.ErrorContains(t, err, "unavailable")
+
+	_, err = Apply(newResult(), NewExternalProcessor("empty", "true"))
+	assert.ErrorContains(t, err, "did not write a valid match document")
+}
@@ -24,6 +24,7 @@ type Presenter struct {
 	metadataProvider vulnerability.MetadataProvider
 	format           cyclonedx.BOMFileFormat
 	sbom             *sbom.SBOM
+	manifest         *models.ScanManifest
 }
 
 // NewPresenter is a *Presenter constructor
@@ -35,6 +36,7 @@ func NewJSONPresenter(pb models.PresenterConfig) *Presenter {
 		metadataProvider: pb.MetadataProvider,
 		src:              pb.Context.Source,
 		sbom:             pb.SBOM,
+		manifest:         pb.Manifest,
 		format:           cyclonedx.BOMFileFormatJSON,
 	}
 }
@@ -48,6 +50,7 @@ func NewXMLPresenter(pb models.PresenterConfig) *Presenter {
 		metadataProvider: pb.MetadataProvider,
 		src:              pb.Context.Source,
 		sbom:             pb.SBOM,
+		manifest:         pb.Manifest,
 		format:           cyclonedx.BOMFileFormatXML,
 	}
 }
@@ -70,6 +73,18 @@ func (pres *Presenter) Present(output io.Writer) error {
 		},
 	}
 
+	// the scan manifest is recorded as properties of the metadata (e.g. "grype:manifest:db.checksum")
+	if props := pres.manifest.Properties(); len(props) > 0 {
+		var properties []cyclonedx.Property
+		if cyclonedxBOM.Metadata.Properties != nil {
+			properties = *cyclonedxBOM.Metadata.Properties
+		}
+		for _, p := range props {
+			properties = append(properties, cyclonedx.Property{Name: "grype:manifest:" + p.Name, Value: p.Value})
+		}
+		cyclonedxBOM.Metadata.Properties = &properties
+	}
+
 	vulns := make([]cyclonedx.Vulnerability, 0)
 	for _, m := range pres.results.Sorted() {
 		v, err := NewVulnerability(m, pres.metadataProvider)
@@ -2,9 +2,12 @@ package cyclonedx
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"testing"
 
+	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/anchore/clio"
@@ -88,3 +91,36 @@ func TestCycloneDxPresenterDir(t *testing.T) {
 
 	require.JSONEq(t, string(expected), string(actual))
 }
+
+func TestCycloneDxPresenter_manifest(t *testing.T) {
+	sbom, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+	pb := models.PresenterConfig{
+		ID:               clio.Identification{Name: "grype", Version: "1.2.3"},
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		SBOM:             sbom,
+		Manifest: &models.ScanManifest{
+			Tool:       "grype",
+			Version:    "1.2.3",
+			DB:         models.ScanManifestDB{Checksum: "sha256:abc"},
+			ConfigHash: "sha256:def",
+			DurationMs: 1000,
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewJSONPresenter(pb).Present(&buffer))
+
+	var bom cyclonedx.BOM
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &bom))
+	require.NotNil(t, bom.Metadata.Properties)
+	properties := map[string]string{}
+	for _, p := range *bom.Metadata.Properties {
+		properties[p.Name] = p.Value
+	}
+	assert.Equal(t, "sha256:abc", properties["grype:manifest:db.checksum"])
+	assert.Equal(t, "sha256:def", properties["grype:manifest:configHash"])
+	assert.Equal(t, "1000", properties["grype:manifest:durationMs"])
+}
@@ -22,6 +22,7 @@ func NewVulnerability(m match.Match, p vulnerability.MetadataProvider) (v cyclon
 	if err != nil {
 		return v, fmt.Errorf("unable to fetch vuln=%q metadata: %+v", m.Vulnerability.ID, err)
 	}
+	metadata = m.WithSeverityOverride(metadata)
 
 	ratings := generateCDXRatings(metadata)
 
@@ -47,7 +48,7 @@ func NewVulnerability(m match.Match, p vulnerability.MetadataProvider) (v cyclon
 	// Note: if a field isn't captured here it's usually because the resulting
 	// reference link contains that information for the consumer
 	return cyclonedx.Vulnerability{
-		BOMRef:     uuid.New().URN(),
+		BOMRef:     matchBOMRef(m),
 		ID:         m.Vulnerability.ID,
 		Source:     source,
 		References: references,
@@ -173,3 +174,8 @@ func deriveBomRef(p pkg.Package) string {
 	// fallback is to use strictly the ID if there is no valid pURL
 	return string(p.ID)
 }
+
+// matchBOMRef returns a reference for the vulnerability that stays the same across scans (see match.Match.ID).
+func matchBOMRef(m match.Match) string {
+	return uuid.MustParse(m.ID()).URN()
+}
@@ -145,3 +145,19 @@ func TestNewVulnerability_AlwaysIncludesSeverity(t *testing.T) {
 		})
 	}
 }
+
+func TestNewVulnerability_BOMRefIsStable(t *testing.T) {
+	m := match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: "CVE-2020-0001", Namespace: "nvd:cpe"},
+		Package:       pkg.Package{Name: "package-1", Version: "1.0.0", PURL: "pkg:generic/package-1@1.0.0"},
+	}
+	provider := &metadataProvider{severity: "High"}
+
+	first, err := NewVulnerability(m, provider)
+	require.NoError(t, err)
+	second, err := NewVulnerability(m, provider)
+	require.NoError(t, err)
+
+	require.Equal(t, "urn:uuid:"+m.ID(), first.BOMRef)
+	require.Equal(t, first.BOMRef, second.BOMRef)
+}
@@ -0,0 +1,219 @@
+package github
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v5 "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/file"
+	"github.com/anchore/syft/syft/source"
+)
+
+// Presenter writes each match as a GitHub workflow command annotation (e.g. "::error file=...,title=CVE-...::..."),
+// so that the findings of a scan run in a GitHub Actions workflow are shown on the files of the pull request.
+type Presenter struct {
+	results          match.Matches
+	src              *source.Description
+	metadataProvider vulnerability.MetadataProvider
+	// workspace is the directory the annotated files are relative to, which is the checkout of the repository.
+	workspace string
+	manifest  *models.ScanManifest
+}
+
+// NewPresenter creates a new GitHub annotations presenter. The files are annotated relative to the workspace of the
+// workflow (GITHUB_WORKSPACE), or else to the working directory.
+func NewPresenter(pb models.PresenterConfig) *Presenter {
+	workspace := os.Getenv("GITHUB_WORKSPACE")
+	if workspace == "" {
+		workspace, _ = os.Getwd()
+	}
+	return &Presenter{
+		results:          pb.Matches,
+		src:              pb.Context.Source,
+		metadataProvider: pb.MetadataProvider,
+		workspace:        workspace,
+		manifest:         pb.Manifest,
+	}
+}
+
+// Present writes an annotation per match: an error for critical and high severity vulnerabilities, a warning for
+// medium severity vulnerabilities and a notice otherwise.
+func (pres *Presenter) Present(output io.Writer) error {
+	if pres.manifest != nil {
+		// shown in the log of the workflow when debug logging is enabled, not as an annotation
+		if _, err := fmt.Fprintf(output, "::debug::scan manifest: %s\n", escapeData(pres.manifest.String())); err != nil {
+			return err
+		}
+	}
+
+	lines := newLineFinder()
+	for _, m := range pres.results.Sorted() {
+		meta := pres.metadata(m)
+		severity := vulnerability.UnknownSeverity
+		title := m.Vulnerability.ID
+		if overridden := m.WithSeverityOverride(meta); overridden != nil && overridden.Severity != "" {
+			severity = vulnerability.ParseSeverity(overridden.Severity)
+			title += " (" + overridden.Severity + ")"
+		}
+
+		var properties []string
+		if annotated, path, ok := pres.annotatedFile(m.Package); ok {
+			properties = append(properties, "file="+escapeProperty(annotated))
+			if line := lines.find(path, m.Package); line > 0 {
+				properties = append(properties, fmt.Sprintf("line=%d", line))
+			}
+		}
+		properties = append(properties, "title="+escapeProperty(title))
+
+		if _, err := fmt.Fprintf(output, "::%s %s::%s\n", command(severity), strings.Join(properties, ","), escapeData(message(m, meta))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metadata returns the metadata of the matched vulnerability, or nil if not found
+func (pres *Presenter) metadata(m match.Match) *vulnerability.Metadata {
+	if pres.metadataProvider == nil {
+		return nil
+	}
+	meta, err := pres.metadataProvider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace)
+	if err != nil {
+		return nil
+	}
+	return meta
+}
+
+func message(m match.Match, meta *vulnerability.Metadata) string {
+	message := fmt.Sprintf("%s %s (%s) is affected by %s", m.Package.Name, m.Package.Version, m.Package.Type, m.Vulnerability.ID)
+	if m.Vulnerability.Fix.State == v5.FixedState && len(m.Vulnerability.Fix.Versions) > 0 {
+		message += ", fixed in " + strings.Join(m.Vulnerability.Fix.Versions, ", ")
+	} else {
+		message += ", no fix is available yet"
+	}
+	if meta != nil && meta.DataSource != "" {
+		message += "\n" + meta.DataSource
+	}
+	return message
+}
+
+// annotatedFile returns the path of the file the package was found in (the manifest or lockfile of a source
+// directory) as annotated (relative to the workspace), and as read from disk. Packages found in images are not
+// attributed to a file of the repository.
+func (pres *Presenter) annotatedFile(p pkg.Package) (string, string, bool) {
+	if pres.src == nil {
+		return "", "", false
+	}
+
+	var path string
+	switch metadata := pres.src.Metadata.(type) {
+	case source.FileMetadata:
+		path = metadata.Path
+	case source.DirectoryMetadata:
+		l, ok := firstLocation(p)
+		if !ok {
+			return "", "", false
+		}
+		// the locations of a directory source are rooted at the directory
+		path = filepath.Join(metadata.Path, strings.TrimPrefix(l.RealPath, "/"))
+	default:
+		return "", "", false
+	}
+
+	annotated := path
+	if pres.workspace != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			if rel, err := filepath.Rel(pres.workspace, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				annotated = rel
+			}
+		}
+	}
+	return filepath.ToSlash(annotated), path, true
+}
+
+func firstLocation(p pkg.Package) (file.Location, bool) {
+	locations := p.Locations.ToSlice()
+	if len(locations) == 0 {
+		return file.Location{}, false
+	}
+	return locations[0], true
+}
+
+func command(severity vulnerability.Severity) string {
+	switch severity {
+	case vulnerability.CriticalSeverity, vulnerability.HighSeverity:
+		return "error"
+	case vulnerability.MediumSeverity:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// lineFinder finds the line of a package in the file it was found in, reading each file once.
+type lineFinder struct {
+	files map[string][]string
+}
+
+func newLineFinder() *lineFinder {
+	return &lineFinder{files: make(map[string][]string)}
+}
+
+// find returns the first line mentioning both the name and the version of the package (e.g. "lodash@4.17.20" or
+// "requests==2.31.0"), or else the first line mentioning its name (e.g. "node_modules/lodash" in a package-lock.json),
+// or 0 when the file cannot be read or does not mention the package.
+func (f *lineFinder) find(path string, p pkg.Package) int {
+	lines, ok := f.files[path]
+	if !ok {
+		lines = readLines(path)
+		f.files[path] = lines
+	}
+
+	nameLine := 0
+	for i, line := range lines {
+		if !strings.Contains(line, p.Name) {
+			continue
+		}
+		if p.Version != "" && strings.Contains(line, p.Version) {
+			return i + 1
+		}
+		if nameLine == 0 {
+			nameLine = i + 1
+		}
+	}
+	return nameLine
+}
+
+func readLines(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes the value of a property of a workflow command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
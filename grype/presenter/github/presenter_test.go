@@ -0,0 +1,133 @@
+package github
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v5 "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/file"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+type metadataProvider map[string]vulnerability.Metadata
+
+func (p metadataProvider) GetMetadata(id, _ string) (*vulnerability.Metadata, error) {
+	if m, ok := p[id]; ok {
+		return &m, nil
+	}
+	return nil, nil
+}
+
+func TestPresenter_Present(t *testing.T) {
+	workspace := t.TempDir()
+	project := filepath.Join(workspace, "app")
+	require.NoError(t, os.MkdirAll(project, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "package-lock.json"), []byte(`{
+  "packages": {
+    "node_modules/lodash": {
+      "version": "4.17.20"
+    }
+  }
+}
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "requirements.txt"), []byte("flask==2.0.0\nrequests==2.25.0\n"), 0o600))
+
+	lodash := pkg.Package{
+		ID:        "lodash",
+		Name:      "lodash",
+		Version:   "4.17.20",
+		Type:      syftPkg.NpmPkg,
+		Locations: file.NewLocationSet(file.NewLocation("/package-lock.json")),
+	}
+	requests := pkg.Package{
+		ID:        "requests",
+		Name:      "requests",
+		Version:   "2.25.0",
+		Type:      syftPkg.PythonPkg,
+		Locations: file.NewLocationSet(file.NewLocation("/requirements.txt")),
+	}
+	matches := match.NewMatches(
+		match.Match{
+			Vulnerability: vulnerability.Vulnerability{
+				ID:        "GHSA-35jh-r3h4-6jhm",
+				Namespace: "github:language:javascript",
+				Fix:       vulnerability.Fix{State: v5.FixedState, Versions: []string{"4.17.21"}},
+			},
+			Package: lodash,
+		},
+		match.Match{
+			Vulnerability: vulnerability.Vulnerability{
+				ID:        "CVE-2023-32681",
+				Namespace: "github:language:python",
+			},
+			Package: requests,
+		},
+	)
+
+	pres := NewPresenter(models.PresenterConfig{
+		Matches: matches,
+		MetadataProvider: metadataProvider{
+			"GHSA-35jh-r3h4-6jhm": {Severity: "High", DataSource: "https://github.com/advisories/GHSA-35jh-r3h4-6jhm"},
+			"CVE-2023-32681":      {Severity: "Medium"},
+		},
+		Context: pkg.Context{Source: &source.Description{Metadata: source.DirectoryMetadata{Path: project}}},
+	})
+	pres.workspace = workspace
+
+	var buf bytes.Buffer
+	require.NoError(t, pres.Present(&buf))
+	assert.Equal(t,
+		"::error file=app/package-lock.json,line=3,title=GHSA-35jh-r3h4-6jhm (High)::lodash 4.17.20 (npm) is affected by GHSA-35jh-r3h4-6jhm, fixed in 4.17.21%0Ahttps://github.com/advisories/GHSA-35jh-r3h4-6jhm\n"+
+			"::warning file=app/requirements.txt,line=2,title=CVE-2023-32681 (Medium)::requests 2.25.0 (python) is affected by CVE-2023-32681, no fix is available yet\n",
+		buf.String())
+}
+
+func TestPresenter_Present_image(t *testing.T) {
+	matches := match.NewMatches(match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0001", Namespace: "debian:distro:debian:12"},
+		Package: pkg.Package{
+			ID:        "openssl",
+			Name:      "openssl",
+			Version:   "3.0.11-1",
+			Type:      syftPkg.DebPkg,
+			Locations: file.NewLocationSet(file.NewLocation("/var/lib/dpkg/status")),
+		},
+	})
+
+	pres := NewPresenter(models.PresenterConfig{
+		Matches:          matches,
+		MetadataProvider: metadataProvider{},
+		Context:          pkg.Context{Source: &source.Description{Metadata: source.ImageMetadata{UserInput: "debian:12"}}},
+	})
+
+	// files of images are not files of the repository, and vulnerabilities without a severity are notices
+	var buf bytes.Buffer
+	require.NoError(t, pres.Present(&buf))
+	assert.Equal(t, "::notice title=CVE-2024-0001::openssl 3.0.11-1 (deb) is affected by CVE-2024-0001, no fix is available yet\n", buf.String())
+}
+
+func TestPresenter_Present_manifest(t *testing.T) {
+	pres := NewPresenter(models.PresenterConfig{
+		Matches:  match.NewMatches(),
+		Manifest: &models.ScanManifest{Tool: "grype", Version: "1.2.3", ConfigHash: "sha256:def", DurationMs: 1000},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, pres.Present(&buf))
+	assert.Equal(t, "::debug::scan manifest: tool=grype version=1.2.3 configHash=sha256:def durationMs=1000\n", buf.String())
+}
+
+func Test_escapeProperty(t *testing.T) {
+	assert.Equal(t, "a%3Ab%2Cc%25d%0Ae", escapeProperty("a:b,c%d\ne"))
+	assert.Equal(t, "a:b,c%25d%0Ae", escapeData("a:b,c%d\ne"))
+}
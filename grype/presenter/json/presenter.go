@@ -3,11 +3,16 @@ package json
 import (
 	"encoding/json"
 	"io"
+	"time"
 
 	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/baseimage"
+	"github.com/anchore/grype/grype/license"
+	"github.com/anchore/grype/grype/malware"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/supplychain"
 	"github.com/anchore/grype/grype/vulnerability"
 )
 
@@ -21,6 +26,23 @@ type Presenter struct {
 	metadataProvider vulnerability.MetadataProvider
 	appConfig        interface{}
 	dbStatus         interface{}
+	platforms        []models.PlatformResult
+	unsupported      []pkg.UnsupportedPackage
+	malware          []malware.Finding
+	supplyChain      []supplychain.Warning
+	packageStatuses  map[pkg.ID][]supplychain.PackageStatus
+	annotations      map[string]map[string]string
+	environment      map[string]string
+	manifest         *models.ScanManifest
+	licenses         []license.Violation
+	baseImage        *baseimage.Advice
+	unscanned        []pkg.Package
+	rejections       []match.Rejection
+	fuzzyRejections  []match.Rejection
+	matchEvidence    bool
+	maxResults       int
+	resultsOffset    int
+	now              func() time.Time
 }
 
 // NewPresenter creates a new JSON presenter
@@ -34,16 +56,61 @@ func NewPresenter(pb models.PresenterConfig) *Presenter {
 		context:          pb.Context,
 		appConfig:        pb.AppConfig,
 		dbStatus:         pb.DBStatus,
+		platforms:        pb.Platforms,
+		unsupported:      pb.UnsupportedPackages,
+		malware:          pb.Malware,
+		supplyChain:      pb.SupplyChain,
+		packageStatuses:  pb.PackageStatuses,
+		annotations:      pb.Annotations,
+		environment:      pb.Environment,
+		manifest:         pb.Manifest,
+		licenses:         pb.LicenseViolations,
+		baseImage:        pb.BaseImage,
+		unscanned:        pb.UnscannedPackages,
+		rejections:       pb.Rejections,
+		fuzzyRejections:  pb.FuzzyRejections,
+		matchEvidence:    pb.MatchEvidence,
+		maxResults:       pb.MaxResults,
+		resultsOffset:    pb.ResultsOffset,
+		now:              pb.Now,
 	}
 }
 
 // Present creates a JSON-based reporting
 func (pres *Presenter) Present(output io.Writer) error {
-	doc, err := models.NewDocument(pres.id, pres.packages, pres.context, pres.matches, pres.ignoredMatches, pres.metadataProvider,
+	doc, err := models.NewDocumentAt(models.ReportTime(pres.now), pres.id, pres.packages, pres.context, pres.matches, pres.ignoredMatches, pres.metadataProvider,
 		pres.appConfig, pres.dbStatus)
 	if err != nil {
 		return err
 	}
+	if doc.Platforms, err = models.NewPlatformDocuments(pres.platforms, pres.metadataProvider); err != nil {
+		return err
+	}
+	doc.UnsupportedPackages = models.NewUnsupportedPackages(pres.unsupported)
+	doc.Malware = models.NewMalwareFindings(pres.malware)
+	doc.SupplyChain = models.NewSupplyChainWarnings(pres.supplyChain)
+	doc.LicenseViolations = models.NewLicenseViolations(pres.licenses)
+	doc.BaseImage = models.NewBaseImageAdvice(pres.baseImage)
+	doc.Incomplete = models.NewIncompleteScan(pres.unscanned)
+	doc.Rejections = models.NewRejections(pres.rejections, pres.matches)
+	doc.FuzzyComparisons = append(doc.FuzzyComparisons, models.NewFuzzyRejections(pres.fuzzyRejections, pres.matches)...)
+	doc.Environment = pres.environment
+	doc.Manifest = pres.manifest
+	models.AnnotatePackageStatuses(doc.Matches, pres.packageStatuses)
+	models.AnnotateMatches(doc.Matches, pres.annotations)
+	if pres.matchEvidence {
+		models.AddMatchEvidence(doc.Matches, pres.matches.Sorted())
+	}
+	for i := range doc.Platforms {
+		models.AnnotatePackageStatuses(doc.Platforms[i].Matches, pres.packageStatuses)
+		if pres.matchEvidence {
+			// the platform documents are in the order of the platform results
+			models.AddMatchEvidence(doc.Platforms[i].Matches, pres.platforms[i].Matches.Sorted())
+		}
+	}
+	if pres.maxResults > 0 || pres.resultsOffset > 0 {
+		models.Paginate(&doc, pres.resultsOffset, pres.maxResults)
+	}
 
 	enc := json.NewEncoder(output)
 	// prevent > and < from being escaped in the payload
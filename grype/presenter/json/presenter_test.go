@@ -2,20 +2,28 @@ package json
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"regexp"
 	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/anchore/clio"
 	"github.com/anchore/go-testutils"
+	"github.com/anchore/grype/grype/baseimage"
+	"github.com/anchore/grype/grype/license"
+	"github.com/anchore/grype/grype/malware"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/presenter/internal"
 	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/supplychain"
+	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/syft/syft/linux"
+	syftPkg "github.com/anchore/syft/syft/pkg"
 	"github.com/anchore/syft/syft/source"
 )
 
@@ -152,6 +160,411 @@ func TestPresenter_Present_NewDocumentSorted(t *testing.T) {
 	}
 }
 
+func TestPresenter_Present_platforms(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		Platforms: []models.PlatformResult{
+			{Platform: "linux/amd64", Matches: matches, Packages: packages, Context: context},
+			{Platform: "linux/arm64", Matches: match.NewMatches(), Context: context},
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	require.Len(t, doc.Platforms, 2)
+	assert.Equal(t, "linux/amd64", doc.Platforms[0].Platform)
+	assert.Len(t, doc.Platforms[0].Matches, len(doc.Matches))
+	assert.Equal(t, "linux/arm64", doc.Platforms[1].Platform)
+	assert.Empty(t, doc.Platforms[1].Matches)
+	assert.NotNil(t, doc.Platforms[1].Source)
+}
+
+func TestPresenter_Present_unsupportedPackages(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		UnsupportedPackages: []pkg.UnsupportedPackage{
+			{Package: pkg.Package{Name: "tool", Version: "nightly", Type: syftPkg.BinaryPkg}, Reason: pkg.UnsupportedEcosystem},
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	assert.Equal(t, []models.UnsupportedPackage{
+		{
+			Name:        "tool",
+			Version:     "nightly",
+			Type:        string(syftPkg.BinaryPkg),
+			Reason:      string(pkg.UnsupportedEcosystem),
+			Description: pkg.UnsupportedEcosystem.Description(),
+		},
+	}, doc.UnsupportedPackages)
+}
+
+func TestPresenter_Present_unscannedPackages(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:           matches,
+		Packages:          packages,
+		Context:           context,
+		MetadataProvider:  metadataProvider,
+		UnscannedPackages: []pkg.Package{{Name: "openssl", Version: "3.0.11", Type: syftPkg.DebPkg}},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	require.NotNil(t, doc.Incomplete)
+	assert.Equal(t, models.ScanTimeExceeded, doc.Incomplete.Reason)
+	assert.Equal(t, []models.UnscannedPackage{{Name: "openssl", Version: "3.0.11", Type: string(syftPkg.DebPkg)}}, doc.Incomplete.UnscannedPackages)
+	assert.NotEmpty(t, doc.Matches)
+
+	// complete scans are not marked
+	pb.UnscannedPackages = nil
+	buffer.Reset()
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+	assert.NotContains(t, buffer.String(), `"incomplete"`)
+}
+
+func TestPresenter_Present_rejections(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+	matched := matches.Sorted()[0]
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		Rejections: []match.Rejection{
+			{
+				Vulnerability: vulnerability.Vulnerability{ID: "CVE-2999-0001", Namespace: "source-1"},
+				Package:       packages[0],
+				Filter:        match.VersionConstraintRejection,
+				Reason:        "version 1.2.3 is not within the constraint",
+			},
+			// the vulnerability matched the package anyway
+			{
+				Vulnerability: matched.Vulnerability,
+				Package:       matched.Package,
+				Filter:        match.TargetSoftwareRejection,
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	assert.Equal(t, []models.Rejection{
+		{
+			Vulnerability: models.RejectedVulnerability{ID: "CVE-2999-0001", Namespace: "source-1"},
+			Package: models.RejectedPackage{
+				ID:      string(packages[0].ID),
+				Name:    packages[0].Name,
+				Version: packages[0].Version,
+				Type:    string(packages[0].Type),
+			},
+			Filter: "version-constraint",
+			Reason: "version 1.2.3 is not within the constraint",
+		},
+	}, doc.Rejections)
+
+	// rejections are only listed when collected
+	pb.Rejections = nil
+	buffer.Reset()
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+	assert.NotContains(t, buffer.String(), `"rejections"`)
+}
+
+func TestPresenter_Present_matchEvidence(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		MatchEvidence:    true,
+		Platforms: []models.PlatformResult{
+			{Platform: "linux/amd64", Matches: matches, Packages: packages, Context: context},
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	require.NotEmpty(t, doc.Matches)
+	for _, m := range doc.Matches {
+		require.NotNil(t, m.Evidence, m.ID)
+		var decisions int
+		for _, e := range m.Evidence.Edges {
+			if e.Relation == string(match.MatchedBy) {
+				assert.Equal(t, "vulnerability:"+m.Vulnerability.ID, e.To)
+				assert.Equal(t, m.MatchDetails[e.Detail].Matcher, e.Matcher)
+				decisions++
+			}
+		}
+		assert.Equal(t, len(m.MatchDetails), decisions)
+	}
+	// as are the matches of each platform
+	require.Len(t, doc.Platforms, 1)
+	require.NotEmpty(t, doc.Platforms[0].Matches)
+	for _, m := range doc.Platforms[0].Matches {
+		assert.NotNil(t, m.Evidence, m.ID)
+	}
+
+	// the evidence is only included when requested
+	pb.MatchEvidence = false
+	buffer.Reset()
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+	assert.NotContains(t, buffer.String(), `"evidence"`)
+}
+
+func TestPresenter_Present_maxResults(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+	var all models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &all))
+	require.Greater(t, len(all.Matches), 1)
+	assert.Nil(t, all.Pagination)
+	assert.Nil(t, all.Summary)
+
+	pb.MaxResults = 1
+	pb.ResultsOffset = 1
+	buffer.Reset()
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+	var page models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &page))
+	require.Len(t, page.Matches, 1)
+	assert.Equal(t, all.Matches[1].ID, page.Matches[0].ID)
+	require.NotNil(t, page.Pagination)
+	assert.Equal(t, len(all.Matches), page.Pagination.Total)
+	require.NotNil(t, page.Summary)
+	assert.Equal(t, len(all.Matches), page.Summary.Total)
+}
+
+func TestPresenter_Present_malware(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		Malware: []malware.Finding{
+			{
+				Package:   pkg.Package{Name: "evil-pkg", Version: "1.0.1", Type: syftPkg.NpmPkg},
+				ID:        "MAL-2022-1001",
+				Summary:   "Malicious code in evil-pkg (npm)",
+				MatchedBy: malware.VersionMatch,
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	assert.Equal(t, []models.MalwareFinding{
+		{
+			Name:      "evil-pkg",
+			Version:   "1.0.1",
+			Type:      string(syftPkg.NpmPkg),
+			ID:        "MAL-2022-1001",
+			Summary:   "Malicious code in evil-pkg (npm)",
+			MatchedBy: "version",
+		},
+	}, doc.Malware)
+	assert.NotEmpty(t, doc.Matches)
+}
+
+func TestPresenter_Present_supplyChain(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		SupplyChain: []supplychain.Warning{
+			{
+				Package: pkg.Package{Name: "lodahs", Version: "1.0.0", Type: syftPkg.NpmPkg},
+				Kind:    supplychain.Typosquat,
+				Message: `the name is 1 edit(s) from the popular npm package "lodash"`,
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	assert.Equal(t, []models.SupplyChainWarning{
+		{
+			Name:    "lodahs",
+			Version: "1.0.0",
+			Type:    string(syftPkg.NpmPkg),
+			Kind:    "typosquat",
+			Message: `the name is 1 edit(s) from the popular npm package "lodash"`,
+		},
+	}, doc.SupplyChain)
+}
+
+func TestPresenter_Present_packageStatuses(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	first := matches.Sorted()[0].Package
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		PackageStatuses: map[pkg.ID][]supplychain.PackageStatus{
+			first.ID: {{Name: first.Name, Status: supplychain.Yanked, Reason: "broken release"}},
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	for _, m := range doc.Matches {
+		if m.Artifact.ID == string(first.ID) {
+			assert.Equal(t, []models.PackageStatus{{Status: "yanked", Reason: "broken release"}}, m.PackageStatus)
+		} else {
+			assert.Empty(t, m.PackageStatus)
+		}
+	}
+}
+
+func TestPresenter_Present_licenseViolations(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		LicenseViolations: []license.Violation{
+			{
+				Package:  pkg.Package{Name: "readline", Version: "8.2", Type: syftPkg.DebPkg},
+				License:  "GPL-3.0-only",
+				Licenses: []string{"GPL-3.0-only"},
+				Reasons:  []license.Reason{license.Denied},
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	assert.Equal(t, []models.LicenseViolation{
+		{
+			Name:       "readline",
+			Version:    "8.2",
+			Type:       string(syftPkg.DebPkg),
+			License:    "GPL-3.0-only",
+			Violations: []models.LicenseViolationReason{{License: "GPL-3.0-only", Reason: "denied"}},
+		},
+	}, doc.LicenseViolations)
+}
+
+func TestPresenter_Present_baseImage(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		BaseImage: &baseimage.Advice{
+			Base:       baseimage.Base{Image: "docker.io/library/alpine", Tag: "3.18.4"},
+			DetectedBy: baseimage.ByAnnotation,
+			Findings:   1,
+			Upgrades:   []baseimage.Upgrade{{Base: baseimage.Base{Tag: "3.18.5"}, Remediates: 1}},
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	assert.Equal(t, &models.BaseImageAdvice{
+		Image:       "docker.io/library/alpine",
+		Tag:         "3.18.4",
+		DetectedBy:  "annotation",
+		Findings:    1,
+		Upgrades:    []models.BaseImageUpgrade{{Tag: "3.18.5", Remediates: 1}},
+		Recommended: "3.18.5",
+	}, doc.BaseImage)
+}
+
 func redact(content []byte) []byte {
 	return timestampRegexp.ReplaceAll(content, []byte(`"timestamp":""`))
 }
+
+func TestPresenter_Present_manifest(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	manifest := &models.ScanManifest{
+		Tool:       "grype",
+		Version:    "1.2.3",
+		DB:         models.ScanManifestDB{Checksum: "sha256:abc", Built: "2024-06-01T00:00:00Z", SchemaVersion: 5},
+		ConfigHash: "sha256:def",
+		Target:     models.ScanManifestTarget{Input: "user-input", Type: "image", Digest: "sha256:123"},
+		StartedAt:  "2024-06-02T10:00:00Z",
+		FinishedAt: "2024-06-02T10:00:01Z",
+		DurationMs: 1000,
+	}
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+		Manifest:         manifest,
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewPresenter(pb).Present(&buffer))
+
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+	assert.Equal(t, manifest, doc.Manifest)
+	assert.Contains(t, buffer.String(), `"configHash": "sha256:def"`)
+}
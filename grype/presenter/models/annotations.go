@@ -0,0 +1,13 @@
+package models
+
+// AnnotateMatches sets the annotations of the matches, as set by the post-processors of the results (by match ID).
+func AnnotateMatches(matches []Match, annotations map[string]map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+	for i := range matches {
+		if a, ok := annotations[matches[i].ID]; ok {
+			matches[i].Annotations = a
+		}
+	}
+}
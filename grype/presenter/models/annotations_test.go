@@ -0,0 +1,21 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotateMatches(t *testing.T) {
+	matches := []Match{
+		{ID: "1"},
+		{ID: "2"},
+	}
+
+	AnnotateMatches(matches, map[string]map[string]string{
+		"1": {"risk-score": "87"},
+	})
+
+	assert.Equal(t, map[string]string{"risk-score": "87"}, matches[0].Annotations)
+	assert.Empty(t, matches[1].Annotations)
+}
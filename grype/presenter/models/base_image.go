@@ -0,0 +1,49 @@
+package models
+
+import (
+	"github.com/anchore/grype/grype/baseimage"
+)
+
+// BaseImageAdvice is the base image of the scanned image, with its newer tags and the findings each would remediate.
+type BaseImageAdvice struct {
+	Image      string `json:"image"`
+	Tag        string `json:"tag"`
+	DetectedBy string `json:"detectedBy"`
+	// Findings is the number of findings of the packages of the base image.
+	Findings int                `json:"findings"`
+	Upgrades []BaseImageUpgrade `json:"upgrades"`
+	// Recommended is the tag of the upgrade remediating the most findings, if any.
+	Recommended string `json:"recommended,omitempty"`
+}
+
+// BaseImageUpgrade is a newer tag of the base image.
+type BaseImageUpgrade struct {
+	Tag        string `json:"tag"`
+	Released   string `json:"released,omitempty"`
+	Remediates int    `json:"remediates"`
+}
+
+// NewBaseImageAdvice returns the model of the base image advice, or nil without advice.
+func NewBaseImageAdvice(advice *baseimage.Advice) *BaseImageAdvice {
+	if advice == nil {
+		return nil
+	}
+	m := &BaseImageAdvice{
+		Image:      advice.Base.Image,
+		Tag:        advice.Base.Tag,
+		DetectedBy: string(advice.DetectedBy),
+		Findings:   advice.Findings,
+		Upgrades:   make([]BaseImageUpgrade, 0, len(advice.Upgrades)),
+	}
+	for _, u := range advice.Upgrades {
+		upgrade := BaseImageUpgrade{Tag: u.Base.Tag, Remediates: u.Remediates}
+		if !u.Base.Released.IsZero() {
+			upgrade.Released = u.Base.Released.UTC().Format("2006-01-02")
+		}
+		m.Upgrades = append(m.Upgrades, upgrade)
+	}
+	if r := advice.Recommended(); r != nil {
+		m.Recommended = r.Base.Tag
+	}
+	return m
+}
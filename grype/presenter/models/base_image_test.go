@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/baseimage"
+)
+
+func TestNewBaseImageAdvice(t *testing.T) {
+	advice := &baseimage.Advice{
+		Base:       baseimage.Base{Image: "docker.io/library/alpine", Tag: "3.18.4"},
+		DetectedBy: baseimage.ByLayers,
+		Findings:   3,
+		Upgrades: []baseimage.Upgrade{
+			{Base: baseimage.Base{Tag: "3.18.5", Released: time.Date(2023, 11, 30, 0, 0, 0, 0, time.UTC)}, Remediates: 1},
+			{Base: baseimage.Base{Tag: "3.18.6"}, Remediates: 2},
+		},
+	}
+
+	assert.Equal(t, &BaseImageAdvice{
+		Image:      "docker.io/library/alpine",
+		Tag:        "3.18.4",
+		DetectedBy: "layers",
+		Findings:   3,
+		Upgrades: []BaseImageUpgrade{
+			{Tag: "3.18.5", Released: "2023-11-30", Remediates: 1},
+			{Tag: "3.18.6", Remediates: 2},
+		},
+		Recommended: "3.18.6",
+	}, NewBaseImageAdvice(advice))
+	assert.Nil(t, NewBaseImageAdvice(nil))
+}
@@ -2,8 +2,8 @@ package models
 
 import (
 	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/internal/log"
-	"github.com/anchore/syft/syft/linux"
 )
 
 // distribution provides information about a detected Linux distribution.
@@ -11,10 +11,13 @@ type distribution struct {
 	Name    string   `json:"name"`    // Name of the Linux distribution
 	Version string   `json:"version"` // Version of the Linux distribution (major or major.minor version)
 	IDLike  []string `json:"idLike"`  // the ID_LIKE field found within the /etc/os-release file
+	// InferredFrom is the evidence the distro was inferred from, for SBOMs that do not record it (e.g. "purls")
+	InferredFrom string `json:"inferredFrom,omitempty"`
 }
 
-// newDistribution creates a struct with the Linux distribution to be represented in JSON.
-func newDistribution(r *linux.Release) distribution {
+// newDistribution creates a struct with the Linux distribution of the context to be represented in JSON.
+func newDistribution(context pkg.Context) distribution {
+	r := context.Distro
 	if r == nil {
 		return distribution{}
 	}
@@ -26,16 +29,18 @@ func newDistribution(r *linux.Release) distribution {
 
 		// as a fallback use the raw release information
 		return distribution{
-			Name:    r.ID,
-			Version: r.VersionID,
-			IDLike:  cleanIDLike(r.IDLike),
+			Name:         r.ID,
+			Version:      r.VersionID,
+			IDLike:       cleanIDLike(r.IDLike),
+			InferredFrom: context.DistroInferredFrom,
 		}
 	}
 
 	return distribution{
-		Name:    d.Name(),
-		Version: d.FullVersion(),
-		IDLike:  cleanIDLike(d.IDLike),
+		Name:         d.Name(),
+		Version:      d.FullVersion(),
+		IDLike:       cleanIDLike(d.IDLike),
+		InferredFrom: context.DistroInferredFrom,
 	}
 }
 
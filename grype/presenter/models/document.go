@@ -13,16 +13,58 @@ import (
 
 // Document represents the JSON document to be presented
 type Document struct {
+	// Incomplete tells that the scan ran out of time before matching every package, so the matches are partial
+	Incomplete *IncompleteScan `json:"incomplete,omitempty"`
+	// Pagination tells that the matches are a single page of the matches of the scan, summarized by Summary
+	Pagination     *Pagination    `json:"pagination,omitempty"`
+	Summary        *MatchSummary  `json:"summary,omitempty"`
+	Matches        []Match        `json:"matches"`
+	IgnoredMatches []IgnoredMatch `json:"ignoredMatches,omitempty"`
+	// FuzzyComparisons are the matches whose package version was compared by a heuristic instead of a version format
+	FuzzyComparisons []FuzzyComparison `json:"fuzzyComparisons,omitempty"`
+	Source           *source           `json:"source"`
+	Distro           distribution      `json:"distro"`
+	Descriptor       descriptor        `json:"descriptor"`
+	// Environment is the environment context of the scanned artifact given to the scan (e.g. "exposure": "internet")
+	Environment map[string]string `json:"environment,omitempty"`
+	// Manifest records the conditions of the scan (tool, DB, configuration, target and timing)
+	Manifest *ScanManifest `json:"manifest,omitempty"`
+	// Platforms are the results of each platform when several platforms of an image index were scanned, in which
+	// case the matches above are those of all platforms combined.
+	Platforms []PlatformDocument `json:"platforms,omitempty"`
+	// UnsupportedPackages are the cataloged packages that could not be matched against any vulnerability data
+	UnsupportedPackages []UnsupportedPackage `json:"unsupportedPackages,omitempty"`
+	// Malware are the cataloged packages found to be known-malicious packages
+	Malware []MalwareFinding `json:"malware,omitempty"`
+	// SupplyChain are the signs of supply-chain risk about the cataloged packages that are not vulnerabilities
+	SupplyChain []SupplyChainWarning `json:"supplyChain,omitempty"`
+	// LicenseViolations are the declared licenses of the cataloged packages that violate the license policy
+	LicenseViolations []LicenseViolation `json:"licenseViolations,omitempty"`
+	// BaseImage is the base image of the scanned image and the newer tags that would remediate its findings
+	BaseImage *BaseImageAdvice `json:"baseImage,omitempty"`
+	// Rejections are the candidate vulnerabilities that were considered but rejected, when requested
+	Rejections []Rejection `json:"rejections,omitempty"`
+	// Enrichment is the EPSS and KEV data of the CVEs of the matches (by CVE ID), given to the post-processors
+	Enrichment map[string]VulnerabilityEnrichment `json:"enrichment,omitempty"`
+}
+
+// PlatformDocument is the result of scanning a single platform of an image index.
+type PlatformDocument struct {
+	Platform       string         `json:"platform"`
 	Matches        []Match        `json:"matches"`
 	IgnoredMatches []IgnoredMatch `json:"ignoredMatches,omitempty"`
 	Source         *source        `json:"source"`
 	Distro         distribution   `json:"distro"`
-	Descriptor     descriptor     `json:"descriptor"`
 }
 
 // NewDocument creates and populates a new Document struct, representing the populated JSON document.
 func NewDocument(id clio.Identification, packages []pkg.Package, context pkg.Context, matches match.Matches, ignoredMatches []match.IgnoredMatch, metadataProvider vulnerability.MetadataProvider, appConfig interface{}, dbStatus interface{}) (Document, error) {
-	timestamp, timestampErr := time.Now().Local().MarshalText()
+	return NewDocumentAt(time.Now(), id, packages, context, matches, ignoredMatches, metadataProvider, appConfig, dbStatus)
+}
+
+// NewDocumentAt creates a new Document as NewDocument does, with the given time as the timestamp of the document.
+func NewDocumentAt(at time.Time, id clio.Identification, packages []pkg.Package, context pkg.Context, matches match.Matches, ignoredMatches []match.IgnoredMatch, metadataProvider vulnerability.MetadataProvider, appConfig interface{}, dbStatus interface{}) (Document, error) {
+	timestamp, timestampErr := at.Local().MarshalText()
 	if timestampErr != nil {
 		return Document{}, timestampErr
 	}
@@ -43,7 +85,7 @@ func NewDocument(id clio.Identification, packages []pkg.Package, context pkg.Con
 		findings = append(findings, *matchModel)
 	}
 
-	sort.Sort(MatchSort(findings))
+	sort.Stable(MatchSort(findings))
 
 	var src *source
 	if context.Source != nil {
@@ -54,6 +96,9 @@ func NewDocument(id clio.Identification, packages []pkg.Package, context pkg.Con
 		src = &theSrc
 	}
 
+	ignoredMatches = append([]match.IgnoredMatch(nil), ignoredMatches...)
+	match.SortIgnored(ignoredMatches)
+
 	var ignoredMatchModels []IgnoredMatch
 	for _, m := range ignoredMatches {
 		p := pkg.ByID(m.Package.ID, packages)
@@ -74,10 +119,11 @@ func NewDocument(id clio.Identification, packages []pkg.Package, context pkg.Con
 	}
 
 	return Document{
-		Matches:        findings,
-		IgnoredMatches: ignoredMatchModels,
-		Source:         src,
-		Distro:         newDistribution(context.Distro),
+		Matches:          findings,
+		IgnoredMatches:   ignoredMatchModels,
+		FuzzyComparisons: newFuzzyComparisons(matches),
+		Source:           src,
+		Distro:           newDistribution(context),
 		Descriptor: descriptor{
 			Name:                  id.Name,
 			Version:               id.Version,
@@ -87,3 +133,22 @@ func NewDocument(id clio.Identification, packages []pkg.Package, context pkg.Con
 		},
 	}, nil
 }
+
+// NewPlatformDocuments creates the per-platform sections of a document for the results of each scanned platform.
+func NewPlatformDocuments(results []PlatformResult, metadataProvider vulnerability.MetadataProvider) ([]PlatformDocument, error) {
+	var docs []PlatformDocument
+	for _, r := range results {
+		doc, err := NewDocument(clio.Identification{}, r.Packages, r.Context, r.Matches, r.IgnoredMatches, metadataProvider, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to describe the results of platform %s: %w", r.Platform, err)
+		}
+		docs = append(docs, PlatformDocument{
+			Platform:       r.Platform,
+			Matches:        doc.Matches,
+			IgnoredMatches: doc.IgnoredMatches,
+			Source:         doc.Source,
+			Distro:         doc.Distro,
+		})
+	}
+	return docs, nil
+}
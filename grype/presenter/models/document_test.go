@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/anchore/clio"
 	"github.com/anchore/grype/grype/match"
@@ -92,7 +93,8 @@ func TestPackagesAreSorted(t *testing.T) {
 		actualVulnerabilities = append(actualVulnerabilities, m.Vulnerability.ID)
 	}
 
-	assert.Equal(t, []string{"CVE-1999-0003", "CVE-1999-0002", "CVE-1999-0001"}, actualVulnerabilities)
+	// matches of the same package are ordered by vulnerability ID
+	assert.Equal(t, []string{"CVE-1999-0001", "CVE-1999-0002", "CVE-1999-0003"}, actualVulnerabilities)
 }
 
 func TestTimestampValidFormat(t *testing.T) {
@@ -117,3 +119,27 @@ func TestTimestampValidFormat(t *testing.T) {
 	}
 
 }
+
+func TestNewDocumentAt(t *testing.T) {
+	at := time.Date(2023, 4, 21, 0, 22, 6, 0, time.UTC)
+
+	doc, err := NewDocumentAt(at, clio.Identification{}, nil, pkg.Context{}, match.NewMatches(), nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	timestamp, err := time.Parse(time.RFC3339, doc.Descriptor.Timestamp)
+	require.NoError(t, err)
+	assert.True(t, at.Equal(timestamp))
+	assert.Equal(t, at, ReportTime(func() time.Time { return at }))
+}
+
+func TestDocumentInferredDistro(t *testing.T) {
+	ctx := pkg.Context{
+		Distro:             &linux.Release{ID: "debian", VersionID: "11"},
+		DistroInferredFrom: pkg.DistroFromPURLs,
+	}
+
+	doc, err := NewDocument(clio.Identification{}, nil, ctx, match.NewMatches(), nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, distribution{Name: "debian", Version: "11", IDLike: []string{}, InferredFrom: pkg.DistroFromPURLs}, doc.Distro)
+}
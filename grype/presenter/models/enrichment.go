@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/priority"
+)
+
+// VulnerabilityEnrichment is the EPSS score and the KEV listing of a CVE.
+type VulnerabilityEnrichment struct {
+	EPSS *EPSSScore  `json:"epss,omitempty"`
+	KEV  *KEVListing `json:"kev,omitempty"`
+}
+
+// EPSSScore is the probability of exploitation of a CVE within 30 days, and its percentile among all scored CVEs.
+type EPSSScore struct {
+	Score      float64 `json:"score"`
+	Percentile float64 `json:"percentile"`
+}
+
+// KEVListing is the listing of a CVE in the catalog of Known Exploited Vulnerabilities.
+type KEVListing struct {
+	DateAdded       string `json:"dateAdded,omitempty"`
+	DueDate         string `json:"dueDate,omitempty"`
+	KnownRansomware bool   `json:"knownRansomware,omitempty"`
+}
+
+// NewEnrichment returns the EPSS and KEV data of the CVEs of the matches (by CVE ID), nil when there is none.
+func NewEnrichment(enrichment *priority.Enrichment, matches match.Matches) map[string]VulnerabilityEnrichment {
+	if !enrichment.HasEPSS() && !enrichment.HasKEV() {
+		return nil
+	}
+	out := make(map[string]VulnerabilityEnrichment)
+	for m := range matches.Enumerate() {
+		for _, cve := range priority.CVEs(m) {
+			var e VulnerabilityEnrichment
+			if s, ok := enrichment.EPSS(cve); ok {
+				e.EPSS = &EPSSScore{Score: s.Score, Percentile: s.Percentile}
+			}
+			if k, ok := enrichment.KEV(cve); ok {
+				e.KEV = &KEVListing{DateAdded: k.DateAdded, DueDate: k.DueDate, KnownRansomware: k.KnownRansomware}
+			}
+			if e.EPSS != nil || e.KEV != nil {
+				out[cve] = e
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
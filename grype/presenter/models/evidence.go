@@ -0,0 +1,75 @@
+package models
+
+import (
+	"github.com/anchore/grype/grype/match"
+)
+
+// MatchEvidence is the "why matched" graph of a match: the criteria searched by, the data sources searched and the
+// decisions of the matchers (see match.Evidence).
+type MatchEvidence struct {
+	Nodes []EvidenceNode `json:"nodes"`
+	Edges []EvidenceEdge `json:"edges"`
+}
+
+// EvidenceNode is a package, criterion, data source or vulnerability of the evidence graph of a match.
+type EvidenceNode struct {
+	ID    string `json:"id"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+// EvidenceEdge links two nodes of the evidence graph of a match, the "matched" edges being the decisions of the
+// matchers.
+type EvidenceEdge struct {
+	From       string              `json:"from"`
+	To         string              `json:"to"`
+	Relation   string              `json:"relation"`
+	Detail     int                 `json:"detail"` // the index of the match detail (in matchDetails) of the edge
+	Matcher    string              `json:"matcher,omitempty"`
+	Type       string              `json:"type,omitempty"`
+	Confidence float64             `json:"confidence,omitempty"`
+	Found      []EvidenceAttribute `json:"found,omitempty"`
+}
+
+// EvidenceAttribute is an attribute of the vulnerability record that was matched on.
+type EvidenceAttribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func newMatchEvidence(e match.Evidence) *MatchEvidence {
+	out := &MatchEvidence{Nodes: make([]EvidenceNode, 0, len(e.Nodes)), Edges: make([]EvidenceEdge, 0, len(e.Edges))}
+	for _, n := range e.Nodes {
+		out.Nodes = append(out.Nodes, EvidenceNode{ID: n.ID, Kind: string(n.Kind), Name: n.Name, Value: n.Value})
+	}
+	for _, edge := range e.Edges {
+		model := EvidenceEdge{
+			From:       edge.From,
+			To:         edge.To,
+			Relation:   string(edge.Relation),
+			Detail:     edge.Detail,
+			Matcher:    string(edge.Matcher),
+			Type:       string(edge.Type),
+			Confidence: edge.Confidence,
+		}
+		for _, f := range edge.Found {
+			model.Found = append(model.Found, EvidenceAttribute{Name: f.Name, Value: f.Value})
+		}
+		out.Edges = append(out.Edges, model)
+	}
+	return out
+}
+
+// AddMatchEvidence sets the evidence graphs of the matches, from the matches of the scan (by match ID).
+func AddMatchEvidence(models []Match, matches []match.Match) {
+	byID := make(map[string]match.Match, len(matches))
+	for _, m := range matches {
+		byID[m.ID()] = m
+	}
+	for i := range models {
+		if m, ok := byID[models[i].ID]; ok {
+			models[i].Evidence = newMatchEvidence(m.Evidence())
+		}
+	}
+}
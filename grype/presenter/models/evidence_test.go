@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+func TestAddMatchEvidence(t *testing.T) {
+	m := match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: "GHSA-1", Namespace: "github:language:python"},
+		Package:       pkg.Package{ID: "pkg-1", Name: "requests", Version: "2.30.0"},
+		Details: match.Details{{
+			Type:       match.ExactDirectMatch,
+			Matcher:    match.PythonMatcher,
+			SearchedBy: map[string]interface{}{"language": "python", "namespace": "github:language:python"},
+			Found:      map[string]interface{}{"versionConstraint": "< 2.31.0"},
+			Confidence: 1,
+		}},
+	}
+	models := []Match{{ID: m.ID()}, {ID: "other"}}
+
+	AddMatchEvidence(models, []match.Match{m})
+
+	require.NotNil(t, models[0].Evidence)
+	assert.Equal(t, &MatchEvidence{
+		Nodes: []EvidenceNode{
+			{ID: "package", Kind: "package", Name: "requests", Value: "2.30.0"},
+			{ID: "vulnerability:GHSA-1", Kind: "vulnerability", Name: "GHSA-1", Value: "github:language:python"},
+			{ID: "data-source:github:language:python", Kind: "data-source", Name: "github:language:python"},
+			{ID: "criterion:language=python", Kind: "criterion", Name: "language", Value: "python"},
+		},
+		Edges: []EvidenceEdge{
+			{From: "package", To: "criterion:language=python", Relation: "has"},
+			{From: "criterion:language=python", To: "data-source:github:language:python", Relation: "searched"},
+			{
+				From: "data-source:github:language:python", To: "vulnerability:GHSA-1", Relation: "matched",
+				Matcher: "python-matcher", Type: "exact-direct-match", Confidence: 1,
+				Found: []EvidenceAttribute{{Name: "versionConstraint", Value: "< 2.31.0"}},
+			},
+		},
+	}, models[0].Evidence)
+	assert.Nil(t, models[1].Evidence)
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+)
+
+const (
+	// FuzzyMatched is the outcome of a heuristic comparison that found the package version within the constraint.
+	FuzzyMatched = "matched"
+	// FuzzyMissed is the outcome of a heuristic comparison that found the package version outside of the constraint.
+	FuzzyMissed = "missed"
+	// FuzzyFailed is the outcome of a heuristic comparison that could not compare the package version.
+	FuzzyFailed = "failed"
+)
+
+// FuzzyComparison is a comparison of the package version against the vulnerability constraint that relied on a
+// heuristic, since neither could be compared by a known version format; such comparisons are worth auditing, whether
+// they matched, missed or failed.
+type FuzzyComparison struct {
+	Package       FuzzyComparisonPackage `json:"package"`
+	Vulnerability string                 `json:"vulnerability"`
+	Constraint    string                 `json:"constraint"`
+	Heuristic     string                 `json:"heuristic"` // "calver" or "fuzzy"
+	Outcome       string                 `json:"outcome"`   // "matched", "missed" or "failed"
+	// Reason describes why the vulnerability was not matched, when the comparison missed or failed.
+	Reason string `json:"reason,omitempty"`
+}
+
+type FuzzyComparisonPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+}
+
+func newFuzzyComparisons(matches match.Matches) []FuzzyComparison {
+	var comparisons []FuzzyComparison
+	for _, m := range matches.Sorted() {
+		if m.Vulnerability.Constraint == nil {
+			continue
+		}
+		v, err := version.NewVersionFromPkg(m.Package)
+		if err != nil {
+			continue
+		}
+		heuristic, ok := version.HeuristicComparison(m.Vulnerability.Constraint, v)
+		if !ok {
+			continue
+		}
+		comparisons = append(comparisons, FuzzyComparison{
+			Package:       newFuzzyComparisonPackage(m.Package),
+			Vulnerability: m.Vulnerability.ID,
+			Constraint:    m.Vulnerability.Constraint.String(),
+			Heuristic:     heuristic,
+			Outcome:       FuzzyMatched,
+		})
+	}
+	return comparisons
+}
+
+// NewFuzzyRejections returns the fuzzy comparisons of the rejections that relied on a heuristic (in the given order),
+// leaving out the vulnerabilities that matched the package anyway (e.g. by another record).
+func NewFuzzyRejections(rejections []match.Rejection, matches match.Matches) []FuzzyComparison {
+	type matchKey struct {
+		packageID       pkg.ID
+		vulnerabilityID string
+	}
+	matched := make(map[matchKey]bool)
+	for _, m := range matches.Sorted() {
+		matched[matchKey{packageID: m.Package.ID, vulnerabilityID: m.Vulnerability.ID}] = true
+	}
+
+	var comparisons []FuzzyComparison
+	for _, r := range rejections {
+		if r.Heuristic == "" || matched[matchKey{packageID: r.Package.ID, vulnerabilityID: r.Vulnerability.ID}] {
+			continue
+		}
+		outcome := FuzzyMissed
+		if r.Filter == match.VersionComparisonRejection {
+			outcome = FuzzyFailed
+		}
+		var constraint string
+		if r.Vulnerability.Constraint != nil {
+			constraint = r.Vulnerability.Constraint.String()
+		}
+		comparisons = append(comparisons, FuzzyComparison{
+			Package:       newFuzzyComparisonPackage(r.Package),
+			Vulnerability: r.Vulnerability.ID,
+			Constraint:    constraint,
+			Heuristic:     r.Heuristic,
+			Outcome:       outcome,
+			Reason:        r.Reason,
+		})
+	}
+	return comparisons
+}
+
+func newFuzzyComparisonPackage(p pkg.Package) FuzzyComparisonPackage {
+	return FuzzyComparisonPackage{
+		Name:    p.Name,
+		Version: p.Version,
+		Type:    string(p.Type),
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestNewFuzzyComparisons(t *testing.T) {
+	matches := match.NewMatches(
+		match.Match{
+			Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0001", Constraint: version.MustGetConstraint("< 2024.02", version.UnknownFormat)},
+			Package:       pkg.Package{ID: "1", Name: "tool", Version: "2024.01.15", Type: syftPkg.BinaryPkg},
+		},
+		match.Match{
+			Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0002", Constraint: version.MustGetConstraint("< 1.2.4", version.PythonFormat)},
+			Package:       pkg.Package{ID: "2", Name: "lib", Version: "1.2.3", Type: syftPkg.PythonPkg},
+		},
+	)
+
+	assert.Equal(t, []FuzzyComparison{
+		{
+			Package:       FuzzyComparisonPackage{Name: "tool", Version: "2024.01.15", Type: string(syftPkg.BinaryPkg)},
+			Vulnerability: "CVE-2024-0001",
+			Constraint:    "< 2024.02 (unknown)",
+			Heuristic:     version.CalverHeuristic,
+			Outcome:       FuzzyMatched,
+		},
+	}, newFuzzyComparisons(matches))
+}
+
+func TestNewFuzzyRejections(t *testing.T) {
+	tool := pkg.Package{ID: "1", Name: "tool", Version: "2024.01.15", Type: syftPkg.BinaryPkg}
+	rejection := func(id string, filter match.RejectionFilter, heuristic string) match.Rejection {
+		return match.Rejection{
+			Vulnerability: vulnerability.Vulnerability{ID: id, Constraint: version.MustGetConstraint("< 2023.11", version.UnknownFormat)},
+			Package:       tool,
+			Filter:        filter,
+			Reason:        "not within",
+			Heuristic:     heuristic,
+		}
+	}
+	rejections := []match.Rejection{
+		rejection("CVE-2024-0002", match.VersionConstraintRejection, version.CalverHeuristic),
+		rejection("CVE-2024-0003", match.VersionComparisonRejection, version.FuzzyHeuristic),
+		// not a heuristic comparison
+		rejection("CVE-2024-0004", match.VersionConstraintRejection, ""),
+		// matched by another record
+		rejection("CVE-2024-0005", match.VersionConstraintRejection, version.CalverHeuristic),
+	}
+	matches := match.NewMatches(match.Match{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0005"}, Package: tool})
+
+	comparisons := NewFuzzyRejections(rejections, matches)
+	require.Len(t, comparisons, 2)
+	assert.Equal(t, FuzzyComparison{
+		Package:       FuzzyComparisonPackage{Name: "tool", Version: "2024.01.15", Type: string(syftPkg.BinaryPkg)},
+		Vulnerability: "CVE-2024-0002",
+		Constraint:    "< 2023.11 (unknown)",
+		Heuristic:     version.CalverHeuristic,
+		Outcome:       FuzzyMissed,
+		Reason:        "not within",
+	}, comparisons[0])
+	assert.Equal(t, FuzzyFailed, comparisons[1].Outcome)
+}
@@ -15,6 +15,11 @@ type IgnoreRule struct {
 	VexStatus        string             `json:"vex-status,omitempty"`
 	VexJustification string             `json:"vex-justification,omitempty"`
 	MatchType        string             `json:"match-type,omitempty"`
+	PublishedWithin  string             `json:"published-within,omitempty"`
+	PublishedBefore  string             `json:"published-before,omitempty"`
+	ModifiedWithin   string             `json:"modified-within,omitempty"`
+	ModifiedBefore   string             `json:"modified-before,omitempty"`
+	Environment      string             `json:"environment,omitempty"`
 }
 
 type IgnoreRulePackage struct {
@@ -47,6 +52,11 @@ func newIgnoreRule(r match.IgnoreRule) IgnoreRule {
 		VexStatus:        r.VexStatus,
 		VexJustification: r.VexJustification,
 		MatchType:        string(r.MatchType),
+		PublishedWithin:  r.PublishedWithin,
+		PublishedBefore:  r.PublishedBefore,
+		ModifiedWithin:   r.ModifiedWithin,
+		ModifiedBefore:   r.ModifiedBefore,
+		Environment:      r.Environment,
 	}
 }
 
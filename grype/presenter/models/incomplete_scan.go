@@ -0,0 +1,57 @@
+package models
+
+import (
+	"sort"
+
+	"github.com/anchore/grype/grype/pkg"
+)
+
+// ScanTimeExceeded is the reason of a scan that ran out of its maximum scan time before matching every package.
+const ScanTimeExceeded = "scan-time-exceeded"
+
+// IncompleteScan tells that the results are partial: the matches are those of the scanned packages only, and finding
+// no vulnerabilities for the unscanned packages does not mean they have none.
+type IncompleteScan struct {
+	Reason            string             `json:"reason"`
+	Description       string             `json:"description"`
+	UnscannedPackages []UnscannedPackage `json:"unscannedPackages"`
+}
+
+// UnscannedPackage is a cataloged package that was not matched against the vulnerability data.
+type UnscannedPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+// NewIncompleteScan returns the model of a scan that ran out of time with the given packages unscanned (sorted by
+// name, version and type), or nil when every package was scanned.
+func NewIncompleteScan(unscanned []pkg.Package) *IncompleteScan {
+	if len(unscanned) == 0 {
+		return nil
+	}
+	packages := make([]UnscannedPackage, 0, len(unscanned))
+	for _, p := range unscanned {
+		packages = append(packages, UnscannedPackage{
+			Name:    p.Name,
+			Version: p.Version,
+			Type:    string(p.Type),
+			PURL:    p.PURL,
+		})
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+		if packages[i].Version != packages[j].Version {
+			return packages[i].Version < packages[j].Version
+		}
+		return packages[i].Type < packages[j].Type
+	})
+	return &IncompleteScan{
+		Reason:            ScanTimeExceeded,
+		Description:       "the scan ran out of its maximum scan time before matching every package",
+		UnscannedPackages: packages,
+	}
+}
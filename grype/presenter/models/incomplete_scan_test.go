@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestNewIncompleteScan(t *testing.T) {
+	incomplete := NewIncompleteScan([]pkg.Package{
+		{Name: "zlib", Version: "1.2.13", Type: syftPkg.DebPkg, PURL: "pkg:deb/zlib@1.2.13"},
+		{Name: "openssl", Version: "3.0.11", Type: syftPkg.DebPkg},
+	})
+	assert.Equal(t, &IncompleteScan{
+		Reason:      ScanTimeExceeded,
+		Description: "the scan ran out of its maximum scan time before matching every package",
+		UnscannedPackages: []UnscannedPackage{
+			{Name: "openssl", Version: "3.0.11", Type: string(syftPkg.DebPkg)},
+			{Name: "zlib", Version: "1.2.13", Type: string(syftPkg.DebPkg), PURL: "pkg:deb/zlib@1.2.13"},
+		},
+	}, incomplete)
+	assert.Nil(t, NewIncompleteScan(nil))
+}
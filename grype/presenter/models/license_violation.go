@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/anchore/grype/grype/license"
+)
+
+// LicenseViolation is a declared license of a cataloged package that violates the license policy.
+type LicenseViolation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+	PURL    string `json:"purl,omitempty"`
+	// License is the license as declared by the package, e.g. "MIT OR GPL-3.0-only".
+	License string `json:"license"`
+	// Violations are the licenses within the declared license that are not compliant.
+	Violations []LicenseViolationReason `json:"violations"`
+}
+
+// LicenseViolationReason is a license not compliant with the license policy, and why.
+type LicenseViolationReason struct {
+	License string `json:"license"`
+	Reason  string `json:"reason"`
+}
+
+// NewLicenseViolations returns the models of the license policy violations, in the order of the violations.
+func NewLicenseViolations(violations []license.Violation) []LicenseViolation {
+	var models []LicenseViolation
+	for _, v := range violations {
+		m := LicenseViolation{
+			Name:    v.Package.Name,
+			Version: v.Package.Version,
+			Type:    string(v.Package.Type),
+			PURL:    v.Package.PURL,
+			License: v.License,
+		}
+		for i := range v.Licenses {
+			m.Violations = append(m.Violations, LicenseViolationReason{
+				License: v.Licenses[i],
+				Reason:  string(v.Reasons[i]),
+			})
+		}
+		models = append(models, m)
+	}
+	return models
+}
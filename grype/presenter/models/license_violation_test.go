@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/license"
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestNewLicenseViolations(t *testing.T) {
+	violations := []license.Violation{
+		{
+			Package:  pkg.Package{Name: "readline", Version: "8.2", Type: syftPkg.DebPkg, PURL: "pkg:deb/readline@8.2"},
+			License:  "GPL-3.0-only OR AGPL-3.0-only",
+			Licenses: []string{"GPL-3.0-only", "AGPL-3.0-only"},
+			Reasons:  []license.Reason{license.Denied, license.NotAllowed},
+		},
+	}
+
+	assert.Equal(t, []LicenseViolation{
+		{
+			Name:    "readline",
+			Version: "8.2",
+			Type:    string(syftPkg.DebPkg),
+			PURL:    "pkg:deb/readline@8.2",
+			License: "GPL-3.0-only OR AGPL-3.0-only",
+			Violations: []LicenseViolationReason{
+				{License: "GPL-3.0-only", Reason: "denied"},
+				{License: "AGPL-3.0-only", Reason: "not-allowed"},
+			},
+		},
+	}, NewLicenseViolations(violations))
+	assert.Empty(t, NewLicenseViolations(nil))
+}
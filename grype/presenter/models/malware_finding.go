@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/anchore/grype/grype/malware"
+)
+
+// MalwareFinding is a cataloged package found to be a known-malicious package. These are supply-chain compromises
+// rather than vulnerabilities, and so are reported apart from the matches.
+type MalwareFinding struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+	PURL    string `json:"purl,omitempty"`
+	// ID is the ID of the OSV record of the malicious package (e.g. "MAL-2022-1234").
+	ID        string `json:"id"`
+	Summary   string `json:"summary,omitempty"`
+	MatchedBy string `json:"matchedBy"`
+	Digest    string `json:"digest,omitempty"`
+}
+
+// NewMalwareFindings returns the models of the malware findings, in the order of the findings.
+func NewMalwareFindings(findings []malware.Finding) []MalwareFinding {
+	var models []MalwareFinding
+	for _, f := range findings {
+		models = append(models, MalwareFinding{
+			Name:      f.Package.Name,
+			Version:   f.Package.Version,
+			Type:      string(f.Package.Type),
+			PURL:      f.Package.PURL,
+			ID:        f.ID,
+			Summary:   f.Summary,
+			MatchedBy: string(f.MatchedBy),
+			Digest:    f.Digest,
+		})
+	}
+	return models
+}
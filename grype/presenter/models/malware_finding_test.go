@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/malware"
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestNewMalwareFindings(t *testing.T) {
+	findings := []malware.Finding{
+		{
+			Package:   pkg.Package{Name: "evil-pkg", Version: "1.0.1", Type: syftPkg.NpmPkg, PURL: "pkg:npm/evil-pkg@1.0.1"},
+			ID:        "MAL-2022-1001",
+			Summary:   "Malicious code in evil-pkg (npm)",
+			MatchedBy: malware.VersionMatch,
+		},
+		{
+			Package:   pkg.Package{Name: "bad-lib", Version: "2.0.0", Type: syftPkg.JavaPkg},
+			ID:        "MAL-2024-3003",
+			MatchedBy: malware.DigestMatch,
+			Digest:    "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		},
+	}
+
+	assert.Equal(t, []MalwareFinding{
+		{
+			Name:      "evil-pkg",
+			Version:   "1.0.1",
+			Type:      string(syftPkg.NpmPkg),
+			PURL:      "pkg:npm/evil-pkg@1.0.1",
+			ID:        "MAL-2022-1001",
+			Summary:   "Malicious code in evil-pkg (npm)",
+			MatchedBy: "version",
+		},
+		{
+			Name:      "bad-lib",
+			Version:   "2.0.0",
+			Type:      string(syftPkg.JavaPkg),
+			ID:        "MAL-2024-3003",
+			MatchedBy: "digest",
+			Digest:    "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		},
+	}, NewMalwareFindings(findings))
+	assert.Empty(t, NewMalwareFindings(nil))
+}
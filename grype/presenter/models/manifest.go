@@ -0,0 +1,148 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anchore/clio"
+	syftSource "github.com/anchore/syft/syft/source"
+)
+
+// ScanManifest records the conditions of a scan (the tool, the DB, the configuration, the target and the timing),
+// embedded in every report so that any report can be correlated back to the exact scan that produced it.
+type ScanManifest struct {
+	// ScanID is the ID of the scan the log entries of the scan are logged with, if any.
+	ScanID  string         `json:"scanId,omitempty"`
+	Tool    string         `json:"tool"`
+	Version string         `json:"version"`
+	DB      ScanManifestDB `json:"db"`
+	// ConfigHash is the SHA-256 digest of the configuration of the scan (as JSON), as "sha256:<hex>".
+	ConfigHash string             `json:"configHash"`
+	Target     ScanManifestTarget `json:"target"`
+	StartedAt  string             `json:"startedAt"`
+	FinishedAt string             `json:"finishedAt"`
+	DurationMs int64              `json:"durationMs"`
+}
+
+// ScanManifestDB is the vulnerability DB a scan used.
+type ScanManifestDB struct {
+	Checksum      string `json:"checksum,omitempty"`
+	Built         string `json:"built,omitempty"`
+	SchemaVersion int    `json:"schemaVersion,omitempty"`
+}
+
+// ScanManifestTarget is the identity of the scanned artifact.
+type ScanManifestTarget struct {
+	// Input is the target as given to the scan (e.g. "alpine:3.18" or "sbom:./sbom.json").
+	Input string `json:"input,omitempty"`
+	// Type is the kind of the scanned artifact: "image", "directory", "file" or "sbom".
+	Type string `json:"type,omitempty"`
+	Name string `json:"name,omitempty"`
+	// ID is the ID of the source of the scanned packages.
+	ID string `json:"id,omitempty"`
+	// Digest is the digest of the scanned artifact: the manifest digest (or ID) of an image, or the digest of a file.
+	Digest string `json:"digest,omitempty"`
+}
+
+// ScanManifestProperty is a field of the manifest as a flat name and value (e.g. "db.checksum").
+type ScanManifestProperty struct {
+	Name  string
+	Value string
+}
+
+// NewScanManifest returns the manifest of a scan of the source with the configuration, which started and finished at
+// the given times.
+func NewScanManifest(id clio.Identification, db ScanManifestDB, config interface{}, input string, src *syftSource.Description, started, finished time.Time) (*ScanManifest, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash the configuration of the scan: %w", err)
+	}
+
+	return &ScanManifest{
+		Tool:       id.Name,
+		Version:    id.Version,
+		DB:         db,
+		ConfigHash: fmt.Sprintf("sha256:%x", sha256.Sum256(configJSON)),
+		Target:     newScanManifestTarget(input, src),
+		StartedAt:  started.UTC().Format(time.RFC3339Nano),
+		FinishedAt: finished.UTC().Format(time.RFC3339Nano),
+		DurationMs: finished.Sub(started).Milliseconds(),
+	}, nil
+}
+
+func newScanManifestTarget(input string, src *syftSource.Description) ScanManifestTarget {
+	t := ScanManifestTarget{Input: input}
+	if strings.HasPrefix(input, "sbom:") {
+		t.Type = "sbom"
+	}
+	if src == nil {
+		return t
+	}
+
+	t.Name = src.Name
+	t.ID = src.ID
+	switch m := src.Metadata.(type) {
+	case syftSource.ImageMetadata:
+		t.Type = "image"
+		t.Name = m.UserInput
+		t.Digest = m.ManifestDigest
+		if t.Digest == "" {
+			t.Digest = m.ID
+		}
+	case syftSource.DirectoryMetadata:
+		t.Type = "directory"
+	case syftSource.FileMetadata:
+		t.Type = "file"
+		for _, d := range m.Digests {
+			if d.Algorithm == "sha256" {
+				t.Digest = "sha256:" + d.Value
+			}
+		}
+	}
+	return t
+}
+
+// Properties returns the fields of the manifest as flat names and values, for the formats embedding the manifest as
+// properties (e.g. the CycloneDX metadata). The empty fields are left out.
+func (m *ScanManifest) Properties() []ScanManifestProperty {
+	if m == nil {
+		return nil
+	}
+	var props []ScanManifestProperty
+	add := func(name, value string) {
+		if value != "" {
+			props = append(props, ScanManifestProperty{Name: name, Value: value})
+		}
+	}
+	add("scanId", m.ScanID)
+	add("tool", m.Tool)
+	add("version", m.Version)
+	add("db.checksum", m.DB.Checksum)
+	add("db.built", m.DB.Built)
+	if m.DB.SchemaVersion > 0 {
+		add("db.schemaVersion", strconv.Itoa(m.DB.SchemaVersion))
+	}
+	add("configHash", m.ConfigHash)
+	add("target.input", m.Target.Input)
+	add("target.type", m.Target.Type)
+	add("target.name", m.Target.Name)
+	add("target.id", m.Target.ID)
+	add("target.digest", m.Target.Digest)
+	add("startedAt", m.StartedAt)
+	add("finishedAt", m.FinishedAt)
+	add("durationMs", strconv.FormatInt(m.DurationMs, 10))
+	return props
+}
+
+// String returns the fields of the manifest as space-separated name=value pairs, for the text formats.
+func (m *ScanManifest) String() string {
+	var pairs []string
+	for _, p := range m.Properties() {
+		pairs = append(pairs, p.Name+"="+p.Value)
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/syft/syft/file"
+	syftSource "github.com/anchore/syft/syft/source"
+)
+
+func TestNewScanManifest(t *testing.T) {
+	id := clio.Identification{Name: "grype", Version: "1.2.3"}
+	db := ScanManifestDB{Checksum: "sha256:abc", Built: "2024-06-01T00:00:00Z", SchemaVersion: 5}
+	started := time.Date(2024, 6, 2, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(1500 * time.Millisecond)
+
+	tests := []struct {
+		name  string
+		input string
+		src   *syftSource.Description
+		want  ScanManifestTarget
+	}{
+		{
+			name:  "image",
+			input: "alpine:3.18",
+			src: &syftSource.Description{
+				ID:       "source-id",
+				Metadata: syftSource.ImageMetadata{UserInput: "alpine:3.18", ID: "sha256:image-id", ManifestDigest: "sha256:manifest"},
+			},
+			want: ScanManifestTarget{Input: "alpine:3.18", Type: "image", Name: "alpine:3.18", ID: "source-id", Digest: "sha256:manifest"},
+		},
+		{
+			name:  "image without a manifest digest",
+			input: "docker-archive:image.tar",
+			src: &syftSource.Description{
+				Metadata: syftSource.ImageMetadata{UserInput: "image.tar", ID: "sha256:image-id"},
+			},
+			want: ScanManifestTarget{Input: "docker-archive:image.tar", Type: "image", Name: "image.tar", Digest: "sha256:image-id"},
+		},
+		{
+			name:  "file",
+			input: "app.jar",
+			src: &syftSource.Description{
+				Name: "app.jar",
+				Metadata: syftSource.FileMetadata{
+					Path:    "app.jar",
+					Digests: []file.Digest{{Algorithm: "sha1", Value: "01"}, {Algorithm: "sha256", Value: "02"}},
+				},
+			},
+			want: ScanManifestTarget{Input: "app.jar", Type: "file", Name: "app.jar", Digest: "sha256:02"},
+		},
+		{
+			name:  "directory",
+			input: "dir:.",
+			src:   &syftSource.Description{Name: ".", Metadata: syftSource.DirectoryMetadata{Path: "."}},
+			want:  ScanManifestTarget{Input: "dir:.", Type: "directory", Name: "."},
+		},
+		{
+			name:  "sbom",
+			input: "sbom:./sbom.json",
+			want:  ScanManifestTarget{Input: "sbom:./sbom.json", Type: "sbom"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewScanManifest(id, db, map[string]string{"output": "json"}, tt.input, tt.src, started, finished)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, m.Target)
+			assert.Equal(t, "grype", m.Tool)
+			assert.Equal(t, "1.2.3", m.Version)
+			assert.Equal(t, db, m.DB)
+			assert.Equal(t, "2024-06-02T10:00:00Z", m.StartedAt)
+			assert.Equal(t, "2024-06-02T10:00:01.5Z", m.FinishedAt)
+			assert.Equal(t, int64(1500), m.DurationMs)
+		})
+	}
+}
+
+func TestNewScanManifest_configHash(t *testing.T) {
+	hash := func(config any) string {
+		m, err := NewScanManifest(clio.Identification{}, ScanManifestDB{}, config, "", nil, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		return m.ConfigHash
+	}
+
+	assert.Equal(t, hash(map[string]string{"a": "1", "b": "2"}), hash(map[string]string{"b": "2", "a": "1"}))
+	assert.NotEqual(t, hash(map[string]string{"a": "1"}), hash(map[string]string{"a": "2"}))
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, hash(nil))
+
+	_, err := NewScanManifest(clio.Identification{}, ScanManifestDB{}, func() {}, "", nil, time.Time{}, time.Time{})
+	require.ErrorContains(t, err, "unable to hash the configuration")
+}
+
+func TestScanManifest_String(t *testing.T) {
+	m := &ScanManifest{
+		ScanID:     "scan-1",
+		Tool:       "grype",
+		Version:    "1.2.3",
+		DB:         ScanManifestDB{Checksum: "sha256:abc", SchemaVersion: 5},
+		ConfigHash: "sha256:def",
+		Target:     ScanManifestTarget{Input: "alpine:3.18", Type: "image", Digest: "sha256:123"},
+		StartedAt:  "2024-06-02T10:00:00Z",
+		FinishedAt: "2024-06-02T10:00:01Z",
+		DurationMs: 1000,
+	}
+	assert.Equal(t, "scanId=scan-1 tool=grype version=1.2.3 db.checksum=sha256:abc db.schemaVersion=5 configHash=sha256:def "+
+		"target.input=alpine:3.18 target.type=image target.digest=sha256:123 startedAt=2024-06-02T10:00:00Z "+
+		"finishedAt=2024-06-02T10:00:01Z durationMs=1000", m.String())
+
+	var nilManifest *ScanManifest
+	assert.Empty(t, nilManifest.Properties())
+	assert.Empty(t, nilManifest.String())
+}
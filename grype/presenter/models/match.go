@@ -7,22 +7,37 @@ import (
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/stringutil"
 )
 
 // Match is a single item for the JSON array reported
 type Match struct {
+	ID                     string                  `json:"id"` // stable across scans, see match.Match.ID
 	Vulnerability          Vulnerability           `json:"vulnerability"`
 	RelatedVulnerabilities []VulnerabilityMetadata `json:"relatedVulnerabilities"`
 	MatchDetails           []MatchDetails          `json:"matchDetails"`
 	Artifact               Package                 `json:"artifact"`
+	Providers              []string                `json:"providers,omitempty"`     // the providers that reported the vulnerability, when several are combined
+	PackageStatus          []PackageStatus         `json:"packageStatus,omitempty"` // whether the package is deprecated, yanked or archived
+	Annotations            map[string]string       `json:"annotations,omitempty"`   // set by the post-processors of the results (e.g. the criticality of the asset)
+	Evidence               *MatchEvidence          `json:"evidence,omitempty"`      // the "why matched" graph of the match, when requested
 }
 
 // MatchDetails contains all data that indicates how the result match was found
 type MatchDetails struct {
 	Type       string      `json:"type"`
 	Matcher    string      `json:"matcher"`
-	SearchedBy interface{} `json:"searchedBy"` // The specific attributes that were used to search (other than package name and version) --this indicates "how" the match was made.
-	Found      interface{} `json:"found"`      // The specific attributes on the vulnerability object that were matched with --this indicates "what" was matched on / within.
+	SearchedBy interface{} `json:"searchedBy"`           // The specific attributes that were used to search (other than package name and version) --this indicates "how" the match was made.
+	Found      interface{} `json:"found"`                // The specific attributes on the vulnerability object that were matched with --this indicates "what" was matched on / within.
+	Confidence float64     `json:"confidence,omitempty"` // The certainty of the match as a ratio, scaled by the trust in the provider of the vulnerability data.
+	Alias      *MatchAlias `json:"alias,omitempty"`      // The alternate package name the match was found under, for an alias match.
+}
+
+// MatchAlias is an alternate name of the package that a match was found under.
+type MatchAlias struct {
+	Name       string  `json:"name"`
+	Source     string  `json:"source"`
+	Confidence float64 `json:"confidence"`
 }
 
 func newMatch(m match.Match, p pkg.Package, metadataProvider vulnerability.MetadataProvider) (*Match, error) {
@@ -41,6 +56,7 @@ func newMatch(m match.Match, p pkg.Package, metadataProvider vulnerability.Metad
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch vuln=%q metadata: %+v", m.Vulnerability.ID, err)
 	}
+	metadata = m.WithSeverityOverride(metadata)
 
 	details := make([]MatchDetails, len(m.Details))
 	for idx, d := range m.Details {
@@ -49,19 +65,28 @@ func newMatch(m match.Match, p pkg.Package, metadataProvider vulnerability.Metad
 			Matcher:    string(d.Matcher),
 			SearchedBy: d.SearchedBy,
 			Found:      d.Found,
+			Confidence: d.Confidence,
+		}
+		if d.Alias != nil {
+			details[idx].Alias = &MatchAlias{Name: d.Alias.Name, Source: d.Alias.Source, Confidence: d.Alias.Confidence}
 		}
 	}
 
 	return &Match{
+		ID:                     m.ID(),
 		Vulnerability:          NewVulnerability(m.Vulnerability, metadata),
 		Artifact:               newPackage(p),
 		RelatedVulnerabilities: relatedVulnerabilities,
 		MatchDetails:           details,
+		Providers:              m.Vulnerability.Providers,
 	}, nil
 }
 
 var _ sort.Interface = (*MatchSort)(nil)
 
+// MatchSort sorts matches in the same order as match.ByElements, which is the order used by every output format: by
+// package (name, version, type), then by vulnerability ID, with the vulnerability namespace, fix versions, package
+// locations and package ID as tie-breaks.
 type MatchSort []Match
 
 // Len is the number of elements in the collection.
@@ -70,23 +95,24 @@ func (m MatchSort) Len() int {
 }
 
 // Less reports whether the element with index i should sort before the element with index j.
-// sort should consistent across presenters: name, version, type, severity, vulnerability
 func (m MatchSort) Less(i, j int) bool {
-	matchI := m[i]
-	matchJ := m[j]
-	if matchI.Artifact.Name == matchJ.Artifact.Name {
-		if matchI.Artifact.Version == matchJ.Artifact.Version {
-			if matchI.Artifact.Type == matchJ.Artifact.Type {
-				if SeverityScore(matchI.Vulnerability.Severity) == SeverityScore(matchJ.Vulnerability.Severity) {
-					return matchI.Vulnerability.ID > matchJ.Vulnerability.ID
-				}
-				return SeverityScore(matchI.Vulnerability.Severity) > SeverityScore(matchJ.Vulnerability.Severity)
-			}
-			return matchI.Artifact.Type < matchJ.Artifact.Type
+	a, b := m[i], m[j]
+	fields := [][2]string{
+		{a.Artifact.Name, b.Artifact.Name},
+		{a.Artifact.Version, b.Artifact.Version},
+		{string(a.Artifact.Type), string(b.Artifact.Type)},
+		{a.Vulnerability.ID, b.Vulnerability.ID},
+		{a.Vulnerability.Namespace, b.Vulnerability.Namespace},
+		{stringutil.SortedJoin(a.Vulnerability.Fix.Versions, ","), stringutil.SortedJoin(b.Vulnerability.Fix.Versions, ",")},
+		{match.LocationsKey(a.Artifact.Locations), match.LocationsKey(b.Artifact.Locations)},
+		{a.Artifact.ID, b.Artifact.ID},
+	}
+	for _, f := range fields {
+		if f[0] != f[1] {
+			return f[0] < f[1]
 		}
-		return matchI.Artifact.Version < matchJ.Artifact.Version
 	}
-	return matchI.Artifact.Name < matchJ.Artifact.Name
+	return false
 }
 
 // Swap swaps the elements with indexes i and j.
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestNewMatch_providers(t *testing.T) {
+	p := pkg.Package{ID: "1", Name: "requests", Version: "2.28.0", Type: syftPkg.PythonPkg}
+	m, err := newMatch(match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: "CVE-1999-0001", Namespace: "source-1", Providers: []string{"grype-db", "osv.dev"}},
+		Package:       p,
+	}, p, NewMetadataMock())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grype-db", "osv.dev"}, m.Providers)
+}
+
+func TestNewMatch_packageProperties(t *testing.T) {
+	p := pkg.Package{ID: "1", Name: "lodash", Version: "4.17.20", Type: syftPkg.NpmPkg, Properties: []pkg.Property{{Name: "acme:team", Value: "payments"}}}
+	m, err := newMatch(match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: "CVE-1999-0001", Namespace: "source-1"},
+		Package:       p,
+	}, p, NewMetadataMock())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageProperty{{Name: "acme:team", Value: "payments"}}, m.Artifact.Properties)
+}
@@ -13,6 +13,7 @@ type Package struct {
 	Name         string             `json:"name"`
 	Version      string             `json:"version"`
 	Type         syftPkg.Type       `json:"type"`
+	Runtime      string             `json:"runtime,omitempty"`
 	Locations    []file.Coordinates `json:"locations"`
 	Language     syftPkg.Language   `json:"language"`
 	Licenses     []string           `json:"licenses"`
@@ -21,6 +22,8 @@ type Package struct {
 	Upstreams    []UpstreamPackage  `json:"upstreams"`
 	MetadataType string             `json:"metadataType,omitempty"`
 	Metadata     interface{}        `json:"metadata,omitempty"`
+	Origin       string             `json:"origin,omitempty"`
+	Properties   []PackageProperty  `json:"properties,omitempty"`
 }
 
 type UpstreamPackage struct {
@@ -28,6 +31,12 @@ type UpstreamPackage struct {
 	Version string `json:"version,omitempty"`
 }
 
+// PackageProperty is a custom property of the package in the SBOM it was read from (e.g. a CycloneDX property).
+type PackageProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
 func newPackage(p pkg.Package) Package {
 	var cpes = make([]string, 0)
 	for _, c := range p.CPEs {
@@ -53,6 +62,14 @@ func newPackage(p pkg.Package) Package {
 		})
 	}
 
+	var properties []PackageProperty
+	for _, property := range p.Properties {
+		properties = append(properties, PackageProperty{
+			Name:  property.Name,
+			Value: property.Value,
+		})
+	}
+
 	return Package{
 		ID:           string(p.ID),
 		Name:         p.Name,
@@ -61,10 +78,13 @@ func newPackage(p pkg.Package) Package {
 		Licenses:     licenses,
 		Language:     p.Language,
 		Type:         p.Type,
+		Runtime:      p.Runtime,
 		CPEs:         cpes,
 		PURL:         p.PURL,
 		Upstreams:    upstreams,
 		MetadataType: packagemetadata.JSONName(p.Metadata),
 		Metadata:     p.Metadata,
+		Origin:       p.Origin,
+		Properties:   properties,
 	}
 }
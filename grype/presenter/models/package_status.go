@@ -0,0 +1,27 @@
+package models
+
+import (
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/supplychain"
+)
+
+// PackageStatus is the maintenance status of the package of a match, such as a deprecated package or a yanked version.
+type PackageStatus struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// AnnotatePackageStatuses sets the statuses of the packages of the matches.
+func AnnotatePackageStatuses(matches []Match, statuses map[pkg.ID][]supplychain.PackageStatus) {
+	if len(statuses) == 0 {
+		return
+	}
+	for i := range matches {
+		for _, s := range statuses[pkg.ID(matches[i].Artifact.ID)] {
+			matches[i].PackageStatus = append(matches[i].PackageStatus, PackageStatus{
+				Status: string(s.Status),
+				Reason: s.Reason,
+			})
+		}
+	}
+}
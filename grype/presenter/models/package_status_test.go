@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/supplychain"
+)
+
+func TestAnnotatePackageStatuses(t *testing.T) {
+	matches := []Match{
+		{Artifact: Package{ID: "request"}},
+		{Artifact: Package{ID: "lodash"}},
+	}
+
+	AnnotatePackageStatuses(matches, map[pkg.ID][]supplychain.PackageStatus{
+		"request": {
+			{Name: "request", Status: supplychain.Deprecated, Reason: "use got instead"},
+			{Name: "request", Status: supplychain.Archived},
+		},
+	})
+
+	assert.Equal(t, []PackageStatus{
+		{Status: "deprecated", Reason: "use got instead"},
+		{Status: "archived"},
+	}, matches[0].PackageStatus)
+	assert.Empty(t, matches[1].PackageStatus)
+}
@@ -0,0 +1,48 @@
+package models
+
+// Pagination tells that the report lists a single page of the matches: at most Limit matches (all the remaining ones
+// when Limit is zero) from the match at Offset on, of the Total matches of the scan in the order of the report.
+type Pagination struct {
+	Offset   int `json:"offset"`
+	Limit    int `json:"limit"`
+	Total    int `json:"total"`
+	Returned int `json:"returned"`
+	// NextOffset is the offset of the next page, when there is one.
+	NextOffset *int `json:"nextOffset,omitempty"`
+}
+
+// MatchSummary counts the matches of the scan, those of the other pages of the report included.
+type MatchSummary struct {
+	Total int `json:"total"`
+	// BySeverity counts the matches by the severity of their vulnerability ("Unknown" when it has none).
+	BySeverity map[string]int `json:"bySeverity"`
+}
+
+// Paginate keeps a single page of the matches of the document (at most limit matches from the offset on, or all the
+// remaining matches when limit is zero), recording the page and the summary of every match in the document. The
+// matches of the platforms of the document are not paginated.
+func Paginate(doc *Document, offset, limit int) {
+	summary := &MatchSummary{Total: len(doc.Matches), BySeverity: make(map[string]int)}
+	for _, m := range doc.Matches {
+		severity := m.Vulnerability.Severity
+		if severity == "" {
+			severity = "Unknown"
+		}
+		summary.BySeverity[severity]++
+	}
+
+	total := len(doc.Matches)
+	start := min(max(offset, 0), total)
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+	page := &Pagination{Offset: start, Limit: limit, Total: total, Returned: end - start}
+	if end < total {
+		page.NextOffset = &end
+	}
+
+	doc.Matches = doc.Matches[start:end]
+	doc.Pagination = page
+	doc.Summary = summary
+}
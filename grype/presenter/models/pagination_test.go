@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate(t *testing.T) {
+	newDoc := func() Document {
+		var doc Document
+		for _, severity := range []string{"High", "Critical", "High", ""} {
+			doc.Matches = append(doc.Matches, Match{Vulnerability: Vulnerability{VulnerabilityMetadata: VulnerabilityMetadata{Severity: severity}}})
+		}
+		return doc
+	}
+	summary := &MatchSummary{Total: 4, BySeverity: map[string]int{"Critical": 1, "High": 2, "Unknown": 1}}
+
+	doc := newDoc()
+	Paginate(&doc, 1, 2)
+	next := 3
+	assert.Equal(t, &Pagination{Offset: 1, Limit: 2, Total: 4, Returned: 2, NextOffset: &next}, doc.Pagination)
+	assert.Equal(t, summary, doc.Summary)
+	assert.Equal(t, []string{"Critical", "High"}, []string{doc.Matches[0].Vulnerability.Severity, doc.Matches[1].Vulnerability.Severity})
+
+	// the last page has no next page, and a limit of zero lists every remaining match
+	doc = newDoc()
+	Paginate(&doc, 2, 0)
+	assert.Equal(t, &Pagination{Offset: 2, Total: 4, Returned: 2}, doc.Pagination)
+	assert.Len(t, doc.Matches, 2)
+
+	doc = newDoc()
+	Paginate(&doc, 10, 2)
+	assert.Equal(t, &Pagination{Offset: 4, Limit: 2, Total: 4}, doc.Pagination)
+	assert.Empty(t, doc.Matches)
+	assert.Equal(t, summary, doc.Summary)
+}
@@ -1,9 +1,16 @@
 package models
 
 import (
+	"time"
+
 	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/baseimage"
+	"github.com/anchore/grype/grype/license"
+	"github.com/anchore/grype/grype/malware"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/priority"
+	"github.com/anchore/grype/grype/supplychain"
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/syft/syft/sbom"
 )
@@ -18,4 +25,59 @@ type PresenterConfig struct {
 	SBOM             *sbom.SBOM
 	AppConfig        interface{}
 	DBStatus         interface{}
+	// Platforms are the results of each scanned platform when scanning several platforms of an image index, in which
+	// case the matches, packages and context above are those of all platforms combined.
+	Platforms []PlatformResult
+	// UnsupportedPackages are the packages that could not be matched against any vulnerability data.
+	UnsupportedPackages []pkg.UnsupportedPackage
+	// Malware are the packages found to be known-malicious packages.
+	Malware []malware.Finding
+	// SupplyChain are the signs of supply-chain risk about the packages that are not vulnerabilities.
+	SupplyChain []supplychain.Warning
+	// PackageStatuses are the maintenance statuses of the packages with any status, to annotate their matches with.
+	PackageStatuses map[pkg.ID][]supplychain.PackageStatus
+	// Annotations are the annotations of the matches (by match ID) set by the post-processors of the results.
+	Annotations map[string]map[string]string
+	// LicenseViolations are the licenses of the packages that violate the license policy.
+	LicenseViolations []license.Violation
+	// BaseImage is the advice for the base image of the scanned image, if the base image is known.
+	BaseImage *baseimage.Advice
+	// UnscannedPackages are the packages left unscanned because the scan ran out of time, when the results are partial.
+	UnscannedPackages []pkg.Package
+	// Rejections are the candidate vulnerabilities that were considered but rejected, when they are collected.
+	Rejections []match.Rejection
+	// FuzzyRejections are the candidate vulnerabilities rejected after a heuristic comparison of their version
+	// constraint, reported with the fuzzy comparisons of the matches.
+	FuzzyRejections []match.Rejection
+	// MatchEvidence includes the "why matched" graph of each match (see match.Evidence) in the reports that support it.
+	MatchEvidence bool
+	// MaxResults and ResultsOffset select a single page of the matches (at most MaxResults matches, from the match at
+	// ResultsOffset on) in the reports that support it, when either is set.
+	MaxResults    int
+	ResultsOffset int
+	// Environment is the environment context of the scanned artifact (e.g. "env" is "prod"), if given.
+	Environment map[string]string
+	// Enrichment is the EPSS and KEV data of the vulnerabilities, when the matches are ranked.
+	Enrichment *priority.Enrichment
+	// Manifest records the conditions of the scan, embedded in every report.
+	Manifest *ScanManifest
+	// Now is the clock giving the timestamps of the reports, time.Now when nil.
+	Now func() time.Time
+}
+
+// ReportTime returns the time of a report made with the given clock, the current time when there is no clock.
+func ReportTime(now func() time.Time) time.Time {
+	if now == nil {
+		return time.Now()
+	}
+	return now()
+}
+
+// PlatformResult is the result of scanning a single platform of an image index.
+type PlatformResult struct {
+	Platform       string
+	Matches        match.Matches
+	IgnoredMatches []match.IgnoredMatch
+	Packages       []pkg.Package
+	Context        pkg.Context
 }
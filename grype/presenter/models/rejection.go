@@ -0,0 +1,70 @@
+package models
+
+import (
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+)
+
+// Rejection is a candidate vulnerability of a package that was considered, but rejected by a filter of the matching.
+type Rejection struct {
+	Vulnerability RejectedVulnerability `json:"vulnerability"`
+	Package       RejectedPackage       `json:"package"`
+	// Filter is the filter that rejected the vulnerability (e.g. "version-constraint" or "qualifier")
+	Filter string `json:"filter"`
+	Reason string `json:"reason"`
+}
+
+// RejectedVulnerability is the record of the vulnerability DB that was rejected.
+type RejectedVulnerability struct {
+	ID         string `json:"id"`
+	Namespace  string `json:"namespace"`
+	Constraint string `json:"versionConstraint,omitempty"`
+}
+
+// RejectedPackage is the package the vulnerability was rejected for.
+type RejectedPackage struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+}
+
+// NewRejections returns the models of the rejections (in the given order), leaving out the vulnerabilities that
+// matched the package anyway (e.g. a CPE rejected by its target software while another CPE matched).
+func NewRejections(rejections []match.Rejection, matches match.Matches) []Rejection {
+	type matchKey struct {
+		packageID       pkg.ID
+		vulnerabilityID string
+	}
+	matched := make(map[matchKey]bool)
+	for _, m := range matches.Sorted() {
+		matched[matchKey{packageID: m.Package.ID, vulnerabilityID: m.Vulnerability.ID}] = true
+	}
+
+	var out []Rejection
+	for _, r := range rejections {
+		if matched[matchKey{packageID: r.Package.ID, vulnerabilityID: r.Vulnerability.ID}] {
+			continue
+		}
+		var constraint string
+		if r.Vulnerability.Constraint != nil {
+			constraint = r.Vulnerability.Constraint.String()
+		}
+		out = append(out, Rejection{
+			Vulnerability: RejectedVulnerability{
+				ID:         r.Vulnerability.ID,
+				Namespace:  r.Vulnerability.Namespace,
+				Constraint: constraint,
+			},
+			Package: RejectedPackage{
+				ID:      string(r.Package.ID),
+				Name:    r.Package.Name,
+				Version: r.Package.Version,
+				Type:    string(r.Package.Type),
+			},
+			Filter: string(r.Filter),
+			Reason: r.Reason,
+		})
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/anchore/grype/grype/supplychain"
+)
+
+// SupplyChainWarning is a sign of supply-chain risk about a cataloged package that is not a vulnerability, such as a
+// name similar to that of a popular package.
+type SupplyChainWarning struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+	PURL    string `json:"purl,omitempty"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// NewSupplyChainWarnings returns the models of the supply-chain warnings, in the order of the warnings.
+func NewSupplyChainWarnings(warnings []supplychain.Warning) []SupplyChainWarning {
+	var models []SupplyChainWarning
+	for _, w := range warnings {
+		models = append(models, SupplyChainWarning{
+			Name:    w.Package.Name,
+			Version: w.Package.Version,
+			Type:    string(w.Package.Type),
+			PURL:    w.Package.PURL,
+			Kind:    string(w.Kind),
+			Message: w.Message,
+		})
+	}
+	return models
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/supplychain"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestNewSupplyChainWarnings(t *testing.T) {
+	warnings := []supplychain.Warning{
+		{
+			Package: pkg.Package{Name: "lodahs", Version: "1.0.0", Type: syftPkg.NpmPkg, PURL: "pkg:npm/lodahs@1.0.0"},
+			Kind:    supplychain.Typosquat,
+			Message: `the name is 1 edit(s) from the popular npm package "lodash"`,
+		},
+	}
+
+	assert.Equal(t, []SupplyChainWarning{
+		{
+			Name:    "lodahs",
+			Version: "1.0.0",
+			Type:    string(syftPkg.NpmPkg),
+			PURL:    "pkg:npm/lodahs@1.0.0",
+			Kind:    "typosquat",
+			Message: `the name is 1 edit(s) from the popular npm package "lodash"`,
+		},
+	}, NewSupplyChainWarnings(warnings))
+	assert.Empty(t, NewSupplyChainWarnings(nil))
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"sort"
+
+	"github.com/anchore/grype/grype/pkg"
+)
+
+// UnsupportedPackage is a cataloged package that could not be matched against any vulnerability data, so that the
+// absence of matches for it is not evidence of the absence of vulnerabilities.
+type UnsupportedPackage struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Type        string `json:"type"`
+	PURL        string `json:"purl,omitempty"`
+	Reason      string `json:"reason"`
+	Description string `json:"description"`
+}
+
+// NewUnsupportedPackages returns the models of the unsupported packages, sorted by name, version and type.
+func NewUnsupportedPackages(unsupported []pkg.UnsupportedPackage) []UnsupportedPackage {
+	var models []UnsupportedPackage
+	for _, u := range unsupported {
+		models = append(models, UnsupportedPackage{
+			Name:        u.Package.Name,
+			Version:     u.Package.Version,
+			Type:        string(u.Package.Type),
+			PURL:        u.Package.PURL,
+			Reason:      string(u.Reason),
+			Description: u.Reason.Description(),
+		})
+	}
+	sort.SliceStable(models, func(i, j int) bool {
+		if models[i].Name != models[j].Name {
+			return models[i].Name < models[j].Name
+		}
+		if models[i].Version != models[j].Version {
+			return models[i].Version < models[j].Version
+		}
+		return models[i].Type < models[j].Type
+	})
+	return models
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestNewUnsupportedPackages(t *testing.T) {
+	unsupported := []pkg.UnsupportedPackage{
+		{Package: pkg.Package{Name: "zlib", Version: "1.2.13", Type: syftPkg.DebPkg, PURL: "pkg:deb/zlib@1.2.13"}, Reason: pkg.MissingDistro},
+		{Package: pkg.Package{Name: "tool", Type: syftPkg.BinaryPkg}, Reason: pkg.MissingVersion},
+	}
+
+	assert.Equal(t, []UnsupportedPackage{
+		{
+			Name:        "tool",
+			Type:        string(syftPkg.BinaryPkg),
+			Reason:      string(pkg.MissingVersion),
+			Description: "the package has no version",
+		},
+		{
+			Name:        "zlib",
+			Version:     "1.2.13",
+			Type:        string(syftPkg.DebPkg),
+			PURL:        "pkg:deb/zlib@1.2.13",
+			Reason:      string(pkg.MissingDistro),
+			Description: "the distro of the OS package is unknown",
+		},
+	}, NewUnsupportedPackages(unsupported))
+	assert.Empty(t, NewUnsupportedPackages(nil))
+}
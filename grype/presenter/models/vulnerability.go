@@ -13,6 +13,9 @@ type Vulnerability struct {
 type Fix struct {
 	Versions []string `json:"versions"`
 	State    string   `json:"state"`
+	// UpgradeRequired is true when the fixes are all in other release streams than the installed version (e.g. of a
+	// newer distro release).
+	UpgradeRequired bool `json:"upgradeRequired,omitempty"`
 }
 
 type Advisory struct {
@@ -44,8 +47,9 @@ func NewVulnerability(vuln vulnerability.Vulnerability, metadata *vulnerability.
 	return Vulnerability{
 		VulnerabilityMetadata: NewVulnerabilityMetadata(vuln.ID, vuln.Namespace, metadata),
 		Fix: Fix{
-			Versions: fixedInVersions,
-			State:    string(vuln.Fix.State),
+			Versions:        fixedInVersions,
+			State:           string(vuln.Fix.State),
+			UpgradeRequired: vuln.Fix.UpgradeRequired,
 		},
 		Advisories: advisories,
 	}
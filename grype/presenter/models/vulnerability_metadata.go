@@ -10,6 +10,11 @@ type VulnerabilityMetadata struct {
 	URLs        []string `json:"urls"`
 	Description string   `json:"description,omitempty"`
 	Cvss        []Cvss   `json:"cvss"`
+
+	// when the severity was overridden (see the severity-overrides option), the severity from the vulnerability data and why it was overridden
+	OriginalSeverity       string   `json:"originalSeverity,omitempty"`
+	SeverityOverrideReason string   `json:"severityOverrideReason,omitempty"`
+	AdjustedCvssScore      *float64 `json:"adjustedCvssScore,omitempty"` // the CVSS score adjusted for temporal and environmental metrics
 }
 
 func NewVulnerabilityMetadata(id, namespace string, metadata *vulnerability.Metadata) VulnerabilityMetadata {
@@ -33,6 +38,10 @@ func NewVulnerabilityMetadata(id, namespace string, metadata *vulnerability.Meta
 		URLs:        urls,
 		Description: metadata.Description,
 		Cvss:        NewCVSS(metadata),
+
+		OriginalSeverity:       metadata.OriginalSeverity,
+		SeverityOverrideReason: metadata.SeverityOverrideReason,
+		AdjustedCvssScore:      metadata.AdjustedScore,
 	}
 }
 
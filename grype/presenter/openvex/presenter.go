@@ -0,0 +1,39 @@
+package openvex
+
+import (
+	"io"
+	"strings"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vex/openvex"
+)
+
+// Presenter writes the triage decisions made with ignore rules as an OpenVEX document
+type Presenter struct {
+	id             clio.Identification
+	ignoredMatches []match.IgnoredMatch
+	context        pkg.Context
+}
+
+// NewPresenter creates a new OpenVEX presenter
+func NewPresenter(pb models.PresenterConfig) *Presenter {
+	return &Presenter{
+		id:             pb.ID,
+		ignoredMatches: pb.IgnoredMatches,
+		context:        pb.Context,
+	}
+}
+
+// Present creates an OpenVEX document with a statement for each match ignored by an ignore rule
+func (pres *Presenter) Present(output io.Writer) error {
+	doc, err := openvex.Generate(&pres.context, pres.ignoredMatches, openvex.GenerateOptions{
+		Tooling: strings.TrimSpace(pres.id.Name + " " + pres.id.Version),
+	})
+	if err != nil {
+		return err
+	}
+	return doc.ToJSON(output)
+}
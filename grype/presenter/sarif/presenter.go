@@ -28,16 +28,20 @@ type Presenter struct {
 	packages         []pkg.Package
 	src              *source.Description
 	metadataProvider vulnerability.MetadataProvider
+	profile          Profile
+	manifest         *models.ScanManifest
 }
 
-// NewPresenter is a *Presenter constructor
-func NewPresenter(pb models.PresenterConfig) *Presenter {
+// NewPresenter is a *Presenter constructor, writing the report of the given profile
+func NewPresenter(pb models.PresenterConfig, profile Profile) *Presenter {
 	return &Presenter{
 		id:               pb.ID,
 		results:          pb.Matches,
 		packages:         pb.Packages,
 		metadataProvider: pb.MetadataProvider,
 		src:              pb.Context.Source,
+		profile:          profile,
+		manifest:         pb.Manifest,
 	}
 }
 
@@ -64,7 +68,7 @@ func (pres *Presenter) toSarifReport() (*sarif.Report, error) {
 		v = "0.0.0-dev"
 	}
 
-	doc.AddRun(&sarif.Run{
+	run := &sarif.Run{
 		Tool: sarif.Tool{
 			Driver: &sarif.ToolComponent{
 				Name:           pres.id.Name,
@@ -74,7 +78,11 @@ func (pres *Presenter) toSarifReport() (*sarif.Report, error) {
 			},
 		},
 		Results: pres.sarifResults(),
-	})
+	}
+	if pres.manifest != nil {
+		run.Properties = sarif.Properties{"scanManifest": pres.manifest}
+	}
+	doc.AddRun(run)
 
 	return doc, nil
 }
@@ -105,10 +113,15 @@ func (pres *Presenter) sarifRules() (out []*sarif.ReportingDescriptor) {
 				}
 			}
 
+			help := pres.helpText(m, link)
+			if pres.profile == AzureProfile {
+				help = pres.vulnerabilityHelpText(m, link)
+			}
+
 			out = append(out, &sarif.ReportingDescriptor{
 				ID:      ruleID,
 				Name:    sp(ruleName(m)),
-				HelpURI: sp("https://github.com/anchore/grype"),
+				HelpURI: sp(pres.helpURI(meta)),
 				// Title of the SARIF report
 				ShortDescription: &sarif.MultiformatMessageString{
 					Text: sp(pres.shortDescription(m)),
@@ -117,12 +130,8 @@ func (pres *Presenter) sarifRules() (out []*sarif.ReportingDescriptor) {
 				FullDescription: &sarif.MultiformatMessageString{
 					Text: sp(pres.subtitle(m)),
 				},
-				Help: pres.helpText(m, link),
-				Properties: sarif.Properties{
-					// For GitHub reportingDescriptor object:
-					// https://docs.github.com/en/code-security/code-scanning/integrating-with-code-scanning/sarif-support-for-code-scanning#reportingdescriptor-object
-					"security-severity": pres.securitySeverityValue(m),
-				},
+				Help:       help,
+				Properties: pres.ruleProperties(m),
 			})
 		}
 	}
@@ -133,6 +142,9 @@ func (pres *Presenter) sarifRules() (out []*sarif.ReportingDescriptor) {
 func (pres *Presenter) ruleID(m match.Match) string {
 	// TODO if we support configuration, we may want to allow addition of another qualifier such that if multiple
 	// vuln scans are run on multiple containers we can identify unique rules for each
+	if pres.profile == AzureProfile {
+		return m.Vulnerability.ID
+	}
 	return fmt.Sprintf("%s-%s", m.Vulnerability.ID, m.Package.Name)
 }
 
@@ -246,9 +258,7 @@ func (pres *Presenter) locations(m match.Match) []*sarif.Location {
 	return []*sarif.Location{
 		{
 			PhysicalLocation: &sarif.PhysicalLocation{
-				ArtifactLocation: &sarif.ArtifactLocation{
-					URI: sp(physicalLocation),
-				},
+				ArtifactLocation: pres.artifactLocation(physicalLocation),
 				// TODO When grype starts reporting line numbers this will need to get updated
 				Region: &sarif.Region{
 					StartLine:   ip(1),
@@ -330,8 +340,15 @@ func (pres *Presenter) cvssScore(v vulnerability.Vulnerability) float64 {
 func (pres *Presenter) securitySeverityValue(m match.Match) string {
 	meta := pres.metadata(m)
 	if meta != nil {
-		// this corresponds directly to the CVSS score, so we return this if we have it
+		// this corresponds directly to the CVSS score, so we return this if we have it (unless the severity was
+		// overridden, in which case the adjusted score or the overridden severity takes its place)
 		score := pres.cvssScore(m.Vulnerability)
+		if m.SeverityOverride != nil {
+			score = -1
+			if m.SeverityOverride.Score != nil {
+				score = *m.SeverityOverride.Score
+			}
+		}
 		if score > 0 {
 			return fmt.Sprintf("%.1f", score)
 		}
@@ -353,7 +370,7 @@ func (pres *Presenter) securitySeverityValue(m match.Match) string {
 // metadata returns the matching *vulnerability.Metadata from the provider or nil if not found / error
 func (pres *Presenter) metadata(m match.Match) *vulnerability.Metadata {
 	meta, _ := pres.metadataProvider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace)
-	return meta
+	return m.WithSeverityOverride(meta)
 }
 
 // subtitle generates a subtitle for the given match
@@ -389,8 +406,11 @@ func (pres *Presenter) sarifResults() []*sarif.Result {
 	out := make([]*sarif.Result, 0) // make sure we have at least an empty array
 	for _, m := range pres.results.Sorted() {
 		out = append(out, &sarif.Result{
-			RuleID:  sp(pres.ruleID(m)),
-			Message: pres.resultMessage(m),
+			RuleID: sp(pres.ruleID(m)),
+			Level:  pres.level(m),
+			// the match ID is the same for the same finding across scans, which is what the correlation GUID is for
+			CorrelationGuid: sp(m.ID()),
+			Message:         pres.resultMessage(m),
 			// According to the SARIF spec, it may be correct to use AnalysisTarget.URI to indicate a logical
 			// file such as a "Dockerfile" but GitHub does not work well with this
 			// GitHub requires partialFingerprints to upload to the API; these are automatically filled in
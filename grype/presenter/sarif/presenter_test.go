@@ -55,7 +55,7 @@ func TestSarifPresenter(t *testing.T) {
 				MetadataProvider: metadataProvider,
 			}
 
-			pres := NewPresenter(pb)
+			pres := NewPresenter(pb, GenericProfile)
 			err := pres.Present(&buffer)
 			if err != nil {
 				t.Fatal(err)
@@ -107,7 +107,7 @@ func Test_SarifIsValid(t *testing.T) {
 				MetadataProvider: metadataProvider,
 			}
 
-			pres := NewPresenter(pb)
+			pres := NewPresenter(pb, GenericProfile)
 			err := pres.Present(&buffer)
 			require.NoError(t, err)
 
@@ -260,7 +260,7 @@ func createDirPresenter(t *testing.T) *Presenter {
 		},
 	}
 
-	pres := NewPresenter(pb)
+	pres := NewPresenter(pb, GenericProfile)
 
 	return pres
 }
@@ -303,7 +303,7 @@ func TestToSarifReport(t *testing.T) {
 				Context:          context,
 			}
 
-			pres := NewPresenter(pb)
+			pres := NewPresenter(pb, GenericProfile)
 
 			report, err := pres.toSarifReport()
 			assert.NoError(t, err)
@@ -345,6 +345,68 @@ func TestToSarifReport(t *testing.T) {
 
 }
 
+func TestToSarifReport_profiles(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.DirectorySource)
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		Context:          context,
+	}
+
+	t.Run("azure", func(t *testing.T) {
+		report, err := NewPresenter(pb, AzureProfile).toSarifReport()
+		require.NoError(t, err)
+		run := report.Runs[0]
+
+		require.Len(t, run.Tool.Driver.Rules, 2)
+		rule := run.Tool.Driver.Rules[0]
+		assert.Equal(t, "CVE-1999-0001", rule.ID)
+		assert.NotContains(t, *rule.Help.Text, "Package:")
+		assert.NotEmpty(t, *rule.HelpURI)
+
+		require.Len(t, run.Results, 2)
+		result := run.Results[0]
+		assert.Equal(t, "CVE-1999-0001", *result.RuleID)
+		require.NotNil(t, result.Level)
+		assert.Equal(t, "note", *result.Level)
+		assert.Equal(t, "some/path/somefile-1.txt", *result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Nil(t, result.Locations[0].PhysicalLocation.ArtifactLocation.URIBaseId)
+	})
+
+	t.Run("ghas", func(t *testing.T) {
+		report, err := NewPresenter(pb, GHASProfile).toSarifReport()
+		require.NoError(t, err)
+		run := report.Runs[0]
+
+		require.Len(t, run.Tool.Driver.Rules, 2)
+		rule := run.Tool.Driver.Rules[0]
+		assert.Equal(t, "CVE-1999-0001-package-1", rule.ID)
+		assert.Equal(t, []string{"security", "vulnerability", "rpm"}, rule.Properties["tags"])
+		assert.Equal(t, "very-high", rule.Properties["precision"])
+
+		result := run.Results[0]
+		assert.Nil(t, result.Level)
+		location := result.Locations[0].PhysicalLocation.ArtifactLocation
+		assert.Equal(t, "some/path/somefile-1.txt", *location.URI)
+		require.NotNil(t, location.URIBaseId)
+		assert.Equal(t, "%SRCROOT%", *location.URIBaseId)
+	})
+}
+
+func TestParseProfile(t *testing.T) {
+	profile, ok := ParseProfile("Azure")
+	assert.True(t, ok)
+	assert.Equal(t, AzureProfile, profile)
+
+	profile, ok = ParseProfile("ghas")
+	assert.True(t, ok)
+	assert.Equal(t, GHASProfile, profile)
+
+	_, ok = ParseProfile("report.sarif")
+	assert.False(t, ok)
+}
+
 type NilMetadataProvider struct{}
 
 func (m *NilMetadataProvider) GetMetadata(_, _ string) (*vulnerability.Metadata, error) {
@@ -509,3 +571,22 @@ func Test_imageShortPathName(t *testing.T) {
 		})
 	}
 }
+
+func TestToSarifReport_manifest(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		Context:          context,
+	}
+
+	report, err := NewPresenter(pb, GenericProfile).toSarifReport()
+	require.NoError(t, err)
+	assert.Nil(t, report.Runs[0].Properties)
+
+	pb.Manifest = &models.ScanManifest{Tool: "grype", ConfigHash: "sha256:def"}
+	report, err = NewPresenter(pb, GenericProfile).toSarifReport()
+	require.NoError(t, err)
+	assert.Equal(t, pb.Manifest, report.Runs[0].Properties["scanManifest"])
+}
@@ -0,0 +1,120 @@
+package sarif
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/owenrumney/go-sarif/sarif"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// Profile adjusts the SARIF report to the quirks of the platform consuming it, since each platform renders the same
+// report differently.
+type Profile string
+
+const (
+	// GenericProfile is the default report, suited to the GitHub upload action and to most SARIF viewers.
+	GenericProfile Profile = ""
+	// AzureProfile suits the SARIF viewers of Azure DevOps (e.g. the Scans tab of a pipeline), which list the findings
+	// by rule rather than by result: each vulnerability is a single rule (the packages being told apart by the results),
+	// the results carry an explicit level since the security-severity property is not read, and the help links to the
+	// advisory of the vulnerability.
+	AzureProfile Profile = "azure"
+	// GHASProfile suits GitHub Advanced Security code scanning as fed by CodeQL: the rules are tagged as security rules
+	// with a precision (so the alerts are classified as security alerts), the help links to the advisory of the
+	// vulnerability, and the locations are relative to the root of the repository (%SRCROOT%).
+	GHASProfile Profile = "ghas"
+)
+
+// Profiles are the profiles that can be selected (e.g. "sarif=azure").
+var Profiles = []Profile{AzureProfile, GHASProfile}
+
+// ParseProfile returns the profile of the given name, or false if there is no such profile.
+func ParseProfile(name string) (Profile, bool) {
+	for _, p := range Profiles {
+		if strings.EqualFold(name, string(p)) {
+			return p, true
+		}
+	}
+	return GenericProfile, false
+}
+
+// srcRootBaseID is the base of the URIs relative to the root of the analyzed sources, as defined by the SARIF spec.
+const srcRootBaseID = "%SRCROOT%"
+
+// helpURI returns the link of the help of a rule: the advisory of the vulnerability for the profiles linking to it.
+func (pres *Presenter) helpURI(meta *vulnerability.Metadata) string {
+	if pres.profile != GenericProfile && meta != nil {
+		switch {
+		case meta.DataSource != "":
+			return meta.DataSource
+		case len(meta.URLs) > 0:
+			return meta.URLs[0]
+		}
+	}
+	return "https://github.com/anchore/grype"
+}
+
+// ruleProperties returns the properties of the rule of the match.
+func (pres *Presenter) ruleProperties(m match.Match) sarif.Properties {
+	properties := sarif.Properties{
+		// For GitHub reportingDescriptor object:
+		// https://docs.github.com/en/code-security/code-scanning/integrating-with-code-scanning/sarif-support-for-code-scanning#reportingdescriptor-object
+		"security-severity": pres.securitySeverityValue(m),
+	}
+	if pres.profile == GHASProfile {
+		properties["tags"] = []string{"security", "vulnerability", string(m.Package.Type)}
+		properties["precision"] = "very-high"
+	}
+	return properties
+}
+
+// vulnerabilityHelpText is the help text of a rule covering every package affected by the vulnerability, which then
+// cannot describe any single package.
+func (pres *Presenter) vulnerabilityHelpText(m match.Match, link string) *sarif.MultiformatMessageString {
+	text := fmt.Sprintf("Vulnerability %s\nSeverity: %s\nData Namespace: %s\nLink: %s",
+		m.Vulnerability.ID, pres.severityText(m), m.Vulnerability.Namespace, link,
+	)
+	markdown := fmt.Sprintf(
+		"**Vulnerability %s**\n"+
+			"| Severity | Data Namespace | Link |\n"+
+			"| --- | --- | --- |\n"+
+			"| %s  | %s  | %s  |\n",
+		m.Vulnerability.ID, pres.severityText(m), m.Vulnerability.Namespace, link,
+	)
+	return &sarif.MultiformatMessageString{
+		Text:     &text,
+		Markdown: &markdown,
+	}
+}
+
+// level returns the level of the result of the match for the profiles requiring one (the SARIF default being
+// "warning"), or nil.
+func (pres *Presenter) level(m match.Match) *string {
+	if pres.profile != AzureProfile {
+		return nil
+	}
+	switch pres.severityText(m) {
+	case "critical", "high":
+		return sp("error")
+	case "medium":
+		return sp("warning")
+	default:
+		return sp("note")
+	}
+}
+
+// artifactLocation returns the location of the artifact at the given path, relative to the root of the sources for
+// the profiles requiring relative URIs.
+func (pres *Presenter) artifactLocation(path string) *sarif.ArtifactLocation {
+	if pres.profile == GenericProfile {
+		return &sarif.ArtifactLocation{URI: sp(path)}
+	}
+	location := &sarif.ArtifactLocation{URI: sp(strings.TrimPrefix(strings.TrimPrefix(path, "./"), "/"))}
+	if pres.profile == GHASProfile {
+		location.URIBaseId = sp(srcRootBaseID)
+	}
+	return location
+}
@@ -4,15 +4,22 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/olekukonko/tablewriter"
 
+	"github.com/anchore/grype/grype/baseimage"
 	grypeDb "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/license"
+	"github.com/anchore/grype/grype/malware"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/presenter/theme"
+	"github.com/anchore/grype/grype/priority"
+	"github.com/anchore/grype/grype/supplychain"
 	"github.com/anchore/grype/grype/vulnerability"
 )
 
@@ -29,6 +36,19 @@ type Presenter struct {
 	metadataProvider vulnerability.MetadataProvider
 	showSuppressed   bool
 	withColor        bool
+	theme            theme.Theme
+	top              int
+	rankBy           priority.Criterion
+	enrichment       *priority.Enrichment
+	platforms        []models.PlatformResult
+	unsupported      []pkg.UnsupportedPackage
+	malware          []malware.Finding
+	supplyChain      []supplychain.Warning
+	packageStatuses  map[pkg.ID][]supplychain.PackageStatus
+	licenses         []license.Violation
+	baseImage        *baseimage.Advice
+	unscanned        []pkg.Package
+	manifest         *models.ScanManifest
 }
 
 // NewPresenter is a *Presenter constructor
@@ -40,26 +60,115 @@ func NewPresenter(pb models.PresenterConfig, showSuppressed bool) *Presenter {
 		metadataProvider: pb.MetadataProvider,
 		showSuppressed:   showSuppressed,
 		withColor:        supportsColor(),
+		platforms:        pb.Platforms,
+		unsupported:      pb.UnsupportedPackages,
+		malware:          pb.Malware,
+		supplyChain:      pb.SupplyChain,
+		packageStatuses:  pb.PackageStatuses,
+		licenses:         pb.LicenseViolations,
+		baseImage:        pb.BaseImage,
+		unscanned:        pb.UnscannedPackages,
+		enrichment:       pb.Enrichment,
+		manifest:         pb.Manifest,
 	}
 }
 
+// WithTheme sets the theme showing the severities.
+func (pres *Presenter) WithTheme(t theme.Theme) *Presenter {
+	pres.theme = t
+	return pres
+}
+
+// WithTop shows only the top matches ranked by the criterion, a prioritized shortlist in place of every match (all
+// the matches are shown when top is 0).
+func (pres *Presenter) WithTop(top int, by priority.Criterion) *Presenter {
+	pres.top = top
+	pres.rankBy = by
+	return pres
+}
+
 // Present creates a JSON-based reporting
 func (pres *Presenter) Present(output io.Writer) error {
+	if len(pres.unscanned) > 0 {
+		if _, err := fmt.Fprintf(output, "Incomplete results: the scan ran out of time, %d package(s) were not scanned\n", len(pres.unscanned)); err != nil {
+			return err
+		}
+	}
+	if err := pres.presentResults(output); err != nil {
+		return err
+	}
+	if err := pres.presentMalware(output); err != nil {
+		return err
+	}
+	if err := pres.presentSupplyChain(output); err != nil {
+		return err
+	}
+	if err := pres.presentLicenses(output); err != nil {
+		return err
+	}
+	if err := pres.presentBaseImage(output); err != nil {
+		return err
+	}
+	if err := pres.presentUnsupported(output); err != nil {
+		return err
+	}
+	if err := pres.presentUnscanned(output); err != nil {
+		return err
+	}
+	return pres.presentManifest(output)
+}
+
+// presentManifest ends the report with the conditions of the scan, as name=value pairs.
+func (pres *Presenter) presentManifest(output io.Writer) error {
+	if pres.manifest == nil {
+		return nil
+	}
+	_, err := fmt.Fprintf(output, "\nScan manifest: %s\n", pres.manifest)
+	return err
+}
+
+func (pres *Presenter) presentResults(output io.Writer) error {
+	if len(pres.platforms) == 0 {
+		return pres.present(output, pres.results, pres.ignoredMatches)
+	}
+
+	// one section per platform, as the vulnerable packages often differ between platforms
+	for i, p := range pres.platforms {
+		prefix := ""
+		if i > 0 {
+			prefix = "\n"
+		}
+		if _, err := fmt.Fprintf(output, "%sPlatform: %s\n", prefix, p.Platform); err != nil {
+			return err
+		}
+		if err := pres.present(output, p.Matches, p.IgnoredMatches); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pres *Presenter) present(output io.Writer, results match.Matches, ignoredMatches []match.IgnoredMatch) error {
+	if pres.top > 0 {
+		return pres.presentTop(output, results)
+	}
+
 	rows := make([][]string, 0)
 
 	columns := []string{"Name", "Installed", "Fixed-In", "Type", "Vulnerability", "Severity"}
 	// Generate rows for matching vulnerabilities
-	for m := range pres.results.Enumerate() {
+	for m := range results.Enumerate() {
 		row, err := createRow(m, pres.metadataProvider, "")
 		if err != nil {
 			return err
 		}
+		row[1] += statusSuffix(pres.packageStatuses[m.Package.ID])
 		rows = append(rows, row)
 	}
 
 	// Generate rows for suppressed vulnerabilities
 	if pres.showSuppressed {
-		for _, m := range pres.ignoredMatches {
+		for _, m := range ignoredMatches {
 			msg := appendSuppressed
 			if m.AppliedIgnoreRules != nil {
 				for i := range m.AppliedIgnoreRules {
@@ -84,6 +193,215 @@ func (pres *Presenter) Present(output io.Writer) error {
 
 	rows = sortRows(removeDuplicateRows(rows))
 
+	table := newTable(output, columns)
+
+	withColor := pres.withColor && pres.theme.HasColors()
+	for _, row := range rows {
+		severity := row[len(row)-1]
+		row[len(row)-1] = pres.theme.Label(severity)
+		if withColor {
+			table.Rich(row, []tablewriter.Colors{{}, {}, {}, {}, {}, tablewriter.Colors(pres.theme.Style(severity).SGR())})
+		} else {
+			table.Append(row)
+		}
+	}
+
+	table.Render()
+
+	return nil
+}
+
+// presentTop lists the top matches by the ranking criterion, with the data they were ranked by.
+func (pres *Presenter) presentTop(output io.Writer, results match.Matches) error {
+	matches := results.Sorted()
+	if len(matches) == 0 {
+		_, err := io.WriteString(output, "No vulnerabilities found\n")
+		return err
+	}
+
+	ranked := priority.Rank(matches, pres.metadataProvider, pres.enrichment, pres.rankBy, pres.top)
+
+	table := newTable(output, []string{"#", "Name", "Installed", "Fixed-In", "Vulnerability", "Severity", "CVSS", "EPSS", "KEV"})
+	withColor := pres.withColor && pres.theme.HasColors()
+	for i, r := range ranked {
+		row, err := createRow(r.Match, pres.metadataProvider, "")
+		if err != nil {
+			return err
+		}
+		severity := row[5]
+		row = []string{fmt.Sprint(i + 1), row[0], row[1] + statusSuffix(pres.packageStatuses[r.Match.Package.ID]), row[2], row[4], pres.theme.Label(severity), cvssCell(r), epssCell(r), kevCell(r)}
+		if withColor {
+			table.Rich(row, []tablewriter.Colors{{}, {}, {}, {}, {}, tablewriter.Colors(pres.theme.Style(severity).SGR())})
+		} else {
+			table.Append(row)
+		}
+	}
+	table.Render()
+
+	_, err := fmt.Fprintf(output, "Top %d of %d vulnerabilities, ranked by %s\n", len(ranked), len(matches), pres.rankBy)
+	return err
+}
+
+func cvssCell(r priority.Ranked) string {
+	if r.CVSS < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", r.CVSS)
+}
+
+func epssCell(r priority.Ranked) string {
+	if r.EPSS == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f%%", r.EPSS.Score*100)
+}
+
+func kevCell(r priority.Ranked) string {
+	switch {
+	case r.KEV == nil:
+		return ""
+	case r.KEV.KnownRansomware:
+		return "yes (ransomware)"
+	default:
+		return "yes"
+	}
+}
+
+// presentMalware lists the packages found to be known-malicious packages, apart from the vulnerabilities.
+func (pres *Presenter) presentMalware(output io.Writer) error {
+	if len(pres.malware) == 0 {
+		return nil
+	}
+
+	findings := models.NewMalwareFindings(pres.malware)
+	if _, err := fmt.Fprintf(output, "\nMalicious packages (%d):\n", len(findings)); err != nil {
+		return err
+	}
+
+	table := newTable(output, []string{"Name", "Installed", "Type", "ID", "Matched By"})
+	for _, f := range findings {
+		table.Append([]string{f.Name, f.Version, f.Type, f.ID, f.MatchedBy})
+	}
+	table.Render()
+
+	return nil
+}
+
+// presentSupplyChain lists the signs of supply-chain risk about the packages, such as likely typosquats.
+func (pres *Presenter) presentSupplyChain(output io.Writer) error {
+	if len(pres.supplyChain) == 0 {
+		return nil
+	}
+
+	warnings := models.NewSupplyChainWarnings(pres.supplyChain)
+	if _, err := fmt.Fprintf(output, "\nSupply chain warnings (%d):\n", len(warnings)); err != nil {
+		return err
+	}
+
+	table := newTable(output, []string{"Name", "Installed", "Type", "Kind", "Warning"})
+	for _, w := range warnings {
+		table.Append([]string{w.Name, w.Version, w.Type, w.Kind, w.Message})
+	}
+	table.Render()
+
+	return nil
+}
+
+// presentLicenses lists the package licenses violating the license policy.
+func (pres *Presenter) presentLicenses(output io.Writer) error {
+	if len(pres.licenses) == 0 {
+		return nil
+	}
+
+	violations := models.NewLicenseViolations(pres.licenses)
+	if _, err := fmt.Fprintf(output, "\nLicense policy violations (%d):\n", len(violations)); err != nil {
+		return err
+	}
+
+	table := newTable(output, []string{"Name", "Installed", "Type", "License", "Violation"})
+	for _, v := range violations {
+		var reasons []string
+		for _, r := range v.Violations {
+			reasons = append(reasons, fmt.Sprintf("%s (%s)", r.License, r.Reason))
+		}
+		table.Append([]string{v.Name, v.Version, v.Type, v.License, strings.Join(reasons, ", ")})
+	}
+	table.Render()
+
+	return nil
+}
+
+// presentBaseImage tells the base image of the scanned image and the findings its newer tags would remediate.
+func (pres *Presenter) presentBaseImage(output io.Writer) error {
+	advice := models.NewBaseImageAdvice(pres.baseImage)
+	if advice == nil {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(output, "\nBase image: %s:%s (detected by %s), %d finding(s) in its packages\n", advice.Image, advice.Tag, advice.DetectedBy, advice.Findings); err != nil {
+		return err
+	}
+	if len(advice.Upgrades) == 0 {
+		_, err := io.WriteString(output, "No newer tag of the base image is known\n")
+		return err
+	}
+
+	table := newTable(output, []string{"Newer Tag", "Released", "Remediates"})
+	for _, u := range advice.Upgrades {
+		remediates := strconv.Itoa(u.Remediates)
+		if u.Tag == advice.Recommended {
+			remediates += " (recommended)"
+		}
+		table.Append([]string{u.Tag, u.Released, remediates})
+	}
+	table.Render()
+
+	return nil
+}
+
+// presentUnsupported lists the packages that could not be matched against any vulnerability data, since finding no
+// vulnerabilities for them does not mean they have none.
+func (pres *Presenter) presentUnsupported(output io.Writer) error {
+	if len(pres.unsupported) == 0 {
+		return nil
+	}
+
+	unsupported := models.NewUnsupportedPackages(pres.unsupported)
+	if _, err := fmt.Fprintf(output, "\nPackages not matched against any vulnerability data (%d):\n", len(unsupported)); err != nil {
+		return err
+	}
+
+	table := newTable(output, []string{"Name", "Installed", "Type", "Reason"})
+	for _, u := range unsupported {
+		table.Append([]string{u.Name, u.Version, u.Type, u.Reason})
+	}
+	table.Render()
+
+	return nil
+}
+
+// presentUnscanned lists the packages left unscanned because the scan ran out of time, whose vulnerabilities are
+// missing from the results.
+func (pres *Presenter) presentUnscanned(output io.Writer) error {
+	incomplete := models.NewIncompleteScan(pres.unscanned)
+	if incomplete == nil {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(output, "\nPackages not scanned within the maximum scan time (%d):\n", len(incomplete.UnscannedPackages)); err != nil {
+		return err
+	}
+
+	table := newTable(output, []string{"Name", "Installed", "Type"})
+	for _, u := range incomplete.UnscannedPackages {
+		table.Append([]string{u.Name, u.Version, u.Type})
+	}
+	table.Render()
+
+	return nil
+}
+
+func newTable(output io.Writer, columns []string) *tablewriter.Table {
 	table := tablewriter.NewWriter(output)
 	table.SetHeader(columns)
 	table.SetAutoWrapText(false)
@@ -98,19 +416,7 @@ func (pres *Presenter) Present(output io.Writer) error {
 	table.SetRowSeparator("")
 	table.SetTablePadding("  ")
 	table.SetNoWhiteSpace(true)
-
-	if pres.withColor {
-		for _, row := range rows {
-			severityColor := getSeverityColor(row[len(row)-1])
-			table.Rich(row, []tablewriter.Colors{{}, {}, {}, {}, {}, severityColor})
-		}
-	} else {
-		table.AppendBulk(rows)
-	}
-
-	table.Render()
-
-	return nil
+	return table
 }
 
 func supportsColor() bool {
@@ -178,12 +484,16 @@ func createRow(m match.Match, metadataProvider vulnerability.MetadataProvider, s
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch vuln=%q metadata: %+v", m.Vulnerability.ID, err)
 	}
+	metadata = m.WithSeverityOverride(metadata)
 
 	if metadata != nil {
 		severity = metadata.Severity + severitySuffix
 	}
 
 	fixVersion := strings.Join(m.Vulnerability.Fix.Versions, ", ")
+	if m.Vulnerability.Fix.UpgradeRequired {
+		fixVersion += " (upgrade required)"
+	}
 	switch m.Vulnerability.Fix.State {
 	case grypeDb.WontFixState:
 		fixVersion = "(won't fix)"
@@ -194,22 +504,14 @@ func createRow(m match.Match, metadataProvider vulnerability.MetadataProvider, s
 	return []string{m.Package.Name, m.Package.Version, fixVersion, string(m.Package.Type), m.Vulnerability.ID, severity}, nil
 }
 
-func getSeverityColor(severity string) tablewriter.Colors {
-	severityFontType, severityColor := tablewriter.Normal, tablewriter.Normal
-
-	switch strings.ToLower(severity) {
-	case "critical":
-		severityFontType = tablewriter.Bold
-		severityColor = tablewriter.FgRedColor
-	case "high":
-		severityColor = tablewriter.FgRedColor
-	case "medium":
-		severityColor = tablewriter.FgYellowColor
-	case "low":
-		severityColor = tablewriter.FgGreenColor
-	case "negligible":
-		severityColor = tablewriter.FgBlueColor
+// statusSuffix returns the statuses of a package to append to its version, e.g. " (deprecated, archived)".
+func statusSuffix(statuses []supplychain.PackageStatus) string {
+	if len(statuses) == 0 {
+		return ""
 	}
-
-	return tablewriter.Colors{severityFontType, severityColor}
+	var kinds []string
+	for _, s := range statuses {
+		kinds = append(kinds, string(s.Status))
+	}
+	return " (" + strings.Join(kinds, ", ") + ")"
 }
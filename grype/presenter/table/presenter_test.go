@@ -2,6 +2,7 @@ package table
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/gkampitakis/go-snaps/snaps"
@@ -10,10 +11,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/anchore/grype/grype/baseimage"
+	"github.com/anchore/grype/grype/license"
+	"github.com/anchore/grype/grype/malware"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/presenter/internal"
 	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/presenter/theme"
+	"github.com/anchore/grype/grype/priority"
+	"github.com/anchore/grype/grype/supplychain"
 	"github.com/anchore/grype/grype/vulnerability"
 	syftPkg "github.com/anchore/syft/syft/pkg"
 )
@@ -229,3 +236,280 @@ func TestDisplaysIgnoredMatches(t *testing.T) {
 	actual := buffer.String()
 	snaps.MatchSnapshot(t, actual)
 }
+
+func TestTablePresenter_platforms(t *testing.T) {
+	_, matches, packages, context, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		Platforms: []models.PlatformResult{
+			{Platform: "linux/amd64", Matches: matches, Packages: packages, Context: context},
+			{Platform: "linux/arm64", Matches: match.NewMatches(), Context: context},
+		},
+	}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(pb, false)
+	pres.withColor = false
+	require.NoError(t, pres.Present(&buffer))
+
+	actual := buffer.String()
+	amd64, arm64, found := strings.Cut(actual, "\nPlatform: linux/arm64\n")
+	require.True(t, found, actual)
+	assert.True(t, strings.HasPrefix(amd64, "Platform: linux/amd64\nNAME"), amd64)
+	assert.Contains(t, amd64, "CVE-1999-0001")
+	assert.Equal(t, "No vulnerabilities found\n", arm64)
+}
+
+func TestTablePresenter_unsupportedPackages(t *testing.T) {
+	_, matches, packages, _, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		UnsupportedPackages: []pkg.UnsupportedPackage{
+			{Package: pkg.Package{Name: "tool", Type: syftPkg.BinaryPkg}, Reason: pkg.MissingVersion},
+		},
+	}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(pb, false)
+	pres.withColor = false
+	require.NoError(t, pres.Present(&buffer))
+
+	results, unsupported, found := strings.Cut(buffer.String(), "\nPackages not matched against any vulnerability data (1):\n")
+	require.True(t, found, buffer.String())
+	assert.Contains(t, results, "CVE-1999-0001")
+	assert.Contains(t, unsupported, "REASON")
+	assert.Regexp(t, `tool\s+binary\s+missing-version`, unsupported)
+}
+
+func TestTablePresenter_unscannedPackages(t *testing.T) {
+	_, matches, packages, _, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:           matches,
+		Packages:          packages,
+		MetadataProvider:  metadataProvider,
+		UnscannedPackages: []pkg.Package{{Name: "openssl", Version: "3.0.11", Type: syftPkg.DebPkg}},
+	}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(pb, false)
+	pres.withColor = false
+	require.NoError(t, pres.Present(&buffer))
+
+	assert.True(t, strings.HasPrefix(buffer.String(), "Incomplete results: the scan ran out of time, 1 package(s) were not scanned\n"), buffer.String())
+	results, unscanned, found := strings.Cut(buffer.String(), "\nPackages not scanned within the maximum scan time (1):\n")
+	require.True(t, found, buffer.String())
+	assert.Contains(t, results, "CVE-1999-0001")
+	assert.Regexp(t, `openssl\s+3\.0\.11\s+deb`, unscanned)
+}
+
+func TestTablePresenter_malware(t *testing.T) {
+	_, matches, packages, _, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		Malware: []malware.Finding{
+			{Package: pkg.Package{Name: "evil-pkg", Version: "1.0.1", Type: syftPkg.NpmPkg}, ID: "MAL-2022-1001", MatchedBy: malware.VersionMatch},
+		},
+	}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(pb, false)
+	pres.withColor = false
+	require.NoError(t, pres.Present(&buffer))
+
+	results, found, ok := strings.Cut(buffer.String(), "\nMalicious packages (1):\n")
+	require.True(t, ok, buffer.String())
+	assert.Contains(t, results, "CVE-1999-0001")
+	assert.Contains(t, found, "MATCHED BY")
+	assert.Regexp(t, `evil-pkg\s+1\.0\.1\s+npm\s+MAL-2022-1001\s+version`, found)
+}
+
+func TestTablePresenter_supplyChain(t *testing.T) {
+	_, matches, packages, _, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		SupplyChain: []supplychain.Warning{
+			{
+				Package: pkg.Package{Name: "lodahs", Version: "1.0.0", Type: syftPkg.NpmPkg},
+				Kind:    supplychain.Typosquat,
+				Message: `the name is 1 edit(s) from the popular npm package "lodash"`,
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(pb, false)
+	pres.withColor = false
+	require.NoError(t, pres.Present(&buffer))
+
+	results, warnings, ok := strings.Cut(buffer.String(), "\nSupply chain warnings (1):\n")
+	require.True(t, ok, buffer.String())
+	assert.Contains(t, results, "CVE-1999-0001")
+	assert.Regexp(t, `lodahs\s+1\.0\.0\s+npm\s+typosquat\s+the name is 1 edit\(s\) from the popular npm package "lodash"`, warnings)
+}
+
+func TestTablePresenter_packageStatuses(t *testing.T) {
+	_, matches, packages, _, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	first := matches.Sorted()[0].Package
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		PackageStatuses: map[pkg.ID][]supplychain.PackageStatus{
+			first.ID: {{Name: first.Name, Status: supplychain.Deprecated}, {Name: first.Name, Status: supplychain.Archived}},
+		},
+	}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(pb, false)
+	pres.withColor = false
+	require.NoError(t, pres.Present(&buffer))
+
+	assert.Contains(t, buffer.String(), first.Version+" (deprecated, archived)")
+}
+
+func TestTablePresenter_licenseViolations(t *testing.T) {
+	_, matches, packages, _, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		LicenseViolations: []license.Violation{
+			{
+				Package:  pkg.Package{Name: "readline", Version: "8.2", Type: syftPkg.DebPkg},
+				License:  "GPL-3.0-only",
+				Licenses: []string{"GPL-3.0-only"},
+				Reasons:  []license.Reason{license.Denied},
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(pb, false)
+	pres.withColor = false
+	require.NoError(t, pres.Present(&buffer))
+
+	results, violations, ok := strings.Cut(buffer.String(), "\nLicense policy violations (1):\n")
+	require.True(t, ok, buffer.String())
+	assert.Contains(t, results, "CVE-1999-0001")
+	assert.Regexp(t, `readline\s+8\.2\s+deb\s+GPL-3\.0-only\s+GPL-3\.0-only \(denied\)`, violations)
+}
+
+func TestTablePresenter_baseImage(t *testing.T) {
+	_, matches, packages, _, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		BaseImage: &baseimage.Advice{
+			Base:       baseimage.Base{Image: "docker.io/library/alpine", Tag: "3.18.4"},
+			DetectedBy: baseimage.ByLayers,
+			Findings:   3,
+			Upgrades: []baseimage.Upgrade{
+				{Base: baseimage.Base{Tag: "3.18.5"}, Remediates: 1},
+				{Base: baseimage.Base{Tag: "3.18.6"}, Remediates: 2},
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(pb, false)
+	pres.withColor = false
+	require.NoError(t, pres.Present(&buffer))
+
+	_, advice, ok := strings.Cut(buffer.String(), "\nBase image: docker.io/library/alpine:3.18.4 (detected by layers), 3 finding(s) in its packages\n")
+	require.True(t, ok, buffer.String())
+	assert.Regexp(t, `3\.18\.5\s+1\s*\n`, advice)
+	assert.Regexp(t, `3\.18\.6\s+2 \(recommended\)`, advice)
+}
+
+func TestTablePresenter_themes(t *testing.T) {
+	_, matches, packages, _, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+	}
+
+	present := func(t *testing.T, name string) string {
+		th, err := theme.New(name, nil)
+		require.NoError(t, err)
+		var buffer bytes.Buffer
+		pres := NewPresenter(pb, false).WithTheme(th)
+		pres.withColor = true
+		require.NoError(t, pres.Present(&buffer))
+		return buffer.String()
+	}
+
+	t.Run("no-color", func(t *testing.T) {
+		got := present(t, theme.NoColorName)
+		assert.NotContains(t, got, "\x1b[")
+		assert.Contains(t, got, "Low")
+	})
+
+	t.Run("emoji", func(t *testing.T) {
+		got := present(t, theme.EmojiName)
+		assert.Contains(t, got, "🟢 Low")
+		assert.Contains(t, got, "\x1b[0;32m")
+	})
+
+	t.Run("high-contrast", func(t *testing.T) {
+		got := present(t, theme.HighContrastName)
+		assert.Contains(t, got, "\x1b[1;92m")
+	})
+}
+
+func TestTablePresenter_top(t *testing.T) {
+	_, matches, packages, _, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	enrichment, err := priority.ParseEPSS(strings.NewReader("cve,epss,percentile\nCVE-1999-0001,0.42,0.97\n"))
+	require.NoError(t, err)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		Enrichment:       enrichment,
+	}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(pb, false).WithTop(1, priority.ByEPSS)
+	pres.withColor = false
+	require.NoError(t, pres.Present(&buffer))
+
+	snaps.MatchSnapshot(t, buffer.String())
+}
+
+func TestTablePresenter_manifest(t *testing.T) {
+	_, matches, packages, _, metadataProvider, _, _ := internal.GenerateAnalysis(t, internal.ImageSource)
+
+	pb := models.PresenterConfig{
+		Matches:          matches,
+		Packages:         packages,
+		MetadataProvider: metadataProvider,
+		Manifest:         &models.ScanManifest{Tool: "grype", Version: "1.2.3", ConfigHash: "sha256:def", DurationMs: 1000},
+	}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(pb, false)
+	pres.withColor = false
+	require.NoError(t, pres.Present(&buffer))
+
+	assert.True(t, strings.HasSuffix(buffer.String(), "\nScan manifest: tool=grype version=1.2.3 configHash=sha256:def durationMs=1000\n"), buffer.String())
+}
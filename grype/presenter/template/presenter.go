@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sort"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/mitchellh/go-homedir"
@@ -28,7 +29,11 @@ type Presenter struct {
 	metadataProvider   vulnerability.MetadataProvider
 	appConfig          interface{}
 	dbStatus           interface{}
+	annotations        map[string]map[string]string
+	environment        map[string]string
+	manifest           *models.ScanManifest
 	pathToTemplateFile string
+	now                func() time.Time
 }
 
 // NewPresenter returns a new template.Presenter.
@@ -42,7 +47,11 @@ func NewPresenter(pb models.PresenterConfig, templateFile string) *Presenter {
 		context:            pb.Context,
 		appConfig:          pb.AppConfig,
 		dbStatus:           pb.DBStatus,
+		annotations:        pb.Annotations,
+		environment:        pb.Environment,
+		manifest:           pb.Manifest,
 		pathToTemplateFile: templateFile,
+		now:                pb.Now,
 	}
 }
 
@@ -64,11 +73,14 @@ func (pres *Presenter) Present(output io.Writer) error {
 		return fmt.Errorf("unable to parse template: %w", err)
 	}
 
-	document, err := models.NewDocument(pres.id, pres.packages, pres.context, pres.matches, pres.ignoredMatches, pres.metadataProvider,
+	document, err := models.NewDocumentAt(models.ReportTime(pres.now), pres.id, pres.packages, pres.context, pres.matches, pres.ignoredMatches, pres.metadataProvider,
 		pres.appConfig, pres.dbStatus)
 	if err != nil {
 		return err
 	}
+	models.AnnotateMatches(document.Matches, pres.annotations)
+	document.Environment = pres.environment
+	document.Manifest = pres.manifest
 
 	err = tmpl.Execute(output, document)
 	if err != nil {
@@ -94,7 +106,7 @@ var FuncMap = func() template.FuncMap {
 			return collection
 		}
 
-		sort.Sort(models.MatchSort(matches))
+		sort.Stable(models.MatchSort(matches))
 		return matches
 	}
 	return f
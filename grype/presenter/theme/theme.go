@@ -0,0 +1,259 @@
+/*
+Package theme defines how the severities of vulnerabilities are shown by the table output and the terminal UI: the
+colors of each severity, and the markers (e.g. emoji) shown before them.
+*/
+package theme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// DefaultName is the theme of the colors Grype has always used: bold red for critical, red for high, yellow for
+	// medium, green for low and blue for negligible.
+	DefaultName = "default"
+	// NoColorName shows the severities without colors (as when NO_COLOR is set, or the terminal has no colors).
+	NoColorName = "no-color"
+	// HighContrastName shows the severities in bold and bright colors, the critical ones on a red background.
+	HighContrastName = "high-contrast"
+	// EmojiName shows the severities in the default colors, marked with an emoji (e.g. 🔴 for critical).
+	EmojiName = "emoji"
+)
+
+// Names are the names of the themes that can be selected.
+var Names = []string{DefaultName, NoColorName, HighContrastName, EmojiName}
+
+// the 16 ANSI colors, by name
+var colors = map[string]int{
+	"black":          0,
+	"red":            1,
+	"green":          2,
+	"yellow":         3,
+	"blue":           4,
+	"magenta":        5,
+	"cyan":           6,
+	"white":          7,
+	"bright-black":   8,
+	"bright-red":     9,
+	"bright-green":   10,
+	"bright-yellow":  11,
+	"bright-blue":    12,
+	"bright-magenta": 13,
+	"bright-cyan":    14,
+	"bright-white":   15,
+}
+
+// Style is how a severity is shown: its foreground and background colors (by name, e.g. "bright-red"), and whether it
+// is bold.
+type Style struct {
+	Foreground string
+	Background string
+	Bold       bool
+}
+
+// ParseStyle parses a style of the form "[bold] [<color>] [on <color>]" (e.g. "bold bright-white on red"), the colors
+// being one of the 16 ANSI colors ("red", "bright-red"...). "none" is no style.
+func ParseStyle(value string) (Style, error) {
+	var s Style
+	words := strings.Fields(strings.ToLower(value))
+	for i := 0; i < len(words); i++ {
+		switch word := words[i]; {
+		case word == "none":
+		case word == "bold":
+			s.Bold = true
+		case word == "on" && i+1 < len(words):
+			i++
+			if _, ok := colors[words[i]]; !ok {
+				return Style{}, fmt.Errorf("unknown color %q (options: %s)", words[i], strings.Join(colorNames(), ", "))
+			}
+			s.Background = words[i]
+		default:
+			if _, ok := colors[word]; !ok {
+				return Style{}, fmt.Errorf("unknown color %q (options: %s)", word, strings.Join(colorNames(), ", "))
+			}
+			s.Foreground = word
+		}
+	}
+	return s, nil
+}
+
+// IsEmpty returns true if the style has no color and is not bold.
+func (s Style) IsEmpty() bool {
+	return s == Style{}
+}
+
+// SGR returns the ANSI SGR parameters of the style, as taken by tablewriter.Colors: the weight (1 for bold, 0 for
+// normal), the foreground color (e.g. 31 for red, 0 for the default color), then the background color, if any.
+func (s Style) SGR() []int {
+	params := []int{0, 0}
+	if s.Bold {
+		params[0] = 1
+	}
+	if c, ok := colors[s.Foreground]; ok {
+		params[1] = sgrColor(c, 30, 90)
+	}
+	if c, ok := colors[s.Background]; ok {
+		params = append(params, sgrColor(c, 40, 100))
+	}
+	return params
+}
+
+func sgrColor(c, base, brightBase int) int {
+	if c >= 8 {
+		return brightBase + c - 8
+	}
+	return base + c
+}
+
+// Lipgloss returns the style as a lipgloss style, for the terminal UI.
+func (s Style) Lipgloss() lipgloss.Style {
+	style := lipgloss.NewStyle().Bold(s.Bold)
+	if c, ok := colors[s.Foreground]; ok {
+		style = style.Foreground(lipgloss.Color(fmt.Sprint(c)))
+	}
+	if c, ok := colors[s.Background]; ok {
+		style = style.Background(lipgloss.Color(fmt.Sprint(c)))
+	}
+	return style
+}
+
+// Theme is the styles and markers of the severities. The zero value is the default theme.
+type Theme struct {
+	name    string
+	styles  map[string]Style
+	markers map[string]string
+}
+
+var themes = map[string]Theme{
+	DefaultName: {
+		name: DefaultName,
+		styles: map[string]Style{
+			"critical":   {Foreground: "red", Bold: true},
+			"high":       {Foreground: "red"},
+			"medium":     {Foreground: "yellow"},
+			"low":        {Foreground: "green"},
+			"negligible": {Foreground: "blue"},
+		},
+	},
+	NoColorName: {
+		name:   NoColorName,
+		styles: map[string]Style{},
+	},
+	HighContrastName: {
+		name: HighContrastName,
+		styles: map[string]Style{
+			"critical":   {Foreground: "bright-white", Background: "red", Bold: true},
+			"high":       {Foreground: "bright-red", Bold: true},
+			"medium":     {Foreground: "bright-yellow", Bold: true},
+			"low":        {Foreground: "bright-green", Bold: true},
+			"negligible": {Foreground: "bright-cyan", Bold: true},
+		},
+	},
+}
+
+var emojiMarkers = map[string]string{
+	"critical":   "🔴",
+	"high":       "🟠",
+	"medium":     "🟡",
+	"low":        "🟢",
+	"negligible": "⚪",
+	"unknown":    "❔",
+}
+
+// New returns the theme of the given name (the default theme when empty), with the styles of the palette (by
+// severity, e.g. "critical" is "bold bright-white on red") in place of the styles of the theme.
+func New(name string, palette map[string]string) (Theme, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultName
+	}
+
+	var t Theme
+	switch name {
+	case EmojiName:
+		t = themes[DefaultName]
+		t.markers = emojiMarkers
+	default:
+		var ok bool
+		if t, ok = themes[name]; !ok {
+			return Theme{}, fmt.Errorf("unknown theme %q (options: %s)", name, strings.Join(Names, ", "))
+		}
+	}
+	t.name = name
+
+	if len(palette) > 0 {
+		styles := make(map[string]Style, len(t.styles)+len(palette))
+		for severity, s := range t.styles {
+			styles[severity] = s
+		}
+		for severity, value := range palette {
+			s, err := ParseStyle(value)
+			if err != nil {
+				return Theme{}, fmt.Errorf("bad style of the %q severity: %w", severity, err)
+			}
+			styles[strings.ToLower(severity)] = s
+		}
+		t.styles = styles
+	}
+	return t, nil
+}
+
+// Name returns the name of the theme.
+func (t Theme) Name() string {
+	if t.name == "" {
+		return DefaultName
+	}
+	return t.name
+}
+
+// Style returns the style of the severity (e.g. "High"). The severities with a suffix (e.g. "High (suppressed)") are
+// not styled, so they stand apart.
+func (t Theme) Style(severity string) Style {
+	styles := t.styles
+	if styles == nil {
+		styles = themes[DefaultName].styles
+	}
+	return styles[strings.ToLower(strings.TrimSpace(severity))]
+}
+
+// HasColors returns true if the theme styles any severity.
+func (t Theme) HasColors() bool {
+	if t.styles == nil {
+		return true
+	}
+	for _, s := range t.styles {
+		if !s.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns the severity as shown by the theme: with its marker, if any (e.g. "🔴 Critical").
+func (t Theme) Label(severity string) string {
+	if marker := t.markers[severityKey(severity)]; marker != "" {
+		return marker + " " + severity
+	}
+	return severity
+}
+
+// severityKey returns the severity of a label, without the suffixes added to it (e.g. " (suppressed)").
+func severityKey(severity string) string {
+	key, _, _ := strings.Cut(strings.TrimSpace(severity), " ")
+	return strings.ToLower(key)
+}
+
+func colorNames() []string {
+	names := make([]string, 0, len(colors))
+	for name := range colors {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return colors[names[i]] < colors[names[j]]
+	})
+	return names
+}
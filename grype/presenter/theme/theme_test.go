@@ -0,0 +1,102 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Style
+		wantErr require.ErrorAssertionFunc
+	}{
+		{value: "red", want: Style{Foreground: "red"}},
+		{value: "bold bright-white on red", want: Style{Foreground: "bright-white", Background: "red", Bold: true}},
+		{value: "Bold Yellow", want: Style{Foreground: "yellow", Bold: true}},
+		{value: "none", want: Style{}},
+		{value: "", want: Style{}},
+		{value: "orange", wantErr: require.Error},
+		{value: "red on orange", wantErr: require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := ParseStyle(tt.value)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStyle_SGR(t *testing.T) {
+	assert.Equal(t, []int{0, 0}, Style{}.SGR())
+	assert.Equal(t, []int{1, 31}, Style{Foreground: "red", Bold: true}.SGR())
+	assert.Equal(t, []int{0, 92}, Style{Foreground: "bright-green"}.SGR())
+	assert.Equal(t, []int{1, 97, 41}, Style{Foreground: "bright-white", Background: "red", Bold: true}.SGR())
+}
+
+func TestNew(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		th, err := New("", nil)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultName, th.Name())
+		assert.Equal(t, Style{Foreground: "red", Bold: true}, th.Style("Critical"))
+		assert.Equal(t, "High", th.Label("High"))
+		assert.True(t, th.HasColors())
+	})
+
+	t.Run("zero value is the default theme", func(t *testing.T) {
+		var th Theme
+		assert.Equal(t, DefaultName, th.Name())
+		assert.Equal(t, Style{Foreground: "yellow"}, th.Style("Medium"))
+		assert.True(t, th.HasColors())
+	})
+
+	t.Run("no-color", func(t *testing.T) {
+		th, err := New("no-color", nil)
+		require.NoError(t, err)
+		assert.True(t, th.Style("Critical").IsEmpty())
+		assert.False(t, th.HasColors())
+	})
+
+	t.Run("emoji", func(t *testing.T) {
+		th, err := New("Emoji", nil)
+		require.NoError(t, err)
+		assert.Equal(t, EmojiName, th.Name())
+		assert.Equal(t, "🔴 Critical", th.Label("Critical"))
+		assert.Equal(t, "🟡 Medium (suppressed)", th.Label("Medium (suppressed)"))
+		assert.Equal(t, Style{Foreground: "green"}, th.Style("Low"))
+	})
+
+	t.Run("suffixed severities are not styled", func(t *testing.T) {
+		th, err := New("high-contrast", nil)
+		require.NoError(t, err)
+		assert.True(t, th.Style("High (suppressed)").IsEmpty())
+	})
+
+	t.Run("palette", func(t *testing.T) {
+		th, err := New("no-color", map[string]string{"Critical": "bold bright-white on red"})
+		require.NoError(t, err)
+		assert.Equal(t, Style{Foreground: "bright-white", Background: "red", Bold: true}, th.Style("critical"))
+		assert.True(t, th.Style("High").IsEmpty())
+		assert.True(t, th.HasColors())
+	})
+
+	t.Run("unknown theme", func(t *testing.T) {
+		_, err := New("neon", nil)
+		require.ErrorContains(t, err, `unknown theme "neon"`)
+	})
+
+	t.Run("bad palette", func(t *testing.T) {
+		_, err := New("", map[string]string{"high": "orange"})
+		require.ErrorContains(t, err, `bad style of the "high" severity`)
+	})
+}
@@ -0,0 +1,222 @@
+/*
+Package priority ranks the matches of a scan by how urgently they should be fixed, so that the reports can show the
+top things to fix rather than every finding. The matches are ranked by their CVSS score, by their EPSS score (the
+probability of exploitation within 30 days, from FIRST.org) or by their listing in the CISA catalog of Known Exploited
+Vulnerabilities (KEV), the EPSS and KEV data coming from files given by the user, or placed beside the vulnerability DB
+(the DB distribution does not provide them).
+*/
+package priority
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/anchore/grype/internal/dataset"
+)
+
+const (
+	// DBDirName is the name of the optional directory of enrichment data that may be placed within the directory of
+	// the vulnerability DB, holding the EPSS scores (EPSSFileName) and the KEV catalog (KEVFileName).
+	DBDirName = "enrichment"
+	// EPSSFileName is the name of the EPSS scores file (as published by FIRST.org) in the enrichment directory.
+	EPSSFileName = "epss_scores.csv.gz"
+	// KEVFileName is the name of the KEV catalog file (as published by CISA) in the enrichment directory.
+	KEVFileName = "known_exploited_vulnerabilities.json"
+)
+
+// EPSS is the EPSS score of a vulnerability: the probability of exploitation within 30 days, and its percentile
+// among all the scored vulnerabilities.
+type EPSS struct {
+	Score      float64
+	Percentile float64
+}
+
+// KEV is the listing of a vulnerability in the catalog of Known Exploited Vulnerabilities.
+type KEV struct {
+	DateAdded       string
+	DueDate         string
+	KnownRansomware bool
+}
+
+// Enrichment holds the EPSS scores and the KEV listings of vulnerabilities, keyed by CVE ID.
+type Enrichment struct {
+	epss map[string]EPSS
+	kev  map[string]KEV
+}
+
+func NewEnrichment() *Enrichment {
+	return &Enrichment{
+		epss: make(map[string]EPSS),
+		kev:  make(map[string]KEV),
+	}
+}
+
+// HasEPSS returns true if there are EPSS scores.
+func (e *Enrichment) HasEPSS() bool {
+	return e != nil && len(e.epss) > 0
+}
+
+// HasKEV returns true if there are KEV listings.
+func (e *Enrichment) HasKEV() bool {
+	return e != nil && len(e.kev) > 0
+}
+
+// EPSS returns the EPSS score of the CVE, if there is one.
+func (e *Enrichment) EPSS(cve string) (EPSS, bool) {
+	if e == nil {
+		return EPSS{}, false
+	}
+	s, ok := e.epss[strings.ToUpper(cve)]
+	return s, ok
+}
+
+// KEV returns the KEV listing of the CVE, if it is listed.
+func (e *Enrichment) KEV(cve string) (KEV, bool) {
+	if e == nil {
+		return KEV{}, false
+	}
+	k, ok := e.kev[strings.ToUpper(cve)]
+	return k, ok
+}
+
+// Merge adds the data of the other enrichment, which takes precedence over the data already in the enrichment.
+func (e *Enrichment) Merge(other *Enrichment) {
+	if other == nil {
+		return
+	}
+	for cve, s := range other.epss {
+		e.epss[cve] = s
+	}
+	for cve, k := range other.kev {
+		e.kev[cve] = k
+	}
+}
+
+// ParseEPSS reads EPSS scores in the CSV format published by FIRST.org: an optional "#model_version:..." comment line,
+// then the "cve,epss,percentile" header and a line per CVE.
+func ParseEPSS(r io.Reader) (*Enrichment, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse EPSS scores: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	cveColumn, ok1 := columns["cve"]
+	scoreColumn, ok2 := columns["epss"]
+	percentileColumn, ok3 := columns["percentile"]
+	if !ok1 || !ok2 || !ok3 {
+		return nil, fmt.Errorf("unable to parse EPSS scores: expected the columns cve, epss and percentile")
+	}
+
+	e := NewEnrichment()
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse EPSS scores: %w", err)
+		}
+		if len(record) <= max(cveColumn, scoreColumn, percentileColumn) {
+			return nil, fmt.Errorf("unable to parse EPSS scores: line %d: expected %d columns", line, len(header))
+		}
+		score, err := strconv.ParseFloat(strings.TrimSpace(record[scoreColumn]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse EPSS scores: line %d: bad score: %w", line, err)
+		}
+		percentile, err := strconv.ParseFloat(strings.TrimSpace(record[percentileColumn]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse EPSS scores: line %d: bad percentile: %w", line, err)
+		}
+		e.epss[strings.ToUpper(strings.TrimSpace(record[cveColumn]))] = EPSS{Score: score, Percentile: percentile}
+	}
+	return e, nil
+}
+
+// ParseKEV reads the catalog of Known Exploited Vulnerabilities in the JSON format published by CISA.
+func ParseKEV(r io.Reader) (*Enrichment, error) {
+	var catalog struct {
+		Vulnerabilities []struct {
+			CveID                      string `json:"cveID"`
+			DateAdded                  string `json:"dateAdded"`
+			DueDate                    string `json:"dueDate"`
+			KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.NewDecoder(r).Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("unable to parse KEV catalog: %w", err)
+	}
+	if catalog.Vulnerabilities == nil {
+		return nil, fmt.Errorf("unable to parse KEV catalog: no vulnerabilities field")
+	}
+
+	e := NewEnrichment()
+	for _, v := range catalog.Vulnerabilities {
+		if v.CveID == "" {
+			continue
+		}
+		e.kev[strings.ToUpper(v.CveID)] = KEV{
+			DateAdded:       v.DateAdded,
+			DueDate:         v.DueDate,
+			KnownRansomware: strings.EqualFold(v.KnownRansomwareCampaignUse, "known"),
+		}
+	}
+	return e, nil
+}
+
+var (
+	// epssSidecar and kevSidecar are the enrichment data placed within the DB directory.
+	epssSidecar = dataset.Sidecar[*Enrichment]{
+		Kind:  "enrichment data",
+		Path:  filepath.Join(DBDirName, EPSSFileName),
+		Parse: parseBytes(ParseEPSS),
+	}
+	kevSidecar = dataset.Sidecar[*Enrichment]{
+		Kind:  "enrichment data",
+		Path:  filepath.Join(DBDirName, KEVFileName),
+		Parse: parseBytes(ParseKEV),
+	}
+)
+
+// EPSSFromPath reads EPSS scores from a file, which may be gzip-compressed (with a .gz extension).
+func EPSSFromPath(path string) (*Enrichment, error) {
+	return epssSidecar.FromFile(path)
+}
+
+// KEVFromPath reads the KEV catalog from a file.
+func KEVFromPath(path string) (*Enrichment, error) {
+	return kevSidecar.FromFile(path)
+}
+
+// FromDBDir reads the enrichment data placed within the given DB directory, if there is any. An empty enrichment
+// (and no error) is returned when there is none.
+func FromDBDir(dir string) (*Enrichment, error) {
+	e := NewEnrichment()
+	for _, s := range []dataset.Sidecar[*Enrichment]{epssSidecar, kevSidecar} {
+		data, err := s.FromDBDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		e.Merge(data)
+	}
+	return e, nil
+}
+
+func parseBytes(parse func(io.Reader) (*Enrichment, error)) func([]byte) (*Enrichment, error) {
+	return func(data []byte) (*Enrichment, error) {
+		return parse(bytes.NewReader(data))
+	}
+}
@@ -0,0 +1,84 @@
+package priority
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEPSSFromPath(t *testing.T) {
+	e, err := EPSSFromPath("test-fixtures/epss.csv")
+	require.NoError(t, err)
+	assert.True(t, e.HasEPSS())
+	assert.False(t, e.HasKEV())
+
+	s, ok := e.EPSS("CVE-2021-44228")
+	require.True(t, ok)
+	assert.Equal(t, EPSS{Score: 0.97565, Percentile: 0.99996}, s)
+
+	s, ok = e.EPSS("cve-2023-0002")
+	require.True(t, ok)
+	assert.Equal(t, 0.52, s.Score)
+
+	_, ok = e.EPSS("CVE-2000-0001")
+	assert.False(t, ok)
+}
+
+func TestParseEPSS_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "empty", input: "", wantErr: "EOF"},
+		{name: "missing columns", input: "cve,score\nCVE-2021-44228,0.9\n", wantErr: "expected the columns"},
+		{name: "bad score", input: "cve,epss,percentile\nCVE-2021-44228,high,0.9\n", wantErr: "line 2: bad score"},
+		{name: "short line", input: "cve,epss,percentile\nCVE-2021-44228\n", wantErr: "line 2: expected 3 columns"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseEPSS(strings.NewReader(tt.input))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParseKEV(t *testing.T) {
+	e, err := ParseKEV(strings.NewReader(`{"vulnerabilities": [{"cveID": "CVE-2023-0003", "dateAdded": "2024-01-02", "dueDate": "2024-01-23", "knownRansomwareCampaignUse": "Unknown"}]}`))
+	require.NoError(t, err)
+	k, ok := e.KEV("CVE-2023-0003")
+	require.True(t, ok)
+	assert.Equal(t, KEV{DateAdded: "2024-01-02", DueDate: "2024-01-23"}, k)
+
+	_, err = ParseKEV(strings.NewReader(`{"title": "not a catalog"}`))
+	require.ErrorContains(t, err, "no vulnerabilities field")
+}
+
+func TestFromDBDir(t *testing.T) {
+	e, err := FromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+
+	s, ok := e.EPSS("CVE-2023-0001")
+	require.True(t, ok, "the gzip-compressed EPSS scores are read")
+	assert.Equal(t, 0.00045, s.Score)
+
+	k, ok := e.KEV("CVE-2021-44228")
+	require.True(t, ok)
+	assert.True(t, k.KnownRansomware)
+
+	e, err = FromDBDir(t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, e.HasEPSS())
+	assert.False(t, e.HasKEV())
+}
+
+func TestEnrichment_nil(t *testing.T) {
+	var e *Enrichment
+	_, ok := e.EPSS("CVE-2021-44228")
+	assert.False(t, ok)
+	_, ok = e.KEV("CVE-2021-44228")
+	assert.False(t, ok)
+	assert.False(t, e.HasEPSS())
+}
@@ -0,0 +1,166 @@
+package priority
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// Criterion is what the matches are ranked by.
+type Criterion string
+
+const (
+	// ByCVSS ranks the matches by their highest CVSS base score (or the score adjusted by the environmental metrics).
+	ByCVSS Criterion = "cvss"
+	// ByEPSS ranks the matches by the EPSS score of their CVEs.
+	ByEPSS Criterion = "epss"
+	// ByKEV ranks the matches of vulnerabilities known to be exploited first, then by their EPSS and CVSS scores.
+	ByKEV Criterion = "kev"
+)
+
+// Criteria are the criteria the matches can be ranked by.
+var Criteria = []Criterion{ByCVSS, ByEPSS, ByKEV}
+
+// ParseCriterion returns the criterion of the given name, ByCVSS when empty.
+func ParseCriterion(value string) (Criterion, error) {
+	switch c := Criterion(strings.ToLower(strings.TrimSpace(value))); c {
+	case "":
+		return ByCVSS, nil
+	case ByCVSS, ByEPSS, ByKEV:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unknown ranking criterion %q (options: %s, %s, %s)", value, ByCVSS, ByEPSS, ByKEV)
+	}
+}
+
+// Ranked is a match with the data it was ranked by.
+type Ranked struct {
+	Match    match.Match
+	Severity string
+	// CVSS is the highest CVSS score of the vulnerability, -1 when it has none.
+	CVSS float64
+	// EPSS is the highest EPSS score of the CVEs of the vulnerability, if any.
+	EPSS *EPSS
+	// KEV is the listing of a CVE of the vulnerability in the KEV catalog, if any.
+	KEV *KEV
+}
+
+// Rank returns the top matches by the criterion (all the matches when top is 0), the other criteria breaking the ties
+// (the KEV listing first, then the EPSS score, the CVSS score and the severity).
+func Rank(matches []match.Match, provider vulnerability.MetadataProvider, enrichment *Enrichment, by Criterion, top int) []Ranked {
+	ranked := make([]Ranked, 0, len(matches))
+	for _, m := range matches {
+		ranked = append(ranked, newRanked(m, provider, enrichment))
+	}
+
+	keys := []func(Ranked) float64{kevKey, epssKey, cvssKey, severityKey}
+	switch by {
+	case ByEPSS:
+		keys = []func(Ranked) float64{epssKey, kevKey, cvssKey, severityKey}
+	case ByCVSS:
+		keys = []func(Ranked) float64{cvssKey, severityKey, kevKey, epssKey}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		for _, key := range keys {
+			if a, b := key(ranked[i]), key(ranked[j]); a != b {
+				return a > b
+			}
+		}
+		// the order of the matches does not depend on the order they were given in
+		return ranked[i].Match.ID() < ranked[j].Match.ID()
+	})
+
+	if top > 0 && len(ranked) > top {
+		ranked = ranked[:top]
+	}
+	return ranked
+}
+
+func newRanked(m match.Match, provider vulnerability.MetadataProvider, enrichment *Enrichment) Ranked {
+	r := Ranked{Match: m, CVSS: -1}
+
+	var all []*vulnerability.Metadata
+	if provider != nil {
+		if meta, err := provider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace); err == nil && meta != nil {
+			meta = m.WithSeverityOverride(meta)
+			r.Severity = meta.Severity
+			if meta.AdjustedScore != nil {
+				r.CVSS = *meta.AdjustedScore
+			}
+			all = append(all, meta)
+		}
+		for _, related := range m.Vulnerability.RelatedVulnerabilities {
+			if meta, err := provider.GetMetadata(related.ID, related.Namespace); err == nil && meta != nil {
+				all = append(all, meta)
+			}
+		}
+	}
+	if r.CVSS < 0 {
+		for _, meta := range all {
+			for _, cvss := range meta.Cvss {
+				if cvss.Metrics.BaseScore > r.CVSS {
+					r.CVSS = cvss.Metrics.BaseScore
+				}
+			}
+		}
+	}
+
+	for _, cve := range CVEs(m) {
+		if s, ok := enrichment.EPSS(cve); ok && (r.EPSS == nil || s.Score > r.EPSS.Score) {
+			score := s
+			r.EPSS = &score
+		}
+		if k, ok := enrichment.KEV(cve); ok && r.KEV == nil {
+			listing := k
+			r.KEV = &listing
+		}
+	}
+	return r
+}
+
+// CVEs returns the CVE IDs of the vulnerability of the match: its ID and the IDs of its related vulnerabilities.
+func CVEs(m match.Match) []string {
+	var ids []string
+	if isCVE(m.Vulnerability.ID) {
+		ids = append(ids, m.Vulnerability.ID)
+	}
+	for _, related := range m.Vulnerability.RelatedVulnerabilities {
+		if isCVE(related.ID) {
+			ids = append(ids, related.ID)
+		}
+	}
+	return ids
+}
+
+func isCVE(id string) bool {
+	return strings.HasPrefix(strings.ToUpper(id), "CVE-")
+}
+
+func kevKey(r Ranked) float64 {
+	if r.KEV == nil {
+		return 0
+	}
+	if r.KEV.KnownRansomware {
+		return 2
+	}
+	return 1
+}
+
+func epssKey(r Ranked) float64 {
+	if r.EPSS == nil {
+		return -1
+	}
+	return r.EPSS.Score
+}
+
+func cvssKey(r Ranked) float64 {
+	return r.CVSS
+}
+
+func severityKey(r Ranked) float64 {
+	return float64(vulnerability.ParseSeverity(r.Severity))
+}
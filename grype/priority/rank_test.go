@@ -0,0 +1,130 @@
+package priority
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+type metadataProvider map[string]vulnerability.Metadata
+
+func (p metadataProvider) GetMetadata(id, _ string) (*vulnerability.Metadata, error) {
+	m, ok := p[id]
+	if !ok {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+func metadata(id, severity string, score float64) vulnerability.Metadata {
+	return vulnerability.Metadata{
+		ID:       id,
+		Severity: severity,
+		Cvss:     []vulnerability.Cvss{{Metrics: vulnerability.CvssMetrics{BaseScore: score}}},
+	}
+}
+
+func newMatch(id string, related ...string) match.Match {
+	m := match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: id},
+		Package:       pkg.Package{ID: pkg.ID(id), Name: "pkg-" + id, Version: "1.0.0"},
+	}
+	for _, r := range related {
+		m.Vulnerability.RelatedVulnerabilities = append(m.Vulnerability.RelatedVulnerabilities, vulnerability.Reference{ID: r})
+	}
+	return m
+}
+
+func TestParseCriterion(t *testing.T) {
+	for value, want := range map[string]Criterion{"": ByCVSS, "cvss": ByCVSS, "EPSS": ByEPSS, " kev ": ByKEV} {
+		got, err := ParseCriterion(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseCriterion("risk")
+	require.ErrorContains(t, err, `unknown ranking criterion "risk"`)
+}
+
+func TestRank(t *testing.T) {
+	provider := metadataProvider{
+		"CVE-2021-44228":      metadata("CVE-2021-44228", "Critical", 10),
+		"CVE-2023-0001":       metadata("CVE-2023-0001", "Critical", 9.8),
+		"GHSA-aaaa-bbbb-cccc": metadata("GHSA-aaaa-bbbb-cccc", "Medium", 5.3),
+		"CVE-2023-0003":       metadata("CVE-2023-0003", "High", 7.5),
+		"CVE-2023-0004":       metadata("CVE-2023-0004", "Low", 3.1),
+	}
+	enrichment, err := FromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+
+	matches := []match.Match{
+		newMatch("CVE-2023-0004"),
+		newMatch("CVE-2023-0003"),
+		// the EPSS score of a GHSA is the score of its CVE
+		newMatch("GHSA-aaaa-bbbb-cccc", "CVE-2023-0002"),
+		newMatch("CVE-2023-0001"),
+		newMatch("CVE-2021-44228"),
+	}
+
+	ids := func(ranked []Ranked) []string {
+		var ids []string
+		for _, r := range ranked {
+			ids = append(ids, r.Match.Vulnerability.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		by   Criterion
+		top  int
+		want []string
+	}{
+		{
+			by:   ByCVSS,
+			want: []string{"CVE-2021-44228", "CVE-2023-0001", "CVE-2023-0003", "GHSA-aaaa-bbbb-cccc", "CVE-2023-0004"},
+		},
+		{
+			by:   ByEPSS,
+			want: []string{"CVE-2021-44228", "GHSA-aaaa-bbbb-cccc", "CVE-2023-0001", "CVE-2023-0003", "CVE-2023-0004"},
+		},
+		{
+			by:   ByKEV,
+			want: []string{"CVE-2021-44228", "CVE-2023-0003", "GHSA-aaaa-bbbb-cccc", "CVE-2023-0001", "CVE-2023-0004"},
+		},
+		{
+			by:   ByKEV,
+			top:  2,
+			want: []string{"CVE-2021-44228", "CVE-2023-0003"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.by), func(t *testing.T) {
+			assert.Equal(t, tt.want, ids(Rank(matches, provider, enrichment, tt.by, tt.top)))
+		})
+	}
+
+	ranked := Rank(matches, provider, enrichment, ByEPSS, 2)
+	require.Len(t, ranked, 2)
+	assert.Equal(t, 10.0, ranked[0].CVSS)
+	assert.Equal(t, "Critical", ranked[0].Severity)
+	require.NotNil(t, ranked[0].KEV)
+	assert.True(t, ranked[0].KEV.KnownRansomware)
+	require.NotNil(t, ranked[1].EPSS)
+	assert.Equal(t, 0.52, ranked[1].EPSS.Score)
+	assert.Nil(t, ranked[1].KEV)
+}
+
+func TestRank_withoutEnrichment(t *testing.T) {
+	provider := metadataProvider{
+		"CVE-2023-0001": metadata("CVE-2023-0001", "Critical", 9.8),
+	}
+	ranked := Rank([]match.Match{newMatch("CVE-2023-0002"), newMatch("CVE-2023-0001")}, provider, nil, ByEPSS, 0)
+	require.Len(t, ranked, 2)
+	assert.Equal(t, "CVE-2023-0001", ranked[0].Match.Vulnerability.ID)
+	assert.Nil(t, ranked[0].EPSS)
+	assert.Equal(t, -1.0, ranked[1].CVSS)
+}
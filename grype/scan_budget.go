@@ -0,0 +1,92 @@
+package grype
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/anchore/grype/grype/pkg"
+)
+
+// ErrScanTimeExceeded is the cause of the contexts of a budget (see ScanBudget.Context) once the deadline passed.
+var ErrScanTimeExceeded = errors.New("the maximum scan time was exceeded")
+
+// ScanBudget bounds the time of a scan: the packages not yet matched when the deadline passes are left unscanned (along
+// with the package being matched, whose matcher is abandoned), and the matches of the packages scanned until then are
+// returned as partial results. A budget may be shared by the scans of several matchers (e.g. of each platform of an
+// image index), which then share the deadline.
+type ScanBudget struct {
+	// Deadline is the time after which no more packages are matched.
+	Deadline time.Time
+
+	// now is the clock the deadline is checked against, time.Now when nil.
+	now func() time.Time
+
+	lock      sync.Mutex
+	unscanned []pkg.Package
+}
+
+// NewScanBudget returns a budget of the given time from now, or nil (no budget) when the time is not positive.
+func NewScanBudget(maxScanTime time.Duration) *ScanBudget {
+	if maxScanTime <= 0 {
+		return nil
+	}
+	return &ScanBudget{Deadline: time.Now().Add(maxScanTime)}
+}
+
+// Exceeded tells whether packages were left unscanned because the deadline passed.
+func (b *ScanBudget) Exceeded() bool {
+	return len(b.Unscanned()) > 0
+}
+
+// Unscanned returns the packages left unscanned because the deadline passed, in the order they would have been
+// scanned.
+func (b *ScanBudget) Unscanned() []pkg.Package {
+	if b == nil {
+		return nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]pkg.Package(nil), b.unscanned...)
+}
+
+// Context returns a context of the parent that is done once the deadline passes, with ErrScanTimeExceeded as its cause
+// (see context.Cause), to bound the work done before matching (e.g. cataloging). The context of a nil budget is only
+// canceled with the parent.
+func (b *ScanBudget) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	if b == nil || b.Deadline.IsZero() {
+		return context.WithCancel(parent)
+	}
+	return context.WithDeadlineCause(parent, b.Deadline, ErrScanTimeExceeded)
+}
+
+func (b *ScanBudget) expired() bool {
+	if b == nil || b.Deadline.IsZero() {
+		return false
+	}
+	return !b.clock()().Before(b.Deadline)
+}
+
+// timer returns a channel receiving once the deadline passes, and a function releasing the timer. The channel of a nil
+// budget never receives.
+func (b *ScanBudget) timer() (<-chan time.Time, func()) {
+	if b == nil || b.Deadline.IsZero() {
+		return nil, func() {}
+	}
+	t := time.NewTimer(b.Deadline.Sub(b.clock()()))
+	return t.C, func() { t.Stop() }
+}
+
+func (b *ScanBudget) clock() func() time.Time {
+	if b.now != nil {
+		return b.now
+	}
+	return time.Now
+}
+
+func (b *ScanBudget) skip(pkgs ...pkg.Package) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.unscanned = append(b.unscanned, pkgs...)
+}
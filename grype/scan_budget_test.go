@@ -0,0 +1,35 @@
+package grype
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScanBudget(t *testing.T) {
+	assert.Nil(t, NewScanBudget(0))
+	budget := NewScanBudget(time.Minute)
+	require.NotNil(t, budget)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), budget.Deadline, 5*time.Second)
+	assert.False(t, budget.Exceeded())
+
+	var none *ScanBudget
+	assert.False(t, none.Exceeded())
+	assert.False(t, none.expired())
+}
+
+func TestScanBudget_Context(t *testing.T) {
+	ctx, cancel := (&ScanBudget{Deadline: time.Now().Add(-time.Second)}).Context(context.Background())
+	defer cancel()
+	<-ctx.Done()
+	assert.ErrorIs(t, context.Cause(ctx), ErrScanTimeExceeded)
+
+	// a nil budget has no deadline
+	var none *ScanBudget
+	ctx, cancel = none.Context(context.Background())
+	assert.NoError(t, ctx.Err())
+	cancel()
+}
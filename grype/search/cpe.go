@@ -94,6 +94,7 @@ func ByPackageCPE(store vulnerability.ProviderByCPE, d *distro.Distro, p pkg.Pac
 	if len(p.CPEs) == 0 {
 		return nil, ErrEmptyCPEMatch
 	}
+	recorder := match.RejectionRecorderOf(store)
 	matchesByFingerprint := make(map[match.Fingerprint]match.Match)
 	for _, c := range p.CPEs {
 		// prefer the CPE version, but if npt specified use the package version
@@ -133,18 +134,18 @@ func ByPackageCPE(store vulnerability.ProviderByCPE, d *distro.Distro, p pkg.Pac
 			return nil, fmt.Errorf("matcher failed to fetch by CPE pkg=%q: %w", p.Name, err)
 		}
 
-		applicableVulns, err := onlyQualifiedPackages(d, p, allPkgVulns)
+		applicableVulns, err := onlyQualifiedPackages(recorder, d, p, allPkgVulns)
 		if err != nil {
 			return nil, fmt.Errorf("unable to filter cpe-related vulnerabilities: %w", err)
 		}
 
 		// TODO: Port this over to a qualifier and remove
-		applicableVulns, err = onlyVulnerableVersions(verObj, applicableVulns)
+		applicableVulns, err = onlyVulnerableVersions(recorder, p, verObj, applicableVulns)
 		if err != nil {
 			return nil, fmt.Errorf("unable to filter cpe-related vulnerabilities: %w", err)
 		}
 
-		applicableVulns = onlyVulnerableTargets(p, applicableVulns)
+		applicableVulns = onlyVulnerableTargets(recorder, p, applicableVulns)
 
 		// for each vulnerability record found, check the version constraint. If the constraint is satisfied
 		// relative to the current version information from the CPE (or the package) then the given package
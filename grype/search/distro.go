@@ -37,13 +37,14 @@ func ByPackageDistro(store vulnerability.ProviderByDistro, d *distro.Distro, p p
 		return nil, fmt.Errorf("matcher failed to fetch distro=%q pkg=%q: %w", d, p.Name, err)
 	}
 
-	applicableVulns, err := onlyQualifiedPackages(d, p, allPkgVulns)
+	recorder := match.RejectionRecorderOf(store)
+	applicableVulns, err := onlyQualifiedPackages(recorder, d, p, allPkgVulns)
 	if err != nil {
 		return nil, fmt.Errorf("unable to filter distro-related vulnerabilities: %w", err)
 	}
 
 	// TODO: Port this over to a qualifier and remove
-	applicableVulns, err = onlyVulnerableVersions(verObj, applicableVulns)
+	applicableVulns, err = onlyVulnerableVersions(recorder, p, verObj, applicableVulns)
 	if err != nil {
 		return nil, fmt.Errorf("unable to filter distro-related vulnerabilities: %w", err)
 	}
@@ -11,6 +11,8 @@ import (
 	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/pkg/qualifier"
+	"github.com/anchore/grype/grype/pkg/qualifier/arch"
 	"github.com/anchore/grype/grype/version"
 	"github.com/anchore/grype/grype/vulnerability"
 	syftPkg "github.com/anchore/syft/syft/pkg"
@@ -173,3 +175,64 @@ func TestFindMatchesByPackageDistroSles(t *testing.T) {
 	assert.NoError(t, err)
 	assertMatchesUsingIDsForVulnerabilities(t, expected, actual)
 }
+
+type rejectionRecordingDistroProvider struct {
+	vulns      []vulnerability.Vulnerability
+	rejections *match.Rejections
+}
+
+func (pr rejectionRecordingDistroProvider) GetByDistro(*distro.Distro, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return pr.vulns, nil
+}
+
+func (pr rejectionRecordingDistroProvider) RecordRejection(r match.Rejection) {
+	pr.rejections.RecordRejection(r)
+}
+
+func TestFindMatchesByPackageDistro_Rejections(t *testing.T) {
+	p := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "neutron",
+		Version: "2014.1.3-6",
+		Type:    syftPkg.DebPkg,
+		Arch:    "amd64",
+	}
+	d, err := distro.New(distro.Debian, "8", "")
+	require.NoError(t, err)
+
+	store := rejectionRecordingDistroProvider{
+		vulns: []vulnerability.Vulnerability{
+			{
+				Constraint: version.MustGetConstraint("< 2014.1.5-6", version.DebFormat),
+				ID:         "CVE-2014-fake-1",
+				Namespace:  "debian:8",
+			},
+			{
+				Constraint: version.MustGetConstraint("< 2014.1.2-1", version.DebFormat),
+				ID:         "CVE-2014-fake-2",
+				Namespace:  "debian:8",
+			},
+			{
+				Constraint:        version.MustGetConstraint("< 2014.1.5-6", version.DebFormat),
+				ID:                "CVE-2014-fake-3",
+				Namespace:         "debian:8",
+				PackageQualifiers: []qualifier.Qualifier{arch.New([]string{"arm64"})},
+			},
+		},
+		rejections: match.NewRejections(),
+	}
+
+	actual, err := ByPackageDistro(store, d, p, match.DpkgMatcher)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, "CVE-2014-fake-1", actual[0].Vulnerability.ID)
+
+	var got []string
+	for _, r := range store.rejections.Sorted() {
+		got = append(got, r.Vulnerability.ID+" "+string(r.Filter)+": "+r.Reason)
+	}
+	assert.Equal(t, []string{
+		`CVE-2014-fake-2 version-constraint: version 2014.1.3-6 is not within the constraint "< 2014.1.2-1 (deb)"`,
+		"CVE-2014-fake-3 qualifier: the package does not satisfy the qualifier arch=arm64",
+	}, got)
+}
@@ -33,13 +33,14 @@ func ByPackageLanguage(store vulnerability.ProviderByLanguage, d *distro.Distro,
 		return nil, fmt.Errorf("matcher failed to fetch language=%q pkg=%q: %w", p.Language, p.Name, err)
 	}
 
-	applicableVulns, err := onlyQualifiedPackages(d, p, allPkgVulns)
+	recorder := match.RejectionRecorderOf(store)
+	applicableVulns, err := onlyQualifiedPackages(recorder, d, p, allPkgVulns)
 	if err != nil {
 		return nil, fmt.Errorf("unable to filter language-related vulnerabilities: %w", err)
 	}
 
 	// TODO: Port this over to a qualifier and remove
-	applicableVulns, err = onlyVulnerableVersions(verObj, applicableVulns)
+	applicableVulns, err = onlyVulnerableVersions(recorder, p, verObj, applicableVulns)
 	if err != nil {
 		return nil, fmt.Errorf("unable to filter language-related vulnerabilities: %w", err)
 	}
@@ -4,11 +4,12 @@ import (
 	"fmt"
 
 	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/vulnerability"
 )
 
-func onlyQualifiedPackages(d *distro.Distro, p pkg.Package, allVulns []vulnerability.Vulnerability) ([]vulnerability.Vulnerability, error) {
+func onlyQualifiedPackages(recorder match.RejectionRecorder, d *distro.Distro, p pkg.Package, allVulns []vulnerability.Vulnerability) ([]vulnerability.Vulnerability, error) {
 	var vulns []vulnerability.Vulnerability
 
 	for _, vuln := range allVulns {
@@ -23,6 +24,12 @@ func onlyQualifiedPackages(d *distro.Distro, p pkg.Package, allVulns []vulnerabi
 
 			isVulnerable = v
 			if !isVulnerable {
+				recorder.RecordRejection(match.Rejection{
+					Vulnerability: vuln,
+					Package:       p,
+					Filter:        match.QualifierRejection,
+					Reason:        fmt.Sprintf("the package does not satisfy the qualifier %s", q),
+				})
 				break
 			}
 		}
@@ -1,15 +1,19 @@
 package search
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/facebookincubator/nvdtools/wfn"
 
+	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/vulnerability"
 	syftPkg "github.com/anchore/syft/syft/pkg"
 )
 
 func isOSPackage(p pkg.Package) bool {
-	return p.Type == syftPkg.AlpmPkg || p.Type == syftPkg.ApkPkg || p.Type == syftPkg.DebPkg || p.Type == syftPkg.KbPkg || p.Type == syftPkg.PortagePkg || p.Type == syftPkg.RpmPkg
+	return p.Type == syftPkg.AlpmPkg || p.Type == syftPkg.ApkPkg || p.Type == syftPkg.DebPkg || p.Type == syftPkg.KbPkg || p.Type == syftPkg.PortagePkg || p.Type == syftPkg.RpmPkg || p.Type == pkg.OpkgPkg
 }
 
 func isUnknownTarget(targetSW string) bool {
@@ -34,7 +38,7 @@ func isUnknownTarget(targetSW string) bool {
 }
 
 // Determines if a vulnerability is an accurate match using the vulnerability's cpes' target software
-func onlyVulnerableTargets(p pkg.Package, allVulns []vulnerability.Vulnerability) []vulnerability.Vulnerability {
+func onlyVulnerableTargets(recorder match.RejectionRecorder, p pkg.Package, allVulns []vulnerability.Vulnerability) []vulnerability.Vulnerability {
 	var vulns []vulnerability.Vulnerability
 
 	// Exclude OS package types from this logic, since they could be embedding any type of ecosystem package
@@ -57,8 +61,10 @@ func onlyVulnerableTargets(p pkg.Package, allVulns []vulnerability.Vulnerability
 
 	for _, vuln := range allVulns {
 		isPackageVulnerable := len(vuln.CPEs) == 0
+		var targets []string
 		for _, cpe := range vuln.CPEs {
 			targetSW := cpe.Attributes.TargetSW
+			targets = append(targets, targetSW)
 			mismatchWithUnknownLanguage := syftPkg.LanguageByName(targetSW) != p.Language && isUnknownTarget(targetSW)
 			if targetSW == wfn.Any || targetSW == wfn.NA || syftPkg.LanguageByName(targetSW) == p.Language || mismatchWithUnknownLanguage {
 				isPackageVulnerable = true
@@ -66,6 +72,12 @@ func onlyVulnerableTargets(p pkg.Package, allVulns []vulnerability.Vulnerability
 		}
 
 		if !isPackageVulnerable {
+			recorder.RecordRejection(match.Rejection{
+				Vulnerability: vuln,
+				Package:       p,
+				Filter:        match.TargetSoftwareRejection,
+				Reason:        fmt.Sprintf("the target software of the CPEs (%s) is not the language of the package (%s)", strings.Join(targets, ", "), p.Language),
+			})
 			continue
 		}
 
@@ -4,26 +4,61 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/version"
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/grype/internal/log"
 )
 
-func onlyVulnerableVersions(verObj *version.Version, allVulns []vulnerability.Vulnerability) ([]vulnerability.Vulnerability, error) {
+func onlyVulnerableVersions(recorder match.RejectionRecorder, p pkg.Package, verObj *version.Version, allVulns []vulnerability.Vulnerability) ([]vulnerability.Vulnerability, error) {
 	var vulns []vulnerability.Vulnerability
 
 	for _, vuln := range allVulns {
 		isPackageVulnerable, err := vuln.Constraint.Satisfied(verObj)
+		if errors.Is(err, version.ErrUnknownCommitRange) {
+			// not reported as a match nor as known not to apply: the rejection tells the commit could not be placed
+			log.WithFields("vulnerability", vuln.ID, "package", p.Name, "commit", verObj.Raw).Debug("unable to tell whether the commit is within the git range of the vulnerability")
+			recorder.RecordRejection(match.Rejection{
+				Vulnerability: vuln,
+				Package:       p,
+				Filter:        match.VersionConstraintRejection,
+				Reason:        fmt.Sprintf("unknown whether commit %s is within the git range %q, the commit graph is not available", verObj.Raw, vuln.Constraint),
+			})
+			continue
+		}
 		if err != nil {
 			var e *version.NonFatalConstraintError
-			if errors.As(err, &e) {
-				log.Warn(e)
-			} else {
+			heuristic, fuzzy := version.HeuristicComparison(vuln.Constraint, verObj)
+			switch {
+			case errors.As(err, &e), fuzzy:
+				// the comparison failed for this vulnerability only (e.g. a version the heuristic cannot compare)
+				log.Warn(err)
+				recorder.RecordRejection(match.Rejection{
+					Vulnerability: vuln,
+					Package:       p,
+					Filter:        match.VersionComparisonRejection,
+					Reason:        fmt.Sprintf("unable to compare version %s with the constraint %q: %v", verObj.Raw, vuln.Constraint, err),
+					Heuristic:     heuristic,
+				})
+				continue
+			case verObj.Format == version.GitFormat:
+				// commit-pinned packages cannot be compared against release-based ranges, only against commit ranges
+				log.WithFields("constraint", vuln.Constraint, "version", verObj).Trace("skipping non-commit constraint for commit version")
+			default:
 				return nil, fmt.Errorf("failed to check constraint=%q version=%q: %w", vuln.Constraint, verObj, err)
 			}
 		}
 
 		if !isPackageVulnerable {
+			heuristic, _ := version.HeuristicComparison(vuln.Constraint, verObj)
+			recorder.RecordRejection(match.Rejection{
+				Vulnerability: vuln,
+				Package:       p,
+				Filter:        match.VersionConstraintRejection,
+				Reason:        fmt.Sprintf("version %s is not within the constraint %q", verObj.Raw, vuln.Constraint),
+				Heuristic:     heuristic,
+			})
 			continue
 		}
 
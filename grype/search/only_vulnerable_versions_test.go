@@ -0,0 +1,37 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func Test_onlyVulnerableVersions_heuristicRejections(t *testing.T) {
+	p := pkg.Package{ID: "tool", Name: "tool", Version: "2024.01.15", Type: syftPkg.BinaryPkg}
+	v, err := version.NewVersionFromPkg(p)
+	require.NoError(t, err)
+
+	vulns := []vulnerability.Vulnerability{
+		{ID: "CVE-2024-0001", Constraint: version.MustGetConstraint("< 2024.02", version.UnknownFormat)},
+		{ID: "CVE-2024-0002", Constraint: version.MustGetConstraint("< 2023.11", version.UnknownFormat)},
+	}
+	rejections := match.NewRejections()
+	matched, err := onlyVulnerableVersions(rejections, p, v, vulns)
+	require.NoError(t, err)
+	require.Len(t, matched, 1)
+	assert.Equal(t, "CVE-2024-0001", matched[0].ID)
+
+	// the rejection tells the comparison relied on a heuristic
+	sorted := rejections.Sorted()
+	require.Len(t, sorted, 1)
+	assert.Equal(t, "CVE-2024-0002", sorted[0].Vulnerability.ID)
+	assert.Equal(t, match.VersionConstraintRejection, sorted[0].Filter)
+	assert.Equal(t, version.CalverHeuristic, sorted[0].Heuristic)
+}
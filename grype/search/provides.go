@@ -0,0 +1,41 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// ByProvidedPackages finds the vulnerabilities filed against the packages that the given package provides (virtual or
+// renamed packages). The matches are indirect matches for the given package, where the search details record how
+// the provided package was resolved.
+func ByProvidedPackages(store vulnerability.ProviderByDistro, d *distro.Distro, p pkg.Package, upstreamMatcher match.MatcherType) ([]match.Match, error) {
+	var matches []match.Match
+	for _, provided := range pkg.ProvidedPackages(p) {
+		providedMatches, err := ByPackageDistro(store, d, provided, upstreamMatcher)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find vulnerabilities for package provided by %q: %w", p.Name, err)
+		}
+		for i := range providedMatches {
+			for j := range providedMatches[i].Details {
+				searchedBy, ok := providedMatches[i].Details[j].SearchedBy.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				// the resolution chain: the installed package provides the package the vulnerability is filed against
+				searchedBy["providedBy"] = map[string]string{
+					"name":    p.Name,
+					"version": p.Version,
+				}
+			}
+		}
+		matches = append(matches, providedMatches...)
+	}
+
+	match.ConvertToIndirectMatches(matches, p)
+
+	return matches, nil
+}
@@ -0,0 +1,50 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestByProvidedPackages(t *testing.T) {
+	p := pkg.Package{
+		ID:       "neutron-ng-id",
+		Name:     "neutron-ng",
+		Version:  "2014.1.3-6",
+		Type:     syftPkg.DebPkg,
+		Provides: []pkg.ProvidedPackage{{Name: "neutron"}},
+	}
+
+	d, err := distro.New(distro.Debian, "8", "")
+	require.NoError(t, err)
+
+	matches, err := ByProvidedPackages(newMockProviderByDistro(), d, p, match.DpkgMatcher)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+
+	m := matches[0]
+	assert.Equal(t, "CVE-2014-fake-1", m.Vulnerability.ID)
+	assert.Equal(t, p.Name, m.Package.Name)
+	require.Len(t, m.Details, 1)
+	assert.Equal(t, match.ExactIndirectMatch, m.Details[0].Type)
+
+	searchedBy, ok := m.Details[0].SearchedBy.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"name": "neutron", "version": "2014.1.3-6"}, searchedBy["package"])
+	assert.Equal(t, map[string]string{"name": "neutron-ng", "version": "2014.1.3-6"}, searchedBy["providedBy"])
+}
+
+func TestByProvidedPackages_noProvides(t *testing.T) {
+	d, err := distro.New(distro.Debian, "8", "")
+	require.NoError(t, err)
+
+	matches, err := ByProvidedPackages(newMockProviderByDistro(), d, pkg.Package{Name: "neutron", Version: "2014.1.3-6", Type: syftPkg.DebPkg}, match.DpkgMatcher)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
@@ -0,0 +1,283 @@
+package severity
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// the temporal and environmental metrics of CVSS v3 (https://www.first.org/cvss/v3.1/specification-document),
+// with the values each may take
+var adjustmentMetrics = map[string][]string{
+	"E":   {"X", "H", "F", "P", "U"},
+	"RL":  {"X", "U", "W", "T", "O"},
+	"RC":  {"X", "C", "R", "U"},
+	"CR":  {"X", "H", "M", "L"},
+	"IR":  {"X", "H", "M", "L"},
+	"AR":  {"X", "H", "M", "L"},
+	"MAV": {"X", "N", "A", "L", "P"},
+	"MAC": {"X", "L", "H"},
+	"MPR": {"X", "N", "L", "H"},
+	"MUI": {"X", "N", "R"},
+	"MS":  {"X", "U", "C"},
+	"MC":  {"X", "H", "L", "N"},
+	"MI":  {"X", "H", "L", "N"},
+	"MA":  {"X", "H", "L", "N"},
+}
+
+var temporalMetrics = []string{"E", "RL", "RC"}
+
+var cvssWeights = map[string]map[string]float64{
+	"AV":  {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC":  {"L": 0.77, "H": 0.44},
+	"UI":  {"N": 0.85, "R": 0.62},
+	"CIA": {"H": 0.56, "L": 0.22, "N": 0},
+	"E":   {"X": 1, "H": 1, "F": 0.97, "P": 0.94, "U": 0.91},
+	"RL":  {"X": 1, "U": 1, "W": 0.97, "T": 0.96, "O": 0.95},
+	"RC":  {"X": 1, "C": 1, "R": 0.96, "U": 0.92},
+	"REQ": {"X": 1, "H": 1.5, "M": 1, "L": 0.5},
+}
+
+// Metrics are CVSS v3 temporal and environmental metrics (e.g. "E:U/RL:O/MAV:L") that describe the exploit maturity
+// of a vulnerability and the context it is deployed in, which adjust the base score of the vulnerability.
+type Metrics map[string]string
+
+// ParseMetrics parses temporal and environmental CVSS v3 metrics in vector form (e.g. "E:U/RL:O/MAV:L"), with or
+// without a leading "CVSS:3.x/" prefix.
+func ParseMetrics(value string) (Metrics, error) {
+	metrics := make(Metrics)
+	for _, part := range strings.Split(strings.TrimSpace(value), "/") {
+		if part == "" || strings.HasPrefix(part, "CVSS:") {
+			continue
+		}
+		name, v, ok := strings.Cut(part, ":")
+		name, v = strings.ToUpper(strings.TrimSpace(name)), strings.ToUpper(strings.TrimSpace(v))
+		values, known := adjustmentMetrics[name]
+		if !ok || !known {
+			return nil, fmt.Errorf("unsupported CVSS metric %q (only temporal and environmental metrics may be given)", part)
+		}
+		if !contains(values, v) {
+			return nil, fmt.Errorf("invalid value for CVSS metric %q (options: %v)", part, values)
+		}
+		metrics[name] = v
+	}
+	return metrics, nil
+}
+
+// Merge returns the metrics with the other metrics taking precedence.
+func (m Metrics) Merge(other Metrics) Metrics {
+	merged := make(Metrics, len(m)+len(other))
+	for k, v := range m {
+		merged[k] = v
+	}
+	for k, v := range other {
+		merged[k] = v
+	}
+	return merged
+}
+
+// String returns the metrics in vector form, in the order of the CVSS specification.
+func (m Metrics) String() string {
+	var parts []string
+	for _, name := range []string{"E", "RL", "RC", "CR", "IR", "AR", "MAV", "MAC", "MPR", "MUI", "MS", "MC", "MI", "MA"} {
+		if v, ok := m[name]; ok {
+			parts = append(parts, name+":"+v)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// AdjustedScore returns the score of the given CVSS v3 vector after applying the metrics: the environmental score
+// when any environmental metric is given (or present in the vector), otherwise the temporal score.
+func AdjustedScore(vector string, adjustment Metrics) (float64, error) {
+	version, metrics, err := parseVector(vector)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range adjustment {
+		metrics[k] = v
+	}
+
+	base, err := baseScore(version, metrics)
+	if err != nil {
+		return 0, err
+	}
+
+	temporal := weight("E", metrics) * weight("RL", metrics) * weight("RC", metrics)
+	if !hasEnvironmentalMetrics(metrics) {
+		return roundUp(version, base*temporal), nil
+	}
+	return environmentalScore(version, metrics, temporal), nil
+}
+
+// Rating returns the qualitative severity rating of a CVSS v3 score.
+func Rating(score float64) vulnerability.Severity {
+	switch {
+	case score >= 9:
+		return vulnerability.CriticalSeverity
+	case score >= 7:
+		return vulnerability.HighSeverity
+	case score >= 4:
+		return vulnerability.MediumSeverity
+	case score > 0:
+		return vulnerability.LowSeverity
+	default:
+		return vulnerability.NegligibleSeverity
+	}
+}
+
+func parseVector(vector string) (string, map[string]string, error) {
+	parts := strings.Split(strings.TrimSpace(vector), "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return "", nil, fmt.Errorf("only CVSS v3 vectors can be adjusted: %q", vector)
+	}
+	version := strings.TrimPrefix(parts[0], "CVSS:")
+
+	metrics := make(map[string]string)
+	for _, part := range parts[1:] {
+		name, v, ok := strings.Cut(part, ":")
+		if !ok {
+			return "", nil, fmt.Errorf("invalid CVSS vector %q", vector)
+		}
+		metrics[name] = v
+	}
+	for _, name := range []string{"AV", "AC", "PR", "UI", "S", "C", "I", "A"} {
+		if _, ok := metrics[name]; !ok {
+			return "", nil, fmt.Errorf("CVSS vector %q is missing the base metric %s", vector, name)
+		}
+	}
+	return version, metrics, nil
+}
+
+func baseScore(version string, m map[string]string) (float64, error) {
+	changed := m["S"] == "C"
+	iss := 1 - (1-ciaWeight(m["C"]))*(1-ciaWeight(m["I"]))*(1-ciaWeight(m["A"]))
+
+	var impact float64
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+
+	av, ok := cvssWeights["AV"][m["AV"]]
+	if !ok {
+		return 0, fmt.Errorf("invalid attack vector %q", m["AV"])
+	}
+	exploitability := 8.22 * av * cvssWeights["AC"][m["AC"]] * privilegesWeight(m["PR"], changed) * cvssWeights["UI"][m["UI"]]
+
+	if impact <= 0 {
+		return 0, nil
+	}
+	if changed {
+		return roundUp(version, math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return roundUp(version, math.Min(impact+exploitability, 10)), nil
+}
+
+func environmentalScore(version string, m map[string]string, temporal float64) float64 {
+	modified := func(name string) string {
+		if v := m["M"+name]; v != "" && v != "X" {
+			return v
+		}
+		return m[name]
+	}
+
+	changed := modified("S") == "C"
+	miss := math.Min(1-
+		(1-cvssWeights["REQ"][requirement(m["CR"])]*ciaWeight(modified("C")))*
+			(1-cvssWeights["REQ"][requirement(m["IR"])]*ciaWeight(modified("I")))*
+			(1-cvssWeights["REQ"][requirement(m["AR"])]*ciaWeight(modified("A"))), 0.915)
+
+	var impact float64
+	switch {
+	case !changed:
+		impact = 6.42 * miss
+	case version == "3.0":
+		impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss-0.02, 15)
+	default:
+		impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss*0.9731-0.02, 13)
+	}
+	exploitability := 8.22 * cvssWeights["AV"][modified("AV")] * cvssWeights["AC"][modified("AC")] *
+		privilegesWeight(modified("PR"), changed) * cvssWeights["UI"][modified("UI")]
+
+	if impact <= 0 {
+		return 0
+	}
+	if changed {
+		return roundUp(version, roundUp(version, math.Min(1.08*(impact+exploitability), 10))*temporal)
+	}
+	return roundUp(version, roundUp(version, math.Min(impact+exploitability, 10))*temporal)
+}
+
+func hasEnvironmentalMetrics(m map[string]string) bool {
+	for name := range adjustmentMetrics {
+		if contains(temporalMetrics, name) {
+			continue
+		}
+		if v, ok := m[name]; ok && v != "X" {
+			return true
+		}
+	}
+	return false
+}
+
+func weight(name string, m map[string]string) float64 {
+	if w, ok := cvssWeights[name][m[name]]; ok {
+		return w
+	}
+	return 1
+}
+
+func ciaWeight(value string) float64 {
+	return cvssWeights["CIA"][value]
+}
+
+func requirement(value string) string {
+	if value == "" {
+		return "X"
+	}
+	return value
+}
+
+func privilegesWeight(value string, changed bool) float64 {
+	switch value {
+	case "N":
+		return 0.85
+	case "L":
+		if changed {
+			return 0.68
+		}
+		return 0.62
+	case "H":
+		if changed {
+			return 0.5
+		}
+		return 0.27
+	}
+	return 0
+}
+
+// roundUp rounds up to one decimal as defined by the CVSS specification of the given version
+func roundUp(version string, value float64) float64 {
+	if version == "3.0" {
+		return math.Ceil(value*10) / 10
+	}
+	// CVSS 3.1 avoids floating point artifacts (e.g. 4.00000000001 rounding up to 4.1)
+	i := int64(math.Round(value * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package severity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+func TestAdjustedScore(t *testing.T) {
+	const critical = "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"
+	const xss = "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N"
+
+	tests := []struct {
+		name    string
+		vector  string
+		metrics string
+		want    float64
+		wantErr require.ErrorAssertionFunc
+	}{
+		{name: "no adjustment", vector: critical, want: 9.8},
+		{name: "temporal", vector: critical, metrics: "E:U/RL:O/RC:C", want: 8.5},
+		{name: "environmental", vector: critical, metrics: "MAV:L", want: 8.4},
+		{name: "environmental and temporal", vector: critical, metrics: "E:U/MAV:L", want: 7.7},
+		{name: "not applicable components", vector: critical, metrics: "MC:N/MI:N/MA:N", want: 0},
+		{name: "scope changed", vector: xss, want: 6.1},
+		{name: "scope changed with requirements", vector: xss, metrics: "CR:H/IR:H", want: 7.4},
+		{name: "CVSS 3.0", vector: "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", metrics: "E:P", want: 9.3},
+		{name: "CVSS 2", vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P", wantErr: require.Error},
+		{name: "missing base metric", vector: "CVSS:3.1/AV:N/AC:L", wantErr: require.Error},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			metrics, err := ParseMetrics(test.metrics)
+			require.NoError(t, err)
+
+			got, err := AdjustedScore(test.vector, metrics)
+			test.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestParseMetrics(t *testing.T) {
+	metrics, err := ParseMetrics("CVSS:3.1/e:u/MAV:L/CR:H")
+	require.NoError(t, err)
+	assert.Equal(t, Metrics{"E": "U", "MAV": "L", "CR": "H"}, metrics)
+	assert.Equal(t, "E:U/CR:H/MAV:L", metrics.String())
+
+	_, err = ParseMetrics("AV:N")
+	require.ErrorContains(t, err, "only temporal and environmental metrics")
+
+	_, err = ParseMetrics("E:Z")
+	require.ErrorContains(t, err, "invalid value")
+}
+
+func TestRating(t *testing.T) {
+	assert.Equal(t, vulnerability.CriticalSeverity, Rating(9.0))
+	assert.Equal(t, vulnerability.HighSeverity, Rating(8.9))
+	assert.Equal(t, vulnerability.MediumSeverity, Rating(4.0))
+	assert.Equal(t, vulnerability.LowSeverity, Rating(0.1))
+	assert.Equal(t, vulnerability.NegligibleSeverity, Rating(0))
+}
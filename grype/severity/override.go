@@ -0,0 +1,307 @@
+/*
+Package severity applies the severity ratings of an organization in place of the ratings from the vulnerability
+data. Security teams routinely re-rate vulnerabilities for their environment (e.g. a critical vulnerability in a
+network service that is never exposed); with an override file these ratings are used for both gating (--fail-on)
+and presentation, while the original rating is retained in the output.
+*/
+package severity
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/packageurl-go"
+)
+
+// Override re-rates a vulnerability, either everywhere or only for the package described by a package URL.
+type Override struct {
+	// Vulnerability is the ID of the vulnerability to re-rate, which also applies to matches of other vulnerabilities
+	// that are related to it (e.g. a GHSA advisory for the CVE).
+	Vulnerability string `yaml:"vulnerability" json:"vulnerability"`
+
+	// PURL optionally restricts the override to a package. Qualifiers are ignored, and a package URL without a
+	// version applies to all versions of the package.
+	PURL string `yaml:"purl,omitempty" json:"purl,omitempty"`
+
+	// Severity is the severity to use instead of the severity from the vulnerability data.
+	Severity string `yaml:"severity,omitempty" json:"severity,omitempty"`
+
+	// CVSS are temporal and environmental CVSS v3 metrics (e.g. "E:U/MAV:L") to adjust the CVSS score of the
+	// vulnerability with, instead of giving the severity. These take precedence over the metrics for all matches.
+	CVSS string `yaml:"cvss,omitempty" json:"cvss,omitempty"`
+
+	// Reason explains why the vulnerability was re-rated.
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// document is the override file, which is either a list of overrides or an object with an "overrides" list
+type document struct {
+	Overrides []Override `yaml:"overrides"`
+}
+
+// Overrides is a collection of severity overrides indexed by vulnerability ID, along with the CVSS metrics that
+// adjust the score (and so the severity) of every match.
+type Overrides struct {
+	byVulnerability map[string][]override
+	metrics         Metrics
+}
+
+type override struct {
+	Override
+	purl    *packageurl.PackageURL
+	metrics Metrics
+}
+
+// New returns the collection of the given overrides, validating their severities and package URLs.
+func New(overrides ...Override) (*Overrides, error) {
+	o := &Overrides{byVulnerability: make(map[string][]override)}
+	for _, ov := range overrides {
+		if err := o.add(ov); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// FromFiles reads the overrides from the given YAML or JSON files, where overrides from later files take precedence.
+func FromFiles(paths ...string) (*Overrides, error) {
+	o, _ := New()
+	for _, path := range paths {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read severity overrides: %w", err)
+		}
+		overrides, err := parse(contents)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse severity overrides from %q: %w", path, err)
+		}
+		for _, ov := range overrides {
+			if err := o.add(ov); err != nil {
+				return nil, fmt.Errorf("invalid severity override in %q: %w", path, err)
+			}
+		}
+	}
+	return o, nil
+}
+
+func parse(contents []byte) ([]Override, error) {
+	// YAML is a superset of JSON, so both forms are read the same way
+	var list []Override
+	if err := yaml.Unmarshal(contents, &list); err == nil {
+		return list, nil
+	}
+	var doc document
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Overrides, nil
+}
+
+func (o *Overrides) add(ov Override) error {
+	ov.Vulnerability = strings.TrimSpace(ov.Vulnerability)
+	if ov.Vulnerability == "" {
+		return fmt.Errorf("no vulnerability given for override to %q", ov.Severity)
+	}
+	entry := override{Override: ov}
+	switch {
+	case ov.Severity != "":
+		sev := vulnerability.ParseSeverity(strings.TrimSpace(ov.Severity))
+		if sev == vulnerability.UnknownSeverity {
+			return fmt.Errorf("unknown severity %q for %s (options: %v)", ov.Severity, ov.Vulnerability, vulnerability.AllSeverities())
+		}
+		entry.Severity = sev.Name()
+	case ov.CVSS != "":
+		metrics, err := ParseMetrics(ov.CVSS)
+		if err != nil {
+			return fmt.Errorf("invalid CVSS metrics for %s: %w", ov.Vulnerability, err)
+		}
+		entry.metrics = metrics
+	default:
+		return fmt.Errorf("no severity or CVSS metrics given for the override of %s", ov.Vulnerability)
+	}
+
+	if ov.PURL != "" {
+		p, err := packageurl.FromString(ov.PURL)
+		if err != nil {
+			return fmt.Errorf("invalid package URL %q for %s: %w", ov.PURL, ov.Vulnerability, err)
+		}
+		entry.purl = &p
+	}
+
+	id := strings.ToLower(ov.Vulnerability)
+	// overrides added later take precedence, so they are looked at first
+	o.byVulnerability[id] = append([]override{entry}, o.byVulnerability[id]...)
+	return nil
+}
+
+// SetMetrics sets the temporal and environmental CVSS metrics that adjust the score, and so the severity, of every
+// match with a CVSS v3 vector (which overrides for a vulnerability take precedence over).
+func (o *Overrides) SetMetrics(metrics Metrics) {
+	o.metrics = metrics
+}
+
+// IsEmpty returns true if there are no overrides or metrics to apply.
+func (o *Overrides) IsEmpty() bool {
+	return o.Len() == 0 && (o == nil || len(o.metrics) == 0)
+}
+
+// Len returns the number of overrides.
+func (o *Overrides) Len() int {
+	if o == nil {
+		return 0
+	}
+	var n int
+	for _, entries := range o.byVulnerability {
+		n += len(entries)
+	}
+	return n
+}
+
+// Find returns the override for the given match, if any. An override for the package of the match takes precedence
+// over an override for the vulnerability, and an override for the vulnerability of the match takes precedence over
+// one for a related vulnerability.
+func (o *Overrides) Find(m match.Match) *Override {
+	if e := o.find(m); e != nil {
+		return &e.Override
+	}
+	return nil
+}
+
+func (o *Overrides) find(m match.Match) *override {
+	if o.Len() == 0 {
+		return nil
+	}
+
+	ids := []string{m.Vulnerability.ID}
+	for _, r := range m.Vulnerability.RelatedVulnerabilities {
+		ids = append(ids, r.ID)
+	}
+
+	var fallback *override
+	for _, id := range ids {
+		entries := o.byVulnerability[strings.ToLower(id)]
+		for i := range entries {
+			if entries[i].purl == nil {
+				if fallback == nil {
+					fallback = &entries[i]
+				}
+				continue
+			}
+			if purlMatches(*entries[i].purl, m.Package.PURL) {
+				return &entries[i]
+			}
+		}
+	}
+	return fallback
+}
+
+// Apply returns the matches with the severity override set on each match that has one, where the metadata provider
+// supplies the CVSS vectors to adjust with CVSS metrics.
+func (o *Overrides) Apply(matches match.Matches, provider vulnerability.MetadataProvider) match.Matches {
+	if o.IsEmpty() {
+		return matches
+	}
+	out := match.NewMatches()
+	for _, m := range matches.Sorted() {
+		out.Add(o.apply(m, provider))
+	}
+	return out
+}
+
+// ApplyIgnored sets the severity override on each ignored match that has one, so suppressed matches are presented
+// with the same ratings.
+func (o *Overrides) ApplyIgnored(ignored []match.IgnoredMatch, provider vulnerability.MetadataProvider) []match.IgnoredMatch {
+	if o.IsEmpty() {
+		return ignored
+	}
+	out := make([]match.IgnoredMatch, len(ignored))
+	for i, m := range ignored {
+		m.Match = o.apply(m.Match, provider)
+		out[i] = m
+	}
+	return out
+}
+
+func (o *Overrides) apply(m match.Match, provider vulnerability.MetadataProvider) match.Match {
+	found := o.find(m)
+	if found != nil && found.Severity != "" {
+		m.SeverityOverride = &match.SeverityOverride{Severity: found.Severity, Reason: found.Reason}
+		return m
+	}
+
+	metrics := o.metrics
+	if found != nil {
+		metrics = metrics.Merge(found.metrics)
+	}
+	if len(metrics) == 0 {
+		return m
+	}
+
+	cvss := cvssToAdjust(m, provider)
+	if cvss == nil {
+		return m
+	}
+	score, err := AdjustedScore(cvss.Vector, metrics)
+	if err != nil {
+		log.WithFields("vulnerability", m.Vulnerability.ID, "vector", cvss.Vector).Debugf("unable to adjust CVSS score: %v", err)
+		return m
+	}
+
+	reason := fmt.Sprintf("CVSS score adjusted from %.1f to %.1f with %s", cvss.Metrics.BaseScore, score, metrics)
+	if found != nil && found.Reason != "" {
+		reason = fmt.Sprintf("%s (%s)", found.Reason, reason)
+	}
+	m.SeverityOverride = &match.SeverityOverride{Severity: Rating(score).Name(), Reason: reason, Score: &score}
+	return m
+}
+
+// cvssToAdjust returns the CVSS v3 score of the match vulnerability to adjust, preferring the scores of the vendor
+// (the namespace of the match) over those of the related NVD records, the same way reports choose a CVSS score.
+func cvssToAdjust(m match.Match, provider vulnerability.MetadataProvider) *vulnerability.Cvss {
+	if provider == nil {
+		return nil
+	}
+	refs := append([]vulnerability.Reference{{ID: m.Vulnerability.ID, Namespace: m.Vulnerability.Namespace}}, m.Vulnerability.RelatedVulnerabilities...)
+
+	var all []*vulnerability.Metadata
+	for _, ref := range refs {
+		metadata, err := provider.GetMetadata(ref.ID, ref.Namespace)
+		if err == nil && metadata != nil {
+			all = append(all, metadata)
+		}
+	}
+
+	for _, vendor := range []bool{true, false} {
+		for _, metadata := range all {
+			if vendor && metadata.Namespace == "nvd:cpe" {
+				continue
+			}
+			for i, cvss := range metadata.Cvss {
+				if strings.HasPrefix(cvss.Vector, "CVSS:3.") {
+					return &metadata.Cvss[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func purlMatches(want packageurl.PackageURL, purl string) bool {
+	if purl == "" {
+		return false
+	}
+	got, err := packageurl.FromString(purl)
+	if err != nil {
+		return false
+	}
+	if !strings.EqualFold(want.Type, got.Type) || !strings.EqualFold(want.Namespace, got.Namespace) || want.Name != got.Name {
+		return false
+	}
+	return want.Version == "" || want.Version == got.Version
+}
@@ -0,0 +1,149 @@
+package severity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+func newMatch(vulnID, purl string, related ...string) match.Match {
+	m := match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: vulnID, Namespace: "github:language:javascript"},
+		Package:       pkg.Package{ID: pkg.ID(purl), Name: "lodash", PURL: purl},
+	}
+	for _, r := range related {
+		m.Vulnerability.RelatedVulnerabilities = append(m.Vulnerability.RelatedVulnerabilities, vulnerability.Reference{ID: r, Namespace: "nvd:cpe"})
+	}
+	return m
+}
+
+func TestOverrides_Find(t *testing.T) {
+	overrides, err := FromFiles("test-fixtures/overrides.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, 3, overrides.Len())
+
+	tests := []struct {
+		name  string
+		match match.Match
+		want  string
+	}{
+		{name: "by package", match: newMatch("CVE-2023-0001", "pkg:npm/lodash@4.17.21?arch=any"), want: "Critical"},
+		{name: "by vulnerability", match: newMatch("CVE-2023-0001", "pkg:npm/minimist@1.2.5"), want: "Low"},
+		{name: "by related vulnerability", match: newMatch("GHSA-xxxx-yyyy-zzzz", "pkg:npm/minimist@1.2.5", "CVE-2023-0001"), want: "Low"},
+		{name: "by package version", match: newMatch("CVE-2023-0002", "pkg:npm/lodash@4.17.20"), want: "Negligible"},
+		{name: "other package version", match: newMatch("CVE-2023-0002", "pkg:npm/lodash@4.17.21")},
+		{name: "no override", match: newMatch("CVE-2023-0003", "pkg:npm/lodash@4.17.21")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := overrides.Find(test.match)
+			if test.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, test.want, got.Severity)
+		})
+	}
+}
+
+func TestFromFiles(t *testing.T) {
+	// later files take precedence
+	overrides, err := FromFiles("test-fixtures/overrides.yaml", "test-fixtures/overrides.json")
+	require.NoError(t, err)
+	got := overrides.Find(newMatch("CVE-2023-0001", "pkg:npm/minimist@1.2.5"))
+	require.NotNil(t, got)
+	assert.Equal(t, "Medium", got.Severity)
+	assert.Equal(t, "re-rated", got.Reason)
+
+	_, err = FromFiles("test-fixtures/does-not-exist.yaml")
+	require.Error(t, err)
+
+	_, err = New(Override{Vulnerability: "CVE-2023-0001", Severity: "severe"})
+	require.ErrorContains(t, err, "unknown severity")
+
+	_, err = New(Override{Vulnerability: "CVE-2023-0001", PURL: "lodash", Severity: "low"})
+	require.ErrorContains(t, err, "invalid package URL")
+}
+
+func TestOverrides_Apply(t *testing.T) {
+	overrides, err := FromFiles("test-fixtures/overrides.yaml")
+	require.NoError(t, err)
+
+	matches := overrides.Apply(match.NewMatches(
+		newMatch("CVE-2023-0001", "pkg:npm/lodash@4.17.21"),
+		newMatch("CVE-2023-0003", "pkg:npm/lodash@4.17.21"),
+	), nil)
+	sorted := matches.Sorted()
+	require.Len(t, sorted, 2)
+	assert.Equal(t, &match.SeverityOverride{Severity: "Critical", Reason: "exposed through the public API"}, sorted[0].SeverityOverride)
+	assert.Nil(t, sorted[1].SeverityOverride)
+
+	metadata := sorted[0].WithSeverityOverride(&vulnerability.Metadata{ID: "CVE-2023-0001", Severity: "Medium"})
+	assert.Equal(t, "Critical", metadata.Severity)
+	assert.Equal(t, "Medium", metadata.OriginalSeverity)
+	assert.Equal(t, "exposed through the public API", metadata.SeverityOverrideReason)
+
+	ignored := overrides.ApplyIgnored([]match.IgnoredMatch{{Match: newMatch("CVE-2023-0001", "pkg:npm/minimist@1.2.5")}}, nil)
+	assert.Equal(t, "Low", ignored[0].SeverityOverride.Severity)
+}
+
+type metadataProvider map[string]*vulnerability.Metadata
+
+func (p metadataProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	return p[namespace+"/"+id], nil
+}
+
+func TestOverrides_Apply_cvssMetrics(t *testing.T) {
+	provider := metadataProvider{
+		"nvd:cpe/CVE-2023-0001": {
+			ID:        "CVE-2023-0001",
+			Namespace: "nvd:cpe",
+			Severity:  "Critical",
+			Cvss: []vulnerability.Cvss{
+				{Version: "3.1", Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", Metrics: vulnerability.CvssMetrics{BaseScore: 9.8}},
+			},
+		},
+	}
+
+	overrides, err := New(Override{Vulnerability: "CVE-2023-0001", PURL: "pkg:npm/lodash", CVSS: "MAV:L", Reason: "only reachable locally"})
+	require.NoError(t, err)
+	overrides.SetMetrics(Metrics{"E": "U"})
+
+	ghsa := newMatch("GHSA-xxxx-yyyy-zzzz", "pkg:npm/minimist@1.2.5", "CVE-2023-0001")
+	lodash := newMatch("GHSA-xxxx-yyyy-zzzz", "pkg:npm/lodash@4.17.21", "CVE-2023-0001")
+	unscored := newMatch("CVE-2023-0002", "pkg:npm/lodash@4.17.21")
+	matches := overrides.Apply(match.NewMatches(ghsa, lodash, unscored), provider)
+	sorted := matches.Sorted()
+	require.Len(t, sorted, 3)
+
+	byPURL := make(map[string]*match.SeverityOverride)
+	for _, m := range sorted {
+		byPURL[m.Package.PURL+" "+m.Vulnerability.ID] = m.SeverityOverride
+	}
+
+	// the metrics for all matches apply through the related NVD record
+	got := byPURL["pkg:npm/minimist@1.2.5 GHSA-xxxx-yyyy-zzzz"]
+	require.NotNil(t, got)
+	assert.Equal(t, "Critical", got.Severity)
+	assert.Equal(t, 9.0, *got.Score)
+	assert.Equal(t, "CVSS score adjusted from 9.8 to 9.0 with E:U", got.Reason)
+
+	// the metrics of the override are combined with the metrics for all matches
+	got = byPURL["pkg:npm/lodash@4.17.21 GHSA-xxxx-yyyy-zzzz"]
+	require.NotNil(t, got)
+	assert.Equal(t, "High", got.Severity)
+	assert.Equal(t, 7.7, *got.Score)
+	assert.Equal(t, "only reachable locally (CVSS score adjusted from 9.8 to 7.7 with E:U/MAV:L)", got.Reason)
+
+	// without a CVSS v3 vector there is nothing to adjust
+	assert.Nil(t, byPURL["pkg:npm/lodash@4.17.21 CVE-2023-0002"])
+
+	_, err = New(Override{Vulnerability: "CVE-2023-0001"})
+	require.ErrorContains(t, err, "no severity or CVSS metrics")
+}
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/grypeerr"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/vulnerability"
 )
@@ -9,4 +11,45 @@ type Store struct {
 	vulnerability.Provider
 	vulnerability.MetadataProvider
 	match.ExclusionProvider
+
+	// Targeting supplies the per-distro defaults for which package names advisories are matched against (optional).
+	Targeting match.TargetingProvider
+}
+
+// DegradationReporter is implemented by the providers that may serve incomplete data, such as a provider querying an
+// online source that could not be reached, and by the providers decorating them (which report the degradations of the
+// providers they decorate).
+type DegradationReporter interface {
+	// Degradations returns the degradations of the data served so far.
+	Degradations() []grypeerr.DataQualityProblem
+}
+
+// GetTargeting returns the default targeting of the distro from the vulnerability DB, if any.
+func (s Store) GetTargeting(d *distro.Distro) (match.Targeting, error) {
+	if s.Targeting == nil {
+		return match.DefaultTargeting, nil
+	}
+	return s.Targeting.GetTargeting(d)
+}
+
+// Degradations returns the degradations of the data served by the vulnerability and metadata providers of the store.
+func (s Store) Degradations() []grypeerr.DataQualityProblem {
+	var problems []grypeerr.DataQualityProblem
+	seen := make(map[grypeerr.DataQualityProblem]bool)
+	// the providers are often one and the same
+	for _, p := range append(DegradationsOf(s.Provider), DegradationsOf(s.MetadataProvider)...) {
+		if !seen[p] {
+			seen[p] = true
+			problems = append(problems, p)
+		}
+	}
+	return problems
+}
+
+// DegradationsOf returns the degradations of the data served by the provider, if it reports any.
+func DegradationsOf(provider any) []grypeerr.DataQualityProblem {
+	if r, ok := provider.(DegradationReporter); ok {
+		return r.Degradations()
+	}
+	return nil
 }
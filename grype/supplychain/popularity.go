@@ -0,0 +1,110 @@
+package supplychain
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/anchore/grype/internal/dataset"
+)
+
+// PopularityFileName is the file of the popularity list within the supply-chain datasets of the DB.
+const PopularityFileName = "popular-packages.json"
+
+var pythonSeparators = regexp.MustCompile(`[-_.]+`)
+
+// Popularity is the list of the most popular package names of each OSV ecosystem (e.g. "npm" or "PyPI"), from the
+// most popular down. It is read from JSON objects of the form {"npm": ["react", "lodash"], "PyPI": ["requests"]}.
+type Popularity struct {
+	ecosystems map[string][]string
+	known      map[string]map[string]bool
+}
+
+func NewPopularity() *Popularity {
+	return &Popularity{
+		ecosystems: make(map[string][]string),
+		known:      make(map[string]map[string]bool),
+	}
+}
+
+// Len returns the number of package names in the list.
+func (p *Popularity) Len() int {
+	if p == nil {
+		return 0
+	}
+	n := 0
+	for _, names := range p.ecosystems {
+		n += len(names)
+	}
+	return n
+}
+
+// Add appends the names to the list of the ecosystem, below the names already listed.
+func (p *Popularity) Add(ecosystem string, names ...string) {
+	key := strings.ToLower(ecosystem)
+	if p.known[key] == nil {
+		p.known[key] = make(map[string]bool)
+	}
+	for _, name := range names {
+		normalized := normalizeName(key, name)
+		if normalized == "" || p.known[key][normalized] {
+			continue
+		}
+		p.known[key][normalized] = true
+		p.ecosystems[key] = append(p.ecosystems[key], normalized)
+	}
+}
+
+// Merge appends the names of the other list to this list.
+func (p *Popularity) Merge(other *Popularity) {
+	if other == nil {
+		return
+	}
+	for ecosystem, names := range other.ecosystems {
+		p.Add(ecosystem, names...)
+	}
+}
+
+// ParsePopularity reads a popularity list.
+func ParsePopularity(data []byte) (*Popularity, error) {
+	var ecosystems map[string][]string
+	if err := json.Unmarshal(data, &ecosystems); err != nil {
+		return nil, fmt.Errorf("unable to parse popularity list: %w", err)
+	}
+	p := NewPopularity()
+	for ecosystem, names := range ecosystems {
+		p.Add(ecosystem, names...)
+	}
+	return p, nil
+}
+
+// popularitySidecar is the popularity list within the supply-chain datasets of the DB.
+var popularitySidecar = dataset.Sidecar[*Popularity]{
+	Kind:  "popularity list",
+	Path:  filepath.Join(DBDirName, PopularityFileName),
+	Parse: ParsePopularity,
+	New:   NewPopularity,
+}
+
+// PopularityFromPath reads a popularity list from a single JSON file or from every JSON file within a directory.
+func PopularityFromPath(path string) (*Popularity, error) {
+	return popularitySidecar.FromPath(path)
+}
+
+// PopularityFromDBDir reads the popularity list shipped within the given DB directory, if there is one. A nil list
+// (and no error) is returned when the DB does not ship a list.
+func PopularityFromDBDir(dir string) (*Popularity, error) {
+	return popularitySidecar.FromDBDir(dir)
+}
+
+// normalizeName returns the name as compared within the ecosystem: case-insensitive, and for PyPI with runs of
+// separators folded (as in PEP 503), since such names are the same package.
+func normalizeName(ecosystem, name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if ecosystem == "pypi" {
+		name = pythonSeparators.ReplaceAllString(name, "-")
+	}
+	return name
+}
@@ -0,0 +1,55 @@
+package supplychain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPopularityFromDBDir(t *testing.T) {
+	p, err := PopularityFromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+	assert.Equal(t, 12, p.Len())
+	assert.Equal(t, []string{"requests", "urllib3", "python-dateutil", "numpy", "colorama"}, p.ecosystems["pypi"])
+
+	p, err = PopularityFromDBDir(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, p)
+	assert.Zero(t, p.Len())
+	assert.Nil(t, p.Typosquats(nil))
+}
+
+func TestPopularity_Merge(t *testing.T) {
+	p, err := PopularityFromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+	extra, err := PopularityFromPath("test-fixtures/extra.json")
+	require.NoError(t, err)
+
+	p.Merge(extra)
+	// lodash is already listed, and keeps its rank
+	assert.Equal(t, []string{"react", "lodash", "express", "chalk", "commander", "@babel/core", "vue", "webpack"}, p.ecosystems["npm"])
+	assert.Equal(t, []string{"serde"}, p.ecosystems["crates.io"])
+}
+
+func TestPopularityFromPath(t *testing.T) {
+	p, err := PopularityFromPath("test-fixtures")
+	require.NoError(t, err)
+	assert.Equal(t, 3, p.Len(), "only the JSON files directly within the directory are read")
+
+	path := filepath.Join(t.TempDir(), "bad.json")
+	require.NoError(t, os.WriteFile(path, []byte(`["lodash"]`), 0o600))
+	_, err = PopularityFromPath(path)
+	assert.ErrorContains(t, err, "unable to parse popularity list")
+
+	_, err = PopularityFromPath("test-fixtures/missing.json")
+	assert.ErrorContains(t, err, "unable to read popularity list")
+}
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "python-dateutil", normalizeName("pypi", "Python_DateUtil"))
+	assert.Equal(t, "zope-interface", normalizeName("pypi", "zope..interface"))
+	assert.Equal(t, "lodash_es", normalizeName("npm", "Lodash_ES"))
+}
@@ -0,0 +1,199 @@
+package supplychain
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/anchore/grype/grype/osv"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/internal/dataset"
+)
+
+// StatusFileName is the file of the package status records within the supply-chain datasets of the DB.
+const StatusFileName = "package-status.json"
+
+const (
+	// Deprecated is a package (or versions of it) deprecated by its maintainers, such as with the npm deprecated flag.
+	Deprecated Kind = "deprecated"
+	// Yanked is a version withdrawn from its registry, such as a yanked PyPI release.
+	Yanked Kind = "yanked"
+	// Archived is a package whose source repository is archived, and so no longer maintained.
+	Archived Kind = "archived"
+)
+
+// PackageStatus is the maintenance status of a package of an OSV ecosystem (e.g. "npm" or "PyPI"), from the metadata
+// of the ecosystem.
+type PackageStatus struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Status    Kind   `json:"status"`
+	// Versions are the versions with the status, or empty for every version of the package.
+	Versions []string `json:"versions,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+func (s PackageStatus) appliesTo(version string) bool {
+	if len(s.Versions) == 0 {
+		return true
+	}
+	for _, v := range s.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// describe returns the status as a message, e.g. "the package is deprecated: use got instead".
+func (s PackageStatus) describe() string {
+	var message string
+	switch s.Status {
+	case Yanked:
+		message = "the version was yanked from the registry"
+	case Archived:
+		message = "the source repository of the package is archived"
+	default:
+		subject := "the package"
+		if len(s.Versions) > 0 {
+			subject = "the version"
+		}
+		message = fmt.Sprintf("%s is %s", subject, s.Status)
+	}
+	if s.Reason != "" {
+		message += ": " + s.Reason
+	}
+	return message
+}
+
+// Statuses are the package status records, keyed by ecosystem and name.
+type Statuses struct {
+	records map[string][]PackageStatus
+}
+
+func NewStatuses() *Statuses {
+	return &Statuses{
+		records: make(map[string][]PackageStatus),
+	}
+}
+
+// Len returns the number of records.
+func (s *Statuses) Len() int {
+	if s == nil {
+		return 0
+	}
+	n := 0
+	for _, records := range s.records {
+		n += len(records)
+	}
+	return n
+}
+
+// Add inserts the records, ignoring those without a name or status.
+func (s *Statuses) Add(records ...PackageStatus) {
+	for _, r := range records {
+		if r.Name == "" || r.Status == "" {
+			continue
+		}
+		key := statusKey(r.Ecosystem, r.Name)
+		s.records[key] = append(s.records[key], r)
+	}
+}
+
+// Merge adds all records of the other statuses to these statuses.
+func (s *Statuses) Merge(other *Statuses) {
+	if other == nil {
+		return
+	}
+	for _, records := range other.records {
+		s.Add(records...)
+	}
+}
+
+// Lookup returns the statuses applying to the version of the package, if the package is within an OSV ecosystem.
+func (s *Statuses) Lookup(p pkg.Package) []PackageStatus {
+	if s.Len() == 0 {
+		return nil
+	}
+	q, ok := osv.PackageQuery(p)
+	if !ok {
+		return nil
+	}
+	var statuses []PackageStatus
+	for _, r := range s.records[statusKey(q.Package.Ecosystem, q.Package.Name)] {
+		if r.appliesTo(p.Version) {
+			statuses = append(statuses, r)
+		}
+	}
+	return statuses
+}
+
+// Annotate returns the statuses of each of the packages with any status.
+func (s *Statuses) Annotate(packages []pkg.Package) map[pkg.ID][]PackageStatus {
+	if s.Len() == 0 {
+		return nil
+	}
+	annotations := make(map[pkg.ID][]PackageStatus)
+	for _, p := range packages {
+		if statuses := s.Lookup(p); len(statuses) > 0 {
+			annotations[p.ID] = statuses
+		}
+	}
+	return annotations
+}
+
+// Abandoned returns a warning for each status of the packages that are not vulnerable (those without any match), so
+// that abandoned dependencies are noticed before they become a problem.
+func (s *Statuses) Abandoned(packages []pkg.Package, vulnerable map[pkg.ID]bool) []Warning {
+	var warnings []Warning
+	for _, p := range packages {
+		if vulnerable[p.ID] {
+			continue
+		}
+		for _, status := range s.Lookup(p) {
+			warnings = append(warnings, Warning{
+				Package: p,
+				Kind:    status.Status,
+				Message: status.describe(),
+			})
+		}
+	}
+	SortWarnings(warnings)
+	return warnings
+}
+
+// ParseStatuses reads package status records, as an array of records.
+func ParseStatuses(data []byte) (*Statuses, error) {
+	var records []PackageStatus
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("unable to parse package statuses: %w", err)
+	}
+	s := NewStatuses()
+	s.Add(records...)
+	return s, nil
+}
+
+// statusSidecar is the package status records within the supply-chain datasets of the DB.
+var statusSidecar = dataset.Sidecar[*Statuses]{
+	Kind:  "package statuses",
+	Path:  filepath.Join(DBDirName, StatusFileName),
+	Parse: ParseStatuses,
+	New:   NewStatuses,
+}
+
+// StatusesFromPath reads package status records from a single JSON file or from every JSON file within a directory.
+func StatusesFromPath(path string) (*Statuses, error) {
+	return statusSidecar.FromPath(path)
+}
+
+// StatusesFromDBDir reads the package status records shipped within the given DB directory, if there are any. Nil
+// statuses (and no error) are returned when the DB does not ship any.
+func StatusesFromDBDir(dir string) (*Statuses, error) {
+	return statusSidecar.FromDBDir(dir)
+}
+
+func statusKey(ecosystem, name string) string {
+	ecosystem = strings.ToLower(ecosystem)
+	return ecosystem + "/" + normalizeName(ecosystem, name)
+}
@@ -0,0 +1,88 @@
+package supplychain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestStatusesFromDBDir(t *testing.T) {
+	s, err := StatusesFromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+	assert.Equal(t, 4, s.Len(), "the record without a status is ignored")
+
+	s, err = StatusesFromDBDir(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	assert.Nil(t, s.Annotate([]pkg.Package{{Name: "request"}}))
+}
+
+func TestStatusesFromPath(t *testing.T) {
+	s, err := StatusesFromPath("test-fixtures/db/supply-chain/package-status.json")
+	require.NoError(t, err)
+	assert.Equal(t, 4, s.Len())
+
+	path := filepath.Join(t.TempDir(), "bad.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"name": "request"}`), 0o600))
+	_, err = StatusesFromPath(path)
+	assert.ErrorContains(t, err, "unable to parse package statuses")
+
+	_, err = StatusesFromPath("test-fixtures/missing.json")
+	assert.ErrorContains(t, err, "unable to read package statuses")
+}
+
+func TestStatuses_Lookup(t *testing.T) {
+	s, err := StatusesFromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+
+	request := pkg.Package{Name: "request", Version: "2.88.2", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript}
+	statuses := s.Lookup(request)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, Deprecated, statuses[0].Status)
+	assert.Equal(t, Archived, statuses[1].Status)
+
+	yanked := pkg.Package{Name: "django-extra", Version: "2.0.1", Type: syftPkg.PythonPkg, Language: syftPkg.Python}
+	statuses = s.Lookup(yanked)
+	require.Len(t, statuses, 1, "PyPI names are normalized")
+	assert.Equal(t, Yanked, statuses[0].Status)
+
+	yanked.Version = "2.0.2"
+	assert.Empty(t, s.Lookup(yanked), "only the listed versions are yanked")
+
+	gem := pkg.Package{Name: "request", Version: "1.0.0", Type: syftPkg.GemPkg, Language: syftPkg.Ruby}
+	assert.Empty(t, s.Lookup(gem), "another ecosystem")
+}
+
+func TestStatuses_Abandoned(t *testing.T) {
+	s, err := StatusesFromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+
+	request := pkg.Package{ID: "request", Name: "request", Version: "2.88.2", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript}
+	leftPad := pkg.Package{ID: "left-pad", Name: "left-pad", Version: "1.3.0", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript}
+	django := pkg.Package{ID: "django", Name: "Django_Extra", Version: "2.0.1", Type: syftPkg.PythonPkg, Language: syftPkg.Python}
+	packages := []pkg.Package{request, leftPad, django}
+
+	assert.Len(t, s.Annotate(packages), 3)
+
+	warnings := s.Abandoned(packages, map[pkg.ID]bool{"django": true})
+	var got []string
+	for _, w := range warnings {
+		got = append(got, w.Package.Name+" ["+string(w.Kind)+"] "+w.Message)
+	}
+	assert.Equal(t, []string{
+		"left-pad [deprecated] the version is deprecated",
+		"request [archived] the source repository of the package is archived: github.com/request/request",
+		"request [deprecated] the package is deprecated: request has been deprecated, see https://github.com/request/request/issues/3142",
+	}, got)
+}
+
+func TestPackageStatus_describe(t *testing.T) {
+	assert.Equal(t, "the version was yanked from the registry: broken release", PackageStatus{Status: Yanked, Reason: "broken release"}.describe())
+	assert.Equal(t, "the package is unmaintained", PackageStatus{Status: "unmaintained"}.describe())
+}
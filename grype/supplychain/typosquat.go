@@ -0,0 +1,116 @@
+package supplychain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/grype/osv"
+	"github.com/anchore/grype/grype/pkg"
+)
+
+// minTyposquatLength is the length of the shortest popular name that is checked, since nearly every short name is
+// within one edit of some short popular name.
+const minTyposquatLength = 5
+
+// Typosquats returns a warning for each language package whose name is a small edit-distance variant of a popular
+// package of the same ecosystem (and is not itself a popular package), naming the most popular of the closest names.
+func (p *Popularity) Typosquats(packages []pkg.Package) []Warning {
+	if p.Len() == 0 {
+		return nil
+	}
+
+	var warnings []Warning
+	for _, pk := range packages {
+		q, ok := osv.PackageQuery(pk)
+		if !ok {
+			continue
+		}
+		ecosystem := strings.ToLower(q.Package.Ecosystem)
+		name := normalizeName(ecosystem, q.Package.Name)
+		if p.known[ecosystem][name] {
+			continue
+		}
+
+		similar, distance := p.closest(ecosystem, name)
+		if similar == "" {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Package: pk,
+			Kind:    Typosquat,
+			Message: fmt.Sprintf("the name is %d edit(s) from the popular %s package %q", distance, q.Package.Ecosystem, similar),
+		})
+	}
+
+	SortWarnings(warnings)
+	return warnings
+}
+
+// closest returns the most popular name of the ecosystem within the allowed distance of the name, preferring the
+// nearest names.
+func (p *Popularity) closest(ecosystem, name string) (string, int) {
+	var best string
+	bestDistance := 0
+	for _, popular := range p.ecosystems[ecosystem] {
+		limit := maxDistance(popular)
+		if limit == 0 || abs(len(popular)-len(name)) > limit {
+			continue
+		}
+		d := editDistance(name, popular)
+		if d == 0 || d > limit {
+			continue
+		}
+		if best == "" || d < bestDistance {
+			best, bestDistance = popular, d
+		}
+	}
+	return best, bestDistance
+}
+
+// maxDistance returns the largest edit distance from the popular name that is considered a variant of it.
+func maxDistance(popular string) int {
+	switch n := len(popular); {
+	case n < minTyposquatLength:
+		return 0
+	case n < 10:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance returns the optimal string alignment distance between the strings: the number of insertions,
+// deletions, substitutions and transpositions of adjacent characters turning one into the other.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// three rows of the dynamic programming table are needed to account for transpositions
+	previous2 := make([]int, len(rb)+1)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				current[j] = min(current[j], previous2[j-2]+1)
+			}
+		}
+		previous2, previous, current = previous, current, previous2
+	}
+	return previous[len(rb)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
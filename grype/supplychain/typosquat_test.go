@@ -0,0 +1,81 @@
+package supplychain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestPopularity_Typosquats(t *testing.T) {
+	p, err := PopularityFromDBDir("test-fixtures/db")
+	require.NoError(t, err)
+
+	npm := func(name string) pkg.Package {
+		return pkg.Package{Name: name, Version: "1.0.0", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript}
+	}
+	python := func(name string) pkg.Package {
+		return pkg.Package{Name: name, Version: "1.0.0", Type: syftPkg.PythonPkg, Language: syftPkg.Python}
+	}
+
+	packages := []pkg.Package{
+		npm("lodash"),             // popular
+		npm("lodahs"),             // transposition
+		npm("expres"),             // deletion
+		npm("reacts"),             // insertion
+		npm("commanderr"),         // insertion
+		npm("vuex"),               // too short to be checked
+		npm("left-pad"),           // nothing similar
+		python("reqeusts"),        // transposition
+		python("Python_Dateutil"), // the same package as python-dateutil
+		python("python-dateutl"),  // deletion
+		{Name: "lodahs", Version: "1.0.0", Type: syftPkg.GemPkg, Language: syftPkg.Ruby}, // another ecosystem
+	}
+
+	warnings := p.Typosquats(packages)
+	var got []string
+	for _, w := range warnings {
+		assert.Equal(t, Typosquat, w.Kind)
+		got = append(got, w.Package.Name+": "+w.Message)
+	}
+	assert.Equal(t, []string{
+		`commanderr: the name is 1 edit(s) from the popular npm package "commander"`,
+		`expres: the name is 1 edit(s) from the popular npm package "express"`,
+		`lodahs: the name is 1 edit(s) from the popular npm package "lodash"`,
+		`python-dateutl: the name is 1 edit(s) from the popular PyPI package "python-dateutil"`,
+		`reacts: the name is 1 edit(s) from the popular npm package "react"`,
+		`reqeusts: the name is 1 edit(s) from the popular PyPI package "requests"`,
+	}, got)
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "lodash", b: "lodash", want: 0},
+		{a: "lodahs", b: "lodash", want: 1},
+		{a: "lodas", b: "lodash", want: 1},
+		{a: "lodassh", b: "lodash", want: 1},
+		{a: "lodesh", b: "lodash", want: 1},
+		{a: "ldoahs", b: "lodash", want: 2},
+		{a: "", b: "chalk", want: 5},
+		{a: "kitten", b: "sitting", want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, editDistance(tt.a, tt.b))
+			assert.Equal(t, tt.want, editDistance(tt.b, tt.a))
+		})
+	}
+}
+
+func TestMaxDistance(t *testing.T) {
+	assert.Zero(t, maxDistance("react"[:4]))
+	assert.Equal(t, 1, maxDistance("react"))
+	assert.Equal(t, 1, maxDistance("commande"))
+	assert.Equal(t, 2, maxDistance("python-dateutil"))
+}
@@ -0,0 +1,43 @@
+/*
+Package supplychain finds the signs of supply-chain risk in the cataloged packages that are not vulnerabilities, such
+as packages named like popular packages (typosquatting) and deprecated, yanked or archived packages. These are warnings
+to review rather than matches.
+*/
+package supplychain
+
+import (
+	"sort"
+
+	"github.com/anchore/grype/grype/pkg"
+)
+
+// DBDirName is the directory of the optional supply-chain datasets that may be shipped alongside the vulnerability DB.
+const DBDirName = "supply-chain"
+
+// Kind is the kind of supply-chain risk a warning is about.
+type Kind string
+
+const (
+	// Typosquat is a package whose name is a small variation of the name of a popular package.
+	Typosquat Kind = "typosquat"
+)
+
+// Warning is a sign of supply-chain risk about a package.
+type Warning struct {
+	Package pkg.Package
+	Kind    Kind
+	Message string
+}
+
+// SortWarnings sorts the warnings by package name, version and kind, as they are reported.
+func SortWarnings(warnings []Warning) {
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Package.Name != warnings[j].Package.Name {
+			return warnings[i].Package.Name < warnings[j].Package.Name
+		}
+		if warnings[i].Package.Version != warnings[j].Package.Version {
+			return warnings[i].Package.Version < warnings[j].Package.Version
+		}
+		return warnings[i].Kind < warnings[j].Kind
+	})
+}
@@ -0,0 +1,250 @@
+/*
+Package trust weighs the vulnerability data of each provider (e.g. the security tracker of a distro against NVD) by how
+much it is trusted. The weights decide which record of a vulnerability is the one reported when several providers
+report it for the same package (the canonical record, and so the reported severity), and they scale the confidence of
+the matches.
+*/
+package trust
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// DefaultWeight is the weight of the providers that are not given one.
+const DefaultWeight = 1.0
+
+// PackageScope restricts a weight to a kind of packages.
+type PackageScope string
+
+const (
+	// AllPackages applies the weight to every package.
+	AllPackages PackageScope = ""
+	// OSPackages applies the weight to the packages installed by the package manager of the distro (packages without
+	// a language, e.g. deb or rpm packages).
+	OSPackages PackageScope = "os"
+	// LanguagePackages applies the weight to the packages of a language ecosystem (e.g. npm or python packages).
+	LanguagePackages PackageScope = "language"
+)
+
+// Weight is the trust put in the vulnerability data of a provider, as a ratio (from 0 to 1).
+type Weight struct {
+	// Provider is the provider of the vulnerability data, as the first part of its namespace (e.g. "nvd" for
+	// "nvd:cpe", "debian" for "debian:distro:debian:12" or "github" for "github:language:python").
+	Provider string `yaml:"provider" json:"provider" mapstructure:"provider"`
+
+	// Weight is the trust in the provider, from 0 (not trusted) to 1 (fully trusted).
+	Weight float64 `yaml:"weight" json:"weight" mapstructure:"weight"`
+
+	// Packages optionally restricts the weight to the "os" or "language" packages.
+	Packages PackageScope `yaml:"packages,omitempty" json:"packages,omitempty" mapstructure:"packages"`
+}
+
+// Weights are the trust weights of the providers. A nil *Weights weighs every provider the same.
+type Weights struct {
+	weights []Weight
+}
+
+// New returns the given weights, validating their providers, ratios and package scopes. A weight restricted to a kind
+// of packages takes precedence over a weight for all packages.
+func New(weights ...Weight) (*Weights, error) {
+	w := &Weights{}
+	for _, weight := range weights {
+		weight.Provider = strings.ToLower(strings.TrimSpace(weight.Provider))
+		if weight.Provider == "" {
+			return nil, fmt.Errorf("no provider given for the trust weight %v", weight.Weight)
+		}
+		if weight.Weight < 0 || weight.Weight > 1 {
+			return nil, fmt.Errorf("the trust weight of %q must be between 0 and 1, got %v", weight.Provider, weight.Weight)
+		}
+		switch weight.Packages {
+		case AllPackages, OSPackages, LanguagePackages:
+		default:
+			return nil, fmt.Errorf("unknown packages %q for the trust weight of %q (options: %s, %s)", weight.Packages, weight.Provider, OSPackages, LanguagePackages)
+		}
+		w.weights = append(w.weights, weight)
+	}
+	return w, nil
+}
+
+// IsEmpty returns true if no provider was given a weight.
+func (w *Weights) IsEmpty() bool {
+	return w == nil || len(w.weights) == 0
+}
+
+// Of returns the weight of the provider of the namespace for the package.
+func (w *Weights) Of(namespace string, p pkg.Package) float64 {
+	if w.IsEmpty() {
+		return DefaultWeight
+	}
+	provider := Provider(namespace)
+	scope := OSPackages
+	if p.Language != "" {
+		scope = LanguagePackages
+	}
+
+	weight := DefaultWeight
+	for _, candidate := range w.weights {
+		if candidate.Provider != provider {
+			continue
+		}
+		if candidate.Packages == scope {
+			return candidate.Weight
+		}
+		if candidate.Packages == AllPackages {
+			weight = candidate.Weight
+		}
+	}
+	return weight
+}
+
+// Provider returns the provider of the vulnerability data of a namespace (e.g. "nvd" for "nvd:cpe").
+func Provider(namespace string) string {
+	provider, _, _ := strings.Cut(namespace, ":")
+	return strings.ToLower(provider)
+}
+
+// Apply returns the matches weighed by the trust in their providers:
+//   - the matches of the same vulnerability for the same package from several providers (e.g. the debian and NVD
+//     records of a CVE) are deduplicated into the match of the most trusted provider, the other records being kept as
+//     related vulnerabilities
+//   - the severity of a match is the severity of its most trusted record, among the record of the match and its
+//     related records (e.g. the NVD record of a GHSA advisory), as a severity override
+//   - the confidence of the details of a match is scaled by the weight of its provider
+func (w *Weights) Apply(matches match.Matches, provider vulnerability.MetadataProvider) match.Matches {
+	if w.IsEmpty() {
+		return matches
+	}
+	out := match.NewMatches()
+	for _, m := range w.deduplicate(matches.Sorted()) {
+		out.Add(w.apply(m, provider))
+	}
+	return out
+}
+
+// ApplyIgnored weighs each ignored match, so suppressed matches are presented with the same severities.
+func (w *Weights) ApplyIgnored(ignored []match.IgnoredMatch, provider vulnerability.MetadataProvider) []match.IgnoredMatch {
+	if w.IsEmpty() {
+		return ignored
+	}
+	out := make([]match.IgnoredMatch, len(ignored))
+	for i, m := range ignored {
+		m.Match = w.apply(m.Match, provider)
+		out[i] = m
+	}
+	return out
+}
+
+// deduplicate keeps a single match per package and vulnerability ID: the match of the most trusted provider. The
+// matches whose providers are equally trusted are all kept.
+func (w *Weights) deduplicate(matches []match.Match) []match.Match {
+	type key struct {
+		packageID     pkg.ID
+		vulnerability string
+	}
+	groups := make(map[key][]int)
+	var order []key
+	for i, m := range matches {
+		k := key{packageID: m.Package.ID, vulnerability: strings.ToLower(m.Vulnerability.ID)}
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], i)
+	}
+
+	var out []match.Match
+	for _, k := range order {
+		group := groups[k]
+		if len(group) == 1 {
+			out = append(out, matches[group[0]])
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			a, b := matches[group[i]], matches[group[j]]
+			return w.Of(a.Vulnerability.Namespace, a.Package) > w.Of(b.Vulnerability.Namespace, b.Package)
+		})
+		best := matches[group[0]]
+		if w.Of(best.Vulnerability.Namespace, best.Package) == w.Of(matches[group[1]].Vulnerability.Namespace, matches[group[1]].Package) {
+			for _, i := range group {
+				out = append(out, matches[i])
+			}
+			continue
+		}
+		for _, i := range group[1:] {
+			best = fold(best, matches[i])
+		}
+		out = append(out, best)
+	}
+	return out
+}
+
+// fold folds the other match of the same vulnerability into the match: the record of the other match becomes a
+// related vulnerability of the match, and the details of the other match are kept.
+func fold(m match.Match, other match.Match) match.Match {
+	related := append([]vulnerability.Reference(nil), m.Vulnerability.RelatedVulnerabilities...)
+	refs := append([]vulnerability.Reference{{ID: other.Vulnerability.ID, Namespace: other.Vulnerability.Namespace}}, other.Vulnerability.RelatedVulnerabilities...)
+	for _, ref := range refs {
+		if ref.ID == m.Vulnerability.ID && ref.Namespace == m.Vulnerability.Namespace {
+			continue
+		}
+		if !hasReference(related, ref) {
+			related = append(related, ref)
+		}
+	}
+	m.Vulnerability.RelatedVulnerabilities = related
+	m.Details = append(append(match.Details(nil), m.Details...), other.Details...)
+	return m
+}
+
+func hasReference(refs []vulnerability.Reference, ref vulnerability.Reference) bool {
+	for _, r := range refs {
+		if r.ID == ref.ID && r.Namespace == ref.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// apply sets the severity of the most trusted record of the match, unless the severity is already overridden, and
+// scales the confidence of its details.
+func (w *Weights) apply(m match.Match, provider vulnerability.MetadataProvider) match.Match {
+	weight := w.Of(m.Vulnerability.Namespace, m.Package)
+	if weight != DefaultWeight {
+		details := make(match.Details, len(m.Details))
+		for i, d := range m.Details {
+			d.Confidence *= weight
+			details[i] = d
+		}
+		m.Details = details
+	}
+
+	if m.SeverityOverride != nil || provider == nil {
+		return m
+	}
+	own, err := provider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace)
+	if err != nil || own == nil {
+		return m
+	}
+	best, bestWeight := own, weight
+	for _, ref := range m.Vulnerability.RelatedVulnerabilities {
+		related, err := provider.GetMetadata(ref.ID, ref.Namespace)
+		if err != nil || related == nil || related.Severity == "" {
+			continue
+		}
+		if relatedWeight := w.Of(ref.Namespace, m.Package); relatedWeight > bestWeight {
+			best, bestWeight = related, relatedWeight
+		}
+	}
+	if best != own && !strings.EqualFold(best.Severity, own.Severity) {
+		m.SeverityOverride = &match.SeverityOverride{
+			Severity: best.Severity,
+			Reason:   fmt.Sprintf("severity of %s (trust weight %.2f) in place of %s (trust weight %.2f)", best.Namespace, bestWeight, m.Vulnerability.Namespace, weight),
+		}
+	}
+	return m
+}
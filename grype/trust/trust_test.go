@@ -0,0 +1,128 @@
+package trust
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type metadataProvider map[vulnerability.Reference]vulnerability.Metadata
+
+func (m metadataProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	if metadata, ok := m[vulnerability.Reference{ID: id, Namespace: namespace}]; ok {
+		return &metadata, nil
+	}
+	return nil, nil
+}
+
+var (
+	debPackage = pkg.Package{ID: "deb", Name: "openssl", Version: "3.0.11-1", Type: syftPkg.DebPkg}
+	npmPackage = pkg.Package{ID: "npm", Name: "lodash", Version: "4.17.20", Type: syftPkg.NpmPkg, Language: syftPkg.JavaScript}
+)
+
+func newMatch(p pkg.Package, id, namespace string, related ...vulnerability.Reference) match.Match {
+	return match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: id, Namespace: namespace, RelatedVulnerabilities: related},
+		Package:       p,
+		Details:       match.Details{{Type: match.ExactDirectMatch, Matcher: match.StockMatcher, Confidence: 0.8, SearchedBy: namespace}},
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Weight{Provider: "nvd", Weight: 0.5, Packages: OSPackages}, Weight{Provider: "debian", Weight: 1})
+	require.NoError(t, err)
+
+	_, err = New(Weight{Weight: 0.5})
+	assert.ErrorContains(t, err, "no provider")
+
+	_, err = New(Weight{Provider: "nvd", Weight: 2})
+	assert.ErrorContains(t, err, "between 0 and 1")
+
+	_, err = New(Weight{Provider: "nvd", Weight: 0.5, Packages: "binary"})
+	assert.ErrorContains(t, err, `unknown packages "binary"`)
+}
+
+func TestWeights_Of(t *testing.T) {
+	w, err := New(
+		Weight{Provider: "NVD", Weight: 0.9},
+		Weight{Provider: "nvd", Weight: 0.5, Packages: OSPackages},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.5, w.Of("nvd:cpe", debPackage))
+	assert.Equal(t, 0.9, w.Of("nvd:cpe", npmPackage))
+	assert.Equal(t, DefaultWeight, w.Of("debian:distro:debian:12", debPackage))
+
+	var none *Weights
+	assert.Equal(t, DefaultWeight, none.Of("nvd:cpe", debPackage))
+}
+
+func TestWeights_Apply(t *testing.T) {
+	w, err := New(
+		Weight{Provider: "nvd", Weight: 0.5, Packages: OSPackages},
+		Weight{Provider: "github", Weight: 0.8, Packages: LanguagePackages},
+	)
+	require.NoError(t, err)
+
+	provider := metadataProvider{
+		{ID: "CVE-2023-0001", Namespace: "debian:distro:debian:12"}: {ID: "CVE-2023-0001", Namespace: "debian:distro:debian:12", Severity: "Low"},
+		{ID: "CVE-2023-0001", Namespace: "nvd:cpe"}:                 {ID: "CVE-2023-0001", Namespace: "nvd:cpe", Severity: "Critical"},
+		{ID: "GHSA-xxxx-yyyy-zzzz", Namespace: "github:language:javascript"}: {
+			ID: "GHSA-xxxx-yyyy-zzzz", Namespace: "github:language:javascript", Severity: "Medium",
+		},
+		{ID: "CVE-2023-0002", Namespace: "nvd:cpe"}: {ID: "CVE-2023-0002", Namespace: "nvd:cpe", Severity: "High"},
+	}
+
+	matches := match.NewMatches(
+		newMatch(debPackage, "CVE-2023-0001", "debian:distro:debian:12"),
+		newMatch(debPackage, "CVE-2023-0001", "nvd:cpe"),
+		newMatch(npmPackage, "GHSA-xxxx-yyyy-zzzz", "github:language:javascript", vulnerability.Reference{ID: "CVE-2023-0002", Namespace: "nvd:cpe"}),
+	)
+
+	applied := w.Apply(matches, provider)
+	actual := applied.Sorted()
+	require.Len(t, actual, 2)
+
+	// the distro record outweighs the NVD record of the OS package, which is folded into it
+	deb := actual[1]
+	assert.Equal(t, "CVE-2023-0001", deb.Vulnerability.ID)
+	assert.Equal(t, "debian:distro:debian:12", deb.Vulnerability.Namespace)
+	assert.Equal(t, []vulnerability.Reference{{ID: "CVE-2023-0001", Namespace: "nvd:cpe"}}, deb.Vulnerability.RelatedVulnerabilities)
+	assert.Len(t, deb.Details, 2)
+	assert.Nil(t, deb.SeverityOverride)
+
+	// NVD outweighs GitHub for the language package, so the NVD severity is reported
+	npm := actual[0]
+	assert.Equal(t, "GHSA-xxxx-yyyy-zzzz", npm.Vulnerability.ID)
+	require.NotNil(t, npm.SeverityOverride)
+	assert.Equal(t, "High", npm.SeverityOverride.Severity)
+	assert.Contains(t, npm.SeverityOverride.Reason, "nvd:cpe")
+	assert.InDelta(t, 0.64, npm.Details[0].Confidence, 0.0001)
+}
+
+func TestWeights_Apply_equallyTrusted(t *testing.T) {
+	w, err := New(Weight{Provider: "github", Weight: 0.8})
+	require.NoError(t, err)
+
+	matches := match.NewMatches(
+		newMatch(debPackage, "CVE-2023-0001", "debian:distro:debian:12"),
+		newMatch(debPackage, "CVE-2023-0001", "nvd:cpe"),
+	)
+	applied := w.Apply(matches, metadataProvider{})
+	assert.Equal(t, 2, applied.Count())
+}
+
+func TestWeights_Apply_empty(t *testing.T) {
+	matches := match.NewMatches(
+		newMatch(debPackage, "CVE-2023-0001", "debian:distro:debian:12"),
+		newMatch(debPackage, "CVE-2023-0001", "nvd:cpe"),
+	)
+	var w *Weights
+	assert.Equal(t, matches, w.Apply(matches, metadataProvider{}))
+}
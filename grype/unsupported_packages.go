@@ -0,0 +1,69 @@
+package grype
+
+import (
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/syft/syft/linux"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// UnsupportedPackages returns the packages that cannot be matched against any vulnerability data with the matchers
+// (e.g. packages without a version, or OS packages of an unknown distro), which are coverage gaps of the scan.
+func (m *VulnerabilityMatcher) UnsupportedPackages(pkgs []pkg.Package, context pkg.Context) ([]pkg.UnsupportedPackage, error) {
+	pkgs, err := pkg.Enrich(pkgs, context, m.Enrichers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.unsupportedPackages(pkgs, context), nil
+}
+
+func (m *VulnerabilityMatcher) unsupportedPackages(pkgs []pkg.Package, context pkg.Context) []pkg.UnsupportedPackage {
+	matcherIndex, _ := newMatcherIndex(m.log(), m.Matchers)
+	var unsupported []pkg.UnsupportedPackage
+	for _, p := range pkgs {
+		if reason := unsupportedReason(p, context, matcherIndex, m.DisabledDistros); reason != "" {
+			unsupported = append(unsupported, pkg.UnsupportedPackage{Package: p, Reason: reason})
+		}
+	}
+	return unsupported
+}
+
+func unsupportedReason(p pkg.Package, context pkg.Context, matcherIndex map[syftPkg.Type][]matcher.Matcher, disabledDistros []distro.Type) pkg.UnsupportedReason {
+	if p.Version == "" {
+		return pkg.MissingVersion
+	}
+	if _, err := version.NewVersionFromPkg(p); err != nil {
+		return pkg.UnparseableVersion
+	}
+
+	switch p.Type {
+	case syftPkg.AlpmPkg, syftPkg.ApkPkg, syftPkg.DebPkg, syftPkg.RpmPkg:
+		release := context.Distro
+		if p.Distro != nil {
+			release = p.Distro
+		}
+		if reason := distroReason(release, disabledDistros); reason != "" {
+			return reason
+		}
+	}
+
+	// packages without a matcher of their own are only searched by language and CPE
+	if _, ok := matcherIndex[p.Type]; !ok && len(p.CPEs) == 0 && (p.Language == "" || p.Language == syftPkg.UnknownLanguage) {
+		return pkg.UnsupportedEcosystem
+	}
+	return ""
+}
+
+func distroReason(release *linux.Release, disabledDistros []distro.Type) pkg.UnsupportedReason {
+	if release == nil {
+		return pkg.MissingDistro
+	}
+	d, err := distro.NewFromRelease(*release)
+	if err != nil || d.Disabled(disabledDistros) {
+		return pkg.UnsupportedDistro
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package grype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/cpe"
+	"github.com/anchore/syft/syft/linux"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestVulnerabilityMatcher_UnsupportedPackages(t *testing.T) {
+	debian := &linux.Release{ID: "debian", VersionID: "8"}
+	tests := []struct {
+		name     string
+		p        pkg.Package
+		context  pkg.Context
+		disabled []distro.Type
+		want     pkg.UnsupportedReason
+	}{
+		{
+			name: "supported language package",
+			p:    pkg.Package{Name: "activerecord", Version: "4.0.1", Type: syftPkg.GemPkg, Language: syftPkg.Ruby},
+		},
+		{
+			name: "missing version",
+			p:    pkg.Package{Name: "activerecord", Type: syftPkg.GemPkg, Language: syftPkg.Ruby},
+			want: pkg.MissingVersion,
+		},
+		{
+			name: "unparseable version",
+			p:    pkg.Package{Name: "neutron", Version: "not a version!", Type: syftPkg.DebPkg},
+			context: pkg.Context{
+				Distro: debian,
+			},
+			want: pkg.UnparseableVersion,
+		},
+		{
+			name: "OS package of the distro of the scan target",
+			p:    pkg.Package{Name: "neutron", Version: "2014.1.3-5", Type: syftPkg.DebPkg},
+			context: pkg.Context{
+				Distro: debian,
+			},
+		},
+		{
+			name: "OS package of its own distro",
+			p:    pkg.Package{Name: "neutron", Version: "2014.1.3-5", Type: syftPkg.DebPkg, Distro: debian},
+		},
+		{
+			name: "OS package of an unknown distro",
+			p:    pkg.Package{Name: "neutron", Version: "2014.1.3-5", Type: syftPkg.DebPkg},
+			want: pkg.MissingDistro,
+		},
+		{
+			name: "OS package of an unsupported distro",
+			p:    pkg.Package{Name: "neutron", Version: "2014.1.3-5", Type: syftPkg.DebPkg},
+			context: pkg.Context{
+				Distro: &linux.Release{ID: "no-such-distro"},
+			},
+			want: pkg.UnsupportedDistro,
+		},
+		{
+			name: "OS package of a disabled distro",
+			p:    pkg.Package{Name: "openssl", Version: "3.3.1-1", Type: syftPkg.AlpmPkg},
+			context: pkg.Context{
+				Distro: &linux.Release{ID: "arch"},
+			},
+			disabled: []distro.Type{distro.ArchLinux},
+			want:     pkg.UnsupportedDistro,
+		},
+		{
+			name: "unsupported ecosystem",
+			p:    pkg.Package{Name: "tool", Version: "1.0.0", Type: syftPkg.BinaryPkg},
+			want: pkg.UnsupportedEcosystem,
+		},
+		{
+			name: "unsupported ecosystem searched by CPE",
+			p: pkg.Package{Name: "tool", Version: "1.0.0", Type: syftPkg.BinaryPkg, CPEs: []cpe.CPE{
+				cpe.Must("cpe:2.3:a:vendor:tool:1.0.0:*:*:*:*:*:*:*", ""),
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := VulnerabilityMatcher{
+				Store:           createMockStore(t, defaultStubFn),
+				Matchers:        matcher.NewDefaultMatchers(matcher.Config{}),
+				DisabledDistros: tt.disabled,
+			}
+
+			unsupported, err := m.UnsupportedPackages([]pkg.Package{tt.p}, tt.context)
+			require.NoError(t, err)
+
+			if tt.want == "" {
+				assert.Empty(t, unsupported)
+				return
+			}
+			require.Len(t, unsupported, 1)
+			assert.Equal(t, tt.want, unsupported[0].Reason)
+			assert.Equal(t, tt.p.Name, unsupported[0].Package.Name)
+		})
+	}
+}
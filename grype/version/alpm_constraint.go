@@ -0,0 +1,67 @@
+//nolint:dupl
+package version
+
+import "fmt"
+
+type alpmConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newAlpmConstraint(raw string) (alpmConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return alpmConstraint{}, nil
+	}
+
+	constraints, err := newConstraintExpression(raw, newAlpmComparator)
+	if err != nil {
+		return alpmConstraint{}, fmt.Errorf("unable to parse alpm constraint phrase: %w", err)
+	}
+	return alpmConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+func newAlpmComparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newAlpmVersion(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return ver, nil
+}
+
+func (c alpmConstraint) supported(format Format) bool {
+	return format == AlpmFormat
+}
+
+func (c alpmConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(alpm) unsupported format: %s", version.Format)
+	}
+
+	if version.rich.alpmVer == nil {
+		return false, fmt.Errorf("no rich alpm version given: %+v", version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c alpmConstraint) String() string {
+	if c.raw == "" {
+		return "none (alpm)"
+	}
+	return fmt.Sprintf("%s (alpm)", c.raw)
+}
@@ -0,0 +1,191 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var _ Comparator = (*alpmVersion)(nil)
+
+// alpmVersion represents an Arch Linux package version in the form [epoch:]pkgver[-pkgrel].
+type alpmVersion struct {
+	epoch   string
+	version string
+	release string
+}
+
+func newAlpmVersion(raw string) (*alpmVersion, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("empty alpm version")
+	}
+
+	epoch := "0"
+	remaining := raw
+	if before, after, found := strings.Cut(raw, ":"); found && isAllDigits(before) {
+		epoch = before
+		remaining = after
+	}
+
+	// the pkgrel is always the last dash-delimited field (pkgver may not contain dashes)
+	version, release := remaining, ""
+	if idx := strings.LastIndex(remaining, "-"); idx >= 0 {
+		version, release = remaining[:idx], remaining[idx+1:]
+	}
+
+	if version == "" {
+		return nil, fmt.Errorf("unable to parse alpm version: %q", raw)
+	}
+
+	return &alpmVersion{
+		epoch:   epoch,
+		version: version,
+		release: release,
+	}, nil
+}
+
+func (v *alpmVersion) Compare(other *Version) (int, error) {
+	if other.Format != AlpmFormat {
+		return -1, fmt.Errorf("unable to compare alpm to given format: %s", other.Format)
+	}
+	if other.rich.alpmVer == nil {
+		return -1, fmt.Errorf("given empty alpmVersion object")
+	}
+
+	return other.rich.alpmVer.compare(*v), nil
+}
+
+// compare follows alpm_pkg_vercmp from libalpm: the epoch is compared first, then the pkgver, and the pkgrel is
+// only considered when both versions specify one.
+func (v alpmVersion) compare(other alpmVersion) int {
+	if ret := alpmVercmp(v.epoch, other.epoch); ret != 0 {
+		return ret
+	}
+	if ret := alpmVercmp(v.version, other.version); ret != 0 {
+		return ret
+	}
+	if v.release == "" || other.release == "" {
+		return 0
+	}
+	return alpmVercmp(v.release, other.release)
+}
+
+func isAllDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(b byte) bool {
+	return isDigit(b) || isAlpha(b)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isAlpha(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// alpmVercmp is a port of rpmvercmp as implemented by libalpm. This differs from the rpm implementation in that a
+// trailing alpha segment is considered older than no segment at all (e.g. "1.0rc1" < "1.0"), which is how
+// pre-releases and VCS snapshots (e.g. "1.2.3.r45.gabcdef0") are ordered in Arch.
+//
+// For the original C implementation, see:
+// https://gitlab.archlinux.org/pacman/pacman/-/blob/master/lib/libalpm/version.c
+//
+//nolint:funlen,gocognit
+func alpmVercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	one, two := 0, 0
+	for one < len(a) && two < len(b) {
+		sep1, sep2 := one, two
+		for one < len(a) && !isAlnum(a[one]) {
+			one++
+		}
+		for two < len(b) && !isAlnum(b[two]) {
+			two++
+		}
+
+		if one >= len(a) || two >= len(b) {
+			break
+		}
+
+		// if the separator lengths were different, we are also finished
+		if one-sep1 != two-sep2 {
+			if one-sep1 < two-sep2 {
+				return -1
+			}
+			return 1
+		}
+
+		ptr1, ptr2 := one, two
+		isNum := isDigit(a[ptr1])
+		if isNum {
+			for ptr1 < len(a) && isDigit(a[ptr1]) {
+				ptr1++
+			}
+			for ptr2 < len(b) && isDigit(b[ptr2]) {
+				ptr2++
+			}
+		} else {
+			for ptr1 < len(a) && isAlpha(a[ptr1]) {
+				ptr1++
+			}
+			for ptr2 < len(b) && isAlpha(b[ptr2]) {
+				ptr2++
+			}
+		}
+
+		// numeric segments are always newer than alpha segments
+		if two == ptr2 {
+			if isNum {
+				return 1
+			}
+			return -1
+		}
+
+		seg1, seg2 := a[one:ptr1], b[two:ptr2]
+		if isNum {
+			seg1 = strings.TrimLeft(seg1, "0")
+			seg2 = strings.TrimLeft(seg2, "0")
+			if len(seg1) > len(seg2) {
+				return 1
+			}
+			if len(seg2) > len(seg1) {
+				return -1
+			}
+		}
+
+		if ret := strings.Compare(seg1, seg2); ret != 0 {
+			return ret
+		}
+
+		one, two = ptr1, ptr2
+	}
+
+	// all numeric and alpha segments compared identically, but the segment separating characters were different
+	if one >= len(a) && two >= len(b) {
+		return 0
+	}
+
+	// the final showdown. a remaining alpha string never beats an empty string:
+	//  - if a is empty and b is not an alpha, b is newer.
+	//  - if a is an alpha, b is newer.
+	//  - otherwise a is newer.
+	if (one >= len(a) && !isAlpha(b[two])) || (one < len(a) && isAlpha(a[one])) {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,83 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionAlpm(t *testing.T) {
+	tests := []struct {
+		v1     string
+		v2     string
+		result int
+	}{
+		// derived from https://gitlab.archlinux.org/pacman/pacman/-/blob/master/test/util/vercmptest.sh
+		{"1.5.0", "1.5.0", 0},
+		{"1.5.1", "1.5.0", 1},
+		{"1.5.1", "1.5", 1},
+		{"1.5.0-1", "1.5.0-1", 0},
+		{"1.5.0-1", "1.5.0-2", -1},
+		{"1.5.0-1", "1.5.1-1", -1},
+		{"1.5.0-2", "1.5.1-1", -1},
+		{"1.5-1", "1.5", 0},
+		{"1.5", "1.5-2", 0},
+		{"1.0a", "1.0alpha", -1},
+		{"1.0b", "1.0a", 1},
+		{"1.0a", "1.0", -1},
+		{"1.0rc1", "1.0", -1},
+		{"1.0pre1", "1.0rc1", -1},
+		{"1.0", "1.0.1", -1},
+		{"1.1", "1.1.0", -1},
+		{"1.0.a", "1.0", 1},
+		{"1.0", "1.0.1a", -1},
+		{"1.5.0", "1.5.0.0", -1},
+		{"1.0_1", "1.0.1", 0},
+		// epochs
+		{"1:1.0", "2.0", 1},
+		{"0:1.0", "1.0", 0},
+		{"1:1.0-1", "1:1.0-2", -1},
+		{"2:1.0", "1:2.0", 1},
+		// VCS snapshots
+		{"1.2.3.r45.gabcdef0", "1.2.3", 1},
+		{"1.2.3.r45.gabcdef0", "1.2.4", -1},
+		{"r1234.abcdef0", "r1235.0123456", -1},
+		{"2.0.0.r12.g1a2b3c4-1", "2.0.0.r9.gffffff0-1", 1},
+	}
+
+	for _, test := range tests {
+		name := test.v1 + "_vs_" + test.v2
+		t.Run(name, func(t *testing.T) {
+			v1, err := newAlpmVersion(test.v1)
+			require.NoError(t, err)
+
+			v2, err := newAlpmVersion(test.v2)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.result, v1.compare(*v2), "unexpected comparison result")
+			assert.Equal(t, -test.result, v2.compare(*v1), "comparison is not symmetric")
+		})
+	}
+}
+
+func TestVersionAlpmConstraint(t *testing.T) {
+	tests := []testCase{
+		{version: "2.3.1-1", constraint: "", satisfied: true},
+		{version: "2.3.1-1", constraint: "< 2.3.1-2", satisfied: true},
+		{version: "2.3.1-2", constraint: "< 2.3.1-2", satisfied: false},
+		{version: "1:2.3.1-1", constraint: "< 3.0.0-1", satisfied: false},
+		{version: "2.3.1rc1-1", constraint: "< 2.3.1-1", satisfied: true},
+		{version: "2.3.1.r12.g1a2b3c4-1", constraint: ">= 2.3.0, < 2.3.2", satisfied: true},
+		{version: "2.3.2-1", constraint: "< 2.3.0 || >= 2.3.2", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newAlpmConstraint(test.constraint)
+			require.NoError(t, err)
+
+			test.assertVersionConstraint(t, AlpmFormat, constraint)
+		})
+	}
+}
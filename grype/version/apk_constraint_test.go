@@ -45,6 +45,11 @@ func TestVersionApk(t *testing.T) {
 		{version: "0.1.0_alpha2", constraint: "> 0.1.0_alpha", satisfied: true},
 		{version: "1.1", constraint: "> 1.1_alpha1", satisfied: true},
 		{version: "1.1", constraint: "< 1.1_alpha1", satisfied: false},
+		// VCS snapshot suffixes (as used in aports) sort after the release they are based on
+		{version: "1.2.3_git20230101-r0", constraint: "< 1.2.4-r0", satisfied: true},
+		{version: "1.2.3_git20230101-r0", constraint: "< 1.2.3-r0", satisfied: false},
+		{version: "1.2.3_git20230101-r0", constraint: "< 1.2.3_git20230201-r0", satisfied: true},
+		{version: "1.2.3_alpha1_git20230101-r0", constraint: "< 1.2.3-r0", satisfied: true},
 		{version: "2.3.0b-r1", constraint: "< 2.3.0b-r2", satisfied: true},
 	}
 
@@ -31,12 +31,34 @@ func GetConstraint(constStr string, format Format) (Constraint, error) {
 		return newPortageConstraint(constStr)
 	case JVMFormat:
 		return newJvmConstraint(constStr)
+	case GitFormat:
+		return newGitConstraint(constStr)
+	case OpkgFormat:
+		return newOpkgConstraint(constStr)
+	case AlpmFormat:
+		return newAlpmConstraint(constStr)
 	case UnknownFormat:
 		return newFuzzyConstraint(constStr, "unknown")
 	}
 	return nil, fmt.Errorf("could not find constraint for given format: %s", format)
 }
 
+// HeuristicComparison returns the heuristic (CalverHeuristic or FuzzyHeuristic) that checking the version against
+// the constraint falls back to when the version and constraint cannot be compared by a known version format, or
+// false if the comparison follows a version format.
+func HeuristicComparison(c Constraint, v *Version) (string, bool) {
+	f, ok := c.(*fuzzyConstraint)
+	if !ok {
+		return "", false
+	}
+	// the heuristic is reported even when the comparison failed
+	_, heuristic, _ := f.check(v)
+	if heuristic == "" {
+		return "", false
+	}
+	return heuristic, true
+}
+
 // MustGetConstraint is meant for testing only, do not use within the library
 func MustGetConstraint(constStr string, format Format) Constraint {
 	constraint, err := GetConstraint(constStr, format)
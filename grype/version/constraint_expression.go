@@ -8,8 +8,8 @@ import (
 )
 
 type constraintExpression struct {
-	units       [][]constraintUnit // only supports or'ing a group of and'ed groups
-	comparators [][]Comparator     // only supports or'ing a group of and'ed groups
+	units       [][]constraintUnit // the or'ing of groups of and'ed units
+	comparators [][]Comparator     // the or'ing of groups of and'ed units
 }
 
 func newConstraintExpression(phrase string, genFn comparatorGenerator) (constraintExpression, error) {
@@ -72,26 +72,80 @@ func (c *constraintExpression) satisfied(other *Version) (bool, error) {
 	return oneSatisfied, nil
 }
 
+// the maximum number of or'd groups an expression may expand to, which bounds the cost of expanding deeply nested
+// combinations of or'd groups that are and'ed together
+const maxExpressionGroups = 256
+
+type expressionToken struct {
+	kind  string // one of the boolean operators, a parenthesis, or empty for a version operator pair
+	value string
+}
+
+func (t expressionToken) String() string {
+	if t.kind == "" {
+		return t.value
+	}
+	return t.kind
+}
+
+// scanExpression parses a constraint phrase of version operator pairs combined with and (",", "&&") and or ("||")
+// operators, which may be grouped with parentheses, into the or'ing of groups of and'ed version operator pairs. And
+// binds tighter than or, so "< 1.0, > 0.5 || 2.0" is "(< 1.0, > 0.5) || 2.0".
 func scanExpression(phrase string) ([][]string, error) {
+	tokens := tokenizeExpression(phrase)
+
+	p := expressionParser{tokens: tokens}
+	groups, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in expression", p.tokens[p.pos])
+	}
+
+	var orGroups [][]string
+	for _, group := range groups {
+		if len(group) > 0 {
+			orGroups = append(orGroups, group)
+		}
+	}
+	return orGroups, nil
+}
+
+// FlattenConstraint rewrites the given constraint phrase without parentheses, as the or'ing of groups of and'ed
+// version operator pairs (e.g. "(>= 1.0 || >= 2.0), < 3.0" is ">=1.0, <3.0 || >=2.0, <3.0"), for consumers that do
+// not support grouping.
+func FlattenConstraint(phrase string) (string, error) {
+	if !strings.ContainsAny(phrase, "()&") {
+		return phrase, nil
+	}
+	groups, err := scanExpression(phrase)
+	if err != nil {
+		return "", err
+	}
+	orParts := make([]string, len(groups))
+	for i, group := range groups {
+		orParts[i] = strings.Join(group, ", ")
+	}
+	return strings.Join(orParts, " || "), nil
+}
+
+func tokenizeExpression(phrase string) []expressionToken {
 	var scnr scanner.Scanner
-	var orGroups [][]string // all versions a group of and'd groups or'd together
-	var andGroup []string   // most current group of and'd versions
-	var buf bytes.Buffer    // most current single version value
+	var tokens []expressionToken
+	var buf bytes.Buffer // most current single version value
 	var lastToken string
 
 	captureVersionOperatorPair := func() {
 		if buf.Len() > 0 {
-			ver := buf.String()
-			andGroup = append(andGroup, ver)
+			tokens = append(tokens, expressionToken{value: buf.String()})
 			buf.Reset()
 		}
 	}
 
-	captureAndGroup := func() {
-		if len(andGroup) > 0 {
-			orGroups = append(orGroups, andGroup)
-			andGroup = nil
-		}
+	captureOperator := func(kind string) {
+		captureVersionOperatorPair()
+		tokens = append(tokens, expressionToken{kind: kind})
 	}
 
 	scnr.Init(strings.NewReader(phrase))
@@ -114,20 +168,102 @@ func scanExpression(phrase string) ([][]string, error) {
 		currentToken := scnr.TokenText()
 		switch {
 		case currentToken == ",":
-			captureVersionOperatorPair()
+			captureOperator(string(AND))
 		case currentToken == "|" && lastToken == "|":
-			captureVersionOperatorPair()
-			captureAndGroup()
+			captureOperator(string(OR))
+			currentToken = ""
+		case currentToken == "&" && lastToken == "&":
+			captureOperator(string(AND))
+			currentToken = ""
 		case currentToken == "(" || currentToken == ")":
-			return nil, fmt.Errorf("parenthetical expressions are not supported yet")
-		case currentToken != "|":
+			captureOperator(currentToken)
+		case currentToken != "|" && currentToken != "&":
 			buf.Write([]byte(currentToken))
 		}
 		lastToken = currentToken
 		tokenRune = scnr.Scan()
 	}
 	captureVersionOperatorPair()
-	captureAndGroup()
 
-	return orGroups, nil
+	return tokens
+}
+
+// expressionParser is a recursive descent parser of expression tokens, where every rule results in the or'ing of
+// groups of and'ed version operator pairs.
+type expressionParser struct {
+	tokens []expressionToken
+	pos    int
+}
+
+func (p *expressionParser) peek(kind string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == kind
+}
+
+func (p *expressionParser) parseOr() ([][]string, error) {
+	groups, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek(string(OR)) {
+		p.pos++
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, next...)
+		if len(groups) > maxExpressionGroups {
+			return nil, fmt.Errorf("expression is too complex (more than %d or'd groups)", maxExpressionGroups)
+		}
+	}
+	return groups, nil
+}
+
+func (p *expressionParser) parseAnd() ([][]string, error) {
+	groups, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek(string(AND)) {
+		p.pos++
+		next, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		if len(groups)*len(next) > maxExpressionGroups {
+			return nil, fmt.Errorf("expression is too complex (more than %d or'd groups)", maxExpressionGroups)
+		}
+		// (a || b), (c || d) is (a, c) || (a, d) || (b, c) || (b, d)
+		var combined [][]string
+		for _, g := range groups {
+			for _, n := range next {
+				group := make([]string, 0, len(g)+len(n))
+				group = append(group, g...)
+				combined = append(combined, append(group, n...))
+			}
+		}
+		groups = combined
+	}
+	return groups, nil
+}
+
+func (p *expressionParser) parseTerm() ([][]string, error) {
+	switch {
+	case p.peek("("):
+		p.pos++
+		groups, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, fmt.Errorf("missing closing parenthesis in expression")
+		}
+		p.pos++
+		return groups, nil
+	case p.peek(""):
+		value := p.tokens[p.pos].value
+		p.pos++
+		return [][]string{{value}}, nil
+	}
+	// an empty term (e.g. between repeated commas) does not constrain the group it is part of
+	return [][]string{nil}, nil
 }
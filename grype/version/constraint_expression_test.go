@@ -1,9 +1,12 @@
 package version
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestScanExpression(t *testing.T) {
@@ -40,8 +43,82 @@ func TestScanExpression(t *testing.T) {
 			},
 		},
 		{
-			// parenthetical expression are not supported yet
 			phrase: "(<1.0, >=2.0|| 3.0) || =4.0",
+			expected: [][]string{
+				{
+					"<1.0",
+					">=2.0",
+				},
+				{
+					"3.0",
+				},
+				{
+					"=4.0",
+				},
+			},
+		},
+		{
+			phrase: "(>= 1.0 || >= 2.0-rc1), < 3.0",
+			expected: [][]string{
+				{
+					">=1.0",
+					"<3.0",
+				},
+				{
+					">=2.0-rc1",
+					"<3.0",
+				},
+			},
+		},
+		{
+			phrase: "(> 1.0 && < 1.5 || > 2.0 && < 2.5) && != 1.2 || (= 3.0)",
+			expected: [][]string{
+				{
+					">1.0",
+					"<1.5",
+					"!=1.2",
+				},
+				{
+					">2.0",
+					"<2.5",
+					"!=1.2",
+				},
+				{
+					"=3.0",
+				},
+			},
+		},
+		{
+			phrase: "((< 1.0 || > 2.0), (< 3.0 || > 4.0))",
+			expected: [][]string{
+				{
+					"<1.0",
+					"<3.0",
+				},
+				{
+					"<1.0",
+					">4.0",
+				},
+				{
+					">2.0",
+					"<3.0",
+				},
+				{
+					">2.0",
+					">4.0",
+				},
+			},
+		},
+		{
+			phrase: "(< 1.0 || > 2.0",
+			err:    true,
+		},
+		{
+			phrase: "< 1.0) || > 2.0",
+			err:    true,
+		},
+		{
+			phrase: "(< 1.0) > 2.0",
 			err:    true,
 		},
 		{
@@ -86,3 +163,38 @@ func TestScanExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestScanExpression_tooComplex(t *testing.T) {
+	// every and'ed group of or'd versions doubles the number of or'd groups
+	phrase := "(1 || 2)" + strings.Repeat(", (1 || 2)", 8)
+	_, err := scanExpression(phrase)
+	require.Error(t, err)
+}
+
+func TestFlattenConstraint(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		expected string
+	}{
+		{
+			phrase:   ">= 1.0, < 2.0 || >= 3.0",
+			expected: ">= 1.0, < 2.0 || >= 3.0",
+		},
+		{
+			phrase:   "(>= 1.0 || >= 2.0), < 3.0",
+			expected: ">=1.0, <3.0 || >=2.0, <3.0",
+		},
+		{
+			phrase:   ">= 1.0 && != 1.5",
+			expected: ">=1.0, !=1.5",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.phrase, func(t *testing.T) {
+			actual, err := FlattenConstraint(test.phrase)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -9,9 +9,9 @@ import (
 	"github.com/anchore/grype/internal/stringutil"
 )
 
-// operator group only matches on range operators (GT, LT, GTE, LTE, E)
+// operator group only matches on range operators (GT, LT, GTE, LTE, E, NE)
 // version group matches on everything except for whitespace and operators (range or boolean)
-var constraintPartPattern = regexp.MustCompile(`\s*(?P<operator>[><=]*)\s*(?P<version>.+)`)
+var constraintPartPattern = regexp.MustCompile(`\s*(?P<operator>[><=!]*)\s*(?P<version>.+)`)
 
 type constraintUnit struct {
 	rangeOperator operator
@@ -62,6 +62,8 @@ func (c *constraintUnit) Satisfied(comparison int) bool {
 	switch c.rangeOperator {
 	case EQ:
 		return comparison == 0
+	case NE:
+		return comparison != 0
 	case GT:
 		return comparison > 0
 	case GTE:
@@ -107,6 +107,13 @@ func TestSplitFuzzyPhrase(t *testing.T) {
 				version:       "1.0",
 			},
 		},
+		{
+			phrase: "!= 1.0",
+			expected: &constraintUnit{
+				rangeOperator: NE,
+				version:       "1.0",
+			},
+		},
 	}
 
 	for _, test := range tests {
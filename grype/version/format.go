@@ -20,6 +20,9 @@ const (
 	PortageFormat
 	GolangFormat
 	JVMFormat
+	GitFormat
+	OpkgFormat
+	AlpmFormat
 )
 
 type Format int
@@ -37,6 +40,9 @@ var formatStr = []string{
 	"Portage",
 	"Go",
 	"JVM",
+	"Git",
+	"Opkg",
+	"Alpm",
 }
 
 var Formats = []Format{
@@ -51,6 +57,9 @@ var Formats = []Format{
 	PortageFormat,
 	GolangFormat,
 	JVMFormat,
+	GitFormat,
+	OpkgFormat,
+	AlpmFormat,
 }
 
 func ParseFormat(userStr string) Format {
@@ -77,6 +86,12 @@ func ParseFormat(userStr string) Format {
 		return PortageFormat
 	case strings.ToLower(JVMFormat.String()), "jvm", "jre", "jdk", "openjdk", "jep223":
 		return JVMFormat
+	case strings.ToLower(GitFormat.String()), "commit":
+		return GitFormat
+	case strings.ToLower(OpkgFormat.String()), "ipk":
+		return OpkgFormat
+	case strings.ToLower(AlpmFormat.String()), "pacman", "arch":
+		return AlpmFormat
 	}
 	return UnknownFormat
 }
@@ -99,8 +114,21 @@ func FormatFromPkg(p pkg.Package) Format {
 		return KBFormat
 	case syftPkg.PortagePkg:
 		return PortageFormat
+	case pkg.OpkgPkg:
+		return OpkgFormat
+	case syftPkg.AlpmPkg:
+		return AlpmFormat
 	case syftPkg.GoModulePkg:
+		if IsCommit(p.Version) {
+			// the module is pinned to a source-repository commit (e.g. pkg:golang/...@<commit>) instead of a release
+			return GitFormat
+		}
 		return GolangFormat
+	case syftPkg.UnknownPkg:
+		if strings.HasPrefix(p.PURL, "pkg:github/") && IsCommit(p.Version) {
+			// a source repository pinned to a commit (e.g. pkg:github/owner/repo@<commit>)
+			return GitFormat
+		}
 	}
 
 	if pkg.IsJvmPackage(p) {
@@ -116,6 +116,25 @@ func TestFormatFromPkgType(t *testing.T) {
 			},
 			format: JVMFormat,
 		},
+		{
+			name: "go module pinned to a commit",
+			p: pkg.Package{
+				Type:    syftPkg.GoModulePkg,
+				Name:    "github.com/anchore/grype",
+				Version: "16171245cfb2",
+			},
+			format: GitFormat,
+		},
+		{
+			name: "repository pinned to a commit",
+			p: pkg.Package{
+				Type:    syftPkg.UnknownPkg,
+				Name:    "github.com/anchore/grype",
+				Version: "16171245cfb2",
+				PURL:    "pkg:github/anchore/grype@16171245cfb2",
+			},
+			format: GitFormat,
+		},
 	}
 
 	for _, test := range tests {
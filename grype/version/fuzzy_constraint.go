@@ -45,7 +45,12 @@ check:
 		}
 	}
 
-	if value, err := hashiVer.NewConstraint(phrase); err == nil && valid {
+	// the semver constraint parser does not support grouping with parentheses
+	flattened, err := FlattenConstraint(phrase)
+	if err != nil {
+		return nil, fmt.Errorf("could not create fuzzy constraint: %+v", err)
+	}
+	if value, err := hashiVer.NewConstraint(flattened); err == nil && valid {
 		semverConstraint = &value
 	}
 
@@ -66,15 +71,21 @@ func newFuzzyComparator(unit constraintUnit) (Comparator, error) {
 }
 
 func (f *fuzzyConstraint) Satisfied(verObj *Version) (bool, error) {
+	satisfied, _, err := f.check(verObj)
+	return satisfied, err
+}
+
+// check returns if the version satisfies the constraint, and the heuristic the comparison fell back to (if any).
+func (f *fuzzyConstraint) check(verObj *Version) (bool, string, error) {
 	if f.rawPhrase == "" && verObj != nil {
 		// an empty constraint is always satisfied
-		return true, nil
+		return true, "", nil
 	} else if verObj == nil {
 		if f.rawPhrase != "" {
 			// a non-empty constraint with no version given should always fail
-			return false, nil
+			return false, "", nil
 		}
-		return true, nil
+		return true, "", nil
 	}
 
 	version := verObj.Raw
@@ -87,21 +98,22 @@ func (f *fuzzyConstraint) Satisfied(verObj *Version) (bool, error) {
 		if err == nil && !ok {
 			satisfied, err := newConstaint.Satisfied(verObj)
 			if err == nil {
-				return satisfied, nil
+				return satisfied, "", nil
 			}
 		}
 	}
 
-	// attempt semver first, then fallback to fuzzy part matching...
+	// attempt semver first, then fallback to heuristic part matching...
 	if f.semanticConstraint != nil {
 		if pseudoSemverPattern.MatchString(version) {
 			if semver, err := newSemanticVersion(version); err == nil && semver != nil {
-				return f.semanticConstraint.Check(semver.verObj), nil
+				return f.semanticConstraint.Check(semver.verObj), "", nil
 			}
 		}
 	}
-	// semver didn't work, use fuzzy part matching instead...
-	return f.constraints.satisfied(verObj)
+	// semver didn't work, use heuristic part matching instead...
+	satisfied, err := f.constraints.satisfied(verObj)
+	return satisfied, heuristicFor(version), err
 }
 
 func (f *fuzzyConstraint) String() string {
@@ -33,6 +33,6 @@ func (v *fuzzyVersion) Compare(other *Version) (int, error) {
 		return other.rich.semVer.verObj.Compare(v.semVer.verObj), nil
 	}
 
-	// one or both are no semver compliant, use fuzzy comparison
-	return fuzzyVersionComparison(other.Raw, v.raw), nil
+	// one or both are no semver compliant, use heuristic comparison
+	return heuristicVersionComparison(other.Raw, v.raw), nil
 }
@@ -0,0 +1,165 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// rootCommit is the OSV "introduced" event value for a range that starts at the first commit of the repository.
+const rootCommit = "0"
+
+// ErrUnknownCommitRange is the error of a commit that may be within a git range: whether it is cannot be known
+// without the commit graph of the repository, so the commit is neither known to be affected nor known not to be.
+var ErrUnknownCommitRange = errors.New("the commit may be within the git range, which cannot be known without the commit graph")
+
+// gitConstraint describes the set of affected commits for a repository, either as a list of commits (e.g.
+// "= 1a2b3c4 || = 5d6e7f8") or as OSV GIT range events, where ">=" is an introduced commit, "<" is a fixed (or limit)
+// commit and "<=" is a last affected commit (e.g. ">= 0, < 1a2b3c4").
+//
+// Commits have no inherent ordering, and the commit graph of the repository is not available when matching, so a
+// commit is only known to be within a range when it is one of the events of the range: an introduced commit is
+// affected (unless it is also the fixed commit), a last affected commit is affected when the range starts at the first
+// commit, and a fixed commit is not affected. Other commits are only affected by a range from the first commit without
+// an upper bound, or when they are listed as well (as records that enumerate the affected commits of a range do). For
+// any other commit, whether it is within the range is unknown, which Satisfied reports as ErrUnknownCommitRange.
+type gitConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newGitConstraint(raw string) (gitConstraint, error) {
+	if raw == "" {
+		return gitConstraint{}, nil
+	}
+
+	constraints, err := newConstraintExpression(raw, newGitComparator)
+	if err != nil {
+		return gitConstraint{}, fmt.Errorf("unable to parse git constraint phrase: %w", err)
+	}
+
+	return gitConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+func newGitComparator(unit constraintUnit) (Comparator, error) {
+	switch unit.rangeOperator {
+	case EQ, NE, GTE, LT, LTE:
+	default:
+		return nil, fmt.Errorf("git constraints only support equality and range events, got operator %q", unit.rangeOperator)
+	}
+	if unit.version == rootCommit {
+		if unit.rangeOperator != GTE {
+			return nil, fmt.Errorf("the first commit may only introduce a git range, got operator %q", unit.rangeOperator)
+		}
+		return &gitVersion{}, nil
+	}
+	ver, err := newGitVersion(unit.version)
+	if err != nil {
+		return nil, err
+	}
+	return ver, nil
+}
+
+func (c gitConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" {
+		return false, &NonFatalConstraintError{
+			constraint: c,
+			version:    version,
+			message:    "Unexpected data in DB: Empty raw version constraint.",
+		}
+	}
+
+	if version == nil {
+		return true, nil
+	}
+
+	var commit string
+	if version.rich.gitVer != nil {
+		commit = version.rich.gitVer.commit
+	} else {
+		var ok bool
+		if commit, ok = commitFromVersion(version.Raw); !ok {
+			// the version is not commit-based and cannot be related to a set of commits
+			return false, nil
+		}
+	}
+
+	var unknown bool
+	for _, units := range c.expression.units {
+		switch gitGroupSatisfied(units, commit) {
+		case gitAffected:
+			return true, nil
+		case gitUnknown:
+			unknown = true
+		}
+	}
+	if unknown {
+		return false, fmt.Errorf("commit %s against %s: %w", commit, c, ErrUnknownCommitRange)
+	}
+	return false, nil
+}
+
+// gitResult is whether a commit is affected by an and'ed group of commits and range events.
+type gitResult int
+
+const (
+	gitUnaffected gitResult = iota
+	gitAffected
+	// gitUnknown is a commit that may be within the range, which cannot be known without the commit graph
+	gitUnknown
+)
+
+// gitGroupSatisfied indicates if the commit is affected by the and'ed group of commits and range events.
+func gitGroupSatisfied(units []constraintUnit, commit string) gitResult {
+	startsAtRoot := true
+	var isRange, introduced, lastAffected, hasUpperBound bool
+	for _, unit := range units {
+		same := sameCommit(strings.ToLower(unit.version), commit)
+		switch unit.rangeOperator {
+		case EQ:
+			if !same {
+				return gitUnaffected
+			}
+		case NE:
+			if same {
+				return gitUnaffected
+			}
+		case GTE:
+			isRange = true
+			if unit.version != rootCommit {
+				startsAtRoot = false
+			}
+			introduced = introduced || same
+		case LT:
+			isRange, hasUpperBound = true, true
+			if same {
+				// the fixed commit is not affected
+				return gitUnaffected
+			}
+		case LTE:
+			isRange, hasUpperBound = true, true
+			lastAffected = lastAffected || same
+		}
+	}
+
+	switch {
+	case !isRange:
+		return gitAffected
+	case introduced:
+		return gitAffected
+	case startsAtRoot && (lastAffected || !hasUpperBound):
+		return gitAffected
+	}
+	// the commit may be within the range, but that cannot be known without the commit graph
+	return gitUnknown
+}
+
+func (c gitConstraint) String() string {
+	if c.raw == "" {
+		return fmt.Sprintf("%q (git)", c.raw)
+	}
+	return fmt.Sprintf("%s (git)", c.raw)
+}
@@ -0,0 +1,100 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionGitConstraint(t *testing.T) {
+	tests := []testCase{
+		{name: "no constraint raises error", version: "16171245cfb2", constraint: "", satisfied: false, shouldErr: true, errorAssertion: func(t *testing.T, err error) {
+			var expectedError *NonFatalConstraintError
+			assert.ErrorAs(t, err, &expectedError, "Unexpected error type from gitConstraint.Satisfied: %v", err)
+		}},
+		{name: "exact commit match", version: "16171245cfb2", constraint: "= 16171245cfb2", satisfied: true},
+		{name: "abbreviated version matches full constraint", version: "16171245cfb2", constraint: "= 16171245cfb2a1d9b5d5e39f9cb8a5e8c2a0b1c3", satisfied: true},
+		{name: "full version matches abbreviated constraint", version: "16171245cfb2a1d9b5d5e39f9cb8a5e8c2a0b1c3", constraint: "= 16171245", satisfied: true},
+		{name: "case insensitive", version: "16171245CFB2", constraint: "= 16171245cfb2", satisfied: true},
+		{name: "commit mismatch", version: "daa7c04131f5", constraint: "= 16171245cfb2", satisfied: false},
+		{name: "OR constraint match", version: "daa7c04131f5", constraint: "= 16171245cfb2 || = daa7c04131f5", satisfied: true},
+		{name: "OR constraint mismatch", version: "aaaaaaaaaaaa", constraint: "= 16171245cfb2 || = daa7c04131f5", satisfied: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			constraint, err := newGitConstraint(test.constraint)
+			require.NoError(t, err)
+
+			test.assertVersionConstraint(t, GitFormat, constraint)
+		})
+	}
+}
+
+func TestVersionGitConstraint_PseudoVersions(t *testing.T) {
+	tests := []testCase{
+		{version: "v0.0.0-20200202094626-16171245cfb2", constraint: "= 16171245cfb2", satisfied: true},
+		{version: "v1.2.4-0.20191109021931-daa7c04131f5+incompatible", constraint: "= daa7c04131f5", satisfied: true},
+		{version: "v0.0.0-20200202094626-16171245cfb2", constraint: "= daa7c04131f5", satisfied: false},
+		{version: "v1.2.3", constraint: "= daa7c04131f5", satisfied: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newGitConstraint(test.constraint)
+			require.NoError(t, err)
+
+			test.assertVersionConstraint(t, GolangFormat, constraint)
+		})
+	}
+}
+
+func TestVersionGitConstraint_RangeEvents(t *testing.T) {
+	tests := []testCase{
+		{name: "introduced commit is affected", version: "16171245cfb2", constraint: ">= 16171245cfb2, < daa7c04131f5", satisfied: true},
+		{name: "fixed commit is not affected", version: "daa7c04131f5", constraint: ">= 16171245cfb2, < daa7c04131f5", satisfied: false},
+		{name: "introduced and fixed by the same commit", version: "16171245cfb2", constraint: ">= 16171245cfb2, < 16171245cfb2", satisfied: false},
+		{name: "last affected commit of range from the first commit", version: "daa7c04131f5", constraint: ">= 0, <= daa7c04131f5", satisfied: true},
+		// whether a commit other than the events is within a range cannot be known without the commit graph
+		{name: "last affected commit of range from another commit", version: "daa7c04131f5", constraint: ">= 16171245cfb2, <= daa7c04131f5", satisfied: false, shouldErr: true, errorAssertion: func(t *testing.T, err error) {
+			assert.ErrorIs(t, err, ErrUnknownCommitRange)
+		}},
+		{name: "any commit of unbounded range from the first commit", version: "aaaaaaaaaaaa", constraint: ">= 0", satisfied: true},
+		{name: "unordered commit within bounded range", version: "aaaaaaaaaaaa", constraint: ">= 0, < daa7c04131f5", satisfied: false, shouldErr: true, errorAssertion: func(t *testing.T, err error) {
+			assert.ErrorIs(t, err, ErrUnknownCommitRange)
+		}},
+		{name: "unknown in a group, excluded in another", version: "aaaaaaaaaaaa", constraint: ">= 0, < daa7c04131f5 || = 16171245cfb2", satisfied: false, shouldErr: true, errorAssertion: func(t *testing.T, err error) {
+			assert.ErrorIs(t, err, ErrUnknownCommitRange)
+		}},
+		{name: "enumerated commit of range", version: "aaaaaaaaaaaa", constraint: ">= 0, < daa7c04131f5 || = aaaaaaaaaaaa", satisfied: true},
+		{name: "excluded commit", version: "16171245cfb2", constraint: ">= 16171245cfb2, != 16171245cfb2", satisfied: false},
+		{name: "pseudo-version at introduced commit", version: "v0.0.0-20200202094626-16171245cfb2", constraint: ">= 16171245cfb2, < daa7c04131f5", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			constraint, err := newGitConstraint(test.constraint)
+			require.NoError(t, err)
+
+			test.assertVersionConstraint(t, GitFormat, constraint)
+		})
+	}
+}
+
+func TestGitConstraint_RejectsUnsupportedOperators(t *testing.T) {
+	for _, raw := range []string{"> 16171245cfb2", "> 16171245cfb2, <= daa7c04131f5", "< 0", "= 0"} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := newGitConstraint(raw)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestIsCommit(t *testing.T) {
+	assert.True(t, IsCommit("16171245cfb2"))
+	assert.True(t, IsCommit("16171245cfb2a1d9b5d5e39f9cb8a5e8c2a0b1c3"))
+	assert.False(t, IsCommit("v1.2.3"))
+	assert.False(t, IsCommit("abc12"))
+	assert.False(t, IsCommit("not-a-commit"))
+}
@@ -0,0 +1,79 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var _ Comparator = (*gitVersion)(nil)
+
+// minCommitLength is the shortest abbreviated commit hash that is considered for matching.
+const minCommitLength = 7
+
+var (
+	commitPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+	// go pseudo-versions end with a 14 digit timestamp and a 12 character abbreviated commit hash, for example
+	// v0.0.0-20200202094626-16171245cfb2 or v1.2.4-0.20191109021931-daa7c04131f5+incompatible
+	pseudoVersionCommitPattern = regexp.MustCompile(`\d{14}-([0-9a-f]{12})(\+incompatible)?$`)
+)
+
+// gitVersion represents a version that is a VCS commit (either directly or embedded within a go pseudo-version).
+// Commits have no inherent ordering, so they can only be compared for equality.
+type gitVersion struct {
+	commit string
+}
+
+func newGitVersion(raw string) (*gitVersion, error) {
+	commit, ok := commitFromVersion(raw)
+	if !ok {
+		return nil, fmt.Errorf("unable to find commit in version: %q", raw)
+	}
+	return &gitVersion{commit: commit}, nil
+}
+
+// IsCommit indicates if the given version is a bare VCS commit hash.
+func IsCommit(raw string) bool {
+	return commitPattern.MatchString(raw)
+}
+
+func commitFromVersion(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if IsCommit(raw) {
+		return strings.ToLower(raw), true
+	}
+	if m := pseudoVersionCommitPattern.FindStringSubmatch(raw); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+func (v *gitVersion) Compare(other *Version) (int, error) {
+	var commit string
+	switch {
+	case other.rich.gitVer != nil:
+		commit = other.rich.gitVer.commit
+	default:
+		c, ok := commitFromVersion(other.Raw)
+		if !ok {
+			return -1, fmt.Errorf("unable to compare commit to given version: %s", other)
+		}
+		commit = c
+	}
+
+	if sameCommit(v.commit, commit) {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// sameCommit considers abbreviated hashes equal to a full hash when one is a prefix of the other.
+func sameCommit(a, b string) bool {
+	if len(a) < minCommitLength || len(b) < minCommitLength {
+		return false
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return strings.HasPrefix(b, a)
+}
@@ -0,0 +1,151 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// CalverHeuristic compares calendar versions (e.g. 2024.01.15 or 2024-01-15) by their date segments.
+	CalverHeuristic = "calver"
+	// FuzzyHeuristic compares versions of an unknown format by their numeric segments and suffix.
+	FuzzyHeuristic = "fuzzy"
+)
+
+var (
+	// calendar versions start with a year, and may separate the date segments with dashes or underscores
+	calverReleasePattern = regexp.MustCompile(`^v?((?:19|20)\d{2}(?:[-._]\d+)+)`)
+	releasePattern       = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)`)
+	segmentSeparators    = regexp.MustCompile(`[-._]`)
+	suffixKeywordPattern = regexp.MustCompile(`^[a-z]+`)
+)
+
+// the order of the suffixes of versions relative to the release they are a suffix of
+var suffixRanks = map[string]int{
+	"dev":       -5,
+	"snapshot":  -5,
+	"alpha":     -4,
+	"a":         -4,
+	"milestone": -3,
+	"m":         -3,
+	"beta":      -2,
+	"b":         -2,
+	"pre":       -1,
+	"preview":   -1,
+	"rc":        -1,
+	"cr":        -1,
+	"c":         -1,
+	"ga":        0,
+	"final":     0,
+	"release":   0,
+}
+
+// other suffixes (such as post-releases, patches, or build and distro labels) are after the release, and ordered by
+// comparing the suffixes
+const unknownSuffixRank = 1
+
+type heuristicVersion struct {
+	segments []string // the numeric release segments, without leading zeros
+	rank     int      // the order of the suffix relative to the release
+	suffix   string   // the remainder of the suffix to compare, after any pre-release keyword
+	calver   bool
+}
+
+func parseHeuristicVersion(raw string) (heuristicVersion, bool) {
+	v := heuristicVersion{}
+	groups := calverReleasePattern.FindStringSubmatch(raw)
+	if groups != nil {
+		v.calver = true
+	} else if groups = releasePattern.FindStringSubmatch(raw); groups == nil {
+		return v, false
+	}
+
+	for _, s := range segmentSeparators.Split(groups[1], -1) {
+		v.segments = append(v.segments, strings.TrimLeft(s, "0"))
+	}
+	// trailing zero segments do not change the version (1.0 is 1.0.0)
+	for len(v.segments) > 1 && v.segments[len(v.segments)-1] == "" {
+		v.segments = v.segments[:len(v.segments)-1]
+	}
+
+	suffix := strings.ToLower(strings.TrimLeft(raw[len(groups[0]):], "-._+~"))
+	if suffix == "" {
+		return v, true
+	}
+	keyword := suffixKeywordPattern.FindString(suffix)
+	rank, ok := suffixRanks[keyword]
+	if ok && len(keyword) == 1 && !startsWithDigit(suffix[1:]) {
+		// single letters are only pre-releases when numbered (e.g. 1.0a1 or 1.0-M1), as lettered releases of
+		// openssl (e.g. 1.0.2k) are after the release
+		ok = false
+	}
+	if !ok {
+		v.rank = unknownSuffixRank
+		v.suffix = suffix
+		return v, true
+	}
+	v.rank = rank
+	v.suffix = strings.TrimLeft(suffix[len(keyword):], "-._+~")
+	return v, true
+}
+
+// heuristicVersionComparison compares versions of an unknown format segment by segment, comparing the numeric
+// segments as numbers (so 1.10 is after 1.9 and 2024.01 is 2024.1) and ordering pre-release suffixes (e.g. "-rc1")
+// before the release they are a suffix of. Versions that do not start with a number are compared with
+// fuzzyVersionComparison. Returns -1 if v1 < v2, 1 if v1 > v2 and 0 if v1 == v2.
+func heuristicVersionComparison(v1, v2 string) int {
+	h1, ok1 := parseHeuristicVersion(v1)
+	h2, ok2 := parseHeuristicVersion(v2)
+	if !ok1 || !ok2 {
+		return fuzzyVersionComparison(v1, v2)
+	}
+
+	for i := 0; i < len(h1.segments) || i < len(h2.segments); i++ {
+		var s1, s2 string
+		if i < len(h1.segments) {
+			s1 = h1.segments[i]
+		}
+		if i < len(h2.segments) {
+			s2 = h2.segments[i]
+		}
+		if cmp := compareNumeric(s1, s2); cmp != 0 {
+			return cmp
+		}
+	}
+
+	switch {
+	case h1.rank < h2.rank:
+		return -1
+	case h1.rank > h2.rank:
+		return 1
+	case h1.suffix == h2.suffix:
+		return 0
+	case h1.suffix == "":
+		return -1
+	case h2.suffix == "":
+		return 1
+	}
+	return fuzzyVersionComparison(h1.suffix, h2.suffix)
+}
+
+// compareNumeric compares two numbers without leading zeros, of any length
+func compareNumeric(n1, n2 string) int {
+	if len(n1) != len(n2) {
+		if len(n1) < len(n2) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(n1, n2)
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
+
+func heuristicFor(raw string) string {
+	if calverReleasePattern.MatchString(raw) {
+		return CalverHeuristic
+	}
+	return FuzzyHeuristic
+}
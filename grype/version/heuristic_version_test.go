@@ -0,0 +1,99 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeuristicVersionComparison(t *testing.T) {
+	cases := []struct {
+		v1, v2 string
+		ret    int
+	}{
+		// calendar versions
+		{"2024.01.15", "2024.1.15", 0},
+		{"2024.01.15", "2024.1.9", 1},
+		{"2024-01-15", "2024-02-01", -1},
+		{"2023.12", "2024.01", -1},
+		{"v2024.04.0", "2024.4", 0},
+		// numeric segments
+		{"1.0", "1.0.0", 0},
+		{"1.10", "1.9", 1},
+		{"12345678901234567890.1", "9.1", 1},
+		{"95SE", "98SP1", -1},
+		{"1.2.3-4", "1.2.3-10", -1},
+		// pre-release suffixes
+		{"1.0.0-rc1", "1.0.0", -1},
+		{"1.0.0-rc1", "1.0.0-rc2", -1},
+		{"1.0.0-beta", "1.0.0-rc1", -1},
+		{"1.0.0-dev1", "1.0.0-alpha", -1},
+		{"1.0a1", "1.0", -1},
+		{"2.0-M1", "2.0", -1},
+		{"1.0.Final", "1.0", 0},
+		// post-release and other suffixes
+		{"1.0.0", "1.0.0-post1", -1},
+		{"1.0.0-post1", "1.0.0-post2", -1},
+		{"1.0.2", "1.0.2a", -1},
+		{"1.0.2k", "1.0.2m", -1},
+		// versions without numeric segments
+		{"abc", "abd", -1},
+	}
+	for _, c := range cases {
+		t.Run(c.v1+" vs "+c.v2, func(t *testing.T) {
+			assert.Equal(t, c.ret, heuristicVersionComparison(c.v1, c.v2))
+			assert.Equal(t, -c.ret, heuristicVersionComparison(c.v2, c.v1))
+		})
+	}
+}
+
+func TestHeuristicComparison(t *testing.T) {
+	cases := []struct {
+		name       string
+		version    string
+		format     Format
+		constraint Constraint
+		heuristic  string
+	}{
+		{
+			name:       "calendar version",
+			version:    "2024.01.15",
+			constraint: MustGetConstraint("< 2024.02", UnknownFormat),
+			heuristic:  CalverHeuristic,
+		},
+		{
+			name:       "unknown format",
+			version:    "1.0.2k",
+			constraint: MustGetConstraint("< 1.0.2m", UnknownFormat),
+			heuristic:  FuzzyHeuristic,
+		},
+		{
+			name:       "semver compatible",
+			version:    "1.2.3",
+			constraint: MustGetConstraint("< 1.2.4", UnknownFormat),
+		},
+		{
+			name:       "known version format",
+			version:    "1.2.3",
+			format:     SemanticFormat,
+			constraint: MustGetConstraint("< 1.2.4", SemanticFormat),
+		},
+		{
+			name:       "known version format of the package",
+			version:    "1:1.2.3-1",
+			format:     DebFormat,
+			constraint: MustGetConstraint("< 1:1.2.4-1", UnknownFormat),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			v, err := NewVersion(c.version, c.format)
+			require.NoError(t, err)
+
+			heuristic, ok := HeuristicComparison(c.constraint, v)
+			assert.Equal(t, c.heuristic != "", ok)
+			assert.Equal(t, c.heuristic, heuristic)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import "fmt"
 
 const (
 	EQ  operator = "="
+	NE  operator = "!="
 	GT  operator = ">"
 	LT  operator = "<"
 	GTE operator = ">="
@@ -18,6 +19,8 @@ func parseOperator(op string) (operator, error) {
 	switch op {
 	case string(EQ), "":
 		return EQ, nil
+	case string(NE):
+		return NE, nil
 	case string(GT):
 		return GT, nil
 	case string(GTE):
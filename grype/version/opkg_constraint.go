@@ -0,0 +1,67 @@
+//nolint:dupl
+package version
+
+import "fmt"
+
+type opkgConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newOpkgConstraint(raw string) (opkgConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return opkgConstraint{}, nil
+	}
+
+	constraints, err := newConstraintExpression(raw, newOpkgComparator)
+	if err != nil {
+		return opkgConstraint{}, fmt.Errorf("unable to parse opkg constraint phrase: %w", err)
+	}
+	return opkgConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+func newOpkgComparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newOpkgVersion(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return ver, nil
+}
+
+func (c opkgConstraint) supported(format Format) bool {
+	return format == OpkgFormat
+}
+
+func (c opkgConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(opkg) unsupported format: %s", version.Format)
+	}
+
+	if version.rich.opkgVer == nil {
+		return false, fmt.Errorf("no rich opkg version given: %+v", version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c opkgConstraint) String() string {
+	if c.raw == "" {
+		return "none (opkg)"
+	}
+	return fmt.Sprintf("%s (opkg)", c.raw)
+}
@@ -0,0 +1,33 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionOpkg(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.90-1", constraint: "", satisfied: true},
+		// fixed-in scenarios
+		{version: "2.90-1", constraint: "< 2.90-2", satisfied: true},
+		{version: "2.90-2", constraint: "< 2.90-2", satisfied: false},
+		{version: "2.89-4", constraint: "< 2.90", satisfied: true},
+		{version: "1:1.0-1", constraint: "< 2.0-1", satisfied: false},
+		{version: "2023.01.21~1a2b3c4d-1", constraint: "< 2023.01.21-1", satisfied: true},
+		{version: "1.36.1-r0", constraint: ">= 1.36.0, < 1.36.2", satisfied: true},
+		// OR constraints
+		{version: "5.4.3-1", constraint: "< 4.0 || >= 5.0, < 5.5", satisfied: true},
+		{version: "4.5.3-1", constraint: "< 4.0 || >= 5.0, < 5.5", satisfied: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newOpkgConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newOpkgConstraint: %v", err)
+
+			test.assertVersionConstraint(t, OpkgFormat, constraint)
+		})
+	}
+}
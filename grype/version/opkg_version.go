@@ -0,0 +1,34 @@
+package version
+
+import (
+	"fmt"
+
+	deb "github.com/knqyf263/go-deb-version"
+)
+
+// opkgVersion represents an ipk package version (as found on OpenWrt and Yocto-based images). The opkg version
+// comparison algorithm is derived from dpkg, so the same [epoch:]upstream[-revision] semantics apply.
+type opkgVersion struct {
+	obj deb.Version
+}
+
+func newOpkgVersion(raw string) (*opkgVersion, error) {
+	ver, err := deb.NewVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &opkgVersion{
+		obj: ver,
+	}, nil
+}
+
+func (o *opkgVersion) Compare(other *Version) (int, error) {
+	if other.Format != OpkgFormat {
+		return -1, fmt.Errorf("unable to compare opkg to given format: %s", other.Format)
+	}
+	if other.rich.opkgVer == nil {
+		return -1, fmt.Errorf("given empty opkgVersion object")
+	}
+
+	return other.rich.opkgVer.obj.Compare(o.obj), nil
+}
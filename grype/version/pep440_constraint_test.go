@@ -206,6 +206,17 @@ func TestItWorks(t *testing.T) {
 			version:    "2022.12.7",
 			constraint: ">=2017.11.05,<2022.12.07",
 		},
+		{
+			name:       "grouped ranges are and'ed with an exclusion",
+			version:    "2.1.0",
+			constraint: "(>= 1.0, < 1.5 || >= 2.0, < 2.5), != 2.2.0",
+			satisfied:  true,
+		},
+		{
+			name:       "excluded versions of grouped ranges are not satisfied",
+			version:    "2.2.0",
+			constraint: "(>= 1.0, < 1.5 || >= 2.0, < 2.5), != 2.2.0",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,88 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+
+	goPepVersion "github.com/aquasecurity/go-pep440-version"
+	"golang.org/x/mod/module"
+
+	hashiVer "github.com/anchore/go-version"
+)
+
+var (
+	// mavenPrerelease captures the release of maven versions with a qualifier that sorts before the release (alpha,
+	// beta, milestone, rc, cr and snapshot). A single letter is only such a qualifier when a number follows it (1.0-M1).
+	mavenPrerelease = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)*)[-.]?(?:(?:alpha|beta|milestone|rc|cr|snapshot)(?:$|[-.\d])|[abm]\d)`)
+
+	// gemPrerelease captures the release of rubygems versions with a letter, which are all pre-releases (1.0.0.pre1).
+	gemPrerelease = regexp.MustCompile(`^(\d+(?:\.\d+)*)[-.]?[A-Za-z]`)
+)
+
+// Prerelease returns the release that the version precedes (e.g. 2.0.0 for 2.0.0-rc.1), or false if the version is
+// not a pre-release by the rules of the version format. Versions of an unknown format are pre-releases when the heuristic
+// comparison sorts them before their release (e.g. 2.0.0-rc.1, but not 1.1.1c-1), and versions of other formats (e.g.
+// distro packages) are never pre-releases.
+func Prerelease(raw string, format Format) (string, bool) {
+	switch format {
+	case SemanticFormat, GolangFormat:
+		v, err := hashiVer.NewSemver(raw)
+		if err != nil || v.Prerelease() == "" {
+			return "", false
+		}
+		if format == GolangFormat && module.IsPseudoVersion(raw) {
+			// pseudo-versions name commits, not the pre-releases of a release
+			return "", false
+		}
+		release, _, _ := strings.Cut(raw, "-")
+		return release, true
+	case UnknownFormat:
+		v, ok := parseHeuristicVersion(raw)
+		if !ok || v.rank >= 0 {
+			return "", false
+		}
+		if v.calver {
+			return calverReleasePattern.FindString(raw), true
+		}
+		return releasePattern.FindString(raw), true
+	case PythonFormat:
+		v, err := goPepVersion.Parse(raw)
+		if err != nil || !v.IsPreRelease() {
+			return "", false
+		}
+		return v.BaseVersion(), true
+	case MavenFormat:
+		return submatch(mavenPrerelease, raw)
+	case GemFormat:
+		return submatch(gemPrerelease, extractSemVer(raw))
+	}
+	return "", false
+}
+
+// WithoutBuildMetadata returns the version without the build metadata of the version format (the "+build.5" of semver or
+// the "+ubuntu1" local version of PEP 440). Versions of formats without build metadata are returned unchanged, since a
+// "+" is part of the version there (e.g. the "+dfsg" of the upstream version of a debian package).
+func WithoutBuildMetadata(raw string, format Format) string {
+	switch format {
+	case SemanticFormat, GolangFormat, UnknownFormat:
+		if v, err := hashiVer.NewSemver(raw); err != nil || v.Metadata() == "" {
+			return raw
+		}
+	case PythonFormat:
+		if v, err := goPepVersion.Parse(raw); err != nil || v.Local() == "" {
+			return raw
+		}
+	default:
+		return raw
+	}
+	release, _, _ := strings.Cut(raw, "+")
+	return release
+}
+
+func submatch(pattern *regexp.Regexp, raw string) (string, bool) {
+	groups := pattern.FindStringSubmatch(raw)
+	if groups == nil {
+		return "", false
+	}
+	return groups[1], true
+}
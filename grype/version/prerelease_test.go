@@ -0,0 +1,63 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrerelease(t *testing.T) {
+	tests := []struct {
+		version string
+		format  Format
+		release string
+	}{
+		{version: "2.0.0-rc.1", format: SemanticFormat, release: "2.0.0"},
+		{version: "v1.4.0-beta.2", format: GolangFormat, release: "v1.4.0"},
+		{version: "2.0.0-rc.1", format: UnknownFormat, release: "2.0.0"},
+		{version: "2.0a1", format: PythonFormat, release: "2.0"},
+		{version: "2.0.dev0", format: PythonFormat, release: "2.0"},
+		{version: "3.0-M1", format: MavenFormat, release: "3.0"},
+		{version: "3.0.0-SNAPSHOT", format: MavenFormat, release: "3.0.0"},
+		{version: "1.0.0.pre1", format: GemFormat, release: "1.0.0"},
+		{version: "2.0.0", format: SemanticFormat},
+		{version: "2.0.post1", format: PythonFormat},
+		{version: "3.0.1.Final", format: MavenFormat},
+		// pseudo-versions name commits
+		{version: "v0.0.0-20200101000000-abcdefabcdef", format: GolangFormat},
+		// lettered releases and package revisions
+		{version: "1.1.1c-1", format: UnknownFormat},
+		{version: "1.2-1", format: UnknownFormat},
+		{version: "1.2.3-alpine", format: UnknownFormat},
+		{version: "1.1.1c-1", format: DebFormat},
+		{version: "2.0.0-rc.1", format: RpmFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.String()+":"+tt.version, func(t *testing.T) {
+			release, ok := Prerelease(tt.version, tt.format)
+			assert.Equal(t, tt.release != "", ok)
+			assert.Equal(t, tt.release, release)
+		})
+	}
+}
+
+func TestWithoutBuildMetadata(t *testing.T) {
+	tests := []struct {
+		version string
+		format  Format
+		want    string
+	}{
+		{version: "1.0.0+build.5", format: SemanticFormat, want: "1.0.0"},
+		{version: "1.0.0-rc.1+build.5", format: UnknownFormat, want: "1.0.0-rc.1"},
+		{version: "v2.0.0+incompatible", format: GolangFormat, want: "v2.0.0"},
+		{version: "1.0+ubuntu1", format: PythonFormat, want: "1.0"},
+		{version: "1.0.0", format: SemanticFormat, want: "1.0.0"},
+		{version: "1.2+dfsg-1", format: DebFormat, want: "1.2+dfsg-1"},
+		{version: "1.2+git20240101-1.el9", format: RpmFormat, want: "1.2+git20240101-1.el9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.String()+":"+tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, WithoutBuildMetadata(tt.version, tt.format))
+		})
+	}
+}
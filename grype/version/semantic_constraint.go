@@ -24,7 +24,13 @@ func newSemanticConstraint(constStr string) (semanticConstraint, error) {
 
 	normalized := normalizer.Replace(constStr)
 
-	constraints, err := hashiVer.NewConstraint(normalized)
+	// the semver constraint parser does not support grouping with parentheses
+	flattened, err := FlattenConstraint(normalized)
+	if err != nil {
+		return semanticConstraint{}, err
+	}
+
+	constraints, err := hashiVer.NewConstraint(flattened)
 	if err != nil {
 		return semanticConstraint{}, err
 	}
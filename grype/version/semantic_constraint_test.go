@@ -82,6 +82,11 @@ func TestVersionSemantic(t *testing.T) {
 		{version: "1.2.0-beta", constraint: ">1.0", satisfied: true},
 		{version: "1.2.0-beta", constraint: "<2.0", satisfied: true},
 		{version: "1.2.0", constraint: ">1.0, <2.0", satisfied: true},
+		// grouped and exclusive bounds
+		{version: "1.2.0", constraint: "(>= 1.0, < 1.5 || >= 2.0, < 2.5), != 1.2.0", satisfied: false},
+		{version: "1.3.0", constraint: "(>= 1.0, < 1.5 || >= 2.0, < 2.5), != 1.2.0", satisfied: true},
+		{version: "2.1.0", constraint: "(>= 1.0 && < 1.5 || >= 2.0 && < 2.5) && != 1.2.0", satisfied: true},
+		{version: "1.7.0", constraint: "(>= 1.0, < 1.5 || >= 2.0, < 2.5), != 1.2.0", satisfied: false},
 	}
 
 	for _, test := range tests {
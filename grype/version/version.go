@@ -29,6 +29,9 @@ type rich struct {
 	portVer       *portageVersion
 	pep440version *pep440Version
 	jvmVersion    *jvmVersion
+	gitVer        *gitVersion
+	opkgVer       *opkgVersion
+	alpmVer       *alpmVersion
 }
 
 func NewVersion(raw string, format Format) (*Version, error) {
@@ -71,6 +74,14 @@ func (v *Version) populate() error {
 		ver, err := newDebVersion(v.Raw)
 		v.rich.debVer = ver
 		return err
+	case OpkgFormat:
+		ver, err := newOpkgVersion(v.Raw)
+		v.rich.opkgVer = ver
+		return err
+	case AlpmFormat:
+		ver, err := newAlpmVersion(v.Raw)
+		v.rich.alpmVer = ver
+		return err
 	case GolangFormat:
 		ver, err := newGolangVersion(v.Raw)
 		v.rich.golangVersion = ver
@@ -103,6 +114,10 @@ func (v *Version) populate() error {
 		ver, err := newJvmVersion(v.Raw)
 		v.rich.jvmVersion = ver
 		return err
+	case GitFormat:
+		ver, err := newGitVersion(v.Raw)
+		v.rich.gitVer = ver
+		return err
 	case UnknownFormat:
 		// use the raw string + fuzzy constraint
 		return nil
@@ -0,0 +1,130 @@
+package openvex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	openvex "github.com/openvex/go-vex/pkg/vex"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/packageurl-go"
+	"github.com/anchore/syft/syft/source"
+)
+
+// GenerateOptions configures the document created by Generate.
+type GenerateOptions struct {
+	// Author identifies who made the triage decisions (defaults to the OpenVEX default author).
+	Author string
+
+	// Tooling describes the tool that generated the document (e.g. "grype 0.80.0").
+	Tooling string
+}
+
+// Generate creates an OpenVEX document with a not_affected statement for each ignored match that was ignored by a
+// triage decision (that is, an ignore rule configured by a user rather than one derived from options like
+// --only-fixed or from VEX data). Each statement applies to the scanned product with the matched package as the
+// subcomponent, using the justification recorded on the rule and the rule reason as the impact statement.
+func Generate(pkgContext *pkg.Context, ignoredMatches []match.IgnoredMatch, opts GenerateOptions) (*openvex.VEX, error) {
+	product, err := productFromContext(pkgContext)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := openvex.New()
+	if opts.Author != "" {
+		doc.Author = opts.Author
+	}
+	doc.Tooling = opts.Tooling
+
+	sorted := append([]match.IgnoredMatch{}, ignoredMatches...)
+	match.SortIgnored(sorted)
+
+	for _, ignored := range sorted {
+		rule := triageRule(ignored.AppliedIgnoreRules)
+		if rule == nil {
+			continue
+		}
+
+		statementProduct := product
+		if ignored.Package.PURL != "" {
+			statementProduct.Subcomponents = []openvex.Subcomponent{
+				{Component: openvex.Component{ID: ignored.Package.PURL}},
+			}
+		}
+
+		statement := openvex.Statement{
+			Vulnerability:   openvex.Vulnerability{Name: openvex.VulnerabilityID(ignored.Vulnerability.ID)},
+			Products:        []openvex.Product{statementProduct},
+			Status:          openvex.StatusNotAffected,
+			Justification:   Justification(*rule),
+			ImpactStatement: rule.Reason,
+		}
+		if statement.Justification == "" && statement.ImpactStatement == "" {
+			// a not_affected statement must explain itself one way or the other
+			statement.ImpactStatement = "ignored by a grype ignore rule"
+		}
+		doc.Statements = append(doc.Statements, statement)
+	}
+
+	if _, err := doc.GenerateCanonicalID(); err != nil {
+		return nil, fmt.Errorf("unable to generate VEX document ID: %w", err)
+	}
+	return &doc, nil
+}
+
+// Justification returns the VEX justification for matches ignored by the given rule: the vex-justification of the
+// rule, when it is a valid justification. The justification is a machine-readable claim that consumers act on, so it
+// is never guessed from the free-text reason of the rule (which is kept as the impact statement instead).
+func Justification(rule match.IgnoreRule) openvex.Justification {
+	if j := openvex.Justification(rule.VexJustification); j.Valid() {
+		return j
+	}
+	return ""
+}
+
+// triageRule returns the first of the applied rules that represents a triage decision, if any.
+func triageRule(rules []match.IgnoreRule) *match.IgnoreRule {
+	for i, r := range rules {
+		// rules with a VEX status come from VEX data, and rules by fix state or match type are derived from options
+		// (e.g. --only-fixed, or ignoring upstream kernel header matches) rather than being a decision about a finding
+		if r.VexStatus != "" || r.FixState != "" || r.MatchType != "" {
+			continue
+		}
+		return &rules[i]
+	}
+	return nil
+}
+
+// productFromContext describes the scanned product: images are identified by an OCI package URL of their digest,
+// while other sources are identified by their name and version.
+func productFromContext(pkgContext *pkg.Context) (openvex.Product, error) {
+	if pkgContext == nil || pkgContext.Source == nil {
+		return openvex.Product{}, errors.New("no source to identify the VEX product with")
+	}
+
+	if _, ok := pkgContext.Source.Metadata.(source.ImageMetadata); ok {
+		identifiers, err := productIdentifiersFromContext(pkgContext)
+		if err != nil {
+			return openvex.Product{}, err
+		}
+		for _, id := range identifiers {
+			if strings.HasPrefix(id, "pkg:oci/") {
+				return openvex.Product{Component: openvex.Component{
+					ID:          id,
+					Identifiers: map[openvex.IdentifierType]string{openvex.PURL: id},
+				}}, nil
+			}
+		}
+	}
+
+	if pkgContext.Source.Name == "" {
+		return openvex.Product{}, errors.New("the scanned source has no name or digest to identify the VEX product with (use --name)")
+	}
+	id := packageurl.NewPackageURL(packageurl.TypeGeneric, "", pkgContext.Source.Name, pkgContext.Source.Version, nil, "").String()
+	return openvex.Product{Component: openvex.Component{
+		ID:          id,
+		Identifiers: map[openvex.IdentifierType]string{openvex.PURL: id},
+	}}, nil
+}
@@ -0,0 +1,120 @@
+package openvex
+
+import (
+	"testing"
+
+	openvex "github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/source"
+)
+
+func ignoredMatch(vulnID, purl string, rules ...match.IgnoreRule) match.IgnoredMatch {
+	return match.IgnoredMatch{
+		Match: match.Match{
+			Vulnerability: vulnerability.Vulnerability{ID: vulnID},
+			Package:       pkg.Package{Name: "pkg", Version: "1.0.0", PURL: purl},
+		},
+		AppliedIgnoreRules: rules,
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	imageContext := &pkg.Context{
+		Source: &source.Description{
+			Metadata: source.ImageMetadata{
+				RepoDigests: []string{"alpine@sha256:124c7d2707904eea7431fffe91522a01e5a861a624ee31d03372cc1d138a3126"},
+			},
+		},
+	}
+	const imagePURL = "pkg:oci/alpine@sha256%3A124c7d2707904eea7431fffe91522a01e5a861a624ee31d03372cc1d138a3126?repository_url=index.docker.io/library"
+
+	ignored := []match.IgnoredMatch{
+		ignoredMatch("CVE-2020-0002", "pkg:apk/alpine/zlib@1.0.0",
+			match.IgnoreRule{Vulnerability: "CVE-2020-0002", Reason: "vulnerable code is not reachable from our service"}),
+		ignoredMatch("CVE-2020-0001", "pkg:apk/alpine/busybox@1.0.0",
+			match.IgnoreRule{Vulnerability: "CVE-2020-0001", VexJustification: "inline_mitigations_already_exist", Reason: "seccomp profile"}),
+		// derived from --only-fixed, not a triage decision
+		ignoredMatch("CVE-2020-0003", "pkg:apk/alpine/musl@1.0.0", match.IgnoreRule{FixState: "not-fixed"}),
+		// from VEX data, which is already shared in VEX form
+		ignoredMatch("CVE-2020-0004", "pkg:apk/alpine/musl@1.0.0", match.IgnoreRule{VexStatus: "not_affected"}),
+		ignoredMatch("CVE-2020-0005", "", match.IgnoreRule{Package: match.IgnoreRulePackage{Name: "pkg"}}),
+	}
+
+	doc, err := Generate(imageContext, ignored, GenerateOptions{Author: "security@example.com", Tooling: "grype 1.0.0"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "security@example.com", doc.Author)
+	assert.Equal(t, "grype 1.0.0", doc.Tooling)
+	assert.NotEmpty(t, doc.ID)
+
+	product := openvex.Component{ID: imagePURL, Identifiers: map[openvex.IdentifierType]string{openvex.PURL: imagePURL}}
+	assert.Equal(t, []openvex.Statement{
+		{
+			Vulnerability: openvex.Vulnerability{Name: "CVE-2020-0001"},
+			Products: []openvex.Product{{
+				Component:     product,
+				Subcomponents: []openvex.Subcomponent{{Component: openvex.Component{ID: "pkg:apk/alpine/busybox@1.0.0"}}},
+			}},
+			Status:          openvex.StatusNotAffected,
+			Justification:   openvex.InlineMitigationsAlreadyExist,
+			ImpactStatement: "seccomp profile",
+		},
+		{
+			Vulnerability: openvex.Vulnerability{Name: "CVE-2020-0002"},
+			Products: []openvex.Product{{
+				Component:     product,
+				Subcomponents: []openvex.Subcomponent{{Component: openvex.Component{ID: "pkg:apk/alpine/zlib@1.0.0"}}},
+			}},
+			Status: openvex.StatusNotAffected,
+			// the reason is not turned into a justification
+			ImpactStatement: "vulnerable code is not reachable from our service",
+		},
+		{
+			Vulnerability:   openvex.Vulnerability{Name: "CVE-2020-0005"},
+			Products:        []openvex.Product{{Component: product}},
+			Status:          openvex.StatusNotAffected,
+			ImpactStatement: "ignored by a grype ignore rule",
+		},
+	}, doc.Statements)
+
+	for _, s := range doc.Statements {
+		require.NoError(t, s.Validate())
+	}
+
+	// the generated document is honored when read back for the same image
+	matches := doc.Matches("CVE-2020-0002", imagePURL, []string{"pkg:apk/alpine/zlib@1.0.0"})
+	assert.Len(t, matches, 1)
+}
+
+func TestGenerate_product(t *testing.T) {
+	doc, err := Generate(&pkg.Context{Source: &source.Description{Name: "my-app", Version: "1.2.3", Metadata: source.DirectoryMetadata{Path: "."}}},
+		[]match.IgnoredMatch{ignoredMatch("CVE-2020-0001", "", match.IgnoreRule{Vulnerability: "CVE-2020-0001"})}, GenerateOptions{})
+	require.NoError(t, err)
+	require.Len(t, doc.Statements, 1)
+	assert.Equal(t, "pkg:generic/my-app@1.2.3", doc.Statements[0].Products[0].ID)
+
+	_, err = Generate(&pkg.Context{Source: &source.Description{Metadata: source.DirectoryMetadata{Path: "."}}}, nil, GenerateOptions{})
+	require.ErrorContains(t, err, "--name")
+}
+
+func TestJustification(t *testing.T) {
+	tests := []struct {
+		rule match.IgnoreRule
+		want openvex.Justification
+	}{
+		{rule: match.IgnoreRule{VexJustification: "component_not_present", Reason: "not reachable"}, want: openvex.ComponentNotPresent},
+		{rule: match.IgnoreRule{VexJustification: "bogus", Reason: "vulnerable_code_not_present"}, want: ""},
+		{rule: match.IgnoreRule{Reason: "Vulnerable code not in execute path"}, want: ""},
+		{rule: match.IgnoreRule{Reason: "mitigated by the WAF"}, want: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.rule.Reason, func(t *testing.T) {
+			assert.Equal(t, test.want, Justification(test.rule))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	openvex "github.com/openvex/go-vex/pkg/vex"
@@ -12,6 +13,7 @@ import (
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/packageurl-go"
+	"github.com/anchore/syft/syft/cpe"
 	"github.com/anchore/syft/syft/source"
 )
 
@@ -66,8 +68,8 @@ func productIdentifiersFromContext(pkgContext *pkg.Context) ([]string, error) {
 		// could generate more identifiers to match better.
 		return identifiersFromDigests(v.RepoDigests), nil
 	default:
-		// Fail for now
-		return nil, errors.New("source type not supported for VEX")
+		// only statements about the packages themselves (by purl or CPE) can apply to other sources
+		return nil, nil
 	}
 }
 
@@ -135,6 +137,99 @@ func subcomponentIdentifiersFromMatch(m *match.Match) []string {
 	return ret
 }
 
+// matchingStatements returns the statements about the vulnerability of the match, most relevant first, along with
+// how they were found. Statements about the scanned product (e.g. the image) take precedence over statements about
+// the matched package itself, which are typically published by the package maintainers and identify the package by
+// purl or CPE.
+func matchingStatements(doc *openvex.VEX, products []string, m *match.Match) ([]openvex.Statement, *SearchedBy) {
+	subcmp := subcomponentIdentifiersFromMatch(m)
+
+	// Range through the product's different names
+	for _, product := range products {
+		if statements := doc.Matches(m.Vulnerability.ID, product, subcmp); len(statements) != 0 {
+			return statements, &SearchedBy{Vulnerability: m.Vulnerability.ID, Product: product, Subcomponents: subcmp}
+		}
+	}
+
+	if m.Package.PURL != "" {
+		if statements := doc.Matches(m.Vulnerability.ID, m.Package.PURL, nil); len(statements) != 0 {
+			return statements, &SearchedBy{Vulnerability: m.Vulnerability.ID, Product: m.Package.PURL}
+		}
+	}
+
+	for _, c := range m.Package.CPEs {
+		if statements := statementsByCPE(doc, m.Vulnerability.ID, c.Attributes); len(statements) != 0 {
+			return statements, &SearchedBy{Vulnerability: m.Vulnerability.ID, Product: c.Attributes.BindToFmtString()}
+		}
+	}
+
+	return nil, nil
+}
+
+// statementsByCPE returns the statements about the vulnerability with a product identified by a CPE that describes
+// the given package CPE, ordered the same as (*openvex.VEX).Matches orders statements.
+func statementsByCPE(doc *openvex.VEX, vulnID string, pkgCPE cpe.Attributes) []openvex.Statement {
+	var statements []openvex.Statement
+	for i := len(doc.Statements) - 1; i >= 0; i-- {
+		s := doc.Statements[i]
+		if !s.Vulnerability.Matches(vulnID) {
+			continue
+		}
+		for _, p := range s.Products {
+			if componentMatchesCPE(p.Component, pkgCPE) {
+				statements = append(statements, s)
+				break
+			}
+		}
+	}
+
+	var t time.Time
+	if doc.Timestamp != nil {
+		t = *doc.Timestamp
+	}
+	openvex.SortStatements(statements, t)
+	return statements
+}
+
+// componentMatchesCPE reports whether the component is identified by a CPE that describes the given package CPE.
+// Fields left unspecified in the component CPE (e.g. the version) match any value, except for the vendor and the
+// product, which a CPE must name to identify a component at all (cpe:2.3:*:*:*:... would describe every package).
+func componentMatchesCPE(c openvex.Component, pkgCPE cpe.Attributes) bool {
+	candidates := []string{c.ID}
+	for t, id := range c.Identifiers {
+		if t == openvex.CPE22 || t == openvex.CPE23 {
+			candidates = append(candidates, id)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if !strings.HasPrefix(candidate, "cpe:") {
+			continue
+		}
+		attrs, err := cpe.NewAttributes(candidate)
+		if err != nil || !cpeFieldNamed(attrs.Vendor) || !cpeFieldNamed(attrs.Product) {
+			continue
+		}
+		if cpeFieldMatches(attrs.Part, pkgCPE.Part) &&
+			cpeFieldMatches(attrs.Vendor, pkgCPE.Vendor) &&
+			cpeFieldMatches(attrs.Product, pkgCPE.Product) &&
+			cpeFieldMatches(attrs.Version, pkgCPE.Version) &&
+			cpeFieldMatches(attrs.TargetSW, pkgCPE.TargetSW) {
+			return true
+		}
+	}
+	return false
+}
+
+// cpeFieldNamed reports whether the CPE field names a value, rather than any value or no value (NA).
+func cpeFieldNamed(value string) bool {
+	return value != cpe.Any && value != "*" && value != "-"
+}
+
+func cpeFieldMatches(statementValue, pkgValue string) bool {
+	return statementValue == cpe.Any || strings.EqualFold(statementValue, pkgValue)
+}
+
 // FilterMatches takes a set of scanning results and moves any results marked in
 // the VEX data as fixed or not_affected to the ignored list.
 func (ovm *Processor) FilterMatches(
@@ -159,14 +254,8 @@ func (ovm *Processor) FilterMatches(
 	sorted := matches.Sorted()
 	for i := range sorted {
 		var statement *openvex.Statement
-		subcmp := subcomponentIdentifiersFromMatch(&sorted[i])
-
-		// Range through the product's different names
-		for _, product := range products {
-			if matchingStatements := doc.Matches(sorted[i].Vulnerability.ID, product, subcmp); len(matchingStatements) != 0 {
-				statement = &matchingStatements[0]
-				break
-			}
+		if statements, _ := matchingStatements(doc, products, &sorted[i]); len(statements) != 0 {
+			statement = &statements[0]
 		}
 
 		// No data about this match's component. Next.
@@ -212,6 +301,8 @@ func matchingRule(ignoreRules []match.IgnoreRule, m match.Match, statement *open
 		if rule.HasConditions() {
 			r := rule
 			r.VexStatus = ""
+			// the environment of the rule was checked by the processor
+			r.Environment = ""
 			if _, ignored := match.ApplyIgnoreRules(ms, []match.IgnoreRule{r}); len(ignored) == 0 {
 				continue
 			}
@@ -273,25 +364,10 @@ func (ovm *Processor) AugmentMatches(
 	// Now, let's go through grype's matches
 	for i := range ignoredMatches {
 		var statement *openvex.Statement
-		var searchedBy *SearchedBy
-		subcmp := subcomponentIdentifiersFromMatch(&ignoredMatches[i].Match)
-
-		// Range through the product's different names to see if they match the
-		// statement data
-		for _, product := range products {
-			if matchingStatements := doc.Matches(ignoredMatches[i].Vulnerability.ID, product, subcmp); len(matchingStatements) != 0 {
-				if matchingStatements[0].Status != openvex.StatusAffected &&
-					matchingStatements[0].Status != openvex.StatusUnderInvestigation {
-					break
-				}
-				statement = &matchingStatements[0]
-				searchedBy = &SearchedBy{
-					Vulnerability: ignoredMatches[i].Vulnerability.ID,
-					Product:       product,
-					Subcomponents: subcmp,
-				}
-				break
-			}
+		statements, searchedBy := matchingStatements(doc, products, &ignoredMatches[i].Match)
+		if len(statements) != 0 && (statements[0].Status == openvex.StatusAffected ||
+			statements[0].Status == openvex.StatusUnderInvestigation) {
+			statement = &statements[0]
 		}
 
 		// No data about this match's component. Next.
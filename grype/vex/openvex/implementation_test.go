@@ -3,7 +3,14 @@ package openvex
 import (
 	"testing"
 
+	openvex "github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
 )
 
 func TestIdentifiersFromDigests(t *testing.T) {
@@ -36,3 +43,85 @@ func TestIdentifiersFromDigests(t *testing.T) {
 		require.Equal(t, tc.expected, res)
 	}
 }
+
+func Test_matchingStatements(t *testing.T) {
+	const image = "pkg:oci/alpine@sha256%3A124c7d2707904eea7431fffe91522a01e5a861a624ee31d03372cc1d138a3126?repository_url=index.docker.io/library"
+
+	statement := func(vulnID string, status openvex.Status, product openvex.Component) openvex.Statement {
+		return openvex.Statement{
+			Vulnerability: openvex.Vulnerability{Name: openvex.VulnerabilityID(vulnID)},
+			Products:      []openvex.Product{{Component: product}},
+			Status:        status,
+		}
+	}
+
+	doc := openvex.New()
+	doc.Statements = []openvex.Statement{
+		// published by the library maintainers, for any version of the package
+		statement("CVE-2023-0001", openvex.StatusNotAffected, openvex.Component{ID: "pkg:golang/github.com/example/lib"}),
+		statement("CVE-2023-0002", openvex.StatusNotAffected, openvex.Component{ID: "pkg:golang/github.com/example/lib@v1.0.0"}),
+		statement("CVE-2023-0003", openvex.StatusFixed, openvex.Component{
+			Identifiers: map[openvex.IdentifierType]string{openvex.CPE23: "cpe:2.3:a:example:lib:*:*:*:*:*:*:*:*"},
+		}),
+		statement("CVE-2023-0004", openvex.StatusNotAffected, openvex.Component{ID: "cpe:/a:example:lib:2.0.0"}),
+		// CPEs that do not name a vendor and a product identify no component
+		statement("CVE-2023-0006", openvex.StatusNotAffected, openvex.Component{ID: "cpe:2.3:*:*:*:*:*:*:*:*:*:*:*"}),
+		statement("CVE-2023-0007", openvex.StatusNotAffected, openvex.Component{
+			Identifiers: map[openvex.IdentifierType]string{openvex.CPE23: "cpe:2.3:a:*:lib:*:*:*:*:*:*:*:*"},
+		}),
+		// the image-scoped statement takes precedence over the package-level one
+		statement("CVE-2023-0005", openvex.StatusNotAffected, openvex.Component{ID: "pkg:golang/github.com/example/lib"}),
+		{
+			Vulnerability: openvex.Vulnerability{Name: "CVE-2023-0005"},
+			Products: []openvex.Product{{
+				Component:     openvex.Component{ID: image},
+				Subcomponents: []openvex.Subcomponent{{Component: openvex.Component{ID: "pkg:golang/github.com/example/lib@v1.0.0"}}},
+			}},
+			Status: openvex.StatusAffected,
+		},
+	}
+
+	libCPE := cpe.Must("cpe:2.3:a:example:lib:1.0.0:*:*:*:*:*:*:*", "")
+	newMatch := func(vulnID string) *match.Match {
+		return &match.Match{
+			Vulnerability: vulnerability.Vulnerability{ID: vulnID},
+			Package: pkg.Package{
+				Name:    "github.com/example/lib",
+				Version: "v1.0.0",
+				PURL:    "pkg:golang/github.com/example/lib@v1.0.0",
+				CPEs:    []cpe.CPE{libCPE},
+			},
+		}
+	}
+
+	tests := []struct {
+		vulnID      string
+		products    []string
+		wantStatus  openvex.Status
+		wantProduct string
+	}{
+		{vulnID: "CVE-2023-0001", wantStatus: openvex.StatusNotAffected, wantProduct: "pkg:golang/github.com/example/lib@v1.0.0"},
+		{vulnID: "CVE-2023-0002", wantStatus: openvex.StatusNotAffected, wantProduct: "pkg:golang/github.com/example/lib@v1.0.0"},
+		{vulnID: "CVE-2023-0003", wantStatus: openvex.StatusFixed, wantProduct: libCPE.Attributes.BindToFmtString()},
+		// the statement is about another version of the package
+		{vulnID: "CVE-2023-0004"},
+		{vulnID: "CVE-2023-0005", products: []string{image}, wantStatus: openvex.StatusAffected, wantProduct: image},
+		{vulnID: "CVE-2023-0005", wantStatus: openvex.StatusNotAffected, wantProduct: "pkg:golang/github.com/example/lib@v1.0.0"},
+		{vulnID: "CVE-2023-0006"},
+		{vulnID: "CVE-2023-0007"},
+		{vulnID: "CVE-2023-9999"},
+	}
+	for _, test := range tests {
+		t.Run(test.vulnID, func(t *testing.T) {
+			statements, searchedBy := matchingStatements(&doc, test.products, newMatch(test.vulnID))
+			if test.wantStatus == "" {
+				assert.Empty(t, statements)
+				assert.Nil(t, searchedBy)
+				return
+			}
+			require.NotEmpty(t, statements)
+			assert.Equal(t, test.wantStatus, statements[0].Status)
+			assert.Equal(t, test.wantProduct, searchedBy.Product)
+		})
+	}
+}
@@ -60,6 +60,9 @@ func NewProcessor(opts ProcessorOptions) *Processor {
 type ProcessorOptions struct {
 	Documents   []string
 	IgnoreRules []match.IgnoreRule
+	// Environment is the environment context of the scans (e.g. "env" is "dev"), outside of which the ignore rules
+	// restricted to an environment do not apply.
+	Environment map[string]string
 }
 
 // ApplyVEX receives the results from a scan run and applies any VEX information
@@ -79,7 +82,7 @@ func (vm *Processor) ApplyVEX(pkgContext *pkg.Context, remainingMatches *match.M
 		return nil, nil, fmt.Errorf("parsing vex document: %w", err)
 	}
 
-	vexRules := extractVexRules(vm.Options.IgnoreRules)
+	vexRules := extractVexRules(vm.Options.IgnoreRules, vm.Options.Environment)
 
 	remainingMatches, ignoredMatches, err = vm.impl.FilterMatches(
 		rawVexData, vexRules, pkgContext, remainingMatches, ignoredMatches,
@@ -99,11 +102,11 @@ func (vm *Processor) ApplyVEX(pkgContext *pkg.Context, remainingMatches *match.M
 }
 
 // extractVexRules is a utility function that takes a set of ignore rules and
-// extracts those that act on VEX statuses.
-func extractVexRules(rules []match.IgnoreRule) []match.IgnoreRule {
+// extracts those that act on VEX statuses in the given environment.
+func extractVexRules(rules []match.IgnoreRule, environment map[string]string) []match.IgnoreRule {
 	newRules := []match.IgnoreRule{}
 	for _, r := range rules {
-		if r.VexStatus != "" {
+		if r.VexStatus != "" && r.AppliesInEnvironment(environment) {
 			newRules = append(newRules, r)
 			newRules[len(newRules)-1].Namespace = "vex"
 		}
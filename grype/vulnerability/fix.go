@@ -16,4 +16,7 @@ func AllFixStates() []grypeDb.FixState {
 type Fix struct {
 	Versions []string
 	State    grypeDb.FixState
+	// UpgradeRequired is true when the fixes are all in other release streams than the installed version (e.g. only
+	// in the openssl 3 stream of a newer distro release for an installed openssl 1.1.1).
+	UpgradeRequired bool
 }
@@ -1,6 +1,8 @@
 package vulnerability
 
 import (
+	"time"
+
 	grypeDB "github.com/anchore/grype/grype/db/v5"
 )
 
@@ -12,6 +14,12 @@ type Metadata struct {
 	URLs        []string
 	Description string
 	Cvss        []Cvss
+	Published   *time.Time // when the vulnerability was published by its source, if known
+	Modified    *time.Time // when the vulnerability was last modified by its source, if known
+
+	OriginalSeverity       string   // the severity from the vulnerability data when Severity was overridden
+	SeverityOverrideReason string   // why Severity was overridden
+	AdjustedScore          *float64 // the CVSS score adjusted for temporal and environmental metrics that Severity was derived from
 }
 
 type Cvss struct {
@@ -41,6 +49,8 @@ func NewMetadata(m *grypeDB.VulnerabilityMetadata) (*Metadata, error) {
 		URLs:        m.URLs,
 		Description: m.Description,
 		Cvss:        NewCvss(m.Cvss),
+		Published:   m.Published,
+		Modified:    m.Modified,
 	}, nil
 }
 
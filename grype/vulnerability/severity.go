@@ -42,6 +42,15 @@ func (f Severity) String() string {
 	return matcherTypeStr[f]
 }
 
+// Name returns the severity the way severities of the vulnerability data are reported (e.g. "High" or "Unknown").
+func (f Severity) Name() string {
+	if f == UnknownSeverity {
+		return "Unknown"
+	}
+	name := f.String()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
 func ParseSeverity(severity string) Severity {
 	switch strings.ToLower(severity) {
 	case NegligibleSeverity.String():
@@ -24,6 +24,7 @@ type Vulnerability struct {
 	Fix                    Fix
 	Advisories             []Advisory
 	RelatedVulnerabilities []Reference
+	Providers              []string // the providers that reported the vulnerability, when several providers are combined (e.g. "grype-db" and "osv.dev")
 }
 
 func NewVulnerability(vuln grypeDB.Vulnerability) (*Vulnerability, error) {
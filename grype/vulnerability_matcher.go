@@ -1,6 +1,8 @@
 package grype
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -8,8 +10,11 @@ import (
 	"github.com/wagoodman/go-partybus"
 	"github.com/wagoodman/go-progress"
 
+	"github.com/anchore/go-logger"
+	"github.com/anchore/grype/grype/alias"
 	grypeDb "github.com/anchore/grype/grype/db/v5"
 	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/environment"
 	"github.com/anchore/grype/grype/event"
 	"github.com/anchore/grype/grype/event/monitor"
 	"github.com/anchore/grype/grype/grypeerr"
@@ -17,7 +22,10 @@ import (
 	"github.com/anchore/grype/grype/matcher"
 	"github.com/anchore/grype/grype/matcher/stock"
 	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/pkg/qualifier/configuration"
+	"github.com/anchore/grype/grype/severity"
 	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/trust"
 	"github.com/anchore/grype/grype/vex"
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/grype/internal/bus"
@@ -38,6 +46,83 @@ type VulnerabilityMatcher struct {
 	FailSeverity   *vulnerability.Severity
 	NormalizeByCVE bool
 	VexProcessor   *vex.Processor
+	Aliases        *alias.Resolver
+
+	// SeverityOverrides re-rate vulnerabilities for gating and presentation, in place of the ratings from the DB.
+	SeverityOverrides *severity.Overrides
+
+	// TrustWeights weigh the vulnerability data of each provider, to choose the record (and so the severity) reported
+	// for a vulnerability reported by several providers, and to scale the confidence of the matches.
+	TrustWeights *trust.Weights
+
+	// DisabledFeatures are the optional features (or configurations) of packages that are not enabled (e.g. "mod_lua"
+	// of apache2): the matches of vulnerabilities of a package requiring only features disabled for that package are
+	// ignored as not affected.
+	DisabledFeatures []configuration.Disabled
+	// DisabledDistros are the linux distributions whose packages are not matched (and are reported as unsupported).
+	DisabledDistros []distro.Type
+
+	// Environment is the environment context of the scans (e.g. "env=dev"): the ignore rules restricted to another
+	// environment (or to any environment, without a context) do not apply.
+	Environment environment.Context
+
+	// Enrichers amend the packages before they are matched (the packages of the matches are the amended packages).
+	Enrichers []pkg.Enricher
+
+	// Logger and Bus receive the logs and progress events of the scans of this matcher, in place of the logger and bus
+	// set with SetLogger and SetBus, so that concurrent scans within one process can be told apart.
+	//
+	// Only the logs written by the VulnerabilityMatcher itself go to Logger: the progress of the scan, the failures of
+	// matchers, the matches found, dropped and ignored, and the summary of the scan. The packages, matchers, stores,
+	// VEX processors and enrichers it calls are shared by every scan and have no logger of their own, so their logs
+	// (e.g. a matcher skipping a malformed record, or syft cataloging the packages) are still written to the logger set
+	// with SetLogger, without telling the scans apart.
+	Logger logger.Logger
+	Bus    partybus.Publisher
+
+	// Strict fails the scan with a *grypeerr.DataQualityError when the results may be incomplete (e.g. packages that
+	// cannot be matched, or matchers that failed), which are otherwise only logged. The matches are still returned.
+	Strict bool
+
+	// Budget, when set, bounds the time of the scans: the packages not yet matched when its deadline passes are left
+	// unscanned (and listed by the budget), which is a data-quality problem in strict mode.
+	Budget *ScanBudget
+
+	// Rejections, when set, collects the candidate vulnerabilities that were considered but rejected by a filter (e.g.
+	// a version constraint that is not satisfied), to investigate missing matches.
+	Rejections *match.Rejections
+
+	// FuzzyRejections, when set, collects the rejections of the version constraints that were compared with the package
+	// version by a heuristic (see match.Rejection.Heuristic), to audit the heuristic comparisons that missed or failed
+	// along with those that matched.
+	FuzzyRejections *match.Rejections
+
+	// Context, when set, aborts the scans once it is done (e.g. when the memory budget of the process is exceeded),
+	// failing them with the cause of the context.
+	Context context.Context
+}
+
+// provider returns the vulnerability provider given to the matchers, which records the rejections of their filters
+// when the rejections are collected.
+func (m *VulnerabilityMatcher) provider() vulnerability.Provider {
+	if m.Rejections == nil && m.FuzzyRejections == nil {
+		return m.Store
+	}
+	return rejectionRecordingStore{Store: m.Store, rejections: m.Rejections, fuzzy: m.FuzzyRejections}
+}
+
+// rejectionRecordingStore is a store recording the rejections of the matchers (see match.RejectionRecorderOf).
+type rejectionRecordingStore struct {
+	store.Store
+	rejections *match.Rejections
+	fuzzy      *match.Rejections
+}
+
+func (s rejectionRecordingStore) RecordRejection(r match.Rejection) {
+	s.rejections.RecordRejection(r)
+	if r.Heuristic != "" {
+		s.fuzzy.RecordRejection(r)
+	}
 }
 
 func DefaultVulnerabilityMatcher(store store.Store) *VulnerabilityMatcher {
@@ -47,6 +132,41 @@ func DefaultVulnerabilityMatcher(store store.Store) *VulnerabilityMatcher {
 	}
 }
 
+// WithLogger sets the logger of the scans of this matcher (see VulnerabilityMatcher.Logger).
+func (m *VulnerabilityMatcher) WithLogger(l logger.Logger) *VulnerabilityMatcher {
+	m.Logger = l
+	return m
+}
+
+// WithBus sets the bus receiving the progress events of the scans of this matcher (see VulnerabilityMatcher.Bus).
+func (m *VulnerabilityMatcher) WithBus(b partybus.Publisher) *VulnerabilityMatcher {
+	m.Bus = b
+	return m
+}
+
+// canceled returns the cause of the context of the scans once it is done.
+func (m *VulnerabilityMatcher) canceled() error {
+	if m.Context == nil || m.Context.Err() == nil {
+		return nil
+	}
+	return context.Cause(m.Context)
+}
+
+func (m *VulnerabilityMatcher) log() logger.Logger {
+	if m.Logger == nil {
+		return log.Get()
+	}
+	return log.Redacted(m.Logger)
+}
+
+func (m *VulnerabilityMatcher) publish(e partybus.Event) {
+	if m.Bus == nil {
+		bus.Publish(e)
+		return
+	}
+	m.Bus.Publish(e)
+}
+
 func (m *VulnerabilityMatcher) FailAtOrAboveSeverity(severity *vulnerability.Severity) *VulnerabilityMatcher {
 	m.FailSeverity = severity
 	return m
@@ -63,7 +183,7 @@ func (m *VulnerabilityMatcher) WithIgnoreRules(ignoreRules []match.IgnoreRule) *
 }
 
 func (m *VulnerabilityMatcher) FindMatches(pkgs []pkg.Package, context pkg.Context) (remainingMatches *match.Matches, ignoredMatches []match.IgnoredMatch, err error) {
-	progressMonitor := trackMatcher(len(pkgs))
+	progressMonitor := m.trackMatcher(len(pkgs))
 
 	defer func() {
 		progressMonitor.Ignored.Set(int64(len(ignoredMatches)))
@@ -73,7 +193,13 @@ func (m *VulnerabilityMatcher) FindMatches(pkgs []pkg.Package, context pkg.Conte
 		}
 	}()
 
-	remainingMatches, ignoredMatches, err = m.findDBMatches(pkgs, context, progressMonitor)
+	pkgs, err = pkg.Enrich(pkgs, context, m.Enrichers...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	problems := &dataQualityProblems{}
+	remainingMatches, ignoredMatches, err = m.findDBMatches(pkgs, context, progressMonitor, problems)
 	if err != nil {
 		return remainingMatches, ignoredMatches, err
 	}
@@ -84,29 +210,60 @@ func (m *VulnerabilityMatcher) FindMatches(pkgs []pkg.Package, context pkg.Conte
 		return remainingMatches, ignoredMatches, err
 	}
 
+	if !m.TrustWeights.IsEmpty() {
+		weighed := m.TrustWeights.Apply(*remainingMatches, m.Store)
+		remainingMatches = &weighed
+		ignoredMatches = m.TrustWeights.ApplyIgnored(ignoredMatches, m.Store)
+	}
+
+	if !m.SeverityOverrides.IsEmpty() {
+		overridden := m.SeverityOverrides.Apply(*remainingMatches, m.Store)
+		remainingMatches = &overridden
+		ignoredMatches = m.SeverityOverrides.ApplyIgnored(ignoredMatches, m.Store)
+	}
+
 	if m.FailSeverity != nil && HasSeverityAtOrAbove(m.Store, *m.FailSeverity, *remainingMatches) {
 		err = grypeerr.ErrAboveSeverityThreshold
+	}
+
+	if m.Strict {
+		for _, u := range m.unsupportedPackages(pkgs, context) {
+			problems.add(string(u.Reason), displayPackage(u.Package), u.Reason.Description())
+		}
+		// the providers that failed, or served partial data (e.g. an online source that could not be reached)
+		problems.problems = append(problems.problems, m.Store.Degradations()...)
+		if len(problems.problems) > 0 {
+			err = errors.Join(err, &grypeerr.DataQualityError{Problems: problems.problems})
+		}
+	}
+
+	if err != nil {
 		return remainingMatches, ignoredMatches, err
 	}
 
-	logListSummary(progressMonitor)
+	logListSummary(m.log(), progressMonitor)
 
-	logIgnoredMatches(ignoredMatches)
+	logIgnoredMatches(m.log(), ignoredMatches)
 
 	return remainingMatches, ignoredMatches, nil
 }
 
-func (m *VulnerabilityMatcher) findDBMatches(pkgs []pkg.Package, context pkg.Context, progressMonitor *monitorWriter) (*match.Matches, []match.IgnoredMatch, error) {
+func (m *VulnerabilityMatcher) findDBMatches(pkgs []pkg.Package, context pkg.Context, progressMonitor *monitorWriter, problems *dataQualityProblems) (*match.Matches, []match.IgnoredMatch, error) {
 	var ignoredMatches []match.IgnoredMatch
 
-	log.Trace("finding matches against DB")
-	matches, err := m.searchDBForMatches(context.Distro, pkgs, progressMonitor)
+	m.log().Trace("finding matches against DB")
+	matches, err := m.searchDBForMatches(context.Distro, pkgs, progressMonitor, problems)
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to find matches in DB: %w", err)
 	}
 
+	matches = selectStreamFixes(matches)
+
 	matches, ignoredMatches = m.applyIgnoreRules(matches)
 
+	matches, disabledMatches := m.applyDisabledFeatures(matches)
+	ignoredMatches = append(ignoredMatches, disabledMatches...)
+
 	if m.NormalizeByCVE {
 		normalizedMatches := match.NewMatches()
 		for originalMatch := range matches.Enumerate() {
@@ -142,19 +299,21 @@ func (m *VulnerabilityMatcher) searchDBForMatches(
 	release *linux.Release,
 	packages []pkg.Package,
 	progressMonitor *monitorWriter,
+	problems *dataQualityProblems,
 ) (match.Matches, error) {
 	var err error
 	res := match.NewMatches()
-	matcherIndex, defaultMatcher := newMatcherIndex(m.Matchers)
+	matcherIndex, defaultMatcher := newMatcherIndex(m.log(), m.Matchers)
 
 	var d *distro.Distro
 	if release != nil {
 		d, err = distro.NewFromRelease(*release)
 		if err != nil {
-			log.Warnf("unable to determine linux distribution: %+v", err)
+			m.log().Warnf("unable to determine linux distribution: %+v", err)
+			problems.add(unknownDistroProblem, "scan target", err.Error())
 		}
-		if d != nil && d.Disabled() {
-			log.Warnf("unsupported linux distribution: %s", d.Name())
+		if d != nil && d.Disabled(m.DisabledDistros) {
+			m.log().Warnf("unsupported linux distribution: %s", d.Name())
 			return match.NewMatches(), nil
 		}
 	}
@@ -170,29 +329,65 @@ func (m *VulnerabilityMatcher) searchDBForMatches(
 	if defaultMatcher == nil {
 		defaultMatcher = stock.NewStockMatcher(stock.MatcherConfig{UseCPEs: true})
 	}
-	for _, p := range packages {
+	packageDistros := make(map[*linux.Release]packageDistroResult)
+	skip := func(unscanned []pkg.Package) {
+		m.log().WithFields("unscanned", len(unscanned), "deadline", m.Budget.Deadline).Warn("the scan ran out of time, the results are incomplete")
+		m.Budget.skip(unscanned...)
+		for _, u := range unscanned {
+			problems.add(scanTimeExceededProblem, displayPackage(u), "the package was not scanned within the maximum scan time")
+		}
+	}
+packages:
+	for i, p := range packages {
+		if err := m.canceled(); err != nil {
+			return match.Matches{}, err
+		}
+		if m.Budget.expired() {
+			skip(packages[i:])
+			break
+		}
 		progressMonitor.PackagesProcessed.Increment()
-		log.WithFields("package", displayPackage(p)).Trace("searching for vulnerability matches")
+		m.log().WithFields("package", displayPackage(p)).Trace("searching for vulnerability matches")
 
-		matchAgainst, ok := matcherIndex[p.Type]
+		d := d
+		if p.Distro != nil {
+			var ok bool
+			if d, ok = packageDistro(m.log(), p, packageDistros, m.DisabledDistros); !ok {
+				continue
+			}
+		}
+
+		matchAgainst, ok := matcherIndex[matcherPackageType(p)]
 		if !ok {
 			matchAgainst = []matcher.Matcher{defaultMatcher}
 		}
 		for _, theMatcher := range matchAgainst {
-			matches, err := theMatcher.Match(m.Store, d, p)
+			matches, err := m.match(theMatcher, d, p)
+			if errors.Is(err, ErrScanTimeExceeded) {
+				// the package being matched is left unscanned as well, since its matcher was abandoned
+				skip(packages[i:])
+				break packages
+			}
+			if cause := m.canceled(); cause != nil {
+				return match.Matches{}, cause
+			}
 			if err != nil {
-				log.WithFields("error", err, "package", displayPackage(p)).Warn("matcher failed")
+				m.log().WithFields("error", err, "package", displayPackage(p)).Warn("matcher failed")
+				problems.add(matcherFailedProblem, displayPackage(p), fmt.Sprintf("%s matcher failed: %v", theMatcher.Type(), err))
 				continue
 			}
 
-			matches = filterMatchesUsingDistroFalsePositives(matches, distroFalsePositivesByLocationPath)
+			matches = append(matches, m.searchAliases(theMatcher, d, p, matches)...)
+
+			matches = filterMatchesUsingDistroFalsePositives(m.log(), m.Rejections, matches, distroFalsePositivesByLocationPath)
 
 			// Filter out matches based on records in the database exclusion table and hard-coded rules
 			filtered, dropped := match.ApplyExplicitIgnoreRules(m.Store, match.NewMatches(matches...))
 
 			additionalMatches := filtered.Sorted()
-			logPackageMatches(p, additionalMatches)
-			logExplicitDroppedPackageMatches(p, dropped)
+			logPackageMatches(m.log(), p, additionalMatches)
+			logExplicitDroppedPackageMatches(m.log(), p, dropped)
+			recordRejectedMatches(m.Rejections, match.ExclusionRejection, dropped)
 			res.Add(additionalMatches...)
 
 			progressMonitor.MatchesDiscovered.Add(int64(len(additionalMatches)))
@@ -207,6 +402,129 @@ func (m *VulnerabilityMatcher) searchDBForMatches(
 	return res, nil
 }
 
+// match runs the matcher for the package, abandoning it (its result is discarded) once the deadline of the budget
+// passes or the context of the scans is done, so that a slow matcher (or vulnerability query) cannot hold the scan past
+// its budget.
+func (m *VulnerabilityMatcher) match(theMatcher matcher.Matcher, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	if m.Budget == nil && m.Context == nil {
+		return theMatcher.Match(m.provider(), d, p)
+	}
+
+	type result struct {
+		matches []match.Match
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		matches, err := theMatcher.Match(m.provider(), d, p)
+		done <- result{matches: matches, err: err}
+	}()
+
+	deadline, stop := m.Budget.timer()
+	defer stop()
+	var canceled <-chan struct{}
+	if m.Context != nil {
+		canceled = m.Context.Done()
+	}
+	select {
+	case r := <-done:
+		return r.matches, r.err
+	case <-deadline:
+		return nil, ErrScanTimeExceeded
+	case <-canceled:
+		return nil, context.Cause(m.Context)
+	}
+}
+
+const (
+	unknownDistroProblem    = "unknown-distro"
+	matcherFailedProblem    = "matcher-failed"
+	scanTimeExceededProblem = "scan-time-exceeded"
+)
+
+// dataQualityProblems collects the problems of a scan that fail it in strict mode.
+type dataQualityProblems struct {
+	problems []grypeerr.DataQualityProblem
+}
+
+func (d *dataQualityProblems) add(kind, subject, message string) {
+	d.problems = append(d.problems, grypeerr.DataQualityProblem{Kind: kind, Subject: subject, Message: message})
+}
+
+type packageDistroResult struct {
+	distro   *distro.Distro
+	disabled bool
+}
+
+// packageDistro returns the distro set on the package (e.g. by an enricher) in place of the distro of the scan target,
+// which is nil when the distro cannot be determined (as for the scan target), or false if the distro is not supported.
+func packageDistro(logs logger.Logger, p pkg.Package, distros map[*linux.Release]packageDistroResult, disabled []distro.Type) (*distro.Distro, bool) {
+	if r, ok := distros[p.Distro]; ok {
+		return r.distro, !r.disabled
+	}
+	var r packageDistroResult
+	d, err := distro.NewFromRelease(*p.Distro)
+	switch {
+	case err != nil:
+		logs.WithFields("package", displayPackage(p), "error", err).Warn("unable to determine the linux distribution of the package")
+	case d.Disabled(disabled):
+		logs.WithFields("package", displayPackage(p)).Warnf("unsupported linux distribution: %s", d.Name())
+		r.disabled = true
+	default:
+		r.distro = d
+	}
+	distros[p.Distro] = r
+	return r.distro, !r.disabled
+}
+
+// searchAliases searches for vulnerabilities filed under alternate names of the given package. Matches are attributed
+// to the original package and are only kept for vulnerabilities that were not already found under the package's own name.
+func (m *VulnerabilityMatcher) searchAliases(theMatcher matcher.Matcher, d *distro.Distro, p pkg.Package, direct []match.Match) []match.Match {
+	candidates := m.Aliases.Candidates(p)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	found := make(map[vulnerability.Reference]struct{})
+	for _, dm := range direct {
+		found[vulnerability.Reference{ID: dm.Vulnerability.ID, Namespace: dm.Vulnerability.Namespace}] = struct{}{}
+	}
+
+	var out []match.Match
+	for _, c := range candidates {
+		aliased := p
+		aliased.Name = c.Name
+		// only search by name: CPEs and PURLs would yield the same results as the original package
+		aliased.CPEs = nil
+		aliased.PURL = ""
+		aliased.Upstreams = nil
+
+		matches, err := theMatcher.Match(m.provider(), d, aliased)
+		if err != nil {
+			m.log().WithFields("error", err, "package", displayPackage(p), "alias", c.Name).Debug("matcher failed for package alias")
+			continue
+		}
+
+		for _, am := range matches {
+			ref := vulnerability.Reference{ID: am.Vulnerability.ID, Namespace: am.Vulnerability.Namespace}
+			if _, ok := found[ref]; ok {
+				continue
+			}
+			found[ref] = struct{}{}
+
+			am.Package = p
+			for idx := range am.Details {
+				am.Details[idx].Type = match.AliasMatch
+				am.Details[idx].Confidence = c.Confidence
+				am.Details[idx].Alias = &match.Alias{Name: c.Name, Source: string(c.Source), Confidence: c.Confidence}
+			}
+			m.log().WithFields("vuln", am.Vulnerability.ID, "package", displayPackage(p), "alias", c.Name, "source", c.Source).Trace("found match under package alias")
+			out = append(out, am)
+		}
+	}
+	return out
+}
+
 func indexFalsePositivesByLocation(
 	d *distro.Distro,
 	packages []pkg.Package,
@@ -261,7 +579,7 @@ func getDistroFalsePositivesByLocation(s store.Store, d *distro.Distro, p pkg.Pa
 	return result, nil
 }
 
-func filterMatchesUsingDistroFalsePositives(ms []match.Match, falsePositivesByLocation map[string][]string) []match.Match {
+func filterMatchesUsingDistroFalsePositives(logs logger.Logger, rejections *match.Rejections, ms []match.Match, falsePositivesByLocation map[string][]string) []match.Match {
 	var result []match.Match
 	for _, m := range ms {
 		isFalsePositive := false
@@ -285,7 +603,13 @@ func filterMatchesUsingDistroFalsePositives(ms []match.Match, falsePositivesByLo
 		if !isFalsePositive {
 			result = append(result, m)
 		} else {
-			log.WithFields("vuln", m.Vulnerability.ID, "package", displayPackage(m.Package)).Trace("dropping false positive using distro security data")
+			logs.WithFields("vuln", m.Vulnerability.ID, "package", displayPackage(m.Package)).Trace("dropping false positive using distro security data")
+			rejections.RecordRejection(match.Rejection{
+				Vulnerability: m.Vulnerability,
+				Package:       m.Package,
+				Filter:        match.DistroRejection,
+				Reason:        "the security data of the distro declares the vulnerability as not affecting the package",
+			})
 		}
 	}
 
@@ -294,11 +618,11 @@ func filterMatchesUsingDistroFalsePositives(ms []match.Match, falsePositivesByLo
 
 func (m *VulnerabilityMatcher) findVEXMatches(context pkg.Context, remainingMatches *match.Matches, ignoredMatches []match.IgnoredMatch, progressMonitor *monitorWriter) (*match.Matches, []match.IgnoredMatch, error) {
 	if m.VexProcessor == nil {
-		log.Trace("no VEX documents provided, skipping VEX matching")
+		m.log().Trace("no VEX documents provided, skipping VEX matching")
 		return remainingMatches, ignoredMatches, nil
 	}
 
-	log.Trace("finding matches against available VEX documents")
+	m.log().Trace("finding matches against available VEX documents")
 	matchesAfterVex, ignoredMatchesAfterVex, err := m.VexProcessor.ApplyVEX(&context, remainingMatches, ignoredMatches)
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to find matches against VEX documents: %w", err)
@@ -319,10 +643,15 @@ func (m *VulnerabilityMatcher) applyIgnoreRules(matches match.Matches) (match.Ma
 		return matches, ignoredMatches
 	}
 
-	matches, ignoredMatches = match.ApplyIgnoreRules(matches, m.IgnoreRules)
+	var metadata vulnerability.MetadataProvider
+	if m.Store.MetadataProvider != nil {
+		metadata = m.Store
+	}
+	matches, ignoredMatches = match.ApplyIgnoreRulesInEnvironment(matches, m.IgnoreRules, metadata, m.Environment)
+	recordRejectedMatches(m.Rejections, match.FixStateRejection, ignoredMatches)
 
 	if count := len(ignoredMatches); count > 0 {
-		log.Infof("ignoring %d matches due to user-provided ignore rules", count)
+		m.log().Infof("ignoring %d matches due to user-provided ignore rules", count)
 	}
 	return matches, ignoredMatches
 }
@@ -342,7 +671,7 @@ func (m *VulnerabilityMatcher) normalizeByCVE(match match.Match) match.Match {
 
 	switch len(effectiveCVERecordRefs) {
 	case 0:
-		log.WithFields(
+		m.log().WithFields(
 			"vuln", match.Vulnerability.ID,
 			"package", displayPackage(match.Package),
 		).Trace("unable to find CVE record for vulnerability, skipping normalization")
@@ -350,7 +679,7 @@ func (m *VulnerabilityMatcher) normalizeByCVE(match match.Match) match.Match {
 	case 1:
 		break
 	default:
-		log.WithFields(
+		m.log().WithFields(
 			"refs", fmt.Sprintf("%+v", effectiveCVERecordRefs),
 			"vuln", match.Vulnerability.ID,
 			"package", displayPackage(match.Package),
@@ -362,7 +691,7 @@ func (m *VulnerabilityMatcher) normalizeByCVE(match match.Match) match.Match {
 
 	upstreamMetadata, err := m.Store.GetMetadata(ref.ID, ref.Namespace)
 	if err != nil {
-		log.WithFields("id", ref.ID, "namespace", ref.Namespace, "error", err).Warn("unable to fetch effective CVE metadata")
+		m.log().WithFields("id", ref.ID, "namespace", ref.Namespace, "error", err).Warn("unable to fetch effective CVE metadata")
 		return match
 	}
 
@@ -408,7 +737,7 @@ func ignoredMatchesDiff(subject []match.IgnoredMatch, other []match.IgnoredMatch
 	return diff
 }
 
-func newMatcherIndex(matchers []matcher.Matcher) (map[syftPkg.Type][]matcher.Matcher, matcher.Matcher) {
+func newMatcherIndex(logs logger.Logger, matchers []matcher.Matcher) (map[syftPkg.Type][]matcher.Matcher, matcher.Matcher) {
 	matcherIndex := make(map[syftPkg.Type][]matcher.Matcher)
 	var defaultMatcher matcher.Matcher
 	for _, m := range matchers {
@@ -422,13 +751,22 @@ func newMatcherIndex(matchers []matcher.Matcher) (map[syftPkg.Type][]matcher.Mat
 			}
 
 			matcherIndex[t] = append(matcherIndex[t], m)
-			log.Debugf("adding matcher: %+v", t)
+			logs.Debugf("adding matcher: %+v", t)
 		}
 	}
 
 	return matcherIndex, defaultMatcher
 }
 
+// matcherPackageType returns the package type of the matchers for the package: runtime binaries are matched by the
+// matchers of runtimes, while keeping their binary package type.
+func matcherPackageType(p pkg.Package) syftPkg.Type {
+	if p.Runtime != "" {
+		return pkg.RuntimePkg
+	}
+	return p.Type
+}
+
 func isCVE(id string) bool {
 	return strings.HasPrefix(strings.ToLower(id), "cve-")
 }
@@ -442,6 +780,10 @@ func HasSeverityAtOrAbove(store vulnerability.MetadataProvider, severity vulnera
 		if err != nil {
 			continue
 		}
+		metadata = m.WithSeverityOverride(metadata)
+		if metadata == nil {
+			continue
+		}
 
 		if vulnerability.ParseSeverity(metadata.Severity) >= severity {
 			return true
@@ -450,23 +792,23 @@ func HasSeverityAtOrAbove(store vulnerability.MetadataProvider, severity vulnera
 	return false
 }
 
-func logListSummary(vl *monitorWriter) {
-	log.Infof("found %d vulnerability matches across %d packages", vl.MatchesDiscovered.Current(), vl.PackagesProcessed.Current())
-	log.Debugf("  ├── fixed: %d", vl.Fixed.Current())
-	log.Debugf("  ├── ignored: %d (due to user-provided rule)", vl.Ignored.Current())
-	log.Debugf("  ├── dropped: %d (due to hard-coded correction)", vl.Dropped.Current())
-	log.Debugf("  └── matched: %d", vl.MatchesDiscovered.Current())
+func logListSummary(logs logger.Logger, vl *monitorWriter) {
+	logs.Infof("found %d vulnerability matches across %d packages", vl.MatchesDiscovered.Current(), vl.PackagesProcessed.Current())
+	logs.Debugf("  ├── fixed: %d", vl.Fixed.Current())
+	logs.Debugf("  ├── ignored: %d (due to user-provided rule)", vl.Ignored.Current())
+	logs.Debugf("  ├── dropped: %d (due to hard-coded correction)", vl.Dropped.Current())
+	logs.Debugf("  └── matched: %d", vl.MatchesDiscovered.Current())
 
 	var unknownCount int64
 	if count, ok := vl.BySeverity[vulnerability.UnknownSeverity]; ok {
 		unknownCount = count.Current()
 	}
-	log.Debugf("      ├── %s: %d", vulnerability.UnknownSeverity.String(), unknownCount)
+	logs.Debugf("      ├── %s: %d", vulnerability.UnknownSeverity.String(), unknownCount)
 
 	allSeverities := vulnerability.AllSeverities()
 	for idx, sev := range allSeverities {
 		arm := selectArm(idx, len(allSeverities))
-		log.Debugf("      %s %s: %d", arm, sev.String(), vl.BySeverity[sev].Current())
+		logs.Debugf("      %s %s: %d", arm, sev.String(), vl.BySeverity[sev].Current())
 	}
 }
 
@@ -494,15 +836,15 @@ func updateVulnerabilityList(mon *monitorWriter, matches []match.Match, ignores
 	mon.Dropped.Add(int64(len(dropped)))
 }
 
-func logPackageMatches(p pkg.Package, matches []match.Match) {
+func logPackageMatches(logs logger.Logger, p pkg.Package, matches []match.Match) {
 	if len(matches) == 0 {
 		return
 	}
 
-	log.WithFields("package", displayPackage(p)).Debugf("found %d vulnerabilities", len(matches))
+	logs.WithFields("package", displayPackage(p)).Debugf("found %d vulnerabilities", len(matches))
 	for idx, m := range matches {
 		arm := selectArm(idx, len(matches))
-		log.WithFields("vuln", m.Vulnerability.ID, "namespace", m.Vulnerability.Namespace).Debugf("  %s", arm)
+		logs.WithFields("vuln", m.Vulnerability.ID, "namespace", m.Vulnerability.Namespace).Debugf("  %s", arm)
 	}
 }
 
@@ -513,29 +855,55 @@ func selectArm(idx, total int) string {
 	return branch
 }
 
-func logExplicitDroppedPackageMatches(p pkg.Package, ignored []match.IgnoredMatch) {
+// recordRejectedMatches records the ignored matches as rejections by the given filter: by the rules excluding the
+// vulnerability for the package, or by the rules ignoring the fix state of the vulnerability (other ignore rules are
+// not rejections, since the ignored matches are reported as such).
+func recordRejectedMatches(rejections *match.Rejections, filter match.RejectionFilter, ignored []match.IgnoredMatch) {
+	if rejections == nil {
+		return
+	}
+	for _, i := range ignored {
+		for _, rule := range i.AppliedIgnoreRules {
+			var reason string
+			switch {
+			case filter == match.ExclusionRejection && rule.Reason != "":
+				reason = "excluded for the package: " + rule.Reason
+			case filter == match.ExclusionRejection:
+				reason = "excluded for the package by the vulnerability DB"
+			case rule.FixState != "":
+				reason = fmt.Sprintf("vulnerabilities in the %q fix state are ignored", rule.FixState)
+			default:
+				continue
+			}
+			rejections.RecordRejection(match.Rejection{Vulnerability: i.Vulnerability, Package: i.Package, Filter: filter, Reason: reason})
+			break
+		}
+	}
+}
+
+func logExplicitDroppedPackageMatches(logs logger.Logger, p pkg.Package, ignored []match.IgnoredMatch) {
 	if len(ignored) == 0 {
 		return
 	}
 
-	log.WithFields("package", displayPackage(p)).Debugf("dropped %d vulnerability matches due to hard-coded correction", len(ignored))
+	logs.WithFields("package", displayPackage(p)).Debugf("dropped %d vulnerability matches due to hard-coded correction", len(ignored))
 	for idx, i := range ignored {
 		arm := selectArm(idx, len(ignored))
 
-		log.WithFields("vuln", i.Match.Vulnerability.ID, "rules", len(i.AppliedIgnoreRules)).Debugf("  %s", arm)
+		logs.WithFields("vuln", i.Match.Vulnerability.ID, "rules", len(i.AppliedIgnoreRules)).Debugf("  %s", arm)
 	}
 }
 
-func logIgnoredMatches(ignored []match.IgnoredMatch) {
+func logIgnoredMatches(logs logger.Logger, ignored []match.IgnoredMatch) {
 	if len(ignored) == 0 {
 		return
 	}
 
-	log.Infof("ignored %d vulnerability matches", len(ignored))
+	logs.Infof("ignored %d vulnerability matches", len(ignored))
 	for idx, i := range ignored {
 		arm := selectArm(idx, len(ignored))
 
-		log.WithFields("vuln", i.Match.Vulnerability.ID, "rules", len(i.AppliedIgnoreRules), "package", displayPackage(i.Package)).Debugf("  %s", arm)
+		logs.WithFields("vuln", i.Match.Vulnerability.ID, "rules", len(i.AppliedIgnoreRules), "package", displayPackage(i.Package)).Debugf("  %s", arm)
 	}
 }
 
@@ -590,10 +958,10 @@ func (m *monitorWriter) SetCompleted() {
 	}
 }
 
-func trackMatcher(pkgCount int) *monitorWriter {
+func (m *VulnerabilityMatcher) trackMatcher(pkgCount int) *monitorWriter {
 	writer, reader := newMonitor(pkgCount)
 
-	bus.Publish(partybus.Event{
+	m.publish(partybus.Event{
 		Type:  event.VulnerabilityScanningStarted,
 		Value: reader,
 	})
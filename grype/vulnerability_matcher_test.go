@@ -1,7 +1,10 @@
 package grype
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -10,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/wagoodman/go-partybus"
 
+	"github.com/anchore/grype/grype/alias"
 	"github.com/anchore/grype/grype/db"
 	grypeDB "github.com/anchore/grype/grype/db/v5"
 	"github.com/anchore/grype/grype/distro"
@@ -27,6 +31,7 @@ import (
 	"github.com/anchore/grype/grype/vex"
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/grype/internal/bus"
+	"github.com/anchore/grype/internal/log"
 	"github.com/anchore/syft/syft/cpe"
 	"github.com/anchore/syft/syft/file"
 	"github.com/anchore/syft/syft/linux"
@@ -237,12 +242,26 @@ func Test_HasSeverityAtOrAbove(t *testing.T) {
 		},
 	})
 
+	overridden := match.NewMatches()
+	overridden.Add(match.Match{
+		Vulnerability:    vulnerability.Vulnerability{ID: "CVE-2014-fake-1", Namespace: "debian:distro:debian:8"},
+		Package:          thePkg,
+		Details:          match.Details{{Type: match.ExactDirectMatch}},
+		SeverityOverride: &match.SeverityOverride{Severity: "Critical"},
+	})
+
 	tests := []struct {
 		name           string
 		failOnSeverity string
 		matches        match.Matches
 		expectedResult bool
 	}{
+		{
+			name:           "overridden-above-threshold",
+			failOnSeverity: "high",
+			matches:        overridden,
+			expectedResult: true,
+		},
 		{
 			name:           "no-severity-set",
 			failOnSeverity: "",
@@ -1107,6 +1126,15 @@ func TestVulnerabilityMatcher_FindMatches(t *testing.T) {
 	}
 }
 
+func Test_matcherPackageType(t *testing.T) {
+	runtime := pkg.New(syftPkg.Package{Name: "node", Version: "18.19.1", Type: syftPkg.BinaryPkg})
+	assert.Equal(t, syftPkg.BinaryPkg, runtime.Type)
+	assert.Equal(t, pkg.RuntimePkg, matcherPackageType(runtime))
+
+	binary := pkg.New(syftPkg.Package{Name: "curl", Version: "8.5.0", Type: syftPkg.BinaryPkg})
+	assert.Equal(t, syftPkg.BinaryPkg, matcherPackageType(binary))
+}
+
 func Test_indexFalsePositivesByLocation(t *testing.T) {
 	cases := []struct {
 		name           string
@@ -1282,6 +1310,419 @@ func createMockStore(t *testing.T, fn mockStoreStubFn) store.Store {
 	}
 }
 
+func TestVulnerabilityMatcher_FindMatches_Aliases(t *testing.T) {
+	dict := alias.NewDictionary()
+	dict.Add(string(syftPkg.GemPkg), "active-record", "activerecord")
+
+	p := pkg.Package{
+		ID:       pkg.ID(uuid.NewString()),
+		Name:     "active-record",
+		Version:  "3.7.5",
+		Type:     syftPkg.GemPkg,
+		Language: syftPkg.Ruby,
+	}
+
+	m := VulnerabilityMatcher{
+		Store:    createMockStore(t, defaultStubFn),
+		Matchers: []matcher.Matcher{ruby.NewRubyMatcher(ruby.MatcherConfig{})},
+	}
+
+	withoutAliases, _, err := m.FindMatches([]pkg.Package{p}, pkg.Context{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, withoutAliases.Count())
+
+	m.Aliases = alias.NewResolver(dict, false)
+	withAliases, _, err := m.FindMatches([]pkg.Package{p}, pkg.Context{})
+	require.NoError(t, err)
+
+	matches := withAliases.Sorted()
+	require.Len(t, matches, 1)
+	assert.Equal(t, "GHSA-2014-fake-3", matches[0].Vulnerability.ID)
+	assert.Equal(t, p.Name, matches[0].Package.Name, "alias matches are attributed to the original package")
+	require.Len(t, matches[0].Details, 1)
+	assert.Equal(t, match.AliasMatch, matches[0].Details[0].Type)
+	assert.Equal(t, alias.DictionaryConfidence, matches[0].Details[0].Confidence)
+	assert.Equal(t, &match.Alias{Name: "activerecord", Source: string(alias.DictionarySource), Confidence: alias.DictionaryConfidence}, matches[0].Details[0].Alias)
+}
+
+func TestVulnerabilityMatcher_FindMatches_AliasesOfTypedSearches(t *testing.T) {
+	dict := alias.NewDictionary()
+	dict.Add(string(syftPkg.GemPkg), "active-record", "activerecord")
+
+	p := pkg.Package{
+		ID:       pkg.ID(uuid.NewString()),
+		Name:     "active-record",
+		Version:  "3.7.5",
+		Type:     syftPkg.GemPkg,
+		Language: syftPkg.Ruby,
+	}
+
+	// the matcher records its search as a struct rather than as a map
+	searchedBy := search.CPEParameters{Namespace: "nvd:cpe", CPEs: []string{"cpe:2.3:a:activerecord:activerecord:3.7.5:*:*:*:*:*:*:*"}}
+	m := VulnerabilityMatcher{
+		Store:    createMockStore(t, defaultStubFn),
+		Matchers: []matcher.Matcher{cpeMatcher{name: "activerecord", searchedBy: searchedBy}},
+		Aliases:  alias.NewResolver(dict, false),
+	}
+
+	results, _, err := m.FindMatches([]pkg.Package{p}, pkg.Context{})
+	require.NoError(t, err)
+
+	matches := results.Sorted()
+	require.Len(t, matches, 1)
+	require.Len(t, matches[0].Details, 1)
+	d := matches[0].Details[0]
+	assert.Equal(t, searchedBy, d.SearchedBy, "the search of the matcher is kept as is")
+	assert.Equal(t, &match.Alias{Name: "activerecord", Source: string(alias.DictionarySource), Confidence: alias.DictionaryConfidence}, d.Alias)
+	assert.Contains(t, d.Criteria(), match.EvidenceAttribute{Name: "alias.name", Value: "activerecord"})
+}
+
+func TestVulnerabilityMatcher_FindMatches_Enrichers(t *testing.T) {
+	neutron := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "neutron",
+		Version: "2099.1.1-1",
+		Type:    syftPkg.DebPkg,
+	}
+
+	m := VulnerabilityMatcher{
+		Store:    createMockStore(t, defaultStubFn),
+		Matchers: matcher.NewDefaultMatchers(matcher.Config{}),
+	}
+
+	// the scan target has no distro to match the package against
+	matches, _, err := m.FindMatches([]pkg.Package{neutron}, pkg.Context{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, matches.Count())
+
+	m.Enrichers = []pkg.Enricher{
+		pkg.EnricherFunc(func(p *pkg.Package, _ pkg.Context) error {
+			if p.Name == "neutron" {
+				p.Version = "2014.1.3-5"
+			}
+			return nil
+		}),
+		pkg.EnricherFunc(func(p *pkg.Package, _ pkg.Context) error {
+			p.Distro = &linux.Release{ID: "debian", VersionID: "8"}
+			return nil
+		}),
+	}
+	matches, _, err = m.FindMatches([]pkg.Package{neutron}, pkg.Context{})
+	require.NoError(t, err)
+
+	sorted := matches.Sorted()
+	require.Len(t, sorted, 1)
+	assert.Equal(t, "CVE-2014-fake-1", sorted[0].Vulnerability.ID)
+	assert.Equal(t, "2014.1.3-5", sorted[0].Package.Version, "the matches are of the enriched package")
+	assert.Equal(t, "2099.1.1-1", neutron.Version, "the given package is not modified")
+
+	m.Enrichers = append(m.Enrichers, pkg.EnricherFunc(func(*pkg.Package, pkg.Context) error {
+		return errors.New("broken")
+	}))
+	_, _, err = m.FindMatches([]pkg.Package{neutron}, pkg.Context{})
+	assert.ErrorContains(t, err, "unable to enrich package neutron@2099.1.1-1: broken")
+}
+
+func TestVulnerabilityMatcher_FindMatches_Bus(t *testing.T) {
+	neutron := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "neutron",
+		Version: "2014.1.3-5",
+		Type:    syftPkg.DebPkg,
+	}
+	debian := pkg.Context{Distro: &linux.Release{ID: "debian", VersionID: "8"}}
+
+	global := &busListener{}
+	bus.Set(global)
+	defer bus.Set(nil)
+
+	// concurrent scans of matchers with their own bus each report their own progress
+	listeners := []*busListener{{}, {}}
+	packages := [][]pkg.Package{{neutron}, {neutron, neutron, neutron}}
+	errs := make(chan error, len(listeners))
+	for i := range listeners {
+		m := DefaultVulnerabilityMatcher(createMockStore(t, defaultStubFn)).WithBus(listeners[i])
+		go func() {
+			_, _, err := m.FindMatches(packages[i], debian)
+			errs <- err
+		}()
+	}
+	for range listeners {
+		require.NoError(t, <-errs)
+	}
+
+	for i, l := range listeners {
+		require.NotNil(t, l.matching.PackagesProcessed)
+		assert.Equal(t, int64(len(packages[i])), l.matching.PackagesProcessed.Current())
+	}
+	assert.Nil(t, global.matching.PackagesProcessed, "the events of the scans are not published to the global bus")
+
+	// without a bus of their own, the events are published to the global bus
+	m := DefaultVulnerabilityMatcher(createMockStore(t, defaultStubFn))
+	_, _, err := m.FindMatches([]pkg.Package{neutron}, debian)
+	require.NoError(t, err)
+	require.NotNil(t, global.matching.PackagesProcessed)
+	assert.Equal(t, int64(1), global.matching.PackagesProcessed.Current())
+}
+
+// cpeMatcher finds a vulnerability for the packages of the given name, recording its search as CPE parameters.
+type cpeMatcher struct {
+	name       string
+	searchedBy search.CPEParameters
+}
+
+func (cpeMatcher) PackageTypes() []syftPkg.Type { return []syftPkg.Type{syftPkg.GemPkg} }
+
+func (cpeMatcher) Type() match.MatcherType { return match.RubyGemMatcher }
+
+func (m cpeMatcher) Match(_ vulnerability.Provider, _ *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	if p.Name != m.name {
+		return nil, nil
+	}
+	return []match.Match{{
+		Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0001", Namespace: "nvd:cpe"},
+		Package:       p,
+		Details:       match.Details{{Type: match.CPEMatch, Matcher: match.RubyGemMatcher, SearchedBy: m.searchedBy, Confidence: 0.9}},
+	}}, nil
+}
+
+type failingMatcher struct{}
+
+func (failingMatcher) PackageTypes() []syftPkg.Type { return []syftPkg.Type{syftPkg.GemPkg} }
+
+func (failingMatcher) Type() match.MatcherType { return match.RubyGemMatcher }
+
+func (failingMatcher) Match(vulnerability.Provider, *distro.Distro, pkg.Package) ([]match.Match, error) {
+	return nil, errors.New("broken")
+}
+
+// blockingMatcher never completes its matches until released.
+type blockingMatcher struct {
+	release chan struct{}
+}
+
+func (blockingMatcher) PackageTypes() []syftPkg.Type { return []syftPkg.Type{syftPkg.GemPkg} }
+
+func (blockingMatcher) Type() match.MatcherType { return match.RubyGemMatcher }
+
+func (m blockingMatcher) Match(vulnerability.Provider, *distro.Distro, pkg.Package) ([]match.Match, error) {
+	<-m.release
+	return nil, nil
+}
+
+func TestVulnerabilityMatcher_FindMatches_BudgetAbandonsMatcher(t *testing.T) {
+	activerecord := pkg.Package{
+		ID:       pkg.ID(uuid.NewString()),
+		Name:     "activerecord",
+		Version:  "3.7.5",
+		Type:     syftPkg.GemPkg,
+		Language: syftPkg.Ruby,
+	}
+	blocking := blockingMatcher{release: make(chan struct{})}
+	defer close(blocking.release)
+
+	budget := NewScanBudget(50 * time.Millisecond)
+	m := VulnerabilityMatcher{
+		Store:    createMockStore(t, defaultStubFn),
+		Matchers: []matcher.Matcher{blocking},
+		Budget:   budget,
+	}
+	matches, _, err := m.FindMatches([]pkg.Package{activerecord}, pkg.Context{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, matches.Count())
+	require.Len(t, budget.Unscanned(), 1, "the package of the abandoned matcher is unscanned")
+	assert.Equal(t, activerecord.ID, budget.Unscanned()[0].ID)
+}
+
+func TestVulnerabilityMatcher_FindMatches_Strict(t *testing.T) {
+	neutron := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "neutron",
+		Version: "2014.1.3-5",
+		Type:    syftPkg.DebPkg,
+	}
+	activerecord := pkg.Package{
+		ID:       pkg.ID(uuid.NewString()),
+		Name:     "activerecord",
+		Version:  "3.7.5",
+		Type:     syftPkg.GemPkg,
+		Language: syftPkg.Ruby,
+	}
+	debian := pkg.Context{Distro: &linux.Release{ID: "debian", VersionID: "8"}}
+
+	m := VulnerabilityMatcher{
+		Store:    createMockStore(t, defaultStubFn),
+		Matchers: matcher.NewDefaultMatchers(matcher.Config{}),
+		Strict:   true,
+	}
+
+	// a complete scan
+	matches, _, err := m.FindMatches([]pkg.Package{neutron, activerecord}, debian)
+	require.NoError(t, err)
+	assert.Equal(t, 2, matches.Count())
+
+	// the OS package of a scan target of an unknown distro cannot be matched
+	matches, _, err = m.FindMatches([]pkg.Package{neutron, activerecord}, pkg.Context{})
+	var dataQualityErr *grypeerr.DataQualityError
+	require.ErrorAs(t, err, &dataQualityErr)
+	assert.True(t, grypeerr.IsScanFailure(err))
+	assert.Equal(t, []grypeerr.DataQualityProblem{
+		{Kind: string(pkg.MissingDistro), Subject: "neutron@2014.1.3-5 (deb)", Message: pkg.MissingDistro.Description()},
+	}, dataQualityErr.Problems)
+	assert.Equal(t, 1, matches.Count(), "the matches are still returned")
+
+	// failing matchers are problems too
+	m.Matchers = []matcher.Matcher{failingMatcher{}}
+	_, _, err = m.FindMatches([]pkg.Package{activerecord}, debian)
+	require.ErrorAs(t, err, &dataQualityErr)
+	require.Len(t, dataQualityErr.Problems, 1)
+	assert.Equal(t, "matcher-failed", dataQualityErr.Problems[0].Kind)
+	assert.Equal(t, "activerecord@3.7.5 (gem)", dataQualityErr.Problems[0].Subject)
+	assert.ErrorContains(t, err, "broken")
+
+	// and so are the degradations of the providers (e.g. an online source that could not be reached)
+	m.Matchers = matcher.NewDefaultMatchers(matcher.Config{})
+	degraded := degradedProvider{Provider: m.Store.Provider, problem: grypeerr.DataQualityProblem{Kind: "provider-failed", Subject: "osv.dev", Message: "unreachable"}}
+	m.Store.Provider = degraded
+	matches, _, err = m.FindMatches([]pkg.Package{neutron, activerecord}, debian)
+	require.ErrorAs(t, err, &dataQualityErr)
+	assert.Equal(t, []grypeerr.DataQualityProblem{degraded.problem}, dataQualityErr.Problems)
+	assert.Equal(t, 2, matches.Count())
+
+	// problems are only logged outside of strict mode
+	m.Strict = false
+	_, _, err = m.FindMatches([]pkg.Package{neutron, activerecord}, pkg.Context{})
+	assert.NoError(t, err)
+}
+
+// degradedProvider is a vulnerability provider that served partial data.
+type degradedProvider struct {
+	vulnerability.Provider
+	problem grypeerr.DataQualityProblem
+}
+
+func (p degradedProvider) Degradations() []grypeerr.DataQualityProblem {
+	return []grypeerr.DataQualityProblem{p.problem}
+}
+
+func TestVulnerabilityMatcher_FindMatches_Budget(t *testing.T) {
+	neutron := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "neutron",
+		Version: "2014.1.3-5",
+		Type:    syftPkg.DebPkg,
+	}
+	activerecord := pkg.Package{
+		ID:       pkg.ID(uuid.NewString()),
+		Name:     "activerecord",
+		Version:  "3.7.5",
+		Type:     syftPkg.GemPkg,
+		Language: syftPkg.Ruby,
+	}
+	debian := pkg.Context{Distro: &linux.Release{ID: "debian", VersionID: "8"}}
+
+	// the deadline passes once the first package is matched (the clock is read before and while matching it)
+	deadline := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	checks := 0
+	budget := &ScanBudget{Deadline: deadline, now: func() time.Time {
+		checks++
+		if checks <= 2 {
+			return deadline.Add(-time.Minute)
+		}
+		return deadline
+	}}
+
+	m := VulnerabilityMatcher{
+		Store:    createMockStore(t, defaultStubFn),
+		Matchers: matcher.NewDefaultMatchers(matcher.Config{}),
+		Budget:   budget,
+	}
+	matches, _, err := m.FindMatches([]pkg.Package{neutron, activerecord}, debian)
+	require.NoError(t, err, "an incomplete scan only fails in strict mode")
+	assert.Equal(t, 1, matches.Count(), "the matches of the scanned packages are returned")
+	assert.True(t, budget.Exceeded())
+	require.Len(t, budget.Unscanned(), 1)
+	assert.Equal(t, activerecord.ID, budget.Unscanned()[0].ID)
+
+	// the unscanned packages are data-quality problems in strict mode
+	m.Strict = true
+	m.Budget = &ScanBudget{Deadline: deadline, now: func() time.Time { return deadline }}
+	matches, _, err = m.FindMatches([]pkg.Package{neutron, activerecord}, debian)
+	var dataQualityErr *grypeerr.DataQualityError
+	require.ErrorAs(t, err, &dataQualityErr)
+	assert.Equal(t, []grypeerr.DataQualityProblem{
+		{Kind: "scan-time-exceeded", Subject: "neutron@2014.1.3-5 (deb)", Message: "the package was not scanned within the maximum scan time"},
+		{Kind: "scan-time-exceeded", Subject: "activerecord@3.7.5 (gem)", Message: "the package was not scanned within the maximum scan time"},
+	}, dataQualityErr.Problems)
+	assert.Equal(t, 0, matches.Count())
+
+	// without a budget, every package is scanned
+	m.Budget = nil
+	matches, _, err = m.FindMatches([]pkg.Package{neutron, activerecord}, debian)
+	require.NoError(t, err)
+	assert.Equal(t, 2, matches.Count())
+}
+
+func TestVulnerabilityMatcher_FindMatches_Context(t *testing.T) {
+	neutron := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "neutron",
+		Version: "2014.1.3-5",
+		Type:    syftPkg.DebPkg,
+	}
+	aborted := errors.New("memory budget exceeded")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(aborted)
+
+	m := VulnerabilityMatcher{
+		Store:    createMockStore(t, defaultStubFn),
+		Matchers: matcher.NewDefaultMatchers(matcher.Config{}),
+		Context:  ctx,
+	}
+	_, _, err := m.FindMatches([]pkg.Package{neutron}, pkg.Context{Distro: &linux.Release{ID: "debian", VersionID: "8"}})
+	require.ErrorIs(t, err, aborted)
+}
+
+func TestVulnerabilityMatcher_FindMatches_Rejections(t *testing.T) {
+	neutron := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "neutron",
+		Version: "2014.1.3-5",
+		Type:    syftPkg.DebPkg,
+	}
+	activerecord := pkg.Package{
+		ID:       pkg.ID(uuid.NewString()),
+		Name:     "activerecord",
+		Version:  "3.7.5",
+		Type:     syftPkg.GemPkg,
+		Language: syftPkg.Ruby,
+	}
+
+	stub := func(d *mockStore) {
+		defaultStubFn(d)
+		d.vulnerabilities["github:language:ruby"]["activerecord"][0].Fix.State = grypeDB.NotFixedState
+	}
+	m := VulnerabilityMatcher{
+		Store:       createMockStore(t, stub),
+		Matchers:    matcher.NewDefaultMatchers(matcher.Config{}),
+		IgnoreRules: []match.IgnoreRule{{FixState: string(grypeDB.NotFixedState)}},
+		Rejections:  match.NewRejections(),
+	}
+	matches, ignored, err := m.FindMatches([]pkg.Package{neutron, activerecord}, pkg.Context{Distro: &linux.Release{ID: "debian", VersionID: "8"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, matches.Count())
+	assert.Len(t, ignored, 1)
+
+	var got []string
+	for _, r := range m.Rejections.Sorted() {
+		got = append(got, r.Package.Name+" "+r.Vulnerability.ID+" "+string(r.Filter)+": "+r.Reason)
+	}
+	assert.Equal(t, []string{
+		`activerecord GHSA-2014-fake-3 fix-state: vulnerabilities in the "not-fixed" fix state are ignored`,
+		`neutron CVE-2013-fake-2 version-constraint: version 2014.1.3-5 is not within the constraint "< 2013.0.2-1 (deb)"`,
+	}, got)
+}
+
 func Test_filterMatchesUsingDistroFalsePositives(t *testing.T) {
 	cases := []struct {
 		name         string
@@ -1387,7 +1828,7 @@ func Test_filterMatchesUsingDistroFalsePositives(t *testing.T) {
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := filterMatchesUsingDistroFalsePositives(tt.inputMatches, tt.fpIndex)
+			actual := filterMatchesUsingDistroFalsePositives(log.Get(), nil, tt.inputMatches, tt.fpIndex)
 			assert.Equal(t, tt.expected, actual)
 		})
 	}
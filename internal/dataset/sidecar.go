@@ -0,0 +1,108 @@
+package dataset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Mergeable is a dataset that can take in the records of another dataset of the same kind.
+type Mergeable[T any] interface {
+	Merge(other T)
+}
+
+// Sidecar describes a dataset that is shipped along with the vulnerability database, within the DB directory, as a
+// single file or as a directory of JSON files.
+type Sidecar[T Mergeable[T]] struct {
+	// Kind names the dataset in error messages (e.g. "malware dataset").
+	Kind string
+	// Path is the path of the dataset relative to the DB directory.
+	Path string
+	// Parse reads the contents of a single file of the dataset (decompressed for files with a .gz extension).
+	Parse func(data []byte) (T, error)
+	// New returns an empty dataset to merge the files of a directory into. Without it a dataset is a single file.
+	New func() T
+	// Recursive tells whether the JSON files within the subdirectories of a directory are read too.
+	Recursive bool
+}
+
+// FromPath reads a dataset from a single file or from every JSON file within a directory.
+func (s Sidecar[T]) FromPath(path string) (T, error) {
+	var empty T
+	info, err := os.Stat(path)
+	if err != nil {
+		return empty, fmt.Errorf("unable to read %s: %w", s.Kind, err)
+	}
+	if !info.IsDir() {
+		return s.FromFile(path)
+	}
+	if s.New == nil {
+		return empty, fmt.Errorf("unable to read %s: %s is a directory", s.Kind, path)
+	}
+
+	d := s.New()
+	err = filepath.WalkDir(path, func(p string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if p != path && !s.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(p), ".json") {
+			return nil
+		}
+		sub, err := s.FromFile(p)
+		if err != nil {
+			return err
+		}
+		d.Merge(sub)
+		return nil
+	})
+	if err != nil {
+		return empty, err
+	}
+	return d, nil
+}
+
+// FromDBDir reads the dataset shipped within the given DB directory, if there is one. The zero value of the dataset
+// (and no error) is returned when the DB does not ship the dataset.
+func (s Sidecar[T]) FromDBDir(dir string) (T, error) {
+	path := filepath.Join(dir, s.Path)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		var empty T
+		return empty, nil
+	}
+	return s.FromPath(path)
+}
+
+// FromFile reads a dataset from a single file, which may be gzip-compressed (with a .gz extension).
+func (s Sidecar[T]) FromFile(path string) (T, error) {
+	var empty T
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return empty, fmt.Errorf("unable to read %s: %w", s.Kind, err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".gz") {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return empty, fmt.Errorf("unable to read %s: %s: %w", s.Kind, path, err)
+		}
+		defer gz.Close()
+		if data, err = io.ReadAll(gz); err != nil {
+			return empty, fmt.Errorf("unable to read %s: %s: %w", s.Kind, path, err)
+		}
+	}
+
+	d, err := s.Parse(data)
+	if err != nil {
+		return empty, fmt.Errorf("%s: %w", path, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,98 @@
+package dataset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type names struct {
+	Names []string
+}
+
+func (n *names) Merge(other *names) {
+	n.Names = append(n.Names, other.Names...)
+}
+
+func parseNames(data []byte) (*names, error) {
+	var n names
+	if err := json.Unmarshal(data, &n.Names); err != nil {
+		return nil, fmt.Errorf("unable to parse names: %w", err)
+	}
+	return &n, nil
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+}
+
+func TestSidecar_FromPath(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.json"), `["a"]`)
+	writeFile(t, filepath.Join(dir, "b.JSON"), `["b"]`)
+	writeFile(t, filepath.Join(dir, "notes.txt"), `not a dataset`)
+	writeFile(t, filepath.Join(dir, "sub", "c.json"), `["c"]`)
+
+	tests := []struct {
+		name      string
+		path      string
+		recursive bool
+		want      []string
+	}{
+		{name: "file", path: filepath.Join(dir, "a.json"), want: []string{"a"}},
+		{name: "directory", path: dir, want: []string{"a", "b"}},
+		{name: "recursive directory", path: dir, recursive: true, want: []string{"a", "b", "c"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := Sidecar[*names]{Kind: "names", Parse: parseNames, New: func() *names { return &names{} }, Recursive: test.recursive}
+			got, err := s.FromPath(test.path)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got.Names)
+		})
+	}
+}
+
+func TestSidecar_FromPath_errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "bad.json"), `{`)
+	s := Sidecar[*names]{Kind: "names", Parse: parseNames}
+
+	_, err := s.FromPath(filepath.Join(dir, "bad.json"))
+	assert.ErrorContains(t, err, "bad.json: unable to parse names")
+
+	_, err = s.FromPath(filepath.Join(dir, "missing.json"))
+	assert.ErrorContains(t, err, "unable to read names")
+
+	_, err = s.FromPath(dir)
+	assert.ErrorContains(t, err, "is a directory", "a dataset without New is a single file")
+}
+
+func TestSidecar_FromDBDir(t *testing.T) {
+	dir := t.TempDir()
+	s := Sidecar[*names]{Kind: "names", Path: filepath.Join("names", "list.json.gz"), Parse: parseNames}
+
+	got, err := s.FromDBDir(dir)
+	require.NoError(t, err)
+	assert.Nil(t, got, "nothing is read when the DB does not ship the dataset")
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err = gz.Write([]byte(`["a", "b"]`))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	writeFile(t, filepath.Join(dir, s.Path), compressed.String())
+
+	got, err = s.FromDBDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, got.Names)
+}
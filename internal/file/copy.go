@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path"
+	"path/filepath"
 
 	"github.com/spf13/afero"
 )
@@ -26,8 +26,8 @@ func CopyDir(fs afero.Fs, src string, dst string) error {
 		return err
 	}
 	for _, fd := range fds {
-		srcPath := path.Join(src, fd.Name())
-		dstPath := path.Join(dst, fd.Name())
+		srcPath := filepath.Join(src, fd.Name())
+		dstPath := filepath.Join(dst, fd.Name())
 
 		if fd.IsDir() {
 			if err = CopyDir(fs, srcPath, dstPath); err != nil {
@@ -5,15 +5,19 @@ import (
 	"bytes"
 	"context"
 	"crypto/x509"
+	"encoding/binary"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"path"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/anchore/clio"
 )
@@ -151,6 +155,60 @@ func TestGetter_GetToDir_CertConcerns(t *testing.T) {
 	}
 }
 
+func TestGetter_GetToDir_zstd(t *testing.T) {
+	tarball := createTarball("foo", testFileContent)
+
+	testCases := []struct {
+		name    string
+		archive []byte
+	}{
+		{
+			name:    "zstd",
+			archive: compressZstd(t, tarball),
+		},
+		{
+			// seekable zstd is a series of independent frames followed by a seek table in a skippable frame
+			name:    "seekable zstd",
+			archive: append(append(compressZstd(t, tarball[:512]), compressZstd(t, tarball[512:])...), zstdSkippableFrame([]byte("seek-table"))...),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requestPath := "/foo.tar.zst"
+
+			server := newTestServer(t, withResponseForPath(t, requestPath, tc.archive))
+			t.Cleanup(server.Close)
+
+			getter := NewGetter(testID, getClient(t, server))
+			tempDir := t.TempDir()
+
+			require.NoError(t, getter.GetToDir(tempDir, createRequestURL(t, server, requestPath)))
+
+			contents, err := os.ReadFile(path.Join(tempDir, "foo"))
+			require.NoError(t, err)
+			assert.Equal(t, testFileContent, contents)
+		})
+	}
+}
+
+func compressZstd(t *testing.T, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+	_, err = w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func zstdSkippableFrame(content []byte) []byte {
+	frame := binary.LittleEndian.AppendUint32(nil, 0x184D2A5E)
+	frame = binary.LittleEndian.AppendUint32(frame, uint32(len(content)))
+	return append(frame, content...)
+}
+
 func assertUnknownAuthorityError(t assert.TestingT, err error, _ ...interface{}) bool {
 	return assert.ErrorAs(t, err, &x509.UnknownAuthorityError{})
 }
@@ -9,9 +9,19 @@ import (
 	"strings"
 
 	"github.com/spf13/afero"
+	"github.com/wagoodman/go-progress"
+
+	"github.com/anchore/grype/internal/fips"
 )
 
-func ValidateByHash(fs afero.Fs, path, hashStr string) (bool, string, error) {
+// ValidateByHash returns true when the file matches the given hash (e.g. "sha256:..."), along with the actual hash. The
+// optional monitor is the progress of the bytes of the file hashed.
+func ValidateByHash(fs afero.Fs, path, hashStr string, monitors ...*progress.Manual) (bool, string, error) {
+	algorithm, _, _ := strings.Cut(hashStr, ":")
+	if err := fips.CheckAlgorithm(algorithm, "verify "+path); err != nil {
+		return false, "", err
+	}
+
 	var hasher hash.Hash
 	var hashFn string
 	switch {
@@ -24,7 +34,7 @@ func ValidateByHash(fs afero.Fs, path, hashStr string) (bool, string, error) {
 
 	hashNoPrefix := strings.Split(hashStr, ":")[1]
 
-	actualHash, err := HashFile(fs, path, hasher)
+	actualHash, err := HashFile(fs, path, hasher, monitors...)
 	if err != nil {
 		return false, "", err
 	}
@@ -32,16 +42,41 @@ func ValidateByHash(fs afero.Fs, path, hashStr string) (bool, string, error) {
 	return actualHash == hashNoPrefix, hashFn + ":" + actualHash, nil
 }
 
-func HashFile(fs afero.Fs, path string, hasher hash.Hash) (string, error) {
+func HashFile(fs afero.Fs, path string, hasher hash.Hash, monitors ...*progress.Manual) (string, error) {
+	if len(monitors) > 1 {
+		return "", fmt.Errorf("multiple monitors provided, which is not allowed")
+	}
+
 	f, err := fs.Open(path)
 	if err != nil {
 		return "", fmt.Errorf("failed to open file '%s': %w", path, err)
 	}
 	defer f.Close()
 
-	if _, err := io.Copy(hasher, f); err != nil {
+	var reader io.Reader = f
+	if len(monitors) == 1 {
+		if info, err := f.Stat(); err == nil {
+			monitors[0].SetTotal(info.Size())
+		}
+		reader = &monitoredReader{reader: f, monitor: monitors[0]}
+	}
+
+	if _, err := io.Copy(hasher, reader); err != nil {
 		return "", fmt.Errorf("failed to hash file '%s': %w", path, err)
 	}
 
 	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
+
+// monitoredReader adds the bytes read to the monitor, leaving it to the caller to complete it (since the file may be
+// only one step of the monitored work).
+type monitoredReader struct {
+	reader  io.Reader
+	monitor *progress.Manual
+}
+
+func (r *monitoredReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.monitor.Add(int64(n))
+	return n, err
+}
@@ -6,6 +6,10 @@ import (
 
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wagoodman/go-progress"
+
+	"github.com/anchore/grype/internal/fips"
 )
 
 func TestValidateByHash(t *testing.T) {
@@ -83,3 +87,33 @@ func TestValidateByHash(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateByHash_monitor(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "test/path", []byte("test content"), 0644))
+
+	monitor := progress.NewManual(-1)
+	valid, _, err := ValidateByHash(fs, "test/path", "sha256:6ae8a75555209fd6c44157c0aed8016e763ff435a19cf186f76863140143ff72", monitor)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, int64(12), monitor.Size())
+	assert.Equal(t, int64(12), monitor.Current())
+	// completing the monitor is left to the caller
+	assert.NoError(t, monitor.Error())
+}
+
+func TestValidateByHash_fips(t *testing.T) {
+	fips.SetEnabled(true)
+	t.Cleanup(func() { fips.SetEnabled(false) })
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "test.txt", []byte("test"), 0644))
+
+	_, _, err := ValidateByHash(fs, "test.txt", "md5:098f6bcd4621d373cade4e832627b4f6")
+	require.ErrorIs(t, err, fips.ErrNotApproved)
+	assert.ErrorContains(t, err, "cannot verify test.txt with md5 in FIPS mode")
+
+	valid, _, err := ValidateByHash(fs, "test.txt", "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08")
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
@@ -0,0 +1,28 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Lock takes an exclusive lock on the lock file at the path (created when missing), waiting while another process (or
+// another Lock call of this process) holds it. The lock is released by calling the returned function, or by the
+// operating system when the process exits, so a crashed process never leaves a stale lock behind.
+func Lock(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("unable to create lock directory: %w", err)
+	}
+	fh, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open lock file: %w", err)
+	}
+	if err := lockFile(fh); err != nil {
+		_ = fh.Close()
+		return nil, fmt.Errorf("unable to lock %s: %w", path, err)
+	}
+	return func() {
+		_ = unlockFile(fh)
+		_ = fh.Close()
+	}, nil
+}
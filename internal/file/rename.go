@@ -0,0 +1,45 @@
+package file
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// renameAttempts and renameBackoff bound the retries of a rename failing because the file is held open by another
+	// process (e.g. a virus scanner or a search indexer scanning a freshly written file on Windows).
+	renameAttempts = 5
+	renameBackoff  = 100 * time.Millisecond
+)
+
+// Rename moves a file or a directory to dst, which must not exist. The rename is retried while the file is held open
+// by another process, and the file is copied (then removed) when it cannot be renamed across volumes.
+func Rename(fs afero.Fs, src, dst string) error {
+	var err error
+	for attempt := 1; attempt <= renameAttempts; attempt++ {
+		err = fs.Rename(src, dst)
+		if err == nil || !isTransientRenameError(err) {
+			break
+		}
+		time.Sleep(time.Duration(attempt) * renameBackoff)
+	}
+	if err == nil || !isCrossVolumeRenameError(err) {
+		return err
+	}
+
+	info, err := fs.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		err = CopyDir(fs, src, dst)
+	} else {
+		err = CopyFile(fs, src, dst)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to copy %s across volumes: %w", src, err)
+	}
+	return fs.RemoveAll(src)
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package file
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isTransientRenameError returns false: files held open by other processes can be renamed.
+func isTransientRenameError(error) bool {
+	return false
+}
+
+// isCrossVolumeRenameError returns true when the file cannot be renamed because the destination is on another
+// filesystem.
+func isCrossVolumeRenameError(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
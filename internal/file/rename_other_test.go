@@ -0,0 +1,8 @@
+//go:build !windows
+
+package file
+
+import "syscall"
+
+// crossVolumeRenameErr is the error of a rename across volumes.
+var crossVolumeRenameErr error = syscall.EXDEV
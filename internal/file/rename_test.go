@@ -0,0 +1,38 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crossVolumeFs fails renames as the OS does across volumes.
+type crossVolumeFs struct {
+	afero.Fs
+}
+
+func (fs crossVolumeFs) Rename(oldname, newname string) error {
+	return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: crossVolumeRenameErr}
+}
+
+func TestRename(t *testing.T) {
+	for _, fs := range []afero.Fs{afero.NewOsFs(), crossVolumeFs{Fs: afero.NewOsFs()}} {
+		root := t.TempDir()
+		src := filepath.Join(root, "src")
+		require.NoError(t, os.MkdirAll(filepath.Join(src, "shards"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "shards", "index.json"), []byte("{}"), 0600))
+
+		dst := filepath.Join(root, "dst")
+		require.NoError(t, Rename(fs, src, dst))
+
+		contents, err := os.ReadFile(filepath.Join(dst, "shards", "index.json"))
+		require.NoError(t, err)
+		assert.Equal(t, "{}", string(contents))
+		_, err = os.Stat(src)
+		assert.True(t, os.IsNotExist(err), "the source is removed")
+	}
+}
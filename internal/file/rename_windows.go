@@ -0,0 +1,30 @@
+package file
+
+import (
+	"errors"
+	"syscall"
+)
+
+// the Windows system error codes of failed renames (see https://learn.microsoft.com/en-us/windows/win32/debug/system-error-codes--0-499-)
+const (
+	errorAccessDenied     syscall.Errno = 5
+	errorNotSameDevice    syscall.Errno = 17
+	errorSharingViolation syscall.Errno = 32
+	errorLockViolation    syscall.Errno = 33
+)
+
+// isTransientRenameError returns true when the file cannot be renamed because it is open in another process, which
+// Windows does not allow (unless the file was opened with FILE_SHARE_DELETE).
+func isTransientRenameError(err error) bool {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return false
+	}
+	return errno == errorAccessDenied || errno == errorSharingViolation || errno == errorLockViolation
+}
+
+// isCrossVolumeRenameError returns true when the file cannot be renamed because the destination is on another volume.
+func isCrossVolumeRenameError(err error) bool {
+	var errno syscall.Errno
+	return errors.As(err, &errno) && errno == errorNotSameDevice
+}
@@ -0,0 +1,4 @@
+package file
+
+// crossVolumeRenameErr is the error of a rename across volumes.
+var crossVolumeRenameErr error = errorNotSameDevice
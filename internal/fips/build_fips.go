@@ -0,0 +1,6 @@
+//go:build fips
+
+package fips
+
+// builtWithFIPS is true in a build with the "fips" build tag, where FIPS mode is always on.
+const builtWithFIPS = true
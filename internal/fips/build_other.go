@@ -0,0 +1,6 @@
+//go:build !fips
+
+package fips
+
+// builtWithFIPS is false without the "fips" build tag, where FIPS mode is on only when enabled.
+const builtWithFIPS = false
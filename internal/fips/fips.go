@@ -0,0 +1,144 @@
+package fips
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrNotApproved is the error of a hash algorithm that is not approved by FIPS 140 being used in FIPS mode.
+var ErrNotApproved = errors.New("not a FIPS-approved algorithm")
+
+var enabled atomic.Bool
+
+// approved are the hash algorithms approved by FIPS 140 (FIPS 180-4 and FIPS 202) to verify digests and signatures
+// with. MD5 is not approved, and SHA-1 is not approved for verifying the integrity of data.
+var approved = map[string]bool{
+	"sha224":     true,
+	"sha256":     true,
+	"sha384":     true,
+	"sha512":     true,
+	"sha512-224": true,
+	"sha512-256": true,
+	"sha3-224":   true,
+	"sha3-256":   true,
+	"sha3-384":   true,
+	"sha3-512":   true,
+}
+
+// SetEnabled turns FIPS mode on (or off) for the rest of the process. FIPS mode cannot be turned off in a build with
+// the "fips" build tag.
+func SetEnabled(on bool) {
+	enabled.Store(on)
+}
+
+// Enabled returns whether FIPS mode is on: when built with the "fips" build tag, or once turned on by the
+// configuration. FIPS mode is a policy on the algorithms Grype uses; the implementations are those of the standard
+// library, which is not a FIPS 140 validated module in the Go version Grype is built with.
+func Enabled() bool {
+	return builtWithFIPS || enabled.Load()
+}
+
+// Approved returns whether the hash algorithm (e.g. "sha256" or "SHA-256") is approved by FIPS 140.
+func Approved(algorithm string) bool {
+	a := strings.ToLower(strings.TrimSpace(algorithm))
+	if strings.HasPrefix(a, "sha-") {
+		a = "sha" + strings.TrimPrefix(a, "sha-")
+	}
+	return approved[a]
+}
+
+// Usable returns whether the hash algorithm can be used: any algorithm when FIPS mode is off, and only the approved
+// algorithms otherwise.
+func Usable(algorithm string) bool {
+	return !Enabled() || Approved(algorithm)
+}
+
+// CheckAlgorithm returns an error wrapping ErrNotApproved when FIPS mode is on and the hash algorithm is not
+// approved, for the given use of the algorithm (e.g. "verify the DB checksum").
+func CheckAlgorithm(algorithm, use string) error {
+	if Usable(algorithm) {
+		return nil
+	}
+	return fmt.Errorf("cannot %s with %s in FIPS mode: %w", use, algorithm, ErrNotApproved)
+}
+
+// TLSConfig returns the TLS configuration restricted to the FIPS-approved protocol versions, cipher suites and curves
+// when FIPS mode is on, or else the configuration as given (which may be nil).
+func TLSConfig(cfg *tls.Config) *tls.Config {
+	if !Enabled() {
+		return cfg
+	}
+	if cfg == nil {
+		cfg = &tls.Config{}
+	} else {
+		cfg = cfg.Clone()
+	}
+	if cfg.MinVersion < tls.VersionTLS12 {
+		cfg.MinVersion = tls.VersionTLS12
+	}
+	// the TLS 1.3 cipher suites are not configurable (all of them are AES-GCM or ChaCha20-Poly1305 suites)
+	cfg.CipherSuites = []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	}
+	cfg.CurvePreferences = []tls.CurveID{tls.CurveP256, tls.CurveP384, tls.CurveP521}
+	return cfg
+}
+
+// HTTPClient returns a copy of the client making only the TLS connections of TLSConfig when FIPS mode is on, or else
+// the client as given. A transport other than an *http.Transport (e.g. one adding credentials) cannot be configured,
+// so it is kept and wrapped instead: a response over a TLS connection that TLSConfig would not allow is refused.
+func HTTPClient(c *http.Client) *http.Client {
+	if !Enabled() {
+		return c
+	}
+	restricted := *c
+	switch transport := c.Transport.(type) {
+	case nil:
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = TLSConfig(t.TLSClientConfig)
+		restricted.Transport = t
+	case *http.Transport:
+		t := transport.Clone()
+		t.TLSClientConfig = TLSConfig(t.TLSClientConfig)
+		restricted.Transport = t
+	default:
+		restricted.Transport = &checkedTransport{next: transport}
+	}
+	return &restricted
+}
+
+// checkedTransport refuses the responses over TLS connections that do not use the FIPS-approved versions and cipher
+// suites.
+type checkedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *checkedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.TLS == nil {
+		return resp, err
+	}
+	if err := checkConnection(resp.TLS); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("refusing the response of %s: %w", req.URL.Host, err)
+	}
+	return resp, nil
+}
+
+func checkConnection(state *tls.ConnectionState) error {
+	if state.Version < tls.VersionTLS12 {
+		return fmt.Errorf("the TLS connection uses %s in FIPS mode: %w", tls.VersionName(state.Version), ErrNotApproved)
+	}
+	if state.Version == tls.VersionTLS12 && !slices.Contains(TLSConfig(nil).CipherSuites, state.CipherSuite) {
+		return fmt.Errorf("the TLS connection uses %s in FIPS mode: %w", tls.CipherSuiteName(state.CipherSuite), ErrNotApproved)
+	}
+	return nil
+}
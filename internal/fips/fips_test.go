@@ -0,0 +1,106 @@
+package fips
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func enable(t *testing.T) {
+	t.Helper()
+	SetEnabled(true)
+	t.Cleanup(func() { SetEnabled(false) })
+}
+
+// disabled skips the test in a build where FIPS mode is always on.
+func disabled(t *testing.T) {
+	t.Helper()
+	if builtWithFIPS {
+		t.Skip("FIPS mode is always on with the fips build tag")
+	}
+}
+
+func TestApproved(t *testing.T) {
+	for _, algorithm := range []string{"sha256", "SHA-256", "sha384", "sha512", "sha3-256", " sha224 "} {
+		assert.True(t, Approved(algorithm), algorithm)
+	}
+	for _, algorithm := range []string{"md5", "sha1", "SHA-1", "xxh64", ""} {
+		assert.False(t, Approved(algorithm), algorithm)
+	}
+}
+
+func TestCheckAlgorithm(t *testing.T) {
+	disabled(t)
+	require.NoError(t, CheckAlgorithm("md5", "verify the DB"), "any algorithm can be used when FIPS mode is off")
+	assert.True(t, Usable("sha1"))
+
+	enable(t)
+	err := CheckAlgorithm("md5", "verify the DB")
+	require.ErrorIs(t, err, ErrNotApproved)
+	assert.EqualError(t, err, "cannot verify the DB with md5 in FIPS mode: not a FIPS-approved algorithm")
+	require.NoError(t, CheckAlgorithm("sha256", "verify the DB"))
+	assert.False(t, Usable("sha1"))
+}
+
+func TestTLSConfig(t *testing.T) {
+	disabled(t)
+	cfg := &tls.Config{MinVersion: tls.VersionTLS10, ServerName: "example.com"}
+	assert.Same(t, cfg, TLSConfig(cfg))
+	assert.Nil(t, TLSConfig(nil))
+
+	enable(t)
+	restricted := TLSConfig(cfg)
+	assert.Equal(t, uint16(tls.VersionTLS12), restricted.MinVersion)
+	assert.Equal(t, "example.com", restricted.ServerName)
+	assert.NotEmpty(t, restricted.CipherSuites)
+	assert.Equal(t, uint16(tls.VersionTLS10), cfg.MinVersion, "the given configuration is not changed")
+
+	assert.Equal(t, uint16(tls.VersionTLS12), TLSConfig(nil).MinVersion)
+}
+
+func TestHTTPClient(t *testing.T) {
+	disabled(t)
+	assert.Same(t, http.DefaultClient, HTTPClient(http.DefaultClient))
+
+	enable(t)
+	c := HTTPClient(http.DefaultClient)
+	assert.NotSame(t, http.DefaultClient, c)
+	assert.Nil(t, http.DefaultClient.Transport, "the given client is not changed")
+	transport, ok := c.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestHTTPClient_wrappedTransport(t *testing.T) {
+	enable(t)
+	state := &tls.ConnectionState{Version: tls.VersionTLS12, CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}
+	var called bool
+	c := HTTPClient(&http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, TLS: state}, nil
+	})})
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+	resp, err := c.Transport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, called, "the given transport is kept")
+
+	state.CipherSuite = tls.TLS_RSA_WITH_AES_128_CBC_SHA
+	_, err = c.Transport.RoundTrip(req)
+	require.ErrorIs(t, err, ErrNotApproved)
+
+	state.Version = tls.VersionTLS11
+	_, err = c.Transport.RoundTrip(req)
+	require.ErrorIs(t, err, ErrNotApproved)
+}
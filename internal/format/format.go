@@ -5,14 +5,16 @@ import (
 )
 
 const (
-	UnknownFormat   Format = "unknown"
-	JSONFormat      Format = "json"
-	TableFormat     Format = "table"
-	CycloneDXFormat Format = "cyclonedx"
-	CycloneDXJSON   Format = "cyclonedx-json"
-	CycloneDXXML    Format = "cyclonedx-xml"
-	SarifFormat     Format = "sarif"
-	TemplateFormat  Format = "template"
+	UnknownFormat           Format = "unknown"
+	JSONFormat              Format = "json"
+	TableFormat             Format = "table"
+	CycloneDXFormat         Format = "cyclonedx"
+	CycloneDXJSON           Format = "cyclonedx-json"
+	CycloneDXXML            Format = "cyclonedx-xml"
+	SarifFormat             Format = "sarif"
+	OpenVEXFormat           Format = "openvex"
+	TemplateFormat          Format = "template"
+	GitHubAnnotationsFormat Format = "github-annotations"
 
 	// DEPRECATED <-- TODO: remove in v1.0
 	EmbeddedVEXJSON Format = "embedded-cyclonedx-vex-json"
@@ -37,8 +39,12 @@ func Parse(userInput string) Format {
 		return TableFormat
 	case strings.ToLower(SarifFormat.String()):
 		return SarifFormat
+	case strings.ToLower(OpenVEXFormat.String()):
+		return OpenVEXFormat
 	case strings.ToLower(TemplateFormat.String()):
 		return TemplateFormat
+	case strings.ToLower(GitHubAnnotationsFormat.String()):
+		return GitHubAnnotationsFormat
 	case strings.ToLower(CycloneDXFormat.String()):
 		return CycloneDXFormat
 	case strings.ToLower(CycloneDXJSON.String()):
@@ -61,7 +67,9 @@ var AvailableFormats = []Format{
 	CycloneDXFormat,
 	CycloneDXJSON,
 	SarifFormat,
+	OpenVEXFormat,
 	TemplateFormat,
+	GitHubAnnotationsFormat,
 }
 
 // DeprecatedFormats TODO: remove in v1.0
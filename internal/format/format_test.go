@@ -23,6 +23,14 @@ func TestParse(t *testing.T) {
 			"jSOn",
 			JSONFormat,
 		},
+		{
+			"openvex",
+			OpenVEXFormat,
+		},
+		{
+			"github-annotations",
+			GitHubAnnotationsFormat,
+		},
 		{
 			"booboodepoopoo",
 			UnknownFormat,
@@ -4,17 +4,35 @@ import (
 	"github.com/wagoodman/go-presenter"
 
 	"github.com/anchore/grype/grype/presenter/cyclonedx"
+	"github.com/anchore/grype/grype/presenter/github"
 	"github.com/anchore/grype/grype/presenter/json"
 	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/presenter/openvex"
 	"github.com/anchore/grype/grype/presenter/sarif"
 	"github.com/anchore/grype/grype/presenter/table"
 	"github.com/anchore/grype/grype/presenter/template"
+	"github.com/anchore/grype/grype/presenter/theme"
+	"github.com/anchore/grype/grype/priority"
 	"github.com/anchore/grype/internal/log"
 )
 
 type PresentationConfig struct {
 	TemplateFilePath string
 	ShowSuppressed   bool
+	// Redact applies the redactions to the values of the results before they are presented, for the reports written to
+	// files as for those published to stdout.
+	Redact bool
+	// SarifProfile adjusts the SARIF report to the platform consuming it (e.g. "sarif=azure").
+	SarifProfile sarif.Profile
+	// Theme is how the table output shows the severities (colors and markers).
+	Theme theme.Theme
+	// Top shows only the top matches ranked by RankBy in the table output, all the matches when 0.
+	Top    int
+	RankBy priority.Criterion
+	// Split writes the reports written to files as a file per group of matches, named after SplitFilename (a
+	// template, DefaultSplitFilename when empty).
+	Split         Split
+	SplitFilename string
 }
 
 // GetPresenter retrieves a Presenter that matches a CLI option
@@ -23,7 +41,7 @@ func GetPresenter(format Format, c PresentationConfig, pb models.PresenterConfig
 	case JSONFormat:
 		return json.NewPresenter(pb)
 	case TableFormat:
-		return table.NewPresenter(pb, c.ShowSuppressed)
+		return table.NewPresenter(pb, c.ShowSuppressed).WithTheme(c.Theme).WithTop(c.Top, c.RankBy)
 
 	// NOTE: cyclonedx is identical to EmbeddedVEXJSON
 	// The cyclonedx library only provides two BOM formats: JSON and XML
@@ -35,9 +53,13 @@ func GetPresenter(format Format, c PresentationConfig, pb models.PresenterConfig
 	case CycloneDXXML:
 		return cyclonedx.NewXMLPresenter(pb)
 	case SarifFormat:
-		return sarif.NewPresenter(pb)
+		return sarif.NewPresenter(pb, c.SarifProfile)
+	case OpenVEXFormat:
+		return openvex.NewPresenter(pb)
 	case TemplateFormat:
 		return template.NewPresenter(pb, c.TemplateFilePath)
+	case GitHubAnnotationsFormat:
+		return github.NewPresenter(pb)
 	// DEPRECATED TODO: remove in v1.0
 	case EmbeddedVEXJSON:
 		log.Warn("embedded-cyclonedx-vex-json format is deprecated and will be removed in v1.0")
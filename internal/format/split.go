@@ -0,0 +1,306 @@
+package format
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// Split is how the reports written to files are split into one file per group of matches.
+type Split string
+
+const (
+	NoSplit Split = ""
+	// SplitByTarget writes a file per scanned platform of an image index, or otherwise per file the packages were
+	// found in (e.g. each lockfile of a directory).
+	SplitByTarget Split = "by-target"
+	// SplitBySeverity writes a file per severity of the matched vulnerabilities.
+	SplitBySeverity Split = "by-severity"
+)
+
+// DefaultSplitFilename is the template of the names of the files of a split report.
+const DefaultSplitFilename = "{{.Base}}-{{.Group}}{{.Ext}}"
+
+// AvailableSplits are the ways a report can be split.
+var AvailableSplits = []Split{SplitByTarget, SplitBySeverity}
+
+// ParseSplit returns the split with the given name, or an error for an unknown split.
+func ParseSplit(s string) (Split, error) {
+	split := Split(strings.ToLower(strings.TrimSpace(s)))
+	if split == NoSplit {
+		return NoSplit, nil
+	}
+	for _, a := range AvailableSplits {
+		if split == a {
+			return split, nil
+		}
+	}
+	return NoSplit, fmt.Errorf("unknown output split %q (options: %v)", s, AvailableSplits)
+}
+
+// SplitFilename is the data of the template of the names of the files of a split report.
+type SplitFilename struct {
+	// Path is the path the report would be written to without splitting (e.g. "reports/results.json").
+	Path string
+	// Base and Ext are the name of the file without the extension and the extension (e.g. "results" and ".json").
+	Base string
+	Ext  string
+	// Format is the format of the report (e.g. "json").
+	Format string
+	// Group is the group of the matches in the file, made safe for a filename (e.g. "critical" or "linux_arm64").
+	Group string
+}
+
+// ParseSplitFilename parses the template of the names of the files of a split report.
+func ParseSplitFilename(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultSplitFilename
+	}
+	tmpl, err := template.New("output-split-filename").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the output split filename template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// NoMatchesGroup is the group of the report written when no group has matches, so that a split report always writes
+// at least one file.
+const NoMatchesGroup = "none"
+
+// splitGroup is the part of the result written to a single file of a split report.
+type splitGroup struct {
+	name   string
+	result models.PresenterConfig
+}
+
+// splitResult returns the groups of the result, in the order of their names (or from the most severe for
+// by-severity). Groups without matches are left out, except the scanned platforms.
+func splitResult(by Split, result models.PresenterConfig) []splitGroup {
+	switch by {
+	case SplitBySeverity:
+		return splitBySeverity(result)
+	case SplitByTarget:
+		if len(result.Platforms) > 0 {
+			return splitByPlatform(result)
+		}
+		return splitByLocation(result)
+	}
+	return []splitGroup{{result: result}}
+}
+
+func splitBySeverity(result models.PresenterConfig) []splitGroup {
+	severity := func(m match.Match) string {
+		var meta *vulnerability.Metadata
+		if result.MetadataProvider != nil {
+			meta, _ = result.MetadataProvider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace)
+		}
+		if meta = m.WithSeverityOverride(meta); meta != nil {
+			if sev := vulnerability.ParseSeverity(meta.Severity); sev != vulnerability.UnknownSeverity {
+				return sev.String()
+			}
+		}
+		return "unknown"
+	}
+
+	groups := groupMatches(result, severity)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return vulnerability.ParseSeverity(groups[i].name) > vulnerability.ParseSeverity(groups[j].name)
+	})
+	return groups
+}
+
+func splitByPlatform(result models.PresenterConfig) []splitGroup {
+	var groups []splitGroup
+	for _, p := range result.Platforms {
+		r := result
+		r.Platforms = nil
+		r.Matches = p.Matches
+		r.IgnoredMatches = p.IgnoredMatches
+		r.Packages = p.Packages
+		r.Context = p.Context
+		groups = append(groups, splitGroup{name: p.Platform, result: r})
+	}
+	return groups
+}
+
+func splitByLocation(result models.PresenterConfig) []splitGroup {
+	location := func(m match.Match) string {
+		return packageLocation(m.Package)
+	}
+
+	groups := groupMatches(result, location)
+	for i := range groups {
+		var packages []pkg.Package
+		for _, p := range result.Packages {
+			if packageLocation(p) == groups[i].name {
+				packages = append(packages, p)
+			}
+		}
+		groups[i].result.Packages = packages
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].name < groups[j].name
+	})
+	return groups
+}
+
+// packageLocation returns the path of the file the package was found in, "unknown" when the location is unknown.
+func packageLocation(p pkg.Package) string {
+	locations := p.Locations.ToSlice()
+	if len(locations) == 0 || locations[0].RealPath == "" {
+		return "unknown"
+	}
+	return locations[0].RealPath
+}
+
+// groupMatches returns the groups of the matches (and the ignored matches) with the same key, in the order the keys
+// are first seen.
+func groupMatches(result models.PresenterConfig, key func(match.Match) string) []splitGroup {
+	var groups []splitGroup
+	index := map[string]int{}
+	group := func(name string) *splitGroup {
+		i, ok := index[name]
+		if !ok {
+			r := result
+			r.Matches = match.NewMatches()
+			r.IgnoredMatches = nil
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, splitGroup{name: name, result: r})
+		}
+		return &groups[i]
+	}
+
+	for _, m := range result.Matches.Sorted() {
+		g := group(key(m))
+		g.result.Matches.Add(m)
+	}
+	for _, m := range result.IgnoredMatches {
+		name := key(m.Match)
+		if _, ok := index[name]; !ok {
+			// the ignored matches alone do not make a group
+			continue
+		}
+		g := group(name)
+		g.result.IgnoredMatches = append(g.result.IgnoredMatches, m)
+	}
+	return groups
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// filenameSafe returns the name with any character that is not safe in a filename replaced (e.g. "linux/arm64"
+// becomes "linux_arm64").
+func filenameSafe(name string) string {
+	name = strings.Trim(unsafeFilenameChars.ReplaceAllString(name, "_"), "_.")
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// groupFilenames returns the names of the groups made safe for a filename, each suffixed with a digest of the name of
+// the group when it is made the same as the name of another group (e.g. "linux/arm64" and "linux_arm64").
+func groupFilenames(groups []splitGroup) []string {
+	names := make([]string, len(groups))
+	count := map[string]int{}
+	for i, g := range groups {
+		names[i] = filenameSafe(g.name)
+		count[names[i]]++
+	}
+	for i, g := range groups {
+		if count[names[i]] > 1 {
+			digest := sha256.Sum256([]byte(g.name))
+			names[i] += "-" + hex.EncodeToString(digest[:])[:8]
+		}
+	}
+	return names
+}
+
+// scanResultSplitWriter implements ScanResultWriter writing a file per group of the matches of the result.
+type scanResultSplitWriter struct {
+	format   Format
+	cfg      PresentationConfig
+	path     string
+	filename *template.Template
+}
+
+// Write the groups of the provided result, each to the file named after the group, or the result to the file of the
+// NoMatchesGroup when no group has matches
+func (w *scanResultSplitWriter) Write(s models.PresenterConfig) error {
+	groups := splitResult(w.cfg.Split, s)
+	if len(groups) == 0 {
+		groups = []splitGroup{{name: NoMatchesGroup, result: s}}
+	}
+	// the files are named before any is written, so that a template naming two groups the same writes nothing
+	paths := make([]string, len(groups))
+	named := map[string]string{}
+	for i, name := range groupFilenames(groups) {
+		path, err := w.groupPath(groups[i].name, name)
+		if err != nil {
+			return err
+		}
+		if other, ok := named[path]; ok {
+			return fmt.Errorf("the output split filename template gives the same name (%s) for the %q and %q groups", path, other, groups[i].name)
+		}
+		named[path] = groups[i].name
+		paths[i] = path
+	}
+
+	for i, g := range groups {
+		path := paths[i]
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return fmt.Errorf("unable to create report file: %w", err)
+		}
+		stream := &scanResultStreamWriter{format: w.format, cfg: w.cfg, out: out}
+		err = stream.Write(g.result)
+		if closeErr := stream.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("unable to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// groupPath returns the path of the file of the group of matches with the given name (and filename safe name).
+func (w *scanResultSplitWriter) groupPath(group, safeGroup string) (string, error) {
+	ext := filepath.Ext(w.path)
+	data := SplitFilename{
+		Path:   w.path,
+		Base:   strings.TrimSuffix(filepath.Base(w.path), ext),
+		Ext:    ext,
+		Format: w.format.String(),
+		Group:  safeGroup,
+	}
+	var buf bytes.Buffer
+	if err := w.filename.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("unable to name the file of the %q group: %w", group, err)
+	}
+	name := buf.String()
+	if name == "" {
+		return "", fmt.Errorf("the output split filename template gives an empty name for the %q group", group)
+	}
+	if !filepath.IsAbs(name) {
+		// the names are relative to the directory of the report
+		name = filepath.Join(filepath.Dir(w.path), name)
+	}
+	return name, nil
+}
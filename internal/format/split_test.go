@@ -0,0 +1,214 @@
+package format
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/file"
+)
+
+type severities map[string]string
+
+func (s severities) GetMetadata(id, _ string) (*vulnerability.Metadata, error) {
+	severity, ok := s[id]
+	if !ok {
+		return nil, nil
+	}
+	return &vulnerability.Metadata{ID: id, Severity: severity}, nil
+}
+
+func splitMatch(id, pkgName, path string) match.Match {
+	p := pkg.Package{ID: pkg.ID(pkgName), Name: pkgName, Version: "1.0.0"}
+	if path != "" {
+		p.Locations = file.NewLocationSet(file.NewLocation(path))
+	}
+	return match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: id},
+		Package:       p,
+	}
+}
+
+func groupNames(groups []splitGroup) map[string][]string {
+	out := map[string][]string{}
+	for _, g := range groups {
+		ids := []string{}
+		for _, m := range g.result.Matches.Sorted() {
+			ids = append(ids, m.Vulnerability.ID)
+		}
+		out[g.name] = ids
+	}
+	return out
+}
+
+func TestParseSplit(t *testing.T) {
+	for value, want := range map[string]Split{"": NoSplit, "by-target": SplitByTarget, " By-Severity ": SplitBySeverity} {
+		got, err := ParseSplit(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseSplit("by-owner")
+	require.ErrorContains(t, err, `unknown output split "by-owner"`)
+}
+
+func Test_splitResult(t *testing.T) {
+	lodash := splitMatch("GHSA-1", "lodash", "/app/package-lock.json")
+	minimist := splitMatch("GHSA-2", "minimist", "/app/package-lock.json")
+	requests := splitMatch("GHSA-3", "requests", "/app/requirements.txt")
+	bare := splitMatch("CVE-2024-0001", "bare", "")
+	ignored := splitMatch("GHSA-4", "urllib3", "/app/requirements.txt")
+	orphan := splitMatch("GHSA-5", "left-pad", "/lib/package-lock.json")
+
+	result := models.PresenterConfig{
+		Matches:          match.NewMatches(lodash, minimist, requests, bare),
+		IgnoredMatches:   []match.IgnoredMatch{{Match: ignored}, {Match: orphan}},
+		Packages:         []pkg.Package{lodash.Package, minimist.Package, requests.Package, bare.Package, ignored.Package},
+		MetadataProvider: severities{"GHSA-1": "High", "GHSA-2": "Critical", "GHSA-3": "High", "GHSA-4": "Low"},
+	}
+
+	t.Run("by-severity", func(t *testing.T) {
+		groups := splitResult(SplitBySeverity, result)
+		require.Len(t, groups, 3)
+		assert.Equal(t, []string{"critical", "high", "unknown"}, []string{groups[0].name, groups[1].name, groups[2].name})
+		assert.Equal(t, map[string][]string{
+			"critical": {"GHSA-2"},
+			"high":     {"GHSA-1", "GHSA-3"},
+			"unknown":  {"CVE-2024-0001"},
+		}, groupNames(groups))
+		// the ignored match of a low severity is left out, as no match has a low severity
+		assert.Empty(t, groups[0].result.IgnoredMatches)
+		assert.Empty(t, groups[1].result.IgnoredMatches)
+		require.Len(t, groups[2].result.IgnoredMatches, 1)
+		assert.Equal(t, "GHSA-5", groups[2].result.IgnoredMatches[0].Vulnerability.ID)
+	})
+
+	t.Run("by-target", func(t *testing.T) {
+		groups := splitResult(SplitByTarget, result)
+		require.Len(t, groups, 3)
+		assert.Equal(t, []string{"/app/package-lock.json", "/app/requirements.txt", "unknown"}, []string{groups[0].name, groups[1].name, groups[2].name})
+		assert.Equal(t, map[string][]string{
+			"/app/package-lock.json": {"GHSA-1", "GHSA-2"},
+			"/app/requirements.txt":  {"GHSA-3"},
+			"unknown":                {"CVE-2024-0001"},
+		}, groupNames(groups))
+
+		requirements := groups[1].result
+		require.Len(t, requirements.IgnoredMatches, 1)
+		assert.Equal(t, "GHSA-4", requirements.IgnoredMatches[0].Vulnerability.ID)
+		assert.Equal(t, []pkg.Package{requests.Package, ignored.Package}, requirements.Packages)
+	})
+
+	t.Run("by-target with platforms", func(t *testing.T) {
+		r := result
+		r.Platforms = []models.PlatformResult{
+			{Platform: "linux/amd64", Matches: match.NewMatches(lodash)},
+			{Platform: "linux/arm64", Matches: match.NewMatches()},
+		}
+		groups := splitResult(SplitByTarget, r)
+		assert.Equal(t, map[string][]string{
+			"linux/amd64": {"GHSA-1"},
+			"linux/arm64": {},
+		}, groupNames(groups))
+		for _, g := range groups {
+			assert.Nil(t, g.result.Platforms)
+		}
+	})
+}
+
+func Test_filenameSafe(t *testing.T) {
+	assert.Equal(t, "linux_arm64", filenameSafe("linux/arm64"))
+	assert.Equal(t, "app_package-lock.json", filenameSafe("/app/package-lock.json"))
+	assert.Equal(t, "unknown", filenameSafe("../"))
+}
+
+func Test_groupFilenames(t *testing.T) {
+	names := groupFilenames([]splitGroup{{name: "linux/arm64"}, {name: "linux_arm64"}, {name: "linux/amd64"}})
+	require.Len(t, names, 3)
+	assert.Regexp(t, `^linux_arm64-[0-9a-f]{8}$`, names[0])
+	assert.Regexp(t, `^linux_arm64-[0-9a-f]{8}$`, names[1])
+	assert.NotEqual(t, names[0], names[1])
+	assert.Equal(t, "linux_amd64", names[2])
+}
+
+func Test_scanResultSplitWriter_noMatches(t *testing.T) {
+	out := t.TempDir()
+	writer, err := MakeScanResultWriter([]string{"json=" + filepath.Join(out, "results.json")}, "", PresentationConfig{Split: SplitBySeverity})
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(models.PresenterConfig{Matches: match.NewMatches()}))
+
+	contents, err := os.ReadFile(filepath.Join(out, "results-none.json"))
+	require.NoError(t, err)
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(contents, &doc))
+	assert.Empty(t, doc.Matches)
+}
+
+func Test_scanResultSplitWriter_sameName(t *testing.T) {
+	lodash := splitMatch("GHSA-1", "lodash", "/app/package-lock.json")
+	minimist := splitMatch("GHSA-2", "minimist", "/lib/package-lock.json")
+	result := models.PresenterConfig{Matches: match.NewMatches(lodash, minimist)}
+	writer, err := MakeScanResultWriter([]string{"json=" + filepath.Join(t.TempDir(), "results.json")}, "", PresentationConfig{Split: SplitByTarget, SplitFilename: "{{.Base}}{{.Ext}}"})
+	require.NoError(t, err)
+	require.ErrorContains(t, writer.Write(result), "gives the same name")
+}
+
+func Test_scanResultSplitWriter(t *testing.T) {
+	dir := t.TempDir()
+	lodash := splitMatch("GHSA-1", "lodash", "/app/package-lock.json")
+	minimist := splitMatch("GHSA-2", "minimist", "/app/package-lock.json")
+	result := models.PresenterConfig{
+		Matches:          match.NewMatches(lodash, minimist),
+		Packages:         []pkg.Package{lodash.Package, minimist.Package},
+		MetadataProvider: severities{"GHSA-1": "High", "GHSA-2": "Critical"},
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		want     []string
+	}{
+		{
+			name: "default filename",
+			want: []string{"results-critical.json", "results-high.json"},
+		},
+		{
+			name:     "templated filename",
+			filename: "{{.Group}}/{{.Base}}.{{.Format}}",
+			want:     []string{"critical/results.json", "high/results.json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := filepath.Join(dir, tt.name)
+			writer, err := MakeScanResultWriter([]string{"json=" + filepath.Join(out, "results.json")}, "", PresentationConfig{Split: SplitBySeverity, SplitFilename: tt.filename})
+			require.NoError(t, err)
+			require.NoError(t, writer.Write(result))
+
+			for _, name := range tt.want {
+				contents, err := os.ReadFile(filepath.Join(out, name))
+				require.NoError(t, err)
+				var doc models.Document
+				require.NoError(t, json.Unmarshal(contents, &doc))
+				assert.Len(t, doc.Matches, 1)
+			}
+			// the unsplit report is not written
+			assert.NoFileExists(t, filepath.Join(out, "results.json"))
+		})
+	}
+}
+
+func Test_MakeScanResultWriter_split(t *testing.T) {
+	_, err := MakeScanResultWriter([]string{"json"}, "", PresentationConfig{Split: SplitBySeverity})
+	require.ErrorContains(t, err, "can only be split when written to files")
+
+	_, err = MakeScanResultWriter([]string{"json=results.json"}, "", PresentationConfig{Split: SplitBySeverity, SplitFilename: "{{.Group"})
+	require.ErrorContains(t, err, "unable to parse the output split filename template")
+}
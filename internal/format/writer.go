@@ -11,9 +11,12 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/mitchellh/go-homedir"
 
+	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/presenter/sarif"
 	"github.com/anchore/grype/internal/bus"
 	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/grype/internal/redact"
 )
 
 type ScanResultWriter interface {
@@ -35,6 +38,10 @@ func MakeScanResultWriter(outputs []string, defaultFile string, cfg Presentation
 		return nil, err
 	}
 
+	if cfg.Split != NoSplit && !anyFile(outputOptions) {
+		return nil, fmt.Errorf("the reports can only be split when written to files (e.g. -o json=results.json)")
+	}
+
 	writer, err := newMultiWriter(outputOptions...)
 	if err != nil {
 		return nil, err
@@ -43,6 +50,15 @@ func MakeScanResultWriter(outputs []string, defaultFile string, cfg Presentation
 	return writer, nil
 }
 
+func anyFile(options []scanResultWriterDescription) bool {
+	for _, o := range options {
+		if o.Path != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // MakeScanResultWriterForFormat creates a ScanResultWriter for the given format or returns an error.
 func MakeScanResultWriterForFormat(f string, path string, cfg PresentationConfig) (ScanResultWriter, error) {
 	format := Parse(f)
@@ -59,6 +75,27 @@ func MakeScanResultWriterForFormat(f string, path string, cfg PresentationConfig
 	return writer, nil
 }
 
+// Output is a report written by a ScanResultWriter: the format of the report and the file it is written to (empty
+// for stdout), along with the profile of a SARIF report.
+type Output struct {
+	Format       Format
+	Path         string
+	SarifProfile sarif.Profile
+}
+
+// ParseOutputs returns the reports that MakeScanResultWriter would write for the given options, without creating any file.
+func ParseOutputs(outputs []string, defaultFile string) ([]Output, error) {
+	descriptions, err := parseOutputFlags(outputs, defaultFile, PresentationConfig{})
+	if err != nil {
+		return nil, err
+	}
+	var out []Output
+	for _, d := range descriptions {
+		out = append(out, Output{Format: d.Format, Path: d.Path, SarifProfile: d.Cfg.SarifProfile})
+	}
+	return out, nil
+}
+
 // parseOutputFlags utility to parse command-line option strings and retain the existing behavior of default format and file
 func parseOutputFlags(outputs []string, defaultFile string, cfg PresentationConfig) (out []scanResultWriterDescription, errs error) {
 	// always should have one option -- we generally get the default of "table", but just make sure
@@ -90,7 +127,20 @@ func parseOutputFlags(outputs []string, defaultFile string, cfg PresentationConf
 			continue
 		}
 
-		out = append(out, newWriterDescription(format, file, cfg))
+		outputCfg := cfg
+		if format == SarifFormat && len(parts) > 1 {
+			// a profile may be given in place of the file (sarif=azure), or before it (sarif=azure=report.sarif)
+			profileParts := strings.SplitN(parts[1], "=", 2)
+			if profile, ok := sarif.ParseProfile(profileParts[0]); ok {
+				outputCfg.SarifProfile = profile
+				file = defaultFile
+				if len(profileParts) > 1 {
+					file = profileParts[1]
+				}
+			}
+		}
+
+		out = append(out, newWriterDescription(format, file, outputCfg))
 	}
 	return out, errs
 }
@@ -137,6 +187,20 @@ func newMultiWriter(options ...scanResultWriterDescription) (_ *scanResultMultiW
 				cfg:    option.Cfg,
 			})
 		default:
+			if option.Cfg.Split != NoSplit {
+				// the files are created as the groups of the result are written
+				filename, err := ParseSplitFilename(option.Cfg.SplitFilename)
+				if err != nil {
+					return nil, err
+				}
+				out.writers = append(out.writers, &scanResultSplitWriter{
+					format:   option.Format,
+					cfg:      option.Cfg,
+					path:     option.Path,
+					filename: filename,
+				})
+				continue
+			}
 			// create any missing subdirectories
 			dir := filepath.Dir(option.Path)
 			if dir != "" {
@@ -185,6 +249,9 @@ type scanResultStreamWriter struct {
 
 // Write the provided result to the data stream
 func (w *scanResultStreamWriter) Write(s models.PresenterConfig) error {
+	if w.cfg.Redact {
+		s = redactedResult(s)
+	}
 	pres := GetPresenter(w.format, w.cfg, s)
 	if err := pres.Present(w.out); err != nil {
 		return fmt.Errorf("unable to encode result: %w", err)
@@ -192,6 +259,24 @@ func (w *scanResultStreamWriter) Write(s models.PresenterConfig) error {
 	return nil
 }
 
+// redactedResult returns a copy of the result with the redactions applied to the values of the model (the source,
+// the package locations and metadata, the configuration, ...) rather than to the encoded report, so that the report
+// stays well-formed.
+func redactedResult(s models.PresenterConfig) models.PresenterConfig {
+	// the provider reads the vulnerability data of the DB, which holds nothing of the environment
+	provider := s.MetadataProvider
+	s.MetadataProvider = nil
+	s = redact.Value(s)
+	s.MetadataProvider = provider
+
+	// the matches are indexed by unexported fields, which are rebuilt from the redacted matches
+	s.Matches = match.NewMatches(redact.Value(s.Matches.Sorted())...)
+	for i := range s.Platforms {
+		s.Platforms[i].Matches = match.NewMatches(redact.Value(s.Platforms[i].Matches.Sorted())...)
+	}
+	return s
+}
+
 // Close any resources, such as open files
 func (w *scanResultStreamWriter) Close() error {
 	if closer, ok := w.out.(io.Closer); ok {
@@ -208,6 +293,9 @@ type scanResultPublisher struct {
 
 // Write the provided result to the data stream
 func (w *scanResultPublisher) Write(s models.PresenterConfig) error {
+	if w.cfg.Redact {
+		s = redactedResult(s)
+	}
 	pres := GetPresenter(w.format, w.cfg, s)
 	buf := &bytes.Buffer{}
 	if err := pres.Present(buf); err != nil {
@@ -1,12 +1,26 @@
 package format
 
 import (
+	"bytes"
+	"encoding/json"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/docker/docker/pkg/homedir"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	loggerRedact "github.com/anchore/go-logger/adapter/redact"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/presenter/sarif"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/redact"
+	"github.com/anchore/syft/syft/file"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
 )
 
 func Test_MakeScanResultWriter(t *testing.T) {
@@ -36,6 +50,34 @@ func Test_MakeScanResultWriter(t *testing.T) {
 	}
 }
 
+func Test_ParseOutputs(t *testing.T) {
+	outputs, err := ParseOutputs([]string{"table", "json=report.json", "sarif"}, "default.out")
+	require.NoError(t, err)
+	assert.Equal(t, []Output{
+		{Format: TableFormat, Path: "default.out"},
+		{Format: JSONFormat, Path: "report.json"},
+		{Format: SarifFormat, Path: "default.out"},
+	}, outputs)
+
+	outputs, err = ParseOutputs(nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, []Output{{Format: TableFormat}}, outputs)
+
+	_, err = ParseOutputs([]string{"unknown"}, "")
+	assert.ErrorContains(t, err, `unsupported output format "unknown"`)
+}
+
+func Test_ParseOutputs_sarifProfiles(t *testing.T) {
+	outputs, err := ParseOutputs([]string{"sarif=azure", "sarif=ghas=ghas.sarif", "sarif=report.sarif", "sarif=./azure"}, "default.out")
+	require.NoError(t, err)
+	assert.Equal(t, []Output{
+		{Format: SarifFormat, Path: "default.out", SarifProfile: sarif.AzureProfile},
+		{Format: SarifFormat, Path: "ghas.sarif", SarifProfile: sarif.GHASProfile},
+		{Format: SarifFormat, Path: "report.sarif"},
+		{Format: SarifFormat, Path: "./azure"},
+	}, outputs)
+}
+
 func Test_newSBOMMultiWriter(t *testing.T) {
 	type writerConfig struct {
 		format string
@@ -216,3 +258,57 @@ func Test_newSBOMWriterDescription(t *testing.T) {
 		})
 	}
 }
+
+func Test_scanResultStreamWriter_redact(t *testing.T) {
+	redact.Set(loggerRedact.NewStore("/home/alice/app"))
+
+	p := pkg.Package{
+		ID:        "pkg-1",
+		Name:      "left-pad",
+		Version:   "1.3.0",
+		Type:      syftPkg.NpmPkg,
+		Locations: file.NewLocationSet(file.NewLocation("/home/alice/app/package-lock.json")),
+	}
+	s := models.PresenterConfig{
+		Packages: []pkg.Package{p},
+		Context: pkg.Context{
+			Source: &source.Description{Metadata: source.DirectoryMetadata{Path: "/home/alice/app"}},
+		},
+	}
+	for _, enabled := range []bool{false, true} {
+		var out bytes.Buffer
+		w := &scanResultStreamWriter{format: JSONFormat, out: &out, cfg: PresentationConfig{Redact: enabled}}
+		require.NoError(t, w.Write(s))
+		assert.True(t, json.Valid(out.Bytes()))
+		if enabled {
+			assert.NotContains(t, out.String(), "/home/alice/app")
+			assert.Contains(t, out.String(), `"target": "*******"`)
+		} else {
+			assert.Contains(t, out.String(), `"target": "/home/alice/app"`)
+		}
+	}
+	assert.Equal(t, "/home/alice/app", s.Context.Source.Metadata.(source.DirectoryMetadata).Path, "the result is not changed")
+	assert.Equal(t, "/home/alice/app/package-lock.json", s.Packages[0].Locations.ToSlice()[0].RealPath)
+
+	t.Run("model values", testRedactedResult)
+}
+
+func testRedactedResult(t *testing.T) {
+	p := pkg.Package{
+		ID:        "pkg-1",
+		Name:      "left-pad",
+		Locations: file.NewLocationSet(file.NewLocation("/home/alice/app/package-lock.json")),
+		Metadata:  pkg.JavaMetadata{VirtualPath: "/home/alice/app/lib.jar"},
+	}
+	s := redactedResult(models.PresenterConfig{
+		Matches:  match.NewMatches(match.Match{Package: p, Vulnerability: vulnerability.Vulnerability{ID: "CVE-2024-0001"}}),
+		Packages: []pkg.Package{p},
+		DBStatus: struct{ Location string }{Location: "/home/alice/app/.cache/grype/db/5"},
+	})
+
+	assert.Equal(t, "*******/package-lock.json", s.Packages[0].Locations.ToSlice()[0].RealPath)
+	assert.Equal(t, "*******/lib.jar", s.Packages[0].Metadata.(pkg.JavaMetadata).VirtualPath)
+	assert.Equal(t, "*******/package-lock.json", s.Matches.Sorted()[0].Package.Locations.ToSlice()[0].RealPath)
+	assert.Equal(t, struct{ Location string }{Location: "*******/.cache/grype/db/5"}, s.DBStatus)
+	assert.Equal(t, "CVE-2024-0001", s.Matches.Sorted()[0].Vulnerability.ID)
+}
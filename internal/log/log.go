@@ -14,6 +14,11 @@ import (
 var log = discard.New()
 
 func Set(l logger.Logger) {
+	log = Redacted(l)
+}
+
+// Redacted returns the given logger redacting the values of the internal redact package.
+func Redacted(l logger.Logger) logger.Logger {
 	// though the application will automatically have a redaction logger, library consumers may not be doing this.
 	// for this reason we additionally ensure there is a redaction logger configured for any logger passed. The
 	// source of truth for redaction values is still in the internal redact package. If the passed logger is already
@@ -22,7 +27,7 @@ func Set(l logger.Logger) {
 	if store != nil {
 		l = redact.New(l, store)
 	}
-	log = l
+	return l
 }
 
 func Get() logger.Logger {
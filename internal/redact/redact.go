@@ -34,3 +34,23 @@ func Apply(value string) string {
 	}
 	return store.RedactString(value)
 }
+
+// Error returns the error with the redactions applied to its message, which still unwraps to the given error.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return redactedError{err: err}
+}
+
+type redactedError struct {
+	err error
+}
+
+func (e redactedError) Error() string {
+	return Apply(e.err.Error())
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
+}
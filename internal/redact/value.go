@@ -0,0 +1,100 @@
+package redact
+
+import (
+	"reflect"
+
+	"github.com/anchore/syft/syft/file"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// Value returns a copy of the value with the redactions applied to every string it holds (the fields of structs, the
+// elements of slices, arrays and maps, the keys of maps, and the values behind pointers and interfaces). The value is
+// not changed. Unexported fields and functions are shared with the copy as they are, except for the sets of
+// locations and the collections of packages of syft, which are rebuilt from their redacted elements.
+func Value[T any](v T) T {
+	if store == nil {
+		panic("cannot apply redactions without a store")
+	}
+	in := reflect.ValueOf(&v).Elem()
+	out := redactValue(in, make(map[uintptr]reflect.Value))
+	return out.Interface().(T)
+}
+
+// redactValue returns the redacted copy of the value, remembering the copies of the pointers already seen so that
+// shared (or cyclic) data is copied once.
+func redactValue(v reflect.Value, seen map[uintptr]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.String:
+		out := reflect.New(v.Type()).Elem()
+		out.SetString(Apply(v.String()))
+		return out
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if c, ok := seen[v.Pointer()]; ok {
+			return c
+		}
+		if collection, ok := v.Interface().(*syftPkg.Collection); ok {
+			pkgs := redactValue(reflect.ValueOf(collection.Sorted()), seen).Interface().([]syftPkg.Package)
+			out := reflect.ValueOf(syftPkg.NewCollection(pkgs...))
+			seen[v.Pointer()] = out
+			return out
+		}
+		out := reflect.New(v.Type().Elem())
+		seen[v.Pointer()] = out
+		out.Elem().Set(redactValue(v.Elem(), seen))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(redactValue(v.Elem(), seen))
+		return out
+	case reflect.Struct:
+		if set, ok := v.Interface().(file.LocationSet); ok {
+			if set.Empty() {
+				return v
+			}
+			locations := redactValue(reflect.ValueOf(set.ToSlice()), seen).Interface().([]file.Location)
+			return reflect.ValueOf(file.NewLocationSet(locations...))
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if out.Field(i).CanSet() {
+				out.Field(i).Set(redactValue(v.Field(i), seen))
+			}
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i), seen))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i), seen))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(redactValue(iter.Key(), seen), redactValue(iter.Value(), seen))
+		}
+		return out
+	default:
+		// numbers, booleans, functions and channels hold nothing to redact
+		return v
+	}
+}
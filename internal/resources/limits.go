@@ -0,0 +1,164 @@
+/*
+Package resources applies soft limits to the CPUs and memory used by the process, so that a scan sharing a machine
+(such as a build agent) with other jobs does not starve them.
+*/
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	liveHeapMetric  = "/gc/heap/live:bytes"
+	defaultInterval = 250 * time.Millisecond
+)
+
+// Limits are the soft limits of the resources used by the process.
+type Limits struct {
+	// CPUs caps the number of CPUs executing Go code simultaneously (as GOMAXPROCS does), or 0 for no cap.
+	CPUs int
+	// Memory is the budget of heap memory in bytes, or 0 for no budget.
+	Memory uint64
+}
+
+// MemoryBudgetExceededError is reported when the heap of the process grew beyond the memory budget.
+type MemoryBudgetExceededError struct {
+	Budget uint64
+	Used   uint64
+}
+
+func (e *MemoryBudgetExceededError) Error() string {
+	return fmt.Sprintf("memory budget exceeded: %s of heap in use with a budget of %s",
+		humanize.IBytes(e.Used), humanize.IBytes(e.Budget))
+}
+
+// Monitor samples the live heap of the process until stopped, recording when it exceeds the memory budget.
+type Monitor struct {
+	budget   uint64
+	interval time.Duration
+	done     chan struct{}
+	stopOnce sync.Once
+
+	lock         sync.Mutex
+	exceeded     *MemoryBudgetExceededError
+	exceededCh   chan struct{}
+	exceededOnce sync.Once
+}
+
+// Apply applies the limits to the process: the CPU cap, and the memory budget as the soft memory limit of the Go
+// runtime (so that the garbage collector reclaims memory more eagerly near the budget) along with a monitor of the heap.
+// It returns the monitor, which is nil without a memory budget, and a function stopping the monitor and restoring the
+// previous limits.
+func Apply(l Limits) (*Monitor, func()) {
+	var restores []func()
+	if l.CPUs > 0 && l.CPUs < runtime.GOMAXPROCS(0) {
+		previous := runtime.GOMAXPROCS(l.CPUs)
+		restores = append(restores, func() { runtime.GOMAXPROCS(previous) })
+	}
+
+	var monitor *Monitor
+	if l.Memory > 0 {
+		previous := debug.SetMemoryLimit(int64(min(l.Memory, math.MaxInt64)))
+		monitor = newMonitor(l.Memory, defaultInterval)
+		go monitor.run()
+		restores = append(restores, monitor.stop, func() { debug.SetMemoryLimit(previous) })
+	}
+
+	return monitor, func() {
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
+
+func newMonitor(budget uint64, interval time.Duration) *Monitor {
+	return &Monitor{
+		budget:     budget,
+		interval:   interval,
+		done:       make(chan struct{}),
+		exceededCh: make(chan struct{}),
+	}
+}
+
+// Context returns a context of the parent that is canceled as soon as the heap is found over the budget, with the
+// *MemoryBudgetExceededError as its cause (see context.Cause), so that the work observing the context is aborted while
+// it runs instead of once it completes. A nil monitor (without a budget) returns a context only canceled with the
+// parent.
+func (m *Monitor) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	if m == nil {
+		return ctx, func() { cancel(context.Canceled) }
+	}
+	go func() {
+		select {
+		case <-m.exceededCh:
+			cancel(m.Err())
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// Err returns a *MemoryBudgetExceededError when the heap exceeded the budget since the monitor started.
+func (m *Monitor) Err() error {
+	if m == nil {
+		return nil
+	}
+	m.check()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.exceeded == nil {
+		return nil
+	}
+	return m.exceeded
+}
+
+func (m *Monitor) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+func (m *Monitor) check() {
+	used := heapInUse()
+	if used <= m.budget {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.exceeded == nil || used > m.exceeded.Used {
+		m.exceeded = &MemoryBudgetExceededError{Budget: m.budget, Used: used}
+	}
+	m.exceededOnce.Do(func() { close(m.exceededCh) })
+}
+
+func (m *Monitor) stop() {
+	m.stopOnce.Do(func() { close(m.done) })
+}
+
+// heapInUse returns the bytes of the heap occupied by live objects as of the last garbage collection, so that the
+// garbage not yet collected does not count towards the budget.
+func heapInUse() uint64 {
+	sample := []metrics.Sample{{Name: liveHeapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
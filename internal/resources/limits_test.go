@@ -0,0 +1,86 @@
+package resources
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply_CPUs(t *testing.T) {
+	previous := runtime.GOMAXPROCS(0)
+	if previous < 2 {
+		t.Skip("the CPU cap needs at least 2 CPUs to be observed")
+	}
+
+	monitor, restore := Apply(Limits{CPUs: 1})
+	assert.Nil(t, monitor)
+	assert.Equal(t, 1, runtime.GOMAXPROCS(0))
+	restore()
+	assert.Equal(t, previous, runtime.GOMAXPROCS(0))
+
+	// the cap never raises the CPUs available
+	_, restore = Apply(Limits{CPUs: previous + 1})
+	assert.Equal(t, previous, runtime.GOMAXPROCS(0))
+	restore()
+}
+
+func TestApply_Memory(t *testing.T) {
+	monitor, restore := Apply(Limits{Memory: 1 << 40})
+	defer restore()
+	require.NotNil(t, monitor)
+	runtime.GC()
+	assert.NoError(t, monitor.Err())
+
+	// a nil monitor (without a budget) never reports an error
+	var none *Monitor
+	assert.NoError(t, none.Err())
+}
+
+func TestMonitor_exceeded(t *testing.T) {
+	monitor := newMonitor(1, time.Millisecond)
+	go monitor.run()
+	defer monitor.stop()
+
+	retained := make([]byte, 1<<20)
+	runtime.GC()
+	require.Eventually(t, func() bool { return monitor.Err() != nil }, time.Second, time.Millisecond)
+	runtime.KeepAlive(retained)
+
+	var exceeded *MemoryBudgetExceededError
+	require.ErrorAs(t, monitor.Err(), &exceeded)
+	assert.Equal(t, uint64(1), exceeded.Budget)
+	assert.Greater(t, exceeded.Used, uint64(1<<20))
+	assert.Contains(t, exceeded.Error(), "memory budget exceeded: ")
+}
+
+func TestMonitor_Context(t *testing.T) {
+	monitor := newMonitor(1, time.Millisecond)
+	go monitor.run()
+	defer monitor.stop()
+
+	ctx, cancel := monitor.Context(context.Background())
+	defer cancel()
+
+	retained := make([]byte, 1<<20)
+	runtime.GC()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("the context was not canceled once the budget was exceeded")
+	}
+	runtime.KeepAlive(retained)
+
+	var exceeded *MemoryBudgetExceededError
+	require.ErrorAs(t, context.Cause(ctx), &exceeded)
+
+	// a nil monitor (without a budget) is only canceled with the parent
+	var none *Monitor
+	ctx, cancel = none.Context(context.Background())
+	assert.NoError(t, ctx.Err())
+	cancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
@@ -1,6 +1,9 @@
 package stringutil
 
-import "strings"
+import (
+	"sort"
+	"strings"
+)
 
 // HasAnyOfSuffixes returns an indication if the given string has any of the given suffixes.
 func HasAnyOfSuffixes(input string, suffixes ...string) bool {
@@ -34,3 +37,27 @@ func SplitCommaSeparatedString(input string) []string {
 	}
 	return output
 }
+
+// AppendUnique appends each of the values that the slice does not hold yet, keeping the order of the slice.
+func AppendUnique(values []string, add ...string) []string {
+	for _, a := range add {
+		found := false
+		for _, v := range values {
+			if v == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, a)
+		}
+	}
+	return values
+}
+
+// SortedJoin joins a sorted copy of the values with the separator, leaving the values themselves untouched.
+func SortedJoin(values []string, sep string) string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, sep)
+}
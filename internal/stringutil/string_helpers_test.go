@@ -154,3 +154,16 @@ func TestSplitCommaSeparatedString(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendUnique(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, AppendUnique([]string{"a", "b"}, "b", "c", "c", "a"))
+	assert.Equal(t, []string{"a"}, AppendUnique(nil, "a"))
+	assert.Nil(t, AppendUnique(nil))
+}
+
+func TestSortedJoin(t *testing.T) {
+	values := []string{"b", "c", "a"}
+	assert.Equal(t, "a,b,c", SortedJoin(values, ","))
+	assert.Equal(t, []string{"b", "c", "a"}, values, "the values are not sorted in place")
+	assert.Empty(t, SortedJoin(nil, ","))
+}
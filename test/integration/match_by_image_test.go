@@ -831,6 +831,16 @@ func TestMatchByImage(t *testing.T) {
 	observedMatchers.Remove(string(match.StockMatcher))
 	definedMatchers.Remove(string(match.StockMatcher))
 	definedMatchers.Remove(string(match.MsrcMatcher))
+	// syft does not catalog opkg packages from images, they are only provided via SBOMs
+	definedMatchers.Remove(string(match.OpkgMatcher))
+	// the test images are linux-based and do not contain Homebrew or MacPorts installations
+	definedMatchers.Remove(string(match.MacOSMatcher))
+	// there is no arch linux test image
+	definedMatchers.Remove(string(match.AlpmMatcher))
+	// there is no nix store in the test images
+	definedMatchers.Remove(string(match.NixMatcher))
+	// the test images do not contain runtime binaries
+	definedMatchers.Remove(string(match.RuntimeMatcher))
 
 	if len(observedMatchers) != len(definedMatchers) {
 		t.Errorf("matcher coverage incomplete (matchers=%d, coverage=%d)", len(definedMatchers), len(observedMatchers))